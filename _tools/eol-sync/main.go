@@ -0,0 +1,202 @@
+// Command eol-sync refreshes the embedded end-of-life dataset
+// (internal/eol/data.json) from the endoflife.date API.
+//
+// Product entries (id, name, images, os) are maintained by hand; this tool
+// only replaces each product's release cycles. Run it via `make eol-sync`.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+)
+
+const (
+	dataPathRel = "internal/eol/data.json"
+	apiBaseURL  = "https://endoflife.date/api/"
+	filePerm    = 0o644
+)
+
+type dataset struct {
+	Products []product `json:"products"`
+}
+
+type product struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Images []string `json:"images"`
+	OS     bool     `json:"os,omitempty"`
+	Cycles []cycle  `json:"cycles"`
+}
+
+type cycle struct {
+	Cycle    string `json:"cycle"`
+	Codename string `json:"codename,omitempty"`
+	EOL      string `json:"eol,omitempty"`
+}
+
+// apiCycle is one element of the endoflife.date /api/<product>.json response.
+type apiCycle struct {
+	Cycle             jsontext.Value `json:"cycle"`
+	Codename          string         `json:"codename"`
+	EOL               jsontext.Value `json:"eol"`
+	LatestReleaseDate string         `json:"latestReleaseDate"`
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "eol-sync: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	repoRoot, err := findRepoRoot()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(repoRoot, filepath.FromSlash(dataPathRel))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var d dataset
+	if err := jsonv2.Unmarshal(data, &d); err != nil {
+		return fmt.Errorf("parse %s: %w", dataPathRel, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	client := &http.Client{Timeout: 20 * time.Second}
+	for i := range d.Products {
+		p := &d.Products[i]
+		cycles, err := fetchCycles(ctx, client, p.ID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.ID, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d cycles\n", p.ID, len(cycles))
+		p.Cycles = cycles
+	}
+
+	out, err := format(&d)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, filePerm)
+}
+
+func fetchCycles(ctx context.Context, client *http.Client, id string) ([]cycle, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiBaseURL+id+".json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	var raw []apiCycle
+	if err := jsonv2.UnmarshalRead(resp.Body, &raw); err != nil {
+		return nil, err
+	}
+
+	cycles := make([]cycle, 0, len(raw))
+	for _, a := range raw {
+		c := cycle{Cycle: scalarString(a.Cycle)}
+		if c.Cycle == "" {
+			continue
+		}
+		// Image tags use the first word of a codename, lowercased
+		// ("Jammy Jellyfish" -> "jammy").
+		first, _, _ := strings.Cut(strings.TrimSpace(a.Codename), " ")
+		c.Codename = strings.ToLower(first)
+		switch eol := scalarString(a.EOL); eol {
+		case "false", "":
+		case "true":
+			// Ended without a published date; the last release is the best bound.
+			c.EOL = a.LatestReleaseDate
+		default:
+			if _, err := time.Parse(time.DateOnly, eol); err != nil {
+				return nil, fmt.Errorf("cycle %s: invalid eol %q", c.Cycle, eol)
+			}
+			c.EOL = eol
+		}
+		cycles = append(cycles, c)
+	}
+	return cycles, nil
+}
+
+func scalarString(v jsontext.Value) string {
+	var s string
+	if err := jsonv2.Unmarshal(v, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(v))
+}
+
+// format renders the dataset with one cycle per line to keep diffs readable.
+func format(d *dataset) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{\n  \"products\": [\n")
+	for i, p := range d.Products {
+		images, err := jsonv2.Marshal(p.Images)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "    {\n      \"id\": %q,\n      \"name\": %q,\n      \"images\": %s,\n",
+			p.ID, p.Name, bytes.ReplaceAll(images, []byte(","), []byte(", ")))
+		if p.OS {
+			b.WriteString("      \"os\": true,\n")
+		}
+		b.WriteString("      \"cycles\": [\n")
+		for j, c := range p.Cycles {
+			line, err := jsonv2.Marshal(c)
+			if err != nil {
+				return nil, err
+			}
+			line = bytes.ReplaceAll(line, []byte(`","`), []byte(`", "`))
+			line = bytes.ReplaceAll(line, []byte(`":"`), []byte(`": "`))
+			fmt.Fprintf(&b, "        { %s }", bytes.TrimSuffix(bytes.TrimPrefix(line, []byte("{")), []byte("}")))
+			b.WriteString(separator(j, len(p.Cycles)))
+		}
+		b.WriteString("      ]\n    }")
+		b.WriteString(separator(i, len(d.Products)))
+	}
+	b.WriteString("  ]\n}\n")
+	return b.Bytes(), nil
+}
+
+func separator(i, n int) string {
+	if i < n-1 {
+		return ",\n"
+	}
+	return "\n"
+}
+
+func findRepoRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(dataPathRel))); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("could not find repo root containing %s", dataPathRel)
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,602 @@
+// Command new-rule scaffolds a new lint rule: the rule and its test, the JSON
+// schema and manifest entry for configurable rules, the documentation page
+// and its docs.json entry, and integration fixtures.
+//
+// Run it via `make new-rule ARGS='-description "..." tally/my-rule'`, then
+// implement Check, fill in the documentation, and record the snapshots.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const (
+	manifestPathRel = "internal/schemas/manifest.json"
+	docsConfigPath  = "_docs/docs.json"
+	allRulesPath    = "internal/rules/all/all.go"
+	minimalPath     = "internal/config/presets/minimal.toml"
+	modulePath      = "github.com/wharflab/tally"
+	filePerm        = 0o644
+	dirPerm         = 0o755
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var (
+	tallyCodeRE    = regexp.MustCompile(`^tally/(?:([a-z][a-z0-9]*)/)?([a-z][a-z0-9]*(?:-[a-z0-9]+)*)$`)
+	hadolintCodeRE = regexp.MustCompile(`^hadolint/(DL[0-9]{4})$`)
+)
+
+// severities maps the -severity values to rules.Severity constants.
+var severities = map[string]string{
+	"error":   "SeverityError",
+	"warning": "SeverityWarning",
+	"info":    "SeverityInfo",
+	"style":   "SeverityStyle",
+	"off":     "SeverityOff",
+}
+
+// categories lists the rule categories in use.
+var categories = []string{
+	"best-practices", "correctness", "maintainability", "performance", "privacy",
+	"reliability", "reproducibility", "security", "style",
+}
+
+// ruleSpec describes the rule to scaffold. It is the data of every template.
+type ruleSpec struct {
+	Code        string // full rule code, e.g. "tally/js/my-rule" or "hadolint/DL3008"
+	Namespace   string // "tally" or "hadolint"
+	SubPackage  string // tally sub-namespace such as "js"; empty for the root package
+	Local       string // rule code without the "tally/" or "hadolint/" prefix
+	Name        string // human-readable rule name
+	Description string
+	Category    string
+	Severity    string
+	Config      bool
+	Fix         bool
+	DocsGroup   string
+}
+
+// Type returns the Go identifier prefix of the rule, e.g. "MyRule" or "DL3008".
+func (s ruleSpec) Type() string {
+	if s.Namespace == "hadolint" {
+		return s.Local
+	}
+	return camelCase(path.Base(s.Local))
+}
+
+// Package returns the Go package name of the rule.
+func (s ruleSpec) Package() string {
+	if s.SubPackage != "" {
+		return s.SubPackage
+	}
+	return s.Namespace
+}
+
+// PackageDir returns the slash-separated directory of the rule's package.
+func (s ruleSpec) PackageDir() string {
+	if s.SubPackage != "" {
+		return "internal/rules/tally/" + s.SubPackage
+	}
+	return "internal/rules/" + s.Namespace
+}
+
+// FileBase returns the file name of the rule without extension.
+func (s ruleSpec) FileBase() string {
+	if s.Namespace == "hadolint" {
+		return strings.ToLower(s.Local)
+	}
+	return strings.ReplaceAll(path.Base(s.Local), "-", "_")
+}
+
+// CodeExpr returns the Go expression for the rule code.
+func (s ruleSpec) CodeExpr() string {
+	if s.Namespace == "hadolint" {
+		return fmt.Sprintf("rules.HadolintRulePrefix + %q", s.Local)
+	}
+	return s.Type() + "RuleCode"
+}
+
+// DocURLExpr returns the Go expression for the rule documentation URL.
+func (s ruleSpec) DocURLExpr() string {
+	if s.Namespace == "hadolint" {
+		return fmt.Sprintf("rules.HadolintDocURL(%q)", s.Local)
+	}
+	return "rules.TallyDocURL(" + s.CodeExpr() + ")"
+}
+
+// SeverityConst returns the rules.Severity constant of the default severity.
+func (s ruleSpec) SeverityConst() string {
+	return severities[s.Severity]
+}
+
+// SchemaRef returns the relative path from the schema file to the shared
+// rule config schema.
+func (s ruleSpec) SchemaRef() string {
+	if s.SubPackage != "" {
+		return "../../rule-config.schema.json"
+	}
+	return "../rule-config.schema.json"
+}
+
+// SchemaID returns the $id of the rule's JSON schema.
+func (s ruleSpec) SchemaID() string {
+	return "https://tally.wharflab.com/" + strings.TrimPrefix(s.PackageDir(), "internal/") + "/" + s.FileBase() + ".schema.json"
+}
+
+// ConfigKey returns the TOML table of the rule's configuration.
+func (s ruleSpec) ConfigKey() string {
+	return "rules." + strings.ReplaceAll(s.Code, "/", ".")
+}
+
+// DocPage returns the docs.json page of the rule.
+func (s ruleSpec) DocPage() string {
+	return "rules/" + s.Code
+}
+
+// FixtureName returns the directory name of the integration fixtures.
+func (s ruleSpec) FixtureName() string {
+	if s.Namespace == "hadolint" {
+		return strings.ToLower(s.Local)
+	}
+	return strings.ReplaceAll(s.Local, "/", "-")
+}
+
+// Title returns s with the first letter upper-cased.
+func (s ruleSpec) Title(text string) string {
+	if text == "" {
+		return text
+	}
+	r := []rune(text)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "new-rule: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("new-rule", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: go run ./new-rule [flags] <tally/rule-name | tally/namespace/rule-name | hadolint/DLxxxx>\n\n")
+		fs.PrintDefaults()
+	}
+	description := fs.String("description", "", "One-line description of what the rule reports (required)")
+	name := fs.String("name", "", "Human-readable rule name (default: derived from the rule code)")
+	category := fs.String("category", "correctness", "Rule category: "+strings.Join(categories, ", "))
+	severity := fs.String("severity", "warning", "Default severity: error, warning, info, style, or off for rules enabled by configuration")
+	config := fs.Bool("config", false, "Scaffold configuration options: a JSON schema, manifest entry and config struct")
+	withFix := fs.Bool("fix", false, "The rule suggests fixes: declare them in the metadata and add a fix fixture")
+	docsGroup := fs.String("docs-group", "", "docs.json group of the rule page (default: derived from the namespace and category)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one rule code")
+	}
+
+	spec, err := newRuleSpec(fs.Arg(0), *description, *name, *category, *severity, *docsGroup)
+	if err != nil {
+		return err
+	}
+	spec.Config = *config
+	spec.Fix = *withFix
+
+	repoRoot, err := findRepoRoot()
+	if err != nil {
+		return err
+	}
+	s := &scaffolder{root: repoRoot, out: out}
+	return s.scaffold(spec)
+}
+
+// newRuleSpec validates the command line and derives the rule spec.
+func newRuleSpec(code, description, name, category, severity, docsGroup string) (ruleSpec, error) {
+	spec := ruleSpec{Code: code, Name: name, Description: strings.TrimSuffix(strings.TrimSpace(description), ".")}
+	switch {
+	case tallyCodeRE.MatchString(code):
+		m := tallyCodeRE.FindStringSubmatch(code)
+		spec.Namespace, spec.SubPackage, spec.Local = "tally", m[1], strings.TrimPrefix(code, "tally/")
+	case hadolintCodeRE.MatchString(code):
+		spec.Namespace, spec.Local = "hadolint", hadolintCodeRE.FindStringSubmatch(code)[1]
+	default:
+		return spec, fmt.Errorf("invalid rule code %q: want tally/<kebab-name>, tally/<namespace>/<kebab-name> or hadolint/DLxxxx", code)
+	}
+	if spec.Description == "" {
+		return spec, errors.New("-description is required")
+	}
+	if _, ok := severities[severity]; !ok {
+		return spec, fmt.Errorf("invalid -severity %q: want error, warning, info, style or off", severity)
+	}
+	if !slices.Contains(categories, category) {
+		return spec, fmt.Errorf("invalid -category %q: want one of %s", category, strings.Join(categories, ", "))
+	}
+	spec.Severity, spec.Category = severity, category
+	if spec.Name == "" {
+		spec.Name = ruleName(spec)
+	}
+	spec.DocsGroup = docsGroup
+	return spec, nil
+}
+
+// ruleName derives a human-readable name from the rule code.
+func ruleName(spec ruleSpec) string {
+	if spec.Namespace == "hadolint" {
+		return spec.Local
+	}
+	words := strings.Split(path.Base(spec.Local), "-")
+	for i, w := range words {
+		words[i] = spec.Title(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// camelCase converts a kebab-case name to CamelCase.
+func camelCase(kebab string) string {
+	var sb strings.Builder
+	for part := range strings.SplitSeq(kebab, "-") {
+		if part == "" {
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
+
+func findRepoRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, filepath.FromSlash(manifestPathRel))
+		if _, err := os.Stat(candidate); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("could not find repo root containing %s", manifestPathRel)
+}
+
+// scaffolder writes the files of a new rule below root.
+type scaffolder struct {
+	root string
+	out  io.Writer
+}
+
+// fileChange is the new content of a created or updated file.
+type fileChange struct {
+	path    string
+	content []byte
+	created bool
+}
+
+// scaffold renders and edits every file in memory first, so that a rule
+// that cannot be scaffolded leaves the tree untouched.
+func (s *scaffolder) scaffold(spec ruleSpec) error {
+	_, err := os.Stat(s.abs(spec.PackageDir()))
+	newPackage := errors.Is(err, os.ErrNotExist)
+
+	templates := []struct {
+		path     string
+		template string
+		skip     bool
+	}{
+		{path.Join(spec.PackageDir(), spec.FileBase()+".go"), "rule.go.tmpl", false},
+		{path.Join(spec.PackageDir(), spec.FileBase()+"_test.go"), "rule_test.go.tmpl", false},
+		{path.Join(spec.PackageDir(), spec.FileBase()+".schema.json"), "schema.json.tmpl", !spec.Config},
+		{path.Join("_docs", spec.DocPage()+".mdx"), "doc.mdx.tmpl", false},
+		{path.Join("internal/integration/fixtures/lint", spec.FixtureName(), ".tally.toml"), "fixture.toml.tmpl", false},
+		{path.Join("internal/integration/fixtures/lint", spec.FixtureName(), "Dockerfile"), "Dockerfile.tmpl", false},
+		{path.Join("internal/integration/fixtures/fix", spec.FixtureName(), ".tally.toml"), "fixture.toml.tmpl", !spec.Fix},
+		{path.Join("internal/integration/fixtures/fix", spec.FixtureName(), "Dockerfile"), "Dockerfile.tmpl", !spec.Fix},
+	}
+	var changes []fileChange
+	for _, t := range templates {
+		if t.skip {
+			continue
+		}
+		content, err := s.render(t.path, t.template, spec)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, fileChange{path: t.path, content: content, created: true})
+	}
+
+	edits := []struct {
+		path string
+		edit func([]byte, ruleSpec) ([]byte, error)
+		skip bool
+	}{
+		{manifestPathRel, addManifestEntry, !spec.Config},
+		{docsConfigPath, addDocsPage, false},
+		{allRulesPath, addRulePackageImport, !newPackage},
+		{minimalPath, addMinimalProfileExclude, spec.Severity != "info" && spec.Severity != "style"},
+	}
+	for _, e := range edits {
+		if e.skip {
+			continue
+		}
+		data, err := os.ReadFile(s.abs(e.path))
+		if err != nil {
+			return err
+		}
+		content, err := e.edit(data, spec)
+		if err != nil {
+			return fmt.Errorf("update %s: %w", e.path, err)
+		}
+		changes = append(changes, fileChange{path: e.path, content: content})
+	}
+
+	for _, c := range changes {
+		if err := os.MkdirAll(filepath.Dir(s.abs(c.path)), dirPerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(s.abs(c.path), c.content, filePerm); err != nil {
+			return err
+		}
+		verb := "updated"
+		if c.created {
+			verb = "created"
+		}
+		fmt.Fprintf(s.out, "%s %s\n", verb, c.path)
+	}
+
+	s.printNextSteps(spec)
+	return nil
+}
+
+func (s *scaffolder) abs(rel string) string {
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}
+
+// render executes a template for a file that must not exist yet. Go files
+// are gofmt'ed.
+func (s *scaffolder) render(rel, name string, spec ruleSpec) ([]byte, error) {
+	if _, err := os.Stat(s.abs(rel)); err == nil {
+		return nil, fmt.Errorf("%s already exists", rel)
+	}
+	tmpl, err := template.ParseFS(templateFS, "templates/"+name)
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, spec); err != nil {
+		return nil, fmt.Errorf("render %s: %w", rel, err)
+	}
+	if !strings.HasSuffix(rel, ".go") {
+		return buf.Bytes(), nil
+	}
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", rel, err)
+	}
+	return content, nil
+}
+
+func (s *scaffolder) printNextSteps(spec ruleSpec) {
+	fmt.Fprintf(s.out, "\nNext steps:\n")
+	step := 1
+	next := func(format string, args ...any) {
+		fmt.Fprintf(s.out, "  %d. %s\n", step, fmt.Sprintf(format, args...))
+		step++
+	}
+	if spec.Config {
+		next("add the options to %s and the config struct, then run `make schema-gen`",
+			path.Join(spec.PackageDir(), spec.FileBase()+".schema.json"))
+	}
+	next("implement Check in %s and add test cases", path.Join(spec.PackageDir(), spec.FileBase()+".go"))
+	next("record the metadata snapshot: UPDATE_SNAPS=true go test ./%s -run %sRule_Metadata",
+		spec.PackageDir(), spec.Type())
+	next("write the Dockerfile of the fixtures under internal/integration/fixtures/ and record their snapshots:\n"+
+		"     UPDATE_SNAPS=true go test ./internal/integration -run 'Test(Lint|Fix)Fixtures/%s$'", spec.FixtureName())
+	next("describe the rule in %s", path.Join("_docs", spec.DocPage()+".mdx"))
+	if spec.Namespace == "hadolint" {
+		next("mark the rule as implemented in _docs/rules/hadolint/overview.mdx")
+	}
+}
+
+// addManifestEntry adds the rule's schema to the manifest, after the last
+// schema of the same package, keeping the file's layout.
+func addManifestEntry(manifest []byte, spec ruleSpec) ([]byte, error) {
+	pkg := modulePath + "/internal/schemas/generated/" + strings.TrimPrefix(spec.PackageDir(), "internal/")
+	input := path.Join(spec.PackageDir(), spec.FileBase()+".schema.json")
+	if bytes.Contains(manifest, []byte(`"`+input+`"`)) {
+		return nil, fmt.Errorf("%s is already listed", input)
+	}
+	entry := fmt.Sprintf(",\n    {\n      \"input\": %q,\n      \"output\": %q,\n      \"package\": %q\n    }",
+		input, "internal/schemas/generated/"+strings.TrimPrefix(spec.PackageDir(), "internal/")+"/"+spec.FileBase()+".gen.go", pkg)
+
+	// Insert after the closing brace of the last entry with the same package,
+	// or of the last schema entry when the package is new.
+	anchor := bytes.LastIndex(manifest, []byte(fmt.Sprintf("\"package\": %q", pkg)))
+	if anchor < 0 {
+		anchor = bytes.LastIndex(manifest, []byte(`"package": `))
+	}
+	if anchor < 0 {
+		return nil, errors.New("no schema entries found")
+	}
+	end := bytes.IndexByte(manifest[anchor:], '}')
+	if end < 0 {
+		return nil, errors.New("unterminated schema entry")
+	}
+	pos := anchor + end + 1
+	return slices.Concat(manifest[:pos], []byte(entry), manifest[pos:]), nil
+}
+
+// addDocsPage adds the rule page to its docs.json group: pages of a tally
+// sub-namespace go to the group already listing that namespace, hadolint
+// rules keep their group sorted, and other rules are appended to the group
+// of their category.
+func addDocsPage(docs []byte, spec ruleSpec) ([]byte, error) {
+	page := spec.DocPage()
+	if bytes.Contains(docs, []byte(`"`+page+`"`)) {
+		return nil, fmt.Errorf("page %s is already listed", page)
+	}
+
+	group := spec.DocsGroup
+	if group == "" {
+		group = defaultDocsGroup(docs, spec)
+	}
+	if group == "" {
+		return nil, fmt.Errorf("no docs group lists rules/tally/%s/ pages; pass -docs-group to add one by hand", spec.SubPackage)
+	}
+
+	start := bytes.Index(docs, []byte(fmt.Sprintf("\"group\": %q", group)))
+	if start < 0 {
+		return nil, fmt.Errorf("docs group %q not found", group)
+	}
+	pagesStart := bytes.Index(docs[start:], []byte(`"pages": [`))
+	if pagesStart < 0 {
+		return nil, fmt.Errorf("docs group %q has no pages", group)
+	}
+	pagesStart += start + len(`"pages": [`)
+	pagesEnd := bytes.IndexByte(docs[pagesStart:], ']')
+	if pagesEnd < 0 {
+		return nil, fmt.Errorf("docs group %q has unterminated pages", group)
+	}
+	pagesEnd += pagesStart
+
+	// Each page sits on its own line; reuse the indentation of the last one.
+	lines := strings.Split(strings.TrimRight(string(docs[pagesStart:pagesEnd]), " \n"), "\n")
+	var pages []string
+	indent := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent = line[:len(line)-len(strings.TrimLeft(line, " "))]
+		pages = append(pages, strings.TrimSuffix(trimmed, ","))
+	}
+	if indent == "" {
+		return nil, fmt.Errorf("docs group %q has no pages to align with", group)
+	}
+
+	at := len(pages)
+	if spec.Namespace == "hadolint" {
+		// Keep DL rules sorted; the overview page stays first.
+		at = 0
+		for at < len(pages) && (!strings.HasPrefix(pages[at], `"rules/hadolint/DL`) || pages[at] < `"`+page+`"`) {
+			at++
+		}
+	}
+	pages = slices.Insert(pages, at, `"`+page+`"`)
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	for i, p := range pages {
+		sb.WriteString(indent + p)
+		if i < len(pages)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	closingIndent := string(docs[bytes.LastIndexByte(docs[:pagesEnd], '\n')+1 : pagesEnd])
+	sb.WriteString(closingIndent)
+	return slices.Concat(docs[:pagesStart], []byte(sb.String()), docs[pagesEnd:]), nil
+}
+
+// defaultDocsGroup returns the docs.json group of a rule page.
+func defaultDocsGroup(docs []byte, spec ruleSpec) string {
+	switch {
+	case spec.Namespace == "hadolint":
+		return "Hadolint Rules"
+	case spec.SubPackage != "":
+		// Find the group that already lists pages of the sub-namespace.
+		idx := bytes.Index(docs, []byte(`"rules/tally/`+spec.SubPackage+`/`))
+		if idx < 0 {
+			return ""
+		}
+		groupIdx := bytes.LastIndex(docs[:idx], []byte(`"group": "`))
+		if groupIdx < 0 {
+			return ""
+		}
+		rest := docs[groupIdx+len(`"group": "`):]
+		return string(rest[:bytes.IndexByte(rest, '"')])
+	}
+	switch spec.Category {
+	case "security", "privacy":
+		return "Security"
+	case "performance":
+		return "Performance"
+	case "style", "best-practices":
+		return "Style"
+	default:
+		return "Correctness"
+	}
+}
+
+// addMinimalProfileExclude excludes an info or style rule from the minimal
+// profile, which keeps correctness and security rules only. The exclude
+// list stays sorted.
+func addMinimalProfileExclude(src []byte, spec ruleSpec) ([]byte, error) {
+	entry := fmt.Sprintf("  %q,\n", spec.Code)
+	lines := strings.SplitAfter(string(src), "\n")
+	start := slices.Index(lines, "exclude = [\n")
+	if start < 0 {
+		return nil, errors.New("no exclude list found")
+	}
+	for i := start + 1; i < len(lines); i++ {
+		switch {
+		case lines[i] == entry:
+			return nil, fmt.Errorf("%s is already excluded", spec.Code)
+		case lines[i] == "]\n" || lines[i] > entry:
+			lines = slices.Insert(lines, i, entry)
+			return []byte(strings.Join(lines, "")), nil
+		}
+	}
+	return nil, errors.New("unterminated exclude list")
+}
+
+// addRulePackageImport registers a new rule package in the blank imports of
+// the all package, keeping them sorted.
+func addRulePackageImport(src []byte, spec ruleSpec) ([]byte, error) {
+	imp := fmt.Sprintf("\t_ %q\n", modulePath+"/"+spec.PackageDir())
+	lines := strings.SplitAfter(string(src), "\n")
+	last := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "\t_ \"") {
+			continue
+		}
+		if line > imp && last < 0 {
+			lines = slices.Insert(lines, i, imp)
+			return format.Source([]byte(strings.Join(lines, "")))
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil, errors.New("no blank imports found")
+	}
+	lines = slices.Insert(lines, last+1, imp)
+	return format.Source([]byte(strings.Join(lines, "")))
+}
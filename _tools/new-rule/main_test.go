@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRuleSpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code       string
+		wantType   string
+		wantDir    string
+		wantFile   string
+		wantFix    string
+		wantSchema string
+	}{
+		{"tally/my-rule", "MyRule", "internal/rules/tally", "my_rule", "my-rule", "../rule-config.schema.json"},
+		{"tally/js/npm-ci", "NpmCi", "internal/rules/tally/js", "npm_ci", "js-npm-ci", "../../rule-config.schema.json"},
+		{"hadolint/DL3008", "DL3008", "internal/rules/hadolint", "dl3008", "dl3008", "../rule-config.schema.json"},
+	}
+	for _, tt := range tests {
+		spec, err := newRuleSpec(tt.code, "Something is wrong.", "", "correctness", "warning", "")
+		if err != nil {
+			t.Fatalf("newRuleSpec(%q): %v", tt.code, err)
+		}
+		if spec.Description != "Something is wrong" {
+			t.Errorf("%s: description = %q", tt.code, spec.Description)
+		}
+		got := []string{spec.Type(), spec.PackageDir(), spec.FileBase(), spec.FixtureName(), spec.SchemaRef()}
+		want := []string{tt.wantType, tt.wantDir, tt.wantFile, tt.wantFix, tt.wantSchema}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("%s: got %v, want %v", tt.code, got, want)
+		}
+	}
+
+	for _, code := range []string{"tally/MyRule", "tally/a/b/c", "buildkit/Foo", "hadolint/DL30"} {
+		if _, err := newRuleSpec(code, "x", "", "correctness", "warning", ""); err == nil {
+			t.Errorf("newRuleSpec(%q): want error", code)
+		}
+	}
+}
+
+func TestAddDocsPage(t *testing.T) {
+	t.Parallel()
+
+	docs := `{
+  "groups": [
+    {
+      "group": "JavaScript",
+      "pages": [
+        "rules/tally/js/a"
+      ]
+    },
+    {
+      "group": "Hadolint Rules",
+      "pages": [
+        "rules/hadolint/overview",
+        "rules/hadolint/DL3001",
+        "rules/hadolint/DL3010"
+      ]
+    }
+  ]
+}
+`
+	js, err := newRuleSpec("tally/js/b", "x", "", "correctness", "warning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := addDocsPage([]byte(docs), js)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\"rules/tally/js/a\",\n        \"rules/tally/js/b\"\n      ]"; !strings.Contains(string(got), want) {
+		t.Errorf("JavaScript group not extended:\n%s", got)
+	}
+
+	dl, err := newRuleSpec("hadolint/DL3005", "x", "", "correctness", "warning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = addDocsPage([]byte(docs), dl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\"rules/hadolint/DL3001\",\n        \"rules/hadolint/DL3005\",\n        \"rules/hadolint/DL3010\""; !strings.Contains(string(got), want) {
+		t.Errorf("hadolint page not inserted in order:\n%s", got)
+	}
+
+	if _, err := addDocsPage(got, dl); err == nil {
+		t.Error("adding a listed page again: want error")
+	}
+}
+
+func TestAddRulePackageImport(t *testing.T) {
+	t.Parallel()
+
+	src := "package all\n\nimport (\n\t_ \"github.com/wharflab/tally/internal/rules/tally\"\n" +
+		"\t_ \"github.com/wharflab/tally/internal/rules/tally/js\"\n" +
+		"\t_ \"github.com/wharflab/tally/internal/rules/tally/ruby\"\n)\n"
+	spec, err := newRuleSpec("tally/php/x", "x", "", "correctness", "warning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := addRulePackageImport([]byte(src), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "tally/js\"\n\t_ \"github.com/wharflab/tally/internal/rules/tally/php\"\n\t_ \"github.com/wharflab/tally/internal/rules/tally/ruby\""
+	if !strings.Contains(string(got), want) {
+		t.Errorf("import not inserted in order:\n%s", got)
+	}
+}
+
+func TestAddMinimalProfileExclude(t *testing.T) {
+	t.Parallel()
+
+	src := "[rules]\nexclude = [\n  \"tally/eol-last\",\n  \"tally/sort-packages\",\n]\n"
+	spec, err := newRuleSpec("tally/prefer-x", "x", "", "style", "info", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := addMinimalProfileExclude([]byte(src), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[rules]\nexclude = [\n  \"tally/eol-last\",\n  \"tally/prefer-x\",\n  \"tally/sort-packages\",\n]\n"
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if _, err := addMinimalProfileExclude(got, spec); err == nil {
+		t.Error("expected an error for a rule already excluded")
+	}
+}
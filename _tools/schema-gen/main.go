@@ -320,9 +320,10 @@ func generateNamespaceIndexes(repoRoot string, m *manifest) error {
 			patternProps = map[string]refSchema{
 				`^SC[0-9]{4}$`: {Ref: "../rule-config.schema.json#/$defs/genericRuleConfig"},
 			}
-			props = map[string]refSchema{
-				"ShellCheck":              {Ref: "../rule-config.schema.json#/$defs/genericRuleConfig"},
-				"ShellCheckInternalError": {Ref: "../rule-config.schema.json#/$defs/genericRuleConfig"},
+			for _, name := range []string{"ShellCheck", "ShellCheckInternalError"} {
+				if _, ok := props[name]; !ok {
+					props[name] = refSchema{Ref: "../rule-config.schema.json#/$defs/genericRuleConfig"}
+				}
 			}
 			additionalProps = false
 		}
@@ -590,8 +591,14 @@ func sanitizeDefKey(raw string) string {
 
 func ruleNameFromSchemaFilename(namespace, filename string) string {
 	base := strings.TrimSuffix(filename, ".schema.json")
-	if namespace == "hadolint" {
+	switch namespace {
+	case "hadolint":
 		return strings.ToUpper(base)
+	case "shellcheck":
+		// shellcheck.schema.json configures the ShellCheck driver rule.
+		if base == "shellcheck" {
+			return "ShellCheck"
+		}
 	}
 	return strings.ReplaceAll(base, "_", "-")
 }
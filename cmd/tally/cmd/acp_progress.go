@@ -52,7 +52,7 @@ func planAcpFixSpinner(
 		}
 
 		cfg := normalizedConfigs[filepath.Clean(v.File())]
-		if cfg == nil || !cfg.AI.Enabled || len(cfg.AI.Command) == 0 {
+		if cfg == nil || !cfg.AI.Enabled || len(cfg.AI.Command) == 0 || !cfg.AI.AllowsRule(v.RuleCode) {
 			continue
 		}
 
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bufio"
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+	"github.com/wharflab/tally/internal/textdiff"
+)
+
+// errAIApproveNotInteractive is returned when --ai-approve is used without a
+// terminal to answer on.
+var errAIApproveNotInteractive = errors.New("--ai-approve requires an interactive terminal on stdin")
+
+// terminalApprover shows each AI AutoFix proposal as a diff and asks for
+// confirmation. Resolvers run concurrently, so prompts are serialized.
+type terminalApprover struct {
+	mu  sync.Mutex
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newTerminalApprover() (*terminalApprover, error) {
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return nil, errAIApproveNotInteractive
+	}
+	return &terminalApprover{in: bufio.NewReader(os.Stdin), out: os.Stderr}, nil
+}
+
+func (a *terminalApprover) Approve(ctx stdcontext.Context, p autofixdata.Proposal) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	title := "AI AutoFix proposal for " + p.FilePath
+	if p.Rule != "" {
+		title += " (" + p.Rule + ")"
+	}
+	fmt.Fprintf(a.out, "\n%s:\n%s", title, textdiff.Unified(filepath.ToSlash(p.FilePath), p.Original, p.Proposed))
+	fmt.Fprint(a.out, "Apply this change? [y/N] ")
+
+	line, err := a.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+)
+
+// transcriptWriter writes one JSON file per AI AutoFix resolution into a
+// directory (--ai-transcript). Files are numbered in the order resolutions
+// finish, so a run's transcripts sort chronologically.
+type transcriptWriter struct {
+	dir string
+	seq atomic.Int64
+}
+
+func newTranscriptWriter(dir string) (*transcriptWriter, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("--ai-transcript: %w", err)
+	}
+	return &transcriptWriter{dir: dir}, nil
+}
+
+func (w *transcriptWriter) Record(t autofixdata.Transcript) error {
+	name := fmt.Sprintf("%03d-%s", w.seq.Add(1), transcriptFileSlug(filepath.Base(t.File)))
+	if t.Rule != "" {
+		name += "-" + transcriptFileSlug(t.Rule)
+	}
+	f, err := os.OpenFile(filepath.Join(w.dir, name+".json"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := json.MarshalWrite(f, t, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  ")); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// transcriptFileSlug keeps file names portable: rule codes contain slashes.
+func transcriptFileSlug(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+)
+
+func TestTranscriptWriter_Record(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "transcripts")
+	w, err := newTranscriptWriter(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := autofixdata.Transcript{
+		File:      filepath.Join("services", "api", "Dockerfile"),
+		Rule:      "tally/prefer-multi-stage-build",
+		Objective: autofixdata.ObjectiveMultiStage,
+		Outcome:   autofixdata.TranscriptResolved,
+		Exchanges: []autofixdata.Exchange{{Round: 1, Mode: autofixdata.OutputPatch, Prompt: "p", Response: "r"}},
+	}
+	if err := w.Record(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Record(autofixdata.Transcript{File: "Containerfile", Outcome: autofixdata.TranscriptFailed}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{"001-Dockerfile-tally_prefer-multi-stage-build.json", "002-Containerfile.json"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, want[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got autofixdata.Transcript
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Rule != first.Rule || len(got.Exchanges) != 1 || got.Exchanges[0].Response != "r" {
+		t.Errorf("round-tripped transcript = %+v", got)
+	}
+}
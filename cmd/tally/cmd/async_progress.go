@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/bubbles/v2/spinner"
+	"github.com/mattn/go-isatty"
+
+	"github.com/wharflab/tally/internal/async"
+)
+
+// asyncProgressDelay keeps the slow-checks progress line hidden for runs that
+// finish quickly (e.g. when every lookup is served from the cache).
+const asyncProgressDelay = 500 * time.Millisecond
+
+// startAsyncProgress shows a spinner with the state of the slow checks on
+// stderr. It returns the callback to install as async.Runtime.Progress and a
+// function that stops the spinner and clears its line.
+//
+// Nothing is shown when quiet is set or stderr is not a terminal: unlike the
+// ACP fix spinner, slow checks run on most invocations, and a note on every
+// CI run would be noise.
+func startAsyncProgress(quiet bool) (func(async.Progress), func()) {
+	if quiet || !isatty.IsTerminal(os.Stderr.Fd()) {
+		return nil, func() {}
+	}
+
+	sp := spinner.Line
+	frames := sp.Frames
+	interval := sp.FPS
+	if len(frames) == 0 {
+		frames = []string{"-"}
+	}
+	if interval <= 0 {
+		interval = 120 * time.Millisecond
+	}
+
+	var (
+		mu    sync.Mutex
+		state async.Progress
+	)
+	update := func(p async.Progress) {
+		mu.Lock()
+		state = p
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		frame := 0
+		drawn := false
+		for {
+			select {
+			case <-stop:
+				if drawn {
+					// Clear the line so subsequent output starts cleanly.
+					_, _ = fmt.Fprint(os.Stderr, "\r\033[2K")
+				}
+				close(done)
+				return
+			case <-ticker.C:
+				elapsed := time.Since(start)
+				if elapsed < asyncProgressDelay {
+					continue
+				}
+				mu.Lock()
+				msg := asyncProgressMessage(state, elapsed)
+				mu.Unlock()
+				_, _ = fmt.Fprintf(os.Stderr, "\r\033[2K%s %s", frames[frame%len(frames)], msg)
+				frame++
+				drawn = true
+			}
+		}
+	}()
+
+	return update, func() {
+		close(stop)
+		<-done
+	}
+}
+
+// asyncProgressMessage renders the progress line, e.g.
+// "Running slow checks: 1 of 3 resolved (alpine:3.20, 4s elapsed)".
+func asyncProgressMessage(p async.Progress, elapsed time.Duration) string {
+	msg := fmt.Sprintf("Running slow checks: %d of %d resolved", p.Resolved, p.Total)
+	details := make([]string, 0, 2)
+	if p.Resolved < p.Total {
+		if label := asyncProgressLabel(p.Current); label != "" {
+			details = append(details, label)
+		}
+	}
+	details = append(details, elapsed.Truncate(time.Second).String()+" elapsed")
+	return msg + " (" + strings.Join(details, ", ") + ")"
+}
+
+// asyncProgressLabel shortens a resolution key for display. Registry keys
+// carry the platform after "|" and some keys join several URLs with newlines;
+// only the first reference is shown.
+func asyncProgressLabel(key async.ResolutionKey) string {
+	label, _, _ := strings.Cut(key.Key, "\n")
+	label, _, _ = strings.Cut(label, "|")
+	const maxLen = 60
+	if len(label) > maxLen {
+		label = label[:maxLen-3] + "..."
+	}
+	return label
+}
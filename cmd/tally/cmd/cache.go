@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/registry"
+)
+
+func cacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and purge the registry lookup cache used by slow checks",
+	}
+	cmd.AddCommand(cacheDirCommand(), cacheListCommand(), cachePurgeCommand())
+	return cmd
+}
+
+// openRegistryCache opens the registry disk cache with the cache TTL from the
+// configuration discovered for the current directory.
+func openRegistryCache() (*registry.DiskCache, error) {
+	dir, err := registry.DefaultCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("locate cache directory: %w", err)
+	}
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := time.ParseDuration(cfg.SlowChecks.CacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slow-checks.cache-ttl %q: %w", cfg.SlowChecks.CacheTTL, err)
+	}
+	return registry.NewDiskCache(dir, ttl), nil
+}
+
+func cacheDirCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dir",
+		Short: "Print the cache directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := registry.DefaultCacheDir()
+			if err != nil {
+				return err
+			}
+			fmt.Println(dir)
+			return nil
+		},
+	}
+}
+
+type cacheListEntry struct {
+	registry.CacheEntry
+
+	Expired bool `json:"expired"`
+}
+
+func cacheListCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List cached registry lookups",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := openRegistryCache()
+			if err != nil {
+				return err
+			}
+			entries, err := cache.Entries()
+			if err != nil {
+				return err
+			}
+
+			ttl, now := cache.TTL(), time.Now()
+			if asJSON {
+				out := make([]cacheListEntry, 0, len(entries))
+				for _, e := range entries {
+					out = append(out, cacheListEntry{CacheEntry: e, Expired: e.Expired(ttl, now)})
+				}
+				return json.MarshalWrite(os.Stdout, out, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+			}
+
+			if len(entries) == 0 {
+				fmt.Fprintf(os.Stderr, "No cached registry lookups in %s\n", cache.Dir())
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "REF\tPLATFORM\tDIGEST\tAGE\tSTATUS")
+			for _, e := range entries {
+				status := "fresh"
+				switch {
+				case e.Pinned():
+					status = "pinned"
+				case e.Expired(ttl, now):
+					status = "expired"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					e.Ref, e.Platform, e.Config.Digest, now.Sub(e.FetchedAt).Round(time.Second), status)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output cache entries as JSON")
+	return cmd
+}
+
+func cachePurgeCommand() *cobra.Command {
+	var expiredOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove cached registry lookups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := openRegistryCache()
+			if err != nil {
+				return err
+			}
+			n, err := cache.Purge(expiredOnly)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Removed %d cached registry lookup(s) from %s\n", n, cache.Dir())
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&expiredOnly, "expired", false, "Only remove entries older than slow-checks.cache-ttl")
+	return cmd
+}
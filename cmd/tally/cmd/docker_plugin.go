@@ -42,10 +42,13 @@ func newDockerLintPluginCommand(dockerCLI command.Cli) *cobra.Command {
 	cmd := newLintCommand(opts)
 	cmd.Version = version.Version()
 	cmd.SetVersionTemplate("tally version {{.Version}}\n")
+	logOpts := &logOptions{}
+	logOpts.register(cmd.PersistentFlags())
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		if err := plugin.PersistentPreRunE(cmd, args); err != nil {
 			return err
 		}
+		logOpts.setup()
 		opts.dockerPlugin = dockerPluginContextFrom(dockerCLI)
 		return nil
 	}
@@ -0,0 +1,445 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/registry"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shellcheck"
+	"github.com/wharflab/tally/internal/version"
+)
+
+// defaultDoctorImage is the image resolved to check registry connectivity
+// when no --image is given.
+const defaultDoctorImage = "docker.io/library/alpine:latest"
+
+// Doctor check statuses, from best to worst.
+const (
+	doctorOK   = "ok"
+	doctorInfo = "info"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorCheck is one line of the doctor report.
+type doctorCheck struct {
+	Section string `json:"section"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail"`
+	// Hint suggests how to fix a failed or degraded check.
+	Hint string `json:"hint,omitempty"`
+}
+
+// doctorReport collects checks in report order.
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks"`
+}
+
+func (r *doctorReport) add(section, name, status, detail, hint string) {
+	r.Checks = append(r.Checks, doctorCheck{Section: section, Name: name, Status: status, Detail: detail, Hint: hint})
+}
+
+// failed reports whether any check failed.
+func (r *doctorReport) failed() bool {
+	return slices.ContainsFunc(r.Checks, func(c doctorCheck) bool { return c.Status == doctorFail })
+}
+
+func doctorCommand() *cobra.Command {
+	var (
+		configPath string
+		images     []string
+		offline    bool
+		asJSON     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the environment tally runs in",
+		Long: `Diagnose the environment tally runs in: the binary's build, the
+configuration discovered for the current directory (or --config), the rules
+it enables, registry credentials and connectivity used by slow checks,
+ShellCheck, and the ACP agent used by AI AutoFix.
+
+Registry connectivity is checked by resolving ` + defaultDoctorImage + `,
+or each --image, without the lookup cache. Use --offline to skip it.
+
+Exits with code 1 when a check fails.
+
+Examples:
+  tally doctor
+  tally doctor --image registry.internal:5000/base/alpine:3.20
+  tally doctor --offline --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			report := &doctorReport{}
+
+			checkDoctorBuild(ctx, report)
+			cfg := checkDoctorConfig(report, configPath)
+			checkDoctorRules(report, cfg)
+			checkDoctorCredentials(report, cfg)
+			if offline {
+				report.add("Registry", "connectivity", doctorInfo, "skipped (--offline)", "")
+			} else {
+				if len(images) == 0 {
+					images = []string{defaultDoctorImage}
+				}
+				checkDoctorRegistry(ctx, report, cfg, images)
+			}
+			checkDoctorAI(report, cfg)
+
+			if asJSON {
+				if err := json.MarshalWrite(os.Stdout, report, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  ")); err != nil {
+					return err
+				}
+			} else if err := writeDoctorReport(os.Stdout, report); err != nil {
+				return err
+			}
+			if report.failed() {
+				return exitWith(ExitViolations)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to config file (default: auto-discover)")
+	cmd.Flags().StringSliceVar(&images, "image", nil, "Image to resolve when checking registry access (can be repeated)")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Skip the registry connectivity check")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output the report as JSON")
+	return cmd
+}
+
+// checkDoctorBuild reports the version and the optional features compiled
+// into the binary.
+func checkDoctorBuild(ctx context.Context, report *doctorReport) {
+	info := version.GetInfo()
+	report.add("Build", "version", doctorOK,
+		fmt.Sprintf("%s (%s, %s/%s)", info.Version, info.GoVersion, info.Platform.OS, info.Platform.Arch), "")
+
+	if registry.NewDefaultResolver == nil {
+		report.add("Build", "registry support", doctorWarn,
+			"not compiled in; slow checks that query registries are skipped",
+			"build with -tags containers_image_openpgp,containers_image_storage_stub,containers_image_docker_daemon_stub "+
+				"or install a release binary")
+	} else {
+		report.add("Build", "registry support", doctorOK, "compiled in", "")
+	}
+
+	runner := shellcheck.NewRunner()
+	defer runner.Close(ctx)
+	if v, err := runner.Version(ctx); err != nil {
+		report.add("Build", "shellcheck", doctorFail, err.Error(), "shellcheck/* rules report internal errors until this is fixed")
+	} else {
+		report.add("Build", "shellcheck", doctorOK, "version "+v, "")
+	}
+}
+
+// checkDoctorConfig reports which config file applies to the current
+// directory and the slow-checks settings it results in. It returns the
+// loaded config, or the defaults when loading fails.
+func checkDoctorConfig(report *doctorReport, configPath string) *config.Config {
+	var (
+		cfg *config.Config
+		err error
+	)
+	if configPath != "" {
+		cfg, err = config.LoadFromFileWithFlags(configPath, nil, nil)
+	} else {
+		// Discovery starts from the target file's directory, so use a
+		// synthetic file under ".".
+		target := filepath.Join(".", "Dockerfile")
+		configPath = config.Discover(target)
+		cfg, err = config.Load(target)
+	}
+	switch {
+	case err != nil:
+		report.add("Config", "config file", doctorFail, err.Error(), "run tally lint to see the full validation error")
+		cfg = config.Default()
+	case configPath == "":
+		report.add("Config", "config file", doctorInfo,
+			"none found; using defaults (looked for "+strings.Join(config.ConfigFileNames, ", ")+")", "")
+	default:
+		report.add("Config", "config file", doctorOK, configPath, "")
+	}
+	if cfg.Profile != "" {
+		report.add("Config", "profile", doctorInfo, cfg.Profile, "")
+	}
+
+	mode := cfg.SlowChecks.Mode
+	if mode == "" {
+		mode = "auto"
+	}
+	detail := fmt.Sprintf("mode %s, timeout %s, cache-ttl %s", mode, cfg.SlowChecks.Timeout, cfg.SlowChecks.CacheTTL)
+	if ci := config.CIName(); ci != "" {
+		detail += "; running in CI (" + ci + ")"
+	}
+	if config.SlowChecksEnabled(mode) {
+		report.add("Config", "slow checks", doctorOK, "enabled: "+detail, "")
+	} else {
+		report.add("Config", "slow checks", doctorInfo, "disabled: "+detail,
+			"pass --slow-checks=on or set slow-checks.mode = \"on\" to enable them")
+	}
+	return cfg
+}
+
+// checkDoctorRules reports how many registered rules the config enables,
+// per namespace.
+func checkDoctorRules(report *doctorReport, cfg *config.Config) {
+	enabled := make(map[string]int)
+	for _, code := range linter.EnabledRuleCodes(cfg) {
+		enabled[ruleNamespace(code)]++
+	}
+
+	namespaces := make([]string, 0, len(enabled))
+	for ns := range enabled {
+		namespaces = append(namespaces, ns)
+	}
+	slices.Sort(namespaces)
+	total := 0
+	parts := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		total += enabled[ns]
+		parts = append(parts, fmt.Sprintf("%s %d", ns, enabled[ns]))
+	}
+
+	status := doctorOK
+	hint := ""
+	if total == 0 {
+		status = doctorWarn
+		hint = "check rules.include and rules.exclude in the config"
+	}
+	report.add("Rules", "enabled", status, fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", ")), hint)
+	report.add("Rules", "registered", doctorInfo, strconv.Itoa(len(rules.DefaultRegistry().All())), "")
+}
+
+// ruleNamespace returns the namespace of a rule code, e.g. "hadolint" for
+// "hadolint/DL3006".
+func ruleNamespace(code string) string {
+	ns, _, _ := strings.Cut(code, "/")
+	return ns
+}
+
+// dockerConfigFile is the part of Docker's config.json that selects
+// credential sources.
+type dockerConfigFile struct {
+	Auths       map[string]jsontext.Value `json:"auths"`
+	CredsStore  string                    `json:"credsStore"`
+	CredHelpers map[string]string         `json:"credHelpers"`
+}
+
+// checkDoctorCredentials reports the credential sources registry lookups
+// use: Docker's config.json with its credential store and helpers, and the
+// REGISTRY_AUTH_FILE override.
+func checkDoctorCredentials(report *doctorReport, cfg *config.Config) {
+	if path := os.Getenv("REGISTRY_AUTH_FILE"); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			report.add("Credentials", "REGISTRY_AUTH_FILE", doctorWarn, err.Error(), "unset it or point it at an auth.json")
+		} else {
+			report.add("Credentials", "REGISTRY_AUTH_FILE", doctorOK, path, "")
+		}
+	}
+
+	path := doctorDockerConfigPath()
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		report.add("Credentials", "docker config", doctorInfo,
+			path+" not found; only public images can be resolved", "run docker login for private registries")
+	case err != nil:
+		report.add("Credentials", "docker config", doctorWarn, err.Error(), "")
+	default:
+		var dockerCfg dockerConfigFile
+		if err := json.Unmarshal(data, &dockerCfg); err != nil {
+			report.add("Credentials", "docker config", doctorFail, fmt.Sprintf("%s: %v", path, err), "fix or remove the file")
+			break
+		}
+		hosts := make([]string, 0, len(dockerCfg.Auths))
+		for host := range dockerCfg.Auths {
+			hosts = append(hosts, host)
+		}
+		slices.Sort(hosts)
+		detail := path
+		if len(hosts) > 0 {
+			detail += "; logins: " + strings.Join(hosts, ", ")
+		}
+		report.add("Credentials", "docker config", doctorOK, detail, "")
+
+		if dockerCfg.CredsStore != "" {
+			checkCredentialHelper(report, "credsStore", dockerCfg.CredsStore)
+		}
+		helperHosts := make([]string, 0, len(dockerCfg.CredHelpers))
+		for host := range dockerCfg.CredHelpers {
+			helperHosts = append(helperHosts, host)
+		}
+		slices.Sort(helperHosts)
+		for _, host := range helperHosts {
+			checkCredentialHelper(report, "credHelpers["+host+"]", dockerCfg.CredHelpers[host])
+		}
+	}
+
+	hosts := make([]string, 0, len(cfg.SlowChecks.Registries))
+	for host := range cfg.SlowChecks.Registries {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+	for _, host := range hosts {
+		reg := cfg.SlowChecks.Registries[host]
+		if reg.CertsDir == "" {
+			continue
+		}
+		if info, err := os.Stat(reg.CertsDir); err != nil || !info.IsDir() {
+			report.add("Credentials", host+" certs-dir", doctorFail, reg.CertsDir+" is not a directory",
+				"fix slow-checks.registries."+host+".certs-dir")
+		} else {
+			report.add("Credentials", host+" certs-dir", doctorOK, reg.CertsDir, "")
+		}
+	}
+}
+
+// checkCredentialHelper reports whether the docker-credential-<name> helper
+// binary is on PATH.
+func checkCredentialHelper(report *doctorReport, name, helper string) {
+	bin := "docker-credential-" + helper
+	if path, err := exec.LookPath(bin); err != nil {
+		report.add("Credentials", name, doctorFail, bin+" not found on PATH",
+			"install the helper or remove it from the docker config")
+	} else {
+		report.add("Credentials", name, doctorOK, path, "")
+	}
+}
+
+// doctorDockerConfigPath returns the path of Docker's config.json, honoring
+// DOCKER_CONFIG.
+func doctorDockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".docker", "config.json")
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// checkDoctorRegistry resolves each image with the registry settings of
+// cfg, bypassing the lookup cache.
+func checkDoctorRegistry(ctx context.Context, report *doctorReport, cfg *config.Config, images []string) {
+	if registry.NewDefaultResolver == nil {
+		report.add("Registry", "connectivity", doctorInfo, "skipped (registry support not compiled in)", "")
+		return
+	}
+	opts := registry.OptionsFromConfig(cfg)
+	opts.CacheTTL = 0
+	resolver := registry.NewDefaultResolver(opts)
+
+	timeout, err := time.ParseDuration(cfg.SlowChecks.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 20 * time.Second
+	}
+	for _, image := range images {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		resolved, err := resolver.ResolveConfig(checkCtx, image, "linux/amd64")
+		elapsed := time.Since(start).Round(time.Millisecond)
+		cancel()
+
+		status, detail, hint := doctorOK, fmt.Sprintf("resolved %s in %s", resolved.Digest, elapsed), ""
+		if err != nil {
+			status, detail, hint = classifyDoctorRegistryError(err)
+		}
+		report.add("Registry", image, status, detail, hint)
+	}
+}
+
+// classifyDoctorRegistryError turns a resolver error into a check status,
+// detail and hint.
+func classifyDoctorRegistryError(err error) (string, string, string) {
+	if _, ok := errors.AsType[*registry.PlatformMismatchError](err); ok {
+		return doctorOK, "reachable (no linux/amd64 variant)", ""
+	}
+	if _, ok := errors.AsType[*registry.NotFoundError](err); ok {
+		return doctorWarn, "reachable, but " + err.Error(), "check the image reference"
+	}
+	if _, ok := errors.AsType[*registry.AuthError](err); ok {
+		return doctorFail, err.Error(), "run docker login for this registry, or check the credential helper"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return doctorFail, "timed out: " + err.Error(), "raise slow-checks.timeout or check proxy settings"
+	}
+	return doctorFail, err.Error(),
+		"check network access and proxy settings; use slow-checks.registries for private CAs or insecure registries"
+}
+
+// checkDoctorAI reports whether the configured ACP agent can be started.
+func checkDoctorAI(report *doctorReport, cfg *config.Config) {
+	if len(cfg.AI.Command) == 0 {
+		status := doctorInfo
+		if cfg.AI.Enabled {
+			status = doctorFail
+		}
+		report.add("AI", "agent", status, "no ai.command configured", "set ai.command or pass --acp-command to use AI AutoFix")
+		return
+	}
+	agent := cfg.AI.Command[0]
+	state := "disabled"
+	if cfg.AI.Enabled {
+		state = "enabled"
+	}
+	if path, err := exec.LookPath(agent); err != nil {
+		status := doctorWarn
+		if cfg.AI.Enabled {
+			status = doctorFail
+		}
+		report.add("AI", "agent", status, fmt.Sprintf("%s not found (%s)", agent, state), "install the agent or fix ai.command")
+	} else {
+		report.add("AI", "agent", doctorOK, fmt.Sprintf("%s (%s)", path, state), "")
+	}
+}
+
+// writeDoctorReport prints the checks grouped by section.
+func writeDoctorReport(w io.Writer, report *doctorReport) error {
+	section := ""
+	for _, c := range report.Checks {
+		if c.Section != section {
+			if section != "" {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			section = c.Section
+			if _, err := fmt.Fprintln(w, section); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "  [%-4s] %s: %s\n", c.Status, c.Name, c.Detail); err != nil {
+			return err
+		}
+		if c.Hint != "" && c.Status != doctorOK {
+			if _, err := fmt.Fprintf(w, "         hint: %s\n", c.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	if report.failed() {
+		_, err := fmt.Fprintln(w, "\nSome checks failed.")
+		return err
+	}
+	return nil
+}
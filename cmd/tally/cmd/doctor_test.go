@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/registry"
+)
+
+func TestCheckDoctorCredentials(t *testing.T) {
+	dir := t.TempDir()
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{
+  "auths": {"ghcr.io": {}, "registry.internal:5000": {}},
+  "credsStore": "present",
+  "credHelpers": {"gcr.io": "missing"}
+}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "docker-credential-present"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("PATH", binDir)
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+
+	cfg := config.Default()
+	cfg.SlowChecks.Registries = map[string]config.RegistryConfig{
+		"registry.internal:5000": {CertsDir: filepath.Join(dir, "missing")},
+	}
+	report := &doctorReport{}
+	checkDoctorCredentials(report, cfg)
+
+	want := map[string]string{
+		"docker config":                    doctorOK,
+		"credsStore":                       doctorOK,
+		"credHelpers[gcr.io]":              doctorFail,
+		"registry.internal:5000 certs-dir": doctorFail,
+	}
+	got := make(map[string]doctorCheck)
+	for _, c := range report.Checks {
+		got[c.Name] = c
+	}
+	for name, status := range want {
+		if got[name].Status != status {
+			t.Errorf("%s: status %q, want %q (%+v)", name, got[name].Status, status, got[name])
+		}
+	}
+	if d := got["docker config"].Detail; !strings.Contains(d, "logins: ghcr.io, registry.internal:5000") {
+		t.Errorf("docker config detail = %q, want the login hosts", d)
+	}
+	if !report.failed() {
+		t.Error("report with failed checks is not marked failed")
+	}
+}
+
+func TestCheckDoctorCredentials_NoDockerConfig(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+
+	report := &doctorReport{}
+	checkDoctorCredentials(report, config.Default())
+	if len(report.Checks) != 1 || report.Checks[0].Status != doctorInfo {
+		t.Fatalf("checks = %+v, want one info check", report.Checks)
+	}
+	if report.failed() {
+		t.Error("missing docker config should not fail the report")
+	}
+}
+
+func TestClassifyDoctorRegistryError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&registry.PlatformMismatchError{Ref: "alpine", Requested: "linux/amd64"}, doctorOK},
+		{&registry.NotFoundError{Ref: "alpine:nope", Err: errors.New("manifest unknown")}, doctorWarn},
+		{&registry.AuthError{Err: errors.New("unauthorized")}, doctorFail},
+		{&registry.NetworkError{Err: errors.New("connection refused")}, doctorFail},
+		{context.DeadlineExceeded, doctorFail},
+	}
+	for _, tt := range tests {
+		if got, _, _ := classifyDoctorRegistryError(tt.err); got != tt.want {
+			t.Errorf("classifyDoctorRegistryError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWriteDoctorReport(t *testing.T) {
+	t.Parallel()
+	report := &doctorReport{}
+	report.add("Build", "registry support", doctorOK, "compiled in", "")
+	report.add("AI", "agent", doctorFail, "gemini not found (enabled)", "install the agent or fix ai.command")
+
+	var buf bytes.Buffer
+	if err := writeDoctorReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	want := `Build
+  [ok  ] registry support: compiled in
+
+AI
+  [fail] agent: gemini not found (enabled)
+         hint: install the agent or fix ai.command
+
+Some checks failed.
+`
+	if got := buf.String(); got != want {
+		t.Errorf("report =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// expectedFileName is the approval file that --update-expected writes next to
+// linted Dockerfiles and --verify-expected compares against.
+const expectedFileName = ".tally-expected.json"
+
+// expectedViolation is one approved violation. Columns and fixes are left out
+// so that edits which only shift text within a line keep the file stable.
+type expectedViolation struct {
+	Rule     string `json:"rule"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// expectedFile maps a Dockerfile's base name to its approved violations,
+// for every Dockerfile in the directory holding the approval file.
+type expectedFile map[string][]expectedViolation
+
+func compareExpected(a, b expectedViolation) int {
+	return cmp.Or(
+		cmp.Compare(a.Line, b.Line),
+		cmp.Compare(a.Rule, b.Rule),
+		cmp.Compare(a.Message, b.Message),
+		cmp.Compare(a.Severity, b.Severity),
+	)
+}
+
+// expectedByFile groups violations by linted file. Every file in files gets
+// an entry, so files without violations are approved as clean.
+func expectedByFile(files []string, violations []rules.Violation) map[string][]expectedViolation {
+	byFile := make(map[string][]expectedViolation, len(files))
+	for _, file := range files {
+		byFile[file] = []expectedViolation{}
+	}
+	for _, v := range violations {
+		if _, ok := byFile[v.File()]; !ok {
+			continue
+		}
+		byFile[v.File()] = append(byFile[v.File()], expectedViolation{
+			Rule:     v.RuleCode,
+			Line:     max(v.Line(), 0),
+			Severity: v.Severity.String(),
+			Message:  v.Message,
+		})
+	}
+	for _, list := range byFile {
+		slices.SortFunc(list, compareExpected)
+	}
+	return byFile
+}
+
+func readExpectedFile(dir string) (expectedFile, error) {
+	data, err := os.ReadFile(filepath.Join(dir, expectedFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return expectedFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ef expectedFile
+	if err := json.Unmarshal(data, &ef); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, expectedFileName), err)
+	}
+	if ef == nil {
+		ef = expectedFile{}
+	}
+	return ef, nil
+}
+
+// updateExpected records the violations of each linted file in the approval
+// file of its directory. Entries for Dockerfiles that were not linted in this
+// run are kept.
+func updateExpected(files []string, violations []rules.Violation) (int, error) {
+	byDir := make(map[string][]string)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		byDir[dir] = append(byDir[dir], file)
+	}
+	actual := expectedByFile(files, violations)
+
+	for dir, dirFiles := range byDir {
+		ef, err := readExpectedFile(dir)
+		if err != nil {
+			return 0, err
+		}
+		for _, file := range dirFiles {
+			ef[filepath.Base(file)] = actual[file]
+		}
+		data, err := json.Marshal(ef, json.Deterministic(true), jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+		if err != nil {
+			return 0, err
+		}
+		data = append(data, '\n')
+		if err := os.WriteFile(filepath.Join(dir, expectedFileName), data, 0o644); err != nil {
+			return 0, err
+		}
+	}
+	return len(byDir), nil
+}
+
+// verifyExpected compares the violations of each linted file with its
+// approval file and writes one line per difference to w. A file without an
+// approval entry is expected to have no violations. It returns the number of
+// files that differ.
+func verifyExpected(w io.Writer, files []string, violations []rules.Violation) (int, error) {
+	actual := expectedByFile(files, violations)
+	approved := make(map[string]expectedFile)
+	sorted := slices.Sorted(slices.Values(files))
+
+	differing := 0
+	for _, file := range sorted {
+		dir := filepath.Dir(file)
+		ef, ok := approved[dir]
+		if !ok {
+			var err error
+			if ef, err = readExpectedFile(dir); err != nil {
+				return 0, err
+			}
+			approved[dir] = ef
+		}
+
+		unexpected, missing := diffExpected(ef[filepath.Base(file)], actual[file])
+		if len(unexpected) == 0 && len(missing) == 0 {
+			continue
+		}
+		differing++
+		for _, v := range unexpected {
+			fmt.Fprintf(w, "%s:%d: unexpected %s: %s\n", file, v.Line, v.Rule, v.Message)
+		}
+		for _, v := range missing {
+			fmt.Fprintf(w, "%s:%d: missing %s: %s\n", file, v.Line, v.Rule, v.Message)
+		}
+	}
+	return differing, nil
+}
+
+// diffExpected returns the violations in actual but not in want, and those in
+// want but not in actual. Both inputs are compared as multisets.
+func diffExpected(want, actual []expectedViolation) ([]expectedViolation, []expectedViolation) {
+	remaining := make(map[expectedViolation]int, len(want))
+	for _, v := range want {
+		remaining[v]++
+	}
+	var unexpected []expectedViolation
+	for _, v := range actual {
+		if remaining[v] > 0 {
+			remaining[v]--
+			continue
+		}
+		unexpected = append(unexpected, v)
+	}
+	var missing []expectedViolation
+	for _, v := range want {
+		if remaining[v] > 0 {
+			remaining[v]--
+			missing = append(missing, v)
+		}
+	}
+	slices.SortFunc(missing, compareExpected)
+	return unexpected, missing
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestExpectedRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	app := filepath.Join(dir, "Dockerfile")
+	dev := filepath.Join(dir, "Dockerfile.dev")
+	files := []string{app, dev}
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation(app, 3), "tally/b", "second", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation(app, 1), "tally/a", "first", rules.SeverityError),
+		rules.NewViolation(rules.NewFileLocation(app), "tally/max-lines", "too long", rules.SeverityStyle),
+	}
+
+	written, err := updateExpected(files, violations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 1 {
+		t.Fatalf("updateExpected() wrote %d files, want 1", written)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, expectedFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clean files are recorded too, and entries are ordered by line.
+	if !strings.Contains(string(data), `"Dockerfile.dev": []`) {
+		t.Errorf("expected an empty entry for Dockerfile.dev:\n%s", data)
+	}
+	if strings.Index(string(data), `"tally/max-lines"`) > strings.Index(string(data), `"tally/a"`) {
+		t.Errorf("expected file-level violations first:\n%s", data)
+	}
+
+	var out bytes.Buffer
+	differing, err := verifyExpected(&out, files, violations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if differing != 0 || out.Len() != 0 {
+		t.Fatalf("verifyExpected() = %d differing files:\n%s", differing, out.String())
+	}
+
+	changed := []rules.Violation{
+		violations[0],
+		violations[2],
+		rules.NewViolation(rules.NewLineLocation(dev, 2), "tally/c", "new", rules.SeverityInfo),
+	}
+	differing, err = verifyExpected(&out, files, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if differing != 2 {
+		t.Errorf("verifyExpected() = %d differing files, want 2", differing)
+	}
+	want := app + ":1: missing tally/a: first\n" + dev + ":2: unexpected tally/c: new\n"
+	if out.String() != want {
+		t.Errorf("verifyExpected() output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestUpdateExpectedKeepsOtherEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	app := filepath.Join(dir, "Dockerfile")
+	dev := filepath.Join(dir, "Dockerfile.dev")
+	devViolation := rules.NewViolation(rules.NewLineLocation(dev, 1), "tally/a", "dev", rules.SeverityWarning)
+	if _, err := updateExpected([]string{dev}, []rules.Violation{devViolation}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := updateExpected([]string{app}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	differing, err := verifyExpected(&out, []string{app, dev}, []rules.Violation{devViolation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if differing != 0 {
+		t.Errorf("verifyExpected() = %d differing files:\n%s", differing, out.String())
+	}
+}
+
+func TestDiffExpectedCountsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	v := expectedViolation{Rule: "tally/a", Line: 2, Severity: "warning", Message: "m"}
+	unexpected, missing := diffExpected([]expectedViolation{v}, []expectedViolation{v, v})
+	if len(unexpected) != 1 || len(missing) != 0 {
+		t.Errorf("diffExpected() = %v, %v; want one unexpected duplicate", unexpected, missing)
+	}
+	unexpected, missing = diffExpected([]expectedViolation{v, v}, []expectedViolation{v})
+	if len(unexpected) != 0 || len(missing) != 1 {
+		t.Errorf("diffExpected() = %v, %v; want one missing duplicate", unexpected, missing)
+	}
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"bytes"
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/discovery"
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/processor"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/tally"
+	"github.com/wharflab/tally/internal/syntax"
+)
+
+// formattingRules are the rules whose fixes tally fmt applies. They only
+// change layout (whitespace, casing, flag order), never what gets built.
+// Rules that are disabled in the file's config are skipped as usual, so
+// e.g. tally/consistent-indentation only applies once it is enabled.
+var formattingRules = []string{
+	"buildkit/ConsistentInstructionCasing",
+	tally.ConsistentIndentationRuleCode,
+	tally.EolLastRuleCode,
+	tally.FlagOrderRuleCode,
+	tally.NewlineBetweenInstructionsRuleCode,
+	tally.NewlinePerChainedCallRuleCode,
+	tally.NoMultiSpacesRuleCode,
+	tally.NoMultipleEmptyLinesRuleCode,
+	tally.NoTrailingSpacesRuleCode,
+	rules.FormattedHeredocsRuleCode,
+}
+
+// fmtIterations bounds the fix passes per file. Formatting fixes can expose
+// each other (splitting a chain adds lines to indent), so fmt keeps going
+// until a second run would change nothing.
+const fmtIterations = 5
+
+func fmtCommand() *cobra.Command {
+	opts := &lintOptions{}
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt [flags] [DOCKERFILE...]",
+		Short: "Format Dockerfiles in place",
+		Long: `Format Dockerfiles in place by applying the fixes of tally's formatting
+rules: instruction casing, indentation, flag order, blank lines, chained
+commands, trailing whitespace and the final newline.
+
+Only layout changes; the build result stays the same. Rule configuration
+from .tally.toml applies, so disabled rules and fix = "never" are honored.
+
+If no files are specified, tally formats the Dockerfiles under the current
+directory. Use "-" to format stdin and write the result to stdout.
+
+Examples:
+  tally fmt
+  tally fmt Dockerfile
+  tally fmt --check .
+  cat Dockerfile | tally fmt -`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.flags = cmd.Flags()
+			ctx := cmd.Context()
+			defer installPowerShellUnavailableReporter(os.Stderr)()
+			defer closeSharedPowerShellRunner(ctx)
+
+			if len(args) == 1 && args[0] == "-" {
+				return runFmtStdin(ctx, opts, check, os.Stdin, os.Stdout)
+			}
+			return runFmt(ctx, opts, check, args)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.configPath, "config", "c", "", "Path to config file (default: auto-discover)")
+	cmd.Flags().BoolVar(&opts.noConfig, "no-config", false, "Ignore config files and use built-in defaults")
+	cmd.Flags().StringSliceVar(&opts.exclude, "exclude", nil, "Glob pattern(s) to exclude files")
+	cmd.Flags().BoolVar(&check, "check", false, "List files that are not formatted and exit 1 instead of writing")
+	return cmd
+}
+
+func runFmt(ctx stdcontext.Context, opts *lintOptions, check bool, args []string) error {
+	inputs := args
+	if len(inputs) == 0 {
+		inputs = []string{"."}
+	}
+	discovered, err := discovery.Discover(inputs, discovery.Options{
+		Patterns:        discovery.DefaultPatterns(),
+		ExcludePatterns: opts.exclude,
+	})
+	if err != nil {
+		if notFound, ok := errors.AsType[*discovery.FileNotFoundError](err); ok {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", notFound)
+			return exitWith(ExitNoFiles)
+		}
+		fmt.Fprintf(os.Stderr, "Error: failed to discover files: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	if len(discovered) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no Dockerfiles found\n")
+		return exitWith(ExitNoFiles)
+	}
+
+	unformatted := 0
+	for _, df := range discovered {
+		cfg, err := loadConfigForFile(opts, df.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+			return exitWith(ExitConfigError)
+		}
+		content, err := os.ReadFile(df.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitWith(ExitConfigError)
+		}
+		formatted, err := formatDockerfile(ctx, df.Path, content, cfg)
+		if err != nil {
+			return handleLintError(err)
+		}
+		if bytes.Equal(formatted, content) {
+			continue
+		}
+		unformatted++
+		if check {
+			fmt.Fprintln(os.Stdout, df.Path)
+			continue
+		}
+		mode := os.FileMode(0o644)
+		if info, err := os.Stat(df.Path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(df.Path, formatted, mode); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", df.Path, err)
+			return exitWith(ExitConfigError)
+		}
+	}
+
+	if check && unformatted > 0 {
+		return exitWith(ExitViolations)
+	}
+	return nil
+}
+
+// runFmtStdin formats a Dockerfile read from r and writes it to w. With
+// check, nothing is written and the exit code tells whether it changed.
+func runFmtStdin(ctx stdcontext.Context, opts *lintOptions, check bool, r io.Reader, w io.Writer) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read stdin: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	// Config discovery starts from the target file's directory, so use a
+	// synthetic file under ".".
+	cfg, err := loadConfigForFile(opts, filepath.Join(".", "Dockerfile"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	formatted, err := formatDockerfile(ctx, stdinPath, content, cfg)
+	if err != nil {
+		return handleLintError(err)
+	}
+	if check {
+		if !bytes.Equal(formatted, content) {
+			return exitWith(ExitViolations)
+		}
+		return nil
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// formatDockerfile returns content with the fixes of the enabled formatting
+// rules applied. Files with syntax errors are refused with a
+// *syntax.CheckError rather than formatted.
+func formatDockerfile(ctx stdcontext.Context, path string, content []byte, cfg *config.Config) ([]byte, error) {
+	parseResult, err := dockerfile.Parse(bytes.NewReader(content), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if syntaxErrors := syntax.Check(path, parseResult.AST, parseResult.Source); len(syntaxErrors) > 0 {
+		return nil, &syntax.CheckError{Errors: syntaxErrors}
+	}
+
+	fileConfigs := map[string]*config.Config{path: cfg}
+	relint := func(ctx stdcontext.Context, filePath string, content []byte) ([]rules.Violation, error) {
+		result, err := linter.LintFileContext(ctx, linter.Input{
+			FilePath: filePath,
+			Content:  content,
+			Config:   cfg,
+		})
+		if err != nil {
+			return nil, err
+		}
+		chain, _ := linter.CLIProcessors()
+		procCtx := processor.NewContext(fileConfigs, cfg, map[string][]byte{filePath: content})
+		return chain.Process(result.Violations, procCtx), nil
+	}
+
+	violations, err := relint(ctx, path, content)
+	if err != nil {
+		return nil, err
+	}
+	sources := map[string][]byte{path: content}
+	fixer := &fix.Fixer{
+		SafetyThreshold: fix.FixSafe,
+		RuleFilter:      formattingRules,
+		EnabledRules:    buildPerFileEnabledRules(fileConfigs, sources),
+		FixModes:        buildPerFileFixModes(fileConfigs),
+		Iterations:      fmtIterations,
+		Relint:          relint,
+	}
+	result, err := fixer.Apply(ctx, violations, sources)
+	if err != nil {
+		return nil, err
+	}
+	if fc := result.Changes[filepath.Clean(path)]; fc != nil && fc.HasChanges() {
+		return fc.ModifiedContent, nil
+	}
+	return content, nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/syntax"
+)
+
+func TestFormatDockerfile(t *testing.T) {
+	t.Parallel()
+
+	content := "FROM alpine:3.20  \n" +
+		"RUN --network=none --mount=type=cache,target=/root/.cache apk add curl\n" +
+		"COPY --chown=app --from=build /out /app\n\n\n\n" +
+		"CMD [\"/app\"]"
+	want := "FROM alpine:3.20\n\n" +
+		"RUN --mount=type=cache,target=/root/.cache --network=none apk add curl\n\n" +
+		"COPY --from=build --chown=app /out /app\n\n" +
+		"CMD [\"/app\"]\n"
+
+	got, err := formatDockerfile(t.Context(), "Dockerfile", []byte(content), config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("formatDockerfile() =\n%s\nwant:\n%s", got, want)
+	}
+
+	// Formatting is idempotent.
+	again, err := formatDockerfile(t.Context(), "Dockerfile", got, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(got) {
+		t.Errorf("second formatDockerfile() =\n%s\nwant:\n%s", again, got)
+	}
+}
+
+func TestFormatDockerfileLeavesSemanticIssues(t *testing.T) {
+	t.Parallel()
+
+	// DL3027 (apt) and the untagged base image are lint findings, not formatting.
+	content := "FROM ubuntu\n\nRUN apt install -y curl\n"
+	got, err := formatDockerfile(t.Context(), "Dockerfile", []byte(content), config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Errorf("formatDockerfile() =\n%s\nwant unchanged", got)
+	}
+}
+
+func TestFormatDockerfileRejectsSyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := formatDockerfile(t.Context(), "Dockerfile", []byte("FROM alpine\nFORM alpine\n"), config.Default())
+	if _, ok := errors.AsType[*syntax.CheckError](err); !ok {
+		t.Fatalf("formatDockerfile() error = %v, want *syntax.CheckError", err)
+	}
+}
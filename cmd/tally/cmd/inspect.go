@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/directive"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+func inspectCommand() *cobra.Command {
+	var (
+		directives bool
+		asJSON     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inspect [--directives] <file>...",
+		Short: "Show how tally reads a Dockerfile",
+		Long: `Show how tally reads a Dockerfile without linting it.
+
+With --directives, list the inline directives (# tally ignore=...,
+# tally disable-..., # hadolint ignore=..., # check=skip=..., shell
+overrides) found in each file, with the lines each one applies to.
+The --json output follows the JSON Schema printed by
+"tally schema directives".
+
+Examples:
+  tally inspect --directives Dockerfile
+  tally inspect --directives --json Dockerfile`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !directives {
+				return errors.New("nothing to inspect: pass --directives")
+			}
+
+			files := make([]inspectedFile, 0, len(args))
+			for _, path := range args {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				files = append(files, inspectDirectives(path, content))
+			}
+
+			if asJSON {
+				return json.MarshalWrite(os.Stdout, files, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+			}
+			return writeInspectedDirectives(os.Stdout, files)
+		},
+	}
+
+	cmd.Flags().BoolVar(&directives, "directives", false, "List inline directives and the lines they apply to")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output as JSON")
+	return cmd
+}
+
+// inspectedFile is the dump of the inline directives in one file.
+// Line numbers are 1-based.
+type inspectedFile struct {
+	File            string                   `json:"file"`
+	Directives      []inspectedDirective     `json:"directives"`
+	ShellDirectives []inspectedShellOverride `json:"shellDirectives"`
+	Errors          []inspectedError         `json:"errors"`
+}
+
+type inspectedDirective struct {
+	Line   int      `json:"line"`
+	Type   string   `json:"type"`
+	Source string   `json:"source"`
+	Rules  []string `json:"rules"`
+	// StartLine and EndLine are omitted for file-wide directives and for
+	// directives that do not apply to any line (e.g. at end of file).
+	StartLine int    `json:"startLine,omitzero"`
+	EndLine   int    `json:"endLine,omitzero"`
+	Reason    string `json:"reason,omitzero"`
+	Text      string `json:"text"`
+}
+
+type inspectedShellOverride struct {
+	Line   int    `json:"line"`
+	Shell  string `json:"shell"`
+	Source string `json:"source"`
+	Text   string `json:"text"`
+}
+
+type inspectedError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+	Text    string `json:"text"`
+}
+
+// inspectDirectives parses the inline directives in content the same way the
+// lint pipeline does with default settings (rule codes are not validated).
+func inspectDirectives(path string, content []byte) inspectedFile {
+	sm := sourcemap.New(content)
+	result := directive.Parse(sm, nil, directive.NewInstructionSpanIndexFromSource(content, sm))
+
+	out := inspectedFile{
+		File:            path,
+		Directives:      make([]inspectedDirective, 0, len(result.Directives)),
+		ShellDirectives: make([]inspectedShellOverride, 0, len(result.ShellDirectives)),
+		Errors:          make([]inspectedError, 0, len(result.Errors)),
+	}
+	for _, d := range result.Directives {
+		id := inspectedDirective{
+			Line:   d.Line + 1,
+			Type:   d.Type.String(),
+			Source: string(d.Source),
+			Rules:  d.Rules,
+			Reason: d.Reason,
+			Text:   d.RawText,
+		}
+		if d.AppliesTo != directive.GlobalRange() && d.AppliesTo.Start >= 0 {
+			id.StartLine = d.AppliesTo.Start + 1
+			if d.AppliesTo.End < sm.LineCount() {
+				id.EndLine = d.AppliesTo.End + 1
+			} else {
+				id.EndLine = sm.LineCount()
+			}
+		}
+		out.Directives = append(out.Directives, id)
+	}
+	for _, sd := range result.ShellDirectives {
+		out.ShellDirectives = append(out.ShellDirectives, inspectedShellOverride{
+			Line:   sd.Line + 1,
+			Shell:  sd.Shell,
+			Source: string(sd.Source),
+			Text:   sd.RawText,
+		})
+	}
+	for _, e := range result.Errors {
+		out.Errors = append(out.Errors, inspectedError{Line: e.Line + 1, Message: e.Message, Text: e.RawText})
+	}
+	return out
+}
+
+func writeInspectedDirectives(w io.Writer, files []inspectedFile) error {
+	for i, f := range files {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if len(files) > 1 {
+			fmt.Fprintf(w, "%s:\n", f.File)
+		}
+		if len(f.Directives) == 0 && len(f.ShellDirectives) == 0 && len(f.Errors) == 0 {
+			fmt.Fprintln(w, "No inline directives")
+			continue
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "LINE\tTYPE\tSOURCE\tRULES\tAPPLIES TO\tREASON")
+		for _, d := range f.Directives {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+				d.Line, d.Type, d.Source, strings.Join(d.Rules, ","), appliesToLabel(d), d.Reason)
+		}
+		for _, sd := range f.ShellDirectives {
+			fmt.Fprintf(tw, "%d\tshell\t%s\t-\tshell=%s\t\n", sd.Line, sd.Source, sd.Shell)
+		}
+		for _, e := range f.Errors {
+			fmt.Fprintf(tw, "%d\terror\t-\t-\t-\t%s\n", e.Line, e.Message)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appliesToLabel(d inspectedDirective) string {
+	switch {
+	case d.Type == directive.TypeGlobal.String():
+		return "file"
+	case d.StartLine == 0:
+		return "nothing"
+	case d.StartLine == d.EndLine:
+		return fmt.Sprintf("line %d", d.StartLine)
+	default:
+		return fmt.Sprintf("lines %d-%d", d.StartLine, d.EndLine)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/wharflab/tally/internal/schemas"
+)
+
+func TestInspectDirectives(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`# tally disable-file=max-lines
+FROM alpine AS build
+# tally disable-next-instruction=DL3018;reason=pinned by digest upstream
+RUN apk add curl
+# tally shell=bash
+FROM alpine
+# tally disable-stage
+RUN echo hi
+`)
+	got := inspectDirectives("Dockerfile", content)
+
+	if len(got.Directives) != 2 {
+		t.Fatalf("got %d directives, want 2: %+v", len(got.Directives), got.Directives)
+	}
+	file, next := got.Directives[0], got.Directives[1]
+	if file.Type != "global" || file.StartLine != 0 || file.EndLine != 0 {
+		t.Errorf("disable-file = %+v, want global without a line range", file)
+	}
+	if next.Line != 3 || next.Type != "next-line" || next.StartLine != 4 || next.EndLine != 4 ||
+		next.Reason != "pinned by digest upstream" {
+		t.Errorf("disable-next-instruction = %+v", next)
+	}
+	if len(got.ShellDirectives) != 1 || got.ShellDirectives[0].Shell != "bash" {
+		t.Errorf("shell directives = %+v, want bash", got.ShellDirectives)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].Line != 7 || got.Errors[0].Message != "empty rule list" {
+		t.Errorf("errors = %+v, want empty rule list on line 7", got.Errors)
+	}
+
+	var out bytes.Buffer
+	if err := writeInspectedDirectives(&out, []inspectedFile{got}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"LINE", "file", "line 4", "shell=bash", "empty rule list"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestInspectDirectivesMatchesSchema(t *testing.T) {
+	t.Parallel()
+
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(schemas.DirectivesSchema(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := schema.Resolve(&jsonschema.ResolveOptions{BaseURI: schemas.DirectivesSchemaID})
+	if err != nil {
+		t.Fatalf("resolve directives schema: %v", err)
+	}
+
+	files := []inspectedFile{
+		inspectDirectives("Dockerfile", []byte(`# tally disable-file=max-lines
+FROM alpine
+# tally disable-next-instruction=DL3018;reason=pinned
+RUN apk add curl
+# hadolint shell=bash
+# check=skip=StageNameCasing
+FROM alpine AS Build
+# tally disable-stage
+RUN echo hi
+`)),
+		inspectDirectives("empty.Dockerfile", []byte("FROM scratch\n")),
+	}
+	out, err := json.Marshal(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var instance any
+	if err := json.Unmarshal(out, &instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		t.Errorf("inspect --directives --json does not match the schema: %v\n%s", err, out)
+	}
+}
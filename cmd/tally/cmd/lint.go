@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -20,13 +22,17 @@ import (
 	"github.com/wharflab/tally/internal/ai/autofix"
 	"github.com/wharflab/tally/internal/ai/autofixdata"
 	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/download"
+	"github.com/wharflab/tally/internal/changedlines"
 	"github.com/wharflab/tally/internal/config"
 	"github.com/wharflab/tally/internal/discovery"
 	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/eol"
 	"github.com/wharflab/tally/internal/fileval"
 	"github.com/wharflab/tally/internal/fix"
 	"github.com/wharflab/tally/internal/invocation"
 	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/osv"
 	"github.com/wharflab/tally/internal/processor"
 	"github.com/wharflab/tally/internal/psanalyzer"
 	"github.com/wharflab/tally/internal/registry"
@@ -34,6 +40,7 @@ import (
 	"github.com/wharflab/tally/internal/ruledeprecation"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/syntax"
+	"github.com/wharflab/tally/internal/textdiff"
 	"github.com/wharflab/tally/internal/version"
 )
 
@@ -44,6 +51,7 @@ const (
 	ExitConfigError = 2 // Parse or config error
 	ExitNoFiles     = 3 // No Dockerfiles found (missing file, empty glob, empty directory)
 	ExitSyntaxError = 4 // Dockerfile has fatal syntax issues (unknown instructions, malformed directives)
+	ExitFixed       = 5 // Fixes were applied (--exit-code-on-fix) and no violations remain at or above fail-level
 )
 
 const installPowerShellURL = "https://learn.microsoft.com/en-us/powershell/scripting/install/install-powershell"
@@ -122,14 +130,22 @@ func installPowerShellUnavailableReporter(w io.Writer) func() {
 
 // lintResults holds the aggregated results of linting all discovered files.
 type lintResults struct {
-	violations         []rules.Violation
-	asyncPlans         []async.CheckRequest
-	fileSources        map[string][]byte
-	fileConfigs        map[string]*config.Config
-	fileInvocations    map[string]*invocation.BuildInvocation
-	firstCfg           *config.Config
+	violations      []rules.Violation
+	asyncPlans      []async.CheckRequest
+	fileSources     map[string][]byte
+	fileStats       map[string]reporter.FileStats
+	fileConfigs     map[string]*config.Config
+	fileInvocations map[string]*invocation.BuildInvocation
+	changedLines    changedlines.Files
+	firstCfg        *config.Config
+	// suppressed holds the violations suppressed by inline directives or
+	// rule config; only collected with --show-suppressed.
+	suppressed         []rules.Violation
 	filesScanned       int
 	invocationsScanned int
+
+	// stats collects --stats counters; nil when the flag is not set.
+	stats *runStats
 }
 
 type applyFixesInput struct {
@@ -261,8 +277,8 @@ func runLint(ctx stdcontext.Context, opts *lintOptions, args []string) error {
 		if orchestrator != nil {
 			return runLintOrchestrator(ctx, opts, orchestrator)
 		}
-		if hasOrchestratorSelectionFlags(opts) {
-			fmt.Fprintf(os.Stderr, "Error: --target and --service are only valid for orchestrator entrypoints\n")
+		if err := validateDockerfileSelectionFlags(opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return exitWith(ExitConfigError)
 		}
 	} else if err := rejectMixedOrchestratorInputs(inputs, opts); err != nil {
@@ -291,20 +307,41 @@ func runLint(ctx stdcontext.Context, opts *lintOptions, args []string) error {
 		reportNoFilesFound(inputs)
 		return exitWith(ExitNoFiles)
 	}
+	if opts.changed {
+		discovered, err = filterChangedFiles(ctx, discovered)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitWith(ExitConfigError)
+		}
+		if len(discovered) == 0 {
+			fmt.Fprintf(os.Stderr, "No changed Dockerfiles to lint\n")
+			return nil
+		}
+	}
 
 	// Lint all discovered files
 	res, err := lintFiles(ctx, discovered, opts)
 	if err != nil {
 		return handleLintError(err)
 	}
+	if err := loadChangedLines(ctx, opts, res); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
 
-	asyncResult, asyncPlans := resolveAsyncChecks(ctx, res)
+	asyncResult, asyncPlans := resolveAsyncChecks(ctx, res, opts.quiet)
 
 	allViolations := processViolations(res, res.firstCfg)
+	if opts.updateExpected || opts.verifyExpected {
+		writeStats(opts, res, allViolations)
+		return runExpected(opts, discovered, allViolations)
+	}
 
-	warnFixUnsafe(opts)
+	warnFixOnlyFlags(opts)
+	var fixResult *fix.Result
 	if opts.fix {
-		fixResult, fixErr := applyFixes(ctx, opts, applyFixesInput{
+		var fixErr error
+		fixResult, fixErr = applyFixes(ctx, opts, applyFixesInput{
 			violations:      allViolations,
 			sources:         res.fileSources,
 			fileConfigs:     res.fileConfigs,
@@ -317,14 +354,20 @@ func runLint(ctx stdcontext.Context, opts *lintOptions, args []string) error {
 			return exitWith(ExitConfigError)
 		}
 
-		if err := writeFixedFiles(fixResult); err != nil {
+		writeChanges := writeFixedFiles
+		summary := "Fixed %d issues in %d files\n"
+		if opts.fixOutput == fixOutputPatch {
+			writeChanges = func(result *fix.Result) error { return writeFixPatch(os.Stdout, result) }
+			summary = "Patch fixes %d issues in %d files\n"
+		}
+		if err := writeChanges(fixResult); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			return exitWith(ExitConfigError)
 		}
+		res.stats.addFixes(fixResult)
 
 		if fixResult.TotalApplied() > 0 {
-			fmt.Fprintf(os.Stderr, "Fixed %d issues in %d files\n",
-				fixResult.TotalApplied(), fixResult.FilesModified())
+			fmt.Fprintf(os.Stderr, summary, fixResult.TotalApplied(), fixResult.FilesModified())
 		}
 		if fixResult.TotalSkipped() > 0 {
 			fmt.Fprintf(os.Stderr, "Skipped %d fixes\n", fixResult.TotalSkipped())
@@ -334,28 +377,88 @@ func runLint(ctx stdcontext.Context, opts *lintOptions, args []string) error {
 		allViolations = filterFixedViolations(allViolations, fixResult, res.fileConfigs)
 	}
 
-	return writeReport(opts, res.firstCfg, allViolations, res.fileSources, len(discovered), 0)
+	// With --fix-output patch, stdout carries the patch.
+	var reportPath string
+	if opts.fix && opts.fixOutput == fixOutputPatch {
+		reportPath = reportPathBesideStdout(opts, res.firstCfg, "patch mode (stdout carries the patch)")
+	}
+	writeStats(opts, res, allViolations)
+	return fixedExit(opts, fixResult,
+		writeReportTo(opts, res.firstCfg, allViolations, res.suppressed, res.fileSources, res.fileStats, len(discovered), 0, reportPath))
+}
+
+// runExpected records or verifies the violations of the linted files
+// instead of writing a report.
+func runExpected(opts *lintOptions, discovered []discovery.DiscoveredFile, violations []rules.Violation) error {
+	files := make([]string, 0, len(discovered))
+	for _, df := range discovered {
+		files = append(files, df.Path)
+	}
+
+	if opts.updateExpected {
+		written, err := updateExpected(files, violations)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to update %s: %v\n", expectedFileName, err)
+			return exitWith(ExitConfigError)
+		}
+		fmt.Fprintf(os.Stderr, "Updated %d %s file(s) for %d Dockerfile(s)\n", written, expectedFileName, len(files))
+		return nil
+	}
+
+	differing, err := verifyExpected(os.Stdout, files, violations)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read %s: %v\n", expectedFileName, err)
+		return exitWith(ExitConfigError)
+	}
+	if differing > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d Dockerfile(s) differ from %s; rerun with --update-expected to accept the changes\n",
+			differing, len(files), expectedFileName)
+		return exitWith(ExitViolations)
+	}
+	return nil
 }
 
 // resolveAsyncChecks executes async check plans if enabled and merges the
 // results into res.violations. Returns the async result and filtered plans
-// needed by the fix pipeline.
-func resolveAsyncChecks(ctx stdcontext.Context, res *lintResults) (*async.RunResult, []async.CheckRequest) {
+// needed by the fix pipeline. quiet suppresses the progress spinner.
+func resolveAsyncChecks(ctx stdcontext.Context, res *lintResults, quiet bool) (*async.RunResult, []async.CheckRequest) {
 	if len(res.asyncPlans) == 0 {
 		return nil, nil
 	}
-	asyncResult, asyncPlans := runAsyncChecks(ctx, res)
+	start := time.Now()
+	asyncResult, asyncPlans := runAsyncChecks(ctx, res, quiet)
+	res.stats.addSlowChecks(asyncResult, time.Since(start))
 	if asyncResult != nil {
 		res.violations = linter.MergeAsyncViolations(res.violations, asyncResult)
 	}
 	return asyncResult, asyncPlans
 }
 
-// warnFixUnsafe emits a warning when --fix-unsafe is set without --fix.
-func warnFixUnsafe(opts *lintOptions) {
-	if opts.fixUnsafe && !opts.fix {
+// warnFixOnlyFlags emits a warning for each fix-only flag set without --fix.
+func warnFixOnlyFlags(opts *lintOptions) {
+	if opts.fix {
+		return
+	}
+	if opts.fixUnsafe {
 		fmt.Fprintf(os.Stderr, "Warning: --fix-unsafe has no effect without --fix\n")
 	}
+	if opts.exitCodeOnFix {
+		fmt.Fprintf(os.Stderr, "Warning: --exit-code-on-fix has no effect without --fix\n")
+	}
+	if opts.fixOutput == fixOutputPatch {
+		fmt.Fprintf(os.Stderr, "Warning: --fix-output has no effect without --fix\n")
+	}
+}
+
+// fixedExit returns ExitFixed when --exit-code-on-fix is set, fixes were
+// applied and the report passed the fail-level check; otherwise it returns
+// reportErr unchanged. Remaining violations (ExitViolations) and errors take
+// precedence so CI can tell "fixed, commit the result" from "still failing".
+func fixedExit(opts *lintOptions, fixResult *fix.Result, reportErr error) error {
+	if reportErr != nil || !opts.exitCodeOnFix || fixResult == nil || fixResult.TotalApplied() == 0 {
+		return reportErr
+	}
+	return exitWith(ExitFixed)
 }
 
 // checkStdinInput returns an error if stdin (-) is mixed with other file arguments.
@@ -382,21 +485,42 @@ func runLintStdin(ctx stdcontext.Context, opts *lintOptions) error {
 		fmt.Fprintf(os.Stderr, "Error: empty input from stdin\n")
 		return exitWith(ExitNoFiles)
 	}
+	if opts.diffBase != "" {
+		fmt.Fprintf(os.Stderr, "Error: --diff-base is not supported when reading from stdin\n")
+		return exitWith(ExitConfigError)
+	}
+	if opts.changed {
+		fmt.Fprintf(os.Stderr, "Error: --changed is not supported when reading from stdin\n")
+		return exitWith(ExitConfigError)
+	}
+	if opts.aiApprove {
+		fmt.Fprintf(os.Stderr, "Error: --ai-approve is not supported when reading from stdin\n")
+		return exitWith(ExitConfigError)
+	}
+	if opts.fix && opts.fixOutput == fixOutputPatch {
+		fmt.Fprintf(os.Stderr, "Error: --fix-output patch is not supported when reading from stdin\n")
+		return exitWith(ExitConfigError)
+	}
+	if opts.updateExpected || opts.verifyExpected {
+		fmt.Fprintf(os.Stderr, "Error: --update-expected and --verify-expected are not supported when reading from stdin\n")
+		return exitWith(ExitConfigError)
+	}
 
 	res, cfg, err := lintStdinContent(ctx, opts, content)
 	if err != nil {
 		return err
 	}
 
-	asyncResult, asyncPlans := resolveAsyncChecks(ctx, res)
+	asyncResult, asyncPlans := resolveAsyncChecks(ctx, res, opts.quiet)
 
 	allViolations := processViolations(res, cfg)
 
-	warnFixUnsafe(opts)
+	warnFixOnlyFlags(opts)
 	if opts.fix {
 		return applyStdinFixes(ctx, opts, content, allViolations, res, asyncPlans, asyncResult)
 	}
-	return writeReport(opts, cfg, allViolations, res.fileSources, 1, 0)
+	writeStats(opts, res, allViolations)
+	return writeReport(opts, cfg, allViolations, res.suppressed, res.fileSources, res.fileStats, 1, 0)
 }
 
 // lintStdinContent parses and lints content read from stdin.
@@ -412,11 +536,13 @@ func lintStdinContent(ctx stdcontext.Context, opts *lintOptions, content []byte)
 	validateAIConfig(cfg, stdinPath)
 	validateDurationConfigs(cfg, stdinPath)
 
+	parseStart := time.Now()
 	parseResult, err := dockerfile.Parse(bytes.NewReader(content), cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to parse stdin: %v\n", err)
 		return nil, nil, exitWith(ExitConfigError)
 	}
+	parseTime := time.Since(parseStart)
 
 	if syntaxErrors := syntax.Check(stdinPath, parseResult.AST, parseResult.Source); len(syntaxErrors) > 0 {
 		for _, e := range syntaxErrors {
@@ -426,11 +552,13 @@ func lintStdinContent(ctx stdcontext.Context, opts *lintOptions, content []byte)
 	}
 
 	inv := invocationFromContextFlag(stdinPath, opts.contextDir)
+	lintStart := time.Now()
 	result, err := linter.LintFileContext(ctx, linter.Input{
 		FilePath:    stdinPath,
 		Config:      cfg,
 		ParseResult: parseResult,
 		Invocation:  inv,
+		TargetStage: dockerfileTargetStage(opts),
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to lint stdin: %v\n", err)
@@ -441,9 +569,15 @@ func lintStdinContent(ctx stdcontext.Context, opts *lintOptions, content []byte)
 		violations:  result.Violations,
 		asyncPlans:  result.AsyncPlan,
 		fileSources: map[string][]byte{stdinPath: result.ParseResult.Source},
+		fileStats:   map[string]reporter.FileStats{stdinPath: fileStatsOf(result.ParseResult)},
 		fileConfigs: map[string]*config.Config{stdinPath: cfg},
 		firstCfg:    cfg,
 	}
+	if opts.stats != "" {
+		res.stats = newRunStats()
+		res.stats.start = parseStart
+		res.stats.addFile(stdinPath, parseTime, time.Since(lintStart), result.RuleDurations)
+	}
 	if inv != nil {
 		res.fileInvocations = make(map[string]*invocation.BuildInvocation, 1)
 		addFileInvocation(res.fileInvocations, inv)
@@ -451,10 +585,12 @@ func lintStdinContent(ctx stdcontext.Context, opts *lintOptions, content []byte)
 	return res, cfg, nil
 }
 
-// processViolations runs the processor chain on raw violations.
+// processViolations runs the processor chain on raw violations. With
+// --show-suppressed, the violations it suppresses are kept in res.suppressed.
 func processViolations(res *lintResults, cfg *config.Config) []rules.Violation {
 	chain, inlineFilter := linter.CLIProcessors()
 	procCtx := processor.NewContext(res.fileConfigs, cfg, res.fileSources)
+	procCtx.CollectSuppressed = cfg != nil && cfg.Output.ShowSuppressed
 	collectConfigRuleDeprecations(procCtx, res.fileConfigs, cfg)
 	allViolations := chain.Process(res.violations, procCtx)
 
@@ -465,10 +601,56 @@ func processViolations(res *lintResults, cfg *config.Config) []rules.Violation {
 		allViolations = append(allViolations, additionalViolations...)
 		allViolations = reporter.SortViolations(allViolations)
 	}
+	if len(procCtx.Suppressed) > 0 {
+		res.suppressed = processor.NewSnippetAttachment().Process(procCtx.Suppressed, procCtx)
+	}
+	if res.changedLines != nil {
+		changed := processor.NewChangedLinesFilter(res.changedLines)
+		allViolations = changed.Process(allViolations, procCtx)
+		res.suppressed = changed.Process(res.suppressed, procCtx)
+	}
 	reportRuleDeprecationWarnings(os.Stderr, procCtx.RuleDeprecations.Notices())
 	return allViolations
 }
 
+// loadChangedLines computes the lines changed relative to --diff-base so
+// processViolations can drop violations on untouched lines.
+func loadChangedLines(ctx stdcontext.Context, opts *lintOptions, res *lintResults) error {
+	if opts.diffBase == "" {
+		return nil
+	}
+	// Resolve the repository of each linted file rather than the working
+	// directory so `tally lint --diff-base main ../other-repo` works, also
+	// when the files come from several repositories or worktrees.
+	var (
+		files changedlines.Files
+		err   error
+	)
+	if paths := slices.Sorted(maps.Keys(res.fileSources)); len(paths) > 0 {
+		files, err = changedlines.FromGitPaths(ctx, paths, opts.diffBase)
+	} else {
+		files, err = changedlines.FromGit(ctx, ".", opts.diffBase)
+	}
+	if err != nil {
+		return fmt.Errorf("--diff-base: %w", err)
+	}
+	res.changedLines = files
+	return nil
+}
+
+// filterChangedFiles keeps the discovered Dockerfiles that git status reports
+// as modified, staged or untracked in the repository containing the working
+// directory.
+func filterChangedFiles(ctx stdcontext.Context, discovered []discovery.DiscoveredFile) ([]discovery.DiscoveredFile, error) {
+	files, err := changedlines.FromStatus(ctx, ".")
+	if err != nil {
+		return nil, fmt.Errorf("--changed: %w", err)
+	}
+	return slices.DeleteFunc(discovered, func(df discovery.DiscoveredFile) bool {
+		return files.Lookup(df.Path) == nil
+	}), nil
+}
+
 func collectConfigRuleDeprecations(
 	procCtx *processor.Context,
 	fileConfigs map[string]*config.Config,
@@ -530,6 +712,7 @@ func applyStdinFixes(
 		return exitWith(ExitConfigError)
 	}
 
+	res.stats.addFixes(fixResult)
 	if fixResult.TotalApplied() > 0 {
 		fmt.Fprintf(os.Stderr, "Fixed %d issues\n", fixResult.TotalApplied())
 	}
@@ -541,18 +724,10 @@ func applyStdinFixes(
 	allViolations = filterFixedViolations(allViolations, fixResult, res.fileConfigs)
 
 	// With --fix and stdin, stdout carries the fixed Dockerfile content.
-	// Redirect the violation report to stderr unless the user explicitly
-	// chose a different output destination (--output or config).
 	cfg := res.firstCfg
-	outCfg := getOutputConfig(opts, cfg)
-	reportPath := outCfg.path
-	if reportPath == "" || reportPath == "stdout" {
-		reportPath = "stderr"
-		if opts.flags != nil && opts.flags.Changed("output") {
-			fmt.Fprintf(os.Stderr, "note: --output overridden to stderr in stdin fix mode (stdout carries fixed content)\n")
-		}
-	}
-	return writeReportTo(opts, cfg, allViolations, res.fileSources, 1, 0, reportPath)
+	reportPath := reportPathBesideStdout(opts, cfg, "stdin fix mode (stdout carries fixed content)")
+	writeStats(opts, res, allViolations)
+	return fixedExit(opts, fixResult, writeReportTo(opts, cfg, allViolations, res.suppressed, res.fileSources, res.fileStats, 1, 0, reportPath))
 }
 
 func runLintOrchestrator(ctx stdcontext.Context, opts *lintOptions, discovered *invocation.DiscoveryResult) error {
@@ -567,7 +742,7 @@ func runLintOrchestrator(ctx stdcontext.Context, opts *lintOptions, discovered *
 			fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
 			return exitWith(ExitConfigError)
 		}
-		return writeReport(opts, cfg, nil, nil, 0, 0)
+		return writeReport(opts, cfg, nil, nil, nil, nil, 0, 0)
 	}
 
 	res, err := lintInvocations(ctx, discovered.Invocations, opts)
@@ -576,12 +751,17 @@ func runLintOrchestrator(ctx stdcontext.Context, opts *lintOptions, discovered *
 	}
 	res.filesScanned = len(res.fileSources)
 	res.invocationsScanned = len(discovered.Invocations)
+	if err := loadChangedLines(ctx, opts, res); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
 
-	resolveAsyncChecks(ctx, res)
+	resolveAsyncChecks(ctx, res, opts.quiet)
 
 	allViolations := processViolations(res, res.firstCfg)
-	warnFixUnsafe(opts)
-	return writeReport(opts, res.firstCfg, allViolations, res.fileSources, res.filesScanned, res.invocationsScanned)
+	warnFixOnlyFlags(opts)
+	writeStats(opts, res, allViolations)
+	return writeReport(opts, res.firstCfg, allViolations, res.suppressed, res.fileSources, res.fileStats, res.filesScanned, res.invocationsScanned)
 }
 
 func classifyLintEntrypoint(ctx stdcontext.Context, inputs []string, opts *lintOptions) (*invocation.DiscoveryResult, bool, error) {
@@ -659,8 +839,8 @@ func isRegularFile(path string) bool {
 }
 
 func rejectMixedOrchestratorInputs(inputs []string, opts *lintOptions) error {
-	if hasOrchestratorSelectionFlags(opts) {
-		return errors.New("--target and --service are only valid for a single explicit orchestrator file")
+	if err := validateDockerfileSelectionFlags(opts); err != nil {
+		return err
 	}
 	for _, input := range inputs {
 		info, err := os.Stat(input)
@@ -677,17 +857,41 @@ func rejectMixedOrchestratorInputs(inputs []string, opts *lintOptions) error {
 	return nil
 }
 
-func hasOrchestratorSelectionFlags(opts *lintOptions) bool {
-	return len(opts.targets) > 0 || len(opts.services) > 0
+// validateDockerfileSelectionFlags checks --target and --service for inputs
+// that are not orchestrator files. There --target names the build stage.
+func validateDockerfileSelectionFlags(opts *lintOptions) error {
+	if len(opts.services) > 0 {
+		return errors.New("--service is only valid for a single explicit Compose file")
+	}
+	if len(opts.targets) > 1 {
+		return errors.New("--target selects a single build stage when linting Dockerfiles")
+	}
+	return nil
+}
+
+// dockerfileTargetStage returns the build stage selected with --target for
+// Dockerfile inputs. Orchestrator entrypoints use --target for their own
+// targets and never call this.
+func dockerfileTargetStage(opts *lintOptions) string {
+	if len(opts.targets) != 1 {
+		return ""
+	}
+	return opts.targets[0]
 }
 
 func validateOrchestratorFlags(opts *lintOptions, kind string) error {
 	if opts.fix {
 		return errors.New("--fix is not supported for orchestrator entrypoints")
 	}
+	if opts.updateExpected || opts.verifyExpected {
+		return errors.New("--update-expected and --verify-expected are not supported for orchestrator entrypoints")
+	}
 	if opts.contextSet && opts.contextDir != "" {
 		return errors.New("--context is not supported for orchestrator entrypoints")
 	}
+	if opts.changed {
+		return errors.New("--changed is not supported for orchestrator entrypoints")
+	}
 	switch kind {
 	case invocation.KindBake:
 		if len(opts.services) > 0 {
@@ -704,9 +908,13 @@ func validateOrchestratorFlags(opts *lintOptions, kind string) error {
 func lintInvocations(ctx stdcontext.Context, invocations []invocation.BuildInvocation, opts *lintOptions) (*lintResults, error) {
 	res := &lintResults{
 		fileSources:     make(map[string][]byte),
+		fileStats:       make(map[string]reporter.FileStats),
 		fileConfigs:     make(map[string]*config.Config),
 		fileInvocations: make(map[string]*invocation.BuildInvocation),
 	}
+	if opts.stats != "" {
+		res.stats = newRunStats()
+	}
 	parseCache := make(map[string]*dockerfile.ParseResult)
 
 	for _, inv := range invocations {
@@ -731,8 +939,10 @@ func lintInvocations(ctx stdcontext.Context, invocations []invocation.BuildInvoc
 			return nil, fmt.Errorf("failed to lint %s: %w", file, err)
 		}
 
+		var parseTime time.Duration
 		parseResult := parseCache[file]
 		if parseResult == nil {
+			parseStart := time.Now()
 			var err error
 			parseResult, err = dockerfile.ParseFile(ctx, file, cfg)
 			if err != nil {
@@ -742,8 +952,10 @@ func lintInvocations(ctx stdcontext.Context, invocations []invocation.BuildInvoc
 				return nil, &syntax.CheckError{Errors: syntaxErrors}
 			}
 			parseCache[file] = parseResult
+			parseTime = time.Since(parseStart)
 		}
 
+		lintStart := time.Now()
 		result, err := linter.LintFileContext(ctx, linter.Input{
 			FilePath:    file,
 			Config:      cfg,
@@ -753,8 +965,10 @@ func lintInvocations(ctx stdcontext.Context, invocations []invocation.BuildInvoc
 		if err != nil {
 			return nil, fmt.Errorf("failed to lint %s: %w", file, err)
 		}
+		res.stats.addFile(file, parseTime, time.Since(lintStart), result.RuleDurations)
 
 		res.fileSources[file] = result.ParseResult.Source
+		res.fileStats[file] = fileStatsOf(result.ParseResult)
 		addFileInvocation(res.fileInvocations, &inv)
 		res.violations = append(res.violations, result.Violations...)
 		res.asyncPlans = append(res.asyncPlans, result.AsyncPlan...)
@@ -766,9 +980,13 @@ func lintInvocations(ctx stdcontext.Context, invocations []invocation.BuildInvoc
 func lintFiles(ctx stdcontext.Context, discovered []discovery.DiscoveredFile, opts *lintOptions) (*lintResults, error) {
 	res := &lintResults{
 		fileSources:     make(map[string][]byte),
+		fileStats:       make(map[string]reporter.FileStats),
 		fileConfigs:     make(map[string]*config.Config),
 		fileInvocations: make(map[string]*invocation.BuildInvocation),
 	}
+	if opts.stats != "" {
+		res.stats = newRunStats()
+	}
 
 	for _, df := range discovered {
 		file := df.Path
@@ -791,10 +1009,12 @@ func lintFiles(ctx stdcontext.Context, discovered []discovery.DiscoveredFile, op
 		}
 
 		// Parse once — reused for syntax checks, build context, and LintFile.
+		parseStart := time.Now()
 		parseResult, err := dockerfile.ParseFile(ctx, file, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to lint %s: %w", file, err)
 		}
+		parseTime := time.Since(parseStart)
 
 		// Fail-fast syntax checks (unknown instructions, directive typos).
 		if syntaxErrors := syntax.Check(file, parseResult.AST, parseResult.Source); len(syntaxErrors) > 0 {
@@ -806,17 +1026,21 @@ func lintFiles(ctx stdcontext.Context, discovered []discovery.DiscoveredFile, op
 			inv = invocationFromContextFlag(file, df.ContextDir)
 		}
 
+		lintStart := time.Now()
 		result, err := linter.LintFileContext(ctx, linter.Input{
 			FilePath:    file,
 			Config:      cfg,
 			ParseResult: parseResult,
 			Invocation:  inv,
+			TargetStage: dockerfileTargetStage(opts),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to lint %s: %w", file, err)
 		}
+		res.stats.addFile(file, parseTime, time.Since(lintStart), result.RuleDurations)
 
 		res.fileSources[file] = result.ParseResult.Source
+		res.fileStats[file] = fileStatsOf(result.ParseResult)
 		if inv != nil {
 			addFileInvocation(res.fileInvocations, inv)
 		}
@@ -841,20 +1065,53 @@ func handleLintError(err error) error {
 	return exitWith(ExitConfigError)
 }
 
+// writeStats prints the --stats summary to stderr. It runs before the
+// report so the summary and any stderr-bound report don't interleave.
+func writeStats(opts *lintOptions, res *lintResults, violations []rules.Violation) {
+	if err := res.stats.write(os.Stderr, opts.stats, violations); err != nil {
+		slog.Warn("failed to write run statistics", "error", err)
+	}
+}
+
 // writeReport formats and writes the violation report using the configured output path.
 func writeReport(
-	opts *lintOptions, cfg *config.Config, violations []rules.Violation,
-	fileSources map[string][]byte, filesScanned, invocationsScanned int,
+	opts *lintOptions, cfg *config.Config, violations, suppressed []rules.Violation,
+	fileSources map[string][]byte, fileStats map[string]reporter.FileStats, filesScanned, invocationsScanned int,
 ) error {
-	return writeReportTo(opts, cfg, violations, fileSources, filesScanned, invocationsScanned, "")
+	return writeReportTo(opts, cfg, violations, suppressed, fileSources, fileStats, filesScanned, invocationsScanned, "")
+}
+
+// fileStatsOf returns the parse statistics the JSON report lists per file.
+func fileStatsOf(parseResult *dockerfile.ParseResult) reporter.FileStats {
+	stats := reporter.FileStats{Stages: len(parseResult.Stages)}
+	if parseResult.AST != nil && parseResult.AST.AST != nil {
+		stats.Instructions = len(parseResult.AST.AST.Children)
+	}
+	return stats
+}
+
+// reportPathBesideStdout returns the report path to use when stdout carries
+// other content: stderr, unless the user explicitly chose a different output
+// destination (--output or config). mode explains the override in the note
+// printed when --output named stdout.
+func reportPathBesideStdout(opts *lintOptions, cfg *config.Config, mode string) string {
+	reportPath := getOutputConfig(opts, cfg).path
+	if reportPath == "" || reportPath == "stdout" {
+		reportPath = "stderr"
+		if opts.flags != nil && opts.flags.Changed("output") {
+			fmt.Fprintf(os.Stderr, "note: --output overridden to stderr in %s\n", mode)
+		}
+	}
+	return reportPath
 }
 
 // writeReportTo formats and writes the violation report. If outputOverride is
 // non-empty, it overrides the configured output path (e.g. "stderr" to keep
 // stdout free for fixed content in stdin mode).
 func writeReportTo(
-	opts *lintOptions, cfg *config.Config, violations []rules.Violation,
-	fileSources map[string][]byte, filesScanned, invocationsScanned int, outputOverride string,
+	opts *lintOptions, cfg *config.Config, violations, suppressed []rules.Violation,
+	fileSources map[string][]byte, fileStats map[string]reporter.FileStats,
+	filesScanned, invocationsScanned int, outputOverride string,
 ) error {
 	outCfg := getOutputConfig(opts, cfg)
 	if outputOverride != "" {
@@ -866,25 +1123,22 @@ func writeReportTo(
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return exitWith(ExitConfigError)
 	}
-
-	writer, closeWriter, err := reporter.GetWriter(outCfg.path)
+	groupBy, err := reporter.ParseGroupBy(outCfg.groupBy)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return exitWith(ExitConfigError)
 	}
-	defer func() {
-		if err := closeWriter(); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to close output: %v\n", err)
-		}
-	}()
 
 	reportOpts := reporter.Options{
-		Format:      formatType,
-		Writer:      writer,
-		ShowSource:  outCfg.showSource,
-		ToolName:    "tally",
-		ToolVersion: version.Version(),
-		ToolURI:     "https://github.com/wharflab/tally",
+		Format:          formatType,
+		ShowSource:      outCfg.showSource,
+		ShowFixes:       outCfg.showFixes,
+		GroupBy:         groupBy,
+		ToolName:        "tally",
+		ToolVersion:     version.Version(),
+		ToolURI:         "https://github.com/wharflab/tally",
+		AnnotationLimit: outCfg.annotationLimit,
+		StepSummaryPath: os.Getenv("GITHUB_STEP_SUMMARY"),
 	}
 
 	if opts.noColor != nil && *opts.noColor {
@@ -892,22 +1146,23 @@ func writeReportTo(
 		reportOpts.Color = &noColor
 	}
 
-	rep, err := reporter.New(reportOpts)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to create reporter: %v\n", err)
-		return exitWith(ExitConfigError)
-	}
-
 	rulesEnabled := len(linter.EnabledRuleCodes(cfg))
 	metadata := reporter.ReportMetadata{
 		FilesScanned:       filesScanned,
 		InvocationsScanned: invocationsScanned,
 		RulesEnabled:       rulesEnabled,
+		Suppressed:         suppressed,
+		Files:              fileStats,
 	}
 
-	if err := rep.Report(violations, fileSources, metadata); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to write output: %v\n", err)
-		return exitWith(ExitConfigError)
+	if reporter.IsOutputTemplate(outCfg.path) {
+		// One report per linted file, e.g. --output "reports/{dir}.sarif".
+		if err := reporter.ReportPerFile(reportOpts, outCfg.path, violations, fileSources, metadata); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write output: %v\n", err)
+			return exitWith(ExitConfigError)
+		}
+	} else if err := writeSingleReport(reportOpts, outCfg.path, violations, fileSources, metadata); err != nil {
+		return err
 	}
 
 	exitCode := determineExitCode(violations, outCfg.failLevel)
@@ -918,6 +1173,35 @@ func writeReportTo(
 	return nil
 }
 
+// writeSingleReport writes the report for all files to path.
+func writeSingleReport(
+	reportOpts reporter.Options, path string,
+	violations []rules.Violation, fileSources map[string][]byte, metadata reporter.ReportMetadata,
+) error {
+	writer, closeWriter, err := reporter.GetWriter(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	defer func() {
+		if err := closeWriter(); err != nil {
+			slog.Warn("failed to close output", "error", err)
+		}
+	}()
+
+	reportOpts.Writer = writer
+	rep, err := reporter.New(reportOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create reporter: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	if err := rep.Report(violations, fileSources, metadata); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write output: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	return nil
+}
+
 // loadConfigForFile loads configuration for a target file.
 //
 // Simple config-shaped flags (--format, --max-lines, --ai-*, ...) participate
@@ -938,15 +1222,17 @@ func loadConfigForFile(opts *lintOptions, targetPath string) (*config.Config, er
 	if err != nil {
 		return nil, err
 	}
+	if opts.configPath != "" {
+		// An explicit config file skips discovery, so per-rule paths are
+		// resolved against the target here.
+		cfg.ScopeToFile(targetPath)
+	}
 
 	// --select / --ignore append to the configured selection rather than
-	// replacing it, so they live outside the posflag layer.
-	if len(opts.selectR) > 0 {
-		cfg.Rules.Include = append(cfg.Rules.Include, opts.selectR...)
-	}
-	if len(opts.ignore) > 0 {
-		cfg.Rules.Exclude = append(cfg.Rules.Exclude, opts.ignore...)
-	}
+	// replacing it, so they live outside the posflag layer. They override
+	// config patterns of equal specificity.
+	cfg.Rules.Select(opts.selectR...)
+	cfg.Rules.Ignore(opts.ignore...)
 
 	// --no-inline-directives inverts the enabled setting.
 	if opts.noInlineDirectives != nil {
@@ -1110,20 +1396,24 @@ func (s *commandLineSplitter) finish() ([]string, error) {
 
 // outputConfig holds output configuration values.
 type outputConfig struct {
-	format     string
-	path       string
-	showSource bool
-	failLevel  string
+	format          string
+	path            string
+	showSource      bool
+	failLevel       string
+	groupBy         string
+	annotationLimit int
+	showFixes       bool
 }
 
 // getOutputConfig returns output configuration from CLI flags and config.
 func getOutputConfig(opts *lintOptions, cfg *config.Config) outputConfig {
 	// Start with defaults
 	oc := outputConfig{
-		format:     "text",
-		path:       "stdout",
-		showSource: true,
-		failLevel:  "style",
+		format:          "text",
+		path:            "stdout",
+		showSource:      true,
+		failLevel:       "style",
+		annotationLimit: 10,
 	}
 
 	if cfg != nil {
@@ -1139,6 +1429,9 @@ func getOutputConfig(opts *lintOptions, cfg *config.Config) outputConfig {
 		if cfg.Output.FailLevel != "" {
 			oc.failLevel = cfg.Output.FailLevel
 		}
+		oc.groupBy = cfg.Output.GroupBy
+		oc.annotationLimit = cfg.Output.AnnotationLimit
+		oc.showFixes = cfg.Output.ShowFixes
 	}
 
 	// --hide-source is an inversion flag that can't go through posflag.
@@ -1195,13 +1488,19 @@ func validateAIConfig(cfg *config.Config, file string) {
 		return
 	}
 
+	source := file
+	if cfg.ConfigFile != "" {
+		source = cfg.ConfigFile
+	}
 	if len(cfg.AI.Command) == 0 {
-		source := file
-		if cfg.ConfigFile != "" {
-			source = cfg.ConfigFile
-		}
 		fmt.Fprintf(os.Stderr, "Warning: ai.enabled=true but ai.command is empty (%s)\n", source)
 	}
+
+	for _, rule := range slices.Sorted(maps.Keys(cfg.AI.Prompts)) {
+		if _, err := autofixdata.ParsePromptTemplate(rule, cfg.AI.Prompts[rule]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid %v (%s)\n", err, source)
+		}
+	}
 }
 
 // validateDurationConfigs validates duration string fields at config load time
@@ -1222,6 +1521,11 @@ func validateDurationConfigs(cfg *config.Config, file string) {
 			fmt.Fprintf(os.Stderr, "Warning: invalid ai.timeout %q (%s): %v\n", t, source, err)
 		}
 	}
+	if t := cfg.Rules.Timeout; t != "" {
+		if _, err := time.ParseDuration(t); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid rules.timeout %q (%s): %v\n", t, source, err)
+		}
+	}
 }
 
 func invocationFromContextFlag(file, contextDir string) *invocation.BuildInvocation {
@@ -1230,7 +1534,7 @@ func invocationFromContextFlag(file, contextDir string) *invocation.BuildInvocat
 	}
 	inv, err := invocation.NewDockerfileInvocation(file, contextDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to normalize build context %q for %s: %v\n", contextDir, file, err)
+		slog.Warn("failed to normalize build context", "context", contextDir, "file", file, "error", err)
 		return nil
 	}
 	return inv
@@ -1281,6 +1585,11 @@ func applyFixes(
 	}
 	if aiEnabled {
 		autofix.Register()
+		defer func() {
+			if err := autofix.Close(); err != nil {
+				slog.Warn("failed to stop ACP agent", "error", err)
+			}
+		}()
 	}
 
 	registryInsightsByFile := collectRegistryInsights(input.asyncPlans, input.asyncResult)
@@ -1292,6 +1601,20 @@ func applyFixes(
 		RuleFilter:       ruleFilter,
 		FixModes:         fixModes,
 	}
+	if opts.aiApprove && aiEnabled {
+		approver, err := newTerminalApprover()
+		if err != nil {
+			return nil, err
+		}
+		fixCtx.Approver = approver
+	}
+	if opts.aiTranscript != "" && aiEnabled {
+		recorder, err := newTranscriptWriter(opts.aiTranscript)
+		if err != nil {
+			return nil, err
+		}
+		fixCtx.Recorder = recorder
+	}
 	for i := range input.violations {
 		v := &input.violations[i]
 		for _, sf := range v.AllFixes() {
@@ -1312,6 +1635,12 @@ func applyFixes(
 			req.SetConfig(cfg)
 			req.SetFixContext(fixCtx)
 
+			if setter, ok := sf.ResolverData.(interface {
+				SetViolation(v autofixdata.ViolationContext)
+			}); ok {
+				setter.SetViolation(autofixdata.NewViolationContext(*v))
+			}
+
 			if setter, ok := sf.ResolverData.(interface {
 				SetRegistryInsights(insights []autofixdata.RegistryInsight)
 			}); ok {
@@ -1328,9 +1657,12 @@ func applyFixes(
 		}
 	}
 
-	aiFixes, maxAITimeout := planAcpFixSpinner(input.violations, safetyThreshold, safetyThresholds, ruleFilter, fixModes, normalizedConfigs)
-	stopSpinner := startAcpFixSpinner(aiFixes, maxAITimeout)
-	defer stopSpinner()
+	// The spinner would redraw over interactive review prompts.
+	if fixCtx.Approver == nil && !opts.quiet {
+		aiFixes, maxAITimeout := planAcpFixSpinner(input.violations, safetyThreshold, safetyThresholds, ruleFilter, fixModes, normalizedConfigs)
+		stopSpinner := startAcpFixSpinner(aiFixes, maxAITimeout)
+		defer stopSpinner()
+	}
 
 	fixer := &fix.Fixer{
 		SafetyThreshold:   safetyThreshold,
@@ -1340,6 +1672,9 @@ func applyFixes(
 		SlowChecksEnabled: buildPerFileSlowChecksEnabled(input.fileConfigs, input.sources),
 		FixModes:          fixModes,
 		Concurrency:       4,
+		Iterations:        opts.fixIterations,
+		FixPrecedence:     buildPerFileFixPrecedence(input.fileConfigs),
+		Relint:            fixRelinter(input.fileConfigs, input.fileInvocations, dockerfileTargetStage(opts)),
 	}
 
 	result, err := fixer.Apply(ctx, input.violations, input.sources)
@@ -1350,6 +1685,55 @@ func applyFixes(
 	return result, nil
 }
 
+// fixRelinter re-lints fixed content with the file's config and the CLI
+// processor chain, so the fixer compares like with like when it retries
+// conflicting fixes, runs further fix iterations, and checks that applied
+// fixes took effect. Async checks are not re-run.
+func fixRelinter(
+	fileConfigs map[string]*config.Config,
+	fileInvocations map[string]*invocation.BuildInvocation,
+	targetStage string,
+) fix.Relinter {
+	return func(ctx stdcontext.Context, filePath string, content []byte) ([]rules.Violation, error) {
+		cfg := fileConfigs[filePath]
+		result, err := linter.LintFileContext(ctx, linter.Input{
+			FilePath:    filePath,
+			Content:     content,
+			Config:      cfg,
+			Invocation:  invocationForFile(fileInvocations, filePath),
+			TargetStage: targetStage,
+		})
+		if err != nil {
+			return nil, err
+		}
+		chain, _ := linter.CLIProcessors()
+		procCtx := processor.NewContext(
+			map[string]*config.Config{filePath: cfg}, cfg, map[string][]byte{filePath: content},
+		)
+		violations := chain.Process(result.Violations, procCtx)
+		// AI fixes are planned and confirmed for the initial lint run only;
+		// later fix iterations must not start new agent sessions.
+		for i := range violations {
+			if pf := violations[i].PreferredFix(); pf != nil && pf.NeedsResolve && pf.ResolverID == autofixdata.ResolverID {
+				violations[i].SuggestedFix, violations[i].SuggestedFixes = nil, nil
+			}
+		}
+		return violations, nil
+	}
+}
+
+// invocationForFile returns the first invocation (by key) that builds
+// filePath, or nil when the file was linted without one.
+func invocationForFile(fileInvocations map[string]*invocation.BuildInvocation, filePath string) *invocation.BuildInvocation {
+	for _, key := range slices.Sorted(maps.Keys(fileInvocations)) {
+		inv := fileInvocations[key]
+		if inv != nil && filepath.Clean(inv.DockerfilePath) == filepath.Clean(filePath) {
+			return inv
+		}
+	}
+	return nil
+}
+
 func fixSafetyThreshold(opts *lintOptions, _ map[string]*config.Config) fix.FixSafety {
 	if opts.fixUnsafe {
 		return fix.FixUnsafe
@@ -1405,6 +1789,41 @@ func writeFixedFiles(result *fix.Result) error {
 	return nil
 }
 
+// writeFixPatch writes the changes of result to w as a single patch that
+// `git apply` accepts, leaving the files untouched. Paths are relative to the
+// working directory, so the patch applies from there.
+func writeFixPatch(w io.Writer, result *fix.Result) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	changes := make(map[string]*fix.FileChange, len(result.Changes))
+	for _, fc := range result.Changes {
+		if fc.HasChanges() {
+			changes[patchPath(wd, fc.Path)] = fc
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(changes)) {
+		fc := changes[path]
+		diff := textdiff.Patch(path, fc.OriginalContent, fc.ModifiedContent)
+		if _, err := io.WriteString(w, diff); err != nil {
+			return fmt.Errorf("failed to write patch: %w", err)
+		}
+	}
+	return nil
+}
+
+// patchPath returns path relative to wd with forward slashes, as patches
+// name files. Paths outside wd are kept as they are.
+func patchPath(wd, path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(wd, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
 // buildPerFileFixModes builds a per-file map of fix modes from fileConfigs.
 // Returns map[filePath]map[ruleCode]FixMode.
 func buildPerFileFixModes(fileConfigs map[string]*config.Config) map[string]map[string]fix.FixMode {
@@ -1421,6 +1840,17 @@ func buildPerFileFixModes(fileConfigs map[string]*config.Config) map[string]map[
 	return result
 }
 
+// buildPerFileFixPrecedence collects each file's fix-precedence patterns.
+func buildPerFileFixPrecedence(fileConfigs map[string]*config.Config) map[string][]string {
+	result := make(map[string][]string)
+	for filePath, cfg := range fileConfigs {
+		if cfg != nil && len(cfg.FixPrecedence) > 0 {
+			result[filepath.Clean(filePath)] = cfg.FixPrecedence
+		}
+	}
+	return result
+}
+
 func buildPerFileEnabledRules(
 	fileConfigs map[string]*config.Config,
 	sources map[string][]byte,
@@ -1454,7 +1884,8 @@ func buildPerFileSlowChecksEnabled(fileConfigs map[string]*config.Config, source
 // runAsyncChecks executes async check plans if slow checks are enabled.
 // Returns nil if slow checks are disabled or no plans exist.
 // Respects per-file slow-checks configuration from res.fileConfigs.
-func runAsyncChecks(ctx stdcontext.Context, res *lintResults) (*async.RunResult, []async.CheckRequest) {
+// Progress is shown on interactive terminals unless quiet is set.
+func runAsyncChecks(ctx stdcontext.Context, res *lintResults, quiet bool) (*async.RunResult, []async.CheckRequest) {
 	if len(res.asyncPlans) == 0 {
 		return nil, nil
 	}
@@ -1469,18 +1900,35 @@ func runAsyncChecks(ctx stdcontext.Context, res *lintResults) (*async.RunResult,
 		fmt.Fprintf(os.Stderr, "note: slow checks not available (missing build tags)\n")
 		return nil, nil
 	}
-	imgResolver := registry.NewDefaultResolver()
+	cfgs := make([]*config.Config, 0, len(plans)+1)
+	for _, req := range plans {
+		cfgs = append(cfgs, res.fileConfigs[req.File])
+	}
+	cfgs = append(cfgs, res.firstCfg)
+	imgResolver := registry.NewDefaultResolver(registry.OptionsFromConfig(cfgs...))
 	asyncImgResolver := registry.NewAsyncImageResolver(imgResolver)
+	copySourceResolver := registry.NewAsyncCopySourceResolver(imgResolver)
+	eolResolver := eol.NewResolver()
+	imgFiles, _ := imgResolver.(registry.ImageFileReader)
+	osvResolver := osv.NewResolver(imgFiles)
+	downloadResolver := download.NewResolver()
 
 	rt := &async.Runtime{
 		Concurrency: 4,
 		Timeout:     maxTimeout,
 		Resolvers: map[string]async.Resolver{
-			asyncImgResolver.ID(): asyncImgResolver,
+			asyncImgResolver.ID():   asyncImgResolver,
+			copySourceResolver.ID(): copySourceResolver,
+			eolResolver.ID():        eolResolver,
+			osvResolver.ID():        osvResolver,
+			downloadResolver.ID():   downloadResolver,
 		},
 	}
 
+	progress, stopProgress := startAsyncProgress(quiet)
+	rt.Progress = progress
 	result := rt.Run(ctx, plans)
+	stopProgress()
 	reportSkipped(result)
 	return result, plans
 }
@@ -1493,6 +1941,7 @@ func filterAsyncPlans(res *lintResults) ([]async.CheckRequest, time.Duration) {
 	// --ignore, and severity overrides).
 	procCtx := processor.NewContext(res.fileConfigs, res.firstCfg, res.fileSources)
 	filtered := processor.NewSeverityOverride().Process(res.violations, procCtx)
+	filtered = processor.NewStageRoleSeverity().Process(filtered, procCtx)
 	filtered = processor.NewEnableFilter().Process(filtered, procCtx)
 	errorContexts := filesWithErrors(failFastViolations(filtered))
 	maxTimeout := 20 * time.Second
@@ -1596,6 +2045,8 @@ func reportSkippedFixes(result *fix.Result) {
 		aiTimeouts int
 		aiErrors   int
 		otherErrs  int
+		rolledBack int
+		oscillated int
 		samples    []skippedFixInfo
 	)
 
@@ -1604,6 +2055,21 @@ func reportSkippedFixes(result *fix.Result) {
 			continue
 		}
 		for _, s := range fc.FixesSkipped {
+			if s.Reason == fix.SkipValidation || s.Reason == fix.SkipOscillation {
+				if s.Reason == fix.SkipValidation {
+					rolledBack++
+				} else {
+					oscillated++
+				}
+				if len(samples) < 5 {
+					samples = append(samples, skippedFixInfo{
+						filePath: fc.Path,
+						ruleCode: s.RuleCode,
+						errorMsg: compactSingleLine(s.Error, 500),
+					})
+				}
+				continue
+			}
 			if s.Reason != fix.SkipResolveError || s.Error == "" {
 				continue
 			}
@@ -1638,6 +2104,12 @@ func reportSkippedFixes(result *fix.Result) {
 	if otherErrs > 0 {
 		fmt.Fprintf(os.Stderr, "note: %d fix(es) skipped due to resolver errors\n", otherErrs)
 	}
+	if rolledBack > 0 {
+		fmt.Fprintf(os.Stderr, "note: %d fix(es) rolled back after failing post-fix validation\n", rolledBack)
+	}
+	if oscillated > 0 {
+		fmt.Fprintf(os.Stderr, "note: %d fix(es) skipped because fix iterations oscillate\n", oscillated)
+	}
 
 	for _, s := range samples {
 		fmt.Fprintf(os.Stderr, "note: skipped fix %s (%s): %s\n", s.ruleCode, s.filePath, s.errorMsg)
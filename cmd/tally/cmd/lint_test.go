@@ -3,6 +3,8 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"errors"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -429,3 +431,115 @@ func TestFailFastViolationsExcludeSlowGatedPowerShell(t *testing.T) {
 		t.Fatalf("non-slow error should still trigger async fail-fast")
 	}
 }
+
+func TestValidateDockerfileSelectionFlags(t *testing.T) {
+	t.Parallel()
+
+	if err := validateDockerfileSelectionFlags(&lintOptions{targets: []string{"build"}}); err != nil {
+		t.Fatalf("a single --target should select a stage, got %v", err)
+	}
+	if got := dockerfileTargetStage(&lintOptions{targets: []string{"build"}}); got != "build" {
+		t.Fatalf("dockerfileTargetStage() = %q, want build", got)
+	}
+	if err := validateDockerfileSelectionFlags(&lintOptions{targets: []string{"a", "b"}}); err == nil {
+		t.Fatal("expected several --target values to be rejected for Dockerfiles")
+	}
+	if err := validateDockerfileSelectionFlags(&lintOptions{services: []string{"api"}}); err == nil {
+		t.Fatal("expected --service to be rejected for Dockerfiles")
+	}
+}
+
+func TestFixedExit(t *testing.T) {
+	t.Parallel()
+
+	applied := &fix.Result{Changes: map[string]*fix.FileChange{
+		"Dockerfile": {FixesApplied: []fix.AppliedFix{{RuleCode: "tally/eol-last"}}},
+	}}
+	violationsErr := exitWith(ExitViolations)
+
+	tests := []struct {
+		name          string
+		exitCodeOnFix bool
+		result        *fix.Result
+		reportErr     error
+		wantCode      int
+	}{
+		{"flag off", false, applied, nil, ExitSuccess},
+		{"fixes applied", true, applied, nil, ExitFixed},
+		{"no fixes", true, &fix.Result{}, nil, ExitSuccess},
+		{"no fix run", true, nil, nil, ExitSuccess},
+		{"violations remain", true, applied, violationsErr, ExitViolations},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := fixedExit(&lintOptions{exitCodeOnFix: tt.exitCodeOnFix}, tt.result, tt.reportErr)
+			code := ExitSuccess
+			if exitErr, ok := errors.AsType[*ExitError](err); ok {
+				code = exitErr.Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWriteFixPatch(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &fix.Result{Changes: map[string]*fix.FileChange{
+		filepath.Join(wd, "b", "Dockerfile"): {
+			Path:            filepath.Join(wd, "b", "Dockerfile"),
+			FixesApplied:    []fix.AppliedFix{{RuleCode: "tally/eol-last"}},
+			OriginalContent: []byte("FROM alpine"),
+			ModifiedContent: []byte("FROM alpine\n"),
+		},
+		"Dockerfile": {
+			Path:            "Dockerfile",
+			FixesApplied:    []fix.AppliedFix{{RuleCode: "buildkit/ConsistentInstructionCasing"}},
+			OriginalContent: []byte("from alpine\n"),
+			ModifiedContent: []byte("FROM alpine\n"),
+		},
+		"unchanged/Dockerfile": {
+			Path:            "unchanged/Dockerfile",
+			OriginalContent: []byte("FROM alpine\n"),
+			ModifiedContent: []byte("FROM alpine\n"),
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeFixPatch(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	want := "diff --git a/Dockerfile b/Dockerfile\n--- a/Dockerfile\n+++ b/Dockerfile\n" +
+		"@@ -1,1 +1,1 @@\n-from alpine\n+FROM alpine\n" +
+		"diff --git a/b/Dockerfile b/b/Dockerfile\n--- a/b/Dockerfile\n+++ b/b/Dockerfile\n" +
+		"@@ -1,1 +1,1 @@\n-FROM alpine\n\\ No newline at end of file\n+FROM alpine\n"
+	if got := buf.String(); got != want {
+		t.Errorf("patch =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPatchPath(t *testing.T) {
+	t.Parallel()
+
+	wd := filepath.FromSlash("/work/repo")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{filepath.FromSlash("/work/repo/app/Dockerfile"), "app/Dockerfile"},
+		{filepath.FromSlash("/work/other/Dockerfile"), "/work/other/Dockerfile"},
+		{filepath.FromSlash("/work/repo/..app/Dockerfile"), "..app/Dockerfile"},
+	}
+	for _, tt := range tests {
+		if got := patchPath(wd, tt.path); got != tt.want {
+			t.Errorf("patchPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/wharflab/tally/internal/logging"
+)
+
+// logOptions holds the persistent flags that control diagnostic logging.
+type logOptions struct {
+	verbose int
+	debug   bool
+}
+
+func (o *logOptions) register(flags *pflag.FlagSet) {
+	flags.CountVarP(&o.verbose, "verbose", "v", "Log progress to stderr (-v for info, -vv for debug)")
+	flags.BoolVar(&o.debug, "debug", false, "Log debug details to stderr (same as -vv)")
+}
+
+// verbosity returns the effective -v count; --debug counts as -vv.
+func (o *logOptions) verbosity() int {
+	if o.debug {
+		return max(o.verbose, 2)
+	}
+	return o.verbose
+}
+
+// setup installs the default logger. TALLY_LOG_FORMAT=json switches to JSON
+// lines for CI log collectors.
+func (o *logOptions) setup() {
+	logging.Setup(o.verbosity())
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLogOptions_Verbosity(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"-v"}, 1},
+		{[]string{"-vv"}, 2},
+		{[]string{"--verbose", "--verbose", "--verbose"}, 3},
+		{[]string{"--debug"}, 2},
+		{[]string{"--debug", "-vvv"}, 3},
+	}
+	for _, tt := range tests {
+		opts := &logOptions{}
+		flags := pflag.NewFlagSet("tally", pflag.ContinueOnError)
+		opts.register(flags)
+		if err := flags.Parse(tt.args); err != nil {
+			t.Fatalf("parse %v: %v", tt.args, err)
+		}
+		if got := opts.verbosity(); got != tt.want {
+			t.Errorf("verbosity(%v) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRootCommand_LoggingFlagsAreInherited(t *testing.T) {
+	t.Parallel()
+	lint, _, err := NewRootCommand().Find([]string{"lint"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lint.ParseFlags([]string{"-vv", "--debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := lint.Flags().GetCount("verbose"); err != nil || n != 2 {
+		t.Errorf("verbose = %d, %v; want 2", n, err)
+	}
+}
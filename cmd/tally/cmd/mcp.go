@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/mcpserver"
+)
+
+func mcpCommand() *cobra.Command {
+	var stdio bool
+
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Start the Model Context Protocol server for AI agents",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !stdio {
+				fmt.Fprintln(os.Stderr, "Error: only --stdio transport is supported")
+				return exitWith(ExitConfigError)
+			}
+			server := mcpserver.New()
+			return server.RunStdio(cmd.Context())
+		},
+	}
+
+	cmd.Flags().BoolVar(&stdio, "stdio", true, "Use stdin/stdout for communication (required)")
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+)
+
+func migrateCommand() *cobra.Command {
+	var (
+		from   string
+		output string
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate --from hadolint [CONFIG]",
+		Short: "Convert another linter's config file to .tally.toml",
+		Long: `Convert another linter's config file to an equivalent .tally.toml.
+
+With --from hadolint, ignored rules, override severities, trustedRegistries,
+failure-threshold, no-fail, format and disable-ignore-pragma are converted.
+Settings without a tally equivalent are listed as warnings.
+
+Without an argument, .hadolint.yaml (or .hadolint.yml) in the current
+directory is read. The result is written to .tally.toml next to it; use
+--output - to print it instead.
+
+Examples:
+  tally migrate --from hadolint
+  tally migrate --from hadolint ci/.hadolint.yaml --output -`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from != "hadolint" {
+				return fmt.Errorf("unsupported --from %q (available: hadolint)", from)
+			}
+			source := ""
+			if len(args) == 1 {
+				source = args[0]
+			} else {
+				for _, name := range config.HadolintConfigFileNames {
+					if _, err := os.Stat(name); err == nil {
+						source = name
+						break
+					}
+				}
+			}
+			if source == "" {
+				fmt.Fprintln(os.Stderr, "Error: no hadolint config file found")
+				return exitWith(ExitConfigError)
+			}
+			if output == "" {
+				output = filepath.Join(filepath.Dir(source), config.ConfigFileNames[0])
+			}
+			return runMigrateHadolint(source, output, force, os.Stdout, os.Stderr)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Linter whose config to convert (hadolint)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", `Where to write the config ("-" for stdout; default: .tally.toml next to CONFIG)`)
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing output file")
+	_ = cmd.MarkFlagRequired("from")
+	return cmd
+}
+
+func runMigrateHadolint(source, output string, force bool, stdout, stderr io.Writer) error {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	migration, err := config.MigrateHadolintConfig(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s: %v\n", source, err)
+		return exitWith(ExitConfigError)
+	}
+	for _, note := range migration.Unmapped {
+		fmt.Fprintf(stderr, "Warning: %s: %s\n", source, note)
+	}
+
+	if output == "-" {
+		_, err := stdout.Write(migration.TOML)
+		return err
+	}
+	if _, err := os.Stat(output); err == nil && !force {
+		fmt.Fprintf(stderr, "Error: %s already exists (use --force to overwrite)\n", output)
+		return exitWith(ExitConfigError)
+	}
+	if err := os.WriteFile(output, migration.TOML, 0o644); err != nil {
+		fmt.Fprintf(stderr, "Error: failed to write %s: %v\n", output, err)
+		return exitWith(ExitConfigError)
+	}
+	fmt.Fprintf(stdout, "Wrote %s\n", output)
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+)
+
+func migrateConfigCommand() *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate-config [CONFIG]",
+		Short: "Rewrite deprecated rule codes in a config file",
+		Long: `Rewrite deprecated rule codes in a .tally.toml to the codes of the rules
+that replace them: rule tables, rules include and exclude lists,
+fix-precedence and severity-by-stage-role. Comments and formatting are kept.
+
+Without an argument, the config file is discovered from the current
+directory. Deprecated codes that cannot be rewritten automatically are
+listed for a manual edit.
+
+Examples:
+  tally migrate-config
+  tally migrate-config --check .tally.toml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
+			} else {
+				// Discovery starts from the target file's directory, so use
+				// a synthetic file under ".".
+				path = config.Discover(filepath.Join(".", "Dockerfile"))
+			}
+			if path == "" {
+				fmt.Fprintln(os.Stderr, "Error: no config file found")
+				return exitWith(ExitConfigError)
+			}
+			return runMigrateConfig(path, check, os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "List deprecated rule codes and exit 1 instead of writing")
+	return cmd
+}
+
+func runMigrateConfig(path string, check bool, w io.Writer) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	migrated, migrations, err := config.MigrateRuleCodes(content)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to parse %s: %v\n", path, err)
+		return exitWith(ExitConfigError)
+	}
+	for _, m := range migrations {
+		fmt.Fprintf(w, "%s:%d: %s\n", path, m.Line, m.Message())
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+	if check {
+		return exitWith(ExitViolations)
+	}
+	if bytes.Equal(migrated, content) {
+		return nil
+	}
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, migrated, mode); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", path, err)
+		return exitWith(ExitConfigError)
+	}
+	return nil
+}
@@ -59,6 +59,20 @@ type lintOptions struct {
 	fixRule      []string
 	fixUnsafe    bool
 	fixUnsafeSet bool
+	// exitCodeOnFix makes a run that applied fixes exit with ExitFixed.
+	exitCodeOnFix bool
+	// fixIterations is the maximum number of re-lint/re-fix iterations.
+	fixIterations int
+	fixOutput     string // --fix-output: fixOutputWrite or fixOutputPatch
+	diffBase      string
+	changed       bool // --changed: lint only files reported by git status
+	aiApprove     bool
+	aiTranscript  string // --ai-transcript: directory for per-fix JSON transcripts
+	stats         string // --stats: "", "text" or "json"
+	quiet         bool   // --quiet: no progress spinners on stderr
+	// Approval workflow: record or check violations in .tally-expected.json.
+	updateExpected bool
+	verifyExpected bool
 
 	// Complex (shell-quoted) AI flag: parsed then folded into the config.
 	acpCommand    string
@@ -76,6 +90,12 @@ type dockerPluginContext struct {
 
 const fixUnsafeFlagName = "fix-unsafe"
 
+// Valid --fix-output values.
+const (
+	fixOutputWrite = "write"
+	fixOutputPatch = "patch"
+)
+
 // addLintFlags registers all lint flags on the given FlagSet. The flags are
 // bound either directly to fields of opts (for operational/transform flags)
 // or live inside the pflag.FlagSet itself so the config loader can pick them
@@ -83,6 +103,7 @@ const fixUnsafeFlagName = "fix-unsafe"
 func addLintFlags(fs *pflag.FlagSet, opts *lintOptions) {
 	fs.StringVarP(&opts.configPath, "config", "c", "", "Path to config file (default: auto-discover)")
 	fs.BoolVar(&opts.noConfig, "no-config", false, "Do not discover or load config files")
+	fs.String("profile", "", "Built-in profile to layer under the config: "+strings.Join(config.Profiles, ", "))
 
 	// Config-shaped flags. Values are read back by the koanf posflag layer;
 	// we don't need Go-side variables for these.
@@ -91,15 +112,21 @@ func addLintFlags(fs *pflag.FlagSet, opts *lintOptions) {
 	fs.Bool("skip-comments", false, "Exclude comment lines from the line count")
 
 	fs.StringP("format", "f", "", "Output format: "+reporter.ValidFormatsUsage())
-	fs.StringP("output", "o", "", "Output path: stdout, stderr, or file path")
+	fs.StringP("output", "o", "",
+		"Output path: stdout, stderr, file path, or a template with {path}, {name}, {dir} or {hash} for one report per file")
 	fs.Bool("show-source", true, "Show source code snippets (default: true)")
 	fs.String("fail-level", "", "Minimum severity to cause non-zero exit: error, warning, info, style, none")
+	fs.String("group-by", "", "Group text output with per-group counts, or pick tap test points: "+reporter.ValidGroupByUsage())
+	fs.Int("annotation-limit", 0, "Maximum github-actions annotations per level (default 10; 0 = no limit)")
+	fs.Bool("show-suppressed", false, "Also list violations suppressed by inline directives or rule config (text, json, sarif)")
+	fs.Bool("show-fixes", false, "Show suggested fixes as inline diffs under each violation (text)")
 
 	fs.Bool("warn-unused-directives", false, "Warn about unused ignore directives")
 	fs.Bool("require-reason", false, "Warn about ignore directives without reason= explanation")
 
 	fs.String("slow-checks", "", "Slow checks mode: auto, on, off")
 	fs.String("slow-checks-timeout", "", "Timeout for slow checks (e.g., 20s)")
+	fs.String("slow-checks-cache-ttl", "", "How long registry lookups are cached on disk (e.g., 1h; 0 disables)")
 
 	fs.Bool("ai", false, "Enable AI AutoFix (requires an ACP agent command)")
 	fs.String("ai-timeout", "", "Per-fix AI timeout (e.g., 90s)")
@@ -117,12 +144,40 @@ func addLintFlags(fs *pflag.FlagSet, opts *lintOptions) {
 	fs.StringSliceVar(&opts.ignore, "ignore", nil, "Disable specific rules (pattern: rule-code, namespace/*, *)")
 
 	fs.StringVar(&opts.contextDir, "context", "", "Build context directory for context-aware rules")
-	fs.StringSliceVar(&opts.targets, "target", nil, "Bake target to lint (can be repeated)")
+	fs.StringArray("build-arg", nil, "Build arg as KEY=VALUE, like docker build --build-arg (can be repeated)")
+	fs.StringSliceVar(&opts.targets, "target", nil, "Bake target to lint (can be repeated), or the Dockerfile stage to build like docker build --target")
 	fs.StringSliceVar(&opts.services, "service", nil, "Compose service to lint (can be repeated)")
 
 	fs.BoolVar(&opts.fix, "fix", false, "Apply all safe fixes automatically")
 	fs.StringSliceVar(&opts.fixRule, "fix-rule", nil, "Only fix specific rules (can be repeated)")
 	fs.BoolVar(&opts.fixUnsafe, fixUnsafeFlagName, false, "Also apply suggestion/unsafe fixes (requires --fix)")
+	fs.IntVar(&opts.fixIterations, "fix-iterations", 1,
+		"Re-lint and re-fix modified files up to N times, stopping early at a fixed point")
+	fs.StringVar(&opts.fixOutput, "fix-output", fixOutputWrite,
+		"Where --fix puts its changes: write (update the files) or patch (print a patch for git apply to stdout)")
+	fs.BoolVar(&opts.exitCodeOnFix, "exit-code-on-fix", false,
+		"Exit with code 5 when fixes were applied and no violations remain at fail-level (requires --fix)")
+
+	fs.BoolVar(&opts.aiApprove, "ai-approve", false, "Review and confirm each AI AutoFix change before it is applied")
+	fs.StringVar(&opts.aiTranscript, "ai-transcript", "",
+		"Write the prompts, agent responses and edits of each AI AutoFix to JSON files in this directory")
+
+	fs.StringVar(&opts.diffBase, "diff-base", "",
+		"Only report violations on lines changed relative to this git ref (e.g. origin/main)")
+	fs.BoolVar(&opts.changed, "changed", false,
+		"Only lint Dockerfiles that git status reports as modified, staged or untracked")
+
+	fs.BoolVarP(&opts.quiet, "quiet", "q", false,
+		"Do not show progress for slow checks and AI AutoFix on stderr")
+
+	fs.StringVar(&opts.stats, "stats", "",
+		"Print run statistics (rule hits, timings, fixes) to stderr: text or json")
+	fs.Lookup("stats").NoOptDefVal = statsText
+
+	fs.BoolVar(&opts.updateExpected, "update-expected", false,
+		"Record the current violations of each Dockerfile in "+expectedFileName+" next to it")
+	fs.BoolVar(&opts.verifyExpected, "verify-expected", false,
+		"Fail when violations differ from those recorded in "+expectedFileName)
 
 	fs.StringVar(&opts.acpCommand, "acp-command", "",
 		`ACP agent command line (e.g. "gemini --experimental-acp --allowed-mcp-server-names=none --model=gemini-3-flash-preview")`)
@@ -146,6 +201,11 @@ func koanfFlagMap(f *pflag.Flag) (string, any) {
 		return "", nil
 	}
 	switch f.Name {
+	case "profile":
+		return "profile", posflagStringVal(f)
+	case "build-arg":
+		return "build-args", posflagBuildArgs(f)
+
 	// Output keys.
 	case "format":
 		return "output.format", posflagStringVal(f)
@@ -155,6 +215,14 @@ func koanfFlagMap(f *pflag.Flag) (string, any) {
 		return "output.show-source", posflagBoolVal(f)
 	case "fail-level":
 		return "output.fail-level", posflagStringVal(f)
+	case "group-by":
+		return "output.group-by", posflagStringVal(f)
+	case "annotation-limit":
+		return "output.annotation-limit", posflagIntVal(f)
+	case "show-suppressed":
+		return "output.show-suppressed", posflagBoolVal(f)
+	case "show-fixes":
+		return "output.show-fixes", posflagBoolVal(f)
 
 	// tally/max-lines rule option shortcuts.
 	case "max-lines":
@@ -175,6 +243,8 @@ func koanfFlagMap(f *pflag.Flag) (string, any) {
 		return "slow-checks.mode", posflagStringVal(f)
 	case "slow-checks-timeout":
 		return "slow-checks.timeout", posflagStringVal(f)
+	case "slow-checks-cache-ttl":
+		return "slow-checks.cache-ttl", posflagStringVal(f)
 
 	// AI.
 	case "ai":
@@ -196,6 +266,28 @@ func posflagStringVal(f *pflag.Flag) string {
 	return f.Value.String()
 }
 
+// posflagBuildArgs converts repeated --build-arg values to the build-args
+// table. Like docker build, a bare KEY takes its value from the environment
+// and is skipped when the variable is unset.
+func posflagBuildArgs(f *pflag.Flag) map[string]any {
+	sv, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return nil
+	}
+	args := make(map[string]any)
+	for _, arg := range sv.GetSlice() {
+		key, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue {
+			var set bool
+			if value, set = os.LookupEnv(key); !set {
+				continue
+			}
+		}
+		args[key] = value
+	}
+	return args
+}
+
 func posflagBoolVal(f *pflag.Flag) bool {
 	b, err := strconv.ParseBool(f.Value.String())
 	if err != nil {
@@ -213,8 +305,9 @@ func posflagIntVal(f *pflag.Flag) int {
 }
 
 // finalizeLintOptions resolves CLI-only env aliases (NO_COLOR, TALLY_EXCLUDE,
-// TALLY_FIX, TALLY_FIX_RULE, TALLY_FIX_UNSAFE, TALLY_CONTEXT, TALLY_RULES_SELECT,
-// TALLY_RULES_IGNORE, TALLY_NO_INLINE_DIRECTIVES, TALLY_ACP_COMMAND) into
+// TALLY_FIX, TALLY_FIX_RULE, TALLY_FIX_UNSAFE, TALLY_FIX_ITERATIONS,
+// TALLY_FIX_OUTPUT, TALLY_CONTEXT, TALLY_RULES_SELECT, TALLY_RULES_IGNORE,
+// TALLY_NO_INLINE_DIRECTIVES, TALLY_ACP_COMMAND, TALLY_DIFF_BASE) into
 // lintOptions. These env vars exist for CLI compatibility but are NOT part of
 // the koanf schema — config-shaped TALLY_* env vars flow through koanf's env
 // provider instead. Flag-provided values always win over env values.
@@ -285,6 +378,57 @@ func finalizeLintOptions(fs *pflag.FlagSet, opts *lintOptions) error {
 		}
 	}
 
+	if !fs.Changed("fix-iterations") {
+		if v, ok := os.LookupEnv("TALLY_FIX_ITERATIONS"); ok {
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("invalid integer for TALLY_FIX_ITERATIONS=%q: %w", v, err)
+			}
+			opts.fixIterations = n
+		}
+	}
+	if opts.fixIterations < 1 {
+		return fmt.Errorf("--fix-iterations must be at least 1, got %d", opts.fixIterations)
+	}
+
+	if !fs.Changed("fix-output") {
+		if v, ok := os.LookupEnv("TALLY_FIX_OUTPUT"); ok {
+			opts.fixOutput = strings.TrimSpace(v)
+		}
+	}
+	switch opts.fixOutput {
+	case fixOutputWrite, fixOutputPatch:
+	default:
+		return fmt.Errorf("--fix-output must be %s or %s, got %q", fixOutputWrite, fixOutputPatch, opts.fixOutput)
+	}
+
+	if !fs.Changed("diff-base") {
+		if v, ok := os.LookupEnv("TALLY_DIFF_BASE"); ok {
+			opts.diffBase = strings.TrimSpace(v)
+		}
+	}
+
+	if buildArgs, err := fs.GetStringArray("build-arg"); err == nil {
+		for _, arg := range buildArgs {
+			if key, _, _ := strings.Cut(arg, "="); key == "" {
+				return fmt.Errorf("--build-arg must be KEY=VALUE or KEY, got %q", arg)
+			}
+		}
+	}
+
+	if opts.updateExpected && opts.verifyExpected {
+		return errors.New("--update-expected and --verify-expected cannot be used together")
+	}
+	if (opts.updateExpected || opts.verifyExpected) && opts.fix {
+		return errors.New("--update-expected and --verify-expected cannot be used with --fix")
+	}
+
+	switch opts.stats {
+	case "", statsText, statsJSON:
+	default:
+		return fmt.Errorf("--stats must be %s or %s, got %q", statsText, statsJSON, opts.stats)
+	}
+
 	// --acp-command: track whether it was set so loadConfigForFile knows
 	// whether to parse it and force ai.enabled=true.
 	if fs.Changed("acp-command") {
@@ -297,18 +441,26 @@ func finalizeLintOptions(fs *pflag.FlagSet, opts *lintOptions) error {
 	return nil
 }
 
-// validateLintFlagFormat surfaces an invalid --format value before the
-// posflag layer turns it into an obscure decode error.
+// validateLintFlagFormat surfaces an invalid --format or --group-by value
+// before the posflag layer turns it into an obscure decode error.
 func validateLintFlagFormat(fs *pflag.FlagSet) error {
-	if !fs.Changed("format") {
-		return nil
-	}
-	v, err := fs.GetString("format")
-	if err != nil {
-		return err
+	if fs.Changed("format") {
+		v, err := fs.GetString("format")
+		if err != nil {
+			return err
+		}
+		if _, err := reporter.ParseFormat(v); err != nil {
+			return fmt.Errorf("invalid --format %q: %w", v, err)
+		}
 	}
-	if _, err := reporter.ParseFormat(v); err != nil {
-		return fmt.Errorf("invalid --format %q: %w", v, err)
+	if fs.Changed("group-by") {
+		v, err := fs.GetString("group-by")
+		if err != nil {
+			return err
+		}
+		if _, err := reporter.ParseGroupBy(v); err != nil {
+			return fmt.Errorf("invalid --group-by %q: %w", v, err)
+		}
 	}
 	return nil
 }
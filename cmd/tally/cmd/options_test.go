@@ -50,7 +50,7 @@ func TestKoanfFlagMap_SkipsUnchangedFlags(t *testing.T) {
 		"format", "output", "show-source", "fail-level",
 		"max-lines", "skip-blank-lines", "skip-comments",
 		"warn-unused-directives", "require-reason",
-		"slow-checks", "slow-checks-timeout",
+		"slow-checks", "slow-checks-timeout", "slow-checks-cache-ttl",
 		"ai", "ai-timeout", "ai-max-input-bytes", "ai-redact-secrets",
 	} {
 		f := fs.Lookup(name)
@@ -81,6 +81,7 @@ func TestKoanfFlagMap_ChangedFlagsRouteToCanonicalKeys(t *testing.T) {
 		key  string
 		want any
 	}{
+		{"profile", []string{"--profile", "strict"}, "profile", "strict"},
 		{"format", []string{"--format", "json"}, "output.format", "json"},
 		{"output", []string{"--output", "stderr"}, "output.path", "stderr"},
 		{"show-source", []string{"--show-source=false"}, "output.show-source", false},
@@ -92,6 +93,7 @@ func TestKoanfFlagMap_ChangedFlagsRouteToCanonicalKeys(t *testing.T) {
 		{"require-reason", []string{"--require-reason"}, "inline-directives.require-reason", true},
 		{"slow-checks", []string{"--slow-checks", "off"}, "slow-checks.mode", "off"},
 		{"slow-checks-timeout", []string{"--slow-checks-timeout", "30s"}, "slow-checks.timeout", "30s"},
+		{"slow-checks-cache-ttl", []string{"--slow-checks-cache-ttl", "6h"}, "slow-checks.cache-ttl", "6h"},
 		{"ai", []string{"--ai"}, "ai.enabled", true},
 		{"ai-timeout", []string{"--ai-timeout", "60s"}, "ai.timeout", "60s"},
 		{"ai-max-input-bytes", []string{"--ai-max-input-bytes", "1024"}, "ai.max-input-bytes", 1024},
@@ -145,6 +147,7 @@ func TestKoanfFlagMap_OperationalFlagsAreDropped(t *testing.T) {
 		{"fix", []string{"--fix"}},
 		{"fix-rule", []string{"--fix-rule", "tally/max-lines"}},
 		{"fix-unsafe", []string{"--fix-unsafe"}},
+		{"fix-iterations", []string{"--fix-iterations", "3"}},
 		{"no-color", []string{"--no-color"}},
 		{"hide-source", []string{"--hide-source"}},
 		{"no-inline-directives", []string{"--no-inline-directives"}},
@@ -181,6 +184,72 @@ func TestFinalizeLintOptions_ConfigFlagsAreMutuallyExclusive(t *testing.T) {
 	}
 }
 
+func TestKoanfFlagMap_BuildArgs(t *testing.T) {
+	t.Setenv("TALLY_TEST_FROM_ENV", "from-env")
+
+	fs := pflag.NewFlagSet("t", pflag.ContinueOnError)
+	addLintFlags(fs, &lintOptions{})
+	argv := []string{
+		"--build-arg", "VERSION=1.2",
+		"--build-arg", "EMPTY=",
+		"--build-arg", "TALLY_TEST_FROM_ENV",
+		"--build-arg", "TALLY_TEST_UNSET_ENV",
+		"--build-arg", "VERSION=1.3",
+	}
+	if err := fs.Parse(argv); err != nil {
+		t.Fatalf("parse %v: %v", argv, err)
+	}
+
+	key, val := koanfFlagMap(fs.Lookup("build-arg"))
+	if key != "build-args" {
+		t.Fatalf("key = %q, want build-args", key)
+	}
+	got, ok := val.(map[string]any)
+	if !ok {
+		t.Fatalf("val = %#v, want a map", val)
+	}
+	// The last value wins, like docker build; a bare KEY reads the environment.
+	want := map[string]any{"VERSION": "1.3", "EMPTY": "", "TALLY_TEST_FROM_ENV": "from-env"}
+	if len(got) != len(want) {
+		t.Fatalf("build args = %#v, want %#v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("build args[%q] = %#v, want %#v", k, got[k], v)
+		}
+	}
+}
+
+func TestFinalizeLintOptions_RejectsBuildArgWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	cmd, _ := buildLintCommandForTest()
+	cmd.SetArgs([]string{"--build-arg", "=1.2"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--build-arg") {
+		t.Fatalf("expected --build-arg without a key to be rejected, got %v", err)
+	}
+}
+
+func TestFinalizeLintOptions_RejectsFixIterationsBelowOne(t *testing.T) {
+	t.Parallel()
+
+	cmd, _ := buildLintCommandForTest()
+	cmd.SetArgs([]string{"--fix-iterations", "0"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --fix-iterations 0 to be rejected")
+	}
+}
+
+func TestFinalizeLintOptions_RejectsUnknownFixOutput(t *testing.T) {
+	t.Parallel()
+
+	cmd, _ := buildLintCommandForTest()
+	cmd.SetArgs([]string{"--fix-output", "diff"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--fix-output") {
+		t.Fatalf("expected --fix-output diff to be rejected, got %v", err)
+	}
+}
+
 // TestFinalizeLintOptions_EnvAliasesFillWhenFlagUnset ensures CLI-only env
 // aliases (which are intentionally NOT part of the TALLY_* koanf schema)
 // still populate lintOptions when the corresponding flag wasn't passed.
@@ -195,6 +264,8 @@ func TestFinalizeLintOptions_EnvAliasesFillWhenFlagUnset(t *testing.T) {
 	t.Setenv("TALLY_CONTEXT", "/workspace")
 	t.Setenv("TALLY_FIX", "1")
 	t.Setenv("TALLY_FIX_UNSAFE", "yes")
+	t.Setenv("TALLY_FIX_ITERATIONS", "3")
+	t.Setenv("TALLY_FIX_OUTPUT", "patch")
 	t.Setenv("TALLY_NO_INLINE_DIRECTIVES", "true")
 	t.Setenv("TALLY_ACP_COMMAND", "gemini --model foo")
 
@@ -228,6 +299,12 @@ func TestFinalizeLintOptions_EnvAliasesFillWhenFlagUnset(t *testing.T) {
 	if !opts.fixUnsafe {
 		t.Errorf("TALLY_FIX_UNSAFE=yes did not set opts.fixUnsafe=true")
 	}
+	if opts.fixIterations != 3 {
+		t.Errorf("TALLY_FIX_ITERATIONS=3 did not set opts.fixIterations: got %d", opts.fixIterations)
+	}
+	if opts.fixOutput != fixOutputPatch {
+		t.Errorf("TALLY_FIX_OUTPUT=patch did not set opts.fixOutput: got %q", opts.fixOutput)
+	}
 	if opts.noInlineDirectives == nil || !*opts.noInlineDirectives {
 		t.Errorf("TALLY_NO_INLINE_DIRECTIVES=true did not set opts.noInlineDirectives=true")
 	}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/reporter"
+)
+
+func reportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Work with reports written by tally lint",
+	}
+	cmd.AddCommand(reportMergeCommand())
+	return cmd
+}
+
+func reportMergeCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "merge <report>... [--output path]",
+		Short: "Merge JSON or SARIF reports into one",
+		Long: `Merge reports written by "tally lint --format json" or "--format sarif"
+into one report of the same format.
+
+Use it when sharded CI jobs each lint part of a repository and a single
+combined report should be published. Findings reported by more than one
+shard are kept once, and the JSON summary is recomputed from the merged
+findings. All reports must have the same format.
+
+Examples:
+  tally report merge shard-*.json --output tally.json
+  tally report merge api.sarif web.sarif -o tally.sarif`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs := make([]reporter.MergeInput, 0, len(args))
+			for _, path := range args {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					return exitWith(ExitConfigError)
+				}
+				inputs = append(inputs, reporter.MergeInput{Name: path, Data: data})
+			}
+
+			writer, closeWriter, err := reporter.GetWriter(output)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitWith(ExitConfigError)
+			}
+			defer func() {
+				if err := closeWriter(); err != nil {
+					slog.Warn("failed to close output", "error", err)
+				}
+			}()
+
+			if _, err := reporter.Merge(writer, inputs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitWith(ExitConfigError)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "stdout", "Output path: stdout, stderr, or file path")
+	return cmd
+}
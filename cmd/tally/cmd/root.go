@@ -31,6 +31,7 @@ func exitWith(code int) error {
 
 // NewRootCommand creates the tally Cobra root command.
 func NewRootCommand() *cobra.Command {
+	logOpts := &logOptions{}
 	cmd := &cobra.Command{
 		Use:     "tally",
 		Short:   "A linter for Dockerfiles and Containerfiles",
@@ -46,12 +47,27 @@ Examples:
   tally lint .`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRun: func(*cobra.Command, []string) {
+			logOpts.setup()
+		},
 	}
+	logOpts.register(cmd.PersistentFlags())
 
 	cmd.SetVersionTemplate("tally version {{.Version}}\n")
 
 	cmd.AddCommand(lintCommand())
 	cmd.AddCommand(lspCommand())
+	cmd.AddCommand(mcpCommand())
+	cmd.AddCommand(fmtCommand())
+	cmd.AddCommand(suggestCommand())
+	cmd.AddCommand(inspectCommand())
+	cmd.AddCommand(rulesCommand())
+	cmd.AddCommand(reportCommand())
+	cmd.AddCommand(schemaCommand())
+	cmd.AddCommand(migrateCommand())
+	cmd.AddCommand(migrateConfigCommand())
+	cmd.AddCommand(cacheCommand())
+	cmd.AddCommand(doctorCommand())
 	cmd.AddCommand(versionCommand())
 	cmd.AddCommand(registerDockerPluginCommand())
 
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit"
+	"github.com/wharflab/tally/internal/version"
+)
+
+func rulesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Inspect the rule set",
+	}
+	cmd.AddCommand(rulesResolveCommand(), rulesExportCommand(), rulesExplainCommand())
+	return cmd
+}
+
+func rulesResolveCommand() *cobra.Command {
+	var (
+		configPath string
+		ignore     []string
+		asJSON     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "resolve [pattern]... [--ignore pattern]...",
+		Short: "Show which rules a set of rule patterns enables",
+		Long: `Show which rules a set of rule patterns enables, and which pattern decides
+each one.
+
+Patterns are applied like --select and --ignore of "tally lint", on top of
+the configuration discovered for the current directory (or --config). Every
+rule matched by one of the patterns is listed; without patterns, every rule
+is listed.
+
+The most specific pattern wins: an exact rule code beats "namespace/*",
+which beats "*". Between equally specific patterns, command-line patterns
+override the config file, which overrides the profile.
+
+Examples:
+  tally rules resolve 'hadolint/*'
+  tally rules resolve hadolint/DL3026 --ignore 'hadolint/*'
+  tally rules resolve --json 'tally/runtime/*'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				cfg *config.Config
+				err error
+			)
+			if configPath != "" {
+				cfg, err = config.LoadFromFileWithFlags(configPath, nil, nil)
+			} else {
+				// Discovery starts from the target file's directory, so use
+				// a synthetic file under ".".
+				cfg, err = config.Load(filepath.Join(".", "Dockerfile"))
+			}
+			if err != nil {
+				return err
+			}
+			cfg.Rules.Select(args...)
+			cfg.Rules.Ignore(ignore...)
+
+			resolved := resolveRules(cfg, args, ignore)
+			if asJSON {
+				return json.MarshalWrite(os.Stdout, resolved, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+			}
+			return writeResolvedRules(os.Stdout, resolved)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to config file (default: auto-discover)")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Disable rules matching a pattern (repeatable)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output as JSON")
+	return cmd
+}
+
+// resolvedRule is the outcome of rule selection for one rule. Pattern and
+// Source are empty when no include/exclude pattern matches the rule.
+type resolvedRule struct {
+	Rule    string `json:"rule"`
+	Enabled bool   `json:"enabled"`
+	Pattern string `json:"pattern,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+// resolveRules lists the known rules matched by the selected or ignored
+// patterns (all rules when there are none) with their state under cfg.
+func resolveRules(cfg *config.Config, selected, ignored []string) []resolvedRule {
+	codes := make([]string, 0, len(rules.DefaultRegistry().All()))
+	for _, rule := range rules.DefaultRegistry().All() {
+		codes = append(codes, rule.Metadata().Code)
+	}
+	for _, info := range buildkit.Captured() {
+		if code := rules.BuildKitRulePrefix + info.Name; !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+
+	filter := &config.RulesConfig{Include: selected, Exclude: ignored}
+	enabled := linter.EnabledRuleCodes(cfg)
+
+	out := make([]resolvedRule, 0, len(codes))
+	for _, code := range codes {
+		if len(selected)+len(ignored) > 0 {
+			if _, ok := filter.Selection(code); !ok {
+				continue
+			}
+		}
+		r := resolvedRule{Rule: code, Enabled: slices.Contains(enabled, code)}
+		if sel, ok := cfg.Rules.Selection(code); ok {
+			r.Pattern = sel.Pattern
+			r.Source = sel.Source.String()
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func writeResolvedRules(w io.Writer, resolved []resolvedRule) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tSTATE\tDECIDED BY")
+	for _, r := range resolved {
+		state := "disabled"
+		if r.Enabled {
+			state = "enabled"
+		}
+		decidedBy := "default"
+		if r.Pattern != "" {
+			decidedBy = fmt.Sprintf("%s (%s)", r.Pattern, r.Source)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Rule, state, decidedBy)
+	}
+	return tw.Flush()
+}
+
+func rulesExportCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export [--format json]",
+		Short: "Print the metadata of every rule",
+		Long: `Print the metadata of every rule tally can report: severity, category,
+documentation URL, experimental status, the safety of the fixes it can
+suggest, and, for configurable rules, the JSON Schema and defaults of its
+options. The output does not depend on the configuration.
+
+Examples:
+  tally rules export --format json > rules.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "json" {
+				return fmt.Errorf("unsupported format %q (available: json)", format)
+			}
+			return json.MarshalWrite(os.Stdout, exportRules(), jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "json", "Output format (json)")
+	return cmd
+}
+
+// exportedRules is the output of "tally rules export".
+type exportedRules struct {
+	Version string         `json:"version"`
+	Rules   []exportedRule `json:"rules"`
+}
+
+// exportedRule is the exported metadata of one rule. Schema and
+// DefaultConfig are set for configurable rules only.
+type exportedRule struct {
+	Code            string         `json:"code"`
+	Namespace       string         `json:"namespace"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	DocURL          string         `json:"docUrl"`
+	DefaultSeverity rules.Severity `json:"defaultSeverity"`
+	Category        string         `json:"category"`
+	Experimental    bool           `json:"experimental"`
+	Deprecated      string         `json:"deprecated,omitempty"`
+	Aliases         []string       `json:"aliases,omitempty"`
+	Supersedes      []string       `json:"supersedes,omitempty"`
+	RelatedTo       []string       `json:"relatedTo,omitempty"`
+	Fixable         bool           `json:"fixable"`
+	Fixes           []string       `json:"fixes"`
+	FixPriority     int            `json:"fixPriority,omitzero"`
+	Schema          map[string]any `json:"schema,omitempty"`
+	DefaultConfig   any            `json:"defaultConfig,omitempty"`
+}
+
+// exportRules collects the registered rules and the BuildKit rules captured
+// during parsing, sorted by code.
+func exportRules() exportedRules {
+	var out []exportedRule
+	for _, rule := range rules.DefaultRegistry().All() {
+		r := newExportedRule(rule.Metadata())
+		if cr, ok := rule.(rules.ConfigurableRule); ok {
+			r.Schema = cr.Schema()
+			r.DefaultConfig = cr.DefaultConfig()
+		}
+		out = append(out, r)
+	}
+	for _, info := range buildkit.Captured() {
+		if rules.DefaultRegistry().Has(rules.BuildKitRulePrefix + info.Name) {
+			continue
+		}
+		if meta := buildkit.GetMetadata(info.Name); meta != nil {
+			out = append(out, newExportedRule(*meta))
+		}
+	}
+	slices.SortFunc(out, func(a, b exportedRule) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return exportedRules{Version: version.RawVersion(), Rules: out}
+}
+
+func newExportedRule(meta rules.RuleMetadata) exportedRule {
+	namespace, _, _ := strings.Cut(meta.Code, "/")
+	fixes := make([]string, 0, len(meta.Fixes))
+	for _, safety := range meta.Fixes {
+		fixes = append(fixes, safety.String())
+	}
+	return exportedRule{
+		Code:            meta.Code,
+		Namespace:       namespace,
+		Name:            meta.Name,
+		Description:     meta.Description,
+		DocURL:          meta.DocURL,
+		DefaultSeverity: meta.DefaultSeverity,
+		Category:        meta.Category,
+		Experimental:    meta.IsExperimental,
+		Deprecated:      meta.Deprecated,
+		Aliases:         meta.Aliases,
+		Supersedes:      meta.Supersedes,
+		RelatedTo:       meta.RelatedTo,
+		Fixable:         len(fixes) > 0,
+		Fixes:           fixes,
+		FixPriority:     meta.FixPriority,
+	}
+}
+
+func rulesExplainCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "explain <rule>",
+		Short: "Describe a rule and the rules it overlaps with",
+		Long: `Describe a rule: its severity, category, fixes and documentation, along
+with the rules that check the same or a neighbouring concern.
+
+"Supersedes" lists rules whose violation on the same line is dropped in
+favour of this rule's (see duplicate-precedence in the configuration);
+"Superseded by" is the reverse. "Related" lists rules worth reading
+alongside this one, in either direction.
+
+Former rule codes are accepted.
+
+Examples:
+  tally rules explain tally/mount-secret-instead-of-copy
+  tally rules explain --json buildkit/JSONArgsRecommended`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			explained, err := explainRule(args[0])
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				return json.MarshalWrite(os.Stdout, explained, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+			}
+			return writeExplainedRule(os.Stdout, explained)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output as JSON")
+	return cmd
+}
+
+// explainedRule is the output of "tally rules explain". Unlike the exported
+// metadata, RelatedTo also lists the rules that name this rule as related.
+type explainedRule struct {
+	exportedRule
+
+	SupersededBy []string `json:"supersededBy,omitempty"`
+}
+
+// explainRule looks up a rule by code or former code and collects the
+// rules linked to it in either direction.
+func explainRule(code string) (explainedRule, error) {
+	all := exportRules().Rules
+	i := slices.IndexFunc(all, func(r exportedRule) bool {
+		return r.Code == code || slices.Contains(r.Aliases, code)
+	})
+	if i < 0 {
+		return explainedRule{}, fmt.Errorf("unknown rule %q", code)
+	}
+
+	out := explainedRule{exportedRule: all[i]}
+	out.RelatedTo = slices.Clone(out.RelatedTo)
+	for _, other := range all {
+		if slices.Contains(other.Supersedes, out.Code) {
+			out.SupersededBy = append(out.SupersededBy, other.Code)
+		}
+		if slices.Contains(other.RelatedTo, out.Code) && !slices.Contains(out.RelatedTo, other.Code) {
+			out.RelatedTo = append(out.RelatedTo, other.Code)
+		}
+	}
+	return out, nil
+}
+
+func writeExplainedRule(w io.Writer, r explainedRule) error {
+	fmt.Fprintf(w, "%s: %s\n", r.Code, r.Name)
+	if r.Description != "" {
+		fmt.Fprintf(w, "%s\n", r.Description)
+	}
+	if r.Deprecated != "" {
+		fmt.Fprintf(w, "Deprecated: %s\n", r.Deprecated)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(label string, values ...string) {
+		if len(values) > 0 && values[0] != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", label, strings.Join(values, ", "))
+		}
+	}
+	row("Severity", r.DefaultSeverity.String())
+	row("Category", r.Category)
+	row("Fixes", r.Fixes...)
+	row("Aliases", r.Aliases...)
+	row("Documentation", r.DocURL)
+	row("Supersedes", r.Supersedes...)
+	row("Superseded by", r.SupersededBy...)
+	row("Related", r.RelatedTo...)
+	return tw.Flush()
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+)
+
+func TestResolveRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	selected, ignored := []string{"hadolint/DL3026"}, []string{"hadolint/*"}
+	cfg.Rules.Select(selected...)
+	cfg.Rules.Ignore(ignored...)
+
+	resolved := resolveRules(cfg, selected, ignored)
+	byRule := make(map[string]resolvedRule, len(resolved))
+	for _, r := range resolved {
+		if !strings.HasPrefix(r.Rule, "hadolint/") {
+			t.Errorf("resolved %s, which no pattern matches", r.Rule)
+		}
+		byRule[r.Rule] = r
+	}
+
+	if got := byRule["hadolint/DL3026"]; !got.Enabled || got.Pattern != "hadolint/DL3026" || got.Source != "command line" {
+		t.Errorf("hadolint/DL3026 = %+v, want enabled by hadolint/DL3026 (command line)", got)
+	}
+	if got := byRule["hadolint/DL3006"]; got.Enabled || got.Pattern != "hadolint/*" {
+		t.Errorf("hadolint/DL3006 = %+v, want disabled by hadolint/*", got)
+	}
+
+	var out bytes.Buffer
+	if err := writeResolvedRules(&out, resolved); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"DECIDED BY", "hadolint/DL3026 (command line)", "disabled"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestExportRules(t *testing.T) {
+	t.Parallel()
+
+	exported := exportRules()
+	byCode := make(map[string]exportedRule, len(exported.Rules))
+	for _, r := range exported.Rules {
+		if _, dup := byCode[r.Code]; dup {
+			t.Errorf("rule %s exported twice", r.Code)
+		}
+		byCode[r.Code] = r
+	}
+
+	maxLines, ok := byCode["tally/max-lines"]
+	if !ok {
+		t.Fatal("tally/max-lines not exported")
+	}
+	if maxLines.Namespace != "tally" || maxLines.Schema == nil || maxLines.DefaultConfig == nil || maxLines.Fixable {
+		t.Errorf("tally/max-lines = %+v, want a configurable rule without fixes", maxLines)
+	}
+
+	// Captured BuildKit rules are not registered but can be reported.
+	stageNameCasing, ok := byCode["buildkit/StageNameCasing"]
+	if !ok {
+		t.Fatal("buildkit/StageNameCasing not exported")
+	}
+	if !stageNameCasing.Fixable || !slices.Equal(stageNameCasing.Fixes, []string{"safe"}) {
+		t.Errorf("buildkit/StageNameCasing fixes = %v, want [safe]", stageNameCasing.Fixes)
+	}
+	if got := byCode["hadolint/DL3001"].Fixes; !slices.Equal(got, []string{"suggestion", "unsafe"}) {
+		t.Errorf("hadolint/DL3001 fixes = %v, want [suggestion unsafe]", got)
+	}
+}
+
+func TestExplainRule(t *testing.T) {
+	t.Parallel()
+
+	explained, err := explainRule("hadolint/DL3020")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(explained.SupersededBy, "tally/mount-secret-instead-of-copy") {
+		t.Errorf("hadolint/DL3020 superseded by %v, want tally/mount-secret-instead-of-copy", explained.SupersededBy)
+	}
+
+	// Related rules are listed in both directions.
+	explained, err = explainRule("buildkit/JSONArgsRecommended")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(explained.RelatedTo, "tally/invalid-json-form") {
+		t.Errorf("buildkit/JSONArgsRecommended related to %v, want tally/invalid-json-form", explained.RelatedTo)
+	}
+	if !slices.Contains(explained.SupersededBy, "tally/runtime/shell-form-entrypoint") {
+		t.Errorf("buildkit/JSONArgsRecommended superseded by %v, want tally/runtime/shell-form-entrypoint",
+			explained.SupersededBy)
+	}
+
+	var out bytes.Buffer
+	if err := writeExplainedRule(&out, explained); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"buildkit/JSONArgsRecommended:", "Severity:", "Related:", "tally/invalid-json-form"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if _, err := explainRule("tally/no-such-rule"); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
+
+func TestRuleLinksResolve(t *testing.T) {
+	t.Parallel()
+
+	known := make(map[string]bool)
+	exported := exportRules().Rules
+	for _, r := range exported {
+		known[r.Code] = true
+	}
+	for _, r := range exported {
+		for _, code := range slices.Concat(r.Supersedes, r.RelatedTo) {
+			if code == r.Code || !known[code] {
+				t.Errorf("%s links to %q, which is not a known rule", r.Code, code)
+			}
+		}
+	}
+}
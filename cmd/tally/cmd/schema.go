@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/schemas"
+)
+
+func schemaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schemas of tally's formats",
+	}
+	cmd.AddCommand(schemaOutputCommand())
+	cmd.AddCommand(schemaDirectivesCommand())
+	return cmd
+}
+
+func schemaOutputCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "output",
+		Short: "Print the JSON Schema of the JSON report",
+		Long: `Print the JSON Schema of the report written by "tally lint --format json"
+and "tally report merge".
+
+Reports carry the schema version they follow in "schemaVersion", as
+MAJOR.MINOR. A minor version only adds fields; removing, renaming or
+retyping a field bumps the major version, so parsers that ignore unknown
+fields keep working across minor versions.
+
+Examples:
+  tally schema output > tally-output.schema.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := cmd.OutOrStdout().Write(schemas.OutputSchema())
+			return err
+		},
+	}
+}
+
+func schemaDirectivesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "directives",
+		Short: "Print the JSON Schema of the inline directives dump",
+		Long: `Print the JSON Schema of the output of "tally inspect --directives --json".
+
+Examples:
+  tally schema directives > tally-directives.schema.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := cmd.OutOrStdout().Write(schemas.DirectivesSchema())
+			return err
+		},
+	}
+}
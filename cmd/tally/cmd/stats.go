@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// Valid --stats values. A bare --stats selects statsText.
+const (
+	statsText = "text"
+	statsJSON = "json"
+)
+
+// statsTopN bounds the rule and slow-check tables in the text summary. The
+// JSON summary always carries every entry.
+const statsTopN = 10
+
+// runStats collects timing and outcome counters for one lint run. All
+// methods are nil-safe so the lint pipeline can record unconditionally and
+// only pay for bookkeeping when --stats is set.
+//
+// Nothing collected here leaves the process: the summary is written to
+// stderr at the end of the run.
+type runStats struct {
+	start time.Time
+
+	files     map[string]*fileStats
+	fileOrder []string
+	ruleTime  map[string]time.Duration
+
+	slowChecks     []async.ResolutionTiming
+	slowChecksTime time.Duration
+
+	fixesApplied int
+	fixesSkipped map[string]int
+}
+
+type fileStats struct {
+	parse time.Duration
+	rules time.Duration
+}
+
+func newRunStats() *runStats {
+	return &runStats{
+		start:        time.Now(),
+		files:        make(map[string]*fileStats),
+		ruleTime:     make(map[string]time.Duration),
+		fixesSkipped: make(map[string]int),
+	}
+}
+
+// addFile records parse and rule time for one lint pass over file. A file
+// linted once per build invocation accumulates across passes.
+func (s *runStats) addFile(file string, parse, lint time.Duration, ruleDurations map[string]time.Duration) {
+	if s == nil {
+		return
+	}
+	fs := s.files[file]
+	if fs == nil {
+		fs = &fileStats{}
+		s.files[file] = fs
+		s.fileOrder = append(s.fileOrder, file)
+	}
+	fs.parse += parse
+	fs.rules += lint
+	for code, d := range ruleDurations {
+		s.ruleTime[code] += d
+	}
+}
+
+// addSlowChecks records resolver timings and the wall time of the async session.
+func (s *runStats) addSlowChecks(result *async.RunResult, elapsed time.Duration) {
+	if s == nil || result == nil {
+		return
+	}
+	s.slowChecks = append(s.slowChecks, result.Timings...)
+	s.slowChecksTime += elapsed
+}
+
+// addFixes records applied fixes and skipped fixes grouped by reason.
+func (s *runStats) addFixes(result *fix.Result) {
+	if s == nil || result == nil {
+		return
+	}
+	s.fixesApplied += result.TotalApplied()
+	for _, fc := range result.Changes {
+		if fc == nil {
+			continue
+		}
+		for _, skipped := range fc.FixesSkipped {
+			s.fixesSkipped[skipped.Reason.String()]++
+		}
+	}
+}
+
+// statsSummary is the --stats=json schema. Field names follow the JSON
+// reporter (snake_case) and durations are milliseconds.
+type statsSummary struct {
+	DurationMS   float64          `json:"duration_ms"`
+	ParseMS      float64          `json:"parse_ms"`
+	RulesMS      float64          `json:"rules_ms"`
+	SlowChecksMS float64          `json:"slow_checks_ms"`
+	Files        []statsFile      `json:"files"`
+	Rules        []statsRule      `json:"rules"`
+	SlowChecks   []statsSlowCheck `json:"slow_checks"`
+	Fixes        statsFixes       `json:"fixes"`
+}
+
+type statsFile struct {
+	File       string  `json:"file"`
+	ParseMS    float64 `json:"parse_ms"`
+	RulesMS    float64 `json:"rules_ms"`
+	Violations int     `json:"violations"`
+}
+
+type statsRule struct {
+	Code   string  `json:"code"`
+	Hits   int     `json:"hits"`
+	TimeMS float64 `json:"time_ms"`
+}
+
+type statsSlowCheck struct {
+	Resolver string  `json:"resolver"`
+	Key      string  `json:"key"`
+	TimeMS   float64 `json:"time_ms"`
+	Error    string  `json:"error,omitzero"`
+}
+
+type statsFixes struct {
+	Applied int            `json:"applied"`
+	Skipped map[string]int `json:"skipped"`
+}
+
+// summary folds the collected counters together with the reported
+// violations. Files keep lint order; rules are sorted by hits, then time;
+// slow checks are sorted slowest first.
+func (s *runStats) summary(violations []rules.Violation, elapsed time.Duration) statsSummary {
+	hitsByRule := make(map[string]int)
+	hitsByFile := make(map[string]int)
+	for _, v := range violations {
+		hitsByRule[v.RuleCode]++
+		hitsByFile[v.File()]++
+	}
+
+	out := statsSummary{
+		DurationMS:   millis(elapsed),
+		SlowChecksMS: millis(s.slowChecksTime),
+		Files:        make([]statsFile, 0, len(s.fileOrder)),
+		Rules:        make([]statsRule, 0, len(s.ruleTime)),
+		SlowChecks:   make([]statsSlowCheck, 0, len(s.slowChecks)),
+		Fixes:        statsFixes{Applied: s.fixesApplied, Skipped: s.fixesSkipped},
+	}
+
+	var parse, lint time.Duration
+	for _, file := range s.fileOrder {
+		fs := s.files[file]
+		parse += fs.parse
+		lint += fs.rules
+		out.Files = append(out.Files, statsFile{
+			File:       file,
+			ParseMS:    millis(fs.parse),
+			RulesMS:    millis(fs.rules),
+			Violations: hitsByFile[file],
+		})
+	}
+	out.ParseMS = millis(parse)
+	out.RulesMS = millis(lint)
+
+	// Rules that never ran (BuildKit parser warnings, async-only results)
+	// still show up with their hit counts.
+	for code := range hitsByRule {
+		if _, ok := s.ruleTime[code]; !ok {
+			out.Rules = append(out.Rules, statsRule{Code: code, Hits: hitsByRule[code]})
+		}
+	}
+	for code, d := range s.ruleTime {
+		out.Rules = append(out.Rules, statsRule{Code: code, Hits: hitsByRule[code], TimeMS: millis(d)})
+	}
+	slices.SortFunc(out.Rules, func(a, b statsRule) int {
+		return cmp.Or(cmp.Compare(b.Hits, a.Hits), cmp.Compare(b.TimeMS, a.TimeMS), cmp.Compare(a.Code, b.Code))
+	})
+
+	for _, t := range s.slowChecks {
+		sc := statsSlowCheck{Resolver: t.ResolverID, Key: t.Key, TimeMS: millis(t.Duration)}
+		if t.Err != nil {
+			sc.Error = t.Err.Error()
+		}
+		out.SlowChecks = append(out.SlowChecks, sc)
+	}
+	slices.SortStableFunc(out.SlowChecks, func(a, b statsSlowCheck) int {
+		return cmp.Compare(b.TimeMS, a.TimeMS)
+	})
+
+	return out
+}
+
+// write writes the run summary in the requested format. A nil receiver
+// (--stats not set) writes nothing.
+func (s *runStats) write(w io.Writer, format string, violations []rules.Violation) error {
+	if s == nil {
+		return nil
+	}
+	summary := s.summary(violations, time.Since(s.start))
+	if format == statsJSON {
+		if err := json.MarshalWrite(w, summary, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  ")); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w)
+		return err
+	}
+	return writeStatsText(w, summary)
+}
+
+func writeStatsText(w io.Writer, summary statsSummary) error {
+	fmt.Fprintf(w, "\nRun statistics (%s total)\n", formatMillis(summary.DurationMS))
+	fmt.Fprintf(w, "  parse:       %s\n", formatMillis(summary.ParseMS))
+	fmt.Fprintf(w, "  rules:       %s\n", formatMillis(summary.RulesMS))
+	fmt.Fprintf(w, "  slow checks: %s (%d resolved)\n", formatMillis(summary.SlowChecksMS), len(summary.SlowChecks))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "\nFILE\tPARSE\tRULES\tVIOLATIONS")
+	for _, f := range summary.Files {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", f.File, formatMillis(f.ParseMS), formatMillis(f.RulesMS), f.Violations)
+	}
+
+	fmt.Fprintln(tw, "\nRULE\tHITS\tTIME")
+	for _, r := range topRules(summary.Rules) {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", r.Code, r.Hits, formatMillis(r.TimeMS))
+	}
+
+	if len(summary.SlowChecks) > 0 {
+		fmt.Fprintln(tw, "\nSLOW CHECK\tTIME\tSTATUS")
+		for _, sc := range summary.SlowChecks[:min(len(summary.SlowChecks), statsTopN)] {
+			status := "ok"
+			if sc.Error != "" {
+				status = compactSingleLine(sc.Error, 80)
+			}
+			fmt.Fprintf(tw, "%s %s\t%s\t%s\n", sc.Resolver, sc.Key, formatMillis(sc.TimeMS), status)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	skipped := 0
+	reasons := make([]string, 0, len(summary.Fixes.Skipped))
+	for reason, n := range summary.Fixes.Skipped {
+		skipped += n
+		reasons = append(reasons, reason)
+	}
+	slices.Sort(reasons)
+	fmt.Fprintf(w, "\nFixes: %d applied, %d skipped\n", summary.Fixes.Applied, skipped)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "  %d %s\n", summary.Fixes.Skipped[reason], reason)
+	}
+	return nil
+}
+
+// topRules returns every rule with hits plus the slowest rules, capped so
+// the text table stays readable with the full rule set enabled.
+func topRules(all []statsRule) []statsRule {
+	out := make([]statsRule, 0, statsTopN)
+	for _, r := range all {
+		if r.Hits > 0 {
+			out = append(out, r)
+		}
+	}
+	slowest := slices.Clone(all)
+	slices.SortStableFunc(slowest, func(a, b statsRule) int { return cmp.Compare(b.TimeMS, a.TimeMS) })
+	for _, r := range slowest {
+		if len(out) >= statsTopN {
+			break
+		}
+		if r.Hits == 0 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func formatMillis(ms float64) string {
+	d := time.Duration(ms * float64(time.Millisecond))
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestRunStats(t *testing.T) {
+	t.Parallel()
+
+	s := newRunStats()
+	s.addFile("Dockerfile", 2*time.Millisecond, 10*time.Millisecond, map[string]time.Duration{
+		"hadolint/DL3006": time.Millisecond,
+		"tally/max-lines": 3 * time.Millisecond,
+	})
+	s.addFile("Dockerfile", 0, 5*time.Millisecond, map[string]time.Duration{"hadolint/DL3006": time.Millisecond})
+	s.addSlowChecks(&async.RunResult{Timings: []async.ResolutionTiming{
+		{ResolutionKey: async.ResolutionKey{ResolverID: "image", Key: "alpine"}, Duration: 40 * time.Millisecond},
+		{
+			ResolutionKey: async.ResolutionKey{ResolverID: "image", Key: "private/app"},
+			Duration:      90 * time.Millisecond,
+			Err:           errors.New("unauthorized"),
+		},
+	}}, 95*time.Millisecond)
+	s.addFixes(&fix.Result{Changes: map[string]*fix.FileChange{
+		"Dockerfile": {
+			FixesApplied: []fix.AppliedFix{{RuleCode: "hadolint/DL3006"}},
+			FixesSkipped: []fix.SkippedFix{
+				{RuleCode: "tally/a", Reason: fix.SkipConflict},
+				{RuleCode: "tally/b", Reason: fix.SkipConflict},
+			},
+		},
+	}})
+	// addFile on a nil collector is a no-op, mirroring --stats being unset.
+	var disabled *runStats
+	disabled.addFile("Dockerfile", time.Second, time.Second, nil)
+
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 1), "hadolint/DL3006", "msg", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 2), "buildkit/JSONArgsRecommended", "msg", rules.SeverityInfo),
+	}
+	got := s.summary(violations, time.Second)
+
+	if got.ParseMS != 2 || got.RulesMS != 15 || got.SlowChecksMS != 95 {
+		t.Errorf("totals = parse %v, rules %v, slow checks %v", got.ParseMS, got.RulesMS, got.SlowChecksMS)
+	}
+	if len(got.Files) != 1 || got.Files[0].Violations != 2 {
+		t.Errorf("files = %+v, want one entry with 2 violations", got.Files)
+	}
+	wantRules := []statsRule{
+		{Code: "hadolint/DL3006", Hits: 1, TimeMS: 2},
+		{Code: "buildkit/JSONArgsRecommended", Hits: 1},
+		{Code: "tally/max-lines", TimeMS: 3},
+	}
+	if len(got.Rules) != len(wantRules) {
+		t.Fatalf("rules = %+v, want %+v", got.Rules, wantRules)
+	}
+	for i, want := range wantRules {
+		if got.Rules[i] != want {
+			t.Errorf("rules[%d] = %+v, want %+v", i, got.Rules[i], want)
+		}
+	}
+	if len(got.SlowChecks) != 2 || got.SlowChecks[0].Key != "private/app" || got.SlowChecks[0].Error != "unauthorized" {
+		t.Errorf("slow checks = %+v, want slowest first", got.SlowChecks)
+	}
+	if got.Fixes.Applied != 1 || got.Fixes.Skipped[fix.SkipConflict.String()] != 2 {
+		t.Errorf("fixes = %+v", got.Fixes)
+	}
+
+	var text bytes.Buffer
+	if err := s.write(&text, statsText, violations); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Run statistics", "FILE", "hadolint/DL3006", "image private/app", "unauthorized",
+		"Fixes: 1 applied, 2 skipped", "2 " + fix.SkipConflict.String()} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := s.write(&out, statsJSON, violations); err != nil {
+		t.Fatal(err)
+	}
+	var decoded statsSummary
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(decoded.Rules) != 3 || decoded.Fixes.Applied != 1 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	stdcontext "context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/discovery"
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/lsp/protocol"
+	"github.com/wharflab/tally/internal/lspserver"
+	"github.com/wharflab/tally/internal/processor"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func suggestCommand() *cobra.Command {
+	opts := &lintOptions{}
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "suggest [flags] [DOCKERFILE...]",
+		Short: "Print suggested fixes as LSP workspace edits without applying them",
+		Long: `Lint Dockerfiles and print the violations that carry fixes, with every
+fix alternative expressed as an LSP WorkspaceEdit (documentChanges with
+text edits). Nothing is written to the Dockerfiles.
+
+Editor plugins and bots can apply the edits with their own workspace
+APIs instead of going through tally lint --fix. Fixes that are computed
+lazily are resolved before printing, against the file as it is on disk,
+and every fix is listed regardless of its safety level or fix mode.
+Edits of different fixes are independent and may overlap.
+
+If no files are specified, tally lints the Dockerfiles under the current
+directory.
+
+Examples:
+  tally suggest --format json Dockerfile
+  tally suggest . | jq '.suggestions[] | select(.fixes[].safety == "safe")'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "json" {
+				fmt.Fprintf(os.Stderr, "Error: unsupported format %q for suggest (supported: json)\n", format)
+				return exitWith(ExitConfigError)
+			}
+			opts.flags = cmd.Flags()
+			ctx := cmd.Context()
+			defer installPowerShellUnavailableReporter(os.Stderr)()
+			defer closeSharedPowerShellRunner(ctx)
+			return runSuggest(ctx, opts, args, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format (json)")
+	cmd.Flags().StringVarP(&opts.configPath, "config", "c", "", "Path to config file (default: auto-discover)")
+	cmd.Flags().BoolVar(&opts.noConfig, "no-config", false, "Ignore config files and use built-in defaults")
+	cmd.Flags().StringSliceVar(&opts.exclude, "exclude", nil, "Glob pattern(s) to exclude files")
+	return cmd
+}
+
+// suggestOutput is the JSON document printed by tally suggest.
+type suggestOutput struct {
+	Suggestions []suggestion `json:"suggestions"`
+}
+
+// suggestion is one violation with its fixes. Range uses LSP coordinates
+// (0-based lines and characters).
+type suggestion struct {
+	File     string         `json:"file"`
+	URI      string         `json:"uri"`
+	Rule     string         `json:"rule"`
+	Severity string         `json:"severity"`
+	Message  string         `json:"message"`
+	Range    protocol.Range `json:"range"`
+	Fixes    []suggestedFix `json:"fixes"`
+}
+
+type suggestedFix struct {
+	Title       string                  `json:"title"`
+	Safety      string                  `json:"safety"`
+	IsPreferred bool                    `json:"isPreferred"`
+	Edit        *protocol.WorkspaceEdit `json:"edit"`
+}
+
+func runSuggest(ctx stdcontext.Context, opts *lintOptions, args []string, w io.Writer) error {
+	inputs := args
+	if len(inputs) == 0 {
+		inputs = []string{"."}
+	}
+	discovered, err := discovery.Discover(inputs, discovery.Options{
+		Patterns:        discovery.DefaultPatterns(),
+		ExcludePatterns: opts.exclude,
+	})
+	if err != nil {
+		if notFound, ok := errors.AsType[*discovery.FileNotFoundError](err); ok {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", notFound)
+			return exitWith(ExitNoFiles)
+		}
+		fmt.Fprintf(os.Stderr, "Error: failed to discover files: %v\n", err)
+		return exitWith(ExitConfigError)
+	}
+	if len(discovered) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no Dockerfiles found\n")
+		return exitWith(ExitNoFiles)
+	}
+
+	out := suggestOutput{Suggestions: []suggestion{}}
+	for _, df := range discovered {
+		cfg, err := loadConfigForFile(opts, df.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+			return exitWith(ExitConfigError)
+		}
+		content, err := os.ReadFile(df.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitWith(ExitConfigError)
+		}
+		suggestions, err := suggestFixes(ctx, df.Path, content, cfg)
+		if err != nil {
+			return handleLintError(err)
+		}
+		out.Suggestions = append(out.Suggestions, suggestions...)
+	}
+
+	return json.MarshalWrite(w, out, jsontext.WithIndentPrefix(""), jsontext.WithIndent("  "))
+}
+
+// suggestFixes lints content and converts every fix of every violation into
+// a workspace edit. Async fixes are resolved eagerly against content; fixes
+// whose resolver is missing or fails are left out.
+func suggestFixes(ctx stdcontext.Context, path string, content []byte, cfg *config.Config) ([]suggestion, error) {
+	result, err := linter.LintFileContext(ctx, linter.Input{
+		FilePath: path,
+		Content:  content,
+		Config:   cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+	chain, _ := linter.CLIProcessors()
+	procCtx := processor.NewContext(
+		map[string]*config.Config{path: cfg}, cfg, map[string][]byte{path: content},
+	)
+	violations := chain.Process(result.Violations, procCtx)
+
+	docURI := fileURI(path)
+	var out []suggestion
+	for _, v := range violations {
+		preferred := v.PreferredFix()
+		var fixes []suggestedFix
+		for _, sf := range v.AllFixes() {
+			edits := sf.Edits
+			if sf.NeedsResolve {
+				edits = resolveSuggestedFix(ctx, path, content, sf)
+			}
+			if len(edits) == 0 {
+				continue
+			}
+			fixes = append(fixes, suggestedFix{
+				Title:       sf.Description,
+				Safety:      sf.Safety.String(),
+				IsPreferred: sf == preferred,
+				Edit:        lspserver.FixWorkspaceEdit(docURI, edits),
+			})
+		}
+		if len(fixes) == 0 {
+			continue
+		}
+		out = append(out, suggestion{
+			File:     path,
+			URI:      docURI,
+			Rule:     v.RuleCode,
+			Severity: v.Severity.String(),
+			Message:  v.Message,
+			Range:    lspserver.LocationRange(v.Location),
+			Fixes:    fixes,
+		})
+	}
+	return out, nil
+}
+
+// resolveSuggestedFix computes the edits of an async fix with its registered
+// resolver. Returns nil if the resolver is unknown or fails.
+func resolveSuggestedFix(ctx stdcontext.Context, path string, content []byte, sf *rules.SuggestedFix) []rules.TextEdit {
+	resolver := fix.GetResolver(sf.ResolverID)
+	if resolver == nil {
+		return nil
+	}
+	edits, err := resolver.Resolve(ctx, fix.ResolveContext{FilePath: path, Content: content}, sf)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve fix", "file", path, "fix", sf.Description, "error", err)
+		return nil
+	}
+	return edits
+}
+
+// fileURI returns the file:// URI of path, made absolute first.
+func fileURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive paths: C:/x -> /C:/x
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
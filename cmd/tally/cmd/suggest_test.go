@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestSuggestFixes(t *testing.T) {
+	t.Parallel()
+
+	content := "FROM alpine\nRUN echo a\nRUN echo b\nRUN echo c\n"
+	suggestions, err := suggestFixes(t.Context(), "Dockerfile", []byte(content), config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var heredoc *suggestion
+	for i := range suggestions {
+		s := &suggestions[i]
+		if len(s.Fixes) == 0 {
+			t.Errorf("%s: suggestion without fixes", s.Rule)
+		}
+		if s.Rule == rules.HeredocRuleCode {
+			heredoc = s
+		}
+	}
+	if heredoc == nil {
+		t.Fatalf("no %s suggestion in %+v", rules.HeredocRuleCode, suggestions)
+	}
+	if !strings.HasPrefix(heredoc.URI, "file://") || !strings.HasSuffix(heredoc.URI, "/Dockerfile") {
+		t.Errorf("URI = %q, want file URI of Dockerfile", heredoc.URI)
+	}
+
+	// The heredoc fix is async; suggest resolves it before printing.
+	changes := heredoc.Fixes[0].Edit.DocumentChanges
+	if changes == nil || len(*changes) != 1 || (*changes)[0].TextDocumentEdit == nil {
+		t.Fatalf("documentChanges = %+v, want one text document edit", changes)
+	}
+	docEdit := (*changes)[0].TextDocumentEdit
+	if string(docEdit.TextDocument.Uri) != heredoc.URI {
+		t.Errorf("edit URI = %q, want %q", docEdit.TextDocument.Uri, heredoc.URI)
+	}
+	if len(docEdit.Edits) == 0 || !strings.Contains(docEdit.Edits[0].TextEdit.NewText, "RUN <<EOF") {
+		t.Errorf("edits = %+v, want a heredoc RUN", docEdit.Edits)
+	}
+}
+
+func TestSuggestFixesSkipsViolationsWithoutFixes(t *testing.T) {
+	t.Parallel()
+
+	// DL3006 (untagged image) has no fix.
+	suggestions, err := suggestFixes(t.Context(), "Dockerfile", []byte("FROM ubuntu\n"), config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("suggestions = %+v, want none", suggestions)
+	}
+}
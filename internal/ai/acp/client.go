@@ -100,6 +100,16 @@ func (c *runClient) WaitForTerminalExit(
 	return acpsdk.WaitForTerminalExitResponse{}, acpsdk.NewInvalidRequest(map[string]any{"error": "terminal is disabled"})
 }
 
+// beginPrompt clears the output of the previous prompt (and any history the
+// agent replayed while loading a session) and makes the output limit cancel
+// the new prompt.
+func (c *runClient) beginPrompt(cancel context.CancelCauseFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancel = cancel
+	c.out = c.out[:0]
+}
+
 func (c *runClient) outputBytes() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -121,7 +131,9 @@ func (c *runClient) appendOutput(text string) {
 	defer c.mu.Unlock()
 
 	if c.maxOutputBytes > 0 && len(c.out)+len(text) > c.maxOutputBytes {
-		c.cancel(ErrOutputLimitExceeded)
+		if c.cancel != nil {
+			c.cancel(ErrOutputLimitExceeded)
+		}
 		return
 	}
 	c.out = append(c.out, text...)
@@ -27,6 +27,11 @@ type Runner struct {
 	maxOutputBytes int
 	stderrTail     int
 	terminateGrace time.Duration
+
+	// reuse keeps one agent process per command alive across Run calls.
+	reuse  bool
+	mu     sync.Mutex
+	agents map[string]*sharedAgent
 }
 
 type Option func(*Runner)
@@ -43,6 +48,13 @@ func WithTerminateGrace(d time.Duration) Option {
 	return func(r *Runner) { r.terminateGrace = d }
 }
 
+// WithSessionReuse keeps the agent process and its sessions alive across Run
+// calls instead of starting the agent for every prompt. Prompts to the same
+// agent are serialized. Callers must Close the runner to stop the agents.
+func WithSessionReuse() Option {
+	return func(r *Runner) { r.reuse = true }
+}
+
 func NewRunner(opts ...Option) *Runner {
 	r := &Runner{
 		maxOutputBytes: defaultMaxAgentOutputBytes,
@@ -163,53 +175,32 @@ func (r *Runner) Run(ctx context.Context, req RunRequest) (RunResponse, error) {
 		return RunResponse{}, &RunnerError{Op: "acp run", Err: err}
 	}
 
-	runCtx := ctx
-	var cancel context.CancelFunc
-	if req.Timeout > 0 {
-		runCtx, cancel = context.WithTimeout(runCtx, req.Timeout)
-		defer cancel()
+	if r.reuse {
+		return r.runShared(ctx, req, absCwd, start)
 	}
-	runCtx, cancelCause := context.WithCancelCause(runCtx)
-	defer func() {
-		// Ensure any in-flight RPC waits are released.
-		cancelCause(context.Canceled)
-	}()
 
-	proc, err := startAgentProcess(absCwd, req.Command, r.stderrTail, r.terminateGrace)
+	runCtx, cancelCause, cancel := promptContext(ctx, req.Timeout)
+	defer cancel()
+
+	client := newRunClient(cancelCause, r.maxOutputBytes)
+	conn, err := r.connect(ctx, runCtx, req.Command, absCwd, client)
 	if err != nil {
-		return RunResponse{}, &RunnerError{Op: "acp start", Err: err}
+		return RunResponse{}, err
 	}
+	proc := conn.proc
 	defer func() {
 		_, terr := proc.terminate()
 		_ = terr
 	}()
 
-	client := newRunClient(cancelCause, r.maxOutputBytes)
-	stdoutGate := newReadGate(proc.stdout)
-	defer stdoutGate.Open()
-
-	conn := acpsdk.NewClientSideConnection(client, proc.stdin, stdoutGate)
-	conn.SetLogger(slog.New(slog.DiscardHandler))
-	stdoutGate.Open()
-
-	if _, err := conn.Initialize(runCtx, acpsdk.InitializeRequest{
-		ProtocolVersion: acpsdk.ProtocolVersionNumber,
-		ClientCapabilities: acpsdk.ClientCapabilities{
-			Fs:       acpsdk.FileSystemCapabilities{ReadTextFile: false, WriteTextFile: false},
-			Terminal: false,
-		},
-	}); err != nil {
-		exit, termErr := proc.terminate()
-		return RunResponse{}, r.wrapErr("acp initialize", errors.Join(err, termErr), proc.stderr, exit)
-	}
-
-	sess, err := conn.NewSession(runCtx, acpsdk.NewSessionRequest{Cwd: absCwd, McpServers: []acpsdk.McpServer{}})
+	sess, err := conn.conn.NewSession(runCtx, acpsdk.NewSessionRequest{Cwd: absCwd, McpServers: []acpsdk.McpServer{}})
 	if err != nil {
 		exit, termErr := proc.terminate()
 		return RunResponse{}, r.wrapErr("acp session", errors.Join(err, termErr), proc.stderr, exit)
 	}
 
-	if _, err := conn.Prompt(runCtx, acpsdk.PromptRequest{
+	conn.log.DebugContext(ctx, "acp prompt sent", "session", sess.SessionId, "prompt_bytes", len(req.Prompt))
+	if _, err := conn.conn.Prompt(runCtx, acpsdk.PromptRequest{
 		SessionId: sess.SessionId,
 		Prompt:    []acpsdk.ContentBlock{acpsdk.TextBlock(req.Prompt)},
 	}); err != nil {
@@ -223,6 +214,8 @@ func (r *Runner) Run(ctx context.Context, req RunRequest) (RunResponse, error) {
 		ResponseBytes: client.outputBytes(),
 		Duration:      time.Since(start),
 	}
+	conn.log.DebugContext(ctx, "acp prompt finished",
+		"session", sess.SessionId, "response_bytes", stats.ResponseBytes, "duration", stats.Duration)
 
 	// Always terminate (start-per-fix model).
 	if exit, termErr := proc.terminate(); termErr != nil {
@@ -232,6 +225,78 @@ func (r *Runner) Run(ctx context.Context, req RunRequest) (RunResponse, error) {
 	return RunResponse{Text: respText, Stats: stats}, nil
 }
 
+// promptContext bounds one prompt by timeout. The cause function lets the
+// client abort the prompt with ErrOutputLimitExceeded; cancel releases both.
+func promptContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelCauseFunc, func()) {
+	runCtx := ctx
+	cancelTimeout := func() {}
+	if timeout > 0 {
+		runCtx, cancelTimeout = context.WithTimeout(runCtx, timeout)
+	}
+	runCtx, cancelCause := context.WithCancelCause(runCtx)
+	return runCtx, cancelCause, func() {
+		// Ensure any in-flight RPC waits are released.
+		cancelCause(context.Canceled)
+		cancelTimeout()
+	}
+}
+
+// agentConn is a started agent process with a completed ACP handshake.
+type agentConn struct {
+	proc *agentProcess
+	conn *acpsdk.ClientSideConnection
+	caps acpsdk.AgentCapabilities
+	log  *slog.Logger
+}
+
+// connect starts the agent process and performs the ACP handshake. The
+// process is terminated when the handshake fails.
+func (r *Runner) connect(
+	ctx, runCtx context.Context,
+	command []string,
+	absCwd string,
+	client *runClient,
+) (*agentConn, error) {
+	proc, err := startAgentProcess(absCwd, command, r.stderrTail, r.terminateGrace)
+	if err != nil {
+		return nil, &RunnerError{Op: "acp start", Err: err}
+	}
+	log := slog.Default().With("agent", command[0], "pid", proc.cmd.Process.Pid)
+	log.DebugContext(ctx, "acp agent started", "cwd", absCwd)
+
+	stdoutGate := newReadGate(proc.stdout)
+	conn := acpsdk.NewClientSideConnection(client, proc.stdin, stdoutGate)
+	conn.SetLogger(protocolLogger(ctx, log))
+	stdoutGate.Open()
+
+	start := time.Now()
+	init, err := conn.Initialize(runCtx, acpsdk.InitializeRequest{
+		ProtocolVersion: acpsdk.ProtocolVersionNumber,
+		ClientCapabilities: acpsdk.ClientCapabilities{
+			Fs:       acpsdk.FileSystemCapabilities{ReadTextFile: false, WriteTextFile: false},
+			Terminal: false,
+		},
+	})
+	if err != nil {
+		exit, termErr := proc.terminate()
+		return nil, r.wrapErr("acp initialize", errors.Join(err, termErr), proc.stderr, exit)
+	}
+	log.DebugContext(ctx, "acp agent initialized",
+		"elapsed", time.Since(start), "load_session", init.AgentCapabilities.LoadSession)
+
+	return &agentConn{proc: proc, conn: conn, caps: init.AgentCapabilities, log: log}, nil
+}
+
+// protocolLogger returns the logger for the ACP connection. The SDK reports
+// protocol chatter and agent-side errors there; it is only shown with debug
+// logging, since failures already surface as RunnerError.
+func protocolLogger(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if !log.Enabled(ctx, slog.LevelDebug) {
+		return slog.New(slog.DiscardHandler)
+	}
+	return log.With("component", "acp-sdk")
+}
+
 func (r *Runner) wrapErr(op string, err error, stderr *tailBuffer, exitCode *int) error {
 	return &RunnerError{
 		Op:       op,
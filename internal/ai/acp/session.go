@@ -0,0 +1,193 @@
+package acp
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"time"
+
+	acpsdk "github.com/coder/acp-go-sdk"
+)
+
+// sharedAgent is an agent process kept alive across Run calls by a runner
+// with session reuse. It holds one ACP session per working directory, since
+// the session cwd scopes what the agent may look at.
+//
+// Prompts are serialized through the runner's lock on the agent: agents are
+// not required to handle concurrent prompts, and one process already answers
+// a fix faster than a cold start.
+type sharedAgent struct {
+	lock chan struct{}
+
+	conn     *agentConn
+	client   *runClient
+	sessions map[string]acpsdk.SessionId
+
+	// previous holds the sessions of a process that was stopped after an
+	// error. They are restored with session/load when the restarted agent
+	// supports it.
+	previous map[string]acpsdk.SessionId
+}
+
+// acquire waits for the agent to be free, or for ctx to be done.
+func (a *sharedAgent) acquire(ctx context.Context) error {
+	select {
+	case a.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+func (a *sharedAgent) release() { <-a.lock }
+
+// sharedAgent returns the agent for command, creating it on first use.
+func (r *Runner) sharedAgent(command []string) *sharedAgent {
+	key := strings.Join(command, "\x00")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.agents == nil {
+		r.agents = make(map[string]*sharedAgent)
+	}
+	a, ok := r.agents[key]
+	if !ok {
+		a = &sharedAgent{lock: make(chan struct{}, 1)}
+		r.agents[key] = a
+	}
+	return a
+}
+
+func (r *Runner) runShared(ctx context.Context, req RunRequest, absCwd string, start time.Time) (RunResponse, error) {
+	a := r.sharedAgent(req.Command)
+
+	// Waiting for other prompts does not count against this one's timeout.
+	if err := a.acquire(ctx); err != nil {
+		return RunResponse{}, &RunnerError{Op: "acp run", Err: err}
+	}
+	defer a.release()
+
+	runCtx, cancelCause, cancel := promptContext(ctx, req.Timeout)
+	defer cancel()
+
+	if a.conn != nil {
+		select {
+		case <-a.conn.conn.Done():
+			// The agent exited since the last prompt; start a new one.
+			_, _ = a.stop()
+		default:
+		}
+	}
+	if a.conn == nil {
+		a.client = newRunClient(nil, r.maxOutputBytes)
+		conn, err := r.connect(ctx, runCtx, req.Command, absCwd, a.client)
+		if err != nil {
+			return RunResponse{}, err
+		}
+		a.conn = conn
+		a.sessions = make(map[string]acpsdk.SessionId)
+	}
+	conn := a.conn
+
+	sessID, err := a.session(ctx, runCtx, absCwd)
+	if err != nil {
+		exit, termErr := a.stop()
+		return RunResponse{}, r.wrapErr("acp session", errors.Join(err, termErr), conn.proc.stderr, exit)
+	}
+
+	a.client.beginPrompt(cancelCause)
+	conn.log.DebugContext(ctx, "acp prompt sent", "session", sessID, "prompt_bytes", len(req.Prompt))
+	if _, err := conn.conn.Prompt(runCtx, acpsdk.PromptRequest{
+		SessionId: sessID,
+		Prompt:    []acpsdk.ContentBlock{acpsdk.TextBlock(req.Prompt)},
+	}); err != nil {
+		// The agent may still be working on the prompt; a fresh process is
+		// the only reliable way to get it back to a known state.
+		exit, termErr := a.stop()
+		return RunResponse{}, r.wrapErr("acp prompt", errors.Join(err, termErr), conn.proc.stderr, exit)
+	}
+
+	stats := Stats{
+		PromptBytes:   len(req.Prompt),
+		ResponseBytes: a.client.outputBytes(),
+		Duration:      time.Since(start),
+	}
+	conn.log.DebugContext(ctx, "acp prompt finished",
+		"session", sessID, "response_bytes", stats.ResponseBytes, "duration", stats.Duration)
+
+	return RunResponse{Text: a.client.outputText(), Stats: stats}, nil
+}
+
+// session returns the session for cwd. A session from a stopped process is
+// loaded again when the agent advertises loadSession; otherwise, or when
+// loading fails, a new session is created.
+func (a *sharedAgent) session(ctx, runCtx context.Context, cwd string) (acpsdk.SessionId, error) {
+	if id, ok := a.sessions[cwd]; ok {
+		return id, nil
+	}
+
+	conn := a.conn
+	if id, ok := a.previous[cwd]; ok {
+		delete(a.previous, cwd)
+		if conn.caps.LoadSession {
+			_, err := conn.conn.LoadSession(runCtx, acpsdk.LoadSessionRequest{
+				SessionId:  id,
+				Cwd:        cwd,
+				McpServers: []acpsdk.McpServer{},
+			})
+			if err == nil {
+				conn.log.DebugContext(ctx, "acp session loaded", "session", id, "cwd", cwd)
+				a.sessions[cwd] = id
+				return id, nil
+			}
+			conn.log.DebugContext(ctx, "acp session load failed; creating a new session",
+				"session", id, "cwd", cwd, "error", err)
+		}
+	}
+
+	resp, err := conn.conn.NewSession(runCtx, acpsdk.NewSessionRequest{Cwd: cwd, McpServers: []acpsdk.McpServer{}})
+	if err != nil {
+		return "", err
+	}
+	conn.log.DebugContext(ctx, "acp session created", "session", resp.SessionId, "cwd", cwd)
+	a.sessions[cwd] = resp.SessionId
+	return resp.SessionId, nil
+}
+
+// stop terminates the agent process. Its sessions are kept for session/load
+// by the next process.
+func (a *sharedAgent) stop() (*int, error) {
+	if a.conn == nil {
+		return nil, nil
+	}
+	exit, err := a.conn.proc.terminate()
+	if len(a.sessions) > 0 {
+		if a.previous == nil {
+			a.previous = make(map[string]acpsdk.SessionId, len(a.sessions))
+		}
+		maps.Copy(a.previous, a.sessions)
+	}
+	a.conn, a.client, a.sessions = nil, nil, nil
+	return exit, err
+}
+
+// Close stops the agent processes kept alive by WithSessionReuse, waiting
+// for in-flight prompts to finish. The runner can be used again afterwards;
+// it then starts new agents.
+func (r *Runner) Close() error {
+	r.mu.Lock()
+	agents := r.agents
+	r.agents = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for _, a := range agents {
+		a.lock <- struct{}{}
+		if _, err := a.stop(); err != nil {
+			errs = append(errs, err)
+		}
+		<-a.lock
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+package acp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sessionReplyRE = regexp.MustCompile(`^pid=(\d+) session=(\S+) prompts=(\d+) loaded=(true|false)$`)
+
+type sessionReply struct {
+	pid, session, prompts string
+	loaded                bool
+}
+
+func runSessionPrompt(t *testing.T, r *Runner, command []string, cwd, prompt string) (sessionReply, error) {
+	t.Helper()
+	resp, err := r.Run(context.Background(), RunRequest{
+		Command: command,
+		Cwd:     cwd,
+		Timeout: 5 * time.Second,
+		Prompt:  prompt,
+	})
+	if err != nil {
+		return sessionReply{}, err
+	}
+	m := sessionReplyRE.FindStringSubmatch(resp.Text)
+	if m == nil {
+		t.Fatalf("unexpected response text: %q", resp.Text)
+	}
+	return sessionReply{pid: m[1], session: m[2], prompts: m[3], loaded: m[4] == "true"}, nil
+}
+
+func TestRunner_SessionReuse(t *testing.T) {
+	t.Parallel()
+
+	r := NewRunner(WithSessionReuse(), WithTerminateGrace(50*time.Millisecond))
+	t.Cleanup(func() { _ = r.Close() })
+	command := []string{testAgentBin, "-mode=session"}
+	dirA, dirB := t.TempDir(), t.TempDir()
+
+	first, err := runSessionPrompt(t, r, command, dirA, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := runSessionPrompt(t, r, command, dirA, "two")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.pid != first.pid || second.session != first.session || second.prompts != "2" {
+		t.Errorf("second prompt = %+v, want the process and session of %+v", second, first)
+	}
+
+	other, err := runSessionPrompt(t, r, command, dirB, "three")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.pid != first.pid || other.session == first.session {
+		t.Errorf("prompt in another cwd = %+v, want same process and a new session", other)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	after, err := runSessionPrompt(t, r, command, dirA, "four")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.pid == first.pid {
+		t.Errorf("prompt after Close reused process %s", after.pid)
+	}
+}
+
+func TestRunner_SessionReuseConcurrentPrompts(t *testing.T) {
+	t.Parallel()
+
+	r := NewRunner(WithSessionReuse(), WithTerminateGrace(50*time.Millisecond))
+	t.Cleanup(func() { _ = r.Close() })
+	command := []string{testAgentBin, "-mode=session"}
+	cwd := t.TempDir()
+
+	const n = 4
+	errs := make(chan error, n)
+	pids := make(chan string, n)
+	for range n {
+		go func() {
+			resp, err := r.Run(context.Background(), RunRequest{Command: command, Cwd: cwd, Timeout: 5 * time.Second, Prompt: "p"})
+			if err == nil {
+				pids <- strings.Fields(resp.Text)[0]
+			}
+			errs <- err
+		}()
+	}
+	for range n {
+		if err := <-errs; err != nil {
+			t.Fatalf("Run() error: %v", err)
+		}
+	}
+	close(pids)
+	first := <-pids
+	for pid := range pids {
+		if pid != first {
+			t.Errorf("concurrent prompts used processes %s and %s", first, pid)
+		}
+	}
+}
+
+func TestRunner_SessionReuseRestartsAfterError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		loadSession bool
+	}{
+		{name: "agent supports session/load", loadSession: true},
+		{name: "agent without session/load", loadSession: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := NewRunner(WithSessionReuse(), WithTerminateGrace(50*time.Millisecond))
+			t.Cleanup(func() { _ = r.Close() })
+			command := []string{testAgentBin, "-mode=session"}
+			if tt.loadSession {
+				command = append(command, "-load-session")
+			}
+			cwd := t.TempDir()
+
+			first, err := runSessionPrompt(t, r, command, cwd, "one")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := runSessionPrompt(t, r, command, cwd, "fail"); err == nil {
+				t.Fatal("Run() expected error for failing prompt")
+			}
+
+			next, err := runSessionPrompt(t, r, command, cwd, "two")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.pid == first.pid {
+				t.Errorf("agent process %s was not restarted after the failed prompt", next.pid)
+			}
+			if tt.loadSession {
+				if next.session != first.session || !next.loaded {
+					t.Errorf("restarted agent reply %+v, want session %s loaded", next, first.session)
+				}
+			} else if next.session == first.session || next.loaded {
+				t.Errorf("restarted agent reply %+v, want a new session", next)
+			}
+		})
+	}
+}
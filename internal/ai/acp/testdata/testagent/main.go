@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"sync"
 	"time"
 
 	acpsdk "github.com/coder/acp-go-sdk"
@@ -22,6 +23,7 @@ import (
 // - multistage: returns a canned unified-diff converting single-stage to multi-stage
 // - uv_over_conda: returns a canned unified-diff migrating conda to uv
 // - command_family_normalize: returns a canned unified-diff rewriting curl to wget
+// - session: reports its pid, session and prompt count; the prompt "fail" errors
 // - hang-prompt: ACP handshake + prompt blocks until cancelled
 // - error-newsession: NewSession returns an error
 // - error-prompt: Prompt returns an error
@@ -34,6 +36,7 @@ func main() {
 	mode := flag.String("mode", "happy", "test agent mode")
 	spawnChild := flag.Bool("spawn-child", false, "spawn a long-lived child process")
 	stderrBytes := flag.Int("stderr-bytes", 0, "write N bytes to stderr before exiting (stderr-exit mode)")
+	loadSession := flag.Bool("load-session", false, "advertise and support session/load")
 	flag.Parse()
 
 	switch *mode {
@@ -62,7 +65,7 @@ func main() {
 		fmt.Fprintln(os.Stdout, "{this is not valid jsonrpc}")
 		os.Exit(9)
 	default:
-		runACP(*mode, *spawnChild)
+		runACP(*mode, *spawnChild, *loadSession)
 	}
 }
 
@@ -87,14 +90,14 @@ func writeStderrPayload(n int) {
 	}
 }
 
-func runACP(mode string, spawnChild bool) {
+func runACP(mode string, spawnChild, loadSession bool) {
 	if spawnChild {
 		if err := startChild(); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(2)
 		}
 	}
-	ag := &testAgent{mode: mode, spawnChild: spawnChild}
+	ag := &testAgent{mode: mode, spawnChild: spawnChild, loadSession: loadSession, prompts: map[acpsdk.SessionId]int{}}
 	asc := acpsdk.NewAgentSideConnection(ag, os.Stdout, os.Stdin)
 	ag.conn = asc
 	// Block until the peer disconnects.
@@ -102,8 +105,14 @@ func runACP(mode string, spawnChild bool) {
 }
 
 type testAgent struct {
-	mode       string
-	spawnChild bool
+	mode        string
+	spawnChild  bool
+	loadSession bool
+
+	mu       sync.Mutex
+	sessions int
+	prompts  map[acpsdk.SessionId]int
+	loaded   map[acpsdk.SessionId]bool
 
 	conn *acpsdk.AgentSideConnection
 }
@@ -121,7 +130,7 @@ func (a *testAgent) Logout(ctx context.Context, params acpsdk.LogoutRequest) (ac
 func (a *testAgent) Initialize(ctx context.Context, params acpsdk.InitializeRequest) (acpsdk.InitializeResponse, error) {
 	return acpsdk.InitializeResponse{
 		ProtocolVersion:   acpsdk.ProtocolVersionNumber,
-		AgentCapabilities: acpsdk.AgentCapabilities{LoadSession: false},
+		AgentCapabilities: acpsdk.AgentCapabilities{LoadSession: a.loadSession},
 	}, nil
 }
 
@@ -135,9 +144,37 @@ func (a *testAgent) NewSession(ctx context.Context, params acpsdk.NewSessionRequ
 	if a.mode == "error-newsession" {
 		return acpsdk.NewSessionResponse{}, errors.New("forced NewSession failure")
 	}
+	if a.mode == "session" {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.sessions++
+		return acpsdk.NewSessionResponse{SessionId: acpsdk.SessionId(fmt.Sprintf("sess_%d_%d", os.Getpid(), a.sessions))}, nil
+	}
 	return acpsdk.NewSessionResponse{SessionId: acpsdk.SessionId("sess_test")}, nil
 }
 
+func (a *testAgent) LoadSession(ctx context.Context, params acpsdk.LoadSessionRequest) (acpsdk.LoadSessionResponse, error) {
+	if !a.loadSession {
+		return acpsdk.LoadSessionResponse{}, acpsdk.NewMethodNotFound(acpsdk.AgentMethodSessionLoad)
+	}
+	// Replay history the way real agents do; clients must not mistake it
+	// for the answer to the next prompt.
+	if err := a.conn.SessionUpdate(ctx, acpsdk.SessionNotification{
+		SessionId: params.SessionId,
+		Update:    acpsdk.UpdateAgentMessageText("replayed history\n"),
+	}); err != nil {
+		return acpsdk.LoadSessionResponse{}, err
+	}
+	time.Sleep(10 * time.Millisecond)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loaded == nil {
+		a.loaded = map[acpsdk.SessionId]bool{}
+	}
+	a.loaded[params.SessionId] = true
+	return acpsdk.LoadSessionResponse{}, nil
+}
+
 func (a *testAgent) SetSessionConfigOption(
 	ctx context.Context,
 	params acpsdk.SetSessionConfigOptionRequest,
@@ -149,6 +186,24 @@ func (a *testAgent) Prompt(ctx context.Context, params acpsdk.PromptRequest) (ac
 	if a.mode == "error-prompt" {
 		return acpsdk.PromptResponse{}, errors.New("forced Prompt failure")
 	}
+	if a.mode == "session" {
+		if len(params.Prompt) > 0 && params.Prompt[0].Text != nil && params.Prompt[0].Text.Text == "fail" {
+			return acpsdk.PromptResponse{}, errors.New("forced Prompt failure")
+		}
+		a.mu.Lock()
+		a.prompts[params.SessionId]++
+		out := fmt.Sprintf("pid=%d session=%s prompts=%d loaded=%t",
+			os.Getpid(), params.SessionId, a.prompts[params.SessionId], a.loaded[params.SessionId])
+		a.mu.Unlock()
+		if err := a.conn.SessionUpdate(ctx, acpsdk.SessionNotification{
+			SessionId: params.SessionId,
+			Update:    acpsdk.UpdateAgentMessageText(out),
+		}); err != nil {
+			return acpsdk.PromptResponse{}, err
+		}
+		time.Sleep(10 * time.Millisecond)
+		return acpsdk.PromptResponse{StopReason: acpsdk.StopReasonEndTurn}, nil
+	}
 	if a.mode == "hang-prompt" {
 		<-ctx.Done()
 		return acpsdk.PromptResponse{StopReason: acpsdk.StopReasonCancelled}, nil
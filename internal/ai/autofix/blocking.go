@@ -2,18 +2,65 @@ package autofix
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 
 	"github.com/wharflab/tally/internal/ai/autofixdata"
+	"github.com/wharflab/tally/internal/dockerfile"
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/tally"
+	"github.com/wharflab/tally/internal/syntax"
 )
 
-func collectBlockingIssues(violations []rules.Violation) []autofixdata.BlockingIssue {
+// regressionGate rejects proposals that introduce violations the original
+// Dockerfile did not have, at or above the severity of the violation being fixed.
+type regressionGate struct {
+	// baseline counts the original Dockerfile's violations by violationKey.
+	baseline    map[string]int
+	minSeverity rules.Severity
+}
+
+func newRegressionGate(original []rules.Violation, minSeverity rules.Severity) *regressionGate {
+	baseline := make(map[string]int, len(original))
+	for _, v := range original {
+		baseline[violationKey(v)]++
+	}
+	return &regressionGate{baseline: baseline, minSeverity: minSeverity}
+}
+
+// introduced reports which proposed violations have no counterpart in the
+// baseline and are severe enough to block. Violations are matched by rule and
+// message so that line shifts caused by the rewrite do not count as new.
+func (g *regressionGate) introduced(violations []rules.Violation) []bool {
+	remaining := maps.Clone(g.baseline)
+	out := make([]bool, len(violations))
+	for i, v := range violations {
+		key := violationKey(v)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		out[i] = v.Severity.IsAtLeast(g.minSeverity)
+	}
+	return out
+}
+
+func violationKey(v rules.Violation) string {
+	return v.RuleCode + "\x00" + v.Message
+}
+
+func collectBlockingIssues(violations []rules.Violation, gate *regressionGate) []autofixdata.BlockingIssue {
 	blocking := make([]autofixdata.BlockingIssue, 0, 8)
 	seen := make(map[string]struct{})
 
-	for _, v := range violations {
-		isBlocking := v.Severity == rules.SeverityError || v.RuleCode == unreachableStagesKey
+	var introduced []bool
+	if gate != nil {
+		introduced = gate.introduced(violations)
+	}
+
+	for i, v := range violations {
+		isRegression := introduced != nil && introduced[i]
+		isBlocking := v.Severity == rules.SeverityError || v.RuleCode == unreachableStagesKey || isRegression
 		if !isBlocking {
 			continue
 		}
@@ -28,6 +75,9 @@ func collectBlockingIssues(violations []rules.Violation) []autofixdata.BlockingI
 			Rule:    v.RuleCode,
 			Message: v.Message,
 		}
+		if isRegression {
+			issue.Message = "introduced by the proposed change: " + v.Message
+		}
 		if !v.Location.IsFileLevel() {
 			issue.Line = v.Location.Start.Line
 			issue.Column = v.Location.Start.Column
@@ -59,3 +109,29 @@ func collectBlockingIssues(violations []rules.Violation) []autofixdata.BlockingI
 
 	return blocking
 }
+
+// syntaxBlockingIssues reports fail-fast syntax errors (unknown instructions,
+// directive typos) that `tally lint` would refuse to lint, and instructions
+// the parser had to drop.
+func syntaxBlockingIssues(filePath string, parsed *dockerfile.ParseResult) []autofixdata.BlockingIssue {
+	errs := syntax.Check(filePath, parsed.AST, parsed.Source)
+	if len(errs) == 0 && len(parsed.SyntaxErrors) == 0 {
+		return nil
+	}
+	blocking := make([]autofixdata.BlockingIssue, 0, len(errs)+len(parsed.SyntaxErrors))
+	for _, se := range parsed.SyntaxErrors {
+		issue := autofixdata.BlockingIssue{Rule: tally.SyntaxErrorRuleCode, Message: se.Message}
+		if len(se.Location) > 0 {
+			issue.Line = se.Location[0].Start.Line
+		}
+		blocking = append(blocking, issue)
+	}
+	for _, e := range errs {
+		blocking = append(blocking, autofixdata.BlockingIssue{
+			Rule:    e.RuleCode,
+			Message: e.Message,
+			Line:    e.Line,
+		})
+	}
+	return blocking
+}
@@ -1,6 +1,8 @@
 package autofix
 
 import (
+	"io"
+
 	"github.com/wharflab/tally/internal/ai/autofixdata"
 	"github.com/wharflab/tally/internal/fix"
 )
@@ -16,3 +18,16 @@ func Register() {
 	}
 	fix.RegisterResolver(newResolver())
 }
+
+// Close stops the ACP agents the registered resolver kept alive for reuse
+// across fixes. Callers that Register should Close once fixing is done.
+func Close() error {
+	r, ok := fix.GetResolver(autofixdata.ResolverID).(*resolver)
+	if !ok {
+		return nil
+	}
+	if closer, ok := r.runner.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"strings"
 	"time"
@@ -38,6 +39,11 @@ type resolver struct {
 type agentConfig struct {
 	cfg     *config.Config
 	timeout time.Duration
+	// gate rejects proposals that introduce new violations. Nil disables the
+	// comparison (only error-severity findings block).
+	gate *regressionGate
+	// transcript collects the exchanges for --ai-transcript; nil when off.
+	transcript *transcript
 }
 
 type agentRunner interface {
@@ -55,14 +61,19 @@ type resolvedEditsBuilder interface {
 
 func newResolver() *resolver {
 	return &resolver{
-		runner:          acp.NewRunner(),
+		// One agent process and session serves every AI fix of the run.
+		runner:          acp.NewRunner(acp.WithSessionReuse()),
 		gitleaksFactory: detect.NewDetectorDefaultConfig,
 	}
 }
 
 func (r *resolver) ID() string { return autofixdata.ResolverID }
 
-func (r *resolver) Resolve(ctx context.Context, resolveCtx fix.ResolveContext, sf *rules.SuggestedFix) ([]rules.TextEdit, error) {
+func (r *resolver) Resolve(
+	ctx context.Context,
+	resolveCtx fix.ResolveContext,
+	sf *rules.SuggestedFix,
+) (edits []rules.TextEdit, err error) {
 	req, err := objectiveRequest(sf)
 	if err != nil {
 		return nil, err
@@ -81,13 +92,33 @@ func (r *resolver) Resolve(ctx context.Context, resolveCtx fix.ResolveContext, s
 	if err != nil {
 		return nil, err
 	}
-	ac := agentConfig{cfg: cfg, timeout: timeout}
+	// The allowlist fails closed: a fix that does not name its rule is
+	// refused when ai.rules is set.
+	if rule := req.Violation.Rule; !cfg.AI.AllowsRule(rule) {
+		if rule == "" {
+			return nil, errors.New("ai-autofix: ai.rules is set but the fix does not name its rule")
+		}
+		return nil, fmt.Errorf("ai-autofix: rule %s is not listed in ai.rules", rule)
+	}
+	ac := agentConfig{cfg: cfg, timeout: timeout, transcript: newTranscript(resolveCtx.FilePath, req, cfg)}
+	defer func() { r.record(ctx, ac.transcript, req.FixContext.Recorder, cfg, edits, err) }()
 
 	origParse, err := parseDockerfile(resolveCtx.Content, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("ai-autofix: parse original: %w", err)
 	}
 
+	// Lint the original once so proposals can be rejected for regressions.
+	origViolations, err := lintAndProcess(ctx, resolveCtx.FilePath, resolveCtx.Content, validationConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("ai-autofix: lint original: %w", err)
+	}
+	minSeverity := rules.SeverityError
+	if req.Violation.Rule != "" {
+		minSeverity = req.Violation.Severity
+	}
+	ac.gate = newRegressionGate(origViolations, minSeverity)
+
 	proposed, err := r.proposeDockerfile(ctx, resolveCtx.FilePath, resolveCtx.Content, req, obj, ac, origParse)
 	if err != nil || proposed == nil {
 		return nil, err
@@ -101,6 +132,21 @@ func (r *resolver) Resolve(ctx context.Context, resolveCtx fix.ResolveContext, s
 		return nil, nil
 	}
 
+	if approver := req.FixContext.Approver; approver != nil {
+		ok, err := approver.Approve(ctx, autofixdata.Proposal{
+			FilePath: resolveCtx.FilePath,
+			Rule:     req.Violation.Rule,
+			Original: resolveCtx.Content,
+			Proposed: []byte(newText),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ai-autofix: approval: %w", err)
+		}
+		if !ok {
+			return nil, errors.New("ai-autofix: proposal rejected during review")
+		}
+	}
+
 	if builder, ok := obj.(resolvedEditsBuilder); ok {
 		edits, err := builder.BuildResolvedEdits(resolveCtx.FilePath, resolveCtx.Content, []byte(newText), req)
 		if err != nil {
@@ -165,6 +211,7 @@ func (r *resolver) proposeDockerfile(
 		rp.input = roundInput
 		rp.proposed = proposed
 		rp.blocking = blocking
+		ac.transcript.setRound(round)
 		prompt, err := buildRoundPrompt(round, rp, mode)
 		if err != nil {
 			return nil, err
@@ -191,7 +238,7 @@ func (r *resolver) proposeDockerfile(
 			}
 		}
 
-		proposed, blocking, err = r.checkProposal(ctx, filePath, proposed, ac.cfg, origParse, obj, req)
+		proposed, blocking, err = r.checkProposal(ctx, filePath, proposed, ac, origParse, obj, req)
 		if err != nil {
 			return nil, err
 		}
@@ -222,7 +269,7 @@ type roundPromptParams struct {
 func buildRoundPrompt(round int, p roundPromptParams, mode autofixdata.OutputMode) (string, error) {
 	switch round {
 	case 1:
-		return p.obj.BuildPrompt(autofixdata.PromptContext{
+		pc := autofixdata.PromptContext{
 			FilePath:   p.filePath,
 			Source:     p.input,
 			Request:    p.req,
@@ -231,7 +278,11 @@ func buildRoundPrompt(round int, p roundPromptParams, mode autofixdata.OutputMod
 			ContextDir: p.contextDir,
 			OrigParse:  p.origParse,
 			Mode:       mode,
-		})
+		}
+		if tmpl, ok := autofixdata.CustomPromptTemplate(pc); ok {
+			return autofixdata.BuildCustomPrompt(tmpl, pc)
+		}
+		return p.obj.BuildPrompt(pc)
 	case 2:
 		return p.obj.BuildRetryPrompt(autofixdata.RetryPromptContext{
 			FilePath:       p.filePath,
@@ -356,28 +407,33 @@ func (r *resolver) checkProposal(
 	ctx context.Context,
 	filePath string,
 	proposed []byte,
-	cfg *config.Config,
+	ac agentConfig,
 	origParse *dockerfile.ParseResult,
 	obj autofixdata.Objective,
 	req *autofixdata.ObjectiveRequest,
 ) ([]byte, []autofixdata.BlockingIssue, error) {
 	var blocking []autofixdata.BlockingIssue
+	cfg := ac.cfg
 
 	propParse, parseErr := parseDockerfile(proposed, cfg)
-	if parseErr != nil {
+	switch {
+	case parseErr != nil:
 		blocking = []autofixdata.BlockingIssue{{
 			Rule:    "syntax",
 			Message: "proposed Dockerfile failed to parse: " + parseErr.Error(),
 		}}
-	} else {
-		blocking = obj.ValidateProposal(req, origParse, propParse)
+	default:
+		blocking = syntaxBlockingIssues(filePath, propParse)
+		if len(blocking) == 0 {
+			blocking = obj.ValidateProposal(req, origParse, propParse)
+		}
 	}
 
 	if len(blocking) > 0 {
 		return proposed, blocking, nil
 	}
 
-	proposed, blocking, err := r.validateWithLint(ctx, filePath, proposed, cfg, req.FixContext)
+	proposed, blocking, err := r.validateWithLint(ctx, filePath, proposed, ac, req.FixContext)
 	if err != nil || len(blocking) > 0 {
 		return proposed, blocking, err
 	}
@@ -430,15 +486,21 @@ func (r *resolver) runAgent(
 	}
 
 	cwd := filepath.Dir(filePath)
+	start := time.Now()
 	resp, err := r.runner.Run(ctx, acp.RunRequest{
 		Command: cfg.AI.Command,
 		Cwd:     cwd,
 		Timeout: ac.timeout,
 		Prompt:  redacted,
 	})
+	ac.transcript.addExchange(mode, redacted, resp.Text, time.Since(start), err)
 	if err != nil {
+		slog.DebugContext(ctx, "ai autofix agent run failed", "file", filePath, "mode", mode, "error", err)
 		return "", err
 	}
+	slog.DebugContext(ctx, "ai autofix agent responded",
+		"file", filePath, "mode", mode, "prompt_bytes", resp.Stats.PromptBytes,
+		"response_bytes", resp.Stats.ResponseBytes, "duration", resp.Stats.Duration)
 	return resp.Text, nil
 }
 
@@ -497,13 +559,12 @@ func (r *resolver) validateWithLint(
 	ctx context.Context,
 	filePath string,
 	proposed []byte,
-	cfg *config.Config,
+	ac agentConfig,
 	fixCtx autofixdata.FixContext,
 ) ([]byte, []autofixdata.BlockingIssue, error) {
-	lintCfg := *cfg
-	lintCfg.AI = config.AIConfig{Enabled: false}
+	lintCfg := validationConfig(ac.cfg)
 
-	violations, err := lintAndProcess(ctx, filePath, proposed, &lintCfg)
+	violations, err := lintAndProcess(ctx, filePath, proposed, lintCfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -515,17 +576,25 @@ func (r *resolver) validateWithLint(
 		}
 		if !bytes.Equal(normalized, proposed) {
 			proposed = normalized
-			violations, err = lintAndProcess(ctx, filePath, proposed, &lintCfg)
+			violations, err = lintAndProcess(ctx, filePath, proposed, lintCfg)
 			if err != nil {
 				return nil, nil, err
 			}
 		}
 	}
 
-	blocking := collectBlockingIssues(violations)
+	blocking := collectBlockingIssues(violations, ac.gate)
 	return proposed, blocking, nil
 }
 
+// validationConfig returns cfg with AI disabled so re-linting a proposal never
+// recurses into another AI resolution.
+func validationConfig(cfg *config.Config) *config.Config {
+	lintCfg := *cfg
+	lintCfg.AI = config.AIConfig{Enabled: false}
+	return &lintCfg
+}
+
 func lintAndProcess(ctx context.Context, filePath string, content []byte, cfg *config.Config) ([]rules.Violation, error) {
 	res, err := linter.LintFileContext(ctx, linter.Input{
 		FilePath: filePath,
@@ -3,6 +3,7 @@ package autofix
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -292,3 +293,156 @@ func TestResolver_Resolve_ResolvedEditsBuilderRejectsEmptyEdits(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "resolved edit builder produced no edits")
 }
+
+func TestResolver_Resolve_RuleNotInAllowlist(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{name: "unlisted rule", rule: rules.HadolintRulePrefix + "DL4001", wantErr: "not listed in ai.rules"},
+		{name: "unknown rule", wantErr: "does not name its rule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.Default()
+			cfg.AI.Enabled = true
+			cfg.AI.Timeout = "5s"
+			cfg.AI.Command = []string{"stub"}
+			cfg.AI.Rules = []string{"tally/prefer-multi-stage-build"}
+
+			req := commandFamilyNormalizeRequest(cfg)
+			req.Violation = autofixdata.ViolationContext{Rule: tt.rule}
+
+			r := &resolver{runner: &stubAgentRunner{}}
+			_, err := r.Resolve(context.Background(), fix.ResolveContext{
+				FilePath: "Dockerfile",
+				Content:  []byte("FROM ubuntu:22.04\nRUN curl -sS https://example.com/install.sh | sh\n"),
+			}, &rules.SuggestedFix{
+				NeedsResolve: true,
+				ResolverID:   autofixdata.ResolverID,
+				ResolverData: req,
+			})
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBuildRoundPrompt_CustomTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.AI.Prompts = map[string]string{
+		"tally/prefer-multi-stage-build": "Split {{.File}} into stages ({{.Rule}}).\n{{.Detail}}\n{{.Excerpt}}",
+	}
+
+	p := testRoundParams(t)
+	p.cfg = cfg
+	p.input = []byte("FROM golang:1.22\nRUN go build ./...\n")
+	p.req = &autofixdata.ObjectiveRequest{
+		Kind: autofixdata.ObjectiveMultiStage,
+		File: "Dockerfile",
+		Violation: autofixdata.ViolationContext{
+			Rule:      "tally/prefer-multi-stage-build",
+			Detail:    "build tools ship in the final image",
+			StartLine: 1,
+		},
+	}
+
+	prompt, err := buildRoundPrompt(1, p, autofixdata.OutputPatch)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(prompt,
+		"Split Dockerfile into stages (tally/prefer-multi-stage-build).\n"+
+			"build tools ship in the final image\n"+
+			"1 | FROM golang:1.22\n"+
+			"2 | RUN go build ./...\n"), prompt)
+	require.Contains(t, prompt, "Input Dockerfile (Dockerfile, 2 lines)")
+	require.Contains(t, prompt, "Output format:")
+
+	// Rules without a template keep the objective's built-in prompt.
+	p.req.Violation.Rule = "tally/other"
+	prompt, err = buildRoundPrompt(1, p, autofixdata.OutputPatch)
+	require.NoError(t, err)
+	require.NotContains(t, prompt, "Split Dockerfile into stages")
+}
+
+type stubApprover struct {
+	approve bool
+	got     []autofixdata.Proposal
+}
+
+func (a *stubApprover) Approve(_ context.Context, p autofixdata.Proposal) (bool, error) {
+	a.got = append(a.got, p)
+	return a.approve, nil
+}
+
+func TestResolver_Resolve_ApproverRejects(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.AI.Enabled = true
+	cfg.AI.Timeout = "5s"
+	cfg.AI.Command = []string{"stub"}
+	cfg.AI.RedactSecrets = false
+
+	approver := &stubApprover{}
+	req := commandFamilyNormalizeRequest(cfg)
+	req.FixContext.Approver = approver
+
+	r := &resolver{
+		runner: &stubAgentRunner{texts: []string{
+			"```diff\n" +
+				"diff --git a/Dockerfile b/Dockerfile\n" +
+				"--- a/Dockerfile\n" +
+				"+++ b/Dockerfile\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" FROM ubuntu:22.04\n" +
+				" RUN wget -qO- https://example.com/bootstrap.sh >/dev/null\n" +
+				"-RUN curl -sS https://example.com/install.sh | sh\n" +
+				"+RUN wget -nv -O- https://example.com/install.sh | sh\n" +
+				"```\n",
+		}},
+	}
+
+	original := "FROM ubuntu:22.04\n" +
+		"RUN wget -qO- https://example.com/bootstrap.sh >/dev/null\n" +
+		"RUN curl -sS https://example.com/install.sh | sh\n"
+	_, err := r.Resolve(context.Background(), fix.ResolveContext{
+		FilePath: "Dockerfile",
+		Content:  []byte(original),
+	}, &rules.SuggestedFix{
+		NeedsResolve: true,
+		ResolverID:   autofixdata.ResolverID,
+		ResolverData: req,
+	})
+	require.ErrorContains(t, err, "proposal rejected during review")
+	require.Len(t, approver.got, 1)
+	require.Equal(t, original, string(approver.got[0].Original))
+	require.Contains(t, string(approver.got[0].Proposed), "wget -nv -O-")
+}
+
+func TestCollectBlockingIssues_RegressionGate(t *testing.T) {
+	t.Parallel()
+
+	existing := rules.Violation{RuleCode: "tally/max-lines", Message: "too long", Severity: rules.SeverityWarning}
+	gate := newRegressionGate([]rules.Violation{existing}, rules.SeverityWarning)
+
+	shifted := existing
+	shifted.Location = rules.NewLineLocation("Dockerfile", 7)
+	added := rules.Violation{
+		RuleCode: "buildkit/MaintainerDeprecated",
+		Message:  "MAINTAINER is deprecated",
+		Severity: rules.SeverityWarning,
+		Location: rules.NewLineLocation("Dockerfile", 2),
+	}
+	minor := rules.Violation{RuleCode: "tally/newline-per-chained-call", Message: "style", Severity: rules.SeverityStyle}
+
+	issues := collectBlockingIssues([]rules.Violation{shifted, added, minor}, gate)
+	require.Len(t, issues, 1)
+	require.Equal(t, "buildkit/MaintainerDeprecated", issues[0].Rule)
+	require.Equal(t, "introduced by the proposed change: MAINTAINER is deprecated", issues[0].Message)
+}
@@ -0,0 +1,107 @@
+package autofix
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// transcript collects the agent exchanges of one resolution for
+// --ai-transcript. All methods are no-ops on a nil transcript, which is what
+// the resolver carries when no recorder is set.
+type transcript struct {
+	round int
+	data  autofixdata.Transcript
+}
+
+func newTranscript(filePath string, req *autofixdata.ObjectiveRequest, cfg *config.Config) *transcript {
+	if req.FixContext.Recorder == nil {
+		return nil
+	}
+	return &transcript{data: autofixdata.Transcript{
+		File:      filePath,
+		Rule:      req.Violation.Rule,
+		Objective: req.Kind,
+		Agent:     cfg.AI.Command,
+		Exchanges: []autofixdata.Exchange{},
+	}}
+}
+
+func (t *transcript) setRound(round int) {
+	if t != nil {
+		t.round = round
+	}
+}
+
+// addExchange records a prompt exactly as it was sent to the agent.
+func (t *transcript) addExchange(mode autofixdata.OutputMode, prompt, response string, d time.Duration, err error) {
+	if t == nil {
+		return
+	}
+	ex := autofixdata.Exchange{
+		Round:      t.round,
+		Mode:       mode,
+		Prompt:     prompt,
+		Response:   response,
+		DurationMS: d.Milliseconds(),
+	}
+	if err != nil {
+		ex.Error = err.Error()
+	}
+	t.data.Exchanges = append(t.data.Exchanges, ex)
+}
+
+// record completes the transcript with the outcome of the resolution and
+// hands it to the recorder. Edits carry the original Dockerfile content, so
+// they are redacted like prompts when ai.redact-secrets is on.
+func (r *resolver) record(
+	ctx context.Context,
+	t *transcript,
+	rec autofixdata.TranscriptRecorder,
+	cfg *config.Config,
+	edits []rules.TextEdit,
+	err error,
+) {
+	if t == nil {
+		return
+	}
+	switch {
+	case err != nil:
+		t.data.Outcome = autofixdata.TranscriptFailed
+		t.data.Error = err.Error()
+	case len(edits) == 0:
+		t.data.Outcome = autofixdata.TranscriptUnchanged
+	default:
+		t.data.Outcome = autofixdata.TranscriptResolved
+		t.data.Edits = edits
+		if cfg.AI.RedactSecrets {
+			t.data.Edits = r.redactEdits(ctx, edits)
+		}
+	}
+	if err := rec.Record(t.data); err != nil {
+		slog.WarnContext(ctx, "failed to record AI AutoFix transcript", "file", t.data.File, "error", err)
+	}
+}
+
+// redactEdits returns a copy of edits with secrets redacted. Without a
+// detector the edit text is dropped rather than recorded unredacted.
+func (r *resolver) redactEdits(ctx context.Context, edits []rules.TextEdit) []rules.TextEdit {
+	det, err := r.gitleaksFactory()
+	out := make([]rules.TextEdit, len(edits))
+	for i, e := range edits {
+		out[i] = e
+		if err != nil {
+			out[i].NewText = ""
+			continue
+		}
+		out[i].NewText, _ = redactSecrets(det, e.NewText)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "secret detector unavailable; AI AutoFix transcript omits edit text", "error", err)
+	}
+	return out
+}
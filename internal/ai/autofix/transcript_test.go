@@ -0,0 +1,140 @@
+package autofix
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zricethezav/gitleaks/v8/detect"
+
+	"github.com/wharflab/tally/internal/ai/acp"
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+type stubRecorder struct {
+	got []autofixdata.Transcript
+}
+
+func (r *stubRecorder) Record(t autofixdata.Transcript) error {
+	r.got = append(r.got, t)
+	return nil
+}
+
+type failingAgentRunner struct{}
+
+func (failingAgentRunner) Run(context.Context, acp.RunRequest) (acp.RunResponse, error) {
+	return acp.RunResponse{}, errors.New("agent crashed")
+}
+
+const transcriptOriginal = "FROM ubuntu:22.04\n" +
+	"RUN wget -qO- https://example.com/bootstrap.sh >/dev/null\n" +
+	"RUN curl -sS https://example.com/install.sh | sh\n"
+
+func transcriptConfig() *config.Config {
+	cfg := config.Default()
+	cfg.AI.Enabled = true
+	cfg.AI.Timeout = "5s"
+	cfg.AI.Command = []string{"stub", "--acp"}
+	cfg.AI.RedactSecrets = false
+	return cfg
+}
+
+func resolveWithRecorder(t *testing.T, runner agentRunner) (*stubRecorder, []rules.TextEdit, error) {
+	t.Helper()
+	rec := &stubRecorder{}
+	req := commandFamilyNormalizeRequest(transcriptConfig())
+	req.Violation.Rule = rules.HadolintRulePrefix + "DL4001"
+	req.FixContext.Recorder = rec
+
+	r := &resolver{runner: runner}
+	edits, err := r.Resolve(context.Background(), fix.ResolveContext{
+		FilePath: "Dockerfile",
+		Content:  []byte(transcriptOriginal),
+	}, &rules.SuggestedFix{
+		NeedsResolve: true,
+		ResolverID:   autofixdata.ResolverID,
+		ResolverData: req,
+	})
+	return rec, edits, err
+}
+
+func TestResolver_Resolve_RecordsTranscript(t *testing.T) {
+	t.Parallel()
+
+	response := "```diff\n" +
+		"--- a/Dockerfile\n" +
+		"+++ b/Dockerfile\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" FROM ubuntu:22.04\n" +
+		" RUN wget -qO- https://example.com/bootstrap.sh >/dev/null\n" +
+		"-RUN curl -sS https://example.com/install.sh | sh\n" +
+		"+RUN wget -nv -O- https://example.com/install.sh | sh\n" +
+		"```\n"
+	rec, edits, err := resolveWithRecorder(t, &stubAgentRunner{texts: []string{response}})
+	require.NoError(t, err)
+	require.Len(t, rec.got, 1)
+
+	tr := rec.got[0]
+	require.Equal(t, "Dockerfile", tr.File)
+	require.Equal(t, "hadolint/DL4001", tr.Rule)
+	require.Equal(t, autofixdata.ObjectiveCommandFamilyNormalize, tr.Objective)
+	require.Equal(t, []string{"stub", "--acp"}, tr.Agent)
+	require.Equal(t, autofixdata.TranscriptResolved, tr.Outcome)
+	require.Equal(t, edits, tr.Edits)
+
+	require.Len(t, tr.Exchanges, 1)
+	ex := tr.Exchanges[0]
+	require.Equal(t, 1, ex.Round)
+	require.Equal(t, autofixdata.OutputPatch, ex.Mode)
+	require.Contains(t, ex.Prompt, "curl -sS https://example.com/install.sh")
+	require.Equal(t, response, ex.Response)
+	require.Empty(t, ex.Error)
+}
+
+func TestResolver_Resolve_RecordsFailedTranscript(t *testing.T) {
+	t.Parallel()
+
+	rec, _, err := resolveWithRecorder(t, failingAgentRunner{})
+	require.ErrorContains(t, err, "agent crashed")
+	require.Len(t, rec.got, 1)
+
+	tr := rec.got[0]
+	require.Equal(t, autofixdata.TranscriptFailed, tr.Outcome)
+	require.Equal(t, "agent crashed", tr.Error)
+	require.Empty(t, tr.Edits)
+	require.Len(t, tr.Exchanges, 1)
+	require.Equal(t, "agent crashed", tr.Exchanges[0].Error)
+	require.NotEmpty(t, tr.Exchanges[0].Prompt)
+}
+
+func TestResolver_RecordRedactsEdits(t *testing.T) {
+	t.Parallel()
+
+	cfg := transcriptConfig()
+	cfg.AI.RedactSecrets = true
+	secret := "ghp_" + strings.Repeat("1", 36)
+	edits := []rules.TextEdit{{
+		Location: rules.NewRangeLocation("Dockerfile", 1, 0, 1, 0),
+		NewText:  "ENV TOKEN=" + secret + "\n",
+	}}
+
+	rec := &stubRecorder{}
+	r := &resolver{gitleaksFactory: func() (*detect.Detector, error) { return testSecretDetector(), nil }}
+	r.record(context.Background(), &transcript{}, rec, cfg, edits, nil)
+
+	require.Len(t, rec.got, 1)
+	require.Equal(t, "ENV TOKEN=REDACTED\n", rec.got[0].Edits[0].NewText)
+	require.Contains(t, edits[0].NewText, secret, "the applied edit must not be redacted")
+}
+
+func TestResolver_Resolve_NoTranscriptWithoutRecorder(t *testing.T) {
+	t.Parallel()
+
+	req := commandFamilyNormalizeRequest(transcriptConfig())
+	require.Nil(t, newTranscript("Dockerfile", req, req.Config))
+}
@@ -1,6 +1,7 @@
 package autofixdata
 
 import (
+	"context"
 	"math"
 
 	"github.com/wharflab/tally/internal/config"
@@ -30,6 +31,27 @@ type FixContext struct {
 	SafetyThresholds map[string]rules.FixSafety
 	RuleFilter       []string
 	FixModes         map[string]map[string]config.FixMode
+
+	// Approver, when set, must confirm each validated proposal before it is
+	// applied (--ai-approve).
+	Approver Approver
+
+	// Recorder, when set, receives the transcript of each resolution
+	// (--ai-transcript).
+	Recorder TranscriptRecorder
+}
+
+// Approver confirms AI AutoFix proposals before they are applied.
+type Approver interface {
+	Approve(ctx context.Context, p Proposal) (bool, error)
+}
+
+// Proposal is a validated AI AutoFix rewrite awaiting approval.
+type Proposal struct {
+	FilePath string
+	Rule     string
+	Original []byte
+	Proposed []byte
 }
 
 type SignalKind string
@@ -79,6 +101,34 @@ type ObjectiveRequest struct {
 	// ContextDir is the explicit build context directory (--context flag).
 	// Empty when not provided. It is attached by cmd/tally/cmd/lint.go:applyFixes.
 	ContextDir string `json:"-"`
+
+	// Violation describes the violation that offered this fix. It drives the
+	// ai.rules allowlist and ai.prompts templates.
+	// It is attached by cmd/tally/cmd/lint.go:applyFixes.
+	Violation ViolationContext `json:"-"`
+}
+
+// ViolationContext is the subset of rules.Violation exposed to the AI
+// resolver and custom prompt templates.
+type ViolationContext struct {
+	Rule      string
+	Message   string
+	Detail    string
+	Severity  rules.Severity
+	StartLine int
+	EndLine   int
+}
+
+// NewViolationContext captures the prompt-relevant fields of v.
+func NewViolationContext(v rules.Violation) ViolationContext {
+	return ViolationContext{
+		Rule:      v.RuleCode,
+		Message:   v.Message,
+		Detail:    v.Detail,
+		Severity:  v.Severity,
+		StartLine: v.Location.Start.Line,
+		EndLine:   v.Location.End.Line,
+	}
 }
 
 func (r *ObjectiveRequest) SetConfig(cfg *config.Config) { r.Config = cfg }
@@ -87,6 +137,8 @@ func (r *ObjectiveRequest) SetFixContext(ctx FixContext) { r.FixContext = ctx }
 
 func (r *ObjectiveRequest) SetContextDir(dir string) { r.ContextDir = dir }
 
+func (r *ObjectiveRequest) SetViolation(v ViolationContext) { r.Violation = v }
+
 func (r *ObjectiveRequest) SetRegistryInsights(insights []RegistryInsight) {
 	r.RegistryInsights = insights
 }
@@ -0,0 +1,103 @@
+package autofixdata
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// excerptContextLines is how many lines around the violation are included in
+// the {{.Excerpt}} placeholder of custom prompt templates.
+const excerptContextLines = 3
+
+// CustomPromptData is the data passed to ai.prompts templates.
+type CustomPromptData struct {
+	// Rule is the rule code that triggered the fix (e.g. "tally/prefer-multi-stage-build").
+	Rule string
+	// File is the Dockerfile base name.
+	File string
+	// Message and Detail are the violation message and detail text.
+	Message string
+	Detail  string
+	// Excerpt is the violation's lines plus a few lines of context, prefixed
+	// with line numbers.
+	Excerpt string
+}
+
+// CustomPromptTemplate returns the ai.prompts template configured for the
+// request's rule, if any.
+func CustomPromptTemplate(ctx PromptContext) (string, bool) {
+	if ctx.Config == nil || ctx.Request == nil || ctx.Request.Violation.Rule == "" {
+		return "", false
+	}
+	tmpl, ok := ctx.Config.AI.Prompts[ctx.Request.Violation.Rule]
+	return tmpl, ok && strings.TrimSpace(tmpl) != ""
+}
+
+// ParsePromptTemplate parses an ai.prompts template.
+func ParsePromptTemplate(rule, text string) (*template.Template, error) {
+	tmpl, err := template.New(rule).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("ai.prompts[%q]: %w", rule, err)
+	}
+	return tmpl, nil
+}
+
+// BuildCustomPrompt renders a user-supplied ai.prompts template in place of an
+// objective's built-in instructions. The file context, input Dockerfile, and
+// output format contract are always appended so the response can still be
+// parsed and validated like any other round-1 answer.
+func BuildCustomPrompt(text string, ctx PromptContext) (string, error) {
+	v := ctx.Request.Violation
+	tmpl, err := ParsePromptTemplate(v.Rule, text)
+	if err != nil {
+		return "", err
+	}
+
+	file := filepath.Base(ctx.FilePath)
+	normalized := NormalizeLF(string(ctx.Source))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, CustomPromptData{
+		Rule:    v.Rule,
+		File:    file,
+		Message: v.Message,
+		Detail:  v.Detail,
+		Excerpt: Excerpt(normalized, v.StartLine, v.EndLine, excerptContextLines),
+	}); err != nil {
+		return "", fmt.Errorf("ai.prompts[%q]: %w", v.Rule, err)
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	WriteFileContext(&b, ctx.AbsPath, ctx.ContextDir)
+	WriteInputDockerfile(&b, file, CountLines(normalized), normalized)
+	WriteOutputFormat(&b, file, ctx.Mode)
+	return b.String(), nil
+}
+
+// Excerpt returns lines [start-context, end+context] of source, each prefixed
+// with its 1-based line number. It returns an empty string for file-level
+// locations (start < 1).
+func Excerpt(source string, start, end, context int) string {
+	if start < 1 {
+		return ""
+	}
+	if end < start {
+		end = start
+	}
+	lines := strings.Split(strings.TrimSuffix(source, "\n"), "\n")
+	from := max(start-context, 1)
+	to := min(end+context, len(lines))
+
+	width := len(strconv.Itoa(to))
+	var b strings.Builder
+	for n := from; n <= to; n++ {
+		fmt.Fprintf(&b, "%*d | %s\n", width, n, lines[n-1])
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package autofixdata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+)
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	source := strings.Repeat("line\n", 12)
+	tests := []struct {
+		name       string
+		start, end int
+		wantFirst  string
+		wantLines  int
+	}{
+		{name: "clamped at start", start: 1, end: 1, wantFirst: "1 | line", wantLines: 4},
+		{name: "range with context", start: 6, end: 7, wantFirst: " 3 | line", wantLines: 8},
+		{name: "clamped at end", start: 12, end: 12, wantFirst: " 9 | line", wantLines: 4},
+		{name: "file-level", start: -1, end: -1, wantLines: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Excerpt(source, tt.start, tt.end, 3)
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			if got == "" {
+				lines = nil
+			}
+			if len(lines) != tt.wantLines {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), tt.wantLines, got)
+			}
+			if tt.wantLines > 0 && lines[0] != tt.wantFirst {
+				t.Errorf("first line = %q, want %q", lines[0], tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestBuildCustomPromptInvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	ctx := PromptContext{
+		FilePath: "Dockerfile",
+		Source:   []byte("FROM alpine\n"),
+		Config:   cfg,
+		Request:  &ObjectiveRequest{Violation: ViolationContext{Rule: "tally/x"}},
+	}
+	if _, err := BuildCustomPrompt("{{.Unknown}}", ctx); err == nil || !strings.Contains(err.Error(), `ai.prompts["tally/x"]`) {
+		t.Errorf("expected ai.prompts error, got %v", err)
+	}
+	if _, err := BuildCustomPrompt("{{.Rule", ctx); err == nil {
+		t.Error("expected parse error")
+	}
+}
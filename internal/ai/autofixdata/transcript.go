@@ -0,0 +1,41 @@
+package autofixdata
+
+import "github.com/wharflab/tally/internal/rules"
+
+// TranscriptRecorder receives a transcript for every AI AutoFix resolution
+// (--ai-transcript). Resolvers run concurrently, so implementations must be
+// safe for concurrent use.
+type TranscriptRecorder interface {
+	Record(t Transcript) error
+}
+
+// Transcript outcomes.
+const (
+	TranscriptResolved  = "resolved"
+	TranscriptUnchanged = "unchanged"
+	TranscriptFailed    = "failed"
+)
+
+// Transcript records what one AI AutoFix resolution sent to the agent and
+// what came back. Prompts are recorded as sent, so they are redacted when
+// ai.redact-secrets is on; the edits are redacted the same way.
+type Transcript struct {
+	File      string           `json:"file"`
+	Rule      string           `json:"rule,omitempty"`
+	Objective ObjectiveKind    `json:"objective"`
+	Agent     []string         `json:"agent"`
+	Exchanges []Exchange       `json:"exchanges"`
+	Outcome   string           `json:"outcome"`
+	Error     string           `json:"error,omitempty"`
+	Edits     []rules.TextEdit `json:"edits,omitempty"`
+}
+
+// Exchange is one prompt sent to the agent and its response.
+type Exchange struct {
+	Round      int        `json:"round"`
+	Mode       OutputMode `json:"mode"`
+	Prompt     string     `json:"prompt"`
+	Response   string     `json:"response,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	DurationMS int64      `json:"duration_ms"`
+}
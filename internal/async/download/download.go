@@ -0,0 +1,210 @@
+// Package download provides an async resolver that computes the SHA-256
+// checksums of remote files, as pinned by ADD --checksum.
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wharflab/tally/internal/async/httpfetch"
+)
+
+// ResolverID is the async resolver ID for remote file checksums.
+const ResolverID = "download"
+
+// DefaultMaxSize is the largest file the resolver downloads to hash.
+const DefaultMaxSize = 512 << 20
+
+// Request asks the resolver for the checksums of the files at URLs.
+type Request struct {
+	URLs []string
+}
+
+// Checksums maps each requested URL to the lowercase hex SHA-256 of the
+// file it serves.
+type Checksums map[string]string
+
+// Resolver computes checksums of remote files. Resolve returns Checksums
+// for every URL of a *Request, or an error if any of them fails.
+//
+// A HEAD request comes first: when the server advertises a SHA-256
+// representation digest (Repr-Digest or Digest) for an unencoded response,
+// it is used as is. Otherwise the file is downloaded, up to MaxSize bytes,
+// and hashed. Checksums are cached for the lifetime of the resolver.
+type Resolver struct {
+	Client  *http.Client
+	MaxSize int64
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewResolver creates a resolver with a default client and size limit.
+func NewResolver() *Resolver {
+	return &Resolver{Client: httpfetch.NewClient(2 * time.Minute), MaxSize: DefaultMaxSize}
+}
+
+// ID returns the resolver identifier.
+func (r *Resolver) ID() string { return ResolverID }
+
+// Resolve computes the checksums for a *Request.
+func (r *Resolver) Resolve(ctx context.Context, data any) (any, error) {
+	req, ok := data.(*Request)
+	if !ok {
+		return nil, fmt.Errorf("download resolver: unexpected data type %T", data)
+	}
+	sums := make(Checksums, len(req.URLs))
+	for _, u := range req.URLs {
+		sum, err := r.checksum(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		sums[u] = sum
+	}
+	return sums, nil
+}
+
+func (r *Resolver) checksum(ctx context.Context, u string) (string, error) {
+	r.mu.Lock()
+	sum, ok := r.cache[u]
+	r.mu.Unlock()
+	if ok {
+		return sum, nil
+	}
+
+	sum, err := r.headDigest(ctx, u)
+	if err != nil {
+		return "", err
+	}
+	if sum == "" {
+		if sum, err = r.hashBody(ctx, u); err != nil {
+			return "", err
+		}
+	}
+
+	r.mu.Lock()
+	if r.cache == nil {
+		r.cache = make(map[string]string)
+	}
+	r.cache[u] = sum
+	r.mu.Unlock()
+	return sum, nil
+}
+
+// headDigest returns the SHA-256 the server advertises for u, or "" when it
+// advertises none. Servers that reject HEAD fall through to a download.
+func (r *Resolver) headDigest(ctx context.Context, u string) (string, error) {
+	resp, err := r.do(ctx, http.MethodHead, u)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+		return "", nil
+	case resp.StatusCode != http.StatusOK:
+		return "", &httpfetch.LookupError{URL: u, Status: resp.StatusCode}
+	case resp.ContentLength > r.maxSize():
+		return "", &TooLargeError{URL: u, Size: resp.ContentLength, MaxSize: r.maxSize()}
+	}
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		// The digest covers the encoded bytes, not the file ADD stores.
+		return "", nil
+	}
+	if sum := parseSHA256Digest(resp.Header.Get("Repr-Digest"), ":"); sum != "" {
+		return sum, nil
+	}
+	return parseSHA256Digest(resp.Header.Get("Digest"), ""), nil
+}
+
+func (r *Resolver) hashBody(ctx context.Context, u string) (string, error) {
+	resp, err := r.do(ctx, http.MethodGet, u)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &httpfetch.LookupError{URL: u, Status: resp.StatusCode}
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(resp.Body, r.maxSize()+1))
+	if err != nil {
+		return "", &httpfetch.LookupError{URL: u, Err: err}
+	}
+	if n > r.maxSize() {
+		return "", &TooLargeError{URL: u, Size: -1, MaxSize: r.maxSize()}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (r *Resolver) do(ctx context.Context, method, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &httpfetch.LookupError{URL: u, Err: err}
+	}
+	return resp, nil
+}
+
+func (r *Resolver) maxSize() int64 {
+	if r.MaxSize > 0 {
+		return r.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+// parseSHA256Digest extracts a sha-256 value from a Repr-Digest (RFC 9530,
+// base64 wrapped in colons) or Digest (RFC 3230, bare base64) header and
+// returns it as hex.
+func parseSHA256Digest(header, wrap string) string {
+	for member := range strings.SplitSeq(header, ",") {
+		alg, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok || !strings.EqualFold(alg, "sha-256") {
+			continue
+		}
+		if wrap != "" {
+			if len(value) < 2 || !strings.HasPrefix(value, wrap) || !strings.HasSuffix(value, wrap) {
+				continue
+			}
+			value = value[1 : len(value)-1]
+		}
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(raw) != sha256.Size {
+			continue
+		}
+		return hex.EncodeToString(raw)
+	}
+	return ""
+}
+
+// TooLargeError reports a file larger than the resolver's size limit.
+type TooLargeError struct {
+	URL string
+	// Size is the advertised size, or -1 when the limit was hit while reading.
+	Size    int64
+	MaxSize int64
+}
+
+func (e *TooLargeError) Error() string {
+	if e.Size >= 0 {
+		return fmt.Sprintf("download %s: %d bytes exceeds the %d byte limit", e.URL, e.Size, e.MaxSize)
+	}
+	return fmt.Sprintf("download %s: exceeds the %d byte limit", e.URL, e.MaxSize)
+}
@@ -0,0 +1,137 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/httpfetch"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	body := []byte("release tarball\n")
+	sum := sha256.Sum256(body)
+	wantHex := hex.EncodeToString(sum[:])
+	advertised := sha256.Sum256([]byte("advertised"))
+
+	var gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/file":
+			if r.Method == http.MethodGet {
+				gets.Add(1)
+			}
+			_, _ = w.Write(body)
+		case "/repr-digest":
+			w.Header().Set("Repr-Digest", "sha-512=:AAAA:, sha-256=:"+base64.StdEncoding.EncodeToString(advertised[:])+":")
+			_, _ = w.Write(body)
+		case "/digest":
+			w.Header().Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(advertised[:]))
+			_, _ = w.Write(body)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			_, _ = w.Write(body)
+		case "/private":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	r := &Resolver{Client: srv.Client()}
+	urls := []string{srv.URL + "/file", srv.URL + "/repr-digest", srv.URL + "/digest", srv.URL + "/no-head"}
+	got, err := r.Resolve(context.Background(), &Request{URLs: urls})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums, ok := got.(Checksums)
+	if !ok {
+		t.Fatalf("Resolve returned %T, want Checksums", got)
+	}
+	want := map[string]string{
+		urls[0]: wantHex,
+		urls[1]: hex.EncodeToString(advertised[:]),
+		urls[2]: hex.EncodeToString(advertised[:]),
+		urls[3]: wantHex,
+	}
+	for u, w := range want {
+		if sums[u] != w {
+			t.Errorf("checksum of %s = %q, want %q", u, sums[u], w)
+		}
+	}
+
+	// Checksums are cached.
+	if _, err := r.Resolve(context.Background(), &Request{URLs: urls[:1]}); err != nil {
+		t.Fatal(err)
+	}
+	if n := gets.Load(); n != 1 {
+		t.Errorf("GET /file %d times, want 1", n)
+	}
+
+	for path, reason := range map[string]async.SkipReason{
+		"/missing": async.SkipNotFound,
+		"/private": async.SkipAuth,
+	} {
+		_, err := r.Resolve(context.Background(), &Request{URLs: []string{srv.URL + path}})
+		var lookupErr *httpfetch.LookupError
+		if !errors.As(err, &lookupErr) || lookupErr.SkipReason() != reason {
+			t.Errorf("%s: got %v, want %s LookupError", path, err, reason)
+		}
+	}
+}
+
+func TestResolver_MaxSize(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// No Content-Length: the limit is hit while reading.
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write(make([]byte, 64))
+	}))
+	defer srv.Close()
+
+	r := &Resolver{Client: srv.Client(), MaxSize: 32}
+	for _, path := range []string{"/sized", "/chunked"} {
+		_, err := r.Resolve(context.Background(), &Request{URLs: []string{srv.URL + path}})
+		var tooLarge *TooLargeError
+		if !errors.As(err, &tooLarge) {
+			t.Errorf("%s: got %v, want TooLargeError", path, err)
+		}
+	}
+}
+
+func TestParseSHA256Digest(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte("x"))
+	b64 := base64.StdEncoding.EncodeToString(sum[:])
+	tests := []struct {
+		header, wrap, want string
+	}{
+		{"sha-256=:" + b64 + ":", ":", hex.EncodeToString(sum[:])},
+		{"sha-256=" + b64, ":", ""},
+		{"md5=abc, SHA-256=" + b64, "", hex.EncodeToString(sum[:])},
+		{"sha-256=:bm90IGEgc2hhMjU2:", ":", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := parseSHA256Digest(tt.header, tt.wrap); got != tt.want {
+			t.Errorf("parseSHA256Digest(%q, %q) = %q, want %q", tt.header, tt.wrap, got, tt.want)
+		}
+	}
+}
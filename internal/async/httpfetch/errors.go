@@ -0,0 +1,42 @@
+package httpfetch
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/wharflab/tally/internal/async"
+)
+
+// LookupError reports a failed HTTP request made by a resolver or other
+// network-backed check.
+type LookupError struct {
+	URL string
+	// Status is the HTTP status code, or 0 when the request never completed.
+	Status int
+	Err    error
+}
+
+func (e *LookupError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("fetch %s: %v", e.URL, e.Err)
+	}
+	return fmt.Sprintf("fetch %s: HTTP %d", e.URL, e.Status)
+}
+
+func (e *LookupError) Unwrap() error { return e.Err }
+
+// NotFound reports whether the server answered that the resource does not exist.
+func (e *LookupError) NotFound() bool {
+	return e.Status == http.StatusNotFound || e.Status == http.StatusGone
+}
+
+// SkipReason classifies the error for async run reporting.
+func (e *LookupError) SkipReason() async.SkipReason {
+	switch {
+	case e.NotFound():
+		return async.SkipNotFound
+	case e.Status == http.StatusUnauthorized, e.Status == http.StatusForbidden:
+		return async.SkipAuth
+	}
+	return async.SkipNetwork
+}
@@ -0,0 +1,29 @@
+package httpfetch
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/wharflab/tally/internal/async"
+)
+
+func TestLookupError_SkipReason(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		err  *LookupError
+		want async.SkipReason
+	}{
+		{&LookupError{Status: http.StatusNotFound}, async.SkipNotFound},
+		{&LookupError{Status: http.StatusGone}, async.SkipNotFound},
+		{&LookupError{Status: http.StatusUnauthorized}, async.SkipAuth},
+		{&LookupError{Status: http.StatusForbidden}, async.SkipAuth},
+		{&LookupError{Status: http.StatusInternalServerError}, async.SkipNetwork},
+		{&LookupError{Err: errors.New("connection refused")}, async.SkipNetwork},
+	} {
+		if got := tt.err.SkipReason(); got != tt.want {
+			t.Errorf("%v: skip reason = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Package httpfetch provides the HTTP plumbing shared by network-backed async
+// resolvers and remote config downloads: a transport that retries transient
+// failures with backoff, limits concurrent requests per host, and remembers
+// hosts that failed to resolve, plus the error type their failures report.
+package httpfetch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults for Transport fields left at zero.
+const (
+	DefaultMaxRetries    = 2
+	DefaultBaseDelay     = 500 * time.Millisecond
+	DefaultMaxDelay      = 10 * time.Second
+	DefaultPerHost       = 4
+	DefaultDNSFailureTTL = time.Minute
+)
+
+// DefaultTransport is shared by the clients returned from NewClient, so that
+// per-host limits and DNS failures apply across all resolvers of a process.
+var DefaultTransport = &Transport{}
+
+// NewClient returns an HTTP client with the given overall timeout that sends
+// requests through DefaultTransport. The timeout covers retries.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: DefaultTransport}
+}
+
+// Transport is an http.RoundTripper for slow checks. It wraps Base with:
+//
+//   - retries with exponential backoff and jitter for connection errors and
+//     429/502/503/504 responses, honoring Retry-After; only idempotent
+//     requests are retried (see IsIdempotent)
+//   - a limit of PerHost requests in flight per host, held until the
+//     response body is closed
+//   - a cache of hosts whose name did not resolve, so that requests to them
+//     fail fast for DNSFailureTTL instead of each waiting on the resolver
+//
+// The zero value is ready to use.
+type Transport struct {
+	// Base performs the requests. Nil means http.DefaultTransport.
+	Base http.RoundTripper
+
+	// MaxRetries is the number of retries after the first attempt.
+	// Zero means DefaultMaxRetries; negative disables retries.
+	MaxRetries int
+
+	// BaseDelay is the backoff before the first retry; it doubles for each
+	// further retry. Zero means DefaultBaseDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff and Retry-After waits. Zero means DefaultMaxDelay.
+	MaxDelay time.Duration
+
+	// PerHost is the number of concurrent requests per host. Zero means DefaultPerHost.
+	PerHost int
+
+	// DNSFailureTTL is how long a failed host lookup is remembered.
+	// Zero means DefaultDNSFailureTTL; negative disables the cache.
+	DNSFailureTTL time.Duration
+
+	mu          sync.Mutex
+	hosts       map[string]chan struct{}
+	dnsFailures map[string]dnsFailure
+
+	// now and sleep allow tests to control time.
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+type dnsFailure struct {
+	err     error
+	expires time.Time
+}
+
+// DNSFailureError reports a request that was not sent because the host
+// recently failed to resolve.
+type DNSFailureError struct {
+	Host string
+	Err  error
+}
+
+func (e *DNSFailureError) Error() string {
+	return "lookup " + e.Host + " failed recently: " + e.Err.Error()
+}
+
+func (e *DNSFailureError) Unwrap() error { return e.Err }
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := req.URL.Hostname()
+	if err := t.cachedDNSFailure(host); err != nil {
+		return nil, err
+	}
+
+	release, err := t.acquire(ctx, req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	retries := t.maxRetries()
+	if !IsIdempotent(req) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base().RoundTrip(req)
+		if err != nil {
+			if dnsErr, ok := errors.AsType[*net.DNSError](err); ok && dnsErr.IsNotFound {
+				t.rememberDNSFailure(host, err)
+				release()
+				return nil, err
+			}
+		}
+		if attempt >= retries || !retryable(resp, err) || ctx.Err() != nil {
+			if err != nil {
+				release()
+				return nil, err
+			}
+			resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+			return resp, nil
+		}
+
+		delay := t.backoff(attempt, resp)
+		if resp != nil {
+			// Drain a little so the connection can be reused.
+			_, _ = io.CopyN(io.Discard, resp.Body, 4<<10)
+			resp.Body.Close()
+		}
+		if err := t.wait(ctx, delay); err != nil {
+			release()
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				release()
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// IsIdempotent reports whether a request may be retried: its method is
+// idempotent, or it carries an Idempotency-Key or X-Idempotency-Key header.
+// As with net/http, a header entry with a nil value marks the request
+// without sending the header, which suits read-only POST queries.
+func IsIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	_, ok := req.Header["X-Idempotency-Key"]
+	return ok
+}
+
+// retryable reports whether a failed attempt is worth retrying.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before retry attempt+1: the server's Retry-After
+// when given, otherwise exponential backoff with up to 50% jitter.
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	maxDelay := t.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+	if resp != nil {
+		if d, ok := t.retryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(d, maxDelay)
+		}
+	}
+	base := t.BaseDelay
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	d := base << attempt
+	d += rand.N(d/2 + 1) //nolint:gosec // Jitter does not need a secure source.
+	return min(d, maxDelay)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func (t *Transport) retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(t.clock()), 0), true
+	}
+	return 0, false
+}
+
+func (t *Transport) wait(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// acquire takes a per-host slot, returning the function that frees it.
+func (t *Transport) acquire(ctx context.Context, host string) (func(), error) {
+	t.mu.Lock()
+	if t.hosts == nil {
+		t.hosts = make(map[string]chan struct{})
+	}
+	slots, ok := t.hosts[host]
+	if !ok {
+		perHost := t.PerHost
+		if perHost <= 0 {
+			perHost = DefaultPerHost
+		}
+		slots = make(chan struct{}, perHost)
+		t.hosts[host] = slots
+	}
+	t.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-slots }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (t *Transport) cachedDNSFailure(host string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failure, ok := t.dnsFailures[host]
+	if !ok {
+		return nil
+	}
+	if t.clock().After(failure.expires) {
+		delete(t.dnsFailures, host)
+		return nil
+	}
+	return &DNSFailureError{Host: host, Err: failure.err}
+}
+
+func (t *Transport) rememberDNSFailure(host string, err error) {
+	ttl := t.DNSFailureTTL
+	if ttl < 0 {
+		return
+	}
+	if ttl == 0 {
+		ttl = DefaultDNSFailureTTL
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.dnsFailures == nil {
+		t.dnsFailures = make(map[string]dnsFailure)
+	}
+	t.dnsFailures[host] = dnsFailure{err: err, expires: t.clock().Add(ttl)}
+}
+
+func (t *Transport) maxRetries() int {
+	switch {
+	case t.MaxRetries < 0:
+		return 0
+	case t.MaxRetries == 0:
+		return DefaultMaxRetries
+	}
+	return t.MaxRetries
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// releasingBody frees the per-host slot when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,224 @@
+package httpfetch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noSleep records backoff waits instead of sleeping.
+type noSleep struct {
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (s *noSleep) sleep(_ context.Context, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.waits = append(s.waits, d)
+	return nil
+}
+
+func TestTransport_RetriesTransientStatuses(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = io.WriteString(w, "ok")
+		}
+	}))
+	defer srv.Close()
+
+	s := &noSleep{}
+	tr := &Transport{Base: srv.Client().Transport, BaseDelay: time.Second, sleep: s.sleep}
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("got %d %q, want 200 \"ok\"", resp.StatusCode, body)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server saw %d requests, want 3", got)
+	}
+	if len(s.waits) != 2 {
+		t.Fatalf("waited %d times, want 2", len(s.waits))
+	}
+	if s.waits[0] != 3*time.Second {
+		t.Errorf("first wait = %v, want Retry-After of 3s", s.waits[0])
+	}
+	if s.waits[1] < 2*time.Second || s.waits[1] > 3*time.Second {
+		t.Errorf("second wait = %v, want exponential backoff in [2s, 3s]", s.waits[1])
+	}
+}
+
+func TestTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	s := &noSleep{}
+	tr := &Transport{Base: srv.Client().Transport, MaxRetries: 1, sleep: s.sleep}
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}
+
+func TestTransport_RetriesOnlyIdempotentRequests(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	var bodies []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	s := &noSleep{}
+	client := &http.Client{Transport: &Transport{Base: srv.Client().Transport, sleep: s.sleep}}
+
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("plain POST status = %d, want 503 without retry", resp.StatusCode)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"b":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header["Idempotency-Key"] = nil
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("idempotent POST status = %d, want 200 after retry", resp.StatusCode)
+	}
+
+	want := []string{`{"a":1}`, `{"b":2}`, `{"b":2}`}
+	if strings.Join(bodies, " ") != strings.Join(want, " ") {
+		t.Errorf("server saw bodies %q, want %q", bodies, want)
+	}
+}
+
+func TestTransport_LimitsConcurrencyPerHost(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{Base: srv.Client().Transport, PerHost: 2}}
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		})
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", got)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestTransport_CachesDNSFailures(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := &Transport{
+		Base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			calls.Add(1)
+			return nil, &net.DNSError{Err: "no such host", Name: "nowhere.invalid", IsNotFound: true}
+		}),
+		DNSFailureTTL: time.Minute,
+		now:           func() time.Time { return now },
+	}
+	client := &http.Client{Transport: tr}
+
+	for range 3 {
+		if _, err := client.Get("https://nowhere.invalid/a"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("base transport called %d times, want 1 (no retries, then cached)", got)
+	}
+
+	_, err := client.Get("https://nowhere.invalid/b")
+	if _, ok := errors.AsType[*DNSFailureError](err); !ok {
+		t.Errorf("err = %v, want a *DNSFailureError", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	_, _ = client.Get("https://nowhere.invalid/c")
+	if got := calls.Load(); got != 2 {
+		t.Errorf("base transport called %d times after the TTL, want 2", got)
+	}
+}
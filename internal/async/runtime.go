@@ -3,6 +3,7 @@ package async
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"sync"
 	"time"
 )
@@ -20,6 +21,23 @@ type Runtime struct {
 	// This allows isolated resolver sets per invocation (useful for testing
 	// and for running multiple check sessions concurrently).
 	Resolvers map[string]Resolver
+
+	// Progress, when non-nil, is called as resolutions start and finish so
+	// that callers can report progress. Calls are serialized.
+	Progress func(Progress)
+}
+
+// Progress describes the state of a Run after a resolution starts or finishes.
+type Progress struct {
+	// Resolved is the number of unique resolutions that have finished,
+	// successfully or not.
+	Resolved int
+
+	// Total is the number of unique resolutions in the run.
+	Total int
+
+	// Current is the most recently started resolution.
+	Current ResolutionKey
 }
 
 // dedupeKey identifies a unique resolution unit.
@@ -72,9 +90,25 @@ func (rt *Runtime) Run(ctx context.Context, requests []CheckRequest) *RunResult
 		allSkipped    []Skipped
 		allCompleted  []CompletedCheck
 		allResolved   = make(map[ResolutionKey]any)
+		allTimings    []ResolutionTiming
 		resultMu      sync.Mutex
 	)
 
+	// Progress reporting, serialized by progressMu.
+	var (
+		progress   = Progress{Total: len(orderedKeys)}
+		progressMu sync.Mutex
+	)
+	report := func(update func(*Progress)) {
+		if rt.Progress == nil {
+			return
+		}
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		update(&progress)
+		rt.Progress(progress)
+	}
+
 	// Semaphore channel for concurrency limiting.
 	sem := make(chan struct{}, concurrency)
 
@@ -85,12 +119,15 @@ func (rt *Runtime) Run(ctx context.Context, requests []CheckRequest) *RunResult
 		wg.Add(1)
 		go func(dk dedupeKey, group *pendingGroup) {
 			defer wg.Done()
+			defer report(func(p *Progress) { p.Resolved++ })
 
 			// Acquire semaphore (respects context cancellation).
 			select {
 			case sem <- struct{}{}:
 				defer func() { <-sem }()
 			case <-ctx.Done():
+				slog.DebugContext(ctx, "async check skipped",
+					"resolver", dk.resolverID, "key", dk.key, "error", ctx.Err())
 				resultMu.Lock()
 				for _, req := range group.requests {
 					allSkipped = append(allSkipped, Skipped{
@@ -112,10 +149,26 @@ func (rt *Runtime) Run(ctx context.Context, requests []CheckRequest) *RunResult
 			if hasCached {
 				result = cached
 			} else {
+				report(func(p *Progress) {
+					p.Current = ResolutionKey{ResolverID: dk.resolverID, Key: dk.key}
+				})
+				start := time.Now()
 				result = rt.resolve(ctx, group.request)
+				elapsed := time.Since(start)
+				slog.DebugContext(ctx, "async check resolved",
+					"resolver", dk.resolverID, "key", dk.key, "requests", len(group.requests),
+					"duration", elapsed, "error", result.err)
 				cacheMu.Lock()
 				cache[dk] = result
 				cacheMu.Unlock()
+
+				resultMu.Lock()
+				allTimings = append(allTimings, ResolutionTiming{
+					ResolutionKey: ResolutionKey{ResolverID: dk.resolverID, Key: dk.key},
+					Duration:      elapsed,
+					Err:           result.err,
+				})
+				resultMu.Unlock()
 			}
 
 			// Process result.
@@ -147,11 +200,16 @@ func (rt *Runtime) Run(ctx context.Context, requests []CheckRequest) *RunResult
 
 	wg.Wait()
 
+	slog.InfoContext(ctx, "async checks finished",
+		"resolutions", len(orderedKeys), "requests", len(requests),
+		"completed", len(allCompleted), "skipped", len(allSkipped))
+
 	return &RunResult{
 		Violations: allViolations,
 		Skipped:    allSkipped,
 		Completed:  allCompleted,
 		Resolved:   allResolved,
+		Timings:    allTimings,
 	}
 }
 
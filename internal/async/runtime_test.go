@@ -182,6 +182,10 @@ func TestRuntime_Deduplication(t *testing.T) {
 	} else if got != "resolved" {
 		t.Errorf("expected resolved in resolved map, got %v", got)
 	}
+	// Deduplicated requests share one timing entry.
+	if len(result.Timings) != 1 || result.Timings[0].ResolutionKey != (ResolutionKey{ResolverID: "test", Key: "same-key"}) {
+		t.Errorf("expected 1 timing entry for (test, same-key), got %+v", result.Timings)
+	}
 }
 
 func TestRuntime_DifferentKeys(t *testing.T) {
@@ -382,6 +386,9 @@ func TestRuntime_ResolverError_SkipsAllHandlers(t *testing.T) {
 			t.Errorf("expected skip reason %q, got %q", SkipNetwork, s.Reason)
 		}
 	}
+	if len(result.Timings) != 1 || result.Timings[0].Err == nil {
+		t.Errorf("expected 1 timing entry carrying the resolver error, got %+v", result.Timings)
+	}
 }
 
 func TestRuntime_UnknownResolver(t *testing.T) {
@@ -482,6 +489,53 @@ func TestRuntime_DefaultConcurrency(t *testing.T) {
 	}
 }
 
+func TestRuntime_Progress(t *testing.T) {
+	t.Parallel()
+	resolver := &mockResolver{
+		id: "test",
+		fn: func(_ context.Context, data any) (any, error) {
+			if data == "bad" {
+				return nil, errors.New("boom")
+			}
+			return "ok", nil
+		},
+	}
+	rt := newTestRuntime(resolver, 1, 5*time.Second)
+
+	var events []Progress
+	rt.Progress = func(p Progress) { events = append(events, p) }
+
+	requests := []CheckRequest{
+		{RuleCode: "rule-a", Key: "key-1", ResolverID: "test", Data: "data", Handler: &mockHandler{}},
+		{RuleCode: "rule-b", Key: "key-1", ResolverID: "test", Data: "data", Handler: &mockHandler{}},
+		{RuleCode: "rule-a", Key: "key-2", ResolverID: "test", Data: "bad", Handler: &mockHandler{}},
+	}
+	rt.Run(context.Background(), requests)
+
+	// One start and one finish event per unique key.
+	if len(events) != 4 {
+		t.Fatalf("expected 4 progress events, got %d: %+v", len(events), events)
+	}
+	for _, e := range events {
+		if e.Total != 2 {
+			t.Errorf("expected total 2, got %d", e.Total)
+		}
+	}
+	last := events[len(events)-1]
+	if last.Resolved != 2 {
+		t.Errorf("expected 2 resolved at the end, got %d", last.Resolved)
+	}
+	started := map[string]bool{}
+	for _, e := range events {
+		if e.Current.Key != "" {
+			started[e.Current.Key] = true
+		}
+	}
+	if !started["key-1"] || !started["key-2"] {
+		t.Errorf("expected both keys reported as current, got %v", started)
+	}
+}
+
 func TestRuntime_FallsBackToGlobalRegistry(t *testing.T) {
 	t.Parallel()
 	resolver := &mockResolver{
@@ -104,6 +104,21 @@ type RunResult struct {
 	// This allows callers (e.g. AI AutoFix) to reuse resolved metadata even when
 	// handlers emit zero violations.
 	Resolved map[ResolutionKey]any
+
+	// Timings records how long each resolver call took, in completion order.
+	// Deduplicated requests share a single entry.
+	Timings []ResolutionTiming
+}
+
+// ResolutionTiming records the duration of one resolver call.
+type ResolutionTiming struct {
+	ResolutionKey
+
+	// Duration is the wall time spent in Resolver.Resolve.
+	Duration time.Duration
+
+	// Err is the resolver error, if any.
+	Err error
 }
 
 // ResolutionKey identifies a unique resolution unit in a runtime session.
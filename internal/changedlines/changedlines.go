@@ -0,0 +1,276 @@
+// Package changedlines computes the set of added or modified lines per file
+// relative to a git revision.
+//
+// It backs diff-aware linting (`--diff-base <ref>`): violations whose location
+// does not touch a changed line are dropped, similar to golangci-lint's
+// new-from-rev mode. Deleted lines have no position in the new file, so only
+// additions are recorded. FromStatus backs `--changed`, which lints only the
+// files git status reports.
+package changedlines
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+)
+
+// LineSet holds the 1-based line numbers that were added or modified in a file.
+// When Whole is set every line of the file is considered changed (new or
+// untracked files).
+type LineSet struct {
+	// Whole marks the entire file as changed.
+	Whole bool
+
+	lines map[int]struct{}
+}
+
+// Add records a changed line.
+func (s *LineSet) Add(line int) {
+	if s.lines == nil {
+		s.lines = make(map[int]struct{})
+	}
+	s.lines[line] = struct{}{}
+}
+
+// Len returns the number of explicitly recorded lines.
+func (s *LineSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.lines)
+}
+
+// Contains reports whether line was changed.
+func (s *LineSet) Contains(line int) bool {
+	if s == nil {
+		return false
+	}
+	if s.Whole {
+		return true
+	}
+	_, ok := s.lines[line]
+	return ok
+}
+
+// Overlaps reports whether any line in the inclusive range [start, end] was changed.
+func (s *LineSet) Overlaps(start, end int) bool {
+	if s == nil {
+		return false
+	}
+	if s.Whole {
+		return true
+	}
+	if end < start {
+		end = start
+	}
+	// Iterate over whichever side is smaller.
+	if end-start+1 <= len(s.lines) {
+		for line := start; line <= end; line++ {
+			if _, ok := s.lines[line]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	for line := range s.lines {
+		if line >= start && line <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// Files maps absolute, cleaned file paths to their changed lines.
+type Files map[string]*LineSet
+
+// Lookup returns the LineSet for path, resolving it to an absolute path first.
+// Returns nil when the file has no recorded changes.
+func (f Files) Lookup(path string) *LineSet {
+	if f == nil {
+		return nil
+	}
+	abs, err := filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return nil
+	}
+	if set, ok := f[abs]; ok {
+		return set
+	}
+	// git reports the repository root with symlinks resolved (e.g. /private/tmp
+	// on macOS), so retry with the resolved path.
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil && resolved != abs {
+		return f[resolved]
+	}
+	return nil
+}
+
+// Parse reads a unified diff (as produced by `git diff`) and returns the
+// changed lines of each post-image file. Paths in the diff are interpreted
+// relative to root.
+func Parse(r io.Reader, root string) (Files, error) {
+	files, _, err := gitdiff.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse diff: %w", err)
+	}
+
+	out := make(Files, len(files))
+	for _, file := range files {
+		if file == nil || file.IsDelete || file.IsBinary || file.NewName == "" {
+			continue
+		}
+		set := &LineSet{Whole: file.IsNew}
+		for _, frag := range file.TextFragments {
+			line := int(frag.NewPosition)
+			for _, l := range frag.Lines {
+				switch l.Op {
+				case gitdiff.OpAdd:
+					set.Add(line)
+					line++
+				case gitdiff.OpContext:
+					line++
+				case gitdiff.OpDelete:
+					// Deleted lines do not exist in the new file.
+				}
+			}
+		}
+		if !set.Whole && set.Len() == 0 {
+			// Pure deletions or mode changes leave nothing to report on.
+			continue
+		}
+		out[filepath.Join(root, filepath.FromSlash(file.NewName))] = set
+	}
+	return out, nil
+}
+
+// FromGit computes changed lines between base and the working tree of the git
+// repository containing dir. Untracked (but not ignored) files are treated as
+// entirely changed.
+func FromGit(ctx context.Context, dir, base string) (Files, error) {
+	if strings.TrimSpace(base) == "" {
+		return nil, errors.New("empty diff base")
+	}
+	if strings.HasPrefix(base, "-") {
+		return nil, fmt.Errorf("invalid diff base %q", base)
+	}
+
+	root, err := repoRoot(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := runGit(ctx, root,
+		"diff", "--no-color", "--no-ext-diff", "--no-renames", "--unified=0", base, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s: %w", base, err)
+	}
+	files, err := Parse(strings.NewReader(diff), root)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := runGit(ctx, root, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("list untracked files: %w", err)
+	}
+	for name := range strings.SplitSeq(untracked, "\x00") {
+		if name == "" {
+			continue
+		}
+		files[filepath.Join(root, filepath.FromSlash(name))] = &LineSet{Whole: true}
+	}
+	return files, nil
+}
+
+// FromStatus returns the files that git status reports as modified, staged
+// or untracked in the repository containing dir, each marked as wholly
+// changed. Files deleted from the working tree are left out.
+func FromStatus(ctx context.Context, dir string) (Files, error) {
+	root, err := repoRoot(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := runGit(ctx, root, "status", "--porcelain=v1", "-z", "--untracked-files=all", "--no-renames")
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	files := make(Files)
+	for entry := range strings.SplitSeq(status, "\x00") {
+		// Each entry is "XY <path>": the index and working tree states.
+		if len(entry) < 4 {
+			continue
+		}
+		xy, name := entry[:2], entry[3:]
+		if xy[1] == 'D' || xy == "D " {
+			continue
+		}
+		files[filepath.Join(root, filepath.FromSlash(name))] = &LineSet{Whole: true}
+	}
+	return files, nil
+}
+
+// FromGitPaths computes changed lines like FromGit for every git repository
+// or worktree containing one of paths, so files from several repositories
+// each get the lines changed in their own repository. A path outside any
+// repository is an error.
+func FromGitPaths(ctx context.Context, paths []string, base string) (Files, error) {
+	files := make(Files)
+	seenDirs := make(map[string]bool)
+	seenRoots := make(map[string]bool)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if seenDirs[dir] {
+			continue
+		}
+		seenDirs[dir] = true
+
+		root, err := repoRoot(ctx, dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if seenRoots[root] {
+			continue
+		}
+		seenRoots[root] = true
+
+		repoFiles, err := FromGit(ctx, root, base)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(files, repoFiles)
+	}
+	return files, nil
+}
+
+// repoRoot returns the top-level directory of the git repository or worktree
+// containing dir.
+func repoRoot(ctx context.Context, dir string) (string, error) {
+	root, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("locate git repository: %w", err)
+	}
+	return filepath.Clean(strings.TrimSpace(root)), nil
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
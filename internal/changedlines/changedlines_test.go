@@ -0,0 +1,289 @@
+package changedlines
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	diff := `diff --git a/Dockerfile b/Dockerfile
+index 1111111..2222222 100644
+--- a/Dockerfile
++++ b/Dockerfile
+@@ -2,0 +3,2 @@ FROM alpine
++RUN apk add curl
++RUN apk add git
+@@ -5 +7 @@ WORKDIR /app
+-CMD ["old"]
++CMD ["new"]
+diff --git a/removed.txt b/removed.txt
+deleted file mode 100644
+index 3333333..0000000
+--- a/removed.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/sub/new.Dockerfile b/sub/new.Dockerfile
+new file mode 100644
+index 0000000..4444444
+--- /dev/null
++++ b/sub/new.Dockerfile
+@@ -0,0 +1 @@
++FROM scratch
+diff --git a/only-deletions b/only-deletions
+index 5555555..6666666 100644
+--- a/only-deletions
++++ b/only-deletions
+@@ -3 +2,0 @@ keep
+-drop
+`
+	root := t.TempDir()
+	files, err := Parse(strings.NewReader(diff), root)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d: %v", len(files), files)
+	}
+
+	df := files[filepath.Join(root, "Dockerfile")]
+	if df == nil {
+		t.Fatal("missing Dockerfile entry")
+	}
+	for _, line := range []int{3, 4, 7} {
+		if !df.Contains(line) {
+			t.Errorf("expected line %d to be changed", line)
+		}
+	}
+	for _, line := range []int{1, 2, 5, 6, 8} {
+		if df.Contains(line) {
+			t.Errorf("expected line %d to be unchanged", line)
+		}
+	}
+
+	added := files[filepath.Join(root, "sub", "new.Dockerfile")]
+	if added == nil || !added.Whole {
+		t.Fatalf("expected new file to be wholly changed, got %+v", added)
+	}
+}
+
+func TestLineSetOverlaps(t *testing.T) {
+	t.Parallel()
+
+	var set LineSet
+	set.Add(10)
+	set.Add(20)
+
+	tests := []struct {
+		start, end int
+		want       bool
+	}{
+		{1, 9, false},
+		{9, 10, true},
+		{11, 19, false},
+		{15, 100, true},
+		{20, 20, true},
+		{21, 21, false},
+		{10, 5, true}, // end before start collapses to start
+	}
+	for _, tt := range tests {
+		if got := set.Overlaps(tt.start, tt.end); got != tt.want {
+			t.Errorf("Overlaps(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+
+	var nilSet *LineSet
+	if nilSet.Overlaps(1, 100) {
+		t.Error("nil set should not overlap")
+	}
+}
+
+func TestFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("Dockerfile", "FROM alpine\nRUN echo one\nRUN echo two\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	write("Dockerfile", "FROM alpine\nRUN echo one\nRUN echo changed\nRUN echo three\n")
+	write("Containerfile", "FROM scratch\n")
+
+	files, err := FromGit(t.Context(), dir, "HEAD")
+	if err != nil {
+		t.Fatalf("FromGit: %v", err)
+	}
+
+	df := files.Lookup(filepath.Join(dir, "Dockerfile"))
+	if df == nil {
+		t.Fatal("expected Dockerfile changes")
+	}
+	if df.Contains(1) || df.Contains(2) {
+		t.Error("unchanged lines reported as changed")
+	}
+	if !df.Contains(3) || !df.Contains(4) {
+		t.Error("changed lines not reported")
+	}
+
+	untracked := files.Lookup(filepath.Join(dir, "Containerfile"))
+	if untracked == nil || !untracked.Whole {
+		t.Errorf("expected untracked file to be wholly changed, got %+v", untracked)
+	}
+
+	if _, err := FromGit(t.Context(), dir, "does-not-exist"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+	if _, err := FromGit(t.Context(), dir, "--output=/tmp/x"); err == nil {
+		t.Error("expected error for option-like ref")
+	}
+}
+
+func TestFromGitPaths(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Parallel()
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	// newRepo commits a Dockerfile, then changes its second line.
+	newRepo := func() string {
+		t.Helper()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "Dockerfile")
+		git(dir, "init", "-q")
+		if err := os.WriteFile(path, []byte("FROM alpine\nRUN echo one\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git(dir, "add", ".")
+		git(dir, "commit", "-q", "-m", "init")
+		if err := os.WriteFile(path, []byte("FROM alpine\nRUN echo changed\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	first, second := newRepo(), newRepo()
+	files, err := FromGitPaths(t.Context(), []string{first, second}, "HEAD")
+	if err != nil {
+		t.Fatalf("FromGitPaths: %v", err)
+	}
+	for _, path := range []string{first, second} {
+		if set := files.Lookup(path); set == nil || !set.Contains(2) || set.Contains(1) {
+			t.Errorf("%s: changed lines = %+v, want line 2", path, set)
+		}
+	}
+
+	outside := filepath.Join(t.TempDir(), "Dockerfile")
+	if _, err := FromGitPaths(t.Context(), []string{first, outside}, "HEAD"); err == nil ||
+		!strings.Contains(err.Error(), outside) {
+		t.Errorf("FromGitPaths() error = %v, want an error naming %s", err, outside)
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Parallel()
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("Dockerfile", "FROM alpine\n")
+	write("unchanged/Dockerfile", "FROM alpine\n")
+	write("staged/Dockerfile", "FROM alpine\n")
+	write("removed/Dockerfile", "FROM alpine\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	write("Dockerfile", "FROM alpine:3.20\n")
+	write("staged/Dockerfile", "FROM alpine:3.20\n")
+	git("add", "staged/Dockerfile")
+	write("new/Containerfile", "FROM scratch\n")
+	if err := os.Remove(filepath.Join(dir, "removed", "Dockerfile")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := FromStatus(t.Context(), filepath.Join(dir, "unchanged"))
+	if err != nil {
+		t.Fatalf("FromStatus: %v", err)
+	}
+	for _, name := range []string{"Dockerfile", "staged/Dockerfile", "new/Containerfile"} {
+		if set := files.Lookup(filepath.Join(dir, name)); set == nil || !set.Whole {
+			t.Errorf("expected %s to be wholly changed, got %+v", name, set)
+		}
+	}
+	for _, name := range []string{"unchanged/Dockerfile", "removed/Dockerfile"} {
+		if set := files.Lookup(filepath.Join(dir, name)); set != nil {
+			t.Errorf("expected %s to be left out, got %+v", name, set)
+		}
+	}
+
+	if _, err := FromStatus(t.Context(), t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
@@ -5,21 +5,21 @@
 //  1. CLI flags
 //  2. Environment variables (TALLY_* prefix)
 //  3. Config file (closest .tally.toml or tally.toml)
-//  4. Built-in defaults
+//  4. Profile selected with the profile key or --profile flag
+//  5. Built-in defaults
 //
 // Config file discovery follows a cascading pattern similar to Ruff:
 // starting from the target file's directory, walk up the filesystem
-// until a config file is found. The closest config wins (no merging).
+// until a config file is found. The closest config wins (no merging); a
+// config file can instead pull in shared configs explicitly with extends.
 package config
 
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
-	"github.com/knadh/koanf/parsers/toml/v2"
-	"github.com/knadh/koanf/providers/env/v2"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
@@ -32,6 +32,15 @@ var ConfigFileNames = []string{".tally.toml", "tally.toml"}
 // EnvPrefix is the prefix for environment variables.
 const EnvPrefix = "TALLY_"
 
+// Dockerfile dialects accepted by the dialect key.
+const (
+	// DialectDocker parses Dockerfiles the way BuildKit does.
+	DialectDocker = "docker"
+	// DialectPodman additionally accepts Podman/Buildah extensions such as
+	// RUN --mount=type=devpts and SELinux relabel mount options.
+	DialectPodman = "podman"
+)
+
 // Config represents the complete tally configuration.
 type Config struct {
 	// Rules contains configuration for individual linting rules.
@@ -50,17 +59,72 @@ type Config struct {
 	// nil means unset, matching Ruff's tri-state unsafe-fixes option.
 	UnsafeFixes *bool `json:"unsafe-fixes,omitempty" koanf:"unsafe-fixes"`
 
+	// FixPrecedence orders rules whose fixes win when two fixes overlap.
+	// Entries are rule codes or namespace wildcards ("hadolint/*"); earlier
+	// entries win over later ones and over unlisted rules.
+	FixPrecedence []string `json:"fix-precedence,omitempty" koanf:"fix-precedence"`
+
+	// DuplicatePrecedence maps a rule code to the rules whose violations it
+	// replaces when both report on the same line. Values are rule codes or
+	// namespace wildcards. An entry replaces the rule's built-in Supersedes
+	// list, so an empty list makes the rule replace none.
+	DuplicatePrecedence map[string][]string `json:"duplicate-precedence,omitempty" koanf:"duplicate-precedence"`
+
+	// SeverityByStageRole overrides rule severities by the role of the stage
+	// a violation is in, e.g. to report secrets as errors only in the stages
+	// that are exported.
+	SeverityByStageRole SeverityByStageRoleConfig `json:"severity-by-stage-role" koanf:"severity-by-stage-role"`
+
 	// FileValidation configures pre-parse file validation checks.
 	FileValidation FileValidationConfig `json:"file-validation" koanf:"file-validation"`
 
 	// SlowChecks configures async checks that require network or other slow I/O.
 	SlowChecks SlowChecksConfig `json:"slow-checks" koanf:"slow-checks"`
 
+	// Registries is the image registry policy shared by rules that check where
+	// base images come from.
+	Registries RegistriesConfig `json:"registries" koanf:"registries"`
+
+	// Extends lists the configs the loaded config file extends, as written.
+	Extends []string `json:"extends,omitempty" koanf:"extends,omitempty"`
+
+	// Profile names the built-in profile layered under this configuration
+	// (see Profiles). omitempty keeps the defaults layer from setting it to an
+	// empty string, which the schema rejects.
+	Profile string `json:"profile,omitempty" koanf:"profile,omitempty"`
+
+	// BuildArgs seeds ARG resolution like docker build --build-arg, so
+	// variable expansion sees the values CI passes. Args declared by a build
+	// invocation (Bake target, Compose service) take precedence.
+	BuildArgs map[string]string `json:"build-args,omitempty" koanf:"build-args"`
+
+	// Dialect selects the Dockerfile flavor to accept: DialectDocker (BuildKit)
+	// or DialectPodman, which also understands Podman/Buildah extensions.
+	Dialect string `json:"dialect,omitempty" koanf:"dialect"`
+
 	// ConfigFile is the path to the config file that was loaded (if any).
 	// This is metadata, not loaded from config.
 	ConfigFile string `json:"-" koanf:"-"`
 }
 
+// SeverityByStageRoleConfig maps rule patterns to severities per stage role.
+// Keys are rule codes, namespace wildcards ("hadolint/*") or "*".
+//
+// Example TOML configuration:
+//
+//	[severity-by-stage-role.final]
+//	"tally/secrets-in-code" = "error"
+//
+//	[severity-by-stage-role.builder]
+//	"tally/secrets-in-code" = "warning"
+type SeverityByStageRoleConfig struct {
+	// Final applies to the exported stage and the stages it is built FROM.
+	Final map[string]string `json:"final,omitempty" koanf:"final"`
+
+	// Builder applies to all other stages.
+	Builder map[string]string `json:"builder,omitempty" koanf:"builder"`
+}
+
 // SlowChecksConfig configures async checks that require potentially slow I/O
 // (registry access, network, filesystem).
 //
@@ -70,6 +134,10 @@ type Config struct {
 //	mode = "auto"
 //	fail-fast = true
 //	timeout = "20s"
+//	cache-ttl = "1h"
+//
+//	[slow-checks.registries."registry.internal:5000"]
+//	insecure = true
 type SlowChecksConfig struct {
 	// Mode controls when slow checks run: auto (CI detection), on, off.
 	Mode string `json:"mode,omitempty" koanf:"mode"`
@@ -79,6 +147,42 @@ type SlowChecksConfig struct {
 
 	// Timeout is the wall-clock budget for all async checks per invocation.
 	Timeout string `json:"timeout,omitempty" koanf:"timeout"`
+
+	// CacheTTL is how long registry lookups are cached on disk ("0" disables).
+	CacheTTL string `json:"cache-ttl,omitempty" koanf:"cache-ttl"`
+
+	// Registries holds per-registry connection settings keyed by registry host.
+	Registries map[string]RegistryConfig `json:"registries,omitempty" koanf:"registries"`
+}
+
+// RegistryConfig configures how slow checks connect to a single registry.
+type RegistryConfig struct {
+	// Insecure skips TLS certificate verification and allows plain HTTP.
+	Insecure bool `json:"insecure,omitempty" koanf:"insecure"`
+
+	// CertsDir is a directory containing ca.crt, client.cert, and client.key
+	// in the /etc/docker/certs.d/<host> layout.
+	CertsDir string `json:"certs-dir,omitempty" koanf:"certs-dir"`
+}
+
+// RegistriesConfig is the image registry policy shared by rules. A rule with
+// a trusted list of its own (e.g. hadolint/DL3026 trusted-registries) uses
+// that list instead of Trusted; Mirrors always apply.
+//
+// Example TOML configuration:
+//
+//	[registries]
+//	trusted = ["docker.io", "*.corp.example.com", "registry.internal:5000"]
+//
+//	[registries.mirrors]
+//	"mirror.gcr.io" = "docker.io"
+type RegistriesConfig struct {
+	// Trusted lists the trusted registries. Entries are hosts with an optional
+	// port, "*", "*.suffix" or "prefix*".
+	Trusted []string `json:"trusted,omitempty" koanf:"trusted"`
+
+	// Mirrors maps mirror registry hosts to the registry they mirror.
+	Mirrors map[string]string `json:"mirrors,omitempty" koanf:"mirrors"`
 }
 
 // FileValidationConfig configures pre-parse file validation checks.
@@ -105,6 +209,20 @@ type OutputConfig struct {
 
 	// FailLevel sets the minimum severity level that causes a non-zero exit code.
 	FailLevel string `json:"fail-level,omitempty" koanf:"fail-level"`
+
+	// GroupBy groups text output by file, rule or severity ("none" disables).
+	GroupBy string `json:"group-by,omitempty" koanf:"group-by"`
+
+	// AnnotationLimit caps github-actions annotations per level (0 = no limit).
+	AnnotationLimit int `json:"annotation-limit,omitempty" koanf:"annotation-limit"`
+
+	// ShowSuppressed also reports suppressed violations, marked with the
+	// inline directive or config setting that suppressed them.
+	ShowSuppressed bool `json:"show-suppressed,omitempty" koanf:"show-suppressed"`
+
+	// ShowFixes renders each violation's suggested fix as an inline diff
+	// in text output.
+	ShowFixes bool `json:"show-fixes,omitempty" koanf:"show-fixes"`
 }
 
 // InlineDirectivesConfig controls inline suppression directives.
@@ -150,19 +268,38 @@ type AIConfig struct {
 
 	// RedactSecrets redacts obvious secrets before sending content to the agent.
 	RedactSecrets bool `json:"redact-secrets,omitempty" koanf:"redact-secrets"`
+
+	// Rules restricts AI AutoFix to these rule codes. Empty allows every rule
+	// that offers an AI fix.
+	Rules []string `json:"rules,omitempty" koanf:"rules"`
+
+	// Prompts maps rule codes to custom prompt templates (text/template syntax).
+	// A template replaces the objective's built-in instructions; tally still
+	// appends the Dockerfile and the output format contract.
+	Prompts map[string]string `json:"prompts,omitempty" koanf:"prompts"`
+}
+
+// AllowsRule reports whether AI AutoFix may resolve fixes for ruleCode.
+func (c AIConfig) AllowsRule(ruleCode string) bool {
+	return len(c.Rules) == 0 || slices.Contains(c.Rules, ruleCode)
 }
 
 // Default returns the default configuration.
 // Rule-specific defaults are owned by each rule via ConfigurableRule.DefaultConfig().
 func Default() *Config {
 	return &Config{
+		Dialect: DialectDocker,
 		Output: OutputConfig{
-			Format:     "text",
-			Path:       "stdout",
-			ShowSource: true,
-			FailLevel:  "style", // Any violation causes exit code 1
+			Format:          "text",
+			Path:            "stdout",
+			ShowSource:      true,
+			FailLevel:       "style", // Any violation causes exit code 1
+			GroupBy:         "none",
+			AnnotationLimit: 10, // GitHub shows at most 10 annotations of each type per step
+		},
+		Rules: RulesConfig{
+			Timeout: "0", // No per-rule budget; everything else defaults in the rules
 		},
-		Rules: RulesConfig{}, // Empty - defaults come from rules
 		InlineDirectives: InlineDirectivesConfig{
 			Enabled:       true,  // Process inline directives by default
 			WarnUnused:    false, // Don't warn about unused directives by default
@@ -182,6 +319,7 @@ func Default() *Config {
 			Mode:     "auto",
 			FailFast: true,
 			Timeout:  "20s",
+			CacheTTL: "1h",
 		},
 	}
 }
@@ -190,7 +328,12 @@ func Default() *Config {
 // It discovers the closest config file, loads it, and applies
 // environment variable overrides.
 func Load(targetPath string) (*Config, error) {
-	return loadWithConfigPath(Discover(targetPath), nil)
+	cfg, err := loadWithConfigPath(Discover(targetPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ScopeToFile(targetPath)
+	return cfg, nil
 }
 
 // FlagKeyMapper maps a pflag.Flag to a canonical koanf key and value. Returning
@@ -204,7 +347,12 @@ type FlagKeyMapper func(f *pflag.Flag) (string, any)
 // already produced a value, which matches the precedence documented at the top
 // of this file.
 func LoadWithFlags(targetPath string, flags *pflag.FlagSet, mapper FlagKeyMapper) (*Config, error) {
-	return loadWithConfigPath(Discover(targetPath), flagLayer(flags, mapper))
+	cfg, err := loadWithConfigPath(Discover(targetPath), flagLayer(flags, mapper))
+	if err != nil {
+		return nil, err
+	}
+	cfg.ScopeToFile(targetPath)
+	return cfg, nil
 }
 
 // LoadFromFileWithFlags is LoadFromFile + posflag layering.
@@ -233,50 +381,78 @@ func flagLayer(flags *pflag.FlagSet, mapper FlagKeyMapper) *flagProvider {
 // loadWithConfigPath is an internal helper that loads config with an optional
 // config file path and an optional CLI-flag layer applied last.
 func loadWithConfigPath(configPath string, flags *flagProvider) (*Config, error) {
-	k := koanf.New(".")
-
-	// 1. Load defaults
-	if err := k.Load(structs.Provider(Default(), "koanf"), nil); err != nil {
-		return nil, err
-	}
+	return loadLayered(configPath, func(k *koanf.Koanf) error {
+		// Config file, then environment variables (TALLY_* prefix):
+		// TALLY_RULES_MAX_LINES_MAX -> rules.max-lines.max
+		if err := loadConfigFile(k, configPath); err != nil {
+			return err
+		}
+		if err := loadEnv(k); err != nil {
+			return err
+		}
 
-	// 2. Load config file if provided
-	if configPath != "" {
-		if err := k.Load(file.Provider(configPath), toml.Parser()); err != nil {
-			return nil, err
+		// Layer CLI flags last — posflag respects `Changed` so defaults only
+		// fill in values that no earlier provider produced.
+		if flags != nil {
+			provider := posflag.ProviderWithFlag(flags.flags, ".", k, func(f *pflag.Flag) (string, any) {
+				return flags.mapper(f)
+			})
+			if err := k.Load(provider, nil); err != nil {
+				return err
+			}
 		}
-	}
+		return nil
+	})
+}
 
-	// 3. Load environment variables (TALLY_* prefix)
-	// TALLY_RULES_MAX_LINES_MAX -> rules.max-lines.max
-	if err := k.Load(env.Provider(".", env.Opt{
-		Prefix:        EnvPrefix,
-		TransformFunc: envKeyTransform,
-	}), nil); err != nil {
+// loadLayered loads the built-in defaults followed by sources, then decodes
+// the merged result. When the merged sources select a profile, everything is
+// loaded again with the profile between the defaults and sources.
+func loadLayered(configPath string, sources func(k *koanf.Koanf) error) (*Config, error) {
+	k, _, err := loadSources("", sources)
+	if err != nil {
 		return nil, err
 	}
 
-	// 4. Layer CLI flags last — posflag respects `Changed` so defaults only
-	//    fill in values that no earlier provider produced.
-	if flags != nil {
-		provider := posflag.ProviderWithFlag(flags.flags, ".", k, func(f *pflag.Flag) (string, any) {
-			return flags.mapper(f)
-		})
-		if err := k.Load(provider, nil); err != nil {
+	var profile *profileRules
+	if name := k.String("profile"); name != "" {
+		k, profile, err = loadSources(name, sources)
+		if err != nil {
 			return nil, err
 		}
 	}
 
-	// 5. Validate merged raw config and decode.
+	// Validate merged raw config and decode.
 	cfg, err := decodeConfig(k.Raw())
 	if err != nil {
 		return nil, err
 	}
+	profile.apply(&cfg.Rules)
 
 	cfg.ConfigFile = configPath
 	return cfg, nil
 }
 
+func loadSources(profile string, sources func(k *koanf.Koanf) error) (*koanf.Koanf, *profileRules, error) {
+	k := koanf.New(".")
+	if err := k.Load(structs.Provider(Default(), "koanf"), nil); err != nil {
+		return nil, nil, err
+	}
+
+	var selected *profileRules
+	if profile != "" {
+		var err error
+		if selected, err = loadProfile(k, profile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := sources(k); err != nil {
+		return nil, nil, err
+	}
+	return k, selected, nil
+}
+
 // knownHyphenatedKeys maps dot-separated patterns to their hyphenated equivalents.
 // Add new entries here when adding rules with hyphenated names.
 var knownHyphenatedKeys = map[string]string{
@@ -289,10 +465,15 @@ var knownHyphenatedKeys = map[string]string{
 	"require.reason":               "require-reason",
 	"show.source":                  "show-source",
 	"fail.level":                   "fail-level",
+	"group.by":                     "group-by",
+	"annotation.limit":             "annotation-limit",
+	"show.suppressed":              "show-suppressed",
+	"show.fixes":                   "show-fixes",
 	"max.input.bytes":              "max-input-bytes",
 	"redact.secrets":               "redact-secrets",
 	"slow.checks":                  "slow-checks",
 	"fail.fast":                    "fail-fast",
+	"cache.ttl":                    "cache-ttl",
 	"unsafe.fixes":                 "unsafe-fixes",
 	"newline.between.instructions": "newline-between-instructions",
 	"file.validation":              "file-validation",
@@ -307,6 +488,8 @@ var allowedEnvTopLevelKeys = map[string]struct{}{
 	"unsafe-fixes":      {},
 	"slow-checks":       {},
 	"file-validation":   {},
+	"profile":           {},
+	"dialect":           {},
 	// Compatibility aliases normalized in normalizeOutputAliases.
 	"format":      {},
 	"path":        {},
@@ -342,6 +525,30 @@ func envKeyTransform(k, v string) (string, any) {
 	return s, v
 }
 
+// ScopeToFile resolves per-rule paths and exclude-paths for the Dockerfile
+// at targetPath: rules whose patterns do not admit it are disabled (see
+// RulesConfig.InScope). Patterns are relative to the config file's
+// directory, or to the working directory when no config file was loaded.
+func (c *Config) ScopeToFile(targetPath string) {
+	if c == nil {
+		return
+	}
+	base := "."
+	if c.ConfigFile != "" {
+		base = filepath.Dir(c.ConfigFile)
+	}
+	rel := targetPath
+	if absBase, err := filepath.Abs(base); err == nil {
+		if absTarget, err := filepath.Abs(targetPath); err == nil {
+			if r, err := filepath.Rel(absBase, absTarget); err == nil {
+				rel = r
+			}
+		}
+	}
+	c.Rules.scopePath = filepath.ToSlash(rel)
+	c.Rules.scoped = true
+}
+
 // Discover finds the closest config file for a target file path.
 // It walks up the directory tree from the target's directory,
 // checking for config files at each level.
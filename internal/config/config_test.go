@@ -1,9 +1,11 @@
 package config
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -170,12 +172,20 @@ func TestConfigFromSchema_CoversAllFields(t *testing.T) {
 
 	// Authoritative list of fields handled by configFromSchema.
 	handled := map[string]bool{
-		"Output":           true,
-		"InlineDirectives": true,
-		"AI":               true,
-		"UnsafeFixes":      true,
-		"FileValidation":   true,
-		"SlowChecks":       true,
+		"Output":              true,
+		"InlineDirectives":    true,
+		"AI":                  true,
+		"UnsafeFixes":         true,
+		"FixPrecedence":       true,
+		"DuplicatePrecedence": true,
+		"FileValidation":      true,
+		"SlowChecks":          true,
+		"Profile":             true,
+		"Extends":             true,
+		"Dialect":             true,
+		"BuildArgs":           true,
+		"SeverityByStageRole": true,
+		"Registries":          true,
 	}
 
 	// Forward: every struct field must be handled.
@@ -265,6 +275,10 @@ timeout = "45s"
 max-input-bytes = 1234
 redact-secrets = false
 command = ["fake-agent", "--acp"]
+rules = ["tally/prefer-multi-stage-build"]
+
+[ai.prompts]
+"tally/prefer-multi-stage-build" = "Split {{.File}} into stages"
 `
 	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
 		t.Fatal(err)
@@ -275,6 +289,12 @@ command = ["fake-agent", "--acp"]
 		t.Fatalf("Load() error = %v", err)
 	}
 
+	if !cfg.AI.AllowsRule("tally/prefer-multi-stage-build") || cfg.AI.AllowsRule("hadolint/DL4001") {
+		t.Errorf("AI.Rules = %v, want only tally/prefer-multi-stage-build allowed", cfg.AI.Rules)
+	}
+	if got := cfg.AI.Prompts["tally/prefer-multi-stage-build"]; got != "Split {{.File}} into stages" {
+		t.Errorf("AI.Prompts = %v", cfg.AI.Prompts)
+	}
 	if !cfg.AI.Enabled {
 		t.Error("AI.Enabled should be true")
 	}
@@ -292,6 +312,36 @@ command = ["fake-agent", "--acp"]
 	}
 }
 
+func TestLoad_SlowChecksRegistries(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configPath := filepath.Join(tmpDir, ".tally.toml")
+	configContent := `
+[slow-checks.registries."registry.internal:5000"]
+insecure = true
+
+[slow-checks.registries."ghcr.example.com"]
+certs-dir = "/etc/docker/certs.d/ghcr.example.com"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string]RegistryConfig{
+		"registry.internal:5000": {Insecure: true},
+		"ghcr.example.com":       {CertsDir: "/etc/docker/certs.d/ghcr.example.com"},
+	}
+	if !maps.Equal(cfg.SlowChecks.Registries, want) {
+		t.Errorf("SlowChecks.Registries = %v, want %v", cfg.SlowChecks.Registries, want)
+	}
+}
+
 func TestLoad_RuleIncludeExclude(t *testing.T) {
 	t.Parallel()
 	tmpDir, dockerfilePath := setupTempProject(t)
@@ -334,6 +384,464 @@ max = 100
 	}
 }
 
+func TestLoad_RuleTimeout(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[rules]
+timeout = "200ms"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Rules.Timeout != "200ms" {
+		t.Errorf("Rules.Timeout = %q, want %q", cfg.Rules.Timeout, "200ms")
+	}
+	// rules.timeout is a rules-level key, not a legacy tally rule table.
+	if got := cfg.Rules.Get("tally/timeout"); got != nil {
+		t.Errorf("rules.timeout was folded into rules.tally: %+v", got)
+	}
+}
+
+func TestLoad_Dialect(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Dialect != DialectDocker {
+		t.Errorf("default Dialect = %q, want %q", cfg.Dialect, DialectDocker)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte("dialect = \"podman\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Dialect != DialectPodman {
+		t.Errorf("Dialect = %q, want %q", cfg.Dialect, DialectPodman)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte("dialect = \"buildah\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dockerfilePath); err == nil {
+		t.Error("Load() accepted an unknown dialect")
+	}
+}
+
+func TestLoad_BuildArgs(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[build-args]
+BASE_IMAGE = "alpine:3.20"
+http_proxy = ""
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	// ARG names are case-sensitive and kept as written.
+	want := map[string]string{"BASE_IMAGE": "alpine:3.20", "http_proxy": ""}
+	if !maps.Equal(cfg.BuildArgs, want) {
+		t.Errorf("BuildArgs = %v, want %v", cfg.BuildArgs, want)
+	}
+}
+
+func TestLoad_Registries(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	content := `[registries]
+trusted = ["docker.io", "*.corp.example.com", "registry.internal:5000"]
+
+[registries.mirrors]
+"mirror.gcr.io" = "docker.io"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	wantTrusted := []string{"docker.io", "*.corp.example.com", "registry.internal:5000"}
+	if !slices.Equal(cfg.Registries.Trusted, wantTrusted) {
+		t.Errorf("Registries.Trusted = %v, want %v", cfg.Registries.Trusted, wantTrusted)
+	}
+	if got := cfg.Registries.Mirrors["mirror.gcr.io"]; got != "docker.io" {
+		t.Errorf("Registries.Mirrors[mirror.gcr.io] = %q, want docker.io", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte("[registries]\nallowed = [\"docker.io\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dockerfilePath); err == nil {
+		t.Error("Load() accepted an unknown registries key")
+	}
+}
+
+func TestLoad_FixPrecedence(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `fix-precedence = ["tally/prefer-package-cache-mounts", "hadolint/*"]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := map[string]int{
+		"tally/prefer-package-cache-mounts": 2,
+		"hadolint/DL3027":                   1,
+		"tally/consistent-indentation":      0,
+	}
+	for rule, want := range tests {
+		if got := FixPrecedenceRank(cfg.FixPrecedence, rule); got != want {
+			t.Errorf("FixPrecedenceRank(%q) = %d, want %d", rule, got, want)
+		}
+	}
+}
+
+func TestLoad_DuplicatePrecedence(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[duplicate-precedence]
+"hadolint/DL3020" = ["tally/*"]
+"tally/mount-secret-instead-of-copy" = []
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	builtin := []string{"hadolint/DL3020"}
+	tests := []struct {
+		ruleCode, other string
+		builtin         []string
+		want            bool
+	}{
+		{"hadolint/DL3020", "tally/mount-secret-instead-of-copy", nil, true},
+		{"tally/mount-secret-instead-of-copy", "hadolint/DL3020", builtin, false},
+		{"tally/prefer-add-unpack", "hadolint/DL3047", []string{"hadolint/DL3047"}, true},
+		{"tally/prefer-add-unpack", "hadolint/DL3020", []string{"hadolint/DL3047"}, false},
+	}
+	for _, tt := range tests {
+		if got := Supersedes(cfg.DuplicatePrecedence, tt.builtin, tt.ruleCode, tt.other); got != tt.want {
+			t.Errorf("Supersedes(%q, %q) = %v, want %v", tt.ruleCode, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_SeverityByStageRole(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[severity-by-stage-role.final]
+"tally/secrets-in-code" = "error"
+
+[severity-by-stage-role.builder]
+"tally/secrets-in-code" = "warning"
+"hadolint/*" = "info"
+"*" = "off"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		role, rule, want string
+	}{
+		{"final", "tally/secrets-in-code", "error"},
+		{"final", "hadolint/DL3008", ""},
+		{"builder", "tally/secrets-in-code", "warning"},
+		{"builder", "hadolint/DL3008", "info"},
+		{"builder", "tally/max-lines", "off"},
+		{"unknown", "tally/secrets-in-code", ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.SeverityByStageRole.Severity(tt.role, tt.rule); got != tt.want {
+			t.Errorf("Severity(%q, %q) = %q, want %q", tt.role, tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_SeverityByStageRoleRejectsUnknownSeverity(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[severity-by-stage-role.final]
+"tally/secrets-in-code" = "fatal"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(dockerfilePath); err == nil {
+		t.Error("Load() accepted an unknown severity")
+	}
+}
+
+func TestLoad_RulePathScoping(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	configContent := `[rules]
+include = ["tally/*"]
+
+[rules.tally.max-lines]
+severity = "error"
+max = 50
+paths = ["services/api/**"]
+exclude-paths = ["services/api/legacy/**"]
+
+[rules.hadolint.DL3008]
+exclude-paths = ["legacy/**"]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file             string
+		maxLines, dl3008 bool
+	}{
+		{"services/api/Dockerfile", true, true},
+		{"services/api/legacy/Dockerfile", false, true},
+		{"services/web/Dockerfile", false, true},
+		{"legacy/Dockerfile", false, false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(tmpDir, filepath.FromSlash(tt.file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", tt.file, err)
+		}
+		if got := cfg.Rules.IsEnabled("tally/max-lines"); (got == nil || *got) != tt.maxLines {
+			t.Errorf("%s: tally/max-lines enabled = %v, want %v", tt.file, got, tt.maxLines)
+		}
+		if got := cfg.Rules.IsEnabled("hadolint/DL3008"); (got == nil || *got) != tt.dl3008 {
+			t.Errorf("%s: hadolint/DL3008 enabled = %v, want %v", tt.file, got, tt.dl3008)
+		}
+		if opts := cfg.Rules.GetOptions("tally/max-lines"); len(opts) != 1 || opts["max"] == nil {
+			t.Errorf("%s: paths leaked into rule options: %v", tt.file, opts)
+		}
+	}
+}
+
+func TestLoad_RuleFixSafety(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[rules.hadolint.DL3003]
+fix-safety = "safe"
+
+[rules.tally.max-lines]
+fix-safety = "unsafe"
+max = 50
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Rules.GetFixSafety("hadolint/DL3003"); got != "safe" {
+		t.Errorf("DL3003 fix-safety = %q, want safe", got)
+	}
+	if got := cfg.Rules.GetFixSafety("tally/max-lines"); got != "unsafe" {
+		t.Errorf("max-lines fix-safety = %q, want unsafe", got)
+	}
+	if got := cfg.Rules.GetFixSafety("tally/eol-last"); got != "" {
+		t.Errorf("eol-last fix-safety = %q, want empty", got)
+	}
+	if opts := cfg.Rules.GetOptions("tally/max-lines"); len(opts) != 1 || opts["max"] == nil {
+		t.Errorf("fix-safety leaked into rule options: %v", opts)
+	}
+}
+
+func TestLoad_RulePathScopingRejectsInvalidPattern(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configContent := `[rules.tally.max-lines]
+paths = ["services/[api"]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".tally.toml"), []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(dockerfilePath); err == nil || !strings.Contains(err.Error(), "invalid path pattern") {
+		t.Errorf("Load() error = %v, want invalid path pattern", err)
+	}
+}
+
+func TestLoad_Profile(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configPath := filepath.Join(tmpDir, ".tally.toml")
+	configContent := `
+profile = "strict"
+
+[inline-directives]
+require-reason = false
+
+[rules.hadolint.DL3022]
+severity = "off"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Profile != ProfileStrict {
+		t.Errorf("Profile = %q, want %q", cfg.Profile, ProfileStrict)
+	}
+	// Profile settings apply where the file is silent...
+	if !cfg.InlineDirectives.WarnUnused {
+		t.Error("InlineDirectives.WarnUnused = false, want true from the strict profile")
+	}
+	if got := cfg.Rules.GetSeverity("tally/base-image-not-eol"); got != "warning" {
+		t.Errorf("tally/base-image-not-eol severity = %q, want %q", got, "warning")
+	}
+	// ...and the file wins where both set a value.
+	if cfg.InlineDirectives.RequireReason {
+		t.Error("InlineDirectives.RequireReason = true, want the config file's false")
+	}
+	if got := cfg.Rules.GetSeverity("hadolint/DL3022"); got != "off" {
+		t.Errorf("hadolint/DL3022 severity = %q, want %q", got, "off")
+	}
+}
+
+func TestLoad_ProfileMergesRuleSelection(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configPath := filepath.Join(tmpDir, ".tally.toml")
+	configContent := `
+profile = "hadolint-compat"
+
+[rules]
+include = ["tally/max-lines", "powershell/*"]
+exclude = ["hadolint/DL3006"]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{"tally/max-lines", true},           // user include beats profile exclude
+		{"powershell/PowerShell", true},     // user namespace include overrides the profile's
+		{"tally/no-trailing-spaces", false}, // profile exclude
+		{"hadolint/DL3006", false},          // user exclude
+		{"buildkit/StageNameCasing", false}, // profile exclude
+	}
+	for _, tt := range tests {
+		enabled := cfg.Rules.IsEnabled(tt.rule)
+		if enabled == nil || *enabled != tt.want {
+			t.Errorf("IsEnabled(%q) = %v, want %v", tt.rule, enabled, tt.want)
+		}
+	}
+	if enabled := cfg.Rules.IsEnabled("buildkit/UndefinedVar"); enabled != nil {
+		t.Errorf("IsEnabled(buildkit/UndefinedVar) = %v, want nil", *enabled)
+	}
+	if cfg.Output.FailLevel != "info" {
+		t.Errorf("Output.FailLevel = %q, want %q", cfg.Output.FailLevel, "info")
+	}
+}
+
+func TestLoad_ProfileFromEnv(t *testing.T) {
+	t.Setenv("TALLY_PROFILE", "minimal")
+	_, dockerfilePath := setupTempProject(t)
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Profile != ProfileMinimal {
+		t.Errorf("Profile = %q, want %q", cfg.Profile, ProfileMinimal)
+	}
+	if !slices.Contains(cfg.Rules.Exclude, "tally/no-trailing-spaces") {
+		t.Errorf("Rules.Exclude = %v, want it to contain tally/no-trailing-spaces", cfg.Rules.Exclude)
+	}
+}
+
+func TestLoad_UnknownProfile(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	configPath := filepath.Join(tmpDir, ".tally.toml")
+	if err := os.WriteFile(configPath, []byte(`profile = "paranoid"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(dockerfilePath)
+	if err == nil || !strings.Contains(err.Error(), `unknown profile "paranoid"`) {
+		t.Fatalf("Load() error = %v, want unknown profile error", err)
+	}
+}
+
+func TestLoadWithOverrides_Profile(t *testing.T) {
+	t.Parallel()
+	_, dockerfilePath := setupTempProject(t)
+
+	overrides := map[string]any{"profile": "recommended"}
+	cfg, err := LoadWithOverrides(dockerfilePath, overrides, ConfigurationPreferenceEditorOnly)
+	if err != nil {
+		t.Fatalf("LoadWithOverrides() error = %v", err)
+	}
+	if got := cfg.Rules.GetSeverity("tally/base-image-not-eol"); got != "warning" {
+		t.Errorf("tally/base-image-not-eol severity = %q, want %q", got, "warning")
+	}
+}
+
 func TestLoad_NamespacedTallyRuleConfig(t *testing.T) {
 	t.Parallel()
 	tmpDir, dockerfilePath := setupTempProject(t)
@@ -543,6 +1051,7 @@ func TestEnvKeyTransform(t *testing.T) {
 		{"TALLY_AI_TIMEOUT", "ai.timeout"},
 		{"TALLY_AI_MAX_INPUT_BYTES", "ai.max-input-bytes"},
 		{"TALLY_AI_REDACT_SECRETS", "ai.redact-secrets"},
+		{"TALLY_SLOW_CHECKS_CACHE_TTL", "slow-checks.cache-ttl"},
 		{"TALLY_EXPECTED_DIAGNOSTICS", ""},
 	}
 
@@ -598,8 +1107,7 @@ func TestRulesConfigIncludeExclude(t *testing.T) {
 		t.Error("buildkit/StageNameCasing should be enabled via include")
 	}
 
-	// Include takes precedence over exclude (Ruff-style semantics)
-	// If both include and exclude match, include wins
+	// Between equally specific patterns from the config file, include wins
 	rc2 := &RulesConfig{
 		Include: []string{"buildkit/*"},
 		Exclude: []string{"buildkit/*"}, // Even with wildcard exclude, include wins
@@ -647,7 +1155,7 @@ func TestRulesConfigIncludeExclude(t *testing.T) {
 		Include: []string{"hadolint/DL3003"},
 		Exclude: []string{"*"},
 	}
-	// Include still takes precedence even with "*" exclude
+	// The exact include is more specific than the "*" exclude
 	enabled = rc3.IsEnabled("hadolint/DL3003")
 	if enabled == nil || *enabled != true {
 		t.Error("hadolint/DL3003 should be enabled - include takes precedence over * exclude")
@@ -695,6 +1203,102 @@ func TestRulesConfigIncludeExclude(t *testing.T) {
 	}
 }
 
+func TestRulesConfig_SelectionPrecedence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		selectR []string
+		ignore  []string
+		rule    string
+		want    *bool
+		pattern string
+	}{
+		{
+			name:    "exact include beats namespace exclude",
+			include: []string{"hadolint/DL3026"},
+			exclude: []string{"hadolint/*"},
+			rule:    "hadolint/DL3026",
+			want:    new(true),
+			pattern: "hadolint/DL3026",
+		},
+		{
+			name:    "exact exclude beats namespace include",
+			include: []string{"buildkit/*"},
+			exclude: []string{"buildkit/MaintainerDeprecated"},
+			rule:    "buildkit/MaintainerDeprecated",
+			want:    new(false),
+			pattern: "buildkit/MaintainerDeprecated",
+		},
+		{
+			name:    "nested namespace beats namespace",
+			include: []string{"tally/*"},
+			exclude: []string{"tally/runtime/*"},
+			rule:    "tally/runtime/invalid-stopsignal",
+			want:    new(false),
+			pattern: "tally/runtime/*",
+		},
+		{
+			name:    "namespace beats universal wildcard",
+			include: []string{"*"},
+			exclude: []string{"tally/*"},
+			rule:    "tally/max-lines",
+			want:    new(false),
+			pattern: "tally/*",
+		},
+		{
+			name:    "ignore overrides equally specific include",
+			include: []string{"tally/*"},
+			ignore:  []string{"tally/*"},
+			rule:    "tally/max-lines",
+			want:    new(false),
+			pattern: "tally/*",
+		},
+		{
+			name:    "select overrides equally specific exclude",
+			exclude: []string{"hadolint/*"},
+			selectR: []string{"hadolint/*"},
+			rule:    "hadolint/DL3008",
+			want:    new(true),
+			pattern: "hadolint/*",
+		},
+		{
+			name:    "config exact pattern beats select wildcard",
+			exclude: []string{"hadolint/DL3008"},
+			selectR: []string{"hadolint/*"},
+			rule:    "hadolint/DL3008",
+			want:    new(false),
+			pattern: "hadolint/DL3008",
+		},
+		{
+			name:    "no matching pattern",
+			include: []string{"tally/*"},
+			rule:    "hadolint/DL3008",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rc := &RulesConfig{Include: tt.include, Exclude: tt.exclude}
+			rc.Select(tt.selectR...)
+			rc.Ignore(tt.ignore...)
+
+			got := rc.IsEnabled(tt.rule)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("IsEnabled(%q) = %v, want %v", tt.rule, got, tt.want)
+			}
+			sel, ok := rc.Selection(tt.rule)
+			if ok != (tt.pattern != "") || sel.Pattern != tt.pattern {
+				t.Errorf("Selection(%q) = %+v, %v, want pattern %q", tt.rule, sel, ok, tt.pattern)
+			}
+		})
+	}
+}
+
 func TestRulesConfigSupersededRuleAlias(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,347 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+
+	"github.com/wharflab/tally/internal/async/httpfetch"
+)
+
+const (
+	// remoteConfigTTL is how long a downloaded remote config is reused before
+	// it is downloaded again.
+	remoteConfigTTL = time.Hour
+
+	// maxRemoteConfigSize bounds the size of a downloaded remote config.
+	maxRemoteConfigSize = 1 << 20
+)
+
+var (
+	// remoteConfigClient downloads remote configs. It shares the slow-check
+	// transport's retries, per-host limit and DNS failure cache. Tests replace it.
+	remoteConfigClient = httpfetch.NewClient(30 * time.Second)
+
+	// githubRawBaseURL serves files for github.com/<owner>/<repo> references.
+	githubRawBaseURL = "https://raw.githubusercontent.com"
+)
+
+// RemoteConfigCacheDir returns the directory where configs referenced by
+// extends are cached. TALLY_CONFIG_CACHE_DIR overrides the default under
+// os.UserCacheDir.
+func RemoteConfigCacheDir() (string, error) {
+	if dir := os.Getenv("TALLY_CONFIG_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "tally", "configs"), nil
+}
+
+// loadConfigFile merges the config file at configPath, preceded by the
+// configs it extends, onto k.
+//
+// Each extends entry is merged in order, so later entries override earlier
+// ones and the extending file overrides them all. Tables merge key by key and
+// arrays are replaced, except rules.include and rules.exclude, which
+// accumulate across the chain.
+func loadConfigFile(k *koanf.Koanf, configPath string) error {
+	if configPath == "" {
+		return nil
+	}
+	layer, err := loadExtending(configSource{name: configPath}, nil)
+	if err != nil {
+		return err
+	}
+	return k.Merge(layer)
+}
+
+// configSource is a config file referenced directly or through extends.
+type configSource struct {
+	// name is the local path or the reference as written for remote
+	// configs. It identifies the source in errors and cycle detection.
+	name string
+	// urls lists the candidate download URLs of a remote config, tried in
+	// order until one exists. Empty for local files.
+	urls []string
+}
+
+func (s configSource) remote() bool { return len(s.urls) > 0 }
+
+// read returns the source's contents and the location that relative extends
+// entries inside it resolve against.
+func (s configSource) read() ([]byte, string, error) {
+	if !s.remote() {
+		data, err := os.ReadFile(s.name)
+		return data, s.name, err
+	}
+	var lastErr error
+	for _, u := range s.urls {
+		data, err := fetchRemoteConfig(u)
+		if err == nil {
+			return data, u, nil
+		}
+		lastErr = err
+		if remoteErr, ok := errors.AsType[*httpfetch.LookupError](err); !ok || !remoteErr.NotFound() {
+			break
+		}
+	}
+	return nil, "", lastErr
+}
+
+// loadExtending loads src with the configs it extends merged underneath.
+// stack holds the sources currently being loaded, to detect cycles.
+func loadExtending(src configSource, stack []string) (*koanf.Koanf, error) {
+	if slices.Contains(stack, src.name) {
+		return nil, fmt.Errorf("config extends cycle: %s", strings.Join(append(stack, src.name), " -> "))
+	}
+	stack = append(stack, src.name)
+
+	data, base, err := src.read()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := toml.Parser().Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.name, err)
+	}
+	layer := koanf.New(".")
+	if err := layer.Load(confmap.Provider(raw, ""), nil); err != nil {
+		return nil, err
+	}
+
+	refs, err := extendsList(raw["extends"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.name, err)
+	}
+	merged := koanf.New(".")
+	for _, ref := range refs {
+		next, err := resolveExtends(base, src.remote(), ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.name, err)
+		}
+		parent, err := loadExtending(next, stack)
+		if err != nil {
+			return nil, fmt.Errorf("%s: extends %q: %w", src.name, ref, err)
+		}
+		// Only the extending file's own list is kept as Config.Extends.
+		parent.Delete("extends")
+		if err := mergeLayer(merged, parent); err != nil {
+			return nil, err
+		}
+	}
+	if err := mergeLayer(merged, layer); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func extendsList(v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("extends must be an array of strings")
+	}
+	refs := make([]string, 0, len(items))
+	for _, item := range items {
+		ref, ok := item.(string)
+		if !ok || ref == "" {
+			return nil, errors.New("extends must be an array of non-empty strings")
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// mergeLayer merges layer over dst, accumulating the rule selection lists.
+func mergeLayer(dst, layer *koanf.Koanf) error {
+	include := appendMissing(dst.Strings("rules.include"), layer.Strings("rules.include"))
+	exclude := appendMissing(dst.Strings("rules.exclude"), layer.Strings("rules.exclude"))
+	if err := dst.Merge(layer); err != nil {
+		return err
+	}
+	for key, list := range map[string][]string{"rules.include": include, "rules.exclude": exclude} {
+		if len(list) == 0 {
+			continue
+		}
+		values := make([]any, len(list))
+		for i, s := range list {
+			values[i] = s
+		}
+		if err := dst.Set(key, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveExtends resolves an extends entry found in a config at base.
+//
+// Supported forms:
+//   - https://host/path/config.toml
+//   - github.com/<owner>/<repo>[/<path>][@<ref>], read from the repository's
+//     <path> (default: .tally.toml, then tally.toml) at <ref> (default: HEAD)
+//   - a path, relative to the extending config's location
+func resolveExtends(base string, baseRemote bool, ref string) (configSource, error) {
+	switch {
+	case strings.HasPrefix(ref, "https://"):
+		return configSource{name: ref, urls: []string{ref}}, nil
+	case strings.HasPrefix(ref, "http://"):
+		return configSource{}, fmt.Errorf("extends %q: remote configs must use https", ref)
+	case strings.HasPrefix(ref, "github.com/"):
+		urls, err := githubConfigURLs(ref)
+		if err != nil {
+			return configSource{}, err
+		}
+		return configSource{name: ref, urls: urls}, nil
+	}
+
+	if baseRemote {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return configSource{}, err
+		}
+		relURL, err := url.Parse(filepath.ToSlash(ref))
+		if err != nil {
+			return configSource{}, fmt.Errorf("extends %q: %w", ref, err)
+		}
+		resolved := baseURL.ResolveReference(relURL).String()
+		return configSource{name: resolved, urls: []string{resolved}}, nil
+	}
+	if !filepath.IsAbs(ref) {
+		ref = filepath.Join(filepath.Dir(base), ref)
+	}
+	return configSource{name: filepath.Clean(ref)}, nil
+}
+
+// githubConfigURLs returns the raw file URLs for a github.com reference.
+func githubConfigURLs(ref string) ([]string, error) {
+	spec, version, _ := strings.Cut(strings.TrimPrefix(ref, "github.com/"), "@")
+	parts := strings.SplitN(spec, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("extends %q: expected github.com/<owner>/<repo>[/<path>][@<ref>]", ref)
+	}
+	if version == "" {
+		version = "HEAD"
+	}
+	prefix := strings.Join([]string{strings.TrimSuffix(githubRawBaseURL, "/"), parts[0], parts[1], version}, "/")
+	if len(parts) == 3 && parts[2] != "" {
+		return []string{prefix + "/" + parts[2]}, nil
+	}
+	urls := make([]string, 0, len(ConfigFileNames))
+	for _, name := range ConfigFileNames {
+		urls = append(urls, prefix+"/"+name)
+	}
+	return urls, nil
+}
+
+// fetchRemoteConfig returns the contents of a remote config, from the cache
+// when the cached copy is younger than remoteConfigTTL. When the download
+// fails for any reason other than the file not existing, a stale cached copy
+// is used instead, so linting keeps working offline.
+func fetchRemoteConfig(rawURL string) ([]byte, error) {
+	cachePath := remoteConfigCachePath(rawURL)
+	cached, cachedAt, cacheErr := readCachedConfig(cachePath)
+	if cacheErr == nil && time.Since(cachedAt) < remoteConfigTTL {
+		return cached, nil
+	}
+
+	data, err := downloadRemoteConfig(rawURL)
+	if err != nil {
+		remoteErr, isRemote := errors.AsType[*httpfetch.LookupError](err)
+		if cacheErr == nil && (!isRemote || !remoteErr.NotFound()) {
+			return cached, nil
+		}
+		return nil, err
+	}
+	writeCachedConfig(cachePath, data)
+	return data, nil
+}
+
+func downloadRemoteConfig(rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := remoteConfigClient.Do(req)
+	if err != nil {
+		return nil, &httpfetch.LookupError{URL: rawURL, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpfetch.LookupError{URL: rawURL, Status: resp.StatusCode}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, &httpfetch.LookupError{URL: rawURL, Err: err}
+	}
+	if len(data) > maxRemoteConfigSize {
+		return nil, &httpfetch.LookupError{URL: rawURL, Err: fmt.Errorf("config exceeds %d bytes", maxRemoteConfigSize)}
+	}
+	return data, nil
+}
+
+// remoteConfigCachePath returns the cache file for rawURL, or "" when no
+// cache directory is available.
+func remoteConfigCachePath(rawURL string) string {
+	dir, err := RemoteConfigCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".toml")
+}
+
+func readCachedConfig(path string) ([]byte, time.Time, error) {
+	if path == "" {
+		return nil, time.Time{}, os.ErrNotExist
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, info.ModTime(), nil
+}
+
+// writeCachedConfig stores data at path. Caching is best effort: failures
+// only cost a download on the next run.
+func writeCachedConfig(path string, data []byte) {
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad_ExtendsLocal(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	writeConfig(t, filepath.Join(tmpDir, "policy", "base.toml"), `
+[output]
+fail-level = "error"
+show-source = false
+
+[rules]
+exclude = ["buildkit/StageNameCasing"]
+
+[rules.tally.max-lines]
+max = 50
+`)
+	writeConfig(t, filepath.Join(tmpDir, "policy", "security.toml"), `
+extends = ["./base.toml"]
+
+[output]
+fail-level = "warning"
+
+[rules]
+exclude = ["hadolint/DL3006"]
+`)
+	writeConfig(t, filepath.Join(tmpDir, ".tally.toml"), `
+extends = ["policy/security.toml"]
+
+[output]
+show-source = true
+
+[rules]
+exclude = ["tally/max-lines"]
+`)
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Output.FailLevel != "warning" {
+		t.Errorf("FailLevel = %q, want %q (later layer wins)", cfg.Output.FailLevel, "warning")
+	}
+	if !cfg.Output.ShowSource {
+		t.Error("ShowSource = false, want the extending file's true")
+	}
+	wantExclude := []string{"buildkit/StageNameCasing", "hadolint/DL3006", "tally/max-lines"}
+	if !slices.Equal(cfg.Rules.Exclude, wantExclude) {
+		t.Errorf("Rules.Exclude = %v, want %v", cfg.Rules.Exclude, wantExclude)
+	}
+	if opts := cfg.Rules.GetOptions("tally/max-lines"); fmt.Sprint(opts["max"]) != "50" {
+		t.Errorf("tally/max-lines options = %v, want max from base.toml", opts)
+	}
+	if !slices.Equal(cfg.Extends, []string{"policy/security.toml"}) {
+		t.Errorf("Extends = %v, want the file's own list", cfg.Extends)
+	}
+}
+
+func TestLoad_ExtendsCycle(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	writeConfig(t, filepath.Join(tmpDir, "a.toml"), `extends = ["b.toml"]`)
+	writeConfig(t, filepath.Join(tmpDir, "b.toml"), `extends = ["a.toml"]`)
+	writeConfig(t, filepath.Join(tmpDir, ".tally.toml"), `extends = ["a.toml"]`)
+
+	_, err := Load(dockerfilePath)
+	if err == nil || !strings.Contains(err.Error(), "config extends cycle") {
+		t.Fatalf("Load() error = %v, want extends cycle", err)
+	}
+}
+
+func TestLoad_ExtendsMissingFile(t *testing.T) {
+	t.Parallel()
+	tmpDir, dockerfilePath := setupTempProject(t)
+
+	writeConfig(t, filepath.Join(tmpDir, ".tally.toml"), `extends = ["missing.toml"]`)
+
+	_, err := Load(dockerfilePath)
+	if err == nil || !strings.Contains(err.Error(), `extends "missing.toml"`) {
+		t.Fatalf("Load() error = %v, want missing extends error", err)
+	}
+}
+
+func TestResolveExtends(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		base       string
+		baseRemote bool
+		ref        string
+		wantName   string
+		wantURLs   []string
+		wantErr    string
+	}{
+		{
+			name:     "relative path",
+			base:     filepath.FromSlash("/repo/.tally.toml"),
+			ref:      "policy/base.toml",
+			wantName: filepath.FromSlash("/repo/policy/base.toml"),
+		},
+		{
+			name:     "https URL",
+			base:     filepath.FromSlash("/repo/.tally.toml"),
+			ref:      "https://example.com/tally.toml",
+			wantName: "https://example.com/tally.toml",
+			wantURLs: []string{"https://example.com/tally.toml"},
+		},
+		{
+			name:    "plain http",
+			base:    filepath.FromSlash("/repo/.tally.toml"),
+			ref:     "http://example.com/tally.toml",
+			wantErr: "must use https",
+		},
+		{
+			name:     "github repository",
+			ref:      "github.com/org/tally-config@v1",
+			wantName: "github.com/org/tally-config@v1",
+			wantURLs: []string{
+				"https://raw.githubusercontent.com/org/tally-config/v1/.tally.toml",
+				"https://raw.githubusercontent.com/org/tally-config/v1/tally.toml",
+			},
+		},
+		{
+			name:     "github file without ref",
+			ref:      "github.com/org/policies/docker/strict.toml",
+			wantName: "github.com/org/policies/docker/strict.toml",
+			wantURLs: []string{"https://raw.githubusercontent.com/org/policies/HEAD/docker/strict.toml"},
+		},
+		{
+			name:    "github without repo",
+			ref:     "github.com/org",
+			wantErr: "expected github.com/<owner>/<repo>",
+		},
+		{
+			name:       "relative to remote",
+			base:       "https://example.com/policy/tally.toml",
+			baseRemote: true,
+			ref:        "../shared/base.toml",
+			wantName:   "https://example.com/shared/base.toml",
+			wantURLs:   []string{"https://example.com/shared/base.toml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := resolveExtends(tt.base, tt.baseRemote, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveExtends() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveExtends() error = %v", err)
+			}
+			if got.name != tt.wantName || !slices.Equal(got.urls, tt.wantURLs) {
+				t.Errorf("resolveExtends() = {%q %v}, want {%q %v}", got.name, got.urls, tt.wantName, tt.wantURLs)
+			}
+		})
+	}
+}
+
+// useTestRemote points remote config downloads at srv and the cache at a
+// temporary directory. Tests using it must not run in parallel.
+func useTestRemote(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	t.Setenv("TALLY_CONFIG_CACHE_DIR", t.TempDir())
+
+	prevClient, prevBase := remoteConfigClient, githubRawBaseURL
+	remoteConfigClient, githubRawBaseURL = srv.Client(), srv.URL
+	t.Cleanup(func() { remoteConfigClient, githubRawBaseURL = prevClient, prevBase })
+}
+
+func TestLoad_ExtendsGitHub(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/org/tally-config/v1/tally.toml":
+			_, _ = w.Write([]byte("extends = [\"shared/base.toml\"]\n[rules]\nexclude = [\"hadolint/DL3006\"]\n"))
+		case "/org/tally-config/v1/shared/base.toml":
+			_, _ = w.Write([]byte("[output]\nfail-level = \"error\"\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	useTestRemote(t, srv)
+
+	tmpDir, dockerfilePath := setupTempProject(t)
+	writeConfig(t, filepath.Join(tmpDir, ".tally.toml"), `extends = ["github.com/org/tally-config@v1"]`)
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Output.FailLevel != "error" {
+		t.Errorf("FailLevel = %q, want %q", cfg.Output.FailLevel, "error")
+	}
+	if !slices.Contains(cfg.Rules.Exclude, "hadolint/DL3006") {
+		t.Errorf("Rules.Exclude = %v, want hadolint/DL3006", cfg.Rules.Exclude)
+	}
+	// .tally.toml (404), tally.toml, shared/base.toml.
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+
+	// A second load is served from the cache.
+	if _, err := Load(dockerfilePath); err != nil {
+		t.Fatalf("second Load() error = %v", err)
+	}
+	if got := requests.Load(); got != 4 {
+		t.Errorf("requests after cached load = %d, want 4 (only the 404 is retried)", got)
+	}
+}
+
+func TestLoad_ExtendsRemoteFallsBackToStaleCache(t *testing.T) {
+	var failing atomic.Bool
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("[output]\nfail-level = \"error\"\n"))
+	}))
+	defer srv.Close()
+	useTestRemote(t, srv)
+
+	tmpDir, dockerfilePath := setupTempProject(t)
+	writeConfig(t, filepath.Join(tmpDir, ".tally.toml"), `extends = ["`+srv.URL+`/policy.toml"]`)
+
+	if _, err := Load(dockerfilePath); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Age the cached copy past its TTL, then make the server fail.
+	cachePath := remoteConfigCachePath(srv.URL + "/policy.toml")
+	old := time.Now().Add(-2 * remoteConfigTTL)
+	if err := os.Chtimes(cachePath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	failing.Store(true)
+
+	cfg, err := Load(dockerfilePath)
+	if err != nil {
+		t.Fatalf("Load() with failing server error = %v", err)
+	}
+	if cfg.Output.FailLevel != "error" {
+		t.Errorf("FailLevel = %q, want %q from the stale cache", cfg.Output.FailLevel, "error")
+	}
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+
+	"github.com/wharflab/tally/internal/ruledeprecation"
+)
+
+// HadolintConfigFileNames are the config file names hadolint discovers in a
+// project directory.
+var HadolintConfigFileNames = []string{".hadolint.yaml", ".hadolint.yml"}
+
+// hadolintTrustedRegistriesRule is the rule hadolint's trustedRegistries
+// setting configures. hadolint reports it as an error once the list is set.
+const hadolintTrustedRegistriesRule = "hadolint/DL3026"
+
+// HadolintMigration is the result of converting a hadolint config file.
+type HadolintMigration struct {
+	// TOML is the equivalent .tally.toml content.
+	TOML []byte
+	// Unmapped lists hadolint settings without a tally equivalent, one
+	// human-readable note per setting.
+	Unmapped []string
+}
+
+// MigrateHadolintConfig converts a hadolint config file (.hadolint.yaml) to
+// an equivalent .tally.toml: ignored rules become rules.exclude entries,
+// override severities become per-rule severities, trustedRegistries becomes
+// the global [registries] trusted list, and failure-threshold, no-fail,
+// format and disable-ignore-pragma map to their output and inline-directive
+// counterparts. Rules that tally implements through BuildKit are written
+// with their BuildKit codes.
+func MigrateHadolintConfig(data []byte) (*HadolintMigration, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse hadolint config: %w", err)
+	}
+
+	m := &hadolintMigrator{severities: make(map[string]string)}
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		if err := m.apply(key, raw[key]); err != nil {
+			return nil, fmt.Errorf("hadolint config %s: %w", key, err)
+		}
+	}
+	return &HadolintMigration{TOML: m.render(), Unmapped: m.unmapped}, nil
+}
+
+type hadolintMigrator struct {
+	format              string
+	failLevel           string
+	noFail              bool
+	disableIgnorePragma bool
+	trusted             []string
+	exclude             []string
+	// severities maps tally rule codes to their overridden severity.
+	severities map[string]string
+	unmapped   []string
+}
+
+func (m *hadolintMigrator) apply(key string, value any) error {
+	switch key {
+	case "ignored":
+		codes, err := yamlStrings(value)
+		if err != nil {
+			return err
+		}
+		for _, code := range codes {
+			if ruleCode, ok := m.ruleCode(code); ok && !slices.Contains(m.exclude, ruleCode) {
+				m.exclude = append(m.exclude, ruleCode)
+			}
+		}
+	case "override":
+		levels, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected a mapping, got %T", value)
+		}
+		for _, level := range []string{"error", "warning", "info", "style"} {
+			codes, err := yamlStrings(levels[level])
+			if err != nil {
+				return fmt.Errorf("%s: %w", level, err)
+			}
+			for _, code := range codes {
+				if ruleCode, ok := m.ruleCode(code); ok {
+					m.severities[ruleCode] = level
+				}
+			}
+		}
+		for _, level := range slices.Sorted(maps.Keys(levels)) {
+			if !slices.Contains([]string{"error", "warning", "info", "style"}, level) {
+				m.unmap("override.%s: unknown severity", level)
+			}
+		}
+	case "trustedRegistries":
+		registries, err := yamlStrings(value)
+		if err != nil {
+			return err
+		}
+		m.trusted = registries
+	case "failure-threshold":
+		switch threshold := fmt.Sprint(value); threshold {
+		case "error", "warning", "info", "style":
+			m.failLevel = threshold
+		case "ignore", "none":
+			m.failLevel = "none"
+		default:
+			m.unmap("failure-threshold %q: unknown severity", threshold)
+		}
+	case "no-fail":
+		m.noFail, _ = value.(bool)
+	case "format":
+		switch format := fmt.Sprint(value); format {
+		case "tty":
+		case "json", "sarif":
+			m.format = format
+		default:
+			m.unmap("format %q: not supported by tally (available: text, json, sarif, github-actions, markdown, html, tap)", format)
+		}
+	case "disable-ignore-pragma":
+		m.disableIgnorePragma, _ = value.(bool)
+	case "no-color":
+		m.unmap("no-color: use the --no-color flag or the NO_COLOR environment variable")
+	case "label-schema", "strict-labels":
+		m.unmap("%s: tally does not implement hadolint's label schema rules (DL3049-DL3056)", key)
+	default:
+		m.unmap("%s: unknown hadolint setting", key)
+	}
+	return nil
+}
+
+// ruleCode maps a hadolint or ShellCheck rule code to its tally code.
+func (m *hadolintMigrator) ruleCode(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	switch {
+	case strings.HasPrefix(code, "DL"):
+		if replacement, ok := ruledeprecation.ReplacementFor(code); ok {
+			return replacement, true
+		}
+		return "hadolint/" + code, true
+	case strings.HasPrefix(code, "SC"):
+		return "shellcheck/" + code, true
+	default:
+		m.unmap("rule %q: not a hadolint (DL) or ShellCheck (SC) rule code", code)
+		return "", false
+	}
+}
+
+func (m *hadolintMigrator) unmap(format string, args ...any) {
+	m.unmapped = append(m.unmapped, fmt.Sprintf(format, args...))
+}
+
+func (m *hadolintMigrator) render() []byte {
+	var b strings.Builder
+	b.WriteString("# Migrated from a hadolint config file by \"tally migrate --from hadolint\".\n")
+
+	failLevel := m.failLevel
+	if m.noFail {
+		failLevel = "none"
+	}
+	if m.format != "" || failLevel != "" {
+		b.WriteString("\n[output]\n")
+		if m.format != "" {
+			fmt.Fprintf(&b, "format = %s\n", strconv.Quote(m.format))
+		}
+		if failLevel != "" {
+			fmt.Fprintf(&b, "fail-level = %s\n", strconv.Quote(failLevel))
+		}
+	}
+
+	if m.disableIgnorePragma {
+		b.WriteString("\n[inline-directives]\nenabled = false\n")
+	}
+
+	if len(m.trusted) > 0 {
+		fmt.Fprintf(&b, "\n[registries]\ntrusted = %s\n", tomlStringArray(m.trusted))
+		if _, ok := m.severities[hadolintTrustedRegistriesRule]; !ok {
+			m.severities[hadolintTrustedRegistriesRule] = "error"
+		}
+	}
+
+	if len(m.exclude) > 0 {
+		fmt.Fprintf(&b, "\n[rules]\nexclude = %s\n", tomlStringArray(m.exclude))
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(m.severities)) {
+		if slices.Contains(m.exclude, code) {
+			// hadolint skips ignored rules whatever their severity.
+			continue
+		}
+		namespace, name, _ := strings.Cut(code, "/")
+		fmt.Fprintf(&b, "\n[rules.%s.%s]\nseverity = %s\n", namespace, quoteKey(name), strconv.Quote(m.severities[code]))
+	}
+	return []byte(b.String())
+}
+
+// yamlStrings converts a YAML string or list of strings to a slice.
+func yamlStrings(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, got %T", item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a list of strings, got %T", value)
+	}
+}
+
+func tomlStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
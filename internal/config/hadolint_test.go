@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMigrateHadolintConfig(t *testing.T) {
+	t.Parallel()
+
+	input := `failure-threshold: warning
+format: json
+ignored:
+  - DL3008
+  - DL3025
+  - SC2086
+override:
+  error:
+    - DL3006
+  info:
+    - DL3008
+trustedRegistries: docker.io
+label-schema:
+  author: text
+no-color: true
+`
+	got, err := MigrateHadolintConfig([]byte(input))
+	if err != nil {
+		t.Fatalf("MigrateHadolintConfig() error = %v", err)
+	}
+
+	want := `# Migrated from a hadolint config file by "tally migrate --from hadolint".
+
+[output]
+format = "json"
+fail-level = "warning"
+
+[registries]
+trusted = ["docker.io"]
+
+[rules]
+exclude = ["hadolint/DL3008", "buildkit/JSONArgsRecommended", "shellcheck/SC2086"]
+
+[rules.hadolint.DL3006]
+severity = "error"
+
+[rules.hadolint.DL3026]
+severity = "error"
+`
+	if string(got.TOML) != want {
+		t.Errorf("TOML:\n%s\nwant:\n%s", got.TOML, want)
+	}
+	wantUnmapped := []string{
+		"label-schema: tally does not implement hadolint's label schema rules (DL3049-DL3056)",
+		"no-color: use the --no-color flag or the NO_COLOR environment variable",
+	}
+	if !slices.Equal(got.Unmapped, wantUnmapped) {
+		t.Errorf("Unmapped = %q, want %q", got.Unmapped, wantUnmapped)
+	}
+
+	// The result must be a valid tally config with the same meaning.
+	path := filepath.Join(t.TempDir(), ".tally.toml")
+	if err := os.WriteFile(path, got.TOML, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFromFileWithFlags(path, nil, nil)
+	if err != nil {
+		t.Fatalf("load migrated config: %v", err)
+	}
+	if cfg.Output.FailLevel != "warning" || cfg.Rules.GetSeverity("hadolint/DL3006") != "error" {
+		t.Errorf("migrated config = %+v", cfg.Output)
+	}
+	if enabled := cfg.Rules.IsEnabled("shellcheck/SC2086"); enabled == nil || *enabled {
+		t.Error("shellcheck/SC2086 should be disabled")
+	}
+}
+
+func TestMigrateHadolintConfig_NoFail(t *testing.T) {
+	t.Parallel()
+
+	got, err := MigrateHadolintConfig([]byte("failure-threshold: error\nno-fail: true\ndisable-ignore-pragma: true\n"))
+	if err != nil {
+		t.Fatalf("MigrateHadolintConfig() error = %v", err)
+	}
+	want := `# Migrated from a hadolint config file by "tally migrate --from hadolint".
+
+[output]
+fail-level = "none"
+
+[inline-directives]
+enabled = false
+`
+	if string(got.TOML) != want || len(got.Unmapped) != 0 {
+		t.Errorf("MigrateHadolintConfig() = %s, unmapped %q", got.TOML, got.Unmapped)
+	}
+}
+
+func TestMigrateHadolintConfig_InvalidValue(t *testing.T) {
+	t.Parallel()
+
+	if _, err := MigrateHadolintConfig([]byte("ignored: {DL3008: true}\n")); err == nil {
+		t.Fatal("MigrateHadolintConfig() error = nil, want error for a mapping in ignored")
+	}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+
+	"github.com/wharflab/tally/internal/ruledeprecation"
+)
+
+// RuleCodeMigration is a deprecated rule code found by MigrateRuleCodes.
+type RuleCodeMigration struct {
+	// Line is the 1-based line of the reference.
+	Line int
+	// From is the deprecated code as written.
+	From string
+	// To is the replacement rule code; empty for rules without one.
+	To string
+	// Applied reports whether the reference was rewritten. References that
+	// need a manual edit (no replacement, or a replacement in another
+	// namespace under a shared table header) are reported but left as is.
+	Applied bool
+}
+
+// Message describes the migration for command output.
+func (m RuleCodeMigration) Message() string {
+	switch {
+	case m.Applied:
+		return fmt.Sprintf("%s -> %s", m.From, m.To)
+	case m.To != "":
+		return fmt.Sprintf("%s is deprecated; move its settings to %s manually", m.From, m.To)
+	default:
+		return fmt.Sprintf("%s is deprecated and has no replacement; remove it", m.From)
+	}
+}
+
+// MigrateRuleCodes rewrites deprecated rule codes in a .tally.toml document
+// to their replacements: rule tables ([rules.<namespace>.<name>]), the
+// rules include and exclude lists, fix-precedence, and the rule keys of
+// severity-by-stage-role. Formatting and comments are preserved.
+func MigrateRuleCodes(content []byte) ([]byte, []RuleCodeMigration, error) {
+	m := &ruleCodeMigrator{}
+	m.parser.Reset(content)
+
+	var table []keyPart
+	for m.parser.NextExpression() {
+		expr := m.parser.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = m.keyParts(expr)
+			m.visitKey(table)
+		case unstable.KeyValue:
+			m.visitKeyValue(table, expr)
+		}
+	}
+	if err := m.parser.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	// Apply edits back to front so earlier offsets stay valid.
+	out := slices.Clone(content)
+	slices.SortFunc(m.edits, func(a, b tomlEdit) int { return b.offset - a.offset })
+	for _, e := range m.edits {
+		out = slices.Replace(out, e.offset, e.offset+e.length, []byte(e.text)...)
+	}
+	slices.SortStableFunc(m.migrations, func(a, b RuleCodeMigration) int { return a.Line - b.Line })
+	return out, m.migrations, nil
+}
+
+// keyPart is one part of a dotted TOML key. node is nil for parts inherited
+// from the enclosing table header, which cannot be edited in place.
+type keyPart struct {
+	name string
+	node *unstable.Node
+}
+
+type tomlEdit struct {
+	offset, length int
+	text           string
+}
+
+type ruleCodeMigrator struct {
+	parser     unstable.Parser
+	edits      []tomlEdit
+	migrations []RuleCodeMigration
+}
+
+// keyParts returns the key of a table or key-value expression.
+func (m *ruleCodeMigrator) keyParts(n *unstable.Node) []keyPart {
+	var parts []keyPart
+	it := n.Key()
+	for it.Next() {
+		parts = append(parts, keyPart{name: string(it.Node().Data), node: it.Node()})
+	}
+	return parts
+}
+
+func (m *ruleCodeMigrator) visitKeyValue(prefix []keyPart, kv *unstable.Node) {
+	// Parts inherited from the table header are not editable here.
+	path := make([]keyPart, 0, len(prefix)+2)
+	for _, p := range prefix {
+		path = append(path, keyPart{name: p.name})
+	}
+	path = append(path, m.keyParts(kv)...)
+	m.visitKey(path)
+
+	value := kv.Value()
+	switch {
+	case value.Kind == unstable.InlineTable:
+		it := value.Children()
+		for it.Next() {
+			if it.Node().Kind == unstable.KeyValue {
+				m.visitKeyValue(path, it.Node())
+			}
+		}
+	case value.Kind == unstable.Array && isRuleCodeList(path):
+		it := value.Children()
+		for it.Next() {
+			if s := it.Node(); s.Kind == unstable.String {
+				m.migrateString(s)
+			}
+		}
+	}
+}
+
+// isRuleCodeList reports whether a key holds a list of rule codes.
+func isRuleCodeList(path []keyPart) bool {
+	names := make([]string, len(path))
+	for i, p := range path {
+		names[i] = p.name
+	}
+	return slices.Equal(names, []string{"rules", "include"}) ||
+		slices.Equal(names, []string{"rules", "exclude"}) ||
+		slices.Equal(names, []string{"fix-precedence"})
+}
+
+// visitKey migrates rule codes used as keys: rules.<namespace>.<name> and
+// severity-by-stage-role.<role>.<code>.
+func (m *ruleCodeMigrator) visitKey(path []keyPart) {
+	switch {
+	case len(path) >= 3 && path[0].name == "rules" && path[2].node != nil:
+		m.migrateRuleTableKey(path[1], path[2])
+	case len(path) == 3 && path[0].name == "severity-by-stage-role" && path[2].node != nil:
+		code := path[2].name
+		if entry, ok := ruledeprecation.Lookup(code); ok {
+			if entry.Replacement == "" {
+				m.report(path[2].node, code, "", false)
+				return
+			}
+			m.replace(path[2].node, quoteKey(entry.Replacement))
+			m.report(path[2].node, code, entry.Replacement, true)
+		}
+	}
+}
+
+func (m *ruleCodeMigrator) migrateRuleTableKey(namespace, name keyPart) {
+	code := namespace.name + "/" + name.name
+	entry, ok := ruledeprecation.Lookup(code)
+	if !ok {
+		return
+	}
+	newNamespace, newName, _ := strings.Cut(entry.Replacement, "/")
+	if entry.Replacement == "" || (newNamespace != namespace.name && namespace.node == nil) {
+		m.report(name.node, code, entry.Replacement, false)
+		return
+	}
+	if newNamespace != namespace.name {
+		m.replace(namespace.node, quoteKey(newNamespace))
+	}
+	m.replace(name.node, quoteKey(newName))
+	m.report(name.node, code, entry.Replacement, true)
+}
+
+func (m *ruleCodeMigrator) migrateString(s *unstable.Node) {
+	code := string(s.Data)
+	entry, ok := ruledeprecation.Lookup(code)
+	if !ok {
+		return
+	}
+	if entry.Replacement == "" {
+		m.report(s, code, "", false)
+		return
+	}
+	quote := m.parser.Raw(s.Raw)[0]
+	m.replace(s, string(quote)+entry.Replacement+string(quote))
+	m.report(s, code, entry.Replacement, true)
+}
+
+func (m *ruleCodeMigrator) replace(n *unstable.Node, text string) {
+	m.edits = append(m.edits, tomlEdit{offset: int(n.Raw.Offset), length: int(n.Raw.Length), text: text})
+}
+
+func (m *ruleCodeMigrator) report(n *unstable.Node, from, to string, applied bool) {
+	m.migrations = append(m.migrations, RuleCodeMigration{
+		Line:    m.parser.Shape(n.Raw).Start.Line,
+		From:    from,
+		To:      to,
+		Applied: applied,
+	})
+}
+
+// quoteKey returns key as a bare TOML key when possible, quoted otherwise.
+func quoteKey(key string) string {
+	bare := key != "" && strings.IndexFunc(key, func(c rune) bool {
+		return (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' && c != '_'
+	}) < 0
+	if bare {
+		return key
+	}
+	return `"` + key + `"`
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestMigrateRuleCodes(t *testing.T) {
+	t.Parallel()
+
+	input := `# Project config
+[rules]
+include = ["hadolint/*", 'DL3000'] # keep
+exclude = [
+  "hadolint/DL4000",
+  "tally/max-lines",
+]
+hadolint.DL3024.severity = "off"
+
+[rules.hadolint.DL3025]
+severity = "error"
+
+[rules.hadolint]
+DL3044 = { severity = "off" }
+
+[severity-by-stage-role.final]
+"hadolint/DL3063" = "error"
+`
+	want := `# Project config
+[rules]
+include = ["hadolint/*", 'buildkit/WorkdirRelativePath'] # keep
+exclude = [
+  "buildkit/MaintainerDeprecated",
+  "tally/max-lines",
+]
+buildkit.DuplicateStageName.severity = "off"
+
+[rules.buildkit.JSONArgsRecommended]
+severity = "error"
+
+[rules.hadolint]
+DL3044 = { severity = "off" }
+
+[severity-by-stage-role.final]
+"buildkit/ReservedStageName" = "error"
+`
+
+	got, migrations, err := MigrateRuleCodes([]byte(input))
+	if err != nil {
+		t.Fatalf("MigrateRuleCodes() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("MigrateRuleCodes() output:\n%s\nwant:\n%s", got, want)
+	}
+
+	wantMigrations := []RuleCodeMigration{
+		{Line: 3, From: "DL3000", To: "buildkit/WorkdirRelativePath", Applied: true},
+		{Line: 5, From: "hadolint/DL4000", To: "buildkit/MaintainerDeprecated", Applied: true},
+		{Line: 8, From: "hadolint/DL3024", To: "buildkit/DuplicateStageName", Applied: true},
+		{Line: 10, From: "hadolint/DL3025", To: "buildkit/JSONArgsRecommended", Applied: true},
+		{Line: 14, From: "hadolint/DL3044", To: "buildkit/UndefinedVar"},
+		{Line: 17, From: "hadolint/DL3063", To: "buildkit/ReservedStageName", Applied: true},
+	}
+	if len(migrations) != len(wantMigrations) {
+		t.Fatalf("migrations = %+v, want %+v", migrations, wantMigrations)
+	}
+	for i := range wantMigrations {
+		if migrations[i] != wantMigrations[i] {
+			t.Errorf("migrations[%d] = %+v, want %+v", i, migrations[i], wantMigrations[i])
+		}
+	}
+}
+
+func TestMigrateRuleCodes_NoDeprecatedCodes(t *testing.T) {
+	t.Parallel()
+
+	input := "[rules.tally.max-lines]\nmax = 100\n"
+	got, migrations, err := MigrateRuleCodes([]byte(input))
+	if err != nil {
+		t.Fatalf("MigrateRuleCodes() error = %v", err)
+	}
+	if string(got) != input || len(migrations) != 0 {
+		t.Errorf("MigrateRuleCodes() = %q, %+v; want input unchanged", got, migrations)
+	}
+}
+
+func TestMigrateRuleCodes_InvalidTOML(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := MigrateRuleCodes([]byte("[rules\n")); err == nil {
+		t.Fatal("MigrateRuleCodes() error = nil, want parse error")
+	}
+}
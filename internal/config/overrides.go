@@ -1,11 +1,8 @@
 package config
 
 import (
-	"github.com/knadh/koanf/parsers/toml/v2"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/env/v2"
-	"github.com/knadh/koanf/providers/file"
-	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
 )
 
@@ -47,6 +44,9 @@ func normalizeConfigurationPreference(p ConfigurationPreference) ConfigurationPr
 // - editorFirst: defaults → filesystem config → env → overrides
 // - filesystemFirst: defaults → overrides → filesystem config → env
 // - editorOnly: defaults → env → overrides (filesystem discovery skipped)
+//
+// A profile selected by any source sits between the defaults and the first
+// source.
 func LoadWithOverrides(targetPath string, overrides map[string]any, preference ConfigurationPreference) (*Config, error) {
 	preference = normalizeConfigurationPreference(preference)
 
@@ -54,7 +54,12 @@ func LoadWithOverrides(targetPath string, overrides map[string]any, preference C
 	if preference != ConfigurationPreferenceEditorOnly {
 		configPath = Discover(targetPath)
 	}
-	return loadWithConfigPathAndOverrides(configPath, overrides, preference)
+	cfg, err := loadWithConfigPathAndOverrides(configPath, overrides, preference)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ScopeToFile(targetPath)
+	return cfg, nil
 }
 
 func loadWithConfigPathAndOverrides(
@@ -64,59 +69,32 @@ func loadWithConfigPathAndOverrides(
 ) (*Config, error) {
 	preference = normalizeConfigurationPreference(preference)
 
-	k := koanf.New(".")
-
-	// 1) Defaults
-	if err := k.Load(structs.Provider(Default(), "koanf"), nil); err != nil {
-		return nil, err
-	}
-
-	// 2) Apply sources in configured precedence order.
-	switch preference {
-	case ConfigurationPreferenceEditorOnly:
-		if err := loadEnv(k); err != nil {
-			return nil, err
-		}
-		if err := loadOverrides(k, overrides); err != nil {
-			return nil, err
-		}
-	case ConfigurationPreferenceFilesystemFirst:
-		if err := loadOverrides(k, overrides); err != nil {
-			return nil, err
-		}
-		if err := loadConfigFile(k, configPath); err != nil {
-			return nil, err
-		}
-		if err := loadEnv(k); err != nil {
-			return nil, err
-		}
-	case ConfigurationPreferenceEditorFirst:
-		if err := loadConfigFile(k, configPath); err != nil {
-			return nil, err
+	// Apply sources in configured precedence order.
+	return loadLayered(configPath, func(k *koanf.Koanf) error {
+		switch preference {
+		case ConfigurationPreferenceEditorOnly:
+			if err := loadEnv(k); err != nil {
+				return err
+			}
+			return loadOverrides(k, overrides)
+		case ConfigurationPreferenceFilesystemFirst:
+			if err := loadOverrides(k, overrides); err != nil {
+				return err
+			}
+			if err := loadConfigFile(k, configPath); err != nil {
+				return err
+			}
+			return loadEnv(k)
+		default:
+			if err := loadConfigFile(k, configPath); err != nil {
+				return err
+			}
+			if err := loadEnv(k); err != nil {
+				return err
+			}
+			return loadOverrides(k, overrides)
 		}
-		if err := loadEnv(k); err != nil {
-			return nil, err
-		}
-		if err := loadOverrides(k, overrides); err != nil {
-			return nil, err
-		}
-	}
-
-	// 3) Validate merged raw config and decode.
-	cfg, err := decodeConfig(k.Raw())
-	if err != nil {
-		return nil, err
-	}
-
-	cfg.ConfigFile = configPath
-	return cfg, nil
-}
-
-func loadConfigFile(k *koanf.Koanf, configPath string) error {
-	if configPath == "" {
-		return nil
-	}
-	return k.Load(file.Provider(configPath), toml.Parser())
+	})
 }
 
 func loadEnv(k *koanf.Koanf) error {
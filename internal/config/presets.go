@@ -0,0 +1,82 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+// Profile names accepted by the profile key and --profile flag.
+const (
+	ProfileRecommended    = "recommended"
+	ProfileStrict         = "strict"
+	ProfileMinimal        = "minimal"
+	ProfileHadolintCompat = "hadolint-compat"
+)
+
+// Profiles lists the built-in profiles in documentation order.
+var Profiles = []string{ProfileRecommended, ProfileStrict, ProfileMinimal, ProfileHadolintCompat}
+
+//go:embed presets/*.toml
+var presetFS embed.FS
+
+// loadProfile layers the named profile onto k. Profiles sit between the
+// built-in defaults and the config file, so any setting in the file, the
+// environment, or on the command line overrides the profile.
+func loadProfile(k *koanf.Koanf, name string) (*profileRules, error) {
+	if !slices.Contains(Profiles, name) {
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(Profiles, ", "))
+	}
+	data, err := presetFS.ReadFile("presets/" + name + ".toml")
+	if err != nil {
+		return nil, fmt.Errorf("read profile %q: %w", name, err)
+	}
+	raw, err := toml.Parser().Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse profile %q: %w", name, err)
+	}
+	layer := koanf.New(".")
+	if err := layer.Load(confmap.Provider(raw, ""), nil); err != nil {
+		return nil, fmt.Errorf("load profile %q: %w", name, err)
+	}
+	if err := k.Merge(layer); err != nil {
+		return nil, fmt.Errorf("load profile %q: %w", name, err)
+	}
+	return &profileRules{
+		include: layer.Strings("rules.include"),
+		exclude: layer.Strings("rules.exclude"),
+	}, nil
+}
+
+// profileRules holds a profile's rule selection lists. Lists are replaced,
+// not merged, when a later layer sets the same key, so they are kept aside
+// and added back after all layers are loaded.
+type profileRules struct {
+	include []string
+	exclude []string
+}
+
+// apply adds the profile's selection lists to rc. Config patterns of equal
+// specificity override them, so a rule the profile excludes can still be
+// enabled with rules.include.
+func (p *profileRules) apply(rc *RulesConfig) {
+	if p == nil {
+		return
+	}
+	rc.Include = rc.addSelection(rc.Include, true, SelectionProfile, p.include)
+	rc.Exclude = rc.addSelection(rc.Exclude, false, SelectionProfile, p.exclude)
+}
+
+func appendMissing(dst, src []string) []string {
+	for _, s := range src {
+		if !slices.Contains(dst, s) {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
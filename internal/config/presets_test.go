@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+	_ "github.com/wharflab/tally/internal/rules/all"
+	"github.com/wharflab/tally/internal/rules/buildkit"
+)
+
+// TestProfilesReferenceKnownRules guards against typos and renamed rules in
+// the embedded profiles.
+func TestProfilesReferenceKnownRules(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range config.Profiles {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.LoadWithOverrides("", map[string]any{"profile": name}, config.ConfigurationPreferenceEditorOnly)
+			if err != nil {
+				t.Fatalf("load profile: %v", err)
+			}
+			for _, code := range profileRuleCodes(&cfg.Rules) {
+				if !isKnownRule(code) {
+					t.Errorf("profile %s references unknown rule %s", name, code)
+				}
+			}
+		})
+	}
+}
+
+// TestMinimalProfileExcludesSuggestions keeps the minimal profile in step
+// with new style and info rules.
+func TestMinimalProfileExcludesSuggestions(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.LoadWithOverrides("", map[string]any{"profile": config.ProfileMinimal},
+		config.ConfigurationPreferenceEditorOnly)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	for _, rule := range rules.All() {
+		meta := rule.Metadata()
+		if meta.DefaultSeverity != rules.SeverityStyle && meta.DefaultSeverity != rules.SeverityInfo {
+			continue
+		}
+		if enabled := cfg.Rules.IsEnabled(meta.Code); enabled == nil || *enabled {
+			t.Errorf("minimal profile does not exclude %s (%s)", meta.Code, meta.DefaultSeverity)
+		}
+	}
+}
+
+func profileRuleCodes(rc *config.RulesConfig) []string {
+	var codes []string
+	for _, pattern := range slices.Concat(rc.Include, rc.Exclude) {
+		if !strings.HasSuffix(pattern, "/*") {
+			codes = append(codes, pattern)
+		}
+	}
+	for ns, entries := range map[string]map[string]config.RuleConfig{
+		"tally":    rc.Tally,
+		"buildkit": rc.Buildkit,
+		"hadolint": rc.Hadolint,
+	} {
+		for name := range entries {
+			codes = append(codes, ns+"/"+name)
+		}
+	}
+	return codes
+}
+
+func isKnownRule(code string) bool {
+	if name, ok := strings.CutPrefix(code, rules.BuildKitRulePrefix); ok && buildkit.Get(name) != nil {
+		return true
+	}
+	return slices.ContainsFunc(rules.All(), func(r rules.Rule) bool {
+		return r.Metadata().Code == code
+	})
+}
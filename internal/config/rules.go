@@ -1,10 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"maps"
 	"slices"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
+
 	"github.com/wharflab/tally/internal/ruledeprecation"
 	"github.com/wharflab/tally/internal/rules/configutil"
 )
@@ -35,6 +38,7 @@ const (
 //	[rules.tally.max-lines]
 //	severity = "warning"
 //	fix = "always"
+//	fix-safety = "safe"
 //	# Rule-specific options are flattened at this level
 //	max = 100
 //	skip-blank-lines = true
@@ -47,9 +51,23 @@ type RuleConfig struct {
 	// Values: never, explicit, always (default), unsafe-only.
 	Fix FixMode `json:"fix,omitempty" koanf:"fix"`
 
+	// FixSafety overrides the safety of this rule's fixes, so a trusted fix
+	// can be applied with --fix or a risky one held back for --fix-unsafe.
+	// Values: safe, suggestion, unsafe. Empty keeps the rule's own safety.
+	FixSafety string `json:"fix-safety,omitempty" koanf:"fix-safety"`
+
 	// Exclude contains path patterns where this rule should not run.
 	Exclude ExcludeConfig `json:"exclude" koanf:"exclude"`
 
+	// Paths restricts the rule to Dockerfiles matching these glob patterns,
+	// relative to the config file's directory. Empty means every file.
+	Paths []string `json:"paths,omitempty" koanf:"paths"`
+
+	// ExcludePaths disables the rule for Dockerfiles matching these glob
+	// patterns, relative to the config file's directory. It takes
+	// precedence over Paths.
+	ExcludePaths []string `json:"exclude-paths,omitempty" koanf:"exclude-paths"`
+
 	// Options contains rule-specific configuration options.
 	Options map[string]any `json:"-" koanf:",remain"`
 }
@@ -82,6 +100,11 @@ type RulesConfig struct {
 	// Exclude explicitly disables rules.
 	Exclude []string `json:"exclude,omitempty" koanf:"exclude"`
 
+	// Timeout is the per-rule execution budget as a Go duration string
+	// (e.g. "200ms"). A rule that exceeds it on a file is skipped and
+	// reported by tally/rule-timeout. "0" (the default) disables the budget.
+	Timeout string `json:"timeout,omitempty" koanf:"timeout"`
+
 	// Tally contains configuration for tally/* rules.
 	Tally map[string]RuleConfig `json:"tally,omitempty" koanf:"tally"`
 
@@ -96,6 +119,22 @@ type RulesConfig struct {
 
 	// Powershell contains configuration for powershell/* rules.
 	Powershell map[string]RuleConfig `json:"powershell,omitempty" koanf:"powershell"`
+
+	// sources records the layer of Include/Exclude patterns that did not
+	// come from the config file. See Selection.
+	sources map[selectionKey]SelectionSource
+
+	// scopePath is the linted file relative to the config file's directory,
+	// set by Config.ScopeToFile. Rules whose paths / exclude-paths do not
+	// admit it are disabled.
+	scopePath string
+	scoped    bool
+}
+
+// selectionKey identifies an Include (include=true) or Exclude pattern.
+type selectionKey struct {
+	include bool
+	pattern string
 }
 
 // Get returns the configuration for a specific rule.
@@ -126,97 +165,268 @@ func parseRuleCode(ruleCode string) (string, string) {
 	return "", ruleCode
 }
 
+// SelectionSource is the layer a rule selection pattern comes from. When two
+// patterns of equal specificity match a rule, the later layer wins.
+type SelectionSource int
+
+const (
+	// SelectionProfile is a pattern from the selected profile.
+	SelectionProfile SelectionSource = iota
+	// SelectionConfig is a pattern from rules.include / rules.exclude.
+	SelectionConfig
+	// SelectionCommandLine is a pattern from --select / --ignore.
+	SelectionCommandLine
+)
+
+// String returns the name of the source as shown by "tally rules resolve".
+func (s SelectionSource) String() string {
+	switch s {
+	case SelectionProfile:
+		return "profile"
+	case SelectionCommandLine:
+		return "command line"
+	default:
+		return "config"
+	}
+}
+
+// RuleSelection is the include or exclude pattern that decides whether a
+// rule is enabled.
+type RuleSelection struct {
+	Pattern string
+	Enabled bool
+	Source  SelectionSource
+
+	specificity int
+}
+
+// exactSpecificity ranks exact rule codes above any namespace wildcard.
+const exactSpecificity = 1 << 16
+
+// beats reports whether s takes precedence over other: the more specific
+// pattern wins, then the later source, then include over exclude.
+func (s RuleSelection) beats(other RuleSelection) bool {
+	if s.specificity != other.specificity {
+		return s.specificity > other.specificity
+	}
+	if s.Source != other.Source {
+		return s.Source > other.Source
+	}
+	return s.Enabled && !other.Enabled
+}
+
+// Select adds include patterns from the command line (--select). They
+// override config and profile patterns of equal specificity.
+func (rc *RulesConfig) Select(patterns ...string) {
+	rc.Include = rc.addSelection(rc.Include, true, SelectionCommandLine, patterns)
+}
+
+// Ignore adds exclude patterns from the command line (--ignore). They
+// override config and profile patterns of equal specificity.
+func (rc *RulesConfig) Ignore(patterns ...string) {
+	rc.Exclude = rc.addSelection(rc.Exclude, false, SelectionCommandLine, patterns)
+}
+
+// addSelection appends patterns to list, recording their source. A pattern
+// already in the list keeps its place and moves to the later source.
+func (rc *RulesConfig) addSelection(list []string, include bool, source SelectionSource, patterns []string) []string {
+	for _, pattern := range patterns {
+		if slices.Contains(list, pattern) {
+			if source < rc.selectionSource(include, pattern) {
+				continue
+			}
+		} else {
+			list = append(list, pattern)
+		}
+		if rc.sources == nil {
+			rc.sources = make(map[selectionKey]SelectionSource)
+		}
+		rc.sources[selectionKey{include: include, pattern: pattern}] = source
+	}
+	return list
+}
+
+func (rc *RulesConfig) selectionSource(include bool, pattern string) SelectionSource {
+	if source, ok := rc.sources[selectionKey{include: include, pattern: pattern}]; ok {
+		return source
+	}
+	return SelectionConfig
+}
+
 // IsEnabled checks if a rule is enabled based on Include/Exclude patterns.
 // Returns nil if no configuration specifies enabled/disabled (use rule default).
-// Include takes precedence over Exclude (Ruff-style semantics).
+// See Selection for how conflicting patterns are resolved.
 func (rc *RulesConfig) IsEnabled(ruleCode string) *bool {
 	if rc == nil {
 		return nil
 	}
 
-	// Check Include first (takes precedence)
-	if matchesAnyIncludePattern(ruleCode, rc.Include) ||
-		matchesAnyDeprecatedExactPattern(ruleCode, rc.Include) {
-		return new(true)
-	}
-
-	// Check Exclude
-	if matchesAnyPattern(ruleCode, rc.Exclude) ||
-		matchesAnyDeprecatedExactPattern(ruleCode, rc.Exclude) {
+	// Path scoping wins over selection: a rule limited to other files is
+	// off here even when included.
+	if !rc.InScope(ruleCode) {
 		return new(false)
 	}
 
-	// No explicit config - use rule default
-	return nil
-}
-
-func matchesAnyDeprecatedExactPattern(ruleCode string, patterns []string) bool {
-	aliases := ruledeprecation.DeprecatedCodesFor(ruleCode)
-	if len(aliases) == 0 {
-		return false
+	sel, ok := rc.Selection(ruleCode)
+	if !ok {
+		// No explicit config - use rule default
+		return nil
 	}
-	return slices.ContainsFunc(patterns, func(pattern string) bool {
-		return slices.Contains(aliases, pattern)
-	})
+	return new(sel.Enabled)
 }
 
-// matchesAnyPattern checks if ruleCode matches any pattern in the list.
-// Patterns can be:
-// - Exact match: "buildkit/StageNameCasing"
-// - Namespace wildcard: "buildkit/*"
-func matchesAnyPattern(ruleCode string, patterns []string) bool {
-	return slices.ContainsFunc(patterns, func(pattern string) bool {
-		return matchesPattern(ruleCode, pattern)
-	})
+// Selection returns the Include/Exclude pattern that decides whether
+// ruleCode is enabled, and false when no pattern matches it.
+//
+// The most specific matching pattern wins: an exact rule code beats a
+// namespace wildcard, "tally/runtime/*" beats "tally/*", and any namespace
+// beats "*". Between patterns of equal specificity the later source wins
+// (profile, then config, then --select / --ignore), and within one source
+// an include beats an exclude.
+func (rc *RulesConfig) Selection(ruleCode string) (RuleSelection, bool) {
+	if rc == nil {
+		return RuleSelection{}, false
+	}
+
+	var best RuleSelection
+	found := false
+	consider := func(patterns []string, include bool) {
+		for _, pattern := range patterns {
+			specificity, ok := selectionSpecificity(ruleCode, pattern, include)
+			if !ok {
+				continue
+			}
+			sel := RuleSelection{
+				Pattern:     pattern,
+				Enabled:     include,
+				Source:      rc.selectionSource(include, pattern),
+				specificity: specificity,
+			}
+			if !found || sel.beats(best) {
+				best, found = sel, true
+			}
+		}
+	}
+	consider(rc.Exclude, false)
+	consider(rc.Include, true)
+	return best, found
 }
 
-// matchesAnyIncludePattern checks if ruleCode matches any include pattern.
-// It also applies ShellCheck include coupling so selecting the engine enables
-// all derived findings, and selecting any specific SC rule enables the engine.
-// PowerShell follows the same engine/derived-finding shape.
-func matchesAnyIncludePattern(ruleCode string, patterns []string) bool {
-	return slices.ContainsFunc(patterns, func(pattern string) bool {
-		if matchesPattern(ruleCode, pattern) {
-			return true
-		}
-		if pattern == "shellcheck/ShellCheck" {
-			return strings.HasPrefix(ruleCode, "shellcheck/")
-		}
-		if ruleCode == "shellcheck/ShellCheck" {
-			return strings.HasPrefix(pattern, "shellcheck/SC")
-		}
-		if pattern == "powershell/PowerShell" {
-			return strings.HasPrefix(ruleCode, "powershell/")
-		}
-		if ruleCode == "powershell/PowerShell" {
-			name, ok := strings.CutPrefix(pattern, "powershell/")
-			return ok && isPowerShellAnalyzerRuleName(name)
-		}
-		return false
-	})
+// selectionSpecificity reports whether pattern selects ruleCode and how
+// specific the match is. Deprecated rule codes match their replacement
+// exactly. Include patterns also apply ShellCheck coupling so selecting the
+// engine enables all derived findings, and selecting any specific SC rule
+// enables the engine. PowerShell follows the same engine/derived-finding
+// shape.
+func selectionSpecificity(ruleCode, pattern string, include bool) (int, bool) {
+	if specificity, ok := patternSpecificity(ruleCode, pattern); ok {
+		return specificity, true
+	}
+	if slices.Contains(ruledeprecation.DeprecatedCodesFor(ruleCode), pattern) {
+		return exactSpecificity, true
+	}
+	if !include {
+		return 0, false
+	}
+	switch {
+	case pattern == "shellcheck/ShellCheck":
+		return 1, strings.HasPrefix(ruleCode, "shellcheck/")
+	case ruleCode == "shellcheck/ShellCheck":
+		return exactSpecificity, strings.HasPrefix(pattern, "shellcheck/SC")
+	case pattern == "powershell/PowerShell":
+		return 1, strings.HasPrefix(ruleCode, "powershell/")
+	case ruleCode == "powershell/PowerShell":
+		name, ok := strings.CutPrefix(pattern, "powershell/")
+		return exactSpecificity, ok && isPowerShellAnalyzerRuleName(name)
+	}
+	return 0, false
 }
 
 // matchesPattern checks if ruleCode matches a single pattern.
+// Patterns can be:
+// - Exact match: "buildkit/StageNameCasing"
+// - Namespace wildcard: "buildkit/*", "tally/runtime/*"
+// - Universal wildcard: "*"
 func matchesPattern(ruleCode, pattern string) bool {
+	_, ok := patternSpecificity(ruleCode, pattern)
+	return ok
+}
+
+// patternSpecificity matches ruleCode against pattern. Exact matches rank
+// highest, namespace wildcards by their depth, and "*" lowest.
+func patternSpecificity(ruleCode, pattern string) (int, bool) {
 	// Universal wildcard matches everything
 	if pattern == "*" {
-		return true
+		return 0, true
 	}
 
 	// Exact match
 	if ruleCode == pattern {
-		return true
+		return exactSpecificity, true
 	}
 
 	// Namespace wildcard: "buildkit/*" matches "buildkit/StageNameCasing"
-	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
-		ns, _ := parseRuleCode(ruleCode)
-		return ns == prefix
+	// and "tally/*" matches "tally/runtime/invalid-stopsignal"
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasSuffix(prefix, "/") {
+		return strings.Count(prefix, "/"), strings.HasPrefix(ruleCode, prefix)
 	}
 
+	return 0, false
+}
+
+// FixPrecedenceRank returns the conflict precedence of ruleCode's fixes for
+// the given fix-precedence patterns. Rules matching an earlier pattern rank
+// higher; rules matching none rank 0.
+func FixPrecedenceRank(patterns []string, ruleCode string) int {
+	for i, pattern := range patterns {
+		if matchesPattern(ruleCode, pattern) {
+			return len(patterns) - i
+		}
+	}
+	return 0
+}
+
+// Supersedes reports whether violations of ruleCode replace those of other
+// on the same line. The duplicate-precedence entry for ruleCode, when
+// configured, replaces the rule's built-in list.
+func Supersedes(precedence map[string][]string, builtin []string, ruleCode, other string) bool {
+	patterns, ok := precedence[ruleCode]
+	if !ok {
+		patterns = builtin
+	}
+	for _, pattern := range patterns {
+		if matchesPattern(other, pattern) {
+			return true
+		}
+	}
 	return false
 }
 
+// Severity returns the configured severity of ruleCode for a stage role
+// ("final" or "builder"), or "" when none applies. An exact rule code wins
+// over a namespace wildcard, which wins over "*".
+func (c SeverityByStageRoleConfig) Severity(role, ruleCode string) string {
+	var byRule map[string]string
+	switch role {
+	case "final":
+		byRule = c.Final
+	case "builder":
+		byRule = c.Builder
+	}
+	if len(byRule) == 0 {
+		return ""
+	}
+	if sev, ok := byRule[ruleCode]; ok {
+		return sev
+	}
+	if ns, _ := parseRuleCode(ruleCode); ns != "" {
+		if sev, ok := byRule[ns+"/*"]; ok {
+			return sev
+		}
+	}
+	return byRule["*"]
+}
+
 // GetSeverity returns the severity override for a rule.
 // Returns empty string if no override is configured.
 func (rc *RulesConfig) GetSeverity(ruleCode string) string {
@@ -241,6 +451,18 @@ func (rc *RulesConfig) GetFixMode(ruleCode string) FixMode {
 	return FixModeAlways
 }
 
+// GetFixSafety returns the fix safety override for a rule.
+// Returns empty string if no override is configured.
+func (rc *RulesConfig) GetFixSafety(ruleCode string) string {
+	if rc == nil {
+		return ""
+	}
+	if cfg := rc.Get(ruleCode); cfg != nil && cfg.FixSafety != "" {
+		return cfg.FixSafety
+	}
+	return ""
+}
+
 // GetExcludePaths returns the exclusion patterns for a rule.
 func (rc *RulesConfig) GetExcludePaths(ruleCode string) []string {
 	if rc == nil {
@@ -257,6 +479,54 @@ func (rc *RulesConfig) GetExcludePaths(ruleCode string) []string {
 	return nil
 }
 
+// InScope reports whether the rule's paths and exclude-paths admit the file
+// the config was scoped to with Config.ScopeToFile. Unscoped configs admit
+// every rule.
+func (rc *RulesConfig) InScope(ruleCode string) bool {
+	if rc == nil || !rc.scoped {
+		return true
+	}
+	cfg := rc.Get(ruleCode)
+	if cfg == nil {
+		return true
+	}
+	if matchesAnyPath(rc.scopePath, cfg.ExcludePaths) {
+		return false
+	}
+	return len(cfg.Paths) == 0 || matchesAnyPath(rc.scopePath, cfg.Paths)
+}
+
+// validatePathPatterns rejects malformed paths / exclude-paths globs.
+func (rc *RulesConfig) validatePathPatterns() error {
+	namespaces := map[string]map[string]RuleConfig{
+		"tally":      rc.Tally,
+		"buildkit":   rc.Buildkit,
+		"hadolint":   rc.Hadolint,
+		"shellcheck": rc.Shellcheck,
+		"powershell": rc.Powershell,
+	}
+	for _, ns := range slices.Sorted(maps.Keys(namespaces)) {
+		for _, name := range slices.Sorted(maps.Keys(namespaces[ns])) {
+			cfg := namespaces[ns][name]
+			for _, pattern := range slices.Concat(cfg.Paths, cfg.ExcludePaths) {
+				if !doublestar.ValidatePattern(pattern) {
+					return fmt.Errorf("rule %s/%s: invalid path pattern %q", ns, name, pattern)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchesAnyPath reports whether path matches any doublestar glob pattern.
+// Invalid patterns never match; they are rejected when the config loads.
+func matchesAnyPath(path string, patterns []string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, err := doublestar.Match(pattern, path)
+		return err == nil && matched
+	})
+}
+
 // GetOptions returns rule-specific options.
 // Returns nil if no options are configured.
 // Returns a shallow copy to prevent mutation of internal state.
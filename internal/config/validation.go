@@ -65,6 +65,9 @@ func decodeRulesConfig(raw map[string]any) (RulesConfig, error) {
 	if err := normalized.Unmarshal("", &rulesCfg); err != nil {
 		return RulesConfig{}, fmt.Errorf("decode rule config: %w", err)
 	}
+	if err := rulesCfg.validatePathPatterns(); err != nil {
+		return RulesConfig{}, err
+	}
 	return rulesCfg, nil
 }
 
@@ -76,10 +79,14 @@ func configFromSchema(schemaCfg *generatedconfig.TallyConfigSchemaJson) *Config
 
 	if output := schemaCfg.Output; output != nil {
 		cfg.Output = OutputConfig{
-			Format:     string(output.Format),
-			Path:       output.Path,
-			ShowSource: output.ShowSource,
-			FailLevel:  string(output.FailLevel),
+			Format:          string(output.Format),
+			Path:            output.Path,
+			ShowSource:      output.ShowSource,
+			FailLevel:       string(output.FailLevel),
+			GroupBy:         string(output.GroupBy),
+			AnnotationLimit: output.AnnotationLimit,
+			ShowSuppressed:  output.ShowSuppressed,
+			ShowFixes:       output.ShowFixes,
 		}
 	}
 
@@ -99,6 +106,8 @@ func configFromSchema(schemaCfg *generatedconfig.TallyConfigSchemaJson) *Config
 			Timeout:       ai.Timeout,
 			MaxInputBytes: ai.MaxInputBytes,
 			RedactSecrets: ai.RedactSecrets,
+			Rules:         slices.Clone(ai.Rules),
+			Prompts:       maps.Clone(map[string]string(ai.Prompts)),
 		}
 	}
 
@@ -109,18 +118,70 @@ func configFromSchema(schemaCfg *generatedconfig.TallyConfigSchemaJson) *Config
 	}
 
 	cfg.UnsafeFixes = schemaCfg.UnsafeFixes
+	cfg.FixPrecedence = slices.Clone(schemaCfg.FixPrecedence)
+	if len(schemaCfg.DuplicatePrecedence) > 0 {
+		cfg.DuplicatePrecedence = make(map[string][]string, len(schemaCfg.DuplicatePrecedence))
+		for code, replaced := range schemaCfg.DuplicatePrecedence {
+			cfg.DuplicatePrecedence[code] = slices.Clone(replaced)
+		}
+	}
+
+	if byRole := schemaCfg.SeverityByStageRole; byRole != nil {
+		cfg.SeverityByStageRole = SeverityByStageRoleConfig{
+			Final:   stringMap(byRole.Final),
+			Builder: stringMap(byRole.Builder),
+		}
+	}
+
+	cfg.Extends = slices.Clone(schemaCfg.Extends)
+
+	if schemaCfg.Profile != nil {
+		cfg.Profile = string(*schemaCfg.Profile)
+	}
+	cfg.Dialect = string(schemaCfg.Dialect)
+	cfg.BuildArgs = maps.Clone(map[string]string(schemaCfg.BuildArgs))
+
+	if registries := schemaCfg.Registries; registries != nil {
+		cfg.Registries = RegistriesConfig{
+			Trusted: slices.Clone(registries.Trusted),
+			Mirrors: maps.Clone(map[string]string(registries.Mirrors)),
+		}
+	}
 
 	if slowChecks := schemaCfg.SlowChecks; slowChecks != nil {
 		cfg.SlowChecks = SlowChecksConfig{
 			Mode:     string(slowChecks.Mode),
 			FailFast: slowChecks.FailFast,
 			Timeout:  slowChecks.Timeout,
+			CacheTTL: slowChecks.CacheTtl,
+		}
+		if len(slowChecks.Registries) > 0 {
+			cfg.SlowChecks.Registries = make(map[string]RegistryConfig, len(slowChecks.Registries))
+			for host, reg := range slowChecks.Registries {
+				rc := RegistryConfig{Insecure: reg.Insecure}
+				if reg.CertsDir != nil {
+					rc.CertsDir = *reg.CertsDir
+				}
+				cfg.SlowChecks.Registries[host] = rc
+			}
 		}
 	}
 
 	return cfg
 }
 
+// stringMap converts a generated map with string-typed enum values.
+func stringMap[V ~string](m map[string]V) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = string(v)
+	}
+	return out
+}
+
 func validateAndNormalize(raw map[string]any) error {
 	normalizeCompatibilityAliases(raw)
 	normalizeRuleShorthand(raw)
@@ -191,7 +252,7 @@ func isRuleConfigEntry(namespace, ruleName string, entry map[string]any) bool {
 	if _, ok := schemasembed.RuleSchemaID(namespace + "/" + ruleName); ok {
 		return true
 	}
-	return entryHasAny(entry, "severity", "fix", "exclude")
+	return entryHasAny(entry, "severity", "fix", "fix-safety", "exclude", "paths", "exclude-paths")
 }
 
 func hasRuleOptionShape(entry map[string]any) bool {
@@ -303,6 +364,7 @@ func normalizeLegacyTallyRules(raw map[string]any) {
 	reserved := map[string]struct{}{
 		"include": {},
 		"exclude": {},
+		"timeout": {},
 	}
 	for _, ns := range schemasembed.RuleNamespaces() {
 		reserved[ns] = struct{}{}
@@ -332,7 +394,10 @@ func optionsFromRuleEntry(entry any) map[string]any {
 	maps.Copy(options, obj)
 	delete(options, "severity")
 	delete(options, "fix")
+	delete(options, "fix-safety")
 	delete(options, "exclude")
+	delete(options, "paths")
+	delete(options, "exclude-paths")
 	if len(options) == 0 {
 		return nil
 	}
@@ -4,8 +4,11 @@ package context
 
 import (
 	"errors"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 
@@ -41,6 +44,13 @@ type BuildContext struct {
 	// patterns stores the raw patterns for debugging
 	patterns []string
 
+	// ignoreFile is the path of the ignore file patterns were read from.
+	ignoreFile string
+
+	// caseInsensitive matches ignore patterns without regard to case, as
+	// Windows builders do.
+	caseInsensitive bool
+
 	// fileCache stores lazily read build-context files by normalized relative path.
 	fileCache map[string]cachedFile
 
@@ -69,6 +79,15 @@ func WithHeredocFiles(files map[string]bool) Option {
 	}
 }
 
+// WithCaseInsensitiveMatching sets whether .dockerignore patterns match paths
+// regardless of case. It defaults to true on Windows, whose file systems and
+// builders treat paths case-insensitively.
+func WithCaseInsensitiveMatching(insensitive bool) Option {
+	return func(ctx *BuildContext) {
+		ctx.caseInsensitive = insensitive
+	}
+}
+
 // New creates a new BuildContext for the given context directory.
 // The dockerfilePath is used for relative path calculations.
 func New(contextDir, dockerfilePath string, opts ...Option) (*BuildContext, error) {
@@ -92,6 +111,8 @@ func New(contextDir, dockerfilePath string, opts ...Option) (*BuildContext, erro
 		fileCache:      make(map[string]cachedFile),
 		lstat:          os.Lstat,
 		readFile:       os.ReadFile,
+
+		caseInsensitive: runtime.GOOS == "windows",
 	}
 
 	for _, opt := range opts {
@@ -115,11 +136,79 @@ func (ctx *BuildContext) IsIgnored(path string) (bool, error) {
 		return false, nil
 	}
 
-	// Normalize path separators
-	path = filepath.ToSlash(path)
-
 	// Use MatchesOrParentMatches for correct directory matching
-	return ctx.patternMatcher.MatchesOrParentMatches(path)
+	return ctx.patternMatcher.MatchesOrParentMatches(ctx.matchPath(path))
+}
+
+// HasIncludedDescendant reports whether an ignored directory still
+// contributes files to the build because a negation pattern (e.g.
+// "!vendor/keep") re-includes something below it. The path should be
+// relative to the build context.
+func (ctx *BuildContext) HasIncludedDescendant(dir string) bool {
+	if err := ctx.ensureInitialized(); err != nil {
+		return false
+	}
+
+	ctx.mu.RLock()
+	pm := ctx.patternMatcher
+	ctx.mu.RUnlock()
+	if pm == nil || !pm.Exclusions() {
+		return false
+	}
+
+	_, root, err := ctx.resolveExistingPath(dir, false)
+	if err != nil {
+		return false
+	}
+
+	found := false
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		rel, relErr := filepath.Rel(ctx.ContextDir, p)
+		if relErr != nil {
+			return nil //nolint:nilerr // Skip paths that cannot be made relative.
+		}
+		if ignored, matchErr := pm.MatchesOrParentMatches(ctx.matchPath(rel)); matchErr == nil && !ignored {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// Glob returns the context-relative paths matching a COPY/ADD source
+// wildcard, sorted lexically. Matches that are ignored by .dockerignore are
+// included; use IsIgnored to filter them.
+func (ctx *BuildContext) Glob(pattern string) ([]string, error) {
+	normalized := filepath.Clean(filepath.FromSlash(pattern))
+	if filepath.IsAbs(normalized) || normalized == ".." || strings.HasPrefix(normalized, ".."+string(filepath.Separator)) {
+		return nil, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(ctx.ContextDir, normalized))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		rel, err := filepath.Rel(ctx.ContextDir, match)
+		if err != nil {
+			continue
+		}
+		// Drops matches outside the context and through symlinks.
+		if _, _, err := ctx.resolveExistingPath(rel, false); err != nil {
+			continue
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths, nil
 }
 
 // FileExists checks if a file exists in the build context.
@@ -182,6 +271,72 @@ func (ctx *BuildContext) ReadFile(path string) ([]byte, error) {
 	return content, nil
 }
 
+// OpenFile opens a regular file from the build context for reading. Unlike
+// ReadFile, the content is not cached, so callers scanning many files do not
+// keep them all in memory. The path must be relative to the context root.
+func (ctx *BuildContext) OpenFile(path string) (io.ReadCloser, error) {
+	_, fullPath, err := ctx.resolvePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fullPath)
+}
+
+// WalkFiles calls fn for each regular file at or below root that the build
+// sends, in lexical order: files ignored by .dockerignore are skipped, as are
+// symlinks. The path passed to fn is relative to the context root and uses
+// forward slashes. Returning filepath.SkipAll from fn stops the walk without
+// an error.
+func (ctx *BuildContext) WalkFiles(root string, fn func(path string, size int64) error) error {
+	if err := ctx.ensureInitialized(); err != nil {
+		return err
+	}
+
+	ctx.mu.RLock()
+	pm := ctx.patternMatcher
+	ctx.mu.RUnlock()
+
+	_, fullRoot, err := ctx.resolveExistingPath(root, false)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(fullRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(ctx.ContextDir, p)
+		if err != nil {
+			return err
+		}
+		if pm != nil && rel != "." {
+			ignored, err := pm.MatchesOrParentMatches(ctx.matchPath(rel))
+			if err != nil {
+				return err
+			}
+			if ignored {
+				// Negation patterns may still re-include files below an
+				// ignored directory, so only prune when there are none.
+				if d.IsDir() && !pm.Exclusions() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), info.Size())
+	})
+}
+
 // IsHeredocFile checks if a path is a virtual heredoc file.
 // Heredoc files are created inline in the Dockerfile and should
 // not be checked against .dockerignore.
@@ -201,6 +356,18 @@ func (ctx *BuildContext) AddHeredocFile(path string) {
 	ctx.heredocFiles[path] = true
 }
 
+// IgnoreFile returns the path of the ignore file in effect, or "" when the
+// build context has none.
+func (ctx *BuildContext) IgnoreFile() string {
+	if err := ctx.ensureInitialized(); err != nil {
+		return ""
+	}
+
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	return ctx.ignoreFile
+}
+
 // Patterns returns the .dockerignore patterns (for debugging).
 func (ctx *BuildContext) Patterns() []string {
 	if err := ctx.ensureInitialized(); err != nil {
@@ -222,18 +389,34 @@ func (ctx *BuildContext) ensureInitialized() error {
 	}
 
 	ctx.initialized = true
-	ctx.patterns, ctx.initErr = LoadDockerignore(ctx.ContextDir)
+	ctx.patterns, ctx.ignoreFile, ctx.initErr = LoadDockerignoreFor(ctx.ContextDir, ctx.DockerfilePath)
 	if ctx.initErr != nil {
 		return ctx.initErr
 	}
 
 	if len(ctx.patterns) > 0 {
-		ctx.patternMatcher, ctx.initErr = patternmatcher.New(ctx.patterns)
+		patterns := ctx.patterns
+		if ctx.caseInsensitive {
+			patterns = make([]string, len(ctx.patterns))
+			for i, p := range ctx.patterns {
+				patterns[i] = strings.ToLower(p)
+			}
+		}
+		ctx.patternMatcher, ctx.initErr = patternmatcher.New(patterns)
 	}
 
 	return ctx.initErr
 }
 
+// matchPath normalizes a context-relative path for pattern matching.
+func (ctx *BuildContext) matchPath(path string) string {
+	path = filepath.ToSlash(path)
+	if ctx.caseInsensitive {
+		path = strings.ToLower(path)
+	}
+	return path
+}
+
 func (ctx *BuildContext) resolvePath(path string) (string, string, error) {
 	return ctx.resolveExistingPath(path, true)
 }
@@ -3,6 +3,7 @@ package context
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -406,3 +407,202 @@ func TestContainerignore(t *testing.T) {
 		t.Error("expected .containerignore to be respected")
 	}
 }
+
+func TestPerDockerfileIgnoreFile(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".dockerignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dockerfile := filepath.Join(tmpDir, "build", "app.Dockerfile")
+	ignoreFile := dockerfile + ".dockerignore"
+	if err := os.WriteFile(ignoreFile, []byte("*.tmp\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := New(tmpDir, dockerfile)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if got := ctx.IgnoreFile(); got != ignoreFile {
+		t.Errorf("IgnoreFile() = %q, want %q", got, ignoreFile)
+	}
+	for path, want := range map[string]bool{"cache.tmp": true, "debug.log": false} {
+		ignored, err := ctx.IsIgnored(path)
+		if err != nil {
+			t.Fatalf("IsIgnored(%q) error: %v", path, err)
+		}
+		if ignored != want {
+			t.Errorf("IsIgnored(%q) = %v, want %v", path, ignored, want)
+		}
+	}
+
+	// Other Dockerfiles in the same context keep the root .dockerignore.
+	other, err := New(tmpDir, filepath.Join(tmpDir, "Dockerfile"))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if ignored, _ := other.IsIgnored("debug.log"); !ignored {
+		t.Error("expected root .dockerignore for a Dockerfile without its own ignore file")
+	}
+}
+
+func TestCaseInsensitiveMatching(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".dockerignore"), []byte("Secrets/\n!secrets/PUBLIC.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		insensitive bool
+		path        string
+		want        bool
+	}{
+		{false, "secrets/key.pem", false},
+		{false, "Secrets/key.pem", true},
+		{true, "secrets/key.pem", true},
+		{true, "SECRETS/key.pem", true},
+		{true, "secrets/public.txt", false},
+	}
+
+	for _, tc := range tests {
+		ctx, err := New(tmpDir, "", WithCaseInsensitiveMatching(tc.insensitive))
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+		ignored, err := ctx.IsIgnored(tc.path)
+		if err != nil {
+			t.Fatalf("IsIgnored(%q) error: %v", tc.path, err)
+		}
+		if ignored != tc.want {
+			t.Errorf("insensitive=%v: IsIgnored(%q) = %v, want %v", tc.insensitive, tc.path, ignored, tc.want)
+		}
+	}
+}
+
+func TestHasIncludedDescendant(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	for _, file := range []string{"vendor/keep/a.go", "vendor/drop/b.go", "build/out.bin"} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".dockerignore"), []byte("vendor\nbuild\n!vendor/keep\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := New(tmpDir, "")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if ignored, _ := ctx.IsIgnored("vendor"); !ignored {
+		t.Fatal("expected vendor to be ignored")
+	}
+	if !ctx.HasIncludedDescendant("vendor") {
+		t.Error("expected vendor/keep to be re-included")
+	}
+	if ctx.HasIncludedDescendant("build") {
+		t.Error("expected nothing under build to be included")
+	}
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	for _, file := range []string{"a.go", "b.go", "c.txt", "cmd/main.go"} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, err := New(tmpDir, "")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"a.go", "b.go"}},
+		{"cmd/*.go", []string{"cmd/main.go"}},
+		{"*.rs", []string{}},
+		{"../*", nil},
+	}
+	for _, tc := range tests {
+		got, err := ctx.Glob(tc.pattern)
+		if err != nil {
+			t.Fatalf("Glob(%q) error: %v", tc.pattern, err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("Glob(%q) = %v, want %v", tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestWalkFiles(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	for _, file := range []string{"app/main.go", "app/.env", "vendor/keep/a.go", "vendor/drop/b.go", "README.md"} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".dockerignore"), []byte("**/.env\nvendor\n!vendor/keep\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := New(tmpDir, "")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	tests := []struct {
+		root string
+		want []string
+	}{
+		{".", []string{".dockerignore", "README.md", "app/main.go", "vendor/keep/a.go"}},
+		{"app", []string{"app/main.go"}},
+		{"README.md", []string{"README.md"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		if err := ctx.WalkFiles(tc.root, func(path string, _ int64) error {
+			got = append(got, path)
+			return nil
+		}); err != nil {
+			t.Fatalf("WalkFiles(%q) error: %v", tc.root, err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("WalkFiles(%q) = %v, want %v", tc.root, got, tc.want)
+		}
+	}
+
+	if err := ctx.WalkFiles("../", func(string, int64) error { return nil }); err == nil {
+		t.Error("expected an error for a root outside the context")
+	}
+}
@@ -19,19 +19,41 @@ var dockerignoreNames = []string{
 // An empty ignore file is valid and means "ignore no files" - we don't fall through
 // to the next file in that case.
 func LoadDockerignore(contextDir string) ([]string, error) {
+	patterns, _, err := LoadDockerignoreFor(contextDir, "")
+	return patterns, err
+}
+
+// LoadDockerignoreFor reads the ignore patterns that apply when building
+// dockerfilePath with contextDir as build context. Like BuildKit, an ignore
+// file named after the Dockerfile (e.g. app.Dockerfile.dockerignore, or
+// <name>.containerignore for Podman) next to the Dockerfile takes precedence
+// over the ignore files at the context root.
+//
+// It also returns the path of the ignore file that was read, or "" when none
+// exists.
+func LoadDockerignoreFor(contextDir, dockerfilePath string) ([]string, string, error) {
+	var candidates []string
+	if dockerfilePath != "" {
+		for _, name := range dockerignoreNames {
+			candidates = append(candidates, dockerfilePath+name)
+		}
+	}
 	for _, name := range dockerignoreNames {
-		ignorePath := filepath.Join(contextDir, name)
+		candidates = append(candidates, filepath.Join(contextDir, name))
+	}
+
+	for _, ignorePath := range candidates {
 		patterns, err := loadIgnoreFile(ignorePath)
 		if err != nil {
 			if os.IsNotExist(err) {
 				continue
 			}
-			return nil, err
+			return nil, "", err
 		}
 		// Return patterns even if empty - an existing empty file is valid
-		return patterns, nil
+		return patterns, ignorePath, nil
 	}
-	return nil, nil
+	return nil, "", nil
 }
 
 // loadIgnoreFile reads patterns from a single ignore file.
@@ -0,0 +1,92 @@
+package directive
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// attributeTail matches the optional attribute list that follows a directive's
+// rule list, e.g. ";reason=legacy;expires=2025-12-31;owner=@platform".
+// The first attribute must use a known key so that arbitrary text after a
+// semicolon is not mistaken for attributes.
+const attributeTail = `(?:;((?:reason|expires|owner)\s*=.*))?$`
+
+// attributeKeyPattern matches the start of an attribute segment.
+var attributeKeyPattern = regexp.MustCompile(`(?i)^\s*(reason|expires|owner)\s*(=)\s*`)
+
+// attribute is one key=value pair from a directive's attribute list.
+// Offsets are byte offsets into the text passed to splitAttributes.
+type attribute struct {
+	Key        string // lowercase
+	KeyStart   int
+	KeyEnd     int
+	EqualStart int
+	ValueStart int
+	ValueEnd   int
+}
+
+// splitAttributes splits an attribute list into key=value pairs. Segments are
+// separated by ';'. A segment that does not start with a known key is part of
+// the previous value, so reasons may contain semicolons.
+func splitAttributes(s string) []attribute {
+	var attrs []attribute
+	offset := 0
+	for segment := range strings.SplitSeq(s, ";") {
+		if m := attributeKeyPattern.FindStringSubmatchIndex(segment); m != nil {
+			attrs = append(attrs, attribute{
+				Key:        strings.ToLower(segment[m[2]:m[3]]),
+				KeyStart:   offset + m[2],
+				KeyEnd:     offset + m[3],
+				EqualStart: offset + m[4],
+				ValueStart: offset + m[1],
+				ValueEnd:   offset + len(segment),
+			})
+		} else if len(attrs) > 0 {
+			attrs[len(attrs)-1].ValueEnd = offset + len(segment)
+		}
+		offset += len(segment) + 1
+	}
+
+	// Trim trailing whitespace from values.
+	for i := range attrs {
+		for attrs[i].ValueEnd > attrs[i].ValueStart && isSpace(s[attrs[i].ValueEnd-1]) {
+			attrs[i].ValueEnd--
+		}
+	}
+	return attrs
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// directiveAttributes holds the parsed attributes of a suppression directive.
+type directiveAttributes struct {
+	Reason  string
+	Expires time.Time
+	Owner   string
+}
+
+// parseAttributes parses an attribute list captured by attributeTail.
+// Returns an error message for a malformed expires date.
+func parseAttributes(s string) (directiveAttributes, error) {
+	var out directiveAttributes
+	for _, a := range splitAttributes(s) {
+		value := s[a.ValueStart:a.ValueEnd]
+		switch a.Key {
+		case "reason":
+			out.Reason = value
+		case "owner":
+			out.Owner = value
+		case "expires":
+			t, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return directiveAttributes{}, fmt.Errorf("invalid expires date %q: want YYYY-MM-DD", value)
+			}
+			out.Expires = t
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package directive
+
+import "regexp"
+
+// CompletionKind is what can be typed at the cursor of a directive comment.
+type CompletionKind int
+
+const (
+	// CompletionNone means the cursor is not in a tally directive.
+	CompletionNone CompletionKind = iota
+	// CompletionKeyword means the directive keyword after "# tally ".
+	CompletionKeyword
+	// CompletionRule means a rule code of the directive's rule list.
+	CompletionRule
+)
+
+// Keyword is a tally directive form offered by completion.
+type Keyword struct {
+	// Text is inserted after "# tally ".
+	Text string
+	// Detail describes what the directive does.
+	Detail string
+}
+
+// Keywords lists the tally directive forms, in the order they are offered.
+var Keywords = []Keyword{
+	{Text: "ignore=", Detail: "Suppress rules on the next instruction"},
+	{Text: "disable-next-instruction", Detail: "Suppress all rules, or the listed ones, on the whole next instruction"},
+	{Text: "disable-stage=", Detail: "Suppress rules in the build stage of the next instruction"},
+	{Text: "disable-file=", Detail: "Suppress rules in the whole file"},
+	{Text: "global ignore=", Detail: "Suppress rules in the whole file"},
+	{Text: "shell=", Detail: "Set the shell used to lint RUN instructions"},
+}
+
+var (
+	keywordCompletionPattern = regexp.MustCompile(`(?i)^\s*#\s*tally\s+([A-Za-z-]*)$`)
+	ruleCompletionPattern    = regexp.MustCompile(
+		`(?i)^\s*#\s*tally\s+(?:(?:global\s+)?ignore|disable-(?:next-instruction|file|stage))\s*=\s*` +
+			`(?:[A-Za-z0-9_./-]+\s*,\s*)*([A-Za-z0-9_./-]*)$`)
+)
+
+// CompletionAt reports what can be completed at the end of prefix, the text
+// of a line up to the cursor, and the byte offset in prefix where the word
+// being completed starts.
+func CompletionAt(prefix string) (CompletionKind, int) {
+	if m := ruleCompletionPattern.FindStringSubmatchIndex(prefix); m != nil {
+		return CompletionRule, m[2]
+	}
+	if m := keywordCompletionPattern.FindStringSubmatchIndex(prefix); m != nil {
+		return CompletionKeyword, m[2]
+	}
+	return CompletionNone, len(prefix)
+}
@@ -0,0 +1,37 @@
+package directive
+
+import "testing"
+
+func TestCompletionAt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prefix   string
+		want     CompletionKind
+		wantWord string
+	}{
+		{prefix: "# tally ", want: CompletionKeyword, wantWord: ""},
+		{prefix: "  #tally  disable-n", want: CompletionKeyword, wantWord: "disable-n"},
+		{prefix: "# tally ignore=", want: CompletionRule, wantWord: ""},
+		{prefix: "# tally ignore=DL3006,hado", want: CompletionRule, wantWord: "hado"},
+		{prefix: "# tally global ignore = max-lines, tally/", want: CompletionRule, wantWord: "tally/"},
+		{prefix: "# tally disable-next-instruction=", want: CompletionRule, wantWord: ""},
+		{prefix: "# tally disable-stage=tally/max-lines,", want: CompletionRule, wantWord: ""},
+		{prefix: "# tally disable-file=DL3006", want: CompletionRule, wantWord: "DL3006"},
+		// Attributes and other comments are not completed.
+		{prefix: "# tally ignore=DL3006;reason=", want: CompletionNone},
+		{prefix: "# hadolint ignore=", want: CompletionNone},
+		{prefix: "# tally", want: CompletionNone},
+		{prefix: "RUN echo # tally ", want: CompletionNone},
+	}
+	for _, tt := range tests {
+		kind, start := CompletionAt(tt.prefix)
+		if kind != tt.want {
+			t.Errorf("CompletionAt(%q) kind = %d, want %d", tt.prefix, kind, tt.want)
+			continue
+		}
+		if kind != CompletionNone && tt.prefix[start:] != tt.wantWord {
+			t.Errorf("CompletionAt(%q) word = %q, want %q", tt.prefix, tt.prefix[start:], tt.wantWord)
+		}
+	}
+}
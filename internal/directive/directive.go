@@ -2,17 +2,21 @@
 //
 // This package implements comment-based suppression compatible with:
 //   - tally:    # tally ignore=RULE1,RULE2 or # tally global ignore=...
+//     plus # tally disable-next-instruction[=RULES], # tally disable-file=RULES
+//     and # tally disable-stage=RULES
 //   - hadolint: # hadolint ignore=RULE1,RULE2 (migration compatibility)
 //   - buildx:   # check=skip=RULE1,RULE2 (Docker buildx compatibility)
 //
 // Directives can be:
-//   - Next-line: Affects the next non-comment line only
+//   - Next-line: Affects the next instruction only (all of its lines)
 //   - Global: Affects the entire file
+//   - Stage: Affects every instruction of one build stage
 package directive
 
 import (
 	"math"
 	"strings"
+	"time"
 
 	"github.com/wharflab/tally/internal/ruledeprecation"
 )
@@ -25,6 +29,8 @@ const (
 	TypeNextLine DirectiveType = iota
 	// TypeGlobal affects the entire file.
 	TypeGlobal
+	// TypeStage affects the build stage of the next instruction.
+	TypeStage
 )
 
 // String returns a human-readable name for the directive type.
@@ -34,6 +40,8 @@ func (t DirectiveType) String() string {
 		return "next-line"
 	case TypeGlobal:
 		return "global"
+	case TypeStage:
+		return "stage"
 	default:
 		return "unknown"
 	}
@@ -61,7 +69,7 @@ func GlobalRange() LineRange {
 
 // Directive represents a parsed inline suppression directive.
 type Directive struct {
-	// Type indicates whether this is a next-line or global directive.
+	// Type indicates whether this is a next-line, global, or stage directive.
 	Type DirectiveType
 
 	// Rules contains the rule codes to suppress.
@@ -87,13 +95,27 @@ type Directive struct {
 	// Reason is an optional explanation for why the rule is being suppressed.
 	// Extracted from `reason=...` in the directive comment.
 	Reason string
+
+	// Expires is the last day (UTC) the directive applies, from
+	// `expires=YYYY-MM-DD`. Zero means the directive never expires.
+	Expires time.Time
+
+	// Owner identifies who is responsible for the suppression, from
+	// `owner=...` (e.g. "@platform-team").
+	Owner string
+}
+
+// Expired reports whether the directive's expires= date has passed at now.
+// A directive stays active through the whole expiry day.
+func (d *Directive) Expired(now time.Time) bool {
+	return !d.Expires.IsZero() && !now.Before(d.Expires.AddDate(0, 0, 1))
 }
 
 // DirectiveSource identifies which syntax format was used.
 type DirectiveSource string
 
 const (
-	// SourceTally indicates # tally ignore=... syntax.
+	// SourceTally indicates # tally ignore=... or # tally disable-...=... syntax.
 	SourceTally DirectiveSource = "tally"
 	// SourceHadolint indicates # hadolint ignore=... syntax.
 	SourceHadolint DirectiveSource = "hadolint"
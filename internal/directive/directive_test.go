@@ -3,6 +3,7 @@ package directive
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/sourcemap"
@@ -79,6 +80,200 @@ RUN echo hello`
 	}
 }
 
+func TestParseTallyDisableNextInstruction(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine
+# tally disable-next-instruction
+RUN <<EOF
+apk add curl
+EOF
+RUN echo done`
+	result := parseDirectives(t, content)
+
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d (errors: %v)", len(result.Directives), result.Errors)
+	}
+	d := result.Directives[0]
+	if d.Type != TypeNextLine {
+		t.Errorf("expected TypeNextLine, got %v", d.Type)
+	}
+	if len(d.Rules) != 1 || d.Rules[0] != "all" {
+		t.Errorf("expected [all], got %v", d.Rules)
+	}
+	// The whole heredoc RUN (lines 2-4) is covered, but not the next RUN.
+	if d.AppliesTo.Start != 2 || d.AppliesTo.End != 4 {
+		t.Errorf("expected AppliesTo {2, 4}, got %v", d.AppliesTo)
+	}
+}
+
+func TestParseTallyDisableNextInstructionWithRules(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine
+# tally disable-next-instruction=DL3018, shellcheck/SC2086;reason=pinned upstream
+RUN apk add curl`
+	result := parseDirectives(t, content)
+
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d", len(result.Directives))
+	}
+	d := result.Directives[0]
+	if len(d.Rules) != 2 || d.Rules[0] != "DL3018" || d.Rules[1] != "shellcheck/SC2086" {
+		t.Errorf("expected [DL3018 shellcheck/SC2086], got %v", d.Rules)
+	}
+	if d.Reason != "pinned upstream" {
+		t.Errorf("expected reason %q, got %q", "pinned upstream", d.Reason)
+	}
+}
+
+func TestParseTallyDisableFile(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine
+# tally disable-file=max-lines
+RUN echo hello`
+	result := parseDirectives(t, content)
+
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d", len(result.Directives))
+	}
+	d := result.Directives[0]
+	if d.Type != TypeGlobal {
+		t.Errorf("expected TypeGlobal, got %v", d.Type)
+	}
+	if d.AppliesTo != GlobalRange() {
+		t.Errorf("expected global range, got %v", d.AppliesTo)
+	}
+}
+
+func TestParseTallyDisableFileRequiresRules(t *testing.T) {
+	t.Parallel()
+	result := parseDirectives(t, "# tally disable-file\nFROM alpine")
+
+	if len(result.Directives) != 0 {
+		t.Errorf("expected 0 directives, got %d", len(result.Directives))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "empty rule list" {
+		t.Errorf("expected empty rule list error, got %v", result.Errors)
+	}
+}
+
+func TestParseTallyDisableStage(t *testing.T) {
+	t.Parallel()
+	content := `ARG VERSION=1
+# tally disable-stage=DL3018
+FROM alpine AS build
+RUN apk add curl
+
+FROM alpine AS runtime
+# tally disable-stage=DL3059
+RUN echo one
+RUN echo two`
+	result := parseDirectives(t, content)
+
+	if len(result.Directives) != 2 {
+		t.Fatalf("expected 2 directives, got %d", len(result.Directives))
+	}
+	build, runtime := result.Directives[0], result.Directives[1]
+	if build.Type != TypeStage || runtime.Type != TypeStage {
+		t.Fatalf("expected TypeStage, got %v and %v", build.Type, runtime.Type)
+	}
+	if build.AppliesTo.Start != 2 || build.AppliesTo.End != 4 {
+		t.Errorf("expected build stage range {2, 4}, got %v", build.AppliesTo)
+	}
+	if runtime.AppliesTo.Start != 5 || runtime.AppliesTo.End != math.MaxInt {
+		t.Errorf("expected runtime stage range {5, MaxInt}, got %v", runtime.AppliesTo)
+	}
+}
+
+func TestParseTallyDisableStageBeforeGlobalArg(t *testing.T) {
+	t.Parallel()
+	content := `# tally disable-stage=DL3006
+ARG BASE=alpine
+FROM ${BASE}
+RUN echo hi`
+	result := parseDirectives(t, content)
+
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d", len(result.Directives))
+	}
+	if got := result.Directives[0].AppliesTo; got.Start != 2 || got.End != math.MaxInt {
+		t.Errorf("expected range {2, MaxInt}, got %v", got)
+	}
+}
+
+func TestParseExpiresAndOwner(t *testing.T) {
+	t.Parallel()
+	content := `# tally ignore=DL3008;reason=pinned by base;expires=2025-12-31;owner=@platform
+RUN apt-get install -y curl`
+	result := parseDirectives(t, content)
+
+	if len(result.Directives) != 1 {
+		t.Fatalf("expected 1 directive, got %d (errors: %v)", len(result.Directives), result.Errors)
+	}
+	d := result.Directives[0]
+	if d.Reason != "pinned by base" {
+		t.Errorf("expected reason %q, got %q", "pinned by base", d.Reason)
+	}
+	if want := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC); !d.Expires.Equal(want) {
+		t.Errorf("expected expires %v, got %v", want, d.Expires)
+	}
+	if d.Owner != "@platform" {
+		t.Errorf("expected owner %q, got %q", "@platform", d.Owner)
+	}
+}
+
+func TestParseAttributesAnyOrder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"tally", "# tally ignore=DL3006;owner=@team;expires=2030-01-01\nFROM ubuntu"},
+		{"hadolint", "# hadolint ignore=DL3006;expires=2030-01-01;owner=@team\nFROM ubuntu"},
+		{"buildx", "# check=skip=DL3006;owner=@team;expires=2030-01-01"},
+		{"disable", "# tally disable-file=DL3006;expires=2030-01-01;owner=@team"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := parseDirectives(t, tt.content)
+			if len(result.Directives) != 1 {
+				t.Fatalf("expected 1 directive, got %d (errors: %v)", len(result.Directives), result.Errors)
+			}
+			d := result.Directives[0]
+			if d.Owner != "@team" || d.Expires.IsZero() || d.Reason != "" {
+				t.Errorf("got owner=%q expires=%v reason=%q", d.Owner, d.Expires, d.Reason)
+			}
+		})
+	}
+}
+
+func TestParseInvalidExpires(t *testing.T) {
+	t.Parallel()
+	result := parseDirectives(t, "# tally ignore=DL3006;expires=31/12/2025\nFROM ubuntu")
+
+	if len(result.Directives) != 0 {
+		t.Errorf("expected 0 directives, got %d", len(result.Directives))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != `invalid expires date "31/12/2025": want YYYY-MM-DD` {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestDirectiveExpired(t *testing.T) {
+	t.Parallel()
+	d := Directive{Expires: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)}
+
+	if d.Expired(time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC)) {
+		t.Error("directive should still apply on its expiry day")
+	}
+	if !d.Expired(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("directive should be expired the day after expires=")
+	}
+	if (&Directive{}).Expired(time.Now()) {
+		t.Error("directive without expires= should never expire")
+	}
+}
+
 func TestParseHadolint(t *testing.T) {
 	t.Parallel()
 	content := `# hadolint ignore=DL3006
@@ -503,6 +698,29 @@ func TestFilterNextLineOnlyAffectsOneLine(t *testing.T) {
 	}
 }
 
+func TestFilterStageDirective(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine AS build
+# tally disable-stage=DL3018
+RUN apk add curl
+FROM alpine
+RUN apk add git`
+	result := parseDirectives(t, content)
+
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3018", "pin", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 5), "hadolint/DL3018", "pin", rules.SeverityWarning),
+	}
+	filtered := Filter(violations, result.Directives)
+
+	if len(filtered.Violations) != 1 || filtered.Violations[0].Line() != 5 {
+		t.Errorf("expected only the line 5 violation to remain, got %v", filtered.Violations)
+	}
+	if len(filtered.UnusedDirectives) != 0 {
+		t.Errorf("expected directive to be used, got %d unused", len(filtered.UnusedDirectives))
+	}
+}
+
 func TestFilterUnusedDirective(t *testing.T) {
 	t.Parallel()
 	violations := []rules.Violation{
@@ -568,6 +786,7 @@ func TestDirectiveType_String(t *testing.T) {
 	}{
 		{TypeNextLine, "next-line"},
 		{TypeGlobal, "global"},
+		{TypeStage, "stage"},
 		{DirectiveType(99), "unknown"},
 	}
 
@@ -772,6 +991,12 @@ FROM ubuntu`,
 FROM ubuntu`,
 			expected: []string{"DL3006", "DL3008", "DL3009"},
 		},
+		{
+			name: "whitespace-separated rules",
+			content: `# hadolint ignore=DL3006 DL3008
+FROM ubuntu`,
+			expected: []string{"DL3006", "DL3008"},
+		},
 		{
 			name: "buildx with spaces",
 			content: `# check=skip=DL3006, DL3008
@@ -10,6 +10,9 @@ type FilterResult struct {
 	// Suppressed violations that were filtered out.
 	Suppressed []rules.Violation
 
+	// SuppressedBy holds the directive that suppressed each entry of Suppressed.
+	SuppressedBy []Directive
+
 	// UnusedDirectives that did not suppress any violations.
 	UnusedDirectives []Directive
 }
@@ -37,21 +40,22 @@ func Filter(violations []rules.Violation, directives []Directive) *FilterResult
 	copy(directiveCopies, directives)
 
 	for _, v := range violations {
-		suppressed := false
+		var suppressedBy *Directive
 		// Convert 1-based violation line to 0-based
 		line0 := v.Line() - 1
 
 		for i := range directiveCopies {
 			d := &directiveCopies[i]
 			if d.SuppressesLine(line0) && d.SuppressesRule(v.RuleCode) {
-				suppressed = true
+				suppressedBy = d
 				d.Used = true
 				break
 			}
 		}
 
-		if suppressed {
+		if suppressedBy != nil {
 			result.Suppressed = append(result.Suppressed, v)
+			result.SuppressedBy = append(result.SuppressedBy, *suppressedBy)
 		} else {
 			result.Violations = append(result.Violations, v)
 		}
@@ -9,9 +9,10 @@ import (
 // reason field, used when formatting new directives.
 const reasonSeparator = ";reason="
 
-// reasonPattern matches the reason separator in existing directive text.
+// attributePattern matches the start of the attribute list (;reason=,
+// ;expires= or ;owner=) in existing directive text.
 // Mirrors the parser's regex: case-insensitive, optional whitespace around '='.
-var reasonPattern = regexp.MustCompile(`(?i);reason\s*=\s*`)
+var attributePattern = regexp.MustCompile(`(?i);(?:reason|expires|owner)\s*=\s*`)
 
 // FormatNextLine produces a canonical next-line suppression directive comment:
 //
@@ -50,13 +51,14 @@ type AppendRuleEdit struct {
 // AppendRule computes the edit needed to append ruleCode to an existing
 // directive line (e.g. "# tally ignore=DL3008" → insert ",DL3027").
 //
-// The edit inserts before ";reason=" if present, otherwise at end of line,
+// The edit inserts before the first attribute (";reason=", ";expires=",
+// ";owner=") if present, otherwise at end of line,
 // trimming trailing whitespace before the insertion point.
 func AppendRule(lineText, ruleCode string) AppendRuleEdit {
-	// Find insertion point: before ;reason= (case-insensitive, flexible whitespace)
-	// if present, otherwise at end of line.
+	// Find insertion point: before the attribute list (case-insensitive,
+	// flexible whitespace) if present, otherwise at end of line.
 	insertPos := len(lineText)
-	if loc := reasonPattern.FindStringIndex(lineText); loc != nil {
+	if loc := attributePattern.FindStringIndex(lineText); loc != nil {
 		insertPos = loc[0]
 	}
 
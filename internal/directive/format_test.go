@@ -32,7 +32,9 @@ func TestFormatNextLine_RoundTrip(t *testing.T) {
 	assert.NotNil(t, matches, "formatted directive should match tallyPattern")
 	assert.Empty(t, matches[1], "should not have 'global' capture")
 	assert.Equal(t, "DL3008,tally/max-lines", matches[2])
-	assert.Equal(t, "testing", matches[3])
+	attrs, err := parseAttributes(matches[3])
+	assert.NoError(t, err)
+	assert.Equal(t, "testing", attrs.Reason)
 }
 
 func TestFormatGlobal_RoundTrip(t *testing.T) {
@@ -80,6 +82,13 @@ func TestAppendRule(t *testing.T) {
 		assert.Equal(t, 23, edit.End, "should replace the trailing tabs")
 	})
 
+	t.Run("before expires", func(t *testing.T) {
+		t.Parallel()
+		edit := AppendRule("# tally ignore=DL3008;expires=2025-12-31", "DL3027")
+		assert.Equal(t, 21, edit.Start)
+		assert.Equal(t, 21, edit.End)
+	})
+
 	t.Run("reason with spaces around equals", func(t *testing.T) {
 		t.Parallel()
 		edit := AppendRule("# tally ignore=DL3008;reason = false positive", "DL3027")
@@ -23,11 +23,13 @@ var (
 	escapeLexPattern = regexp.MustCompile(
 		`(?i)#\s*(escape)\s*(=)\s*(\S(?:.*\S)?)\s*$`)
 	tallyIgnoreLexPattern = regexp.MustCompile(
-		`(?i)#\s*(tally)\s+((global)\s+)?(ignore)\s*(=)\s*([A-Za-z0-9_,\s/.-]+?)(?:;(reason)\s*(=)\s*(.*))?$`)
+		`(?i)#\s*(tally)\s+((global)\s+)?(ignore)\s*(=)\s*([A-Za-z0-9_,\s/.-]+?)` + attributeTail)
+	tallyDisableLexPattern = regexp.MustCompile(
+		`(?i)#\s*(tally)\s+(disable-(?:next-instruction|file|stage))(?:\s*(=)\s*([A-Za-z0-9_,\s/.-]*?))?\s*` + attributeTail)
 	hadolintIgnoreLexPattern = regexp.MustCompile(
-		`(?i)#\s*(hadolint)\s+((global)\s+)?(ignore)\s*(=)\s*([A-Za-z0-9_,\s/.-]+?)(?:;(reason)\s*(=)\s*(.*))?$`)
+		`(?i)#\s*(hadolint)\s+((global)\s+)?(ignore)\s*(=)\s*([A-Za-z0-9_,\s/.-]+?)` + attributeTail)
 	buildxLexPattern = regexp.MustCompile(
-		`(?i)#\s*(check)\s*(=)\s*(skip)\s*(=)\s*([A-Za-z0-9_,\s/.-]+?)(?:;(reason)\s*(=)\s*(.*))?$`)
+		`(?i)#\s*(check)\s*(=)\s*(skip)\s*(=)\s*([A-Za-z0-9_,\s/.-]+?)` + attributeTail)
 	tallyShellLexPattern = regexp.MustCompile(
 		`(?i)#\s*(tally)\s+(shell)\s*(=)\s*([A-Za-z0-9_./-]+)\s*$`)
 	hadolintShellLexPattern = regexp.MustCompile(
@@ -47,6 +49,9 @@ func LexComment(text string) []CommentToken {
 	if tokens := lexIgnoreComment(text, tallyIgnoreLexPattern); tokens != nil {
 		return tokens
 	}
+	if tokens := lexDisableComment(text); tokens != nil {
+		return tokens
+	}
 	if tokens := lexIgnoreComment(text, hadolintIgnoreLexPattern); tokens != nil {
 		return tokens
 	}
@@ -99,13 +104,26 @@ func lexIgnoreComment(text string, pattern *regexp.Regexp) []CommentToken {
 		CommentToken{StartByte: matches[10], EndByte: matches[11], Kind: CommentTokenOperator},
 	)
 	tokens = append(tokens, lexRuleList(text, matches[12], matches[13])...)
-	if matches[14] >= 0 && matches[15] >= 0 {
-		tokens = append(tokens,
-			CommentToken{StartByte: matches[14], EndByte: matches[15], Kind: CommentTokenKeyword},
-			CommentToken{StartByte: matches[16], EndByte: matches[17], Kind: CommentTokenOperator},
-			CommentToken{StartByte: matches[18], EndByte: matches[19], Kind: CommentTokenValue},
-		)
+	tokens = append(tokens, lexAttributes(text, matches[14], matches[15])...)
+	return tokens
+}
+
+func lexDisableComment(text string) []CommentToken {
+	matches := tallyDisableLexPattern.FindStringSubmatchIndex(text)
+	if matches == nil {
+		return nil
+	}
+
+	tokens := make([]CommentToken, 0, 8)
+	tokens = append(tokens,
+		CommentToken{StartByte: matches[2], EndByte: matches[3], Kind: CommentTokenKeyword},
+		CommentToken{StartByte: matches[4], EndByte: matches[5], Kind: CommentTokenKeyword},
+	)
+	if matches[6] >= 0 && matches[7] >= 0 {
+		tokens = append(tokens, CommentToken{StartByte: matches[6], EndByte: matches[7], Kind: CommentTokenOperator})
+		tokens = append(tokens, lexRuleList(text, matches[8], matches[9])...)
 	}
+	tokens = append(tokens, lexAttributes(text, matches[10], matches[11])...)
 	return tokens
 }
 
@@ -123,13 +141,7 @@ func lexBuildxComment(text string) []CommentToken {
 		CommentToken{StartByte: matches[8], EndByte: matches[9], Kind: CommentTokenOperator},
 	)
 	tokens = append(tokens, lexRuleList(text, matches[10], matches[11])...)
-	if matches[12] >= 0 && matches[13] >= 0 {
-		tokens = append(tokens,
-			CommentToken{StartByte: matches[12], EndByte: matches[13], Kind: CommentTokenKeyword},
-			CommentToken{StartByte: matches[14], EndByte: matches[15], Kind: CommentTokenOperator},
-			CommentToken{StartByte: matches[16], EndByte: matches[17], Kind: CommentTokenValue},
-		)
-	}
+	tokens = append(tokens, lexAttributes(text, matches[12], matches[13])...)
 	return tokens
 }
 
@@ -147,6 +159,30 @@ func lexShellComment(text string, pattern *regexp.Regexp) []CommentToken {
 	}
 }
 
+// lexAttributes tokenizes the attribute list (reason=, expires=, owner=) that
+// spans text[start:end].
+func lexAttributes(text string, start, end int) []CommentToken {
+	if start < 0 || end <= start {
+		return nil
+	}
+	attrs := splitAttributes(text[start:end])
+	tokens := make([]CommentToken, 0, 3*len(attrs))
+	for _, a := range attrs {
+		tokens = append(tokens,
+			CommentToken{StartByte: start + a.KeyStart, EndByte: start + a.KeyEnd, Kind: CommentTokenKeyword},
+			CommentToken{StartByte: start + a.EqualStart, EndByte: start + a.EqualStart + 1, Kind: CommentTokenOperator},
+		)
+		if a.ValueEnd > a.ValueStart {
+			tokens = append(tokens, CommentToken{
+				StartByte: start + a.ValueStart,
+				EndByte:   start + a.ValueEnd,
+				Kind:      CommentTokenValue,
+			})
+		}
+	}
+	return tokens
+}
+
 func lexRuleList(text string, start, end int) []CommentToken {
 	if start < 0 || end <= start {
 		return nil
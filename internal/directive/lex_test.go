@@ -72,3 +72,33 @@ func assertLexToken(t *testing.T, text string, tokens []CommentToken, wantKind C
 
 	t.Fatalf("missing token kind=%d text=%q in %+v", wantKind, wantText, tokens)
 }
+
+func TestLexComment_TallyDisable(t *testing.T) {
+	t.Parallel()
+
+	text := "# tally disable-stage=DL3008, max-lines;reason=vendored stage"
+	tokens := LexComment(text)
+
+	assertLexToken(t, text, tokens, CommentTokenKeyword, "tally")
+	assertLexToken(t, text, tokens, CommentTokenKeyword, "disable-stage")
+	assertLexToken(t, text, tokens, CommentTokenOperator, "=")
+	assertLexToken(t, text, tokens, CommentTokenRule, "DL3008")
+	assertLexToken(t, text, tokens, CommentTokenRule, "max-lines")
+	assertLexToken(t, text, tokens, CommentTokenValue, "vendored stage")
+
+	bare := "# tally disable-next-instruction"
+	assertLexToken(t, bare, LexComment(bare), CommentTokenKeyword, "disable-next-instruction")
+}
+
+func TestLexComment_Attributes(t *testing.T) {
+	t.Parallel()
+
+	text := "# hadolint ignore=DL3008;expires=2025-12-31;owner=@platform"
+	tokens := LexComment(text)
+
+	assertLexToken(t, text, tokens, CommentTokenRule, "DL3008")
+	assertLexToken(t, text, tokens, CommentTokenKeyword, "expires")
+	assertLexToken(t, text, tokens, CommentTokenValue, "2025-12-31")
+	assertLexToken(t, text, tokens, CommentTokenKeyword, "owner")
+	assertLexToken(t, text, tokens, CommentTokenValue, "@platform")
+}
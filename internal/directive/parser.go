@@ -2,8 +2,10 @@ package directive
 
 import (
 	"bytes"
+	"math"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
@@ -12,23 +14,31 @@ import (
 
 // Regex patterns for directive parsing.
 // All patterns are case-insensitive for the directive keywords.
-// Patterns capture an optional reason after `;reason=` (BuildKit-style separator).
+// Patterns capture an optional attribute list after the rule list, starting
+// at the first `;reason=`, `;expires=` or `;owner=` (BuildKit-style separator).
 // Rule lists allow optional whitespace around commas (e.g., "DL3006, DL3008").
 // Rule names can include / for namespaced rules (e.g., "buildkit/StageNameCasing").
 var (
-	// # tally [global] ignore=RULE1,RULE2[;reason=explanation]
+	// # tally [global] ignore=RULE1,RULE2[;reason=explanation][;expires=YYYY-MM-DD][;owner=@team]
 	tallyPattern = regexp.MustCompile(
-		`(?i)#\s*tally\s+(global\s+)?ignore\s*=\s*([A-Za-z0-9_,\s/.-]+?)(?:;reason\s*=\s*(.*))?$`)
+		`(?i)#\s*tally\s+(global\s+)?ignore\s*=\s*([A-Za-z0-9_,\s/.-]+?)` + attributeTail)
 
-	// # hadolint [global] ignore=RULE1,RULE2[;reason=explanation]
-	// Note: ;reason= is a tally extension, not part of hadolint's native syntax
+	// # tally disable-next-instruction[=RULE1,RULE2][;attributes]
+	// # tally disable-file=RULE1,RULE2[;attributes]
+	// # tally disable-stage=RULE1,RULE2[;attributes]
+	// The rule list is optional for disable-next-instruction (defaults to all).
+	tallyDisablePattern = regexp.MustCompile(
+		`(?i)#\s*tally\s+disable-(next-instruction|file|stage)(?:\s*=\s*([A-Za-z0-9_,\s/.-]*?))?\s*` + attributeTail)
+
+	// # hadolint [global] ignore=RULE1,RULE2[;attributes]
+	// Note: attributes are a tally extension, not part of hadolint's native syntax
 	hadolintPattern = regexp.MustCompile(
-		`(?i)#\s*hadolint\s+(global\s+)?ignore\s*=\s*([A-Za-z0-9_,\s/.-]+?)(?:;reason\s*=\s*(.*))?$`)
+		`(?i)#\s*hadolint\s+(global\s+)?ignore\s*=\s*([A-Za-z0-9_,\s/.-]+?)` + attributeTail)
 
-	// # check=skip=RULE1,RULE2[;reason=explanation] (buildx - always file-level/global)
-	// Note: ;reason= is a tally extension, BuildKit silently ignores it
+	// # check=skip=RULE1,RULE2[;attributes] (buildx - always file-level/global)
+	// Note: attributes are a tally extension, BuildKit silently ignores them
 	buildxPattern = regexp.MustCompile(
-		`(?i)#\s*check\s*=\s*skip\s*=\s*([A-Za-z0-9_,\s/.-]+?)(?:;reason\s*=\s*(.*))?$`)
+		`(?i)#\s*check\s*=\s*skip\s*=\s*([A-Za-z0-9_,\s/.-]+?)` + attributeTail)
 
 	// # tally shell=<shell>
 	// Shell names can include dots for extensions (e.g., cmd.exe)
@@ -50,6 +60,9 @@ type RuleValidator func(string) bool
 type InstructionSpan struct {
 	StartLine int
 	EndLine   int
+
+	// Keyword is the lowercase instruction keyword (e.g. "from", "run").
+	Keyword string
 }
 
 // InstructionSpanIndex enables efficient lookup of the next instruction span
@@ -74,7 +87,7 @@ func NewInstructionSpanIndexFromAST(ast *parser.Result, sm *sourcemap.SourceMap)
 		if start < 0 || end < start {
 			continue
 		}
-		spans = append(spans, InstructionSpan{StartLine: start, EndLine: end})
+		spans = append(spans, InstructionSpan{StartLine: start, EndLine: end, Keyword: strings.ToLower(node.Value)})
 	}
 
 	// Children are already in source order; StartLine is monotonic.
@@ -124,6 +137,39 @@ func (idx *InstructionSpanIndex) nextInstructionSpan(afterLine int) (Instruction
 	return InstructionSpan{}, false
 }
 
+// stageRange returns the line range of the build stage that contains the next
+// instruction after afterLine. Instructions before the first FROM (global ARGs)
+// belong to no stage; for those the first stage after the directive is used.
+// The range runs from the stage's FROM to the line before the next FROM, or to
+// the end of the file for the last stage.
+func (idx *InstructionSpanIndex) stageRange(afterLine int) (LineRange, bool) {
+	next, ok := idx.nextInstructionSpan(afterLine)
+	if !ok {
+		return LineRange{}, false
+	}
+
+	start := -1
+	for _, span := range idx.Spans {
+		if span.Keyword != "from" {
+			continue
+		}
+		if span.StartLine <= next.StartLine {
+			start = span.StartLine
+			continue
+		}
+		if start < 0 {
+			// Next instruction is a global ARG; use the first stage after it.
+			start = span.StartLine
+			continue
+		}
+		return LineRange{Start: start, End: span.StartLine - 1}, true
+	}
+	if start < 0 {
+		return LineRange{}, false
+	}
+	return LineRange{Start: start, End: math.MaxInt}, true
+}
+
 // Parse extracts all inline directives from a SourceMap.
 // If validator is non-nil, unknown rule codes generate parse errors.
 func Parse(sm *sourcemap.SourceMap, validator RuleValidator, spanIndex *InstructionSpanIndex) *ParseResult {
@@ -136,6 +182,16 @@ func Parse(sm *sourcemap.SourceMap, validator RuleValidator, spanIndex *Instruct
 		}
 
 		// Try ignore directive patterns first
+		if d, err := parseTallyDisable(comment, sm, spanIndex); d != nil || err != nil {
+			if err != nil {
+				result.Errors = append(result.Errors, *err)
+			}
+			if d != nil {
+				validateDirective(d, validator, result)
+			}
+			continue
+		}
+
 		if d, err := parseTally(comment, sm, spanIndex); d != nil || err != nil {
 			if err != nil {
 				result.Errors = append(result.Errors, *err)
@@ -218,12 +274,6 @@ func parseIgnoreDirective(
 	isGlobal := strings.TrimSpace(matches[1]) != ""
 	rulesStr := matches[2]
 
-	// Extract optional reason from capture group 3
-	var reason string
-	if len(matches) > 3 {
-		reason = strings.TrimSpace(matches[3])
-	}
-
 	rules, err := parseRuleList(rulesStr)
 	if err != nil {
 		return nil, &ParseError{
@@ -238,7 +288,9 @@ func parseIgnoreDirective(
 		Line:    comment.Line,
 		RawText: comment.Text,
 		Source:  source,
-		Reason:  reason,
+	}
+	if perr := applyAttributes(d, matches[3], comment); perr != nil {
+		return nil, perr
 	}
 
 	if isGlobal {
@@ -257,6 +309,64 @@ func parseTally(comment sourcemap.Comment, sm *sourcemap.SourceMap, spanIndex *I
 	return parseIgnoreDirective(comment, sm, tallyPattern, SourceTally, spanIndex)
 }
 
+// parseTallyDisable attempts to parse a tally disable-next-instruction,
+// disable-file, or disable-stage directive.
+func parseTallyDisable(
+	comment sourcemap.Comment,
+	sm *sourcemap.SourceMap,
+	spanIndex *InstructionSpanIndex,
+) (*Directive, *ParseError) {
+	matches := tallyDisablePattern.FindStringSubmatch(comment.Text)
+	if matches == nil {
+		return nil, nil
+	}
+
+	kind := strings.ToLower(matches[1])
+	rulesStr := strings.TrimSpace(matches[2])
+
+	var rules []string
+	if rulesStr == "" && kind == "next-instruction" {
+		rules = []string{"all"}
+	} else {
+		var err error
+		rules, err = parseRuleList(rulesStr)
+		if err != nil {
+			return nil, &ParseError{
+				Line:    comment.Line,
+				Message: err.Error(),
+				RawText: comment.Text,
+			}
+		}
+	}
+
+	d := &Directive{
+		Rules:   rules,
+		Line:    comment.Line,
+		RawText: comment.Text,
+		Source:  SourceTally,
+	}
+	if perr := applyAttributes(d, matches[3], comment); perr != nil {
+		return nil, perr
+	}
+
+	switch kind {
+	case "file":
+		d.Type = TypeGlobal
+		d.AppliesTo = GlobalRange()
+	case "stage":
+		d.Type = TypeStage
+		d.AppliesTo = LineRange{Start: -1, End: -1}
+		if r, ok := spanIndex.stageRange(comment.Line); ok {
+			d.AppliesTo = r
+		}
+	default:
+		d.Type = TypeNextLine
+		d.AppliesTo = nextInstructionLineRange(comment.Line, sm, spanIndex)
+	}
+
+	return d, nil
+}
+
 // parseHadolint attempts to parse a hadolint-format directive.
 func parseHadolint(comment sourcemap.Comment, sm *sourcemap.SourceMap, spanIndex *InstructionSpanIndex) (*Directive, *ParseError) {
 	return parseIgnoreDirective(comment, sm, hadolintPattern, SourceHadolint, spanIndex)
@@ -264,7 +374,8 @@ func parseHadolint(comment sourcemap.Comment, sm *sourcemap.SourceMap, spanIndex
 
 // parseBuildx attempts to parse a buildx-format directive.
 // buildx's check=skip is always file-level (global).
-// Note: ;reason= is a tally extension; BuildKit silently ignores unknown options.
+// Note: attributes (;reason=, ;expires=, ;owner=) are a tally extension;
+// BuildKit silently ignores unknown options.
 func parseBuildx(comment sourcemap.Comment) (*Directive, *ParseError) {
 	matches := buildxPattern.FindStringSubmatch(comment.Text)
 	if matches == nil {
@@ -273,12 +384,6 @@ func parseBuildx(comment sourcemap.Comment) (*Directive, *ParseError) {
 
 	rulesStr := matches[1]
 
-	// Extract optional reason from capture group 2
-	var reason string
-	if len(matches) > 2 {
-		reason = strings.TrimSpace(matches[2])
-	}
-
 	rules, err := parseRuleList(rulesStr)
 	if err != nil {
 		return nil, &ParseError{
@@ -288,34 +393,48 @@ func parseBuildx(comment sourcemap.Comment) (*Directive, *ParseError) {
 		}
 	}
 
-	return &Directive{
+	d := &Directive{
 		Type:      TypeGlobal, // buildx check=skip is always global
 		Rules:     rules,
 		Line:      comment.Line,
 		AppliesTo: GlobalRange(),
 		RawText:   comment.Text,
 		Source:    SourceBuildx,
-		Reason:    reason,
-	}, nil
+	}
+	if perr := applyAttributes(d, matches[2], comment); perr != nil {
+		return nil, perr
+	}
+	return d, nil
 }
 
-// parseRuleList parses a comma-separated list of rule codes.
+// applyAttributes copies reason=, expires= and owner= from the captured
+// attribute list onto d.
+func applyAttributes(d *Directive, raw string, comment sourcemap.Comment) *ParseError {
+	attrs, err := parseAttributes(raw)
+	if err != nil {
+		return &ParseError{
+			Line:    comment.Line,
+			Message: err.Error(),
+			RawText: comment.Text,
+		}
+	}
+	d.Reason = attrs.Reason
+	d.Expires = attrs.Expires
+	d.Owner = attrs.Owner
+	return nil
+}
+
+// parseRuleList parses a list of rule codes separated by commas and/or
+// whitespace (e.g. "DL3006,DL3008" or "DL3006 DL3008").
 // Returns an error if the list is empty.
 func parseRuleList(s string) ([]string, error) {
 	if s == "" {
 		return nil, &parseRuleError{msg: "empty rule list"}
 	}
 
-	parts := strings.Split(s, ",")
-	rules := make([]string, 0, len(parts))
-
-	for _, part := range parts {
-		rule := strings.TrimSpace(part)
-		if rule == "" {
-			continue
-		}
-		rules = append(rules, rule)
-	}
+	rules := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
 
 	if len(rules) == 0 {
 		return nil, &parseRuleError{msg: "empty rule list"}
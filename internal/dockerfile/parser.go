@@ -3,6 +3,8 @@ package dockerfile
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"slices"
@@ -33,6 +35,21 @@ type LintWarning struct {
 	Location    []parser.Range
 }
 
+// SyntaxError describes an instruction BuildKit could not parse into a typed
+// command (e.g. COPY with a single argument or an unknown flag). Parse drops
+// such instructions and keeps going so the rest of the file can be linted.
+type SyntaxError struct {
+	Message  string
+	Location []parser.Range
+}
+
+func (e SyntaxError) Error() string {
+	if len(e.Location) == 0 {
+		return "dockerfile parse error: " + e.Message
+	}
+	return fmt.Sprintf("dockerfile parse error on line %d: %s", e.Location[0].Start.Line, e.Message)
+}
+
 // ParseResult contains the parsed Dockerfile information
 type ParseResult struct {
 	// AST is the parsed Dockerfile AST from BuildKit
@@ -45,6 +62,13 @@ type ParseResult struct {
 	Source []byte
 	// Warnings contains lint warnings from BuildKit's built-in linter
 	Warnings []LintWarning
+	// SyntaxErrors lists instructions that were dropped because BuildKit
+	// could not parse them. Stages and MetaArgs cover the remaining ones.
+	SyntaxErrors []SyntaxError
+	// PodmanExtensions holds the line ranges of RUN instructions whose
+	// Podman-only mount syntax was hidden from the typed parse (dialect =
+	// "podman"). Their Stages commands lack that syntax.
+	PodmanExtensions []parser.Range
 }
 
 // ASTEscapeToken returns the Dockerfile escape token from a BuildKit AST,
@@ -122,10 +146,30 @@ func Parse(r io.Reader, cfg *config.Config) (*ParseResult, error) {
 	// original AST for semantic checks and output.
 	astForInstructions := sanitizeASTForInstructionParse(ast.AST)
 
-	// Parse into typed instructions (stages and meta args)
+	// Podman/Buildah accept RUN --mount syntax that BuildKit rejects when the
+	// mounts are expanded. Hide it so rules see the mounts that remain.
+	var podmanExtensions []parser.Range
+	if cfg != nil && cfg.Dialect == config.DialectPodman {
+		astForInstructions, podmanExtensions = hidePodmanExtensions(astForInstructions)
+	}
+
+	// Parse into typed instructions (stages and meta args). A single bad
+	// instruction makes instructions.Parse fail for the whole file, so on
+	// error we drop the instructions that fail on their own and parse the
+	// rest again, reporting the dropped ones as syntax errors.
 	stages, metaArgs, err := instructions.Parse(astForInstructions, lint)
+	var syntaxErrors []SyntaxError
 	if err != nil {
-		return nil, err
+		var recovered *parser.Node
+		recovered, syntaxErrors = dropUnparsableInstructions(astForInstructions)
+		if len(syntaxErrors) == 0 {
+			return nil, err
+		}
+		warnings = warnings[:0]
+		stages, metaArgs, err = instructions.Parse(recovered, linter.New(lintCfg))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Convert parser-level warnings to LintWarning format.
@@ -154,14 +198,67 @@ func Parse(r io.Reader, cfg *config.Config) (*ParseResult, error) {
 	}
 
 	return &ParseResult{
-		AST:      ast,
-		Stages:   stages,
-		MetaArgs: metaArgs,
-		Source:   content,
-		Warnings: warnings,
+		AST:              ast,
+		Stages:           stages,
+		MetaArgs:         metaArgs,
+		Source:           content,
+		Warnings:         warnings,
+		SyntaxErrors:     syntaxErrors,
+		PodmanExtensions: podmanExtensions,
 	}, nil
 }
 
+// dropUnparsableInstructions returns a copy of root without the instructions
+// BuildKit rejects on their own, together with a SyntaxError for each. A
+// rejected FROM is replaced by "FROM scratch" on the same lines so the
+// instructions that follow still belong to a stage of their own.
+func dropUnparsableInstructions(root *parser.Node) (*parser.Node, []SyntaxError) {
+	if root == nil {
+		return root, nil
+	}
+	// Probe with a silent linter; warnings come from the final parse.
+	probe := linter.New(&linter.Config{
+		Warn: func(string, string, string, string, []parser.Range) {},
+	})
+
+	var syntaxErrors []SyntaxError
+	filtered := make([]*parser.Node, 0, len(root.Children))
+	for _, child := range root.Children {
+		if child == nil {
+			continue
+		}
+		_, err := instructions.ParseInstructionWithLinter(child, probe)
+		if err == nil {
+			filtered = append(filtered, child)
+			continue
+		}
+		syntaxErrors = append(syntaxErrors, newSyntaxError(child, err))
+		if strings.EqualFold(child.Value, command.From) {
+			filtered = append(filtered, &parser.Node{
+				Value:     command.From,
+				Next:      &parser.Node{Value: "scratch"},
+				StartLine: child.StartLine,
+				EndLine:   child.EndLine,
+			})
+		}
+	}
+	if len(syntaxErrors) == 0 {
+		return root, nil
+	}
+	recovered := *root
+	recovered.Children = filtered
+	return &recovered, syntaxErrors
+}
+
+func newSyntaxError(node *parser.Node, err error) SyntaxError {
+	location := node.Location()
+	var locErr *parser.LocationError
+	if errors.As(err, &locErr) && len(locErr.Locations) > 0 && len(locErr.Locations[0]) > 0 {
+		location = locErr.Locations[0]
+	}
+	return SyntaxError{Message: err.Error(), Location: location}
+}
+
 // extractRuleNameFromURL extracts rule name from Docker documentation URL.
 // Example: "https://docs.docker.com/go/dockerfile/rule/no-empty-continuation/"
 // returns "NoEmptyContinuation" (converted to PascalCase to match BuildKit rule names).
@@ -320,8 +417,13 @@ func buildLinterConfig(cfg *config.Config, warnFunc linter.LintWarnFunc) *linter
 			// Individual rules will be filtered by our processor
 			continue
 		}
-		// Handle specific buildkit rule: "buildkit/StageNameCasing"
-		if ns, name := parseRuleCode(pattern); ns == "buildkit" && name != "" {
+		// Handle specific buildkit rule: "buildkit/StageNameCasing", unless
+		// a pattern that takes precedence (e.g. --select) enables it again.
+		ns, name := parseRuleCode(pattern)
+		if ns != "buildkit" || name == "" {
+			continue
+		}
+		if sel, ok := cfg.Rules.Selection(pattern); ok && !sel.Enabled {
 			lintCfg.SkipRules = append(lintCfg.SkipRules, name)
 		}
 	}
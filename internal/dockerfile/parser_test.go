@@ -248,6 +248,49 @@ func TestParse_BuildKitWarnings(t *testing.T) {
 	}
 }
 
+func TestParse_RecoversFromInstructionErrors(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine:3.18\nCOPY app\nRUN --foo echo hi\nUSER app\n" +
+		"FROM --bogus=1 debian AS Second\nRUN echo two\nMAINTAINER me\n"
+
+	result, err := Parse(strings.NewReader(content), nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	wantLines := []int{2, 3, 5}
+	if len(result.SyntaxErrors) != len(wantLines) {
+		t.Fatalf("len(SyntaxErrors) = %d, want %d: %+v", len(result.SyntaxErrors), len(wantLines), result.SyntaxErrors)
+	}
+	for i, line := range wantLines {
+		se := result.SyntaxErrors[i]
+		if len(se.Location) == 0 || se.Location[0].Start.Line != line {
+			t.Errorf("SyntaxErrors[%d] location = %+v, want line %d", i, se.Location, line)
+		}
+	}
+	if !strings.Contains(result.SyntaxErrors[0].Message, "COPY requires at least two arguments") {
+		t.Errorf("SyntaxErrors[0].Message = %q", result.SyntaxErrors[0].Message)
+	}
+	if got := result.SyntaxErrors[1].Error(); !strings.HasPrefix(got, "dockerfile parse error on line 3: ") {
+		t.Errorf("SyntaxErrors[1].Error() = %q", got)
+	}
+
+	// The dropped FROM keeps its stage so later instructions stay in place.
+	if len(result.Stages) != 2 {
+		t.Fatalf("len(Stages) = %d, want 2", len(result.Stages))
+	}
+	if n := len(result.Stages[0].Commands); n != 1 {
+		t.Errorf("len(Stages[0].Commands) = %d, want 1 (USER)", n)
+	}
+	if n := len(result.Stages[1].Commands); n != 2 {
+		t.Errorf("len(Stages[1].Commands) = %d, want 2", n)
+	}
+	// Warnings come from the recovered parse only, without duplicates.
+	if len(result.Warnings) != 1 || result.Warnings[0].RuleName != "MaintainerDeprecated" {
+		t.Errorf("Warnings = %+v, want one MaintainerDeprecated", result.Warnings)
+	}
+}
+
 func TestParse_Source(t *testing.T) {
 	t.Parallel()
 	content := "FROM alpine:3.18\nRUN echo hello\n"
@@ -0,0 +1,124 @@
+package dockerfile
+
+import (
+	"encoding/csv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// podmanMountTypes are RUN --mount types Buildah accepts but BuildKit rejects.
+var podmanMountTypes = map[string]bool{
+	"devpts": true,
+}
+
+// podmanMountKeys are RUN --mount options Buildah accepts but BuildKit
+// rejects, lowercased the way BuildKit compares keys: SELinux relabeling
+// (z, Z, relabel), ownership (U), bind propagation and tmpfs copy-up.
+var podmanMountKeys = map[string]bool{
+	"z":                 true,
+	"u":                 true,
+	"relabel":           true,
+	"bind-propagation":  true,
+	"bind-nonrecursive": true,
+	"tmpcopyup":         true,
+	"notmpcopyup":       true,
+}
+
+const mountFlagPrefix = "--mount="
+
+// hidePodmanExtensions returns a copy of root in which RUN --mount flags only
+// use syntax BuildKit understands, together with the line ranges of the RUN
+// instructions that changed. Podman-only mount types are dropped and
+// Podman-only options are removed, so the remaining mounts still parse into
+// typed instructions. The original AST is left untouched.
+func hidePodmanExtensions(root *parser.Node) (*parser.Node, []parser.Range) {
+	if root == nil {
+		return root, nil
+	}
+
+	var changed []parser.Range
+	children := make([]*parser.Node, len(root.Children))
+	for i, child := range root.Children {
+		children[i] = child
+		if child == nil || !strings.EqualFold(child.Value, command.Run) {
+			continue
+		}
+		flags, ok := hidePodmanMountFlags(child.Flags)
+		if !ok {
+			continue
+		}
+		node := *child
+		node.Flags = flags
+		children[i] = &node
+		changed = append(changed, parser.Range{
+			Start: parser.Position{Line: child.StartLine},
+			End:   parser.Position{Line: child.EndLine},
+		})
+	}
+	if len(changed) == 0 {
+		return root, nil
+	}
+	hidden := *root
+	hidden.Children = children
+	return &hidden, changed
+}
+
+// hidePodmanMountFlags rewrites the --mount flags in flags and reports
+// whether any of them changed.
+func hidePodmanMountFlags(flags []string) ([]string, bool) {
+	out := make([]string, 0, len(flags))
+	changed := false
+	for _, flag := range flags {
+		if !strings.HasPrefix(flag, mountFlagPrefix) {
+			out = append(out, flag)
+			continue
+		}
+		value, keep, ok := hidePodmanMountOptions(strings.TrimPrefix(flag, mountFlagPrefix))
+		if !ok {
+			out = append(out, flag)
+			continue
+		}
+		changed = true
+		if keep {
+			out = append(out, mountFlagPrefix+value)
+		}
+	}
+	return out, changed
+}
+
+// hidePodmanMountOptions removes Podman-only options from a --mount value.
+// keep is false when the mount type itself is Podman-only; ok is false when
+// nothing had to change or the value is not valid CSV (BuildKit's mount
+// syntax), leaving it for BuildKit to judge.
+func hidePodmanMountOptions(value string) (string, bool, bool) {
+	fields, err := csv.NewReader(strings.NewReader(value)).Read()
+	if err != nil {
+		return "", false, false
+	}
+
+	kept := make([]string, 0, len(fields))
+	for _, field := range fields {
+		key, val, _ := strings.Cut(field, "=")
+		key = strings.ToLower(key)
+		if key == "type" && podmanMountTypes[strings.ToLower(val)] {
+			return "", false, true
+		}
+		if podmanMountKeys[key] {
+			continue
+		}
+		kept = append(kept, field)
+	}
+	if len(kept) == len(fields) {
+		return "", false, false
+	}
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	if err := w.Write(kept); err != nil {
+		return "", false, false
+	}
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n"), true, true
+}
@@ -0,0 +1,107 @@
+package dockerfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/runmount"
+)
+
+func TestHidePodmanMountOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		value    string
+		want     string
+		wantKeep bool
+		wantOK   bool
+	}{
+		{
+			name:  "buildkit mount unchanged",
+			value: "type=cache,target=/var/cache/apt,sharing=locked",
+		},
+		{
+			name:     "selinux relabel options",
+			value:    "type=cache,target=/var/cache/apt,Z,relabel=shared",
+			want:     "type=cache,target=/var/cache/apt",
+			wantKeep: true,
+			wantOK:   true,
+		},
+		{
+			name:     "ownership and propagation",
+			value:    "type=bind,source=.,target=/src,U,bind-propagation=rslave",
+			want:     "type=bind,source=.,target=/src",
+			wantKeep: true,
+			wantOK:   true,
+		},
+		{
+			name:     "quoted field keeps quoting",
+			value:    `type=bind,"source=a,b",target=/src,z`,
+			want:     `type=bind,"source=a,b",target=/src`,
+			wantKeep: true,
+			wantOK:   true,
+		},
+		{
+			name:   "devpts mount dropped",
+			value:  "type=devpts,target=/dev/pts",
+			wantOK: true,
+		},
+		{
+			name:  "invalid csv left to buildkit",
+			value: `type=bind,"source`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, keep, ok := hidePodmanMountOptions(tt.value)
+			if got != tt.want || keep != tt.wantKeep || ok != tt.wantOK {
+				t.Errorf("hidePodmanMountOptions(%q) = (%q, %v, %v), want (%q, %v, %v)",
+					tt.value, got, keep, ok, tt.want, tt.wantKeep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParse_PodmanDialect(t *testing.T) {
+	t.Parallel()
+	content := "FROM fedora:40\n" +
+		"RUN --mount=type=cache,target=/var/cache/dnf,Z --mount=type=devpts,target=/dev/pts dnf -y install make\n" +
+		"RUN --mount=type=cache,target=/root/.cache echo ok\n"
+
+	docker, err := Parse(strings.NewReader(content), config.Default())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(docker.PodmanExtensions) != 0 {
+		t.Errorf("docker dialect PodmanExtensions = %+v, want none", docker.PodmanExtensions)
+	}
+	// BuildKit cannot expand these mounts, so rules don't see the cache mount.
+	for _, m := range runmount.GetMounts(docker.Stages[0].Commands[0].(*instructions.RunCommand)) {
+		if m.Target == "/var/cache/dnf" {
+			t.Errorf("docker dialect parsed the Podman mount: %+v", m)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Dialect = config.DialectPodman
+	podman, err := Parse(strings.NewReader(content), cfg)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(podman.PodmanExtensions) != 1 || podman.PodmanExtensions[0].Start.Line != 2 {
+		t.Errorf("PodmanExtensions = %+v, want line 2 only", podman.PodmanExtensions)
+	}
+	mounts := runmount.GetMounts(podman.Stages[0].Commands[0].(*instructions.RunCommand))
+	if len(mounts) != 1 || mounts[0].Type != instructions.MountTypeCache || mounts[0].Target != "/var/cache/dnf" {
+		t.Errorf("podman dialect mounts = %+v, want the dnf cache mount", mounts)
+	}
+	// The AST keeps the source as written.
+	if flags := podman.AST.AST.Children[1].Flags; len(flags) != 2 || !strings.HasSuffix(flags[0], ",Z") {
+		t.Errorf("AST flags = %q, want the original flags", flags)
+	}
+}
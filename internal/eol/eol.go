@@ -0,0 +1,202 @@
+// Package eol maps container base image references to product release cycles
+// and their end-of-life dates.
+//
+// The embedded dataset (data.json) is a snapshot of https://endoflife.date for
+// the products whose official images are common Dockerfile bases. Refresh it
+// with `make eol-sync`. When slow checks are enabled, Resolver fetches the
+// live data for a product instead.
+package eol
+
+import (
+	_ "embed"
+	"encoding/json/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+)
+
+//go:embed data.json
+var rawData []byte
+
+// Dataset is a set of products with their release cycles.
+type Dataset struct {
+	Products []Product `json:"products"`
+}
+
+// Product is a piece of software with published release cycles.
+type Product struct {
+	// ID is the endoflife.date product identifier (e.g. "nodejs").
+	ID string `json:"id"`
+	// Name is the human-readable product name (e.g. "Node.js").
+	Name string `json:"name"`
+	// Images are the familiar image names that ship the product directly
+	// (e.g. "node"). The image tag selects the release cycle.
+	Images []string `json:"images"`
+	// OS marks operating system products. Their codenames and <image><cycle>
+	// forms (e.g. "bookworm", "alpine3.19") also select a cycle when they
+	// appear as a tag variant of any other image (e.g. "python:3.9-buster").
+	OS bool `json:"os,omitempty"`
+	// Cycles are ordered newest first.
+	Cycles []Cycle `json:"cycles"`
+}
+
+// Cycle is one release cycle of a product.
+type Cycle struct {
+	Cycle    string `json:"cycle"`
+	Codename string `json:"codename,omitempty"`
+	// EOL is the date the cycle stops receiving security updates. The zero
+	// value means no end-of-life date has been announced.
+	EOL time.Time `json:"eol,omitzero"`
+}
+
+// Ended reports whether the cycle is past end-of-life at now, allowing grace
+// extra time after the EOL date.
+func (c Cycle) Ended(now time.Time, grace time.Duration) bool {
+	return !c.EOL.IsZero() && now.After(c.EOL.Add(grace))
+}
+
+// Cycle returns the cycle with the given name or codename.
+func (p *Product) Cycle(name string) (Cycle, bool) {
+	for _, c := range p.Cycles {
+		if c.Cycle == name || (c.Codename != "" && c.Codename == name) {
+			return c, true
+		}
+	}
+	return Cycle{}, false
+}
+
+// Supported returns the newest cycle that is not past end-of-life at now.
+func (p *Product) Supported(now time.Time) (Cycle, bool) {
+	for _, c := range p.Cycles {
+		if !c.Ended(now, 0) {
+			return c, true
+		}
+	}
+	return Cycle{}, false
+}
+
+// URL returns the endoflife.date page for the product.
+func (p *Product) URL() string {
+	return "https://endoflife.date/" + p.ID
+}
+
+// Match is a product release cycle selected by an image reference.
+type Match struct {
+	Product *Product
+	Cycle   Cycle
+}
+
+// unmarshalDateOnly decodes the dataset's YYYY-MM-DD dates. Struct field
+// `format` options are not supported by every encoding/json/v2 release.
+var unmarshalDateOnly = json.UnmarshalFunc(func(b []byte, t *time.Time) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+})
+
+// Default returns the embedded dataset.
+var Default = sync.OnceValue(func() *Dataset {
+	var d Dataset
+	if err := json.Unmarshal(rawData, &d, json.WithUnmarshalers(unmarshalDateOnly)); err != nil {
+		panic("eol: invalid embedded dataset: " + err.Error())
+	}
+	return &d
+})
+
+// Product returns the product with the given endoflife.date identifier.
+func (d *Dataset) Product(id string) *Product {
+	for i := range d.Products {
+		if d.Products[i].ID == id {
+			return &d.Products[i]
+		}
+	}
+	return nil
+}
+
+// Match returns the release cycles selected by an image reference, most
+// specific first: the cycle of the product the image ships (node:14 is
+// Node.js 14), then operating system cycles named by tag variants
+// (node:14-buster is also Debian 10). Untagged, digest-only, and
+// unparseable references match nothing.
+func (d *Dataset) Match(ref string) []Match {
+	named, err := reference.ParseNormalizedNamed(strings.ToLower(ref))
+	if err != nil {
+		return nil
+	}
+	tagged, ok := named.(reference.Tagged)
+	if !ok {
+		return nil
+	}
+	image := reference.FamiliarName(named)
+	tokens := strings.Split(tagged.Tag(), "-")
+
+	var matches []Match
+	for i := range d.Products {
+		p := &d.Products[i]
+		if !slices.Contains(p.Images, image) {
+			continue
+		}
+		if c, ok := p.versionCycle(tokens[0]); ok {
+			matches = append(matches, Match{Product: p, Cycle: c})
+		}
+	}
+	for _, tok := range tokens[1:] {
+		for i := range d.Products {
+			p := &d.Products[i]
+			if !p.OS || slices.Contains(p.Images, image) {
+				continue
+			}
+			if c, ok := p.variantCycle(tok); ok {
+				matches = append(matches, Match{Product: p, Cycle: c})
+			}
+		}
+	}
+	return matches
+}
+
+// versionCycle selects a cycle from the leading version (or codename) of a
+// tag: "3.9.18" selects cycle "3.9", "12" selects "12", "buster" selects the
+// cycle named buster. Cycles only match at a version component boundary, so
+// "3.10" never selects cycle "3.1".
+func (p *Product) versionCycle(version string) (Cycle, bool) {
+	var best Cycle
+	found := false
+	for _, c := range p.Cycles {
+		if c.Codename != "" && c.Codename == version {
+			return c, true
+		}
+		if version != c.Cycle && !strings.HasPrefix(version, c.Cycle+".") {
+			continue
+		}
+		if !found || len(c.Cycle) > len(best.Cycle) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
+
+// variantCycle selects an operating system cycle from a tag variant such as
+// "bookworm", "alpine3.19", or "ubuntu22.04".
+func (p *Product) variantCycle(variant string) (Cycle, bool) {
+	for _, image := range p.Images {
+		if version, ok := strings.CutPrefix(variant, image); ok && version != "" {
+			return p.versionCycle(version)
+		}
+	}
+	for _, c := range p.Cycles {
+		if c.Codename != "" && c.Codename == variant {
+			return c, true
+		}
+	}
+	return Cycle{}, false
+}
@@ -0,0 +1,88 @@
+package eol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefault_Loads(t *testing.T) {
+	t.Parallel()
+
+	d := Default()
+	if len(d.Products) == 0 {
+		t.Fatal("embedded dataset has no products")
+	}
+	for _, p := range d.Products {
+		if p.ID == "" || p.Name == "" || len(p.Images) == 0 || len(p.Cycles) == 0 {
+			t.Errorf("incomplete product: %+v", p)
+		}
+	}
+	if c, ok := d.Product("debian").Cycle("buster"); !ok || c.Cycle != "10" || c.EOL.Format(time.DateOnly) != "2022-09-10" {
+		t.Errorf("debian buster = %+v, %v", c, ok)
+	}
+}
+
+func TestDataset_Match(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref  string
+		want []string // product/cycle
+	}{
+		{ref: "debian:buster", want: []string{"debian/10"}},
+		{ref: "debian:buster-slim", want: []string{"debian/10"}},
+		{ref: "debian:10.13", want: []string{"debian/10"}},
+		{ref: "docker.io/library/ubuntu:18.04", want: []string{"ubuntu/18.04"}},
+		{ref: "ubuntu:bionic-20230530", want: []string{"ubuntu/18.04"}},
+		{ref: "node:14", want: []string{"nodejs/14"}},
+		{ref: "node:14.21.3-buster-slim", want: []string{"nodejs/14", "debian/10"}},
+		{ref: "node:hydrogen-alpine3.18", want: []string{"nodejs/18", "alpine/3.18"}},
+		{ref: "python:3.10-slim", want: []string{"python/3.10"}},
+		{ref: "python:3.1", want: nil},
+		{ref: "golang:1.22.3-alpine3.19", want: []string{"go/1.22", "alpine/3.19"}},
+		{ref: "nvidia/cuda:12.1.0-devel-ubuntu20.04", want: []string{"ubuntu/20.04"}},
+		{ref: "alpine:3.18.4", want: []string{"alpine/3.18"}},
+		{ref: "python:3.12-alpine", want: []string{"python/3.12"}},
+		{ref: "centos:7", want: []string{"centos/7"}},
+		{ref: "node", want: nil},
+		{ref: "node:lts", want: nil},
+		{ref: "alpine@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", want: nil},
+		{ref: "${BASE}", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, m := range Default().Match(tt.ref) {
+				got = append(got, m.Product.ID+"/"+m.Cycle.Cycle)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match(%q) = %v, want %v", tt.ref, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Match(%q) = %v, want %v", tt.ref, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCycle_Ended(t *testing.T) {
+	t.Parallel()
+
+	c := Cycle{Cycle: "10", EOL: time.Date(2022, 9, 10, 0, 0, 0, 0, time.UTC)}
+	day := 24 * time.Hour
+	if c.Ended(c.EOL.Add(-day), 0) {
+		t.Error("cycle should not be ended before its EOL date")
+	}
+	if !c.Ended(c.EOL.Add(day), 0) {
+		t.Error("cycle should be ended after its EOL date")
+	}
+	if c.Ended(c.EOL.Add(day), 30*day) {
+		t.Error("cycle should not be ended within the grace period")
+	}
+	if (Cycle{Cycle: "13"}).Ended(time.Now(), 0) {
+		t.Error("cycle without an EOL date should never be ended")
+	}
+}
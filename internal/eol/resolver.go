@@ -0,0 +1,132 @@
+package eol
+
+import (
+	"context"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wharflab/tally/internal/async/httpfetch"
+)
+
+// ResolverID is the async resolver ID for live endoflife.date lookups.
+const ResolverID = "endoflife"
+
+// DefaultBaseURL is the endoflife.date API root.
+const DefaultBaseURL = "https://endoflife.date"
+
+// Request asks the resolver for the release cycles of one product.
+type Request struct {
+	// Product is the endoflife.date product identifier.
+	Product string
+}
+
+// Resolver fetches product release cycles from the endoflife.date API.
+// Resolve returns a *Product holding only the ID and Cycles.
+type Resolver struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewResolver creates a resolver for the public endoflife.date API.
+func NewResolver() *Resolver {
+	return &Resolver{BaseURL: DefaultBaseURL, Client: httpfetch.NewClient(10 * time.Second)}
+}
+
+// ID returns the resolver identifier.
+func (r *Resolver) ID() string { return ResolverID }
+
+// Resolve fetches the release cycles for a *Request.
+func (r *Resolver) Resolve(ctx context.Context, data any) (any, error) {
+	req, ok := data.(*Request)
+	if !ok {
+		return nil, fmt.Errorf("endoflife resolver: unexpected data type %T", data)
+	}
+	cycles, err := r.fetch(ctx, req.Product)
+	if err != nil {
+		return nil, err
+	}
+	return &Product{ID: req.Product, Cycles: cycles}, nil
+}
+
+// apiCycle is one element of the endoflife.date /api/<product>.json response.
+// eol is either a YYYY-MM-DD date or a boolean.
+type apiCycle struct {
+	Cycle             jsontext.Value `json:"cycle"`
+	Codename          string         `json:"codename"`
+	EOL               jsontext.Value `json:"eol"`
+	LatestReleaseDate string         `json:"latestReleaseDate"`
+}
+
+func (r *Resolver) fetch(ctx context.Context, product string) ([]Cycle, error) {
+	endpoint := strings.TrimSuffix(r.BaseURL, "/") + "/api/" + url.PathEscape(product) + ".json"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, &httpfetch.LookupError{URL: endpoint, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpfetch.LookupError{URL: endpoint, Status: resp.StatusCode}
+	}
+	var raw []apiCycle
+	if err := json.UnmarshalRead(resp.Body, &raw); err != nil {
+		return nil, fmt.Errorf("endoflife %s: decode response: %w", product, err)
+	}
+	return parseCycles(raw)
+}
+
+// parseCycles converts endoflife.date API cycles into Cycles. A boolean eol
+// of true has no date, so the cycle's latest release date stands in for it.
+func parseCycles(raw []apiCycle) ([]Cycle, error) {
+	cycles := make([]Cycle, 0, len(raw))
+	for _, a := range raw {
+		c := Cycle{Cycle: scalarString(a.Cycle), Codename: normalizeCodename(a.Codename)}
+		if c.Cycle == "" {
+			continue
+		}
+		eol := scalarString(a.EOL)
+		if eol == "true" {
+			eol = a.LatestReleaseDate
+		}
+		if eol != "" && eol != "false" {
+			t, err := time.Parse(time.DateOnly, eol)
+			if err != nil {
+				return nil, fmt.Errorf("cycle %s: invalid eol %q", c.Cycle, eol)
+			}
+			c.EOL = t
+		}
+		cycles = append(cycles, c)
+	}
+	return cycles, nil
+}
+
+// scalarString returns a JSON string's contents or a number/boolean's literal text.
+func scalarString(v jsontext.Value) string {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(v))
+}
+
+// normalizeCodename maps an upstream codename to the form used in image tags:
+// "Jammy Jellyfish" becomes "jammy", "Bookworm" becomes "bookworm".
+func normalizeCodename(name string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(name), " ")
+	return strings.ToLower(first)
+}
@@ -0,0 +1,59 @@
+package eol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/httpfetch"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/debian.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"cycle": "13", "codename": "Trixie", "eol": false, "latestReleaseDate": "2026-09-01"},
+			{"cycle": "10", "codename": "Buster", "eol": "2022-09-10", "latestReleaseDate": "2024-06-29"},
+			{"cycle": 6, "eol": true, "latestReleaseDate": "2016-02-29"}
+		]`))
+	}))
+	defer srv.Close()
+
+	r := &Resolver{BaseURL: srv.URL, Client: srv.Client()}
+	got, err := r.Resolve(context.Background(), &Request{Product: "debian"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := got.(*Product)
+	if !ok {
+		t.Fatalf("Resolve returned %T, want *Product", got)
+	}
+	if len(p.Cycles) != 3 {
+		t.Fatalf("cycles = %+v", p.Cycles)
+	}
+	if c := p.Cycles[0]; c.Cycle != "13" || c.Codename != "trixie" || !c.EOL.IsZero() {
+		t.Errorf("trixie = %+v", c)
+	}
+	if c, ok := p.Cycle("buster"); !ok || c.EOL.Format(time.DateOnly) != "2022-09-10" {
+		t.Errorf("buster = %+v, %v", c, ok)
+	}
+	if c, ok := p.Cycle("6"); !ok || c.EOL.Format(time.DateOnly) != "2016-02-29" {
+		t.Errorf("eol: true should fall back to the latest release date: %+v, %v", c, ok)
+	}
+
+	_, err = r.Resolve(context.Background(), &Request{Product: "nope"})
+	var lookupErr *httpfetch.LookupError
+	if !errors.As(err, &lookupErr) || lookupErr.SkipReason() != async.SkipNotFound {
+		t.Errorf("unknown product: got %v, want not-found LookupError", err)
+	}
+}
@@ -89,6 +89,13 @@ type StageFacts struct {
 	// HasEntrypoint is true when the stage contains an ENTRYPOINT instruction.
 	HasEntrypoint bool
 
+	// HasCmd is true when the stage, or the stage it is built FROM, contains
+	// a CMD instruction.
+	HasCmd bool
+
+	// Kind classifies what the stage is for (see StageKind).
+	Kind StageKind
+
 	// ObservableFiles collects image files written in this stage whose content
 	// can be observed directly or loaded lazily at lint time.
 	ObservableFiles []*ObservableFile
@@ -335,6 +342,15 @@ func (f *FileFacts) Labels() []LabelPairFact {
 	return labels
 }
 
+// ContextFiles returns the reader for the local build context, or nil when
+// the file is linted without one.
+func (f *FileFacts) ContextFiles() ContextFileReader {
+	if f == nil {
+		return nil
+	}
+	return f.contextFiles
+}
+
 // RubyFacts returns the cached Ruby project state derived from the build
 // context (Gemfile, Gemfile.lock, .ruby-version, .tool-versions). The
 // returned value is non-nil; individual sub-fields (Lockfile, Gemfile,
@@ -370,6 +386,7 @@ func (f *FileFacts) build() {
 	for stageIdx := range stages {
 		f.stages[stageIdx] = f.buildStageFacts(stageIdx, &stages[stageIdx], len(stages), sm, escapeToken)
 	}
+	f.classifyStageKinds(stages)
 }
 
 func factsBuildContext(parseResult *dockerfile.ParseResult) (*sourcemap.SourceMap, rune) {
@@ -501,7 +518,19 @@ func (f *FileFacts) processStageCommands(
 ) stageEntrypointState {
 	var entrypointState stageEntrypointState
 
+	prevLine := -1
+	if len(stage.Location) > 0 {
+		prevLine = stage.Location[0].Start.Line - 1
+	}
 	for cmdIdx, cmd := range stage.Commands {
+		if locs := cmd.Location(); len(locs) > 0 && locs[0].Start.Line > 0 {
+			if shellName, ok := shellDirectiveBetween(f.shellDirectives, prevLine, locs[0].Start.Line-1); ok {
+				state.currentShell = newShellFacts([]string{shellName, "-c"})
+				stageFacts.FinalShell = state.currentShell
+			}
+			prevLine = locs[0].Start.Line - 1
+		}
+
 		switch c := cmd.(type) {
 		case *instructions.WorkdirCommand:
 			state.workdir = ResolveWorkdir(state.workdir, c.Path)
@@ -547,6 +576,7 @@ func (f *FileFacts) processStageCommands(
 			entrypointState.sawLocalEntrypoint = true
 			entrypointState.lastEntrypointCmdLine = append([]string(nil), c.CmdLine...)
 		case *instructions.CmdCommand:
+			stageFacts.HasCmd = true
 			entrypointState.sawLocalCmd = true
 			entrypointState.lastCmdCmdLine = append([]string(nil), c.CmdLine...)
 		}
@@ -642,6 +672,7 @@ func seedStageEntrypointState(semInfo *semantic.StageInfo, stages []*StageFacts,
 
 	parent := stages[baseIdx]
 	target.HasEntrypoint = parent.HasEntrypoint
+	target.HasCmd = parent.HasCmd
 	target.HasPrivilegeDropEntrypoint = parent.HasPrivilegeDropEntrypoint
 	target.HasPrivilegeDropCmd = parent.HasPrivilegeDropCmd
 }
@@ -975,18 +1006,34 @@ func analyzeBuildContextSource(
 	}
 
 	normalized := normalizeBuildContextSourcePath(sourcePath)
+	if isContextSourceGlob(sourcePath) {
+		available, err := globSourceAvailability(contextFiles, normalized)
+		return &BuildContextSource{
+			Instruction:          instruction,
+			SourcePath:           sourcePath,
+			NormalizedSourcePath: normalized,
+			Line:                 line,
+			Location:             location,
+			AvailableInContext:   available,
+			AvailabilityErr:      err,
+		}
+	}
+
 	ignored, err := contextFiles.IsIgnored(normalized)
-	isGlob := isContextSourceGlob(sourcePath)
-	// Globs expand during the build; probing the literal pattern would make
-	// PathExists/FileExists report unknown matches as missing.
-	available := isGlob
+	available := false
 	observableSourcePath := ""
-	if err == nil && !ignored && !isGlob {
+	if err == nil && !ignored {
 		var regularFile bool
 		available, regularFile = contextSourceAvailability(contextFiles, normalized)
 		if regularFile && canObserveBuildContextSource(instruction, normalized) {
 			observableSourcePath = normalized
 		}
+	} else if err == nil {
+		// An ignored directory still sends the files that negation patterns
+		// re-include below it.
+		if finder, ok := contextFiles.(includedDescendantFinder); ok {
+			available = finder.HasIncludedDescendant(normalized)
+		}
 	}
 	return &BuildContextSource{
 		Instruction:              instruction,
@@ -1012,6 +1059,42 @@ type pathExister interface {
 	PathExists(path string) bool
 }
 
+type contextGlobber interface {
+	Glob(pattern string) ([]string, error)
+}
+
+type includedDescendantFinder interface {
+	HasIncludedDescendant(dir string) bool
+}
+
+// globSourceAvailability reports whether a wildcard source matches at least
+// one path the build sends. Readers that cannot expand wildcards report every
+// wildcard as available, since the matches are unknown.
+func globSourceAvailability(contextFiles ContextFileReader, pattern string) (bool, error) {
+	globber, ok := contextFiles.(contextGlobber)
+	if !ok {
+		return true, nil
+	}
+
+	matches, err := globber.Glob(pattern)
+	if err != nil {
+		return false, err
+	}
+	for _, match := range matches {
+		ignored, err := contextFiles.IsIgnored(match)
+		if err != nil {
+			return false, err
+		}
+		if !ignored {
+			return true, nil
+		}
+		if finder, ok := contextFiles.(includedDescendantFinder); ok && finder.HasIncludedDescendant(match) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func contextSourceAvailability(contextFiles ContextFileReader, normalized string) (availableInContext, regularFile bool) {
 	if contextFiles == nil {
 		return false, false
@@ -1133,7 +1216,8 @@ func initialStageShell(
 	// accounts for distro-aware variant refinement (e.g. VariantBash for
 	// most Linux distros, VariantPOSIX for Alpine/Debian/Ubuntu).
 	if semInfo != nil {
-		return newShellFactsWithVariant(semInfo.ShellSetting.Shell, semInfo.ShellSetting.Variant)
+		initial := semInfo.InitialShellSetting()
+		return newShellFactsWithVariant(initial.Shell, initial.Variant)
 	}
 
 	defaultShell := append([]string(nil), semantic.DefaultShell...)
@@ -1159,6 +1243,23 @@ func activeShellDirective(stage *instructions.Stage, shellDirectives []ShellDire
 	return active.Shell, true
 }
 
+// shellDirectiveBetween returns the shell of the last shell directive after
+// afterLine and before beforeLine (both 0-based). Such a directive inside a
+// stage switches the shell for the instructions that follow it.
+func shellDirectiveBetween(shellDirectives []ShellDirective, afterLine, beforeLine int) (string, bool) {
+	var active *ShellDirective
+	for i := range shellDirectives {
+		sd := &shellDirectives[i]
+		if sd.Line > afterLine && sd.Line < beforeLine && (active == nil || sd.Line > active.Line) {
+			active = sd
+		}
+	}
+	if active == nil {
+		return "", false
+	}
+	return active.Shell, true
+}
+
 func newShellFacts(shellCmd []string) ShellFacts {
 	variant := shell.VariantFromShellCmd(shellCmd)
 	return newShellFactsWithVariant(shellCmd, variant)
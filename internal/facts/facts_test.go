@@ -104,6 +104,30 @@ RUN env PIP_INDEX_URL=https://example.com/simple pip install flask && npm instal
 	}
 }
 
+func TestFileFacts_ShellDirectiveInsideStageSwitchesRunShell(t *testing.T) {
+	t.Parallel()
+
+	fileFacts := makeFileFacts(t, `FROM ubuntu:22.04
+RUN echo default
+# hadolint shell=powershell
+RUN Write-Output switched
+`)
+
+	stage := fileFacts.Stage(0)
+	if stage == nil || len(stage.Runs) != 2 {
+		t.Fatalf("expected stage facts with 2 RUN facts, got %+v", stage)
+	}
+	if stage.Runs[0].Shell.IsPowerShell {
+		t.Fatal("expected first RUN to use the default shell")
+	}
+	if !stage.Runs[1].Shell.IsPowerShell {
+		t.Fatalf("expected second RUN to use powershell, got %+v", stage.Runs[1].Shell)
+	}
+	if !stage.FinalShell.IsPowerShell {
+		t.Fatalf("expected final shell powershell, got %+v", stage.FinalShell)
+	}
+}
+
 func TestResolveWorkdirAndUnquote(t *testing.T) {
 	t.Parallel()
 
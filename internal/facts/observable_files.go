@@ -387,5 +387,5 @@ func normalizeBuildContextSourcePath(path string) string {
 }
 
 func isBuildContextURLSource(path string) bool {
-	return shell.IsURL(path) || strings.HasPrefix(path, "git://")
+	return shell.IsURL(path) || strings.HasPrefix(path, "git://") || strings.HasPrefix(path, "git@")
 }
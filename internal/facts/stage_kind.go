@@ -0,0 +1,124 @@
+package facts
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+)
+
+// StageKind classifies what a stage is for, so rules about the image that
+// runs (a non-root USER, a HEALTHCHECK, no secrets) can skip stages that
+// never run as a container.
+type StageKind int
+
+const (
+	// StageKindBuilder is a stage that prepares files or a base for other
+	// stages: it is copied from or built upon, or is not otherwise
+	// classified.
+	StageKindBuilder StageKind = iota
+
+	// StageKindRuntime is a stage whose image is meant to run: the final
+	// (or target) stage, and leaf stages that declare ENTRYPOINT or CMD,
+	// such as an alternative debug target.
+	StageKindRuntime
+
+	// StageKindArtifact is a FROM scratch stage without ENTRYPOINT or CMD.
+	// It only collects files, e.g. for docker build --output.
+	StageKindArtifact
+
+	// StageKindTest is a stage named for running tests or checks, such as
+	// "test", "unit-tests" or "lint".
+	StageKindTest
+)
+
+// String returns the lowercase name of the kind.
+func (k StageKind) String() string {
+	switch k {
+	case StageKindRuntime:
+		return "runtime"
+	case StageKindArtifact:
+		return "artifact"
+	case StageKindTest:
+		return "test"
+	default:
+		return "builder"
+	}
+}
+
+// IsRuntime reports whether the stage's image is meant to run.
+func (s *StageFacts) IsRuntime() bool {
+	return s != nil && s.Kind == StageKindRuntime
+}
+
+// testStageNameWords are the words of a stage name that mark a stage that
+// runs tests or checks instead of producing an image.
+var testStageNameWords = map[string]bool{
+	"test":    true,
+	"tests":   true,
+	"testing": true,
+	"e2e":     true,
+	"spec":    true,
+	"specs":   true,
+	"lint":    true,
+	"linter":  true,
+	"linting": true,
+	"check":   true,
+	"checks":  true,
+}
+
+// classifyStageKinds sets the Kind of every stage. It runs after all stages
+// are built, since a stage's kind depends on the stages that use it.
+func (f *FileFacts) classifyStageKinds(stages []instructions.Stage) {
+	finalIdx := len(stages) - 1
+	if f.semantic != nil {
+		finalIdx = f.semantic.FinalStageIndex()
+	}
+	for i, sf := range f.stages {
+		if sf == nil {
+			continue
+		}
+		sf.Kind = f.classifyStageKind(sf, stages[i].Name, i == finalIdx)
+	}
+}
+
+func (f *FileFacts) classifyStageKind(sf *StageFacts, name string, isFinal bool) StageKind {
+	if isTestStageName(name) {
+		return StageKindTest
+	}
+	runs := sf.HasEntrypoint || sf.HasCmd
+	info := f.stageInfo(sf.Index)
+	if info != nil && info.IsScratch() && !runs {
+		return StageKindArtifact
+	}
+	if isFinal {
+		return StageKindRuntime
+	}
+	if runs && !f.hasDependents(sf.Index) {
+		return StageKindRuntime
+	}
+	return StageKindBuilder
+}
+
+// hasDependents reports whether another stage copies from or builds on the
+// stage at index.
+func (f *FileFacts) hasDependents(index int) bool {
+	if f.semantic == nil || f.semantic.Graph() == nil {
+		return false
+	}
+	return len(f.semantic.Graph().DirectDependents(index)) > 0
+}
+
+// isTestStageName reports whether a stage name contains a word such as
+// "test" or "lint", e.g. "test", "unit-tests" or "go_lint".
+func isTestStageName(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if testStageNameWords[w] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package facts
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStageKinds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []StageKind
+	}{
+		{
+			name: "builder and runtime",
+			content: `FROM golang:1.24 AS build
+RUN go build -o /out/app .
+
+FROM alpine:3.20
+COPY --from=build /out/app /app
+`,
+			want: []StageKind{StageKindBuilder, StageKindRuntime},
+		},
+		{
+			name: "test stage by name",
+			content: `FROM golang:1.24 AS build
+RUN go build ./...
+
+FROM build AS unit-tests
+RUN go test ./...
+
+FROM build AS go_lint
+RUN golangci-lint run
+
+FROM alpine:3.20
+COPY --from=build /out/app /app
+`,
+			want: []StageKind{StageKindBuilder, StageKindTest, StageKindTest, StageKindRuntime},
+		},
+		{
+			name: "scratch artifact",
+			content: `FROM golang:1.24 AS build
+RUN go build -o /out/app .
+
+FROM scratch AS binaries
+COPY --from=build /out/app /
+
+FROM gcr.io/distroless/static
+COPY --from=binaries /app /app
+`,
+			want: []StageKind{StageKindBuilder, StageKindArtifact, StageKindRuntime},
+		},
+		{
+			name: "scratch runtime with entrypoint",
+			content: `FROM golang:1.24 AS build
+RUN CGO_ENABLED=0 go build -o /out/app .
+
+FROM scratch
+COPY --from=build /out/app /app
+ENTRYPOINT ["/app"]
+`,
+			want: []StageKind{StageKindBuilder, StageKindRuntime},
+		},
+		{
+			name: "leaf stage with cmd is an alternative runtime",
+			content: `FROM node:22 AS base
+CMD ["node", "server.js"]
+
+FROM base AS debug
+RUN npm install -g ndb
+
+FROM base AS deps
+RUN npm ci
+
+FROM node:22-slim
+COPY --from=deps /app /app
+`,
+			want: []StageKind{StageKindBuilder, StageKindRuntime, StageKindBuilder, StageKindRuntime},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ff := makeFileFacts(t, tt.content)
+			var got []StageKind
+			for _, sf := range ff.Stages() {
+				got = append(got, sf.Kind)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("stage kinds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTestStageName(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]bool{
+		"test":        true,
+		"Unit-Tests":  true,
+		"e2e":         true,
+		"lint":        true,
+		"testdata":    false,
+		"contest":     false,
+		"build":       false,
+		"":            false,
+		"api_check":   true,
+		"integration": false,
+	} {
+		if got := isTestStageName(name); got != want {
+			t.Errorf("isTestStageName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package fix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// manyFileFixes simulates a repository with one Dockerfile per service. Each
+// Dockerfile has the given number of RUN instructions using apt, with one
+// violation per instruction whose fix replaces apt with apt-get.
+func manyFileFixes(files, runs int) (map[string][]byte, []rules.Violation) {
+	sources := make(map[string][]byte, files)
+	violations := make([]rules.Violation, 0, files*runs)
+	for i := range files {
+		path := fmt.Sprintf("services/svc-%03d/Dockerfile", i)
+		var sb strings.Builder
+		sb.WriteString("FROM debian:bookworm\n")
+		for j := range runs {
+			line := j + 2
+			fmt.Fprintf(&sb, "RUN apt install -y --no-install-recommends pkg-%d-%d && rm -rf /var/lib/apt/lists/*\n", i, j)
+			violations = append(violations, rules.Violation{
+				Location: rules.NewLineLocation(path, line),
+				RuleCode: "hadolint/DL3027",
+				Message:  "Do not use apt",
+				SuggestedFix: &rules.SuggestedFix{
+					Description: "Replace apt with apt-get",
+					Safety:      rules.FixSafe,
+					Edits: []rules.TextEdit{{
+						Location: rules.NewRangeLocation(path, line, 4, line, 7),
+						NewText:  "apt-get",
+					}},
+				},
+			})
+		}
+		sources[path] = []byte(sb.String())
+	}
+	return sources, violations
+}
+
+func TestFixer_Apply_ConcurrentFilesDeterministic(t *testing.T) {
+	t.Parallel()
+	sources, violations := manyFileFixes(40, 5)
+	// Conflicting fixes exercise the per-file winner selection.
+	for i := range 40 {
+		path := fmt.Sprintf("services/svc-%03d/Dockerfile", i)
+		violations = append(violations, rules.Violation{
+			Location: rules.NewLineLocation(path, 2),
+			RuleCode: "tally/prefer-apt-get",
+			SuggestedFix: &rules.SuggestedFix{
+				Description: "Rewrite the install",
+				Safety:      rules.FixSafe,
+				Edits: []rules.TextEdit{{
+					Location: rules.NewRangeLocation(path, 2, 4, 2, 14),
+					NewText:  "apt-get install",
+				}},
+			},
+		})
+	}
+
+	sequential, err := (&Fixer{SafetyThreshold: FixSafe, Concurrency: 1}).Apply(
+		context.Background(), cloneViolations(violations), sources)
+	if err != nil {
+		t.Fatalf("sequential Apply error: %v", err)
+	}
+	concurrent, err := (&Fixer{SafetyThreshold: FixSafe, Concurrency: 8}).Apply(
+		context.Background(), cloneViolations(violations), sources)
+	if err != nil {
+		t.Fatalf("concurrent Apply error: %v", err)
+	}
+
+	if sequential.TotalApplied() != 200 || sequential.TotalSkipped() != 40 {
+		t.Errorf("applied %d, skipped %d fixes, want 200 and 40", sequential.TotalApplied(), sequential.TotalSkipped())
+	}
+	for file, want := range sequential.Changes {
+		got := concurrent.Changes[file]
+		if string(got.ModifiedContent) != string(want.ModifiedContent) {
+			t.Errorf("%s: content differs between sequential and concurrent fixing", file)
+		}
+		if fmt.Sprint(got.FixesApplied, got.FixesSkipped) != fmt.Sprint(want.FixesApplied, want.FixesSkipped) {
+			t.Errorf("%s: applied/skipped fixes differ between sequential and concurrent fixing", file)
+		}
+	}
+}
+
+// cloneViolations copies violations and their fixes, which the fixer updates
+// in place.
+func cloneViolations(violations []rules.Violation) []rules.Violation {
+	out := make([]rules.Violation, len(violations))
+	for i, v := range violations {
+		if v.SuggestedFix != nil {
+			sf := *v.SuggestedFix
+			v.SuggestedFix = &sf
+		}
+		out[i] = v
+	}
+	return out
+}
+
+func BenchmarkFixerApply_ManyFiles(b *testing.B) {
+	sources, violations := manyFileFixes(300, 40)
+
+	for _, concurrency := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("files=300/concurrency=%d", concurrency), func(b *testing.B) {
+			fixer := &Fixer{SafetyThreshold: FixSafe, Concurrency: concurrency}
+			for b.Loop() {
+				if _, err := fixer.Apply(context.Background(), cloneViolations(violations), sources); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
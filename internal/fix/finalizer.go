@@ -17,7 +17,8 @@ type FinalizeContext struct {
 // Finalizer computes a final set of edits after sync and async fixes have been applied.
 //
 // Finalizers are intended for cleanup rules whose violations may only appear
-// after another fix creates new source text.
+// after another fix creates new source text. Finalize may be called
+// concurrently for different files.
 type Finalizer interface {
 	RuleCode() string
 	Description() string
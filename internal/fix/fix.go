@@ -15,8 +15,8 @@ import (
 // offsets are not invalidated, matching the ordering used by the production
 // fixer for a single fix. Input src is not modified.
 //
-// Intended for tests that want to round-trip a rule's SuggestedFix.Edits
-// back through the source without reimplementing the reverse-order loop.
+// Used by tests that want to round-trip a rule's SuggestedFix.Edits back
+// through the source, and by reporters that preview a fix as a diff.
 func ApplyEdits(src []byte, edits []rules.TextEdit) []byte {
 	if len(edits) == 0 {
 		return src
@@ -91,6 +91,11 @@ type AppliedFix struct {
 	// Positions reference the original document content, making them
 	// suitable for direct conversion to LSP TextEdits.
 	Edits []rules.TextEdit
+
+	// Iteration is the 0-based fix iteration that applied this fix (see
+	// [Fixer.Iterations]). Location and Edits of fixes from later iterations
+	// reference the content produced by the previous iteration.
+	Iteration int
 }
 
 // SkipReason explains why a fix was skipped.
@@ -114,6 +119,14 @@ const (
 
 	// SkipFixMode means the rule's fix mode config disallows fixing.
 	SkipFixMode
+
+	// SkipValidation means the fix was rolled back because the fixed file
+	// failed post-fix validation.
+	SkipValidation
+
+	// SkipOscillation means a later fix iteration was discarded because it
+	// would return the file to the content of an earlier iteration.
+	SkipOscillation
 )
 
 // String returns a human-readable description of the skip reason.
@@ -131,6 +144,10 @@ func (r SkipReason) String() string {
 		return "no edits in fix"
 	case SkipFixMode:
 		return "disabled by fix mode config"
+	case SkipValidation:
+		return "rolled back after failing validation"
+	case SkipOscillation:
+		return "would undo an earlier fix iteration"
 	default:
 		return "unknown reason"
 	}
@@ -147,7 +164,8 @@ type SkippedFix struct {
 	// Location is where the fix would have been applied.
 	Location rules.Location
 
-	// Error contains the error message if Reason is SkipResolveError.
+	// Error contains the error message if Reason is SkipResolveError,
+	// SkipValidation, or SkipOscillation.
 	Error string
 }
 
@@ -167,6 +185,11 @@ type FileChange struct {
 
 	// ModifiedContent is the file content after fixes.
 	ModifiedContent []byte
+
+	// relinted holds the violations reported for ModifiedContent after the
+	// last fix iteration; hasRelinted is set when more than one ran.
+	relinted    []rules.Violation
+	hasRelinted bool
 }
 
 // HasChanges returns true if any fixes were applied to this file.
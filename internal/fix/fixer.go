@@ -4,14 +4,22 @@ import (
 	"bytes"
 	"cmp"
 	"context"
+	"log/slog"
+	"maps"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/wharflab/tally/internal/config"
 	"github.com/wharflab/tally/internal/rules"
 )
 
+// defaultConcurrency is the number of files fixed in parallel when
+// [Fixer.Concurrency] is not set.
+const defaultConcurrency = 4
+
 // normalizePath ensures consistent path format for map lookups.
 // This handles Windows vs Unix path separator differences.
 func normalizePath(path string) string {
@@ -49,9 +57,29 @@ type Fixer struct {
 	// If nil or a file/rule is not present, FixModeAlways is assumed.
 	FixModes map[string]map[string]FixMode
 
-	// Concurrency sets the number of parallel async resolutions.
-	// Defaults to 4 if not set.
+	// Concurrency sets the number of files fixed in parallel. Fixes within a
+	// file are always applied sequentially, so the result does not depend on
+	// it. Defaults to 4 if not set.
 	Concurrency int
+
+	// FixPrecedence maps file paths to their fix-precedence patterns
+	// (config.Config.FixPrecedence). When fixes overlap, rules matching an
+	// earlier pattern win. Outer key is the normalized file path.
+	FixPrecedence map[string][]string
+
+	// Iterations is the maximum number of fix iterations. After the first,
+	// each modified file is re-linted and re-fixed until it reaches a fixed
+	// point, so fixes that only become applicable after other fixes are
+	// applied in one call. Values below 2, or a nil Relint, run a single
+	// iteration.
+	Iterations int
+
+	// Relint re-lints modified file content. Fixes that lost a conflict are
+	// retried against the relinted content, and each modified file is
+	// validated after all fixes are applied: files whose fixed rules still
+	// report violations are rolled back. If nil, conflicting fixes stay
+	// skipped and fixed content is only checked to still parse.
+	Relint Relinter
 }
 
 // Result contains the outcome of applying fixes.
@@ -93,12 +121,30 @@ func (r *Result) FilesModified() int {
 // sources maps file paths to their original content.
 //
 // Fix application follows a two-phase approach to avoid position drift:
-//  1. Apply sync fixes first (NeedsResolve=false) - these have pre-computed edits
+//  1. Apply sync fixes first (NeedsResolve=false) - these have pre-computed edits.
+//     Fixes that lose a conflict are retried once against the updated content
+//     (see [Fixer.Relint]).
 //  2. Resolve and apply async fixes (NeedsResolve=true) - resolvers see the modified content
 //
 // This ensures structural transforms (like prefer-run-heredoc) operate on content
 // that has already been modified by content fixes (like apt → apt-get).
+//
+// Finally, each modified file is validated (see [Fixer.Relint]); files whose
+// fixes regressed are restored to their original content. With more than one
+// [Fixer.Iterations], the whole process repeats on each modified file.
 func (f *Fixer) Apply(ctx context.Context, violations []rules.Violation, sources map[string][]byte) (*Result, error) {
+	result, err := f.applyIteration(ctx, violations, sources)
+	if err != nil {
+		return nil, err
+	}
+	if f.Iterations > 1 && f.Relint != nil {
+		f.iterate(ctx, result)
+	}
+	return result, nil
+}
+
+// applyIteration runs a single fix iteration over sources.
+func (f *Fixer) applyIteration(ctx context.Context, violations []rules.Violation, sources map[string][]byte) (*Result, error) {
 	result := &Result{
 		Changes: make(map[string]*FileChange),
 	}
@@ -109,29 +155,107 @@ func (f *Fixer) Apply(ctx context.Context, violations []rules.Violation, sources
 	// Classify violations into sync and async candidates
 	syncCandidates, asyncCandidates := f.classifyViolations(violations, result.Changes)
 
+	syncByFile := candidatesByFile(syncCandidates)
+	asyncByFile := candidatesByFile(asyncCandidates)
+	before := make(map[string][]rules.Violation)
+	for i := range violations {
+		path := normalizePath(violations[i].File())
+		before[path] = append(before[path], violations[i])
+	}
+	finalizers := sortedFinalizers()
+
+	f.forEachFile(result.Changes, func(file string, fc *FileChange) {
+		f.fixFile(ctx, fc, syncByFile[file], asyncByFile[file], finalizers, before[file])
+	})
+
+	return result, nil
+}
+
+// fixFile runs the fix phases for a single file. before holds the violations
+// reported for the file's original content.
+func (f *Fixer) fixFile(
+	ctx context.Context,
+	fc *FileChange,
+	syncCandidates, asyncCandidates []*fixCandidate,
+	finalizers []Finalizer,
+	before []rules.Violation,
+) {
 	// Phase 1: Apply sync fixes (content fixes with pre-computed edits)
-	f.applyCandidatesToFiles(result.Changes, syncCandidates)
+	f.applySyncFixes(fc, syncCandidates)
+
+	// Phase 1b: Retry fixes that lost a conflict against the updated content.
+	f.retryConflictingFixes(ctx, fc)
 
 	// Phase 2: Resolve and apply async fixes (each is applied immediately after resolution)
 	if len(asyncCandidates) > 0 {
-		f.resolveAsyncFixes(ctx, result.Changes, asyncCandidates)
+		f.resolveAsyncFixes(ctx, fc, asyncCandidates)
 		// Record skipped fixes for any that still need resolution (resolver failed)
-		for _, fc := range asyncCandidates {
-			if fc.fix.NeedsResolve {
-				if fc.fix.ResolveErr != nil {
+		for _, c := range asyncCandidates {
+			if c.fix.NeedsResolve {
+				if c.fix.ResolveErr != nil {
 					// Resolver already reported a concrete error; avoid double-recording.
 					continue
 				}
-				recordSkipped(result.Changes, fc.violation, SkipResolveError, "resolver failed or missing")
+				fc.skip(c.violation, SkipResolveError, "resolver failed or missing")
 			}
 		}
 	}
 
 	// Phase 3: Run finalizers after all normal fixes. This catches cleanup
 	// opportunities introduced by earlier fixers, such as newly emitted heredocs.
-	f.applyFinalizers(ctx, result.Changes)
+	f.applyFinalizers(ctx, fc, finalizers)
 
-	return result, nil
+	// Phase 4: Re-parse (and re-lint) the modified file and roll it back
+	// if its fixes regressed, e.g. from resolver edge cases.
+	f.validateFile(ctx, fc, before)
+}
+
+// forEachFile calls fn for every file in changes, running up to
+// [Fixer.Concurrency] files in parallel. fn must only touch the FileChange
+// it is given; each file is then fixed the same way regardless of
+// scheduling, keeping the result deterministic.
+func (f *Fixer) forEachFile(changes map[string]*FileChange, fn func(file string, fc *FileChange)) {
+	files := slices.Sorted(maps.Keys(changes))
+	workers := min(f.concurrency(), len(files))
+	if workers <= 1 {
+		for _, file := range files {
+			fn(file, changes[file])
+		}
+		return
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for file := range jobs {
+				fn(file, changes[file])
+			}
+		})
+	}
+	for _, file := range files {
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// concurrency returns the number of files fixed in parallel.
+func (f *Fixer) concurrency() int {
+	if f.Concurrency <= 0 {
+		return defaultConcurrency
+	}
+	return f.Concurrency
+}
+
+// candidatesByFile groups candidates by normalized file path.
+func candidatesByFile(candidates []*fixCandidate) map[string][]*fixCandidate {
+	byFile := make(map[string][]*fixCandidate)
+	for _, c := range candidates {
+		file := normalizePath(c.violation.File())
+		byFile[file] = append(byFile[file], c)
+	}
+	return byFile
 }
 
 // initializeChanges populates the result with FileChange entries for each source file.
@@ -182,24 +306,18 @@ func (f *Fixer) classifyViolations(violations []rules.Violation, changes map[str
 	return syncCandidates, asyncCandidates
 }
 
-// applyCandidatesToFiles groups candidates by file and applies them.
-func (f *Fixer) applyCandidatesToFiles(changes map[string]*FileChange, candidates []*fixCandidate) {
-	byFile := make(map[string][]*fixCandidate)
-	for _, fc := range candidates {
-		if len(fc.fix.Edits) == 0 {
-			recordSkipped(changes, fc.violation, SkipNoEdits, "")
+// applySyncFixes applies the sync fix candidates of a single file.
+func (f *Fixer) applySyncFixes(fc *FileChange, candidates []*fixCandidate) {
+	withEdits := make([]*fixCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if len(c.fix.Edits) == 0 {
+			fc.skip(c.violation, SkipNoEdits, "")
 			continue
 		}
-		normalizedFile := normalizePath(fc.violation.File())
-		byFile[normalizedFile] = append(byFile[normalizedFile], fc)
+		withEdits = append(withEdits, c)
 	}
-
-	for file, fileCandidates := range byFile {
-		fc := changes[file]
-		if fc == nil {
-			continue
-		}
-		f.applyFixesToFile(fc, fileCandidates)
+	if len(withEdits) > 0 {
+		f.applyFixesToFile(fc, withEdits)
 	}
 }
 
@@ -212,18 +330,23 @@ type fixCandidate struct {
 // recordSkipped adds a skipped fix entry for a file if the file exists in changes.
 func recordSkipped(changes map[string]*FileChange, v *rules.Violation, reason SkipReason, errMsg string) {
 	if fc := changes[normalizePath(v.File())]; fc != nil {
-		skipped := SkippedFix{
-			RuleCode: v.RuleCode,
-			Reason:   reason,
-			Location: v.Location,
-		}
-		if errMsg != "" {
-			skipped.Error = errMsg
-		}
-		fc.FixesSkipped = append(fc.FixesSkipped, skipped)
+		fc.skip(v, reason, errMsg)
 	}
 }
 
+// skip records a skipped fix for violation v.
+func (fc *FileChange) skip(v *rules.Violation, reason SkipReason, errMsg string) {
+	skipped := SkippedFix{
+		RuleCode: v.RuleCode,
+		Reason:   reason,
+		Location: v.Location,
+	}
+	if errMsg != "" {
+		skipped.Error = errMsg
+	}
+	fc.FixesSkipped = append(fc.FixesSkipped, skipped)
+}
+
 // ruleAllowed checks if a rule passes the filter.
 func (f *Fixer) ruleAllowed(ruleCode string) bool {
 	if len(f.RuleFilter) == 0 {
@@ -281,72 +404,63 @@ func (f *Fixer) ruleEnabledForFile(filePath, ruleCode string) bool {
 	return false
 }
 
-func (f *Fixer) applyFinalizers(ctx context.Context, changes map[string]*FileChange) {
+// sortedFinalizers returns the registered finalizers in the order they run:
+// by priority, then rule code.
+func sortedFinalizers() []Finalizer {
 	finalizers := registeredFinalizers()
-	if len(finalizers) == 0 {
-		return
-	}
 	slices.SortStableFunc(finalizers, func(a, b Finalizer) int {
 		if c := cmp.Compare(a.Priority(), b.Priority()); c != 0 {
 			return c
 		}
 		return cmp.Compare(a.RuleCode(), b.RuleCode())
 	})
+	return finalizers
+}
 
-	files := make([]string, 0, len(changes))
-	for file := range changes {
-		files = append(files, file)
-	}
-	slices.Sort(files)
-
-	for _, file := range files {
-		fc := changes[file]
-		if fc == nil {
+// applyFinalizers runs finalizers over a single file after its other fixes.
+func (f *Fixer) applyFinalizers(ctx context.Context, fc *FileChange, finalizers []Finalizer) {
+	for _, finalizer := range finalizers {
+		if !f.finalizerAllowed(fc.Path, finalizer) {
 			continue
 		}
-		for _, finalizer := range finalizers {
-			if !f.finalizerAllowed(fc.Path, finalizer) {
-				continue
-			}
-			edits, err := finalizer.Finalize(ctx, FinalizeContext{
-				FilePath:          fc.Path,
-				Content:           fc.ModifiedContent,
-				SlowChecksEnabled: f.slowChecksEnabledForFile(fc.Path),
+		edits, err := finalizer.Finalize(ctx, FinalizeContext{
+			FilePath:          fc.Path,
+			Content:           fc.ModifiedContent,
+			SlowChecksEnabled: f.slowChecksEnabledForFile(fc.Path),
+		})
+		if err != nil {
+			fc.FixesSkipped = append(fc.FixesSkipped, SkippedFix{
+				RuleCode: finalizer.RuleCode(),
+				Reason:   SkipResolveError,
+				Location: rules.NewFileLocation(fc.Path),
+				Error:    err.Error(),
 			})
-			if err != nil {
-				fc.FixesSkipped = append(fc.FixesSkipped, SkippedFix{
-					RuleCode: finalizer.RuleCode(),
-					Reason:   SkipResolveError,
-					Location: rules.NewFileLocation(fc.Path),
-					Error:    err.Error(),
-				})
-				continue
-			}
-			if len(edits) == 0 {
-				continue
-			}
+			continue
+		}
+		if len(edits) == 0 {
+			continue
+		}
 
-			severity := rules.SeverityStyle
-			if rule := rules.DefaultRegistry().Get(finalizer.RuleCode()); rule != nil {
-				severity = rule.Metadata().DefaultSeverity
-			}
-			v := rules.NewViolation(
-				rules.NewFileLocation(fc.Path),
-				finalizer.RuleCode(),
-				finalizer.Description(),
-				severity,
-			)
-			f.applyFixesToFile(fc, []*fixCandidate{{
-				violation: &v,
-				fix: &rules.SuggestedFix{
-					Description: finalizer.Description(),
-					Safety:      finalizer.Safety(),
-					Priority:    finalizer.Priority(),
-					Edits:       edits,
-					IsPreferred: true,
-				},
-			}})
+		severity := rules.SeverityStyle
+		if rule := rules.DefaultRegistry().Get(finalizer.RuleCode()); rule != nil {
+			severity = rule.Metadata().DefaultSeverity
 		}
+		v := rules.NewViolation(
+			rules.NewFileLocation(fc.Path),
+			finalizer.RuleCode(),
+			finalizer.Description(),
+			severity,
+		)
+		f.applyFixesToFile(fc, []*fixCandidate{{
+			violation: &v,
+			fix: &rules.SuggestedFix{
+				Description: finalizer.Description(),
+				Safety:      finalizer.Safety(),
+				Priority:    finalizer.Priority(),
+				Edits:       edits,
+				IsPreferred: true,
+			},
+		}})
 	}
 }
 
@@ -381,6 +495,14 @@ func (f *Fixer) finalizerAllowed(filePath string, finalizer Finalizer) bool {
 	return f.fixModeAllowed(filePath, ruleCode)
 }
 
+// fixPrecedenceForFile returns the fix-precedence patterns for filePath.
+func (f *Fixer) fixPrecedenceForFile(filePath string) []string {
+	if f.FixPrecedence == nil {
+		return nil
+	}
+	return f.FixPrecedence[normalizePath(filePath)]
+}
+
 func (f *Fixer) safetyThresholdForFile(filePath string) FixSafety {
 	if f.SafetyThresholds == nil {
 		return f.SafetyThreshold
@@ -398,81 +520,63 @@ func (f *Fixer) safetyThresholdForFile(filePath string) FixSafety {
 	return f.SafetyThreshold
 }
 
-// resolveAsyncFixes runs resolvers for fixes that need external data.
-// This is called AFTER sync fixes have been applied, so resolvers receive
-// the modified content and can compute correct positions.
+// resolveAsyncFixes runs resolvers for the fixes of a single file that need
+// external data. This is called AFTER sync fixes have been applied, so
+// resolvers receive the modified content and can compute correct positions.
 //
 // IMPORTANT: Async fixes are resolved and applied ONE AT A TIME, sequentially.
 // This ensures each resolver sees the content after previous async fixes were applied,
 // avoiding position drift between async fixes.
-func (f *Fixer) resolveAsyncFixes(ctx context.Context, changes map[string]*FileChange, candidates []*fixCandidate) {
-	byFile := make(map[string][]*fixCandidate)
-	for _, candidate := range candidates {
-		if candidate.fix.NeedsResolve {
-			normalizedFile := normalizePath(candidate.violation.File())
-			byFile[normalizedFile] = append(byFile[normalizedFile], candidate)
+func (f *Fixer) resolveAsyncFixes(ctx context.Context, fc *FileChange, candidates []*fixCandidate) {
+	// Resolve in SuggestedFix.Priority order so whole-file rewrites (high priority)
+	// run after content/structural async transforms for the same file.
+	slices.SortStableFunc(candidates, func(a, b *fixCandidate) int {
+		if c := cmp.Compare(a.fix.Priority, b.fix.Priority); c != 0 {
+			return c
 		}
-	}
-
-	files := make([]string, 0, len(byFile))
-	for file := range byFile {
-		files = append(files, file)
-	}
-	slices.Sort(files)
+		if c := cmp.Compare(a.violation.RuleCode, b.violation.RuleCode); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.violation.Location.Start.Line, b.violation.Location.Start.Line)
+	})
 
-	for _, file := range files {
-		fc := changes[file]
-		if fc == nil {
+	for _, candidate := range candidates {
+		fix := candidate.fix
+		if !fix.NeedsResolve {
 			continue
 		}
 
-		fileCandidates := byFile[file]
-		// Resolve in SuggestedFix.Priority order so whole-file rewrites (high priority)
-		// run after content/structural async transforms for the same file.
-		slices.SortStableFunc(fileCandidates, func(a, b *fixCandidate) int {
-			if c := cmp.Compare(a.fix.Priority, b.fix.Priority); c != 0 {
-				return c
-			}
-			if c := cmp.Compare(a.violation.RuleCode, b.violation.RuleCode); c != 0 {
-				return c
-			}
-			return cmp.Compare(a.violation.Location.Start.Line, b.violation.Location.Start.Line)
-		})
-
-		for _, candidate := range fileCandidates {
-			fix := candidate.fix
-			if !fix.NeedsResolve {
-				continue
-			}
-
-			resolver := GetResolver(fix.ResolverID)
-			if resolver == nil {
-				recordSkipped(changes, candidate.violation, SkipResolveError, "resolver not registered: "+fix.ResolverID)
-				fix.NeedsResolve = false
-				continue
-			}
+		resolver := GetResolver(fix.ResolverID)
+		if resolver == nil {
+			fc.skip(candidate.violation, SkipResolveError, "resolver not registered: "+fix.ResolverID)
+			fix.NeedsResolve = false
+			continue
+		}
 
-			resolveCtx := ResolveContext{
-				FilePath: fc.Path,
-				Content:  fc.ModifiedContent,
-			}
+		resolveCtx := ResolveContext{
+			FilePath: fc.Path,
+			Content:  fc.ModifiedContent,
+		}
 
-			// Resolve synchronously (sequential within a file to avoid position drift).
-			edits, err := resolver.Resolve(ctx, resolveCtx, fix)
-			if err != nil {
-				fix.ResolveErr = err
-				recordSkipped(changes, candidate.violation, SkipResolveError, err.Error())
-				fix.NeedsResolve = false
-				continue
-			}
-			fix.ResolveErr = nil
-			fix.Edits = edits
+		// Resolve synchronously (sequential within a file to avoid position drift).
+		start := time.Now()
+		edits, err := resolver.Resolve(ctx, resolveCtx, fix)
+		slog.DebugContext(ctx, "fix resolved",
+			"file", fc.Path, "rule", candidate.violation.RuleCode, "resolver", fix.ResolverID,
+			"edits", len(edits), "duration", time.Since(start), "error", err)
+		if err != nil {
+			fix.ResolveErr = err
+			fc.skip(candidate.violation, SkipResolveError, err.Error())
 			fix.NeedsResolve = false
+			continue
+		}
+		fix.ResolveErr = nil
+		fix.Edits = edits
+		fix.NeedsResolve = false
 
-			// Apply this fix immediately so the next resolver sees updated content.
-			if len(fix.Edits) > 0 {
-				f.applyFixesToFile(fc, []*fixCandidate{candidate})
-			}
+		// Apply this fix immediately so the next resolver sees updated content.
+		if len(fix.Edits) > 0 {
+			f.applyFixesToFile(fc, []*fixCandidate{candidate})
 		}
 	}
 }
@@ -486,7 +590,7 @@ func (f *Fixer) applyFixesToFile(fc *FileChange, candidates []*fixCandidate) {
 	// rewrites). We want "important" fixes (security/correctness/performance…)
 	// to win over stylistic ones when they conflict, while still applying edits
 	// in FixPriority+position order to avoid drift.
-	selected := selectNonConflictingCandidates(fc, candidates)
+	selected := selectNonConflictingCandidates(fc, candidates, f.fixPrecedenceForFile(fc.Path))
 
 	// Collect all edits with their source info (selected candidates only).
 	type editWithSource struct {
@@ -578,11 +682,18 @@ func (f *Fixer) applyFixesToFile(fc *FileChange, candidates []*fixCandidate) {
 	}
 }
 
-func selectNonConflictingCandidates(fc *FileChange, candidates []*fixCandidate) []*fixCandidate {
+func selectNonConflictingCandidates(fc *FileChange, candidates []*fixCandidate, precedence []string) []*fixCandidate {
 	// Prefer "important" fixes over stylistic ones when overlapping.
 	// We consider all candidates atomically: if ANY edit overlaps, the entire fix is skipped.
 	ordered := slices.Clone(candidates)
 	slices.SortStableFunc(ordered, func(a, b *fixCandidate) int {
+		// Configured fix-precedence first.
+		if c := cmp.Compare(
+			config.FixPrecedenceRank(precedence, b.violation.RuleCode),
+			config.FixPrecedenceRank(precedence, a.violation.RuleCode),
+		); c != 0 {
+			return c
+		}
 		// Higher importance first (style last).
 		if c := cmp.Compare(candidateImportanceRank(b), candidateImportanceRank(a)); c != 0 {
 			return c
@@ -811,11 +922,11 @@ func recordLineShift(shifts *[]lineShift, edit rules.TextEdit) {
 	}
 
 	afterLine := edit.Location.End.Line
-	// Zero-width insertion at column 0 (e.g., DL4006 SHELL insertion before a RUN):
-	// content AT the edit's line shifts down, so we use End.Line - 1 to ensure
-	// edits targeting the same line are also adjusted.
-	if edit.Location.Start.Line == edit.Location.End.Line &&
-		edit.Location.Start.Column == 0 && edit.Location.End.Column == 0 {
+	// An edit ending at column 0 leaves all of End.Line after it, so content
+	// AT that line shifts too: zero-width insertions before a line (e.g.,
+	// DL4006 SHELL insertion before a RUN) and whole-line deletions (e.g.,
+	// excess blank lines followed by an eol-last fix on the next line).
+	if edit.Location.End.Column == 0 {
 		afterLine = edit.Location.End.Line - 1
 	}
 
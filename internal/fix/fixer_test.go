@@ -570,6 +570,66 @@ func TestFixer_Apply_CrossPrioritySameLengthReplace(t *testing.T) {
 	}
 }
 
+func TestFixer_Apply_CrossPriorityLineDeletionBeforeEdit(t *testing.T) {
+	t.Parallel()
+	// A deletion of whole lines ends at column 0 of the next line, which then
+	// moves up. A later edit on that line must follow it.
+	//
+	// Scenario (excess blank lines + missing final newline):
+	// - Priority 98: delete [4, 0, 5, 0] (the blank line before CMD)
+	// - Priority 99: insert "\n" at [5, 12, 5, 12] (end of file)
+
+	sources := map[string][]byte{
+		"Dockerfile": []byte("FROM alpine\n\n\n\nCMD [\"/app\"]"),
+	}
+
+	violations := []rules.Violation{
+		{
+			Location: rules.NewLineLocation("Dockerfile", 4),
+			RuleCode: "blank-lines",
+			Message:  "too many blank lines",
+			SuggestedFix: &rules.SuggestedFix{
+				Description: "Remove blank line",
+				Safety:      rules.FixSafe,
+				Priority:    98,
+				Edits: []rules.TextEdit{
+					{
+						Location: rules.NewRangeLocation("Dockerfile", 4, 0, 5, 0),
+						NewText:  "",
+					},
+				},
+			},
+		},
+		{
+			Location: rules.NewLineLocation("Dockerfile", 5),
+			RuleCode: "eol",
+			Message:  "missing final newline",
+			SuggestedFix: &rules.SuggestedFix{
+				Description: "Add newline",
+				Safety:      rules.FixSafe,
+				Priority:    99,
+				Edits: []rules.TextEdit{
+					{
+						Location: rules.NewRangeLocation("Dockerfile", 5, 12, 5, 12),
+						NewText:  "\n",
+					},
+				},
+			},
+		},
+	}
+
+	fixer := &Fixer{SafetyThreshold: FixSafe}
+	result, err := fixer.Apply(context.Background(), violations, sources)
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	want := "FROM alpine\n\n\nCMD [\"/app\"]\n"
+	if got := string(result.Changes["Dockerfile"].ModifiedContent); got != want {
+		t.Errorf("ModifiedContent =\n%q\nwant:\n%q", got, want)
+	}
+}
+
 func TestFixer_Apply_MultipleFixes(t *testing.T) {
 	t.Parallel()
 	sources := map[string][]byte{
@@ -0,0 +1,68 @@
+package fix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// iterate re-lints and re-fixes each modified file until it reaches a fixed
+// point or [Fixer.Iterations] iterations have run. Fixes from later
+// iterations are appended to the file's applied fixes.
+//
+// An iteration that would return a file to the content of an earlier one
+// means two fixes keep undoing each other. That iteration is discarded, its
+// fixes are recorded as skipped with [SkipOscillation], and the file keeps the
+// content of the previous iteration.
+func (f *Fixer) iterate(ctx context.Context, result *Result) {
+	f.forEachFile(result.Changes, func(_ string, fc *FileChange) {
+		if fc.HasChanges() {
+			f.iterateFile(ctx, fc)
+		}
+	})
+}
+
+func (f *Fixer) iterateFile(ctx context.Context, fc *FileChange) {
+	seen := map[string]bool{
+		string(fc.OriginalContent): true,
+		string(fc.ModifiedContent): true,
+	}
+	for iteration := 1; ; iteration++ {
+		relinted, err := f.Relint(ctx, fc.Path, fc.ModifiedContent)
+		if err != nil {
+			return
+		}
+		fc.relinted, fc.hasRelinted = relinted, true
+		if iteration >= f.Iterations {
+			return
+		}
+
+		next, err := f.applyIteration(ctx, relinted, map[string][]byte{fc.Path: fc.ModifiedContent})
+		if err != nil {
+			return
+		}
+		nc := next.Changes[normalizePath(fc.Path)]
+		if nc == nil || !nc.HasChanges() || bytes.Equal(nc.ModifiedContent, fc.ModifiedContent) {
+			// Fixed point: nothing left to fix.
+			return
+		}
+		if seen[string(nc.ModifiedContent)] {
+			for _, af := range nc.FixesApplied {
+				fc.FixesSkipped = append(fc.FixesSkipped, SkippedFix{
+					RuleCode: af.RuleCode,
+					Reason:   SkipOscillation,
+					Location: af.Location,
+					Error:    fmt.Sprintf("fix iteration %d would undo an earlier iteration", iteration+1),
+				})
+			}
+			return
+		}
+		seen[string(nc.ModifiedContent)] = true
+
+		for _, af := range nc.FixesApplied {
+			af.Iteration = iteration
+			fc.FixesApplied = append(fc.FixesApplied, af)
+		}
+		fc.ModifiedContent = nc.ModifiedContent
+	}
+}
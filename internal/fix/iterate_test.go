@@ -0,0 +1,114 @@
+package fix
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestFixer_Apply_IterationsReachFixedPoint(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	lateRule := rules.Violation{
+		Location: rules.NewLineLocation("Dockerfile", 2),
+		RuleCode: "test/yes",
+		Message:  "missing -y",
+	}
+	violations := []rules.Violation{
+		replaceFixViolation("test/apt-get", 2, 4, 7, "apt-get"),
+		lateRule,
+	}
+
+	relints := 0
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Iterations:      5,
+		Relint: func(_ context.Context, _ string, content []byte) ([]rules.Violation, error) {
+			relints++
+			if strings.Contains(string(content), "apt-get install curl") {
+				// Only fixable once apt has become apt-get.
+				return []rules.Violation{replaceFixViolation("test/yes", 2, 20, 20, "-y ")}, nil
+			}
+			return nil, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if want := "FROM alpine\nRUN apt-get install -y curl\n"; string(fc.ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", fc.ModifiedContent, want)
+	}
+	if len(fc.FixesApplied) != 2 || fc.FixesApplied[1].RuleCode != "test/yes" || fc.FixesApplied[1].Iteration != 1 {
+		t.Fatalf("FixesApplied = %+v, want test/yes applied in iteration 1", fc.FixesApplied)
+	}
+	if relints > 10 {
+		t.Errorf("Relint called %d times, want the loop to stop at the fixed point", relints)
+	}
+
+	remaining := FilterFixedViolations(violations, result, nil)
+	if len(remaining) != 0 {
+		t.Errorf("remaining violations = %+v, want test/yes resolved by the later iteration", remaining)
+	}
+}
+
+func TestFixer_Apply_SingleIterationByDefault(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	violations := []rules.Violation{replaceFixViolation("test/apt-get", 2, 4, 7, "apt-get")}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Relint: func(_ context.Context, _ string, content []byte) ([]rules.Violation, error) {
+			if strings.Contains(string(content), "apt-get install curl") {
+				return []rules.Violation{replaceFixViolation("test/yes", 2, 20, 20, "-y ")}, nil
+			}
+			return nil, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	if want := "FROM alpine\nRUN apt-get install curl\n"; string(result.Changes["Dockerfile"].ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", result.Changes["Dockerfile"].ModifiedContent, want)
+	}
+}
+
+func TestFixer_Apply_IterationsStopOnOscillation(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN get curl\n")
+	violations := []rules.Violation{replaceFixViolation("test/flip", 2, 8, 12, "wget")}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Iterations:      5,
+		Relint: func(_ context.Context, _ string, content []byte) ([]rules.Violation, error) {
+			// Two "fixes" that undo each other.
+			if strings.Contains(string(content), "wget") {
+				return []rules.Violation{replaceFixViolation("test/flop", 2, 8, 12, "curl")}, nil
+			}
+			return []rules.Violation{replaceFixViolation("test/flip", 2, 8, 12, "wget")}, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if want := "FROM alpine\nRUN get wget\n"; string(fc.ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", fc.ModifiedContent, want)
+	}
+	if len(fc.FixesApplied) != 1 {
+		t.Errorf("FixesApplied = %+v, want only the first iteration's fix", fc.FixesApplied)
+	}
+	if len(fc.FixesSkipped) != 1 || fc.FixesSkipped[0].RuleCode != "test/flop" || fc.FixesSkipped[0].Reason != SkipOscillation {
+		t.Errorf("FixesSkipped = %+v, want test/flop skipped for oscillation", fc.FixesSkipped)
+	}
+}
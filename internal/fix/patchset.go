@@ -0,0 +1,176 @@
+package fix
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// PatchSet describes the outcome of fixing in-memory sources as data: for
+// each file, the replacements that turn its original content into the fixed
+// content. Computing a patch set never touches the file system, so library
+// consumers and the LSP server can apply the changes their own way.
+type PatchSet struct {
+	// Files lists the files with applied or skipped fixes, sorted by path.
+	Files []FilePatch
+}
+
+// FilePatch describes the fixes for a single file.
+type FilePatch struct {
+	// Path is the file path as given in the sources.
+	Path string
+
+	// Replacements turn the original content into Content. They are sorted,
+	// do not overlap, and their ranges reference the original content.
+	// Empty when no fix changed the file.
+	Replacements []Replacement
+
+	// Content is the file content after fixes.
+	Content []byte
+
+	// FixesApplied lists the fixes that were applied.
+	FixesApplied []AppliedFix
+
+	// FixesSkipped lists fixes that couldn't be applied.
+	FixesSkipped []SkippedFix
+}
+
+// Replacement replaces a range of the original content with new text.
+type Replacement struct {
+	// Start and End are the byte offsets of the replaced range (end exclusive).
+	Start, End int
+
+	// Location is the replaced range with 1-based lines and 0-based byte
+	// columns, like rules.TextEdit.
+	Location rules.Location
+
+	// NewText is the replacement text. An empty NewText deletes the range.
+	NewText string
+}
+
+// Patch applies fixes to sources like [Fixer.Apply] and returns the result as
+// a patch set. sources maps file paths to their original content.
+func (f *Fixer) Patch(ctx context.Context, violations []rules.Violation, sources map[string][]byte) (*PatchSet, error) {
+	result, err := f.Apply(ctx, violations, sources)
+	if err != nil {
+		return nil, err
+	}
+	return result.PatchSet(), nil
+}
+
+// PatchSet returns the changes of the result as a patch set. Files without
+// applied or skipped fixes are omitted.
+func (r *Result) PatchSet() *PatchSet {
+	ps := &PatchSet{}
+	for _, key := range slices.Sorted(maps.Keys(r.Changes)) {
+		fc := r.Changes[key]
+		var replacements []Replacement
+		if fc.HasChanges() {
+			replacements = Diff(fc.Path, fc.OriginalContent, fc.ModifiedContent)
+		}
+		if len(replacements) == 0 && len(fc.FixesSkipped) == 0 {
+			continue
+		}
+		ps.Files = append(ps.Files, FilePatch{
+			Path:         fc.Path,
+			Replacements: replacements,
+			Content:      fc.ModifiedContent,
+			FixesApplied: fc.FixesApplied,
+			FixesSkipped: fc.FixesSkipped,
+		})
+	}
+	return ps
+}
+
+// Diff returns the replacements that turn original into modified. Changed
+// lines are grouped into one replacement per hunk, trimmed to the bytes that
+// differ without splitting UTF-8 runes. path is recorded in each Location.
+func Diff(path string, original, modified []byte) []Replacement {
+	if bytes.Equal(original, modified) {
+		return nil
+	}
+	a, b := splitLinesKeepEnds(original), splitLinesKeepEnds(modified)
+	aOffsets := lineOffsets(a)
+
+	var out []Replacement
+	for _, op := range difflib.NewMatcher(a, b).GetOpCodes() {
+		if op.Tag == 'e' {
+			continue
+		}
+		start, end := aOffsets[op.I1], aOffsets[op.I2]
+		replacement := strings.Join(b[op.J1:op.J2], "")
+		prefix, suffix := commonAffixes(original[start:end], []byte(replacement))
+		start, end = start+prefix, end-suffix
+		startLine, startCol := positionAt(original, start)
+		endLine, endCol := positionAt(original, end)
+		out = append(out, Replacement{
+			Start:    start,
+			End:      end,
+			Location: rules.NewRangeLocation(path, startLine, startCol, endLine, endCol),
+			NewText:  replacement[prefix : len(replacement)-suffix],
+		})
+	}
+	return out
+}
+
+// splitLinesKeepEnds splits content after each newline; joining the lines
+// reproduces content exactly.
+func splitLinesKeepEnds(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineOffsets returns the byte offset of each line start, plus the total
+// length as a final entry.
+func lineOffsets(lines []string) []int {
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = offsets[i] + len(line)
+	}
+	return offsets
+}
+
+// positionAt returns the 1-based line and 0-based byte column of offset.
+func positionAt(content []byte, offset int) (int, int) {
+	before := content[:offset]
+	return bytes.Count(before, []byte{'\n'}) + 1, offset - (bytes.LastIndexByte(before, '\n') + 1)
+}
+
+// commonAffixes returns the lengths of the longest common prefix and suffix
+// of a and b in whole runes. The suffix never overlaps the prefix.
+func commonAffixes(a, b []byte) (int, int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) {
+		r1, size1 := utf8.DecodeRune(a[prefix:])
+		r2, size2 := utf8.DecodeRune(b[prefix:])
+		if r1 != r2 || size1 != size2 || !bytes.Equal(a[prefix:prefix+size1], b[prefix:prefix+size2]) {
+			break
+		}
+		prefix += size1
+	}
+
+	aEnd, bEnd := len(a), len(b)
+	for aEnd > prefix && bEnd > prefix {
+		r1, size1 := utf8.DecodeLastRune(a[:aEnd])
+		r2, size2 := utf8.DecodeLastRune(b[:bEnd])
+		if aEnd-size1 < prefix || bEnd-size2 < prefix {
+			break
+		}
+		if r1 != r2 || size1 != size2 || !bytes.Equal(a[aEnd-size1:aEnd], b[bEnd-size2:bEnd]) {
+			break
+		}
+		aEnd -= size1
+		bEnd -= size2
+	}
+	return prefix, len(a) - aEnd
+}
@@ -0,0 +1,161 @@
+package fix
+
+import (
+	"context"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// applyReplacements applies replacements to original, back to front.
+func applyReplacements(original []byte, replacements []Replacement) []byte {
+	out := string(original)
+	for i := len(replacements) - 1; i >= 0; i-- {
+		r := replacements[i]
+		out = out[:r.Start] + r.NewText + out[r.End:]
+	}
+	return []byte(out)
+}
+
+func TestFixer_Patch(t *testing.T) {
+	t.Parallel()
+	sources := map[string][]byte{
+		"Dockerfile":    []byte("FROM alpine\nRUN apt install curl\nRUN echo ok\nRUN apt update\n"),
+		"b/Dockerfile":  []byte("FROM alpine\n"),
+		"unchanged.txt": []byte("FROM alpine\n"),
+	}
+	replace := func(file string, line, startCol, endCol int, safety rules.FixSafety) rules.Violation {
+		return rules.Violation{
+			Location: rules.NewLineLocation(file, line),
+			RuleCode: "hadolint/DL3027",
+			SuggestedFix: &rules.SuggestedFix{
+				Description: "Replace apt with apt-get",
+				Safety:      safety,
+				Edits: []rules.TextEdit{{
+					Location: rules.NewRangeLocation(file, line, startCol, line, endCol),
+					NewText:  "apt-get",
+				}},
+			},
+		}
+	}
+	violations := []rules.Violation{
+		replace("Dockerfile", 2, 4, 7, rules.FixSafe),
+		replace("Dockerfile", 4, 4, 7, rules.FixSafe),
+		replace("b/Dockerfile", 1, 0, 4, rules.FixUnsafe),
+	}
+
+	ps, err := (&Fixer{SafetyThreshold: FixSafe}).Patch(context.Background(), violations, sources)
+	if err != nil {
+		t.Fatalf("Patch error: %v", err)
+	}
+	if len(ps.Files) != 2 || ps.Files[0].Path != "Dockerfile" || ps.Files[1].Path != "b/Dockerfile" {
+		t.Fatalf("Files = %+v, want Dockerfile and b/Dockerfile", ps.Files)
+	}
+
+	fp := ps.Files[0]
+	want := "FROM alpine\nRUN apt-get install curl\nRUN echo ok\nRUN apt-get update\n"
+	if string(fp.Content) != want {
+		t.Errorf("Content = %q, want %q", fp.Content, want)
+	}
+	if len(fp.Replacements) != 2 || len(fp.FixesApplied) != 2 {
+		t.Fatalf("got %d replacements, %d applied fixes, want 2 each", len(fp.Replacements), len(fp.FixesApplied))
+	}
+	if got := applyReplacements(sources["Dockerfile"], fp.Replacements); string(got) != want {
+		t.Errorf("replacements produce %q, want %q", got, want)
+	}
+	if r := fp.Replacements[1]; r.NewText != "-get" ||
+		r.Location != rules.NewRangeLocation("Dockerfile", 4, 7, 4, 7) {
+		t.Errorf("second replacement = %+v, want insertion of -get at 4:7", r)
+	}
+
+	// The unsafe fix is only reported as skipped.
+	if skipped := ps.Files[1]; len(skipped.Replacements) != 0 || len(skipped.FixesSkipped) != 1 ||
+		string(skipped.Content) != "FROM alpine\n" {
+		t.Errorf("b/Dockerfile = %+v, want one skipped fix and no replacements", skipped)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name               string
+		original, modified string
+		want               []Replacement
+	}{
+		{
+			name:     "equal",
+			original: "FROM alpine\n",
+			modified: "FROM alpine\n",
+		},
+		{
+			name:     "inserted line",
+			original: "FROM alpine\nRUN a\n",
+			modified: "FROM alpine\nWORKDIR /app\nRUN a\n",
+			want: []Replacement{{
+				Start: 12, End: 12, NewText: "WORKDIR /app\n",
+				Location: rules.NewRangeLocation("Dockerfile", 2, 0, 2, 0),
+			}},
+		},
+		{
+			name:     "deleted line",
+			original: "FROM alpine\n\n\nRUN a\n",
+			modified: "FROM alpine\n\nRUN a\n",
+			want: []Replacement{{
+				Start: 13, End: 14,
+				Location: rules.NewRangeLocation("Dockerfile", 3, 0, 4, 0),
+			}},
+		},
+		{
+			name:     "missing final newline",
+			original: "FROM alpine",
+			modified: "FROM alpine\n",
+			want: []Replacement{{
+				Start: 11, End: 11, NewText: "\n",
+				Location: rules.NewRangeLocation("Dockerfile", 1, 11, 1, 11),
+			}},
+		},
+		{
+			// The runes share their leading byte.
+			name:     "prefix shares bytes",
+			original: "a🙂b",
+			modified: "a🙃b",
+			want: []Replacement{{
+				Start: 1, End: 5, NewText: "🙃",
+				Location: rules.NewRangeLocation("Dockerfile", 1, 1, 1, 5),
+			}},
+		},
+		{
+			// The runes share their trailing byte, so byte-wise suffix
+			// scanning would match a partial rune.
+			name:     "suffix shares bytes",
+			original: "xé",
+			modified: "xĩ",
+			want: []Replacement{{
+				Start: 1, End: 3, NewText: "ĩ",
+				Location: rules.NewRangeLocation("Dockerfile", 1, 1, 1, 3),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := Diff("Dockerfile", []byte(tt.original), []byte(tt.modified))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Diff() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("replacement %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+				if !utf8.ValidString(got[i].NewText) || !utf8.ValidString(tt.original[got[i].Start:got[i].End]) {
+					t.Errorf("replacement %d splits a rune: %+v", i, got[i])
+				}
+			}
+			if applied := applyReplacements([]byte(tt.original), got); string(applied) != tt.modified {
+				t.Errorf("replacements produce %q, want %q", applied, tt.modified)
+			}
+		})
+	}
+}
@@ -24,7 +24,8 @@ type ResolveContext struct {
 //
 // Resolvers are called AFTER sync fixes have been applied to the file.
 // This allows structural transforms to operate on already-modified content,
-// avoiding position drift issues.
+// avoiding position drift issues. Fixes within a file are resolved one at a
+// time, but Resolve may be called concurrently for different files.
 //
 // Examples:
 //   - Image digest resolver: fetches digests from container registries
@@ -0,0 +1,105 @@
+package fix
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// retryConflictingFixes re-attempts sync fixes that lost a conflict.
+//
+// A losing fix's edits were computed against the original content, which the
+// winning fixes have since rewritten, so they cannot be replayed. Instead a
+// file with conflict losers is re-linted (see [Fixer.Relint]) and the losing
+// rules' fresh fixes are applied in their place, paired with the skipped
+// violations in source order. A retried fix is recorded against the original
+// violation so callers can match it; a fix that conflicts again, or whose
+// rule no longer reports anything, stays skipped.
+func (f *Fixer) retryConflictingFixes(ctx context.Context, fc *FileChange) {
+	if f.Relint == nil {
+		return
+	}
+
+	losers := conflictLosers(fc)
+	if len(losers) == 0 || bytes.Equal(fc.OriginalContent, fc.ModifiedContent) {
+		return
+	}
+
+	relinted, err := f.Relint(ctx, fc.Path, fc.ModifiedContent)
+	if err != nil {
+		return
+	}
+	// A nil changes map drops skip records for the relinted violations;
+	// the original skips stand for them.
+	fresh, _ := f.classifyViolations(relinted, nil)
+	byRule := make(map[string][]*fixCandidate)
+	for _, c := range fresh {
+		if len(c.fix.Edits) > 0 {
+			byRule[c.violation.RuleCode] = append(byRule[c.violation.RuleCode], c)
+		}
+	}
+
+	var retry []*fixCandidate
+	retried := make(map[int]bool)
+	for _, rule := range slices.Sorted(maps.Keys(losers)) {
+		candidates := byRule[rule]
+		slices.SortStableFunc(candidates, func(a, b *fixCandidate) int {
+			return compareLocations(a.violation.Location, b.violation.Location)
+		})
+		for i, skipIdx := range losers[rule] {
+			if i >= len(candidates) {
+				break
+			}
+			v := *candidates[i].violation
+			v.Location = fc.FixesSkipped[skipIdx].Location
+			retry = append(retry, &fixCandidate{violation: &v, fix: candidates[i].fix})
+			retried[skipIdx] = true
+		}
+	}
+	if len(retry) == 0 {
+		return
+	}
+
+	// Drop the first-pass skips being retried; applyFixesToFile records
+	// the retry's own outcome.
+	kept := fc.FixesSkipped[:0]
+	for i, s := range fc.FixesSkipped {
+		if !retried[i] {
+			kept = append(kept, s)
+		}
+	}
+	fc.FixesSkipped = kept
+	f.applyFixesToFile(fc, retry)
+}
+
+// conflictLosers returns the indexes into fc.FixesSkipped of fixes skipped
+// for a conflict, grouped by rule code and sorted by location.
+func conflictLosers(fc *FileChange) map[string][]int {
+	if fc == nil {
+		return nil
+	}
+	losers := make(map[string][]int)
+	for i, s := range fc.FixesSkipped {
+		if s.Reason == SkipConflict {
+			losers[s.RuleCode] = append(losers[s.RuleCode], i)
+		}
+	}
+	for _, idxs := range losers {
+		slices.SortStableFunc(idxs, func(a, b int) int {
+			return compareLocations(fc.FixesSkipped[a].Location, fc.FixesSkipped[b].Location)
+		})
+	}
+	return losers
+}
+
+// compareLocations orders locations by start position.
+func compareLocations(a, b rules.Location) int {
+	if c := cmp.Compare(a.Start.Line, b.Start.Line); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Start.Column, b.Start.Column)
+}
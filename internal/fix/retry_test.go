@@ -0,0 +1,103 @@
+package fix
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestFixer_Apply_FixPrecedenceWinsConflict(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	violations := []rules.Violation{
+		replaceFixViolation("test/a", 2, 4, 15, "apt-get install"),
+		replaceFixViolation("test/b", 2, 8, 20, "install -y curl"),
+	}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		FixPrecedence:   map[string][]string{"Dockerfile": {"test/b"}},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if want := "FROM alpine\nRUN apt install -y curl\n"; string(fc.ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", fc.ModifiedContent, want)
+	}
+	if len(fc.FixesSkipped) != 1 || fc.FixesSkipped[0].RuleCode != "test/a" || fc.FixesSkipped[0].Reason != SkipConflict {
+		t.Errorf("FixesSkipped = %+v, want test/a skipped for conflict", fc.FixesSkipped)
+	}
+}
+
+func TestFixer_Apply_RetriesConflictLoserAgainstUpdatedContent(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	violations := []rules.Violation{
+		replaceFixViolation("test/a", 2, 4, 15, "apt-get install"),
+		replaceFixViolation("test/b", 2, 8, 20, "install -y curl"),
+	}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Relint: func(_ context.Context, _ string, content []byte) ([]rules.Violation, error) {
+			if strings.Contains(string(content), "-y") {
+				return nil, nil
+			}
+			// test/b is reported again, with edits for the rewritten line.
+			return []rules.Violation{replaceFixViolation("test/b", 2, 12, 24, "install -y curl")}, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if want := "FROM alpine\nRUN apt-get install -y curl\n"; string(fc.ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", fc.ModifiedContent, want)
+	}
+	if len(fc.FixesSkipped) != 0 {
+		t.Errorf("FixesSkipped = %+v, want none", fc.FixesSkipped)
+	}
+	if len(fc.FixesApplied) != 2 {
+		t.Fatalf("FixesApplied = %+v, want both fixes", fc.FixesApplied)
+	}
+	for _, af := range fc.FixesApplied {
+		if af.RuleCode == "test/b" && af.Location != violations[1].Location {
+			t.Errorf("retried fix location = %+v, want the original violation's %+v", af.Location, violations[1].Location)
+		}
+	}
+}
+
+func TestFixer_Apply_ConflictLoserStaysSkippedWhenNotReportedAgain(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	violations := []rules.Violation{
+		replaceFixViolation("test/a", 2, 4, 15, "apt-get install"),
+		replaceFixViolation("test/b", 2, 8, 20, "install -y curl"),
+	}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Relint: func(_ context.Context, _ string, _ []byte) ([]rules.Violation, error) {
+			return nil, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if want := "FROM alpine\nRUN apt-get install curl\n"; string(fc.ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", fc.ModifiedContent, want)
+	}
+	if len(fc.FixesSkipped) != 1 || fc.FixesSkipped[0].RuleCode != "test/b" || fc.FixesSkipped[0].Reason != SkipConflict {
+		t.Errorf("FixesSkipped = %+v, want test/b skipped for conflict", fc.FixesSkipped)
+	}
+}
@@ -12,6 +12,8 @@ import (
 //
 // A violation is removed if:
 //   - It was directly fixed (exact location+rule match in fixResult), or
+//   - Its rule was fixed in a later fix iteration and is no longer reported
+//     for the modified file content, or
 //   - The rule implements PostFixRevalidator and RevalidateAfterFix returns
 //     false for the modified file content (the fix from another rule
 //     resolved the condition).
@@ -27,10 +29,20 @@ func FilterFixedViolations(
 		code string
 	}
 	fixed := make(map[locKey]bool)
+	// resolvedRules holds, per file, the rules fixed in a later iteration
+	// that the final content no longer reports. Later-iteration locations
+	// reference intermediate content, so they cannot be matched exactly.
+	resolvedRules := make(map[string]map[string]bool)
 
 	modifiedContent := make(map[string][]byte)
 	for _, fc := range fixResult.Changes {
+		if resolved := rulesResolvedByIterations(fc); len(resolved) > 0 {
+			resolvedRules[filepath.ToSlash(fc.Path)] = resolved
+		}
 		for _, af := range fc.FixesApplied {
+			if af.Iteration > 0 {
+				continue
+			}
 			fixed[locKey{
 				file: filepath.ToSlash(fc.Path),
 				line: af.Location.Start.Line,
@@ -51,7 +63,7 @@ func FilterFixedViolations(
 			col:  v.Location.Start.Column,
 			code: v.RuleCode,
 		}
-		if fixed[key] {
+		if fixed[key] || resolvedRules[key.file][v.RuleCode] {
 			continue
 		}
 
@@ -97,3 +109,21 @@ func shouldSuppressAfterFix(
 
 	return !revalidator.RevalidateAfterFix(v, modifiedContent, ruleCfg)
 }
+
+// rulesResolvedByIterations returns the rules fixed in a later fix iteration
+// of fc that the relinted final content no longer reports.
+func rulesResolvedByIterations(fc *FileChange) map[string]bool {
+	if !fc.hasRelinted {
+		return nil
+	}
+	resolved := make(map[string]bool)
+	for _, af := range fc.FixesApplied {
+		if af.Iteration > 0 {
+			resolved[af.RuleCode] = true
+		}
+	}
+	for _, v := range fc.relinted {
+		delete(resolved, v.RuleCode)
+	}
+	return resolved
+}
@@ -0,0 +1,213 @@
+package fix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// Relinter re-lints fixed file content, to retry fixes that lost a conflict
+// and for post-fix validation. It returns the violations reported for
+// content, filtered the same way as the violations passed to [Fixer.Apply]
+// (severity overrides, disabled rules, inline directives, ...), or an error
+// when content cannot be linted. It may be called concurrently for
+// different files (see [Fixer.Concurrency]).
+type Relinter func(ctx context.Context, filePath string, content []byte) ([]rules.Violation, error)
+
+// validateFile checks a file after all fixes have been applied and rolls
+// back its changes when the fixed content regressed:
+//   - the content no longer parses although the original did, or
+//   - a fixed violation is still reported on the same, unchanged source text,
+//     meaning its fix did not take effect.
+//
+// Violations on text that the fixes rewrote are not regressions: they may be
+// newly exposed by another fix and are left to the next lint run. Rolled-back
+// fixes are recorded as skipped with [SkipValidation] so callers can report
+// which fix regressed. before holds the violations reported for the file's
+// original content.
+func (f *Fixer) validateFile(ctx context.Context, fc *FileChange, before []rules.Violation) {
+	if !fc.HasChanges() || bytes.Equal(fc.OriginalContent, fc.ModifiedContent) {
+		return
+	}
+	if regressed, reason := f.validateChange(ctx, fc, before); reason != "" {
+		rollbackChange(fc, regressed, reason)
+	}
+}
+
+// flaggedText identifies violations of one rule on the same source text.
+type flaggedText struct {
+	rule string
+	text string
+}
+
+// validateChange returns the rule codes whose fixes regressed fc and a
+// description of the regression, or an empty reason when fc is valid.
+// before holds the violations reported for fc's original content.
+func (f *Fixer) validateChange(ctx context.Context, fc *FileChange, before []rules.Violation) ([]string, string) {
+	origParsed, origErr := parseStrict(fc.OriginalContent)
+	modParsed, err := parseStrict(fc.ModifiedContent)
+	if err != nil {
+		if origErr != nil {
+			// Nothing to regress from; leave the fixes in place.
+			return nil, ""
+		}
+		return unparsableFixes(fc), "fixed content no longer parses: " + err.Error()
+	}
+
+	if f.Relint == nil {
+		return nil, ""
+	}
+	after, err := f.Relint(ctx, fc.Path, fc.ModifiedContent)
+	if err != nil {
+		return appliedRuleCodes(fc), "fixed content cannot be linted: " + err.Error()
+	}
+
+	original := flaggedSource{sm: sourcemap.New(fc.OriginalContent)}
+	if origErr == nil {
+		original.ast = origParsed.AST.AST
+	}
+	modified := flaggedSource{sm: sourcemap.New(fc.ModifiedContent), ast: modParsed.AST.AST}
+
+	reported := make(map[flaggedText]int)
+	for i := range before {
+		reported[flaggedText{before[i].RuleCode, original.text(before[i].Location)}]++
+	}
+	stillReported := make(map[flaggedText]int)
+	firstLine := make(map[flaggedText]int)
+	for i := range after {
+		key := flaggedText{after[i].RuleCode, modified.text(after[i].Location)}
+		if stillReported[key] == 0 {
+			firstLine[key] = after[i].Line()
+		}
+		stillReported[key]++
+	}
+
+	fixed := make(map[flaggedText]int)
+	var keys []flaggedText
+	for _, af := range fc.FixesApplied {
+		key := flaggedText{af.RuleCode, original.text(af.Location)}
+		if key.text == "" {
+			// File-level fixes (e.g. finalizers) have no text to compare.
+			continue
+		}
+		if fixed[key] == 0 {
+			keys = append(keys, key)
+		}
+		fixed[key]++
+	}
+
+	var regressed, reasons []string
+	for _, key := range keys {
+		// Identical text may be flagged in several places; only the
+		// violations that were fixed have to be gone.
+		if stillReported[key] <= max(reported[key]-fixed[key], 0) || slices.Contains(regressed, key.rule) {
+			continue
+		}
+		regressed = append(regressed, key.rule)
+		reasons = append(reasons, fmt.Sprintf("%s is still reported at line %d", key.rule, firstLine[key]))
+	}
+	if len(regressed) == 0 {
+		return nil, ""
+	}
+	return regressed, strings.Join(reasons, "; ")
+}
+
+// flaggedSource resolves violation locations to the source text they flag.
+type flaggedSource struct {
+	sm  *sourcemap.SourceMap
+	ast *parser.Node
+}
+
+// text returns the source of the instruction containing loc, so that
+// violations are compared by the whole instruction they flag, or "" for
+// file-level locations.
+func (s flaggedSource) text(loc rules.Location) string {
+	if loc.IsFileLevel() || loc.Start.Line <= 0 {
+		return ""
+	}
+	start, end := loc.Start.Line, max(loc.End.Line, loc.Start.Line)
+	if s.ast != nil {
+		for _, node := range s.ast.Children {
+			if node.StartLine <= loc.Start.Line && loc.Start.Line <= node.EndLine {
+				start, end = node.StartLine, max(node.EndLine, end)
+				break
+			}
+		}
+	}
+	return s.sm.Snippet(start-1, end-1)
+}
+
+// unparsableFixes returns the rule codes of applied fixes that break parsing
+// on their own. Fixes resolved against intermediate content cannot be
+// replayed in isolation, so all applied rules are blamed when none of the
+// individual replays fails.
+func unparsableFixes(fc *FileChange) []string {
+	var culprits []string
+	for _, af := range fc.FixesApplied {
+		if len(af.Edits) == 0 {
+			continue
+		}
+		content := ApplyEdits(fc.OriginalContent, af.Edits)
+		if _, err := parseStrict(content); err != nil && !slices.Contains(culprits, af.RuleCode) {
+			culprits = append(culprits, af.RuleCode)
+		}
+	}
+	if len(culprits) == 0 {
+		return appliedRuleCodes(fc)
+	}
+	return culprits
+}
+
+// parseStrict parses content and treats instructions the parser had to drop
+// as a parse failure, since a fix must never introduce them.
+func parseStrict(content []byte) (*dockerfile.ParseResult, error) {
+	parsed, err := dockerfile.Parse(bytes.NewReader(content), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.SyntaxErrors) > 0 {
+		return nil, parsed.SyntaxErrors[0]
+	}
+	return parsed, nil
+}
+
+// appliedRuleCodes returns the distinct rule codes of fc's applied fixes in
+// application order.
+func appliedRuleCodes(fc *FileChange) []string {
+	var codes []string
+	for _, af := range fc.FixesApplied {
+		if !slices.Contains(codes, af.RuleCode) {
+			codes = append(codes, af.RuleCode)
+		}
+	}
+	return codes
+}
+
+// rollbackChange restores fc's original content and moves its applied fixes to
+// the skipped list, naming the regressed fixes in each entry.
+func rollbackChange(fc *FileChange, regressed []string, reason string) {
+	for _, af := range fc.FixesApplied {
+		msg := fmt.Sprintf("rolled back all fixes in %s: %s fix regressed (%s)",
+			filepath.Base(fc.Path), strings.Join(regressed, ", "), reason)
+		if slices.Contains(regressed, af.RuleCode) {
+			msg = fmt.Sprintf("fix regressed and was rolled back: %s", reason)
+		}
+		fc.FixesSkipped = append(fc.FixesSkipped, SkippedFix{
+			RuleCode: af.RuleCode,
+			Reason:   SkipValidation,
+			Location: af.Location,
+			Error:    msg,
+		})
+	}
+	fc.FixesApplied = nil
+	fc.ModifiedContent = bytes.Clone(fc.OriginalContent)
+}
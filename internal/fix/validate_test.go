@@ -0,0 +1,115 @@
+package fix
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func replaceFixViolation(ruleCode string, line, startCol, endCol int, newText string) rules.Violation {
+	return rules.Violation{
+		Location: rules.NewLineLocation("Dockerfile", line),
+		RuleCode: ruleCode,
+		Message:  "test violation",
+		SuggestedFix: &rules.SuggestedFix{
+			Description: "test fix",
+			Safety:      rules.FixSafe,
+			Edits: []rules.TextEdit{{
+				Location: rules.NewRangeLocation("Dockerfile", line, startCol, line, endCol),
+				NewText:  newText,
+			}},
+		},
+	}
+}
+
+func TestFixer_Apply_RollsBackUnparsableFix(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN echo hi\n")
+	violations := []rules.Violation{
+		replaceFixViolation("test/breaks-parse", 2, 0, 11, "COPY onlysrc"),
+		replaceFixViolation("test/ok", 1, 5, 11, "alpine:3.20"),
+	}
+
+	fixer := &Fixer{SafetyThreshold: FixSafe}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if fc.HasChanges() || !bytes.Equal(fc.ModifiedContent, original) {
+		t.Fatalf("expected the file to be rolled back, got applied=%d content=%q", len(fc.FixesApplied), fc.ModifiedContent)
+	}
+	if len(fc.FixesSkipped) != 2 {
+		t.Fatalf("FixesSkipped = %+v, want both fixes", fc.FixesSkipped)
+	}
+	for _, s := range fc.FixesSkipped {
+		if s.Reason != SkipValidation {
+			t.Errorf("%s skip reason = %v, want SkipValidation", s.RuleCode, s.Reason)
+		}
+		regressed := strings.HasPrefix(s.Error, "fix regressed")
+		if want := s.RuleCode == "test/breaks-parse"; regressed != want {
+			t.Errorf("%s error = %q, regressed = %v, want %v", s.RuleCode, s.Error, regressed, want)
+		}
+	}
+}
+
+func TestFixer_Apply_ValidateRollsBackIneffectiveFix(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	violations := []rules.Violation{
+		// A no-op "fix" leaves the flagged instruction unchanged.
+		replaceFixViolation("test/no-op", 2, 4, 7, "apt"),
+		replaceFixViolation("test/ok", 1, 5, 11, "alpine:3.20"),
+	}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Relint: func(_ context.Context, _ string, _ []byte) ([]rules.Violation, error) {
+			return []rules.Violation{{Location: rules.NewLineLocation("Dockerfile", 2), RuleCode: "test/no-op"}}, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if fc.HasChanges() || !bytes.Equal(fc.ModifiedContent, original) {
+		t.Fatalf("expected the file to be rolled back, got content %q", fc.ModifiedContent)
+	}
+	for _, s := range fc.FixesSkipped {
+		if !strings.Contains(s.Error, "test/no-op is still reported at line 2") {
+			t.Errorf("%s error = %q, want the regressed fix named", s.RuleCode, s.Error)
+		}
+	}
+}
+
+func TestFixer_Apply_ValidateKeepsFixesWithNewlyExposedViolations(t *testing.T) {
+	t.Parallel()
+	original := []byte("FROM alpine\nRUN apt install curl\n")
+	violations := []rules.Violation{replaceFixViolation("hadolint/DL3027", 2, 4, 7, "apt-get")}
+
+	fixer := &Fixer{
+		SafetyThreshold: FixSafe,
+		Relint: func(_ context.Context, _ string, _ []byte) ([]rules.Violation, error) {
+			// Same rule, but on the rewritten instruction: not a regression.
+			return []rules.Violation{{Location: rules.NewLineLocation("Dockerfile", 2), RuleCode: "hadolint/DL3027"}}, nil
+		},
+	}
+	result, err := fixer.Apply(context.Background(), violations, map[string][]byte{"Dockerfile": original})
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+
+	fc := result.Changes["Dockerfile"]
+	if want := "FROM alpine\nRUN apt-get install curl\n"; string(fc.ModifiedContent) != want {
+		t.Errorf("ModifiedContent = %q, want %q", fc.ModifiedContent, want)
+	}
+	if len(fc.FixesSkipped) != 0 {
+		t.Errorf("FixesSkipped = %+v, want none", fc.FixesSkipped)
+	}
+}
@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestChanged verifies that --changed only lints the Dockerfiles that git
+// status reports as modified, staged or untracked.
+func TestChanged(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_SYSTEM="+os.DevNull,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const dockerfile = "FROM alpine:3.20\nMAINTAINER me@example.com\n"
+	runGit("init", "-q")
+	write("api/Dockerfile", dockerfile)
+	write("web/Dockerfile", dockerfile)
+	runGit("add", ".")
+	runGit("commit", "-q", "-m", "init")
+
+	run := func() ([]string, string) {
+		t.Helper()
+		cmd := exec.Command(binaryPath, "lint", "--changed", "--format", "json", "--slow-checks=off",
+			"--ignore", "*", "--select", "buildkit/MaintainerDeprecated", ".")
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		_ = cmd.Run()
+
+		var report struct {
+			Files []struct {
+				File string `json:"file"`
+			} `json:"files"`
+		}
+		if stdout.Len() > 0 {
+			if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+				t.Fatalf("parse JSON output: %v\n%s", err, stdout.String())
+			}
+		}
+		var files []string
+		for _, f := range report.Files {
+			rel, err := filepath.Rel(repo, f.File)
+			if err != nil {
+				rel = f.File
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		slices.Sort(files)
+		return files, stderr.String()
+	}
+
+	if files, stderr := run(); len(files) != 0 {
+		t.Fatalf("expected no files in a clean tree, got %v (stderr: %s)", files, stderr)
+	}
+
+	write("web/Dockerfile", dockerfile+"MAINTAINER you@example.com\n")
+	write("worker/Dockerfile", dockerfile)
+	files, stderr := run()
+	if want := []string{"web/Dockerfile", "worker/Dockerfile"}; !slices.Equal(files, want) {
+		t.Fatalf("expected %v with --changed, got %v (stderr: %s)", want, files, stderr)
+	}
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDiffBase verifies that --diff-base only reports violations on lines
+// added or modified relative to the given git ref.
+func TestDiffBase(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_SYSTEM="+os.DevNull,
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	dockerfile := filepath.Join(repo, "Dockerfile")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(dockerfile, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init", "-q")
+	write("FROM alpine:3.20\nMAINTAINER old@example.com\n")
+	runGit("add", "Dockerfile")
+	runGit("commit", "-q", "-m", "init")
+	write("FROM alpine:3.20\nMAINTAINER old@example.com\nMAINTAINER new@example.com\n")
+
+	run := func(extra ...string) ([]int, int, string) {
+		t.Helper()
+		args := append([]string{
+			"lint", "--format", "json", "--slow-checks=off",
+			"--ignore", "*", "--select", "buildkit/MaintainerDeprecated",
+		}, extra...)
+		args = append(args, dockerfile)
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		exitCode := 0
+		if err := cmd.Run(); err != nil {
+			exitErr, ok := errors.AsType[*exec.ExitError](err)
+			if !ok {
+				t.Fatalf("command failed to start: %v", err)
+			}
+			exitCode = exitErr.ExitCode()
+		}
+
+		var report struct {
+			Files []struct {
+				Violations []struct {
+					Location struct {
+						Start struct {
+							Line int `json:"line"`
+						} `json:"start"`
+					} `json:"location"`
+				} `json:"violations"`
+			} `json:"files"`
+		}
+		if stdout.Len() > 0 {
+			if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+				t.Fatalf("parse JSON output: %v\n%s", err, stdout.String())
+			}
+		}
+		var lines []int
+		for _, f := range report.Files {
+			for _, v := range f.Violations {
+				lines = append(lines, v.Location.Start.Line)
+			}
+		}
+		return lines, exitCode, stderr.String()
+	}
+
+	all, _, stderr := run()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 violations without --diff-base, got %v (stderr: %s)", all, stderr)
+	}
+
+	changed, _, stderr := run("--diff-base", "HEAD")
+	if len(changed) != 1 || changed[0] != 3 {
+		t.Fatalf("expected only the line 3 violation with --diff-base, got %v (stderr: %s)", changed, stderr)
+	}
+
+	_, exitCode, stderr := run("--diff-base", "no-such-ref")
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2 for unknown ref, got %d", exitCode)
+	}
+	if !strings.Contains(stderr, "--diff-base") {
+		t.Errorf("expected --diff-base error in stderr, got %q", stderr)
+	}
+}
@@ -81,7 +81,7 @@ func runLintFixture(t *testing.T, dir string) {
 	}
 	args = append(args, dockerfilePath)
 	cmd := exec.Command(binaryPath, args...)
-	cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir)
+	cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverageDir, "GITHUB_STEP_SUMMARY=")
 
 	var stdoutBuf, stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
@@ -100,6 +100,8 @@ func runLintFixture(t *testing.T, dir string) {
 		}
 		if outputFormat == "sarif" {
 			opts = append(opts, snaps.Ext(".sarif"))
+		} else {
+			validateJSONReport(t, got)
 		}
 		snaps.WithConfig(opts...).MatchStandaloneJSON(t, got)
 	} else {
@@ -65,6 +65,7 @@ func runLintCase(t *testing.T, tc lintCase) {
 	cmd := exec.Command(binaryPath, args...)
 	cmd.Env = append(os.Environ(),
 		"GOCOVERDIR="+coverageDir,
+		"GITHUB_STEP_SUMMARY=", // keep CI job summaries free of test output
 	)
 	// Add test-specific environment variables
 	cmd.Env = append(cmd.Env, tc.env...)
@@ -116,6 +117,8 @@ func runLintCase(t *testing.T, tc lintCase) {
 		}
 		if tc.snapExt != "" {
 			opts = append(opts, snaps.Ext(tc.snapExt))
+		} else {
+			validateJSONReport(t, outputStr)
 		}
 		snaps.WithConfig(opts...).MatchStandaloneJSON(t, outputStr)
 	}
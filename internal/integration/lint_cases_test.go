@@ -71,6 +71,15 @@ func lintCases(t *testing.T) []lintCase {
 			args:       append([]string{"--format", "json"}, mustSelectRules("buildkit/CopyIgnoredFile")...),
 			useContext: true,
 		},
+		{
+			// With the build context, the message estimates the size the
+			// recursive chown duplicates.
+			name:       "recursive-permissions-after-copy-context",
+			dir:        "recursive-permissions-after-copy-context",
+			args:       append([]string{"--format", "json"}, mustSelectRules("tally/recursive-permissions-after-copy")...),
+			wantExit:   1,
+			useContext: true,
+		},
 		{
 			// Context-aware refinement for tally/ruby/bootsnap-precompile-without-j1:
 			// Gemfile.lock lists bootsnap, so the rule fires.
@@ -420,6 +420,13 @@ func finalizeMockRegistry(confPath string) error {
 		return fmt.Errorf("set DOCKER_DEFAULT_PLATFORM: %w", err)
 	}
 
+	// Disable the registry disk cache so every run talks to the mock registry
+	// and never reuses lookups cached against real registries.
+	if err := os.Setenv("TALLY_SLOW_CHECKS_CACHE_TTL", "0"); err != nil {
+		mockRegistry.Close()
+		return fmt.Errorf("set TALLY_SLOW_CHECKS_CACHE_TTL: %w", err)
+	}
+
 	// Clear setup requests (image pushes) so only test-time requests are tracked.
 	mockRegistry.ResetRequests()
 	return nil
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"os/exec"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/wharflab/tally/internal/reporter"
+	"github.com/wharflab/tally/internal/schemas"
+)
+
+var outputSchema = sync.OnceValues(func() (*jsonschema.Resolved, error) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(schemas.OutputSchema(), &schema); err != nil {
+		return nil, err
+	}
+	return schema.Resolve(&jsonschema.ResolveOptions{BaseURI: schemas.OutputSchemaID})
+})
+
+// validateJSONReport checks a "--format json" report against the published
+// output schema.
+func validateJSONReport(t *testing.T, report string) {
+	t.Helper()
+	resolved, err := outputSchema()
+	if err != nil {
+		t.Fatalf("resolve output schema: %v", err)
+	}
+	var instance any
+	if err := json.Unmarshal([]byte(report), &instance); err != nil {
+		t.Fatalf("parse JSON report: %v", err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		t.Errorf("JSON report does not match the output schema: %v", err)
+	}
+}
+
+func TestSchemaOutput(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command(binaryPath, "schema", "output")
+	cmd.Env = append(cmd.Environ(), "GOCOVERDIR="+coverageDir)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("tally schema output: %v", err)
+	}
+	if !bytes.Equal(out, schemas.OutputSchema()) {
+		t.Error("tally schema output does not print the embedded output schema")
+	}
+
+	var schema struct {
+		ID         string `json:"$id"`
+		Properties struct {
+			SchemaVersion struct {
+				Pattern string `json:"pattern"`
+			} `json:"schemaVersion"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(out, &schema); err != nil {
+		t.Fatalf("parse output schema: %v", err)
+	}
+	if schema.ID != schemas.OutputSchemaID {
+		t.Errorf("$id = %q, want %q", schema.ID, schemas.OutputSchemaID)
+	}
+	// A version bump must stay within the schema's major version.
+	if !regexp.MustCompile(schema.Properties.SchemaVersion.Pattern).MatchString(reporter.JSONSchemaVersion) {
+		t.Errorf("schemaVersion pattern %q does not match %q", schema.Properties.SchemaVersion.Pattern,
+			reporter.JSONSchemaVersion)
+	}
+}
+
+func TestSchemaDirectives(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command(binaryPath, "schema", "directives")
+	cmd.Env = append(cmd.Environ(), "GOCOVERDIR="+coverageDir)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("tally schema directives: %v", err)
+	}
+	if !bytes.Equal(out, schemas.DirectivesSchema()) {
+		t.Error("tally schema directives does not print the embedded directives schema")
+	}
+}
@@ -14,6 +14,7 @@ func TestStdin(t *testing.T) {
 	t.Run("lint-text", testStdinLintText)
 	t.Run("fix-outputs-to-stdout", testStdinFixOutputsToStdout)
 	t.Run("fix-no-changes", testStdinFixNoChanges)
+	t.Run("fix-exit-code-on-fix", testStdinFixExitCodeOnFix)
 	t.Run("empty-stdin", testStdinEmpty)
 	t.Run("mixed-with-files", testStdinMixedWithFiles)
 	t.Run("syntax-error", testStdinSyntaxError)
@@ -43,6 +44,7 @@ func testStdinLintJSON(t *testing.T) {
 		t.Errorf("expected <stdin> in output, got: %s", stdout)
 	}
 
+	validateJSONReport(t, stdout)
 	snaps.WithConfig(
 		snaps.JSON(snaps.JSONConfig{SortKeys: true, Indent: "  "}),
 	).MatchStandaloneJSON(t, stdout)
@@ -138,6 +140,27 @@ func testStdinFixNoChanges(t *testing.T) {
 	}
 }
 
+// testStdinFixExitCodeOnFix verifies that --exit-code-on-fix exits 5 when
+// fixes were applied and 0 when there was nothing to fix.
+func testStdinFixExitCodeOnFix(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]int{
+		"FROM alpine:3.19\nRUN echo hello\n":   5,
+		"FROM alpine:3.19\n\nRUN echo hello\n": 0,
+	} {
+		_, stderr, exitCode := runTallyStdin(t, input,
+			"lint", "--fix", "--exit-code-on-fix", "--slow-checks=off",
+			"--ignore", "*",
+			"--select", "tally/newline-between-instructions",
+			"-",
+		)
+		if exitCode != want {
+			t.Errorf("input %q: expected exit code %d, got %d\nstderr: %s", input, want, exitCode, stderr)
+		}
+	}
+}
+
 // testStdinEmpty verifies that empty stdin produces an error.
 func testStdinEmpty(t *testing.T) {
 	t.Parallel()
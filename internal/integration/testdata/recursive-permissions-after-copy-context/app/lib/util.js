@@ -0,0 +1,101 @@
+// util
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
+module.exports = {};
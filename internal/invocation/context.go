@@ -78,3 +78,25 @@ func (c *InvocationContext) Invocation() *BuildInvocation {
 	}
 	return c.invocation
 }
+
+// BuildSecrets returns the build secrets declared for the invocation and
+// whether the invocation declares them at all. Bake and Compose list every
+// secret the build receives; a direct Dockerfile invocation does not know
+// which secrets the build command line passes.
+func (c *InvocationContext) BuildSecrets() ([]SecretRef, bool) {
+	if c == nil || c.invocation == nil {
+		return nil, false
+	}
+	switch c.invocation.Source.Kind {
+	case KindBake, KindCompose:
+	default:
+		return nil, false
+	}
+	var secrets []SecretRef
+	for _, secret := range c.invocation.Secrets {
+		if secret.Scope == SecretScopeBuild {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, true
+}
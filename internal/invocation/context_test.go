@@ -20,3 +20,34 @@ func TestInvocationContext_Invocation_ReturnsConstructed(t *testing.T) {
 		t.Fatalf("Invocation() = %#v, want %#v", got, inv)
 	}
 }
+
+func TestInvocationContext_BuildSecrets(t *testing.T) {
+	t.Parallel()
+
+	var nilCtx *InvocationContext
+	if _, ok := nilCtx.BuildSecrets(); ok {
+		t.Fatal("nil context BuildSecrets() reported declared secrets")
+	}
+
+	direct := NewContext(&BuildInvocation{Source: InvocationSource{Kind: KindDockerfile}})
+	if _, ok := direct.BuildSecrets(); ok {
+		t.Fatal("Dockerfile invocation BuildSecrets() reported declared secrets")
+	}
+
+	compose := NewContext(&BuildInvocation{
+		Source: InvocationSource{Kind: KindCompose, Name: "api"},
+		Secrets: []SecretRef{
+			{Scope: SecretScopeBuild, ID: "npmrc"},
+			{Scope: SecretScopeService, ID: "db_password"},
+		},
+	})
+	secrets, ok := compose.BuildSecrets()
+	if !ok || len(secrets) != 1 || secrets[0].ID != "npmrc" {
+		t.Fatalf("Compose BuildSecrets() = %+v, %v, want only the npmrc build secret", secrets, ok)
+	}
+
+	bake := NewContext(&BuildInvocation{Source: InvocationSource{Kind: KindBake, Name: "app"}})
+	if secrets, ok := bake.BuildSecrets(); !ok || len(secrets) != 0 {
+		t.Fatalf("Bake BuildSecrets() = %+v, %v, want no secrets, declared", secrets, ok)
+	}
+}
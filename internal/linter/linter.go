@@ -8,7 +8,15 @@ package linter
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
 	"github.com/wharflab/tally/internal/async"
 	"github.com/wharflab/tally/internal/config"
@@ -20,6 +28,7 @@ import (
 	"github.com/wharflab/tally/internal/rules"
 	_ "github.com/wharflab/tally/internal/rules/all" // Register all rules.
 	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
+	"github.com/wharflab/tally/internal/rules/tally"
 	"github.com/wharflab/tally/internal/semantic"
 	"github.com/wharflab/tally/internal/sourcemap"
 )
@@ -61,6 +70,11 @@ type Input struct {
 	// Invocation describes the build orchestration context, when present.
 	Invocation *invocation.BuildInvocation
 
+	// TargetStage selects the stage to build, like docker build --target.
+	// It applies when the invocation does not name a target stage itself.
+	// Violations in stages the target does not depend on are dropped.
+	TargetStage string
+
 	// Channel receives progress and diagnostic output. Nil means silent.
 	Channel Channel
 }
@@ -79,6 +93,10 @@ type Result struct {
 
 	// Config is the resolved config (loaded or passed in via Input).
 	Config *config.Config
+
+	// RuleDurations records the wall time spent in each rule's check, keyed by
+	// rule code. Callers use it for run statistics (tally lint --stats).
+	RuleDurations map[string]time.Duration
 }
 
 // LintFile runs the full lint pipeline for one file.
@@ -100,6 +118,7 @@ func LintFile(input Input) (*Result, error) {
 
 // LintFileContext runs the full lint pipeline with caller cancellation and deadlines.
 func LintFileContext(ctx context.Context, input Input) (*Result, error) {
+	lintStart := time.Now()
 	cfg := input.Config
 	if cfg == nil {
 		var err error
@@ -118,17 +137,37 @@ func LintFileContext(ctx context.Context, input Input) (*Result, error) {
 	spanIndex := directive.NewInstructionSpanIndexFromAST(parseResult.AST, sm)
 	directiveResult := directive.Parse(sm, nil, spanIndex)
 
-	var buildArgs map[string]string
-	targetStage := ""
+	buildArgs := configBuildArgs(cfg)
+	targetStage := input.TargetStage
 	if input.Invocation != nil {
-		buildArgs = invocation.ConcreteBuildArgs(input.Invocation.BuildArgs)
-		targetStage = input.Invocation.TargetStage
+		// Args declared by the invocation describe this build more precisely
+		// than the project-wide build-args.
+		if invArgs := invocation.ConcreteBuildArgs(input.Invocation.BuildArgs); len(invArgs) > 0 {
+			if buildArgs == nil {
+				buildArgs = make(map[string]string, len(invArgs))
+			}
+			maps.Copy(buildArgs, invArgs)
+		}
+		if input.Invocation.TargetStage != "" {
+			targetStage = input.Invocation.TargetStage
+		}
 	}
 	sem := semantic.NewBuilder(parseResult, buildArgs, input.FilePath).
 		WithTargetStage(targetStage).
 		WithShellDirectives(directive.ToSemanticShellDirectives(directiveResult.ShellDirectives)).
 		Build()
 
+	targetIdx := -1
+	if targetStage != "" {
+		idx, ok := sem.StageIndexByName(targetStage)
+		if !ok && targetStage == input.TargetStage {
+			return nil, fmt.Errorf("target stage %q could not be found", targetStage)
+		}
+		if ok {
+			targetIdx = idx
+		}
+	}
+
 	invocationCtx := invocation.NewContext(input.Invocation)
 	contextFiles := buildContextReader(input, parseResult)
 	fileFacts := facts.NewFileFacts(
@@ -154,15 +193,38 @@ func LintFileContext(ctx context.Context, input Input) (*Result, error) {
 		EnabledRules:       enabledRules,
 		SlowChecksEnabled:  slowChecksEnabled,
 		HeredocMinCommands: heredocMinCommands(cfg),
-	}
+		Registries: rules.RegistryPolicy{
+			Trusted: cfg.Registries.Trusted,
+			Mirrors: cfg.Registries.Mirrors,
+		},
+	}.WithSourceMap(sm)
 
 	violations := make([]rules.Violation, 0, len(rules.All())+len(parseResult.Warnings))
+	ruleDurations := make(map[string]time.Duration, len(rules.All()))
+	budget := ruleBudget(cfg)
+	var timedOut []string
 
 	// Run all registered rules.
 	for _, rule := range rules.All() {
+		code := rule.Metadata().Code
 		ruleInput := baseInput
-		ruleInput.Config = configForRuleInput(cfg, rule.Metadata().Code)
-		violations = append(violations, checkRule(ctx, rule, ruleInput)...)
+		ruleInput.Config = configForRuleInput(cfg, code)
+		start := time.Now()
+		ruleViolations, ok := checkRuleWithBudget(ctx, rule, ruleInput, budget)
+		elapsed := time.Since(start)
+		ruleDurations[code] += elapsed
+		if !ok {
+			slog.InfoContext(ctx, "rule exceeded its time budget",
+				"file", input.FilePath, "rule", code, "budget", budget)
+			timedOut = append(timedOut, code)
+			continue
+		}
+		slog.DebugContext(ctx, "rule checked",
+			"file", input.FilePath, "rule", code, "violations", len(ruleViolations), "duration", elapsed)
+		violations = append(violations, ruleViolations...)
+	}
+	if len(timedOut) > 0 {
+		violations = append(violations, tally.NewRuleTimeoutViolation(input.FilePath, timedOut, budget))
 	}
 
 	// Convert BuildKit warnings to violations.
@@ -172,21 +234,170 @@ func LintFileContext(ctx context.Context, input Input) (*Result, error) {
 		))
 	}
 
+	// Lint the instructions inside ONBUILD like the ones around them.
+	violations = append(violations, lintOnbuildTriggers(ctx, input, cfg, parseResult, sem, sm, violations)...)
+
+	violations = dropOutsideTarget(violations, sem, targetIdx)
+
+	// Instructions the parser had to drop are reported instead of failing the file.
+	for _, se := range parseResult.SyntaxErrors {
+		violations = append(violations, tally.NewSyntaxErrorViolation(input.FilePath, se.Message, se.Location))
+	}
+
 	attachInvocation(violations, input.Invocation)
+	var root *parser.Node
+	if parseResult.AST != nil {
+		root = parseResult.AST.AST
+	}
+	attachStageContext(violations, sem, root)
 
 	// Enrich BuildKit violations with auto-fix suggestions.
 	fixes.EnrichBuildKitFixes(violations, sem, content)
 
+	dropFixesOnRanges(violations, parseResult.PodmanExtensions)
+
 	asyncPlan := planAsyncChecks(baseInput, cfg, input.Invocation)
+	if targetIdx >= 0 {
+		asyncPlan = slices.DeleteFunc(asyncPlan, func(req async.CheckRequest) bool {
+			return !sem.Graph().IsReachable(req.StageIndex, targetIdx)
+		})
+	}
+	for i := range asyncPlan {
+		if asyncPlan[i].Handler != nil {
+			asyncPlan[i].Handler = stageContextHandler{inner: asyncPlan[i].Handler, sem: sem, root: root}
+		}
+	}
+
+	slog.InfoContext(ctx, "file linted",
+		"file", input.FilePath, "violations", len(violations), "async_checks", len(asyncPlan),
+		"duration", time.Since(lintStart))
 
 	return &Result{
-		Violations:  violations,
-		AsyncPlan:   asyncPlan,
-		ParseResult: parseResult,
-		Config:      cfg,
+		Violations:    violations,
+		AsyncPlan:     asyncPlan,
+		ParseResult:   parseResult,
+		Config:        cfg,
+		RuleDurations: ruleDurations,
 	}, nil
 }
 
+// dropOutsideTarget removes violations in stages that building the target
+// stage at targetIdx does not need, as docker build --target skips them.
+// File-level violations and those before the first FROM are kept.
+func dropOutsideTarget(violations []rules.Violation, sem *semantic.Model, targetIdx int) []rules.Violation {
+	if targetIdx < 0 {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(v rules.Violation) bool {
+		if v.Location.IsFileLevel() {
+			return false
+		}
+		stageIdx := sem.StageIndexAt(v.Location.Start.Line)
+		return stageIdx >= 0 && !sem.Graph().IsReachable(stageIdx, targetIdx)
+	})
+}
+
+// attachStageContext records the role of the stage each violation is in,
+// for the severity-by-stage-role processor, and the stage and instruction
+// in its metadata for editor integrations.
+func attachStageContext(violations []rules.Violation, sem *semantic.Model, root *parser.Node) {
+	for i := range violations {
+		annotateStageContext(&violations[i], sem, root)
+	}
+}
+
+func annotateStageContext(v *rules.Violation, sem *semantic.Model, root *parser.Node) {
+	v.StageRole = stageRoleAt(sem, v.Location)
+	if v.Location.IsFileLevel() {
+		return
+	}
+	line := v.Location.Start.Line
+	var md rules.ViolationMetadata
+	if v.Metadata != nil {
+		md = *v.Metadata
+	}
+	if md.Instruction == "" {
+		md.Instruction = instructionAt(root, line)
+	}
+	if idx := sem.StageIndexAt(line); md.Stage == nil && idx >= 0 {
+		md.Stage = &rules.ViolationStage{Index: idx}
+		if stage := sem.Stage(idx); stage != nil {
+			md.Stage.Name = stage.Name
+		}
+	}
+	if md != (rules.ViolationMetadata{}) {
+		v.Metadata = &md
+	}
+}
+
+// instructionAt returns the upper-case keyword of the instruction spanning
+// line, or "" when the line is outside every instruction.
+func instructionAt(root *parser.Node, line int) string {
+	if root == nil {
+		return ""
+	}
+	for _, node := range root.Children {
+		if node.StartLine <= line && line <= node.EndLine {
+			return strings.ToUpper(node.Value)
+		}
+	}
+	return ""
+}
+
+func stageRoleAt(sem *semantic.Model, loc rules.Location) semantic.StageRole {
+	if loc.IsFileLevel() {
+		return semantic.StageRoleUnknown
+	}
+	return sem.StageRole(sem.StageIndexAt(loc.Start.Line))
+}
+
+// dropFixesOnRanges removes suggested fixes from violations on the given
+// instructions. Fixes rebuild RUN flags from the typed mounts, which lack the
+// Podman-only syntax hidden by the parser, and would silently drop it.
+func dropFixesOnRanges(violations []rules.Violation, ranges []parser.Range) {
+	if len(ranges) == 0 {
+		return
+	}
+	for i := range violations {
+		v := &violations[i]
+		if v.SuggestedFix == nil && len(v.SuggestedFixes) == 0 {
+			continue
+		}
+		if slices.ContainsFunc(ranges, func(r parser.Range) bool { return fixTouches(v, r) }) {
+			v.SuggestedFix = nil
+			v.SuggestedFixes = nil
+		}
+	}
+}
+
+// fixTouches reports whether a fix of v rewrites text on the lines of r.
+// Pure insertions keep the original text and are allowed; fixes resolved
+// later have no edits yet and are judged by the violation location.
+func fixTouches(v *rules.Violation, r parser.Range) bool {
+	overlaps := func(loc rules.Location) bool {
+		if loc.IsFileLevel() {
+			return false
+		}
+		end := max(loc.End.Line, loc.Start.Line)
+		return loc.Start.Line <= r.End.Line && end >= r.Start.Line
+	}
+	fixes := v.SuggestedFixes
+	if v.SuggestedFix != nil {
+		fixes = append([]*rules.SuggestedFix{v.SuggestedFix}, fixes...)
+	}
+	for _, fix := range fixes {
+		if fix.NeedsResolve && overlaps(v.Location) {
+			return true
+		}
+		for _, edit := range fix.Edits {
+			if edit.Location.Start != edit.Location.End && overlaps(edit.Location) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func checkRule(ctx context.Context, rule rules.Rule, input rules.LintInput) []rules.Violation {
 	if contextRule, ok := rule.(rules.ContextRule); ok {
 		return contextRule.CheckContext(ctx, input)
@@ -194,6 +405,48 @@ func checkRule(ctx context.Context, rule rules.Rule, input rules.LintInput) []ru
 	return rule.Check(input)
 }
 
+// checkRuleWithBudget runs rule with the rules.timeout budget. It returns
+// false when the rule did not finish in time; the rule's goroutine is then
+// abandoned (context-aware rules see their context canceled) so one
+// pathological input can't stall the whole run. A zero budget runs the rule
+// inline.
+func checkRuleWithBudget(
+	ctx context.Context, rule rules.Rule, input rules.LintInput, budget time.Duration,
+) ([]rules.Violation, bool) {
+	if budget <= 0 {
+		return checkRule(ctx, rule, input), true
+	}
+
+	ruleCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	done := make(chan []rules.Violation, 1)
+	go func() {
+		done <- checkRule(ruleCtx, rule, input)
+	}()
+
+	select {
+	case violations := <-done:
+		return violations, true
+	case <-ruleCtx.Done():
+		// Caller cancellation is not a budget overrun; drop the result quietly.
+		return nil, ctx.Err() != nil
+	}
+}
+
+// ruleBudget returns the parsed rules.timeout. Invalid values disable the
+// budget; the CLI warns about them at config load.
+func ruleBudget(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.Rules.Timeout == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(cfg.Rules.Timeout)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
 func planAsyncChecks(
 	baseInput rules.LintInput,
 	cfg *config.Config,
@@ -206,13 +459,11 @@ func planAsyncChecks(
 		if !ok {
 			continue
 		}
-		code := rule.Metadata().Code
-		// Skip rules disabled by Include/Exclude patterns.
-		if enabled := cfg.Rules.IsEnabled(code); enabled != nil && !*enabled {
-			continue
-		}
-		// Skip rules with severity "off".
-		if sev := cfg.Rules.GetSeverity(code); sev == "off" {
+		meta := rule.Metadata()
+		code := meta.Code
+		// Skip rules disabled by Include/Exclude patterns, severity "off",
+		// or an "off" default severity that the config does not override.
+		if !isRuleEnabled(code, meta.DefaultSeverity, cfg) {
 			continue
 		}
 		ruleInput := baseInput
@@ -289,6 +540,14 @@ func attachInvocation(violations []rules.Violation, inv *invocation.BuildInvocat
 	}
 }
 
+// configBuildArgs returns a copy of the configured build args, or nil.
+func configBuildArgs(cfg *config.Config) map[string]string {
+	if cfg == nil || len(cfg.BuildArgs) == 0 {
+		return nil
+	}
+	return maps.Clone(cfg.BuildArgs)
+}
+
 func configForRuleInput(cfg *config.Config, ruleCode string) any {
 	if cfg == nil {
 		return nil
@@ -350,3 +609,22 @@ func (h invocationAwareHandler) OnSuccess(resolved any) []any {
 	}
 	return results
 }
+
+// stageContextHandler attaches stage context to the violations of an async
+// check, like attachStageContext does for the fast checks.
+type stageContextHandler struct {
+	inner async.ResultHandler
+	sem   *semantic.Model
+	root  *parser.Node
+}
+
+func (h stageContextHandler) OnSuccess(resolved any) []any {
+	results := h.inner.OnSuccess(resolved)
+	for i, result := range results {
+		if v, ok := result.(rules.Violation); ok {
+			annotateStageContext(&v, h.sem, h.root)
+			results[i] = v
+		}
+	}
+	return results
+}
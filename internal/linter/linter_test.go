@@ -1,11 +1,22 @@
 package linter
 
 import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
 	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/config"
 	"github.com/wharflab/tally/internal/invocation"
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/tally"
+	"github.com/wharflab/tally/internal/semantic"
 )
 
 func TestAttachInvocation_DockerfileSetsKeyWithoutSource(t *testing.T) {
@@ -199,3 +210,323 @@ func (h staticAsyncHandler) OnSuccess(any) []any {
 	copy(out, h)
 	return out
 }
+
+func TestLintFile_RecordsRuleDurations(t *testing.T) {
+	t.Parallel()
+
+	result, err := LintFile(Input{
+		FilePath: "Dockerfile",
+		Content:  []byte("FROM alpine:3.20\nRUN echo hi\n"),
+		Config:   config.Default(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.RuleDurations) != len(rules.All()) {
+		t.Fatalf("got %d rule durations, want one per registered rule (%d)", len(result.RuleDurations), len(rules.All()))
+	}
+	if _, ok := result.RuleDurations["hadolint/DL3006"]; !ok {
+		t.Error("missing duration for hadolint/DL3006")
+	}
+}
+
+func TestLintFile_ReportsSyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	result, err := LintFile(Input{
+		FilePath: "Dockerfile",
+		Content:  []byte("FROM alpine:3.20\nCOPY app\nUSER root\n"),
+		Config:   config.Default(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var syntaxErrors, lastUser int
+	for _, v := range result.Violations {
+		switch v.RuleCode {
+		case tally.SyntaxErrorRuleCode:
+			syntaxErrors++
+			if v.Line() != 2 {
+				t.Errorf("syntax error at line %d, want 2", v.Line())
+			}
+		case "hadolint/DL3002":
+			lastUser++
+		}
+	}
+	if syntaxErrors != 1 {
+		t.Errorf("got %d %s violations, want 1", syntaxErrors, tally.SyntaxErrorRuleCode)
+	}
+	// Rules still run on the instructions after the broken one.
+	if lastUser != 1 {
+		t.Errorf("got %d hadolint/DL3002 violations, want 1", lastUser)
+	}
+}
+
+func TestLintFile_PodmanDialectKeepsFixesOffExtensions(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Default()
+	cfg.Dialect = config.DialectPodman
+	result, err := LintFile(Input{
+		FilePath: "Containerfile",
+		Content: []byte("FROM ubuntu:24.04\n" +
+			"RUN --mount=type=cache,target=/var/cache/apt,sharing=locked,Z " +
+			"--mount=type=cache,target=/var/lib/apt,sharing=locked,Z " +
+			"apt-get update && apt-get install -y --no-install-recommends curl=8.5.0\n"),
+		Config: cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range result.Violations {
+		// The existing cache mounts are recognized despite the ,Z option.
+		if v.RuleCode == "tally/prefer-package-cache-mounts" {
+			t.Errorf("unexpected %s: %s", v.RuleCode, v.Message)
+		}
+		if v.Line() == 2 && v.SuggestedFix != nil {
+			for _, edit := range v.SuggestedFix.Edits {
+				if edit.Location.Start != edit.Location.End {
+					t.Errorf("%s offers a fix rewriting the Podman RUN: %+v", v.RuleCode, edit)
+				}
+			}
+		}
+	}
+}
+
+func TestDropFixesOnRanges(t *testing.T) {
+	t.Parallel()
+
+	fix := func(loc rules.Location) *rules.SuggestedFix {
+		return &rules.SuggestedFix{Edits: []rules.TextEdit{{Location: loc, NewText: "x"}}}
+	}
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Containerfile", 2), "test/rewrite", "msg", rules.SeverityWarning).
+			WithSuggestedFix(fix(rules.NewRangeLocation("Containerfile", 2, 4, 2, 20))),
+		rules.NewViolation(rules.NewLineLocation("Containerfile", 2), "test/insert", "msg", rules.SeverityWarning).
+			WithSuggestedFix(fix(rules.NewRangeLocation("Containerfile", 2, 4, 2, 4))),
+		rules.NewViolation(rules.NewLineLocation("Containerfile", 3), "test/other", "msg", rules.SeverityWarning).
+			WithSuggestedFix(fix(rules.NewRangeLocation("Containerfile", 3, 0, 3, 3))),
+		rules.NewViolation(rules.NewLineLocation("Containerfile", 2), "test/resolve", "msg", rules.SeverityWarning).
+			WithSuggestedFix(&rules.SuggestedFix{NeedsResolve: true, ResolverID: "test"}),
+	}
+
+	dropFixesOnRanges(violations, []parser.Range{{Start: parser.Position{Line: 2}, End: parser.Position{Line: 2}}})
+
+	for i, wantFix := range []bool{false, true, true, false} {
+		if got := violations[i].SuggestedFix != nil; got != wantFix {
+			t.Errorf("%s: has fix = %v, want %v", violations[i].RuleCode, got, wantFix)
+		}
+	}
+}
+
+func TestLintFile_BuildArgs(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("ARG BASE=ubuntu:24.04\nFROM ${BASE}\nRUN echo hi\n")
+	eolFor := func(t *testing.T, cfg *config.Config, inv *invocation.BuildInvocation) bool {
+		t.Helper()
+		result, err := LintFile(Input{FilePath: "Dockerfile", Content: content, Config: cfg, Invocation: inv})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range result.Violations {
+			if v.RuleCode == tally.BaseImageNotEOLRuleCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	cfg := config.Default()
+	cfg.BuildArgs = map[string]string{"BASE": "debian:stretch"}
+	if !eolFor(t, cfg, nil) {
+		t.Error("expected build-args to resolve FROM to the EOL debian:stretch image")
+	}
+
+	// The invocation's own args win over the configured ones.
+	current := "ubuntu:24.04"
+	inv := &invocation.BuildInvocation{
+		Key:       "bake:api",
+		Source:    invocation.InvocationSource{Kind: invocation.KindBake, Name: "api"},
+		BuildArgs: map[string]*string{"BASE": &current},
+	}
+	if eolFor(t, cfg, inv) {
+		t.Error("expected the invocation's BASE to override build-args")
+	}
+	if cfg.BuildArgs["BASE"] != "debian:stretch" {
+		t.Error("LintFile modified the configured build args")
+	}
+}
+
+func TestLintFile_TargetStage(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("FROM alpine:3.20 AS deps\n" +
+		"MAINTAINER deps\n" +
+		"FROM deps AS build\n" +
+		"MAINTAINER build\n" +
+		"FROM alpine:3.20 AS docs\n" +
+		"MAINTAINER docs\n" +
+		"FROM alpine:3.20 AS final\n" +
+		"COPY --from=build /out /out\n")
+	lines := func(t *testing.T, input Input) []int {
+		t.Helper()
+		result, err := LintFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int
+		for _, v := range result.Violations {
+			if v.RuleCode == "buildkit/MaintainerDeprecated" || v.RuleCode == tally.UnreachableStagesRuleCode {
+				got = append(got, v.Location.Start.Line)
+			}
+		}
+		slices.Sort(got)
+		return got
+	}
+
+	all := lines(t, Input{FilePath: "Dockerfile", Content: content, Config: config.Default()})
+	if !slices.Equal(all, []int{2, 4, 5, 6}) {
+		t.Fatalf("lines without a target = %v, want [2 4 5 6]", all)
+	}
+
+	// Only build and the deps stage it is based on are built.
+	scoped := lines(t, Input{FilePath: "Dockerfile", Content: content, Config: config.Default(), TargetStage: "BUILD"})
+	if !slices.Equal(scoped, []int{2, 4}) {
+		t.Errorf("lines with --target build = %v, want [2 4]", scoped)
+	}
+
+	_, err := LintFile(Input{FilePath: "Dockerfile", Content: content, Config: config.Default(), TargetStage: "nope"})
+	if err == nil || !strings.Contains(err.Error(), `target stage "nope" could not be found`) {
+		t.Errorf("expected an unknown target stage to fail, got %v", err)
+	}
+}
+
+func TestLintFile_StageRoles(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("FROM alpine:3.20 AS build\n" +
+		"MAINTAINER build\n" +
+		"FROM alpine:3.20\n" +
+		"MAINTAINER final\n" +
+		"COPY --from=build /out /out\n")
+	roles := func(t *testing.T, input Input) map[int]semantic.StageRole {
+		t.Helper()
+		result, err := LintFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[int]semantic.StageRole)
+		for _, v := range result.Violations {
+			if v.RuleCode == "buildkit/MaintainerDeprecated" {
+				got[v.Location.Start.Line] = v.StageRole
+			}
+		}
+		return got
+	}
+
+	got := roles(t, Input{FilePath: "Dockerfile", Content: content, Config: config.Default()})
+	want := map[int]semantic.StageRole{2: semantic.StageRoleBuilder, 4: semantic.StageRoleFinal}
+	if !maps.Equal(got, want) {
+		t.Errorf("stage roles = %v, want %v", got, want)
+	}
+
+	// The target stage is the one exported.
+	got = roles(t, Input{FilePath: "Dockerfile", Content: content, Config: config.Default(), TargetStage: "build"})
+	want = map[int]semantic.StageRole{2: semantic.StageRoleFinal}
+	if !maps.Equal(got, want) {
+		t.Errorf("stage roles with --target build = %v, want %v", got, want)
+	}
+}
+
+func TestLintFile_Metadata(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("FROM alpine:3.20 AS build\n" +
+		"MAINTAINER build\n" +
+		"FROM ubuntu\n" +
+		"MAINTAINER final\n")
+	result, err := LintFile(Input{FilePath: "Dockerfile", Content: content, Config: config.Default()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]rules.ViolationMetadata)
+	for _, v := range result.Violations {
+		if v.Metadata != nil {
+			got[fmt.Sprintf("%s:%d", v.RuleCode, v.Location.Start.Line)] = *v.Metadata
+		}
+	}
+	for key, want := range map[string]rules.ViolationMetadata{
+		"buildkit/MaintainerDeprecated:2": {Instruction: "MAINTAINER", Stage: &rules.ViolationStage{Index: 0, Name: "build"}},
+		"buildkit/MaintainerDeprecated:4": {Instruction: "MAINTAINER", Stage: &rules.ViolationStage{Index: 1}},
+		"hadolint/DL3006:3":               {Instruction: "FROM", Stage: &rules.ViolationStage{Index: 1}, Token: "ubuntu"},
+	} {
+		md, ok := got[key]
+		if !ok {
+			t.Errorf("no metadata for %s", key)
+			continue
+		}
+		if md.Instruction != want.Instruction || md.Token != want.Token || md.Stage == nil || *md.Stage != *want.Stage {
+			t.Errorf("%s metadata = %+v (stage %+v), want %+v (stage %+v)", key, md, md.Stage, want, want.Stage)
+		}
+	}
+}
+
+type sleepyRule struct {
+	delay time.Duration
+}
+
+func (r sleepyRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{Code: "test/sleepy"}
+}
+
+func (r sleepyRule) Check(rules.LintInput) []rules.Violation {
+	time.Sleep(r.delay)
+	return []rules.Violation{rules.NewViolation(rules.NewFileLocation("Dockerfile"), "test/sleepy", "done", rules.SeverityWarning)}
+}
+
+func TestCheckRuleWithBudget(t *testing.T) {
+	t.Parallel()
+
+	if got, ok := checkRuleWithBudget(context.Background(), sleepyRule{}, rules.LintInput{}, 0); !ok || len(got) != 1 {
+		t.Errorf("no budget: got %d violations, ok=%v; want 1, true", len(got), ok)
+	}
+	if got, ok := checkRuleWithBudget(context.Background(), sleepyRule{}, rules.LintInput{}, time.Second); !ok || len(got) != 1 {
+		t.Errorf("within budget: got %d violations, ok=%v; want 1, true", len(got), ok)
+	}
+	if got, ok := checkRuleWithBudget(
+		context.Background(), sleepyRule{delay: time.Second}, rules.LintInput{}, 10*time.Millisecond,
+	); ok || got != nil {
+		t.Errorf("over budget: got %v, ok=%v; want nil, false", got, ok)
+	}
+
+	// Caller cancellation drops the result without reporting a timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, ok := checkRuleWithBudget(ctx, sleepyRule{delay: time.Second}, rules.LintInput{}, time.Minute); !ok || got != nil {
+		t.Errorf("canceled: got %v, ok=%v; want nil, true", got, ok)
+	}
+}
+
+func TestRuleBudget(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]time.Duration{
+		"":      0,
+		"0":     0,
+		"200ms": 200 * time.Millisecond,
+		"bogus": 0,
+		"-1s":   0,
+	}
+	for value, want := range tests {
+		cfg := config.Default()
+		cfg.Rules.Timeout = value
+		if got := ruleBudget(cfg); got != want {
+			t.Errorf("ruleBudget(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package linter
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// virtualLine maps a line of a virtual Dockerfile back to the source.
+// Line is 0 for lines that only set up the virtual stage.
+type virtualLine struct {
+	line      int
+	colOffset int
+}
+
+// onbuildStage is the virtual Dockerfile for the ONBUILD triggers of one
+// stage: the triggers run as ordinary instructions in a stage built on the
+// same base image with the same environment, working directory, user and
+// shell.
+type onbuildStage struct {
+	index    int
+	content  strings.Builder
+	lines    []virtualLine
+	triggers []parser.Range
+}
+
+func (s *onbuildStage) addLine(text string, mapped virtualLine) {
+	s.content.WriteString(text)
+	s.content.WriteByte('\n')
+	s.lines = append(s.lines, mapped)
+}
+
+// lintOnbuildTriggers lints the instructions inside ONBUILD like top-level
+// instructions. Each stage's triggers are linted as a virtual Dockerfile
+// and the violations on trigger lines are moved back to the ONBUILD lines.
+// Violations of rules that already reported on a trigger themselves (such
+// as the RUN checks that read ONBUILD RUN) are dropped, and so are style
+// violations: the layout of a trigger is linted where it is written.
+func lintOnbuildTriggers(
+	ctx context.Context,
+	input Input,
+	cfg *config.Config,
+	parseResult *dockerfile.ParseResult,
+	sem *semantic.Model,
+	sm *sourcemap.SourceMap,
+	reported []rules.Violation,
+) []rules.Violation {
+	stages := onbuildStages(parseResult, sem, sm)
+	if len(stages) == 0 {
+		return nil
+	}
+
+	inv := input.Invocation
+	if inv != nil {
+		// The target stage names a stage of the real Dockerfile.
+		virtualInv := *inv
+		virtualInv.TargetStage = ""
+		inv = &virtualInv
+	}
+
+	var violations []rules.Violation
+	for _, stage := range stages {
+		result, err := LintFileContext(ctx, Input{
+			FilePath:   input.FilePath,
+			Content:    []byte(stage.content.String()),
+			Config:     cfg,
+			Invocation: inv,
+		})
+		if err != nil {
+			continue
+		}
+		for _, v := range result.Violations {
+			if isStyleRule(v.RuleCode) || !stage.remap(&v) || reportedOnTrigger(reported, v, stage.triggers) {
+				continue
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// onbuildStages builds the virtual Dockerfiles for the stages that have
+// ONBUILD triggers. Forbidden triggers (DL3043) are skipped.
+func onbuildStages(parseResult *dockerfile.ParseResult, sem *semantic.Model, sm *sourcemap.SourceMap) []*onbuildStage {
+	if parseResult.AST == nil || parseResult.AST.AST == nil {
+		return nil
+	}
+	root := parseResult.AST.AST
+	escape := dockerfile.ASTEscapeToken(parseResult.AST)
+
+	var stages []*onbuildStage
+	byIndex := make(map[int]*onbuildStage)
+	for _, node := range root.Children {
+		trigger := onbuildTrigger(node)
+		if trigger == "" || isForbiddenOnbuildTrigger(trigger) {
+			continue
+		}
+		idx := sem.StageIndexAt(node.StartLine)
+		if idx < 0 || node.StartLine < 1 || node.EndLine > sm.LineCount() {
+			continue
+		}
+		stage, ok := byIndex[idx]
+		if !ok {
+			stage = newOnbuildStage(idx, sem, root, sm, escape)
+			byIndex[idx] = stage
+			stages = append(stages, stage)
+		}
+
+		first := sm.Line(node.StartLine - 1)
+		bodyCol := onbuildBodyColumn(first)
+		stage.addLine(first[bodyCol:], virtualLine{line: node.StartLine, colOffset: bodyCol})
+		for line := node.StartLine + 1; line <= node.EndLine; line++ {
+			stage.addLine(sm.Line(line-1), virtualLine{line: line})
+		}
+		stage.triggers = append(stage.triggers, parser.Range{
+			Start: parser.Position{Line: node.StartLine},
+			End:   parser.Position{Line: node.EndLine},
+		})
+	}
+	return stages
+}
+
+// newOnbuildStage starts the virtual Dockerfile of a stage with its escape
+// directive, its external base image, and the ENV, WORKDIR, USER and SHELL
+// instructions of the stage and the stages it is built on, which the image
+// passes on to the build that runs the triggers.
+func newOnbuildStage(
+	idx int,
+	sem *semantic.Model,
+	root *parser.Node,
+	sm *sourcemap.SourceMap,
+	escape rune,
+) *onbuildStage {
+	stage := &onbuildStage{index: idx}
+	if escape != '\\' {
+		stage.addLine("# escape="+string(escape), virtualLine{})
+	}
+
+	from := "FROM scratch"
+	if base := sem.ExternalBase(idx); base != nil && base.Effective != "" {
+		from = "FROM " + base.Effective
+		if base.Platform != "" && !strings.Contains(base.Platform, "$") {
+			from = "FROM --platform=" + base.Platform + " " + base.Effective
+		}
+	}
+	stage.addLine(from, virtualLine{})
+
+	for _, ancestor := range stageAncestry(sem, idx) {
+		for _, node := range root.Children {
+			if !inheritedByTriggers(node) || sem.StageIndexAt(node.StartLine) != ancestor {
+				continue
+			}
+			for line := node.StartLine; line <= node.EndLine && line <= sm.LineCount(); line++ {
+				stage.addLine(sm.Line(line-1), virtualLine{})
+			}
+		}
+	}
+	return stage
+}
+
+// stageAncestry returns the stage and the stages it is built on through
+// FROM <stage>, base first.
+func stageAncestry(sem *semantic.Model, idx int) []int {
+	chain := []int{idx}
+	for {
+		info := sem.StageInfo(chain[len(chain)-1])
+		if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef ||
+			info.BaseImage.StageIndex < 0 || slices.Contains(chain, info.BaseImage.StageIndex) {
+			break
+		}
+		chain = append(chain, info.BaseImage.StageIndex)
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// inheritedByTriggers reports whether an instruction sets image config that
+// ONBUILD triggers run with.
+func inheritedByTriggers(node *parser.Node) bool {
+	if node == nil {
+		return false
+	}
+	switch strings.ToLower(node.Value) {
+	case command.Env, command.Workdir, command.User, command.Shell:
+		return true
+	}
+	return false
+}
+
+func isStyleRule(code string) bool {
+	rule := rules.DefaultRegistry().Get(code)
+	return rule != nil && rule.Metadata().Category == "style"
+}
+
+// onbuildTrigger returns the lowercased keyword of the instruction inside
+// an ONBUILD node, or "" for other nodes.
+func onbuildTrigger(node *parser.Node) string {
+	if node == nil || !strings.EqualFold(node.Value, command.Onbuild) {
+		return ""
+	}
+	if node.Next == nil || len(node.Next.Children) == 0 || node.Next.Children[0] == nil {
+		return ""
+	}
+	return strings.ToLower(node.Next.Children[0].Value)
+}
+
+func isForbiddenOnbuildTrigger(trigger string) bool {
+	return trigger == command.Onbuild || trigger == command.From || trigger == command.Maintainer
+}
+
+// onbuildBodyColumn returns the column where the instruction after the
+// ONBUILD keyword starts on the first line.
+func onbuildBodyColumn(line string) int {
+	col := len(line) - len(strings.TrimLeft(line, " \t"))
+	col += len(command.Onbuild)
+	for col < len(line) && (line[col] == ' ' || line[col] == '\t') {
+		col++
+	}
+	return min(col, len(line))
+}
+
+// remap moves a violation of the virtual Dockerfile to the source. It
+// reports false for violations outside the triggers. Only fixes that edit
+// within trigger lines are kept: fixes that add lines would put new
+// instructions inside the ONBUILD instruction, and fixes resolved later
+// would edit the virtual content.
+func (s *onbuildStage) remap(v *rules.Violation) bool {
+	loc, ok := s.remapLocation(v.Location)
+	if !ok {
+		return false
+	}
+	v.Location = loc
+	v.StageIndex = s.index
+	v.SourceCode = ""
+	if v.Metadata != nil {
+		md := *v.Metadata
+		md.Instruction = ""
+		md.Stage = nil
+		v.Metadata = &md
+		if md == (rules.ViolationMetadata{}) {
+			v.Metadata = nil
+		}
+	}
+
+	if v.SuggestedFix != nil && !s.remapFix(v.SuggestedFix) {
+		v.SuggestedFix = nil
+	}
+	fixes := make([]*rules.SuggestedFix, 0, len(v.SuggestedFixes))
+	for _, fix := range v.SuggestedFixes {
+		if s.remapFix(fix) {
+			fixes = append(fixes, fix)
+		}
+	}
+	v.SuggestedFixes = nil
+	if len(fixes) > 0 {
+		v.SuggestedFixes = fixes
+	}
+	return true
+}
+
+func (s *onbuildStage) remapFix(fix *rules.SuggestedFix) bool {
+	if fix.NeedsResolve {
+		return false
+	}
+	edits := make([]rules.TextEdit, len(fix.Edits))
+	for i, edit := range fix.Edits {
+		if strings.Contains(edit.NewText, "\n") {
+			return false
+		}
+		loc, ok := s.remapLocation(edit.Location)
+		if !ok || loc.IsPointLocation() != edit.Location.IsPointLocation() {
+			return false
+		}
+		edits[i] = rules.TextEdit{Location: loc, NewText: edit.NewText}
+	}
+	fix.Edits = edits
+	return true
+}
+
+func (s *onbuildStage) remapLocation(loc rules.Location) (rules.Location, bool) {
+	if loc.IsFileLevel() {
+		return loc, false
+	}
+	start, ok := s.remapPosition(loc.Start)
+	if !ok {
+		return loc, false
+	}
+	out := rules.Location{File: loc.File, Start: start, End: loc.End}
+	if loc.IsPointLocation() {
+		if loc.End.Line >= 0 {
+			out.End = start
+		}
+		return out, true
+	}
+
+	// An end at the start of a line ends the line before it, which keeps
+	// ranges that span whole lines off the next trigger.
+	if prev := loc.End.Line - 1; loc.End.Column == 0 && prev >= 1 && prev <= len(s.lines) && s.lines[prev-1].line > 0 {
+		out.End = rules.Position{Line: s.lines[prev-1].line + 1}
+		return out, true
+	}
+	end, ok := s.remapPosition(loc.End)
+	if !ok {
+		return rules.Location{File: loc.File, Start: start, End: start}, true
+	}
+	out.End = end
+	return out, true
+}
+
+func (s *onbuildStage) remapPosition(pos rules.Position) (rules.Position, bool) {
+	if pos.Line < 1 || pos.Line > len(s.lines) {
+		return pos, false
+	}
+	mapped := s.lines[pos.Line-1]
+	if mapped.line == 0 {
+		return pos, false
+	}
+	return rules.Position{Line: mapped.line, Column: pos.Column + mapped.colOffset}, true
+}
+
+// reportedOnTrigger reports whether the rule of v already reported on the
+// ONBUILD instruction v is on.
+func reportedOnTrigger(reported []rules.Violation, v rules.Violation, triggers []parser.Range) bool {
+	idx := slices.IndexFunc(triggers, func(r parser.Range) bool {
+		return r.Start.Line <= v.Location.Start.Line && v.Location.Start.Line <= r.End.Line
+	})
+	if idx < 0 {
+		return false
+	}
+	r := triggers[idx]
+	return slices.ContainsFunc(reported, func(other rules.Violation) bool {
+		return other.RuleCode == v.RuleCode &&
+			r.Start.Line <= other.Location.Start.Line && other.Location.Start.Line <= r.End.Line
+	})
+}
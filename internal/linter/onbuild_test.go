@@ -0,0 +1,99 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestLintFile_OnbuildTriggers(t *testing.T) {
+	t.Parallel()
+
+	content := `FROM ubuntu:24.04 AS base
+ENV APP_HOME=/srv/app
+
+FROM base
+ONBUILD RUN apt install -y curl
+ONBUILD ADD . $APP_HOME
+ONBUILD RUN cd /tmp && \
+    wget http://example.com/x.tar.gz
+`
+	result, err := LintFile(Input{
+		FilePath: "Dockerfile",
+		Content:  []byte(content),
+		Config:   config.Default(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byRule := make(map[string][]rules.Violation)
+	for _, v := range result.Violations {
+		byRule[v.RuleCode] = append(byRule[v.RuleCode], v)
+	}
+
+	// ADD is linted inside ONBUILD, with the fix moved to the trigger.
+	add := byRule["hadolint/DL3020"]
+	if len(add) != 1 {
+		t.Fatalf("got %d hadolint/DL3020 violations, want 1", len(add))
+	}
+	if add[0].Location.Start != (rules.Position{Line: 6, Column: 8}) || add[0].StageIndex != 1 {
+		t.Errorf("DL3020 at %+v in stage %d, want line 6 column 8 in stage 1", add[0].Location.Start, add[0].StageIndex)
+	}
+	if fix := add[0].SuggestedFix; fix == nil || len(fix.Edits) != 1 ||
+		fix.Edits[0].Location != rules.NewRangeLocation("Dockerfile", 6, 8, 6, 11) || fix.Edits[0].NewText != "COPY" {
+		t.Errorf("DL3020 fix = %+v, want ADD replaced by COPY on line 6", fix)
+	}
+
+	// Continuation lines keep their columns.
+	wget := byRule["hadolint/DL3047"]
+	if len(wget) != 1 || wget[0].Location != rules.NewRangeLocation("Dockerfile", 8, 4, 8, 8) {
+		t.Errorf("DL3047 violations = %+v, want one at 8:4-8:8", wget)
+	}
+
+	// Rules that lint ONBUILD RUN themselves are not reported twice.
+	if n := len(byRule["hadolint/DL3027"]); n != 1 {
+		t.Errorf("got %d hadolint/DL3027 violations, want 1", n)
+	}
+
+	// The environment of the stages the triggers run on is known.
+	if vs := byRule["buildkit/UndefinedVar"]; len(vs) != 0 {
+		t.Errorf("unexpected buildkit/UndefinedVar: %+v", vs)
+	}
+
+	// Style rules are not run on the virtual triggers.
+	for _, v := range byRule["tally/newline-between-instructions"] {
+		if v.Location.Start.Column != 0 {
+			t.Errorf("style violation inside a trigger: %s at %+v", v.Message, v.Location.Start)
+		}
+	}
+}
+
+func TestLintFile_OnbuildTriggersEscapeToken(t *testing.T) {
+	t.Parallel()
+
+	content := "# escape=`\nFROM alpine:3.20\n  onbuild   ADD `\n    . /app\n"
+	result, err := LintFile(Input{
+		FilePath: "Dockerfile",
+		Content:  []byte(content),
+		Config:   config.Default(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, v := range result.Violations {
+		if v.RuleCode != "hadolint/DL3020" {
+			continue
+		}
+		found = true
+		if v.Location.Start != (rules.Position{Line: 3, Column: 12}) {
+			t.Errorf("DL3020 at %+v, want line 3 column 12", v.Location.Start)
+		}
+	}
+	if !found {
+		t.Error("missing hadolint/DL3020 for ADD inside ONBUILD")
+	}
+}
@@ -9,9 +9,12 @@ func CLIProcessors() (*processor.Chain, *processor.InlineDirectiveFilter) {
 	chain := processor.NewChain(
 		processor.NewPathNormalization(),   // Normalize paths for cross-platform consistency
 		processor.NewSeverityOverride(),    // Apply severity overrides (must run before EnableFilter)
+		processor.NewStageRoleSeverity(),   // Apply severity-by-stage-role overrides
+		processor.NewFixSafetyOverride(),   // Apply per-rule fix safety overrides
 		processor.NewEnableFilter(),        // Filter rules with severity="off"
 		processor.NewPathExclusionFilter(), // Apply per-rule path exclusions
 		inlineFilter,                       // Apply inline ignore directives
+		processor.NewDuplicateFilter(),     // Drop violations another rule supersedes
 		processor.NewSupersession(),        // Drop lower-severity when error exists
 		processor.NewDeduplication(),       // Remove duplicate violations
 		processor.NewSorting(),             // Stable output ordering
@@ -26,8 +29,11 @@ func CLIProcessors() (*processor.Chain, *processor.InlineDirectiveFilter) {
 func LSPProcessors() *processor.Chain {
 	return processor.NewChain(
 		processor.NewSeverityOverride(),
+		processor.NewStageRoleSeverity(),
+		processor.NewFixSafetyOverride(),
 		processor.NewEnableFilter(),
 		processor.NewInlineDirectiveFilter(),
+		processor.NewDuplicateFilter(),
 		processor.NewSupersession(),
 		processor.NewDeduplication(),
 		processor.NewSorting(),
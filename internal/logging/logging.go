@@ -0,0 +1,65 @@
+// Package logging configures tally's diagnostic logging.
+//
+// Packages log through the default log/slog logger. The CLI installs it once
+// at startup from the --verbose/--debug flags and TALLY_LOG_FORMAT; without
+// flags only warnings are written. Logs always go to stderr so they never mix
+// with lint output or fixed content on stdout.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// EnvFormat selects the log format: "text" (default) or "json".
+const EnvFormat = "TALLY_LOG_FORMAT"
+
+const (
+	// FormatText writes logfmt-style key=value lines.
+	FormatText = "text"
+	// FormatJSON writes one JSON object per line, for CI log collectors.
+	FormatJSON = "json"
+)
+
+// LevelForVerbosity maps the number of -v flags to a log level: warnings by
+// default, info for -v and debug for -vv and above.
+func LevelForVerbosity(verbosity int) slog.Level {
+	switch {
+	case verbosity <= 0:
+		return slog.LevelWarn
+	case verbosity == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// NewHandler returns a handler writing records at or above level to w in the
+// given format. Unknown formats fall back to text. Text records omit the
+// timestamp unless debug logging is on, since warnings are read by people
+// rather than correlated with other logs.
+func NewHandler(w io.Writer, level slog.Level, format string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(strings.TrimSpace(format), FormatJSON) {
+		return slog.NewJSONHandler(w, opts)
+	}
+	if level > slog.LevelDebug {
+		opts.ReplaceAttr = dropTime
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// Setup installs the default logger for the given verbosity, writing to
+// stderr in the format named by TALLY_LOG_FORMAT.
+func Setup(verbosity int) {
+	slog.SetDefault(slog.New(NewHandler(os.Stderr, LevelForVerbosity(verbosity), os.Getenv(EnvFormat))))
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json/v2"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevelForVerbosity(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		verbosity int
+		want      slog.Level
+	}{
+		{0, slog.LevelWarn},
+		{1, slog.LevelInfo},
+		{2, slog.LevelDebug},
+		{5, slog.LevelDebug},
+	}
+	for _, tt := range tests {
+		if got := LevelForVerbosity(tt.verbosity); got != tt.want {
+			t.Errorf("LevelForVerbosity(%d) = %v, want %v", tt.verbosity, got, tt.want)
+		}
+	}
+}
+
+func TestNewHandler_Text(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, slog.LevelWarn, ""))
+	logger.Info("hidden")
+	logger.Warn("failed to close output", "error", "disk full")
+
+	got := buf.String()
+	if strings.Contains(got, "hidden") {
+		t.Errorf("info record written at warn level: %q", got)
+	}
+	want := `level=WARN msg="failed to close output" error="disk full"` + "\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestNewHandler_TextDebugKeepsTime(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, slog.LevelDebug, FormatText)).Debug("rule finished")
+	if !strings.HasPrefix(buf.String(), "time=") {
+		t.Errorf("debug output %q has no timestamp", buf.String())
+	}
+}
+
+func TestNewHandler_JSON(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	h := NewHandler(&buf, slog.LevelInfo, " JSON ")
+	if !h.Enabled(context.Background(), slog.LevelInfo) || h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("JSON handler does not honor the level")
+	}
+	slog.New(h).Info("async check resolved", "resolver", "registry", "key", "alpine:3.21")
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("output %q is not JSON: %v", buf.String(), err)
+	}
+	if rec["msg"] != "async check resolved" || rec["resolver"] != "registry" || rec["level"] != "INFO" {
+		t.Errorf("unexpected record: %v", rec)
+	}
+	if _, ok := rec["time"]; !ok {
+		t.Error("JSON record has no time")
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/download"
 	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/eol"
+	"github.com/wharflab/tally/internal/osv"
 	"github.com/wharflab/tally/internal/processor"
 	"github.com/wharflab/tally/internal/registry"
 	"github.com/wharflab/tally/internal/rules"
@@ -30,6 +33,7 @@ func (s *Server) runAsyncChecks(
 		map[string][]byte{filePath: content},
 	)
 	filtered := processor.NewSeverityOverride().Process(fastViolations, procCtx)
+	filtered = processor.NewStageRoleSeverity().Process(filtered, procCtx)
 	filtered = processor.NewEnableFilter().Process(filtered, procCtx)
 	if cfg.SlowChecks.FailFast && hasSeverityError(filtered) {
 		return nil
@@ -50,13 +54,22 @@ func (s *Server) runAsyncChecks(
 		return nil
 	}
 
-	imgResolver := registry.NewDefaultResolver()
+	imgResolver := registry.NewDefaultResolver(registry.OptionsFromConfig(cfg))
 	asyncImgResolver := registry.NewAsyncImageResolver(imgResolver)
+	copySourceResolver := registry.NewAsyncCopySourceResolver(imgResolver)
+	eolResolver := eol.NewResolver()
+	imgFiles, _ := imgResolver.(registry.ImageFileReader)
+	osvResolver := osv.NewResolver(imgFiles)
+	downloadResolver := download.NewResolver()
 	rt := &async.Runtime{
 		Concurrency: 4,
 		Timeout:     timeout,
 		Resolvers: map[string]async.Resolver{
-			asyncImgResolver.ID(): asyncImgResolver,
+			asyncImgResolver.ID():   asyncImgResolver,
+			copySourceResolver.ID(): copySourceResolver,
+			eolResolver.ID():        eolResolver,
+			osvResolver.ID():        osvResolver,
+			downloadResolver.ID():   downloadResolver,
 		},
 	}
 
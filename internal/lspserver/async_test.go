@@ -109,7 +109,7 @@ func TestLintContentWithConfig_RunsAsyncUndefinedVarResolution(t *testing.T) {
 	t.Parallel()
 
 	oldResolver := registry.NewDefaultResolver
-	registry.NewDefaultResolver = func() registry.ImageResolver {
+	registry.NewDefaultResolver = func(registry.Options) registry.ImageResolver {
 		return &stubImageResolver{
 			resolveConfig: func(_ context.Context, ref, _ string) (registry.ImageConfig, error) {
 				require.Equal(t, "public.ecr.aws/lambda/python:3.12", ref)
@@ -257,32 +257,55 @@ func kindRequested(only *[]protocol.CodeActionKind, kind protocol.CodeActionKind
 func convertTextEdits(edits []rules.TextEdit) []*protocol.TextEdit {
 	result := make([]*protocol.TextEdit, 0, len(edits))
 	for _, e := range edits {
-		loc := e.Location
-		if loc.IsFileLevel() {
+		if e.Location.IsFileLevel() {
 			continue
 		}
-
-		startLine := clampUint32(loc.Start.Line - 1)
-		startChar := clampUint32(loc.Start.Column)
-		endLine := startLine
-		endChar := startChar
-
-		if !loc.IsPointLocation() {
-			endLine = clampUint32(loc.End.Line - 1)
-			endChar = clampUint32(loc.End.Column)
-		}
-
 		result = append(result, &protocol.TextEdit{
-			Range: protocol.Range{
-				Start: protocol.Position{Line: startLine, Character: startChar},
-				End:   protocol.Position{Line: endLine, Character: endChar},
-			},
+			Range:   LocationRange(e.Location),
 			NewText: e.NewText,
 		})
 	}
 	return result
 }
 
+// LocationRange converts a tally Location (1-based lines, 0-based columns)
+// to an LSP Range. Point locations become empty ranges.
+func LocationRange(loc rules.Location) protocol.Range {
+	startLine := clampUint32(loc.Start.Line - 1)
+	startChar := clampUint32(loc.Start.Column)
+	endLine := startLine
+	endChar := startChar
+
+	if !loc.IsPointLocation() {
+		endLine = clampUint32(loc.End.Line - 1)
+		endChar = clampUint32(loc.End.Column)
+	}
+
+	return protocol.Range{
+		Start: protocol.Position{Line: startLine, Character: startChar},
+		End:   protocol.Position{Line: endLine, Character: endChar},
+	}
+}
+
+// FixWorkspaceEdit returns edits as a WorkspaceEdit with a single unversioned
+// TextDocumentEdit for docURI, so clients can apply it without knowing the
+// document version.
+func FixWorkspaceEdit(docURI string, edits []rules.TextEdit) *protocol.WorkspaceEdit {
+	converted := convertTextEdits(edits)
+	docEdits := make([]protocol.TextEditOrAnnotatedTextEditOrSnippetTextEdit, 0, len(converted))
+	for _, e := range converted {
+		docEdits = append(docEdits, protocol.TextEditOrAnnotatedTextEditOrSnippetTextEdit{TextEdit: e})
+	}
+	return &protocol.WorkspaceEdit{
+		DocumentChanges: &[]protocol.TextDocumentEditOrCreateFileOrRenameFileOrDeleteFile{{
+			TextDocumentEdit: &protocol.TextDocumentEdit{
+				TextDocument: protocol.OptionalVersionedTextDocumentIdentifier{Uri: protocol.DocumentUri(docURI)},
+				Edits:        docEdits,
+			},
+		}},
+	}
+}
+
 // rangesOverlap checks if two LSP ranges overlap.
 // LSP ranges are half-open [start, end), so touching ranges (a.End == b.Start)
 // are not considered overlapping.
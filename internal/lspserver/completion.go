@@ -0,0 +1,91 @@
+package lspserver
+
+import (
+	"fmt"
+	"strings"
+
+	protocol "github.com/wharflab/tally/internal/lsp/protocol"
+
+	"github.com/wharflab/tally/internal/directive"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// handleCompletion completes inline directives: the directive keyword after
+// "# tally " and the rule codes of its rule list.
+func (s *Server) handleCompletion(params *protocol.CompletionParams) (any, error) {
+	doc := s.documents.Get(string(params.TextDocument.Uri))
+	if doc == nil {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no completions"
+	}
+	lines := strings.Split(doc.Content, "\n")
+	if int(params.Position.Line) >= len(lines) {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no completions"
+	}
+	line := strings.TrimSuffix(lines[params.Position.Line], "\r")
+	prefix := line[:byteOffsetAtUTF16(line, params.Position.Character)]
+
+	kind, start := directive.CompletionAt(prefix)
+	if kind == directive.CompletionNone {
+		return nil, nil //nolint:nilnil // LSP: null result is valid for "no completions"
+	}
+	// Each item replaces the word typed so far.
+	replace := protocol.Range{
+		Start: protocol.Position{
+			Line:      params.Position.Line,
+			Character: positionAtOffset([]byte(prefix), start).Character,
+		},
+		End: params.Position,
+	}
+
+	var items []*protocol.CompletionItem
+	switch kind {
+	case directive.CompletionKeyword:
+		for i, kw := range directive.Keywords {
+			items = append(items, completionItem(kw.Text, kw.Detail, protocol.CompletionItemKindKeyword, i, replace))
+		}
+	case directive.CompletionRule:
+		items = append(items, completionItem("all", "Every rule", protocol.CompletionItemKindValue, 0, replace))
+		for i, rule := range rules.All() {
+			meta := rule.Metadata()
+			items = append(items, completionItem(meta.Code, meta.Name, protocol.CompletionItemKindValue, i+1, replace))
+		}
+	case directive.CompletionNone:
+	}
+	return &protocol.CompletionList{Items: items}, nil
+}
+
+func completionItem(
+	label, detail string,
+	kind protocol.CompletionItemKind,
+	order int,
+	replace protocol.Range,
+) *protocol.CompletionItem {
+	// Keep the directive order instead of the client's alphabetical one.
+	sortText := fmt.Sprintf("%04d", order)
+	return &protocol.CompletionItem{
+		Label:    label,
+		Kind:     ptrTo(kind),
+		Detail:   ptrTo(detail),
+		SortText: &sortText,
+		TextEdit: &protocol.TextEditOrInsertReplaceEdit{
+			TextEdit: &protocol.TextEdit{Range: replace, NewText: label},
+		},
+	}
+}
+
+// byteOffsetAtUTF16 returns the byte offset in line of a UTF-16 character
+// offset, clamped to the end of the line.
+func byteOffsetAtUTF16(line string, character uint32) int {
+	units := uint32(0)
+	for i, r := range line {
+		if units >= character {
+			return i
+		}
+		if r > 0xFFFF {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return len(line)
+}
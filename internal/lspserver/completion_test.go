@@ -0,0 +1,59 @@
+package lspserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protocol "github.com/wharflab/tally/internal/lsp/protocol"
+)
+
+func TestHandleCompletion(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	uri := "file:///test/Dockerfile"
+	s.documents.Open(uri, "dockerfile", 1, "FROM alpine\n# tally disable-stage=DL3006,tally/max\n# tally \nRUN true\n")
+
+	complete := func(line, character uint32) *protocol.CompletionList {
+		t.Helper()
+		result, err := s.handleCompletion(&protocol.CompletionParams{
+			TextDocument: protocol.TextDocumentIdentifier{Uri: protocol.DocumentUri(uri)},
+			Position:     protocol.Position{Line: line, Character: character},
+		})
+		require.NoError(t, err)
+		if result == nil {
+			return nil
+		}
+		list, ok := result.(*protocol.CompletionList)
+		require.True(t, ok, "result = %T", result)
+		return list
+	}
+	labels := func(list *protocol.CompletionList) []string {
+		out := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			out = append(out, item.Label)
+		}
+		return out
+	}
+
+	// Keywords after "# tally ".
+	list := complete(2, 8)
+	require.NotNil(t, list)
+	assert.Contains(t, labels(list), "disable-next-instruction")
+	assert.Contains(t, labels(list), "disable-file=")
+
+	// Rule codes replace the word typed after the last comma.
+	list = complete(1, 38)
+	require.NotNil(t, list)
+	assert.Contains(t, labels(list), "tally/max-lines")
+	edit := list.Items[0].TextEdit.TextEdit
+	require.NotNil(t, edit)
+	assert.Equal(t, protocol.Position{Line: 1, Character: 29}, edit.Range.Start)
+	assert.Equal(t, protocol.Position{Line: 1, Character: 38}, edit.Range.End)
+
+	// Nothing outside directives.
+	assert.Nil(t, complete(3, 4))
+	assert.Nil(t, complete(0, 4))
+}
@@ -100,7 +100,7 @@ func (s *Server) computeFixEdits(
 	if err != nil || fixed == nil || bytes.Equal(fixed, content) {
 		return nil
 	}
-	return minimalTextEdit(content, fixed)
+	return textEdits(content, fix.Diff(filePath, content, fixed))
 }
 
 // computeFixEditsIterative runs lint+fix in a loop until the content stabilizes
@@ -130,7 +130,7 @@ func (s *Server) computeFixEditsIterative(ctx context.Context, docURI string, co
 	if bytes.Equal(current, content) {
 		return nil
 	}
-	return minimalTextEdit(content, current)
+	return textEdits(content, fix.Diff(filePath, content, current))
 }
 
 // computeFixedContent runs a single lint+fix pass and returns the modified content.
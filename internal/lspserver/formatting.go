@@ -1,7 +1,6 @@
 package lspserver
 
 import (
-	"bytes"
 	"context"
 	"path/filepath"
 	"unicode/utf8"
@@ -17,8 +16,8 @@ import (
 
 // handleFormatting handles textDocument/formatting by applying safe auto-fixes.
 //
-// The response is computed by applying the fixes and then returning a minimal edit
-// that transforms the original document into the fixed output (ESLint-style).
+// The response is computed by applying the fixes and then returning minimal edits
+// that transform the original document into the fixed output (ESLint-style).
 func (s *Server) handleFormatting(ctx context.Context, params *protocol.DocumentFormattingParams) (any, error) {
 	doc := s.documents.Get(string(params.TextDocument.Uri))
 	if doc == nil {
@@ -129,76 +128,35 @@ func (s *Server) formatRanges(ctx context.Context, doc *Document, ranges []proto
 			fileKey: fixModes,
 		},
 	}
-	fixResult, err := fixer.Apply(ctx, violations, map[string][]byte{fileKey: content})
+	patches, err := fixer.Patch(ctx, violations, map[string][]byte{fileKey: content})
 	if err != nil {
 		return nil
 	}
-
-	change := fixResult.Changes[fileKey]
-	if change == nil || !change.HasChanges() || bytes.Equal(change.ModifiedContent, content) {
-		return nil
-	}
-
-	edits := minimalTextEdit(content, change.ModifiedContent)
-	if len(edits) == 0 {
-		return nil
+	for _, fp := range patches.Files {
+		if filepath.Clean(fp.Path) == fileKey {
+			return textEdits(content, fp.Replacements)
+		}
 	}
-	return edits
+	return nil
 }
 
-func minimalTextEdit(original, modified []byte) []*protocol.TextEdit {
-	start, end, replacement, ok := minimalReplacement(original, modified)
-	if !ok {
+// textEdits converts fix replacements of original into LSP text edits.
+// Returns nil when there are no replacements.
+func textEdits(original []byte, replacements []fix.Replacement) []*protocol.TextEdit {
+	if len(replacements) == 0 {
 		return nil
 	}
-
-	return []*protocol.TextEdit{
-		{
+	edits := make([]*protocol.TextEdit, 0, len(replacements))
+	for _, r := range replacements {
+		edits = append(edits, &protocol.TextEdit{
 			Range: protocol.Range{
-				Start: positionAtOffset(original, start),
-				End:   positionAtOffset(original, end),
+				Start: positionAtOffset(original, r.Start),
+				End:   positionAtOffset(original, r.End),
 			},
-			NewText: string(replacement),
-		},
-	}
-}
-
-func minimalReplacement(original, modified []byte) (int, int, []byte, bool) {
-	if bytes.Equal(original, modified) {
-		return 0, 0, nil, false
+			NewText: r.NewText,
+		})
 	}
-
-	prefix := 0
-	for prefix < len(original) && prefix < len(modified) {
-		r1, size1 := utf8.DecodeRune(original[prefix:])
-		r2, size2 := utf8.DecodeRune(modified[prefix:])
-		if r1 != r2 || size1 != size2 || !bytes.Equal(original[prefix:prefix+size1], modified[prefix:prefix+size2]) {
-			break
-		}
-		prefix += size1
-	}
-
-	origEnd := len(original)
-	modEnd := len(modified)
-	for origEnd > prefix && modEnd > prefix {
-		r1, size1 := utf8.DecodeLastRune(original[:origEnd])
-		r2, size2 := utf8.DecodeLastRune(modified[:modEnd])
-		if origEnd-size1 < prefix || modEnd-size2 < prefix {
-			break
-		}
-		if r1 != r2 || size1 != size2 || !bytes.Equal(original[origEnd-size1:origEnd], modified[modEnd-size2:modEnd]) {
-			break
-		}
-		origEnd -= size1
-		modEnd -= size2
-	}
-
-	start := prefix
-	end := origEnd
-	replStart := prefix
-	replEnd := modEnd
-	replacement := modified[replStart:replEnd]
-	return start, end, replacement, true
+	return edits
 }
 
 func positionAtOffset(content []byte, offset int) protocol.Position {
@@ -255,6 +255,8 @@ func (s *Server) handle(ctx context.Context, req *jsonrpc2.Request) (any, error)
 		return unmarshalAndCall(req, func(p *protocol.DocumentRangesFormattingParams) (any, error) {
 			return s.handleRangesFormatting(ctx, p)
 		})
+	case string(protocol.MethodTextDocumentCompletion):
+		return unmarshalAndCall(req, s.handleCompletion)
 	case string(protocol.MethodTextDocumentSemanticTokensFull):
 		return unmarshalAndCall(req, func(p *protocol.SemanticTokensParams) (any, error) {
 			return s.handleSemanticTokensFull(ctx, p)
@@ -471,6 +473,10 @@ func (s *Server) handleInitialize(params *protocol.InitializeParams) (any, error
 			CodeActionProvider: &protocol.BooleanOrCodeActionOptions{
 				CodeActionOptions: codeActionOptions(codeActionDocSupported),
 			},
+			// Completes "# tally" directive keywords and rule codes.
+			CompletionProvider: &protocol.CompletionOptions{
+				TriggerCharacters: &[]string{" ", "=", ","},
+			},
 			DocumentFormattingProvider: &protocol.BooleanOrDocumentFormattingOptions{
 				Boolean: new(true),
 			},
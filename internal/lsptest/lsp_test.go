@@ -541,8 +541,8 @@ func TestLSP_NoPushDiagnosticsWhenClientSupportsPull(t *testing.T) {
 	assert.Equal(t, "full", report.Kind)
 	assert.NotEmpty(t, report.Items, "expected diagnostics for CMD in shell form")
 	assert.True(t, slices.ContainsFunc(report.Items, func(d diagnostic) bool {
-		return d.Code == "buildkit/JSONArgsRecommended"
-	}), "expected JSONArgsRecommended in pull diagnostics")
+		return d.Code == "tally/runtime/shell-form-entrypoint"
+	}), "expected shell-form-entrypoint in pull diagnostics")
 }
 
 func TestLSP_CodeActionInPullDiagnosticsMode(t *testing.T) {
@@ -0,0 +1,81 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/wharflab/tally/internal/ruledeprecation"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit"
+)
+
+type explainRuleInput struct {
+	Rule string `json:"rule" jsonschema:"rule code, e.g. hadolint/DL3006, buildkit/StageNameCasing, or tally/max-lines"`
+}
+
+type explainRuleOutput struct {
+	Code            string         `json:"code"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	DocURL          string         `json:"docUrl,omitempty"`
+	DefaultSeverity string         `json:"defaultSeverity"`
+	Category        string         `json:"category,omitempty"`
+	Experimental    bool           `json:"experimental,omitempty"`
+	ConfigSchema    map[string]any `json:"configSchema,omitempty"`
+	// Deprecated is set when the requested code is a deprecated alias; the
+	// remaining fields then describe the replacement rule.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+func explainRuleTool(_ context.Context, _ *mcp.CallToolRequest, in explainRuleInput) (*mcp.CallToolResult, explainRuleOutput, error) {
+	out, err := explainRule(strings.TrimSpace(in.Rule))
+	return nil, out, err
+}
+
+func explainRule(code string) (explainRuleOutput, error) {
+	if code == "" {
+		return explainRuleOutput{}, errors.New("rule is required")
+	}
+
+	var deprecated string
+	if entry, ok := ruledeprecation.Lookup(code); ok {
+		deprecated = (ruledeprecation.Notice{Entry: entry}).Message()
+		if entry.Kind != ruledeprecation.KindSuperseded {
+			return explainRuleOutput{Code: entry.Code, Deprecated: deprecated}, nil
+		}
+		code = entry.Replacement
+	}
+
+	var (
+		meta   *rules.RuleMetadata
+		schema map[string]any
+	)
+	if rule := rules.DefaultRegistry().Get(code); rule != nil {
+		m := rule.Metadata()
+		meta = &m
+		if cr, ok := rule.(rules.ConfigurableRule); ok {
+			schema = cr.Schema()
+		}
+	} else if name, ok := strings.CutPrefix(code, rules.BuildKitRulePrefix); ok {
+		meta = buildkit.GetMetadata(name)
+	}
+	if meta == nil {
+		return explainRuleOutput{}, fmt.Errorf("unknown rule %q", code)
+	}
+
+	return explainRuleOutput{
+		Code:            meta.Code,
+		Name:            meta.Name,
+		Description:     meta.Description,
+		DocURL:          meta.DocURL,
+		DefaultSeverity: meta.DefaultSeverity.String(),
+		Category:        meta.Category,
+		Experimental:    meta.IsExperimental,
+		ConfigSchema:    schema,
+		Deprecated:      deprecated,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+type applyFixInput struct {
+	fileInput
+
+	Rules  []string `json:"rules,omitempty"  jsonschema:"only apply fixes for these rule codes (like --fix-rule); all rules when empty"`
+	Unsafe bool     `json:"unsafe,omitempty" jsonschema:"also apply suggestion and unsafe fixes (like --fix-unsafe), and return AI objective prompts"`
+	Write  bool     `json:"write,omitempty"  jsonschema:"write the fixed content back to path; otherwise the file is left untouched"`
+}
+
+type applyFixOutput struct {
+	File    string        `json:"file"`
+	Changed bool          `json:"changed"`
+	Written bool          `json:"written"`
+	Content string        `json:"content"`
+	Applied []appliedFix  `json:"applied"`
+	Skipped []skippedFix  `json:"skipped,omitempty"`
+	AIFixes []aiObjective `json:"aiFixes,omitempty"`
+}
+
+type appliedFix struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Line        int    `json:"line"`
+}
+
+type skippedFix struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+	Line   int    `json:"line"`
+	Error  string `json:"error,omitempty"`
+}
+
+// aiObjective is a fix tally would delegate to an ACP agent during
+// `tally lint --fix`. Over MCP the calling agent is the AI, so tally hands it
+// the same objective prompt instead of spawning another agent.
+type aiObjective struct {
+	Rule   string `json:"rule"`
+	Line   int    `json:"line"`
+	Prompt string `json:"prompt"`
+}
+
+func applyFixTool(ctx context.Context, _ *mcp.CallToolRequest, in applyFixInput) (*mcp.CallToolResult, applyFixOutput, error) {
+	out, err := applyFix(ctx, in)
+	return nil, out, err
+}
+
+func applyFix(ctx context.Context, in applyFixInput) (applyFixOutput, error) {
+	lf, err := lintFile(ctx, in.fileInput)
+	if err != nil {
+		return applyFixOutput{}, err
+	}
+
+	safety := fix.FixSafe
+	if in.Unsafe {
+		safety = fix.FixUnsafe
+	}
+	out := applyFixOutput{File: lf.path, Content: string(lf.source), Applied: []appliedFix{}}
+
+	// AI objective fixes are handed back as prompts; everything else goes
+	// through the regular fixer.
+	var violations []rules.Violation
+	for _, v := range lf.violations {
+		sf := aiFix(v)
+		if sf == nil {
+			violations = append(violations, v)
+			continue
+		}
+		if !in.Unsafe || !lf.config.AI.AllowsRule(v.RuleCode) ||
+			(len(in.Rules) > 0 && !slices.Contains(in.Rules, v.RuleCode)) {
+			continue
+		}
+		prompt, err := buildObjectivePrompt(lf, v, sf)
+		if err != nil {
+			return applyFixOutput{}, fmt.Errorf("%s: %w", v.RuleCode, err)
+		}
+		out.AIFixes = append(out.AIFixes, aiObjective{Rule: v.RuleCode, Line: v.Location.Start.Line, Prompt: prompt})
+	}
+
+	fileKey := filepath.Clean(lf.path)
+	fixer := &fix.Fixer{
+		SafetyThreshold:   safety,
+		RuleFilter:        in.Rules,
+		EnabledRules:      map[string][]string{fileKey: linter.EnabledRuleCodes(lf.config)},
+		SlowChecksEnabled: map[string]bool{fileKey: false},
+		FixModes:          map[string]map[string]fix.FixMode{fileKey: fix.BuildFixModes(lf.config)},
+		FixPrecedence:     map[string][]string{fileKey: lf.config.FixPrecedence},
+	}
+	result, err := fixer.Apply(ctx, violations, map[string][]byte{lf.path: lf.source})
+	if err != nil {
+		return applyFixOutput{}, err
+	}
+
+	change := result.Changes[fileKey]
+	if change == nil {
+		return out, nil
+	}
+	for _, f := range change.FixesApplied {
+		out.Applied = append(out.Applied, appliedFix{
+			Rule:        f.RuleCode,
+			Description: f.Description,
+			Line:        f.Location.Start.Line,
+		})
+	}
+	for _, s := range change.FixesSkipped {
+		out.Skipped = append(out.Skipped, skippedFix{
+			Rule:   s.RuleCode,
+			Reason: s.Reason.String(),
+			Line:   s.Location.Start.Line,
+			Error:  s.Error,
+		})
+	}
+	if !change.HasChanges() || bytes.Equal(change.ModifiedContent, lf.source) {
+		return out, nil
+	}
+
+	out.Changed = true
+	out.Content = string(change.ModifiedContent)
+	if in.Write {
+		info, err := os.Stat(lf.path)
+		if err != nil {
+			return applyFixOutput{}, err
+		}
+		if err := os.WriteFile(lf.path, change.ModifiedContent, info.Mode().Perm()); err != nil {
+			return applyFixOutput{}, err
+		}
+		out.Written = true
+	}
+	return out, nil
+}
+
+// aiFix returns the AI AutoFix objective fix attached to v, if any.
+func aiFix(v rules.Violation) *rules.SuggestedFix {
+	for _, sf := range v.AllFixes() {
+		if sf.NeedsResolve && sf.ResolverID == autofixdata.ResolverID {
+			return sf
+		}
+	}
+	return nil
+}
+
+// buildObjectivePrompt renders the round-1 prompt the ACP resolver would send,
+// asking for a unified diff so the calling agent can apply it with its own
+// editing tools.
+func buildObjectivePrompt(lf *lintedFile, v rules.Violation, sf *rules.SuggestedFix) (string, error) {
+	req, ok := sf.ResolverData.(*autofixdata.ObjectiveRequest)
+	if !ok || req == nil {
+		return "", fmt.Errorf("unexpected AI AutoFix request type %T", sf.ResolverData)
+	}
+	obj, ok := autofixdata.GetObjective(req.Kind)
+	if !ok {
+		return "", fmt.Errorf("unknown AI AutoFix objective %q", req.Kind)
+	}
+	req.SetConfig(lf.config)
+	req.SetViolation(autofixdata.NewViolationContext(v))
+	pc := autofixdata.PromptContext{
+		FilePath:  lf.path,
+		Source:    lf.source,
+		Request:   req,
+		Config:    lf.config,
+		AbsPath:   lf.path,
+		OrigParse: lf.parse,
+		Mode:      autofixdata.OutputPatch,
+	}
+	if tmpl, ok := autofixdata.CustomPromptTemplate(pc); ok {
+		return autofixdata.BuildCustomPrompt(tmpl, pc)
+	}
+	return obj.BuildPrompt(pc)
+}
@@ -0,0 +1,167 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/wharflab/tally/internal/ai/autofixdata"
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/fileval"
+	"github.com/wharflab/tally/internal/linter"
+	"github.com/wharflab/tally/internal/processor"
+	"github.com/wharflab/tally/internal/reporter"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/syntax"
+)
+
+// fileInput identifies the Dockerfile a tool operates on.
+type fileInput struct {
+	Path    string `json:"path"              jsonschema:"path to the Dockerfile; used for config discovery and reading the file when content is omitted"`
+	Content string `json:"content,omitempty" jsonschema:"optional in-memory Dockerfile content (e.g. unsaved edits); overrides the file on disk"`
+}
+
+type lintOutput struct {
+	File       string      `json:"file"`
+	Violations []violation `json:"violations"`
+}
+
+// violation is the MCP-facing view of rules.Violation. Lines are 1-based and
+// columns 0-based, matching tally's JSON output.
+type violation struct {
+	Rule      string    `json:"rule"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+	Detail    string    `json:"detail,omitempty"`
+	DocURL    string    `json:"docUrl,omitempty"`
+	Line      int       `json:"line"`
+	Column    int       `json:"column"`
+	EndLine   int       `json:"endLine"`
+	EndColumn int       `json:"endColumn"`
+	Fixes     []fixInfo `json:"fixes,omitempty"`
+}
+
+type fixInfo struct {
+	Description string `json:"description"`
+	Safety      string `json:"safety"`
+	// NeedsAI marks fixes that tally delegates to an AI agent. apply_fix
+	// returns an objective prompt for these instead of editing the file.
+	NeedsAI bool `json:"needsAI,omitempty"`
+}
+
+// lintedFile is the result of running the shared lint pipeline on one file.
+type lintedFile struct {
+	path       string
+	source     []byte
+	config     *config.Config
+	parse      *dockerfile.ParseResult
+	violations []rules.Violation
+}
+
+func lintTool(ctx context.Context, _ *mcp.CallToolRequest, in fileInput) (*mcp.CallToolResult, lintOutput, error) {
+	lf, err := lintFile(ctx, in)
+	if err != nil {
+		return nil, lintOutput{}, err
+	}
+	out := lintOutput{File: lf.path, Violations: make([]violation, 0, len(lf.violations))}
+	for _, v := range lf.violations {
+		out.Violations = append(out.Violations, toViolation(v))
+	}
+	return nil, out, nil
+}
+
+// lintFile loads config, parses, and lints a file the same way `tally lint`
+// does for a single path. Slow checks (registry lookups) are not run.
+func lintFile(ctx context.Context, in fileInput) (*lintedFile, error) {
+	if in.Path == "" {
+		return nil, errors.New("path is required")
+	}
+	path, err := filepath.Abs(in.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var content []byte
+	if in.Content != "" {
+		content = []byte(in.Content)
+	} else {
+		content, err = fileval.ReadValidatedFile(path, cfg.FileValidation.MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parseResult, err := dockerfile.Parse(bytes.NewReader(content), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if syntaxErrors := syntax.Check(path, parseResult.AST, parseResult.Source); len(syntaxErrors) > 0 {
+		msgs := make([]string, 0, len(syntaxErrors))
+		for _, e := range syntaxErrors {
+			msgs = append(msgs, e.Error())
+		}
+		return nil, errors.New(strings.Join(msgs, "\n"))
+	}
+
+	result, err := linter.LintFileContext(ctx, linter.Input{
+		FilePath:    path,
+		Content:     content,
+		Config:      cfg,
+		ParseResult: parseResult,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lint %s: %w", path, err)
+	}
+
+	fileConfigs := map[string]*config.Config{path: result.Config}
+	sources := map[string][]byte{path: result.ParseResult.Source}
+	chain, inlineFilter := linter.CLIProcessors()
+	procCtx := processor.NewContext(fileConfigs, result.Config, sources)
+	violations := chain.Process(result.Violations, procCtx)
+	if extra := inlineFilter.AdditionalViolations(); len(extra) > 0 {
+		extra = processor.NewPathNormalization().Process(extra, procCtx)
+		extra = processor.NewSnippetAttachment().Process(extra, procCtx)
+		violations = reporter.SortViolations(append(violations, extra...))
+	}
+
+	return &lintedFile{
+		path:       path,
+		source:     result.ParseResult.Source,
+		config:     result.Config,
+		parse:      result.ParseResult,
+		violations: violations,
+	}, nil
+}
+
+func toViolation(v rules.Violation) violation {
+	out := violation{
+		Rule:      v.RuleCode,
+		Severity:  v.Severity.String(),
+		Message:   v.Message,
+		Detail:    v.Detail,
+		DocURL:    v.DocURL,
+		Line:      v.Location.Start.Line,
+		Column:    v.Location.Start.Column,
+		EndLine:   v.Location.End.Line,
+		EndColumn: v.Location.End.Column,
+	}
+	for _, sf := range v.AllFixes() {
+		out.Fixes = append(out.Fixes, fixInfo{
+			Description: sf.Description,
+			Safety:      sf.Safety.String(),
+			NeedsAI:     sf.NeedsResolve && sf.ResolverID == autofixdata.ResolverID,
+		})
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+// Package mcpserver exposes tally's lint and fix pipeline over the Model
+// Context Protocol (MCP) so AI agents can call tally as a native tool.
+//
+// The server complements the ACP-based AI AutoFix integration in internal/ai:
+// ACP lets tally drive an agent, MCP lets an agent drive tally. Fixes that
+// tally would normally delegate to an ACP agent are returned to the MCP client
+// as ready-to-use objective prompts built by internal/ai/autofixdata.
+package mcpserver
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/wharflab/tally/internal/version"
+)
+
+const serverName = "tally"
+
+const instructions = `tally lints Dockerfiles and Containerfiles.
+Use lint_dockerfile to list violations, explain_rule to read what a rule checks and how to configure it,
+and apply_fix to compute (and optionally write) tally's auto-fixes.
+Fixes that need an AI rewrite are returned as objective prompts for you to carry out.`
+
+// Server is an MCP server backed by tally's linter.
+type Server struct {
+	mcp *mcp.Server
+}
+
+// New creates an MCP server with tally's tools registered.
+func New() *Server {
+	s := mcp.NewServer(&mcp.Implementation{
+		Name:    serverName,
+		Title:   "tally Dockerfile linter",
+		Version: version.Version(),
+	}, &mcp.ServerOptions{
+		Instructions: instructions,
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "lint_dockerfile",
+		Description: "Lint a Dockerfile or Containerfile and return its violations. Config is discovered from the file's directory like the CLI.",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, lintTool)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "explain_rule",
+		Description: "Describe a tally rule: what it checks, default severity, documentation URL, and configuration schema.",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, explainRuleTool)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "apply_fix",
+		Description: "Apply tally's auto-fixes to a Dockerfile and return the fixed content. " +
+			"The file is only modified when write is true.",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: new(false)},
+	}, applyFixTool)
+
+	return &Server{mcp: s}
+}
+
+// Run serves MCP requests on the given transport until the client disconnects
+// or ctx is canceled.
+func (s *Server) Run(ctx context.Context, transport mcp.Transport) error {
+	return s.mcp.Run(ctx, transport)
+}
+
+// RunStdio serves MCP requests over stdin/stdout.
+func (s *Server) RunStdio(ctx context.Context) error {
+	return s.Run(ctx, &mcp.StdioTransport{})
+}
@@ -0,0 +1,184 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func connect(t *testing.T) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() {
+		_ = New().Run(ctx, serverTransport)
+	}()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "v0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func callTool[T any](t *testing.T, session *mcp.ClientSession, name string, args map[string]any) (T, *mcp.CallToolResult) {
+	t.Helper()
+	var out T
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if res.IsError {
+		return out, res
+	}
+	data, err := json.Marshal(res.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("%s: decode structured content: %v", name, err)
+	}
+	return out, res
+}
+
+func writeDockerfile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestListTools(t *testing.T) {
+	t.Parallel()
+	session := connect(t)
+
+	res, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+	}
+	want := []string{"apply_fix", "explain_rule", "lint_dockerfile"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("tools = %v, want %v", names, want)
+	}
+}
+
+func TestLintDockerfile(t *testing.T) {
+	t.Parallel()
+	session := connect(t)
+	path := writeDockerfile(t, "FROM alpine:3.20\nMAINTAINER someone@example.com\n")
+
+	out, res := callTool[lintOutput](t, session, "lint_dockerfile", map[string]any{"path": path})
+	if res.IsError {
+		t.Fatalf("unexpected tool error: %v", res.Content)
+	}
+	var found *violation
+	for i := range out.Violations {
+		if out.Violations[i].Rule == "buildkit/MaintainerDeprecated" {
+			found = &out.Violations[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("expected buildkit/MaintainerDeprecated, got %+v", out.Violations)
+	}
+	if found.Line != 2 {
+		t.Errorf("line = %d, want 2", found.Line)
+	}
+
+	// In-memory content overrides the file on disk.
+	out, _ = callTool[lintOutput](t, session, "lint_dockerfile", map[string]any{
+		"path":    path,
+		"content": "FROM alpine:3.20\n",
+	})
+	for _, v := range out.Violations {
+		if v.Rule == "buildkit/MaintainerDeprecated" {
+			t.Errorf("content override ignored: %+v", v)
+		}
+	}
+}
+
+func TestLintDockerfileSyntaxError(t *testing.T) {
+	t.Parallel()
+	session := connect(t)
+	path := writeDockerfile(t, "FROM alpine:3.20\nFROMM scratch\n")
+
+	_, res := callTool[lintOutput](t, session, "lint_dockerfile", map[string]any{"path": path})
+	if !res.IsError {
+		t.Fatal("expected tool error for syntax error")
+	}
+}
+
+func TestExplainRule(t *testing.T) {
+	t.Parallel()
+	session := connect(t)
+
+	out, res := callTool[explainRuleOutput](t, session, "explain_rule", map[string]any{"rule": "hadolint/DL3006"})
+	if res.IsError {
+		t.Fatalf("unexpected tool error: %v", res.Content)
+	}
+	if out.Code != "hadolint/DL3006" || out.Description == "" || out.DocURL == "" {
+		t.Errorf("unexpected explanation: %+v", out)
+	}
+
+	out, _ = callTool[explainRuleOutput](t, session, "explain_rule", map[string]any{"rule": "buildkit/MaintainerDeprecated"})
+	if out.Code != "buildkit/MaintainerDeprecated" || out.Description == "" {
+		t.Errorf("unexpected BuildKit explanation: %+v", out)
+	}
+
+	out, _ = callTool[explainRuleOutput](t, session, "explain_rule", map[string]any{"rule": "hadolint/DL4000"})
+	if out.Code != "buildkit/MaintainerDeprecated" || out.Deprecated == "" {
+		t.Errorf("expected deprecated alias to resolve to its replacement, got %+v", out)
+	}
+
+	_, res = callTool[explainRuleOutput](t, session, "explain_rule", map[string]any{"rule": "tally/no-such-rule"})
+	if !res.IsError {
+		t.Error("expected tool error for unknown rule")
+	}
+}
+
+func TestApplyFix(t *testing.T) {
+	t.Parallel()
+	session := connect(t)
+	original := "FROM alpine:3.20\nMAINTAINER someone@example.com\n"
+	path := writeDockerfile(t, original)
+
+	out, res := callTool[applyFixOutput](t, session, "apply_fix", map[string]any{
+		"path":  path,
+		"rules": []string{"buildkit/MaintainerDeprecated"},
+	})
+	if res.IsError {
+		t.Fatalf("unexpected tool error: %v", res.Content)
+	}
+	if !out.Changed || strings.Contains(out.Content, "MAINTAINER") {
+		t.Fatalf("expected MAINTAINER to be fixed, got %+v", out)
+	}
+	if out.Written {
+		t.Error("file must not be written without write=true")
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("file modified without write=true:\n%s", data)
+	}
+
+	out, _ = callTool[applyFixOutput](t, session, "apply_fix", map[string]any{
+		"path":  path,
+		"rules": []string{"buildkit/MaintainerDeprecated"},
+		"write": true,
+	})
+	if !out.Written {
+		t.Fatal("expected file to be written")
+	}
+	if data, _ := os.ReadFile(path); string(data) != out.Content {
+		t.Errorf("written content mismatch:\n%s", data)
+	}
+}
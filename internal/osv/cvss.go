@@ -0,0 +1,86 @@
+package osv
+
+import (
+	"math"
+	"strings"
+)
+
+// cvss3BaseScore computes the base score of a CVSS v3.0 or v3.1 vector such
+// as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H". It returns false for
+// other versions or malformed vectors.
+func cvss3BaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 2 || (parts[0] != "CVSS:3.0" && parts[0] != "CVSS:3.1") {
+		return 0, false
+	}
+	metrics := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, ok := strings.Cut(p, ":")
+		if !ok {
+			return 0, false
+		}
+		metrics[k] = v
+	}
+
+	scopeChanged := metrics["S"] == "C"
+	av, ok1 := metricWeight(metrics["AV"], map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2})
+	ac, ok2 := metricWeight(metrics["AC"], map[string]float64{"L": 0.77, "H": 0.44})
+	prWeights := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	if scopeChanged {
+		prWeights = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+	}
+	pr, ok3 := metricWeight(metrics["PR"], prWeights)
+	ui, ok4 := metricWeight(metrics["UI"], map[string]float64{"N": 0.85, "R": 0.62})
+	impactWeights := map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	c, ok5 := metricWeight(metrics["C"], impactWeights)
+	i, ok6 := metricWeight(metrics["I"], impactWeights)
+	a, ok7 := metricWeight(metrics["A"], impactWeights)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 || !ok7 || (metrics["S"] != "U" && !scopeChanged) {
+		return 0, false
+	}
+
+	iss := 1 - (1-c)*(1-i)*(1-a)
+	impact := 6.42 * iss
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * av * ac * pr * ui
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+func metricWeight(value string, weights map[string]float64) (float64, bool) {
+	w, ok := weights[value]
+	return w, ok
+}
+
+// roundUp implements the CVSS v3.1 Roundup function: the smallest number,
+// to one decimal place, that is equal to or higher than x.
+func roundUp(x float64) float64 {
+	n := int64(math.Round(x * 100000))
+	if n%10000 == 0 {
+		return float64(n) / 100000
+	}
+	return float64(n/10000+1) / 10
+}
+
+// cvssSeverity maps a CVSS base score to its qualitative rating.
+func cvssSeverity(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
@@ -0,0 +1,114 @@
+// Package osv reports known vulnerabilities in the distribution packages of
+// container images, using the OSV database (https://osv.dev).
+//
+// The resolver reads the os-release file and package database (dpkg or apk)
+// from an image's layers, queries OSV for each installed source package, and
+// classifies every advisory by severity.
+package osv
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Severity is the severity of an advisory, ordered from least to most severe.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// String returns the lowercase severity name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSeverity parses a severity name as used by advisory databases.
+// "moderate" (GitHub) and "important" (Red Hat) map to medium and high.
+func ParseSeverity(s string) (Severity, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return SeverityCritical, true
+	case "high", "important":
+		return SeverityHigh, true
+	case "medium", "moderate":
+		return SeverityMedium, true
+	case "low", "negligible":
+		return SeverityLow, true
+	default:
+		return SeverityUnknown, false
+	}
+}
+
+// Vulnerability is one advisory affecting an installed package.
+type Vulnerability struct {
+	// ID is the OSV identifier (e.g. "DEBIAN-CVE-2024-6119", "ALPINE-CVE-2024-5535").
+	ID string
+
+	// CVE is the upstream CVE identifier when the advisory names one.
+	CVE string
+
+	// Package is the affected source package.
+	Package string
+
+	Severity Severity
+}
+
+// Report is the vulnerability summary for one image.
+type Report struct {
+	// Ecosystem is the OSV ecosystem queried (e.g. "Debian:12").
+	Ecosystem string
+
+	// Packages is the number of distinct packages queried.
+	Packages int
+
+	// Vulnerabilities are sorted by severity (most severe first), then ID.
+	Vulnerabilities []Vulnerability
+}
+
+// AtLeast returns the vulnerabilities whose severity is min or higher.
+func (r *Report) AtLeast(minSeverity Severity) []Vulnerability {
+	var out []Vulnerability
+	for _, v := range r.Vulnerabilities {
+		if v.Severity >= minSeverity {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Count returns the number of vulnerabilities with exactly severity s.
+func (r *Report) Count(s Severity) int {
+	n := 0
+	for _, v := range r.Vulnerabilities {
+		if v.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+func sortVulnerabilities(vulns []Vulnerability) {
+	slices.SortFunc(vulns, func(a, b Vulnerability) int {
+		if c := cmp.Compare(b.Severity, a.Severity); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,162 @@
+package osv
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Image paths read by the resolver. /etc/os-release is usually a symlink to
+// /usr/lib/os-release, and symlinks are not followed, so both are requested.
+const (
+	etcOSReleasePath = "/etc/os-release"
+	usrOSReleasePath = "/usr/lib/os-release"
+	dpkgStatusPath   = "/var/lib/dpkg/status"
+	apkInstalledPath = "/lib/apk/db/installed"
+)
+
+var imagePaths = []string{etcOSReleasePath, usrOSReleasePath, dpkgStatusPath, apkInstalledPath}
+
+// Distro identifies an image's distribution from its os-release file.
+type Distro struct {
+	ID        string // e.g. "debian", "ubuntu", "alpine"
+	VersionID string // e.g. "12", "22.04", "3.20.3"
+}
+
+// ParseOSRelease parses the os-release(5) fields tally needs.
+func ParseOSRelease(data []byte) Distro {
+	var d Distro
+	for line := range strings.Lines(string(data)) {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			d.ID = strings.ToLower(value)
+		case "VERSION_ID":
+			d.VersionID = value
+		}
+	}
+	return d
+}
+
+// Ecosystem returns the OSV ecosystem for the distribution release, or false
+// when OSV has no matching ecosystem or the release is not versioned (such as
+// Debian testing).
+func (d Distro) Ecosystem() (string, bool) {
+	if d.VersionID == "" {
+		return "", false
+	}
+	switch d.ID {
+	case "debian":
+		major, _, _ := strings.Cut(d.VersionID, ".")
+		return "Debian:" + major, true
+	case "ubuntu":
+		eco := "Ubuntu:" + d.VersionID
+		// LTS releases ship every two years in April (20.04, 22.04, ...).
+		year, month, _ := strings.Cut(d.VersionID, ".")
+		if y, err := strconv.Atoi(year); err == nil && y%2 == 0 && month == "04" {
+			eco += ":LTS"
+		}
+		return eco, true
+	case "alpine":
+		parts := strings.SplitN(d.VersionID, ".", 3)
+		if len(parts) < 2 {
+			return "", false
+		}
+		return "Alpine:v" + parts[0] + "." + parts[1], true
+	}
+	return "", false
+}
+
+// Package is an installed source package.
+type Package struct {
+	Name    string
+	Version string
+}
+
+// ParseDpkgStatus returns the source packages of installed dpkg packages.
+// Debian and Ubuntu advisories are keyed by source package, so binary
+// packages built from the same source collapse into one entry.
+func ParseDpkgStatus(data []byte) []Package {
+	var (
+		pkgs []Package
+		seen = make(map[Package]bool)
+	)
+	for _, fields := range stanzas(data) {
+		if !strings.HasSuffix(fields["Status"], " installed") {
+			continue
+		}
+		p := Package{Name: fields["Package"], Version: fields["Version"]}
+		// "Source: glibc" or "Source: glibc (2.36-9+deb12u4)" when the source
+		// version differs from the binary version.
+		if src := fields["Source"]; src != "" {
+			name, version, ok := strings.Cut(src, " ")
+			p.Name = name
+			if ok {
+				p.Version = strings.Trim(version, "()")
+			}
+		}
+		if p.Name == "" || p.Version == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		pkgs = append(pkgs, p)
+	}
+	return pkgs
+}
+
+// ParseApkInstalled returns the origin packages of installed apk packages.
+func ParseApkInstalled(data []byte) []Package {
+	var (
+		pkgs []Package
+		seen = make(map[Package]bool)
+	)
+	for _, fields := range stanzas(data) {
+		p := Package{Name: fields["o"], Version: fields["V"]}
+		if p.Name == "" {
+			p.Name = fields["P"]
+		}
+		if p.Name == "" || p.Version == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		pkgs = append(pkgs, p)
+	}
+	return pkgs
+}
+
+// stanzas splits blank-line separated "Key: value" records. Continuation
+// lines (leading whitespace) are ignored; none of the fields read here span
+// lines.
+func stanzas(data []byte) []map[string]string {
+	var (
+		out     []map[string]string
+		current map[string]string
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		i := strings.IndexByte(line, ':')
+		if i <= 0 {
+			continue
+		}
+		if current == nil {
+			current = make(map[string]string)
+			out = append(out, current)
+		}
+		current[line[:i]] = strings.TrimSpace(line[i+1:])
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package osv
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistroEcosystem(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		release string
+		want    string
+	}{
+		{"ID=debian\nVERSION_ID=\"12\"\n", "Debian:12"},
+		{"PRETTY_NAME=\"Ubuntu 22.04.4 LTS\"\nID=ubuntu\nVERSION_ID=\"22.04\"\n", "Ubuntu:22.04:LTS"},
+		{"ID=ubuntu\nVERSION_ID=\"23.10\"\n", "Ubuntu:23.10"},
+		{"ID=ubuntu\nVERSION_ID=\"21.04\"\n", "Ubuntu:21.04"},
+		{"ID=alpine\nVERSION_ID=3.20.3\n", "Alpine:v3.20"},
+		{"ID=debian\n", ""}, // testing/sid has no VERSION_ID
+		{"ID=fedora\nVERSION_ID=40\n", ""},
+	}
+	for _, tt := range tests {
+		got, ok := ParseOSRelease([]byte(tt.release)).Ecosystem()
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Ecosystem(%q) = %q, %v; want %q", tt.release, got, ok, tt.want)
+		}
+	}
+}
+
+func TestParseDpkgStatus(t *testing.T) {
+	t.Parallel()
+
+	status := `Package: libc6
+Status: install ok installed
+Source: glibc
+Version: 2.36-9+deb12u4
+Description: GNU C Library
+ continuation line: ignored
+
+Package: libc-bin
+Status: install ok installed
+Source: glibc
+Version: 2.36-9+deb12u4
+
+Package: libssl3
+Status: install ok installed
+Source: openssl (3.0.13-1~deb12u1)
+Version: 3.0.13-1~deb12u1+b1
+
+Package: bash
+Status: install ok installed
+Version: 5.2.15-2+b2
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+`
+	got := ParseDpkgStatus([]byte(status))
+	want := []Package{
+		{Name: "glibc", Version: "2.36-9+deb12u4"},
+		{Name: "openssl", Version: "3.0.13-1~deb12u1"},
+		{Name: "bash", Version: "5.2.15-2+b2"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseDpkgStatus = %v, want %v", got, want)
+	}
+}
+
+func TestParseApkInstalled(t *testing.T) {
+	t.Parallel()
+
+	installed := `C:Q1abc=
+P:libcrypto3
+V:3.3.2-r0
+o:openssl
+
+P:libssl3
+V:3.3.2-r0
+o:openssl
+
+P:busybox
+V:1.36.1-r29
+`
+	got := ParseApkInstalled([]byte(installed))
+	want := []Package{
+		{Name: "openssl", Version: "3.3.2-r0"},
+		{Name: "busybox", Version: "1.36.1-r29"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseApkInstalled = %v, want %v", got, want)
+	}
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		vector string
+		want   float64
+		sev    Severity
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, SeverityCritical},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10, SeverityCritical},
+		{"CVSS:3.0/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", 5.9, SeverityMedium},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8, SeverityHigh},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", 5.4, SeverityMedium},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.6, SeverityLow},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, SeverityUnknown},
+	}
+	for _, tt := range tests {
+		got, ok := cvss3BaseScore(tt.vector)
+		if !ok || got != tt.want || cvssSeverity(got) != tt.sev {
+			t.Errorf("cvss3BaseScore(%q) = %v, %v (%s); want %v (%s)", tt.vector, got, ok, cvssSeverity(got), tt.want, tt.sev)
+		}
+	}
+
+	for _, bad := range []string{
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.1/AV:N",
+	} {
+		if _, ok := cvss3BaseScore(bad); ok {
+			t.Errorf("cvss3BaseScore(%q) should fail", bad)
+		}
+	}
+}
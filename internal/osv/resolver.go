@@ -0,0 +1,354 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/httpfetch"
+	"github.com/wharflab/tally/internal/registry"
+)
+
+// ResolverID is the async resolver ID for OSV vulnerability lookups.
+const ResolverID = "osv"
+
+// DefaultBaseURL is the OSV API root.
+const DefaultBaseURL = "https://api.osv.dev"
+
+const (
+	// batchSize is the maximum number of queries per /v1/querybatch call.
+	batchSize = 1000
+	// fetchConcurrency bounds concurrent /v1/vulns requests.
+	fetchConcurrency = 8
+)
+
+// Request asks the resolver for the vulnerability report of one image.
+type Request struct {
+	Ref      string
+	Platform string
+}
+
+// Resolver reads an image's package database from its registry and looks up
+// the installed packages in OSV. Resolve returns a *Report.
+//
+// Advisory severities are cached for the lifetime of the resolver, so images
+// sharing packages only fetch each advisory once.
+type Resolver struct {
+	Files   registry.ImageFileReader
+	BaseURL string
+	Client  *http.Client
+
+	mu         sync.Mutex
+	severities map[string]Severity // advisory ID → classified severity
+}
+
+// NewResolver creates a resolver for the public OSV API that reads image
+// files through files.
+func NewResolver(files registry.ImageFileReader) *Resolver {
+	return &Resolver{Files: files, BaseURL: DefaultBaseURL, Client: httpfetch.NewClient(30 * time.Second)}
+}
+
+// ID returns the resolver identifier.
+func (r *Resolver) ID() string { return ResolverID }
+
+// Resolve builds the vulnerability report for a *Request.
+func (r *Resolver) Resolve(ctx context.Context, data any) (any, error) {
+	req, ok := data.(*Request)
+	if !ok {
+		return nil, fmt.Errorf("osv resolver: unexpected data type %T", data)
+	}
+	if r.Files == nil {
+		return nil, &UnsupportedImageError{Ref: req.Ref, Reason: "image files cannot be read"}
+	}
+	files, err := r.Files.ReadImageFiles(ctx, req.Ref, req.Platform, imagePaths)
+	if err != nil {
+		return nil, err
+	}
+	ecosystem, pkgs, err := imagePackages(req.Ref, files)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Ecosystem: ecosystem, Packages: len(pkgs)}
+	affected, err := r.queryBatch(ctx, ecosystem, pkgs)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.classify(ctx, affected); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	for _, v := range affected {
+		v.Severity = r.severities[v.ID]
+		report.Vulnerabilities = append(report.Vulnerabilities, v)
+	}
+	r.mu.Unlock()
+	sortVulnerabilities(report.Vulnerabilities)
+	return report, nil
+}
+
+// imagePackages detects the OSV ecosystem and installed packages from the
+// files read out of the image.
+func imagePackages(ref string, files map[string][]byte) (string, []Package, error) {
+	release, ok := files[etcOSReleasePath]
+	if !ok {
+		release, ok = files[usrOSReleasePath]
+	}
+	if !ok {
+		return "", nil, &UnsupportedImageError{Ref: ref, Reason: "no os-release file"}
+	}
+	distro := ParseOSRelease(release)
+	ecosystem, ok := distro.Ecosystem()
+	if !ok {
+		return "", nil, &UnsupportedImageError{
+			Ref:    ref,
+			Reason: fmt.Sprintf("distribution %q %q is not covered by OSV", distro.ID, distro.VersionID),
+		}
+	}
+
+	var pkgs []Package
+	if status, ok := files[dpkgStatusPath]; ok {
+		pkgs = ParseDpkgStatus(status)
+	} else if installed, ok := files[apkInstalledPath]; ok {
+		pkgs = ParseApkInstalled(installed)
+	} else {
+		return "", nil, &UnsupportedImageError{Ref: ref, Reason: "no dpkg or apk package database"}
+	}
+	return ecosystem, pkgs, nil
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type batchQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type batchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// queryBatch returns one Vulnerability (without severity) per advisory,
+// attributed to the first installed package it affects.
+func (r *Resolver) queryBatch(ctx context.Context, ecosystem string, pkgs []Package) ([]Vulnerability, error) {
+	var (
+		out  []Vulnerability
+		seen = make(map[string]bool)
+	)
+	for start := 0; start < len(pkgs); start += batchSize {
+		chunk := pkgs[start:min(start+batchSize, len(pkgs))]
+		queries := make([]batchQuery, len(chunk))
+		for i, p := range chunk {
+			queries[i] = batchQuery{Package: osvPackage{Name: p.Name, Ecosystem: ecosystem}, Version: p.Version}
+		}
+		var resp batchResponse
+		if err := r.do(ctx, http.MethodPost, "/v1/querybatch", map[string]any{"queries": queries}, &resp); err != nil {
+			return nil, err
+		}
+		for i, result := range resp.Results {
+			if i >= len(chunk) {
+				break
+			}
+			for _, v := range result.Vulns {
+				if seen[v.ID] {
+					continue
+				}
+				seen[v.ID] = true
+				out = append(out, Vulnerability{ID: v.ID, CVE: cveID(v.ID), Package: chunk[i].Name})
+			}
+		}
+	}
+	return out, nil
+}
+
+// osvRecord holds the parts of an OSV record used for severity.
+type osvRecord struct {
+	Aliases  []string `json:"aliases"`
+	Upstream []string `json:"upstream"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// classify fetches and classifies every advisory not already cached. When
+// an advisory carries no severity of its own (Debian and Alpine advisories
+// usually do not), the CVE it tracks is classified instead.
+func (r *Resolver) classify(ctx context.Context, vulns []Vulnerability) error {
+	var ids []string
+	r.mu.Lock()
+	if r.severities == nil {
+		r.severities = make(map[string]Severity)
+	}
+	for _, v := range vulns {
+		if _, ok := r.severities[v.ID]; !ok {
+			ids = append(ids, v.ID)
+		}
+	}
+	r.mu.Unlock()
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, fetchConcurrency)
+		errMu    sync.Mutex
+		firstErr error
+	)
+	for _, id := range ids {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			sev, err := r.severity(ctx, id)
+			if err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errMu.Unlock()
+				return
+			}
+			r.mu.Lock()
+			r.severities[id] = sev
+			r.mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (r *Resolver) severity(ctx context.Context, id string) (Severity, error) {
+	rec, err := r.fetchRecord(ctx, id)
+	if err != nil {
+		return SeverityUnknown, err
+	}
+	if sev := recordSeverity(rec); sev != SeverityUnknown {
+		return sev, nil
+	}
+	for _, alias := range slices.Concat(rec.Upstream, rec.Aliases) {
+		if alias == id || !strings.HasPrefix(alias, "CVE-") {
+			continue
+		}
+		cve, err := r.fetchRecord(ctx, alias)
+		if lookupErr, ok := errors.AsType[*httpfetch.LookupError](err); ok && lookupErr.NotFound() {
+			continue
+		}
+		if err != nil {
+			return SeverityUnknown, err
+		}
+		if sev := recordSeverity(cve); sev != SeverityUnknown {
+			return sev, nil
+		}
+	}
+	return SeverityUnknown, nil
+}
+
+func (r *Resolver) fetchRecord(ctx context.Context, id string) (*osvRecord, error) {
+	var rec osvRecord
+	if err := r.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(id), nil, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// recordSeverity classifies a record from its database-specific rating, a
+// distribution rating (Ubuntu), or its CVSS v3 vector, in that order.
+func recordSeverity(rec *osvRecord) Severity {
+	if sev, ok := ParseSeverity(rec.DatabaseSpecific.Severity); ok {
+		return sev
+	}
+	var cvss Severity
+	for _, s := range rec.Severity {
+		switch s.Type {
+		case "Ubuntu":
+			if sev, ok := ParseSeverity(s.Score); ok {
+				return sev
+			}
+		case "CVSS_V3":
+			if score, ok := cvss3BaseScore(s.Score); ok {
+				cvss = max(cvss, cvssSeverity(score))
+			}
+		}
+	}
+	return cvss
+}
+
+// cveID extracts "CVE-2024-1234" from IDs such as "DEBIAN-CVE-2024-1234".
+func cveID(id string) string {
+	if i := strings.Index(id, "CVE-"); i >= 0 {
+		return id[i:]
+	}
+	return ""
+}
+
+func (r *Resolver) do(ctx context.Context, method, path string, body, out any) error {
+	endpoint := strings.TrimSuffix(r.BaseURL, "/") + path
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+		// Queries are read-only, so the shared transport may retry them.
+		httpReq.Header["Idempotency-Key"] = nil
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return &httpfetch.LookupError{URL: endpoint, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &httpfetch.LookupError{URL: endpoint, Status: resp.StatusCode}
+	}
+	if err := json.UnmarshalRead(resp.Body, out); err != nil {
+		return fmt.Errorf("osv %s: decode response: %w", path, err)
+	}
+	return nil
+}
+
+// UnsupportedImageError reports an image whose packages cannot be matched
+// against OSV, such as distroless or scratch-based images.
+type UnsupportedImageError struct {
+	Ref    string
+	Reason string
+}
+
+func (e *UnsupportedImageError) Error() string {
+	return fmt.Sprintf("osv %s: %s", e.Ref, e.Reason)
+}
+
+// SkipReason classifies the error for async run reporting.
+func (e *UnsupportedImageError) SkipReason() async.SkipReason { return async.SkipNotFound }
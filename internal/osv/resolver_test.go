@@ -0,0 +1,178 @@
+package osv
+
+import (
+	"context"
+	"encoding/json/v2"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/registry"
+)
+
+type fakeFiles map[string]string
+
+func (f fakeFiles) ReadImageFiles(_ context.Context, ref, _ string, paths []string) (map[string][]byte, error) {
+	if ref == "missing:1" {
+		return nil, &registry.NotFoundError{Ref: ref}
+	}
+	out := make(map[string][]byte)
+	for _, p := range paths {
+		if content, ok := f[p]; ok {
+			out[p] = []byte(content)
+		}
+	}
+	return out, nil
+}
+
+var debianImage = fakeFiles{
+	usrOSReleasePath: "ID=debian\nVERSION_ID=\"12\"\n",
+	dpkgStatusPath: "Package: libssl3\nStatus: install ok installed\nSource: openssl\nVersion: 3.0.11-1\n\n" +
+		"Package: zlib1g\nStatus: install ok installed\nSource: zlib\nVersion: 1:1.2.13\n\n" +
+		"Package: bash\nStatus: install ok installed\nVersion: 5.2.15\n",
+}
+
+// newOSVServer serves querybatch results for openssl and zlib and advisory
+// records with severity from each source the resolver understands.
+func newOSVServer(t *testing.T, fetches *atomic.Int32) *httptest.Server {
+	t.Helper()
+	records := map[string]string{
+		"DEBIAN-CVE-2024-0001": `{"upstream": ["CVE-2024-0001"]}`,
+		"CVE-2024-0001":        `{"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}]}`,
+		"DSA-5000-1":           `{"database_specific": {"severity": "HIGH"}}`,
+		"DEBIAN-CVE-2024-0002": `{"aliases": ["CVE-2024-0002"]}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/querybatch" {
+			var body struct {
+				Queries []batchQuery `json:"queries"`
+			}
+			if err := json.UnmarshalRead(r.Body, &body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			results := make([]string, len(body.Queries))
+			for i, q := range body.Queries {
+				if q.Package.Ecosystem != "Debian:12" {
+					t.Errorf("ecosystem = %q", q.Package.Ecosystem)
+				}
+				switch q.Package.Name {
+				case "openssl":
+					results[i] = `{"vulns": [{"id": "DEBIAN-CVE-2024-0001"}, {"id": "DSA-5000-1"}]}`
+				case "zlib":
+					results[i] = `{"vulns": [{"id": "DEBIAN-CVE-2024-0002"}, {"id": "DSA-5000-1"}]}`
+				default:
+					results[i] = `{}`
+				}
+			}
+			_, _ = w.Write([]byte(`{"results": [` + strings.Join(results, ",") + `]}`))
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/v1/vulns/")
+		fetches.Add(1)
+		rec, ok := records[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(rec))
+	}))
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	srv := newOSVServer(t, &fetches)
+	defer srv.Close()
+
+	r := &Resolver{Files: debianImage, BaseURL: srv.URL, Client: srv.Client()}
+	got, err := r.Resolve(context.Background(), &Request{Ref: "debian:12", Platform: "linux/amd64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, ok := got.(*Report)
+	if !ok {
+		t.Fatalf("Resolve returned %T, want *Report", got)
+	}
+	if report.Ecosystem != "Debian:12" || report.Packages != 3 {
+		t.Errorf("report = %+v", report)
+	}
+	want := []Vulnerability{
+		{ID: "DEBIAN-CVE-2024-0001", CVE: "CVE-2024-0001", Package: "openssl", Severity: SeverityCritical},
+		{ID: "DSA-5000-1", Package: "openssl", Severity: SeverityHigh},
+		{ID: "DEBIAN-CVE-2024-0002", CVE: "CVE-2024-0002", Package: "zlib", Severity: SeverityUnknown},
+	}
+	if len(report.Vulnerabilities) != len(want) {
+		t.Fatalf("vulnerabilities = %+v, want %+v", report.Vulnerabilities, want)
+	}
+	for i, v := range report.Vulnerabilities {
+		if v != want[i] {
+			t.Errorf("vulnerability %d = %+v, want %+v", i, v, want[i])
+		}
+	}
+	if n := len(report.AtLeast(SeverityHigh)); n != 2 {
+		t.Errorf("AtLeast(high) = %d, want 2", n)
+	}
+
+	// Severities are cached: a second image with the same advisories does
+	// not fetch records again.
+	before := fetches.Load()
+	if _, err := r.Resolve(context.Background(), &Request{Ref: "debian:12-slim"}); err != nil {
+		t.Fatal(err)
+	}
+	if after := fetches.Load(); after != before {
+		t.Errorf("record fetches = %d after cached resolve, want %d", after, before)
+	}
+}
+
+func TestResolver_Resolve_Errors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		files fakeFiles
+		ref   string
+		want  async.SkipReason
+	}{
+		{name: "registry error", ref: "missing:1", want: async.SkipNotFound},
+		{name: "no os-release", files: fakeFiles{}, ref: "scratch:1", want: async.SkipNotFound},
+		{
+			name:  "distroless",
+			files: fakeFiles{etcOSReleasePath: "ID=debian\nVERSION_ID=\"12\"\n"},
+			ref:   "gcr.io/distroless/static:latest",
+			want:  async.SkipNotFound,
+		},
+		{
+			name:  "unsupported distribution",
+			files: fakeFiles{etcOSReleasePath: "ID=fedora\nVERSION_ID=40\n"},
+			ref:   "fedora:40",
+			want:  async.SkipNotFound,
+		},
+		{name: "osv unavailable", files: debianImage, ref: "debian:12", want: async.SkipNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := &Resolver{Files: tt.files, BaseURL: srv.URL, Client: srv.Client()}
+			_, err := r.Resolve(context.Background(), &Request{Ref: tt.ref})
+			skip, ok := errors.AsType[interface {
+				error
+				SkipReason() async.SkipReason
+			}](err)
+			if !ok || skip.SkipReason() != tt.want {
+				t.Errorf("error = %v, want skip reason %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package printer renders Dockerfile instructions back to source text.
+//
+// Fixes that replace whole instructions build an [Instruction] and print it
+// instead of concatenating strings, so flags, heredoc markers and bodies
+// always come out in a form BuildKit parses back to the same instruction.
+// [FromNode] converts a parsed instruction, so unchanged parts of an
+// instruction can be carried over into a rewrite without re-quoting them.
+//
+// The printer emits one logical line per instruction: continuation lines of
+// the source are joined, which is what the parser sees anyway. Layout is left
+// to the formatting rules (tally fmt).
+package printer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// Instruction is a Dockerfile instruction to print.
+type Instruction struct {
+	// Comments are printed on their own lines before the instruction,
+	// without the leading "# ".
+	Comments []string
+
+	// Keyword is the instruction keyword, e.g. "RUN". It is printed as given.
+	Keyword string
+
+	// Flags are printed after the keyword in order, e.g. "--chown=app".
+	// Empty entries are skipped, so optional flags can be listed inline.
+	Flags []string
+
+	// Args is the rest of the instruction line, including heredoc markers
+	// such as "<<EOF /etc/app.conf" or a JSON array.
+	Args string
+
+	// Heredocs are the bodies of the heredocs opened by markers in Args, in
+	// marker order.
+	Heredocs []Heredoc
+}
+
+// Heredoc is the body of one heredoc.
+type Heredoc struct {
+	// Name is the delimiter that closes the body.
+	Name string
+
+	// Content is the body. A missing final newline is added when printing,
+	// since the delimiter has to start its own line.
+	Content string
+}
+
+// NewHeredoc returns a heredoc for content with a delimiter that does not
+// occur in it.
+func NewHeredoc(content string) Heredoc {
+	return Heredoc{Name: ChooseDelimiter(content), Content: content}
+}
+
+// Marker returns the "<<NAME" marker that opens h in an instruction line.
+func (h Heredoc) Marker() string {
+	return "<<" + h.Name
+}
+
+// ChooseDelimiter selects a heredoc delimiter that doesn't appear in content.
+func ChooseDelimiter(content string) string {
+	delimiters := []string{"EOF", "CONTENT", "FILE", "DATA", "END"}
+	for _, d := range delimiters {
+		if !strings.Contains(content, d) {
+			return d
+		}
+	}
+	// Fallback with number suffix
+	for i := 1; i < 100; i++ {
+		d := fmt.Sprintf("EOF%d", i)
+		if !strings.Contains(content, d) {
+			return d
+		}
+	}
+	return "EOF"
+}
+
+// Flag returns the "--name=value" form of a flag.
+func Flag(name, value string) string {
+	return "--" + name + "=" + value
+}
+
+// String renders the instruction. The result has no trailing newline, so it
+// can replace the source range of an instruction as is.
+func (i Instruction) String() string {
+	var sb strings.Builder
+	for _, c := range i.Comments {
+		sb.WriteString("#")
+		if c != "" {
+			sb.WriteString(" ")
+			sb.WriteString(c)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(i.Keyword)
+	for _, f := range i.Flags {
+		if f == "" {
+			continue
+		}
+		sb.WriteString(" ")
+		sb.WriteString(f)
+	}
+	if i.Args != "" {
+		sb.WriteString(" ")
+		sb.WriteString(i.Args)
+	}
+
+	for _, h := range i.Heredocs {
+		sb.WriteString("\n")
+		if h.Content != "" {
+			sb.WriteString(strings.TrimSuffix(h.Content, "\n"))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(h.Name)
+	}
+	return sb.String()
+}
+
+// FromNode converts a parsed instruction. Flags and arguments are taken from
+// the source text rather than the parsed values, so quoting, variable
+// references and heredoc markers survive printing unchanged.
+func FromNode(node *parser.Node) Instruction {
+	rest := strings.TrimLeft(node.Original, " \t")
+	keyword := rest
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		keyword, rest = rest[:i], rest[i:]
+	} else {
+		rest = ""
+	}
+
+	inst := Instruction{
+		Comments: slices.Clone(node.PrevComment),
+		Keyword:  keyword,
+	}
+
+	// Split off as many flags as the parser found.
+	col := 0
+	for range node.Flags {
+		for col < len(rest) && (rest[col] == ' ' || rest[col] == '\t') {
+			col++
+		}
+		if !strings.HasPrefix(rest[col:], "--") {
+			break
+		}
+		end := shell.SkipDockerfileFlagValue(rest, col, false)
+		inst.Flags = append(inst.Flags, rest[col:end])
+		col = end
+	}
+	inst.Args = strings.TrimSpace(rest[col:])
+
+	for _, h := range node.Heredocs {
+		inst.Heredocs = append(inst.Heredocs, Heredoc{Name: h.Name, Content: h.Content})
+	}
+	return inst
+}
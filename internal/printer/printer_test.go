@@ -0,0 +1,166 @@
+package printer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+func TestInstructionString(t *testing.T) {
+	t.Parallel()
+
+	body := NewHeredoc("[app]\nport = 80\n")
+	tests := []struct {
+		name string
+		inst Instruction
+		want string
+	}{
+		{
+			name: "shell form",
+			inst: Instruction{Keyword: "RUN", Args: "apt-get update"},
+			want: "RUN apt-get update",
+		},
+		{
+			name: "empty flags are skipped",
+			inst: Instruction{Keyword: "RUN", Flags: []string{"", "--network=none"}, Args: "make"},
+			want: "RUN --network=none make",
+		},
+		{
+			name: "heredoc",
+			inst: Instruction{
+				Keyword:  "COPY",
+				Flags:    []string{Flag("chmod", "644")},
+				Args:     body.Marker() + " /etc/app.conf",
+				Heredocs: []Heredoc{body},
+			},
+			want: "COPY --chmod=644 <<EOF /etc/app.conf\n[app]\nport = 80\nEOF",
+		},
+		{
+			name: "empty heredoc",
+			inst: Instruction{Keyword: "COPY", Args: "<<EOF /empty", Heredocs: []Heredoc{{Name: "EOF"}}},
+			want: "COPY <<EOF /empty\nEOF",
+		},
+		{
+			name: "comments",
+			inst: Instruction{Comments: []string{"build the app"}, Keyword: "RUN", Args: "make"},
+			want: "# build the app\nRUN make",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.inst.String(); got != tt.want {
+				t.Errorf("String() =\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseDelimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no conflict",
+			content: "hello world",
+			want:    "EOF",
+		},
+		{
+			name:    "contains EOF",
+			content: "Some EOF text",
+			want:    "CONTENT",
+		},
+		{
+			name:    "contains EOF and CONTENT",
+			content: "EOF and CONTENT here",
+			want:    "FILE",
+		},
+		{
+			name:    "contains all standard delimiters",
+			content: "EOF CONTENT FILE DATA END",
+			want:    "EOF1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ChooseDelimiter(tt.content)
+			if got != tt.want {
+				t.Errorf("ChooseDelimiter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFromNodeRoundTrip prints every instruction of a Dockerfile and checks
+// that the parser reads the printed text back to the same instruction.
+func TestFromNodeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const dockerfile = `# syntax=docker/dockerfile:1
+FROM golang:1.24 AS build
+# cache modules
+RUN --mount=type=cache,target=/go/pkg/mod \
+    --mount=type=bind,source=go.sum,target=go.sum \
+    go mod download
+COPY --from=build --chown=app:app --chmod=755 /out/app "/usr/local/bin/my app"
+COPY <<EOF /etc/app.conf
+[app]
+port = ${PORT}
+EOF
+RUN <<-'SCRIPT' bash
+	set -e
+	echo "$HOME"
+SCRIPT
+ENV A=1 B="two words"
+HEALTHCHECK --interval=30s --timeout=3s CMD ["curl", "-f", "http://localhost/"]
+CMD ["/usr/local/bin/app", "--port=80"]
+`
+	want, err := parser.Parse(strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, node := range want.AST.Children {
+		printed := FromNode(node).String()
+		got, err := parser.Parse(strings.NewReader(printed + "\n"))
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", printed, err)
+		}
+		if len(got.AST.Children) != 1 {
+			t.Fatalf("Parse(%q) = %d instructions, want 1", printed, len(got.AST.Children))
+		}
+		assertSameNode(t, printed, node, got.AST.Children[0])
+	}
+}
+
+func assertSameNode(t *testing.T, printed string, want, got *parser.Node) {
+	t.Helper()
+	if got.Value != want.Value || !slices.Equal(got.Flags, want.Flags) {
+		t.Errorf("%q: got %s %v, want %s %v", printed, got.Value, got.Flags, want.Value, want.Flags)
+	}
+	if !slices.Equal(got.PrevComment, want.PrevComment) {
+		t.Errorf("%q: comments = %q, want %q", printed, got.PrevComment, want.PrevComment)
+	}
+	if !slices.Equal(got.Heredocs, want.Heredocs) {
+		t.Errorf("%q: heredocs = %+v, want %+v", printed, got.Heredocs, want.Heredocs)
+	}
+	var gotArgs, wantArgs []string
+	for n := got.Next; n != nil; n = n.Next {
+		gotArgs = append(gotArgs, n.Value)
+	}
+	for n := want.Next; n != nil; n = n.Next {
+		wantArgs = append(wantArgs, n.Value)
+	}
+	if !slices.Equal(gotArgs, wantArgs) {
+		t.Errorf("%q: args = %q, want %q", printed, gotArgs, wantArgs)
+	}
+}
@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"github.com/wharflab/tally/internal/changedlines"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// ChangedLinesFilter keeps only violations that touch lines added or modified
+// relative to a diff base (`--diff-base <ref>`).
+//
+// Violations in files without changes are dropped. File-level violations are
+// kept when the file has any change, since they cannot be attributed to a line.
+type ChangedLinesFilter struct {
+	files changedlines.Files
+}
+
+// NewChangedLinesFilter creates a changed-lines filter for the given per-file line sets.
+func NewChangedLinesFilter(files changedlines.Files) *ChangedLinesFilter {
+	return &ChangedLinesFilter{files: files}
+}
+
+// Name returns the processor's identifier.
+func (p *ChangedLinesFilter) Name() string {
+	return "changed-lines-filter"
+}
+
+// Process filters out violations located entirely on unchanged lines.
+func (p *ChangedLinesFilter) Process(violations []rules.Violation, _ *Context) []rules.Violation {
+	return filterViolations(violations, func(v rules.Violation) bool {
+		set := p.files.Lookup(v.Location.File)
+		if set == nil {
+			return false
+		}
+		if v.Location.IsFileLevel() {
+			return true
+		}
+		start, end := violationLineSpan(v.Location)
+		return set.Overlaps(start, end)
+	})
+}
+
+// violationLineSpan returns the inclusive 1-based line span covered by loc.
+// End positions are exclusive, so a range ending at column 0 does not cover
+// its final line.
+func violationLineSpan(loc rules.Location) (int, int) {
+	start := loc.Start.Line
+	if loc.IsPointLocation() || loc.End.Line <= start {
+		return start, start
+	}
+	end := loc.End.Line
+	if loc.End.Column == 0 {
+		end--
+	}
+	return start, max(start, end)
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/wharflab/tally/internal/changedlines"
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestChangedLinesFilter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	changed := filepath.ToSlash(filepath.Join(dir, "Dockerfile"))
+	untouched := filepath.ToSlash(filepath.Join(dir, "other", "Dockerfile"))
+
+	set := &changedlines.LineSet{}
+	set.Add(3)
+	set.Add(4)
+	files := changedlines.Files{filepath.Join(dir, "Dockerfile"): set}
+
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation(changed, 3), "r1", "on changed line", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation(changed, 1), "r2", "on unchanged line", rules.SeverityWarning),
+		rules.NewViolation(
+			rules.NewRangeLocation(changed, 1, 0, 3, 5), "r3", "range ending on changed line", rules.SeverityWarning),
+		rules.NewViolation(
+			rules.NewRangeLocation(changed, 1, 0, 3, 0), "r4", "range ending before changed line", rules.SeverityWarning),
+		rules.NewViolation(rules.NewFileLocation(changed), "r5", "file-level in changed file", rules.SeverityWarning),
+		rules.NewViolation(rules.NewFileLocation(untouched), "r6", "file-level in unchanged file", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation(untouched, 3), "r7", "unchanged file", rules.SeverityWarning),
+	}
+
+	result := NewChangedLinesFilter(files).Process(violations, NewContext(nil, config.Default(), nil))
+
+	var got []string
+	for _, v := range result {
+		got = append(got, v.RuleCode)
+	}
+	want := []string{"r1", "r3", "r5"}
+	if len(got) != len(want) {
+		t.Fatalf("got rules %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got rules %v, want %v", got, want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package processor
 
 import (
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/wharflab/tally/internal/directive"
 	"github.com/wharflab/tally/internal/ruledeprecation"
@@ -11,6 +13,9 @@ import (
 // InlineDirectiveFilter applies inline ignore directives.
 // Supports # tally ignore=..., # hadolint ignore=..., and # check=skip=...
 //
+// Directives past their expires= date are not applied; tally/expired-suppression
+// reports them instead.
+//
 // This processor collects additional violations for:
 //   - Parse errors in directives
 //   - Unused directives (if WarnUnused is enabled)
@@ -27,6 +32,9 @@ type InlineDirectiveFilter struct {
 
 	// registry is used to validate rule codes
 	registry *rules.Registry
+
+	// now returns the current time for expires= checks.
+	now func() time.Time
 }
 
 // NewInlineDirectiveFilter creates a new inline directive filter processor.
@@ -42,6 +50,7 @@ func NewInlineDirectiveFilterWithRegistry(registry *rules.Registry) *InlineDirec
 	}
 	return &InlineDirectiveFilter{
 		registry: registry,
+		now:      time.Now,
 	}
 }
 
@@ -166,10 +175,20 @@ func (p *InlineDirectiveFilter) processFile(
 		).WithDetail("Directive: "+parseErr.RawText))
 	}
 
-	// Filter violations based on directives
-	if len(directiveResult.Directives) > 0 {
-		filterResult := directive.Filter(violations, directiveResult.Directives)
+	// Filter violations based on directives that have not expired
+	now := p.now()
+	active := make([]directive.Directive, 0, len(directiveResult.Directives))
+	for _, d := range directiveResult.Directives {
+		if !d.Expired(now) {
+			active = append(active, d)
+		}
+	}
+	if len(active) > 0 {
+		filterResult := directive.Filter(violations, active)
 		violations = filterResult.Violations
+		for i, v := range filterResult.Suppressed {
+			ctx.suppress(v, rules.SuppressionInline, strings.TrimSpace(filterResult.SuppressedBy[i].RawText))
+		}
 
 		// Report unused directives if configured
 		if cfg.InlineDirectives.WarnUnused {
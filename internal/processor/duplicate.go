@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"path/filepath"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// DuplicateFilter collapses violations of different rules that report the
+// same problem on the same line, e.g. hadolint/DL3020 ("use COPY instead of
+// ADD") next to tally/mount-secret-instead-of-copy on an ADD of a credential
+// file. A rule replaces the rules listed in its RuleMetadata.Supersedes, or
+// in its duplicate-precedence entry when one is configured.
+type DuplicateFilter struct {
+	// registry is used to look up rule metadata
+	registry *rules.Registry
+}
+
+// NewDuplicateFilter creates a new duplicate filter processor.
+// Uses the default registry. For testing, use NewDuplicateFilterWithRegistry.
+func NewDuplicateFilter() *DuplicateFilter {
+	return NewDuplicateFilterWithRegistry(rules.DefaultRegistry())
+}
+
+// NewDuplicateFilterWithRegistry creates a duplicate filter with a custom registry.
+func NewDuplicateFilterWithRegistry(registry *rules.Registry) *DuplicateFilter {
+	if registry == nil {
+		registry = rules.DefaultRegistry()
+	}
+	return &DuplicateFilter{registry: registry}
+}
+
+// Name returns the processor's identifier.
+func (p *DuplicateFilter) Name() string {
+	return "duplicate-filter"
+}
+
+// Process removes violations superseded by a violation of another rule at
+// the same file+line. When two rules supersede each other, both are kept.
+func (p *DuplicateFilter) Process(violations []rules.Violation, ctx *Context) []rules.Violation {
+	type locKey struct {
+		invocationKey string
+		file          string
+		line          int
+	}
+	keyOf := func(v rules.Violation) (locKey, bool) {
+		if v.Location.File == "" || v.Location.Start.Line <= 0 {
+			return locKey{}, false
+		}
+		return locKey{
+			invocationKey: v.InvocationKey,
+			file:          filepath.ToSlash(v.Location.File),
+			line:          v.Location.Start.Line,
+		}, true
+	}
+
+	// Collect the rules reporting at each location.
+	ruleCodes := make(map[locKey][]string)
+	for _, v := range violations {
+		if key, ok := keyOf(v); ok {
+			ruleCodes[key] = append(ruleCodes[key], v.RuleCode)
+		}
+	}
+
+	return filterViolations(violations, func(v rules.Violation) bool {
+		key, ok := keyOf(v)
+		if !ok {
+			return true
+		}
+		var precedence map[string][]string
+		if cfg := ctx.ConfigForFile(v.Location.File); cfg != nil {
+			precedence = cfg.DuplicatePrecedence
+		}
+		for _, code := range ruleCodes[key] {
+			if code == v.RuleCode ||
+				!config.Supersedes(precedence, p.builtinSupersedes(code), code, v.RuleCode) ||
+				config.Supersedes(precedence, p.builtinSupersedes(v.RuleCode), v.RuleCode, code) {
+				continue
+			}
+			ctx.suppress(v, rules.SuppressionDuplicate, "duplicate of "+code)
+			return false
+		}
+		return true
+	})
+}
+
+// builtinSupersedes returns the Supersedes list of a registered rule.
+func (p *DuplicateFilter) builtinSupersedes(ruleCode string) []string {
+	if rule := p.registry.Get(ruleCode); rule != nil {
+		return rule.Metadata().Supersedes
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wharflab/tally/internal/config"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+type supersedingRule struct {
+	code       string
+	supersedes []string
+}
+
+func (m *supersedingRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{Code: m.code, DefaultSeverity: rules.SeverityWarning, Supersedes: m.supersedes}
+}
+
+func (m *supersedingRule) Check(_ rules.LintInput) []rules.Violation {
+	return nil
+}
+
+func duplicateTestRegistry() *rules.Registry {
+	registry := rules.NewRegistry()
+	registry.Register(&supersedingRule{code: "tally/mount-secret-instead-of-copy", supersedes: []string{"hadolint/DL3020"}})
+	registry.Register(&supersedingRule{code: "hadolint/DL3020"})
+	registry.Register(&supersedingRule{code: "tally/a", supersedes: []string{"tally/b"}})
+	registry.Register(&supersedingRule{code: "tally/b", supersedes: []string{"tally/*"}})
+	return registry
+}
+
+func ruleCodesAt(violations []rules.Violation) []string {
+	codes := make([]string, 0, len(violations))
+	for _, v := range violations {
+		codes = append(codes, v.RuleCode)
+	}
+	return codes
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	t.Parallel()
+
+	at := func(line int, code string) rules.Violation {
+		return rules.NewViolation(rules.NewLineLocation("Dockerfile", line), code, "msg", rules.SeverityWarning)
+	}
+	violations := []rules.Violation{
+		at(2, "hadolint/DL3020"),
+		at(2, "tally/mount-secret-instead-of-copy"),
+		at(3, "hadolint/DL3020"), // no superseding violation on this line
+		at(4, "tally/a"),         // a and b supersede each other: both kept
+		at(4, "tally/b"),
+	}
+
+	tests := []struct {
+		name       string
+		precedence map[string][]string
+		want       []string
+	}{
+		{
+			name: "built-in supersedes",
+			want: []string{
+				"tally/mount-secret-instead-of-copy", "hadolint/DL3020", "tally/a", "tally/b",
+			},
+		},
+		{
+			name:       "config replaces built-in list",
+			precedence: map[string][]string{"tally/mount-secret-instead-of-copy": {}},
+			want: []string{
+				"hadolint/DL3020", "tally/mount-secret-instead-of-copy", "hadolint/DL3020", "tally/a", "tally/b",
+			},
+		},
+		{
+			name: "config reverses precedence",
+			precedence: map[string][]string{
+				"tally/mount-secret-instead-of-copy": {},
+				"hadolint/DL3020":                    {"tally/*"},
+			},
+			want: []string{"hadolint/DL3020", "hadolint/DL3020", "tally/a", "tally/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := config.Default()
+			cfg.DuplicatePrecedence = tt.precedence
+			ctx := NewContext(nil, cfg, nil)
+			ctx.CollectSuppressed = true
+
+			got := ruleCodesAt(NewDuplicateFilterWithRegistry(duplicateTestRegistry()).Process(violations, ctx))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if len(ctx.Suppressed)+len(got) != len(violations) {
+				t.Fatalf("suppressed %d violations, want %d", len(ctx.Suppressed), len(violations)-len(got))
+			}
+			for _, v := range ctx.Suppressed {
+				if v.Suppression == nil || v.Suppression.Source != rules.SuppressionDuplicate {
+					t.Errorf("suppression of %s = %+v, want source %q", v.RuleCode, v.Suppression, rules.SuppressionDuplicate)
+				}
+			}
+		})
+	}
+}
+
+func TestDuplicateFilter_OtherInvocation(t *testing.T) {
+	t.Parallel()
+
+	secret := rules.NewViolation(rules.NewLineLocation("Dockerfile", 2),
+		"tally/mount-secret-instead-of-copy", "msg", rules.SeverityWarning)
+	secret.InvocationKey = "target-a"
+	dl3020 := rules.NewViolation(rules.NewLineLocation("Dockerfile", 2), "hadolint/DL3020", "msg", rules.SeverityError)
+	dl3020.InvocationKey = "target-b"
+
+	got := NewDuplicateFilterWithRegistry(duplicateTestRegistry()).Process(
+		[]rules.Violation{secret, dl3020}, NewContext(nil, config.Default(), nil))
+	if len(got) != 2 {
+		t.Errorf("got %v, want violations of different invocations kept", ruleCodesAt(got))
+	}
+}
@@ -1,7 +1,11 @@
 package processor
 
 import (
+	"fmt"
+
+	"github.com/wharflab/tally/internal/config"
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
 )
 
 // EnableFilter removes violations for disabled rules.
@@ -37,19 +41,29 @@ func (p *EnableFilter) Name() string {
 // Rules are disabled if:
 //  1. Severity is "off" (after SeverityOverride has run)
 //  2. Excluded by Include/Exclude patterns
+//
+// Violations disabled by an explicit setting are recorded as suppressed;
+// those of rules that are off by default are not.
 func (p *EnableFilter) Process(violations []rules.Violation, ctx *Context) []rules.Violation {
 	return filterViolations(violations, func(v rules.Violation) bool {
+		cfg := ctx.ConfigForFile(v.Location.File)
+
 		// Filter out violations with severity="off"
 		// (SeverityOverride runs before this processor)
 		if v.Severity == rules.SeverityOff {
+			if reason := severityOffReason(cfg, v); reason != "" {
+				ctx.suppress(v, rules.SuppressionConfig, reason)
+			}
 			return false
 		}
 
 		// Check Include/Exclude patterns from config
-		cfg := ctx.ConfigForFile(v.Location.File)
 		if cfg != nil {
 			enabled := cfg.Rules.IsEnabled(v.RuleCode)
 			if enabled != nil {
+				if !*enabled {
+					ctx.suppress(v, rules.SuppressionConfig, disabledReason(cfg, v.RuleCode))
+				}
 				return *enabled
 			}
 		}
@@ -58,3 +72,29 @@ func (p *EnableFilter) Process(violations []rules.Violation, ctx *Context) []rul
 		return true
 	})
 }
+
+// severityOffReason names the setting that turned a violation "off", or
+// returns "" when the rule is simply off by default.
+func severityOffReason(cfg *config.Config, v rules.Violation) string {
+	if cfg == nil {
+		return ""
+	}
+	if v.StageRole != semantic.StageRoleUnknown {
+		role := v.StageRole.String()
+		if cfg.SeverityByStageRole.Severity(role, v.RuleCode) == "off" {
+			return fmt.Sprintf("severity-by-stage-role.%s = \"off\"", role)
+		}
+	}
+	if cfg.Rules.GetSeverity(v.RuleCode) == "off" {
+		return `severity = "off"`
+	}
+	return ""
+}
+
+// disabledReason names the setting that disabled a rule for a file.
+func disabledReason(cfg *config.Config, ruleCode string) string {
+	if !cfg.Rules.InScope(ruleCode) {
+		return "outside the rule's paths / exclude-paths"
+	}
+	return "rules.exclude (--ignore)"
+}
@@ -1,6 +1,8 @@
 package processor
 
 import (
+	"fmt"
+
 	"github.com/bmatcuk/doublestar/v4"
 
 	"github.com/wharflab/tally/internal/rules"
@@ -42,6 +44,7 @@ func (p *PathExclusionFilter) Process(violations []rules.Violation, ctx *Context
 				continue
 			}
 			if matched {
+				ctx.suppress(v, rules.SuppressionConfig, fmt.Sprintf("exclude.paths %q", pattern))
 				return false // excluded
 			}
 		}
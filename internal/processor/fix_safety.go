@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// FixSafetyOverride applies per-rule fix safety overrides from configuration
+// (fix-safety = "safe"), so the fixer's safety threshold admits a trusted fix
+// with --fix, or holds a risky one back for --fix-unsafe. The override
+// applies to every fix alternative of the violation.
+type FixSafetyOverride struct{}
+
+// NewFixSafetyOverride creates a new fix safety override processor.
+func NewFixSafetyOverride() *FixSafetyOverride {
+	return &FixSafetyOverride{}
+}
+
+// Name returns the processor's identifier.
+func (p *FixSafetyOverride) Name() string {
+	return "fix-safety-override"
+}
+
+// Process applies fix safety overrides from config.
+func (p *FixSafetyOverride) Process(violations []rules.Violation, ctx *Context) []rules.Violation {
+	return transformViolations(violations, func(v rules.Violation) rules.Violation {
+		fixes := v.AllFixes()
+		if len(fixes) == 0 {
+			return v
+		}
+		cfg := ctx.ConfigForFile(v.Location.File)
+		if cfg == nil {
+			return v
+		}
+		override := cfg.Rules.GetFixSafety(v.RuleCode)
+		if override == "" {
+			return v
+		}
+		safety, err := rules.ParseFixSafety(override)
+		if err != nil {
+			// Invalid fix safety in config - keep original
+			return v
+		}
+
+		// Fixes are shared with the input slice; override copies.
+		overridden := make([]*rules.SuggestedFix, len(fixes))
+		for i, f := range fixes {
+			c := *f
+			c.Safety = safety
+			overridden[i] = &c
+		}
+		if len(v.SuggestedFixes) > 0 {
+			return v.WithSuggestedFixes(overridden)
+		}
+		v.SuggestedFix = overridden[0]
+		return v
+	})
+}
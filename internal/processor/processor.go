@@ -8,11 +8,13 @@
 //  1. PathNormalization - Cross-platform path consistency
 //  2. EnableFilter - Remove violations for disabled rules
 //  3. SeverityOverride - Apply config severity overrides
-//  4. PathExclusionFilter - Remove per-rule path exclusions
-//  5. InlineDirectiveFilter - Apply # tally ignore=... etc.
-//  6. Deduplication - Remove duplicate violations
-//  7. Sorting - Stable output ordering
-//  8. SnippetAttachment - Populate SourceCode field
+//  4. FixSafetyOverride - Apply config fix safety overrides
+//  5. PathExclusionFilter - Remove per-rule path exclusions
+//  6. InlineDirectiveFilter - Apply # tally ignore=... etc.
+//  7. DuplicateFilter - Remove violations another rule supersedes
+//  8. Deduplication - Remove duplicate violations
+//  9. Sorting - Stable output ordering
+//  10. SnippetAttachment - Populate SourceCode field
 package processor
 
 import (
@@ -60,6 +62,14 @@ type Context struct {
 
 	// RuleDeprecations collects deprecated rule-code usage found while processing.
 	RuleDeprecations *ruledeprecation.Collector
+
+	// CollectSuppressed makes the filtering processors record the violations
+	// they suppress in Suppressed (--show-suppressed).
+	CollectSuppressed bool
+
+	// Suppressed collects the violations dropped by rule configuration or
+	// inline directives, each marked with its rules.Suppression.
+	Suppressed []rules.Violation
 }
 
 // NewContext creates a new processor context.
@@ -146,6 +156,14 @@ func (ctx *Context) GetSourceMap(file string) *sourcemap.SourceMap {
 	return sm
 }
 
+// suppress records a violation dropped by a filtering processor when
+// CollectSuppressed is set.
+func (ctx *Context) suppress(v rules.Violation, source rules.SuppressionSource, justification string) {
+	if ctx.CollectSuppressed {
+		ctx.Suppressed = append(ctx.Suppressed, v.WithSuppression(source, justification))
+	}
+}
+
 // Chain runs processors in sequence.
 type Chain struct {
 	processors []Processor
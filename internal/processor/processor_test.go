@@ -2,9 +2,11 @@ package processor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/wharflab/tally/internal/config"
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
 )
 
 func TestChain(t *testing.T) {
@@ -246,6 +248,82 @@ FROM alpine:3.21 AS scratch
 	}
 }
 
+func TestInlineDirectiveFilter_ExpiredDirective(t *testing.T) {
+	t.Parallel()
+	const file = "Dockerfile"
+	source := []byte(`# tally ignore=DL3006;expires=2025-06-30;owner=@platform
+FROM ubuntu
+# tally ignore=DL3006;expires=2025-07-31
+FROM debian
+`)
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation(file, 2), "hadolint/DL3006", "msg", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation(file, 4), "hadolint/DL3006", "msg", rules.SeverityWarning),
+	}
+
+	cfg := config.Default()
+	cfg.InlineDirectives.WarnUnused = true
+	p := NewInlineDirectiveFilter()
+	p.now = func() time.Time { return time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC) }
+	ctx := NewContext(nil, cfg, map[string][]byte{file: source})
+
+	result := p.Process(violations, ctx)
+	if len(result) != 1 || result[0].Line() != 2 {
+		t.Fatalf("expected only the line 2 violation to remain, got %v", result)
+	}
+	// The expired directive is reported by tally/expired-suppression, not as unused.
+	if additional := p.AdditionalViolations(); len(additional) != 0 {
+		t.Fatalf("got %d additional violations, want 0: %v", len(additional), additional)
+	}
+}
+
+func TestCollectSuppressed(t *testing.T) {
+	t.Parallel()
+	const file = "Dockerfile"
+	source := []byte(`FROM ubuntu
+# tally ignore=DL3006;reason=pinned by digest upstream
+FROM debian
+MAINTAINER me
+`)
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation(file, 1), "tally/max-lines", "msg", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation(file, 3), "hadolint/DL3006", "msg", rules.SeverityWarning),
+		rules.NewViolation(rules.NewLineLocation(file, 4), "buildkit/MaintainerDeprecated", "msg", rules.SeverityOff),
+		rules.NewViolation(rules.NewLineLocation(file, 4), "tally/prefer-copy-heredoc", "msg", rules.SeverityOff),
+	}
+
+	cfg := config.Default()
+	cfg.Rules.Exclude = append(cfg.Rules.Exclude, "tally/max-lines")
+	cfg.Rules.Set("buildkit/MaintainerDeprecated", config.RuleConfig{Severity: "off"})
+	chain := NewChain(NewEnableFilter(), NewInlineDirectiveFilter())
+
+	ctx := NewContext(nil, cfg, map[string][]byte{file: source})
+	if result := chain.Process(violations, ctx); len(result) != 0 || len(ctx.Suppressed) != 0 {
+		t.Fatalf("without CollectSuppressed: got %d violations, %d suppressed", len(result), len(ctx.Suppressed))
+	}
+
+	ctx = NewContext(nil, cfg, map[string][]byte{file: source})
+	ctx.CollectSuppressed = true
+	chain.Process(violations, ctx)
+	want := map[string]rules.Suppression{
+		"tally/max-lines":               {Source: rules.SuppressionConfig, Justification: "rules.exclude (--ignore)"},
+		"buildkit/MaintainerDeprecated": {Source: rules.SuppressionConfig, Justification: `severity = "off"`},
+		"hadolint/DL3006": {
+			Source:        rules.SuppressionInline,
+			Justification: "# tally ignore=DL3006;reason=pinned by digest upstream",
+		},
+	}
+	// A rule that is off by default was not suppressed by anything.
+	if len(ctx.Suppressed) != len(want) {
+		t.Fatalf("got %d suppressed, want %d: %+v", len(ctx.Suppressed), len(want), ctx.Suppressed)
+	}
+	for _, v := range ctx.Suppressed {
+		if v.Suppression == nil || *v.Suppression != want[v.RuleCode] {
+			t.Errorf("%s: suppression = %+v, want %+v", v.RuleCode, v.Suppression, want[v.RuleCode])
+		}
+	}
+}
+
 func TestSnippetAttachment(t *testing.T) {
 	t.Parallel()
 	source := []byte("line 1\nline 2\nline 3\n")
@@ -463,3 +541,77 @@ func (m *mockRuleWithMetadata) Metadata() rules.RuleMetadata {
 func (m *mockRuleWithMetadata) Check(_ rules.LintInput) []rules.Violation {
 	return nil
 }
+
+func TestStageRoleSeverity(t *testing.T) {
+	t.Parallel()
+	inStage := func(line int, role semantic.StageRole, sev rules.Severity) rules.Violation {
+		v := rules.NewViolation(rules.NewLineLocation("Dockerfile", line), "tally/secrets-in-code", "msg", sev)
+		v.StageRole = role
+		return v
+	}
+	violations := []rules.Violation{
+		inStage(2, semantic.StageRoleBuilder, rules.SeverityError),
+		inStage(5, semantic.StageRoleFinal, rules.SeverityWarning),
+		inStage(1, semantic.StageRoleUnknown, rules.SeverityWarning),
+		inStage(6, semantic.StageRoleFinal, rules.SeverityOff),
+	}
+
+	cfg := config.Default()
+	cfg.SeverityByStageRole = config.SeverityByStageRoleConfig{
+		Final:   map[string]string{"tally/*": "error"},
+		Builder: map[string]string{"tally/secrets-in-code": "warning", "*": "off"},
+	}
+
+	result := NewStageRoleSeverity().Process(violations, NewContext(nil, cfg, nil))
+	want := []rules.Severity{rules.SeverityWarning, rules.SeverityError, rules.SeverityWarning, rules.SeverityOff}
+	for i, v := range result {
+		if v.Severity != want[i] {
+			t.Errorf("violation %d: severity = %s, want %s", i, v.Severity, want[i])
+		}
+	}
+}
+
+func TestFixSafetyOverride(t *testing.T) {
+	t.Parallel()
+	withFix := func(rule string, line int) rules.Violation {
+		v := rules.NewViolation(rules.NewLineLocation("Dockerfile", line), rule, "msg", rules.SeverityWarning)
+		return v.WithSuggestedFix(&rules.SuggestedFix{Description: "fix", Safety: rules.FixSuggestion})
+	}
+	alternatives := rules.NewViolation(
+		rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3003", "msg", rules.SeverityWarning,
+	).WithSuggestedFixes([]*rules.SuggestedFix{
+		{Description: "a", Safety: rules.FixUnsafe},
+		{Description: "b", Safety: rules.FixSuggestion, IsPreferred: true},
+	})
+	violations := []rules.Violation{
+		withFix("hadolint/DL3003", 1),
+		withFix("tally/max-lines", 2),
+		alternatives,
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 4), "hadolint/DL3003", "msg", rules.SeverityWarning),
+	}
+
+	cfg := config.Default()
+	cfg.Rules.Set("hadolint/DL3003", config.RuleConfig{FixSafety: "safe"})
+
+	result := NewFixSafetyOverride().Process(violations, NewContext(nil, cfg, nil))
+	if got := result[0].PreferredFix().Safety; got != rules.FixSafe {
+		t.Errorf("DL3003 fix safety = %s, want safe", got)
+	}
+	if got := result[1].PreferredFix().Safety; got != rules.FixSuggestion {
+		t.Errorf("max-lines fix safety = %s, want suggestion", got)
+	}
+	for _, f := range result[2].AllFixes() {
+		if f.Safety != rules.FixSafe {
+			t.Errorf("alternative %q safety = %s, want safe", f.Description, f.Safety)
+		}
+	}
+	if result[2].SuggestedFix != result[2].PreferredFix() {
+		t.Error("SuggestedFix no longer mirrors the preferred alternative")
+	}
+	if result[3].SuggestedFix != nil {
+		t.Errorf("violation without a fix gained one: %+v", result[3].SuggestedFix)
+	}
+	if violations[0].SuggestedFix.Safety != rules.FixSuggestion {
+		t.Error("input violation fix was modified")
+	}
+}
@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// StageRoleSeverity applies the severity-by-stage-role configuration.
+// It raises or lowers the severity of a violation depending on whether its
+// stage is exported or a builder stage (see semantic.StageRole), e.g. to
+// report secrets as errors in the final stage and as warnings in builders.
+//
+// It runs after SeverityOverride, so the stage role has the last word, and
+// before EnableFilter, so "off" drops the violation. Violations of disabled
+// rules and those outside any stage are left alone.
+type StageRoleSeverity struct{}
+
+// NewStageRoleSeverity creates a new stage role severity processor.
+func NewStageRoleSeverity() *StageRoleSeverity {
+	return &StageRoleSeverity{}
+}
+
+// Name returns the processor's identifier.
+func (p *StageRoleSeverity) Name() string {
+	return "stage-role-severity"
+}
+
+// Process applies the severity configured for each violation's stage role.
+func (p *StageRoleSeverity) Process(violations []rules.Violation, ctx *Context) []rules.Violation {
+	return transformViolations(violations, func(v rules.Violation) rules.Violation {
+		if v.StageRole == semantic.StageRoleUnknown || v.Severity == rules.SeverityOff {
+			return v
+		}
+		cfg := ctx.ConfigForFile(v.Location.File)
+		if cfg == nil {
+			return v
+		}
+		override := cfg.SeverityByStageRole.Severity(v.StageRole.String(), v.RuleCode)
+		if override == "" {
+			return v
+		}
+		sev, err := rules.ParseSeverity(override)
+		if err != nil {
+			return v
+		}
+		v.Severity = sev
+		return v
+	})
+}
@@ -0,0 +1,120 @@
+//go:build containers_image_openpgp && containers_image_storage_stub && containers_image_docker_daemon_stub
+
+package registry
+
+import (
+	"encoding/json/v2"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"go.podman.io/image/v5/docker/reference"
+	"go.podman.io/image/v5/pkg/docker/config"
+	"go.podman.io/image/v5/types"
+)
+
+// dockerHubServerURL is the key Docker uses for Docker Hub credentials.
+const dockerHubServerURL = "https://index.docker.io/v1/"
+
+// authCache fills in credentials that containers/image does not look up on
+// its own: the REGISTRY_AUTH_FILE override used by podman/skopeo and Docker's
+// default credential store (the "credsStore" key of config.json).
+//
+// containers/image already reads auth.json, ~/.docker/config.json (honoring
+// DOCKER_CONFIG) and per-registry "credHelpers", so the credential store is
+// only consulted when none of those yield credentials. Lookups are cached per
+// registry so helper binaries run at most once per invocation.
+type authCache struct {
+	// dockerConfigPath is the path to Docker's config.json.
+	dockerConfigPath string
+	// newProgram creates the credential helper program; tests replace it.
+	newProgram func(name string) client.ProgramFunc
+
+	mu    sync.Mutex
+	creds map[string]*types.DockerAuthConfig
+}
+
+func newAuthCache() *authCache {
+	return &authCache{
+		dockerConfigPath: dockerConfigPath(),
+		newProgram:       client.NewShellProgramFunc,
+		creds:            make(map[string]*types.DockerAuthConfig),
+	}
+}
+
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// apply sets up sysCtx to authenticate against the registry of named.
+func (c *authCache) apply(sysCtx *types.SystemContext, named reference.Named) {
+	if sysCtx.AuthFilePath == "" && sysCtx.DockerCompatAuthFilePath == "" {
+		sysCtx.AuthFilePath = os.Getenv("REGISTRY_AUTH_FILE")
+	}
+	if sysCtx.DockerAuthConfig != nil {
+		return
+	}
+	if creds := c.lookup(sysCtx, named); creds != nil {
+		sysCtx.DockerAuthConfig = creds
+	}
+}
+
+// lookup returns credentials from Docker's credential store when the regular
+// containers/image lookup finds none, or nil to leave authentication to
+// containers/image.
+func (c *authCache) lookup(sysCtx *types.SystemContext, named reference.Named) *types.DockerAuthConfig {
+	host := reference.Domain(named)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if creds, ok := c.creds[host]; ok {
+		return creds
+	}
+
+	var creds *types.DockerAuthConfig
+	if found, err := config.GetCredentialsForRef(sysCtx, named); err != nil || found == (types.DockerAuthConfig{}) {
+		creds = c.credsStore(host)
+	}
+	c.creds[host] = creds
+	return creds
+}
+
+// credsStore queries the credential store named by config.json's "credsStore".
+func (c *authCache) credsStore(host string) *types.DockerAuthConfig {
+	if c.dockerConfigPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.dockerConfigPath)
+	if err != nil {
+		return nil
+	}
+	var dockerCfg struct {
+		CredsStore string `json:"credsStore"`
+	}
+	if err := json.Unmarshal(data, &dockerCfg); err != nil || dockerCfg.CredsStore == "" {
+		return nil
+	}
+
+	serverURL := host
+	if host == "docker.io" {
+		serverURL = dockerHubServerURL
+	}
+	got, err := client.Get(c.newProgram("docker-credential-"+dockerCfg.CredsStore), serverURL)
+	if err != nil {
+		// Missing credentials are expected for public registries; other helper
+		// failures surface later as auth errors from the registry itself.
+		return nil
+	}
+	if got.Username == "<token>" {
+		return &types.DockerAuthConfig{IdentityToken: got.Secret}
+	}
+	return &types.DockerAuthConfig{Username: got.Username, Password: got.Secret}
+}
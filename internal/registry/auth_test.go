@@ -0,0 +1,150 @@
+//go:build containers_image_openpgp && containers_image_storage_stub && containers_image_docker_daemon_stub
+
+package registry
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"go.podman.io/image/v5/docker/reference"
+	"go.podman.io/image/v5/types"
+)
+
+// fakeHelper implements client.Program for a docker-credential-* helper that
+// knows a fixed set of server URLs.
+type fakeHelper struct {
+	name    string
+	secrets map[string]string
+	calls   *[]string
+	input   string
+}
+
+func (h *fakeHelper) Input(in io.Reader) {
+	data, _ := io.ReadAll(in)
+	h.input = strings.TrimSpace(string(data))
+}
+
+func (h *fakeHelper) Output() ([]byte, error) {
+	*h.calls = append(*h.calls, h.name+" "+h.input)
+	secret, ok := h.secrets[h.input]
+	if !ok {
+		return []byte("credentials not found in native keychain"), errors.New("exit status 1")
+	}
+	user := "robot"
+	if strings.HasPrefix(secret, "token:") {
+		user, secret = "<token>", strings.TrimPrefix(secret, "token:")
+	}
+	return []byte(`{"ServerURL":"` + h.input + `","Username":"` + user + `","Secret":"` + secret + `"}`), nil
+}
+
+func newTestAuthCache(t *testing.T, dockerConfig string, secrets map[string]string) (*authCache, *[]string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(dockerConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	calls := &[]string{}
+	c := newAuthCache()
+	c.dockerConfigPath = path
+	c.newProgram = func(name string) client.ProgramFunc {
+		return func(...string) client.Program {
+			return &fakeHelper{name: name, secrets: secrets, calls: calls}
+		}
+	}
+	return c, calls
+}
+
+// isolatedSystemContext points containers/image at an empty auth file so the
+// test does not depend on the host's credentials.
+func isolatedSystemContext(t *testing.T) *types.SystemContext {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "auth.json")
+	if err := os.WriteFile(path, []byte(`{"auths":{}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return &types.SystemContext{AuthFilePath: path}
+}
+
+func TestAuthCache_CredsStore(t *testing.T) {
+	t.Parallel()
+
+	c, calls := newTestAuthCache(t, `{"credsStore":"desktop"}`, map[string]string{
+		"registry.example.com": "s3cret",
+		dockerHubServerURL:     "token:hub-token",
+	})
+
+	named, err := reference.ParseNormalizedNamed("registry.example.com/team/app:1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sysCtx := isolatedSystemContext(t)
+	c.apply(sysCtx, named)
+	if sysCtx.DockerAuthConfig == nil || sysCtx.DockerAuthConfig.Username != "robot" || sysCtx.DockerAuthConfig.Password != "s3cret" {
+		t.Fatalf("DockerAuthConfig = %+v, want robot/s3cret", sysCtx.DockerAuthConfig)
+	}
+
+	// A second image on the same registry reuses the cached lookup.
+	other, _ := reference.ParseNormalizedNamed("registry.example.com/team/other:2.0")
+	sysCtx = isolatedSystemContext(t)
+	c.apply(sysCtx, other)
+	if sysCtx.DockerAuthConfig == nil || len(*calls) != 1 {
+		t.Errorf("expected cached credentials, helper calls = %v", *calls)
+	}
+
+	hub, _ := reference.ParseNormalizedNamed("alpine:3.20")
+	sysCtx = isolatedSystemContext(t)
+	c.apply(sysCtx, hub)
+	if sysCtx.DockerAuthConfig == nil || sysCtx.DockerAuthConfig.IdentityToken != "hub-token" {
+		t.Errorf("Docker Hub DockerAuthConfig = %+v, want identity token", sysCtx.DockerAuthConfig)
+	}
+	if got := (*calls)[len(*calls)-1]; got != "docker-credential-desktop "+dockerHubServerURL {
+		t.Errorf("Docker Hub lookup = %q", got)
+	}
+}
+
+func TestAuthCache_NoCredentials(t *testing.T) {
+	t.Parallel()
+
+	c, calls := newTestAuthCache(t, `{"credsStore":"desktop"}`, nil)
+	named, _ := reference.ParseNormalizedNamed("public.ecr.aws/lambda/python:3.12")
+	sysCtx := isolatedSystemContext(t)
+	c.apply(sysCtx, named)
+	if sysCtx.DockerAuthConfig != nil {
+		t.Errorf("DockerAuthConfig = %+v, want nil for anonymous access", sysCtx.DockerAuthConfig)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("helper calls = %v, want one lookup", *calls)
+	}
+
+	// Without a credsStore, authentication is left to containers/image.
+	c, calls = newTestAuthCache(t, `{"auths":{}}`, nil)
+	sysCtx = isolatedSystemContext(t)
+	c.apply(sysCtx, named)
+	if sysCtx.DockerAuthConfig != nil || len(*calls) != 0 {
+		t.Errorf("unexpected credentials lookup: %+v, calls %v", sysCtx.DockerAuthConfig, *calls)
+	}
+}
+
+func TestAuthCache_ExistingCredentialsWin(t *testing.T) {
+	t.Parallel()
+
+	c, calls := newTestAuthCache(t, `{"credsStore":"desktop"}`, map[string]string{"registry.example.com": "from-store"})
+	authFile := filepath.Join(t.TempDir(), "auth.json")
+	// "cm9ib3Q6ZnJvbS1maWxl" is base64("robot:from-file").
+	if err := os.WriteFile(authFile, []byte(`{"auths":{"registry.example.com":{"auth":"cm9ib3Q6ZnJvbS1maWxl"}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	named, _ := reference.ParseNormalizedNamed("registry.example.com/team/app:1.0")
+	sysCtx := &types.SystemContext{AuthFilePath: authFile}
+	c.apply(sysCtx, named)
+	if sysCtx.DockerAuthConfig != nil || len(*calls) != 0 {
+		t.Errorf("auth file credentials should take precedence: %+v, calls %v", sysCtx.DockerAuthConfig, *calls)
+	}
+}
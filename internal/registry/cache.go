@@ -0,0 +1,243 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json/v2"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// cacheFileExt is the extension of cache entry files inside the cache directory.
+const cacheFileExt = ".json"
+
+// DefaultCacheDir returns the directory used for the registry disk cache.
+// TALLY_REGISTRY_CACHE_DIR overrides the default under os.UserCacheDir.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("TALLY_REGISTRY_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "tally", "registry"), nil
+}
+
+// CacheEntry is a cached ImageConfig for one ref+platform pair.
+type CacheEntry struct {
+	Ref       string      `json:"ref"`
+	Platform  string      `json:"platform"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+	Config    ImageConfig `json:"config"`
+}
+
+// Pinned reports whether the entry's ref includes a digest. Digest-pinned
+// refs are immutable and never expire.
+func (e CacheEntry) Pinned() bool {
+	return strings.Contains(e.Ref, "@")
+}
+
+// Expired reports whether the entry is older than ttl at now.
+func (e CacheEntry) Expired(ttl time.Duration, now time.Time) bool {
+	return !e.Pinned() && now.Sub(e.FetchedAt) > ttl
+}
+
+// DiskCache stores resolved image configs as one JSON file per ref+platform.
+// Only successful lookups are cached, so auth, network, and not-found errors
+// are always retried against the registry.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewDiskCache creates a cache rooted at dir whose entries expire after ttl.
+func NewDiskCache(dir string, ttl time.Duration) *DiskCache {
+	return &DiskCache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// Dir returns the cache directory.
+func (c *DiskCache) Dir() string { return c.dir }
+
+// TTL returns how long entries stay fresh.
+func (c *DiskCache) TTL() time.Duration { return c.ttl }
+
+// Get returns the cached config for ref and platform if present and fresh.
+func (c *DiskCache) Get(ref, platform string) (ImageConfig, bool) {
+	entry, err := readCacheEntry(c.path(ref, platform))
+	if err != nil || entry.Ref != ref || entry.Platform != platform || entry.Expired(c.ttl, c.now()) {
+		return ImageConfig{}, false
+	}
+	return entry.Config, true
+}
+
+// Put stores cfg for ref and platform. The entry is written to a temporary
+// file and renamed so concurrent readers never observe partial writes.
+func (c *DiskCache) Put(ref, platform string, cfg ImageConfig) error {
+	// Keep nil Env/Shell distinguishable from empty ones (nil Shell means the
+	// image sets no SHELL).
+	data, err := json.Marshal(
+		CacheEntry{Ref: ref, Platform: platform, FetchedAt: c.now().UTC(), Config: cfg},
+		json.FormatNilMapAsNull(true), json.FormatNilSliceAsNull(true),
+	)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(ref, platform))
+}
+
+// Entries returns all readable cache entries sorted by ref and platform.
+func (c *DiskCache) Entries() ([]CacheEntry, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]CacheEntry, 0, len(files))
+	for _, path := range files {
+		if entry, err := readCacheEntry(path); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b CacheEntry) int {
+		if n := strings.Compare(a.Ref, b.Ref); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Platform, b.Platform)
+	})
+	return entries, nil
+}
+
+// Purge removes cache entries and returns how many were removed. When
+// expiredOnly is set, fresh entries are kept; unreadable entries are always
+// removed.
+func (c *DiskCache) Purge(expiredOnly bool) (int, error) {
+	files, err := c.files()
+	if err != nil {
+		return 0, err
+	}
+	now := c.now()
+	removed := 0
+	for _, path := range files {
+		if expiredOnly {
+			if entry, err := readCacheEntry(path); err == nil && !entry.Expired(c.ttl, now) {
+				continue
+			}
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (c *DiskCache) files() ([]string, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, de := range dirEntries {
+		if !de.IsDir() && strings.HasSuffix(de.Name(), cacheFileExt) {
+			files = append(files, filepath.Join(c.dir, de.Name()))
+		}
+	}
+	return files, nil
+}
+
+func (c *DiskCache) path(ref, platform string) string {
+	sum := sha256.Sum256([]byte(ref + "\x00" + platform))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+cacheFileExt)
+}
+
+func readCacheEntry(path string) (CacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, err
+	}
+	return entry, nil
+}
+
+// CachingResolver serves ResolveConfig from a DiskCache and falls back to an
+// inner resolver on a miss.
+type CachingResolver struct {
+	inner ImageResolver
+	cache *DiskCache
+}
+
+// NewCachingResolver wraps inner with cache.
+func NewCachingResolver(inner ImageResolver, cache *DiskCache) *CachingResolver {
+	return &CachingResolver{inner: inner, cache: cache}
+}
+
+// ResolveConfig returns the cached config when fresh, otherwise resolves it
+// from the registry and caches the result.
+func (r *CachingResolver) ResolveConfig(ctx context.Context, ref, platform string) (ImageConfig, error) {
+	if cfg, ok := r.cache.Get(ref, platform); ok {
+		return cfg, nil
+	}
+	cfg, err := r.inner.ResolveConfig(ctx, ref, platform)
+	if err != nil {
+		return cfg, err
+	}
+	// A cache write failure only costs a future registry round-trip.
+	_ = r.cache.Put(ref, platform, cfg)
+	return cfg, nil
+}
+
+// ReadImageFiles reads image files through the inner resolver. File contents
+// are not cached.
+func (r *CachingResolver) ReadImageFiles(
+	ctx context.Context,
+	ref, platform string,
+	paths []string,
+) (map[string][]byte, error) {
+	files, ok := r.inner.(ImageFileReader)
+	if !ok {
+		return nil, ErrFilesUnsupported
+	}
+	return files.ReadImageFiles(ctx, ref, platform, paths)
+}
+
+// MissingImagePaths checks image paths through the inner resolver. Results
+// are not cached.
+func (r *CachingResolver) MissingImagePaths(
+	ctx context.Context,
+	ref, platform string,
+	patterns []string,
+) ([]string, error) {
+	paths, ok := r.inner.(ImagePathChecker)
+	if !ok {
+		return nil, ErrFilesUnsupported
+	}
+	return paths.MissingImagePaths(ctx, ref, platform, patterns)
+}
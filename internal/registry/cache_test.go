@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCachingResolver(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	inner := &mockImageResolver{
+		fn: func(_ context.Context, ref, _ string) (ImageConfig, error) {
+			calls++
+			if ref == "missing:latest" {
+				return ImageConfig{}, &NotFoundError{Ref: ref}
+			}
+			return ImageConfig{OS: "linux", Arch: "amd64", Digest: "sha256:abc", Env: map[string]string{"PATH": "/bin"}}, nil
+		},
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewDiskCache(t.TempDir(), time.Hour)
+	cache.now = func() time.Time { return now }
+	r := NewCachingResolver(inner, cache)
+	ctx := context.Background()
+
+	for range 2 {
+		cfg, err := r.ResolveConfig(ctx, "alpine:3.20", "linux/amd64")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Digest != "sha256:abc" || cfg.Env["PATH"] != "/bin" {
+			t.Fatalf("unexpected config: %+v", cfg)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("inner calls = %d, want 1 (second lookup served from cache)", calls)
+	}
+
+	// A different platform is a separate entry.
+	if _, err := r.ResolveConfig(ctx, "alpine:3.20", "linux/arm64"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("inner calls = %d, want 2", calls)
+	}
+
+	// Errors are never cached.
+	for range 2 {
+		if _, err := r.ResolveConfig(ctx, "missing:latest", "linux/amd64"); !errors.As(err, new(*NotFoundError)) {
+			t.Fatalf("expected NotFoundError, got %v", err)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("inner calls = %d, want 4", calls)
+	}
+
+	// Entries expire after the TTL.
+	now = now.Add(2 * time.Hour)
+	if _, err := r.ResolveConfig(ctx, "alpine:3.20", "linux/amd64"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 5 {
+		t.Errorf("inner calls = %d, want 5 after expiry", calls)
+	}
+}
+
+func TestDiskCache_EntriesAndPurge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewDiskCache(t.TempDir(), time.Hour)
+	cache.now = func() time.Time { return now }
+
+	pinned := "alpine@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	for _, ref := range []string{"alpine:3.20", pinned} {
+		if err := cache.Put(ref, "linux/amd64", ImageConfig{OS: "linux"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if err := cache.Put("busybox:1", "linux/amd64", ImageConfig{OS: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := cache.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Ref != "alpine:3.20" || entries[1].Ref != pinned || entries[2].Ref != "busybox:1" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if _, ok := cache.Get(pinned, "linux/amd64"); !ok {
+		t.Error("digest-pinned entry should never expire")
+	}
+	if entries[0].Config.Shell != nil || entries[0].Config.Env != nil {
+		t.Errorf("nil Shell/Env should round-trip as nil: %+v", entries[0].Config)
+	}
+
+	n, err := cache.Purge(true)
+	if err != nil || n != 1 {
+		t.Fatalf("Purge(expired) = %d, %v; want 1 expired entry removed", n, err)
+	}
+	n, err = cache.Purge(false)
+	if err != nil || n != 2 {
+		t.Fatalf("Purge(all) = %d, %v; want 2", n, err)
+	}
+	if entries, _ := cache.Entries(); len(entries) != 0 {
+		t.Errorf("entries after purge: %+v", entries)
+	}
+}
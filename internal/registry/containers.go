@@ -16,6 +16,7 @@ import (
 	"go.podman.io/image/v5/docker/reference"
 	"go.podman.io/image/v5/manifest"
 	"go.podman.io/image/v5/pkg/blobinfocache/memory"
+	"go.podman.io/image/v5/pkg/compression"
 	"go.podman.io/image/v5/types"
 
 	godigest "github.com/opencontainers/go-digest"
@@ -23,24 +24,38 @@ import (
 )
 
 func init() {
-	NewDefaultResolver = func() ImageResolver {
-		return NewContainersResolver()
+	NewDefaultResolver = func(opts Options) ImageResolver {
+		return opts.withCache(NewContainersResolverWithOptions(opts))
 	}
 }
 
 // ContainersResolver uses go.podman.io/image/v5 (containers/image) to resolve
-// image configs from OCI/Docker registries. It respects registries.conf and
-// auth.json via types.SystemContext.
+// image configs from OCI/Docker registries. It respects registries.conf,
+// auth.json, Docker's config.json, and credential helpers via
+// types.SystemContext.
 type ContainersResolver struct {
 	sysCtx    *types.SystemContext
 	blobCache types.BlobInfoCache
+	hosts     map[string]HostOptions
+	auth      *authCache
 }
 
 // NewContainersResolver creates a resolver using the default system context.
 // CONTAINERS_REGISTRIES_CONF is honored automatically by sysregistriesv2 when
 // resolving registry mirrors and redirects.
 func NewContainersResolver() *ContainersResolver {
-	return &ContainersResolver{sysCtx: &types.SystemContext{}, blobCache: memory.New()}
+	return NewContainersResolverWithOptions(Options{})
+}
+
+// NewContainersResolverWithOptions creates a resolver using the default system
+// context plus per-registry settings from opts.
+func NewContainersResolverWithOptions(opts Options) *ContainersResolver {
+	return &ContainersResolver{
+		sysCtx:    &types.SystemContext{},
+		blobCache: memory.New(),
+		hosts:     opts.Hosts,
+		auth:      newAuthCache(),
+	}
 }
 
 // NewContainersResolverWithContext creates a resolver with a custom system context.
@@ -53,10 +68,133 @@ func NewContainersResolverWithContext(sysCtx *types.SystemContext) *ContainersRe
 
 // ResolveConfig resolves image config from the registry.
 func (r *ContainersResolver) ResolveConfig(ctx context.Context, ref, platform string) (ImageConfig, error) {
+	src, sysCtx, err := r.openImageSource(ctx, ref, platform)
+	if err != nil {
+		return ImageConfig{}, err
+	}
+	defer src.Close()
+
+	// Get the manifest.
+	rawManifest, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return ImageConfig{}, classifyContainersError(ref, err)
+	}
+
+	// If it's a manifest list/index, select the matching platform entry.
+	if manifest.MIMETypeIsMultiImage(mimeType) {
+		return r.resolveFromIndex(ctx, src, rawManifest, mimeType, ref, platform, sysCtx)
+	}
+
+	// Single manifest: get config directly.
+	return r.resolveFromManifest(ctx, src, rawManifest, mimeType, ref, platform)
+}
+
+// ReadImageFiles reads regular files from the image's layers, scanning from
+// the top layer down and stopping once every requested path is decided.
+func (r *ContainersResolver) ReadImageFiles(
+	ctx context.Context,
+	ref, platform string,
+	paths []string,
+) (map[string][]byte, error) {
+	finder := newLayerFileFinder(paths)
+	if err := r.scanImageLayers(ctx, ref, platform, finder); err != nil {
+		return nil, err
+	}
+	return finder.files(), nil
+}
+
+// MissingImagePaths scans the image's layers from the top down and returns
+// the patterns that match nothing. Proving a path absent reads every layer.
+func (r *ContainersResolver) MissingImagePaths(
+	ctx context.Context,
+	ref, platform string,
+	patterns []string,
+) ([]string, error) {
+	matcher := newLayerPathMatcher(patterns)
+	if err := r.scanImageLayers(ctx, ref, platform, matcher); err != nil {
+		return nil, err
+	}
+	return matcher.missing(), nil
+}
+
+// scanImageLayers feeds the layers of the image matching platform to
+// scanner, from the top layer down, until the scanner is done.
+func (r *ContainersResolver) scanImageLayers(
+	ctx context.Context,
+	ref, platform string,
+	scanner layerScanner,
+) error {
+	src, sysCtx, err := r.openImageSource(ctx, ref, platform)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	rawManifest, mimeType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return classifyContainersError(ref, err)
+	}
+	if manifest.MIMETypeIsMultiImage(mimeType) {
+		list, err := manifest.ListFromBlob(rawManifest, mimeType)
+		if err != nil {
+			return classifyContainersError(ref, err)
+		}
+		chosen, err := list.ChooseInstance(sysCtx)
+		if err != nil {
+			return &PlatformMismatchError{
+				Ref:       ref,
+				Requested: platform,
+				Available: collectAvailablePlatforms(list),
+				Err:       err,
+			}
+		}
+		if rawManifest, mimeType, err = src.GetManifest(ctx, &chosen); err != nil {
+			return classifyContainersError(ref, err)
+		}
+	}
+	man, err := manifest.FromBlob(rawManifest, mimeType)
+	if err != nil {
+		return classifyContainersError(ref, err)
+	}
+
+	layers := man.LayerInfos()
+	for i := len(layers) - 1; i >= 0 && !scanner.done(); i-- {
+		if err := r.scanLayer(ctx, src, layers[i].BlobInfo, scanner); err != nil {
+			return classifyContainersError(ref, err)
+		}
+	}
+	return nil
+}
+
+func (r *ContainersResolver) scanLayer(
+	ctx context.Context,
+	src types.ImageSource,
+	info types.BlobInfo,
+	scanner layerScanner,
+) error {
+	blob, _, err := src.GetBlob(ctx, info, r.blobCache)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	uncompressed, _, err := compression.AutoDecompress(blob)
+	if err != nil {
+		return err
+	}
+	defer uncompressed.Close()
+	return scanner.scanLayer(uncompressed)
+}
+
+// openImageSource opens ref with the system context configured for platform
+// and the registry hosting ref.
+func (r *ContainersResolver) openImageSource(
+	ctx context.Context,
+	ref, platform string,
+) (types.ImageSource, *types.SystemContext, error) {
 	// Parse the image reference.
 	named, err := reference.ParseNormalizedNamed(ref)
 	if err != nil {
-		return ImageConfig{}, &NotFoundError{Ref: ref, Err: fmt.Errorf("invalid reference: %w", err)}
+		return nil, nil, &NotFoundError{Ref: ref, Err: fmt.Errorf("invalid reference: %w", err)}
 	}
 	// Ensure we have a tag or digest.
 	named = reference.TagNameOnly(named)
@@ -64,7 +202,7 @@ func (r *ContainersResolver) ResolveConfig(ctx context.Context, ref, platform st
 	// Create a docker reference.
 	dockerRef, err := docker.NewReference(named)
 	if err != nil {
-		return ImageConfig{}, classifyContainersError(ref, err)
+		return nil, nil, classifyContainersError(ref, err)
 	}
 
 	// Set up system context with platform selection.
@@ -82,26 +220,30 @@ func (r *ContainersResolver) ResolveConfig(ctx context.Context, ref, platform st
 		}
 	}
 
+	r.configureRegistry(&sysCtx, named)
+
 	// Create image source.
 	src, err := dockerRef.NewImageSource(ctx, &sysCtx)
 	if err != nil {
-		return ImageConfig{}, classifyContainersError(ref, err)
+		return nil, nil, classifyContainersError(ref, err)
 	}
-	defer src.Close()
+	return src, &sysCtx, nil
+}
 
-	// Get the manifest.
-	rawManifest, mimeType, err := src.GetManifest(ctx, nil)
-	if err != nil {
-		return ImageConfig{}, classifyContainersError(ref, err)
+// configureRegistry applies per-registry TLS settings and credentials for the
+// registry hosting named.
+func (r *ContainersResolver) configureRegistry(sysCtx *types.SystemContext, named reference.Named) {
+	if host, ok := r.hosts[reference.Domain(named)]; ok {
+		if host.Insecure {
+			sysCtx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+		}
+		if host.CertsDir != "" {
+			sysCtx.DockerCertPath = host.CertsDir
+		}
 	}
-
-	// If it's a manifest list/index, select the matching platform entry.
-	if manifest.MIMETypeIsMultiImage(mimeType) {
-		return r.resolveFromIndex(ctx, src, rawManifest, mimeType, ref, platform, &sysCtx)
+	if r.auth != nil {
+		r.auth.apply(sysCtx, named)
 	}
-
-	// Single manifest: get config directly.
-	return r.resolveFromManifest(ctx, src, rawManifest, mimeType, ref, platform)
 }
 
 func (r *ContainersResolver) resolveFromIndex(
@@ -186,7 +328,9 @@ func (r *ContainersResolver) resolveFromManifest(
 		Digest:         manifestDigest.String(),
 		HasHealthcheck: extractHasHealthcheck(configBytes),
 		WorkingDir:     ociConfig.Config.WorkingDir,
+		User:           ociConfig.Config.User,
 		Shell:          extractShell(configBytes),
+		Labels:         ociConfig.Config.Labels,
 	}
 
 	// Platform mismatch check for single-manifest images.
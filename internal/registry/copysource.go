@@ -0,0 +1,86 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const copySourceResolverID = "registry-copy-source"
+
+// CopySourceResolverID is the resolver ID for checking COPY --from images.
+func CopySourceResolverID() string { return copySourceResolverID }
+
+// CopySourceRequest is the typed input for the copy-source async resolver.
+type CopySourceRequest struct {
+	Ref      string
+	Platform string
+
+	// Paths are source patterns to look up in the image. Empty means only
+	// the image's existence is checked.
+	Paths []string
+}
+
+// CopySourceResult describes whether a COPY --from image can be copied from.
+// At most one of NotFound and PlatformMismatch is set; MissingPaths is only
+// filled in for images that resolved.
+type CopySourceResult struct {
+	NotFound         *NotFoundError
+	PlatformMismatch *PlatformMismatchError
+
+	// MissingPaths are the requested patterns that match nothing in the image.
+	MissingPaths []string
+}
+
+// AsyncCopySourceResolver checks images used as COPY --from sources. Unlike
+// AsyncImageResolver, a missing image is a result rather than a skip: a
+// COPY --from image that does not exist fails the build.
+type AsyncCopySourceResolver struct {
+	images *AsyncImageResolver
+	paths  ImagePathChecker
+}
+
+// NewAsyncCopySourceResolver creates a copy-source resolver. Path checks are
+// available when inner implements ImagePathChecker.
+func NewAsyncCopySourceResolver(inner ImageResolver) *AsyncCopySourceResolver {
+	paths, _ := inner.(ImagePathChecker)
+	return &AsyncCopySourceResolver{images: NewAsyncImageResolver(inner), paths: paths}
+}
+
+// ID returns the resolver identifier.
+func (r *AsyncCopySourceResolver) ID() string { return copySourceResolverID }
+
+// Resolve resolves the image config with AsyncImageResolver's retry policy,
+// then looks up the requested paths. Auth and network errors, and path
+// lookups the resolver cannot perform, are returned as errors so the check
+// is skipped.
+func (r *AsyncCopySourceResolver) Resolve(ctx context.Context, data any) (any, error) {
+	req, ok := data.(*CopySourceRequest)
+	if !ok {
+		return nil, fmt.Errorf("copy-source resolver: unexpected data type %T", data)
+	}
+
+	if _, err := r.images.resolveWithRetry(ctx, req.Ref, req.Platform); err != nil {
+		if notFound, ok := errors.AsType[*NotFoundError](err); ok {
+			return &CopySourceResult{NotFound: notFound}, nil
+		}
+		if mismatch, ok := errors.AsType[*PlatformMismatchError](err); ok {
+			return &CopySourceResult{PlatformMismatch: mismatch}, nil
+		}
+		return nil, err
+	}
+
+	result := &CopySourceResult{}
+	if len(req.Paths) == 0 {
+		return result, nil
+	}
+	if r.paths == nil {
+		return nil, ErrFilesUnsupported
+	}
+	missing, err := r.paths.MissingImagePaths(ctx, req.Ref, req.Platform, req.Paths)
+	if err != nil {
+		return nil, err
+	}
+	result.MissingPaths = missing
+	return result, nil
+}
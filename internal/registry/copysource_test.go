@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// mockPathResolver implements ImageResolver and ImagePathChecker for testing.
+type mockPathResolver struct {
+	mockImageResolver
+	missing func(patterns []string) ([]string, error)
+}
+
+func (r *mockPathResolver) MissingImagePaths(_ context.Context, _, _ string, patterns []string) ([]string, error) {
+	return r.missing(patterns)
+}
+
+func TestAsyncCopySourceResolver_ID(t *testing.T) {
+	t.Parallel()
+	if got := NewAsyncCopySourceResolver(&mockImageResolver{}).ID(); got != CopySourceResolverID() {
+		t.Errorf("ID() = %q, want %q", got, CopySourceResolverID())
+	}
+}
+
+func TestAsyncCopySourceResolver_NotFoundIsAResult(t *testing.T) {
+	t.Parallel()
+	inner := &mockImageResolver{
+		fn: func(_ context.Context, ref, _ string) (ImageConfig, error) {
+			return ImageConfig{}, &NotFoundError{Ref: ref, Err: errors.New("manifest unknown")}
+		},
+	}
+	got, err := NewAsyncCopySourceResolver(inner).Resolve(context.Background(),
+		&CopySourceRequest{Ref: "example.com/tools:9", Platform: "linux/amd64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, ok := got.(*CopySourceResult)
+	if !ok || result.NotFound == nil || result.NotFound.Ref != "example.com/tools:9" {
+		t.Errorf("result = %#v, want NotFound for example.com/tools:9", got)
+	}
+}
+
+func TestAsyncCopySourceResolver_PlatformMismatchIsAResult(t *testing.T) {
+	t.Parallel()
+	inner := &mockImageResolver{
+		fn: func(_ context.Context, ref, platform string) (ImageConfig, error) {
+			return ImageConfig{}, &PlatformMismatchError{Ref: ref, Requested: platform, Available: []string{"linux/amd64"}}
+		},
+	}
+	got, err := NewAsyncCopySourceResolver(inner).Resolve(context.Background(),
+		&CopySourceRequest{Ref: "tools:9", Platform: "linux/arm64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result, ok := got.(*CopySourceResult); !ok || result.PlatformMismatch == nil {
+		t.Errorf("result = %#v, want PlatformMismatch", got)
+	}
+}
+
+func TestAsyncCopySourceResolver_NetworkErrorSkips(t *testing.T) {
+	t.Parallel()
+	inner := &mockImageResolver{
+		fn: func(_ context.Context, _, _ string) (ImageConfig, error) {
+			return ImageConfig{}, &AuthError{Err: errors.New("denied")}
+		},
+	}
+	_, err := NewAsyncCopySourceResolver(inner).Resolve(context.Background(),
+		&CopySourceRequest{Ref: "private.example.com/tools:9", Platform: "linux/amd64"})
+	if _, ok := errors.AsType[*AuthError](err); !ok {
+		t.Errorf("err = %v, want AuthError", err)
+	}
+}
+
+func TestAsyncCopySourceResolver_Paths(t *testing.T) {
+	t.Parallel()
+	var asked []string
+	inner := &mockPathResolver{
+		mockImageResolver: mockImageResolver{
+			fn: func(_ context.Context, _, _ string) (ImageConfig, error) { return ImageConfig{}, nil },
+		},
+		missing: func(patterns []string) ([]string, error) {
+			asked = patterns
+			return []string{"/usr/bin/tool"}, nil
+		},
+	}
+	req := &CopySourceRequest{Ref: "tools:9", Platform: "linux/amd64", Paths: []string{"/etc/tool", "/usr/bin/tool"}}
+	got, err := NewAsyncCopySourceResolver(inner).Resolve(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, ok := got.(*CopySourceResult)
+	if !ok || !slices.Equal(result.MissingPaths, []string{"/usr/bin/tool"}) {
+		t.Errorf("result = %#v, want MissingPaths [/usr/bin/tool]", got)
+	}
+	if !slices.Equal(asked, req.Paths) {
+		t.Errorf("checked %v, want %v", asked, req.Paths)
+	}
+}
+
+func TestAsyncCopySourceResolver_PathsUnsupported(t *testing.T) {
+	t.Parallel()
+	inner := &mockImageResolver{
+		fn: func(_ context.Context, _, _ string) (ImageConfig, error) { return ImageConfig{}, nil },
+	}
+	r := NewAsyncCopySourceResolver(inner)
+
+	got, err := r.Resolve(context.Background(), &CopySourceRequest{Ref: "tools:9", Platform: "linux/amd64"})
+	if err != nil {
+		t.Fatalf("existence check: unexpected error: %v", err)
+	}
+	if result, ok := got.(*CopySourceResult); !ok || result.NotFound != nil || len(result.MissingPaths) > 0 {
+		t.Errorf("result = %#v, want an empty result", got)
+	}
+
+	_, err = r.Resolve(context.Background(),
+		&CopySourceRequest{Ref: "tools:9", Platform: "linux/amd64", Paths: []string{"/etc/tool"}})
+	if !errors.Is(err, ErrFilesUnsupported) {
+		t.Errorf("err = %v, want ErrFilesUnsupported", err)
+	}
+}
@@ -0,0 +1,246 @@
+package registry
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxImageFileSize bounds each file returned by ReadImageFiles. Package
+// databases of large distribution images stay well below it.
+const maxImageFileSize = 32 << 20
+
+// ImageFileReader reads files from an image's root filesystem.
+type ImageFileReader interface {
+	// ReadImageFiles returns the contents of the requested regular files as
+	// they appear in the image's final filesystem, keyed by the requested
+	// path. Paths are absolute or relative to the image root. Files that are
+	// absent, deleted by a whiteout, or not regular files (symlinks included)
+	// are omitted from the result.
+	//
+	// The error contract matches ImageResolver.ResolveConfig.
+	ReadImageFiles(ctx context.Context, ref, platform string, paths []string) (map[string][]byte, error)
+}
+
+// ErrFilesUnsupported is returned by resolvers that cannot read image layers.
+var ErrFilesUnsupported = errors.New("resolver cannot read image files")
+
+// layerFileFinder locates files in a stack of layer tarballs scanned from the
+// top layer down. The first layer that mentions a path decides it: a regular
+// file is read, anything else (symlink, directory, whiteout) hides the path
+// in lower layers.
+type layerFileFinder struct {
+	requested map[string]string // normalized path → requested path
+	decided   map[string]bool
+	found     map[string][]byte
+}
+
+func newLayerFileFinder(paths []string) *layerFileFinder {
+	f := &layerFileFinder{
+		requested: make(map[string]string, len(paths)),
+		decided:   make(map[string]bool, len(paths)),
+		found:     make(map[string][]byte, len(paths)),
+	}
+	for _, p := range paths {
+		f.requested[normalizeLayerPath(p)] = p
+	}
+	return f
+}
+
+// done reports whether every requested path has been decided.
+func (f *layerFileFinder) done() bool {
+	return len(f.decided) == len(f.requested)
+}
+
+// files returns the regular files found so far, keyed by requested path.
+func (f *layerFileFinder) files() map[string][]byte {
+	return f.found
+}
+
+// scanLayer reads one uncompressed layer tarball. Whiteouts in the layer only
+// affect lower layers, so they are applied after the whole layer is read.
+func (f *layerFileFinder) scanLayer(r io.Reader) error {
+	var hidden []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read layer: %w", err)
+		}
+		name := normalizeLayerPath(hdr.Name)
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+		switch {
+		case base == ".wh..wh..opq":
+			// An opaque directory hides everything below it.
+			hidden = append(hidden, dir)
+			continue
+		case strings.HasPrefix(base, ".wh."):
+			hidden = append(hidden, path.Join(dir, strings.TrimPrefix(base, ".wh.")))
+			continue
+		}
+
+		if _, ok := f.requested[name]; !ok || f.decided[name] {
+			continue
+		}
+		f.decided[name] = true
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxImageFileSize {
+			return fmt.Errorf("%s: file exceeds %d bytes", name, maxImageFileSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxImageFileSize))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		f.found[f.requested[name]] = data
+	}
+
+	for _, name := range hidden {
+		for req := range f.requested {
+			if name == "" || req == name || strings.HasPrefix(req, name+"/") {
+				f.decided[req] = true
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeLayerPath converts "/etc/os-release" and "./etc/os-release" to
+// "etc/os-release".
+func normalizeLayerPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// ImagePathChecker checks which paths exist in an image's root filesystem.
+type ImagePathChecker interface {
+	// MissingImagePaths returns the patterns that match nothing in the
+	// image's final filesystem, in the order given. Patterns use path.Match
+	// syntax, are absolute or relative to the image root, and match files,
+	// directories and symlinks alike. A pattern naming a directory matches
+	// when anything is stored below it.
+	//
+	// The error contract matches ImageResolver.ResolveConfig.
+	MissingImagePaths(ctx context.Context, ref, platform string, patterns []string) ([]string, error)
+}
+
+// layerScanner consumes layer tarballs from the top layer down.
+type layerScanner interface {
+	scanLayer(r io.Reader) error
+	done() bool
+}
+
+// layerPathMatcher records which patterns match an entry of a stack of layer
+// tarballs scanned from the top layer down. Entries removed by a whiteout in
+// a higher layer do not count.
+type layerPathMatcher struct {
+	patterns []string // normalized, in request order
+	original []string
+	matched  []bool
+	left     int
+	hidden   []string // paths whited out by the layers scanned so far
+}
+
+func newLayerPathMatcher(patterns []string) *layerPathMatcher {
+	m := &layerPathMatcher{
+		patterns: make([]string, len(patterns)),
+		original: patterns,
+		matched:  make([]bool, len(patterns)),
+		left:     len(patterns),
+	}
+	for i, p := range patterns {
+		m.patterns[i] = normalizeLayerPath(p)
+		if m.patterns[i] == "" {
+			// The image root always exists.
+			m.matched[i] = true
+			m.left--
+		}
+	}
+	return m
+}
+
+// done reports whether every pattern has matched.
+func (m *layerPathMatcher) done() bool {
+	return m.left == 0
+}
+
+// missing returns the patterns that matched nothing.
+func (m *layerPathMatcher) missing() []string {
+	var out []string
+	for i, ok := range m.matched {
+		if !ok {
+			out = append(out, m.original[i])
+		}
+	}
+	return out
+}
+
+// scanLayer reads one uncompressed layer tarball. Like layerFileFinder, it
+// applies the layer's whiteouts to lower layers only.
+func (m *layerPathMatcher) scanLayer(r io.Reader) error {
+	var hidden []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read layer: %w", err)
+		}
+		name := normalizeLayerPath(hdr.Name)
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+		switch {
+		case base == ".wh..wh..opq":
+			hidden = append(hidden, dir)
+			continue
+		case strings.HasPrefix(base, ".wh."):
+			hidden = append(hidden, path.Join(dir, strings.TrimPrefix(base, ".wh.")))
+			continue
+		}
+		if name == "" || m.isHidden(name) {
+			continue
+		}
+		m.match(name)
+		if m.done() {
+			return nil
+		}
+	}
+	m.hidden = append(m.hidden, hidden...)
+	return nil
+}
+
+// isHidden reports whether name was whited out by a higher layer.
+func (m *layerPathMatcher) isHidden(name string) bool {
+	for _, h := range m.hidden {
+		if h == "" || name == h || strings.HasPrefix(name, h+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// match marks the patterns matching name or one of its parent directories.
+func (m *layerPathMatcher) match(name string) {
+	for i, p := range m.patterns {
+		if m.matched[i] {
+			continue
+		}
+		for prefix := name; prefix != "."; prefix = path.Dir(prefix) {
+			if ok, _ := path.Match(p, prefix); ok {
+				m.matched[i] = true
+				m.left--
+				break
+			}
+		}
+	}
+}
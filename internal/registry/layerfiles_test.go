@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+)
+
+type tarEntry struct {
+	name     string
+	body     string
+	typeflag byte
+}
+
+func makeLayer(t *testing.T, entries ...tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Size: int64(len(e.body)), Mode: 0o644}
+		if e.typeflag == 0 {
+			hdr.Typeflag = tar.TypeReg
+		} else {
+			hdr.Size = 0
+			hdr.Linkname = e.body
+		}
+		if err := w.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := w.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestLayerFileFinder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		layers [][]tarEntry // top layer first
+		want   map[string]string
+	}{
+		{
+			name: "upper layer wins",
+			layers: [][]tarEntry{
+				{{name: "etc/os-release", body: "upper"}},
+				{{name: "./etc/os-release", body: "lower"}, {name: "var/lib/dpkg/status", body: "dpkg"}},
+			},
+			want: map[string]string{"/etc/os-release": "upper", "var/lib/dpkg/status": "dpkg"},
+		},
+		{
+			name: "whiteout hides lower file",
+			layers: [][]tarEntry{
+				{{name: "etc/.wh.os-release"}},
+				{{name: "etc/os-release", body: "lower"}},
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "opaque directory hides lower files",
+			layers: [][]tarEntry{
+				{{name: "var/lib/dpkg/.wh..wh..opq"}, {name: "etc/os-release", body: "upper"}},
+				{{name: "var/lib/dpkg/status", body: "lower"}},
+			},
+			want: map[string]string{"/etc/os-release": "upper"},
+		},
+		{
+			name: "whiteout does not hide the same layer",
+			layers: [][]tarEntry{
+				{{name: "var/lib/dpkg/.wh..wh..opq"}, {name: "var/lib/dpkg/status", body: "upper"}},
+				{{name: "var/lib/dpkg/status", body: "lower"}},
+			},
+			want: map[string]string{"var/lib/dpkg/status": "upper"},
+		},
+		{
+			name: "symlink shadows lower file",
+			layers: [][]tarEntry{
+				{{name: "etc/os-release", body: "../usr/lib/os-release", typeflag: tar.TypeSymlink}},
+				{{name: "etc/os-release", body: "lower"}},
+			},
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := newLayerFileFinder([]string{"/etc/os-release", "var/lib/dpkg/status"})
+			for _, layer := range tt.layers {
+				if f.done() {
+					break
+				}
+				if err := f.scanLayer(makeLayer(t, layer...)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := f.files()
+			if len(got) != len(tt.want) {
+				t.Fatalf("files = %v, want %v", got, tt.want)
+			}
+			for p, want := range tt.want {
+				if string(got[p]) != want {
+					t.Errorf("%s = %q, want %q", p, got[p], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLayerFileFinder_Done(t *testing.T) {
+	t.Parallel()
+	f := newLayerFileFinder([]string{"etc/os-release"})
+	if f.done() {
+		t.Fatal("done before scanning")
+	}
+	if err := f.scanLayer(makeLayer(t, tarEntry{name: "etc/os-release", body: "x"})); err != nil {
+		t.Fatal(err)
+	}
+	if !f.done() {
+		t.Error("not done after finding every path")
+	}
+}
+
+func TestLayerPathMatcher(t *testing.T) {
+	t.Parallel()
+
+	patterns := []string{"/usr/local/bin/tool", "/usr/share/doc", "/etc/*.conf", "/opt/app", "/"}
+	tests := []struct {
+		name    string
+		layers  [][]tarEntry // top layer first
+		missing []string
+	}{
+		{
+			name: "files, parent directories and globs match",
+			layers: [][]tarEntry{
+				{{name: "usr/local/bin/tool", body: "x"}, {name: "etc/app.conf", body: "x"}},
+				{{name: "./usr/share/doc/README", body: "x"}, {name: "opt/app", body: "../srv/app", typeflag: tar.TypeSymlink}},
+			},
+		},
+		{
+			name: "absent paths are missing",
+			layers: [][]tarEntry{
+				{{name: "usr/local/bin/other", body: "x"}, {name: "etc/app.yaml", body: "x"}},
+				{{name: "usr/share/docs/README", body: "x"}},
+			},
+			missing: []string{"/usr/local/bin/tool", "/usr/share/doc", "/etc/*.conf", "/opt/app"},
+		},
+		{
+			name: "whiteouts hide lower layers",
+			layers: [][]tarEntry{
+				{{name: "usr/local/bin/.wh.tool"}, {name: "usr/share/doc/.wh..wh..opq"}, {name: "etc/app.conf", body: "x"}},
+				{{name: "usr/local/bin/tool", body: "x"}, {name: "usr/share/doc/README", body: "x"}, {name: "opt/app/run", body: "x"}},
+			},
+			missing: []string{"/usr/local/bin/tool", "/usr/share/doc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m := newLayerPathMatcher(patterns)
+			for _, layer := range tt.layers {
+				if m.done() {
+					break
+				}
+				if err := m.scanLayer(makeLayer(t, layer...)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := m.missing()
+			if len(got) != len(tt.missing) {
+				t.Fatalf("missing = %v, want %v", got, tt.missing)
+			}
+			for i := range got {
+				if got[i] != tt.missing[i] {
+					t.Errorf("missing = %v, want %v", got, tt.missing)
+					break
+				}
+			}
+			if m.done() != (len(tt.missing) == 0) {
+				t.Errorf("done() = %v with missing %v", m.done(), got)
+			}
+		})
+	}
+}
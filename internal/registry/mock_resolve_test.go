@@ -29,6 +29,9 @@ func TestContainersResolver_MockRegistry_SingleImage(t *testing.T) {
 		Env: map[string]string{
 			"PATH": "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
 		},
+		WorkingDir: "/srv",
+		User:       "nobody",
+		Labels:     map[string]string{"org.opencontainers.image.base.name": "docker.io/library/busybox:1.36"},
 	})
 	if err != nil {
 		t.Fatalf("AddImage: %v", err)
@@ -57,6 +60,15 @@ func TestContainersResolver_MockRegistry_SingleImage(t *testing.T) {
 	if cfg.Env["PATH"] != "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin" {
 		t.Errorf("PATH = %q, want standard PATH", cfg.Env["PATH"])
 	}
+	if cfg.WorkingDir != "/srv" {
+		t.Errorf("WorkingDir = %q, want /srv", cfg.WorkingDir)
+	}
+	if cfg.User != "nobody" {
+		t.Errorf("User = %q, want nobody", cfg.User)
+	}
+	if got := cfg.Labels["org.opencontainers.image.base.name"]; got != "docker.io/library/busybox:1.36" {
+		t.Errorf("base name label = %q, want docker.io/library/busybox:1.36", got)
+	}
 }
 
 func TestContainersResolver_MockRegistry_MultiArch(t *testing.T) {
@@ -208,3 +220,101 @@ func TestContainersResolver_MockRegistry_RegistriesConf(t *testing.T) {
 		t.Errorf("expected mock to receive manifest request, got: %v", mr.Requests())
 	}
 }
+
+func TestContainersResolver_MockRegistry_PerHostInsecure(t *testing.T) {
+	t.Parallel()
+
+	mr := testutil.New()
+	defer mr.Close()
+
+	if _, err := mr.AddImage(testutil.ImageOpts{Repo: "library/alpine", Tag: "3.19", OS: "linux", Arch: "amd64"}); err != nil {
+		t.Fatalf("AddImage: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The mock registry serves plain HTTP, so it is only reachable when the
+	// host is configured as insecure.
+	resolver := NewContainersResolverWithOptions(Options{
+		Hosts: map[string]HostOptions{mr.Host(): {Insecure: true}},
+	})
+	if _, err := resolver.ResolveConfig(ctx, mr.Host()+"/library/alpine:3.19", "linux/amd64"); err != nil {
+		t.Fatalf("ResolveConfig with insecure host: %v", err)
+	}
+
+	resolver = NewContainersResolverWithOptions(Options{})
+	if _, err := resolver.ResolveConfig(ctx, mr.Host()+"/library/alpine:3.19", "linux/amd64"); err == nil {
+		t.Fatal("expected TLS failure without per-host insecure setting")
+	}
+}
+
+func TestContainersResolver_MockRegistry_ReadImageFiles(t *testing.T) {
+	t.Parallel()
+
+	mr := testutil.New()
+	defer mr.Close()
+
+	_, err := mr.AddIndex("library/debian", "12", []testutil.ImageOpts{
+		{OS: "linux", Arch: "amd64", Files: map[string]string{"etc/os-release": "ID=debian\nVERSION_ID=\"12\"\n"}},
+		{OS: "linux", Arch: "arm64", Files: map[string]string{"etc/os-release": "ID=debian\nVERSION_ID=\"12\"\nARM=1\n"}},
+	})
+	if err != nil {
+		t.Fatalf("AddIndex: %v", err)
+	}
+
+	resolver := NewContainersResolverWithContext(&types.SystemContext{
+		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	files, err := resolver.ReadImageFiles(ctx, mr.Host()+"/library/debian:12", "linux/arm64",
+		[]string{"/etc/os-release", "/var/lib/dpkg/status"})
+	if err != nil {
+		t.Fatalf("ReadImageFiles: %v", err)
+	}
+	if got := string(files["/etc/os-release"]); got != "ID=debian\nVERSION_ID=\"12\"\nARM=1\n" {
+		t.Errorf("os-release = %q", got)
+	}
+	if _, ok := files["/var/lib/dpkg/status"]; ok {
+		t.Error("absent file should be omitted")
+	}
+
+	_, err = resolver.ReadImageFiles(ctx, mr.Host()+"/library/debian:12", "linux/s390x", []string{"/etc/os-release"})
+	if _, ok := errors.AsType[*PlatformMismatchError](err); !ok {
+		t.Errorf("expected PlatformMismatchError, got %T: %v", err, err)
+	}
+}
+
+func TestContainersResolver_MockRegistry_MissingImagePaths(t *testing.T) {
+	t.Parallel()
+
+	mr := testutil.New()
+	defer mr.Close()
+
+	_, err := mr.AddImage(testutil.ImageOpts{
+		Repo: "tools/cli", Tag: "1.0", OS: "linux", Arch: "amd64",
+		Files: map[string]string{"usr/local/bin/cli": "#!/bin/sh\n", "etc/cli/config.yaml": "debug: false\n"},
+	})
+	if err != nil {
+		t.Fatalf("AddImage: %v", err)
+	}
+
+	resolver := NewContainersResolverWithContext(&types.SystemContext{
+		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	missing, err := resolver.MissingImagePaths(ctx, mr.Host()+"/tools/cli:1.0", "linux/amd64",
+		[]string{"/usr/local/bin/cli", "/etc/cli", "/etc/cli/*.yaml", "/usr/bin/cli"})
+	if err != nil {
+		t.Fatalf("MissingImagePaths: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != "/usr/bin/cli" {
+		t.Errorf("missing = %v, want [/usr/bin/cli]", missing)
+	}
+}
@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/wharflab/tally/internal/config"
+)
+
+// Options configures how a resolver connects to registries.
+type Options struct {
+	// Hosts holds per-registry settings keyed by registry host
+	// (e.g. "registry.internal:5000").
+	Hosts map[string]HostOptions
+
+	// CacheTTL enables the registry disk cache when positive.
+	CacheTTL time.Duration
+
+	// CacheDir overrides DefaultCacheDir.
+	CacheDir string
+}
+
+// HostOptions configures the connection to a single registry.
+type HostOptions struct {
+	// Insecure skips TLS certificate verification and allows plain HTTP.
+	Insecure bool
+
+	// CertsDir is a directory containing ca.crt, client.cert, and client.key
+	// in the /etc/docker/certs.d/<host> layout.
+	CertsDir string
+}
+
+// OptionsFromConfig builds resolver options from the slow-checks settings of
+// cfgs. When several configs define the same host or a cache TTL, the first
+// wins.
+func OptionsFromConfig(cfgs ...*config.Config) Options {
+	var (
+		opts   Options
+		ttlSet bool
+	)
+	for _, cfg := range cfgs {
+		if cfg == nil {
+			continue
+		}
+		if !ttlSet && cfg.SlowChecks.CacheTTL != "" {
+			if d, err := time.ParseDuration(cfg.SlowChecks.CacheTTL); err == nil {
+				opts.CacheTTL = d
+				ttlSet = true
+			}
+		}
+		for host, reg := range cfg.SlowChecks.Registries {
+			if _, ok := opts.Hosts[host]; ok {
+				continue
+			}
+			if opts.Hosts == nil {
+				opts.Hosts = make(map[string]HostOptions)
+			}
+			opts.Hosts[host] = HostOptions{Insecure: reg.Insecure, CertsDir: reg.CertsDir}
+		}
+	}
+	return opts
+}
+
+// withCache wraps inner with the registry disk cache when opts enable it.
+func (opts Options) withCache(inner ImageResolver) ImageResolver {
+	if opts.CacheTTL <= 0 {
+		return inner
+	}
+	dir := opts.CacheDir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultCacheDir(); err != nil {
+			return inner
+		}
+	}
+	return NewCachingResolver(inner, NewDiskCache(dir, opts.CacheTTL))
+}
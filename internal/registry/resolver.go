@@ -52,10 +52,18 @@ type ImageConfig struct {
 	// Empty string means no explicit WORKDIR was set (default is /).
 	WorkingDir string
 
+	// User is the image's configured user (from USER).
+	// Empty string means no explicit USER was set (default is root).
+	User string
+
 	// Shell is the image's configured default shell (from SHELL instruction).
 	// Nil means no explicit SHELL was set (Docker defaults apply).
 	// This is a Docker extension — not part of the OCI image spec.
 	Shell []string
+
+	// Labels are the image's labels (from LABEL), e.g.
+	// "org.opencontainers.image.base.name". Nil when the image has none.
+	Labels map[string]string
 }
 
 // ResolveRequest is the typed input for the registry async resolver.
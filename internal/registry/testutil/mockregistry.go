@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -115,6 +116,9 @@ type ImageOpts struct {
 	Env         map[string]string // e.g. {"PATH": "/usr/bin", "PYTHON_VERSION": "3.12"}
 	Healthcheck []string          // e.g. {"CMD-SHELL", "curl -f http://localhost/ || exit 1"} (optional)
 	WorkingDir  string            // e.g. "/app" (optional)
+	User        string            // e.g. "nonroot" (optional)
+	Labels      map[string]string // e.g. {"org.opencontainers.image.base.name": "alpine:3.20"} (optional)
+	Files       map[string]string // e.g. {"etc/os-release": "ID=debian\n"}, added as a top layer (optional)
 }
 
 // AddImage pushes a single-platform image and returns its digest.
@@ -233,6 +237,16 @@ func buildImage(opts ImageOpts) (v1.Image, error) {
 		cfgFile.Config.WorkingDir = opts.WorkingDir
 	}
 
+	// Set user if provided.
+	if opts.User != "" {
+		cfgFile.Config.User = opts.User
+	}
+
+	// Set labels if provided.
+	if len(opts.Labels) > 0 {
+		cfgFile.Config.Labels = opts.Labels
+	}
+
 	// Set healthcheck if provided.
 	if len(opts.Healthcheck) > 0 {
 		cfgFile.Config.Healthcheck = &v1.HealthConfig{
@@ -245,6 +259,21 @@ func buildImage(opts ImageOpts) (v1.Image, error) {
 		return nil, err
 	}
 
+	// Add requested files as a top layer.
+	if len(opts.Files) > 0 {
+		filemap := make(map[string][]byte, len(opts.Files))
+		for p, content := range opts.Files {
+			filemap[p] = []byte(content)
+		}
+		layer, err := crane.Layer(filemap)
+		if err != nil {
+			return nil, err
+		}
+		if img, err = mutate.AppendLayers(img, layer); err != nil {
+			return nil, err
+		}
+	}
+
 	// Ensure media type is OCI.
 	img = mutate.MediaType(img, types.OCIManifestSchema1)
 	return img, nil
@@ -3,6 +3,7 @@ package reporter
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -14,61 +15,154 @@ import (
 //
 // Format: ::{level} file={file},line={line},col={col}::{message}
 //
+// GitHub drops annotations beyond a per-step cap for each level, so the
+// reporter annotates up to AnnotationLimit violations per level, preferring
+// info over style for notices, and notes how many it left out. The full
+// list goes to the job summary when StepSummaryPath is set.
+//
 // See: https://docs.github.com/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
 type GitHubActionsReporter struct {
 	writer io.Writer
+	opts   GitHubActionsOptions
+}
+
+// GitHubActionsOptions configures the GitHub Actions reporter.
+type GitHubActionsOptions struct {
+	// AnnotationLimit is the maximum number of annotations per level
+	// (error, warning, notice). Zero means no limit.
+	AnnotationLimit int
+
+	// StepSummaryPath is the job summary file (GITHUB_STEP_SUMMARY) that a
+	// markdown table of all violations is appended to. Empty disables it.
+	StepSummaryPath string
 }
 
 // NewGitHubActionsReporter creates a new GitHub Actions reporter.
-func NewGitHubActionsReporter(w io.Writer) *GitHubActionsReporter {
-	return &GitHubActionsReporter{writer: w}
+func NewGitHubActionsReporter(w io.Writer, opts GitHubActionsOptions) *GitHubActionsReporter {
+	return &GitHubActionsReporter{writer: w, opts: opts}
 }
 
 // Report implements Reporter.
-func (r *GitHubActionsReporter) Report(violations []rules.Violation, _ map[string][]byte, _ ReportMetadata) error {
+func (r *GitHubActionsReporter) Report(violations []rules.Violation, sources map[string][]byte, metadata ReportMetadata) error {
 	sorted := SortViolations(violations)
+	annotated := limitAnnotations(sorted, r.opts.AnnotationLimit)
 
-	for _, v := range sorted {
-		level := severityToGitHubLevel(v.Severity)
+	for _, v := range annotated {
+		if err := r.writeAnnotation(v); err != nil {
+			return err
+		}
+	}
 
-		// Normalize file path to forward slashes for consistent output
-		filePath := filepath.ToSlash(v.Location.File)
+	if omitted := len(sorted) - len(annotated); omitted > 0 {
+		where := "rerun with --annotation-limit=0 to see them"
+		if r.opts.StepSummaryPath != "" {
+			where = "see the job summary for the full list"
+		}
+		if _, err := fmt.Fprintf(r.writer,
+			"tally: %d more %s not annotated (at most %d per level); %s\n",
+			omitted, plural(omitted, "violation", "violations"), r.opts.AnnotationLimit, where,
+		); err != nil {
+			return err
+		}
+	}
 
-		// Build the annotation
-		// Format: ::{level} file={file},line={line},col={col},title={title}::{message}
-		var parts []string
-		parts = append(parts, "file="+escapeGitHubProperty(filePath))
+	if r.opts.StepSummaryPath != "" {
+		return writeStepSummary(r.opts.StepSummaryPath, violations, sources, metadata)
+	}
+	return nil
+}
 
-		if !v.Location.IsFileLevel() {
-			parts = append(parts, fmt.Sprintf("line=%d", v.Location.Start.Line))
-			if v.Location.Start.Column >= 0 {
-				parts = append(parts, fmt.Sprintf("col=%d", v.Location.Start.Column+1)) // 1-based
-			}
-			if !v.Location.IsPointLocation() && v.Location.End.Line > v.Location.Start.Line {
-				parts = append(parts, fmt.Sprintf("endLine=%d", v.Location.End.Line))
-			}
+// writeAnnotation writes one violation as a workflow command.
+func (r *GitHubActionsReporter) writeAnnotation(v rules.Violation) error {
+	level := severityToGitHubLevel(v.Severity)
+
+	// Normalize file path to forward slashes for consistent output
+	filePath := filepath.ToSlash(v.Location.File)
+
+	// Build the annotation
+	// Format: ::{level} file={file},line={line},col={col},title={title}::{message}
+	var parts []string
+	parts = append(parts, "file="+escapeGitHubProperty(filePath))
+
+	if !v.Location.IsFileLevel() {
+		parts = append(parts, fmt.Sprintf("line=%d", v.Location.Start.Line))
+		if v.Location.Start.Column >= 0 {
+			parts = append(parts, fmt.Sprintf("col=%d", v.Location.Start.Column+1)) // 1-based
+		}
+		if !v.Location.IsPointLocation() && v.Location.End.Line > v.Location.Start.Line {
+			parts = append(parts, fmt.Sprintf("endLine=%d", v.Location.End.Line))
 		}
+	}
 
-		// Add rule code as title
-		parts = append(parts, "title="+escapeGitHubProperty(v.RuleCode))
+	// Add rule code as title
+	parts = append(parts, "title="+escapeGitHubProperty(v.RuleCode))
 
-		// Escape message (newlines not allowed in workflow commands)
-		messageText := v.Message
-		if label := InvocationLabel(v); label != "" {
-			messageText = "[" + label + "] " + messageText
+	// Escape message (newlines not allowed in workflow commands)
+	messageText := v.Message
+	if label := InvocationLabel(v); label != "" {
+		messageText = "[" + label + "] " + messageText
+	}
+	message := escapeGitHubMessage(messageText)
+
+	_, err := fmt.Fprintf(r.writer, "::%s %s::%s\n",
+		level,
+		strings.Join(parts, ","),
+		message,
+	)
+	return err
+}
+
+// limitAnnotations returns the sorted violations to annotate: at most limit
+// per GitHub level, filled from the most severe violations first, in their
+// original order. A limit of zero or less keeps them all.
+func limitAnnotations(sorted []rules.Violation, limit int) []rules.Violation {
+	if limit <= 0 {
+		return sorted
+	}
+	keep := make([]bool, len(sorted))
+	used := make(map[string]int)
+	for _, sev := range []rules.Severity{rules.SeverityError, rules.SeverityWarning, rules.SeverityInfo, rules.SeverityStyle} {
+		for i, v := range sorted {
+			if v.Severity != sev {
+				continue
+			}
+			level := severityToGitHubLevel(sev)
+			if used[level] < limit {
+				used[level]++
+				keep[i] = true
+			}
 		}
-		message := escapeGitHubMessage(messageText)
+	}
 
-		if _, err := fmt.Fprintf(r.writer, "::%s %s::%s\n",
-			level,
-			strings.Join(parts, ","),
-			message,
-		); err != nil {
-			return err
+	out := make([]rules.Violation, 0, len(sorted))
+	for i, v := range sorted {
+		if keep[i] {
+			out = append(out, v)
 		}
 	}
+	return out
+}
 
-	return nil
+// writeStepSummary appends a markdown report of all violations to the job
+// summary file.
+func writeStepSummary(path string, violations []rules.Violation, sources map[string][]byte, metadata ReportMetadata) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open job summary: %w", err)
+	}
+	if _, err := fmt.Fprint(f, "## tally\n\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := NewMarkdownReporter(f).Report(violations, sources, metadata); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err := fmt.Fprintln(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
 }
 
 // GitHub Actions annotation levels.
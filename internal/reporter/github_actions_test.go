@@ -2,6 +2,9 @@ package reporter
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -34,7 +37,7 @@ func TestGitHubActionsReporter(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	reporter := NewGitHubActionsReporter(&buf)
+	reporter := NewGitHubActionsReporter(&buf, GitHubActionsOptions{})
 
 	err := reporter.Report(violations, nil, ReportMetadata{})
 	if err != nil {
@@ -104,7 +107,7 @@ func TestGitHubActionsReporterSeverityMapping(t *testing.T) {
 func TestGitHubActionsReporterEmpty(t *testing.T) {
 	t.Parallel()
 	var buf bytes.Buffer
-	reporter := NewGitHubActionsReporter(&buf)
+	reporter := NewGitHubActionsReporter(&buf, GitHubActionsOptions{})
 
 	err := reporter.Report(nil, nil, ReportMetadata{})
 	if err != nil {
@@ -131,7 +134,7 @@ func TestGitHubActionsReporterMessageEscaping(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	reporter := NewGitHubActionsReporter(&buf)
+	reporter := NewGitHubActionsReporter(&buf, GitHubActionsOptions{})
 
 	err := reporter.Report(violations, nil, ReportMetadata{})
 	if err != nil {
@@ -166,7 +169,7 @@ func TestGitHubActionsReporterPropertyEscaping(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	reporter := NewGitHubActionsReporter(&buf)
+	reporter := NewGitHubActionsReporter(&buf, GitHubActionsOptions{})
 
 	err := reporter.Report(violations, nil, ReportMetadata{})
 	if err != nil {
@@ -224,7 +227,7 @@ func TestGitHubActionsReporterSorting(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	reporter := NewGitHubActionsReporter(&buf)
+	reporter := NewGitHubActionsReporter(&buf, GitHubActionsOptions{})
 
 	err := reporter.Report(violations, nil, ReportMetadata{})
 	if err != nil {
@@ -247,3 +250,58 @@ func TestGitHubActionsReporterSorting(t *testing.T) {
 		t.Errorf("Third line should be b.Dockerfile:10, got: %s", lines[2])
 	}
 }
+
+func TestGitHubActionsReporterAnnotationLimit(t *testing.T) {
+	t.Parallel()
+	var violations []rules.Violation
+	add := func(line int, sev rules.Severity, code string) {
+		violations = append(violations, rules.Violation{
+			Location: rules.NewLineLocation("Dockerfile", line),
+			RuleCode: code,
+			Message:  code,
+			Severity: sev,
+		})
+	}
+	add(1, rules.SeverityStyle, "style-1")
+	add(2, rules.SeverityInfo, "info-1")
+	add(3, rules.SeverityStyle, "style-2")
+	add(4, rules.SeverityInfo, "info-2")
+	add(5, rules.SeverityError, "error-1")
+	add(6, rules.SeverityError, "error-2")
+	add(7, rules.SeverityError, "error-3")
+	add(8, rules.SeverityWarning, "warning-1")
+
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	var buf bytes.Buffer
+	reporter := NewGitHubActionsReporter(&buf, GitHubActionsOptions{AnnotationLimit: 2, StepSummaryPath: summary})
+	if err := reporter.Report(violations, nil, ReportMetadata{}); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+
+	var titles []string
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		_, title, _ := strings.Cut(line, "title=")
+		title, _, _ = strings.Cut(title, "::")
+		titles = append(titles, title)
+	}
+	// Info fills the notice slots before style; annotations keep file order.
+	want := []string{"info-1", "info-2", "error-1", "error-2", "warning-1"}
+	if !slices.Equal(titles, want) {
+		t.Errorf("annotated %v, want %v", titles, want)
+	}
+	if last := lines[len(lines)-1]; !strings.Contains(last, "3 more violations not annotated") ||
+		!strings.Contains(last, "job summary") {
+		t.Errorf("missing omitted note, got %q", last)
+	}
+
+	data, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"style-1", "style-2", "error-3"} {
+		if !strings.Contains(string(data), code) {
+			t.Errorf("job summary is missing %s:\n%s", code, data)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package reporter
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/textdiff"
+)
+
+//go:embed html.tmpl
+var htmlTemplateText string
+
+// htmlTemplate renders the standalone report. Styles and the severity
+// filters are inlined so the file can be shared without any assets.
+var htmlTemplate = template.Must(template.New("report").Parse(htmlTemplateText))
+
+// htmlSeverities lists the severities in filter and summary order.
+var htmlSeverities = []rules.Severity{
+	rules.SeverityError,
+	rules.SeverityWarning,
+	rules.SeverityInfo,
+	rules.SeverityStyle,
+}
+
+// HTMLReporter formats violations as a self-contained HTML page with the
+// source of every affected file, the violation ranges highlighted, and a
+// diff preview of each suggested fix.
+type HTMLReporter struct {
+	writer      io.Writer
+	toolName    string
+	toolVersion string
+}
+
+// NewHTMLReporter creates a new HTML reporter.
+func NewHTMLReporter(w io.Writer, toolName, toolVersion string) *HTMLReporter {
+	return &HTMLReporter{writer: w, toolName: toolName, toolVersion: toolVersion}
+}
+
+// htmlReport is the template data for the whole page.
+type htmlReport struct {
+	Tool       string
+	Summary    Summary
+	Severities []htmlSeverityCount
+	Files      []htmlFile
+}
+
+// htmlSeverityCount is one severity filter toggle.
+type htmlSeverityCount struct {
+	Name  string
+	Count int
+}
+
+// htmlFile is one file section: its violations and annotated source.
+type htmlFile struct {
+	ID         string
+	Path       string
+	Violations []htmlViolation
+	Lines      []htmlLine
+}
+
+// htmlViolation is one entry of a file's violation list.
+type htmlViolation struct {
+	Severity   string
+	RuleCode   string
+	DocURL     string
+	Message    string
+	Detail     string
+	Invocation string
+	Position   string
+	Anchor     string
+	Fixes      []htmlFix
+}
+
+// htmlFix is a suggested fix with its unified diff preview.
+type htmlFix struct {
+	Description string
+	Safety      string
+	Diff        []htmlDiffLine
+}
+
+// htmlDiffLine is one line of a fix preview, classed for coloring.
+type htmlDiffLine struct {
+	Class string
+	Text  string
+}
+
+// htmlLine is one source line, split into highlighted and plain segments.
+type htmlLine struct {
+	Number   int
+	Severity string
+	Segments []htmlSegment
+}
+
+// htmlSegment is a run of characters sharing the same highlight.
+type htmlSegment struct {
+	Text     string
+	Severity string
+}
+
+// Report implements Reporter.
+func (r *HTMLReporter) Report(violations []rules.Violation, sources map[string][]byte, metadata ReportMetadata) error {
+	sorted := SortViolations(violations)
+
+	byFile := make(map[string][]rules.Violation)
+	var filesOrder []string
+	for _, v := range sorted {
+		file := filepath.ToSlash(v.Location.File)
+		if _, exists := byFile[file]; !exists {
+			filesOrder = append(filesOrder, file)
+		}
+		byFile[file] = append(byFile[file], v)
+	}
+
+	report := htmlReport{
+		Tool:    strings.TrimSpace(r.toolName + " " + r.toolVersion),
+		Summary: calculateSummary(violations, len(filesOrder), metadata.InvocationsScanned),
+	}
+	for _, sev := range htmlSeverities {
+		report.Severities = append(report.Severities, htmlSeverityCount{
+			Name:  sev.String(),
+			Count: severityCount(report.Summary, sev),
+		})
+	}
+
+	for i, file := range filesOrder {
+		fileViolations := byFile[file]
+		source := sourceFor(sources, fileViolations[0].Location.File)
+		report.Files = append(report.Files, buildHTMLFile(fmt.Sprintf("f%d", i+1), file, fileViolations, source))
+	}
+
+	return htmlTemplate.Execute(r.writer, report)
+}
+
+// severityCount returns the summary count for a severity.
+func severityCount(s Summary, sev rules.Severity) int {
+	switch sev {
+	case rules.SeverityError:
+		return s.Errors
+	case rules.SeverityWarning:
+		return s.Warnings
+	case rules.SeverityInfo:
+		return s.Info
+	case rules.SeverityStyle:
+		return s.Style
+	case rules.SeverityOff:
+		return 0
+	}
+	return 0
+}
+
+// sourceFor looks a file up in the sources map, which is keyed by the
+// path as reported, trying the slash-normalized form as a fallback.
+func sourceFor(sources map[string][]byte, file string) []byte {
+	if src, ok := sources[file]; ok {
+		return src
+	}
+	return sources[filepath.ToSlash(file)]
+}
+
+// buildHTMLFile assembles the violation list and annotated source of a file.
+func buildHTMLFile(id, path string, violations []rules.Violation, source []byte) htmlFile {
+	f := htmlFile{ID: id, Path: path}
+	lines := splitSourceLines(source)
+	// marks[i][j] holds 1+severity of the most severe violation covering
+	// byte j of line i+1; 0 means the byte is not covered.
+	marks := make([][]int, len(lines))
+	lineSeverity := make([]int, len(lines))
+
+	for _, v := range violations {
+		hv := htmlViolation{
+			Severity: v.Severity.String(),
+			RuleCode: v.RuleCode,
+			DocURL:   v.DocURL,
+			Message:  v.Message,
+			Detail:   v.Detail,
+			Position: "file",
+		}
+		if v.Invocation != nil {
+			hv.Invocation = InvocationLabel(v)
+		}
+		if !v.Location.IsFileLevel() {
+			hv.Position = fmt.Sprintf("%d:%d", v.Location.Start.Line, v.Location.Start.Column+1)
+			if v.Location.Start.Line <= len(lines) {
+				hv.Anchor = fmt.Sprintf("%s-L%d", id, v.Location.Start.Line)
+			}
+			markViolation(marks, lineSeverity, lines, v)
+		}
+		hv.Fixes = htmlFixes(path, v, source)
+		f.Violations = append(f.Violations, hv)
+	}
+
+	for i, line := range lines {
+		f.Lines = append(f.Lines, htmlLine{
+			Number:   i + 1,
+			Severity: markSeverity(lineSeverity[i]),
+			Segments: lineSegments(line, marks[i]),
+		})
+	}
+	return f
+}
+
+// splitSourceLines splits source into lines without their terminators.
+func splitSourceLines(source []byte) []string {
+	if len(source) == 0 {
+		return nil
+	}
+	text := strings.TrimSuffix(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+	return strings.Split(text, "\n")
+}
+
+// markViolation records the range of v in marks, keeping the most severe
+// violation where ranges overlap. A point location marks the whole line.
+func markViolation(marks [][]int, lineSeverity []int, lines []string, v rules.Violation) {
+	rank := int(v.Severity) + 1
+	start, end := v.Location.Start, v.Location.End
+	if v.Location.IsPointLocation() || end.Line < start.Line {
+		end = rules.Position{Line: start.Line, Column: -1}
+		start.Column = 0
+	}
+	for line := max(start.Line, 1); line <= end.Line && line <= len(lines); line++ {
+		i := line - 1
+		if lineSeverity[i] == 0 || rank < lineSeverity[i] {
+			lineSeverity[i] = rank
+		}
+		from, to := 0, len(lines[i])
+		if line == start.Line {
+			from = min(max(start.Column, 0), to)
+		}
+		if line == end.Line && end.Column >= 0 {
+			to = min(end.Column, to)
+		}
+		if from >= to {
+			continue
+		}
+		if marks[i] == nil {
+			marks[i] = make([]int, len(lines[i]))
+		}
+		for j := from; j < to; j++ {
+			if marks[i][j] == 0 || rank < marks[i][j] {
+				marks[i][j] = rank
+			}
+		}
+	}
+}
+
+// lineSegments splits a line into runs of equal highlight. Run boundaries
+// are moved off UTF-8 continuation bytes so no character is split.
+func lineSegments(line string, marks []int) []htmlSegment {
+	if marks == nil {
+		return []htmlSegment{{Text: line}}
+	}
+	var segments []htmlSegment
+	start := 0
+	for j := 1; j <= len(line); j++ {
+		if j < len(line) && (marks[j] == marks[start] || !utf8.RuneStart(line[j])) {
+			continue
+		}
+		segments = append(segments, htmlSegment{Text: line[start:j], Severity: markSeverity(marks[start])})
+		start = j
+	}
+	return segments
+}
+
+// markSeverity converts a mark rank back to a severity name ("" for none).
+func markSeverity(rank int) string {
+	if rank == 0 {
+		return ""
+	}
+	return rules.Severity(rank - 1).String()
+}
+
+// htmlFixes renders a diff preview for every fix of v whose edits are
+// known. Fixes only computed during --fix, such as AI AutoFix, are left
+// out, as are edits to files whose source is not available.
+func htmlFixes(path string, v rules.Violation, source []byte) []htmlFix {
+	candidates := v.SuggestedFixes
+	if len(candidates) == 0 && v.SuggestedFix != nil {
+		candidates = []*rules.SuggestedFix{v.SuggestedFix}
+	}
+	if source == nil {
+		return nil
+	}
+
+	var fixes []htmlFix
+	for _, sf := range candidates {
+		if sf == nil || len(sf.Edits) == 0 {
+			continue
+		}
+		var edits []rules.TextEdit
+		for _, edit := range sf.Edits {
+			if edit.Location.File == "" || filepath.ToSlash(edit.Location.File) == path {
+				edits = append(edits, edit)
+			}
+		}
+		diff := textdiff.Unified(path, source, fix.ApplyEdits(source, edits))
+		if diff == "" {
+			continue
+		}
+		fixes = append(fixes, htmlFix{
+			Description: sf.Description,
+			Safety:      sf.Safety.String(),
+			Diff:        diffLines(diff),
+		})
+	}
+	return fixes
+}
+
+// diffLines classifies the lines of a unified diff for coloring.
+func diffLines(diff string) []htmlDiffLine {
+	var out []htmlDiffLine
+	for line := range strings.Lines(diff) {
+		line = strings.TrimSuffix(line, "\n")
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			class = "hdr"
+		case strings.HasPrefix(line, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+		out = append(out, htmlDiffLine{Class: class, Text: line})
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestHTMLReporterSourceAndHighlights(t *testing.T) {
+	t.Parallel()
+	source := []byte("FROM alpine AS Builder\nRUN echo <hi>\n")
+	violations := []rules.Violation{
+		{
+			Location: rules.NewRangeLocation("Dockerfile", 1, 15, 1, 22),
+			RuleCode: "StageNameCasing",
+			Message:  "Stage name 'Builder' should be lowercase",
+			Severity: rules.SeverityWarning,
+			DocURL:   "https://docs.docker.com/go/dockerfile/rule/stage-name-casing/",
+		},
+		{
+			Location: rules.NewLineLocation("Dockerfile", 2),
+			RuleCode: "tally/example",
+			Message:  "Shell <script> issue",
+			Severity: rules.SeverityError,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewHTMLReporter(&buf, "tally", "1.2.3").Report(
+		violations, map[string][]byte{"Dockerfile": source}, ReportMetadata{},
+	); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`content="tally 1.2.3"`,
+		"2 issues in 1 file",
+		`<h2>Dockerfile</h2>`,
+		`FROM alpine AS <mark class="warning">Builder</mark>`,
+		`<tr id="f1-L2" class="error">`,
+		`<mark class="error">RUN echo &lt;hi&gt;</mark>`,
+		"Shell &lt;script&gt; issue",
+		`<a href="#f1-L1">1:16</a>`,
+		`<a href="https://docs.docker.com/go/dockerfile/rule/stage-name-casing/">StageNameCasing</a>`,
+		`<input type="checkbox" id="show-error" checked>`,
+		`<li class="v-warning">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script") {
+		t.Error("message must be escaped")
+	}
+}
+
+func TestHTMLReporterFixPreview(t *testing.T) {
+	t.Parallel()
+	source := []byte("FROM alpine\nWORKDIR app\n")
+	v := rules.Violation{
+		Location: rules.NewRangeLocation("Dockerfile", 2, 8, 2, 11),
+		RuleCode: "DL3000",
+		Message:  "Use absolute WORKDIR",
+		Severity: rules.SeverityError,
+	}.WithSuggestedFix(&rules.SuggestedFix{
+		Description: "Make WORKDIR absolute",
+		Safety:      rules.FixSuggestion,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation("Dockerfile", 2, 8, 2, 11),
+			NewText:  "/app",
+		}},
+	})
+
+	var buf bytes.Buffer
+	if err := NewHTMLReporter(&buf, "tally", "dev").Report(
+		[]rules.Violation{v}, map[string][]byte{"Dockerfile": source}, ReportMetadata{},
+	); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<summary>Fix (suggestion): Make WORKDIR absolute</summary>",
+		`<span class="hdr">--- a/Dockerfile</span>`,
+		`<span class="del">-WORKDIR app</span>`,
+		`<span class="add">&#43;WORKDIR /app</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+}
+
+func TestHTMLReporterNoViolations(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := NewHTMLReporter(&buf, "tally", "dev").Report(nil, nil, ReportMetadata{}); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No issues found") {
+		t.Errorf("expected empty report message, got:\n%s", buf.String())
+	}
+}
+
+func TestLineSegmentsKeepsRunesWhole(t *testing.T) {
+	t.Parallel()
+	line := "RUN echo é"
+	marks := make([]int, len(line))
+	// Mark only the first byte of the two-byte 'é'.
+	marks[len(line)-2] = 1
+	segments := lineSegments(line, marks)
+	last := segments[len(segments)-1]
+	if last.Text != "é" || last.Severity != "error" {
+		t.Errorf("last segment = %+v, want whole rune highlighted", last)
+	}
+}
@@ -4,13 +4,24 @@ import (
 	"encoding/json/jsontext"
 	"encoding/json/v2"
 	"io"
+	"maps"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/wharflab/tally/internal/rules"
 )
 
+// JSONSchemaVersion is the version of the JSON report schema, published as
+// internal/schemas/output/tally-output.schema.json. Bump the minor version
+// when adding fields, and the major version, with a new schema, when
+// removing, renaming or retyping one.
+const JSONSchemaVersion = "1.0"
+
 // JSONOutput is the top-level structure for JSON output.
 type JSONOutput struct {
+	// SchemaVersion is the JSONSchemaVersion the report follows.
+	SchemaVersion string `json:"schemaVersion"`
 	// Files contains results grouped by file.
 	Files []FileResult `json:"files"`
 	// Summary contains aggregate statistics.
@@ -21,12 +32,51 @@ type JSONOutput struct {
 	InvocationsScanned int `json:"invocations_scanned,omitzero"`
 	// RulesEnabled is the total number of rules that were active.
 	RulesEnabled int `json:"rules_enabled"`
+	// Suppressed lists suppressed violations (--show-suppressed), each with
+	// its suppression source.
+	Suppressed []rules.Violation `json:"suppressed,omitzero"`
 }
 
 // FileResult contains the linting results for a single file.
 type FileResult struct {
 	File       string            `json:"file"`
 	Violations []rules.Violation `json:"violations"`
+	// Summary counts the file's violations by severity, in total and per
+	// build stage.
+	Summary FileSummary `json:"summary"`
+	// Parse describes the structure of the file, when it was parsed.
+	Parse *ParseStats `json:"parse,omitzero"`
+}
+
+// SeverityCounts counts violations by severity.
+type SeverityCounts struct {
+	Total    int `json:"total"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Info     int `json:"info"`
+	Style    int `json:"style"`
+}
+
+// FileSummary contains the violation counts of a single file.
+type FileSummary struct {
+	SeverityCounts `json:",inline"`
+	// Stages counts the violations of each build stage, ordered by stage
+	// index. Stages without violations, and violations outside any stage
+	// such as file-level ones, are not listed.
+	Stages []StageSummary `json:"stages,omitzero"`
+}
+
+// StageSummary contains the violation counts of a build stage.
+type StageSummary struct {
+	Index          int    `json:"index"`
+	Name           string `json:"name,omitempty"`
+	SeverityCounts `json:",inline"`
+}
+
+// ParseStats describes the structure of a parsed Dockerfile.
+type ParseStats struct {
+	StageCount       int `json:"stage_count"`
+	InstructionCount int `json:"instruction_count"`
 }
 
 // Summary contains aggregate statistics about violations.
@@ -38,6 +88,10 @@ type Summary struct {
 	Style       int `json:"style"`
 	Files       int `json:"files"`
 	Invocations int `json:"invocations,omitzero"`
+	Suppressed  int `json:"suppressed,omitzero"`
+	// Namespaces counts violations by rule namespace, e.g. "tally" or
+	// "hadolint".
+	Namespaces map[string]SeverityCounts `json:"namespaces,omitzero"`
 }
 
 // JSONReporter formats violations as JSON output.
@@ -55,65 +109,140 @@ func (r *JSONReporter) Report(violations []rules.Violation, _ map[string][]byte,
 	// Group violations by file (deterministic order)
 	// Normalize paths to forward slashes for cross-platform consistency
 	byFile := make(map[string][]rules.Violation)
-	filesOrder := make([]string, 0)
-
 	for _, v := range SortViolations(violations) {
 		// Normalize file path in location for consistent output
 		v.Location.File = filepath.ToSlash(v.Location.File)
-		file := v.Location.File
-		if _, exists := byFile[file]; !exists {
-			filesOrder = append(filesOrder, file)
+		byFile[v.Location.File] = append(byFile[v.Location.File], v)
+	}
+
+	// Every linted file is listed, including files without violations.
+	parseStats := make(map[string]*ParseStats, len(metadata.Files))
+	for file, stats := range metadata.Files {
+		parseStats[filepath.ToSlash(file)] = &ParseStats{
+			StageCount:       stats.Stages,
+			InstructionCount: stats.Instructions,
 		}
-		byFile[file] = append(byFile[file], v)
 	}
 
 	// Build output structure
 	output := JSONOutput{
-		Files:              make([]FileResult, 0, len(filesOrder)),
-		Summary:            calculateSummary(violations, len(filesOrder), metadata.InvocationsScanned),
+		SchemaVersion:      JSONSchemaVersion,
+		Files:              fileResults(byFile, parseStats),
+		Summary:            calculateSummary(violations, len(byFile), metadata.InvocationsScanned),
 		FilesScanned:       metadata.FilesScanned,
 		InvocationsScanned: metadata.InvocationsScanned,
 		RulesEnabled:       metadata.RulesEnabled,
 	}
-
-	for _, file := range filesOrder {
-		output.Files = append(output.Files, FileResult{
-			File:       file,
-			Violations: byFile[file],
-		})
+	if len(metadata.Suppressed) > 0 {
+		output.Summary.Suppressed = len(metadata.Suppressed)
+		for _, v := range SortViolations(metadata.Suppressed) {
+			v.Location.File = filepath.ToSlash(v.Location.File)
+			output.Suppressed = append(output.Suppressed, v)
+		}
 	}
 
 	return json.MarshalWrite(
 		r.writer,
 		output,
+		json.Deterministic(true),
 		jsontext.EscapeForHTML(true),
 		jsontext.WithIndentPrefix(""),
 		jsontext.WithIndent("  "),
 	)
 }
 
+// fileResults builds the results of every file that has violations or parse
+// statistics, ordered by path. Files without violations get zero counts.
+func fileResults(byFile map[string][]rules.Violation, parseStats map[string]*ParseStats) []FileResult {
+	files := slices.Collect(maps.Keys(byFile))
+	for file := range parseStats {
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
+		}
+	}
+	slices.Sort(files)
+
+	results := make([]FileResult, 0, len(files))
+	for _, file := range files {
+		violations := byFile[file]
+		if violations == nil {
+			violations = []rules.Violation{}
+		}
+		results = append(results, newFileResult(file, violations, parseStats[file]))
+	}
+	return results
+}
+
+// newFileResult builds the result of a file from its sorted violations.
+func newFileResult(file string, violations []rules.Violation, parse *ParseStats) FileResult {
+	result := FileResult{File: file, Violations: violations, Parse: parse}
+	for _, v := range violations {
+		result.Summary.add(v.Severity)
+		if v.Metadata == nil || v.Metadata.Stage == nil {
+			continue
+		}
+		stage := v.Metadata.Stage
+		i, found := slices.BinarySearchFunc(result.Summary.Stages, stage.Index, func(s StageSummary, index int) int {
+			return s.Index - index
+		})
+		if !found {
+			result.Summary.Stages = slices.Insert(result.Summary.Stages, i,
+				StageSummary{Index: stage.Index, Name: stage.Name})
+		}
+		result.Summary.Stages[i].add(v.Severity)
+	}
+	return result
+}
+
 // calculateSummary computes aggregate statistics from violations.
 func calculateSummary(violations []rules.Violation, fileCount, invocationCount int) Summary {
+	var counts SeverityCounts
+	for _, v := range violations {
+		counts.add(v.Severity)
+	}
 	summary := Summary{
-		Total:       len(violations),
+		Total:       counts.Total,
+		Errors:      counts.Errors,
+		Warnings:    counts.Warnings,
+		Info:        counts.Info,
+		Style:       counts.Style,
 		Files:       fileCount,
 		Invocations: invocationCount,
 	}
 
 	for _, v := range violations {
-		switch v.Severity {
-		case rules.SeverityError:
-			summary.Errors++
-		case rules.SeverityWarning:
-			summary.Warnings++
-		case rules.SeverityInfo:
-			summary.Info++
-		case rules.SeverityStyle:
-			summary.Style++
-		case rules.SeverityOff:
-			// Should never reach here - filtered by EnableFilter
+		if summary.Namespaces == nil {
+			summary.Namespaces = make(map[string]SeverityCounts)
 		}
+		ns := ruleNamespace(v.RuleCode)
+		nsCounts := summary.Namespaces[ns]
+		nsCounts.add(v.Severity)
+		summary.Namespaces[ns] = nsCounts
 	}
 
 	return summary
 }
+
+// add counts a violation of the given severity.
+func (c *SeverityCounts) add(severity rules.Severity) {
+	c.Total++
+	switch severity {
+	case rules.SeverityError:
+		c.Errors++
+	case rules.SeverityWarning:
+		c.Warnings++
+	case rules.SeverityInfo:
+		c.Info++
+	case rules.SeverityStyle:
+		c.Style++
+	case rules.SeverityOff:
+		// Should never reach here - filtered by EnableFilter
+	}
+}
+
+// ruleNamespace returns the namespace of a rule code: "tally" for
+// "tally/ruby/missing-bundle-deployment", the code itself when it has none.
+func ruleNamespace(code string) string {
+	ns, _, _ := strings.Cut(code, "/")
+	return ns
+}
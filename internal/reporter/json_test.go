@@ -3,6 +3,7 @@ package reporter
 import (
 	"bytes"
 	"encoding/json/v2"
+	"reflect"
 	"testing"
 
 	"github.com/wharflab/tally/internal/rules"
@@ -159,3 +160,134 @@ func TestJSONReporterEmpty(t *testing.T) {
 		t.Errorf("Expected total 0, got %d", output.Summary.Total)
 	}
 }
+
+func TestJSONReporterSuppressed(t *testing.T) {
+	t.Parallel()
+	suppressed := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 1), "hadolint/DL3006", "tag the image", rules.SeverityWarning).
+			WithSuppression(rules.SuppressionInline, "# tally ignore=DL3006"),
+	}
+
+	var buf bytes.Buffer
+	if err := NewJSONReporter(&buf).Report(nil, nil, ReportMetadata{Suppressed: suppressed}); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if output.Summary.Total != 0 || output.Summary.Suppressed != 1 {
+		t.Errorf("summary = %+v, want 0 total and 1 suppressed", output.Summary)
+	}
+	if len(output.Suppressed) != 1 {
+		t.Fatalf("got %d suppressed, want 1", len(output.Suppressed))
+	}
+	if s := output.Suppressed[0].Suppression; s == nil || s.Source != rules.SuppressionInline ||
+		s.Justification != "# tally ignore=DL3006" {
+		t.Errorf("suppression = %+v", s)
+	}
+}
+
+func TestJSONReporterSummaries(t *testing.T) {
+	t.Parallel()
+	inStage := func(v rules.Violation, index int, name string) rules.Violation {
+		v.Metadata = &rules.ViolationMetadata{Stage: &rules.ViolationStage{Index: index, Name: name}}
+		return v
+	}
+	violations := []rules.Violation{
+		inStage(rules.NewViolation(rules.NewLineLocation("Dockerfile", 8), "hadolint/DL3008", "pin versions",
+			rules.SeverityWarning), 1, ""),
+		inStage(rules.NewViolation(rules.NewLineLocation("Dockerfile", 2), "tally/prefer-add-unpack", "use ADD",
+			rules.SeverityInfo), 0, "build"),
+		inStage(rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3003", "use WORKDIR",
+			rules.SeverityWarning), 0, "build"),
+		rules.NewViolation(rules.NewFileLocation("Dockerfile"), "tally/max-lines", "too long", rules.SeverityError),
+	}
+
+	var buf bytes.Buffer
+	err := NewJSONReporter(&buf).Report(violations, nil, ReportMetadata{
+		Files: map[string]FileStats{"Dockerfile": {Stages: 2, Instructions: 9}},
+	})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(output.Files) != 1 {
+		t.Fatalf("got %d files, want 1", len(output.Files))
+	}
+	file := output.Files[0]
+	if want := (SeverityCounts{Total: 4, Errors: 1, Warnings: 2, Info: 1}); file.Summary.SeverityCounts != want {
+		t.Errorf("file summary = %+v, want %+v", file.Summary.SeverityCounts, want)
+	}
+	// The file-level violation belongs to no stage.
+	wantStages := []StageSummary{
+		{Index: 0, Name: "build", SeverityCounts: SeverityCounts{Total: 2, Warnings: 1, Info: 1}},
+		{Index: 1, SeverityCounts: SeverityCounts{Total: 1, Warnings: 1}},
+	}
+	if !reflect.DeepEqual(file.Summary.Stages, wantStages) {
+		t.Errorf("stage summaries = %+v, want %+v", file.Summary.Stages, wantStages)
+	}
+	if file.Parse == nil || *file.Parse != (ParseStats{StageCount: 2, InstructionCount: 9}) {
+		t.Errorf("parse = %+v, want 2 stages and 9 instructions", file.Parse)
+	}
+	wantNamespaces := map[string]SeverityCounts{
+		"hadolint": {Total: 2, Warnings: 2},
+		"tally":    {Total: 2, Errors: 1, Info: 1},
+	}
+	if !reflect.DeepEqual(output.Summary.Namespaces, wantNamespaces) {
+		t.Errorf("namespaces = %+v, want %+v", output.Summary.Namespaces, wantNamespaces)
+	}
+}
+
+func TestJSONReporterCleanFiles(t *testing.T) {
+	t.Parallel()
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("b/Dockerfile", 2), "hadolint/DL3008", "pin versions",
+			rules.SeverityWarning),
+	}
+
+	var buf bytes.Buffer
+	err := NewJSONReporter(&buf).Report(violations, nil, ReportMetadata{
+		FilesScanned: 2,
+		Files: map[string]FileStats{
+			"b/Dockerfile": {Stages: 1, Instructions: 3},
+			"a/Dockerfile": {Stages: 2, Instructions: 5},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	var output JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(output.Files) != 2 {
+		t.Fatalf("got %d files, want 2", len(output.Files))
+	}
+	clean := output.Files[0]
+	if clean.File != "a/Dockerfile" {
+		t.Errorf("files[0] = %q, want the clean file a/Dockerfile first", clean.File)
+	}
+	if clean.Violations == nil || len(clean.Violations) != 0 {
+		t.Errorf("clean file violations = %#v, want an empty list", clean.Violations)
+	}
+	if clean.Summary.SeverityCounts != (SeverityCounts{}) || clean.Summary.Stages != nil {
+		t.Errorf("clean file summary = %+v, want zero counts", clean.Summary)
+	}
+	if clean.Parse == nil || *clean.Parse != (ParseStats{StageCount: 2, InstructionCount: 5}) {
+		t.Errorf("clean file parse = %+v, want 2 stages and 5 instructions", clean.Parse)
+	}
+	if output.Files[1].File != "b/Dockerfile" || output.Files[1].Summary.Total != 1 {
+		t.Errorf("files[1] = %+v, want b/Dockerfile with 1 violation", output.Files[1])
+	}
+	// summary.files counts files with violations.
+	if output.Summary.Files != 1 {
+		t.Errorf("summary.files = %d, want 1", output.Summary.Files)
+	}
+}
@@ -0,0 +1,336 @@
+package reporter
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// MergeInput is one report to merge, as written by "tally lint".
+type MergeInput struct {
+	// Name identifies the report in errors, usually its file path.
+	Name string
+	// Data is the report content.
+	Data []byte
+}
+
+// Merge merges tally JSON or SARIF reports into one report of the same
+// format and writes it to w. Every input must have the same format.
+// Findings reported by more than one input, e.g. by overlapping shards,
+// are kept once. It returns the format of the merged report.
+func Merge(w io.Writer, inputs []MergeInput) (Format, error) {
+	if len(inputs) == 0 {
+		return "", errors.New("no reports to merge")
+	}
+
+	var format Format
+	for _, in := range inputs {
+		f, err := detectReportFormat(in.Data)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", in.Name, err)
+		}
+		if format != "" && f != format {
+			return "", fmt.Errorf("%s: cannot merge %s with %s reports", in.Name, f, format)
+		}
+		format = f
+	}
+
+	switch format {
+	case FormatJSON:
+		outputs := make([]JSONOutput, 0, len(inputs))
+		for _, in := range inputs {
+			var out JSONOutput
+			if err := json.Unmarshal(in.Data, &out); err != nil {
+				return "", fmt.Errorf("%s: parse JSON report: %w", in.Name, err)
+			}
+			if !compatibleSchemaVersion(out.SchemaVersion) {
+				return "", fmt.Errorf("%s: unsupported JSON report schema version %q, want %s",
+					in.Name, out.SchemaVersion, JSONSchemaVersion)
+			}
+			outputs = append(outputs, out)
+		}
+		return format, json.MarshalWrite(
+			w,
+			MergeJSON(outputs),
+			json.Deterministic(true),
+			jsontext.EscapeForHTML(true),
+			jsontext.WithIndentPrefix(""),
+			jsontext.WithIndent("  "),
+		)
+	case FormatSARIF:
+		reports := make([]*sarif.Report, 0, len(inputs))
+		for _, in := range inputs {
+			report, err := sarif.FromBytes(in.Data)
+			if err != nil {
+				return "", fmt.Errorf("%s: parse SARIF report: %w", in.Name, err)
+			}
+			reports = append(reports, report)
+		}
+		return format, MergeSARIF(reports).PrettyWrite(w)
+	default:
+		return "", fmt.Errorf("cannot merge %s reports", format)
+	}
+}
+
+// detectReportFormat tells tally JSON reports from SARIF logs.
+func detectReportFormat(data []byte) (Format, error) {
+	var probe struct {
+		Version *string         `json:"version"`
+		Runs    jsontext.Value  `json:"runs"`
+		Files   jsontext.Value  `json:"files"`
+		Summary *jsontext.Value `json:"summary"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &probe); err != nil {
+		return "", fmt.Errorf("not a JSON or SARIF report: %w", err)
+	}
+	switch {
+	case probe.Runs != nil && probe.Version != nil:
+		return FormatSARIF, nil
+	case probe.Files != nil && probe.Summary != nil:
+		return FormatJSON, nil
+	default:
+		return "", errors.New("not a tally JSON or SARIF report")
+	}
+}
+
+// MergeJSON merges JSON reports. Violations are deduplicated by
+// ViolationFingerprint and the summary is recomputed from the merged
+// violations. Scan counts are added up; RulesEnabled is the largest of the
+// inputs.
+func MergeJSON(outputs []JSONOutput) JSONOutput {
+	var (
+		violations, suppressed []rules.Violation
+		seen                   = make(map[string]struct{})
+		seenSuppressed         = make(map[string]struct{})
+		parseStats             = make(map[string]*ParseStats)
+		merged                 = JSONOutput{SchemaVersion: JSONSchemaVersion}
+	)
+	for _, out := range outputs {
+		for _, file := range out.Files {
+			// Every input file is listed, with the first parse statistics seen.
+			if parseStats[file.File] == nil {
+				parseStats[file.File] = file.Parse
+			}
+			for _, v := range file.Violations {
+				if v.Location.File == "" {
+					v.Location.File = file.File
+				}
+				if key := ViolationFingerprint(v); !setAdd(seen, key) {
+					continue
+				}
+				violations = append(violations, v)
+			}
+		}
+		for _, v := range out.Suppressed {
+			if key := ViolationFingerprint(v); !setAdd(seenSuppressed, key) {
+				continue
+			}
+			suppressed = append(suppressed, v)
+		}
+		merged.FilesScanned += out.FilesScanned
+		merged.InvocationsScanned += out.InvocationsScanned
+		merged.RulesEnabled = max(merged.RulesEnabled, out.RulesEnabled)
+	}
+
+	violations = SortViolations(violations)
+	byFile := make(map[string][]rules.Violation)
+	for _, v := range violations {
+		byFile[v.Location.File] = append(byFile[v.Location.File], v)
+	}
+	merged.Files = fileResults(byFile, parseStats)
+	merged.Summary = calculateSummary(violations, len(byFile), merged.InvocationsScanned)
+	if len(suppressed) > 0 {
+		merged.Suppressed = SortViolations(suppressed)
+		merged.Summary.Suppressed = len(suppressed)
+	}
+	return merged
+}
+
+// compatibleSchemaVersion reports whether a JSON report of the given schema
+// version can be read: one of the same major version, or one written before
+// reports were versioned.
+func compatibleSchemaVersion(version string) bool {
+	if version == "" {
+		return true
+	}
+	major, _, _ := strings.Cut(version, ".")
+	currentMajor, _, _ := strings.Cut(JSONSchemaVersion, ".")
+	return major == currentMajor
+}
+
+// ViolationFingerprint identifies a finding across reports: the rule, file,
+// range, message and build invocation.
+func ViolationFingerprint(v rules.Violation) string {
+	var inv string
+	if v.Invocation != nil {
+		inv = v.Invocation.Kind + "\x00" + v.Invocation.File + "\x00" + v.Invocation.Name
+	}
+	loc := v.Location
+	return fingerprint(
+		v.RuleCode, loc.File,
+		strconv.Itoa(loc.Start.Line), strconv.Itoa(loc.Start.Column),
+		strconv.Itoa(loc.End.Line), strconv.Itoa(loc.End.Column),
+		v.Message, inv,
+	)
+}
+
+// MergeSARIF merges SARIF logs. Runs of the same tool are combined into one
+// run with the union of their rules and artifacts, and results are
+// deduplicated by their fingerprints, or by rule, locations, message and
+// properties when they have none.
+func MergeSARIF(reports []*sarif.Report) *sarif.Report {
+	merged := sarif.NewReport()
+	runs := make(map[string]*sarif.Run)
+	seen := make(map[*sarif.Run]map[string]struct{})
+	for i, report := range reports {
+		if i == 0 {
+			merged.Schema = cmp.Or(report.Schema, merged.Schema)
+		}
+		for _, run := range report.Runs {
+			if run == nil {
+				continue
+			}
+			tool := sarifToolName(run)
+			target, ok := runs[tool]
+			if !ok {
+				// The first run of each tool keeps its tool and properties.
+				target = sarif.NewRun()
+				target.Tool = run.Tool
+				target.Properties = run.Properties
+				runs[tool] = target
+				seen[target] = make(map[string]struct{})
+				merged.AddRun(target)
+			}
+			mergeSARIFRun(target, run, seen[target])
+		}
+	}
+	return merged
+}
+
+func mergeSARIFRun(target, run *sarif.Run, seen map[string]struct{}) {
+	if run.Tool != nil && run.Tool.Driver != nil && target.Tool != nil && target.Tool.Driver != nil {
+		for _, rule := range run.Tool.Driver.Rules {
+			if rule == nil || rule.ID == nil || target.GetRuleIndex(*rule.ID) >= 0 {
+				continue
+			}
+			target.Tool.Driver.Rules = append(target.Tool.Driver.Rules, rule)
+		}
+	}
+	for _, artifact := range run.Artifacts {
+		if artifact == nil || artifact.Location == nil || artifact.Location.URI == nil {
+			continue
+		}
+		target.AddDistinctArtifact(*artifact.Location.URI)
+	}
+
+	for _, result := range run.Results {
+		if result == nil || !setAdd(seen, sarifResultFingerprint(result)) {
+			continue
+		}
+		// Indexes point into the source run; re-point them at the merged one.
+		if result.RuleIndex >= 0 && result.RuleID != nil {
+			result.RuleIndex = target.GetRuleIndex(*result.RuleID)
+		}
+		for _, loc := range result.Locations {
+			if loc == nil || loc.PhysicalLocation == nil || loc.PhysicalLocation.ArtifactLocation == nil {
+				continue
+			}
+			if al := loc.PhysicalLocation.ArtifactLocation; al.Index >= 0 {
+				al.Index = sarifArtifactIndex(target, al.URI)
+			}
+		}
+		target.AddResult(result)
+	}
+}
+
+func sarifToolName(run *sarif.Run) string {
+	if run.Tool == nil || run.Tool.Driver == nil || run.Tool.Driver.Name == nil {
+		return ""
+	}
+	return *run.Tool.Driver.Name
+}
+
+func sarifArtifactIndex(run *sarif.Run, uri *string) int {
+	if uri == nil {
+		return -1
+	}
+	return slices.IndexFunc(run.Artifacts, func(a *sarif.Artifact) bool {
+		return a != nil && a.Location != nil && a.Location.URI != nil && *a.Location.URI == *uri
+	})
+}
+
+func sarifResultFingerprint(result *sarif.Result) string {
+	for _, prints := range []map[string]string{result.Fingerprints, result.PartialFingerprints} {
+		if len(prints) == 0 {
+			continue
+		}
+		parts := []string{"fingerprints"}
+		for _, k := range slices.Sorted(maps.Keys(prints)) {
+			parts = append(parts, k, prints[k])
+		}
+		return fingerprint(parts...)
+	}
+
+	var rule, message string
+	if result.RuleID != nil {
+		rule = *result.RuleID
+	}
+	if result.Message != nil && result.Message.Text != nil {
+		message = *result.Message.Text
+	}
+	parts := []string{rule, message}
+	for _, loc := range result.Locations {
+		if loc == nil || loc.PhysicalLocation == nil {
+			continue
+		}
+		pl := loc.PhysicalLocation
+		if pl.ArtifactLocation != nil && pl.ArtifactLocation.URI != nil {
+			parts = append(parts, *pl.ArtifactLocation.URI)
+		}
+		if r := pl.Region; r != nil {
+			parts = append(parts, intPtrString(r.StartLine), intPtrString(r.StartColumn),
+				intPtrString(r.EndLine), intPtrString(r.EndColumn))
+		}
+	}
+	if result.Properties != nil {
+		if props, err := json.Marshal(result.Properties.Properties, json.Deterministic(true)); err == nil {
+			parts = append(parts, string(props))
+		}
+	}
+	return fingerprint(parts...)
+}
+
+func intPtrString(p *int) string {
+	if p == nil {
+		return ""
+	}
+	return strconv.Itoa(*p)
+}
+
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// setAdd adds key to set and reports whether it was not there yet.
+func setAdd(set map[string]struct{}, key string) bool {
+	if _, ok := set[key]; ok {
+		return false
+	}
+	set[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,215 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func mergeTestViolations() []rules.Violation {
+	return []rules.Violation{
+		{
+			Location: rules.NewRangeLocation("api/Dockerfile", 2, 0, 2, 10),
+			RuleCode: "buildkit/MaintainerDeprecated",
+			Message:  "Maintainer instruction is deprecated",
+			Severity: rules.SeverityWarning,
+		},
+		{
+			Location: rules.NewLineLocation("web/Dockerfile", 1),
+			RuleCode: "hadolint/DL3006",
+			Message:  "Always tag the version of an image explicitly",
+			Severity: rules.SeverityError,
+		},
+	}
+}
+
+// writeShard renders violations in format as a merge input.
+func writeShard(t *testing.T, format Format, name string, violations []rules.Violation, metadata ReportMetadata) MergeInput {
+	t.Helper()
+	var buf bytes.Buffer
+	rep, err := New(Options{Format: format, Writer: &buf, ToolName: "tally", ToolVersion: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rep.Report(violations, nil, metadata); err != nil {
+		t.Fatal(err)
+	}
+	return MergeInput{Name: name, Data: buf.Bytes()}
+}
+
+func TestMergeJSON(t *testing.T) {
+	t.Parallel()
+
+	vs := mergeTestViolations()
+	inputs := []MergeInput{
+		writeShard(t, FormatJSON, "api.json", vs[:1], ReportMetadata{FilesScanned: 1, RulesEnabled: 100}),
+		// The second shard overlaps the first one on api/Dockerfile.
+		writeShard(t, FormatJSON, "web.json", vs, ReportMetadata{
+			FilesScanned: 2,
+			RulesEnabled: 120,
+			Files:        map[string]FileStats{"web/Dockerfile": {Stages: 1, Instructions: 3}},
+		}),
+	}
+
+	var buf bytes.Buffer
+	format, err := Merge(&buf, inputs)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if format != FormatJSON {
+		t.Errorf("format = %q, want json", format)
+	}
+
+	var merged JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &merged); err != nil {
+		t.Fatalf("parse merged report: %v\n%s", err, buf.String())
+	}
+	want := Summary{
+		Total: 2, Errors: 1, Warnings: 1, Files: 2,
+		Namespaces: map[string]SeverityCounts{
+			"buildkit": {Total: 1, Warnings: 1},
+			"hadolint": {Total: 1, Errors: 1},
+		},
+	}
+	if !reflect.DeepEqual(merged.Summary, want) {
+		t.Errorf("summary = %+v, want %+v", merged.Summary, want)
+	}
+	if len(merged.Files) != 2 || merged.Files[0].File != "api/Dockerfile" || len(merged.Files[0].Violations) != 1 {
+		t.Errorf("files = %+v, want api/Dockerfile then web/Dockerfile with one violation each", merged.Files)
+	}
+	if len(merged.Files) == 2 {
+		if got := merged.Files[1].Parse; got == nil || *got != (ParseStats{StageCount: 1, InstructionCount: 3}) {
+			t.Errorf("web/Dockerfile parse = %+v, want the stats of the shard that linted it", got)
+		}
+		if got := merged.Files[1].Summary.SeverityCounts; got != (SeverityCounts{Total: 1, Errors: 1}) {
+			t.Errorf("web/Dockerfile summary = %+v, want 1 error", got)
+		}
+	}
+	if merged.FilesScanned != 3 || merged.RulesEnabled != 120 {
+		t.Errorf("files_scanned = %d, rules_enabled = %d; want 3 and 120", merged.FilesScanned, merged.RulesEnabled)
+	}
+}
+
+func TestMergeJSONKeepsCleanFiles(t *testing.T) {
+	t.Parallel()
+
+	vs := mergeTestViolations()
+	inputs := []MergeInput{
+		writeShard(t, FormatJSON, "api.json", vs[:1], ReportMetadata{FilesScanned: 1}),
+		writeShard(t, FormatJSON, "ops.json", nil, ReportMetadata{
+			FilesScanned: 1,
+			Files:        map[string]FileStats{"ops/Dockerfile": {Stages: 1, Instructions: 2}},
+		}),
+	}
+
+	var buf bytes.Buffer
+	if _, err := Merge(&buf, inputs); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	var merged JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &merged); err != nil {
+		t.Fatalf("parse merged report: %v\n%s", err, buf.String())
+	}
+	if len(merged.Files) != 2 || merged.Files[1].File != "ops/Dockerfile" {
+		t.Fatalf("files = %+v, want api/Dockerfile then the clean ops/Dockerfile", merged.Files)
+	}
+	clean := merged.Files[1]
+	if clean.Summary.Total != 0 || clean.Parse == nil || clean.Parse.InstructionCount != 2 {
+		t.Errorf("ops/Dockerfile = %+v, want zero counts and its parse stats", clean)
+	}
+	if merged.Summary.Files != 1 {
+		t.Errorf("summary.files = %d, want 1", merged.Summary.Files)
+	}
+}
+
+func TestMergeSARIF(t *testing.T) {
+	t.Parallel()
+
+	vs := mergeTestViolations()
+	inputs := []MergeInput{
+		writeShard(t, FormatSARIF, "api.sarif", vs[:1], ReportMetadata{}),
+		writeShard(t, FormatSARIF, "web.sarif", vs, ReportMetadata{}),
+	}
+
+	var buf bytes.Buffer
+	if _, err := Merge(&buf, inputs); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	merged, err := sarif.FromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("parse merged report: %v", err)
+	}
+	if len(merged.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(merged.Runs))
+	}
+	run := merged.Runs[0]
+	if len(run.Results) != 2 || len(run.Tool.Driver.Rules) != 2 || len(run.Artifacts) != 2 {
+		t.Errorf("got %d results, %d rules, %d artifacts; want 2 of each",
+			len(run.Results), len(run.Tool.Driver.Rules), len(run.Artifacts))
+	}
+}
+
+func TestMergeRejectsMixedFormats(t *testing.T) {
+	t.Parallel()
+
+	vs := mergeTestViolations()
+	inputs := []MergeInput{
+		writeShard(t, FormatJSON, "a.json", vs, ReportMetadata{}),
+		writeShard(t, FormatSARIF, "b.sarif", vs, ReportMetadata{}),
+	}
+	if _, err := Merge(&bytes.Buffer{}, inputs); err == nil || !strings.Contains(err.Error(), "b.sarif") {
+		t.Errorf("Merge() error = %v, want an error naming b.sarif", err)
+	}
+
+	_, err := Merge(&bytes.Buffer{}, []MergeInput{{Name: "notes.txt", Data: []byte("not a report")}})
+	if err == nil || !strings.Contains(err.Error(), "notes.txt") {
+		t.Errorf("Merge() error = %v, want an error naming notes.txt", err)
+	}
+}
+
+func TestMergeJSONSchemaVersions(t *testing.T) {
+	t.Parallel()
+
+	current := writeShard(t, FormatJSON, "current.json", mergeTestViolations(), ReportMetadata{})
+	unversioned := MergeInput{Name: "old.json", Data: []byte(`{"files": [], "summary": {}, "files_scanned": 1}`)}
+	newer := MergeInput{Name: "newer.json", Data: []byte(`{"schemaVersion": "1.7", "files": [], "summary": {}, "extra": true}`)}
+
+	var buf bytes.Buffer
+	if _, err := Merge(&buf, []MergeInput{current, unversioned, newer}); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	var merged JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &merged); err != nil {
+		t.Fatalf("parse merged report: %v", err)
+	}
+	if merged.SchemaVersion != JSONSchemaVersion {
+		t.Errorf("schemaVersion = %q, want %q", merged.SchemaVersion, JSONSchemaVersion)
+	}
+
+	nextMajor := MergeInput{Name: "next.json", Data: []byte(`{"schemaVersion": "2.0", "files": [], "summary": {}}`)}
+	if _, err := Merge(&bytes.Buffer{}, []MergeInput{current, nextMajor}); err == nil ||
+		!strings.Contains(err.Error(), "next.json") {
+		t.Errorf("Merge() error = %v, want an error naming next.json", err)
+	}
+}
+
+func TestViolationFingerprint(t *testing.T) {
+	t.Parallel()
+
+	a := mergeTestViolations()[0]
+	b := a
+	b.Detail = "details do not change the finding"
+	if ViolationFingerprint(a) != ViolationFingerprint(b) {
+		t.Error("fingerprint changed with the detail")
+	}
+	b.Location.Start.Line = 3
+	if ViolationFingerprint(a) == ViolationFingerprint(b) {
+		t.Error("fingerprint did not change with the location")
+	}
+}
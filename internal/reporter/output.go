@@ -0,0 +1,155 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// Output path placeholders. An output path containing any of them is a
+// template that ReportPerFile expands once per linted file.
+const (
+	// PlaceholderPath is the file path relative to the working directory,
+	// e.g. "services/api/Dockerfile".
+	PlaceholderPath = "{path}"
+	// PlaceholderName is the file name, e.g. "Dockerfile".
+	PlaceholderName = "{name}"
+	// PlaceholderDir is the name of the directory containing the file, e.g. "api".
+	PlaceholderDir = "{dir}"
+	// PlaceholderHash is a short, stable hash of the absolute file path.
+	PlaceholderHash = "{hash}"
+)
+
+var outputPlaceholders = []string{PlaceholderPath, PlaceholderName, PlaceholderDir, PlaceholderHash}
+
+// IsOutputTemplate reports whether path contains an output placeholder.
+func IsOutputTemplate(path string) bool {
+	return slices.ContainsFunc(outputPlaceholders, func(p string) bool {
+		return strings.Contains(path, p)
+	})
+}
+
+// ExpandOutputPath expands the placeholders of template for file.
+func ExpandOutputPath(template, file string) string {
+	native := filepath.FromSlash(file)
+	abs, err := filepath.Abs(native)
+	if err != nil {
+		abs = native
+	}
+	rel := native
+	if cwd, err := os.Getwd(); err == nil {
+		if r, err := filepath.Rel(cwd, abs); err == nil {
+			rel = r
+		}
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		// Keep reports for files outside the working directory below the
+		// template's directory instead of climbing out of it.
+		rel = strings.TrimLeft(strings.TrimPrefix(abs, filepath.VolumeName(abs)), `/\`)
+	}
+	sum := sha256.Sum256([]byte(filepath.ToSlash(abs)))
+
+	return strings.NewReplacer(
+		PlaceholderPath, filepath.ToSlash(rel),
+		PlaceholderName, filepath.Base(abs),
+		PlaceholderDir, filepath.Base(filepath.Dir(abs)),
+		PlaceholderHash, hex.EncodeToString(sum[:])[:12],
+	).Replace(template)
+}
+
+// ReportPerFile writes one report per linted file to the path template
+// expands to for that file, creating parent directories as needed. Each
+// report holds the violations of its file only; files without violations
+// get an empty report. opts.Writer is ignored.
+func ReportPerFile(
+	opts Options, template string,
+	violations []rules.Violation, sources map[string][]byte, metadata ReportMetadata,
+) error {
+	byFile := make(map[string][]rules.Violation)
+	for path := range sources {
+		byFile[filepath.ToSlash(path)] = nil
+	}
+	for _, v := range violations {
+		file := filepath.ToSlash(v.Location.File)
+		byFile[file] = append(byFile[file], v)
+	}
+	suppressedByFile := make(map[string][]rules.Violation)
+	for _, v := range metadata.Suppressed {
+		file := filepath.ToSlash(v.Location.File)
+		suppressedByFile[file] = append(suppressedByFile[file], v)
+	}
+	sourcesByFile := make(map[string][]byte, len(sources))
+	for path, src := range sources {
+		sourcesByFile[filepath.ToSlash(path)] = src
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	slices.Sort(files)
+
+	outputs := make(map[string]string, len(files))
+	for _, file := range files {
+		out := ExpandOutputPath(template, file)
+		if other, ok := outputs[out]; ok {
+			return fmt.Errorf("output template %q writes both %s and %s to %s; add {path} or {hash}",
+				template, other, file, out)
+		}
+		outputs[out] = file
+	}
+
+	for _, out := range slices.Sorted(maps.Keys(outputs)) {
+		file := outputs[out]
+		fileSources := map[string][]byte{}
+		if src, ok := sourcesByFile[file]; ok {
+			fileSources[file] = src
+		}
+		fileMetadata := metadata
+		fileMetadata.FilesScanned = 1
+		fileMetadata.Suppressed = suppressedByFile[file]
+		if err := reportToFile(opts, out, byFile[file], fileSources, fileMetadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reportToFile(
+	opts Options, path string,
+	violations []rules.Violation, sources map[string][]byte, metadata ReportMetadata,
+) (err error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	writer, closeWriter, err := GetWriter(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := closeWriter(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+	}()
+
+	opts.Writer = writer
+	rep, err := New(opts)
+	if err != nil {
+		return err
+	}
+	if err := rep.Report(violations, sources, metadata); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
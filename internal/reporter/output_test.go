@@ -0,0 +1,114 @@
+package reporter
+
+import (
+	"encoding/json/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func TestIsOutputTemplate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]bool{
+		"stdout":                false,
+		"results.sarif":         false,
+		"reports/{name}.sarif":  true,
+		"reports/{path}.json":   true,
+		"{dir}-{hash}.sarif":    true,
+		"reports/{unknown}.txt": false,
+	}
+	for path, want := range tests {
+		if got := IsOutputTemplate(path); got != want {
+			t.Errorf("IsOutputTemplate(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestExpandOutputPath(t *testing.T) {
+	t.Parallel()
+
+	got := ExpandOutputPath("reports/{dir}/{name}-{path}.sarif", "services/api/Dockerfile")
+	if want := "reports/api/Dockerfile-services/api/Dockerfile.sarif"; got != want {
+		t.Errorf("ExpandOutputPath() = %q, want %q", got, want)
+	}
+
+	hash := ExpandOutputPath("{hash}", "services/api/Dockerfile")
+	if len(hash) != 12 || hash != ExpandOutputPath("{hash}", "./services/api/Dockerfile") {
+		t.Errorf("hash %q is not a stable 12-character hash of the path", hash)
+	}
+	if hash == ExpandOutputPath("{hash}", "services/web/Dockerfile") {
+		t.Error("different files share a hash")
+	}
+
+	// Files outside the working directory do not climb out of the template's directory.
+	outside := filepath.Join(t.TempDir(), "Dockerfile")
+	if got := ExpandOutputPath("reports/{path}", outside); strings.Contains(got, "..") {
+		t.Errorf("ExpandOutputPath() = %q, want a path below reports/", got)
+	}
+}
+
+func TestReportPerFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	violations := []rules.Violation{
+		{
+			Location: rules.NewLineLocation("services/api/Dockerfile", 2),
+			RuleCode: "buildkit/MaintainerDeprecated",
+			Message:  "Maintainer instruction is deprecated",
+			Severity: rules.SeverityWarning,
+		},
+	}
+	sources := map[string][]byte{
+		"services/api/Dockerfile": []byte("FROM alpine\nMAINTAINER me\n"),
+		"services/web/Dockerfile": []byte("FROM alpine\n"),
+	}
+
+	template := filepath.Join(dir, "reports", "{dir}.json")
+	err := ReportPerFile(Options{Format: FormatJSON}, template, violations, sources, ReportMetadata{FilesScanned: 2})
+	if err != nil {
+		t.Fatalf("ReportPerFile() error = %v", err)
+	}
+
+	read := func(name string) (files, scanned int) {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, "reports", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report struct {
+			Files        []any `json:"files"`
+			FilesScanned int   `json:"files_scanned"`
+		}
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		return len(report.Files), report.FilesScanned
+	}
+	if files, scanned := read("api.json"); files != 1 || scanned != 1 {
+		t.Errorf("api.json has %d files, %d scanned; want 1 and 1", files, scanned)
+	}
+	if files, scanned := read("web.json"); files != 0 || scanned != 1 {
+		t.Errorf("web.json has %d files, %d scanned; want 0 and 1", files, scanned)
+	}
+}
+
+func TestReportPerFileRejectsCollisions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sources := map[string][]byte{
+		"services/api/Dockerfile": []byte("FROM alpine\n"),
+		"services/web/Dockerfile": []byte("FROM alpine\n"),
+	}
+	err := ReportPerFile(Options{Format: FormatJSON}, filepath.Join(dir, "{name}.json"), nil, sources, ReportMetadata{})
+	if err == nil || !strings.Contains(err.Error(), "writes both") {
+		t.Fatalf("ReportPerFile() error = %v, want a collision error", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("reports were written despite the collision: %v", entries)
+	}
+}
@@ -6,6 +6,8 @@
 //   - sarif: Static Analysis Results Interchange Format for CI/CD integration
 //   - github-actions: Native GitHub Actions workflow annotations
 //   - markdown: Concise markdown tables for AI agents
+//   - html: Standalone HTML report with source view and fix previews
+//   - tap: Test Anything Protocol for TAP-consuming test harnesses
 package reporter
 
 import (
@@ -28,6 +30,21 @@ type ReportMetadata struct {
 	InvocationsScanned int
 	// RulesEnabled is the total number of rules that were active (not "off").
 	RulesEnabled int
+	// Suppressed lists the violations suppressed by inline directives or rule
+	// configuration, each with its Suppression set. Only populated with
+	// --show-suppressed; the text, JSON and SARIF reporters list them.
+	Suppressed []rules.Violation
+	// Files holds parse statistics of the linted files, keyed by the path
+	// violations report. The JSON reporter includes them per file.
+	Files map[string]FileStats
+}
+
+// FileStats describes the structure of a parsed Dockerfile.
+type FileStats struct {
+	// Stages is the number of build stages (FROM instructions).
+	Stages int
+	// Instructions is the number of top-level instructions.
+	Instructions int
 }
 
 // Reporter formats and outputs lint violations.
@@ -82,6 +99,10 @@ const (
 	FormatGitHubActions Format = "github-actions"
 	// FormatMarkdown is concise markdown tables for AI agents.
 	FormatMarkdown Format = "markdown"
+	// FormatHTML is a self-contained HTML report for sharing.
+	FormatHTML Format = "html"
+	// FormatTAP is Test Anything Protocol (version 13) output.
+	FormatTAP Format = "tap"
 )
 
 // formatEntry maps input aliases to a canonical Format.
@@ -98,6 +119,8 @@ var formats = []formatEntry{
 	{canonical: FormatSARIF},
 	{canonical: FormatGitHubActions, aliases: []string{"github"}},
 	{canonical: FormatMarkdown, aliases: []string{"md"}},
+	{canonical: FormatHTML},
+	{canonical: FormatTAP},
 }
 
 // ValidFormatsUsage returns a comma-separated list of canonical format names
@@ -127,6 +150,44 @@ func ParseFormat(s string) (Format, error) {
 	return "", fmt.Errorf("unknown format: %q (valid: %s)", s, ValidFormatsUsage())
 }
 
+// GroupBy selects how the text reporter groups violations.
+type GroupBy string
+
+const (
+	// GroupByNone prints violations in file and line order without group headers.
+	GroupByNone GroupBy = "none"
+	// GroupByFile groups violations per Dockerfile.
+	GroupByFile GroupBy = "file"
+	// GroupByRule groups violations per rule code, most frequent rule first.
+	GroupByRule GroupBy = "rule"
+	// GroupBySeverity groups violations per severity, most severe first.
+	GroupBySeverity GroupBy = "severity"
+)
+
+// groupByModes lists the accepted GroupBy values in help-text order.
+var groupByModes = []GroupBy{GroupByNone, GroupByFile, GroupByRule, GroupBySeverity}
+
+// ValidGroupByUsage returns a comma-separated list of GroupBy values
+// suitable for CLI help text and error messages.
+func ValidGroupByUsage() string {
+	names := make([]string, len(groupByModes))
+	for i, g := range groupByModes {
+		names[i] = string(g)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseGroupBy parses a group-by string. An empty string means GroupByNone.
+func ParseGroupBy(s string) (GroupBy, error) {
+	if s == "" {
+		return GroupByNone, nil
+	}
+	if g := GroupBy(s); slices.Contains(groupByModes, g) {
+		return g, nil
+	}
+	return "", fmt.Errorf("unknown group-by: %q (valid: %s)", s, ValidGroupByUsage())
+}
+
 // Options configures reporter creation.
 type Options struct {
 	// Format specifies the output format.
@@ -142,10 +203,24 @@ type Options struct {
 	// ShowSource enables source code snippets (text format only).
 	ShowSource bool
 
-	// ToolVersion is included in SARIF output.
+	// ShowFixes renders suggested fixes as inline diffs (text format only).
+	ShowFixes bool
+
+	// GroupBy groups violations under per-group headers (text format), or
+	// selects what a test point stands for (tap format).
+	GroupBy GroupBy
+
+	// AnnotationLimit caps annotations per level (github-actions format only).
+	AnnotationLimit int
+
+	// StepSummaryPath is the GitHub job summary file to append a full
+	// markdown report to (github-actions format only).
+	StepSummaryPath string
+
+	// ToolVersion is included in SARIF and HTML output.
 	ToolVersion string
 
-	// ToolName is the tool name for SARIF output.
+	// ToolName is the tool name for SARIF and HTML output.
 	ToolName string
 
 	// ToolURI is the tool information URI for SARIF output.
@@ -178,6 +253,8 @@ func New(opts Options) (Reporter, error) {
 			// Enable syntax highlighting when color is auto-detected (nil) or explicitly enabled
 			SyntaxHighlight: opts.Color == nil || *opts.Color,
 			ShowSource:      opts.ShowSource,
+			ShowFixes:       opts.ShowFixes,
+			GroupBy:         opts.GroupBy,
 		}
 		return &textReporterAdapter{
 			reporter: NewTextReporter(textOpts),
@@ -191,11 +268,20 @@ func New(opts Options) (Reporter, error) {
 		return NewSARIFReporter(opts.Writer, opts.ToolName, opts.ToolVersion, opts.ToolURI), nil
 
 	case FormatGitHubActions:
-		return NewGitHubActionsReporter(opts.Writer), nil
+		return NewGitHubActionsReporter(opts.Writer, GitHubActionsOptions{
+			AnnotationLimit: opts.AnnotationLimit,
+			StepSummaryPath: opts.StepSummaryPath,
+		}), nil
 
 	case FormatMarkdown:
 		return NewMarkdownReporter(opts.Writer), nil
 
+	case FormatHTML:
+		return NewHTMLReporter(opts.Writer, opts.ToolName, opts.ToolVersion), nil
+
+	case FormatTAP:
+		return NewTAPReporter(opts.Writer, opts.GroupBy), nil
+
 	default:
 		return nil, fmt.Errorf("unknown format: %q", opts.Format)
 	}
@@ -20,6 +20,8 @@ func TestParseFormat(t *testing.T) {
 		{"sarif", FormatSARIF, false},
 		{"github-actions", FormatGitHubActions, false},
 		{"github", FormatGitHubActions, false},
+		{"html", FormatHTML, false},
+		{"tap", FormatTAP, false},
 		{"unknown", "", true},
 		{"TEXT", "", true}, // Case sensitive
 	}
@@ -38,6 +40,32 @@ func TestParseFormat(t *testing.T) {
 	}
 }
 
+func TestParseGroupBy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input    string
+		expected GroupBy
+		wantErr  bool
+	}{
+		{"", GroupByNone, false},
+		{"none", GroupByNone, false},
+		{"file", GroupByFile, false},
+		{"rule", GroupByRule, false},
+		{"severity", GroupBySeverity, false},
+		{"stage", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseGroupBy(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseGroupBy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.expected {
+			t.Errorf("ParseGroupBy(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -3,6 +3,7 @@ package reporter
 import (
 	"io"
 	"path/filepath"
+	"slices"
 	"sort"
 
 	"github.com/owenrumney/go-sarif/v3/pkg/report/v210/sarif"
@@ -45,7 +46,11 @@ func NewSARIFReporter(w io.Writer, toolName, toolVersion, toolURI string) *SARIF
 }
 
 // Report implements Reporter.
-func (r *SARIFReporter) Report(violations []rules.Violation, _ map[string][]byte, _ ReportMetadata) error {
+//
+// Suppressed violations in metadata (--show-suppressed) are reported as
+// results with a suppressions entry: kind "inSource" for inline directives
+// and "external" for rule configuration.
+func (r *SARIFReporter) Report(violations []rules.Violation, _ map[string][]byte, metadata ReportMetadata) error {
 	// Create a new SARIF report (v2.1.0 for maximum compatibility)
 	report := sarif.NewReport()
 	report.Schema = "https://schemastore.azurewebsites.net/schemas/json/sarif-2.1.0-rtm.5.json"
@@ -56,6 +61,10 @@ func (r *SARIFReporter) Report(violations []rules.Violation, _ map[string][]byte
 		run.Tool.Driver.WithVersion(r.toolVersion)
 	}
 
+	if len(metadata.Suppressed) > 0 {
+		violations = append(slices.Clip(violations), SortViolations(metadata.Suppressed)...)
+	}
+
 	// Collect unique rule codes and files
 	ruleSet := make(map[string]rules.Violation)
 	fileSet := make(map[string]struct{})
@@ -155,6 +164,13 @@ func (r *SARIFReporter) Report(violations []rules.Violation, _ map[string][]byte
 			})
 		}
 
+		if fixes := sarifFixes(v); len(fixes) > 0 {
+			result.WithFixes(fixes)
+		}
+		if v.Suppression != nil {
+			result.AddSuppression(sarifSuppression(v.Suppression))
+		}
+
 		run.AddResult(result)
 	}
 
@@ -164,11 +180,93 @@ func (r *SARIFReporter) Report(violations []rules.Violation, _ map[string][]byte
 	return report.PrettyWrite(r.writer)
 }
 
+// sarifSuppression converts a violation's suppression to a SARIF suppression.
+func sarifSuppression(s *rules.Suppression) *sarif.Suppression {
+	kind := "external"
+	if s.Source == rules.SuppressionInline {
+		kind = "inSource"
+	}
+	out := sarif.NewSuppression().
+		WithKind(kind).
+		WithProperties(sarif.NewPropertyBag().Add("source", string(s.Source)))
+	if s.Justification != "" {
+		out.WithJustification(s.Justification)
+	}
+	return out
+}
+
+// sarifFixes converts a violation's suggested fixes to SARIF fix objects,
+// one artifactChange per edited file. Fixes whose edits are only computed
+// during --fix (async resolvers, AI) have nothing to convert and are omitted.
+func sarifFixes(v rules.Violation) []*sarif.Fix {
+	candidates := v.SuggestedFixes
+	if len(candidates) == 0 && v.SuggestedFix != nil {
+		candidates = []*rules.SuggestedFix{v.SuggestedFix}
+	}
+
+	var fixes []*sarif.Fix
+	for _, sf := range candidates {
+		if sf == nil || len(sf.Edits) == 0 {
+			continue
+		}
+		changes := make(map[string]*sarif.ArtifactChange)
+		var files []string
+		for _, edit := range sf.Edits {
+			file := filepath.ToSlash(edit.Location.File)
+			if file == "" {
+				file = filepath.ToSlash(v.Location.File)
+			}
+			change, ok := changes[file]
+			if !ok {
+				change = sarif.NewArtifactChange().
+					WithArtifactLocation(sarif.NewSimpleArtifactLocation(file))
+				changes[file] = change
+				files = append(files, file)
+			}
+			change.AddReplacement(sarifReplacement(edit))
+		}
+
+		fix := sarif.NewFix().
+			WithProperties(sarif.NewPropertyBag().Add("safety", sf.Safety.String()))
+		if sf.Description != "" {
+			fix.WithDescription(sarif.NewTextMessage(sf.Description))
+		}
+		for _, file := range files {
+			fix.AddArtifactChange(changes[file])
+		}
+		fixes = append(fixes, fix)
+	}
+	return fixes
+}
+
+// sarifReplacement converts a TextEdit to a SARIF replacement. Both use
+// 1-based lines; tally columns are 0-based while SARIF's are 1-based, with
+// the end column pointing just past the region. An insertion is an empty
+// region (start equal to end) and a deletion has no inserted content.
+func sarifReplacement(edit rules.TextEdit) *sarif.Replacement {
+	loc := edit.Location
+	end := loc.End
+	if loc.IsPointLocation() {
+		end = loc.Start
+	}
+	region := sarif.NewRegion().
+		WithStartLine(loc.Start.Line).
+		WithStartColumn(loc.Start.Column + 1).
+		WithEndLine(end.Line).
+		WithEndColumn(end.Column + 1)
+	replacement := sarif.NewReplacement().WithDeletedRegion(region)
+	if edit.NewText != "" {
+		replacement.WithInsertedContent(sarif.NewArtifactContent().WithText(edit.NewText))
+	}
+	return replacement
+}
+
 // SARIF severity levels.
 const (
 	sarifLevelError   = "error"
 	sarifLevelWarning = "warning"
 	sarifLevelNote    = "note"
+	sarifLevelNone    = "none"
 )
 
 // severityToSARIFLevel maps our Severity to SARIF levels.
@@ -182,8 +280,8 @@ func severityToSARIFLevel(s rules.Severity) string {
 	case rules.SeverityInfo, rules.SeverityStyle:
 		return sarifLevelNote
 	case rules.SeverityOff:
-		// Should never reach here - filtered by EnableFilter
-		return sarifLevelNote
+		// Only suppressed violations (--show-suppressed) are still "off" here.
+		return sarifLevelNone
 	default:
 		return sarifLevelWarning
 	}
@@ -315,3 +315,151 @@ func TestSARIFReporterFileLevelViolation(t *testing.T) {
 		t.Error("Expected artifactLocation in physical location")
 	}
 }
+
+func TestSARIFReporterFixes(t *testing.T) {
+	t.Parallel()
+	violations := []rules.Violation{
+		{
+			Location: rules.NewLineLocation("Dockerfile", 2),
+			RuleCode: "buildkit/StageNameCasing",
+			Message:  "Stage name 'Builder' should be lowercase",
+			Severity: rules.SeverityWarning,
+			SuggestedFix: &rules.SuggestedFix{
+				Description: "Rename stage to builder",
+				Safety:      rules.FixSafe,
+				Edits: []rules.TextEdit{
+					{Location: rules.NewRangeLocation("Dockerfile", 2, 15, 2, 22), NewText: "builder"},
+					{Location: rules.NewRangeLocation("Dockerfile", 3, 0, 4, 0)},
+				},
+			},
+		},
+		{
+			Location: rules.NewLineLocation("Dockerfile", 1),
+			RuleCode: "hadolint/DL3006",
+			Message:  "Always tag the version of an image explicitly",
+			Severity: rules.SeverityWarning,
+			SuggestedFix: &rules.SuggestedFix{
+				Description:  "Pin the image",
+				NeedsResolve: true,
+				ResolverID:   "image-digest",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewSARIFReporter(&buf, "", "", "").Report(violations, nil, ReportMetadata{}); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	var out struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Fixes  []struct {
+					Description struct {
+						Text string `json:"text"`
+					} `json:"description"`
+					Properties      map[string]any `json:"properties"`
+					ArtifactChanges []struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Replacements []struct {
+							DeletedRegion struct {
+								StartLine   int `json:"startLine"`
+								StartColumn int `json:"startColumn"`
+								EndLine     int `json:"endLine"`
+								EndColumn   int `json:"endColumn"`
+							} `json:"deletedRegion"`
+							InsertedContent *struct {
+								Text string `json:"text"`
+							} `json:"insertedContent"`
+						} `json:"replacements"`
+					} `json:"artifactChanges"`
+				} `json:"fixes"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Failed to parse SARIF output: %v\nOutput: %s", err, buf.String())
+	}
+
+	results := out.Runs[0].Results
+	if len(results[1].Fixes) != 0 {
+		t.Errorf("unresolved async fix should be omitted, got %+v", results[1].Fixes)
+	}
+	if len(results[0].Fixes) != 1 {
+		t.Fatalf("got %d fixes, want 1", len(results[0].Fixes))
+	}
+	fix := results[0].Fixes[0]
+	if fix.Description.Text != "Rename stage to builder" || fix.Properties["safety"] != "safe" {
+		t.Errorf("fix description/properties = %q, %v", fix.Description.Text, fix.Properties)
+	}
+	if len(fix.ArtifactChanges) != 1 || fix.ArtifactChanges[0].ArtifactLocation.URI != "Dockerfile" {
+		t.Fatalf("artifactChanges = %+v", fix.ArtifactChanges)
+	}
+	reps := fix.ArtifactChanges[0].Replacements
+	if len(reps) != 2 {
+		t.Fatalf("got %d replacements, want 2", len(reps))
+	}
+	if r := reps[0]; r.DeletedRegion.StartLine != 2 || r.DeletedRegion.StartColumn != 16 ||
+		r.DeletedRegion.EndLine != 2 || r.DeletedRegion.EndColumn != 23 ||
+		r.InsertedContent == nil || r.InsertedContent.Text != "builder" {
+		t.Errorf("replacement = %+v", r)
+	}
+	if r := reps[1]; r.DeletedRegion.StartLine != 3 || r.DeletedRegion.StartColumn != 1 ||
+		r.DeletedRegion.EndLine != 4 || r.DeletedRegion.EndColumn != 1 || r.InsertedContent != nil {
+		t.Errorf("line deletion = %+v", r)
+	}
+}
+
+func TestSARIFReporterSuppressed(t *testing.T) {
+	t.Parallel()
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3008", "pin apt", rules.SeverityWarning),
+	}
+	suppressed := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 1), "hadolint/DL3006", "tag the image", rules.SeverityWarning).
+			WithSuppression(rules.SuppressionInline, "# tally ignore=DL3006;reason=pinned upstream"),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 4), "buildkit/MaintainerDeprecated", "deprecated", rules.SeverityOff).
+			WithSuppression(rules.SuppressionConfig, `severity = "off"`),
+	}
+
+	var buf bytes.Buffer
+	err := NewSARIFReporter(&buf, "", "", "").Report(violations, nil, ReportMetadata{Suppressed: suppressed})
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	var out struct {
+		Runs []struct {
+			Results []struct {
+				RuleID       string `json:"ruleId"`
+				Level        string `json:"level"`
+				Suppressions []struct {
+					Kind          string `json:"kind"`
+					Justification string `json:"justification"`
+				} `json:"suppressions"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Failed to parse SARIF output: %v\nOutput: %s", err, buf.String())
+	}
+
+	results := out.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if len(results[0].Suppressions) != 0 {
+		t.Errorf("reported violation has suppressions: %+v", results[0].Suppressions)
+	}
+	if s := results[1].Suppressions; results[1].RuleID != "hadolint/DL3006" || len(s) != 1 ||
+		s[0].Kind != "inSource" || s[0].Justification != "# tally ignore=DL3006;reason=pinned upstream" {
+		t.Errorf("inline suppression = %+v", results[1])
+	}
+	if s := results[2].Suppressions; results[2].Level != "none" || len(s) != 1 ||
+		s[0].Kind != "external" || s[0].Justification != `severity = "off"` {
+		t.Errorf("config suppression = %+v", results[2])
+	}
+}
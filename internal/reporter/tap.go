@@ -0,0 +1,167 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	yaml "go.yaml.in/yaml/v4"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// TAPReporter formats results as TAP version 13 (Test Anything Protocol).
+// Each file is a test point that passes when it has no violations; with
+// GroupByRule or GroupBySeverity each rule or severity is one instead.
+// The violations of a failing test point are listed in its YAML diagnostic.
+type TAPReporter struct {
+	writer  io.Writer
+	groupBy GroupBy
+}
+
+// NewTAPReporter creates a new TAP reporter.
+func NewTAPReporter(w io.Writer, groupBy GroupBy) *TAPReporter {
+	if groupBy == GroupByNone || groupBy == "" {
+		groupBy = GroupByFile
+	}
+	return &TAPReporter{writer: w, groupBy: groupBy}
+}
+
+// tapDiagnostic is the YAML block following a "not ok" test point.
+type tapDiagnostic struct {
+	Message  string  `yaml:"message"`
+	Severity string  `yaml:"severity"`
+	Data     tapData `yaml:"data"`
+}
+
+// tapData holds the violations of a failing test point.
+type tapData struct {
+	Violations []tapViolation `yaml:"violations"`
+}
+
+// tapViolation is one violation in a TAP diagnostic.
+type tapViolation struct {
+	Rule       string `yaml:"rule"`
+	Severity   string `yaml:"severity"`
+	Message    string `yaml:"message"`
+	File       string `yaml:"file"`
+	Line       int    `yaml:"line,omitempty"`
+	Column     int    `yaml:"column,omitempty"`
+	Invocation string `yaml:"invocation,omitempty"`
+	URL        string `yaml:"url,omitempty"`
+}
+
+// Report implements Reporter.
+func (r *TAPReporter) Report(violations []rules.Violation, sources map[string][]byte, _ ReportMetadata) error {
+	sorted := SortViolations(violations)
+	for i := range sorted {
+		sorted[i].Location.File = filepath.ToSlash(sorted[i].Location.File)
+	}
+	groups := groupViolations(sorted, r.groupBy)
+	if r.groupBy == GroupByFile {
+		groups = withCleanFiles(groups, sources)
+	}
+
+	if _, err := fmt.Fprintf(r.writer, "TAP version 13\n1..%d\n", len(groups)); err != nil {
+		return err
+	}
+	for i, g := range groups {
+		if err := r.writeTestPoint(i+1, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withCleanFiles adds a passing group for every linted file without
+// violations, and orders all file groups by path.
+func withCleanFiles(groups []violationGroup, sources map[string][]byte) []violationGroup {
+	seen := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		seen[g.key] = struct{}{}
+	}
+	for file := range sources {
+		file = filepath.ToSlash(file)
+		if _, ok := seen[file]; !ok {
+			seen[file] = struct{}{}
+			groups = append(groups, violationGroup{key: file})
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b violationGroup) int {
+		return strings.Compare(a.key, b.key)
+	})
+	return groups
+}
+
+// writeTestPoint writes one "ok"/"not ok" line and, for failures, its
+// indented YAML diagnostic block.
+func (r *TAPReporter) writeTestPoint(n int, g violationGroup) error {
+	if len(g.violations) == 0 {
+		_, err := fmt.Fprintf(r.writer, "ok %d - %s\n", n, tapDescription(g.key))
+		return err
+	}
+	if _, err := fmt.Fprintf(r.writer, "not ok %d - %s\n", n, tapDescription(g.key)); err != nil {
+		return err
+	}
+
+	diag := tapDiagnostic{
+		Message:  groupCounts(g, r.groupBy),
+		Severity: mostSevere(g.violations).String(),
+	}
+	for _, v := range g.violations {
+		tv := tapViolation{
+			Rule:     v.RuleCode,
+			Severity: v.Severity.String(),
+			Message:  v.Message,
+			File:     v.Location.File,
+			URL:      v.DocURL,
+		}
+		if !v.Location.IsFileLevel() {
+			tv.Line = v.Location.Start.Line
+			tv.Column = v.Location.Start.Column + 1
+		}
+		if v.Invocation != nil {
+			tv.Invocation = InvocationLabel(v)
+		}
+		diag.Data.Violations = append(diag.Data.Violations, tv)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(diag); err != nil {
+		_ = enc.Close()
+		return fmt.Errorf("failed to encode TAP diagnostic: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode TAP diagnostic: %w", err)
+	}
+	var b strings.Builder
+	b.WriteString("  ---\n")
+	for line := range strings.Lines(out.String()) {
+		b.WriteString("  " + line)
+	}
+	b.WriteString("  ...\n")
+	_, err := io.WriteString(r.writer, b.String())
+	return err
+}
+
+// tapDescription escapes the characters TAP gives meaning to in a test
+// point description: "#" starts a directive.
+func tapDescription(s string) string {
+	return strings.ReplaceAll(s, "#", `\#`)
+}
+
+// mostSevere returns the highest severity among violations.
+func mostSevere(violations []rules.Violation) rules.Severity {
+	sev := rules.SeverityStyle
+	for _, v := range violations {
+		if v.Severity.IsMoreSevereThan(sev) {
+			sev = v.Severity
+		}
+	}
+	return sev
+}
@@ -0,0 +1,133 @@
+package reporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+func tapTestViolations() []rules.Violation {
+	return []rules.Violation{
+		{
+			Location: rules.NewLineLocation("app/Dockerfile", 2),
+			RuleCode: "DL3000",
+			Message:  "Use absolute WORKDIR",
+			Severity: rules.SeverityError,
+			DocURL:   "https://github.com/hadolint/hadolint/wiki/DL3000",
+		},
+		{
+			Location: rules.NewLineLocation("app/Dockerfile", 1),
+			RuleCode: "StageNameCasing",
+			Message:  "Stage name 'Builder' should be lowercase",
+			Severity: rules.SeverityWarning,
+		},
+		{
+			Location: rules.NewFileLocation("web/Dockerfile"),
+			RuleCode: "tally/max-lines",
+			Message:  "file has 120 lines # too many",
+			Severity: rules.SeverityWarning,
+		},
+	}
+}
+
+func TestTAPReporterPerFile(t *testing.T) {
+	t.Parallel()
+	sources := map[string][]byte{
+		"app/Dockerfile":   nil,
+		"clean/Dockerfile": nil,
+		"web/Dockerfile":   nil,
+	}
+
+	var buf bytes.Buffer
+	if err := NewTAPReporter(&buf, GroupByNone).Report(tapTestViolations(), sources, ReportMetadata{}); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	want := `TAP version 13
+1..3
+not ok 1 - app/Dockerfile
+  ---
+  message: 2 violations (1 error, 1 warning)
+  severity: error
+  data:
+    violations:
+      - rule: StageNameCasing
+        severity: warning
+        message: Stage name 'Builder' should be lowercase
+        file: app/Dockerfile
+        line: 1
+        column: 1
+      - rule: DL3000
+        severity: error
+        message: Use absolute WORKDIR
+        file: app/Dockerfile
+        line: 2
+        column: 1
+        url: https://github.com/hadolint/hadolint/wiki/DL3000
+  ...
+ok 2 - clean/Dockerfile
+not ok 3 - web/Dockerfile
+  ---
+  message: 1 violation (1 warning)
+  severity: warning
+  data:
+    violations:
+      - rule: tally/max-lines
+        severity: warning
+        message: 'file has 120 lines # too many'
+        file: web/Dockerfile
+  ...
+`
+	if got := buf.String(); got != want {
+		t.Errorf("output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTAPReporterPerRule(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := NewTAPReporter(&buf, GroupByRule).Report(tapTestViolations(), nil, ReportMetadata{}); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	want := []string{
+		"TAP version 13",
+		"1..3",
+		"not ok 1 - DL3000",
+		"not ok 2 - StageNameCasing",
+		"not ok 3 - tally/max-lines",
+	}
+	var got []string
+	for _, line := range bytes.Split(buf.Bytes(), []byte("\n")) {
+		if len(line) > 0 && line[0] != ' ' {
+			got = append(got, string(line))
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("test lines = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTAPReporterNoFiles(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := NewTAPReporter(&buf, GroupByFile).Report(nil, nil, ReportMetadata{}); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if got, want := buf.String(), "TAP version 13\n1..0\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestTAPDescriptionEscapesHash(t *testing.T) {
+	t.Parallel()
+	if got, want := tapDescription("stage#1/Dockerfile"), `stage\#1/Dockerfile`; got != want {
+		t.Errorf("tapDescription() = %q, want %q", got, want)
+	}
+}
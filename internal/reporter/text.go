@@ -92,8 +92,16 @@ type TextOptions struct {
 	// ShowSource shows source code snippets. Default: true.
 	ShowSource bool
 
+	// ShowFixes shows the preferred suggested fix of each violation as an
+	// inline diff. Default: false.
+	ShowFixes bool
+
 	// Theme controls color palette selection for snippets: auto, dark, or light.
 	Theme string
+
+	// GroupBy groups violations by file, rule or severity, with a header
+	// and count per group and a summary at the end. Default: no grouping.
+	GroupBy GroupBy
 }
 
 // DefaultTextOptions returns sensible defaults for text output.
@@ -141,16 +149,19 @@ func (r *TextReporter) PrintReport(w io.Writer, violations []rules.Violation, so
 	r.docCache = make(map[string]*highlight.Document, len(sources))
 	sorted := SortViolations(violations)
 
-	lastLabel := ""
-	for _, v := range sorted {
-		label := InvocationLabel(v)
-		if err := emitLabelHeaderIfChanged(w, label, &lastLabel); err != nil {
+	switch r.opts.GroupBy {
+	case GroupByFile, GroupByRule, GroupBySeverity:
+		if err := r.printGroups(w, groupViolations(sorted, r.opts.GroupBy), sources); err != nil {
 			return err
 		}
-		if err := r.printViolation(w, v, sources[v.Location.File]); err != nil {
+	default:
+		if err := r.printViolations(w, sorted, sources); err != nil {
 			return err
 		}
 	}
+	if err := r.printSuppressed(w, metadata.Suppressed); err != nil {
+		return err
+	}
 	if metadata.InvocationsScanned > 0 {
 		if _, err := fmt.Fprintf(w, "\nSummary: %d %s, %d %s, %d %s.\n",
 			metadata.FilesScanned,
@@ -166,6 +177,54 @@ func (r *TextReporter) PrintReport(w io.Writer, violations []rules.Violation, so
 	return nil
 }
 
+// printViolations prints sorted violations, with a header each time the
+// invocation label changes.
+func (r *TextReporter) printViolations(w io.Writer, sorted []rules.Violation, sources map[string][]byte) error {
+	lastLabel := ""
+	for _, v := range sorted {
+		label := InvocationLabel(v)
+		if err := emitLabelHeaderIfChanged(w, label, &lastLabel); err != nil {
+			return err
+		}
+		if err := r.printViolation(w, v, sources[v.Location.File]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printSuppressed lists suppressed violations compactly, one per location,
+// each followed by the directive or setting that suppressed it.
+func (r *TextReporter) printSuppressed(w io.Writer, suppressed []rules.Violation) error {
+	if len(suppressed) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "\nSuppressed (%d):\n", len(suppressed)); err != nil {
+		return err
+	}
+	for _, v := range SortViolations(suppressed) {
+		loc := v.Location.File
+		if !v.Location.IsFileLevel() {
+			loc = fmt.Sprintf("%s:%d", loc, v.Location.Start.Line)
+		}
+		code := v.RuleCode
+		by := "suppressed"
+		if s := v.Suppression; s != nil {
+			by = "suppressed by " + string(s.Source)
+			if s.Justification != "" {
+				by += ": " + s.Justification
+			}
+		}
+		if r.colorEnabled {
+			loc, code, by = fileLocStyle.Render(loc), ruleCodeStyle.Render(code), lineNumStyle.Render(by)
+		}
+		if _, err := fmt.Fprintf(w, "  %s  %s  %s\n    %s\n", loc, code, v.Message, by); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func emitLabelHeaderIfChanged(w io.Writer, label string, lastLabel *string) error {
 	if label == "" {
 		*lastLabel = ""
@@ -234,6 +293,9 @@ func (r *TextReporter) printViolation(w io.Writer, v rules.Violation, source []b
 		}
 	}
 
+	if r.opts.ShowFixes {
+		return r.printFix(w, v, source)
+	}
 	return nil
 }
 
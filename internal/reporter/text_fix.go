@@ -0,0 +1,147 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+var (
+	// Fix header style
+	fixHeaderStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42")) // Green
+
+	// Removed line style in fix diffs
+	diffDelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")) // Red
+
+	// Added line style in fix diffs
+	diffAddStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("42")) // Green
+)
+
+// fixDiffLine is one line of an inline fix diff.
+type fixDiffLine struct {
+	num     int
+	added   bool
+	content string
+}
+
+// printFix renders the preferred fix of v as an inline diff of the lines it
+// changes, so the result of --fix can be reviewed before applying it. Fixes
+// whose edits are only computed during --fix, such as image digests and AI
+// AutoFix, show their description alone.
+func (r *TextReporter) printFix(w io.Writer, v rules.Violation, source []byte) error {
+	sf := v.PreferredFix()
+	if sf == nil {
+		return nil
+	}
+
+	header := fmt.Sprintf("Fix (%s): %s", sf.Safety, sf.Description)
+	if len(sf.Edits) == 0 {
+		if !sf.NeedsResolve {
+			return nil
+		}
+		header += " (computed when applied)"
+	}
+	if r.colorEnabled {
+		header = fixHeaderStyle.Render(header)
+	}
+	if _, err := fmt.Fprintf(w, "\n%s\n", header); err != nil {
+		return err
+	}
+
+	for _, line := range r.fixDiff(v.Location.File, sf, source) {
+		if err := r.writeDiffLine(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixDiff returns the lines the fix removes, followed by the lines it adds.
+// Edits to other files are left out.
+func (r *TextReporter) fixDiff(file string, sf *rules.SuggestedFix, source []byte) []fixDiffLine {
+	if len(source) == 0 {
+		return nil
+	}
+	var edits []rules.TextEdit
+	start, end := 0, 0
+	for _, edit := range sf.Edits {
+		if edit.Location.File != "" && filepath.ToSlash(edit.Location.File) != filepath.ToSlash(file) {
+			continue
+		}
+		loc := edit.Location
+		if loc.Start.Line < 1 {
+			continue
+		}
+		editEnd := max(loc.End.Line, loc.Start.Line)
+		// An edit ending at column 0 stops before that line.
+		if loc.End.Column == 0 && editEnd > loc.Start.Line {
+			editEnd--
+		}
+		if len(edits) == 0 || loc.Start.Line < start {
+			start = loc.Start.Line
+		}
+		end = max(end, editEnd)
+		edits = append(edits, edit)
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	before := r.highlightDocument(file, source).SourceMap.Lines()
+	after := sourcemap.New(fix.ApplyEdits(source, edits)).Lines()
+	if start > len(before) {
+		return nil
+	}
+	end = min(end, len(before))
+	afterEnd := end + len(after) - len(before)
+	if afterEnd < start-1 || afterEnd > len(after) {
+		return nil
+	}
+	removed := before[start-1 : end]
+	added := after[start-1 : afterEnd]
+
+	// Drop unchanged lines at both ends of the changed range.
+	for len(removed) > 0 && len(added) > 0 && removed[0] == added[0] {
+		removed, added = removed[1:], added[1:]
+		start++
+	}
+	for len(removed) > 0 && len(added) > 0 && removed[len(removed)-1] == added[len(added)-1] {
+		removed, added = removed[:len(removed)-1], added[:len(added)-1]
+	}
+
+	lines := make([]fixDiffLine, 0, len(removed)+len(added))
+	for i, content := range removed {
+		lines = append(lines, fixDiffLine{num: start + i, content: content})
+	}
+	for i, content := range added {
+		lines = append(lines, fixDiffLine{num: start + i, added: true, content: content})
+	}
+	return lines
+}
+
+// writeDiffLine writes one line of a fix diff: the line number it has
+// before (removed) or after (added) the fix, a -/+ sign, and the content.
+func (r *TextReporter) writeDiffLine(w io.Writer, line fixDiffLine) error {
+	content := strings.TrimSuffix(line.content, "\r")
+	sign, style := "-", diffDelStyle
+	if line.added {
+		sign, style = "+", diffAddStyle
+	}
+	if r.colorEnabled {
+		_, err := fmt.Fprintf(w, "%s %s\n", r.formatLineNumber(line.num), style.Render(sign+" "+content))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s %s %s\n", r.formatLineNumber(line.num), sign, content)
+	return err
+}
@@ -0,0 +1,164 @@
+package reporter
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// violationGroup is the set of violations sharing a file, rule or severity.
+type violationGroup struct {
+	key        string
+	severity   rules.Severity // set for GroupBySeverity
+	violations []rules.Violation
+}
+
+// groupViolations splits sorted violations into groups, keeping the sorted
+// order within each group. Files are listed in report order, rules by
+// descending count (then code) so the noisiest rule comes first, and
+// severities from error to style.
+func groupViolations(sorted []rules.Violation, by GroupBy) []violationGroup {
+	var groups []violationGroup
+	index := make(map[string]int)
+	for _, v := range sorted {
+		key := v.Location.File
+		switch by {
+		case GroupByRule:
+			key = v.RuleCode
+		case GroupBySeverity:
+			key = v.Severity.String()
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, violationGroup{key: key, severity: v.Severity})
+		}
+		groups[i].violations = append(groups[i].violations, v)
+	}
+
+	switch by {
+	case GroupByRule:
+		slices.SortStableFunc(groups, func(a, b violationGroup) int {
+			if c := cmp.Compare(len(b.violations), len(a.violations)); c != 0 {
+				return c
+			}
+			return strings.Compare(a.key, b.key)
+		})
+	case GroupBySeverity:
+		slices.SortStableFunc(groups, func(a, b violationGroup) int {
+			return cmp.Compare(a.severity, b.severity)
+		})
+	}
+	return groups
+}
+
+// printGroups prints each group under a header with its counts, then a
+// summary listing the count of every group.
+func (r *TextReporter) printGroups(w io.Writer, groups []violationGroup, sources map[string][]byte) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	by := r.opts.GroupBy
+	for _, g := range groups {
+		if err := r.writeGroupHeader(w, g, by); err != nil {
+			return err
+		}
+		if err := r.printViolations(w, g.violations, sources); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\nSummary by %s:\n", by); err != nil {
+		return err
+	}
+	width := 0
+	for _, g := range groups {
+		width = max(width, len(strconv.Itoa(len(g.violations))))
+	}
+	for _, g := range groups {
+		if _, err := fmt.Fprintf(w, "  %*d  %s\n", width, len(g.violations), r.groupLabel(g, by)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeGroupHeader writes "<key>: <counts>" followed by a separator.
+func (r *TextReporter) writeGroupHeader(w io.Writer, g violationGroup, by GroupBy) error {
+	if _, err := fmt.Fprintf(w, "\n%s: %s\n", r.groupLabel(g, by), groupCounts(g, by)); err != nil {
+		return err
+	}
+	return r.writeSeparator(w)
+}
+
+// groupLabel returns the group key, styled like the violation headers.
+func (r *TextReporter) groupLabel(g violationGroup, by GroupBy) string {
+	label := g.key
+	if by == GroupBySeverity {
+		label = strings.ToUpper(label)
+	}
+	if !r.colorEnabled {
+		return label
+	}
+	switch by {
+	case GroupByRule:
+		return ruleCodeStyle.Render(label)
+	case GroupBySeverity:
+		if style, ok := severityStyles[g.severity]; ok {
+			return style.Render(label)
+		}
+	}
+	return fileLocStyle.Render(label)
+}
+
+// groupCounts summarizes a group: the severity breakdown of a file, the
+// files a rule fires in, or the rules behind a severity.
+func groupCounts(g violationGroup, by GroupBy) string {
+	n := len(g.violations)
+	total := fmt.Sprintf("%d %s", n, plural(n, "violation", "violations"))
+	switch by {
+	case GroupByRule:
+		files := countDistinct(g.violations, func(v rules.Violation) string { return v.Location.File })
+		return fmt.Sprintf("%s in %d %s", total, files, plural(files, "file", "files"))
+	case GroupBySeverity:
+		codes := countDistinct(g.violations, func(v rules.Violation) string { return v.RuleCode })
+		return fmt.Sprintf("%s from %d %s", total, codes, plural(codes, "rule", "rules"))
+	default:
+		return total + " (" + severityBreakdown(g.violations) + ")"
+	}
+}
+
+// severityBreakdown returns e.g. "1 error, 2 warnings, 3 info".
+func severityBreakdown(violations []rules.Violation) string {
+	counts := make(map[rules.Severity]int)
+	for _, v := range violations {
+		counts[v.Severity]++
+	}
+	var parts []string
+	for _, sev := range []rules.Severity{rules.SeverityError, rules.SeverityWarning, rules.SeverityInfo, rules.SeverityStyle} {
+		n := counts[sev]
+		if n == 0 {
+			continue
+		}
+		name := sev.String()
+		if sev == rules.SeverityError || sev == rules.SeverityWarning {
+			name = plural(n, name, name+"s")
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func countDistinct(violations []rules.Violation, key func(rules.Violation) string) int {
+	seen := make(map[string]struct{})
+	for _, v := range violations {
+		seen[key(v)] = struct{}{}
+	}
+	return len(seen)
+}
@@ -737,3 +737,145 @@ func TestTextReporter_FormatLineContent_EmptyLine(t *testing.T) {
 		t.Fatalf("formatLineContent(empty) = %q, want %q", got, "<blank>")
 	}
 }
+
+func TestTextReporter_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	violations := []rules.Violation{
+		{Location: rules.NewLineLocation("b/Dockerfile", 1), RuleCode: "tally/max-lines", Message: "too long", Severity: rules.SeverityError},
+		{Location: rules.NewLineLocation("a/Dockerfile", 2), RuleCode: "hadolint/DL3008", Message: "pin apt", Severity: rules.SeverityWarning},
+		{Location: rules.NewLineLocation("a/Dockerfile", 3), RuleCode: "hadolint/DL3008", Message: "pin apt", Severity: rules.SeverityWarning},
+		{Location: rules.NewLineLocation("b/Dockerfile", 2), RuleCode: "hadolint/DL3008", Message: "pin apt", Severity: rules.SeverityWarning},
+		{Location: rules.NewLineLocation("a/Dockerfile", 1), RuleCode: "tally/no-multi-spaces", Message: "spaces", Severity: rules.SeverityStyle},
+	}
+
+	tests := []struct {
+		groupBy GroupBy
+		want    []string // group headers and summary, in output order
+	}{
+		{GroupByFile, []string{
+			"a/Dockerfile: 3 violations (2 warnings, 1 style)",
+			"b/Dockerfile: 2 violations (1 error, 1 warning)",
+			"Summary by file:\n  3  a/Dockerfile\n  2  b/Dockerfile\n",
+		}},
+		{GroupByRule, []string{
+			"hadolint/DL3008: 3 violations in 2 files",
+			"tally/max-lines: 1 violation in 1 file",
+			"tally/no-multi-spaces: 1 violation in 1 file",
+			"Summary by rule:\n  3  hadolint/DL3008\n  1  tally/max-lines\n  1  tally/no-multi-spaces\n",
+		}},
+		{GroupBySeverity, []string{
+			"ERROR: 1 violation from 1 rule",
+			"WARNING: 3 violations from 1 rule",
+			"STYLE: 1 violation from 1 rule",
+			"Summary by severity:\n  1  ERROR\n  3  WARNING\n  1  STYLE\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.groupBy), func(t *testing.T) {
+			t.Parallel()
+			colorOff := false
+			r := NewTextReporter(TextOptions{Color: &colorOff, GroupBy: tt.groupBy})
+			var buf bytes.Buffer
+			if err := r.Print(&buf, violations, nil); err != nil {
+				t.Fatalf("Print failed: %v", err)
+			}
+			out := buf.String()
+			pos := 0
+			for _, want := range tt.want {
+				i := strings.Index(out[pos:], want)
+				if i < 0 {
+					t.Fatalf("missing %q after offset %d in output:\n%s", want, pos, out)
+				}
+				pos += i + len(want)
+			}
+			if n := strings.Count(out, "pin apt"); n != 3 {
+				t.Errorf("got %d DL3008 messages, want 3:\n%s", n, out)
+			}
+		})
+	}
+}
+
+func TestTextReporter_Suppressed(t *testing.T) {
+	t.Parallel()
+
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "hadolint/DL3008", "pin apt", rules.SeverityWarning),
+	}
+	suppressed := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 4), "buildkit/MaintainerDeprecated", "deprecated", rules.SeverityOff).
+			WithSuppression(rules.SuppressionConfig, `severity = "off"`),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 1), "hadolint/DL3006", "tag the image", rules.SeverityWarning).
+			WithSuppression(rules.SuppressionInline, "# tally ignore=DL3006"),
+	}
+
+	colorOff := false
+	r := NewTextReporter(TextOptions{Color: &colorOff})
+	var buf bytes.Buffer
+	if err := r.PrintReport(&buf, violations, nil, ReportMetadata{Suppressed: suppressed}); err != nil {
+		t.Fatalf("PrintReport failed: %v", err)
+	}
+	want := `
+Suppressed (2):
+  Dockerfile:1  hadolint/DL3006  tag the image
+    suppressed by inline: # tally ignore=DL3006
+  Dockerfile:4  buildkit/MaintainerDeprecated  deprecated
+    suppressed by config: severity = "off"
+`
+	if out := buf.String(); !strings.HasSuffix(out, want) {
+		t.Errorf("output does not end with the suppressed list:\n%s", out)
+	}
+}
+
+func TestTextReporter_ShowFixes(t *testing.T) {
+	t.Parallel()
+
+	source := []byte("FROM alpine\nRUN apk add curl\nUSER app\n")
+	violations := []rules.Violation{
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 1), "hadolint/DL3006", "tag the image", rules.SeverityWarning).
+			WithSuggestedFix(&rules.SuggestedFix{
+				Description: "Pin the image tag",
+				Safety:      rules.FixSuggestion,
+				Edits: []rules.TextEdit{{
+					Location: rules.NewRangeLocation("Dockerfile", 1, 5, 1, 11),
+					NewText:  "alpine:3.20",
+				}},
+			}),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 2), "hadolint/DL3018", "pin packages", rules.SeverityWarning).
+			WithSuggestedFix(&rules.SuggestedFix{
+				Description: "Split the RUN",
+				Edits: []rules.TextEdit{{
+					Location: rules.NewRangeLocation("Dockerfile", 2, 0, 3, 0),
+					NewText:  "RUN apk add \\\n    curl\n",
+				}},
+			}),
+		rules.NewViolation(rules.NewLineLocation("Dockerfile", 3), "tally/example", "resolve me", rules.SeverityInfo).
+			WithSuggestedFix(&rules.SuggestedFix{Description: "Pin by digest", NeedsResolve: true}),
+	}
+
+	colorOff := false
+	r := NewTextReporter(TextOptions{Color: &colorOff, ShowFixes: true})
+	var buf bytes.Buffer
+	if err := r.Print(&buf, violations, map[string][]byte{"Dockerfile": source}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"\nFix (suggestion): Pin the image tag\n   1 | - FROM alpine\n   1 | + FROM alpine:3.20\n",
+		"\nFix (safe): Split the RUN\n   2 | - RUN apk add curl\n   2 | + RUN apk add \\\n   3 | +     curl\n",
+		"\nFix (safe): Pin by digest (computed when applied)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+
+	r = NewTextReporter(TextOptions{Color: &colorOff})
+	buf.Reset()
+	if err := r.Print(&buf, violations, map[string][]byte{"Dockerfile": source}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Fix (") {
+		t.Errorf("fixes shown without ShowFixes:\n%s", buf.String())
+	}
+}
@@ -2,8 +2,10 @@ package ruledeprecation
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
+	"sync"
 )
 
 // Kind describes how a deprecated rule should behave.
@@ -88,10 +90,46 @@ var entries = []Entry{
 }
 
 var (
+	mu                  sync.RWMutex
 	lookupByCode        = buildLookup(entries)
 	lookupByReplacement = buildReplacementLookup(entries)
 )
 
+// Register adds a deprecation entry, typically for a rule declaring former
+// codes or a deprecation in its metadata. It is meant to be called from init
+// functions and panics if the code or one of its aliases is already known.
+func Register(entry Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, code := range append([]string{entry.Code}, entry.Aliases...) {
+		if _, exists := lookupByCode[code]; exists {
+			panic(fmt.Sprintf("deprecated rule code %q already registered", code))
+		}
+	}
+	added := []Entry{entry}
+	maps.Copy(lookupByCode, buildLookup(added))
+	for replacement, codes := range buildReplacementLookup(added) {
+		lookupByReplacement[replacement] = append(lookupByReplacement[replacement], codes...)
+	}
+}
+
+// Renamed returns the entry for a rule whose code changed from oldCode to
+// newCode. The bare rule name is kept as an alias, like the namespaced
+// spellings accepted in inline directives.
+func Renamed(oldCode, newCode string) Entry {
+	entry := Entry{
+		Code:        oldCode,
+		Kind:        KindSuperseded,
+		Replacement: newCode,
+		Detail:      "the rule was renamed",
+	}
+	if idx := strings.LastIndexByte(oldCode, '/'); idx >= 0 {
+		entry.Aliases = []string{oldCode[idx+1:]}
+	}
+	return entry
+}
+
 func supersededByBuildKit(code, ruleName, subject string) Entry {
 	return Entry{
 		Code:        "hadolint/" + code,
@@ -127,6 +165,8 @@ func buildReplacementLookup(in []Entry) map[string][]string {
 
 // Lookup returns the deprecation entry for code or one of its aliases.
 func Lookup(code string) (Entry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
 	entry, ok := lookupByCode[code]
 	return entry, ok
 }
@@ -148,6 +188,8 @@ func ReplacementFor(code string) (string, bool) {
 
 // DeprecatedCodesFor returns deprecated codes and aliases that target ruleCode.
 func DeprecatedCodesFor(ruleCode string) []string {
+	mu.RLock()
+	defer mu.RUnlock()
 	return slices.Clone(lookupByReplacement[ruleCode])
 }
 
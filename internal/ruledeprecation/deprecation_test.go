@@ -130,3 +130,31 @@ func TestBuildKitCoveredHadolintRulesAreDeprecated(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterRenamedRule(t *testing.T) {
+	t.Parallel()
+
+	Register(Renamed("tally/test-old-name", "tally/test-new-name"))
+
+	for _, code := range []string{"tally/test-old-name", "test-old-name"} {
+		if !IsDeprecatedAliasFor(code, "tally/test-new-name") {
+			t.Errorf("IsDeprecatedAliasFor(%q, tally/test-new-name) = false, want true", code)
+		}
+	}
+	codes := DeprecatedCodesFor("tally/test-new-name")
+	if !slices.Equal(codes, []string{"tally/test-old-name", "test-old-name"}) {
+		t.Errorf("DeprecatedCodesFor(tally/test-new-name) = %v", codes)
+	}
+	entry, _ := Lookup("test-old-name")
+	if got, want := (Notice{Entry: entry}).Message(),
+		"rule tally/test-old-name is deprecated; use tally/test-new-name instead: the rule was renamed"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a known deprecated code did not panic")
+		}
+	}()
+	Register(Renamed("hadolint/DL3000", "tally/test-new-name"))
+}
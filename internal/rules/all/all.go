@@ -17,5 +17,6 @@ import (
 	_ "github.com/wharflab/tally/internal/rules/tally/php"
 	_ "github.com/wharflab/tally/internal/rules/tally/powershell"
 	_ "github.com/wharflab/tally/internal/rules/tally/ruby"
+	_ "github.com/wharflab/tally/internal/rules/tally/runtime"
 	_ "github.com/wharflab/tally/internal/rules/tally/windows"
 )
@@ -81,3 +81,13 @@ func RefinedViolations(
 	}
 	return out
 }
+
+// ImageShellConfig extracts the parts of a resolved image config that decide
+// the default shell of a stage built on it (see semantic.Model.RecheckShellSetting).
+func ImageShellConfig(cfg *registry.ImageConfig) semantic.ImageShellConfig {
+	return semantic.ImageShellConfig{
+		OS:       cfg.OS,
+		Shell:    cfg.Shell,
+		BaseName: cfg.Labels[semantic.BaseNameLabel],
+	}
+}
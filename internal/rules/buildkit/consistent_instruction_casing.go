@@ -7,6 +7,7 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
 )
 
 // ConsistentInstructionCasingRule implements the ConsistentInstructionCasing linting rule.
@@ -32,6 +33,7 @@ func (r *ConsistentInstructionCasingRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixes:           fixes.FixSafeties("ConsistentInstructionCasing"),
 	}
 }
 
@@ -79,26 +79,49 @@ func (r *CopyIgnoredFileRule) checkBuildContextSources(
 			continue
 		}
 
-		if !src.AvailableInContext {
+		if src.AvailableInContext {
+			continue
+		}
+		if isContextSourceGlob(src) {
 			violations = append(violations, rules.NewViolation(
 				loc,
 				r.Metadata().Code,
-				"source '"+src.SourcePath+"' is not available in the build context and will not be copied",
+				"source pattern '"+src.SourcePath+"' matches no files available in the build context",
 				r.Metadata().DefaultSeverity,
 			).WithDocURL(r.Metadata().DocURL).WithDetail(
-				"The source '"+src.SourcePath+"' is not present in the resolved build context. "+
-					"It may be excluded by .dockerignore or otherwise unavailable to the build."))
+				"Every path matching '"+src.SourcePath+"' is excluded by .dockerignore, or nothing matches it. "+
+					"The build fails when a wildcard source matches no files."))
+			continue
 		}
+		violations = append(violations, rules.NewViolation(
+			loc,
+			r.Metadata().Code,
+			"source '"+src.SourcePath+"' is not available in the build context and will not be copied",
+			r.Metadata().DefaultSeverity,
+		).WithDocURL(r.Metadata().DocURL).WithDetail(
+			"The source '"+src.SourcePath+"' is not present in the resolved build context. "+
+				"It may be excluded by .dockerignore or otherwise unavailable to the build."))
 	}
 	return violations
 }
 
+// isSpecificContextSource reports whether a source names something narrower
+// than the whole context. Wildcards count: their availability is computed
+// from the paths they match.
 func isSpecificContextSource(src *facts.BuildContextSource) bool {
-	path := src.NormalizedSourcePath
-	if path == "" {
-		path = src.SourcePath
+	path := sourcePathForCheck(src)
+	return path != "" && path != "."
+}
+
+func isContextSourceGlob(src *facts.BuildContextSource) bool {
+	return strings.ContainsAny(sourcePathForCheck(src), "*?[")
+}
+
+func sourcePathForCheck(src *facts.BuildContextSource) string {
+	if src.NormalizedSourcePath != "" {
+		return src.NormalizedSourcePath
 	}
-	return path != "" && path != "." && !strings.ContainsAny(path, "*?[")
+	return src.SourcePath
 }
 
 // init registers the rule with the default registry.
@@ -262,3 +262,80 @@ COPY ignored2.txt /ignored2.txt
 		t.Fatalf("expected 2 violations from 2 stages, got %d", len(violations))
 	}
 }
+
+type globbingBuildContext struct {
+	mockBuildContext
+	globs         map[string][]string
+	includedBelow map[string]bool
+}
+
+func (m *globbingBuildContext) Glob(pattern string) ([]string, error) {
+	return m.globs[pattern], nil
+}
+
+func (m *globbingBuildContext) HasIncludedDescendant(dir string) bool {
+	return m.includedBelow[dir]
+}
+
+func TestCopyIgnoredFileRule_Check_GlobMatchingOnlyIgnoredFiles(t *testing.T) {
+	t.Parallel()
+
+	input := testutil.MakeLintInputWithContext(t, "Dockerfile", `FROM scratch
+COPY *.env /config/
+COPY *.go /app/
+`, &globbingBuildContext{
+		mockBuildContext: mockBuildContext{
+			ignoredPaths: map[string]bool{"dev.env": true, "prod.env": true},
+		},
+		globs: map[string][]string{
+			"*.env": {"dev.env", "prod.env"},
+			"*.go":  {"main.go"},
+		},
+	})
+
+	violations := NewCopyIgnoredFileRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %d", len(violations))
+	}
+	if !strings.Contains(violations[0].Message, "source pattern '*.env' matches no files") {
+		t.Fatalf("violation message = %q", violations[0].Message)
+	}
+	if violations[0].Line() != 2 {
+		t.Fatalf("violation line = %d, want 2", violations[0].Line())
+	}
+}
+
+func TestCopyIgnoredFileRule_Check_IgnoredDirectoryWithNegatedChild(t *testing.T) {
+	t.Parallel()
+
+	input := testutil.MakeLintInputWithContext(t, "Dockerfile", `FROM scratch
+COPY vendor /vendor
+COPY build /build
+`, &globbingBuildContext{
+		mockBuildContext: mockBuildContext{
+			ignoredPaths: map[string]bool{"vendor": true, "build": true},
+		},
+		includedBelow: map[string]bool{"vendor": true},
+	})
+
+	violations := NewCopyIgnoredFileRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("expected only the fully ignored directory to be reported, got %d", len(violations))
+	}
+	if violations[0].Line() != 3 {
+		t.Fatalf("violation line = %d, want 3", violations[0].Line())
+	}
+}
+
+func TestCopyIgnoredFileRule_Check_SkipsGitSSHSources(t *testing.T) {
+	t.Parallel()
+
+	input := testutil.MakeLintInputWithContext(t, "Dockerfile", `FROM scratch
+ADD git@github.com:moby/buildkit.git#v0.20.0 /src
+`, &mockBuildContext{})
+
+	violations := NewCopyIgnoredFileRule().Check(input)
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations for git ADD source, got %d", len(violations))
+	}
+}
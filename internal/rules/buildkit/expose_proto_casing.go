@@ -7,6 +7,7 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/linter"
 
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
 )
 
 // ExposeProtoCasingRule implements the ExposeProtoCasing linting rule.
@@ -32,6 +33,7 @@ func (r *ExposeProtoCasingRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixes:           fixes.FixSafeties("ExposeProtoCasing"),
 	}
 }
 
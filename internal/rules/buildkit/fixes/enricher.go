@@ -3,6 +3,7 @@
 package fixes
 
 import (
+	"maps"
 	"slices"
 	"strings"
 
@@ -10,23 +11,31 @@ import (
 	"github.com/wharflab/tally/internal/semantic"
 )
 
-var fixableRuleNames = []string{
-	"StageNameCasing",
-	"FromAsCasing",
-	"NoEmptyContinuation",
-	"MaintainerDeprecated",
-	"ConsistentInstructionCasing",
-	"JSONArgsRecommended",
-	"InvalidDefinitionDescription",
-	"LegacyKeyValueFormat",
-	"MultipleInstructionsDisallowed",
-	"ExposeProtoCasing",
-	"WorkdirRelativePath",
+// fixSafeties lists the safety levels of the fixes tally generates for each
+// BuildKit rule, safest first.
+var fixSafeties = map[string][]rules.FixSafety{
+	"StageNameCasing":                {rules.FixSafe},
+	"FromAsCasing":                   {rules.FixSafe},
+	"NoEmptyContinuation":            {rules.FixSafe},
+	"MaintainerDeprecated":           {rules.FixSafe},
+	"ConsistentInstructionCasing":    {rules.FixSafe},
+	"JSONArgsRecommended":            {rules.FixSuggestion},
+	"InvalidDefinitionDescription":   {rules.FixSafe},
+	"LegacyKeyValueFormat":           {rules.FixSafe},
+	"MultipleInstructionsDisallowed": {rules.FixSafe, rules.FixSuggestion},
+	"ExposeProtoCasing":              {rules.FixSafe},
+	"WorkdirRelativePath":            {rules.FixSafe, rules.FixSuggestion},
 }
 
 // FixableRuleNames returns the BuildKit rule names for which tally can generate auto-fixes.
 func FixableRuleNames() []string {
-	return slices.Clone(fixableRuleNames)
+	return slices.Sorted(maps.Keys(fixSafeties))
+}
+
+// FixSafeties returns the safety levels of the fixes tally generates for a
+// BuildKit rule, or nil when it has none.
+func FixSafeties(ruleName string) []rules.FixSafety {
+	return slices.Clone(fixSafeties[ruleName])
 }
 
 // EnrichBuildKitFixes adds SuggestedFix to BuildKit violations where possible.
@@ -41,17 +41,7 @@ func enrichStageNameCasingFix(v *rules.Violation, sem *semantic.Model, source []
 		return
 	}
 
-	file := v.Location.File
-	var edits []rules.TextEdit
-
-	// 1. Fix the stage definition (FROM ... AS stagename)
-	if edit := createStageDefEdit(sem.Stage(stageIdx), stageName, lowerName, file, source); edit != nil {
-		edits = append(edits, *edit)
-	}
-
-	// 2. Fix all references to this stage
-	edits = append(edits, collectStageRefEdits(sem, stageIdx, stageName, lowerName, file, source)...)
-
+	edits := StageRenameEdits(sem, stageIdx, stageName, lowerName, v.Location.File, source)
 	if len(edits) > 0 {
 		v.SuggestedFix = &rules.SuggestedFix{
 			Description: fmt.Sprintf("Rename stage '%s' to '%s'", stageName, lowerName),
@@ -62,6 +52,22 @@ func enrichStageNameCasingFix(v *rules.Violation, sem *semantic.Model, source []
 	}
 }
 
+// StageRenameEdits returns the edits that rename stage stageIdx from stageName
+// to newName: the stage definition (FROM ... AS stagename) and every reference
+// to it (FROM <stagename> and COPY --from=<stagename>). Names are matched
+// case-insensitively, as BuildKit resolves stage names.
+func StageRenameEdits(sem *semantic.Model, stageIdx int, stageName, newName, file string, source []byte) []rules.TextEdit {
+	var edits []rules.TextEdit
+
+	// 1. Fix the stage definition (FROM ... AS stagename)
+	if edit := createStageDefEdit(sem.Stage(stageIdx), stageName, newName, file, source); edit != nil {
+		edits = append(edits, *edit)
+	}
+
+	// 2. Fix all references to this stage
+	return append(edits, collectStageRefEdits(sem, stageIdx, stageName, newName, file, source)...)
+}
+
 // createStageDefEdit creates an edit for the stage definition (FROM ... AS stagename).
 func createStageDefEdit(stage *instructions.Stage, stageName, lowerName, file string, source []byte) *rules.TextEdit {
 	if stage == nil || len(stage.Location) == 0 {
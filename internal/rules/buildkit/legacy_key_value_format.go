@@ -5,6 +5,7 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/linter"
 
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
 )
 
 // LegacyKeyValueFormatRule implements the LegacyKeyValueFormat linting rule.
@@ -33,6 +34,7 @@ func (r *LegacyKeyValueFormatRule) Metadata() rules.RuleMetadata {
 		// FixPriority 91 ensures semantic rules like prefer-package-cache-mounts (priority 90)
 		// can delete an ENV instruction before this rule tries to reformat it.
 		FixPriority: 91,
+		Fixes:       fixes.FixSafeties("LegacyKeyValueFormat"),
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/linter"
 
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
 )
 
 // CapturedRuleNames lists BuildKit rule names that can be captured by tally during parsing.
@@ -182,6 +183,7 @@ func GetMetadata(ruleName string) *rules.RuleMetadata {
 		DefaultSeverity: info.DefaultSeverity,
 		Category:        info.Category,
 		IsExperimental:  info.Experimental,
+		Fixes:           fixes.FixSafeties(ruleName),
 	}
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/wharflab/tally/internal/registry"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/rules/asyncutil"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
 	"github.com/wharflab/tally/internal/semantic"
 )
 
@@ -34,6 +35,7 @@ func (r *WorkdirRelativePathRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		IsExperimental:  false,
+		Fixes:           fixes.FixSafeties("WorkdirRelativePath"),
 	}
 }
 
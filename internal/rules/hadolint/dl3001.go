@@ -68,6 +68,7 @@ func (r *DL3001Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion, rules.FixUnsafe},
 	}
 }
 
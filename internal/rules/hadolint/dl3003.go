@@ -28,6 +28,7 @@ func (r *DL3003Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
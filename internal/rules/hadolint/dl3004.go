@@ -52,7 +52,7 @@ func (r *DL3004Rule) Check(input rules.LintInput) []rules.Violation {
 						"sudo is designed for interactive use and doesn't work reliably in containers. " +
 							"Instead, use the USER instruction to switch users, or run specific commands " +
 							"as a different user with 'su -c' if necessary.",
-					),
+					).WithToken("sudo"),
 				}
 			}
 			return nil
@@ -56,7 +56,7 @@ func (r *DL3006Rule) Check(input rules.LintInput) []rules.Violation {
 		).WithDocURL(r.Metadata().DocURL).WithDetail(
 			"Untagged images default to :latest, which can change unexpectedly and break builds. "+
 				"Always specify an explicit tag for reproducibility.",
-		))
+		).WithToken(info.Stage.BaseName))
 	}
 
 	return violations
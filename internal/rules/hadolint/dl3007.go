@@ -57,7 +57,7 @@ func (r *DL3007Rule) Check(input rules.LintInput) []rules.Violation {
 		).WithDocURL(r.Metadata().DocURL).WithDetail(
 			"The :latest tag can change at any time, potentially breaking builds "+
 				"or introducing unexpected behavior. Use a specific version tag for reproducibility.",
-		))
+		).WithToken(info.Stage.BaseName))
 	}
 
 	return violations
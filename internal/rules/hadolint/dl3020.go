@@ -1,7 +1,9 @@
 package hadolint
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"slices"
 	"strings"
 
@@ -10,16 +12,35 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
 	"github.com/wharflab/tally/internal/shell"
 	"github.com/wharflab/tally/internal/sourcemap"
 )
 
+// DL3020Config is the configuration for the DL3020 rule.
+type DL3020Config struct {
+	// ArchiveExtensions are extra file name suffixes, besides the tar
+	// extensions, marking local archives that ADD is meant to extract.
+	ArchiveExtensions []string `json:"archive-extensions,omitempty" koanf:"archive-extensions"`
+}
+
+// DefaultDL3020Config returns the default configuration.
+func DefaultDL3020Config() DL3020Config {
+	return DL3020Config{}
+}
+
 // DL3020Rule implements the DL3020 linting rule.
-type DL3020Rule struct{}
+type DL3020Rule struct {
+	schema map[string]any
+}
 
 // NewDL3020Rule creates a new DL3020 rule instance.
 func NewDL3020Rule() *DL3020Rule {
-	return &DL3020Rule{}
+	schema, err := configutil.RuleSchema(rules.HadolintRulePrefix + "DL3020")
+	if err != nil {
+		panic(err)
+	}
+	return &DL3020Rule{schema: schema}
 }
 
 // Metadata returns the rule metadata.
@@ -32,72 +53,183 @@ func (r *DL3020Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityError,
 		Category:        "best-practice",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DL3020Rule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DL3020Rule) DefaultConfig() any {
+	return DefaultDL3020Config()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DL3020Rule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(r.Metadata().Code, config)
+}
+
 // Check runs the DL3020 rule.
 // It warns when ADD is used with local files/folders instead of COPY.
-// ADD is acceptable for:
-// - Remote URLs (http://, https://, ftp://)
-// - Tar archives (recognized by extension or explicit use case)
-// - Git repositories
+// Only plain ADDs are reported, where every source is a local file or
+// directory and no ADD-only flag (--checksum, --keep-git-dir, --unpack) is
+// set, so that swapping the keyword to COPY never changes the build. ADD is
+// acceptable for:
+//   - Remote URLs (http://, https://, ftp://)
+//   - Tar archives, recognized by extension or, when the build context is
+//     available, by content
+//   - Git repositories
 func (r *DL3020Rule) Check(input rules.LintInput) []rules.Violation {
+	cfg := configutil.Coerce(input.Config, DefaultDL3020Config())
 	meta := r.Metadata()
 	sm := input.SourceMap()
 	var violations []rules.Violation
 
-	for _, stage := range input.Stages {
+	for stageIdx, stage := range input.Stages {
 		for _, cmd := range stage.Commands {
 			add, ok := cmd.(*instructions.AddCommand)
+			if !ok || hasAddOnlyFlags(add) {
+				continue
+			}
+
+			src, ok := plainLocalAddSource(input, stageIdx, add, cfg)
 			if !ok {
 				continue
 			}
 
-			// Check each source
-			for _, src := range add.SourcePaths {
-				// Skip URLs - ADD is valid for URLs
-				if isURLDL3020(src) {
-					continue
-				}
-
-				// Skip tar archives - ADD auto-extracts them
-				if isTarArchiveDL3020(src) {
-					continue
-				}
-
-				// Skip heredocs - they use special syntax
-				if isHeredocDL3020(src) {
-					continue
-				}
-
-				// This is a local file/folder - should use COPY
-				loc := rules.NewLocationFromRanges(input.File, add.Location())
-				v := rules.NewViolation(
-					loc,
-					meta.Code,
-					fmt.Sprintf(
-						"use COPY instead of ADD for local file %q; COPY is more explicit and secure",
-						src,
-					),
-					meta.DefaultSeverity,
-				).WithDocURL(meta.DocURL).WithDetail(
-					"ADD has implicit features (auto-extraction, URL fetching) that make builds less predictable. " +
-						"Use COPY for simple file copies. Only use ADD when you need tar extraction or URL fetching.",
-				)
-
-				if fix := buildDL3020Fix(input.File, add.Location(), sm, meta); fix != nil {
-					v = v.WithSuggestedFix(fix)
-				}
-
-				violations = append(violations, v)
-				break // One violation per ADD instruction is enough
+			// This is a local file/folder - should use COPY
+			loc := rules.NewLocationFromRanges(input.File, add.Location())
+			v := rules.NewViolation(
+				loc,
+				meta.Code,
+				fmt.Sprintf(
+					"use COPY instead of ADD for local file %q; COPY is more explicit and secure",
+					src,
+				),
+				meta.DefaultSeverity,
+			).WithDocURL(meta.DocURL).WithDetail(
+				"ADD has implicit features (auto-extraction, URL fetching) that make builds less predictable. " +
+					"Use COPY for simple file copies. Only use ADD when you need tar extraction or URL fetching.",
+			).WithToken(src)
+
+			if fix := buildDL3020Fix(input.File, add.Location(), sm, meta); fix != nil {
+				v = v.WithSuggestedFix(fix).WithFixKind("add-to-copy")
 			}
+
+			violations = append(violations, v)
 		}
 	}
 
 	return violations
 }
 
+// hasAddOnlyFlags reports whether add uses a flag that COPY does not accept.
+func hasAddOnlyFlags(add *instructions.AddCommand) bool {
+	return add.Checksum != "" || add.KeepGitDir != nil || add.Unpack != nil
+}
+
+// plainLocalAddSource returns the first local source of add when every
+// source is a local file or directory that ADD copies as is. Heredoc
+// sources behave the same under COPY and are ignored.
+func plainLocalAddSource(
+	input rules.LintInput,
+	stageIdx int,
+	add *instructions.AddCommand,
+	cfg DL3020Config,
+) (string, bool) {
+	first := ""
+	for _, src := range add.SourcePaths {
+		if isHeredocDL3020(src) {
+			continue
+		}
+		// URLs and git repositories need ADD.
+		if isURLDL3020(src) {
+			return "", false
+		}
+		// Archives are extracted by ADD.
+		if isTarArchiveDL3020(src) || hasArchiveExtension(src, cfg.ArchiveExtensions) ||
+			isArchiveInContext(input, stageIdx, add, src) {
+			return "", false
+		}
+		if first == "" {
+			first = src
+		}
+	}
+	return first, first != ""
+}
+
+// hasArchiveExtension reports whether src ends with one of the configured
+// archive extensions.
+func hasArchiveExtension(src string, extensions []string) bool {
+	src = shell.DropQuotes(strings.ToLower(src))
+	return slices.ContainsFunc(extensions, func(ext string) bool {
+		return ext != "" && strings.HasSuffix(src, strings.ToLower(ext))
+	})
+}
+
+// contextFileOpener is the part of the build context needed to sniff files.
+// It is implemented by the local build context reader.
+type contextFileOpener interface {
+	OpenFile(path string) (io.ReadCloser, error)
+}
+
+// isArchiveInContext reports whether src resolves to a regular build context
+// file whose content is a tar archive, compressed or not, that ADD would
+// extract whatever its name. It reports false when the build context is not
+// available.
+func isArchiveInContext(input rules.LintInput, stageIdx int, add *instructions.AddCommand, src string) bool {
+	opener, ok := input.Facts.ContextFiles().(contextFileOpener)
+	if !ok {
+		return false
+	}
+	stageFacts := input.Facts.Stage(stageIdx)
+	if stageFacts == nil {
+		return false
+	}
+	line := add.Location()[0].Start.Line
+	for _, sf := range stageFacts.BuildContextSources {
+		if sf == nil || sf.Line != line || sf.SourcePath != src || sf.ObservableFileSourcePath == "" {
+			continue
+		}
+		rc, err := opener.OpenFile(sf.ObservableFileSourcePath)
+		if err != nil {
+			return false
+		}
+		defer rc.Close()
+		return looksLikeArchive(rc)
+	}
+	return false
+}
+
+// archiveMagics are the leading bytes of the compression formats ADD
+// decompresses before extracting: gzip, bzip2, xz and zstd.
+var archiveMagics = [][]byte{
+	{0x1f, 0x8b},
+	[]byte("BZh"),
+	{0xfd, '7', 'z', 'X', 'Z', 0x00},
+	{0x28, 0xb5, 0x2f, 0xfd},
+}
+
+// tarMagicOffset is the offset of the "ustar" magic in a tar header.
+const tarMagicOffset = 257
+
+// looksLikeArchive reports whether r starts with a compression magic or a
+// tar header.
+func looksLikeArchive(r io.Reader) bool {
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(r, header)
+	header = header[:n]
+	for _, magic := range archiveMagics {
+		if bytes.HasPrefix(header, magic) {
+			return true
+		}
+	}
+	return len(header) >= tarMagicOffset+5 && string(header[tarMagicOffset:tarMagicOffset+5]) == "ustar"
+}
+
 // buildDL3020Fix generates an auto-fix that replaces "ADD" with "COPY" on the
 // instruction's first source line. The edit targets only the 3-character keyword,
 // preserving all flags, sources, and destination unchanged.
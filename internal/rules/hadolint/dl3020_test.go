@@ -1,9 +1,15 @@
 package hadolint
 
 import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+
+	buildcontext "github.com/wharflab/tally/internal/context"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/testutil"
 )
@@ -139,6 +145,44 @@ ADD file1.txt file2.txt /app/
 `,
 			wantCount: 1, // Only one violation per ADD instruction
 		},
+		{
+			name: "ADD mixing a URL and a local file is allowed",
+			dockerfile: `FROM ubuntu:22.04
+ADD https://example.com/tool.sh config.yaml /app/
+`,
+			wantCount: 0, // COPY cannot fetch the URL
+		},
+		{
+			name: "ADD mixing an archive and a local file is allowed",
+			dockerfile: `FROM ubuntu:22.04
+ADD rootfs.tar.gz config.yaml /
+`,
+			wantCount: 0, // COPY would not extract the archive
+		},
+		{
+			name: "ADD with --unpack is allowed",
+			dockerfile: `# syntax=docker/dockerfile:1.17
+FROM ubuntu:22.04
+ADD --unpack=false file.txt /app/
+`,
+			wantCount: 0,
+		},
+		{
+			name: "ADD with --chown and --chmod is reported",
+			dockerfile: `FROM ubuntu:22.04
+ADD --chown=app:app --chmod=644 file.txt /app/
+`,
+			wantCount: 1,
+		},
+		{
+			name: "ADD heredoc is allowed",
+			dockerfile: `FROM ubuntu:22.04
+ADD <<EOF /app/config
+key=value
+EOF
+`,
+			wantCount: 0,
+		},
 		// Tests from hadolint/hadolint test/Hadolint/Rule/DL3020Spec.hs
 		{
 			name: "ADD for tgz with quotes",
@@ -344,3 +388,59 @@ func TestIsTarArchiveDL3020(t *testing.T) {
 		})
 	}
 }
+
+func TestDL3020Rule_ArchiveExtensionsConfig(t *testing.T) {
+	t.Parallel()
+	content := `FROM ubuntu:22.04
+ADD rootfs.layer /
+ADD notes.txt /
+`
+	input := testutil.MakeLintInputWithConfig(t, "Dockerfile", content, DL3020Config{
+		ArchiveExtensions: []string{".layer"},
+	})
+	violations := NewDL3020Rule().Check(input)
+	if len(violations) != 1 || violations[0].Line() != 3 {
+		t.Fatalf("got %v, want one violation on line 3", violations)
+	}
+}
+
+func TestDL3020Rule_SniffsArchivesInBuildContext(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	if _, err := zw.Write([]byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tarHeader := make([]byte, 512)
+	copy(tarHeader[257:], "ustar")
+	files := map[string][]byte{
+		"rootfs":     gz.Bytes(),
+		"bundle.bin": tarHeader,
+		"config":     []byte("key=value\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, err := buildcontext.New(tmpDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := `FROM ubuntu:22.04
+ADD rootfs /
+ADD bundle.bin /opt/
+ADD config /etc/app/
+`
+	input := testutil.MakeLintInputWithContext(t, "Dockerfile", content, ctx)
+	violations := NewDL3020Rule().Check(input)
+	if len(violations) != 1 || violations[0].Line() != 4 {
+		t.Fatalf("got %v, want one violation on line 4", violations)
+	}
+}
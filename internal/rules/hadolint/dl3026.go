@@ -43,7 +43,7 @@ func (r *DL3026Rule) Metadata() rules.RuleMetadata {
 		Name:            "Use only trusted base images",
 		Description:     "Use only an allowed registry in the FROM image",
 		DocURL:          rules.HadolintDocURL("DL3026"),
-		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when trusted-registries configured
+		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when a trusted registries list is configured
 		Category:        "security",
 		IsExperimental:  false,
 	}
@@ -59,8 +59,10 @@ func (r *DL3026Rule) Schema() map[string]any {
 func (r *DL3026Rule) Check(input rules.LintInput) []rules.Violation {
 	cfg := r.resolveConfig(input.Config)
 
-	// If no trusted registries configured, rule is disabled
-	if len(cfg.TrustedRegistries) == 0 {
+	// The rule's own list wins over the global [registries] trusted list.
+	// If neither is configured, the rule is disabled.
+	policy := input.Registries.WithTrusted(cfg.TrustedRegistries)
+	if len(policy.Trusted) == 0 {
 		return nil
 	}
 
@@ -69,6 +71,7 @@ func (r *DL3026Rule) Check(input rules.LintInput) []rules.Violation {
 		return nil
 	}
 
+	meta := r.Metadata()
 	var violations []rules.Violation
 
 	// ExternalImageStages already skips scratch and inter-stage references.
@@ -84,78 +87,27 @@ func (r *DL3026Rule) Check(input rules.LintInput) []rules.Violation {
 
 		registry := ref.Domain()
 
-		if !isRegistryTrusted(registry, cfg.TrustedRegistries) {
+		if !policy.IsTrusted(registry) {
 			loc := rules.NewLocationFromRanges(input.File, info.Stage.Location)
+			// The rule only runs once a trusted list is configured, so it
+			// reports at warning level even when enabled by the global list.
 			violations = append(violations, rules.NewViolation(
 				loc,
-				r.Metadata().Code,
+				meta.Code,
 				fmt.Sprintf(
 					"image %q is from untrusted registry %q (allowed: %s)",
 					imageName,
 					registry,
-					strings.Join(cfg.TrustedRegistries, ", "),
+					strings.Join(policy.Trusted, ", "),
 				),
-				r.Metadata().DefaultSeverity,
-			).WithDocURL(r.Metadata().DocURL))
+				rules.SeverityWarning,
+			).WithDocURL(meta.DocURL))
 		}
 	}
 
 	return violations
 }
 
-// isRegistryTrusted checks if a registry is in the trusted list.
-// Handles docker.io aliases (docker.io, index.docker.io, registry-1.docker.io).
-// Supports wildcard patterns:
-//   - "*" matches any registry
-//   - "*.example.com" matches any subdomain of example.com (suffix match)
-//   - "prefix*" matches any registry starting with prefix (prefix match)
-func isRegistryTrusted(registry string, trusted []string) bool {
-	normalizedRegistry := normalizeRegistry(registry)
-
-	for _, t := range trusted {
-		if matchRegistry(normalizeRegistry(t), normalizedRegistry) {
-			return true
-		}
-	}
-	return false
-}
-
-// matchRegistry checks if a registry matches a pattern.
-// Supports exact match, "*" (any), "*.suffix" (suffix match), and "prefix*" (prefix match).
-func matchRegistry(pattern, registry string) bool {
-	// Exact wildcard matches everything
-	if pattern == "*" {
-		return true
-	}
-
-	// Suffix wildcard: *.example.com matches foo.example.com
-	if strings.HasPrefix(pattern, "*.") {
-		suffix := pattern[1:] // Keep the dot: ".example.com"
-		return strings.HasSuffix(registry, suffix)
-	}
-
-	// Prefix wildcard: prefix* matches prefix.anything
-	if strings.HasSuffix(pattern, "*") {
-		prefix := pattern[:len(pattern)-1]
-		return strings.HasPrefix(registry, prefix)
-	}
-
-	// Exact match
-	return pattern == registry
-}
-
-// normalizeRegistry normalizes registry names for comparison.
-// Converts Docker Hub aliases to canonical "docker.io".
-// Trims whitespace to handle accidental spaces in config files.
-func normalizeRegistry(registry string) string {
-	registry = strings.ToLower(strings.TrimSpace(registry))
-	switch registry {
-	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com", "hub.docker.com":
-		return "docker.io"
-	}
-	return registry
-}
-
 // DefaultConfig returns the default configuration for this rule.
 func (r *DL3026Rule) DefaultConfig() any {
 	return DefaultDL3026Config()
@@ -1,6 +1,7 @@
 package hadolint
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
@@ -343,15 +344,102 @@ func TestDL3026Rule_MatchRegistry(t *testing.T) {
 		{"gcr*", "gcr.io", true},
 		{"gcr*", "gcr.example.com", true},
 		{"gcr*", "docker.io", false},
+
+		// Ports
+		{"registry.internal:5000", "registry.internal:5000", true},
+		{"registry.internal:5000", "registry.internal:5001", false},
+		{"registry.internal:5000", "registry.internal", false},
+		{"registry.internal", "registry.internal:5000", true},
+		{"*.corp.example.com", "harbor.corp.example.com:8443", true},
+		{"*.corp.example.com:8443", "harbor.corp.example.com:8443", true},
+		{"*.corp.example.com:8443", "harbor.corp.example.com", false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.pattern+"_"+tt.registry, func(t *testing.T) {
 			t.Parallel()
-			got := matchRegistry(tt.pattern, tt.registry)
+			got := rules.MatchRegistry(tt.pattern, tt.registry)
 			if got != tt.want {
 				t.Errorf("matchRegistry(%q, %q) = %v, want %v", tt.pattern, tt.registry, got, tt.want)
 			}
 		})
 	}
 }
+
+func TestDL3026Rule_InheritsGlobalTrustedRegistries(t *testing.T) {
+	t.Parallel()
+	r := NewDL3026Rule()
+	input := testutil.MakeLintInput(t, "Dockerfile", `FROM gcr.io/distroless/static AS build
+FROM harbor.corp.example.com:8443/base/alpine:3.20
+FROM quay.io/prometheus/busybox
+`)
+	input.Registries = rules.RegistryPolicy{Trusted: []string{"gcr.io", "*.corp.example.com"}}
+
+	violations := r.Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation with the global list, got %d", len(violations))
+	}
+	if !strings.Contains(violations[0].Message, `"quay.io"`) {
+		t.Errorf("expected quay.io to be reported, got %q", violations[0].Message)
+	}
+	if violations[0].Severity != rules.SeverityWarning {
+		t.Errorf("expected warning severity, got %s", violations[0].Severity)
+	}
+
+	// The rule's own list replaces the global one.
+	input.Config = DL3026Config{TrustedRegistries: []string{"quay.io"}}
+	if got := len(r.Check(input)); got != 2 {
+		t.Errorf("expected 2 violations with the rule's own list, got %d", got)
+	}
+}
+
+func TestDL3026Rule_Mirrors(t *testing.T) {
+	t.Parallel()
+	r := NewDL3026Rule()
+
+	tests := []struct {
+		name       string
+		dockerfile string
+		trusted    []string
+		wantViol   int
+	}{
+		{
+			name:       "mirror of trusted upstream",
+			dockerfile: "FROM mirror.gcr.io/library/alpine:3.20\n",
+			trusted:    []string{"docker.io"},
+			wantViol:   0,
+		},
+		{
+			name:       "upstream of trusted mirror",
+			dockerfile: "FROM alpine:3.20\n",
+			trusted:    []string{"mirror.gcr.io"},
+			wantViol:   0,
+		},
+		{
+			name:       "mirror with port",
+			dockerfile: "FROM harbor.internal:8443/library/alpine:3.20\n",
+			trusted:    []string{"registry-1.docker.io"},
+			wantViol:   0,
+		},
+		{
+			name:       "unrelated registry",
+			dockerfile: "FROM quay.io/prometheus/busybox\n",
+			trusted:    []string{"docker.io"},
+			wantViol:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := makeDL3026Input(t, tt.dockerfile, DL3026Config{TrustedRegistries: tt.trusted})
+			input.Registries = rules.RegistryPolicy{Mirrors: map[string]string{
+				"mirror.gcr.io":        "docker.io",
+				"harbor.internal:8443": "index.docker.io",
+			}}
+			if got := len(r.Check(input)); got != tt.wantViol {
+				t.Errorf("expected %d violations, got %d", tt.wantViol, got)
+			}
+		})
+	}
+}
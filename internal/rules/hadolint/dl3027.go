@@ -26,6 +26,7 @@ func (r *DL3027Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
@@ -118,28 +119,20 @@ func (r *DL3027Rule) Check(input rules.LintInput) []rules.Violation {
 					"The apt command is designed for interactive use and has an unstable command-line interface. " +
 						"For scripting and automation (like Dockerfiles), use apt-get for package management " +
 						"or apt-cache for querying package information.",
-				)
+				).WithToken("apt")
 
 				// Only add edits for shell form RUN commands.
 				if run.PrependShell {
-					editLine := runStartLine + occ.Line
-					editEndCol := occ.EndCol
-
-					lineIdx := editLine - 1
-					if lineIdx < 0 || lineIdx >= sm.LineCount() {
-						violations = append(violations, v)
-						continue
-					}
-					sourceLine := sm.Line(lineIdx)
-					if occ.StartCol < 0 || editEndCol > len(sourceLine) ||
-						sourceLine[occ.StartCol:editEndCol] != "apt" {
+					span, ok := sm.TokenSpan(runStartLine-1+occ.Line, occ.StartCol, "apt")
+					if !ok {
 						violations = append(violations, v)
 						continue
 					}
 
 					// Source validation passed — use precise occurrence location
 					// so same-line violations survive deduplication.
-					v.Location = rules.NewRangeLocation(file, editLine, occ.StartCol, editLine, editEndCol)
+					v.Location = rules.NewLocationFromSpan(file, span)
+					editLine, editEndCol := span.EndLine+1, span.EndCol
 
 					// Zero-width insertion right after "apt" to produce "apt-get" / "apt-cache".
 					v = v.WithSuggestedFix(&rules.SuggestedFix{
@@ -149,7 +142,7 @@ func (r *DL3027Rule) Check(input rules.LintInput) []rules.Violation {
 							Location: rules.NewRangeLocation(file, editLine, editEndCol, editLine, editEndCol),
 							NewText:  suffix,
 						}},
-					})
+					}).WithFixKind("replace-command")
 				}
 
 				violations = append(violations, v)
@@ -24,6 +24,7 @@ func (r *DL3030Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "best-practice",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
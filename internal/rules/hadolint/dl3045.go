@@ -35,18 +35,26 @@ func (r *DL3045Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "best-practice",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
 // Check runs the DL3045 rule.
 // It warns when a COPY instruction uses a relative destination path without
 // a WORKDIR having been set in the current stage (or inherited from a parent stage).
+// Stage COPYs are left to tally/relative-copy-destination when it is enabled;
+// ONBUILD COPY triggers are always checked.
 func (r *DL3045Rule) Check(input rules.LintInput) []rules.Violation {
 	meta := r.Metadata()
 
 	sem := input.Semantic
 
 	violations, hasWorkdir := findCopyViolations(sem, input.Stages, input.File, meta)
+	if input.IsRuleEnabled(rules.RelativeCopyDestinationRuleCode) {
+		// tally/relative-copy-destination reports these COPYs with a fix
+		// that resolves the destination against the tracked WORKDIR.
+		violations = nil
+	}
 
 	// Also check ONBUILD COPY instructions.
 	for stageIdx := range input.Stages {
@@ -62,7 +70,7 @@ func (r *DL3045Rule) Check(input rules.LintInput) []rules.Violation {
 // precise ones based on the base image's actual WorkingDir.
 func (r *DL3045Rule) PlanAsync(input rules.LintInput) []async.CheckRequest {
 	sem := input.Semantic
-	if sem == nil {
+	if sem == nil || input.IsRuleEnabled(rules.RelativeCopyDestinationRuleCode) {
 		return nil
 	}
 
@@ -252,6 +252,28 @@ func TestDL3045Rule_Check_FixSuggestion(t *testing.T) {
 	}
 }
 
+func TestDL3045Rule_DefersToRelativeCopyDestination(t *testing.T) {
+	t.Parallel()
+
+	input := testutil.MakeLintInput(t, "Dockerfile", `FROM alpine:3.18
+COPY foo bar
+ONBUILD COPY baz qux
+`)
+	input.EnabledRules = []string{rules.HadolintRulePrefix + "DL3045", rules.RelativeCopyDestinationRuleCode}
+
+	r := NewDL3045Rule()
+	violations := r.Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1 (ONBUILD COPY only)", len(violations))
+	}
+	if line := violations[0].Location.Start.Line; line != 3 {
+		t.Errorf("violation line = %d, want 3", line)
+	}
+	if requests := r.PlanAsync(input); len(requests) != 0 {
+		t.Errorf("expected no async requests, got %d", len(requests))
+	}
+}
+
 func TestDL3045Rule_PlanAsync(t *testing.T) {
 	t.Parallel()
 
@@ -42,6 +42,7 @@ func (r *DL3047Rule) Metadata() rules.RuleMetadata {
 		// When wget|tar is replaced by ADD --unpack, the progress-bar fix becomes
 		// moot and is harmlessly skipped. For standalone wget the fix still applies.
 		FixPriority: 96,
+		Fixes:       []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -125,25 +126,22 @@ func (r *DL3047Rule) Check(input rules.LintInput) []rules.Violation {
 						"compact progress indicator, or -q/--quiet/-nv/--no-verbose to suppress output entirely.",
 				)
 
-				// Add auto-fix: insert --progress=dot:giga after "wget"
+				// Point at the wget token and add auto-fix: insert
+				// --progress=dot:giga right after it. The token check also
+				// accepts path-qualified invocations like /usr/bin/wget.
 				if run.PrependShell {
-					editLine := runStartLine + cmd.Line
-					insertCol := cmd.EndCol // Right after "wget"
-
-					lineIdx := editLine - 1
-					if lineIdx >= 0 && lineIdx < sm.LineCount() {
-						sourceLine := sm.Line(lineIdx)
-						if insertCol >= 0 && insertCol <= len(sourceLine) {
-							v = v.WithSuggestedFix(&rules.SuggestedFix{
-								Description: "Add --progress=dot:giga to wget",
-								Safety:      rules.FixSafe,
-								Priority:    meta.FixPriority,
-								Edits: []rules.TextEdit{{
-									Location: rules.NewRangeLocation(file, editLine, insertCol, editLine, insertCol),
-									NewText:  " --progress=dot:giga",
-								}},
-							})
-						}
+					if span, ok := sm.TokenSpan(runStartLine-1+cmd.Line, cmd.EndCol-len("wget"), "wget"); ok {
+						v.Location = rules.NewLocationFromSpan(file, span)
+						editLine, insertCol := span.EndLine+1, span.EndCol
+						v = v.WithSuggestedFix(&rules.SuggestedFix{
+							Description: "Add --progress=dot:giga to wget",
+							Safety:      rules.FixSafe,
+							Priority:    meta.FixPriority,
+							Edits: []rules.TextEdit{{
+								Location: rules.NewRangeLocation(file, editLine, insertCol, editLine, insertCol),
+								NewText:  " --progress=dot:giga",
+							}},
+						})
 					}
 				}
 
@@ -211,6 +211,7 @@ func TestDL3047_AutoFix(t *testing.T) {
 		name          string
 		dockerfile    string
 		wantFix       bool
+		wantLine      int
 		wantInsertCol int
 		wantNewText   string
 	}{
@@ -219,9 +220,29 @@ func TestDL3047_AutoFix(t *testing.T) {
 			dockerfile: `FROM ubuntu
 RUN wget http://example.com/file.tar.gz`,
 			wantFix:       true,
+			wantLine:      2,
 			wantInsertCol: 8, // After "wget" (RUN + space = 4 cols, wget = 4 chars)
 			wantNewText:   " --progress=dot:giga",
 		},
+		{
+			name: "chained path-qualified wget",
+			dockerfile: `FROM ubuntu
+RUN apt-get update && \
+    /usr/bin/wget http://example.com/file.tar.gz`,
+			wantFix:       true,
+			wantLine:      3,
+			wantInsertCol: 17,
+			wantNewText:   " --progress=dot:giga",
+		},
+		{
+			name: "wget in sh -c",
+			dockerfile: `FROM ubuntu
+RUN sh -c 'wget http://example.com/file.tar.gz'`,
+			wantFix:       true,
+			wantLine:      2,
+			wantInsertCol: 15,
+			wantNewText:   " --progress=dot:giga",
+		},
 		{
 			name: "exec form wget (detected but no auto-fix)",
 			dockerfile: `FROM ubuntu
@@ -249,7 +270,14 @@ RUN ["wget", "http://example.com/file.tar.gz"]`,
 				if len(v.SuggestedFix.Edits) == 0 {
 					t.Fatal("expected at least one edit")
 				}
+				wantStart := rules.Position{Line: tt.wantLine, Column: tt.wantInsertCol - len("wget")}
+				if v.Location.Start != wantStart || v.Location.End.Column != tt.wantInsertCol {
+					t.Errorf("location = %+v, want the wget token starting at %+v", v.Location, wantStart)
+				}
 				edit := v.SuggestedFix.Edits[0]
+				if edit.Location.Start.Line != tt.wantLine {
+					t.Errorf("insert line = %d, want %d", edit.Location.Start.Line, tt.wantLine)
+				}
 				if edit.Location.Start.Column != tt.wantInsertCol {
 					t.Errorf("insert column = %d, want %d", edit.Location.Start.Column, tt.wantInsertCol)
 				}
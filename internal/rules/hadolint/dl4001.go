@@ -78,6 +78,7 @@ func (r *DL4001Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "maintainability",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixUnsafe},
 	}
 }
 
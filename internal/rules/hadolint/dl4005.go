@@ -30,6 +30,7 @@ func (r *DL4005Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "style",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
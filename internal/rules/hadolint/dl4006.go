@@ -2,12 +2,16 @@ package hadolint
 
 import (
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
 
+	"github.com/wharflab/tally/internal/async"
 	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/registry"
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/asyncutil"
 	"github.com/wharflab/tally/internal/semantic"
 	"github.com/wharflab/tally/internal/shell"
 )
@@ -38,6 +42,7 @@ func (r *DL4006Rule) Metadata() rules.RuleMetadata {
 		// RUN). Since SHELL persists until the next FROM, a single insertion
 		// covers all subsequent piped RUNs in the same stage.
 		FixPriority: 96,
+		Fixes:       []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -57,7 +62,8 @@ type dl4006StageState struct {
 	pipefailSet  bool
 	isNonPOSIX   bool
 	shellVariant shell.Variant
-	generatedFix bool // true after a SHELL fix is emitted for this stage
+	generatedFix bool   // true after a SHELL fix is emitted for this stage
+	fixShell     string // shell executable used in the SHELL fix
 }
 
 // Check runs the DL4006 rule.
@@ -75,24 +81,107 @@ func (r *DL4006Rule) Check(input rules.LintInput) []rules.Violation {
 
 	sem := input.Semantic
 
-	for stageIdx, stage := range input.Stages {
+	for stageIdx := range input.Stages {
 		state := r.initStageState(sem, stageIdx)
+		violations = append(violations, r.checkStage(input, stageIdx, state, meta)...)
+	}
+
+	return violations
+}
 
-		for _, cmd := range stage.Commands {
-			switch c := cmd.(type) {
-			case *instructions.ShellCommand:
-				state.updateFromShell(c.Shell)
-			case *instructions.RunCommand:
-				if v := r.checkRun(c, &state, stageIdx, input, meta); v != nil {
-					violations = append(violations, *v)
-				}
+// checkStage walks the commands of one stage starting from the given state.
+func (r *DL4006Rule) checkStage(
+	input rules.LintInput,
+	stageIdx int,
+	state dl4006StageState,
+	meta rules.RuleMetadata,
+) []rules.Violation {
+	var violations []rules.Violation
+	for _, cmd := range input.Stages[stageIdx].Commands {
+		switch c := cmd.(type) {
+		case *instructions.ShellCommand:
+			state.updateFromShell(c.Shell)
+		case *instructions.RunCommand:
+			if v := r.checkRun(c, &state, stageIdx, input, meta); v != nil {
+				violations = append(violations, *v)
 			}
 		}
 	}
-
 	return violations
 }
 
+// PlanAsync resolves the config of external base images so that stages are
+// checked with the shell the image actually uses: a Windows image runs RUN
+// with cmd, and an image may set its own SHELL (possibly with pipefail).
+func (r *DL4006Rule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+
+	requests := asyncutil.PlanExternalImageChecks(input, r.Metadata(), func(
+		m rules.RuleMetadata,
+		info *semantic.StageInfo,
+		_, _ string,
+	) async.ResultHandler {
+		return &pipefailShellHandler{
+			rule:     r,
+			meta:     m,
+			input:    input,
+			stageIdx: info.Index,
+		}
+	})
+
+	// Only stages that pipe in a RUN can change their result.
+	return slices.DeleteFunc(requests, func(req async.CheckRequest) bool {
+		return !stageHasPipedRun(&input.Stages[req.StageIndex])
+	})
+}
+
+// stageHasPipedRun reports whether any shell-form RUN of the stage contains a
+// pipe under a POSIX shell.
+func stageHasPipedRun(stage *instructions.Stage) bool {
+	for _, cmd := range stage.Commands {
+		if run, ok := cmd.(*instructions.RunCommand); ok && run.PrependShell &&
+			shell.HasPipes(dockerfile.RunCommandString(run), shell.VariantBash) {
+			return true
+		}
+	}
+	return false
+}
+
+// pipefailShellHandler re-checks a stage once its base image config is resolved.
+type pipefailShellHandler struct {
+	rule     *DL4006Rule
+	meta     rules.RuleMetadata
+	input    rules.LintInput
+	stageIdx int
+}
+
+func (h *pipefailShellHandler) OnSuccess(resolved any) []any {
+	cfg, ok := resolved.(*registry.ImageConfig)
+	if !ok || cfg == nil {
+		return nil
+	}
+	setting, ok := h.input.Semantic.RecheckShellSetting(h.stageIdx, asyncutil.ImageShellConfig(cfg))
+	if !ok {
+		return nil
+	}
+
+	return asyncutil.RefinedViolations(
+		h.meta.Code, h.input.File, []int{h.stageIdx},
+		func(int) bool { return true },
+		func(idx int) []any {
+			violations := h.rule.checkStage(h.input, idx, imageStageState(setting), h.meta)
+			out := make([]any, 0, len(violations))
+			for _, v := range violations {
+				out = append(out, v)
+			}
+			return out
+		},
+	)
+}
+
 // initStageState creates the initial pipefail state for a stage.
 // The shell variant starts at the Docker default (/bin/sh → VariantPOSIX)
 // and is updated per-instruction as SHELL commands are encountered.
@@ -100,9 +189,11 @@ func (r *DL4006Rule) Check(input rules.LintInput) []rules.Violation {
 func (r *DL4006Rule) initStageState(sem *semantic.Model, stageIdx int) dl4006StageState {
 	state := dl4006StageState{
 		shellVariant: shell.VariantPOSIX, // Docker default: /bin/sh -c
+		fixShell:     pipefailFixShell(nil),
 	}
 	if sem != nil {
 		if info := sem.StageInfo(stageIdx); info != nil {
+			state.fixShell = pipefailFixShell(info.ShellSetting.Shell)
 			// Use the semantic model's shell setting only when it reflects
 			// the state before stage commands execute (OS-aware default or
 			// inline directive). A ShellSourceInstruction might appear later
@@ -118,6 +209,18 @@ func (r *DL4006Rule) initStageState(sem *semantic.Model, stageIdx int) dl4006Sta
 	return state
 }
 
+// imageStageState creates the initial pipefail state for a stage from the
+// shell its resolved base image runs RUN instructions with. Unlike the
+// Dockerfile defaults, an image SHELL may already enable pipefail.
+func imageStageState(setting semantic.ShellSetting) dl4006StageState {
+	return dl4006StageState{
+		shellVariant: setting.Variant,
+		isNonPOSIX:   !setting.Variant.SupportsPOSIXShellAST(),
+		pipefailSet:  hasPipefailOption(setting.Shell),
+		fixShell:     pipefailFixShell(setting.Shell),
+	}
+}
+
 // updateFromShell updates the pipefail tracking state from a SHELL instruction.
 // A new SHELL resets generatedFix so a fresh SHELL fix can be emitted if needed.
 func (s *dl4006StageState) updateFromShell(shellCmd []string) {
@@ -150,6 +253,12 @@ func (r *DL4006Rule) checkRun(
 		return nil
 	}
 
+	// A script that enables pipefail before running anything else covers its
+	// own pipes, provided the shell understands the option.
+	if supportsInlinePipefail(state.shellVariant) && shell.SetsPipefailFirst(cmdStr, state.shellVariant) {
+		return nil
+	}
+
 	loc := rules.NewLocationFromRanges(input.File, run.Location())
 	v := rules.NewViolation(
 		loc,
@@ -163,8 +272,8 @@ func (r *DL4006Rule) checkRun(
 	)
 
 	if !state.generatedFix {
-		if fix := r.generateFix(input, run, stageIdx, state.shellVariant); fix != nil {
-			v = v.WithSuggestedFix(fix)
+		if fixes := r.generateFixes(input, run, state.shellVariant, state.fixShell); len(fixes) > 0 {
+			v = v.WithSuggestedFixes(fixes)
 			state.generatedFix = true
 		}
 	}
@@ -172,6 +281,13 @@ func (r *DL4006Rule) checkRun(
 	return &v
 }
 
+// supportsInlinePipefail reports whether "set -o pipefail" inside the script
+// is understood by the shell variant. Plain sh (dash, busybox ash) is excluded
+// for the same reason /bin/sh is not accepted in SHELL.
+func supportsInlinePipefail(variant shell.Variant) bool {
+	return variant == shell.VariantBash || variant == shell.VariantZsh || variant == shell.VariantMksh
+}
+
 // isNonPOSIXShellCmd checks if a SHELL instruction sets a non-POSIX shell.
 func isNonPOSIXShellCmd(shellCmd []string) bool {
 	if len(shellCmd) == 0 {
@@ -223,17 +339,20 @@ func hasPipefailOption(shellCmd []string) bool {
 	return false
 }
 
-// generateFix creates a suggested fix that adds a SHELL instruction with -o pipefail
-// before the offending RUN instruction.
+// generateFixes creates the fixes for the first piped RUN of a stage. The
+// preferred fix adds a SHELL instruction with -o pipefail before the offending
+// RUN, which also covers later RUNs of the stage. When the shell understands
+// the option, prefixing just this RUN with "set -o pipefail &&" is offered as
+// an alternative.
 //
 // When prefer-run-heredoc is enabled and the RUN is a heredoc candidate, skip the fix
 // since heredoc conversion would need a different approach (shebang + set -o pipefail).
-func (r *DL4006Rule) generateFix(
+func (r *DL4006Rule) generateFixes(
 	input rules.LintInput,
 	run *instructions.RunCommand,
-	stageIdx int,
 	shellVariant shell.Variant,
-) *rules.SuggestedFix {
+	fixShell string,
+) []*rules.SuggestedFix {
 	if !run.PrependShell {
 		return nil
 	}
@@ -252,39 +371,68 @@ func (r *DL4006Rule) generateFix(
 		return nil
 	}
 
-	fixShell := r.determineFixShell(input, stageIdx)
-
 	meta := r.Metadata()
 	shellLine := `SHELL ["` + fixShell + `", "-o", "pipefail", "-c"]` + "\n"
 	startLine := runLoc[0].Start.Line
 	startCol := runLoc[0].Start.Character
 
-	return &rules.SuggestedFix{
+	fixes := []*rules.SuggestedFix{{
 		Description: "Add SHELL with -o pipefail before RUN",
 		Safety:      rules.FixSuggestion,
 		Priority:    meta.FixPriority,
+		IsPreferred: true,
 		Edits: []rules.TextEdit{{
 			Location: rules.NewRangeLocation(
 				input.File, startLine, startCol, startLine, startCol,
 			),
 			NewText: shellLine,
 		}},
+	}}
+
+	if supportsInlinePipefail(shellVariant) && len(run.Files) == 0 {
+		if fix := prefixPipefailFix(input, startLine, meta.FixPriority); fix != nil {
+			fixes = append(fixes, fix)
+		}
 	}
+	return fixes
 }
 
-// determineFixShell picks the shell path to use in the SHELL fix instruction.
-func (r *DL4006Rule) determineFixShell(input rules.LintInput, stageIdx int) string {
-	fixShell := "/bin/bash"
-	if sem := input.Semantic; sem != nil {
-		if info := sem.StageInfo(stageIdx); info != nil && len(info.ShellSetting.Shell) > 0 {
-			shellBase := strings.ToLower(path.Base(strings.ReplaceAll(info.ShellSetting.Shell[0], `\`, "/")))
-			shellBase = strings.TrimSuffix(shellBase, ".exe")
-			if pipefailValidShells[shellBase] {
-				fixShell = info.ShellSetting.Shell[0]
-			}
+// prefixPipefailFix inserts "set -o pipefail && " at the start of the script
+// of the RUN on the given line. It returns nil when the script does not start
+// on the RUN's first line.
+func prefixPipefailFix(input rules.LintInput, runLine, priority int) *rules.SuggestedFix {
+	sm := input.SourceMap()
+	if sm == nil {
+		return nil
+	}
+	line := sm.Line(runLine - 1)
+	col := shell.DockerfileRunCommandStartCol(line)
+	if rest := strings.TrimSpace(line[col:]); rest == "" || rest == `\` || rest == "`" {
+		return nil
+	}
+
+	return &rules.SuggestedFix{
+		Description: "Prefix RUN with set -o pipefail",
+		Safety:      rules.FixSuggestion,
+		Priority:    priority,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(input.File, runLine, col, runLine, col),
+			NewText:  "set -o pipefail && ",
+		}},
+	}
+}
+
+// pipefailFixShell picks the shell path to use in the SHELL fix instruction:
+// the stage's shell when it supports pipefail, /bin/bash otherwise.
+func pipefailFixShell(shellCmd []string) string {
+	if len(shellCmd) > 0 {
+		shellBase := strings.ToLower(path.Base(strings.ReplaceAll(shellCmd[0], `\`, "/")))
+		shellBase = strings.TrimSuffix(shellBase, ".exe")
+		if pipefailValidShells[shellBase] {
+			return shellCmd[0]
 		}
 	}
-	return fixShell
+	return "/bin/bash"
 }
 
 // init registers the rule with the default registry.
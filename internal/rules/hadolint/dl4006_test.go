@@ -4,6 +4,10 @@ import (
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/async"
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/registry"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/testutil"
 )
@@ -247,6 +251,35 @@ RUN Get-Variable PSVersionTable | Select-Object -ExpandProperty Value
 			wantCount: 1,
 			wantCode:  rules.HadolintRulePrefix + "DL4006",
 		},
+		{
+			name: "inline set -o pipefail under bash",
+			dockerfile: `FROM node:22
+SHELL ["/bin/bash", "-c"]
+RUN set -o pipefail && wget -O - https://some.site | wc -l > /number
+`,
+			wantCount: 0,
+		},
+		{
+			name: "inline set -euo pipefail under default bash",
+			dockerfile: `FROM node:22
+RUN set -euo pipefail; wget -O - https://some.site | wc -l > /number
+`,
+			wantCount: 0,
+		},
+		{
+			name: "inline pipefail after the pipe does not count",
+			dockerfile: `FROM node:22
+RUN wget -O - https://some.site | wc -l > /number && set -o pipefail
+`,
+			wantCount: 1,
+		},
+		{
+			name: "inline pipefail under plain sh",
+			dockerfile: `FROM alpine:3.20
+RUN set -o pipefail && wget -O - https://some.site | wc -l > /number
+`,
+			wantCount: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -320,6 +353,69 @@ RUN ["sh", "-c", "wget -O - https://some.site | wc -l"]
 	}
 }
 
+func TestDL4006Rule_PrefixFixAlternative(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantPrefix string // expected result of the prefix fix; empty when not offered
+	}{
+		{
+			name: "bash stage offers prefix",
+			dockerfile: `FROM node:22
+RUN --mount=type=cache,target=/root/.cache wget -O - https://some.site | wc -l > /number
+`,
+			wantPrefix: `FROM node:22
+RUN --mount=type=cache,target=/root/.cache set -o pipefail && wget -O - https://some.site | wc -l > /number
+`,
+		},
+		{
+			name: "plain sh stage has SHELL fix only",
+			dockerfile: `FROM alpine:3.20
+RUN wget -O - https://some.site | wc -l > /number
+`,
+		},
+		{
+			name: "script on continuation line has SHELL fix only",
+			dockerfile: `FROM node:22
+RUN \
+    wget -O - https://some.site | wc -l > /number
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.dockerfile)
+
+			violations := NewDL4006Rule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("expected 1 violation, got %d", len(violations))
+			}
+			v := violations[0]
+			if got := v.PreferredFix(); got == nil || got.Description != "Add SHELL with -o pipefail before RUN" {
+				t.Fatalf("preferred fix = %+v, want the SHELL fix", got)
+			}
+
+			fixes := v.AllFixes()
+			if tt.wantPrefix == "" {
+				if len(fixes) != 1 {
+					t.Fatalf("expected only the SHELL fix, got %d fixes", len(fixes))
+				}
+				return
+			}
+			if len(fixes) != 2 {
+				t.Fatalf("expected 2 fixes, got %d", len(fixes))
+			}
+			got := string(fixpkg.ApplyFix([]byte(tt.dockerfile), fixes[1]))
+			if got != tt.wantPrefix {
+				t.Errorf("prefix fix result:\n%s\nwant:\n%s", got, tt.wantPrefix)
+			}
+		})
+	}
+}
+
 func TestDL4006Rule_SingleShellFixPerStage(t *testing.T) {
 	t.Parallel()
 
@@ -432,6 +528,83 @@ RUN wget -O - https://some.site | wc -l
 	})
 }
 
+func TestDL4006Rule_PlanAsync(t *testing.T) {
+	t.Parallel()
+
+	content := `FROM myorg/app:1
+RUN curl -fsSL https://example.com/install.sh | sh
+
+FROM alpine:3.20
+RUN echo no pipes here
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	requests := NewDL4006Rule().PlanAsync(input)
+	if len(requests) != 1 || requests[0].StageIndex != 0 {
+		t.Fatalf("expected one async request for stage 0, got %+v", requests)
+	}
+	handler := requests[0].Handler
+
+	tests := []struct {
+		name           string
+		cfg            *registry.ImageConfig
+		wantViolations int
+		wantFixShell   string
+	}{
+		{
+			name:           "image SHELL enables pipefail",
+			cfg:            &registry.ImageConfig{OS: "linux", Shell: []string{"/bin/bash", "-o", "pipefail", "-c"}},
+			wantViolations: 0,
+		},
+		{
+			name:           "windows image runs cmd",
+			cfg:            &registry.ImageConfig{OS: "windows"},
+			wantViolations: 0,
+		},
+		{
+			name:           "image SHELL without pipefail",
+			cfg:            &registry.ImageConfig{OS: "linux", Shell: []string{"/bin/zsh", "-c"}},
+			wantViolations: 1,
+			wantFixShell:   "/bin/zsh",
+		},
+		{
+			name:           "plain linux image",
+			cfg:            &registry.ImageConfig{OS: "linux"},
+			wantViolations: 1,
+			wantFixShell:   "/bin/bash",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var completed bool
+			var violations []rules.Violation
+			for _, r := range handler.OnSuccess(tt.cfg) {
+				switch v := r.(type) {
+				case async.CompletedCheck:
+					completed = completed || v.StageIndex == 0
+				case rules.Violation:
+					violations = append(violations, v)
+				}
+			}
+			if !completed {
+				t.Error("expected a CompletedCheck for stage 0")
+			}
+			if len(violations) != tt.wantViolations {
+				t.Fatalf("got %d violations, want %d", len(violations), tt.wantViolations)
+			}
+			if tt.wantFixShell == "" {
+				return
+			}
+			fix := violations[0].SuggestedFix
+			want := `SHELL ["` + tt.wantFixShell + `", "-o", "pipefail", "-c"]` + "\n"
+			if fix == nil || fix.Edits[0].NewText != want {
+				t.Errorf("fix = %+v, want SHELL insertion %q", fix, want)
+			}
+		})
+	}
+}
+
 func TestHasPipefailOption(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -1,6 +1,10 @@
 package rules
 
-import "github.com/moby/buildkit/frontend/dockerfile/parser"
+import (
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/sourcemap"
+)
 
 // Position represents a single point in a source file.
 // This is our JSON-serializable equivalent of parser.Position, which uses
@@ -66,6 +70,12 @@ func NewRangeLocation(file string, startLine, startCol, endLine, endCol int) Loc
 	}
 }
 
+// NewLocationFromSpan converts a sourcemap.Span, which uses 0-based lines, to
+// a Location.
+func NewLocationFromSpan(file string, s sourcemap.Span) Location {
+	return NewRangeLocation(file, s.StartLine+1, s.StartCol, s.EndLine+1, s.EndCol)
+}
+
 // NewLocationFromRange converts a BuildKit parser.Range to our Location type.
 // This bridges BuildKit's internal types with our output schema.
 // BuildKit uses 1-based line numbers. The mapping is direct—no adjustment needed.
@@ -57,6 +57,7 @@ func (r *Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "best-practices",
 		IsExperimental:  true,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -0,0 +1,104 @@
+package rules
+
+import "strings"
+
+// RegistryPolicy is the image registry policy from the top-level [registries]
+// config, shared by rules that check where images come from.
+type RegistryPolicy struct {
+	// Trusted lists the trusted registry patterns (see MatchRegistry).
+	Trusted []string
+
+	// Mirrors maps mirror registry hosts to the registry they mirror.
+	Mirrors map[string]string
+}
+
+// WithTrusted returns the policy with its trusted list replaced by a rule's
+// own list. An empty list keeps the inherited one.
+func (p RegistryPolicy) WithTrusted(trusted []string) RegistryPolicy {
+	if len(trusted) > 0 {
+		p.Trusted = trusted
+	}
+	return p
+}
+
+// Canonical returns the normalized name of registry, resolving Docker Hub
+// aliases and configured mirrors to the registry they stand for.
+func (p RegistryPolicy) Canonical(registry string) string {
+	registry = NormalizeRegistry(registry)
+	for mirror, upstream := range p.Mirrors {
+		if NormalizeRegistry(mirror) == registry {
+			return NormalizeRegistry(upstream)
+		}
+	}
+	return registry
+}
+
+// IsTrusted reports whether registry matches a trusted pattern. A mirror and
+// the registry it mirrors are interchangeable on either side.
+func (p RegistryPolicy) IsTrusted(registry string) bool {
+	normalized := NormalizeRegistry(registry)
+	canonical := p.Canonical(registry)
+	for _, t := range p.Trusted {
+		pattern := NormalizeRegistry(t)
+		if MatchRegistry(pattern, normalized) || MatchRegistry(p.Canonical(pattern), canonical) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRegistry reports whether a registry host, with an optional port,
+// matches a pattern. Patterns are:
+//   - "*", which matches any registry
+//   - "*.example.com", which matches any subdomain of example.com
+//   - "prefix*", which matches any host starting with prefix
+//   - an exact host
+//
+// A pattern with a port only matches that port; a pattern without one
+// matches the host on any port.
+func MatchRegistry(pattern, registry string) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	patternHost, patternPort := splitRegistryPort(pattern)
+	host, port := splitRegistryPort(registry)
+	if patternPort != "" && patternPort != port {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(patternHost, "*."):
+		// Keep the dot: ".example.com".
+		return strings.HasSuffix(host, patternHost[1:])
+	case strings.HasSuffix(patternHost, "*"):
+		return strings.HasPrefix(host, patternHost[:len(patternHost)-1])
+	}
+	return patternHost == host
+}
+
+// NormalizeRegistry normalizes a registry name for comparison: it trims
+// whitespace, lowercases, and maps Docker Hub aliases to "docker.io".
+func NormalizeRegistry(registry string) string {
+	registry = strings.ToLower(strings.TrimSpace(registry))
+	switch registry {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com", "hub.docker.com":
+		return "docker.io"
+	}
+	return registry
+}
+
+// splitRegistryPort splits "host:port" into host and port. The port is empty
+// when the registry has none.
+func splitRegistryPort(registry string) (string, string) {
+	i := strings.LastIndexByte(registry, ':')
+	if i < 0 || i == len(registry)-1 || strings.Contains(registry[i:], "]") {
+		return registry, ""
+	}
+	for _, c := range registry[i+1:] {
+		if c < '0' || c > '9' {
+			return registry, ""
+		}
+	}
+	return registry[:i], registry[i+1:]
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"slices"
 	"sync"
+
+	"github.com/wharflab/tally/internal/ruledeprecation"
 )
 
 func byCode(a, b Rule) int {
@@ -132,9 +134,30 @@ func DefaultRegistry() *Registry {
 	return defaultRegistry
 }
 
-// Register adds a rule to the default registry.
+// Register adds a rule to the default registry and records its former
+// codes and deprecation with ruledeprecation.
 func Register(rule Rule) {
+	meta := rule.Metadata()
+	if replacement, ok := ruledeprecation.ReplacementFor(meta.Code); ok {
+		panic(fmt.Sprintf("rule %q is a former code of %q", meta.Code, replacement))
+	}
+	for _, alias := range meta.Aliases {
+		if defaultRegistry.Has(alias) {
+			panic(fmt.Sprintf("rule %q alias %q is a registered rule", meta.Code, alias))
+		}
+	}
 	defaultRegistry.Register(rule)
+
+	for _, alias := range meta.Aliases {
+		ruledeprecation.Register(ruledeprecation.Renamed(alias, meta.Code))
+	}
+	if meta.Deprecated != "" {
+		ruledeprecation.Register(ruledeprecation.Entry{
+			Code:   meta.Code,
+			Kind:   ruledeprecation.KindDeadEnd,
+			Detail: meta.Deprecated,
+		})
+	}
 }
 
 // All returns all rules from the default registry.
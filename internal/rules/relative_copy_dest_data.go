@@ -0,0 +1,6 @@
+package rules
+
+// RelativeCopyDestinationRuleCode is the full rule code for the
+// relative-copy-destination rule. hadolint/DL3045 defers its COPY checks to
+// it when both are enabled.
+const RelativeCopyDestinationRuleCode = TallyRulePrefix + "relative-copy-destination"
@@ -73,15 +73,41 @@ type LintInput struct {
 	// Rules that coordinate with heredoc (like DL3003) should use this value.
 	// Zero means use the default (HeredocDefaultMinCommands).
 	HeredocMinCommands int
+
+	// Registries is the registry policy from the [registries] config.
+	// Rules with a trusted list of their own combine it via WithTrusted.
+	Registries RegistryPolicy
+
+	// sourceMap is the shared SourceMap of Source, set with WithSourceMap.
+	sourceMap *sourcemap.SourceMap
+}
+
+// WithSourceMap returns a copy of input whose SourceMap method returns sm
+// instead of building a new SourceMap on every call. sm must have been built
+// from input.Source. The lint pipeline and testutil.MakeLintInput set it once
+// per file, so the rules of a run share one SourceMap.
+func (input LintInput) WithSourceMap(sm *sourcemap.SourceMap) LintInput {
+	input.sourceMap = sm
+	return input
 }
 
-// SourceMap creates a SourceMap for snippet extraction and line-based operations.
-// The SourceMap is computed on demand from Source.
-// Results are not cached; call once and reuse if needed multiple times.
+// SourceMap returns a SourceMap for snippet extraction and line-based operations.
+// It returns the SourceMap set with WithSourceMap while Source is unchanged,
+// and otherwise computes one on demand from Source.
 func (input LintInput) SourceMap() *sourcemap.SourceMap {
+	if sm := input.sourceMap; sm != nil && sameBytes(sm.Source(), input.Source) {
+		return sm
+	}
 	return sourcemap.New(input.Source)
 }
 
+// sameBytes reports whether a and b are the same slice of the same array.
+// Derived inputs (e.g. ONBUILD or heredoc sub-lints) replace Source, and a
+// cached SourceMap must not outlive that.
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
 // Snippet extracts a range of lines from the source (0-based, inclusive).
 // This is a convenience wrapper around SourceMap().Snippet().
 func (input LintInput) Snippet(startLine, endLine int) string {
@@ -175,6 +201,29 @@ type RuleMetadata struct {
 	// Higher values = later application (structural transforms like prefer-run-heredoc).
 	// Default 0 is for content fixes. Use 100+ for structural transformations.
 	FixPriority int
+
+	// Fixes lists the safety levels of the fixes the rule can suggest,
+	// safest first. Empty for rules that never suggest a fix.
+	Fixes []FixSafety `json:",omitempty"`
+
+	// Supersedes lists the codes of rules that report the same problem as
+	// this rule. When both report on the same line, only this rule's
+	// violation is kept; the duplicate-precedence setting overrides the list.
+	Supersedes []string `json:",omitempty"`
+
+	// RelatedTo lists the codes of rules that check a neighbouring concern,
+	// e.g. the BuildKit or hadolint counterpart of a tally rule. Unlike
+	// Supersedes it never affects which violations are reported.
+	RelatedTo []string `json:",omitempty"`
+
+	// Aliases lists former codes of the rule (e.g. before a rename). Config
+	// and inline directives using them still apply to the rule, with a
+	// deprecation warning.
+	Aliases []string `json:",omitempty"`
+
+	// Deprecated explains why the rule is deprecated; configuring it produces
+	// a warning. Empty for supported rules.
+	Deprecated string `json:",omitempty"`
 }
 
 // Rule is the interface that all linting rules must implement.
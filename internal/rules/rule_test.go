@@ -2,6 +2,8 @@ package rules
 
 import (
 	"testing"
+
+	"github.com/wharflab/tally/internal/sourcemap"
 )
 
 func TestLintInput_SourceMap(t *testing.T) {
@@ -25,6 +27,24 @@ func TestLintInput_SourceMap(t *testing.T) {
 	}
 }
 
+func TestLintInput_WithSourceMap(t *testing.T) {
+	t.Parallel()
+	source := []byte("FROM alpine\nRUN echo hello\n")
+	sm := sourcemap.New(source)
+	input := LintInput{Source: source}.WithSourceMap(sm)
+
+	if got := input.SourceMap(); got != sm {
+		t.Error("SourceMap() did not return the shared SourceMap")
+	}
+
+	// A derived input with different Source must not reuse the cached map.
+	derived := input
+	derived.Source = []byte("FROM scratch\n")
+	if got := derived.SourceMap(); got == sm || got.Line(0) != "FROM scratch" {
+		t.Errorf("derived SourceMap().Line(0) = %q, want %q", got.Line(0), "FROM scratch")
+	}
+}
+
 func TestLintInput_Snippet(t *testing.T) {
 	t.Parallel()
 	source := []byte("line0\nline1\nline2\nline3\nline4")
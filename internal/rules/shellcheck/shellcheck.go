@@ -22,6 +22,7 @@ import (
 	"mvdan.cc/sh/v3/syntax"
 
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
 )
 
 const (
@@ -54,6 +55,9 @@ type task struct {
 
 type taskAppender struct {
 	tasks []task
+
+	// checker runs ShellCheck for every task added.
+	checker intshellcheck.Checker
 }
 
 func (a *taskAppender) add(fn func() []rules.Violation) {
@@ -72,14 +76,42 @@ type collectTasksContext struct {
 	escapeToken      rune
 }
 
-// Rule runs embedded ShellCheck on shell snippets in Dockerfile instructions.
+// Engines accepted by the engine option.
+const (
+	EngineEmbedded = "embedded"
+	EngineExternal = "external"
+)
+
+// Config is the configuration for the ShellCheck rule.
+type Config struct {
+	// Engine selects the ShellCheck implementation: the embedded WebAssembly
+	// build (default) or an installed shellcheck executable.
+	Engine string `json:"engine,omitempty"`
+
+	// Executable is the shellcheck executable used by the external engine.
+	// Empty means $TALLY_SHELLCHECK, then "shellcheck" from PATH.
+	Executable string `json:"executable,omitempty"`
+}
+
+// DefaultConfig returns the default ShellCheck rule configuration.
+func DefaultConfig() Config {
+	return Config{Engine: EngineEmbedded}
+}
+
+// Rule runs ShellCheck on shell snippets in Dockerfile instructions.
 type Rule struct {
 	runner *intshellcheck.Runner
+	schema map[string]any
 }
 
 func NewRule() *Rule {
+	schema, err := configutil.RuleSchema(ShellCheckRuleCode)
+	if err != nil {
+		panic(err)
+	}
 	return &Rule{
 		runner: intshellcheck.NewRunner(),
+		schema: schema,
 	}
 }
 
@@ -92,9 +124,33 @@ func (r *Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "best-practices",
 		IsExperimental:  true,
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *Rule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *Rule) DefaultConfig() any {
+	return DefaultConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *Rule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(ShellCheckRuleCode, config)
+}
+
+// checker returns the ShellCheck implementation selected by cfg.
+func (r *Rule) checker(cfg Config) intshellcheck.Checker {
+	if cfg.Engine == EngineExternal {
+		return intshellcheck.NewExternalRunner(cfg.Executable)
+	}
+	return r.runner
+}
+
 func (r *Rule) Check(input rules.LintInput) []rules.Violation {
 	meta := r.Metadata()
 	if !input.IsRuleEnabled(meta.Code) {
@@ -112,7 +168,8 @@ func (r *Rule) Check(input rules.LintInput) []rules.Violation {
 	shellDirectives := directive.Parse(sm, nil, nil).ShellDirectives
 	nodesByStartLine := extract.NodeIndexFromResult(ast)
 
-	tasks := r.collectTasks(input, sem, sm, nodesByStartLine, shellDirectives, ast.EscapeToken)
+	cfg := configutil.Coerce(input.Config, DefaultConfig())
+	tasks := r.collectTasks(input, r.checker(cfg), sem, sm, nodesByStartLine, shellDirectives, ast.EscapeToken)
 	if len(tasks) == 0 {
 		return nil
 	}
@@ -122,6 +179,7 @@ func (r *Rule) Check(input rules.LintInput) []rules.Violation {
 
 func (r *Rule) collectTasks(
 	input rules.LintInput,
+	checker intshellcheck.Checker,
 	sem *semantic.Model,
 	sm *sourcemap.SourceMap,
 	nodesByStartLine map[int]*parser.Node,
@@ -129,7 +187,7 @@ func (r *Rule) collectTasks(
 	escapeToken rune,
 ) []task {
 	ctx := collectTasksContext{
-		app: &taskAppender{},
+		app: &taskAppender{checker: checker},
 
 		input: input,
 		sem:   sem,
@@ -312,8 +370,9 @@ func (r *Rule) addShellSnippetTask(
 	shellNameForTask := shellName
 	knownEnvForTask := knownEnv
 	snippetForTask := snippet
+	checker := app.checker
 	app.add(func() []rules.Violation {
-		return r.checkShellSnippet(fileForTask, locationForTask, shellNameForTask, knownEnvForTask, snippetForTask)
+		return r.checkShellSnippet(checker, fileForTask, locationForTask, shellNameForTask, knownEnvForTask, snippetForTask)
 	})
 }
 
@@ -330,8 +389,9 @@ func (r *Rule) addShellMappingTask(
 	shellNameForTask := shellName
 	knownEnvForTask := knownEnv
 	mappingForTask := mapping
+	checker := app.checker
 	app.add(func() []rules.Violation {
-		return r.checkShellMapping(fileForTask, fallbackLocForTask, shellNameForTask, knownEnvForTask, mappingForTask)
+		return r.checkShellMapping(checker, fileForTask, fallbackLocForTask, shellNameForTask, knownEnvForTask, mappingForTask)
 	})
 }
 
@@ -344,6 +404,7 @@ func runTasks(tasks []task) []rules.Violation {
 }
 
 func (r *Rule) checkShellSnippet(
+	checker intshellcheck.Checker,
 	file string,
 	location []parser.Range,
 	shellName string,
@@ -399,7 +460,7 @@ func (r *Rule) checkShellSnippet(
 		exclude = append(exclude, "SC1128")
 	}
 
-	out, err := r.runShellcheck(script, intshellcheck.Options{
+	out, err := runShellcheck(checker, script, intshellcheck.Options{
 		Dialect:  dialect,
 		Severity: "style",
 		Norc:     true,
@@ -448,6 +509,7 @@ func shellSyntaxVariantForDialect(dialect string) syntax.LangVariant {
 }
 
 func (r *Rule) checkShellMapping(
+	checker intshellcheck.Checker,
 	file string,
 	fallbackLoc rules.Location,
 	shellName string,
@@ -494,7 +556,7 @@ func (r *Rule) checkShellMapping(
 		exclude = append(exclude, "SC1128")
 	}
 
-	out, err := r.runShellcheck(script, intshellcheck.Options{
+	out, err := runShellcheck(checker, script, intshellcheck.Options{
 		Dialect:  dialect,
 		Severity: "style",
 		Norc:     true,
@@ -540,17 +602,17 @@ func shellcheckRunContext() (context.Context, context.CancelFunc) {
 	return ctx, cancel
 }
 
-func (r *Rule) runShellcheck(script string, opts intshellcheck.Options) (intshellcheck.JSON1Output, error) {
+func runShellcheck(checker intshellcheck.Checker, script string, opts intshellcheck.Options) (intshellcheck.JSON1Output, error) {
 	ctx, cancel := shellcheckRunContext()
 	defer cancel()
-	out, _, err := r.runner.Run(ctx, script, opts)
+	out, _, err := checker.Run(ctx, script, opts)
 	return out, err
 }
 
 func shellcheckRunFailureWithNative(nativeViolations []rules.Violation, loc rules.Location, err error) []rules.Violation {
 	// Tool failures should not hard-fail linting; surface as a single violation
 	// on the instruction line once precise mapping lands.
-	msg := "failed to run embedded ShellCheck"
+	msg := "failed to run ShellCheck"
 	if strings.TrimSpace(err.Error()) != "" {
 		msg += ": " + err.Error()
 	}
@@ -728,18 +790,17 @@ func shellNameForLine(
 		return stageInfo.ShellNameAtLine(startLine)
 	}
 	// Fallback when semantic model is unavailable: use directive-based detection.
+	// The last directive before the instruction wins, whether it precedes the
+	// stage's FROM or sits inside the stage.
 	shellName := semantic.DefaultShell[0]
-	fromLine := -1
-	if len(stage.Location) > 0 {
-		fromLine = stage.Location[0].Start.Line - 1 // 0-based
-	}
-	if fromLine < 0 {
+	if len(stage.Location) == 0 || stage.Location[0].Start.Line < 1 {
 		return shellName
 	}
+	cmdLine := startLine - 1 // 0-based
 	bestLine := -1
 	for i := range directives {
 		sd := directives[i]
-		if sd.Line < fromLine && sd.Line > bestLine {
+		if sd.Line < cmdLine && sd.Line > bestLine {
 			bestLine = sd.Line
 			shellName = sd.Shell
 		}
@@ -1,6 +1,9 @@
 package shellcheck
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -123,6 +126,7 @@ func TestCheckShellSnippetReportsViolationOnFallbackLocation(t *testing.T) {
 	r := NewRule()
 
 	violations := r.checkShellSnippet(
+		r.runner,
 		"Dockerfile",
 		[]parser.Range{{Start: parser.Position{Line: 7, Character: 0}, End: parser.Position{Line: 7, Character: 12}}},
 		"/bin/sh",
@@ -151,6 +155,7 @@ func TestCheckShellSnippetSkipsNonPOSIXShell(t *testing.T) {
 
 	r := &Rule{}
 	violations := r.checkShellSnippet(
+		r.runner,
 		"Dockerfile",
 		[]parser.Range{{Start: parser.Position{Line: 2, Character: 0}}},
 		"pwsh",
@@ -167,6 +172,7 @@ func TestCheckShellSnippetSkipsNonParseableSnippet(t *testing.T) {
 
 	r := &Rule{}
 	violations := r.checkShellSnippet(
+		r.runner,
 		"Dockerfile",
 		[]parser.Range{{Start: parser.Position{Line: 2, Character: 0}}},
 		"/bin/sh",
@@ -192,6 +198,7 @@ func TestCheckShellSnippetParseErrorOwnsDiagnostics(t *testing.T) {
 
 	r := &Rule{}
 	violations := r.checkShellSnippet(
+		r.runner,
 		"Dockerfile",
 		[]parser.Range{{Start: parser.Position{Line: 10, Character: 0}}},
 		"/bin/sh",
@@ -211,6 +218,7 @@ func TestCheckShellSnippetSkipsBlankSnippet(t *testing.T) {
 
 	r := &Rule{}
 	violations := r.checkShellSnippet(
+		r.runner,
 		"Dockerfile",
 		[]parser.Range{{Start: parser.Position{Line: 2, Character: 0}}},
 		"/bin/sh",
@@ -369,3 +377,65 @@ func parseRunCommand(t *testing.T, dockerfileContent string) *instructions.RunCo
 	t.Fatal("no RUN command found")
 	return nil
 }
+
+func TestRule_ExternalEngineMapsHeredocComments(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake shellcheck executable is a shell script")
+	}
+
+	// Line 2 of the checked script is the heredoc's first line: line 1 is the
+	// synthetic shebang prelude.
+	fake := filepath.Join(t.TempDir(), "shellcheck")
+	script := `#!/bin/sh
+cat >/dev/null
+echo '{"comments":[{"file":"-","line":2,"endLine":2,"column":6,"endColumn":8,"level":"info","code":2086,"message":"Double quote to prevent globbing and word splitting."}]}'
+exit 1
+`
+	if err := os.WriteFile(fake, []byte(script), 0o700); err != nil { //nolint:gosec // test executable
+		t.Fatal(err)
+	}
+
+	input := makeShellcheckLintInput(t, `FROM alpine
+RUN <<EOF
+echo $1
+EOF
+`)
+	input.Config = Config{Engine: EngineExternal, Executable: fake}
+
+	violations := NewRule().Check(input)
+	var found bool
+	for _, v := range violations {
+		if v.RuleCode != "shellcheck/SC2086" {
+			continue
+		}
+		found = true
+		if v.Severity != rules.SeverityInfo {
+			t.Errorf("severity = %v, want info from the ShellCheck level", v.Severity)
+		}
+		if v.Location.Start.Line != 3 || v.Location.Start.Column != 5 {
+			t.Errorf("location = %d:%d, want 3:5", v.Location.Start.Line, v.Location.Start.Column)
+		}
+	}
+	if !found {
+		t.Fatalf("expected shellcheck/SC2086 from the external engine, got %+v", violations)
+	}
+}
+
+func TestRule_ExternalEngineMissingExecutable(t *testing.T) {
+	t.Parallel()
+
+	input := makeShellcheckLintInput(t, "FROM alpine\nRUN echo $1\n")
+	input.Config = map[string]any{
+		"engine":     EngineExternal,
+		"executable": filepath.Join(t.TempDir(), "missing-shellcheck"),
+	}
+
+	violations := NewRule().Check(input)
+	if len(violations) != 1 || violations[0].RuleCode != metaFailureRuleCode {
+		t.Fatalf("expected a single %s violation, got %+v", metaFailureRuleCode, violations)
+	}
+	if !strings.Contains(violations[0].Message, "not found") {
+		t.Errorf("message = %q, want the missing executable explained", violations[0].Message)
+	}
+}
@@ -0,0 +1,244 @@
+package tally
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/download"
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// AddChecksumRequiredRuleCode is the full rule code for the
+// add-checksum-required rule.
+const AddChecksumRequiredRuleCode = rules.TallyRulePrefix + "add-checksum-required"
+
+// AddChecksumRequiredRule reports ADD instructions that download a remote
+// file without --checksum.
+//
+// Without a checksum the build trusts whatever the server returns at build
+// time. With slow checks enabled, the rule downloads each file (or reads the
+// digest the server advertises) and suggests the --checksum flag to pin it.
+type AddChecksumRequiredRule struct{}
+
+// NewAddChecksumRequiredRule creates a new rule instance.
+func NewAddChecksumRequiredRule() *AddChecksumRequiredRule {
+	return &AddChecksumRequiredRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *AddChecksumRequiredRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            AddChecksumRequiredRuleCode,
+		Name:            "ADD Checksum Required",
+		Description:     "ADD of a remote URL should pin the file with --checksum",
+		DocURL:          rules.TallyDocURL(AddChecksumRequiredRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "security",
+		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// uncheckedAdd is an ADD of remote URLs without --checksum.
+type uncheckedAdd struct {
+	add      *instructions.AddCommand
+	urls     []string
+	stageIdx int
+}
+
+// fixable reports whether a checksum can be computed and added: BuildKit
+// accepts --checksum only for a single source, and the URL must not depend
+// on build arguments.
+func (u uncheckedAdd) fixable() bool {
+	return len(u.add.SourcePaths) == 1 && len(u.urls) == 1 && !strings.Contains(u.urls[0], "$")
+}
+
+// Check reports remote ADD sources without --checksum.
+func (r *AddChecksumRequiredRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for _, u := range uncheckedAdds(input.Stages) {
+		violations = append(violations, addChecksumViolation(meta, input.File, u))
+	}
+	return violations
+}
+
+// PlanAsync requests the checksums of the fixable ADD sources of each stage.
+// One request covers a whole stage, since a completed request replaces all
+// of the stage's fast-path violations.
+func (r *AddChecksumRequiredRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	meta := r.Metadata()
+	byStage := make(map[int][]uncheckedAdd)
+	var stageOrder []int
+	for _, u := range uncheckedAdds(input.Stages) {
+		if _, seen := byStage[u.stageIdx]; !seen {
+			stageOrder = append(stageOrder, u.stageIdx)
+		}
+		byStage[u.stageIdx] = append(byStage[u.stageIdx], u)
+	}
+
+	var requests []async.CheckRequest
+	for _, stageIdx := range stageOrder {
+		adds := byStage[stageIdx]
+		var urls []string
+		for _, u := range adds {
+			if u.fixable() && !slices.Contains(urls, u.urls[0]) {
+				urls = append(urls, u.urls[0])
+			}
+		}
+		if len(urls) == 0 {
+			continue
+		}
+		requests = append(requests, async.CheckRequest{
+			RuleCode:   meta.Code,
+			Category:   async.CategoryNetwork,
+			Key:        strings.Join(urls, "\n"),
+			ResolverID: download.ResolverID,
+			Data:       &download.Request{URLs: urls},
+			File:       input.File,
+			StageIndex: stageIdx,
+			Handler:    &addChecksumHandler{meta: meta, input: input, adds: adds},
+		})
+	}
+	return requests
+}
+
+// uncheckedAdds returns the ADD instructions with remote sources and no
+// --checksum, in file order. Git sources are left to their own checksum
+// (a commit ID), which tally/prefer-add-git covers.
+func uncheckedAdds(stages []instructions.Stage) []uncheckedAdd {
+	var out []uncheckedAdd
+	for stageIdx, stage := range stages {
+		for _, cmd := range stage.Commands {
+			add, ok := cmd.(*instructions.AddCommand)
+			if !ok || add.Checksum != "" {
+				continue
+			}
+			var urls []string
+			for _, src := range add.SourcePaths {
+				if shell.IsURL(src) && !isGitSourceURL(src) {
+					urls = append(urls, src)
+				}
+			}
+			if len(urls) > 0 {
+				out = append(out, uncheckedAdd{add: add, urls: urls, stageIdx: stageIdx})
+			}
+		}
+	}
+	return out
+}
+
+// isGitSourceURL reports whether BuildKit treats an http(s) ADD source as a
+// git repository, i.e. its path ends in ".git".
+func isGitSourceURL(src string) bool {
+	u, err := url.Parse(src)
+	return err == nil && strings.HasSuffix(u.Path, ".git")
+}
+
+func addChecksumViolation(meta rules.RuleMetadata, file string, u uncheckedAdd) rules.Violation {
+	msg := fmt.Sprintf("ADD downloads %s without --checksum", u.urls[0])
+	if len(u.urls) > 1 {
+		msg = fmt.Sprintf("ADD downloads %d remote files without --checksum", len(u.urls))
+	}
+	detail := "The build trusts whatever the server returns, so a compromised or changed file goes unnoticed. "
+	if len(u.add.SourcePaths) == 1 {
+		detail += "Pin it with --checksum=sha256:<digest>; with slow checks enabled, tally computes the digest."
+	} else {
+		detail += "--checksum pins a single source, so split the ADD into one instruction per URL."
+	}
+	v := rules.NewViolation(rules.NewLocationFromRanges(file, u.add.Location()), meta.Code, msg, meta.DefaultSeverity).
+		WithDocURL(meta.DocURL).
+		WithDetail(detail)
+	v.StageIndex = u.stageIdx
+	return v
+}
+
+// addChecksumHandler re-reports a stage's unchecked ADDs with a fix for
+// each one whose checksum was computed.
+type addChecksumHandler struct {
+	meta  rules.RuleMetadata
+	input rules.LintInput
+	adds  []uncheckedAdd
+}
+
+func (h *addChecksumHandler) OnSuccess(resolved any) []any {
+	sums, ok := resolved.(download.Checksums)
+	if !ok {
+		return nil
+	}
+	out := make([]any, 0, len(h.adds))
+	for _, u := range h.adds {
+		v := addChecksumViolation(h.meta, h.input.File, u)
+		if sum := sums[u.urls[0]]; sum != "" && u.fixable() {
+			if fix := addChecksumFix(h.input, u.add, sum); fix != nil {
+				v = v.WithSuggestedFix(fix)
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// addChecksumFix inserts --checksum=sha256:<sum> into the ADD, in the slot
+// tally/flag-order expects it.
+func addChecksumFix(input rules.LintInput, add *instructions.AddCommand, sum string) *rules.SuggestedFix {
+	node := nodeAtLine(input.AST, add.Location())
+	sm := input.SourceMap()
+	if node == nil || sm == nil {
+		return nil
+	}
+	flag := "--checksum=sha256:" + sum
+	slots := scanFlagSlots(sm, node, byte(dockerfile.ASTEscapeToken(input.AST)))
+	if len(slots) != len(node.Flags) {
+		return nil
+	}
+
+	order := canonicalFlagOrder[command.Add]
+	rank := flagRank(order, flag)
+	line, col, text := node.StartLine, addKeywordEnd(sm.Line(node.StartLine-1)), " "+flag
+	for _, slot := range slots {
+		if flagRank(order, slot.text) > rank {
+			line, col, text = slot.line, slot.start, flag+" "
+			break
+		}
+		line, col = slot.line, slot.end
+	}
+	return &rules.SuggestedFix{
+		Description: "Add --checksum=sha256:" + shortDigest(sum),
+		Safety:      rules.FixSuggestion,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(input.File, line, col, line, col),
+			NewText:  text,
+		}},
+	}
+}
+
+// addKeywordEnd returns the column just past the instruction keyword.
+func addKeywordEnd(line string) int {
+	col := len(line) - len(strings.TrimLeft(line, " \t"))
+	for col < len(line) && line[col] != ' ' && line[col] != '\t' {
+		col++
+	}
+	return col
+}
+
+// shortDigest abbreviates a hex digest for display.
+func shortDigest(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12] + "…"
+	}
+	return sum
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewAddChecksumRequiredRule())
+}
@@ -0,0 +1,156 @@
+package tally
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/async/download"
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+const testChecksum = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func TestAddChecksumRequiredMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewAddChecksumRequiredRule().Metadata())
+}
+
+func TestAddChecksumRequiredRule(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewAddChecksumRequiredRule(), []testutil.RuleTestCase{
+		{
+			Name: "remote file without checksum",
+			Content: `FROM alpine:3.20
+ADD https://example.com/tool.tar.gz /tmp/
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"ADD downloads https://example.com/tool.tar.gz without --checksum"},
+		},
+		{
+			Name: "remote file with checksum",
+			Content: `FROM alpine:3.20
+ADD --checksum=sha256:` + testChecksum + ` https://example.com/tool.tar.gz /tmp/
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "local files and git sources",
+			Content: `FROM alpine:3.20
+ADD app.tar.gz /app/
+ADD https://github.com/example/repo.git /src
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "several remote sources",
+			Content: `FROM alpine:3.20
+ADD https://example.com/a https://example.com/b /opt/
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"ADD downloads 2 remote files without --checksum"},
+		},
+	})
+}
+
+func TestAddChecksumRequiredPlanAsync(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20 AS fetch
+ARG VERSION=1.0
+ADD https://example.com/tool-${VERSION}.tar.gz /tmp/
+ADD https://example.com/a https://example.com/b /opt/
+
+FROM alpine:3.20
+ADD https://example.com/tool /usr/local/bin/tool
+ADD --link https://example.com/tool /opt/tool
+`
+	reqs := NewAddChecksumRequiredRule().PlanAsync(testutil.MakeLintInput(t, "Dockerfile", content))
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1 for the stage with fixable ADDs", len(reqs))
+	}
+	data, ok := reqs[0].Data.(*download.Request)
+	if !ok || reqs[0].ResolverID != download.ResolverID || reqs[0].StageIndex != 1 ||
+		len(data.URLs) != 1 || data.URLs[0] != "https://example.com/tool" {
+		t.Errorf("request = %+v", reqs[0])
+	}
+}
+
+func TestAddChecksumRequiredHandler(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20
+ADD https://example.com/tool /usr/local/bin/tool
+ADD --chmod=755 --link https://example.com/run.sh /run.sh
+ADD https://example.com/a https://example.com/b /opt/
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	reqs := NewAddChecksumRequiredRule().PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	handler := reqs[0].Handler
+	if out := handler.OnSuccess("nope"); out != nil {
+		t.Errorf("wrong type: got %v, want nil", out)
+	}
+
+	out := handler.OnSuccess(download.Checksums{
+		"https://example.com/tool":   testChecksum,
+		"https://example.com/run.sh": testChecksum,
+	})
+	if len(out) != 3 {
+		t.Fatalf("got %d results, want all 3 violations of the stage", len(out))
+	}
+	violations := make([]rules.Violation, 0, len(out))
+	for _, o := range out {
+		v, ok := o.(rules.Violation)
+		if !ok {
+			t.Fatalf("result %T is not a violation", o)
+		}
+		violations = append(violations, v)
+	}
+	if violations[2].SuggestedFix != nil {
+		t.Errorf("multi-source ADD should have no fix: %+v", violations[2].SuggestedFix)
+	}
+	if fix := violations[0].SuggestedFix; fix == nil || fix.Safety != rules.FixSuggestion {
+		t.Fatalf("want a suggestion fix, got %+v", fix)
+	}
+
+	result, err := (&fixpkg.Fixer{SafetyThreshold: rules.FixSuggestion}).Apply(
+		context.Background(),
+		violations,
+		map[string][]byte{"Dockerfile": []byte(content)},
+	)
+	if err != nil {
+		t.Fatalf("apply fixes: %v", err)
+	}
+	want := `FROM alpine:3.20
+ADD --checksum=sha256:` + testChecksum + ` https://example.com/tool /usr/local/bin/tool
+ADD --checksum=sha256:` + testChecksum + ` --chmod=755 --link https://example.com/run.sh /run.sh
+ADD https://example.com/a https://example.com/b /opt/
+`
+	if got := string(result.Changes["Dockerfile"].ModifiedContent); got != want {
+		t.Errorf("fixed content =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAddChecksumFixAfterEarlierFlags(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine:3.20\nADD --keep-git-dir=false \\\n    https://example.com/tool /tool\n"
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	adds := uncheckedAdds(input.Stages)
+	if len(adds) != 1 {
+		t.Fatalf("got %d unchecked ADDs, want 1", len(adds))
+	}
+	fix := addChecksumFix(input, adds[0].add, testChecksum)
+	if fix == nil || len(fix.Edits) != 1 {
+		t.Fatalf("want a single-edit fix, got %+v", fix)
+	}
+	edit := fix.Edits[0]
+	if edit.Location.Start.Line != 2 || edit.Location.Start.Column != len("ADD --keep-git-dir=false") ||
+		!strings.HasPrefix(edit.NewText, " --checksum=sha256:") {
+		t.Errorf("edit = %+v", edit)
+	}
+}
@@ -0,0 +1,264 @@
+package tally
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// AllowedBaseImagesRuleCode is the full rule code for the allowed-base-images rule.
+const AllowedBaseImagesRuleCode = rules.TallyRulePrefix + "allowed-base-images"
+
+// BaseImagePolicy is an allowlist and a denylist of image patterns.
+type BaseImagePolicy struct {
+	// Allow lists the patterns base images must match. Empty allows every
+	// image that is not denied.
+	Allow []string `json:"allow,omitempty" koanf:"allow"`
+
+	// Deny lists the patterns base images must not match. Deny wins over allow.
+	Deny []string `json:"deny,omitempty" koanf:"deny"`
+}
+
+// AllowedBaseImagesConfig is the configuration for the allowed-base-images rule.
+type AllowedBaseImagesConfig struct {
+	// Allow lists the patterns base images of every stage must match.
+	Allow []string `json:"allow,omitempty" koanf:"allow"`
+
+	// Deny lists the patterns no base image may match.
+	Deny []string `json:"deny,omitempty" koanf:"deny"`
+
+	// Final overrides the allowlist for stages exported in the image and
+	// adds to the denylist.
+	Final *BaseImagePolicy `json:"final,omitempty" koanf:"final"`
+
+	// Builder overrides the allowlist for builder stages and adds to the
+	// denylist.
+	Builder *BaseImagePolicy `json:"builder,omitempty" koanf:"builder"`
+}
+
+// DefaultAllowedBaseImagesConfig returns the default configuration.
+// Nothing is restricted until patterns are configured.
+func DefaultAllowedBaseImagesConfig() AllowedBaseImagesConfig {
+	return AllowedBaseImagesConfig{}
+}
+
+// policyFor returns the effective policy for a stage role: the role's
+// allowlist replaces the top-level one when set, and the denylists add up.
+func (c AllowedBaseImagesConfig) policyFor(role semantic.StageRole) BaseImagePolicy {
+	policy := BaseImagePolicy{Allow: c.Allow, Deny: c.Deny}
+	var override *BaseImagePolicy
+	switch role {
+	case semantic.StageRoleFinal:
+		override = c.Final
+	case semantic.StageRoleBuilder:
+		override = c.Builder
+	}
+	if override == nil {
+		return policy
+	}
+	if len(override.Allow) > 0 {
+		policy.Allow = override.Allow
+	}
+	policy.Deny = append(append([]string(nil), policy.Deny...), override.Deny...)
+	return policy
+}
+
+// AllowedBaseImagesRule restricts the images stages may be built FROM to an
+// organization's policy, e.g. only images from an internal registry or a
+// hardened vendor. Builder stages and final stages can have separate lists,
+// since build toolchains rarely ship in the minimal images allowed at
+// runtime. Off by default; configuring it enables it.
+type AllowedBaseImagesRule struct {
+	schema map[string]any
+}
+
+// NewAllowedBaseImagesRule creates a new allowed-base-images rule instance.
+func NewAllowedBaseImagesRule() *AllowedBaseImagesRule {
+	schema, err := configutil.RuleSchema(AllowedBaseImagesRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &AllowedBaseImagesRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *AllowedBaseImagesRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            AllowedBaseImagesRuleCode,
+		Name:            "Allowed base images",
+		Description:     "Base images must match the configured allowlist and must not match the denylist",
+		DocURL:          rules.TallyDocURL(AllowedBaseImagesRuleCode),
+		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when configured
+		Category:        "security",
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *AllowedBaseImagesRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *AllowedBaseImagesRule) DefaultConfig() any {
+	return DefaultAllowedBaseImagesConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema
+// and checks that every pattern is a valid glob.
+func (r *AllowedBaseImagesRule) ValidateConfig(config any) error {
+	if err := configutil.ValidateRuleOptions(AllowedBaseImagesRuleCode, config); err != nil {
+		return err
+	}
+	cfg := configutil.Coerce(config, DefaultAllowedBaseImagesConfig())
+	lists := [][]string{cfg.Allow, cfg.Deny}
+	if cfg.Final != nil {
+		lists = append(lists, cfg.Final.Allow, cfg.Final.Deny)
+	}
+	if cfg.Builder != nil {
+		lists = append(lists, cfg.Builder.Allow, cfg.Builder.Deny)
+	}
+	for _, patterns := range lists {
+		for _, pattern := range patterns {
+			if _, err := compileImagePattern(pattern); err != nil {
+				return fmt.Errorf("invalid image pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Check reports external base images that the policy for their stage's role
+// does not allow. Images are matched after ARG expansion; references that do
+// not resolve to a valid image (an ARG without a default, for instance) are
+// skipped.
+func (r *AllowedBaseImagesRule) Check(input rules.LintInput) []rules.Violation {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+	cfg := configutil.Coerce(input.Config, DefaultAllowedBaseImagesConfig())
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for info := range sem.ExternalImageStages() {
+		base := info.BaseImage
+		if base == nil {
+			continue
+		}
+		ref := base.Effective
+		if ref == "" {
+			ref = base.Raw
+		}
+		named, err := reference.ParseNormalizedNamed(ref)
+		if err != nil {
+			continue
+		}
+
+		role := sem.StageRole(info.Index)
+		policy := cfg.policyFor(role)
+		candidates := imageMatchCandidates(named)
+		var msg string
+		if pattern, ok := matchImagePattern(policy.Deny, candidates); ok {
+			msg = fmt.Sprintf("base image %s is denied by pattern %q", ref, pattern)
+		} else if len(policy.Allow) > 0 {
+			if _, ok := matchImagePattern(policy.Allow, candidates); !ok {
+				msg = fmt.Sprintf("base image %s is not allowed in %s stages (allowed: %s)",
+					ref, role, strings.Join(policy.Allow, ", "))
+			}
+		}
+		if msg == "" {
+			continue
+		}
+
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(input.File, base.Location), meta.Code, msg, rules.SeverityWarning,
+		).WithDocURL(meta.DocURL)
+		if base.Effective != base.Raw {
+			v = v.WithDetail(fmt.Sprintf("FROM %s resolves to %s with the default build arguments.", base.Raw, ref))
+		}
+		v.StageIndex = info.Index
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// imageMatchCandidates returns the forms of an image reference a pattern is
+// matched against: fully qualified and familiar, each with and without the
+// tag or digest. This lets "alpine:*", "docker.io/library/alpine" and
+// "cgr.dev/chainguard/*" all match what users expect. An untagged image is
+// matched as :latest, the tag BuildKit pulls.
+func imageMatchCandidates(named reference.Named) []string {
+	named = reference.TagNameOnly(named)
+	name := named.Name()
+	familiar := reference.FamiliarName(named)
+	candidates := []string{named.String(), reference.FamiliarString(named), name, familiar}
+	if tagged, ok := named.(reference.Tagged); ok {
+		if _, digested := named.(reference.Digested); digested {
+			// name:tag@digest: also offer the tag-only forms.
+			candidates = append(candidates, name+":"+tagged.Tag(), familiar+":"+tagged.Tag())
+		}
+	}
+	return candidates
+}
+
+// matchImagePattern returns the first pattern that matches any candidate.
+func matchImagePattern(patterns, candidates []string) (string, bool) {
+	for _, pattern := range patterns {
+		re, err := compileImagePattern(pattern)
+		if err != nil {
+			continue // rejected by ValidateConfig
+		}
+		for _, candidate := range candidates {
+			if re.MatchString(candidate) {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+// compileImagePattern compiles an image glob. Unlike path globs, "*" also
+// matches "/", so "registry.corp/*" covers every repository of the registry
+// and "*:latest" every image tagged latest. "?" matches one character and
+// "[...]" a character class.
+func compileImagePattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewAllowedBaseImagesRule())
+}
@@ -0,0 +1,112 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestAllowedBaseImagesRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewAllowedBaseImagesRule().Metadata())
+}
+
+func TestAllowedBaseImagesRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewAllowedBaseImagesRule(), []testutil.RuleTestCase{
+		{
+			Name:           "nothing configured",
+			Content:        "FROM alpine:3.20\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "image outside the allowlist",
+			Content:        "FROM registry.corp/base/alpine:3.20 AS base\nFROM alpine:3.20\n",
+			Config:         AllowedBaseImagesConfig{Allow: []string{"registry.corp/*"}},
+			WantViolations: 1,
+			WantCodes:      []string{AllowedBaseImagesRuleCode},
+			WantMessages:   []string{"base image alpine:3.20 is not allowed in final stages (allowed: registry.corp/*)"},
+		},
+		{
+			Name:           "familiar and qualified patterns",
+			Content:        "FROM docker.io/library/alpine:3.20\nFROM golang:1.25\n",
+			Config:         AllowedBaseImagesConfig{Allow: []string{"alpine:*", "docker.io/library/golang"}},
+			WantViolations: 0,
+		},
+		{
+			Name:           "deny wins over allow",
+			Content:        "FROM registry.corp/app\n",
+			Config:         AllowedBaseImagesConfig{Allow: []string{"registry.corp/*"}, Deny: []string{"*:latest"}},
+			WantViolations: 1,
+			WantMessages:   []string{`base image registry.corp/app is denied by pattern "*:latest"`},
+		},
+		{
+			Name:    "builder stages use a broader allowlist",
+			Content: "FROM golang:1.25 AS build\nFROM node:22 AS assets\nFROM cgr.dev/chainguard/static\nCOPY --from=build /app /app\n",
+			Config: AllowedBaseImagesConfig{
+				Allow:   []string{"cgr.dev/chainguard/*"},
+				Builder: &BaseImagePolicy{Allow: []string{"cgr.dev/chainguard/*", "golang:*"}},
+			},
+			WantViolations: 1,
+			WantMessages:   []string{"base image node:22 is not allowed in builder stages"},
+		},
+		{
+			Name:    "stages the final stage is built from are final",
+			Content: "FROM golang:1.25 AS base\nFROM base\n",
+			Config: AllowedBaseImagesConfig{
+				Final:   &BaseImagePolicy{Allow: []string{"cgr.dev/chainguard/*"}},
+				Builder: &BaseImagePolicy{Allow: []string{"golang:*"}},
+			},
+			WantViolations: 1,
+			WantMessages:   []string{"base image golang:1.25 is not allowed in final stages"},
+		},
+		{
+			Name:    "role denylists add to the top-level one",
+			Content: "FROM alpine:3.20 AS build\nFROM busybox:1.37\n",
+			Config: AllowedBaseImagesConfig{
+				Deny:  []string{"ubuntu:*"},
+				Final: &BaseImagePolicy{Deny: []string{"busybox:*"}},
+			},
+			WantViolations: 1,
+			WantMessages:   []string{`base image busybox:1.37 is denied by pattern "busybox:*"`},
+		},
+		{
+			Name:           "ARG defaults are expanded",
+			Content:        "ARG REGISTRY=docker.io\nFROM ${REGISTRY}/library/alpine:3.20\n",
+			Config:         AllowedBaseImagesConfig{Allow: []string{"registry.corp/*"}},
+			WantViolations: 1,
+			WantMessages:   []string{"base image docker.io/library/alpine:3.20 is not allowed"},
+		},
+		{
+			Name:           "unresolved ARGs are skipped",
+			Content:        "ARG BASE\nFROM ${BASE}:3.20\n",
+			Config:         AllowedBaseImagesConfig{Allow: []string{"registry.corp/*"}},
+			WantViolations: 0,
+		},
+		{
+			Name:           "scratch and stage references are skipped",
+			Content:        "FROM registry.corp/go:1.25 AS build\nFROM scratch\nFROM build\n",
+			Config:         AllowedBaseImagesConfig{Allow: []string{"registry.corp/*"}},
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestAllowedBaseImagesRule_ValidateConfig(t *testing.T) {
+	t.Parallel()
+	r := NewAllowedBaseImagesRule()
+	if err := r.ValidateConfig(map[string]any{
+		"allow":   []any{"registry.corp/*"},
+		"builder": map[string]any{"allow": []any{"golang:*"}},
+	}); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := r.ValidateConfig(map[string]any{"deny": []any{"alpine:[3"}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if err := r.ValidateConfig(map[string]any{"final": map[string]any{"only": []any{"alpine"}}}); err == nil {
+		t.Error("expected an error for an unknown option")
+	}
+}
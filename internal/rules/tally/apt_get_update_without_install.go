@@ -0,0 +1,226 @@
+package tally
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// AptGetUpdateWithoutInstallRuleCode is the full rule code for the apt-get-update-without-install rule.
+const AptGetUpdateWithoutInstallRuleCode = rules.TallyRulePrefix + "apt-get-update-without-install"
+
+// AptGetUpdateWithoutInstallRule reports a RUN that refreshes the apt package
+// lists when the packages are installed by a later RUN of the same stage:
+//
+//	RUN apt-get update
+//	RUN apt-get install -y curl
+//
+// Each RUN is cached on its own instruction text. When the install line
+// changes, the update layer is reused from the cache and the install runs
+// against package lists that may be months old, failing on removed package
+// versions or silently installing outdated ones.
+type AptGetUpdateWithoutInstallRule struct{}
+
+// NewAptGetUpdateWithoutInstallRule creates a new apt-get-update-without-install rule instance.
+func NewAptGetUpdateWithoutInstallRule() *AptGetUpdateWithoutInstallRule {
+	return &AptGetUpdateWithoutInstallRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *AptGetUpdateWithoutInstallRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            AptGetUpdateWithoutInstallRuleCode,
+		Name:            "apt-get update Without install",
+		Description:     "apt-get update runs in a different RUN than the apt-get install that needs it",
+		DocURL:          rules.TallyDocURL(AptGetUpdateWithoutInstallRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "reliability",
+		IsExperimental:  false,
+		FixPriority:     96, //nolint:mnd // Merge the RUNs after prefer-package-cache-mounts (90) has added mounts to them.
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		RelatedTo:       []string{PreferPackageCacheMountsRuleCode},
+	}
+}
+
+// aptUpdateRun is a RUN that refreshes the package lists without installing.
+type aptUpdateRun struct {
+	run    *facts.RunFacts
+	update shell.CommandInfo
+}
+
+// Check runs the apt-get-update-without-install rule.
+func (r *AptGetUpdateWithoutInstallRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Facts == nil {
+		return nil
+	}
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	escapeToken := dockerfile.ASTEscapeToken(input.AST)
+
+	var violations []rules.Violation
+	for stageIdx := range input.Stages {
+		sf := input.Facts.Stage(stageIdx)
+		if sf == nil {
+			continue
+		}
+		var pending *aptUpdateRun
+		for _, run := range sf.Runs {
+			update, hasUpdate := findAptCommand(run.CommandInfos, "update")
+			install, hasInstall := findAptCommand(run.CommandInfos, "install")
+			switch {
+			case hasUpdate && hasInstall:
+				pending = nil
+			case hasUpdate:
+				pending = &aptUpdateRun{run: run, update: update}
+			case hasInstall && pending != nil:
+				violations = append(violations,
+					aptSplitUpdateViolation(input.File, sm, escapeToken, meta, pending, run, install))
+				pending = nil
+			}
+		}
+	}
+	return violations
+}
+
+// findAptCommand returns the first apt-get or apt command with the given subcommand.
+func findAptCommand(cmds []shell.CommandInfo, subcommand string) (shell.CommandInfo, bool) {
+	for _, cmd := range cmds {
+		if (cmd.Name == "apt-get" || cmd.Name == "apt") && cmd.Subcommand == subcommand {
+			return cmd, true
+		}
+	}
+	return shell.CommandInfo{}, false
+}
+
+// aptSplitUpdateViolation reports the update RUN of a split update/install pair.
+func aptSplitUpdateViolation(
+	file string,
+	sm *sourcemap.SourceMap,
+	escapeToken rune,
+	meta rules.RuleMetadata,
+	pending *aptUpdateRun,
+	installRun *facts.RunFacts,
+	install shell.CommandInfo,
+) rules.Violation {
+	installLine := installRun.Run.Location()[0].Start.Line
+	v := rules.NewViolation(
+		rules.NewLocationFromRanges(file, pending.run.Run.Location()),
+		meta.Code,
+		fmt.Sprintf("`%s update` runs in a separate RUN from `%s install` (line %d)",
+			pending.update.Name, install.Name, installLine),
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(
+		"Each RUN is cached separately. When the install RUN changes, the update RUN is reused from the " +
+			"build cache and the packages are installed from stale package lists, which fails on removed " +
+			"versions or installs outdated ones. Run `apt-get update && apt-get install` in the same RUN.",
+	)
+	if fix := aptSplitUpdateFix(file, sm, escapeToken, meta, pending, installRun, install); fix != nil {
+		v = v.WithSuggestedFix(fix)
+	}
+	return v
+}
+
+// aptSplitUpdateFix prepends the update command to the install command.
+// When the update RUN does nothing else and directly precedes the install
+// RUN, it is removed, merging the two RUNs.
+func aptSplitUpdateFix(
+	file string,
+	sm *sourcemap.SourceMap,
+	escapeToken rune,
+	meta rules.RuleMetadata,
+	pending *aptUpdateRun,
+	installRun *facts.RunFacts,
+	install shell.CommandInfo,
+) *rules.SuggestedFix {
+	run := installRun.Run
+	if sm == nil || !run.PrependShell || len(run.Files) > 0 || !installRun.Shell.Variant.SupportsPOSIXShellAST() {
+		return nil
+	}
+	script, startLine := dockerfile.RunSourceScript(run, sm, escapeToken)
+	if script == "" {
+		return nil
+	}
+	installs := shell.FindCommands(script, installRun.Shell.Variant, install.Name)
+	idx := slices.IndexFunc(installs, func(cmd shell.CommandInfo) bool { return cmd.Subcommand == "install" })
+	if idx < 0 {
+		return nil
+	}
+	target := installs[idx]
+	if !startsShellCommand(script, target.Line, target.StartCol, escapeToken) {
+		return nil
+	}
+
+	updateCmd := strings.Join(append([]string{pending.update.Name}, pending.update.Args...), " ")
+	editLine := startLine + target.Line
+	edits := []rules.TextEdit{{
+		Location: rules.NewRangeLocation(file, editLine, target.StartCol, editLine, target.StartCol),
+		NewText:  updateCmd + " && ",
+	}}
+
+	description := fmt.Sprintf("Run `%s` in the install RUN", updateCmd)
+	if aptUpdateOnly(pending) && installRun.CommandIndex == pending.run.CommandIndex+1 {
+		updateLoc := pending.run.Run.Location()
+		edits = append(edits, rules.TextEdit{
+			Location: rules.NewRangeLocation(file,
+				updateLoc[0].Start.Line, 0,
+				sm.ResolveEndLineWithEscape(updateLoc[len(updateLoc)-1].End.Line, escapeToken)+1, 0),
+			NewText: "",
+		})
+		description = fmt.Sprintf("Merge the `%s` RUN into the install RUN", updateCmd)
+	}
+
+	return &rules.SuggestedFix{
+		Description: description,
+		Safety:      rules.FixSuggestion,
+		Priority:    meta.FixPriority,
+		Edits:       edits,
+	}
+}
+
+// aptUpdateOnly reports whether the update RUN can be removed once the
+// install RUN refreshes the package lists itself: a plain shell-form RUN
+// whose only commands are package list updates.
+func aptUpdateOnly(pending *aptUpdateRun) bool {
+	run := pending.run.Run
+	if !isPlainShellRun(run) || len(pending.run.CommandInfos) == 0 {
+		return false
+	}
+	for _, cmd := range pending.run.CommandInfos {
+		if (cmd.Name != "apt-get" && cmd.Name != "apt") || cmd.Subcommand != "update" {
+			return false
+		}
+	}
+	return true
+}
+
+// startsShellCommand reports whether the command at the given script position
+// starts a statement or follows `&&`, so that prepending `cmd && ` to it keeps
+// the meaning of the surrounding script. Commands after `||`, inside pipes,
+// or prefixed with variable assignments are not.
+func startsShellCommand(script string, line, col int, escapeToken rune) bool {
+	lines := strings.Split(script, "\n")
+	if line < 0 || line >= len(lines) || col > len(lines[line]) {
+		return false
+	}
+	before := strings.Join(append(slices.Clone(lines[:line]), lines[line][:col]), "\n")
+	for {
+		trimmed := strings.TrimRight(before, " \t\r\n")
+		trimmed = strings.TrimSuffix(trimmed, string(escapeToken))
+		if trimmed == before {
+			break
+		}
+		before = trimmed
+	}
+	return before == "" || strings.HasSuffix(before, "&&") || strings.HasSuffix(before, ";")
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewAptGetUpdateWithoutInstallRule())
+}
@@ -0,0 +1,132 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestAptGetUpdateWithoutInstallRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewAptGetUpdateWithoutInstallRule().Metadata())
+}
+
+func TestAptGetUpdateWithoutInstallRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewAptGetUpdateWithoutInstallRule(), []testutil.RuleTestCase{
+		{
+			Name:           "update and install in one RUN",
+			Content:        "FROM debian:bookworm\nRUN apt-get update && apt-get install -y curl\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "update and install in consecutive RUNs",
+			Content:        "FROM debian:bookworm\nRUN apt-get update\nRUN apt-get install -y curl\n",
+			WantViolations: 1,
+			WantCodes:      []string{AptGetUpdateWithoutInstallRuleCode},
+			WantMessages:   []string{"`apt-get update` runs in a separate RUN from `apt-get install` (line 3)"},
+		},
+		{
+			Name:           "other instructions in between",
+			Content:        "FROM debian:bookworm\nRUN apt update\nENV DEBIAN_FRONTEND=noninteractive\nRUN apt install -y curl\n",
+			WantViolations: 1,
+			WantMessages:   []string{"`apt update` runs in a separate RUN from `apt install` (line 4)"},
+		},
+		{
+			Name: "one report per update",
+			Content: "FROM debian:bookworm\nRUN apt-get update\n" +
+				"RUN apt-get install -y curl\nRUN apt-get install -y git\n",
+			WantViolations: 1,
+		},
+		{
+			Name: "install after a RUN that updates and installs",
+			Content: "FROM debian:bookworm\nRUN apt-get update\n" +
+				"RUN apt-get update && apt-get install -y curl\nRUN apt-get install -y git\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "update in another stage",
+			Content:        "FROM debian:bookworm AS base\nRUN apt-get update\nFROM base\nRUN apt-get install -y curl\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "update without install",
+			Content:        "FROM debian:bookworm\nRUN apt-get update\nRUN echo done\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestAptGetUpdateWithoutInstallRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "consecutive RUNs are merged",
+			content: "FROM debian:bookworm\nRUN apt-get update -qq\nRUN apt-get install -y curl\n",
+			want:    "FROM debian:bookworm\nRUN apt-get update -qq && apt-get install -y curl\n",
+		},
+		{
+			name:    "continuation lines",
+			content: "FROM debian:bookworm\nRUN apt-get update\nRUN mkdir -p /app && \\\n    apt-get install -y curl\n",
+			want:    "FROM debian:bookworm\nRUN mkdir -p /app && \\\n    apt-get update && apt-get install -y curl\n",
+		},
+		{
+			name: "update RUN with other commands is kept",
+			content: "FROM debian:bookworm\nRUN apt-get update && apt-get upgrade -y\n" +
+				"RUN apt-get install -y curl\n",
+			want: "FROM debian:bookworm\nRUN apt-get update && apt-get upgrade -y\n" +
+				"RUN apt-get update && apt-get install -y curl\n",
+		},
+		{
+			name:    "non-adjacent update RUN is kept",
+			content: "FROM debian:bookworm\nRUN apt-get update\nWORKDIR /app\nRUN apt-get install -y curl\n",
+			want:    "FROM debian:bookworm\nRUN apt-get update\nWORKDIR /app\nRUN apt-get update && apt-get install -y curl\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewAptGetUpdateWithoutInstallRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			fix := violations[0].SuggestedFix
+			if fix == nil {
+				t.Fatal("violation has no SuggestedFix")
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.content), fix)); got != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAptGetUpdateWithoutInstallRule_NoFix(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{
+		// Prepending to the install would only run it when `false` fails.
+		"FROM debian:bookworm\nRUN apt-get update\nRUN false || apt-get install -y curl\n",
+		// The assignment would move to the update command.
+		"FROM debian:bookworm\nRUN apt-get update\nRUN DEBIAN_FRONTEND=noninteractive apt-get install -y curl\n",
+		"FROM debian:bookworm\nRUN apt-get update\nRUN [\"apt-get\", \"install\", \"-y\", \"curl\"]\n",
+	} {
+		violations := NewAptGetUpdateWithoutInstallRule().Check(testutil.MakeLintInput(t, "Dockerfile", content))
+		if len(violations) != 1 {
+			t.Fatalf("got %d violations for %q, want 1", len(violations), content)
+		}
+		if violations[0].SuggestedFix != nil {
+			t.Errorf("unexpected fix for %q: %+v", content, violations[0].SuggestedFix)
+		}
+	}
+}
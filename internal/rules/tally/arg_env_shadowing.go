@@ -0,0 +1,283 @@
+package tally
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	dfshell "github.com/moby/buildkit/frontend/dockerfile/shell"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// ArgEnvShadowingRuleCode is the full rule code for the arg-env-shadowing rule.
+const ArgEnvShadowingRuleCode = rules.TallyRulePrefix + "arg-env-shadowing"
+
+// ArgEnvShadowingRule detects ARG and ENV declarations whose effective value
+// differs from what the Dockerfile suggests:
+//
+//   - an ENV that shadows an ARG of the same name, so --build-arg stops working;
+//   - an ARG declared after an ENV of the same name, which it can never override;
+//   - a stage ARG that redefines a global ARG with a different default;
+//   - an ENV value that expands a variable before the value is changed, either
+//     earlier in the same ENV instruction or later in the stage.
+type ArgEnvShadowingRule struct{}
+
+// NewArgEnvShadowingRule creates a new arg-env-shadowing rule instance.
+func NewArgEnvShadowingRule() *ArgEnvShadowingRule {
+	return &ArgEnvShadowingRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *ArgEnvShadowingRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            ArgEnvShadowingRuleCode,
+		Name:            "ARG/ENV shadowing",
+		Description:     "ARG and ENV declarations whose effective value differs from what the Dockerfile suggests",
+		DocURL:          rules.TallyDocURL(ArgEnvShadowingRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+	}
+}
+
+// envOrigin records where an ENV variable got its current value.
+type envOrigin struct {
+	value string
+	line  int
+	// stage is the quoted name or index of the stage the value was inherited
+	// from; empty when set in the current stage.
+	stage string
+}
+
+// argOrigin records a stage ARG declaration.
+type argOrigin struct {
+	line int
+}
+
+// envCapture records an ENV value that expanded other variables.
+type envCapture struct {
+	refs     []string
+	location []parser.Range
+	line     int
+}
+
+// Check runs the arg-env-shadowing rule.
+func (r *ArgEnvShadowingRule) Check(input rules.LintInput) []rules.Violation {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+	lex := dfshell.NewLex(dockerfile.ASTEscapeToken(input.AST))
+	lex.SkipProcessQuotes = true
+	c := &argEnvChecker{file: input.File, lex: lex, meta: r.Metadata()}
+	if input.IsRuleEnabled(StaleMetaArgRuleCode) {
+		// stale-meta-arg reports redefined global ARGs that FROM uses.
+		c.fromArgs = fromArgRefs(sem, lex)
+	}
+
+	// Global ARG defaults as written, last declaration with a value wins.
+	globals := make(map[string]globalArg)
+	for _, cmd := range sem.MetaArgs() {
+		for _, kv := range cmd.Args {
+			if kv.Value != nil {
+				globals[kv.Key] = globalArg{value: *kv.Value, line: startLine(cmd.Location())}
+			}
+		}
+	}
+
+	// Final ENV state of each stage, inherited by stages built FROM it.
+	stageEnv := make([]map[string]envOrigin, sem.StageCount())
+	for i := range sem.StageCount() {
+		info := sem.StageInfo(i)
+		if info == nil || info.Stage == nil {
+			continue
+		}
+		env := make(map[string]envOrigin)
+		if base := info.BaseImage; base != nil && base.IsStageRef && base.StageIndex >= 0 && base.StageIndex < i {
+			parentName := strconv.Itoa(base.StageIndex)
+			if parent := sem.StageInfo(base.StageIndex); parent != nil && parent.Stage != nil && parent.Stage.Name != "" {
+				parentName = strconv.Quote(parent.Stage.Name)
+			}
+			for name, origin := range stageEnv[base.StageIndex] {
+				if origin.stage == "" {
+					origin.stage = parentName
+				}
+				env[name] = origin
+			}
+		}
+		stageEnv[i] = c.checkStage(info.Stage, info.Index, globals, env)
+	}
+	return c.violations
+}
+
+type globalArg struct {
+	value string
+	line  int
+}
+
+type argEnvChecker struct {
+	meta       rules.RuleMetadata
+	file       string
+	lex        *dfshell.Lex
+	fromArgs   map[string][]fromRef
+	violations []rules.Violation
+}
+
+// checkStage walks a stage's ARG and ENV instructions in order and returns
+// the stage's final ENV state.
+func (c *argEnvChecker) checkStage(
+	stage *instructions.Stage,
+	stageIdx int,
+	globals map[string]globalArg,
+	env map[string]envOrigin,
+) map[string]envOrigin {
+	args := make(map[string]argOrigin)
+	captures := make(map[string]envCapture)
+
+	for _, cmd := range stage.Commands {
+		switch cmd := cmd.(type) {
+		case *instructions.ArgCommand:
+			line := startLine(cmd.Location())
+			for _, kv := range cmd.Args {
+				if origin, ok := env[kv.Key]; ok {
+					c.report(stageIdx, cmd.Location(),
+						fmt.Sprintf("ARG %s has no effect because ENV %s is already set %s", kv.Key, kv.Key, origin.describe()),
+						fmt.Sprintf("ENV takes precedence over ARG, so $%s keeps the ENV value %q and --build-arg %s=... is ignored. "+
+							"Remove the ARG, or declare it before the ENV and set the ENV from it (ENV %s=$%s).",
+							kv.Key, origin.value, kv.Key, kv.Key, kv.Key))
+				} else if global, ok := globals[kv.Key]; ok && kv.Value != nil && *kv.Value != global.value &&
+					len(c.fromArgs[kv.Key]) == 0 {
+					c.report(stageIdx, cmd.Location(),
+						fmt.Sprintf("ARG %s redefines global ARG %s with a different default", kv.Key, kv.Key),
+						fmt.Sprintf("The global ARG on line %d defaults to %q, which FROM instructions see; in this stage $%s defaults to %q. "+
+							"Redeclare it without a value (ARG %s) to inherit the global default, or rename one of them.",
+							global.line, global.value, kv.Key, *kv.Value, kv.Key))
+				}
+				if kv.Value != nil {
+					c.checkStaleCaptures(stageIdx, captures, kv.Key, line)
+				}
+				args[kv.Key] = argOrigin{line: line}
+			}
+
+		case *instructions.EnvCommand:
+			line := startLine(cmd.Location())
+			before := maps.Clone(env)
+			setHere := make(map[string]bool, len(cmd.Env))
+			for _, kv := range cmd.Env {
+				refs := c.references(kv.Value)
+				c.checkSameInstructionRefs(stageIdx, cmd, kv, refs, setHere, before, args)
+
+				if arg, ok := args[kv.Key]; ok && !slices.Contains(refs, kv.Key) {
+					if _, shadowed := before[kv.Key]; !shadowed {
+						c.report(stageIdx, cmd.Location(),
+							fmt.Sprintf("ENV %s shadows ARG %s declared on line %d", kv.Key, kv.Key, arg.line),
+							fmt.Sprintf("From line %d on, $%s expands to %q set by this ENV, so --build-arg %s=... no longer changes it. "+
+								"Write ENV %s=$%s to pass the build argument through, or rename one of the two.",
+								line, kv.Key, kv.Value, kv.Key, kv.Key, kv.Key))
+					}
+				}
+
+				c.checkStaleCaptures(stageIdx, captures, kv.Key, line)
+				delete(captures, kv.Key)
+				if len(refs) > 0 {
+					captures[kv.Key] = envCapture{refs: refs, location: cmd.Location(), line: line}
+				}
+				env[kv.Key] = envOrigin{value: kv.Value, line: line}
+				setHere[kv.Key] = true
+			}
+		}
+	}
+	return env
+}
+
+// checkSameInstructionRefs reports references to variables assigned earlier
+// in the same ENV instruction. BuildKit expands every value of an ENV
+// instruction with the environment from before the instruction, so such a
+// reference sees the previous value. References to variables with no
+// previous value are left to buildkit/UndefinedVar.
+func (c *argEnvChecker) checkSameInstructionRefs(
+	stageIdx int,
+	cmd *instructions.EnvCommand,
+	kv instructions.KeyValuePair,
+	refs []string,
+	setHere map[string]bool,
+	before map[string]envOrigin,
+	args map[string]argOrigin,
+) {
+	for _, ref := range refs {
+		if !setHere[ref] || ref == kv.Key {
+			continue
+		}
+		var previous string
+		if origin, ok := before[ref]; ok {
+			previous = fmt.Sprintf("the value %q set %s", origin.value, origin.describe())
+		} else if arg, ok := args[ref]; ok {
+			previous = fmt.Sprintf("the value of ARG %s declared on line %d", ref, arg.line)
+		} else {
+			continue
+		}
+		c.report(stageIdx, cmd.Location(),
+			fmt.Sprintf("ENV %s expands $%s before this instruction changes it", kv.Key, ref),
+			fmt.Sprintf("Values in one ENV instruction are expanded with the environment from before the instruction, "+
+				"so $%s in %s is %s, not the value assigned here. "+
+				"Move %s to its own ENV instruction after this one if it should see the new value.",
+				ref, kv.Key, previous, kv.Key))
+	}
+}
+
+// checkStaleCaptures reports ENV values that expanded name before name is
+// reassigned on line.
+func (c *argEnvChecker) checkStaleCaptures(stageIdx int, captures map[string]envCapture, name string, line int) {
+	for _, key := range slices.Sorted(maps.Keys(captures)) {
+		capture := captures[key]
+		if key == name || capture.line == line || !slices.Contains(capture.refs, name) {
+			continue
+		}
+		c.report(stageIdx, capture.location,
+			fmt.Sprintf("ENV %s expands $%s before it is changed on line %d", key, name, line),
+			fmt.Sprintf("$%s is expanded when ENV %s is set, so %s keeps the earlier value after line %d reassigns %s. "+
+				"Set %s after line %d if it should follow the new value.",
+				name, key, key, line, name, key, line))
+		capture.refs = slices.DeleteFunc(slices.Clone(capture.refs), func(ref string) bool { return ref == name })
+		captures[key] = capture
+	}
+}
+
+// references returns the variable names a value expands, in sorted order.
+func (c *argEnvChecker) references(value string) []string {
+	res, err := c.lex.ProcessWordWithMatches(value, dfshell.EnvsFromSlice(nil))
+	if err != nil || len(res.Unmatched) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(res.Unmatched))
+}
+
+func (c *argEnvChecker) report(stageIdx int, location []parser.Range, message, detail string) {
+	v := rules.NewViolation(rules.NewLocationFromRanges(c.file, location), c.meta.Code, message, c.meta.DefaultSeverity).
+		WithDocURL(c.meta.DocURL).
+		WithDetail(detail)
+	v.StageIndex = stageIdx
+	c.violations = append(c.violations, v)
+}
+
+func (o envOrigin) describe() string {
+	if o.stage != "" {
+		return "in base stage " + o.stage
+	}
+	return fmt.Sprintf("on line %d", o.line)
+}
+
+func startLine(location []parser.Range) int {
+	if len(location) == 0 {
+		return 0
+	}
+	return location[0].Start.Line
+}
+
+func init() {
+	rules.Register(NewArgEnvShadowingRule())
+}
@@ -0,0 +1,188 @@
+package tally
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestArgEnvShadowingRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewArgEnvShadowingRule().Metadata())
+}
+
+func TestArgEnvShadowingRule_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		want     []string // expected message substrings, in order
+		wantLine []int
+	}{
+		{
+			name: "env shadows arg",
+			content: `FROM alpine:3.20
+ARG VERSION=1.0
+ENV VERSION=2.0
+`,
+			want:     []string{"ENV VERSION shadows ARG VERSION declared on line 2"},
+			wantLine: []int{3},
+		},
+		{
+			name: "env promotes arg",
+			content: `FROM alpine:3.20
+ARG VERSION=1.0
+ENV VERSION=$VERSION
+ENV OTHER=${OTHER:-x}
+`,
+		},
+		{
+			name: "env promotes arg with default expansion",
+			content: `FROM alpine:3.20
+ARG VERSION
+ENV VERSION=${VERSION:-1.0}
+`,
+		},
+		{
+			name: "arg after env",
+			content: `FROM alpine:3.20
+ENV MODE=production
+ARG MODE
+`,
+			want:     []string{"ARG MODE has no effect because ENV MODE is already set on line 2"},
+			wantLine: []int{3},
+		},
+		{
+			name: "arg after env inherited from stage",
+			content: `FROM alpine:3.20 AS base
+ENV MODE=production
+
+FROM base
+ARG MODE=debug
+`,
+			want:     []string{`ARG MODE has no effect because ENV MODE is already set in base stage "base"`},
+			wantLine: []int{5},
+		},
+		{
+			name: "global arg redefined with different default",
+			content: `ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION}
+ARG GO_VERSION=1.23
+`,
+			want:     []string{"ARG GO_VERSION redefines global ARG GO_VERSION with a different default"},
+			wantLine: []int{3},
+		},
+		{
+			name: "global arg redeclared",
+			content: `ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION}
+ARG GO_VERSION
+ARG OTHER=1
+`,
+		},
+		{
+			name: "global arg redefined with same default",
+			content: `ARG GO_VERSION=1.22
+FROM golang:${GO_VERSION}
+ARG GO_VERSION=1.22
+`,
+		},
+		{
+			name: "same instruction reference sees previous value",
+			content: `FROM alpine:3.20
+ENV APP_HOME=/opt/app
+ENV APP_HOME=/srv/app APP_DATA=$APP_HOME/data
+`,
+			want:     []string{"ENV APP_DATA expands $APP_HOME before this instruction changes it"},
+			wantLine: []int{3},
+		},
+		{
+			name: "same instruction reference without previous value",
+			content: `FROM alpine:3.20
+ENV APP_HOME=/srv/app APP_DATA=$APP_HOME/data
+`,
+		},
+		{
+			name: "value captured before reassignment",
+			content: `FROM alpine:3.20
+ENV APP_HOME=/opt/app
+ENV APP_DATA=$APP_HOME/data
+ENV APP_HOME=/srv/app
+`,
+			want:     []string{"ENV APP_DATA expands $APP_HOME before it is changed on line 4"},
+			wantLine: []int{3},
+		},
+		{
+			name: "captured value updated after reassignment",
+			content: `FROM alpine:3.20
+ENV APP_HOME=/opt/app
+ENV APP_DATA=$APP_HOME/data
+ENV APP_HOME=/srv/app
+ENV APP_DATA=$APP_HOME/data
+`,
+			want:     []string{"ENV APP_DATA expands $APP_HOME before it is changed on line 4"},
+			wantLine: []int{3},
+		},
+		{
+			name: "self-referencing path",
+			content: `FROM alpine:3.20
+ENV PATH=/opt/a/bin:$PATH
+ENV PATH=/opt/b/bin:$PATH
+`,
+		},
+		{
+			name: "separate stages",
+			content: `FROM alpine:3.20 AS one
+ENV MODE=production
+
+FROM alpine:3.20
+ARG MODE
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewArgEnvShadowingRule().Check(input)
+
+			if len(violations) != len(tt.want) {
+				for _, v := range violations {
+					t.Logf("violation: %s (line %d)", v.Message, v.Location.Start.Line)
+				}
+				t.Fatalf("got %d violations, want %d", len(violations), len(tt.want))
+			}
+			for i, v := range violations {
+				if !strings.Contains(v.Message, tt.want[i]) {
+					t.Errorf("violation %d message = %q, want it to contain %q", i, v.Message, tt.want[i])
+				}
+				if v.Location.Start.Line != tt.wantLine[i] {
+					t.Errorf("violation %d line = %d, want %d", i, v.Location.Start.Line, tt.wantLine[i])
+				}
+				if v.Detail == "" {
+					t.Errorf("violation %d has no detail", i)
+				}
+			}
+		})
+	}
+}
+
+func TestArgEnvShadowingRule_DetailExplainsEffectiveValue(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", `FROM alpine:3.20
+ENV APP_HOME=/opt/app
+ENV APP_HOME=/srv/app APP_DATA=$APP_HOME/data
+`)
+	violations := NewArgEnvShadowingRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	if want := `the value "/opt/app" set on line 2`; !strings.Contains(violations[0].Detail, want) {
+		t.Errorf("detail = %q, want it to contain %q", violations[0].Detail, want)
+	}
+}
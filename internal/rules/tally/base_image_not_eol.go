@@ -0,0 +1,229 @@
+package tally
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/eol"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// BaseImageNotEOLRuleCode is the full rule code for the base-image-not-eol rule.
+const BaseImageNotEOLRuleCode = rules.TallyRulePrefix + "base-image-not-eol"
+
+// BaseImageNotEOLConfig is the configuration for the base-image-not-eol rule.
+type BaseImageNotEOLConfig struct {
+	// GracePeriodDays delays reporting until this many days after the EOL date
+	// (nil = use default).
+	GracePeriodDays *int `json:"grace-period-days,omitempty" koanf:"grace-period-days"`
+}
+
+// DefaultBaseImageNotEOLConfig returns the default configuration.
+func DefaultBaseImageNotEOLConfig() BaseImageNotEOLConfig {
+	grace := 0
+	return BaseImageNotEOLConfig{GracePeriodDays: &grace}
+}
+
+// BaseImageNotEOLRule flags FROM images whose product or operating system
+// release cycle is past end-of-life (debian:buster, node:14, ubuntu:18.04,
+// python:3.9-buster, ...).
+//
+// The fast path uses the dataset embedded in internal/eol. With slow checks
+// enabled, the rule re-checks the matched cycle against the live
+// endoflife.date API, and that result replaces the fast-path one.
+type BaseImageNotEOLRule struct {
+	schema map[string]any
+	now    func() time.Time
+}
+
+// NewBaseImageNotEOLRule creates a new base-image-not-eol rule instance.
+func NewBaseImageNotEOLRule() *BaseImageNotEOLRule {
+	schema, err := configutil.RuleSchema(BaseImageNotEOLRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &BaseImageNotEOLRule{schema: schema, now: time.Now}
+}
+
+// Metadata returns the rule metadata.
+func (r *BaseImageNotEOLRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            BaseImageNotEOLRuleCode,
+		Name:            "Base image is end-of-life",
+		Description:     "Base image release is past end-of-life and no longer receives security updates",
+		DocURL:          rules.TallyDocURL(BaseImageNotEOLRuleCode),
+		DefaultSeverity: rules.SeverityOff,
+		Category:        "security",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *BaseImageNotEOLRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *BaseImageNotEOLRule) DefaultConfig() any {
+	return DefaultBaseImageNotEOLConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *BaseImageNotEOLRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(BaseImageNotEOLRuleCode, config)
+}
+
+// Check reports base images that are past end-of-life according to the
+// embedded dataset.
+func (r *BaseImageNotEOLRule) Check(input rules.LintInput) []rules.Violation {
+	checker := r.newChecker(input)
+	var violations []rules.Violation
+	for info := range input.Semantic.ExternalImageStages() {
+		ref, loc := eolBaseImage(info.BaseImage)
+		if ref == "" {
+			continue
+		}
+		if m, ok := checker.firstEnded(eol.Default().Match(ref)); ok {
+			violations = append(violations, checker.violation(ref, loc, info.Index, m))
+		}
+	}
+	return violations
+}
+
+// PlanAsync requests the live release cycles of the product each base image
+// matched. For a stage the fast path flagged, that is the flagged product;
+// otherwise the most specific match, so newly announced EOL dates are caught.
+func (r *BaseImageNotEOLRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	checker := r.newChecker(input)
+	var requests []async.CheckRequest
+	for info := range input.Semantic.ExternalImageStages() {
+		ref, loc := eolBaseImage(info.BaseImage)
+		if ref == "" {
+			continue
+		}
+		matches := eol.Default().Match(ref)
+		if len(matches) == 0 {
+			continue
+		}
+		m, ok := checker.firstEnded(matches)
+		if !ok {
+			m = matches[0]
+		}
+		requests = append(requests, async.CheckRequest{
+			RuleCode:   checker.meta.Code,
+			Category:   async.CategoryNetwork,
+			Key:        m.Product.ID,
+			ResolverID: eol.ResolverID,
+			Data:       &eol.Request{Product: m.Product.ID},
+			File:       input.File,
+			StageIndex: info.Index,
+			Handler: &baseImageNotEOLHandler{
+				checker:  checker,
+				ref:      ref,
+				location: loc,
+				stageIdx: info.Index,
+				match:    m,
+			},
+		})
+	}
+	return requests
+}
+
+// eolBaseImage returns the expanded base image reference and FROM location.
+func eolBaseImage(base *semantic.BaseImageRef) (string, []parser.Range) {
+	if base == nil {
+		return "", nil
+	}
+	ref := base.Effective
+	if ref == "" {
+		ref = base.Raw
+	}
+	return ref, base.Location
+}
+
+// eolChecker evaluates matches against the configured grace period.
+type eolChecker struct {
+	meta  rules.RuleMetadata
+	file  string
+	now   time.Time
+	grace time.Duration
+}
+
+func (r *BaseImageNotEOLRule) newChecker(input rules.LintInput) *eolChecker {
+	cfg := configutil.Coerce(input.Config, DefaultBaseImageNotEOLConfig())
+	var grace time.Duration
+	if cfg.GracePeriodDays != nil && *cfg.GracePeriodDays > 0 {
+		grace = time.Duration(*cfg.GracePeriodDays) * 24 * time.Hour
+	}
+	return &eolChecker{meta: r.Metadata(), file: input.File, now: r.now(), grace: grace}
+}
+
+// firstEnded returns the first match whose cycle is past end-of-life.
+func (c *eolChecker) firstEnded(matches []eol.Match) (eol.Match, bool) {
+	for _, m := range matches {
+		if m.Cycle.Ended(c.now, c.grace) {
+			return m, true
+		}
+	}
+	return eol.Match{}, false
+}
+
+func (c *eolChecker) violation(ref string, location []parser.Range, stageIdx int, m eol.Match) rules.Violation {
+	release := m.Product.Name + " " + m.Cycle.Cycle
+	if m.Cycle.Codename != "" && m.Cycle.Codename != m.Cycle.Cycle {
+		release += " (" + m.Cycle.Codename + ")"
+	}
+	msg := fmt.Sprintf("base image %s uses %s, which reached end-of-life on %s",
+		ref, release, m.Cycle.EOL.Format(time.DateOnly))
+
+	detail := release + " no longer receives security updates, so vulnerabilities found after " +
+		m.Cycle.EOL.Format(time.DateOnly) + " stay unpatched in this image."
+	if supported, ok := m.Product.Supported(c.now); ok {
+		detail += fmt.Sprintf(" Upgrade to a supported release such as %s %s.", m.Product.Name, supported.Cycle)
+	}
+	detail += " See " + m.Product.URL() + " for the support schedule."
+
+	v := rules.NewViolation(rules.NewLocationFromRanges(c.file, location), c.meta.Code, msg, c.meta.DefaultSeverity).
+		WithDocURL(c.meta.DocURL).
+		WithDetail(detail)
+	v.StageIndex = stageIdx
+	return v
+}
+
+// baseImageNotEOLHandler re-checks a matched cycle against live release data.
+type baseImageNotEOLHandler struct {
+	checker  *eolChecker
+	ref      string
+	location []parser.Range
+	stageIdx int
+	match    eol.Match
+}
+
+func (h *baseImageNotEOLHandler) OnSuccess(resolved any) []any {
+	live, ok := resolved.(*eol.Product)
+	if !ok || live == nil {
+		return nil
+	}
+	cycle, ok := live.Cycle(h.match.Cycle.Cycle)
+	if !ok {
+		// The live data no longer lists this cycle; keep the fast-path result.
+		return nil
+	}
+	// Keep the embedded product metadata but use the live cycle list for the
+	// EOL date and the suggested replacement.
+	product := *h.match.Product
+	product.Cycles = live.Cycles
+	if !cycle.Ended(h.checker.now, h.checker.grace) {
+		return []any{}
+	}
+	return []any{h.checker.violation(h.ref, h.location, h.stageIdx, eol.Match{Product: &product, Cycle: cycle})}
+}
+
+func init() {
+	rules.Register(NewBaseImageNotEOLRule())
+}
@@ -0,0 +1,193 @@
+package tally
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wharflab/tally/internal/eol"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+// newBaseImageNotEOLRuleAt returns the rule with a fixed clock so results do
+// not drift as dataset entries reach end-of-life.
+func newBaseImageNotEOLRuleAt(now time.Time) *BaseImageNotEOLRule {
+	r := NewBaseImageNotEOLRule()
+	r.now = func() time.Time { return now }
+	return r
+}
+
+var eolTestNow = time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+func TestBaseImageNotEOLRule_Metadata(t *testing.T) {
+	t.Parallel()
+	meta := NewBaseImageNotEOLRule().Metadata()
+	if meta.Code != BaseImageNotEOLRuleCode {
+		t.Errorf("code = %q, want %q", meta.Code, BaseImageNotEOLRuleCode)
+	}
+	if meta.DefaultSeverity != rules.SeverityOff {
+		t.Errorf("severity = %v, want Off", meta.DefaultSeverity)
+	}
+}
+
+func TestBaseImageNotEOLRule_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		config  any
+		want    []string // message substrings, one per violation
+	}{
+		{
+			name:    "debian codename",
+			content: "FROM debian:buster\n",
+			want:    []string{"base image debian:buster uses Debian 10 (buster), which reached end-of-life on 2022-09-10"},
+		},
+		{
+			name:    "node major",
+			content: "FROM node:14-alpine\n",
+			want:    []string{"Node.js 14 (fermium)"},
+		},
+		{
+			name:    "ubuntu version",
+			content: "FROM ubuntu:18.04\n",
+			want:    []string{"Ubuntu 18.04 (bionic)"},
+		},
+		{
+			name:    "os variant of supported product",
+			content: "FROM python:3.12-slim-buster\n",
+			want:    []string{"Debian 10 (buster)"},
+		},
+		{
+			name:    "product cycle reported before os variant",
+			content: "FROM node:14-buster\n",
+			want:    []string{"Node.js 14"},
+		},
+		{
+			name:    "supported release",
+			content: "FROM debian:bookworm\nFROM node:22\n",
+		},
+		{
+			name:    "meta arg is expanded",
+			content: "ARG UBUNTU=16.04\nFROM ubuntu:${UBUNTU}\n",
+			want:    []string{"base image ubuntu:16.04 uses Ubuntu 16.04"},
+		},
+		{
+			name:    "stage reference is not re-reported",
+			content: "FROM centos:7 AS base\nFROM base\n",
+			want:    []string{"CentOS 7"},
+		},
+		{
+			name:    "unknown images and scratch are ignored",
+			content: "FROM scratch\nFROM example.com/app:1.0\nFROM node\n",
+		},
+		{
+			name:    "within grace period",
+			content: "FROM node:18\n",
+			config:  map[string]any{"grace-period-days": 30},
+		},
+		{
+			name:    "past grace period",
+			content: "FROM node:16\n",
+			config:  map[string]any{"grace-period-days": 30},
+			want:    []string{"Node.js 16"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// node:18 reached EOL on 2025-04-30; check it 10 days later.
+			now := eolTestNow
+			if tt.config != nil {
+				now = time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+			}
+			input := testutil.MakeLintInputWithConfig(t, "Dockerfile", tt.content, tt.config)
+			got := newBaseImageNotEOLRuleAt(now).Check(input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d violations, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, v := range got {
+				if !strings.Contains(v.Message, tt.want[i]) {
+					t.Errorf("message = %q, want substring %q", v.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBaseImageNotEOLRule_Check_Detail(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM node:14\n")
+	got := newBaseImageNotEOLRuleAt(eolTestNow).Check(input)
+	if len(got) != 1 {
+		t.Fatalf("got %d violations, want 1", len(got))
+	}
+	v := got[0]
+	if v.Location.Start.Line != 1 || v.StageIndex != 0 {
+		t.Errorf("location = %+v, stage = %d", v.Location, v.StageIndex)
+	}
+	for _, want := range []string{"Upgrade to a supported release such as Node.js 24.", "https://endoflife.date/nodejs"} {
+		if !strings.Contains(v.Detail, want) {
+			t.Errorf("detail = %q, want substring %q", v.Detail, want)
+		}
+	}
+}
+
+func TestBaseImageNotEOLRule_PlanAsync(t *testing.T) {
+	t.Parallel()
+	content := "FROM node:14-buster\nFROM python:3.12\nFROM example.com/app:1.0\n"
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	reqs := newBaseImageNotEOLRuleAt(eolTestNow).PlanAsync(input)
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	for i, want := range []string{"nodejs", "python"} {
+		req := reqs[i]
+		data, ok := req.Data.(*eol.Request)
+		if !ok || data.Product != want || req.ResolverID != eol.ResolverID || req.StageIndex != i {
+			t.Errorf("request %d = %+v, want product %q", i, req, want)
+		}
+	}
+}
+
+func TestBaseImageNotEOLHandler_OnSuccess(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM python:3.12\n")
+	reqs := newBaseImageNotEOLRuleAt(eolTestNow).PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	handler := reqs[0].Handler
+
+	// Live data moved the EOL date into the past.
+	live := &eol.Product{ID: "python", Cycles: []eol.Cycle{
+		{Cycle: "3.13"},
+		{Cycle: "3.12", EOL: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+	}}
+	out := handler.OnSuccess(live)
+	if len(out) != 1 {
+		t.Fatalf("got %d results, want 1 violation", len(out))
+	}
+	v, ok := out[0].(rules.Violation)
+	if !ok || !strings.Contains(v.Message, "Python 3.12, which reached end-of-life on 2024-12-01") ||
+		!strings.Contains(v.Detail, "Python 3.13") {
+		t.Errorf("unexpected violation: %+v", out[0])
+	}
+
+	// Live data says still supported: completed with no violations.
+	live.Cycles[1].EOL = time.Time{}
+	if out := handler.OnSuccess(live); out == nil || len(out) != 0 {
+		t.Errorf("supported cycle: got %v, want empty non-nil result", out)
+	}
+
+	// Cycle missing from live data, or unexpected type: not completed.
+	if out := handler.OnSuccess(&eol.Product{ID: "python"}); out != nil {
+		t.Errorf("missing cycle: got %v, want nil", out)
+	}
+	if out := handler.OnSuccess("nope"); out != nil {
+		t.Errorf("wrong type: got %v, want nil", out)
+	}
+}
@@ -0,0 +1,207 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/osv"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// BaseImageVulnerabilitiesRuleCode is the full rule code for the
+// base-image-vulnerabilities rule.
+const BaseImageVulnerabilitiesRuleCode = rules.TallyRulePrefix + "base-image-vulnerabilities"
+
+// maxListedVulnerabilities caps the advisories named in a violation detail.
+const maxListedVulnerabilities = 5
+
+// BaseImageVulnerabilitiesConfig is the configuration for the
+// base-image-vulnerabilities rule.
+type BaseImageVulnerabilitiesConfig struct {
+	// MinSeverity is the lowest advisory severity counted (nil = use default).
+	MinSeverity *string `json:"min-severity,omitempty" koanf:"min-severity"`
+}
+
+// DefaultBaseImageVulnerabilitiesConfig returns the default configuration.
+func DefaultBaseImageVulnerabilitiesConfig() BaseImageVulnerabilitiesConfig {
+	minSeverity := "critical"
+	return BaseImageVulnerabilitiesConfig{MinSeverity: &minSeverity}
+}
+
+// BaseImageVulnerabilitiesRule reports known vulnerabilities in the
+// distribution packages of pinned base images, as listed by OSV.
+//
+// The rule is async-only and off by default: it downloads image layers and
+// queries the OSV API, so it runs only when enabled and with slow checks on.
+type BaseImageVulnerabilitiesRule struct {
+	schema map[string]any
+}
+
+// NewBaseImageVulnerabilitiesRule creates a new base-image-vulnerabilities rule instance.
+func NewBaseImageVulnerabilitiesRule() *BaseImageVulnerabilitiesRule {
+	schema, err := configutil.RuleSchema(BaseImageVulnerabilitiesRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &BaseImageVulnerabilitiesRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *BaseImageVulnerabilitiesRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            BaseImageVulnerabilitiesRuleCode,
+		Name:            "Base image has known vulnerabilities",
+		Description:     "Pinned base image ships distribution packages with known vulnerabilities",
+		DocURL:          rules.TallyDocURL(BaseImageVulnerabilitiesRuleCode),
+		DefaultSeverity: rules.SeverityOff,
+		Category:        "security",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *BaseImageVulnerabilitiesRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *BaseImageVulnerabilitiesRule) DefaultConfig() any {
+	return DefaultBaseImageVulnerabilitiesConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *BaseImageVulnerabilitiesRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(BaseImageVulnerabilitiesRuleCode, config)
+}
+
+// Check returns nil — this is an async-only rule.
+func (r *BaseImageVulnerabilitiesRule) Check(_ rules.LintInput) []rules.Violation {
+	return nil
+}
+
+// PlanAsync requests a vulnerability report for each pinned external base
+// image. Untagged and :latest references are skipped: their contents change
+// under the same name, so a report would not describe what gets built.
+func (r *BaseImageVulnerabilitiesRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+	cfg := configutil.Coerce(input.Config, DefaultBaseImageVulnerabilitiesConfig())
+	minSeverity := osv.SeverityCritical
+	if cfg.MinSeverity != nil {
+		if sev, ok := osv.ParseSeverity(*cfg.MinSeverity); ok {
+			minSeverity = sev
+		}
+	}
+
+	meta := r.Metadata()
+	var requests []async.CheckRequest
+	for info := range sem.ExternalImageStages() {
+		ref, loc := eolBaseImage(info.BaseImage)
+		if !isPinnedImageRef(ref) {
+			continue
+		}
+		platform, unresolved := semantic.ExpectedPlatform(info, sem)
+		if len(unresolved) > 0 || platform == "" {
+			continue
+		}
+		requests = append(requests, async.CheckRequest{
+			RuleCode:   meta.Code,
+			Category:   async.CategoryNetwork,
+			Key:        ref + "|" + platform,
+			ResolverID: osv.ResolverID,
+			Data:       &osv.Request{Ref: ref, Platform: platform},
+			File:       input.File,
+			StageIndex: info.Index,
+			Handler: &baseImageVulnerabilitiesHandler{
+				meta:        meta,
+				file:        input.File,
+				ref:         ref,
+				location:    loc,
+				stageIdx:    info.Index,
+				minSeverity: minSeverity,
+			},
+		})
+	}
+	return requests
+}
+
+// isPinnedImageRef reports whether ref names a digest or an explicit tag
+// other than "latest".
+func isPinnedImageRef(ref string) bool {
+	if ref == "" || strings.Contains(ref, "$") {
+		return false
+	}
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return false
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return true
+	}
+	tagged, ok := named.(reference.Tagged)
+	return ok && tagged.Tag() != "latest"
+}
+
+// baseImageVulnerabilitiesHandler turns an OSV report into a violation.
+type baseImageVulnerabilitiesHandler struct {
+	meta        rules.RuleMetadata
+	file        string
+	ref         string
+	location    []parser.Range
+	stageIdx    int
+	minSeverity osv.Severity
+}
+
+func (h *baseImageVulnerabilitiesHandler) OnSuccess(resolved any) []any {
+	report, ok := resolved.(*osv.Report)
+	if !ok || report == nil {
+		return nil
+	}
+	vulns := report.AtLeast(h.minSeverity)
+	if len(vulns) == 0 {
+		return []any{}
+	}
+
+	var counts []string
+	for sev := osv.SeverityCritical; sev >= h.minSeverity; sev-- {
+		if n := report.Count(sev); n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, sev))
+		}
+	}
+	noun := "vulnerabilities"
+	if len(vulns) == 1 {
+		noun = "vulnerability"
+	}
+	msg := fmt.Sprintf("base image %s has known %s in its %s packages: %s",
+		h.ref, noun, report.Ecosystem, strings.Join(counts, ", "))
+
+	listed := make([]string, 0, maxListedVulnerabilities)
+	for _, v := range vulns[:min(len(vulns), maxListedVulnerabilities)] {
+		id := v.CVE
+		if id == "" {
+			id = v.ID
+		}
+		listed = append(listed, fmt.Sprintf("%s (%s, %s)", id, v.Package, v.Severity))
+	}
+	detail := "OSV lists advisories against packages installed in this image, including " +
+		strings.Join(listed, ", ") + ". Rebuild on a newer release of the base image, " +
+		"or upgrade the affected packages in the first RUN of the stage. See https://osv.dev for details."
+
+	v := rules.NewViolation(rules.NewLocationFromRanges(h.file, h.location), h.meta.Code, msg, rules.SeverityInfo).
+		WithDocURL(h.meta.DocURL).
+		WithDetail(detail)
+	v.StageIndex = h.stageIdx
+	return []any{v}
+}
+
+func init() {
+	rules.Register(NewBaseImageVulnerabilitiesRule())
+}
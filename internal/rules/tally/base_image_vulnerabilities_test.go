@@ -0,0 +1,152 @@
+package tally
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wharflab/tally/internal/osv"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestBaseImageVulnerabilitiesRule_Metadata(t *testing.T) {
+	t.Parallel()
+	meta := NewBaseImageVulnerabilitiesRule().Metadata()
+	if meta.Code != BaseImageVulnerabilitiesRuleCode {
+		t.Errorf("code = %q, want %q", meta.Code, BaseImageVulnerabilitiesRuleCode)
+	}
+	if meta.DefaultSeverity != rules.SeverityOff {
+		t.Errorf("severity = %v, want Off", meta.DefaultSeverity)
+	}
+}
+
+func TestBaseImageVulnerabilitiesRule_Check(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM debian:12\n")
+	if got := NewBaseImageVulnerabilitiesRule().Check(input); len(got) != 0 {
+		t.Errorf("Check() = %v, want no violations (async-only)", got)
+	}
+}
+
+func TestBaseImageVulnerabilitiesRule_PlanAsync(t *testing.T) {
+	t.Parallel()
+	content := `ARG ALPINE=3.20
+FROM debian:12-slim AS deb
+FROM alpine:${ALPINE}
+FROM --platform=linux/arm64 ubuntu@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef
+FROM debian
+FROM debian:latest
+FROM deb
+FROM scratch
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	reqs := NewBaseImageVulnerabilitiesRule().PlanAsync(input)
+
+	want := []struct {
+		ref      string
+		platform string
+		stage    int
+	}{
+		{ref: "debian:12-slim", stage: 0},
+		{ref: "alpine:3.20", stage: 1},
+		{
+			ref:      "ubuntu@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			platform: "linux/arm64",
+			stage:    2,
+		},
+	}
+	if len(reqs) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(reqs), len(want), reqs)
+	}
+	for i, w := range want {
+		data, ok := reqs[i].Data.(*osv.Request)
+		if !ok || data.Ref != w.ref || reqs[i].ResolverID != osv.ResolverID || reqs[i].StageIndex != w.stage {
+			t.Errorf("request %d = %+v (data %+v), want ref %q stage %d", i, reqs[i], reqs[i].Data, w.ref, w.stage)
+			continue
+		}
+		if w.platform != "" && data.Platform != w.platform {
+			t.Errorf("request %d platform = %q, want %q", i, data.Platform, w.platform)
+		}
+	}
+}
+
+func TestBaseImageVulnerabilitiesHandler_OnSuccess(t *testing.T) {
+	t.Parallel()
+
+	report := &osv.Report{
+		Ecosystem: "Debian:12",
+		Packages:  90,
+		Vulnerabilities: []osv.Vulnerability{
+			{ID: "DEBIAN-CVE-2024-0001", CVE: "CVE-2024-0001", Package: "openssl", Severity: osv.SeverityCritical},
+			{ID: "DEBIAN-CVE-2024-0002", CVE: "CVE-2024-0002", Package: "glibc", Severity: osv.SeverityHigh},
+			{ID: "DSA-5000-1", Package: "zlib", Severity: osv.SeverityHigh},
+			{ID: "DEBIAN-CVE-2024-0003", CVE: "CVE-2024-0003", Package: "bash", Severity: osv.SeverityLow},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		config  any
+		message string
+		detail  string
+	}{
+		{
+			name:    "default counts critical only",
+			message: "base image debian:12 has known vulnerability in its Debian:12 packages: 1 critical",
+			detail:  "CVE-2024-0001 (openssl, critical)",
+		},
+		{
+			name:    "min-severity high",
+			config:  map[string]any{"min-severity": "high"},
+			message: "base image debian:12 has known vulnerabilities in its Debian:12 packages: 1 critical, 2 high",
+			detail:  "CVE-2024-0001 (openssl, critical), CVE-2024-0002 (glibc, high), DSA-5000-1 (zlib, high)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithConfig(t, "Dockerfile", "FROM debian:12\n", tt.config)
+			reqs := NewBaseImageVulnerabilitiesRule().PlanAsync(input)
+			if len(reqs) != 1 {
+				t.Fatalf("got %d requests, want 1", len(reqs))
+			}
+			out := reqs[0].Handler.OnSuccess(report)
+			if len(out) != 1 {
+				t.Fatalf("got %d results, want 1", len(out))
+			}
+			v, ok := out[0].(rules.Violation)
+			if !ok {
+				t.Fatalf("result is %T, want rules.Violation", out[0])
+			}
+			if v.Message != tt.message {
+				t.Errorf("message = %q, want %q", v.Message, tt.message)
+			}
+			if !strings.Contains(v.Detail, tt.detail) {
+				t.Errorf("detail = %q, want substring %q", v.Detail, tt.detail)
+			}
+			if v.Severity != rules.SeverityInfo || v.StageIndex != 0 || v.Location.Start.Line != 1 {
+				t.Errorf("severity = %v, stage = %d, location = %+v", v.Severity, v.StageIndex, v.Location)
+			}
+		})
+	}
+}
+
+func TestBaseImageVulnerabilitiesHandler_OnSuccess_Clean(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM alpine:3.20\n")
+	reqs := NewBaseImageVulnerabilitiesRule().PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	handler := reqs[0].Handler
+
+	clean := &osv.Report{Ecosystem: "Alpine:v3.20", Vulnerabilities: []osv.Vulnerability{
+		{ID: "ALPINE-CVE-2024-0001", Package: "busybox", Severity: osv.SeverityMedium},
+	}}
+	if out := handler.OnSuccess(clean); out == nil || len(out) != 0 {
+		t.Errorf("below min-severity: got %v, want empty non-nil result", out)
+	}
+	if out := handler.OnSuccess("nope"); out != nil {
+		t.Errorf("wrong type: got %v, want nil", out)
+	}
+}
@@ -0,0 +1,277 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/runmount"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// CacheMountMisuseRuleCode is the full rule code for cache-mount-misuse.
+const CacheMountMisuseRuleCode = rules.TallyRulePrefix + "cache-mount-misuse"
+
+// lockedCacheTargets maps package manager cache directories that the package
+// manager locks while it runs to the package manager's name. Concurrent
+// builds sharing one of these caches fail on the lock or corrupt it.
+var lockedCacheTargets = map[string]string{
+	"/var/cache/apt":  "apt",
+	"/var/lib/apt":    "apt",
+	"/var/cache/dnf":  "dnf",
+	"/var/cache/yum":  "yum",
+	"/var/cache/zypp": "zypper",
+}
+
+// CacheMountMisuseRule checks RUN --mount=type=cache usage: package manager
+// caches that need sharing=locked, one target cached under different ids,
+// and cache targets that a later COPY --from expects to find in the image.
+type CacheMountMisuseRule struct{}
+
+// NewCacheMountMisuseRule creates a new rule instance.
+func NewCacheMountMisuseRule() *CacheMountMisuseRule {
+	return &CacheMountMisuseRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *CacheMountMisuseRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            CacheMountMisuseRuleCode,
+		Name:            "Cache mount misuse",
+		Description:     "Cache mounts need the right sharing mode and a consistent id, and their content is not in the image",
+		DocURL:          rules.TallyDocURL(CacheMountMisuseRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		RelatedTo:       []string{PreferPackageCacheMountsRuleCode},
+	}
+}
+
+// cacheMountUse is a cache mount of a RUN instruction.
+type cacheMountUse struct {
+	stageIdx int
+	run      *instructions.RunCommand
+	mount    *instructions.Mount
+	// target is the mount target resolved against the RUN's WORKDIR.
+	target string
+}
+
+// Check runs the rule.
+func (r *CacheMountMisuseRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Facts == nil || input.Semantic == nil {
+		return nil
+	}
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	var uses []cacheMountUse
+	for _, stageFacts := range input.Facts.Stages() {
+		if stageFacts == nil || stageFacts.BaseImageOS == semantic.BaseImageOSWindows {
+			continue
+		}
+		for _, runFacts := range stageFacts.Runs {
+			for _, m := range runmount.CacheMounts(runFacts.Run) {
+				if m.Target == "" || strings.Contains(m.Target, "$") {
+					continue
+				}
+				workdir := runFacts.Workdir
+				if workdir == "" {
+					workdir = "/"
+				}
+				use := cacheMountUse{
+					stageIdx: stageFacts.Index,
+					run:      runFacts.Run,
+					mount:    m,
+					target:   facts.ResolveWorkdir(workdir, m.Target),
+				}
+				if v, ok := r.checkSharing(input.File, meta, use); ok {
+					violations = append(violations, v)
+				}
+				uses = append(uses, use)
+			}
+		}
+	}
+
+	violations = append(violations, r.checkIDs(input, meta, uses)...)
+	violations = append(violations, r.checkCopiedTargets(input, meta, uses)...)
+	return violations
+}
+
+// checkSharing reports a locked package manager cache mounted with shared
+// sharing. Private sharing is safe too: concurrent builds get separate caches.
+func (r *CacheMountMisuseRule) checkSharing(
+	file string,
+	meta rules.RuleMetadata,
+	use cacheMountUse,
+) (rules.Violation, bool) {
+	manager, ok := lockedCacheTargets[use.target]
+	if !ok || runmount.CacheSharing(use.mount) != instructions.MountSharingShared {
+		return rules.Violation{}, false
+	}
+	loc := rules.NewLocationFromRanges(file, use.run.Location())
+	if loc.IsFileLevel() {
+		return rules.Violation{}, false
+	}
+
+	v := rules.NewViolation(
+		loc,
+		meta.Code,
+		fmt.Sprintf("cache mount at %s should use sharing=locked", use.target),
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(
+		fmt.Sprintf(
+			"%s locks %s while it runs. With the default sharing=shared, concurrent builds use the cache "+
+				"at the same time and fail to get the lock or corrupt it. Add sharing=locked to the mount.",
+			manager, use.target,
+		),
+	)
+	v.StageIndex = use.stageIdx
+	return v, true
+}
+
+// checkIDs reports cache mounts whose target was already cached under a
+// different id, reporting each target and id pair once. Mounts seeded from
+// another stage and ids with variables are skipped.
+func (r *CacheMountMisuseRule) checkIDs(
+	input rules.LintInput,
+	meta rules.RuleMetadata,
+	uses []cacheMountUse,
+) []rules.Violation {
+	firstByTarget := make(map[string]cacheMountUse)
+	reported := make(map[[2]string]bool)
+
+	var violations []rules.Violation
+	for _, use := range uses {
+		id := runmount.CacheID(use.mount)
+		if use.mount.From != "" || strings.Contains(id, "$") {
+			continue
+		}
+		first, seen := firstByTarget[use.target]
+		if !seen {
+			firstByTarget[use.target] = use
+			continue
+		}
+		firstID := runmount.CacheID(first.mount)
+		key := [2]string{use.target, id}
+		if firstID == id || reported[key] {
+			continue
+		}
+		reported[key] = true
+
+		loc := rules.NewLocationFromRanges(input.File, use.run.Location())
+		if loc.IsFileLevel() {
+			continue
+		}
+		firstLine := 0
+		if firstLoc := first.run.Location(); len(firstLoc) > 0 {
+			firstLine = firstLoc[0].Start.Line
+		}
+		v := rules.NewViolation(
+			loc,
+			meta.Code,
+			fmt.Sprintf("cache mount at %s uses id %q, but line %d caches it with id %q", use.target, id, firstLine, firstID),
+			meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL).WithDetail(
+			"BuildKit keys caches by id, so each id keeps a separate copy of " + use.target +
+				" and the instructions do not reuse each other's downloads or build outputs. " +
+				"Use the same id for both mounts, or different targets if the caches must stay separate.",
+		)
+		v.StageIndex = use.stageIdx
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// checkCopiedTargets reports COPY --from sources that are inside a cache
+// mount target of the source stage. Cache mount content is not part of the
+// stage's filesystem, so such sources are empty or missing.
+func (r *CacheMountMisuseRule) checkCopiedTargets(
+	input rules.LintInput,
+	meta rules.RuleMetadata,
+	uses []cacheMountUse,
+) []rules.Violation {
+	if len(uses) == 0 {
+		return nil
+	}
+	targetsByStage := make(map[int][]cacheMountUse)
+	for _, use := range uses {
+		targetsByStage[use.stageIdx] = append(targetsByStage[use.stageIdx], use)
+	}
+
+	sem := input.Semantic
+	var violations []rules.Violation
+	for i := range sem.StageCount() {
+		info := sem.StageInfo(i)
+		if info == nil {
+			continue
+		}
+		for _, ref := range info.CopyFromRefs {
+			if !ref.IsStageRef || ref.Command == nil {
+				continue
+			}
+			stageUses := targetsByStage[ref.StageIndex]
+			if len(stageUses) == 0 {
+				continue
+			}
+			use, src, ok := copiedCacheTarget(ref.Command, stageUses, input.Facts.Stage(ref.StageIndex))
+			if !ok {
+				continue
+			}
+			loc := rules.NewLocationFromRanges(input.File, ref.Location)
+			if loc.IsFileLevel() {
+				continue
+			}
+			v := rules.NewViolation(
+				loc,
+				meta.Code,
+				fmt.Sprintf(
+					"COPY --from=%s copies %s from a cache mount, which is not in the image",
+					ref.From, src,
+				),
+				meta.DefaultSeverity,
+			).WithDocURL(meta.DocURL).WithDetail(
+				fmt.Sprintf(
+					"%s is a cache mount target in %s. Cache content is only visible while the RUN "+
+						"instruction runs, so the copied path is empty or missing. Copy the build "+
+						"output out of the cache directory in the RUN instruction first.",
+					use.target, formatStageName(sem, ref.StageIndex),
+				),
+			)
+			v.StageIndex = i
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// copiedCacheTarget returns the first cache mount use whose target contains
+// a source path of cmd, together with the resolved source path. Relative
+// sources resolve against the source stage's final WORKDIR.
+func copiedCacheTarget(
+	cmd *instructions.CopyCommand,
+	uses []cacheMountUse,
+	stageFacts *facts.StageFacts,
+) (cacheMountUse, string, bool) {
+	workdir := "/"
+	if stageFacts != nil && stageFacts.FinalWorkdir != "" {
+		workdir = stageFacts.FinalWorkdir
+	}
+	for _, src := range cmd.SourcePaths {
+		if strings.Contains(src, "$") {
+			continue
+		}
+		resolved := facts.ResolveWorkdir(workdir, src)
+		for _, use := range uses {
+			if pathWithin(resolved, use.target) && use.target != "/" {
+				return use, resolved, true
+			}
+		}
+	}
+	return cacheMountUse{}, "", false
+}
+
+func init() {
+	rules.Register(NewCacheMountMisuseRule())
+}
@@ -0,0 +1,134 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestCacheMountMisuseMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewCacheMountMisuseRule().Metadata())
+}
+
+func TestCacheMountMisuseRule(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewCacheMountMisuseRule(), []testutil.RuleTestCase{
+		{
+			Name: "apt cache without locking",
+			Content: `FROM debian:bookworm
+RUN --mount=type=cache,target=/var/cache/apt --mount=type=cache,target=/var/lib/apt,sharing=locked \
+    apt-get update && apt-get install -y curl
+`,
+			WantViolations: 1,
+			WantCodes:      []string{CacheMountMisuseRuleCode},
+			WantMessages:   []string{"cache mount at /var/cache/apt should use sharing=locked"},
+		},
+		{
+			Name: "explicit shared dnf cache",
+			Content: `FROM fedora:40
+RUN --mount=type=cache,target=/var/cache/dnf/,sharing=shared dnf install -y git
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"cache mount at /var/cache/dnf should use sharing=locked"},
+		},
+		{
+			Name: "locked and private package caches",
+			Content: `FROM debian:bookworm
+RUN --mount=type=cache,target=/var/cache/apt,sharing=locked \
+    --mount=type=cache,target=/var/lib/apt,sharing=private \
+    apt-get update && apt-get install -y curl
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "caches without locking needs",
+			Content: `FROM golang:1.25
+RUN --mount=type=cache,target=/go/pkg/mod --mount=type=cache,target=/root/.cache/go-build go build ./...
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "target cached under different ids across stages",
+			Content: `FROM golang:1.25 AS test
+RUN --mount=type=cache,target=/root/.cache/go-build,id=gobuild go test ./...
+
+FROM golang:1.25 AS build
+RUN --mount=type=cache,target=/root/.cache/go-build go build -o /out/app .
+RUN --mount=type=cache,target=/root/.cache/go-build go vet ./...
+`,
+			WantViolations: 1,
+			WantMessages: []string{
+				`cache mount at /root/.cache/go-build uses id "/root/.cache/go-build", but line 2 caches it with id "gobuild"`,
+			},
+		},
+		{
+			Name: "relative targets resolve against WORKDIR",
+			Content: `FROM node:22 AS deps
+WORKDIR /app
+RUN --mount=type=cache,target=node_modules/.cache,id=cache npm ci
+
+FROM node:22 AS build
+RUN --mount=type=cache,target=/app/node_modules/.cache,id=cache npm run build
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "ids with variables and seeded caches",
+			Content: `FROM golang:1.25 AS build
+ARG TARGETARCH
+RUN --mount=type=cache,target=/root/.cache/go-build,id=gobuild-$TARGETARCH go build ./...
+RUN --mount=type=cache,target=/root/.cache/go-build,id=gobuild go vet ./...
+RUN --mount=type=cache,target=/root/.cache/go-build,from=build,source=/cache go test ./...
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "copy from a cache target",
+			Content: `FROM rust:1 AS build
+WORKDIR /src
+RUN --mount=type=cache,target=target cargo build --release
+
+FROM debian:bookworm-slim
+COPY --from=build /src/target/release/app /usr/local/bin/app
+`,
+			WantViolations: 1,
+			WantMessages: []string{
+				"COPY --from=build copies /src/target/release/app from a cache mount, which is not in the image",
+			},
+		},
+		{
+			Name: "copy with relative source",
+			Content: `FROM node:22 AS build
+WORKDIR /app
+RUN --mount=type=cache,target=/app/dist npm run build
+
+FROM nginx:1
+COPY --from=build dist /usr/share/nginx/html
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"copies /app/dist from a cache mount"},
+		},
+		{
+			Name: "copy of output moved out of the cache",
+			Content: `FROM rust:1 AS build
+WORKDIR /src
+RUN --mount=type=cache,target=/src/target cargo build --release && cp target/release/app /out/app
+
+FROM debian:bookworm-slim
+COPY --from=build /out/app /usr/local/bin/app
+COPY --from=build /src /src
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "windows stages are left to windows/no-run-mounts",
+			Content: `FROM mcr.microsoft.com/windows/servercore:ltsc2022
+RUN --mount=type=cache,target=/var/cache/apt echo hi
+`,
+			WantViolations: 0,
+		},
+	})
+}
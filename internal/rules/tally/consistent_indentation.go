@@ -47,6 +47,7 @@ func (r *ConsistentIndentationRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  true,
 		FixPriority:     50, // After content fixes (casing at 0) but before structural (heredoc at 100+)
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -59,6 +59,7 @@ func (r *CopyAfterUserWithoutChownRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     99, // Match prefer-copy-chmod for COPY flag insertion
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
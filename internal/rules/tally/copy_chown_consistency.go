@@ -0,0 +1,178 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// CopyChownConsistencyRuleCode is the full rule code.
+const CopyChownConsistencyRuleCode = rules.TallyRulePrefix + "copy-chown-consistency"
+
+// CopyChownConsistencyRule detects COPY/ADD instructions that run while the
+// stage is still root, in a stage that later switches to a non-root USER, and
+// whose destination is then handed over with a RUN chown or chmod. Copying
+// with --chown=<user> sets the ownership in the same layer instead.
+//
+// COPY/ADD that are not followed by a chown or chmod of their destination are
+// not reported: root-owned, read-only application files are a sound default.
+//
+// Cross-rule interactions:
+//
+//   - tally/copy-after-user-without-chown covers COPY/ADD after the non-root
+//     USER; this rule only looks at COPY/ADD before it, so the two never
+//     report the same instruction.
+//   - tally/prefer-copy-chmod (priority 99) folds the RUN chmod into
+//     COPY --chmod. Both rules insert flags at the same column and compose
+//     into COPY --chown=<user> --chmod=<mode>.
+type CopyChownConsistencyRule struct{}
+
+// NewCopyChownConsistencyRule creates a new rule instance.
+func NewCopyChownConsistencyRule() *CopyChownConsistencyRule {
+	return &CopyChownConsistencyRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *CopyChownConsistencyRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            CopyChownConsistencyRuleCode,
+		Name:            "COPY/ADD ownership consistent with later USER",
+		Description:     "COPY/ADD before a non-root USER is re-owned by RUN chown/chmod instead of --chown",
+		DocURL:          rules.TallyDocURL(CopyChownConsistencyRuleCode),
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "performance",
+		FixPriority:     99, // Match prefer-copy-chmod for COPY flag insertion
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// Check runs the rule across all stages.
+func (r *CopyChownConsistencyRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+	sm := input.SourceMap()
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		stageFacts := input.Facts.Stage(stageIdx)
+		if stageFacts == nil || stageFacts.EffectiveUser == "" || facts.IsRootUser(stageFacts.EffectiveUser) {
+			continue
+		}
+		if info := input.Semantic.StageInfo(stageIdx); info != nil && info.IsWindows() {
+			// --chown is ignored on Windows (tally/windows/no-chown-flag).
+			continue
+		}
+
+		ctx := copyChownCtx{
+			stageIdx: stageIdx,
+			stage:    stage,
+			file:     input.File,
+			sm:       sm,
+			meta:     meta,
+			variant:  stageShellVariantForCopyChown(input.Semantic, stageIdx),
+			workdir:  inheritedWorkdirForCopyChown(input.Facts, input.Semantic, stageIdx),
+		}
+		violations = append(violations,
+			r.checkStage(&ctx, inheritedUserForCopyChown(input.Semantic, input.Facts, stageIdx),
+				stageFacts.EffectiveUser)...)
+	}
+	return violations
+}
+
+// checkStage walks a stage and reports COPY/ADD that run as root and are
+// re-owned later. finalUser is the stage's last USER and becomes the
+// suggested --chown value.
+func (r *CopyChownConsistencyRule) checkStage(ctx *copyChownCtx, user, finalUser string) []rules.Violation {
+	var violations []rules.Violation
+	for cmdIdx, cmd := range ctx.stage.Commands {
+		switch c := cmd.(type) {
+		case *instructions.UserCommand:
+			user = c.User
+		case *instructions.WorkdirCommand:
+			ctx.workdir = facts.ResolveWorkdir(ctx.workdir, c.Path)
+		case *instructions.CopyCommand:
+			if v := r.checkCopyOrAdd(cmdIdx, c.Chown, c.DestPath, c.Location(), command.Copy, user, finalUser, ctx); v != nil {
+				violations = append(violations, *v)
+			}
+		case *instructions.AddCommand:
+			if v := r.checkCopyOrAdd(cmdIdx, c.Chown, c.DestPath, c.Location(), command.Add, user, finalUser, ctx); v != nil {
+				violations = append(violations, *v)
+			}
+		}
+	}
+	return violations
+}
+
+// checkCopyOrAdd evaluates a single COPY or ADD instruction.
+func (r *CopyChownConsistencyRule) checkCopyOrAdd(
+	cmdIdx int,
+	chown, destPath string,
+	loc []parser.Range,
+	keyword, user, finalUser string,
+	ctx *copyChownCtx,
+) *rules.Violation {
+	if chown != "" || len(loc) == 0 {
+		return nil
+	}
+	// After a non-root USER, tally/copy-after-user-without-chown applies.
+	if user != "" && !facts.IsRootUser(user) {
+		return nil
+	}
+
+	dest := resolveDestForChownCheck(destPath, ctx.workdir)
+	fixer := followingOwnershipFixer(ctx.stage, cmdIdx, dest, ctx.variant)
+	if fixer == "" {
+		return nil
+	}
+
+	upperKeyword := strings.ToUpper(keyword)
+	v := rules.NewViolation(rules.NewLocationFromRanges(ctx.file, loc), ctx.meta.Code,
+		fmt.Sprintf("%s creates root-owned files that a later RUN %s hands to USER %s",
+			upperKeyword, fixer, finalUser),
+		ctx.meta.DefaultSeverity,
+	).WithDocURL(ctx.meta.DocURL).
+		WithDetail(fmt.Sprintf(
+			"The stage switches to USER %s, and a RUN %s after this %s adjusts %s so that user can use it. "+
+				"Changing ownership in a later layer copies the files again. "+
+				"--chown=%s creates them with the right owner in the same layer.",
+			finalUser, fixer, upperKeyword, dest, finalUser,
+		))
+	v.StageIndex = ctx.stageIdx
+
+	line := loc[0].Start.Line
+	insertCol := findInstructionFlagInsertCol(ctx.sm, line, keyword)
+	v = v.WithSuggestedFix(&rules.SuggestedFix{
+		Description: fmt.Sprintf("Add --chown=%s to %s", finalUser, upperKeyword),
+		// The RUN chown/chmod is kept and may grant more than ownership
+		// (group bits, other paths); review before relying on --chown alone.
+		Safety:      rules.FixSuggestion,
+		IsPreferred: true,
+		Priority:    ctx.meta.FixPriority,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(ctx.file, line, insertCol, line, insertCol),
+			NewText:  "--chown=" + finalUser + " ",
+		}},
+	})
+	return &v
+}
+
+// followingOwnershipFixer returns "chown" or "chmod" when a RUN after
+// cmdIdx runs that command on dest, or "" when none does.
+func followingOwnershipFixer(stage instructions.Stage, cmdIdx int, dest string, variant shell.Variant) string {
+	for _, name := range []string{"chown", "chmod"} {
+		if hasFollowingRunTargetingPath(stage, cmdIdx, name, dest, variant) {
+			return name
+		}
+	}
+	return ""
+}
+
+func init() {
+	rules.Register(NewCopyChownConsistencyRule())
+}
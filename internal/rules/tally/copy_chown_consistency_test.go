@@ -0,0 +1,149 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestCopyChownConsistencyRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewCopyChownConsistencyRule().Metadata())
+}
+
+func TestCopyChownConsistencyRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewCopyChownConsistencyRule(), []testutil.RuleTestCase{
+		{
+			Name: "COPY then RUN chown then USER",
+			Content: `FROM ubuntu:22.04
+COPY app /app
+RUN chown -R app:app /app
+USER app
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"COPY creates root-owned files that a later RUN chown hands to USER app"},
+		},
+		{
+			Name: "ADD then RUN chmod then USER",
+			Content: `FROM ubuntu:22.04
+ADD data.tar.gz /srv/data/
+RUN chmod -R g+w /srv
+USER 1000:1000
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"ADD creates root-owned files that a later RUN chmod hands to USER 1000:1000"},
+		},
+		{
+			Name: "relative destination resolved against WORKDIR",
+			Content: `FROM ubuntu:22.04
+WORKDIR /app
+COPY . .
+RUN chown -R app /app
+USER app
+`,
+			WantViolations: 1,
+		},
+		{
+			Name: "no ownership change after COPY",
+			Content: `FROM ubuntu:22.04
+COPY app /app
+USER app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "chown of an unrelated path",
+			Content: `FROM ubuntu:22.04
+COPY app /app
+RUN chown -R app /var/lib/app
+USER app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "COPY already has --chown",
+			Content: `FROM ubuntu:22.04
+COPY --chown=app app /app
+RUN chown -R app /app
+USER app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "stage stays root",
+			Content: `FROM ubuntu:22.04
+COPY app /app
+RUN chown -R app /app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "stage switches back to root",
+			Content: `FROM ubuntu:22.04
+COPY app /app
+RUN chown -R app /app
+USER app
+RUN build
+USER root
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "COPY after non-root USER is left to copy-after-user-without-chown",
+			Content: `FROM ubuntu:22.04
+USER app
+COPY app /app
+RUN chown -R app /app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "Windows stage",
+			Content: `FROM mcr.microsoft.com/windows/servercore:ltsc2022
+COPY app C:/app
+RUN icacls C:/app
+USER ContainerUser
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestCopyChownConsistencyRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	content := `FROM ubuntu:22.04
+RUN useradd -r app
+COPY app /app
+RUN chown -R app:app /app
+USER app:app
+`
+	want := `FROM ubuntu:22.04
+RUN useradd -r app
+COPY --chown=app:app app /app
+RUN chown -R app:app /app
+USER app:app
+`
+
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	violations := NewCopyChownConsistencyRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	fix := violations[0].SuggestedFix
+	if fix == nil {
+		t.Fatal("violation has no SuggestedFix")
+	}
+	if fix.Safety != rules.FixSuggestion {
+		t.Errorf("fix safety = %v, want %v", fix.Safety, rules.FixSuggestion)
+	}
+	if got := string(fixpkg.ApplyFix([]byte(content), fix)); got != want {
+		t.Errorf("after fix:\ngot:  %q\nwant: %q", got, want)
+	}
+}
@@ -32,6 +32,7 @@ func (r *CopyFromEmptyScratchStageRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(CopyFromEmptyScratchStageRuleCode),
 		DefaultSeverity: rules.SeverityError,
 		Category:        "correctness",
+		RelatedTo:       []string{ShellRunInScratchRuleCode},
 	}
 }
 
@@ -0,0 +1,250 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/invocation"
+	"github.com/wharflab/tally/internal/registry"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// CopyFromUnresolvedImageRuleCode is the full rule code for the
+// copy-from-unresolved-image rule.
+const CopyFromUnresolvedImageRuleCode = rules.TallyRulePrefix + "copy-from-unresolved-image"
+
+// CopyFromUnresolvedImageConfig is the configuration for the
+// copy-from-unresolved-image rule.
+type CopyFromUnresolvedImageConfig struct {
+	// CheckPaths also looks up the COPY sources in the image layers (nil = use default).
+	CheckPaths *bool `json:"check-paths,omitempty" koanf:"check-paths"`
+}
+
+// DefaultCopyFromUnresolvedImageConfig returns the default configuration.
+func DefaultCopyFromUnresolvedImageConfig() CopyFromUnresolvedImageConfig {
+	checkPaths := false
+	return CopyFromUnresolvedImageConfig{CheckPaths: &checkPaths}
+}
+
+// CopyFromUnresolvedImageRule reports COPY --from references to external
+// images that do not exist in their registry or have no variant for the
+// stage's platform, and optionally COPY sources missing from the image.
+//
+// The rule is async-only: it needs registry access, so it only runs with slow
+// checks enabled. Looking up paths downloads every layer of the image and is
+// opt-in through check-paths.
+type CopyFromUnresolvedImageRule struct {
+	schema map[string]any
+}
+
+// NewCopyFromUnresolvedImageRule creates a new copy-from-unresolved-image rule instance.
+func NewCopyFromUnresolvedImageRule() *CopyFromUnresolvedImageRule {
+	schema, err := configutil.RuleSchema(CopyFromUnresolvedImageRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &CopyFromUnresolvedImageRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *CopyFromUnresolvedImageRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            CopyFromUnresolvedImageRuleCode,
+		Name:            "COPY --from image cannot be resolved",
+		Description:     "COPY --from references an external image that does not exist or lacks the copied paths",
+		DocURL:          rules.TallyDocURL(CopyFromUnresolvedImageRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *CopyFromUnresolvedImageRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *CopyFromUnresolvedImageRule) DefaultConfig() any {
+	return DefaultCopyFromUnresolvedImageConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *CopyFromUnresolvedImageRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(CopyFromUnresolvedImageRuleCode, config)
+}
+
+// Check returns nil — this is an async-only rule.
+func (r *CopyFromUnresolvedImageRule) Check(_ rules.LintInput) []rules.Violation {
+	return nil
+}
+
+// PlanAsync requests a lookup for each COPY --from that names an external
+// image. References that may be build contexts instead are skipped: bare
+// names such as --from=assets, unless the invocation declares its named
+// contexts, and names the invocation maps to a context.
+func (r *CopyFromUnresolvedImageRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+	cfg := configutil.Coerce(input.Config, DefaultCopyFromUnresolvedImageConfig())
+	checkPaths := cfg.CheckPaths != nil && *cfg.CheckPaths
+	contexts, knownContexts := namedBuildContexts(input)
+
+	meta := r.Metadata()
+	var requests []async.CheckRequest
+	for i := range sem.StageCount() {
+		info := sem.StageInfo(i)
+		if info == nil {
+			continue
+		}
+		platform, unresolved := semantic.ExpectedPlatform(info, sem)
+		if len(unresolved) > 0 || platform == "" {
+			continue
+		}
+		for _, ref := range info.CopyFromRefs {
+			if ref.IsStageRef || isStageIndex(ref.From) {
+				continue
+			}
+			if !isCopyFromImageRef(ref.From, knownContexts) || contexts[ref.From] {
+				continue
+			}
+
+			var paths []string
+			if checkPaths && ref.Command != nil {
+				paths = copyFromImagePaths(ref.Command.SourcePaths)
+			}
+			key := ref.From + "|" + platform
+			if len(paths) > 0 {
+				key += "|" + strings.Join(paths, "\x00")
+			}
+			requests = append(requests, async.CheckRequest{
+				RuleCode:   meta.Code,
+				Category:   async.CategoryNetwork,
+				Key:        key,
+				ResolverID: registry.CopySourceResolverID(),
+				Data:       &registry.CopySourceRequest{Ref: ref.From, Platform: platform, Paths: paths},
+				File:       input.File,
+				StageIndex: i,
+				Handler: &copyFromUnresolvedImageHandler{
+					meta:     meta,
+					file:     input.File,
+					ref:      ref.From,
+					platform: platform,
+					location: ref.Location,
+					stageIdx: i,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// namedBuildContexts returns the named contexts of the invocation, and
+// whether the invocation declares all of them: Bake and Compose do, while a
+// direct Dockerfile invocation does not see --build-context flags.
+func namedBuildContexts(input rules.LintInput) (map[string]bool, bool) {
+	inv := input.InvocationContext.Invocation()
+	if inv == nil {
+		return nil, false
+	}
+	contexts := make(map[string]bool, len(inv.NamedContexts))
+	for name := range inv.NamedContexts {
+		contexts[name] = true
+	}
+	switch inv.Source.Kind {
+	case invocation.KindBake, invocation.KindCompose:
+		return contexts, true
+	}
+	return contexts, false
+}
+
+// isCopyFromImageRef reports whether from can be looked up as an image.
+// Without the invocation's named contexts, only references with a tag,
+// digest or repository path are treated as images.
+func isCopyFromImageRef(from string, knownContexts bool) bool {
+	if from == "" || strings.Contains(from, "$") {
+		return false
+	}
+	if _, err := reference.ParseNormalizedNamed(from); err != nil {
+		return false
+	}
+	return knownContexts || strings.ContainsAny(from, ":@/")
+}
+
+// copyFromImagePaths returns the COPY sources as patterns relative to the
+// image root, dropping those that cannot be looked up.
+func copyFromImagePaths(sources []string) []string {
+	var paths []string
+	for _, src := range sources {
+		if strings.Contains(src, "$") {
+			continue
+		}
+		p := path.Clean("/" + src)
+		if _, err := path.Match(p, ""); err != nil {
+			continue
+		}
+		if !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// copyFromUnresolvedImageHandler turns a copy-source lookup into violations.
+type copyFromUnresolvedImageHandler struct {
+	meta     rules.RuleMetadata
+	file     string
+	ref      string
+	platform string
+	location []parser.Range
+	stageIdx int
+}
+
+func (h *copyFromUnresolvedImageHandler) OnSuccess(resolved any) []any {
+	result, ok := resolved.(*registry.CopySourceResult)
+	if !ok || result == nil {
+		return nil
+	}
+
+	var msg, detail string
+	switch {
+	case result.NotFound != nil:
+		msg = fmt.Sprintf("COPY --from image %s was not found in its registry", h.ref)
+		detail = "The build fails when BuildKit cannot pull the image. Check the repository and tag, " +
+			"or pass --build-context " + h.ref + "=... if the reference is meant to be a build context."
+	case result.PlatformMismatch != nil:
+		msg = fmt.Sprintf("COPY --from image %s has no %s variant", h.ref, h.platform)
+		detail = "The build fails when the image has no variant for the stage's platform. Available platforms: " +
+			strings.Join(result.PlatformMismatch.Available, ", ") + "."
+	case len(result.MissingPaths) > 0:
+		noun := "path"
+		if len(result.MissingPaths) > 1 {
+			noun = "paths"
+		}
+		msg = fmt.Sprintf("COPY --from image %s has no %s %s", h.ref, noun, strings.Join(result.MissingPaths, ", "))
+		detail = "COPY fails when a source matches nothing in the image. " +
+			"Check the path against the image version, since files move between releases."
+	default:
+		return []any{}
+	}
+
+	v := rules.NewViolation(rules.NewLocationFromRanges(h.file, h.location), h.meta.Code, msg, h.meta.DefaultSeverity).
+		WithDocURL(h.meta.DocURL).
+		WithDetail(detail)
+	v.StageIndex = h.stageIdx
+	return []any{v}
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewCopyFromUnresolvedImageRule())
+}
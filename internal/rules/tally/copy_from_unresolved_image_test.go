@@ -0,0 +1,174 @@
+package tally
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/invocation"
+	"github.com/wharflab/tally/internal/registry"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestCopyFromUnresolvedImageRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewCopyFromUnresolvedImageRule().Metadata())
+}
+
+func TestCopyFromUnresolvedImageRule_Check(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM alpine:3.20\nCOPY --from=busybox:1.36 /bin/busybox /bin/\n")
+	if got := NewCopyFromUnresolvedImageRule().Check(input); len(got) != 0 {
+		t.Errorf("Check() = %v, want no violations (async-only)", got)
+	}
+}
+
+func TestCopyFromUnresolvedImageRule_PlanAsync(t *testing.T) {
+	t.Parallel()
+	content := `FROM golang:1.25 AS build
+FROM --platform=linux/arm64 alpine:3.20
+COPY --from=build /app /app
+COPY --from=0 /app /app2
+COPY --from=assets /static /static
+COPY --from=${TOOLS} /bin/tool /bin/
+COPY --from=busybox:1.36 /bin/busybox /bin/
+COPY --from=ghcr.io/acme/tools /bin/tool /bin/
+ONBUILD COPY --from=nginx:1.27 /etc/nginx /etc/nginx
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	reqs := NewCopyFromUnresolvedImageRule().PlanAsync(input)
+
+	want := []string{"busybox:1.36", "ghcr.io/acme/tools"}
+	if len(reqs) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(reqs), len(want), reqs)
+	}
+	for i, ref := range want {
+		data, ok := reqs[i].Data.(*registry.CopySourceRequest)
+		if !ok || data.Ref != ref || reqs[i].ResolverID != registry.CopySourceResolverID() {
+			t.Errorf("request %d = %+v (data %+v), want ref %q", i, reqs[i], reqs[i].Data, ref)
+			continue
+		}
+		if data.Platform != "linux/arm64" || reqs[i].StageIndex != 1 || len(data.Paths) != 0 {
+			t.Errorf("request %d: platform %q, stage %d, paths %v", i, data.Platform, reqs[i].StageIndex, data.Paths)
+		}
+	}
+}
+
+func TestCopyFromUnresolvedImageRule_PlanAsync_CheckPaths(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine:3.20\nCOPY --from=busybox:1.36 bin/busybox /bin/* ./bin/busybox ${X} /bin/\n"
+	input := testutil.MakeLintInputWithConfig(t, "Dockerfile", content, map[string]any{"check-paths": true})
+	reqs := NewCopyFromUnresolvedImageRule().PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	data, ok := reqs[0].Data.(*registry.CopySourceRequest)
+	if !ok {
+		t.Fatalf("data is %T", reqs[0].Data)
+	}
+	if want := []string{"/bin/busybox", "/bin/*"}; !slices.Equal(data.Paths, want) {
+		t.Errorf("paths = %v, want %v", data.Paths, want)
+	}
+	if !strings.Contains(reqs[0].Key, "/bin/busybox") {
+		t.Errorf("key %q does not include the paths", reqs[0].Key)
+	}
+}
+
+func TestCopyFromUnresolvedImageRule_PlanAsync_NamedContexts(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine:3.20\nCOPY --from=assets /static /static\nCOPY --from=tools:1 /bin/tool /bin/\n"
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	input.InvocationContext = invocation.NewContext(&invocation.BuildInvocation{
+		Source: invocation.InvocationSource{Kind: invocation.KindBake, Name: "app"},
+		NamedContexts: map[string]invocation.ContextRef{
+			"tools:1": {Kind: invocation.ContextKindDir, Value: "/src/tools"},
+		},
+	})
+
+	// Bake declares every named context: "assets" is an image, "tools:1" is not.
+	reqs := NewCopyFromUnresolvedImageRule().PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1: %+v", len(reqs), reqs)
+	}
+	if data, ok := reqs[0].Data.(*registry.CopySourceRequest); !ok || data.Ref != "assets" {
+		t.Errorf("request data = %+v, want ref assets", reqs[0].Data)
+	}
+}
+
+func TestCopyFromUnresolvedImageHandler_OnSuccess(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		result  *registry.CopySourceResult
+		message string
+		detail  string
+	}{
+		{
+			name:    "image not found",
+			result:  &registry.CopySourceResult{NotFound: &registry.NotFoundError{Ref: "busybox:1.99"}},
+			message: "COPY --from image busybox:1.99 was not found in its registry",
+			detail:  "--build-context busybox:1.99=...",
+		},
+		{
+			name: "platform missing",
+			result: &registry.CopySourceResult{PlatformMismatch: &registry.PlatformMismatchError{
+				Available: []string{"linux/amd64", "linux/arm64"},
+			}},
+			message: "COPY --from image busybox:1.99 has no linux/amd64 variant",
+			detail:  "Available platforms: linux/amd64, linux/arm64.",
+		},
+		{
+			name:    "paths missing",
+			result:  &registry.CopySourceResult{MissingPaths: []string{"/bin/tool", "/etc/tool"}},
+			message: "COPY --from image busybox:1.99 has no paths /bin/tool, /etc/tool",
+			detail:  "matches nothing in the image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile",
+				"FROM --platform=linux/amd64 alpine:3.20\nCOPY --from=busybox:1.99 /bin/tool /bin/\n")
+			reqs := NewCopyFromUnresolvedImageRule().PlanAsync(input)
+			if len(reqs) != 1 {
+				t.Fatalf("got %d requests, want 1", len(reqs))
+			}
+			out := reqs[0].Handler.OnSuccess(tt.result)
+			if len(out) != 1 {
+				t.Fatalf("got %d results, want 1", len(out))
+			}
+			v, ok := out[0].(rules.Violation)
+			if !ok {
+				t.Fatalf("result is %T, want rules.Violation", out[0])
+			}
+			if v.Message != tt.message {
+				t.Errorf("message = %q, want %q", v.Message, tt.message)
+			}
+			if !strings.Contains(v.Detail, tt.detail) {
+				t.Errorf("detail = %q, want substring %q", v.Detail, tt.detail)
+			}
+			if v.Severity != rules.SeverityWarning || v.StageIndex != 0 || v.Location.Start.Line != 2 {
+				t.Errorf("severity = %v, stage = %d, location = %+v", v.Severity, v.StageIndex, v.Location)
+			}
+		})
+	}
+}
+
+func TestCopyFromUnresolvedImageHandler_OnSuccess_Resolved(t *testing.T) {
+	t.Parallel()
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM alpine:3.20\nCOPY --from=busybox:1.36 /bin/busybox /bin/\n")
+	reqs := NewCopyFromUnresolvedImageRule().PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	if out := reqs[0].Handler.OnSuccess(&registry.CopySourceResult{}); out == nil || len(out) != 0 {
+		t.Errorf("OnSuccess(resolved) = %v, want an empty non-nil slice", out)
+	}
+	if out := reqs[0].Handler.OnSuccess(&registry.ImageConfig{}); out != nil {
+		t.Errorf("OnSuccess(wrong type) = %v, want nil", out)
+	}
+}
@@ -39,6 +39,7 @@ func (r *CurlShouldFollowRedirectsRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(CurlShouldFollowRedirectsRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
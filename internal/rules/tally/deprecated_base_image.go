@@ -0,0 +1,264 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// DeprecatedBaseImageRuleCode is the full rule code for the deprecated-base-image rule.
+const DeprecatedBaseImageRuleCode = rules.TallyRulePrefix + "deprecated-base-image"
+
+// deprecatedImage is a retired image and the image that replaces it.
+type deprecatedImage struct {
+	// successor is the repository to use instead, or "" when there is no
+	// single replacement.
+	successor string
+	// sameTags reports whether the successor publishes the tags of the
+	// deprecated image, so that the fix can keep the tag.
+	sameTags bool
+	// note explains the deprecation.
+	note string
+}
+
+// deprecatedOfficialImages maps the familiar names of deprecated Docker
+// Official Images (and a few retired vendor images) to their successors.
+var deprecatedOfficialImages = map[string]deprecatedImage{
+	"openjdk": {
+		successor: "eclipse-temurin", sameTags: true,
+		note: "The openjdk image was deprecated in 2022 and only ever received early-access builds after that.",
+	},
+	"adoptopenjdk": {
+		successor: "eclipse-temurin", sameTags: true,
+		note: "AdoptOpenJDK moved to the Eclipse Foundation; its builds are published as eclipse-temurin.",
+	},
+	"java": {
+		successor: "eclipse-temurin", sameTags: true,
+		note: "The java image was deprecated in 2017 and has not been updated since.",
+	},
+	"mysql/mysql-server": {
+		successor: "container-registry.oracle.com/mysql/community-server", sameTags: true,
+		note: "Oracle stopped publishing mysql/mysql-server to Docker Hub; use the Oracle Container Registry or the mysql image.",
+	},
+	"jenkins": {
+		successor: "jenkins/jenkins", sameTags: true,
+		note: "The jenkins image was deprecated in favor of jenkins/jenkins, maintained by the Jenkins project.",
+	},
+	"consul": {
+		successor: "hashicorp/consul", sameTags: true,
+		note: "HashiCorp stopped updating the consul image in 2023; releases are published as hashicorp/consul.",
+	},
+	"vault": {
+		successor: "hashicorp/vault", sameTags: true,
+		note: "HashiCorp stopped updating the vault image in 2023; releases are published as hashicorp/vault.",
+	},
+	"owncloud": {
+		successor: "owncloud/server",
+		note:      "The owncloud image was deprecated in favor of owncloud/server, maintained by ownCloud.",
+	},
+	"ubuntu-debootstrap": {
+		successor: "ubuntu", sameTags: true,
+		note: "The ubuntu-debootstrap image was deprecated; the ubuntu image is built the same way.",
+	},
+	"iojs": {
+		successor: "node",
+		note:      "io.js merged back into Node.js in 2015.",
+	},
+	"django": {
+		successor: "python",
+		note:      "The django image was deprecated in 2016; install Django with pip on a python image.",
+	},
+	"rails": {
+		successor: "ruby",
+		note:      "The rails image was deprecated in 2016; install Rails with bundler on a ruby image.",
+	},
+	"celery": {
+		successor: "python",
+		note:      "The celery image was deprecated in 2017; install Celery with pip on a python image.",
+	},
+	"centos": {
+		note: "CentOS Linux reached end-of-life and the centos image is no longer updated; " +
+			"use quay.io/centos/centos for CentOS Stream, or almalinux or rockylinux.",
+	},
+}
+
+// DeprecatedBaseImageConfig is the configuration for the deprecated-base-image rule.
+type DeprecatedBaseImageConfig struct {
+	// Replacements maps further deprecated images to their successors, e.g.
+	// internal image renames. An empty successor flags the image without a
+	// fix. Entries override the built-in mapping.
+	Replacements map[string]string `json:"replacements,omitempty" koanf:"replacements"`
+}
+
+// DefaultDeprecatedBaseImageConfig returns the default configuration.
+func DefaultDeprecatedBaseImageConfig() DeprecatedBaseImageConfig {
+	return DeprecatedBaseImageConfig{}
+}
+
+// DeprecatedBaseImageRule flags FROM instructions that use a deprecated or
+// renamed image (openjdk, jenkins, mysql/mysql-server, ...) and offers to
+// rewrite the reference to the successor, keeping the tag when the successor
+// publishes the same tags.
+type DeprecatedBaseImageRule struct {
+	schema map[string]any
+}
+
+// NewDeprecatedBaseImageRule creates a new deprecated-base-image rule instance.
+func NewDeprecatedBaseImageRule() *DeprecatedBaseImageRule {
+	schema, err := configutil.RuleSchema(DeprecatedBaseImageRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &DeprecatedBaseImageRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *DeprecatedBaseImageRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            DeprecatedBaseImageRuleCode,
+		Name:            "Deprecated base image",
+		Description:     "Base image is deprecated or was renamed and no longer receives updates",
+		DocURL:          rules.TallyDocURL(DeprecatedBaseImageRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "maintainability",
+		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *DeprecatedBaseImageRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *DeprecatedBaseImageRule) DefaultConfig() any {
+	return DefaultDeprecatedBaseImageConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *DeprecatedBaseImageRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(DeprecatedBaseImageRuleCode, config)
+}
+
+// Check runs the deprecated-base-image rule.
+func (r *DeprecatedBaseImageRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Semantic == nil {
+		return nil
+	}
+	cfg := configutil.Coerce(input.Config, DefaultDeprecatedBaseImageConfig())
+	replacements := deprecatedImageReplacements(cfg.Replacements)
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for info := range input.Semantic.ExternalImageStages() {
+		base := info.BaseImage
+		if base == nil {
+			continue
+		}
+		ref := base.Effective
+		if ref == "" {
+			ref = base.Raw
+		}
+		named, err := reference.ParseNormalizedNamed(ref)
+		if err != nil {
+			continue
+		}
+		name := reference.FamiliarName(named)
+		deprecated, ok := replacements[name]
+		if !ok {
+			continue
+		}
+
+		msg := fmt.Sprintf("base image %s is deprecated", name)
+		if deprecated.successor != "" {
+			msg += "; use " + deprecated.successor + " instead"
+		}
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(input.File, base.Location), meta.Code, msg, meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL)
+		if deprecated.note != "" {
+			v = v.WithDetail(deprecated.note)
+		}
+		v.StageIndex = info.Index
+		if fix := deprecatedBaseImageFix(input, base, named, deprecated); fix != nil {
+			v = v.WithSuggestedFix(fix)
+		}
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// deprecatedImageReplacements merges the configured replacements over the
+// built-in mapping, keyed by familiar image name.
+func deprecatedImageReplacements(configured map[string]string) map[string]deprecatedImage {
+	if len(configured) == 0 {
+		return deprecatedOfficialImages
+	}
+	merged := make(map[string]deprecatedImage, len(deprecatedOfficialImages)+len(configured))
+	for name, d := range deprecatedOfficialImages {
+		merged[name] = d
+	}
+	for image, successor := range configured {
+		name := image
+		if named, err := reference.ParseNormalizedNamed(image); err == nil {
+			name = reference.FamiliarName(named)
+		}
+		// Configured renames are assumed to keep their tags.
+		merged[name] = deprecatedImage{successor: strings.TrimSpace(successor), sameTags: true}
+	}
+	return merged
+}
+
+// deprecatedBaseImageFix rewrites the FROM reference to the successor. The
+// tag is kept when the successor publishes the same tags; a digest never is,
+// since it identifies the deprecated image. No fix is offered for references
+// that use ARG variables or for images without a tag-compatible successor.
+func deprecatedBaseImageFix(
+	input rules.LintInput,
+	base *semantic.BaseImageRef,
+	named reference.Named,
+	deprecated deprecatedImage,
+) *rules.SuggestedFix {
+	if deprecated.successor == "" || !deprecated.sameTags || strings.Contains(base.Raw, "$") {
+		return nil
+	}
+	newRef := deprecated.successor
+	if tagged, ok := named.(reference.Tagged); ok {
+		newRef += ":" + tagged.Tag()
+	}
+	if len(base.Location) == 0 {
+		return nil
+	}
+
+	sm := input.SourceMap()
+	startLine := base.Location[0].Start.Line
+	endLine := base.Location[len(base.Location)-1].End.Line
+	for lineNo := startLine; lineNo <= endLine; lineNo++ {
+		line := sm.Line(lineNo - 1)
+		idx := strings.Index(line, base.Raw)
+		if idx < 0 {
+			continue
+		}
+		return &rules.SuggestedFix{
+			Description: "Replace " + reference.FamiliarString(named) + " with " + newRef,
+			Safety:      rules.FixSuggestion,
+			IsPreferred: true,
+			Edits: []rules.TextEdit{{
+				Location: rules.NewRangeLocation(input.File, lineNo, idx, lineNo, idx+len(base.Raw)),
+				NewText:  newRef,
+			}},
+		}
+	}
+	return nil
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDeprecatedBaseImageRule())
+}
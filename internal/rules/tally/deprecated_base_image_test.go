@@ -0,0 +1,167 @@
+package tally
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestDeprecatedBaseImageRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDeprecatedBaseImageRule().Metadata())
+}
+
+func TestDeprecatedBaseImageRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewDeprecatedBaseImageRule(), []testutil.RuleTestCase{
+		{
+			Name:           "openjdk",
+			Content:        "FROM openjdk:17-jdk AS build\nRUN javac Main.java\n",
+			WantViolations: 1,
+			WantMessages:   []string{"base image openjdk is deprecated; use eclipse-temurin instead"},
+		},
+		{
+			Name:           "fully qualified reference",
+			Content:        "FROM docker.io/library/openjdk:11\n",
+			WantViolations: 1,
+			WantMessages:   []string{"base image openjdk is deprecated"},
+		},
+		{
+			Name:           "vendor image",
+			Content:        "FROM mysql/mysql-server:8.0\n",
+			WantViolations: 1,
+			WantMessages:   []string{"use container-registry.oracle.com/mysql/community-server instead"},
+		},
+		{
+			Name:           "no successor",
+			Content:        "FROM centos:7\n",
+			WantViolations: 1,
+			WantMessages:   []string{"base image centos is deprecated"},
+		},
+		{
+			Name:           "ARG in FROM is expanded",
+			Content:        "ARG JDK=openjdk:17\nFROM ${JDK}\n",
+			WantViolations: 1,
+		},
+		{
+			Name: "current images and stage references",
+			Content: `FROM eclipse-temurin:21-jdk AS build
+FROM mysql:8.4
+FROM build
+FROM registry.example.com/openjdk:17
+`,
+			WantViolations: 0,
+		},
+		{
+			Name:           "configured rename",
+			Content:        "FROM registry.example.com/base/java:21\n",
+			Config:         DeprecatedBaseImageConfig{Replacements: map[string]string{"registry.example.com/base/java": "registry.example.com/base/temurin"}},
+			WantViolations: 1,
+			WantMessages:   []string{"base image registry.example.com/base/java is deprecated; use registry.example.com/base/temurin instead"},
+		},
+		{
+			Name:           "configured entry overrides the built-in one",
+			Content:        "FROM openjdk:17\n",
+			Config:         map[string]any{"replacements": map[string]any{"openjdk": "registry.example.com/temurin"}},
+			WantViolations: 1,
+			WantMessages:   []string{"use registry.example.com/temurin instead"},
+		},
+	})
+}
+
+func TestDeprecatedBaseImageRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		config  any
+		want    string
+	}{
+		{
+			name:    "keeps the tag and stage name",
+			content: "FROM openjdk:17-jdk AS build\nRUN javac Main.java\n",
+			want:    "FROM eclipse-temurin:17-jdk AS build\nRUN javac Main.java\n",
+		},
+		{
+			name:    "drops the digest",
+			content: "FROM --platform=linux/amd64 jenkins:2.60.3@sha256:" + strings.Repeat("a", 64) + "\n",
+			want:    "FROM --platform=linux/amd64 jenkins/jenkins:2.60.3\n",
+		},
+		{
+			name:    "no tag",
+			content: "FROM docker.io/library/consul\n",
+			want:    "FROM hashicorp/consul\n",
+		},
+		{
+			name:    "configured rename",
+			content: "FROM registry.example.com/base/java:21 AS runtime\n",
+			config: DeprecatedBaseImageConfig{
+				Replacements: map[string]string{"registry.example.com/base/java": "registry.example.com/base/temurin"},
+			},
+			want: "FROM registry.example.com/base/temurin:21 AS runtime\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			input.Config = tt.config
+			violations := NewDeprecatedBaseImageRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			fix := violations[0].SuggestedFix
+			if fix == nil {
+				t.Fatal("violation has no SuggestedFix")
+			}
+			if fix.Safety != rules.FixSuggestion {
+				t.Errorf("fix safety = %v, want %v", fix.Safety, rules.FixSuggestion)
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.content), fix)); got != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeprecatedBaseImageRule_NoFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		config  any
+	}{
+		{name: "no successor", content: "FROM centos:7\n"},
+		{name: "successor with other tags", content: "FROM django:3\n"},
+		{name: "ARG in FROM", content: "ARG JDK=openjdk:17\nFROM ${JDK}\n"},
+		{
+			name:    "configured without successor",
+			content: "FROM registry.example.com/legacy:1\n",
+			config:  DeprecatedBaseImageConfig{Replacements: map[string]string{"registry.example.com/legacy": ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			input.Config = tt.config
+			violations := NewDeprecatedBaseImageRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			if violations[0].SuggestedFix != nil {
+				t.Errorf("unexpected fix: %s", violations[0].SuggestedFix.Description)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// DuplicateStageWorkRuleCode is the full rule code for the duplicate-stage-work rule.
+const DuplicateStageWorkRuleCode = rules.TallyRulePrefix + "duplicate-stage-work"
+
+// minDuplicateCommands is the number of shell commands a RUN block needs
+// before a repeat is worth reporting. Single commands such as `RUN make` are
+// commonly repeated on purpose.
+const minDuplicateCommands = 2
+
+// DuplicateStageWorkRule reports RUN blocks that repeat the work of a RUN
+// block in another stage.
+//
+// A block is a run of consecutive RUN instructions in a stage. Blocks are
+// compared by their normalized shell commands (see shell.NormalizedCommands)
+// and the WORKDIR they run in, so layout differences and splitting the same
+// commands into more or fewer RUN instructions don't hide a repeat.
+//
+// A repeat is reported when it can be avoided:
+//   - the later stage builds on the earlier one, so the work already ran
+//     in its base; or
+//   - both stages start from the same base, so the block can move into a
+//     shared base stage.
+//
+// Stages with different bases are not compared: the same commands on
+// different images are different work.
+type DuplicateStageWorkRule struct{}
+
+// NewDuplicateStageWorkRule creates a new duplicate-stage-work rule instance.
+func NewDuplicateStageWorkRule() *DuplicateStageWorkRule {
+	return &DuplicateStageWorkRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *DuplicateStageWorkRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            DuplicateStageWorkRuleCode,
+		Name:            "Duplicate Stage Work",
+		Description:     "RUN instructions repeat the work of another stage",
+		DocURL:          rules.TallyDocURL(DuplicateStageWorkRuleCode),
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "performance",
+		IsExperimental:  false,
+	}
+}
+
+// runBlock is a run of consecutive RUN instructions in one stage.
+type runBlock struct {
+	stageIdx int
+	runs     []*facts.RunFacts
+	commands []string
+}
+
+// fingerprint identifies the work of the block. The shell variant is left
+// out: normalized commands already differ between shells that parse them
+// differently, and a stage built FROM another stage may not know its shell
+// as precisely as the stage it builds on.
+func (b *runBlock) fingerprint() string {
+	return b.runs[0].Workdir + "\x00" + strings.Join(b.commands, "\n")
+}
+
+// Check runs the duplicate-stage-work rule.
+func (r *DuplicateStageWorkRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Facts == nil || input.Semantic == nil || len(input.Stages) < 2 {
+		return nil
+	}
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	seen := make(map[string][]*runBlock)
+	for stageIdx, stage := range input.Stages {
+		for _, block := range stageRunBlocks(input.Facts.Stage(stageIdx), stage) {
+			if len(block.commands) < minDuplicateCommands {
+				continue
+			}
+			key := block.fingerprint()
+			if msg, detail := describeDuplicateBlock(input.Semantic, seen[key], block); msg != "" {
+				loc := rules.NewLocationFromRanges(input.File, block.runs[0].Run.Location())
+				violations = append(violations, rules.NewViolation(loc, meta.Code, msg, meta.DefaultSeverity).
+					WithDocURL(meta.DocURL).
+					WithDetail(detail))
+			}
+			seen[key] = append(seen[key], block)
+		}
+	}
+	return violations
+}
+
+// stageRunBlocks splits the RUN instructions of a stage into blocks of
+// consecutive RUNs.
+func stageRunBlocks(sf *facts.StageFacts, stage instructions.Stage) []*runBlock {
+	if sf == nil {
+		return nil
+	}
+	runsByCommand := make(map[int]*facts.RunFacts, len(sf.Runs))
+	for _, run := range sf.Runs {
+		runsByCommand[run.CommandIndex] = run
+	}
+
+	var blocks []*runBlock
+	var current *runBlock
+	for cmdIdx := range stage.Commands {
+		run, ok := runsByCommand[cmdIdx]
+		if !ok {
+			current = nil
+			continue
+		}
+		if current == nil {
+			current = &runBlock{stageIdx: sf.Index}
+			blocks = append(blocks, current)
+		}
+		current.runs = append(current.runs, run)
+		current.commands = append(current.commands, shell.NormalizedCommands(run.CommandScript, run.Shell.Variant)...)
+	}
+	return blocks
+}
+
+// describeDuplicateBlock returns the message and detail for block when it
+// repeats one of the earlier blocks with the same fingerprint, or empty
+// strings when none of them is an avoidable repeat.
+func describeDuplicateBlock(sem *semantic.Model, earlier []*runBlock, block *runBlock) (string, string) {
+	for _, prev := range earlier {
+		if prev.stageIdx == block.stageIdx {
+			continue
+		}
+		prevLine := prev.runs[0].Run.Location()[0].Start.Line
+		prevStage := formatStageName(sem, prev.stageIdx)
+		if stageDescendsFrom(sem, block.stageIdx, prev.stageIdx) {
+			return fmt.Sprintf("RUN repeats work already done in base %s (line %d)", prevStage, prevLine),
+				"The same commands ran in a stage this one builds on, so their result is already in the image. " +
+					"Remove the repeated RUN instructions."
+		}
+		if sameStageBase(sem, block.stageIdx, prev.stageIdx) {
+			return fmt.Sprintf("RUN duplicates the work of %s (line %d)", prevStage, prevLine),
+				"Both stages start from the same base and run the same commands. " +
+					"Move the commands into a shared stage and start both stages FROM it, " +
+					"so the work runs and is cached once."
+		}
+	}
+	return "", ""
+}
+
+// stageDescendsFrom reports whether stage idx builds on stage ancestor
+// through its chain of FROM <stage> references.
+func stageDescendsFrom(sem *semantic.Model, idx, ancestor int) bool {
+	visited := map[int]bool{idx: true}
+	for {
+		info := sem.StageInfo(idx)
+		if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef || info.BaseImage.StageIndex < 0 {
+			return false
+		}
+		idx = info.BaseImage.StageIndex
+		if idx == ancestor {
+			return true
+		}
+		if visited[idx] {
+			return false
+		}
+		visited[idx] = true
+	}
+}
+
+// sameStageBase reports whether two stages start FROM the same stage, or
+// from the same external image and platform.
+func sameStageBase(sem *semantic.Model, a, b int) bool {
+	infoA, infoB := sem.StageInfo(a), sem.StageInfo(b)
+	if infoA == nil || infoB == nil || infoA.BaseImage == nil || infoB.BaseImage == nil {
+		return false
+	}
+	baseA, baseB := infoA.BaseImage, infoB.BaseImage
+	if baseA.IsStageRef || baseB.IsStageRef {
+		return baseA.IsStageRef && baseB.IsStageRef && baseA.StageIndex == baseB.StageIndex && baseA.StageIndex >= 0
+	}
+	return !infoA.IsScratch() && baseA.Effective == baseB.Effective && baseA.Platform == baseB.Platform
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewDuplicateStageWorkRule())
+}
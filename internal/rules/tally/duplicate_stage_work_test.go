@@ -0,0 +1,126 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestDuplicateStageWorkMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewDuplicateStageWorkRule().Metadata())
+}
+
+func TestDuplicateStageWorkRule(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewDuplicateStageWorkRule(), []testutil.RuleTestCase{
+		{
+			Name: "blocks differ in a later RUN",
+			Content: `FROM debian:bookworm AS build
+RUN apt-get update && apt-get install -y curl git
+RUN make
+
+FROM debian:bookworm AS test
+RUN apt-get update \
+    && apt-get install -y   curl git
+RUN make test
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "same base, same consecutive RUNs",
+			Content: `FROM debian:bookworm AS build
+RUN apt-get update && apt-get install -y curl git
+
+FROM debian:bookworm AS test
+RUN apt-get update \
+    && apt-get install -y   curl git
+`,
+			WantViolations: 1,
+			WantMessages:   []string{`RUN duplicates the work of "build" (line 2)`},
+		},
+		{
+			Name: "split into separate RUNs",
+			Content: `FROM node:22 AS deps
+RUN npm ci && npm run build
+
+FROM node:22 AS lint
+RUN npm ci
+RUN npm run build
+`,
+			WantViolations: 1,
+			WantMessages:   []string{`RUN duplicates the work of "deps" (line 2)`},
+		},
+		{
+			Name: "repeated in a descendant stage",
+			Content: `FROM alpine:3.21 AS base
+RUN apk add --no-cache curl && curl --version
+
+FROM base AS app
+RUN apk add --no-cache curl && curl --version
+`,
+			WantViolations: 1,
+			WantMessages:   []string{`RUN repeats work already done in base "base" (line 2)`},
+		},
+		{
+			Name: "different bases",
+			Content: `FROM golang:1.24 AS build
+RUN apt-get update && apt-get install -y curl
+
+FROM debian:bookworm
+RUN apt-get update && apt-get install -y curl
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "different workdir",
+			Content: `FROM node:22 AS a
+WORKDIR /a
+RUN npm ci && npm run build
+
+FROM node:22 AS b
+WORKDIR /b
+RUN npm ci && npm run build
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "single command is not reported",
+			Content: `FROM node:22 AS a
+RUN npm ci
+
+FROM node:22 AS b
+RUN npm ci
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "repeat within one stage",
+			Content: `FROM node:22
+RUN npm ci && npm run build
+COPY . .
+RUN npm ci && npm run build
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "reported once per block",
+			Content: `FROM python:3.13 AS a
+RUN pip install -U pip && pip install poetry
+
+FROM python:3.13 AS b
+RUN pip install -U pip && pip install poetry
+
+FROM python:3.13 AS c
+RUN pip install -U pip && pip install poetry
+`,
+			WantViolations: 2,
+			WantMessages: []string{
+				`RUN duplicates the work of "a" (line 2)`,
+				`RUN duplicates the work of "a" (line 2)`,
+			},
+		},
+	})
+}
@@ -0,0 +1,276 @@
+package tally
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	dfshell "github.com/moby/buildkit/frontend/dockerfile/shell"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// EnvLayerConsolidationRuleCode is the full rule code for the env-layer-consolidation rule.
+const EnvLayerConsolidationRuleCode = rules.TallyRulePrefix + "env-layer-consolidation"
+
+// EnvLayerConsolidationRule detects ENV instructions in a stage that can be
+// merged into a single multi-key ENV.
+//
+// ENV instructions are grouped while merging them cannot change any value:
+//
+//   - a RUN, ARG or ONBUILD between two ENVs ends the group, since the
+//     process environment and ARG/ENV precedence depend on their order;
+//   - any other instruction between them ends the group when it mentions a
+//     variable set by the later ENV, which it would otherwise start to see;
+//   - an ENV whose values reference a variable set earlier in the group, or
+//     that sets such a variable again, starts a new group, because all values
+//     of one ENV are expanded with the environment from before it.
+//
+// Cross-rule interactions:
+//   - buildkit/LegacyKeyValueFormat (priority 91): an ENV in the legacy
+//     "ENV key value" form whose value contains whitespace ends the group;
+//     once rewritten, a later fix iteration can merge it.
+//   - prefer-telemetry-opt-out (priority 96): adds ENV instructions this rule
+//     may merge on a later run or fix iteration.
+type EnvLayerConsolidationRule struct{}
+
+// NewEnvLayerConsolidationRule creates a new env-layer-consolidation rule instance.
+func NewEnvLayerConsolidationRule() *EnvLayerConsolidationRule {
+	return &EnvLayerConsolidationRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *EnvLayerConsolidationRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            EnvLayerConsolidationRuleCode,
+		Name:            "ENV layer consolidation",
+		Description:     "ENV instructions in a stage that can be merged into a single ENV",
+		DocURL:          rules.TallyDocURL(EnvLayerConsolidationRuleCode),
+		DefaultSeverity: rules.SeverityStyle,
+		Category:        "style",
+		FixPriority:     97, //nolint:mnd // After legacy key/value (91) and telemetry ENV (96) fixes, before heredoc transforms.
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// envGroup is a run of ENV instructions that can be merged.
+type envGroup struct {
+	envs []*instructions.EnvCommand
+	// keys are the variables set by the group.
+	keys map[string]bool
+	// between holds the source text of the instructions between the group's
+	// ENVs, which a merged ENV would move in front of.
+	between []string
+}
+
+// Check runs the env-layer-consolidation rule.
+func (r *EnvLayerConsolidationRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	escapeToken := dockerfile.ASTEscapeToken(input.AST)
+	lex := dfshell.NewLex(escapeToken)
+	lex.SkipProcessQuotes = true
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		var group *envGroup
+		flush := func() {
+			if group != nil && len(group.envs) > 1 {
+				v := r.violation(input.File, sm, escapeToken, group, meta)
+				v.StageIndex = stageIdx
+				violations = append(violations, v)
+			}
+			group = nil
+		}
+
+		for _, cmd := range stage.Commands {
+			switch cmd := cmd.(type) {
+			case *instructions.EnvCommand:
+				if !mergeableEnv(cmd) {
+					flush()
+					continue
+				}
+				if group != nil && !group.accepts(cmd, lex) {
+					flush()
+				}
+				if group == nil {
+					group = &envGroup{keys: make(map[string]bool)}
+				}
+				group.envs = append(group.envs, cmd)
+				for _, kv := range cmd.Env {
+					group.keys[kv.Key] = true
+				}
+			case *instructions.RunCommand, *instructions.ArgCommand, *instructions.OnbuildCommand:
+				flush()
+			default:
+				if group != nil {
+					group.between = append(group.between, instructionText(sm, cmd))
+				}
+			}
+		}
+		flush()
+	}
+	return violations
+}
+
+// accepts reports whether env can be merged into the group.
+func (g *envGroup) accepts(env *instructions.EnvCommand, lex *dfshell.Lex) bool {
+	for _, kv := range env.Env {
+		if g.keys[kv.Key] {
+			return false
+		}
+		refs, ok := envValueRefs(lex, kv.Value)
+		if !ok {
+			return false
+		}
+		for _, ref := range refs {
+			if g.keys[ref] {
+				return false
+			}
+		}
+		for _, text := range g.between {
+			if mentionsVariable(text, kv.Key) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *EnvLayerConsolidationRule) violation(
+	file string,
+	sm *sourcemap.SourceMap,
+	escapeToken rune,
+	group *envGroup,
+	meta rules.RuleMetadata,
+) rules.Violation {
+	first := group.envs[0]
+	last := group.envs[len(group.envs)-1]
+	loc := rules.NewLocationFromRanges(file, first.Location())
+	loc.End = rules.NewLocationFromRanges(file, last.Location()).End
+
+	lines := make([]string, 0, len(group.envs))
+	var pairs []string
+	for _, env := range group.envs {
+		lines = append(lines, strconv.Itoa(env.Location()[0].Start.Line))
+		for _, kv := range env.Env {
+			pairs = append(pairs, kv.Key+"="+kv.Value)
+		}
+	}
+
+	edits := make([]rules.TextEdit, 0, len(group.envs))
+	firstLoc := first.Location()
+	edits = append(edits, rules.TextEdit{
+		Location: rules.NewRangeLocation(file,
+			firstLoc[0].Start.Line, firstLoc[0].Start.Character,
+			sm.ResolveEndLineWithEscape(firstLoc[len(firstLoc)-1].End.Line, escapeToken)+1, 0),
+		NewText: "ENV " + strings.Join(pairs, " "+string(escapeToken)+"\n    ") + "\n",
+	})
+	for _, env := range group.envs[1:] {
+		envLoc := env.Location()
+		edits = append(edits, rules.TextEdit{
+			Location: rules.NewRangeLocation(file,
+				envLoc[0].Start.Line, 0,
+				sm.ResolveEndLineWithEscape(envLoc[len(envLoc)-1].End.Line, escapeToken)+1, 0),
+			NewText: "",
+		})
+	}
+
+	// Merging is a style change only, so it stays out of formatting and
+	// "apply all fixes" and is offered as a suggestion.
+	detail := fmt.Sprintf("The ENV instructions on lines %s set independent variables. "+
+		"Each ENV adds an entry to the image history; a single ENV with several keys sets them all at once.",
+		joinLineList(lines))
+	if len(group.between) > 0 {
+		detail += " Merging moves the later ENVs above instructions that do not use their variables."
+	}
+
+	return rules.NewViolation(loc, meta.Code,
+		fmt.Sprintf("%d ENV instructions can be merged into one", len(group.envs)),
+		meta.DefaultSeverity).
+		WithDocURL(meta.DocURL).
+		WithDetail(detail).
+		WithSuggestedFix(&rules.SuggestedFix{
+			Description: "Merge ENV instructions into a single ENV",
+			Safety:      rules.FixSuggestion,
+			Priority:    meta.FixPriority,
+			Edits:       edits,
+			IsPreferred: true,
+		})
+}
+
+// mergeableEnv reports whether an ENV instruction's pairs can be rewritten in
+// key=value form. Values in the legacy "ENV key value" form may contain
+// unquoted whitespace, which key=value would split.
+func mergeableEnv(env *instructions.EnvCommand) bool {
+	if len(env.Location()) == 0 || len(env.Env) == 0 {
+		return false
+	}
+	for _, kv := range env.Env {
+		if kv.NoDelim && strings.ContainsAny(kv.Value, " \t\n") {
+			return false
+		}
+	}
+	return true
+}
+
+// envValueRefs returns the variable names an ENV value expands. ok is false
+// when the value cannot be parsed and its references are unknown.
+func envValueRefs(lex *dfshell.Lex, value string) (refs []string, ok bool) {
+	res, err := lex.ProcessWordWithMatches(value, dfshell.EnvsFromSlice(nil))
+	if err != nil {
+		return nil, false
+	}
+	return slices.Sorted(maps.Keys(res.Unmatched)), true
+}
+
+// instructionText returns the source text of an instruction.
+func instructionText(sm *sourcemap.SourceMap, cmd instructions.Command) string {
+	loc := cmd.Location()
+	if len(loc) == 0 {
+		return ""
+	}
+	return sm.Snippet(loc[0].Start.Line-1, loc[len(loc)-1].End.Line-1)
+}
+
+// mentionsVariable reports whether text contains a $name or ${name...}
+// reference.
+func mentionsVariable(text, name string) bool {
+	for i := strings.IndexByte(text, '$'); i >= 0; {
+		rest := text[i+1:]
+		rest = strings.TrimPrefix(rest, "{")
+		if strings.HasPrefix(rest, name) {
+			after := rest[len(name):]
+			if after == "" || !isVariableNameByte(after[0]) {
+				return true
+			}
+		}
+		next := strings.IndexByte(text[i+1:], '$')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+func isVariableNameByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// joinLineList formats line numbers as "2, 3 and 5".
+func joinLineList(lines []string) string {
+	if len(lines) < 2 {
+		return strings.Join(lines, "")
+	}
+	return strings.Join(lines[:len(lines)-1], ", ") + " and " + lines[len(lines)-1]
+}
+
+func init() {
+	rules.Register(NewEnvLayerConsolidationRule())
+}
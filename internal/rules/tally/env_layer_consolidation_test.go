@@ -0,0 +1,196 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestEnvLayerConsolidationRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewEnvLayerConsolidationRule().Metadata())
+}
+
+func TestEnvLayerConsolidationRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewEnvLayerConsolidationRule(), []testutil.RuleTestCase{
+		{
+			Name: "consecutive ENVs",
+			Content: `FROM alpine:3.20
+ENV A=1
+ENV B=2
+ENV C=3
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"3 ENV instructions can be merged into one"},
+		},
+		{
+			Name: "single ENV",
+			Content: `FROM alpine:3.20
+ENV A=1 B=2
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "RUN between ENVs",
+			Content: `FROM alpine:3.20
+ENV A=1
+RUN make
+ENV B=2
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "ARG between ENVs",
+			Content: `FROM alpine:3.20
+ENV A=1
+ARG VERSION
+ENV B=2
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "later ENV references earlier one",
+			Content: `FROM alpine:3.20
+ENV APP_HOME=/srv/app
+ENV APP_DATA=$APP_HOME/data
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "reference splits the group",
+			Content: `FROM alpine:3.20
+ENV A=1
+ENV B=2
+ENV C=${A}
+ENV D=4
+`,
+			WantViolations: 2,
+			WantMessages:   []string{"2 ENV instructions", "2 ENV instructions"},
+		},
+		{
+			Name: "same key set again",
+			Content: `FROM alpine:3.20
+ENV PATH=/opt/a/bin:$PATH
+ENV PATH=/opt/b/bin:$PATH
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "unrelated instruction between ENVs",
+			Content: `FROM alpine:3.20
+ENV A=1
+WORKDIR /app
+ENV B=2
+`,
+			WantViolations: 1,
+		},
+		{
+			Name: "instruction between uses later variable",
+			Content: `FROM alpine:3.20
+ENV A=1
+WORKDIR $APP_DIR
+ENV APP_DIR=/app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "legacy form with spaces",
+			Content: `FROM alpine:3.20
+ENV A=1
+ENV GREETING hello world
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "separate stages",
+			Content: `FROM alpine:3.20 AS one
+ENV A=1
+
+FROM alpine:3.20
+ENV B=2
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestEnvLayerConsolidationRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "consecutive",
+			content: `FROM alpine:3.20
+ENV A=1
+ENV B="two words" \
+    C=3
+RUN true
+`,
+			want: `FROM alpine:3.20
+ENV A=1 \
+    B="two words" \
+    C=3
+RUN true
+`,
+		},
+		{
+			name: "across unrelated instruction",
+			content: `FROM alpine:3.20
+ENV A=1
+# working directory
+WORKDIR /app
+ENV B=2
+CMD ["app"]
+`,
+			want: `FROM alpine:3.20
+ENV A=1 \
+    B=2
+# working directory
+WORKDIR /app
+CMD ["app"]
+`,
+		},
+		{
+			name: "legacy form without spaces",
+			content: `FROM alpine:3.20
+ENV A 1
+ENV B=2
+`,
+			want: `FROM alpine:3.20
+ENV A=1 \
+    B=2
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewEnvLayerConsolidationRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			fix := violations[0].SuggestedFix
+			if fix == nil {
+				t.Fatal("violation has no SuggestedFix")
+			}
+			if fix.Safety != rules.FixSuggestion {
+				t.Errorf("fix safety = %v, want %v", fix.Safety, rules.FixSuggestion)
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.content), fix)); got != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
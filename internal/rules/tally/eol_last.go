@@ -51,6 +51,7 @@ func (r *EolLastRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     99,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
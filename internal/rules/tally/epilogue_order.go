@@ -44,6 +44,7 @@ func (r *EpilogueOrderRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     175,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -0,0 +1,75 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wharflab/tally/internal/directive"
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// ExpiredSuppressionRuleCode is the full rule code for the expired-suppression rule.
+const ExpiredSuppressionRuleCode = rules.TallyRulePrefix + "expired-suppression"
+
+// ExpiredSuppressionRule reports inline suppression directives whose
+// expires=YYYY-MM-DD date has passed. The inline directive filter stops
+// honoring such directives, so the rules they suppressed report again; this
+// rule points at the stale directive itself.
+type ExpiredSuppressionRule struct {
+	now func() time.Time
+}
+
+// NewExpiredSuppressionRule creates a new expired-suppression rule instance.
+func NewExpiredSuppressionRule() *ExpiredSuppressionRule {
+	return &ExpiredSuppressionRule{now: time.Now}
+}
+
+// Metadata returns the rule metadata.
+func (r *ExpiredSuppressionRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            ExpiredSuppressionRuleCode,
+		Name:            "Expired suppression",
+		Description:     "Inline suppression directive is past its expires= date",
+		DocURL:          rules.TallyDocURL(ExpiredSuppressionRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "maintainability",
+	}
+}
+
+// Check reports every suppression directive that has expired.
+func (r *ExpiredSuppressionRule) Check(input rules.LintInput) []rules.Violation {
+	sm := input.SourceMap()
+	parsed := directive.Parse(sm, nil, directive.NewInstructionSpanIndexFromAST(input.AST, sm))
+
+	meta := r.Metadata()
+	now := r.now()
+
+	var violations []rules.Violation
+	for _, d := range parsed.Directives {
+		if !d.Expired(now) {
+			continue
+		}
+
+		msg := fmt.Sprintf("suppression of %s expired on %s",
+			strings.Join(d.Rules, ", "), d.Expires.Format(time.DateOnly))
+		if d.Owner != "" {
+			msg += " (owner " + d.Owner + ")"
+		}
+		v := rules.NewViolation(
+			rules.NewLineLocation(input.File, d.Line+1),
+			meta.Code,
+			msg,
+			meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL).
+			WithDetail("The directive is no longer applied, so the rules it suppressed are reported again. " +
+				"Fix the underlying issue and remove the directive, or move expires= forward after review. " +
+				"Directive: " + d.RawText)
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+func init() {
+	rules.Register(NewExpiredSuppressionRule())
+}
@@ -0,0 +1,66 @@
+package tally
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestExpiredSuppressionRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewExpiredSuppressionRule().Metadata())
+}
+
+func TestExpiredSuppressionRule_Check(t *testing.T) {
+	t.Parallel()
+
+	rule := NewExpiredSuppressionRule()
+	rule.now = func() time.Time { return time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC) }
+
+	testutil.RunRuleTests(t, rule, []testutil.RuleTestCase{
+		{
+			Name: "expired next-line directive",
+			Content: `FROM ubuntu:22.04
+# tally ignore=DL3008;expires=2026-01-31
+RUN apt-get install -y curl
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"suppression of DL3008 expired on 2026-01-31"},
+		},
+		{
+			Name: "expired directive with owner",
+			Content: `# hadolint global ignore=DL3006,DL3007;expires=2026-03-14;owner=@platform
+FROM ubuntu
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"suppression of DL3006, DL3007 expired on 2026-03-14 (owner @platform)"},
+		},
+		{
+			Name: "directive still applies on its expiry day",
+			Content: `FROM ubuntu:22.04
+# tally disable-next-instruction;expires=2026-03-15
+RUN apt-get install -y curl
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "future expiry",
+			Content: `FROM ubuntu:22.04
+# tally ignore=DL3008;expires=2027-01-01;owner=@platform
+RUN apt-get install -y curl
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "no expiry",
+			Content: `FROM ubuntu:22.04
+# tally ignore=DL3008;reason=pinned upstream
+RUN apt-get install -y curl
+`,
+			WantViolations: 0,
+		},
+	})
+}
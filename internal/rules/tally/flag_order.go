@@ -0,0 +1,188 @@
+package tally
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// FlagOrderRuleCode is the full rule code for the flag-order rule.
+const FlagOrderRuleCode = rules.TallyRulePrefix + "flag-order"
+
+// canonicalFlagOrder lists, per instruction, the order flags are expected in.
+// It follows the order of the Dockerfile reference, so mounts come first on
+// RUN and the source stage first on COPY. Flags not listed here sort after
+// the known ones and keep their relative order.
+var canonicalFlagOrder = map[string][]string{
+	command.Run:         {"mount", "network", "security", "device"},
+	command.Copy:        {"from", "parents", "chown", "chmod", "link", "exclude"},
+	command.Add:         {"keep-git-dir", "checksum", "unpack", "chown", "chmod", "link", "exclude"},
+	command.Healthcheck: {"interval", "timeout", "start-period", "start-interval", "retries"},
+}
+
+// FlagOrderRule reports instruction flags that are out of canonical order.
+type FlagOrderRule struct{}
+
+// NewFlagOrderRule creates a new flag-order rule instance.
+func NewFlagOrderRule() *FlagOrderRule {
+	return &FlagOrderRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *FlagOrderRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            FlagOrderRuleCode,
+		Name:            "Flag Order",
+		Description:     "Instruction flags should follow a consistent order",
+		DocURL:          rules.TallyDocURL(FlagOrderRuleCode),
+		DefaultSeverity: rules.SeverityStyle,
+		Category:        "style",
+		IsExperimental:  false,
+		FixPriority:     10, // Content fix: swap flag tokens before line-splitting transforms
+		Fixes:           []rules.FixSafety{rules.FixSafe},
+	}
+}
+
+// flagSlot is one flag token in the source, with its 1-based line and
+// 0-based column span.
+type flagSlot struct {
+	line       int
+	start, end int
+	text       string
+}
+
+// Check runs the flag-order rule.
+//
+// The fix keeps every flag slot where it is and only swaps the text between
+// slots, so flags spread over continuation lines keep their layout.
+func (r *FlagOrderRule) Check(input rules.LintInput) []rules.Violation {
+	if input.AST == nil || input.AST.AST == nil {
+		return nil
+	}
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	escape := byte(dockerfile.ASTEscapeToken(input.AST))
+
+	var violations []rules.Violation
+	for _, node := range input.AST.AST.Children {
+		order, ok := canonicalFlagOrder[strings.ToLower(node.Value)]
+		if !ok || len(node.Flags) < 2 {
+			continue
+		}
+		slots := scanFlagSlots(sm, node, escape)
+		if len(slots) != len(node.Flags) {
+			// Flags we cannot map back to the source are left alone.
+			continue
+		}
+
+		sorted := slices.Clone(slots)
+		slices.SortStableFunc(sorted, func(a, b flagSlot) int {
+			return flagRank(order, a.text) - flagRank(order, b.text)
+		})
+
+		var edits []rules.TextEdit
+		first := -1
+		for i := range slots {
+			if slots[i].text == sorted[i].text {
+				continue
+			}
+			if first < 0 {
+				first = i
+			}
+			edits = append(edits, rules.TextEdit{
+				Location: rules.NewRangeLocation(input.File, slots[i].line, slots[i].start, slots[i].line, slots[i].end),
+				NewText:  sorted[i].text,
+			})
+		}
+		if first < 0 {
+			continue
+		}
+
+		moved := sorted[first]
+		msg := fmt.Sprintf("%s flag --%s should come before --%s",
+			strings.ToUpper(node.Value), flagName(moved.text), flagName(slots[first].text))
+		loc := rules.NewRangeLocation(input.File, moved.line, moved.start, moved.line, moved.end)
+		violations = append(violations, rules.NewViolation(loc, meta.Code, msg, meta.DefaultSeverity).
+			WithDocURL(meta.DocURL).
+			WithSuggestedFix(&rules.SuggestedFix{
+				Description: "Reorder flags",
+				Safety:      rules.FixSafe,
+				Priority:    meta.FixPriority,
+				Edits:       edits,
+				IsPreferred: true,
+			}))
+	}
+	return violations
+}
+
+// scanFlagSlots finds the flag tokens of node in the source, following line
+// continuations and skipping comment lines between them.
+func scanFlagSlots(sm *sourcemap.SourceMap, node *parser.Node, escape byte) []flagSlot {
+	lineNum := node.StartLine
+	endLine := sm.ResolveEndLineWithEscape(node.EndLine, rune(escape))
+	line := sm.Line(lineNum - 1)
+
+	// Skip the keyword itself.
+	col := len(line) - len(strings.TrimLeft(line, " \t"))
+	for col < len(line) && line[col] != ' ' && line[col] != '\t' {
+		col++
+	}
+
+	var slots []flagSlot
+	for {
+		body := strings.TrimRight(line, " \t")
+		continued := escape != 0 && strings.HasSuffix(body, string(escape))
+		if continued {
+			body = body[:len(body)-1]
+		}
+		for col < len(body) && (body[col] == ' ' || body[col] == '\t') {
+			col++
+		}
+
+		if col >= len(body) {
+			if !continued || lineNum >= endLine {
+				return slots
+			}
+			lineNum++
+			line = sm.Line(lineNum - 1)
+			col = 0
+			if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				// Blank and comment lines keep the continuation going.
+				line = string(rune(escape))
+			}
+			continue
+		}
+		if !strings.HasPrefix(body[col:], "--") {
+			return slots
+		}
+
+		end := shell.SkipDockerfileFlagValue(body, col, false)
+		slots = append(slots, flagSlot{line: lineNum, start: col, end: end, text: body[col:end]})
+		col = end
+	}
+}
+
+func flagName(token string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(token, "--"), "=")
+	return strings.ToLower(name)
+}
+
+func flagRank(order []string, token string) int {
+	if i := slices.Index(order, flagName(token)); i >= 0 {
+		return i
+	}
+	return len(order)
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewFlagOrderRule())
+}
@@ -0,0 +1,117 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestFlagOrderMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewFlagOrderRule().Metadata())
+}
+
+func TestFlagOrderCheck(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewFlagOrderRule(), []testutil.RuleTestCase{
+		{
+			Name:           "ordered RUN flags",
+			Content:        "FROM alpine:3.20\nRUN --mount=type=cache,target=/root/.cache --network=none echo hi\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "single flag",
+			Content:        "FROM alpine:3.20\nCOPY --chown=app . /app\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "repeated mounts keep their order",
+			Content:        "FROM alpine:3.20\nRUN --mount=type=cache,target=/b --mount=type=cache,target=/a echo hi\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "network before mount",
+			Content:        "FROM alpine:3.20\nRUN --network=none --mount=type=cache,target=/root/.cache echo hi\n",
+			WantViolations: 1,
+			WantMessages:   []string{"RUN flag --mount should come before --network"},
+		},
+		{
+			Name:           "COPY from after chown",
+			Content:        "FROM alpine:3.20 AS build\nFROM alpine:3.20\nCOPY --chown=app --from=build /out /app\n",
+			WantViolations: 1,
+			WantMessages:   []string{"COPY flag --from should come before --chown"},
+		},
+		{
+			Name:           "unknown flags sort last",
+			Content:        "FROM alpine:3.20\nRUN --frobnicate --mount=type=tmpfs,target=/tmp echo hi\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "instruction without canonical order",
+			Content:        "FROM --platform=linux/amd64 alpine:3.20\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestFlagOrderFix(t *testing.T) {
+	t.Parallel()
+	r := NewFlagOrderRule()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "single line",
+			content: "FROM alpine:3.20\nRUN --network=none --mount=type=cache,target=/root/.cache echo hi\n",
+			want:    "FROM alpine:3.20\nRUN --mount=type=cache,target=/root/.cache --network=none echo hi\n",
+		},
+		{
+			name: "flags on continuation lines keep their layout",
+			content: "FROM alpine:3.20\nRUN --security=insecure \\\n" +
+				"    # cache\n" +
+				"    --mount=type=cache,target=/var/cache/apk \\\n" +
+				"    --network=host \\\n" +
+				"    apk add curl\n",
+			want: "FROM alpine:3.20\nRUN --mount=type=cache,target=/var/cache/apk \\\n" +
+				"    # cache\n" +
+				"    --network=host \\\n" +
+				"    --security=insecure \\\n" +
+				"    apk add curl\n",
+		},
+		{
+			name:    "quoted flag values",
+			content: "FROM alpine:3.20\nCOPY --chmod=755 --chown=\"app user\" --from=build /out /app\n",
+			want:    "FROM alpine:3.20\nCOPY --from=build --chown=\"app user\" --chmod=755 /out /app\n",
+		},
+		{
+			name:    "healthcheck options",
+			content: "FROM alpine:3.20\nHEALTHCHECK --retries=3 --interval=30s CMD true\n",
+			want:    "FROM alpine:3.20\nHEALTHCHECK --interval=30s --retries=3 CMD true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithConfig(t, "Dockerfile", tt.content, nil)
+			violations := r.Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			got := fixpkg.ApplyFix([]byte(tt.content), violations[0].SuggestedFix)
+			if string(got) != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+			if again := r.Check(testutil.MakeLintInputWithConfig(t, "Dockerfile", string(got), nil)); len(again) != 0 {
+				t.Errorf("fixed content still has %d violations", len(again))
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package tally
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
+	"github.com/wharflab/tally/internal/runmount"
+)
+
+// FromAliasRequiredRuleCode is the full rule code for the from-alias-required rule.
+const FromAliasRequiredRuleCode = rules.TallyRulePrefix + "from-alias-required"
+
+// FromAliasRequiredRule flags stages of multi-stage Dockerfiles that are
+// referenced by index, as in COPY --from=0 or RUN --mount=from=0. Index
+// references silently point at another stage once stages are inserted or
+// reordered; names keep working.
+type FromAliasRequiredRule struct{}
+
+// NewFromAliasRequiredRule creates a new from-alias-required rule instance.
+func NewFromAliasRequiredRule() *FromAliasRequiredRule {
+	return &FromAliasRequiredRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *FromAliasRequiredRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            FromAliasRequiredRuleCode,
+		Name:            "FROM Alias Required",
+		Description:     "Stages referenced from other stages should be named and referenced by name, not by index",
+		DocURL:          rules.TallyDocURL(FromAliasRequiredRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "maintainability",
+		Fixes:           []rules.FixSafety{rules.FixSafe},
+	}
+}
+
+// indexStageRef is a reference to a stage by its numeric index.
+type indexStageRef struct {
+	// Instruction is the referencing flag as written, for messages.
+	Instruction string
+	From        string
+	Line        int
+	// IsMount is true for RUN --mount references. Mount is the flag
+	// holding the reference, when it could be located in the source.
+	IsMount bool
+	Mount   *runmount.MountFlag
+}
+
+// Check reports each stage that is referenced by index, once, at its FROM
+// instruction. The fix names unnamed stages with a name derived from the base
+// image and rewrites every index reference to the stage name.
+func (r *FromAliasRequiredRule) Check(input rules.LintInput) []rules.Violation {
+	sem := input.Semantic
+	if sem == nil || len(input.Stages) < 2 {
+		return nil
+	}
+
+	refs := collectIndexStageRefs(input)
+	if len(refs) == 0 {
+		return nil
+	}
+	names := newStageAliases(input)
+
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for idx := range input.Stages {
+		stageRefs := refs[idx]
+		if len(stageRefs) == 0 {
+			continue
+		}
+		stage := &input.Stages[idx]
+		first := stageRefs[0]
+
+		var msg string
+		if stage.Name == "" {
+			msg = fmt.Sprintf("Stage %d has no name but is referenced by index (%s on line %d)",
+				idx, first.Instruction, first.Line)
+		} else {
+			msg = fmt.Sprintf("Stage %q is referenced by index (%s on line %d) instead of by name",
+				stage.Name, first.Instruction, first.Line)
+		}
+		if n := len(stageRefs) - 1; n > 0 {
+			msg += fmt.Sprintf(" and %d more time", n)
+			if n > 1 {
+				msg += "s"
+			}
+		}
+
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(input.File, stage.Location),
+			meta.Code,
+			msg,
+			meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL).WithDetail(
+			"Index references point at whatever stage ends up at that position, so inserting or " +
+				"reordering stages silently changes what gets copied. Name the stage with FROM ... AS <name> " +
+				"and reference it by name.",
+		)
+		v.StageIndex = idx
+
+		name := stage.Name
+		if name == "" {
+			name = names.generate(idx)
+		}
+		if f := fromAliasFix(input, idx, name, stageRefs); f != nil {
+			v = v.WithSuggestedFix(f)
+		}
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// collectIndexStageRefs returns the index references to each stage, in
+// source order. ONBUILD references are skipped: they resolve in the child
+// image's build, where the index means something else.
+func collectIndexStageRefs(input rules.LintInput) map[int][]indexStageRef {
+	sem := input.Semantic
+	sm := input.SourceMap()
+	escape := rune('\\')
+	if input.AST != nil {
+		escape = input.AST.EscapeToken
+	}
+
+	refs := make(map[int][]indexStageRef)
+	for i, stage := range input.Stages {
+		if info := sem.StageInfo(i); info != nil {
+			for _, ref := range info.CopyFromRefs {
+				if !ref.IsStageRef || !isStageIndex(ref.From) || len(ref.Location) == 0 {
+					continue
+				}
+				refs[ref.StageIndex] = append(refs[ref.StageIndex], indexStageRef{
+					Instruction: "COPY --from=" + ref.From,
+					From:        ref.From,
+					Line:        ref.Location[0].Start.Line,
+				})
+			}
+		}
+
+		for _, cmd := range stage.Commands {
+			run, ok := cmd.(*instructions.RunCommand)
+			if !ok {
+				continue
+			}
+			flags := runmount.MountFlags(run.Location(), sm, escape)
+			used := make([]bool, len(flags))
+			for _, m := range runmount.GetMounts(run) {
+				idx, ok := stageIndexRef(m.From, i)
+				if !ok {
+					continue
+				}
+				ref := indexStageRef{
+					Instruction: "RUN --mount from=" + m.From,
+					From:        m.From,
+					Line:        run.Location()[0].Start.Line,
+					IsMount:     true,
+				}
+				for fi := range flags {
+					if !used[fi] && mountFromValue(flags[fi]) == m.From {
+						used[fi] = true
+						ref.Mount = &flags[fi]
+						ref.Line = flags[fi].Line
+						break
+					}
+				}
+				refs[idx] = append(refs[idx], ref)
+			}
+		}
+	}
+	return refs
+}
+
+// isStageIndex reports whether from is a numeric stage reference.
+func isStageIndex(from string) bool {
+	_, err := strconv.Atoi(from)
+	return err == nil
+}
+
+// stageIndexRef resolves a numeric --mount from= value in stage stageIdx the
+// way the semantic model does: only earlier stages can be referenced.
+func stageIndexRef(from string, stageIdx int) (int, bool) {
+	idx, err := strconv.Atoi(from)
+	if err != nil || idx < 0 || idx >= stageIdx {
+		return 0, false
+	}
+	return idx, true
+}
+
+// mountFromValue returns the from= option of a mount flag, or "".
+func mountFromValue(flag runmount.MountFlag) string {
+	opts, err := flag.Options()
+	if err != nil {
+		return ""
+	}
+	for _, opt := range opts {
+		if opt.Key == "from" {
+			return opt.Value
+		}
+	}
+	return ""
+}
+
+// fromAliasFix names stage idx (when it has no name yet) and rewrites its
+// index references to name. It returns nil unless every reference can be
+// rewritten, since a partial fix would mix names and indexes.
+func fromAliasFix(input rules.LintInput, idx int, name string, refs []indexStageRef) *rules.SuggestedFix {
+	if name == "" {
+		return nil
+	}
+	stage := &input.Stages[idx]
+
+	var edits []rules.TextEdit
+	if stage.Name == "" {
+		edit := fromAliasEdit(input, idx, name)
+		if edit == nil {
+			return nil
+		}
+		edits = append(edits, *edit)
+	}
+
+	// Renaming "<index>" to the name rewrites exactly the COPY --from
+	// references spelled as the index; the stage definition and references
+	// by name are left alone.
+	copyRefs := 0
+	for _, ref := range refs {
+		if !ref.IsMount {
+			copyRefs++
+		}
+	}
+	if copyRefs > 0 {
+		copyEdits := fixes.StageRenameEdits(input.Semantic, idx, strconv.Itoa(idx), name, input.File, input.Source)
+		if len(copyEdits) != copyRefs {
+			return nil
+		}
+		edits = append(edits, copyEdits...)
+	}
+
+	for _, ref := range refs {
+		if !ref.IsMount {
+			continue
+		}
+		edit := mountFromEdit(input.File, ref, name)
+		if edit == nil {
+			return nil
+		}
+		edits = append(edits, *edit)
+	}
+
+	desc := fmt.Sprintf("Reference stage %d as '%s'", idx, name)
+	if stage.Name == "" {
+		desc = fmt.Sprintf("Name stage %d '%s' and reference it by name", idx, name)
+	}
+	return &rules.SuggestedFix{
+		Description: desc,
+		Safety:      rules.FixSafe,
+		Edits:       edits,
+		IsPreferred: true,
+	}
+}
+
+// fromAliasEdit inserts " AS <name>" after the base image of stage idx,
+// matching the case of the FROM keyword.
+func fromAliasEdit(input rules.LintInput, idx int, name string) *rules.TextEdit {
+	info := input.Semantic.StageInfo(idx)
+	stage := &input.Stages[idx]
+	if info == nil || info.BaseImage == nil || len(stage.Location) == 0 {
+		return nil
+	}
+
+	lineNum := stage.Location[0].Start.Line
+	if lineNum < 1 || lineNum > input.SourceMap().LineCount() {
+		return nil
+	}
+	it := fixes.ParseInstruction([]byte(input.SourceMap().Line(lineNum - 1)))
+	from := it.FindKeyword(command.From)
+	args := it.Arguments()
+	if from == nil || len(args) != 1 || args[0].Value != info.BaseImage.Raw {
+		return nil
+	}
+
+	as := " AS "
+	if from.Value == strings.ToLower(from.Value) {
+		as = " as "
+	}
+	return &rules.TextEdit{
+		Location: rules.NewRangeLocation(input.File, lineNum, args[0].End, lineNum, args[0].End),
+		NewText:  as + name,
+	}
+}
+
+// mountFromEdit replaces the from= value of a RUN --mount flag. Flags with
+// quoted options are left alone.
+func mountFromEdit(file string, ref indexStageRef, name string) *rules.TextEdit {
+	if ref.Mount == nil || strings.ContainsAny(ref.Mount.Value, `"'`) {
+		return nil
+	}
+	col := ref.Mount.StartCol + len("--mount=")
+	for field := range strings.SplitSeq(ref.Mount.Value, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if ok && strings.EqualFold(key, "from") && value == ref.From {
+			start := col + len(key) + 1
+			return &rules.TextEdit{
+				Location: rules.NewRangeLocation(file, ref.Mount.Line, start, ref.Mount.Line, start+len(value)),
+				NewText:  name,
+			}
+		}
+		col += len(field) + 1
+	}
+	return nil
+}
+
+// stageAliases generates stage names that cannot be mistaken for anything
+// else the Dockerfile refers to.
+type stageAliases struct {
+	input rules.LintInput
+	taken map[string]bool
+}
+
+// newStageAliases reserves every stage name and every image or stage
+// reference in the Dockerfile: a new stage named after a referenced image
+// would shadow it.
+func newStageAliases(input rules.LintInput) *stageAliases {
+	taken := map[string]bool{"scratch": true, "context": true}
+	for i, stage := range input.Stages {
+		taken[strings.ToLower(stage.Name)] = true
+		taken[strings.ToLower(stage.BaseName)] = true
+		if info := input.Semantic.StageInfo(i); info != nil {
+			for _, ref := range info.CopyFromRefs {
+				taken[strings.ToLower(ref.From)] = true
+			}
+			for _, ref := range info.OnbuildCopyFromRefs {
+				taken[strings.ToLower(ref.From)] = true
+			}
+		}
+		for _, cmd := range stage.Commands {
+			if run, ok := cmd.(*instructions.RunCommand); ok {
+				for _, m := range runmount.GetMounts(run) {
+					taken[strings.ToLower(m.From)] = true
+				}
+			}
+		}
+	}
+	return &stageAliases{input: input, taken: taken}
+}
+
+// generate returns a free name for stage idx, based on the repository name of
+// its base image ("golang" for golang:1.25) and falling back to "stage-<idx>".
+func (a *stageAliases) generate(idx int) string {
+	base := fmt.Sprintf("stage-%d", idx)
+	if info := a.input.Semantic.StageInfo(idx); info != nil && info.BaseImage != nil && !info.BaseImage.IsStageRef {
+		if name := imageAliasBase(info.BaseImage.Raw); name != "" {
+			base = name
+		}
+	}
+
+	name := base
+	for n := 2; a.taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	a.taken[name] = true
+	return name
+}
+
+// imageAliasBase turns an image reference into a kebab-case stage name: the
+// last path component without tag or digest. It returns "" when nothing
+// usable is left, such as for images chosen through ARGs.
+func imageAliasBase(image string) string {
+	if strings.Contains(image, "$") {
+		return ""
+	}
+	image, _, _ = strings.Cut(image, "@")
+	image = image[strings.LastIndex(image, "/")+1:]
+	image, _, _ = strings.Cut(image, ":")
+
+	var sb strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(image) {
+		switch {
+		case c >= 'a' && c <= 'z' || c >= '0' && c <= '9':
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(c)
+		default:
+			dash = true
+		}
+	}
+	name := sb.String()
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		return ""
+	}
+	return name
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewFromAliasRequiredRule())
+}
@@ -0,0 +1,186 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestFromAliasRequiredRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewFromAliasRequiredRule().Metadata())
+}
+
+func TestFromAliasRequiredRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewFromAliasRequiredRule(), []testutil.RuleTestCase{
+		{
+			Name:           "unnamed stage referenced by index",
+			Content:        "FROM golang:1.25\nRUN go build -o /app .\n\nFROM alpine\nCOPY --from=0 /app /app\n",
+			WantViolations: 1,
+			WantCodes:      []string{FromAliasRequiredRuleCode},
+			WantMessages:   []string{"Stage 0 has no name but is referenced by index (COPY --from=0 on line 5)"},
+		},
+		{
+			Name:           "named stage referenced by index",
+			Content:        "FROM golang:1.25 AS build\nFROM alpine\nCOPY --from=0 /app /app\nCOPY --from=0 /etc/ssl /etc/ssl\n",
+			WantViolations: 1,
+			WantMessages:   []string{`Stage "build" is referenced by index (COPY --from=0 on line 3) instead of by name and 1 more time`},
+		},
+		{
+			Name:           "RUN --mount from index",
+			Content:        "FROM golang:1.25\nFROM alpine\nRUN --mount=type=bind,from=0,target=/src ls /src\n",
+			WantViolations: 1,
+			WantMessages:   []string{"(RUN --mount from=0 on line 3)"},
+		},
+		{
+			Name:           "references by name",
+			Content:        "FROM golang:1.25 AS build\nFROM alpine\nCOPY --from=build /app /app\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "unnamed stages without index references",
+			Content:        "FROM golang:1.25\nFROM alpine\nCOPY --from=nginx:1.27 /etc/nginx /etc/nginx\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "single stage",
+			Content:        "FROM alpine\nRUN echo hi\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "ONBUILD references resolve in the child build",
+			Content:        "FROM golang:1.25\nFROM alpine\nONBUILD COPY --from=0 /app /app\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestFromAliasRequiredRule_Fix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "names the stage after its base image",
+			content: `FROM docker.io/library/golang:1.25@sha256:abc
+RUN go build -o /app .
+
+FROM alpine
+COPY --from=0 /app /app
+RUN --mount=type=bind,from=0,source=/go,target=/go ls /go
+`,
+			want: []string{`FROM docker.io/library/golang:1.25@sha256:abc AS golang
+RUN go build -o /app .
+
+FROM alpine
+COPY --from=golang /app /app
+RUN --mount=type=bind,from=golang,source=/go,target=/go ls /go
+`},
+		},
+		{
+			name: "keeps existing names and lowercase keywords",
+			content: `from node:22 as deps
+from node:22
+copy --from=0 /app/node_modules ./node_modules
+`,
+			want: []string{`from node:22 as deps
+from node:22
+copy --from=deps /app/node_modules ./node_modules
+`},
+		},
+		{
+			name: "generated names do not collide",
+			content: `FROM golang:1.25
+FROM golang:1.25
+FROM golang
+COPY --from=0 /a /a
+COPY --from=1 /b /b
+`,
+			want: []string{
+				`FROM golang:1.25 AS golang-2
+FROM golang:1.25
+FROM golang
+COPY --from=golang-2 /a /a
+COPY --from=1 /b /b
+`,
+				`FROM golang:1.25
+FROM golang:1.25 AS golang-3
+FROM golang
+COPY --from=0 /a /a
+COPY --from=golang-3 /b /b
+`,
+			},
+		},
+		{
+			name: "falls back to the stage index",
+			content: `ARG BASE=alpine
+FROM $BASE
+FROM scratch
+COPY --from=0 / /
+`,
+			want: []string{`ARG BASE=alpine
+FROM $BASE AS stage-0
+FROM scratch
+COPY --from=stage-0 / /
+`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			violations := NewFromAliasRequiredRule().Check(testutil.MakeLintInput(t, "Dockerfile", tt.content))
+			if len(violations) != len(tt.want) {
+				t.Fatalf("got %d violations, want %d", len(violations), len(tt.want))
+			}
+			for i, v := range violations {
+				if v.SuggestedFix == nil {
+					t.Fatalf("violation %d: expected a suggested fix", i)
+				}
+				if got := string(fix.ApplyFix([]byte(tt.content), v.PreferredFix())); got != tt.want[i] {
+					t.Errorf("violation %d: fixed content =\n%s\nwant\n%s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFromAliasRequiredRule_NoFix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "FROM continues on the next line",
+			content: "FROM \\\n  golang:1.25\nFROM alpine\nCOPY --from=0 /app /app\n",
+		},
+		{
+			name:    "quoted mount options",
+			content: "FROM golang:1.25\nFROM alpine\nRUN --mount=type=bind,from=0,\"target=/src\" ls /src\n",
+		},
+		{
+			name:    "COPY --from on a continuation line",
+			content: "FROM golang:1.25\nFROM alpine\nCOPY --chown=1000 \\\n  --from=0 /app /app\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			violations := NewFromAliasRequiredRule().Check(testutil.MakeLintInput(t, "Dockerfile", tt.content))
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			if f := violations[0].SuggestedFix; f != nil {
+				t.Errorf("unexpected fix %q", f.Description)
+			}
+		})
+	}
+}
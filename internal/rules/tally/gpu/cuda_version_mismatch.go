@@ -38,6 +38,7 @@ func (r *CUDAVersionMismatchRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     8,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
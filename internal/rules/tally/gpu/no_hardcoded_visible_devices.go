@@ -51,6 +51,7 @@ func (r *NoHardcodedVisibleDevicesRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     8,
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
@@ -36,6 +36,7 @@ func (r *PreferMinimalDriverCapabilitiesRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "correctness",
 		FixPriority:     8,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
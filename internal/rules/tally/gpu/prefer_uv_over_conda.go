@@ -93,6 +93,7 @@ func (r *PreferUVOverCondaRule) Metadata() rules.RuleMetadata {
 		Category:        "best-practices",
 		IsExperimental:  true,
 		FixPriority:     150,
+		Fixes:           []rules.FixSafety{rules.FixUnsafe},
 	}
 }
 
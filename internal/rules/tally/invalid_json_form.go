@@ -64,6 +64,8 @@ func (r *InvalidJSONFormRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityError,
 		Category:        "correctness",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		RelatedTo:       []string{rules.BuildKitRulePrefix + "JSONArgsRecommended"},
 	}
 }
 
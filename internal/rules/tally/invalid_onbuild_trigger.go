@@ -45,6 +45,7 @@ func (r *InvalidOnbuildTriggerRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityError,
 		Category:        "correctness",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -94,6 +94,7 @@ func (r *NodeGypCacheMountsRule) Metadata() rules.RuleMetadata {
 		Category:        "performance",
 		IsExperimental:  false,
 		FixPriority:     91, // After package cache mounts (90), before structural rewrites.
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -64,6 +64,7 @@ func (r *NoBuildxGitOverlapRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
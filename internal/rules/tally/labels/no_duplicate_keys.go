@@ -31,6 +31,7 @@ func (r *NoDuplicateKeysRule) Metadata() rules.RuleMetadata {
 		Category:        "correctness",
 		IsExperimental:  false,
 		FixPriority:     -1,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -60,6 +60,7 @@ func (r *PreferGroupedRule) Metadata() rules.RuleMetadata {
 		// output uses the same multi-line shape that the splitter emits, so
 		// the splitter's idempotent guard skips it on the same fix run.
 		FixPriority: 96,
+		Fixes:       []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -71,6 +71,7 @@ func (r *PreferStableOrderRule) Metadata() rules.RuleMetadata {
 		// LABEL instruction and don't overlap structural rewrites that operate
 		// at instruction boundaries.
 		FixPriority: 95,
+		Fixes:       []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -0,0 +1,108 @@
+package tally
+
+import (
+	"fmt"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// MaxCommandsPerRunRuleCode is the full rule code for the max-commands-per-run rule.
+const MaxCommandsPerRunRuleCode = rules.TallyRulePrefix + "max-commands-per-run"
+
+// MaxCommandsPerRunConfig is the configuration for the max-commands-per-run rule.
+type MaxCommandsPerRunConfig struct {
+	// Max is the maximum number of commands in one RUN (0 = disabled, nil = use default).
+	Max *int `json:"max,omitempty"`
+}
+
+// DefaultMaxCommandsPerRunConfig returns the default configuration.
+func DefaultMaxCommandsPerRunConfig() MaxCommandsPerRunConfig {
+	return MaxCommandsPerRunConfig{Max: new(15)}
+}
+
+// MaxCommandsPerRunRule limits the number of commands a single RUN executes.
+//
+// Commands are counted the same way whatever the RUN's form: every command of
+// an && chain and every top-level statement counts once, so a heredoc script
+// and the equivalent "a && b && c" chain have the same count. Pipelines, ||
+// chains and compound commands (if, for, ...) count as one. Exec-form RUNs
+// are a single command. Off by default; configuring max enables it.
+type MaxCommandsPerRunRule struct {
+	schema map[string]any
+}
+
+// NewMaxCommandsPerRunRule creates a new max-commands-per-run rule instance.
+func NewMaxCommandsPerRunRule() *MaxCommandsPerRunRule {
+	schema, err := configutil.RuleSchema(MaxCommandsPerRunRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &MaxCommandsPerRunRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *MaxCommandsPerRunRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            MaxCommandsPerRunRuleCode,
+		Name:            "Maximum Commands per RUN",
+		Description:     "Limits the number of chained commands in a single RUN instruction",
+		DocURL:          rules.TallyDocURL(MaxCommandsPerRunRuleCode),
+		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when max is configured
+		Category:        "maintainability",
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *MaxCommandsPerRunRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *MaxCommandsPerRunRule) DefaultConfig() any {
+	return DefaultMaxCommandsPerRunConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *MaxCommandsPerRunRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(MaxCommandsPerRunRuleCode, config)
+}
+
+// Check reports RUN instructions with more commands than allowed.
+func (r *MaxCommandsPerRunRule) Check(input rules.LintInput) []rules.Violation {
+	cfg := configutil.Coerce(input.Config, DefaultMaxCommandsPerRunConfig())
+	if cfg.Max == nil || *cfg.Max <= 0 || input.Facts == nil {
+		return nil
+	}
+	maxCommands := *cfg.Max
+
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for _, run := range input.Facts.Runs() {
+		if !run.UsesShell || len(run.Run.Location()) == 0 {
+			continue
+		}
+		count := shell.CountChainedCommands(run.CommandScript, run.Shell.Variant)
+		if count <= maxCommands {
+			continue
+		}
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(input.File, run.Run.Location()),
+			meta.Code,
+			fmt.Sprintf("RUN has %d commands, maximum allowed is %d", count, maxCommands),
+			rules.SeverityWarning,
+		).WithDocURL(meta.DocURL).WithDetail(
+			"A long RUN is one cache layer: any change reruns all of it, and a failure deep in the chain is hard " +
+				"to locate. Split it at natural boundaries, or move the script into a file you COPY and run.",
+		)
+		v.StageIndex = run.StageIndex
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewMaxCommandsPerRunRule())
+}
@@ -0,0 +1,64 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestMaxCommandsPerRunRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewMaxCommandsPerRunRule().Metadata())
+}
+
+func TestMaxCommandsPerRunRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewMaxCommandsPerRunRule(), []testutil.RuleTestCase{
+		{
+			Name:           "chain at limit",
+			Content:        "FROM alpine\nRUN a && b && c\n",
+			Config:         MaxCommandsPerRunConfig{Max: new(3)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "chain over limit",
+			Content:        "FROM alpine\nRUN apk add curl && \\\n    curl -o /x https://example.com/x && \\\n    chmod +x /x\n",
+			Config:         MaxCommandsPerRunConfig{Max: new(2)},
+			WantViolations: 1,
+			WantCodes:      []string{MaxCommandsPerRunRuleCode},
+			WantMessages:   []string{"RUN has 3 commands, maximum allowed is 2"},
+		},
+		{
+			Name:           "heredoc counts like the equivalent chain",
+			Content:        "FROM alpine\nRUN <<EOF\napk add curl\ncurl -o /x https://example.com/x\nchmod +x /x\nEOF\n",
+			Config:         MaxCommandsPerRunConfig{Max: new(2)},
+			WantViolations: 1,
+			WantMessages:   []string{"RUN has 3 commands, maximum allowed is 2"},
+		},
+		{
+			Name:           "pipelines and || chains count once",
+			Content:        "FROM alpine\nRUN curl -s https://example.com | sh && (test -f /x || touch /x)\n",
+			Config:         MaxCommandsPerRunConfig{Max: new(2)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "exec form is a single command",
+			Content:        "FROM alpine\nRUN [\"sh\", \"-c\", \"a && b && c\"]\n",
+			Config:         MaxCommandsPerRunConfig{Max: new(1)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "disabled when max is 0",
+			Content:        "FROM alpine\nRUN a && b && c\n",
+			Config:         MaxCommandsPerRunConfig{Max: new(0)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "nil config uses defaults",
+			Content:        "FROM alpine\nRUN a && b && c\n",
+			WantViolations: 0,
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package tally
+
+import (
+	"fmt"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+)
+
+// MaxInstructionsPerStageRuleCode is the full rule code for the
+// max-instructions-per-stage rule.
+const MaxInstructionsPerStageRuleCode = rules.TallyRulePrefix + "max-instructions-per-stage"
+
+// MaxInstructionsPerStageConfig is the configuration for the
+// max-instructions-per-stage rule.
+type MaxInstructionsPerStageConfig struct {
+	// Max is the maximum number of instructions per stage, not counting FROM
+	// (0 = disabled, nil = use default).
+	Max *int `json:"max,omitempty"`
+}
+
+// DefaultMaxInstructionsPerStageConfig returns the default configuration.
+func DefaultMaxInstructionsPerStageConfig() MaxInstructionsPerStageConfig {
+	return MaxInstructionsPerStageConfig{Max: new(40)}
+}
+
+// MaxInstructionsPerStageRule limits the number of instructions in a single
+// build stage. Off by default; configuring max enables it.
+type MaxInstructionsPerStageRule struct {
+	schema map[string]any
+}
+
+// NewMaxInstructionsPerStageRule creates a new max-instructions-per-stage rule instance.
+func NewMaxInstructionsPerStageRule() *MaxInstructionsPerStageRule {
+	schema, err := configutil.RuleSchema(MaxInstructionsPerStageRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &MaxInstructionsPerStageRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *MaxInstructionsPerStageRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            MaxInstructionsPerStageRuleCode,
+		Name:            "Maximum Instructions per Stage",
+		Description:     "Limits the number of instructions in a single build stage",
+		DocURL:          rules.TallyDocURL(MaxInstructionsPerStageRuleCode),
+		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when max is configured
+		Category:        "maintainability",
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *MaxInstructionsPerStageRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *MaxInstructionsPerStageRule) DefaultConfig() any {
+	return DefaultMaxInstructionsPerStageConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *MaxInstructionsPerStageRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(MaxInstructionsPerStageRuleCode, config)
+}
+
+// Check reports, for each stage over the limit, the first instruction past it.
+func (r *MaxInstructionsPerStageRule) Check(input rules.LintInput) []rules.Violation {
+	cfg := configutil.Coerce(input.Config, DefaultMaxInstructionsPerStageConfig())
+	if cfg.Max == nil || *cfg.Max <= 0 {
+		return nil
+	}
+	maxInstructions := *cfg.Max
+
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		if len(stage.Commands) <= maxInstructions {
+			continue
+		}
+		name := fmt.Sprintf("stage %d", stageIdx)
+		if stage.Name != "" {
+			name = fmt.Sprintf("stage %q", stage.Name)
+		}
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(input.File, stage.Commands[maxInstructions].Location()),
+			meta.Code,
+			fmt.Sprintf("%s has %d instructions, maximum allowed is %d", name, len(stage.Commands), maxInstructions),
+			rules.SeverityWarning,
+		).WithDocURL(meta.DocURL).WithDetail(
+			"Long stages are hard to review and rebuild from the first changed layer on. " +
+				"Split the stage into a builder and a runtime stage, or move setup that rarely changes into a base image.",
+		)
+		v.StageIndex = stageIdx
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewMaxInstructionsPerStageRule())
+}
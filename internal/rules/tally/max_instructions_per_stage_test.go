@@ -0,0 +1,81 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestMaxInstructionsPerStageRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewMaxInstructionsPerStageRule().Metadata())
+}
+
+func TestMaxInstructionsPerStageRule_Check(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine AS build
+WORKDIR /src
+COPY . .
+RUN make
+
+FROM alpine
+COPY --from=build /src/app /app
+USER nobody
+`
+	testutil.RunRuleTests(t, NewMaxInstructionsPerStageRule(), []testutil.RuleTestCase{
+		{
+			Name:           "at limit",
+			Content:        content,
+			Config:         MaxInstructionsPerStageConfig{Max: new(3)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "named stage over limit",
+			Content:        content,
+			Config:         MaxInstructionsPerStageConfig{Max: new(2)},
+			WantViolations: 1,
+			WantCodes:      []string{MaxInstructionsPerStageRuleCode},
+			WantMessages:   []string{`stage "build" has 3 instructions, maximum allowed is 2`},
+		},
+		{
+			Name:           "every stage over limit is reported",
+			Content:        content,
+			Config:         MaxInstructionsPerStageConfig{Max: new(1)},
+			WantViolations: 2,
+			WantMessages:   []string{`stage "build" has 3 instructions`, "stage 1 has 2 instructions"},
+		},
+		{
+			Name:           "disabled when max is 0",
+			Content:        content,
+			Config:         MaxInstructionsPerStageConfig{Max: new(0)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "unnamed stage",
+			Content:        "FROM alpine\nRUN echo a\nRUN echo b\n",
+			Config:         MaxInstructionsPerStageConfig{Max: new(1)},
+			WantViolations: 1,
+			WantMessages:   []string{"stage 0 has 2 instructions, maximum allowed is 1"},
+		},
+		{
+			Name:           "nil config uses defaults",
+			Content:        content,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestMaxInstructionsPerStageRule_ReportsFirstInstructionOverLimit(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine\nRUN echo a\nRUN echo b\nRUN echo c\n"
+	input := testutil.MakeLintInputWithConfig(t, "Dockerfile", content, MaxInstructionsPerStageConfig{Max: new(2)})
+	violations := NewMaxInstructionsPerStageRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	if got := violations[0].Location.Start.Line; got != 4 {
+		t.Errorf("violation line = %d, want 4", got)
+	}
+}
@@ -0,0 +1,92 @@
+package tally
+
+import (
+	"fmt"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+)
+
+// MaxStageCountRuleCode is the full rule code for the max-stage-count rule.
+const MaxStageCountRuleCode = rules.TallyRulePrefix + "max-stage-count"
+
+// MaxStageCountConfig is the configuration for the max-stage-count rule.
+type MaxStageCountConfig struct {
+	// Max is the maximum number of build stages allowed (0 = disabled, nil = use default).
+	Max *int `json:"max,omitempty"`
+}
+
+// DefaultMaxStageCountConfig returns the default configuration.
+func DefaultMaxStageCountConfig() MaxStageCountConfig {
+	return MaxStageCountConfig{Max: new(10)}
+}
+
+// MaxStageCountRule limits the number of build stages in a Dockerfile.
+// It complements max-lines: a short Dockerfile can still fan out into more
+// stages than a reader can follow. Off by default; configuring max enables it.
+type MaxStageCountRule struct {
+	schema map[string]any
+}
+
+// NewMaxStageCountRule creates a new max-stage-count rule instance.
+func NewMaxStageCountRule() *MaxStageCountRule {
+	schema, err := configutil.RuleSchema(MaxStageCountRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &MaxStageCountRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *MaxStageCountRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            MaxStageCountRuleCode,
+		Name:            "Maximum Stage Count",
+		Description:     "Limits the number of build stages in a Dockerfile",
+		DocURL:          rules.TallyDocURL(MaxStageCountRuleCode),
+		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when max is configured
+		Category:        "maintainability",
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *MaxStageCountRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *MaxStageCountRule) DefaultConfig() any {
+	return DefaultMaxStageCountConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *MaxStageCountRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(MaxStageCountRuleCode, config)
+}
+
+// Check reports the first stage over the limit.
+func (r *MaxStageCountRule) Check(input rules.LintInput) []rules.Violation {
+	cfg := configutil.Coerce(input.Config, DefaultMaxStageCountConfig())
+	if cfg.Max == nil || *cfg.Max <= 0 || len(input.Stages) <= *cfg.Max {
+		return nil
+	}
+	maxStages := *cfg.Max
+
+	meta := r.Metadata()
+	v := rules.NewViolation(
+		rules.NewLocationFromRanges(input.File, input.Stages[maxStages].Location),
+		meta.Code,
+		fmt.Sprintf("Dockerfile has %d stages, maximum allowed is %d", len(input.Stages), maxStages),
+		rules.SeverityWarning,
+	).WithDocURL(meta.DocURL).WithDetail(
+		"Many stages make the build graph hard to follow. Merge stages that only exist to run a step or two, " +
+			"or move shared toolchains into a base image.",
+	)
+	v.StageIndex = maxStages
+	return []rules.Violation{v}
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewMaxStageCountRule())
+}
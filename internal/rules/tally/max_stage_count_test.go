@@ -0,0 +1,62 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestMaxStageCountRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewMaxStageCountRule().Metadata())
+}
+
+func TestMaxStageCountRule_Check(t *testing.T) {
+	t.Parallel()
+	threeStages := "FROM alpine AS a\nRUN echo a\nFROM alpine AS b\nRUN echo b\nFROM alpine AS c\nRUN echo c\n"
+	testutil.RunRuleTests(t, NewMaxStageCountRule(), []testutil.RuleTestCase{
+		{
+			Name:           "at limit",
+			Content:        threeStages,
+			Config:         MaxStageCountConfig{Max: new(3)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "over limit",
+			Content:        threeStages,
+			Config:         MaxStageCountConfig{Max: new(2)},
+			WantViolations: 1,
+			WantCodes:      []string{MaxStageCountRuleCode},
+			WantMessages:   []string{"Dockerfile has 3 stages, maximum allowed is 2"},
+		},
+		{
+			Name:           "disabled when max is 0",
+			Content:        threeStages,
+			Config:         MaxStageCountConfig{Max: new(0)},
+			WantViolations: 0,
+		},
+		{
+			Name:           "nil config uses defaults",
+			Content:        threeStages,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestMaxStageCountRule_ReportsFirstStageOverLimit(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine AS a\nFROM alpine AS b\nFROM alpine AS c\n"
+	input := testutil.MakeLintInputWithConfig(t, "Dockerfile", content, MaxStageCountConfig{Max: new(2)})
+	violations := NewMaxStageCountRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	if got := violations[0].Location.Start.Line; got != 3 {
+		t.Errorf("violation line = %d, want 3", got)
+	}
+	if got := violations[0].StageIndex; got != 2 {
+		t.Errorf("StageIndex = %d, want 2", got)
+	}
+}
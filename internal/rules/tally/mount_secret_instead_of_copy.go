@@ -0,0 +1,404 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/runmount"
+	"github.com/wharflab/tally/internal/semantic"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// MountSecretInsteadOfCopyRuleCode is the full rule code for the
+// mount-secret-instead-of-copy rule.
+const MountSecretInsteadOfCopyRuleCode = rules.TallyRulePrefix + "mount-secret-instead-of-copy"
+
+// defaultCredentialFilePatterns are base-name globs of files that hold
+// credentials: SSH keys, registry and package-manager auth files, TLS keys,
+// and cloud service account keys.
+var defaultCredentialFilePatterns = []string{
+	"id_rsa",
+	"id_dsa",
+	"id_ecdsa",
+	"id_ed25519",
+	".netrc",
+	".npmrc",
+	".pypirc",
+	".git-credentials",
+	".dockercfg",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"serviceaccount.json",
+	"service-account*.json",
+}
+
+// MountSecretInsteadOfCopyConfig is the configuration for the
+// mount-secret-instead-of-copy rule.
+type MountSecretInsteadOfCopyConfig struct {
+	// Patterns are globs matched against the base name of COPY/ADD sources
+	// and destinations. Defaults to common credential file names.
+	Patterns []string `json:"patterns,omitempty" koanf:"patterns"`
+}
+
+// DefaultMountSecretInsteadOfCopyConfig returns the default configuration.
+func DefaultMountSecretInsteadOfCopyConfig() MountSecretInsteadOfCopyConfig {
+	return MountSecretInsteadOfCopyConfig{
+		Patterns: slices.Clone(defaultCredentialFilePatterns),
+	}
+}
+
+// MountSecretInsteadOfCopyRule flags COPY and ADD instructions that put
+// credential files into an image layer. A file is a credential when its name
+// matches a configured pattern or, when the build context is available, when
+// gitleaks finds a secret in its content.
+//
+// Files in builder stages still end up in the layer cache and in any image
+// pushed from that stage, so they are reported too; files in the stages that
+// make up the final image are reported as errors.
+//
+// The fix removes the COPY and mounts the file as a build secret in the RUN
+// that follows it, which is where the credential is normally consumed.
+type MountSecretInsteadOfCopyRule struct {
+	schema map[string]any
+}
+
+// NewMountSecretInsteadOfCopyRule creates a new rule instance.
+func NewMountSecretInsteadOfCopyRule() *MountSecretInsteadOfCopyRule {
+	schema, err := configutil.RuleSchema(MountSecretInsteadOfCopyRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &MountSecretInsteadOfCopyRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *MountSecretInsteadOfCopyRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            MountSecretInsteadOfCopyRuleCode,
+		Name:            "Mount credential files as secrets instead of copying them",
+		Description:     "Credential files copied into an image stay in its layers; use RUN --mount=type=secret instead",
+		DocURL:          rules.TallyDocURL(MountSecretInsteadOfCopyRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "security",
+		IsExperimental:  false,
+		FixPriority:     85, // Same as require-secret-mounts: mount insertions go first.
+		Fixes:           []rules.FixSafety{rules.FixUnsafe},
+		// Replacing ADD with COPY does not help when the file must not be
+		// copied at all.
+		Supersedes: []string{rules.HadolintRulePrefix + "DL3020"},
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *MountSecretInsteadOfCopyRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *MountSecretInsteadOfCopyRule) DefaultConfig() any {
+	return DefaultMountSecretInsteadOfCopyConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *MountSecretInsteadOfCopyRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(MountSecretInsteadOfCopyRuleCode, config)
+}
+
+// resolveConfig extracts the config from input, falling back to defaults.
+func (r *MountSecretInsteadOfCopyRule) resolveConfig(config any) MountSecretInsteadOfCopyConfig {
+	return configutil.Coerce(config, DefaultMountSecretInsteadOfCopyConfig())
+}
+
+// credentialCopy is one credential file written by a COPY or ADD.
+type credentialCopy struct {
+	cmdIdx int
+	cmd    instructions.Command
+	name   string // source path, or in-image path for content findings
+	dest   string // in-image path
+	reason string
+}
+
+// Check runs the mount-secret-instead-of-copy rule.
+func (r *MountSecretInsteadOfCopyRule) Check(input rules.LintInput) []rules.Violation {
+	cfg := r.resolveConfig(input.Config)
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	sem := input.Semantic
+
+	var violations []rules.Violation
+	for stageIdx := range input.Stages {
+		stage := &input.Stages[stageIdx]
+		workdirAt := func(int) (string, bool) { return "/", false }
+		if sem != nil {
+			if info := sem.StageInfo(stageIdx); info != nil {
+				workdirAt = info.WorkdirAt
+			}
+		}
+
+		found := credentialCopies(stage, cfg.Patterns, workdirAt)
+		if input.Facts != nil {
+			found = append(found, contentCredentialCopies(stage, input.Facts.Stage(stageIdx), found)...)
+		}
+
+		severity := meta.DefaultSeverity
+		if sem != nil && sem.StageRole(stageIdx) == semantic.StageRoleFinal {
+			severity = rules.SeverityError
+		}
+
+		for _, c := range found {
+			v := rules.NewViolation(
+				rules.NewLocationFromRanges(input.File, c.cmd.Location()),
+				meta.Code,
+				fmt.Sprintf("%s copies credential file %s into the image; mount it as a secret instead",
+					strings.ToUpper(c.cmd.Name()), c.name),
+				severity,
+			).WithDocURL(meta.DocURL).WithDetail(
+				c.reason + ". Files copied into an image stay in its layers, even when a later RUN deletes them. " +
+					"Mount the file in the RUN that needs it with --mount=type=secret and pass it with " +
+					"docker build --secret id=<id>,src=<file>.",
+			)
+			if fix := secretMountFix(input.File, sm, stage, c, meta); fix != nil {
+				v = v.WithSuggestedFix(fix)
+			}
+			v.StageIndex = stageIdx
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// credentialCopies returns the COPY/ADD sources of a stage whose name, or the
+// name they are copied to, matches one of the patterns.
+func credentialCopies(
+	stage *instructions.Stage,
+	patterns []string,
+	workdirAt func(cmdIdx int) (string, bool),
+) []credentialCopy {
+	var found []credentialCopy
+	for cmdIdx, cmd := range stage.Commands {
+		var sources []string
+		var dest string
+		switch c := cmd.(type) {
+		case *instructions.CopyCommand:
+			sources, dest = c.SourcePaths, c.DestPath
+		case *instructions.AddCommand:
+			sources, dest = c.SourcePaths, c.DestPath
+		default:
+			continue
+		}
+		if len(cmd.Location()) == 0 {
+			continue
+		}
+
+		workdir, _ := workdirAt(cmdIdx)
+		for _, src := range sources {
+			if shell.IsURL(src) || isCopyHeredocSource(cmd, src) {
+				continue
+			}
+			target := credentialCopyDest(workdir, dest, src, len(sources))
+			pattern, ok := matchCredentialPattern(patterns, path.Base(src), path.Base(target))
+			if !ok {
+				continue
+			}
+			found = append(found, credentialCopy{
+				cmdIdx: cmdIdx,
+				cmd:    cmd,
+				name:   src,
+				dest:   target,
+				reason: fmt.Sprintf("The file name matches the credential pattern %q", pattern),
+			})
+		}
+	}
+	return found
+}
+
+// contentCredentialCopies returns the build context files copied by a stage
+// whose content gitleaks reports as a secret, skipping files already found by
+// name.
+func contentCredentialCopies(
+	stage *instructions.Stage,
+	stageFacts *facts.StageFacts,
+	byName []credentialCopy,
+) []credentialCopy {
+	detector := sharedGitleaksDetector()
+	if stageFacts == nil || detector == nil {
+		return nil
+	}
+
+	var found []credentialCopy
+	for cmdIdx, cmd := range stage.Commands {
+		loc := cmd.Location()
+		if len(loc) == 0 {
+			continue
+		}
+		line := loc[0].Start.Line
+		for _, observed := range stageFacts.ObservableFiles {
+			if observed == nil || observed.Line != line ||
+				(observed.Source != facts.ObservableFileSourceCopyContext &&
+					observed.Source != facts.ObservableFileSourceAddContext) {
+				continue
+			}
+			if slices.ContainsFunc(byName, func(c credentialCopy) bool {
+				return c.cmdIdx == cmdIdx && c.dest == observed.Path
+			}) {
+				continue
+			}
+			content, ok := observed.Content()
+			if !ok {
+				continue
+			}
+			findings := detector.DetectString(content)
+			if len(findings) == 0 {
+				continue
+			}
+			found = append(found, credentialCopy{
+				cmdIdx: cmdIdx,
+				cmd:    cmd,
+				name:   observed.Path,
+				dest:   observed.Path,
+				reason: fmt.Sprintf("The file contains a secret (gitleaks rule %s)", findings[0].RuleID),
+			})
+		}
+	}
+	return found
+}
+
+// matchCredentialPattern returns the first pattern matching any of the names.
+// Matching ignores case.
+func matchCredentialPattern(patterns []string, names ...string) (string, bool) {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(name)); err == nil && ok {
+				return pattern, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isCopyHeredocSource reports whether src names an inline heredoc of the
+// COPY/ADD rather than a file.
+func isCopyHeredocSource(cmd instructions.Command, src string) bool {
+	var contents []instructions.SourceContent
+	switch c := cmd.(type) {
+	case *instructions.CopyCommand:
+		contents = c.SourceContents
+	case *instructions.AddCommand:
+		contents = c.SourceContents
+	}
+	return slices.ContainsFunc(contents, func(sc instructions.SourceContent) bool {
+		return sc.Path == src
+	})
+}
+
+// credentialCopyDest returns the in-image path a source is copied to.
+func credentialCopyDest(workdir, dest, src string, sources int) string {
+	dest = shell.DropQuotes(dest)
+	if strings.HasSuffix(dest, "/") || sources > 1 {
+		dest = path.Join(dest, path.Base(src))
+	}
+	if !path.IsAbs(dest) {
+		dest = path.Join(workdir, dest)
+	}
+	return path.Clean(dest)
+}
+
+// secretMountFix removes a single-source, single-line COPY and adds a secret
+// mount with the same target to the next RUN of the stage. The fix is unsafe:
+// the build now needs the secret passed with --secret, and later RUNs no
+// longer see the file.
+func secretMountFix(
+	file string,
+	sm *sourcemap.SourceMap,
+	stage *instructions.Stage,
+	c credentialCopy,
+	meta rules.RuleMetadata,
+) *rules.SuggestedFix {
+	copyCmd, ok := c.cmd.(*instructions.CopyCommand)
+	if !ok || copyCmd.From != "" || copyCmd.Chown != "" || copyCmd.Chmod != "" ||
+		len(copyCmd.SourcePaths) != 1 || len(copyCmd.SourceContents) > 0 ||
+		strings.ContainsAny(copyCmd.SourcePaths[0], "*?[") {
+		return nil
+	}
+	if sm == nil {
+		return nil
+	}
+	copyLine, ok := singleLineInstruction(sm, copyCmd)
+	if !ok {
+		return nil
+	}
+
+	var run *instructions.RunCommand
+	for _, cmd := range stage.Commands[c.cmdIdx+1:] {
+		if r, isRun := cmd.(*instructions.RunCommand); isRun {
+			run = r
+			break
+		}
+	}
+	if run == nil || len(run.Location()) == 0 {
+		return nil
+	}
+	if slices.ContainsFunc(runmount.GetMounts(run), func(m *instructions.Mount) bool {
+		return m.Target == c.dest
+	}) {
+		return nil
+	}
+
+	id := secretID(c.name)
+	mount := runmount.FormatMount(&instructions.Mount{
+		Type:    instructions.MountTypeSecret,
+		CacheID: id,
+		Target:  c.dest,
+	})
+	runLoc := run.Location()
+	insertLine := runLoc[0].Start.Line
+	insertCol := runmount.RunKeywordEndColumn(runLoc, sm)
+
+	return &rules.SuggestedFix{
+		Description: fmt.Sprintf("Mount %s as secret %q in the next RUN instead of copying it", c.name, id),
+		Safety:      rules.FixUnsafe,
+		Priority:    meta.FixPriority,
+		Edits: []rules.TextEdit{
+			{
+				Location: rules.DeleteLineLocation(file, copyLine, len(sm.Line(copyLine-1)), sm.LineCount()),
+			},
+			{
+				Location: rules.NewRangeLocation(file, insertLine, insertCol, insertLine, insertCol),
+				NewText:  mount + " ",
+			},
+		},
+	}
+}
+
+// secretID derives a secret ID from a file name: ".npmrc" becomes "npmrc".
+func secretID(name string) string {
+	id := strings.TrimLeft(path.Base(shell.DropQuotes(name)), ".")
+	id = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, id)
+	if id == "" {
+		return strings.ToLower(command.Copy)
+	}
+	return id
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewMountSecretInsteadOfCopyRule())
+}
@@ -0,0 +1,564 @@
+package tally
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/runcheck"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// MultiPlatformPitfallsRuleCode is the full rule code for the multi-platform-pitfalls rule.
+const MultiPlatformPitfallsRuleCode = rules.TallyRulePrefix + "multi-platform-pitfalls"
+
+var (
+	// hardcodedArchToken matches an architecture name delimited by
+	// non-alphanumerics, e.g. "linux-amd64" or "tool_x86_64.tar.gz".
+	hardcodedArchToken = regexp.MustCompile(`(?:^|[^A-Za-z0-9])(amd64|x86_64|arm64|aarch64)(?:[^A-Za-z0-9]|$)`)
+
+	// archProbe matches shell commands that detect the running architecture.
+	archProbe = regexp.MustCompile(`uname\s+(-[a-z]*m|--machine)|dpkg\s+--print-architecture|apk\s+--print-arch`)
+
+	x86ArchName = regexp.MustCompile(`x86_64|amd64`)
+	armArchName = regexp.MustCompile(`aarch64|arm64`)
+)
+
+// stagePlatform classifies which platform a stage's instructions run on.
+type stagePlatform int
+
+const (
+	// stageForTarget stages run on every requested target platform.
+	stageForTarget stagePlatform = iota
+	// stageForBuild stages run on the build platform (FROM --platform=$BUILDPLATFORM).
+	stageForBuild
+	// stagePinned stages are pinned to one constant platform.
+	stagePinned
+)
+
+// MultiPlatformPitfallsRule detects common mistakes in Dockerfiles built for
+// several platforms:
+//
+//   - download URLs with a hardcoded architecture in stages that run on every
+//     target platform,
+//   - RUN instructions that read $TARGETARCH and the other automatic platform
+//     ARGs without declaring them in the stage (they expand to empty strings),
+//   - `uname -m` probes that handle x86_64 but not aarch64/arm64.
+//
+// The first and last checks only apply when the build is evidently
+// multi-platform: the invocation requests several platforms, a FROM uses
+// --platform=$BUILDPLATFORM/$TARGETPLATFORM, or the file declares one of the
+// automatic platform ARGs.
+type MultiPlatformPitfallsRule struct{}
+
+// NewMultiPlatformPitfallsRule creates a new multi-platform-pitfalls rule instance.
+func NewMultiPlatformPitfallsRule() *MultiPlatformPitfallsRule {
+	return &MultiPlatformPitfallsRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *MultiPlatformPitfallsRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            MultiPlatformPitfallsRuleCode,
+		Name:            "Multi-Platform Pitfalls",
+		Description:     "Multi-platform builds should not hardcode architectures or read undeclared platform ARGs",
+		DocURL:          rules.TallyDocURL(MultiPlatformPitfallsRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// Check runs the multi-platform-pitfalls rule.
+func (r *MultiPlatformPitfallsRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	escapeToken := dockerfile.ASTEscapeToken(input.AST)
+	multiPlatform := isMultiPlatformBuild(input)
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		if !stageUsesPOSIXShell(input, stageIdx) {
+			continue
+		}
+		forTarget := multiPlatform && classifyStagePlatform(input, stageIdx) == stageForTarget
+		declared := inheritedEnvNames(input, stageIdx)
+
+		for _, cmd := range stage.Commands {
+			switch c := cmd.(type) {
+			case *instructions.ArgCommand:
+				for _, kv := range c.Args {
+					declared[kv.Key] = true
+				}
+			case *instructions.EnvCommand:
+				for _, kv := range c.Env {
+					declared[kv.Key] = true
+				}
+			case *instructions.AddCommand:
+				if forTarget {
+					if v, ok := r.checkAddURL(input, meta, c, declared, sm); ok {
+						violations = append(violations, withStage(v, stageIdx))
+					}
+				}
+			case *instructions.RunCommand:
+				script := dockerfile.RunScript(c)
+				if v, ok := r.checkUndeclaredPlatformArg(input.File, meta, c, script, declared, sm); ok {
+					violations = append(violations, withStage(v, stageIdx))
+				}
+				if !forTarget {
+					continue
+				}
+				if v, ok := r.checkRunURLs(input.File, meta, c, script, declared, sm, escapeToken); ok {
+					violations = append(violations, withStage(v, stageIdx))
+				}
+				if v, ok := r.checkArchProbe(input.File, meta, c, script); ok {
+					violations = append(violations, withStage(v, stageIdx))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+func withStage(v rules.Violation, stageIdx int) rules.Violation {
+	v.StageIndex = stageIdx
+	return v
+}
+
+// checkUndeclaredPlatformArg reports a RUN that reads an automatic platform
+// ARG which is not declared in the stage. BuildKit only exposes these ARGs to
+// RUN after an `ARG <name>` in the same stage; a global ARG is not enough.
+func (r *MultiPlatformPitfallsRule) checkUndeclaredPlatformArg(
+	file string,
+	meta rules.RuleMetadata,
+	run *instructions.RunCommand,
+	script string,
+	declared map[string]bool,
+	sm *sourcemap.SourceMap,
+) (rules.Violation, bool) {
+	for _, name := range autoPlatformArgs {
+		if declared[name] || !referencesVar(script, name) || assignsVar(script, name) {
+			continue
+		}
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(file, run.Location()),
+			meta.Code,
+			fmt.Sprintf("RUN uses $%s but the stage does not declare ARG %s", name, name),
+			meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL).WithDetail(
+			"BuildKit only passes automatic platform ARGs to RUN after they are declared in the stage. " +
+				"Without `ARG " + name + "`, the variable expands to an empty string. " +
+				"A global ARG before the first FROM does not make it visible inside the stage.",
+		)
+		if edit, ok := insertArgBefore(file, run, name, sm); ok {
+			v = v.WithSuggestedFix(&rules.SuggestedFix{
+				Description: "Declare ARG " + name + " before the RUN instruction",
+				Safety:      rules.FixSuggestion,
+				Edits:       []rules.TextEdit{edit},
+				IsPreferred: true,
+			})
+		}
+		return v, true
+	}
+	return rules.Violation{}, false
+}
+
+// checkRunURLs reports curl/wget downloads whose URLs hardcode an
+// architecture. RUNs that probe the architecture themselves are skipped, since
+// they usually pick the URL per platform.
+func (r *MultiPlatformPitfallsRule) checkRunURLs(
+	file string,
+	meta rules.RuleMetadata,
+	run *instructions.RunCommand,
+	script string,
+	declared map[string]bool,
+	sm *sourcemap.SourceMap,
+	escapeToken rune,
+) (rules.Violation, bool) {
+	if archProbe.MatchString(script) || referencesAnyAutoPlatformArg(script) {
+		return rules.Violation{}, false
+	}
+	cmds, runStartLine := runcheck.FindCommands(run, shell.VariantBash, sm, escapeToken, "curl", "wget")
+
+	var (
+		first   string
+		loc     rules.Location
+		edits   []rules.TextEdit
+		fixable = runStartLine > 0 && sm != nil
+	)
+	for i := range cmds {
+		cmd := &cmds[i]
+		for argIdx, arg := range cmd.Args {
+			if !shell.IsURL(arg) {
+				continue
+			}
+			tokens := hardcodedArchTokens(arg)
+			if len(tokens) == 0 {
+				continue
+			}
+			if first == "" {
+				first = tokens[0]
+				if runStartLine > 0 {
+					line := runStartLine + cmd.Line
+					loc = rules.NewRangeLocation(file, line, cmd.StartCol, line, cmd.EndCol)
+				} else {
+					loc = rules.NewLocationFromRanges(file, run.Location())
+				}
+			}
+			if !fixable {
+				continue
+			}
+			argEdits, ok := templateArchEdits(file, cmd, argIdx, runStartLine, sm)
+			if !ok {
+				fixable = false
+				continue
+			}
+			edits = append(edits, argEdits...)
+		}
+	}
+	if first == "" {
+		return rules.Violation{}, false
+	}
+
+	v := hardcodedArchViolation(loc, meta, first, "Download URL")
+	if fixable && len(edits) > 0 {
+		if !declared["TARGETARCH"] {
+			edit, ok := insertArgBefore(file, run, "TARGETARCH", sm)
+			if !ok {
+				return v, true
+			}
+			edits = append([]rules.TextEdit{edit}, edits...)
+		}
+		v = v.WithSuggestedFix(&rules.SuggestedFix{
+			Description: "Use ${TARGETARCH} instead of the hardcoded architecture",
+			Safety:      rules.FixSuggestion,
+			Edits:       edits,
+			IsPreferred: true,
+		})
+	}
+	return v, true
+}
+
+// checkAddURL reports ADD <url> sources that hardcode an architecture.
+func (r *MultiPlatformPitfallsRule) checkAddURL(
+	input rules.LintInput,
+	meta rules.RuleMetadata,
+	add *instructions.AddCommand,
+	declared map[string]bool,
+	sm *sourcemap.SourceMap,
+) (rules.Violation, bool) {
+	for _, src := range add.SourcePaths {
+		if !shell.IsURL(src) || referencesAnyAutoPlatformArg(src) {
+			continue
+		}
+		tokens := hardcodedArchTokens(src)
+		if len(tokens) == 0 {
+			continue
+		}
+		loc := rules.NewLocationFromRanges(input.File, add.Location())
+		v := hardcodedArchViolation(loc, meta, tokens[0], "ADD source URL")
+		if edits, ok := templateArchInSource(input.File, add.Location(), src, sm); ok {
+			if !declared["TARGETARCH"] {
+				edit, ok := insertArgBefore(input.File, add, "TARGETARCH", sm)
+				if !ok {
+					return v, true
+				}
+				edits = append([]rules.TextEdit{edit}, edits...)
+			}
+			v = v.WithSuggestedFix(&rules.SuggestedFix{
+				Description: "Use ${TARGETARCH} instead of the hardcoded architecture",
+				Safety:      rules.FixSuggestion,
+				Edits:       edits,
+				IsPreferred: true,
+			})
+		}
+		return v, true
+	}
+	return rules.Violation{}, false
+}
+
+// checkArchProbe reports RUN scripts that branch on `uname -m` (or a package
+// manager's architecture query) and only mention x86_64/amd64.
+func (r *MultiPlatformPitfallsRule) checkArchProbe(
+	file string,
+	meta rules.RuleMetadata,
+	run *instructions.RunCommand,
+	script string,
+) (rules.Violation, bool) {
+	probe := archProbe.FindString(script)
+	if probe == "" || !x86ArchName.MatchString(script) || armArchName.MatchString(script) {
+		return rules.Violation{}, false
+	}
+	return rules.NewViolation(
+		rules.NewLocationFromRanges(file, run.Location()),
+		meta.Code,
+		fmt.Sprintf("Architecture check `%s` handles x86_64/amd64 but not aarch64/arm64", probe),
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(
+		"This stage is built for multiple platforms, and the script has no branch for arm64 hosts. " +
+			"Prefer `ARG TARGETARCH` and templating the download with ${TARGETARCH} (amd64, arm64, ...), " +
+			"or add an aarch64/arm64 branch.",
+	), true
+}
+
+func hardcodedArchViolation(loc rules.Location, meta rules.RuleMetadata, arch, what string) rules.Violation {
+	detail := "The stage is built for multiple platforms, but the URL always fetches the " + arch + " artifact. "
+	switch arch {
+	case "amd64", "arm64":
+		detail += "Declare `ARG TARGETARCH` in the stage and use ${TARGETARCH} in the URL."
+	default:
+		detail += "Declare `ARG TARGETARCH` in the stage and map it to the vendor's naming, " +
+			`e.g. case "${TARGETARCH}" in amd64) arch=x86_64 ;; arm64) arch=aarch64 ;; esac.`
+	}
+	return rules.NewViolation(
+		loc,
+		meta.Code,
+		fmt.Sprintf("%s hardcodes the %s architecture in a multi-platform build", what, arch),
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(detail)
+}
+
+// templateArchEdits replaces the amd64/arm64 tokens of a curl/wget URL
+// argument with ${TARGETARCH}. Fails for single-quoted or multi-line
+// arguments, and for vendor names (x86_64/aarch64) that TARGETARCH does not use.
+func templateArchEdits(
+	file string,
+	cmd *shell.CommandInfo,
+	argIdx, runStartLine int,
+	sm *sourcemap.SourceMap,
+) ([]rules.TextEdit, bool) {
+	if argIdx >= len(cmd.ArgRanges) {
+		return nil, false
+	}
+	rng := cmd.ArgRanges[argIdx]
+	line := runStartLine + rng.Line
+	if line < 1 || line > sm.LineCount() {
+		return nil, false
+	}
+	src := sm.Line(line - 1)
+	if rng.StartCol < 0 || rng.EndCol > len(src) || rng.StartCol >= rng.EndCol {
+		return nil, false
+	}
+	raw := src[rng.StartCol:rng.EndCol]
+	if strings.HasPrefix(raw, "'") {
+		return nil, false
+	}
+	return archTokenEdits(file, line, rng.StartCol, raw)
+}
+
+// templateArchInSource finds an ADD source URL on one of the instruction's
+// source lines and replaces its amd64/arm64 tokens with ${TARGETARCH}.
+func templateArchInSource(file string, loc []parser.Range, src string, sm *sourcemap.SourceMap) ([]rules.TextEdit, bool) {
+	if sm == nil || len(loc) == 0 {
+		return nil, false
+	}
+	for line := loc[0].Start.Line; line <= loc[len(loc)-1].End.Line; line++ {
+		if line < 1 || line > sm.LineCount() {
+			continue
+		}
+		text := sm.Line(line - 1)
+		if col := strings.Index(text, src); col >= 0 {
+			return archTokenEdits(file, line, col, src)
+		}
+	}
+	return nil, false
+}
+
+// archTokenEdits builds one edit per amd64/arm64 token in raw, which starts
+// at col on line.
+func archTokenEdits(file string, line, col int, raw string) ([]rules.TextEdit, bool) {
+	var edits []rules.TextEdit
+	for _, m := range hardcodedArchToken.FindAllStringSubmatchIndex(raw, -1) {
+		start, end := m[2], m[3]
+		switch raw[start:end] {
+		case "amd64", "arm64":
+		default:
+			return nil, false
+		}
+		edits = append(edits, rules.TextEdit{
+			Location: rules.NewRangeLocation(file, line, col+start, line, col+end),
+			NewText:  "${TARGETARCH}",
+		})
+	}
+	return edits, len(edits) > 0
+}
+
+// hardcodedArchTokens returns the architecture names hardcoded in s.
+func hardcodedArchTokens(s string) []string {
+	var tokens []string
+	for _, m := range hardcodedArchToken.FindAllStringSubmatch(s, -1) {
+		tokens = append(tokens, m[1])
+	}
+	return tokens
+}
+
+// insertArgBefore returns an edit that declares `ARG name` on its own line
+// before cmd, using the same indentation.
+func insertArgBefore(file string, cmd instructions.Command, name string, sm *sourcemap.SourceMap) (rules.TextEdit, bool) {
+	loc := cmd.Location()
+	if sm == nil || len(loc) == 0 {
+		return rules.TextEdit{}, false
+	}
+	line := loc[0].Start.Line
+	if line < 1 || line > sm.LineCount() {
+		return rules.TextEdit{}, false
+	}
+	text := sm.Line(line - 1)
+	indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+	return rules.TextEdit{
+		Location: rules.NewRangeLocation(file, line, 0, line, 0),
+		NewText:  indent + "ARG " + name + "\n",
+	}, true
+}
+
+// isMultiPlatformBuild reports whether the build evidently targets several
+// platforms.
+func isMultiPlatformBuild(input rules.LintInput) bool {
+	if input.InvocationContext != nil {
+		if inv := input.InvocationContext.Invocation(); inv != nil && len(inv.Platforms) > 1 {
+			return true
+		}
+	}
+	for _, stage := range input.Stages {
+		if referencesAutoPlatformArg(stage.Platform) {
+			return true
+		}
+		for _, cmd := range stage.Commands {
+			if arg, ok := cmd.(*instructions.ArgCommand); ok && declaresAutoPlatformArg(arg) {
+				return true
+			}
+		}
+	}
+	for _, arg := range input.MetaArgs {
+		if declaresAutoPlatformArg(&arg) {
+			return true
+		}
+	}
+	return false
+}
+
+func declaresAutoPlatformArg(arg *instructions.ArgCommand) bool {
+	for _, kv := range arg.Args {
+		if slices.Contains(autoPlatformArgs, kv.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyStagePlatform follows FROM <stage> chains to the first stage with a
+// --platform flag. BUILDOS/BUILDARCH-only expressions count as build platform.
+func classifyStagePlatform(input rules.LintInput, stageIdx int) stagePlatform {
+	seen := map[int]bool{}
+	for stageIdx >= 0 && stageIdx < len(input.Stages) && !seen[stageIdx] {
+		seen[stageIdx] = true
+		platform := input.Stages[stageIdx].Platform
+		switch {
+		case platform == "":
+		case strings.Contains(platform, "BUILD"):
+			return stageForBuild
+		case !strings.Contains(platform, "$"):
+			return stagePinned
+		default:
+			return stageForTarget
+		}
+		stageIdx = parentStageIndex(input, stageIdx)
+	}
+	return stageForTarget
+}
+
+// parentStageIndex returns the stage a stage is built FROM, or -1.
+func parentStageIndex(input rules.LintInput, stageIdx int) int {
+	if input.Semantic == nil {
+		return -1
+	}
+	info := input.Semantic.StageInfo(stageIdx)
+	if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef {
+		return -1
+	}
+	return info.BaseImage.StageIndex
+}
+
+// inheritedEnvNames collects ENV keys set by the stages a stage is built FROM.
+// ARGs are not inherited across FROM, ENV is.
+func inheritedEnvNames(input rules.LintInput, stageIdx int) map[string]bool {
+	names := map[string]bool{}
+	seen := map[int]bool{stageIdx: true}
+	for parent := parentStageIndex(input, stageIdx); parent >= 0 && !seen[parent]; parent = parentStageIndex(input, parent) {
+		seen[parent] = true
+		for _, cmd := range input.Stages[parent].Commands {
+			if env, ok := cmd.(*instructions.EnvCommand); ok {
+				for _, kv := range env.Env {
+					names[kv.Key] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// stageUsesPOSIXShell reports whether RUN scripts of the stage are POSIX shell.
+func stageUsesPOSIXShell(input rules.LintInput, stageIdx int) bool {
+	if input.Semantic == nil {
+		return true
+	}
+	info := input.Semantic.StageInfo(stageIdx)
+	return info == nil || info.ShellSetting.Variant.SupportsPOSIXShellAST()
+}
+
+// referencesAnyAutoPlatformArg reports whether s expands one of the
+// automatic platform ARGs.
+func referencesAnyAutoPlatformArg(s string) bool {
+	return slices.ContainsFunc(autoPlatformArgs, func(name string) bool {
+		return referencesVar(s, name)
+	})
+}
+
+// referencesVar reports whether script expands $name or ${name...}.
+func referencesVar(script, name string) bool {
+	for _, prefix := range []string{"$" + name, "${" + name} {
+		rest := script
+		for {
+			idx := strings.Index(rest, prefix)
+			if idx < 0 {
+				break
+			}
+			rest = rest[idx+len(prefix):]
+			if rest == "" || !isShellNameChar(rest[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assignsVar reports whether script assigns name itself (name=... or export name=...).
+func assignsVar(script, name string) bool {
+	rest := script
+	for {
+		idx := strings.Index(rest, name+"=")
+		if idx < 0 {
+			return false
+		}
+		if idx == 0 || !isShellNameChar(rest[idx-1]) && rest[idx-1] != '$' && rest[idx-1] != '{' {
+			return true
+		}
+		rest = rest[idx+len(name)+1:]
+	}
+}
+
+func isShellNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewMultiPlatformPitfallsRule())
+}
@@ -0,0 +1,224 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/invocation"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestMultiPlatformPitfallsRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewMultiPlatformPitfallsRule().Metadata())
+}
+
+func TestMultiPlatformPitfallsRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewMultiPlatformPitfallsRule(), []testutil.RuleTestCase{
+		{
+			Name: "undeclared TARGETARCH in RUN",
+			Content: `FROM alpine
+RUN wget -O /usr/local/bin/tool https://example.com/tool-linux-$TARGETARCH
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"RUN uses $TARGETARCH but the stage does not declare ARG TARGETARCH"},
+		},
+		{
+			Name: "global ARG does not reach the stage",
+			Content: `ARG TARGETOS
+FROM alpine
+RUN echo "${TARGETOS}"
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"does not declare ARG TARGETOS"},
+		},
+		{
+			Name: "declared TARGETARCH",
+			Content: `FROM alpine
+ARG TARGETARCH
+RUN wget -O /usr/local/bin/tool https://example.com/tool-linux-${TARGETARCH}
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "ENV inherited from parent stage",
+			Content: `FROM alpine AS base
+ENV TARGETARCH=amd64
+FROM base
+RUN echo $TARGETARCH
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "variable assigned in the script",
+			Content: `FROM alpine
+RUN TARGETARCH=$(uname -m) && echo $TARGETARCH
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "similar variable name",
+			Content: `FROM alpine
+RUN echo $TARGETARCH_SUFFIX
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "hardcoded amd64 in multi-platform build",
+			Content: `FROM --platform=$BUILDPLATFORM golang:1.25 AS build
+RUN echo build
+
+FROM alpine
+RUN curl -fsSLo /usr/local/bin/kubectl https://dl.k8s.io/release/v1.31.0/bin/linux/amd64/kubectl
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"Download URL hardcodes the amd64 architecture in a multi-platform build"},
+		},
+		{
+			Name: "hardcoded amd64 in single-platform build",
+			Content: `FROM alpine
+RUN curl -fsSLo /usr/local/bin/kubectl https://dl.k8s.io/release/v1.31.0/bin/linux/amd64/kubectl
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "hardcoded arch in build-platform stage",
+			Content: `FROM --platform=$BUILDPLATFORM alpine
+RUN wget https://example.com/protoc-linux-x86_64.zip
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "hardcoded arch in pinned stage",
+			Content: `ARG TARGETARCH
+FROM --platform=linux/amd64 alpine
+RUN wget https://example.com/protoc-linux-x86_64.zip
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "hardcoded x86_64 in ADD",
+			Content: `FROM --platform=$TARGETPLATFORM alpine
+ADD https://example.com/tool-x86_64.tar.gz /tmp/
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"ADD source URL hardcodes the x86_64 architecture"},
+		},
+		{
+			Name: "uname -m branch without arm64",
+			Content: `FROM alpine
+ARG TARGETARCH
+RUN case "$(uname -m)" in \
+      x86_64) url=https://example.com/tool-x86_64 ;; \
+      *) echo unsupported; exit 1 ;; \
+    esac && wget "$url"
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"Architecture check `uname -m` handles x86_64/amd64 but not aarch64/arm64"},
+		},
+		{
+			Name: "uname -m branch with arm64",
+			Content: `FROM alpine
+ARG TARGETARCH
+RUN case "$(uname -m)" in \
+      x86_64) url=https://example.com/tool-x86_64 ;; \
+      aarch64) url=https://example.com/tool-aarch64 ;; \
+    esac && wget "$url"
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestMultiPlatformPitfallsRule_InvocationPlatforms(t *testing.T) {
+	t.Parallel()
+	content := "FROM alpine\nRUN wget https://example.com/tool-linux-arm64\n"
+
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	if got := NewMultiPlatformPitfallsRule().Check(input); len(got) != 0 {
+		t.Fatalf("got %d violations without platforms, want 0", len(got))
+	}
+
+	input.InvocationContext = invocation.NewContext(&invocation.BuildInvocation{
+		Platforms: []string{"linux/amd64", "linux/arm64"},
+	})
+	if got := NewMultiPlatformPitfallsRule().Check(input); len(got) != 1 {
+		t.Fatalf("got %d violations with two platforms, want 1", len(got))
+	}
+}
+
+func TestMultiPlatformPitfallsRule_Fix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "declare ARG",
+			content: `FROM alpine
+    RUN echo $TARGETARCH
+`,
+			want: `FROM alpine
+    ARG TARGETARCH
+    RUN echo $TARGETARCH
+`,
+		},
+		{
+			name: "template URL",
+			content: `ARG TARGETARCH
+FROM alpine
+RUN curl -fsSLo /tmp/tool.tgz "https://example.com/v1/tool-linux-amd64.tgz"
+`,
+			want: `ARG TARGETARCH
+FROM alpine
+ARG TARGETARCH
+RUN curl -fsSLo /tmp/tool.tgz "https://example.com/v1/tool-linux-${TARGETARCH}.tgz"
+`,
+		},
+		{
+			name: "template ADD URL",
+			content: `FROM --platform=$TARGETPLATFORM alpine
+ARG TARGETARCH
+ADD https://example.com/tool-linux-arm64 /usr/local/bin/tool
+`,
+			want: `FROM --platform=$TARGETPLATFORM alpine
+ARG TARGETARCH
+ADD https://example.com/tool-linux-${TARGETARCH} /usr/local/bin/tool
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewMultiPlatformPitfallsRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			if violations[0].SuggestedFix == nil {
+				t.Fatal("expected a suggested fix")
+			}
+			got := string(fix.ApplyFix([]byte(tt.content), violations[0].PreferredFix()))
+			if got != tt.want {
+				t.Errorf("fixed content =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiPlatformPitfallsRule_NoFixForVendorArchNames(t *testing.T) {
+	t.Parallel()
+	content := "ARG TARGETARCH\nFROM alpine\nRUN wget https://example.com/tool-linux-x86_64.zip\n"
+	violations := NewMultiPlatformPitfallsRule().Check(testutil.MakeLintInput(t, "Dockerfile", content))
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	if violations[0].SuggestedFix != nil {
+		t.Errorf("unexpected fix %q", violations[0].SuggestedFix.Description)
+	}
+}
@@ -50,6 +50,7 @@ func (r *NamedIdentityInPasswdlessStageRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(NamedIdentityInPasswdlessStageRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -52,6 +52,7 @@ func (r *NewlineBetweenInstructionsRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     200,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -68,6 +68,7 @@ func (r *NewlinePerChainedCallRule) Metadata() rules.RuleMetadata {
 		// DL3014/10, DL3047/96) whose column shifts the fixer tracks. Our edits
 		// insert newlines which the fixer can't track, so we run last among syncs.
 		FixPriority: 97,
+		Fixes:       []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -0,0 +1,270 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/printer"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/runmount"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// NoBuildtimeNetworkInFinalStageRuleCode is the full rule code for the
+// no-buildtime-network-in-final-stage rule.
+const NoBuildtimeNetworkInFinalStageRuleCode = rules.TallyRulePrefix + "no-buildtime-network-in-final-stage"
+
+// downloadStageName is the name of the stage the fix moves a download into.
+const downloadStageName = "downloads"
+
+// NoBuildtimeNetworkInFinalStageRule reports RUN instructions that fetch
+// from the network with curl, wget or git clone in a runtime stage of a
+// multi-stage build (see facts.StageKindRuntime).
+//
+// A fetch in the image that ships ties its layers to whatever the remote
+// served at build time and usually keeps the download tool around. Doing it
+// in a builder stage and copying the result, or using ADD --checksum, keeps
+// the runtime stage to files whose origin is pinned.
+//
+// When the RUN is a single curl or wget download to a file and nothing
+// earlier in the stage can affect it, a FixSuggestion moves it into a new
+// stage built from the same base and copies the file back.
+type NoBuildtimeNetworkInFinalStageRule struct{}
+
+// NewNoBuildtimeNetworkInFinalStageRule creates a new rule instance.
+func NewNoBuildtimeNetworkInFinalStageRule() *NoBuildtimeNetworkInFinalStageRule {
+	return &NoBuildtimeNetworkInFinalStageRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *NoBuildtimeNetworkInFinalStageRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            NoBuildtimeNetworkInFinalStageRuleCode,
+		Name:            "No Build-time Network in Final Stage",
+		Description:     "RUN fetches from the network in the final stage instead of a builder stage",
+		DocURL:          rules.TallyDocURL(NoBuildtimeNetworkInFinalStageRuleCode),
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "security",
+		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// Check runs the no-buildtime-network-in-final-stage rule.
+func (r *NoBuildtimeNetworkInFinalStageRule) Check(input rules.LintInput) []rules.Violation {
+	// Single-stage builds have no builder stage to move the fetch into;
+	// tally/prefer-multi-stage-build covers them.
+	if input.Facts == nil || input.Semantic == nil || len(input.Stages) < 2 {
+		return nil
+	}
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for stageIdx := range input.Stages {
+		sf := input.Facts.Stage(stageIdx)
+		if !sf.IsRuntime() {
+			continue
+		}
+		for _, run := range sf.Runs {
+			tool := networkFetchTool(run.CommandInfos)
+			if tool == "" {
+				continue
+			}
+			loc := rules.NewLocationFromRanges(input.File, run.Run.Location())
+			msg := fmt.Sprintf("%s fetches from the network in runtime stage %s",
+				tool, formatStageName(input.Semantic, stageIdx))
+			v := rules.NewViolation(loc, meta.Code, msg, meta.DefaultSeverity).
+				WithDocURL(meta.DocURL).
+				WithDetail("The image that ships depends on what the remote served at build time, " +
+					"and the fetch usually leaves its tool behind. Fetch in a builder stage and COPY --from " +
+					"the result, or use ADD --checksum to pin a download.")
+			if fix := moveDownloadToStageFix(input, stageIdx, run); fix != nil {
+				v = v.WithSuggestedFix(fix)
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// networkFetchTool returns the first command that fetches from the network:
+// curl or wget with a URL, or git clone. It returns "" when there is none.
+func networkFetchTool(commands []shell.CommandInfo) string {
+	for i := range commands {
+		cmd := &commands[i]
+		switch cmd.Name {
+		case "curl", "wget":
+			if shell.DownloadURL(cmd) != "" {
+				return cmd.Name
+			}
+		case "git":
+			if cmd.Subcommand == "clone" {
+				return "git clone"
+			}
+		}
+	}
+	return ""
+}
+
+// moveDownloadToStageFix returns a fix that moves a single-file download
+// into a new stage before the runtime stage and copies the file back, or nil
+// when the move could change the result.
+func moveDownloadToStageFix(input rules.LintInput, stageIdx int, run *facts.RunFacts) *rules.SuggestedFix {
+	outputPath, ok := movableDownloadOutput(run)
+	if !ok || !runStartsStage(input.Stages[stageIdx], run.CommandIndex) || hasNumericStageRefs(input.Stages) {
+		return nil
+	}
+	stage := input.Stages[stageIdx]
+	fromNode := nodeAtLine(input.AST, stage.Location)
+	runNode := nodeAtLine(input.AST, run.Run.Location())
+	sm := input.SourceMap()
+	if fromNode == nil || runNode == nil || sm == nil {
+		return nil
+	}
+
+	from := printer.FromNode(fromNode)
+	if len(from.Flags) > 1 || (len(from.Flags) == 1 && !strings.HasPrefix(from.Flags[0], "--platform=")) {
+		return nil
+	}
+	base, _, _ := strings.Cut(from.Args, " ")
+	if base == "" {
+		return nil
+	}
+	name := uniqueStageName(input.Stages, downloadStageName)
+	runLoc := run.Run.Location()
+	endLine := sm.ResolveEndLineWithEscape(runNode.EndLine, dockerfile.ASTEscapeToken(input.AST))
+	// Move the RUN verbatim so its line continuations survive.
+	runText := sm.Snippet(runLoc[0].Start.Line-1, endLine-1)
+	newStage := printer.Instruction{
+		Keyword: from.Keyword,
+		Flags:   from.Flags,
+		Args:    base + " AS " + name,
+	}.String() + "\n" + strings.TrimLeft(runText, " \t") + "\n\n"
+
+	if !path.IsAbs(outputPath) {
+		outputPath = path.Join(run.Workdir, outputPath)
+	}
+	copyKeyword := "COPY"
+	if keyword := printer.FromNode(runNode).Keyword; keyword == strings.ToLower(keyword) {
+		copyKeyword = "copy"
+	}
+	copyInst := printer.Instruction{
+		Keyword: copyKeyword,
+		Flags:   []string{printer.Flag("from", name)},
+		Args:    outputPath + " " + outputPath,
+	}.String()
+
+	insertLine := sm.EffectiveStartLine(fromNode.StartLine, fromNode.PrevComment)
+	return &rules.SuggestedFix{
+		Description: fmt.Sprintf("Move the download into a new %q stage and COPY the file from it", name),
+		Safety:      rules.FixSuggestion,
+		Edits: []rules.TextEdit{
+			{
+				Location: rules.NewRangeLocation(input.File, insertLine, 0, insertLine, 0),
+				NewText:  newStage,
+			},
+			{
+				Location: rules.NewRangeLocation(input.File,
+					runLoc[0].Start.Line, runLoc[0].Start.Character, endLine, len(sm.Line(endLine-1))),
+				NewText: copyInst,
+			},
+		},
+	}
+}
+
+// movableDownloadOutput returns the file a RUN downloads to when the RUN is
+// nothing but one curl or wget transfer to a file.
+func movableDownloadOutput(run *facts.RunFacts) (string, bool) {
+	if !run.Run.PrependShell || len(run.Run.Files) > 0 || len(run.CommandInfos) != 1 ||
+		len(run.CommandOperationFacts) != 1 {
+		return "", false
+	}
+	op := run.CommandOperationFacts[0]
+	if op.Status != facts.CommandOperationLifted || op.HTTPTransfer == nil ||
+		op.HTTPTransfer.SinkKind != facts.HTTPTransferSinkFile || op.HTTPTransfer.OutputPath == "" {
+		return "", false
+	}
+	return op.HTTPTransfer.OutputPath, true
+}
+
+// runStartsStage reports whether nothing before the command at cmdIdx in
+// stage can change what it downloads or where the file lands.
+func runStartsStage(stage instructions.Stage, cmdIdx int) bool {
+	for _, cmd := range stage.Commands[:cmdIdx] {
+		switch cmd.(type) {
+		case *instructions.RunCommand, *instructions.CopyCommand, *instructions.AddCommand,
+			*instructions.WorkdirCommand, *instructions.UserCommand, *instructions.ShellCommand,
+			*instructions.OnbuildCommand:
+			return false
+		}
+	}
+	return true
+}
+
+// hasNumericStageRefs reports whether any instruction refers to a stage by
+// index, which inserting a stage would shift.
+func hasNumericStageRefs(stages []instructions.Stage) bool {
+	isIndex := func(ref string) bool {
+		_, err := strconv.Atoi(ref)
+		return err == nil
+	}
+	for _, stage := range stages {
+		if isIndex(stage.BaseName) {
+			return true
+		}
+		for _, cmd := range stage.Commands {
+			switch c := cmd.(type) {
+			case *instructions.CopyCommand:
+				if isIndex(c.From) {
+					return true
+				}
+			case *instructions.RunCommand:
+				for _, m := range runmount.GetMounts(c) {
+					if isIndex(m.From) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// uniqueStageName returns name, or name with a numeric suffix when a stage
+// already uses it.
+func uniqueStageName(stages []instructions.Stage, name string) string {
+	taken := make(map[string]bool, len(stages))
+	for _, stage := range stages {
+		taken[strings.ToLower(stage.Name)] = true
+	}
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	return candidate
+}
+
+// nodeAtLine returns the top-level AST node starting on the first line of loc.
+func nodeAtLine(ast *parser.Result, loc []parser.Range) *parser.Node {
+	if ast == nil || ast.AST == nil || len(loc) == 0 {
+		return nil
+	}
+	for _, node := range ast.AST.Children {
+		if node.StartLine == loc[0].Start.Line {
+			return node
+		}
+	}
+	return nil
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewNoBuildtimeNetworkInFinalStageRule())
+}
@@ -0,0 +1,203 @@
+package tally
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestNoBuildtimeNetworkInFinalStageMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewNoBuildtimeNetworkInFinalStageRule().Metadata())
+}
+
+func TestNoBuildtimeNetworkInFinalStageRule(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewNoBuildtimeNetworkInFinalStageRule(), []testutil.RuleTestCase{
+		{
+			Name: "single stage",
+			Content: `FROM alpine:3.20
+RUN wget -O /usr/local/bin/tool https://example.com/tool
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "fetch in builder stage",
+			Content: `FROM alpine:3.20 AS build
+RUN wget -O /out/tool https://example.com/tool
+
+FROM alpine:3.20
+COPY --from=build /out/tool /usr/local/bin/tool
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "curl, wget and git clone in the final stage",
+			Content: `FROM golang:1.24 AS build
+RUN go build -o /out/app .
+
+FROM debian:bookworm-slim
+RUN apt-get update && apt-get install -y curl git
+RUN curl -fsSL https://example.com/install.sh | sh
+RUN wget -q https://example.com/data.tar.gz && tar xzf data.tar.gz
+RUN git clone https://github.com/example/plugins /opt/plugins
+COPY --from=build /out/app /app
+`,
+			WantViolations: 3,
+			WantMessages: []string{
+				"curl fetches from the network in runtime stage stage 1",
+				"wget fetches from the network in runtime stage stage 1",
+				"git clone fetches from the network in runtime stage stage 1",
+			},
+		},
+		{
+			Name: "curl without a URL",
+			Content: `FROM alpine:3.20 AS build
+RUN make
+
+FROM alpine:3.20
+RUN curl --version
+COPY --from=build /out /out
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "test stage is not a runtime stage",
+			Content: `FROM alpine:3.20 AS build
+RUN make
+
+FROM build AS test
+RUN curl -fsSL https://example.com/fixtures.json -o /tmp/fixtures.json
+
+FROM alpine:3.20
+COPY --from=build /out /out
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestNoBuildtimeNetworkInFinalStageFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		content   string
+		wantFixed string
+	}{
+		{
+			name: "move a download into a new stage",
+			content: `FROM golang:1.24 AS build
+RUN go build -o /out/app .
+
+# Runtime image
+FROM --platform=linux/amd64 alpine:3.20 AS runtime
+ENV TOOL_HOME=/opt/tool
+RUN curl -fsSL -o /usr/local/bin/tool \
+    https://example.com/tool
+COPY --from=build /out/app /app
+`,
+			wantFixed: `FROM golang:1.24 AS build
+RUN go build -o /out/app .
+
+FROM --platform=linux/amd64 alpine:3.20 AS downloads
+RUN curl -fsSL -o /usr/local/bin/tool \
+    https://example.com/tool
+
+# Runtime image
+FROM --platform=linux/amd64 alpine:3.20 AS runtime
+ENV TOOL_HOME=/opt/tool
+COPY --from=downloads /usr/local/bin/tool /usr/local/bin/tool
+COPY --from=build /out/app /app
+`,
+		},
+		{
+			name: "relative output and taken stage name",
+			content: `FROM alpine:3.20 AS downloads
+RUN make
+
+FROM alpine:3.20
+RUN wget -O tool.tar.gz https://example.com/tool.tar.gz
+COPY --from=downloads /out /out
+`,
+			wantFixed: `FROM alpine:3.20 AS downloads
+RUN make
+
+FROM alpine:3.20 AS downloads-2
+RUN wget -O tool.tar.gz https://example.com/tool.tar.gz
+
+FROM alpine:3.20
+COPY --from=downloads-2 /tool.tar.gz /tool.tar.gz
+COPY --from=downloads /out /out
+`,
+		},
+	}
+
+	rule := NewNoBuildtimeNetworkInFinalStageRule()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			violations := rule.Check(testutil.MakeLintInput(t, "Dockerfile", tt.content))
+			if len(violations) != 1 || violations[0].SuggestedFix == nil {
+				t.Fatalf("want one violation with a fix, got %+v", violations)
+			}
+			if violations[0].SuggestedFix.Safety != rules.FixSuggestion {
+				t.Errorf("fix safety = %v, want %v", violations[0].SuggestedFix.Safety, rules.FixSuggestion)
+			}
+			result, err := (&fixpkg.Fixer{SafetyThreshold: rules.FixSuggestion}).Apply(
+				context.Background(),
+				violations,
+				map[string][]byte{"Dockerfile": []byte(tt.content)},
+			)
+			if err != nil {
+				t.Fatalf("apply fixes: %v", err)
+			}
+			if got := string(result.Changes["Dockerfile"].ModifiedContent); got != tt.wantFixed {
+				t.Errorf("fixed content =\n%s\nwant:\n%s", got, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestNoBuildtimeNetworkInFinalStageNoFix(t *testing.T) {
+	t.Parallel()
+
+	for name, content := range map[string]string{
+		"earlier RUN may install the tool": `FROM alpine:3.20 AS build
+RUN make
+
+FROM alpine:3.20
+RUN apk add --no-cache curl
+RUN curl -fsSL -o /usr/local/bin/tool https://example.com/tool
+`,
+		"download piped to a shell": `FROM alpine:3.20 AS build
+RUN make
+
+FROM alpine:3.20
+RUN curl -fsSL https://example.com/install.sh | sh
+`,
+		"stage referenced by index": `FROM alpine:3.20
+RUN make
+
+FROM alpine:3.20
+RUN curl -fsSL -o /usr/local/bin/tool https://example.com/tool
+COPY --from=0 /out /out
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			violations := NewNoBuildtimeNetworkInFinalStageRule().Check(testutil.MakeLintInput(t, "Dockerfile", content))
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			if violations[0].SuggestedFix != nil {
+				t.Errorf("unexpected fix: %+v", violations[0].SuggestedFix)
+			}
+		})
+	}
+}
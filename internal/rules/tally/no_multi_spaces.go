@@ -36,6 +36,7 @@ func (r *NoMultiSpacesRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     10,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -86,6 +86,7 @@ func (r *NoMultipleEmptyLinesRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     98, // After newline-per-chained-call (97) to avoid line-shift conflicts
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -0,0 +1,339 @@
+package tally
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/async/download"
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// NoPipeToShellRuleCode is the full rule code for the no-pipe-to-shell rule.
+const NoPipeToShellRuleCode = rules.TallyRulePrefix + "no-pipe-to-shell"
+
+// NoPipeToShellConfig is the configuration for the no-pipe-to-shell rule.
+type NoPipeToShellConfig struct {
+	// AllowedHosts are hosts whose downloads may be piped into a shell.
+	// A leading "*." matches any subdomain.
+	AllowedHosts []string `json:"allowed-hosts,omitempty" koanf:"allowed-hosts"`
+}
+
+// DefaultNoPipeToShellConfig returns the default configuration.
+func DefaultNoPipeToShellConfig() NoPipeToShellConfig {
+	return NoPipeToShellConfig{}
+}
+
+// NoPipeToShellRule flags RUN instructions that pipe a download from curl
+// or wget straight into a shell, as in "curl -fsSL https://... | sh". The
+// build runs whatever the server returns at that moment, so a compromised,
+// changed or truncated script goes unnoticed.
+//
+// With slow checks enabled, the rule computes the checksum of the script
+// and suggests downloading it with ADD --checksum, then running the
+// verified file from the RUN.
+type NoPipeToShellRule struct {
+	schema map[string]any
+}
+
+// NewNoPipeToShellRule creates a new rule instance.
+func NewNoPipeToShellRule() *NoPipeToShellRule {
+	schema, err := configutil.RuleSchema(NoPipeToShellRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &NoPipeToShellRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *NoPipeToShellRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            NoPipeToShellRuleCode,
+		Name:            "No pipe-to-shell installs",
+		Description:     "Downloads piped into a shell run unverified code; download with ADD --checksum instead",
+		DocURL:          rules.TallyDocURL(NoPipeToShellRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "security",
+		IsExperimental:  false,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		RelatedTo:       []string{AddChecksumRequiredRuleCode, CurlShouldFollowRedirectsRuleCode},
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *NoPipeToShellRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *NoPipeToShellRule) DefaultConfig() any {
+	return DefaultNoPipeToShellConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *NoPipeToShellRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(NoPipeToShellRuleCode, config)
+}
+
+// resolveConfig extracts the config from input, falling back to defaults.
+func (r *NoPipeToShellRule) resolveConfig(config any) NoPipeToShellConfig {
+	return configutil.Coerce(config, DefaultNoPipeToShellConfig())
+}
+
+// pipeToShellFinding is one download piped into a shell in a RUN.
+type pipeToShellFinding struct {
+	run      *instructions.RunCommand
+	stageIdx int
+	pipe     shell.PipeToShell
+	// startLine is the 1-based line the pipe positions are relative to, or 0
+	// when they are not source positions (heredoc scripts).
+	startLine int
+}
+
+// fixable reports whether the script can be fetched by ADD --checksum: the
+// pipe is in the RUN source, it downloads one literal http(s) URL and the
+// shell arguments are literal.
+func (f pipeToShellFinding) fixable() bool {
+	if f.startLine == 0 || !f.pipe.Literal || len(f.pipe.URLs) != 1 || !shell.IsURL(f.pipe.URLs[0]) {
+		return false
+	}
+	_, ok := f.pipe.FileCommand("/tmp/" + pipeScriptName(f.pipe.URLs[0]))
+	return ok
+}
+
+// Check reports downloads piped into a shell.
+func (r *NoPipeToShellRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for _, f := range r.findings(input) {
+		violations = append(violations, pipeToShellViolation(meta, input.File, f))
+	}
+	return violations
+}
+
+// PlanAsync requests the checksums of the fixable downloads of each stage.
+// One request covers a whole stage, since a completed request replaces all
+// of the stage's fast-path violations.
+func (r *NoPipeToShellRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	meta := r.Metadata()
+	byStage := make(map[int][]pipeToShellFinding)
+	var stageOrder []int
+	for _, f := range r.findings(input) {
+		if _, seen := byStage[f.stageIdx]; !seen {
+			stageOrder = append(stageOrder, f.stageIdx)
+		}
+		byStage[f.stageIdx] = append(byStage[f.stageIdx], f)
+	}
+
+	var requests []async.CheckRequest
+	for _, stageIdx := range stageOrder {
+		findings := byStage[stageIdx]
+		var urls []string
+		for _, f := range findings {
+			if f.fixable() && !slices.Contains(urls, f.pipe.URLs[0]) {
+				urls = append(urls, f.pipe.URLs[0])
+			}
+		}
+		if len(urls) == 0 {
+			continue
+		}
+		requests = append(requests, async.CheckRequest{
+			RuleCode:   meta.Code,
+			Category:   async.CategoryNetwork,
+			Key:        strings.Join(urls, "\n"),
+			ResolverID: download.ResolverID,
+			Data:       &download.Request{URLs: urls},
+			File:       input.File,
+			StageIndex: stageIdx,
+			Handler:    &pipeToShellHandler{meta: meta, input: input, findings: findings},
+		})
+	}
+	return requests
+}
+
+// findings returns the downloads piped into a shell, in file order, leaving
+// out those from allowed hosts. Stages with a non-POSIX shell and exec-form
+// RUNs are skipped.
+func (r *NoPipeToShellRule) findings(input rules.LintInput) []pipeToShellFinding {
+	cfg := r.resolveConfig(input.Config)
+	sm := input.SourceMap()
+	escapeToken := dockerfile.ASTEscapeToken(input.AST)
+
+	var out []pipeToShellFinding
+	for stageIdx, stage := range input.Stages {
+		variant := shell.VariantBash
+		if input.Semantic != nil {
+			if info := input.Semantic.StageInfo(stageIdx); info != nil {
+				variant = info.ShellSetting.Variant
+			}
+		}
+		if !variant.SupportsPOSIXShellAST() {
+			continue
+		}
+
+		for _, cmd := range stage.Commands {
+			run, ok := cmd.(*instructions.RunCommand)
+			if !ok || !run.PrependShell {
+				continue
+			}
+			var (
+				pipes     []shell.PipeToShell
+				startLine int
+			)
+			switch {
+			case len(run.Files) > 0:
+				for _, f := range run.Files {
+					pipes = append(pipes, shell.FindPipeToShell(f.Data, variant)...)
+				}
+			case sm != nil:
+				script, line := dockerfile.RunSourceScript(run, sm, escapeToken)
+				pipes, startLine = shell.FindPipeToShell(script, variant), line
+			default:
+				pipes = shell.FindPipeToShell(dockerfile.RunCommandString(run), variant)
+			}
+			for _, p := range pipes {
+				if allowedPipeHosts(p.URLs, cfg.AllowedHosts) {
+					continue
+				}
+				out = append(out, pipeToShellFinding{run: run, stageIdx: stageIdx, pipe: p, startLine: startLine})
+			}
+		}
+	}
+	return out
+}
+
+// allowedPipeHosts reports whether every URL is on an allowed host. URLs
+// whose host needs expansion are never allowed.
+func allowedPipeHosts(urls, allowed []string) bool {
+	if len(urls) == 0 || len(allowed) == 0 {
+		return false
+	}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" || strings.Contains(u.Host, "$") {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		if !slices.ContainsFunc(allowed, func(pattern string) bool {
+			pattern = strings.ToLower(pattern)
+			if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasPrefix(suffix, ".") {
+				return strings.HasSuffix(host, suffix)
+			}
+			return host == pattern
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func pipeToShellViolation(meta rules.RuleMetadata, file string, f pipeToShellFinding) rules.Violation {
+	loc := rules.NewLocationFromRanges(file, f.run.Location())
+	if f.startLine > 0 {
+		p := f.pipe
+		loc = rules.NewRangeLocation(file, f.startLine+p.StartLine, p.StartCol, f.startLine+p.EndLine, p.EndCol)
+	}
+	msg := fmt.Sprintf("%s output is piped into %s, which runs the download unverified", f.pipe.Downloader, f.pipe.Shell)
+	v := rules.NewViolation(loc, meta.Code, msg, meta.DefaultSeverity).
+		WithDocURL(meta.DocURL).
+		WithDetail("The build runs whatever the server returns, so a compromised, changed or truncated script " +
+			"goes unnoticed. Download the script with ADD --checksum and run the verified file; with slow checks " +
+			"enabled, tally computes the checksum. Trusted hosts can be listed in allowed-hosts.")
+	if len(f.pipe.URLs) == 1 {
+		v = v.WithToken(f.pipe.URLs[0])
+	}
+	v.StageIndex = f.stageIdx
+	return v
+}
+
+// pipeToShellHandler re-reports a stage's pipe-to-shell findings with a fix
+// for each one whose checksum was computed.
+type pipeToShellHandler struct {
+	meta     rules.RuleMetadata
+	input    rules.LintInput
+	findings []pipeToShellFinding
+}
+
+func (h *pipeToShellHandler) OnSuccess(resolved any) []any {
+	sums, ok := resolved.(download.Checksums)
+	if !ok {
+		return nil
+	}
+	out := make([]any, 0, len(h.findings))
+	for _, f := range h.findings {
+		v := pipeToShellViolation(h.meta, h.input.File, f)
+		if f.fixable() {
+			if sum := sums[f.pipe.URLs[0]]; sum != "" {
+				if fix := pipeToShellFix(h.input, f, sum); fix != nil {
+					v = v.WithSuggestedFix(fix).WithFixKind("add-checksum")
+				}
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// pipeToShellFix inserts an ADD --checksum that downloads the script to
+// /tmp before the RUN, and replaces the pipeline with a command running the
+// downloaded file.
+func pipeToShellFix(input rules.LintInput, f pipeToShellFinding, sum string) *rules.SuggestedFix {
+	sm := input.SourceMap()
+	if sm == nil || f.startLine < 1 || f.startLine > sm.LineCount() {
+		return nil
+	}
+	rawURL := f.pipe.URLs[0]
+	name := pipeScriptName(rawURL)
+	dest := "/tmp/" + name
+	runCmd, ok := f.pipe.FileCommand(dest)
+	if !ok {
+		return nil
+	}
+
+	runLine := sm.Line(f.startLine - 1)
+	indent := runLine[:len(runLine)-len(strings.TrimLeft(runLine, " \t"))]
+	add := fmt.Sprintf("%sADD --checksum=sha256:%s %s %s\n", indent, sum, rawURL, dest)
+	p := f.pipe
+	return &rules.SuggestedFix{
+		Description: fmt.Sprintf("Download %s with ADD --checksum=sha256:%s and run the verified file", name, shortDigest(sum)),
+		Safety:      rules.FixSuggestion,
+		Edits: []rules.TextEdit{
+			{
+				Location: rules.NewRangeLocation(input.File, f.startLine, 0, f.startLine, 0),
+				NewText:  add,
+			},
+			{
+				Location: rules.NewRangeLocation(input.File,
+					f.startLine+p.StartLine, p.StartCol, f.startLine+p.EndLine, p.EndCol),
+				NewText: runCmd,
+			},
+		},
+	}
+}
+
+// pipeScriptName returns the file name to save a downloaded script as: the
+// last path segment of the URL, or install.sh when it has none.
+func pipeScriptName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "install.sh"
+	}
+	name := path.Base(u.Path)
+	if name == "" || name == "." || name == "/" || strings.ContainsAny(name, " '\"$\\") {
+		return "install.sh"
+	}
+	return name
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewNoPipeToShellRule())
+}
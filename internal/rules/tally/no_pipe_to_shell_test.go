@@ -0,0 +1,170 @@
+package tally
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/async/download"
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestNoPipeToShellMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewNoPipeToShellRule().Metadata())
+}
+
+func TestNoPipeToShellRule(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewNoPipeToShellRule(), []testutil.RuleTestCase{
+		{
+			Name: "curl into sh",
+			Content: `FROM alpine:3.20
+RUN curl -fsSL https://get.example.com/install.sh | sh
+`,
+			WantViolations: 1,
+			WantCodes:      []string{NoPipeToShellRuleCode},
+			WantMessages:   []string{"curl output is piped into sh, which runs the download unverified"},
+		},
+		{
+			Name: "wget into sudo bash in a chain",
+			Content: `FROM ubuntu:24.04
+RUN apt-get update && wget -qO- https://deb.example.com/setup | sudo -E bash - && apt-get install -y tool
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"wget output is piped into bash"},
+		},
+		{
+			Name: "heredoc script",
+			Content: `FROM alpine:3.20
+RUN <<EOF
+set -e
+curl -sSf https://sh.rustup.rs | sh -s -- -y
+EOF
+`,
+			WantViolations: 1,
+		},
+		{
+			Name: "download saved to a file",
+			Content: `FROM alpine:3.20
+RUN curl -fsSLo /tmp/install.sh https://get.example.com/install.sh && sh /tmp/install.sh
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "download piped into another tool",
+			Content: `FROM debian:bookworm
+RUN curl -fsSL https://example.com/key.gpg | gpg --dearmor -o /usr/share/keyrings/example.gpg
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "allowed host",
+			Content: `FROM alpine:3.20
+RUN curl -fsSL https://get.example.com/install.sh | sh
+RUN curl -fsSL https://tools.internal.example.org/i.sh | sh
+RUN curl -fsSL https://$MIRROR/i.sh | sh
+`,
+			Config:         NoPipeToShellConfig{AllowedHosts: []string{"GET.example.com", "*.example.org"}},
+			WantViolations: 1,
+			WantMessages:   []string{"curl output is piped into sh"},
+		},
+		{
+			Name: "windows stage",
+			Content: `FROM mcr.microsoft.com/windows/servercore:ltsc2022
+SHELL ["powershell", "-Command"]
+RUN curl https://example.com/i.ps1 | sh
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestNoPipeToShellPlanAsync(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20 AS tools
+ARG VERSION=1.0
+RUN curl -fsSL "https://example.com/v${VERSION}/install.sh" | sh
+
+FROM alpine:3.20
+RUN curl -fsSL https://get.example.com/install.sh | sh -s -- --yes
+RUN <<EOF
+curl -fsSL https://get.example.com/other.sh | sh
+EOF
+`
+	reqs := NewNoPipeToShellRule().PlanAsync(testutil.MakeLintInput(t, "Dockerfile", content))
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1 for the stage with a fixable download", len(reqs))
+	}
+	data, ok := reqs[0].Data.(*download.Request)
+	if !ok || reqs[0].ResolverID != download.ResolverID || reqs[0].StageIndex != 1 ||
+		len(data.URLs) != 1 || data.URLs[0] != "https://get.example.com/install.sh" {
+		t.Errorf("request = %+v", reqs[0])
+	}
+}
+
+func TestNoPipeToShellHandler(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20
+WORKDIR /app
+RUN apk add --no-cache curl \
+    && curl -fsSL https://sh.rustup.rs | sh -s -- -y --profile minimal \
+    && rustc --version
+RUN <<EOF
+curl -fsSL https://get.example.com/other.sh | sh
+EOF
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	reqs := NewNoPipeToShellRule().PlanAsync(input)
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	handler := reqs[0].Handler
+	if out := handler.OnSuccess("nope"); out != nil {
+		t.Errorf("wrong type: got %v, want nil", out)
+	}
+
+	out := handler.OnSuccess(download.Checksums{"https://sh.rustup.rs": testChecksum})
+	if len(out) != 2 {
+		t.Fatalf("got %d results, want both violations of the stage", len(out))
+	}
+	violations := make([]rules.Violation, 0, len(out))
+	for _, o := range out {
+		v, ok := o.(rules.Violation)
+		if !ok {
+			t.Fatalf("result %T is not a violation", o)
+		}
+		violations = append(violations, v)
+	}
+	if violations[1].SuggestedFix != nil {
+		t.Errorf("heredoc download should have no fix: %+v", violations[1].SuggestedFix)
+	}
+	if fix := violations[0].SuggestedFix; fix == nil || fix.Safety != rules.FixSuggestion {
+		t.Fatalf("want a suggestion fix, got %+v", fix)
+	}
+
+	result, err := (&fixpkg.Fixer{SafetyThreshold: rules.FixSuggestion}).Apply(
+		context.Background(),
+		violations,
+		map[string][]byte{"Dockerfile": []byte(content)},
+	)
+	if err != nil {
+		t.Fatalf("apply fixes: %v", err)
+	}
+	want := `FROM alpine:3.20
+WORKDIR /app
+ADD --checksum=sha256:` + testChecksum + ` https://sh.rustup.rs /tmp/install.sh
+RUN apk add --no-cache curl \
+    && sh /tmp/install.sh -y --profile minimal \
+    && rustc --version
+RUN <<EOF
+curl -fsSL https://get.example.com/other.sh | sh
+EOF
+`
+	if got := string(result.Changes["Dockerfile"].ModifiedContent); got != want {
+		t.Errorf("fixed content =\n%s\nwant:\n%s", got, want)
+	}
+}
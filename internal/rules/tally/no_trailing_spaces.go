@@ -55,6 +55,7 @@ func (r *NoTrailingSpacesRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     10,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
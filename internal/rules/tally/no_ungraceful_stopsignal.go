@@ -33,6 +33,7 @@ func (r *NoUngracefulStopsignalRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(NoUngracefulStopsignalRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -32,6 +32,7 @@ func (r *ComposerNoDevInProductionRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
 		FixPriority:     88,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -43,6 +43,7 @@ func (r *EnableOpcacheInProductionRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		FixPriority:     88, //nolint:mnd // stable priority contract, consistent with companion PHP rules
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -36,6 +36,7 @@ func (r *NoXdebugInFinalImageRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "best-practices",
 		FixPriority:     88, //nolint:mnd // stable priority contract, consistent with companion PHP rules
+		Fixes:           []rules.FixSafety{rules.FixSuggestion, rules.FixUnsafe},
 	}
 }
 
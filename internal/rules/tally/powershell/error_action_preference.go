@@ -54,6 +54,7 @@ func (r *ErrorActionPreferenceRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     96, //nolint:mnd // After prefer-shell-instruction (95), before heredoc (100).
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
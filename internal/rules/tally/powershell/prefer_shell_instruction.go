@@ -85,6 +85,7 @@ func (r *PreferShellInstructionRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  true,
 		FixPriority:     95,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -37,6 +37,7 @@ func (r *ProgressPreferenceRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityStyle,
 		Category:        "style",
 		FixPriority:     97, //nolint:mnd // After error-action-preference (96), before prefer-run-heredoc (100).
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -38,6 +38,7 @@ func (r *PreferAddGitRule) Metadata() rules.RuleMetadata {
 		Category:        "security",
 		IsExperimental:  false,
 		FixPriority:     8,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
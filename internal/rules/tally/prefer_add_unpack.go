@@ -35,8 +35,9 @@ func DefaultPreferAddUnpackConfig() PreferAddUnpackConfig {
 }
 
 // PreferAddUnpackRule flags RUN commands that download and extract remote
-// archives (via curl/wget piped to tar, or downloaded then extracted),
-// suggesting `ADD --unpack <url> <dest>` instead.
+// archives (via curl/wget piped to tar, or downloaded then extracted, in one
+// RUN or across consecutive RUNs), suggesting `ADD --unpack <url> <dest>`
+// instead.
 //
 // ADD --unpack is a BuildKit feature that downloads and extracts a remote
 // archive in a single layer, reducing image size and build complexity.
@@ -64,6 +65,9 @@ func (r *PreferAddUnpackRule) Metadata() rules.RuleMetadata {
 		Category:        "performance",
 		IsExperimental:  false,
 		FixPriority:     95,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		// The wget progress bar is moot once ADD downloads the archive.
+		Supersedes: []string{rules.HadolintRulePrefix + "DL3047"},
 	}
 }
 
@@ -92,6 +96,7 @@ func (r *PreferAddUnpackRule) Check(input rules.LintInput) []rules.Violation {
 	meta := r.Metadata()
 
 	var sem = input.Semantic
+	sm := input.SourceMap()
 
 	var violations []rules.Violation
 
@@ -111,8 +116,18 @@ func (r *PreferAddUnpackRule) Check(input rules.LintInput) []rules.Violation {
 		// Docker default is "/" when no WORKDIR is set.
 		workdir := "/"
 
+		// Download, extract, and delete steps spread over consecutive RUNs.
+		var seq archiveSequence
+		flushSequence := func() {
+			if v := seq.violation(input.File, sm, meta); v != nil {
+				violations = append(violations, *v)
+			}
+			seq = archiveSequence{}
+		}
+
 		for _, cmd := range stage.Commands {
 			if wd, ok := cmd.(*instructions.WorkdirCommand); ok {
+				flushSequence()
 				normalized, err := system.NormalizeWorkdir(workdir, wd.Path, platformOS)
 				if err == nil {
 					workdir = normalized
@@ -122,6 +137,15 @@ func (r *PreferAddUnpackRule) Check(input rules.LintInput) []rules.Violation {
 
 			run, ok := cmd.(*instructions.RunCommand)
 			if !ok {
+				flushSequence()
+				continue
+			}
+
+			if seq.extend(run, shellVariant, workdir) {
+				continue
+			}
+			flushSequence()
+			if seq.start(run, shellVariant) {
 				continue
 			}
 
@@ -145,6 +169,7 @@ func (r *PreferAddUnpackRule) Check(input rules.LintInput) []rules.Violation {
 				violations = append(violations, v)
 			}
 		}
+		flushSequence()
 	}
 
 	return violations
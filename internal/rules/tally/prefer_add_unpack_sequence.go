@@ -0,0 +1,251 @@
+package tally
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/runmount"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// archiveSequence tracks a remote archive that is downloaded, extracted, and
+// optionally deleted by consecutive RUN instructions:
+//
+//	RUN wget https://example.com/app.tar.gz
+//	RUN tar -xzf app.tar.gz -C /opt
+//	RUN rm app.tar.gz
+//
+// Like the consecutive-RUN check of prefer-copy-heredoc, any instruction
+// other than RUN ends the sequence.
+type archiveSequence struct {
+	runs    []*instructions.RunCommand
+	url     string
+	archive string // downloaded file, as named by the download command
+	dest    string // extraction directory; empty until the extract step is seen
+	deleted bool
+}
+
+// start begins a new sequence when the RUN only downloads an archive to a
+// file. It returns false when the RUN is not a download step.
+func (s *archiveSequence) start(run *instructions.RunCommand, variant shell.Variant) bool {
+	if !isPlainShellRun(run) {
+		return false
+	}
+	archiveURL, archive, ok := archiveDownloadStep(dockerfile.RunCommandString(run), variant)
+	if !ok {
+		return false
+	}
+	*s = archiveSequence{runs: []*instructions.RunCommand{run}, url: archiveURL, archive: archive}
+	return true
+}
+
+// extend adds the RUN to the sequence when it is the next step: extracting
+// the archive (possibly deleting it too), or deleting it after extraction.
+// It returns false when the RUN does not continue the sequence.
+func (s *archiveSequence) extend(run *instructions.RunCommand, variant shell.Variant, workdir string) bool {
+	if len(s.runs) == 0 || s.deleted || !isPlainShellRun(run) {
+		return false
+	}
+	cmdStr := dockerfile.RunCommandString(run)
+
+	if s.dest == "" {
+		dest, deleted, ok := archiveExtractStep(cmdStr, variant, s.archive, workdir)
+		if !ok {
+			return false
+		}
+		s.runs = append(s.runs, run)
+		s.dest, s.deleted = dest, deleted
+		return true
+	}
+
+	if !archiveDeleteStep(cmdStr, variant, s.archive) {
+		return false
+	}
+	s.runs = append(s.runs, run)
+	s.deleted = true
+	return true
+}
+
+// violation reports the sequence once it has reached the extract step.
+// The fix replaces all of its RUN instructions with a single ADD --unpack.
+func (s *archiveSequence) violation(
+	file string,
+	sm *sourcemap.SourceMap,
+	meta rules.RuleMetadata,
+) *rules.Violation {
+	if s.dest == "" {
+		return nil
+	}
+
+	firstLoc := s.runs[0].Location()
+	lastRun := s.runs[len(s.runs)-1]
+	lastLoc := lastRun.Location()
+	if len(firstLoc) == 0 || len(lastLoc) == 0 {
+		return nil
+	}
+
+	addCmd := "ADD --unpack " + s.url + " " + s.dest
+	v := rules.NewViolation(
+		rules.NewLocationFromRanges(file, firstLoc),
+		meta.Code,
+		"use `ADD --unpack <url> <dest>` instead of downloading and extracting across RUN instructions",
+		meta.DefaultSeverity,
+	).WithDetail(fmt.Sprintf(
+		"%d consecutive RUN instructions download and extract %s. "+
+			"`ADD --unpack` does both in a single layer without keeping the archive in an earlier layer. "+
+			"When the archive needs more processing than ADD --unpack offers, download and extract it "+
+			"in a separate build stage and COPY the result instead.",
+		len(s.runs), s.url,
+	))
+
+	endLine, endCol := resolveRunEndPosition(lastLoc, sm, lastRun)
+	v = v.WithSuggestedFix(&rules.SuggestedFix{
+		Description: fmt.Sprintf("Replace %d RUNs with %s", len(s.runs), addCmd),
+		Safety:      rules.FixSuggestion,
+		Priority:    meta.FixPriority,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(
+				file, firstLoc[0].Start.Line, firstLoc[0].Start.Character, endLine, endCol,
+			),
+			NewText: addCmd,
+		}},
+	})
+	return &v
+}
+
+// isPlainShellRun reports whether the RUN is a shell-form RUN without mounts.
+// Files in a mount are not kept between instructions, and ADD cannot use
+// secrets or SSH agents for the download.
+func isPlainShellRun(run *instructions.RunCommand) bool {
+	return run.PrependShell && len(run.Files) == 0 && len(runmount.GetMounts(run)) == 0
+}
+
+// archiveDownloadStep reports whether a RUN only downloads a single archive
+// to a file, returning the URL and the file name.
+func archiveDownloadStep(cmdStr string, variant shell.Variant) (string, string, bool) {
+	if !variant.SupportsPOSIXShellAST() {
+		return "", "", false
+	}
+	for _, name := range shell.CommandNamesWithVariant(cmdStr, variant) {
+		if name != nonPOSIXDownloadCommandCurl && name != nonPOSIXDownloadCommandWget {
+			return "", "", false
+		}
+	}
+
+	dlCmds := shell.FindCommands(cmdStr, variant, shell.DownloadCommands...)
+	if len(dlCmds) != 1 {
+		return "", "", false
+	}
+	archiveURL := findArchiveURL(dlCmds)
+	if archiveURL == "" {
+		return "", "", false
+	}
+
+	dl := &dlCmds[0]
+	archive := shell.DownloadOutputFile(dl)
+	if archive == "" && dl.Name == nonPOSIXDownloadCommandWget && wgetSavesToURLName(dl) {
+		// wget without -O saves to the URL's file name in the working directory.
+		if u, err := url.Parse(archiveURL); err == nil && shell.IsArchiveFilename(path.Base(u.Path)) {
+			archive = path.Base(u.Path)
+		}
+	}
+	if archive == "" {
+		return "", "", false
+	}
+	return archiveURL, shell.DropQuotes(archive), true
+}
+
+// wgetSavesToURLName reports whether wget keeps its default output file:
+// no -O/--output-document, -P/--directory-prefix, or --content-disposition.
+func wgetSavesToURLName(dl *shell.CommandInfo) bool {
+	return !slices.ContainsFunc(dl.Args, func(arg string) bool {
+		if strings.HasPrefix(arg, "--") {
+			return strings.HasPrefix(arg, "--output-document") ||
+				strings.HasPrefix(arg, "--directory-prefix") ||
+				strings.HasPrefix(arg, "--content-disposition")
+		}
+		return strings.HasPrefix(arg, "-") && strings.ContainsAny(arg, "OP")
+	})
+}
+
+// archiveExtractStep reports whether a RUN extracts the downloaded archive
+// with a single tar command that ADD --unpack can replace, optionally
+// deleting it afterwards. It returns the extraction directory and whether
+// the archive is deleted too.
+func archiveExtractStep(cmdStr string, variant shell.Variant, archive, workdir string) (string, bool, bool) {
+	for _, name := range shell.CommandNamesWithVariant(cmdStr, variant) {
+		if name != "tar" && name != "rm" {
+			return "", false, false
+		}
+	}
+
+	extractTar := findSingleExtractTar(cmdStr, variant)
+	if extractTar == nil || hasTarSemanticFlags(extractTar) ||
+		!slices.ContainsFunc(extractTar.Args, func(arg string) bool { return isArchiveArg(arg, archive) }) {
+		return "", false, false
+	}
+
+	rmCmds := shell.FindCommands(cmdStr, variant, "rm")
+	for i := range rmCmds {
+		if !removesOnlyArchive(&rmCmds[i], archive) {
+			return "", false, false
+		}
+	}
+
+	// Default to the effective WORKDIR; tar without -C extracts into cwd.
+	dest := workdir
+	if d := shell.TarDestination(extractTar); d != "" {
+		dest = d
+	}
+	return dest, len(rmCmds) > 0, true
+}
+
+// archiveDeleteStep reports whether a RUN only deletes the downloaded archive.
+func archiveDeleteStep(cmdStr string, variant shell.Variant, archive string) bool {
+	for _, name := range shell.CommandNamesWithVariant(cmdStr, variant) {
+		if name != "rm" {
+			return false
+		}
+	}
+	rmCmds := shell.FindCommands(cmdStr, variant, "rm")
+	if len(rmCmds) == 0 {
+		return false
+	}
+	for i := range rmCmds {
+		if !removesOnlyArchive(&rmCmds[i], archive) {
+			return false
+		}
+	}
+	return true
+}
+
+// removesOnlyArchive reports whether an rm command deletes the archive and
+// nothing else.
+func removesOnlyArchive(rm *shell.CommandInfo, archive string) bool {
+	operands := 0
+	for _, arg := range rm.Args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if !isArchiveArg(arg, archive) {
+			return false
+		}
+		operands++
+	}
+	return operands > 0
+}
+
+// isArchiveArg reports whether a command argument names the archive, by full
+// path or basename (matching the single-RUN check in extractFixData).
+func isArchiveArg(arg, archive string) bool {
+	arg = shell.DropQuotes(arg)
+	return arg == archive || arg == shell.Basename(archive)
+}
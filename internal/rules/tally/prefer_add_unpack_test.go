@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/testutil"
 )
@@ -545,3 +547,149 @@ RUN curl.exe -fsSL https://example.com/app.tar.gz -o C:\tmp\app.tar.gz && tar.ex
 		})
 	}
 }
+
+func TestPreferAddUnpackRule_ConsecutiveRuns(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantCount  int
+		want       string // result of applying the first violation's fix; empty when no fix
+	}{
+		{
+			name: "download, extract, delete",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget https://example.com/app.tar.gz
+RUN tar -xzf app.tar.gz -C /opt
+RUN rm app.tar.gz
+ENV PATH=/opt/app/bin:$PATH
+`,
+			wantCount: 1,
+			want: `FROM ubuntu:22.04
+ADD --unpack https://example.com/app.tar.gz /opt
+ENV PATH=/opt/app/bin:$PATH
+`,
+		},
+		{
+			name: "extract and delete in one RUN",
+			dockerfile: `FROM ubuntu:22.04
+WORKDIR /srv
+RUN curl -fsSL -o /tmp/app.tgz https://example.com/app.tgz
+RUN tar -xzf /tmp/app.tgz && \
+    rm -f /tmp/app.tgz
+`,
+			wantCount: 1,
+			want: `FROM ubuntu:22.04
+WORKDIR /srv
+ADD --unpack https://example.com/app.tgz /srv
+`,
+		},
+		{
+			name: "download and extract without delete",
+			dockerfile: `FROM ubuntu:22.04
+RUN curl -o /tmp/app.tar.gz https://example.com/latest
+RUN tar -xf /tmp/app.tar.gz -C /usr/local
+RUN echo done
+`,
+			wantCount: 1,
+			want: `FROM ubuntu:22.04
+ADD --unpack https://example.com/latest /usr/local
+RUN echo done
+`,
+		},
+		{
+			name: "download only",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget https://example.com/app.tar.gz
+`,
+			wantCount: 0,
+		},
+		{
+			name: "WORKDIR between steps breaks the sequence",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget https://example.com/app.tar.gz
+WORKDIR /opt
+RUN tar -xzf /app.tar.gz
+`,
+			wantCount: 0,
+		},
+		{
+			name: "extract of another file",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget -O /tmp/app.tar.gz https://example.com/app.tar.gz
+RUN tar -xzf /tmp/other.tar.gz -C /opt
+`,
+			wantCount: 0,
+		},
+		{
+			name: "wget to stdout",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget -qO- https://example.com/app.tar.gz
+RUN tar -xzf app.tar.gz -C /opt
+`,
+			wantCount: 0,
+		},
+		{
+			name: "strip-components cannot be replicated",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget https://example.com/app.tar.gz
+RUN tar -xzf app.tar.gz -C /opt --strip-components=1
+`,
+			wantCount: 0,
+		},
+		{
+			name: "extract RUN does more work",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget https://example.com/app.tar.gz
+RUN tar -xzf app.tar.gz -C /opt && chmod +x /opt/app/bin/start
+`,
+			wantCount: 0,
+		},
+		{
+			name: "delete removes more than the archive",
+			dockerfile: `FROM ubuntu:22.04
+RUN wget https://example.com/app.tar.gz
+RUN tar -xzf app.tar.gz -C /opt
+RUN rm -rf app.tar.gz /var/cache/apt
+`,
+			wantCount: 1,
+			want: `FROM ubuntu:22.04
+ADD --unpack https://example.com/app.tar.gz /opt
+RUN rm -rf app.tar.gz /var/cache/apt
+`,
+		},
+		{
+			name: "download with a mount",
+			dockerfile: `FROM ubuntu:22.04
+RUN --mount=type=secret,id=netrc wget https://example.com/app.tar.gz
+RUN tar -xzf app.tar.gz -C /opt
+`,
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.dockerfile)
+			violations := NewPreferAddUnpackRule().Check(input)
+
+			if len(violations) != tt.wantCount {
+				t.Fatalf("got %d violations, want %d", len(violations), tt.wantCount)
+			}
+			if tt.want == "" {
+				return
+			}
+			fix := violations[0].SuggestedFix
+			if fix == nil {
+				t.Fatal("expected SuggestedFix, got nil")
+			}
+			if fix.Safety != rules.FixSuggestion {
+				t.Errorf("Safety = %v, want FixSuggestion", fix.Safety)
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.dockerfile), fix)); got != tt.want {
+				t.Errorf("fixed Dockerfile:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ func (r *PreferCanonicalStopsignalRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(PreferCanonicalStopsignalRuleCode),
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "style",
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
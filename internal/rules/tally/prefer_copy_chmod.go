@@ -30,6 +30,7 @@ func (r *PreferCopyChmodRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "style",
 		FixPriority:     99, // Match prefer-copy-heredoc to avoid cross-priority line drift
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -244,27 +245,31 @@ func (r *PreferCopyChmodRule) buildFix(
 	}
 
 	// Edit 2: Delete the entire RUN chmod line(s).
+	edit2 := deleteRunEdit(runCmd, file, sm)
+
+	return &rules.SuggestedFix{
+		Description: "Merge COPY + RUN chmod into COPY --chmod=" + finalMode,
+		Safety:      rules.FixSafe,
+		Priority:    meta.FixPriority,
+		Edits:       []rules.TextEdit{edit1, edit2},
+	}
+}
+
+// deleteRunEdit returns an edit removing the whole lines of a RUN
+// instruction, including its line continuations.
+func deleteRunEdit(runCmd *instructions.RunCommand, file string, sm *sourcemap.SourceMap) rules.TextEdit {
+	runLoc := runCmd.Location()
 	runStartLine := runLoc[0].Start.Line
 	runEndLine, runEndCol := resolveRunEndPosition(runLoc, sm, runCmd)
-
-	var edit2 rules.TextEdit
 	if sm != nil && runEndLine >= sm.LineCount() {
-		edit2 = rules.TextEdit{
+		return rules.TextEdit{
 			Location: rules.NewRangeLocation(file, runStartLine, 0, runEndLine, runEndCol),
 			NewText:  "",
 		}
-	} else {
-		edit2 = rules.TextEdit{
-			Location: rules.NewRangeLocation(file, runStartLine, 0, runEndLine+1, 0),
-			NewText:  "",
-		}
 	}
-
-	return &rules.SuggestedFix{
-		Description: "Merge COPY + RUN chmod into COPY --chmod=" + finalMode,
-		Safety:      rules.FixSafe,
-		Priority:    meta.FixPriority,
-		Edits:       []rules.TextEdit{edit1, edit2},
+	return rules.TextEdit{
+		Location: rules.NewRangeLocation(file, runStartLine, 0, runEndLine+1, 0),
+		NewText:  "",
 	}
 }
 
@@ -9,7 +9,9 @@ import (
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
+	"github.com/wharflab/tally/internal/dockerfile"
 	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/printer"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/rules/configutil"
 	"github.com/wharflab/tally/internal/runmount"
@@ -68,6 +70,7 @@ func (r *PreferCopyHeredocRule) Metadata() rules.RuleMetadata {
 		Category:        "performance",
 		IsExperimental:  false,
 		FixPriority:     99, // Run before prefer-run-heredoc (100)
+		Fixes:           []rules.FixSafety{rules.FixSuggestion, rules.FixUnsafe},
 	}
 }
 
@@ -86,6 +89,7 @@ func (r *PreferCopyHeredocRule) Check(input rules.LintInput) []rules.Violation {
 	var violations []rules.Violation
 	meta := r.Metadata()
 	sm := input.SourceMap()
+	escapeToken := dockerfile.ASTEscapeToken(input.AST)
 	fileFacts := input.Facts
 
 	// Get semantic model for shell variant and variable info
@@ -117,6 +121,7 @@ func (r *PreferCopyHeredocRule) Check(input rules.LintInput) []rules.Violation {
 			knownVars:       knownVars,
 			file:            input.File,
 			sm:              sm,
+			escapeToken:     escapeToken,
 			meta:            meta,
 		}
 
@@ -161,6 +166,7 @@ type copyHeredocCheckContext struct {
 	knownVars       func(string) bool
 	file            string
 	sm              *sourcemap.SourceMap
+	escapeToken     rune
 	meta            rules.RuleMetadata
 }
 
@@ -312,7 +318,7 @@ func (r *PreferCopyHeredocRule) checkSingleRuns(
 			}
 
 			// Create violation
-			loc := rules.NewLocationFromRanges(ctx.file, c.Location())
+			loc := redirectTargetLocation(c, info.TargetPath, shellCtx.variant, ctx)
 
 			v := rules.NewViolation(
 				loc,
@@ -335,6 +341,36 @@ func (r *PreferCopyHeredocRule) checkSingleRuns(
 	return violations
 }
 
+// redirectTargetLocation points a single-RUN violation at the redirect target
+// that creates targetPath (the "/etc/app.conf" in "echo x > /etc/app.conf").
+// It falls back to the whole instruction when the target cannot be located in
+// the source, e.g. for tee or a resolved "~" path.
+func redirectTargetLocation(
+	run *instructions.RunCommand,
+	targetPath string,
+	variant shell.Variant,
+	ctx copyHeredocCheckContext,
+) rules.Location {
+	fallback := rules.NewLocationFromRanges(ctx.file, run.Location())
+
+	script, startLine := dockerfile.RunSourceScript(run, ctx.sm, ctx.escapeToken)
+	if script == "" {
+		return fallback
+	}
+	targets := shell.FindRedirectTargets(script, variant)
+	for i := len(targets) - 1; i >= 0; i-- {
+		t := targets[i]
+		if t.Path != targetPath {
+			continue
+		}
+		if span, ok := ctx.sm.TokenSpan(startLine-1+t.Line, t.StartCol, t.Path); ok {
+			return rules.NewLocationFromSpan(ctx.file, span)
+		}
+		break
+	}
+	return fallback
+}
+
 // checkConsecutiveRuns checks for sequences of RUN instructions that write to the same file.
 func (r *PreferCopyHeredocRule) checkConsecutiveRuns(ctx copyHeredocCheckContext) []rules.Violation {
 	var violations []rules.Violation
@@ -557,10 +593,14 @@ func (r *PreferCopyHeredocRule) multiTargetViolation(
 	return v, true
 }
 
-// runPrefixToken is the literal "RUN " prefix used when emitting shell-form
-// RUN instructions in generated fixes. Declared once so lints like goconst
-// don't nudge us per call site.
-const runPrefixToken = "RUN "
+// printRun prints a shell-form RUN that keeps the original RUN's mounts.
+func printRun(mountFlags, script string) string {
+	return printer.Instruction{
+		Keyword: "RUN",
+		Flags:   []string{mountFlags},
+		Args:    script,
+	}.String()
+}
 
 // buildMultiFixParts walks the multi-target analysis and produces the ordered
 // sequence of fix parts: COPY heredocs for each distinct target, interleaved
@@ -578,11 +618,6 @@ func buildMultiFixParts(
 	}
 
 	mountFlags := runmount.FormatMounts(runmount.GetMounts(run))
-	runPrefix := runPrefixToken
-	if mountFlags != "" {
-		runPrefix = runPrefixToken + mountFlags + " "
-	}
-
 	chownUser := chownUserForCopy(effectiveUser)
 
 	var parts []string
@@ -602,7 +637,7 @@ func buildMultiFixParts(
 		// (`set -ex`, `shopt`) — these don't cross RUN boundaries, so
 		// preserving them after extraction is pure noise.
 		if !pendingAllStateOnly {
-			parts = append(parts, runPrefix+strings.Join(pendingRun, " && "))
+			parts = append(parts, printRun(mountFlags, strings.Join(pendingRun, " && ")))
 		}
 		pendingRun = pendingRun[:0]
 		pendingAllStateOnly = true
@@ -789,16 +824,12 @@ func (r *PreferCopyHeredocRule) generateFix(
 
 	// Get mount flags to preserve on remaining RUN commands
 	mountFlags := runmount.FormatMounts(runmount.GetMounts(run))
-	runPrefix := runPrefixToken
-	if mountFlags != "" {
-		runPrefix = runPrefixToken + mountFlags + " "
-	}
 
 	// Add preceding commands as RUN if any (preserve mounts). The shell
 	// layer already drops chains that would be pure shell-state noise
 	// (`set -ex`, `shopt`, `trap`) — options don't cross RUN boundaries.
 	if info.PrecedingCommands != "" {
-		parts = append(parts, runPrefix+info.PrecedingCommands)
+		parts = append(parts, printRun(mountFlags, info.PrecedingCommands))
 	}
 
 	// Add COPY heredoc for the file creation
@@ -808,7 +839,7 @@ func (r *PreferCopyHeredocRule) generateFix(
 
 	// Add remaining commands as RUN if any (preserve mounts)
 	if info.RemainingCommands != "" {
-		parts = append(parts, runPrefix+info.RemainingCommands)
+		parts = append(parts, printRun(mountFlags, info.RemainingCommands))
 	}
 
 	endLine, endCol := resolveRunEndPosition(runLoc, sm, run)
@@ -935,41 +966,19 @@ func (r *PreferCopyHeredocRule) generateSequenceFix(
 // rawChmodMode is the original mode notation (e.g. "+x", "755"); used directly since
 // COPY --chmod supports both octal and symbolic modes (Dockerfile 1.14+).
 func buildCopyHeredoc(targetPath, content, rawChmodMode, chownUser string) string {
-	var sb strings.Builder
-	sb.WriteString("COPY ")
-
+	body := printer.NewHeredoc(content)
+	inst := printer.Instruction{
+		Keyword:  "COPY",
+		Args:     body.Marker() + " " + targetPath,
+		Heredocs: []printer.Heredoc{body},
+	}
 	if chownUser != "" {
-		sb.WriteString("--chown=")
-		sb.WriteString(chownUser)
-		sb.WriteString(" ")
+		inst.Flags = append(inst.Flags, printer.Flag("chown", chownUser))
 	}
-
 	if rawChmodMode != "" {
-		sb.WriteString("--chmod=")
-		sb.WriteString(rawChmodMode)
-		sb.WriteString(" ")
-	}
-
-	// Choose delimiter that doesn't conflict with content
-	delimiter := chooseDelimiter(content)
-
-	sb.WriteString("<<")
-	sb.WriteString(delimiter)
-	sb.WriteString(" ")
-	sb.WriteString(targetPath)
-	sb.WriteString("\n")
-
-	if content == "" {
-		// Empty file: delimiter immediately after header newline (0-byte file)
-		sb.WriteString(delimiter)
-		return sb.String()
+		inst.Flags = append(inst.Flags, printer.Flag("chmod", rawChmodMode))
 	}
-	contentStr := strings.TrimSuffix(content, "\n")
-	sb.WriteString(contentStr)
-	sb.WriteString("\n")
-	sb.WriteString(delimiter)
-
-	return sb.String()
+	return inst.String()
 }
 
 // chownUserForCopy returns the user string for --chown when the active USER is
@@ -987,24 +996,6 @@ func chownUserForCopy(effectiveUser string) string {
 	return user
 }
 
-// chooseDelimiter selects a heredoc delimiter that doesn't appear in content.
-func chooseDelimiter(content string) string {
-	delimiters := []string{"EOF", "CONTENT", "FILE", "DATA", "END"}
-	for _, d := range delimiters {
-		if !strings.Contains(content, d) {
-			return d
-		}
-	}
-	// Fallback with number suffix
-	for i := 1; i < 100; i++ {
-		d := fmt.Sprintf("EOF%d", i)
-		if !strings.Contains(content, d) {
-			return d
-		}
-	}
-	return "EOF"
-}
-
 // resolveConfig extracts the PreferCopyHeredocConfig from input.
 func (r *PreferCopyHeredocRule) resolveConfig(config any) PreferCopyHeredocConfig {
 	return configutil.Coerce(config, DefaultPreferCopyHeredocConfig())
@@ -1387,12 +1378,7 @@ func resolveRunEndPosition(loc []parser.Range, sm *sourcemap.SourceMap, run *ins
 	// Fallback: when end position equals start (point location), compute from command text
 	if endLine == loc[0].Start.Line && endCol == loc[0].Start.Character {
 		cmdStr := getRunScriptFromCmd(run)
-		mountFlags := runmount.FormatMounts(runmount.GetMounts(run))
-		fullInstr := runPrefixToken
-		if mountFlags != "" {
-			fullInstr += mountFlags + " "
-		}
-		fullInstr += cmdStr
+		fullInstr := printRun(runmount.FormatMounts(runmount.GetMounts(run)), cmdStr)
 
 		lines := strings.Split(fullInstr, "\n")
 		if len(lines) > 1 {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gkampitakis/go-snaps/snaps"
 	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/testutil"
 )
 
@@ -429,6 +430,53 @@ RUN echo "#! /bin/bash\n\n# script to activate the conda environment" > /app/bas
 	}
 }
 
+func TestPreferCopyHeredocRule_LocationPointsAtRedirectTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		content   string
+		wantStart rules.Position
+		wantEnd   rules.Position
+	}{
+		{
+			name: "redirect target",
+			content: `FROM alpine:3.20
+RUN echo "hello" \
+    > /etc/motd
+`,
+			wantStart: rules.Position{Line: 3, Column: 6},
+			wantEnd:   rules.Position{Line: 3, Column: 15},
+		},
+		{
+			name: "tee falls back to the instruction",
+			content: `FROM alpine:3.20
+RUN echo "hello" | tee /etc/motd
+`,
+			wantStart: rules.Position{Line: 2, Column: 0},
+			wantEnd:   rules.Position{Line: 2, Column: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithConfig(t, "Dockerfile", tt.content, nil)
+			violations := NewPreferCopyHeredocRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			v := violations[0]
+			if v.Location.Start != tt.wantStart || v.Location.End != tt.wantEnd {
+				t.Errorf("location = %+v-%+v, want %+v-%+v", v.Location.Start, v.Location.End, tt.wantStart, tt.wantEnd)
+			}
+			if v.SuggestedFix == nil || len(v.SuggestedFix.Edits) != 1 || v.SuggestedFix.Edits[0].Location.Start.Line != 2 {
+				t.Errorf("expected the fix to still replace the whole RUN, got %+v", v.SuggestedFix)
+			}
+		})
+	}
+}
+
 func TestPreferCopyHeredocRule_AshNoEscapeInterpretation(t *testing.T) {
 	t.Parallel()
 
@@ -704,46 +752,6 @@ func TestBuildCopyHeredoc(t *testing.T) {
 	}
 }
 
-func TestChooseDelimiter(t *testing.T) {
-	t.Parallel()
-	tests := []struct {
-		name    string
-		content string
-		want    string
-	}{
-		{
-			name:    "no conflict",
-			content: "hello world",
-			want:    "EOF",
-		},
-		{
-			name:    "contains EOF",
-			content: "Some EOF text",
-			want:    "CONTENT",
-		},
-		{
-			name:    "contains EOF and CONTENT",
-			content: "EOF and CONTENT here",
-			want:    "FILE",
-		},
-		{
-			name:    "contains all standard delimiters",
-			content: "EOF CONTENT FILE DATA END",
-			want:    "EOF1",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			got := chooseDelimiter(tt.content)
-			if got != tt.want {
-				t.Errorf("chooseDelimiter() = %q, want %q", got, tt.want)
-			}
-		})
-	}
-}
-
 // TestPreferCopyHeredocRule_CrossRuleInteraction verifies that prefer-copy-heredoc
 // and prefer-run-heredoc do not both fire for printf file creation patterns.
 // prefer-copy-heredoc should handle these; prefer-run-heredoc should not.
@@ -65,6 +65,8 @@ func (r *PreferCurlConfigRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "reliability",
 		FixPriority:     93, //nolint:mnd // After cache-mounts (90), before add-unpack (95)
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		RelatedTo:       []string{CurlShouldFollowRedirectsRuleCode, PreferCopyHeredocRuleCode},
 	}
 }
 
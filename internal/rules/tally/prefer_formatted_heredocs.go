@@ -48,6 +48,7 @@ func (r *PreferFormattedHeredocsRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     rules.FormattedHeredocsFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -3,13 +3,18 @@ package tally
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
 
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/registry"
 	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/asyncutil"
 	"github.com/wharflab/tally/internal/rules/configutil"
 	"github.com/wharflab/tally/internal/runmount"
+	"github.com/wharflab/tally/internal/semantic"
 	"github.com/wharflab/tally/internal/shell"
 	"github.com/wharflab/tally/internal/sourcemap"
 )
@@ -64,6 +69,7 @@ func (r *PreferHeredocRule) Metadata() rules.RuleMetadata {
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     100, // Structural transform: run after content fixes
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -74,6 +80,31 @@ func (r *PreferHeredocRule) Schema() map[string]any {
 
 // Check runs the prefer-run-heredoc rule.
 func (r *PreferHeredocRule) Check(input rules.LintInput) []rules.Violation {
+	var violations []rules.Violation
+	for stageIdx := range input.Stages {
+		violations = append(violations, r.checkStage(input, stageIdx, staticShellVariant(input, stageIdx))...)
+	}
+	return violations
+}
+
+// staticShellVariant returns the shell variant a stage starts with according
+// to the semantic model, or bash when no model is available.
+func staticShellVariant(input rules.LintInput, stageIdx int) shell.Variant {
+	if input.Semantic != nil {
+		if info := input.Semantic.StageInfo(stageIdx); info != nil {
+			return info.ShellSetting.Variant
+		}
+	}
+	return shell.VariantBash
+}
+
+// checkStage checks one stage whose RUN instructions start out with the
+// given shell variant.
+func (r *PreferHeredocRule) checkStage(
+	input rules.LintInput,
+	stageIdx int,
+	initialVariant shell.Variant,
+) []rules.Violation {
 	cfg := r.resolveConfig(input.Config)
 
 	// Get effective minCommands (default 3)
@@ -97,48 +128,102 @@ func (r *PreferHeredocRule) Check(input rules.LintInput) []rules.Violation {
 	// heredoc body for commands with pipes, avoiding a separate SHELL instruction.
 	pipefailEnabled := input.IsRuleEnabled(rules.PipefailRuleCode)
 
-	// Get semantic model for shell variant info (may be nil)
-	var sem = input.Semantic
+	stage := input.Stages[stageIdx]
+
+	// Build a per-instruction shell variant map by tracking SHELL
+	// instruction changes through the stage, so heredoc suggestions
+	// are only emitted for instructions running under a heredoc-
+	// compatible shell.
+	shellAtCmd := buildShellVariantMap(stage, initialVariant)
+
+	p := heredocCheckParams{
+		stageIdx:        stageIdx,
+		shellVariant:    initialVariant,
+		shellAtCmd:      shellAtCmd,
+		file:            input.File,
+		sm:              sm,
+		minCommands:     minCommands,
+		pipefailEnabled: pipefailEnabled,
+		deferToGit:      input.IsRuleEnabled(rules.PreferAddGitRuleCode),
+		meta:            meta,
+	}
 
-	for stageIdx, stage := range input.Stages {
-		// Get initial shell variant for this stage.
-		initialVariant := shell.VariantBash
-		if sem != nil {
-			if info := sem.StageInfo(stageIdx); info != nil {
-				initialVariant = info.ShellSetting.Variant
-			}
-		}
+	// Check consecutive RUNs
+	if checkConsecutive {
+		violations = append(violations, r.checkConsecutiveRuns(stage, p)...)
+	}
 
-		// Build a per-instruction shell variant map by tracking SHELL
-		// instruction changes through the stage, so heredoc suggestions
-		// are only emitted for instructions running under a heredoc-
-		// compatible shell.
-		shellAtCmd := buildShellVariantMap(stage, initialVariant)
+	// Check chained commands within single RUN
+	if checkChained {
+		violations = append(violations, r.checkChainedCommands(stage, p)...)
+	}
 
-		p := heredocCheckParams{
-			stageIdx:        stageIdx,
-			shellVariant:    initialVariant,
-			shellAtCmd:      shellAtCmd,
-			file:            input.File,
-			sm:              sm,
-			minCommands:     minCommands,
-			pipefailEnabled: pipefailEnabled,
-			deferToGit:      input.IsRuleEnabled(rules.PreferAddGitRuleCode),
-			meta:            meta,
-		}
+	return violations
+}
 
-		// Check consecutive RUNs
-		if checkConsecutive {
-			violations = append(violations, r.checkConsecutiveRuns(stage, p)...)
-		}
+// PlanAsync resolves the config of external base images so that stages are
+// checked with the shell the image actually uses. An image may run RUN with
+// cmd or PowerShell, where heredocs do not apply, even when its name does not
+// tell.
+func (r *PreferHeredocRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
 
-		// Check chained commands within single RUN
-		if checkChained {
-			violations = append(violations, r.checkChainedCommands(stage, p)...)
+	requests := asyncutil.PlanExternalImageChecks(input, r.Metadata(), func(
+		m rules.RuleMetadata,
+		info *semantic.StageInfo,
+		_, _ string,
+	) async.ResultHandler {
+		return &heredocShellHandler{
+			rule:     r,
+			meta:     m,
+			input:    input,
+			stageIdx: info.Index,
 		}
+	})
+
+	// Only stages with shell-form RUNs can change their result.
+	return slices.DeleteFunc(requests, func(req async.CheckRequest) bool {
+		return !slices.ContainsFunc(input.Stages[req.StageIndex].Commands, func(cmd instructions.Command) bool {
+			run, ok := cmd.(*instructions.RunCommand)
+			return ok && run.PrependShell
+		})
+	})
+}
+
+// heredocShellHandler re-checks a stage once its base image config is
+// resolved, if the image changes the shell variant the stage starts with.
+type heredocShellHandler struct {
+	rule     *PreferHeredocRule
+	meta     rules.RuleMetadata
+	input    rules.LintInput
+	stageIdx int
+}
+
+func (h *heredocShellHandler) OnSuccess(resolved any) []any {
+	cfg, ok := resolved.(*registry.ImageConfig)
+	if !ok || cfg == nil {
+		return nil
+	}
+	setting, ok := h.input.Semantic.RecheckShellSetting(h.stageIdx, asyncutil.ImageShellConfig(cfg))
+	if !ok || setting.Variant == staticShellVariant(h.input, h.stageIdx) {
+		return nil
 	}
 
-	return violations
+	return asyncutil.RefinedViolations(
+		h.meta.Code, h.input.File, []int{h.stageIdx},
+		func(int) bool { return true },
+		func(idx int) []any {
+			violations := h.rule.checkStage(h.input, idx, setting.Variant)
+			out := make([]any, 0, len(violations))
+			for _, v := range violations {
+				out = append(out, v)
+			}
+			return out
+		},
+	)
 }
 
 // DefaultConfig returns the default configuration for this rule.
@@ -6,7 +6,9 @@ import (
 	"github.com/gkampitakis/go-snaps/snaps"
 	"github.com/moby/buildkit/frontend/dockerfile/instructions"
 
+	"github.com/wharflab/tally/internal/async"
 	"github.com/wharflab/tally/internal/heredoc"
+	"github.com/wharflab/tally/internal/registry"
 	"github.com/wharflab/tally/internal/rules"
 	"github.com/wharflab/tally/internal/shell"
 	"github.com/wharflab/tally/internal/testutil"
@@ -214,6 +216,44 @@ RUN echo foo && git clone https://github.com/NVIDIA/apex && echo zoo
 	}
 }
 
+func TestPreferHeredocRule_PlanAsync(t *testing.T) {
+	t.Parallel()
+
+	content := `FROM myorg/builder:1
+RUN apt-get update && apt-get install -y curl && rm -rf /var/lib/apt/lists/*
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	if got := len(NewPreferHeredocRule().Check(input)); got != 1 {
+		t.Fatalf("fast path: got %d violations, want 1", got)
+	}
+
+	requests := NewPreferHeredocRule().PlanAsync(input)
+	if len(requests) != 1 {
+		t.Fatalf("got %d async requests, want 1", len(requests))
+	}
+	handler := requests[0].Handler
+
+	// The image runs RUN with PowerShell: the chain is not a heredoc candidate.
+	result := handler.OnSuccess(&registry.ImageConfig{OS: "linux", Shell: []string{"pwsh", "-Command"}})
+	var completed bool
+	for _, r := range result {
+		switch r.(type) {
+		case async.CompletedCheck:
+			completed = true
+		case rules.Violation:
+			t.Errorf("unexpected refined violation: %+v", r)
+		}
+	}
+	if !completed {
+		t.Error("expected a CompletedCheck replacing the fast-path result")
+	}
+
+	// An image that keeps the statically assumed shell leaves the fast path alone.
+	if got := handler.OnSuccess(&registry.ImageConfig{OS: "linux"}); got != nil {
+		t.Errorf("expected nil for an unchanged shell variant, got %v", got)
+	}
+}
+
 func TestPreferHeredocRule_CheckWithFixes(t *testing.T) {
 	t.Parallel()
 	rule := NewPreferHeredocRule()
@@ -50,6 +50,7 @@ func (r *PreferMultiStageBuildRule) Metadata() rules.RuleMetadata {
 		Category:        "performance",
 		IsExperimental:  true,
 		FixPriority:     150, // Whole-file rewrite should run after other structural transforms.
+		Fixes:           []rules.FixSafety{rules.FixUnsafe},
 	}
 }
 
@@ -45,6 +45,7 @@ func (r *PreferNginxSigquitRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(PreferNginxSigquitRuleCode),
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "best-practice",
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
@@ -40,6 +40,7 @@ func (r *PreferPackageCacheMountsRule) Metadata() rules.RuleMetadata {
 		Category:        "performance",
 		IsExperimental:  false,
 		FixPriority:     90, // Content rewrite before heredoc structural transforms (99/100+)
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -333,8 +334,11 @@ func buildCacheMountEdits(p cacheMountEditParams) cacheMountEditResult {
 		edits = append(edits, buildMountFlagEdit(p, merged)...)
 	}
 
-	// Edit 2+: cleanup edits (heredoc or targeted non-heredoc).
-	edits = append(edits, cleanupEdits...)
+	// Edit 2+: cleanup edits (heredoc or targeted non-heredoc). A tail
+	// rewrite already carries the cleaned script and spans the same range.
+	if !needsTailRewrite {
+		edits = append(edits, cleanupEdits...)
+	}
 
 	// Async resolver path: emit the whole-RUN tail rewrite now that narrow
 	// sync fixes have already been applied to the content the resolver sees.
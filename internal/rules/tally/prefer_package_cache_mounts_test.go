@@ -955,6 +955,38 @@ RUN pip install --no-cache-dir lodash
 	}
 }
 
+func TestPreferPackageCacheMountsResolver_ContinuedRunTailRewriteIsSingleEdit(t *testing.T) {
+	t.Parallel()
+
+	content := `FROM python:3.13
+RUN pip install --no-cache-dir left-pad \
+    lodash
+RUN pip install --no-cache-dir setuptools
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	violations := NewPreferPackageCacheMountsRule().Check(input)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %d", len(violations))
+	}
+
+	fix := violations[0].SuggestedFix
+	if fix == nil || !fix.NeedsResolve {
+		t.Fatal("expected async suggested fix for first RUN")
+	}
+	if edits := resolveIfAsync(t, fix, content); len(edits) != 1 {
+		t.Fatalf("expected a single tail rewrite edit, got %d: %+v", len(edits), edits)
+	}
+
+	got := applyResolvedFix(t, fix, content)
+	want := `FROM python:3.13
+RUN --mount=type=cache,target=/root/.cache/pip,id=pip pip install left-pad lodash
+RUN pip install --no-cache-dir setuptools
+`
+	if got != want {
+		t.Fatalf("fixed content =\n%s\nwant:\n%s", got, want)
+	}
+}
+
 func TestUVUsesCache(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -41,6 +41,7 @@ func (r *PreferSystemdSigrtminPlus3Rule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(PreferSystemdSigrtminPlus3RuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
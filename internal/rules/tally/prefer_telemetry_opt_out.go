@@ -49,6 +49,7 @@ func (r *PreferTelemetryOptOutRule) Metadata() rules.RuleMetadata {
 		Category:        "privacy",
 		IsExperimental:  false,
 		FixPriority:     96, // After shell/curl setup fixes, before heredoc transforms.
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
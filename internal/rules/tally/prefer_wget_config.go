@@ -61,6 +61,8 @@ func (r *PreferWgetConfigRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "reliability",
 		FixPriority:     94, //nolint:mnd // After curl config (93), before add-unpack (95)
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		RelatedTo:       []string{rules.HadolintRulePrefix + "DL3047", PreferAddUnpackRuleCode, PreferCopyHeredocRuleCode},
 	}
 }
 
@@ -0,0 +1,367 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// RecursivePermissionsAfterCopyRuleCode is the full rule code for the recursive-permissions-after-copy rule.
+const RecursivePermissionsAfterCopyRuleCode = rules.TallyRulePrefix + "recursive-permissions-after-copy"
+
+// RecursivePermissionsAfterCopyRule reports RUN chmod -R and chown -R in the
+// final stage over a directory that an earlier COPY or ADD of the stage
+// wrote to. Changing the mode or owner of a file from an earlier layer
+// stores a full copy of the file in the RUN's layer, so every affected file
+// ships twice; COPY --chmod/--chown sets them when the files are written.
+//
+// When the build context is available, the message estimates the size the
+// RUN duplicates from the context files the COPY instructions send.
+//
+// When the RUN is nothing but the chmod or chown of exactly the directory a
+// single COPY writes, and nothing in between can add files to it, a
+// FixSuggestion moves the mode or owner onto the COPY and removes the RUN.
+//
+// Copies that tally/copy-chown-consistency reports, because they are
+// re-owned for a later non-root USER, are left to that rule when it is
+// enabled: its fix adds --chown to the same COPY.
+type RecursivePermissionsAfterCopyRule struct{}
+
+// NewRecursivePermissionsAfterCopyRule creates a new recursive-permissions-after-copy rule instance.
+func NewRecursivePermissionsAfterCopyRule() *RecursivePermissionsAfterCopyRule {
+	return &RecursivePermissionsAfterCopyRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *RecursivePermissionsAfterCopyRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            RecursivePermissionsAfterCopyRuleCode,
+		Name:            "Recursive chmod/chown after COPY",
+		Description:     "RUN chmod -R or chown -R in the final stage duplicates copied files into a new layer",
+		DocURL:          rules.TallyDocURL(RecursivePermissionsAfterCopyRuleCode),
+		DefaultSeverity: rules.SeverityInfo,
+		Category:        "performance",
+		IsExperimental:  false,
+		FixPriority:     99, //nolint:mnd // Match prefer-copy-chmod, which edits the same COPY + RUN pairs
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+		RelatedTo: []string{
+			rules.TallyRulePrefix + "prefer-copy-chmod",
+			CopyAfterUserWithoutChownRuleCode,
+			CopyChownConsistencyRuleCode,
+		},
+	}
+}
+
+// permissionChange is a recursive chmod or chown found in a RUN.
+type permissionChange struct {
+	tool    string // "chmod" or "chown"
+	value   string // mode or owner
+	literal bool   // value has no shell expansion
+	targets []string
+}
+
+// copiedPath is the destination of a COPY or ADD of the stage.
+type copiedPath struct {
+	cmd      instructions.Command
+	cmdIndex int
+	dest     string
+}
+
+// Check runs the recursive-permissions-after-copy rule.
+func (r *RecursivePermissionsAfterCopyRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Facts == nil {
+		return nil
+	}
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	walker, _ := input.Facts.ContextFiles().(contextFileWalker)
+
+	// tally/copy-chown-consistency reports a COPY/ADD that is re-owned for a
+	// later non-root USER on the COPY itself, and adds --chown to it.
+	var deferred map[int]bool
+	if input.IsRuleEnabled(CopyChownConsistencyRuleCode) {
+		deferred = make(map[int]bool)
+		for _, v := range NewCopyChownConsistencyRule().Check(input) {
+			deferred[v.Location.Start.Line] = true
+		}
+	}
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		sf := input.Facts.Stage(stageIdx)
+		if sf == nil || !sf.IsLast {
+			continue
+		}
+		copies := stageCopiedPaths(stage)
+		for _, run := range sf.Runs {
+			if run.Shell.IsPowerShell {
+				continue
+			}
+			for i := range run.CommandInfos {
+				change := recursivePermissionChange(&run.CommandInfos[i])
+				if change == nil {
+					continue
+				}
+				for _, target := range change.targets {
+					if !path.IsAbs(target) {
+						target = path.Join(run.Workdir, target)
+					}
+					target = path.Clean(target)
+					sources := slices.DeleteFunc(copiesUnder(copies, run.CommandIndex, target), func(c copiedPath) bool {
+						return deferred[c.cmd.Location()[0].Start.Line]
+					})
+					if len(sources) == 0 {
+						continue
+					}
+					v := r.violation(input, meta, run, change, target, sources, walker, sf)
+					if fix := recursivePermissionsFix(input.File, sm, stage, run, change, target, sources, meta); fix != nil {
+						v = v.WithSuggestedFix(fix)
+					}
+					v.StageIndex = stageIdx
+					violations = append(violations, v)
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// violation builds the violation for a recursive change of target, which
+// the COPY/ADD instructions in sources wrote to.
+func (r *RecursivePermissionsAfterCopyRule) violation(
+	input rules.LintInput,
+	meta rules.RuleMetadata,
+	run *facts.RunFacts,
+	change *permissionChange,
+	target string,
+	sources []copiedPath,
+	walker contextFileWalker,
+	sf *facts.StageFacts,
+) rules.Violation {
+	lines := make([]string, 0, len(sources))
+	for _, src := range sources {
+		lines = append(lines, fmt.Sprintf("%s at line %d",
+			strings.ToUpper(src.cmd.Name()), src.cmd.Location()[0].Start.Line))
+	}
+	msg := fmt.Sprintf("%s -R %s duplicates the files written by %s into a new layer",
+		change.tool, target, strings.Join(lines, ", "))
+	if size, ok := copiedContextSize(walker, sf, sources, target); ok {
+		msg += fmt.Sprintf(" (%s of build context files)", formatByteSize(size))
+	}
+
+	return rules.NewViolation(
+		rules.NewLocationFromRanges(input.File, run.Run.Location()),
+		meta.Code, msg, meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(fmt.Sprintf(
+		"Changing the %s of a file from an earlier layer stores a full copy of the file in the RUN's layer, "+
+			"so the image ships every affected file twice. Set it when the files are copied with "+
+			"COPY --%s=%s instead.",
+		map[string]string{"chmod": "mode", "chown": "owner"}[change.tool], change.tool, change.value,
+	))
+}
+
+// recursivePermissionChange returns the recursive chmod or chown run by cmd,
+// or nil when cmd is something else.
+func recursivePermissionChange(cmd *shell.CommandInfo) *permissionChange {
+	if cmd.Name != "chmod" && cmd.Name != "chown" {
+		return nil
+	}
+	if !cmd.HasAnyFlag("-R", "--recursive") || cmd.HasFlag("--reference") {
+		return nil
+	}
+	change := &permissionChange{tool: cmd.Name}
+	for i, arg := range cmd.Args {
+		literal := i >= len(cmd.ArgLiteral) || cmd.ArgLiteral[i]
+		if change.value == "" {
+			// Symbolic modes like "-w" look like flags.
+			if cmd.Name == "chmod" && shell.IsSymbolicMode(arg) {
+				change.value, change.literal = arg, literal
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			change.value, change.literal = arg, literal
+			continue
+		}
+		if strings.HasPrefix(arg, "-") || !literal {
+			continue
+		}
+		change.targets = append(change.targets, arg)
+	}
+	if change.value == "" || len(change.targets) == 0 {
+		return nil
+	}
+	// Words that expand to nothing known are dropped from Args, shifting a
+	// target into the mode or owner position.
+	if (cmd.Name == "chmod" && !shell.IsOctalMode(change.value) && !shell.IsSymbolicMode(change.value)) ||
+		(cmd.Name == "chown" && strings.Contains(change.value, "/")) {
+		return nil
+	}
+	return change
+}
+
+// stageCopiedPaths returns the destinations of the COPY and ADD instructions
+// of stage, resolved against the WORKDIR in effect.
+func stageCopiedPaths(stage instructions.Stage) []copiedPath {
+	var copies []copiedPath
+	workdir := "/" // Docker default
+	for i, cmd := range stage.Commands {
+		var dest string
+		switch c := cmd.(type) {
+		case *instructions.WorkdirCommand:
+			workdir = facts.ResolveWorkdir(workdir, c.Path)
+			continue
+		case *instructions.CopyCommand:
+			dest = c.DestPath
+		case *instructions.AddCommand:
+			dest = c.DestPath
+		default:
+			continue
+		}
+		if dest == "" || len(cmd.Location()) == 0 {
+			continue
+		}
+		if !path.IsAbs(dest) {
+			dest = path.Join(workdir, dest)
+		}
+		copies = append(copies, copiedPath{cmd: cmd, cmdIndex: i, dest: path.Clean(dest)})
+	}
+	return copies
+}
+
+// copiesUnder returns the copies made before the command at cmdIndex whose
+// destination is target, inside it, or contains it.
+func copiesUnder(copies []copiedPath, cmdIndex int, target string) []copiedPath {
+	var out []copiedPath
+	for _, c := range copies {
+		if c.cmdIndex < cmdIndex && (pathWithin(c.dest, target) || pathWithin(target, c.dest)) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// copiedContextSize estimates the bytes a recursive change of target
+// duplicates: the size of the build context files the sources send. It
+// returns false when no source sends context files, or when target is
+// inside a copied directory and only part of it is affected.
+func copiedContextSize(
+	walker contextFileWalker,
+	sf *facts.StageFacts,
+	sources []copiedPath,
+	target string,
+) (int64, bool) {
+	if walker == nil {
+		return 0, false
+	}
+	var total int64
+	found := false
+	seen := make(map[string]bool)
+	for _, copied := range sources {
+		if !pathWithin(copied.dest, target) {
+			return 0, false
+		}
+		line := copied.cmd.Location()[0].Start.Line
+		for _, src := range sf.BuildContextSources {
+			if src == nil || src.Line != line || !src.AvailableInContext {
+				continue
+			}
+			for _, root := range contextSourceRoots(walker, src) {
+				_ = walker.WalkFiles(root, func(path string, size int64) error {
+					if !seen[path] {
+						seen[path] = true
+						total += size
+						found = true
+					}
+					return nil
+				})
+			}
+		}
+	}
+	return total, found
+}
+
+// recursivePermissionsFix returns a fix that sets the mode or owner on the
+// COPY and removes the RUN, or nil when that could change the result.
+func recursivePermissionsFix(
+	file string,
+	sm *sourcemap.SourceMap,
+	stage instructions.Stage,
+	run *facts.RunFacts,
+	change *permissionChange,
+	target string,
+	sources []copiedPath,
+	meta rules.RuleMetadata,
+) *rules.SuggestedFix {
+	if sm == nil || len(sources) != 1 || len(run.CommandInfos) != 1 || len(change.targets) != 1 ||
+		len(run.Run.Files) > 0 || !change.literal || len(run.Run.Location()) == 0 {
+		return nil
+	}
+	copied := sources[0]
+	copyCmd, ok := copied.cmd.(*instructions.CopyCommand)
+	if !ok || copied.dest != target {
+		return nil
+	}
+	switch change.tool {
+	case "chmod":
+		if copyCmd.Chmod != "" || !shell.IsOctalMode(change.value) {
+			return nil
+		}
+	case "chown":
+		if copyCmd.Chown != "" {
+			return nil
+		}
+	}
+	// Files added in between would lose the change.
+	for _, cmd := range stage.Commands[copied.cmdIndex+1 : run.CommandIndex] {
+		switch cmd.(type) {
+		case *instructions.RunCommand, *instructions.CopyCommand, *instructions.AddCommand,
+			*instructions.OnbuildCommand:
+			return nil
+		}
+	}
+
+	flag := "--" + change.tool + "=" + change.value
+	copyLine := copyCmd.Location()[0].Start.Line
+	insertCol := findCopyInsertCol(sm, copyLine)
+	return &rules.SuggestedFix{
+		Description: fmt.Sprintf("Use COPY %s and remove the RUN %s -R", flag, change.tool),
+		Safety:      rules.FixSuggestion,
+		Priority:    meta.FixPriority,
+		Edits: []rules.TextEdit{
+			{
+				Location: rules.NewRangeLocation(file, copyLine, insertCol, copyLine, insertCol),
+				NewText:  flag + " ",
+			},
+			deleteRunEdit(run.Run, file, sm),
+		},
+	}
+}
+
+// formatByteSize formats n bytes with a binary unit, e.g. "12.3 MiB".
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewRecursivePermissionsAfterCopyRule())
+}
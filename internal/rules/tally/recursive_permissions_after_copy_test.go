@@ -0,0 +1,188 @@
+package tally
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestRecursivePermissionsAfterCopyRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewRecursivePermissionsAfterCopyRule().Metadata())
+}
+
+func TestRecursivePermissionsAfterCopyRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewRecursivePermissionsAfterCopyRule(), []testutil.RuleTestCase{
+		{
+			Name:           "clean Dockerfile",
+			Content:        "FROM alpine:3.20\nRUN echo hello\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "chown of copied directory",
+			Content:        "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R app:app /app\n",
+			WantViolations: 1,
+			WantMessages:   []string{"chown -R /app duplicates the files written by COPY at line 2"},
+		},
+		{
+			Name:           "chmod of parent directory with combined flags",
+			Content:        "FROM alpine:3.20\nCOPY app/ /srv/app/\nRUN chmod -Rf 755 /srv\n",
+			WantViolations: 1,
+			WantMessages:   []string{"chmod -R /srv"},
+		},
+		{
+			Name:           "relative target resolved against WORKDIR",
+			Content:        "FROM alpine:3.20\nWORKDIR /srv\nADD app.tar.gz app/\nRUN chmod -R g+w app\n",
+			WantViolations: 1,
+			WantMessages:   []string{"chmod -R /srv/app duplicates the files written by ADD at line 3"},
+		},
+		{
+			Name:           "subdirectory of a copied directory",
+			Content:        "FROM alpine:3.20\nCOPY . /app\nRUN chown -R app /app/data\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "not recursive",
+			Content:        "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown app /app\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "directory not written by a COPY",
+			Content:        "FROM alpine:3.20\nCOPY app/ /app/\nRUN mkdir -p /data && chown -R app /data\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "COPY after the RUN",
+			Content:        "FROM alpine:3.20\nRUN chown -R app /app\nCOPY app/ /app/\n",
+			WantViolations: 0,
+		},
+		{
+			Name: "builder stage",
+			Content: "FROM alpine:3.20 AS build\nCOPY . /src\nRUN chown -R app /src\n\n" +
+				"FROM alpine:3.20\nCOPY --from=build /src /app\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "owner from a shell variable",
+			Content:        "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R \"$APP_USER\" /app /srv\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "reference mode",
+			Content:        "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R --reference=/etc /app\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestRecursivePermissionsAfterCopyRule_ContextSize(t *testing.T) {
+	t.Parallel()
+
+	ctx := writeContextFiles(t, map[string]string{
+		"app/a.bin":     strings.Repeat("a", 2048),
+		"app/lib/b.bin": strings.Repeat("b", 1024),
+		"other.txt":     "not copied",
+	})
+	input := testutil.MakeLintInputWithContext(t, "Dockerfile",
+		"FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R app /app\nRUN chown -R app /app/lib\n", ctx)
+
+	violations := NewRecursivePermissionsAfterCopyRule().Check(input)
+	if len(violations) != 2 {
+		t.Fatalf("got %d violations, want 2", len(violations))
+	}
+	if msg := violations[0].Message; !strings.HasSuffix(msg, "(3.0 KiB of build context files)") {
+		t.Errorf("message = %q, want the size of the copied files", msg)
+	}
+	// Only part of the copy is affected: no estimate.
+	if msg := violations[1].Message; strings.Contains(msg, "build context files") {
+		t.Errorf("message = %q, want no size estimate", msg)
+	}
+}
+
+func TestRecursivePermissionsAfterCopyRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string // empty: no fix
+	}{
+		{
+			name:    "chown",
+			content: "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R app:app /app\nUSER app\n",
+			want:    "FROM alpine:3.20\nCOPY --chown=app:app app/ /app/\nUSER app\n",
+		},
+		{
+			name:    "octal chmod in exec form",
+			content: "FROM alpine:3.20\nCOPY app/ /app/\nRUN [\"chmod\", \"-R\", \"0755\", \"/app\"]\n",
+			want:    "FROM alpine:3.20\nCOPY --chmod=0755 app/ /app/\n",
+		},
+		{
+			name:    "symbolic chmod",
+			content: "FROM alpine:3.20\nCOPY app/ /app/\nRUN chmod -R g+w /app\n",
+		},
+		{
+			name:    "COPY already sets the owner",
+			content: "FROM alpine:3.20\nCOPY --chown=root app/ /app/\nRUN chown -R app /app\n",
+		},
+		{
+			name:    "RUN does more than the chown",
+			content: "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R app /app && echo done\n",
+		},
+		{
+			name:    "parent directory",
+			content: "FROM alpine:3.20\nCOPY app/ /srv/app/\nRUN chown -R app /srv\n",
+		},
+		{
+			name:    "files added in between",
+			content: "FROM alpine:3.20\nCOPY app/ /app/\nRUN touch /app/log\nRUN chown -R app /app\n",
+		},
+		{
+			name:    "group from a shell variable",
+			content: "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R app:${APP_GROUP} /app\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewRecursivePermissionsAfterCopyRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			fix := violations[0].SuggestedFix
+			if tt.want == "" {
+				if fix != nil {
+					t.Errorf("unexpected fix %q", fix.Description)
+				}
+				return
+			}
+			if fix == nil {
+				t.Fatal("expected a fix")
+			}
+			if got := applyFixEdits(t, tt.content, fix.Edits); got != tt.want {
+				t.Errorf("fixed Dockerfile =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecursivePermissionsAfterCopyRule_DefersToCopyChownConsistency(t *testing.T) {
+	t.Parallel()
+
+	content := "FROM alpine:3.20\nCOPY app/ /app/\nRUN chown -R app:app /app\nUSER app\n"
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	if got := len(NewRecursivePermissionsAfterCopyRule().Check(input)); got != 1 {
+		t.Fatalf("got %d violations, want 1", got)
+	}
+
+	input.EnabledRules = []string{RecursivePermissionsAfterCopyRuleCode, CopyChownConsistencyRuleCode}
+	if got := len(NewRecursivePermissionsAfterCopyRule().Check(input)); got != 0 {
+		t.Errorf("got %d violations, want the COPY left to %s", got, CopyChownConsistencyRuleCode)
+	}
+}
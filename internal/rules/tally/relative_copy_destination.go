@@ -0,0 +1,362 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/util/system"
+
+	"github.com/wharflab/tally/internal/async"
+	"github.com/wharflab/tally/internal/registry"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/asyncutil"
+	"github.com/wharflab/tally/internal/semantic"
+	"github.com/wharflab/tally/internal/shell"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// RelativeCopyDestinationRule reports COPY and ADD instructions with a
+// relative destination in a stage that never sets WORKDIR, neither itself nor
+// through a parent stage. The files then land in the base image's working
+// directory, which the Dockerfile does not show: "/" for scratch and most
+// images, something else for images that set their own WORKDIR.
+//
+// The working directory comes from the semantic model. When it is known (the
+// stage is built on scratch), the fix rewrites the destination to the absolute
+// path it resolves to and is safe. For external base images the fast path
+// assumes "/" and offers the fix as a suggestion; with slow checks the image
+// config is resolved and the fix uses the image's actual WORKDIR. Windows
+// stages are always treated as unknown.
+//
+// Cross-rule interactions:
+//
+//   - hadolint/DL3045 reports the same COPY instructions; it skips them while
+//     this rule is enabled and keeps checking ONBUILD COPY triggers.
+type RelativeCopyDestinationRule struct{}
+
+// NewRelativeCopyDestinationRule creates a new rule instance.
+func NewRelativeCopyDestinationRule() *RelativeCopyDestinationRule {
+	return &RelativeCopyDestinationRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *RelativeCopyDestinationRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            rules.RelativeCopyDestinationRuleCode,
+		Name:            "Relative COPY destination without WORKDIR",
+		Description:     "COPY and ADD with a relative destination need a WORKDIR or an absolute path",
+		DocURL:          rules.TallyDocURL(rules.RelativeCopyDestinationRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
+	}
+}
+
+// Check runs the relative-copy-destination rule.
+func (r *RelativeCopyDestinationRule) Check(input rules.LintInput) []rules.Violation {
+	violations, _ := r.scan(input)
+	return violations
+}
+
+// PlanAsync resolves the base image config of external images so that the
+// fixes can use the image's actual WORKDIR instead of assuming "/".
+func (r *RelativeCopyDestinationRule) PlanAsync(input rules.LintInput) []async.CheckRequest {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+
+	violations, workdirSet := r.scan(input)
+	stagesWithViolations := make(map[int]bool, len(violations))
+	for _, v := range violations {
+		stagesWithViolations[v.StageIndex] = true
+	}
+	if len(stagesWithViolations) == 0 {
+		return nil
+	}
+
+	sm := input.SourceMap()
+	return asyncutil.PlanExternalImageChecks(input, r.Metadata(), func(
+		m rules.RuleMetadata,
+		info *semantic.StageInfo,
+		file, _ string,
+	) async.ResultHandler {
+		return &relativeCopyDestHandler{
+			meta:                 m,
+			file:                 file,
+			sm:                   sm,
+			stageIdx:             info.Index,
+			semantic:             sem,
+			stages:               input.Stages,
+			workdirSet:           workdirSet,
+			stagesWithViolations: stagesWithViolations,
+		}
+	})
+}
+
+// scan reports every stage and returns, per stage, whether a WORKDIR was set
+// before the stage's first command (inherited from a parent stage).
+func (r *RelativeCopyDestinationRule) scan(input rules.LintInput) ([]rules.Violation, []bool) {
+	sem := input.Semantic
+	if sem == nil {
+		return nil, nil
+	}
+	meta := r.Metadata()
+	sm := input.SourceMap()
+
+	inherited := make([]bool, len(input.Stages))
+	final := make([]bool, len(input.Stages))
+	var violations []rules.Violation
+	for stageIdx := range input.Stages {
+		info := sem.StageInfo(stageIdx)
+		if info == nil {
+			continue
+		}
+		if info.BaseImage != nil && info.BaseImage.IsStageRef {
+			if parent := info.BaseImage.StageIndex; parent >= 0 && parent < stageIdx {
+				inherited[stageIdx] = final[parent]
+			}
+		}
+
+		vs, set := relativeCopyDestViolations(
+			input.File, sm, meta, &input.Stages[stageIdx], stageIdx,
+			inherited[stageIdx], info.WorkdirAt,
+		)
+		violations = append(violations, vs...)
+		final[stageIdx] = set
+	}
+	return violations, inherited
+}
+
+// relativeCopyDestViolations reports the COPY/ADD instructions of one stage
+// that run before any WORKDIR and have a relative destination. workdirAt
+// gives the working directory per command index; it is the base image's while
+// no WORKDIR is set. It also returns whether a WORKDIR is set by the end of
+// the stage.
+func relativeCopyDestViolations(
+	file string,
+	sm *sourcemap.SourceMap,
+	meta rules.RuleMetadata,
+	stage *instructions.Stage,
+	stageIdx int,
+	workdirSet bool,
+	workdirAt func(cmdIdx int) (string, bool),
+) ([]rules.Violation, bool) {
+	var violations []rules.Violation
+	offeredWorkdir := false
+	for cmdIdx, cmd := range stage.Commands {
+		if workdirSet {
+			break
+		}
+
+		var (
+			name     string
+			dest     string
+			heredocs bool
+		)
+		switch c := cmd.(type) {
+		case *instructions.WorkdirCommand:
+			workdirSet = true
+			continue
+		case *instructions.CopyCommand:
+			name, dest, heredocs = strings.ToUpper(command.Copy), c.DestPath, len(c.SourceContents) > 0
+		case *instructions.AddCommand:
+			name, dest, heredocs = strings.ToUpper(command.Add), c.DestPath, len(c.SourceContents) > 0
+		default:
+			continue
+		}
+		if !isRelativeCopyDest(dest) || len(cmd.Location()) == 0 {
+			continue
+		}
+
+		base, known := workdirAt(cmdIdx)
+		v := newRelativeCopyDestViolation(file, sm, meta, cmd, name, dest, heredocs, base, known, !offeredWorkdir)
+		v.StageIndex = stageIdx
+		violations = append(violations, v)
+		offeredWorkdir = true
+	}
+	return violations, workdirSet
+}
+
+// isRelativeCopyDest reports whether a COPY/ADD destination is a relative
+// path. Destinations starting with a variable are not judged.
+func isRelativeCopyDest(dest string) bool {
+	dest = shell.DropQuotes(dest)
+	if dest == "" || strings.HasPrefix(dest, "$") {
+		return false
+	}
+	if system.IsAbs(dest, "") || system.IsAbs(dest, "windows") {
+		return false
+	}
+	// Drive-letter destinations (C:\app) are absolute for COPY on Windows.
+	return len(dest) < 2 || dest[1] != ':'
+}
+
+// absoluteCopyDest resolves a relative destination against base, keeping a
+// directory destination ("dir/", ".", "..") a directory.
+func absoluteCopyDest(base, dest string) string {
+	abs := path.Join(base, dest)
+	isDir := strings.HasSuffix(dest, "/") || path.Clean(dest) == "." || path.Clean(dest) == ".."
+	if isDir && abs != "/" {
+		abs += "/"
+	}
+	return abs
+}
+
+func newRelativeCopyDestViolation(
+	file string,
+	sm *sourcemap.SourceMap,
+	meta rules.RuleMetadata,
+	cmd instructions.Command,
+	name, dest string,
+	heredocs bool,
+	base string,
+	known, offerWorkdir bool,
+) rules.Violation {
+	abs := absoluteCopyDest(base, dest)
+
+	var msg, detail string
+	if known {
+		msg = fmt.Sprintf("%s destination %s is relative and no WORKDIR is set; files land in %s", name, dest, abs)
+		detail = fmt.Sprintf(
+			"The working directory is %s only because nothing changed it. A later WORKDIR, or a different "+
+				"base image, silently moves these files. Use an absolute destination or set WORKDIR first.", base)
+	} else {
+		msg = fmt.Sprintf("%s destination %s is relative and no WORKDIR is set; "+
+			"it depends on the base image's working directory", name, dest)
+		detail = "Without a WORKDIR the files land in the base image's working directory, which is / unless " +
+			"the image sets one. Use an absolute destination or set WORKDIR first."
+	}
+
+	safety := rules.FixSuggestion
+	if known {
+		safety = rules.FixSafe
+	}
+
+	// A Windows image may report a drive-letter WORKDIR, which the fixes
+	// cannot join with a POSIX destination.
+	var fixes []*rules.SuggestedFix
+	if !path.IsAbs(base) {
+		offerWorkdir = false
+	} else if edit, ok := copyDestEdit(file, sm, cmd, dest, abs, heredocs); ok {
+		fixes = append(fixes, &rules.SuggestedFix{
+			Description: "Use absolute destination " + abs,
+			Safety:      safety,
+			Edits:       []rules.TextEdit{edit},
+			IsPreferred: true,
+		})
+	}
+	if offerWorkdir && isSafeFixPath(base) {
+		line := cmd.Location()[0].Start.Line
+		fixes = append(fixes, &rules.SuggestedFix{
+			Description: fmt.Sprintf("Add WORKDIR %s before %s", base, name),
+			Safety:      safety,
+			Edits: []rules.TextEdit{{
+				Location: rules.NewRangeLocation(file, line, 0, line, 0),
+				NewText:  "WORKDIR " + base + "\n",
+			}},
+			IsPreferred: len(fixes) == 0,
+		})
+	}
+
+	v := rules.NewViolation(
+		rules.NewLocationFromRanges(file, cmd.Location()),
+		meta.Code,
+		msg,
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(detail)
+	if len(fixes) > 0 {
+		v = v.WithSuggestedFixes(fixes)
+	}
+	return v
+}
+
+// copyDestEdit replaces the destination, the last word of the instruction.
+// JSON-form, heredoc and quoted destinations are left alone.
+func copyDestEdit(
+	file string,
+	sm *sourcemap.SourceMap,
+	cmd instructions.Command,
+	dest, abs string,
+	heredocs bool,
+) (rules.TextEdit, bool) {
+	loc := cmd.Location()
+	if sm == nil || heredocs || !isSafeFixPath(abs) {
+		return rules.TextEdit{}, false
+	}
+	line := loc[len(loc)-1].End.Line
+	if line < 1 || line > sm.LineCount() {
+		return rules.TextEdit{}, false
+	}
+	text := strings.TrimRight(sm.Line(line-1), " \t")
+	start := strings.LastIndexAny(text, " \t") + 1
+	if start == 0 || text[start:] != dest {
+		return rules.TextEdit{}, false
+	}
+	return rules.TextEdit{
+		Location: rules.NewRangeLocation(file, line, start, line, len(text)),
+		NewText:  abs,
+	}, true
+}
+
+// isSafeFixPath reports whether p can be written into a fix without breaking
+// the line-oriented Dockerfile syntax.
+func isSafeFixPath(p string) bool {
+	return p != "" && !strings.ContainsAny(p, "\n\r\x00")
+}
+
+// relativeCopyDestHandler re-reports a stage and the stages built FROM it
+// once the base image config is resolved, with fixes that use the image's
+// actual WORKDIR.
+type relativeCopyDestHandler struct {
+	meta                 rules.RuleMetadata
+	file                 string
+	sm                   *sourcemap.SourceMap
+	stageIdx             int
+	semantic             *semantic.Model
+	stages               []instructions.Stage
+	workdirSet           []bool
+	stagesWithViolations map[int]bool
+}
+
+func (h *relativeCopyDestHandler) OnSuccess(resolved any) []any {
+	cfg, ok := resolved.(*registry.ImageConfig)
+	if !ok || cfg == nil || strings.ContainsAny(cfg.WorkingDir, "\n\r\x00") {
+		return nil
+	}
+
+	contexts := make(map[int]*semantic.StageContext)
+	indices := make([]int, 0)
+	for _, ctx := range h.semantic.RecheckStageContext(h.stageIdx, cfg.WorkingDir, cfg.User) {
+		contexts[ctx.StageIdx] = ctx
+		indices = append(indices, ctx.StageIdx)
+	}
+
+	return asyncutil.RefinedViolations(
+		h.meta.Code, h.file, indices,
+		func(idx int) bool { return h.stagesWithViolations[idx] },
+		func(idx int) []any { return h.refineStage(idx, contexts[idx]) },
+	)
+}
+
+func (h *relativeCopyDestHandler) refineStage(stageIdx int, ctx *semantic.StageContext) []any {
+	if stageIdx >= len(h.stages) || stageIdx >= len(h.workdirSet) || ctx == nil {
+		return nil
+	}
+	violations, _ := relativeCopyDestViolations(
+		h.file, h.sm, h.meta, &h.stages[stageIdx], stageIdx,
+		h.workdirSet[stageIdx], ctx.Workdir,
+	)
+	out := make([]any, 0, len(violations))
+	for _, v := range violations {
+		out = append(out, v)
+	}
+	return out
+}
+
+func init() {
+	rules.Register(NewRelativeCopyDestinationRule())
+}
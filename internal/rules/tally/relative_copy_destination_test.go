@@ -0,0 +1,239 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/async"
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/registry"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestRelativeCopyDestinationRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewRelativeCopyDestinationRule().Metadata())
+}
+
+func TestRelativeCopyDestinationRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewRelativeCopyDestinationRule(), []testutil.RuleTestCase{
+		{
+			Name: "scratch stage",
+			Content: `FROM scratch
+COPY app bin/
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"COPY destination bin/ is relative and no WORKDIR is set; files land in /bin/"},
+		},
+		{
+			Name: "external base image",
+			Content: `FROM alpine:3.20
+ADD app.tar.gz app
+`,
+			WantViolations: 1,
+			WantMessages: []string{
+				"ADD destination app is relative and no WORKDIR is set; it depends on the base image's working directory",
+			},
+		},
+		{
+			Name: "WORKDIR set before COPY",
+			Content: `FROM alpine:3.20
+WORKDIR /app
+COPY . .
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "only COPYs before the first WORKDIR",
+			Content: `FROM alpine:3.20
+COPY go.mod .
+WORKDIR /src
+COPY . .
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"COPY destination . is relative"},
+		},
+		{
+			Name: "WORKDIR inherited from parent stage",
+			Content: `FROM alpine:3.20 AS base
+WORKDIR /app
+
+FROM base
+COPY . .
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "parent stage without WORKDIR",
+			Content: `FROM scratch AS base
+COPY a /a
+
+FROM base
+COPY b b
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"files land in /b"},
+		},
+		{
+			Name: "absolute and variable destinations",
+			Content: `FROM alpine:3.20
+ARG DEST=/opt
+COPY a /a
+COPY b $DEST
+COPY ["c", "/c"]
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "Windows stage",
+			Content: `FROM mcr.microsoft.com/windows/servercore:ltsc2022
+COPY app app
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"it depends on the base image's working directory"},
+		},
+	})
+}
+
+func TestRelativeCopyDestinationRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		content    string
+		want       string
+		wantSafety rules.FixSafety
+		wantFixes  int
+	}{
+		{
+			name:       "scratch directory destination",
+			content:    "FROM scratch\nCOPY app .\n",
+			want:       "FROM scratch\nCOPY app /\n",
+			wantSafety: rules.FixSafe,
+			wantFixes:  2,
+		},
+		{
+			name:       "external image assumes root",
+			content:    "FROM alpine:3.20\nCOPY --chown=app app.conf etc/app/\n",
+			want:       "FROM alpine:3.20\nCOPY --chown=app app.conf /etc/app/\n",
+			wantSafety: rules.FixSuggestion,
+			wantFixes:  2,
+		},
+		{
+			name:       "continued instruction",
+			content:    "FROM scratch\nCOPY a \\\n     b \\\n     dir/\n",
+			want:       "FROM scratch\nCOPY a \\\n     b \\\n     /dir/\n",
+			wantSafety: rules.FixSafe,
+			wantFixes:  2,
+		},
+		{
+			name:       "JSON form falls back to WORKDIR",
+			content:    "FROM scratch\nCOPY [\"a\", \"b\"]\n",
+			want:       "FROM scratch\nWORKDIR /\nCOPY [\"a\", \"b\"]\n",
+			wantSafety: rules.FixSafe,
+			wantFixes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewRelativeCopyDestinationRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			if got := len(violations[0].AllFixes()); got != tt.wantFixes {
+				t.Errorf("got %d fixes, want %d", got, tt.wantFixes)
+			}
+			fix := violations[0].SuggestedFix
+			if fix == nil {
+				t.Fatal("violation has no SuggestedFix")
+			}
+			if fix.Safety != tt.wantSafety {
+				t.Errorf("fix safety = %v, want %v", fix.Safety, tt.wantSafety)
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.content), fix)); got != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeCopyDestinationRule_WorkdirOfferedOncePerStage(t *testing.T) {
+	t.Parallel()
+
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM scratch\nCOPY a a\nCOPY b b\n")
+	violations := NewRelativeCopyDestinationRule().Check(input)
+	if len(violations) != 2 {
+		t.Fatalf("got %d violations, want 2", len(violations))
+	}
+	if got := len(violations[0].AllFixes()); got != 2 {
+		t.Errorf("first violation has %d fixes, want 2", got)
+	}
+	if got := len(violations[1].AllFixes()); got != 1 {
+		t.Errorf("second violation has %d fixes, want 1", got)
+	}
+}
+
+func TestRelativeCopyDestinationRule_Async(t *testing.T) {
+	t.Parallel()
+
+	content := `FROM node:22 AS base
+COPY package.json .
+
+FROM base
+COPY dist dist/
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	requests := NewRelativeCopyDestinationRule().PlanAsync(input)
+	if len(requests) != 1 {
+		t.Fatalf("got %d async requests, want 1", len(requests))
+	}
+
+	result := requests[0].Handler.OnSuccess(&registry.ImageConfig{WorkingDir: "/home/node/app"})
+	completed := map[int]bool{}
+	var violations []rules.Violation
+	for _, r := range result {
+		switch v := r.(type) {
+		case async.CompletedCheck:
+			completed[v.StageIndex] = true
+		case rules.Violation:
+			violations = append(violations, v)
+		}
+	}
+	if !completed[0] || !completed[1] {
+		t.Errorf("completed stages = %v, want 0 and 1", completed)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("got %d refined violations, want 2", len(violations))
+	}
+
+	wants := []string{"/home/node/app/", "/home/node/app/dist/"}
+	for i, v := range violations {
+		fix := v.SuggestedFix
+		if fix == nil || fix.Safety != rules.FixSafe {
+			t.Fatalf("violation %d: fix = %+v, want a safe fix", i, fix)
+		}
+		if got := fix.Edits[0].NewText; got != wants[i] {
+			t.Errorf("violation %d: NewText = %q, want %q", i, got, wants[i])
+		}
+	}
+
+	if got := requests[0].Handler.OnSuccess(&registry.ImageConfig{WorkingDir: "/app\nRUN id"}); got != nil {
+		t.Errorf("expected nil for a WorkingDir with a newline, got %v", got)
+	}
+}
+
+func TestRelativeCopyDestinationRule_NoPlansWithoutViolations(t *testing.T) {
+	t.Parallel()
+
+	input := testutil.MakeLintInput(t, "Dockerfile", "FROM alpine:3.20\nWORKDIR /app\nCOPY . .\n")
+	if requests := NewRelativeCopyDestinationRule().PlanAsync(input); len(requests) != 0 {
+		t.Errorf("got %d async requests, want 0", len(requests))
+	}
+}
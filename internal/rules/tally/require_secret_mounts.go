@@ -65,6 +65,7 @@ func (r *RequireSecretMountsRule) Metadata() rules.RuleMetadata {
 		Category:        "security",
 		IsExperimental:  false,
 		FixPriority:     85, // Before prefer-package-cache-mounts (90)
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
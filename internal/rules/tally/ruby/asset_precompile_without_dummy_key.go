@@ -68,6 +68,7 @@ func (r *AssetPrecompileWithoutDummyKeyRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
 		FixPriority:     assetPrecompileFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
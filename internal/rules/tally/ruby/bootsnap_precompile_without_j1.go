@@ -56,6 +56,7 @@ func (r *BootsnapPrecompileWithoutJ1Rule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     bootsnapFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -60,6 +60,7 @@ func (r *DeprecatedBundlerInstallFlagsRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     deprecatedBundlerInstallFlagsFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
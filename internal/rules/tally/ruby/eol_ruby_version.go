@@ -40,6 +40,7 @@ func (r *EOLRubyVersionRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
 		FixPriority:     eolRubyVersionFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
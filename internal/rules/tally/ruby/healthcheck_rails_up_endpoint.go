@@ -39,6 +39,7 @@ func (r *HealthcheckRailsUpEndpointRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "correctness",
 		FixPriority:     healthcheckRailsUpEndpointFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -73,6 +73,7 @@ func (r *JemallocInstalledButNotPreloadedRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "performance",
 		FixPriority:     jemallocFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
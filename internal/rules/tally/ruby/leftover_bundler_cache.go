@@ -41,6 +41,7 @@ func (r *LeftoverBundlerCacheRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		FixPriority:     leftoverBundlerCacheFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -42,6 +42,7 @@ func (r *MissingBundleDeploymentRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     missingBundleDeploymentFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -54,6 +54,7 @@ func (r *MissingBundleWithoutDevelopmentRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
 		FixPriority:     missingBundleWithoutDevelopmentFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
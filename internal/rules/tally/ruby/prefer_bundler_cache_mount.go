@@ -41,6 +41,7 @@ func (r *PreferBundlerCacheMountRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		FixPriority:     preferBundlerCacheMountFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -37,6 +37,7 @@ func (r *PreferGemfileBindMountsRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		FixPriority:     preferGemfileBindMountsFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -42,6 +42,7 @@ func (r *PreferNetworkNoneInstallRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "security",
 		FixPriority:     preferNetworkNoneInstallFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -59,6 +59,7 @@ func (r *PreferSecretMountsForBuildCredentialsRule) Metadata() rules.RuleMetadat
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "security",
 		FixPriority:     preferSecretMountsForBuildCredentialsFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
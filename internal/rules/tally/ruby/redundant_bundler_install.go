@@ -43,6 +43,7 @@ func (r *RedundantBundlerInstallRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "performance",
 		FixPriority:     redundantBundlerInstallFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -49,6 +49,7 @@ func (r *SecretsInArgOrEnvRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityError,
 		Category:        "security",
 		FixPriority:     secretsInArgOrEnvFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixUnsafe},
 	}
 }
 
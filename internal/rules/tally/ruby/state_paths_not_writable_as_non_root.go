@@ -53,6 +53,7 @@ func (r *StatePathsNotWritableAsNonRootRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
 		FixPriority:     statePathsNotWritableAsNonRootFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -77,6 +77,7 @@ func (r *YJITNotEnabledOnSupportedRuntimeRule) Metadata() rules.RuleMetadata {
 		DefaultSeverity: rules.SeverityInfo,
 		Category:        "performance",
 		FixPriority:     yjitNotEnabledFixPriority,
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
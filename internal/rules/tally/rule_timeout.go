@@ -0,0 +1,62 @@
+package tally
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// RuleTimeoutRuleCode is the full rule code for the rule-timeout rule.
+const RuleTimeoutRuleCode = rules.TallyRulePrefix + "rule-timeout"
+
+// RuleTimeoutRule reports rules that were skipped because they exceeded the
+// rules.timeout budget. The linter enforces the budget and emits the
+// violations via NewRuleTimeoutViolation; Check itself returns nil.
+type RuleTimeoutRule struct{}
+
+// NewRuleTimeoutRule creates a new rule-timeout rule instance.
+func NewRuleTimeoutRule() *RuleTimeoutRule {
+	return &RuleTimeoutRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *RuleTimeoutRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            RuleTimeoutRuleCode,
+		Name:            "Rule timeout",
+		Description:     "A rule was skipped because it exceeded the rules.timeout budget",
+		DocURL:          rules.TallyDocURL(RuleTimeoutRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+	}
+}
+
+// Check returns nil — violations are produced by the linter's budget enforcement.
+func (r *RuleTimeoutRule) Check(_ rules.LintInput) []rules.Violation {
+	return nil
+}
+
+// NewRuleTimeoutViolation reports that ruleCodes did not finish within
+// budget on file and their findings were dropped. One violation covers every
+// rule that timed out on the file.
+func NewRuleTimeoutViolation(file string, ruleCodes []string, budget time.Duration) rules.Violation {
+	subject := "rule " + ruleCodes[0]
+	if len(ruleCodes) > 1 {
+		subject = fmt.Sprintf("%d rules (%s)", len(ruleCodes), strings.Join(ruleCodes, ", "))
+	}
+	return rules.NewViolation(
+		rules.NewFileLocation(file),
+		RuleTimeoutRuleCode,
+		fmt.Sprintf("%s skipped: exceeded the %s rules.timeout budget", subject, budget),
+		rules.SeverityWarning,
+	).WithDocURL(rules.TallyDocURL(RuleTimeoutRuleCode)).WithDetail(
+		"Findings from these rules are missing for this file. Raise rules.timeout, disable the slow rule, " +
+			"or report the Dockerfile that triggers the slow path.",
+	)
+}
+
+func init() {
+	rules.Register(NewRuleTimeoutRule())
+}
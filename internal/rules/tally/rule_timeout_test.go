@@ -0,0 +1,46 @@
+package tally
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestRuleTimeoutRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewRuleTimeoutRule().Metadata())
+}
+
+func TestRuleTimeoutRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewRuleTimeoutRule(), []testutil.RuleTestCase{
+		{
+			Name:           "check never reports",
+			Content:        "FROM alpine:3.20\nRUN echo hi\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestNewRuleTimeoutViolation(t *testing.T) {
+	t.Parallel()
+
+	v := NewRuleTimeoutViolation("Dockerfile", []string{"tally/secrets-in-code"}, 200*time.Millisecond)
+	if v.RuleCode != RuleTimeoutRuleCode {
+		t.Errorf("RuleCode = %q, want %q", v.RuleCode, RuleTimeoutRuleCode)
+	}
+	if want := "rule tally/secrets-in-code skipped: exceeded the 200ms rules.timeout budget"; v.Message != want {
+		t.Errorf("Message = %q, want %q", v.Message, want)
+	}
+	if !v.Location.IsFileLevel() {
+		t.Errorf("Location = %+v, want file-level", v.Location)
+	}
+
+	v = NewRuleTimeoutViolation("Dockerfile", []string{"hadolint/DL3008", "tally/secrets-in-code"}, time.Second)
+	if want := "2 rules (hadolint/DL3008, tally/secrets-in-code) skipped: exceeded the 1s rules.timeout budget"; v.Message != want {
+		t.Errorf("Message = %q, want %q", v.Message, want)
+	}
+}
@@ -0,0 +1,5 @@
+// Package runtime contains tally rules that catch image patterns which build
+// fine but break on common container runtimes (Docker, containerd, CRI-O on
+// Kubernetes). Rules in this package use the "tally/runtime/<rule-slug>" code
+// convention and live under _docs/rules/tally/runtime/ in the docs site.
+package runtime
@@ -0,0 +1,16 @@
+package runtime
+
+import (
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// isWindowsStage reports whether a stage targets Windows. Windows containers
+// have no POSIX signals, /bin/sh, or privileged ports, so tally/runtime/*
+// rules skip those stages; tally/windows/* covers them instead.
+func isWindowsStage(input rules.LintInput, stageIdx int) bool {
+	if input.Semantic == nil {
+		return false
+	}
+	info := input.Semantic.StageInfo(stageIdx)
+	return info != nil && info.IsWindows()
+}
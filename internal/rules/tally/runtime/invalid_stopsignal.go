@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// InvalidStopsignalRuleCode is the full rule code.
+const InvalidStopsignalRuleCode = rules.TallyRulePrefix + "runtime/invalid-stopsignal"
+
+// maxLinuxSignal is the highest signal number on Linux (SIGRTMAX).
+const maxLinuxSignal = 64
+
+// linuxSignalNames lists the signal names, without the "SIG" prefix, that
+// BuildKit and the Docker daemon accept for Linux containers. It mirrors the
+// Linux SignalMap in github.com/moby/sys/signal; note that only RTMIN+0..15
+// and RTMAX-14..0 are named there.
+var linuxSignalNames = func() map[string]bool {
+	names := map[string]bool{
+		"ABRT": true, "ALRM": true, "BUS": true, "CHLD": true, "CLD": true,
+		"CONT": true, "FPE": true, "HUP": true, "ILL": true, "INT": true,
+		"IO": true, "IOT": true, "KILL": true, "PIPE": true, "POLL": true,
+		"PROF": true, "PWR": true, "QUIT": true, "SEGV": true, "STKFLT": true,
+		"STOP": true, "SYS": true, "TERM": true, "TRAP": true, "TSTP": true,
+		"TTIN": true, "TTOU": true, "URG": true, "USR1": true, "USR2": true,
+		"VTALRM": true, "WINCH": true, "XCPU": true, "XFSZ": true,
+		"RTMIN": true, "RTMAX": true,
+	}
+	for i := 1; i <= 15; i++ {
+		names["RTMIN+"+strconv.Itoa(i)] = true
+	}
+	for i := 1; i <= 14; i++ {
+		names["RTMAX-"+strconv.Itoa(i)] = true
+	}
+	return names
+}()
+
+// InvalidStopsignalRule flags STOPSIGNAL values that are not valid Linux
+// signals. BuildKit rejects unknown signal names when it dispatches the
+// instruction, failing the build late; numeric values are stored unchecked,
+// so an out-of-range number only fails when the runtime tries to stop the
+// container.
+//
+// Windows stages are skipped: STOPSIGNAL has no effect there at all, which
+// tally/windows/no-stopsignal reports.
+type InvalidStopsignalRule struct{}
+
+// NewInvalidStopsignalRule creates a new rule instance.
+func NewInvalidStopsignalRule() *InvalidStopsignalRule {
+	return &InvalidStopsignalRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *InvalidStopsignalRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            InvalidStopsignalRuleCode,
+		Name:            "Invalid STOPSIGNAL",
+		Description:     "STOPSIGNAL is not a valid Linux signal and fails the build or the container stop",
+		DocURL:          rules.TallyDocURL(InvalidStopsignalRuleCode),
+		DefaultSeverity: rules.SeverityError,
+		Category:        "correctness",
+	}
+}
+
+// Check runs the rule.
+func (r *InvalidStopsignalRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		if isWindowsStage(input, stageIdx) {
+			continue
+		}
+		for _, cmd := range stage.Commands {
+			stopSig, ok := cmd.(*instructions.StopSignalCommand)
+			if !ok || strings.Contains(stopSig.Signal, "$") {
+				continue
+			}
+
+			reason := invalidLinuxSignalReason(stopSig.Signal)
+			if reason == "" {
+				continue
+			}
+
+			loc := rules.NewLocationFromRanges(input.File, stopSig.Location())
+			v := rules.NewViolation(
+				loc, meta.Code,
+				fmt.Sprintf("STOPSIGNAL %s is not a valid Linux signal", stopSig.Signal),
+				meta.DefaultSeverity,
+			).WithDocURL(meta.DocURL).WithDetail(reason)
+			v.StageIndex = stageIdx
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// invalidLinuxSignalReason explains why raw is not a valid Linux stop signal,
+// or returns "" when it is valid. Names are matched like BuildKit does:
+// case-insensitively and with an optional "SIG" prefix.
+func invalidLinuxSignalReason(raw string) string {
+	s := strings.TrimSpace(raw)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	if s == "" {
+		return "STOPSIGNAL requires a signal name or number."
+	}
+
+	if num, err := strconv.Atoi(s); err == nil {
+		if num >= 1 && num <= maxLinuxSignal {
+			return ""
+		}
+		if num == 0 {
+			return "Signal 0 only checks that a process exists; BuildKit fails the build with \"invalid signal\"."
+		}
+		return fmt.Sprintf(
+			"Linux signal numbers range from 1 to %d. BuildKit stores the value unchecked, "+
+				"so the runtime fails when it sends the signal to stop the container.",
+			maxLinuxSignal,
+		)
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(s), "SIG")
+	if linuxSignalNames[name] {
+		return ""
+	}
+	detail := "BuildKit fails the build with \"invalid signal\" when it reaches this instruction."
+	if strings.HasPrefix(name, "RTMIN+") || strings.HasPrefix(name, "RTMAX-") {
+		detail += " Real-time signals are only named up to SIGRTMIN+15 and down to SIGRTMAX-14; " +
+			"use the signal number for the others."
+	}
+	return detail
+}
+
+func init() {
+	rules.Register(NewInvalidStopsignalRule())
+}
@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestInvalidStopsignalRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewInvalidStopsignalRule().Metadata())
+}
+
+func TestInvalidStopsignalRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewInvalidStopsignalRule(), []testutil.RuleTestCase{
+		{
+			Name:           "unknown name",
+			Content:        "FROM alpine:3.20\nSTOPSIGNAL SIGTERN\n",
+			WantViolations: 1,
+			WantCodes:      []string{InvalidStopsignalRuleCode},
+			WantMessages:   []string{"STOPSIGNAL SIGTERN is not a valid Linux signal"},
+		},
+		{
+			Name:           "real-time signal without a name",
+			Content:        "FROM debian:bookworm\nSTOPSIGNAL SIGRTMIN+20\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "number out of range",
+			Content:        "FROM debian:bookworm\nSTOPSIGNAL 99\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "zero",
+			Content:        "FROM debian:bookworm\nSTOPSIGNAL 0\n",
+			WantViolations: 1,
+		},
+		{
+			Name: "valid signals",
+			Content: `FROM debian:bookworm AS a
+STOPSIGNAL SIGTERM
+FROM debian:bookworm AS b
+STOPSIGNAL quit
+FROM debian:bookworm AS c
+STOPSIGNAL "SIGRTMIN+3"
+FROM debian:bookworm AS d
+STOPSIGNAL 64
+FROM debian:bookworm
+STOPSIGNAL SIGRTMAX-1
+`,
+			WantViolations: 0,
+		},
+		{
+			Name:           "variable",
+			Content:        "FROM alpine:3.20\nARG SIG=SIGTERM\nSTOPSIGNAL $SIG\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "windows stage is skipped",
+			Content:        "FROM mcr.microsoft.com/windows/servercore:ltsc2022\nSTOPSIGNAL SIGFOO\n",
+			WantViolations: 0,
+		},
+	})
+}
@@ -0,0 +1,194 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// PrivilegedPortAsNonrootRuleCode is the full rule code.
+const PrivilegedPortAsNonrootRuleCode = rules.TallyRulePrefix + "runtime/privileged-port-as-nonroot"
+
+// defaultUnprivilegedPortStart is the Linux default of the
+// net.ipv4.ip_unprivileged_port_start sysctl.
+const defaultUnprivilegedPortStart = 1024
+
+// PrivilegedPortAsNonrootConfig is the configuration for the
+// privileged-port-as-nonroot rule.
+type PrivilegedPortAsNonrootConfig struct {
+	// UnprivilegedPortStart is the first port a non-root process may bind.
+	// Set it to the target runtime's net.ipv4.ip_unprivileged_port_start
+	// (0 disables the rule).
+	UnprivilegedPortStart *int `json:"unprivileged-port-start,omitempty" koanf:"unprivileged-port-start"`
+}
+
+// DefaultPrivilegedPortAsNonrootConfig returns the default configuration.
+func DefaultPrivilegedPortAsNonrootConfig() PrivilegedPortAsNonrootConfig {
+	start := defaultUnprivilegedPortStart
+	return PrivilegedPortAsNonrootConfig{UnprivilegedPortStart: &start}
+}
+
+// PrivilegedPortAsNonrootRule flags EXPOSE of a privileged port (below 1024)
+// in a final stage that runs as a non-root user. Docker lowers
+// net.ipv4.ip_unprivileged_port_start to 0 for its containers, so the image
+// works locally, but many Kubernetes nodes and rootless Podman keep the kernel
+// default and the application fails to bind with "permission denied".
+//
+// Stages that grant cap_net_bind_service with setcap are skipped, as are
+// stages without an explicit USER: tally/stateful-root-runtime and
+// hadolint/DL3002 cover images that run as root.
+type PrivilegedPortAsNonrootRule struct {
+	schema map[string]any
+}
+
+// NewPrivilegedPortAsNonrootRule creates a new rule instance.
+func NewPrivilegedPortAsNonrootRule() *PrivilegedPortAsNonrootRule {
+	schema, err := configutil.RuleSchema(PrivilegedPortAsNonrootRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &PrivilegedPortAsNonrootRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *PrivilegedPortAsNonrootRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            PrivilegedPortAsNonrootRuleCode,
+		Name:            "Privileged port as non-root",
+		Description:     "Image exposes a privileged port but runs as a non-root user that cannot bind it on many runtimes",
+		DocURL:          rules.TallyDocURL(PrivilegedPortAsNonrootRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *PrivilegedPortAsNonrootRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *PrivilegedPortAsNonrootRule) DefaultConfig() any {
+	return DefaultPrivilegedPortAsNonrootConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *PrivilegedPortAsNonrootRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(PrivilegedPortAsNonrootRuleCode, config)
+}
+
+// Check runs the rule.
+func (r *PrivilegedPortAsNonrootRule) Check(input rules.LintInput) []rules.Violation {
+	if len(input.Stages) == 0 || input.Facts == nil {
+		return nil
+	}
+	cfg := configutil.Coerce(input.Config, DefaultPrivilegedPortAsNonrootConfig())
+	portStart := defaultUnprivilegedPortStart
+	if cfg.UnprivilegedPortStart != nil {
+		portStart = *cfg.UnprivilegedPortStart
+	}
+
+	finalIdx := len(input.Stages) - 1
+	if portStart <= 0 || isWindowsStage(input, finalIdx) {
+		return nil
+	}
+	sf := input.Facts.Stage(finalIdx)
+	user := effectiveRuntimeUser(input.Semantic, input.Facts, finalIdx)
+	if user == "" || strings.Contains(user, "$") || facts.IsRootUser(user) || grantsNetBindService(sf) {
+		return nil
+	}
+
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for _, cmd := range input.Stages[finalIdx].Commands {
+		expose, ok := cmd.(*instructions.ExposeCommand)
+		if !ok {
+			continue
+		}
+		var privileged []string
+		for _, port := range expose.Ports {
+			if first, ok := exposedPortStart(port); ok && first < portStart {
+				privileged = append(privileged, port)
+			}
+		}
+		if len(privileged) == 0 {
+			continue
+		}
+
+		loc := rules.NewLocationFromRanges(input.File, expose.Location())
+		v := rules.NewViolation(
+			loc, meta.Code,
+			fmt.Sprintf(
+				"EXPOSE %s is below port %d but the image runs as non-root user %s",
+				strings.Join(privileged, " "), portStart, user,
+			),
+			meta.DefaultSeverity,
+		).WithDocURL(meta.DocURL).WithDetail(
+			"Binding a port below net.ipv4.ip_unprivileged_port_start requires root or CAP_NET_BIND_SERVICE. " +
+				"Docker lowers the limit to 0, but many Kubernetes nodes and rootless Podman keep the default of 1024, " +
+				"so the application fails to start there. Listen on a port of 1024 or higher and map it at runtime.",
+		)
+		v.StageIndex = finalIdx
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// effectiveRuntimeUser returns the last USER of a stage, following local
+// stage references when the stage has none. It returns "" when no USER is
+// set in the chain.
+func effectiveRuntimeUser(sem *semantic.Model, fileFacts *facts.FileFacts, stageIdx int) string {
+	visited := make(map[int]bool)
+	for idx := stageIdx; !visited[idx]; {
+		visited[idx] = true
+		if sf := fileFacts.Stage(idx); sf != nil && sf.EffectiveUser != "" {
+			return sf.EffectiveUser
+		}
+		if sem == nil {
+			return ""
+		}
+		info := sem.StageInfo(idx)
+		if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef || info.BaseImage.StageIndex < 0 {
+			return ""
+		}
+		idx = info.BaseImage.StageIndex
+	}
+	return ""
+}
+
+// grantsNetBindService reports whether a RUN in the stage grants a binary
+// the capability to bind privileged ports (setcap cap_net_bind_service=+ep).
+func grantsNetBindService(sf *facts.StageFacts) bool {
+	if sf == nil {
+		return false
+	}
+	for _, run := range sf.Runs {
+		if strings.Contains(strings.ToLower(run.CommandScript), "cap_net_bind_service") {
+			return true
+		}
+	}
+	return false
+}
+
+// exposedPortStart parses an EXPOSE value ("80", "80/tcp", "8000-8100/udp")
+// and returns its first port. Values with variables do not parse.
+func exposedPortStart(value string) (int, bool) {
+	port, _, _ := strings.Cut(value, "/")
+	port, _, _ = strings.Cut(port, "-")
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+func init() {
+	rules.Register(NewPrivilegedPortAsNonrootRule())
+}
@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestPrivilegedPortAsNonrootRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewPrivilegedPortAsNonrootRule().Metadata())
+}
+
+func TestPrivilegedPortAsNonrootRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewPrivilegedPortAsNonrootRule(), []testutil.RuleTestCase{
+		{
+			Name:           "privileged port as non-root",
+			Content:        "FROM nginx:1.27\nUSER nginx\nEXPOSE 80 8443/tcp 443/tcp\n",
+			WantViolations: 1,
+			WantCodes:      []string{PrivilegedPortAsNonrootRuleCode},
+			WantMessages:   []string{"EXPOSE 443/tcp 80 is below port 1024 but the image runs as non-root user nginx"},
+		},
+		{
+			Name:           "port range",
+			Content:        "FROM alpine:3.20\nUSER 1000\nEXPOSE 1000-1100/udp\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "user from parent stage",
+			Content:        "FROM alpine:3.20 AS base\nUSER app\nFROM base\nEXPOSE 80\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "unprivileged port",
+			Content:        "FROM nginx:1.27\nUSER nginx\nEXPOSE 8080\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "root user",
+			Content:        "FROM nginx:1.27\nUSER root\nEXPOSE 80\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "no USER",
+			Content:        "FROM nginx:1.27\nEXPOSE 80\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "capability granted",
+			Content:        "FROM alpine:3.20\nRUN setcap cap_net_bind_service=+ep /usr/local/bin/app\nUSER app\nEXPOSE 80\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "variable port",
+			Content:        "FROM alpine:3.20\nARG PORT=80\nUSER app\nEXPOSE $PORT\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "runtime allows all ports",
+			Content:        "FROM nginx:1.27\nUSER nginx\nEXPOSE 80\n",
+			Config:         map[string]any{"unprivileged-port-start": 0},
+			WantViolations: 0,
+		},
+		{
+			Name:           "custom port start",
+			Content:        "FROM alpine:3.20\nUSER app\nEXPOSE 80 8080\n",
+			Config:         map[string]any{"unprivileged-port-start": 9000},
+			WantViolations: 1,
+			WantMessages:   []string{"EXPOSE 80 8080 is below port 9000"},
+		},
+	})
+}
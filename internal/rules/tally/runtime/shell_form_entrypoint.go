@@ -0,0 +1,242 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json/v2"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// ShellFormEntrypointRuleCode is the full rule code.
+const ShellFormEntrypointRuleCode = rules.TallyRulePrefix + "runtime/shell-form-entrypoint"
+
+// defaultShellFormShell is the shell Docker runs shell-form instructions with
+// when the stage sets no SHELL.
+var defaultShellFormShell = []string{"/bin/sh", "-c"}
+
+// ShellFormEntrypointRule flags the command the final stage starts with when
+// it is in shell form: the effective ENTRYPOINT, or the effective CMD when
+// the ENTRYPOINT is not in shell form. The runtime starts "/bin/sh -c
+// <command>", so the shell is PID 1: it does not forward SIGTERM to the
+// application, which is killed after the stop timeout instead of shutting
+// down gracefully.
+//
+// The fix converts the instruction to exec form by tokenizing the command
+// like the shell would. When a word needs shell expansion ($VAR, $(...),
+// globs, ~), exec form cannot express it, so the fix keeps the stage shell
+// but runs the command with "exec"; that fix is a suggestion.
+//
+// Cross-rule interaction with buildkit/JSONArgsRecommended: that rule (info)
+// flags every shell-form CMD and ENTRYPOINT, with a fix only for literal
+// commands. This rule supersedes it on the instructions it reports, so each
+// is reported once, as a warning, with the better fix.
+type ShellFormEntrypointRule struct{}
+
+// NewShellFormEntrypointRule creates a new rule instance.
+func NewShellFormEntrypointRule() *ShellFormEntrypointRule {
+	return &ShellFormEntrypointRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *ShellFormEntrypointRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            ShellFormEntrypointRuleCode,
+		Name:            "Shell-form ENTRYPOINT or CMD",
+		Description:     "Shell-form ENTRYPOINT and CMD run the application under /bin/sh, which does not forward stop signals",
+		DocURL:          rules.TallyDocURL(ShellFormEntrypointRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
+		Supersedes:      []string{rules.BuildKitRulePrefix + "JSONArgsRecommended"},
+		RelatedTo:       []string{rules.TallyRulePrefix + "no-ungraceful-stopsignal"},
+		// tally/entrypoint-shell-form reported the same instructions in every
+		// stage before it was merged into this rule.
+		Aliases: []string{rules.TallyRulePrefix + "entrypoint-shell-form"},
+	}
+}
+
+// Check runs the rule.
+func (r *ShellFormEntrypointRule) Check(input rules.LintInput) []rules.Violation {
+	if len(input.Stages) == 0 {
+		return nil
+	}
+	finalIdx := len(input.Stages) - 1
+	if isWindowsStage(input, finalIdx) {
+		return nil
+	}
+
+	// Only the last ENTRYPOINT and CMD of the stage are used at runtime.
+	var (
+		entrypoint *instructions.EntrypointCommand
+		cmd        *instructions.CmdCommand
+	)
+	for _, c := range input.Stages[finalIdx].Commands {
+		switch c := c.(type) {
+		case *instructions.EntrypointCommand:
+			entrypoint = c
+		case *instructions.CmdCommand:
+			cmd = c
+		}
+	}
+
+	var (
+		inst    instructions.Command
+		cmdLine instructions.ShellDependantCmdLine
+		// An exec-form ENTRYPOINT gets the CMD appended as arguments, which
+		// the shell form ignored, and an ENTRYPOINT receives the words of an
+		// exec-form CMD instead of a "/bin/sh -c" command line.
+		changesArgv bool
+	)
+	switch {
+	case entrypoint != nil && entrypoint.PrependShell:
+		// A shell-form ENTRYPOINT ignores CMD.
+		inst, cmdLine, changesArgv = entrypoint, entrypoint.ShellDependantCmdLine, cmd != nil
+	case cmd != nil && cmd.PrependShell:
+		inst, cmdLine, changesArgv = cmd, cmd.ShellDependantCmdLine, entrypoint != nil
+	default:
+		return nil
+	}
+
+	script := strings.TrimSpace(strings.Join(cmdLine.CmdLine, " "))
+	if script == "" || script == "exec" || strings.HasPrefix(script, "exec ") {
+		// "exec" replaces the shell with the application, which then
+		// receives signals as PID 1.
+		return nil
+	}
+
+	name := strings.ToUpper(inst.Name())
+	detail := "The shell is PID 1, so SIGTERM from \"docker stop\" or a Kubernetes pod shutdown does not reach the " +
+		"application and the container is killed after the grace period. "
+	if inst == entrypoint {
+		detail += "CMD and \"docker run\" arguments are also ignored. "
+	}
+	detail += "Use the exec form, or prefix the command with \"exec\" when it needs the shell."
+
+	meta := r.Metadata()
+	loc := rules.NewLocationFromRanges(input.File, inst.Location())
+	v := rules.NewViolation(
+		loc, meta.Code,
+		fmt.Sprintf("shell-form %s runs under /bin/sh -c, which does not forward stop signals to the application", name),
+		meta.DefaultSeverity,
+	).WithDocURL(meta.DocURL).WithDetail(detail)
+	v.StageIndex = finalIdx
+
+	if args, ok := shell.SplitSimpleCommand(script, shell.VariantPOSIX); ok && len(args) > 0 {
+		v = v.WithToken(args[0])
+	}
+	// Scripts chaining several commands need the shell; they are reported
+	// without a fix.
+	if shell.IsSingleSimpleCommand(script, shell.VariantPOSIX) {
+		if fix := execFormFix(input, finalIdx, inst, name, script, changesArgv); fix != nil {
+			v = v.WithSuggestedFix(fix).WithFixKind("exec-form")
+		}
+	}
+	return []rules.Violation{v}
+}
+
+// execFormFix rewrites a single-line shell-form instruction to exec form.
+// Literal commands become their argv; commands that need shell expansion
+// run under the stage shell with "exec". The fix is a suggestion when the
+// conversion changes the argv the container starts with. Instructions
+// spanning several lines or holding a "#" (which may start a shell comment)
+// are not fixed.
+func execFormFix(
+	input rules.LintInput,
+	stageIdx int,
+	inst instructions.Command,
+	name, script string,
+	changesArgv bool,
+) *rules.SuggestedFix {
+	locs := inst.Location()
+	if len(locs) == 0 || locs[0].Start.Line != locs[0].End.Line || strings.Contains(script, "#") {
+		return nil
+	}
+	lineNo := locs[0].Start.Line
+	lines := bytes.Split(input.Source, []byte("\n"))
+	if lineNo < 1 || lineNo > len(lines) {
+		return nil
+	}
+	line := string(lines[lineNo-1])
+	start, end := instructionArgsRange(line, name)
+	if start < 0 || strings.TrimSpace(line[start:end]) != script {
+		return nil
+	}
+
+	safety := rules.FixSafe
+	description := fmt.Sprintf("Convert %s to exec form", name)
+	args, literal := shell.SplitSimpleCommand(script, shell.VariantPOSIX)
+	if !literal {
+		shellCmd, ok := posixStageShell(input, stageIdx)
+		if !ok {
+			return nil
+		}
+		args = append(shellCmd, "exec "+script)
+		safety = rules.FixSuggestion
+		description = fmt.Sprintf("Convert %s to exec form running the command with exec", name)
+	}
+	if changesArgv {
+		safety = rules.FixSuggestion
+	}
+
+	execForm, err := json.Marshal(args)
+	if err != nil {
+		return nil
+	}
+	return &rules.SuggestedFix{
+		Description: description,
+		Safety:      safety,
+		IsPreferred: true,
+		Edits: []rules.TextEdit{{
+			Location: rules.NewRangeLocation(input.File, lineNo, start, lineNo, end),
+			NewText:  string(execForm),
+		}},
+	}
+}
+
+// posixStageShell returns the shell command array of a stage, as set by
+// SHELL, when it is a POSIX shell that understands "exec".
+func posixStageShell(input rules.LintInput, stageIdx int) ([]string, bool) {
+	if input.Semantic == nil {
+		return slices.Clone(defaultShellFormShell), true
+	}
+	info := input.Semantic.StageInfo(stageIdx)
+	if info == nil || len(info.ShellSetting.Shell) == 0 {
+		return slices.Clone(defaultShellFormShell), true
+	}
+	if !info.ShellSetting.Variant.SupportsPOSIXShellAST() {
+		return nil, false
+	}
+	return slices.Clone(info.ShellSetting.Shell), true
+}
+
+// instructionArgsRange returns the 0-based [start, end) column range of the
+// arguments of instruction keyword on a source line, or (-1, -1) when the
+// line does not start with the keyword.
+func instructionArgsRange(line, keyword string) (int, int) {
+	i := len(line) - len(strings.TrimLeft(line, " \t"))
+	if len(line) < i+len(keyword) || !strings.EqualFold(line[i:i+len(keyword)], keyword) {
+		return -1, -1
+	}
+	i += len(keyword)
+	if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+		return -1, -1
+	}
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	end := len(strings.TrimRight(line, " \t\r"))
+	if i >= end {
+		return -1, -1
+	}
+	return i, end
+}
+
+func init() {
+	rules.Register(NewShellFormEntrypointRule())
+}
@@ -0,0 +1,179 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestShellFormEntrypointRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewShellFormEntrypointRule().Metadata())
+}
+
+func TestShellFormEntrypointRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewShellFormEntrypointRule(), []testutil.RuleTestCase{
+		{
+			Name:           "shell form",
+			Content:        "FROM node:22\nENTRYPOINT node server.js\n",
+			WantViolations: 1,
+			WantCodes:      []string{ShellFormEntrypointRuleCode},
+			WantMessages:   []string{"does not forward stop signals"},
+		},
+		{
+			Name:           "shell form with expansion",
+			Content:        "FROM python:3.13\nENTRYPOINT gunicorn --bind 0.0.0.0:$PORT app:app\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "exec form",
+			Content:        "FROM node:22\nENTRYPOINT [\"node\", \"server.js\"]\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "shell form with exec",
+			Content:        "FROM python:3.13\nENTRYPOINT exec gunicorn --bind 0.0.0.0:$PORT app:app\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "overridden by a later exec form",
+			Content:        "FROM node:22\nENTRYPOINT node server.js\nENTRYPOINT [\"node\", \"server.js\"]\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "builder stage only",
+			Content:        "FROM node:22 AS build\nENTRYPOINT node build.js\nFROM nginx:1.27\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "windows stage is skipped",
+			Content:        "FROM mcr.microsoft.com/windows/servercore:ltsc2022\nENTRYPOINT app.exe\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "shell-form CMD",
+			Content:        "FROM node:22\nCMD node server.js\n",
+			WantViolations: 1,
+			WantMessages:   []string{"shell-form CMD runs under /bin/sh -c"},
+		},
+		{
+			Name:           "shell-form CMD after an exec-form ENTRYPOINT",
+			Content:        "FROM node:22\nENTRYPOINT [\"docker-entrypoint.sh\"]\nCMD node server.js\n",
+			WantViolations: 1,
+			WantMessages:   []string{"shell-form CMD"},
+		},
+		{
+			Name:           "CMD is ignored by a shell-form ENTRYPOINT",
+			Content:        "FROM node:22\nENTRYPOINT node server.js\nCMD node other.js\n",
+			WantViolations: 1,
+			WantMessages:   []string{"shell-form ENTRYPOINT"},
+		},
+		{
+			Name:           "CMD already uses exec",
+			Content:        "FROM node:22\nCMD exec node server.js\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestShellFormEntrypointRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		content    string
+		want       string
+		wantSafety rules.FixSafety
+		wantToken  string
+	}{
+		{
+			name:       "literal ENTRYPOINT without CMD",
+			content:    "FROM node:22\nentrypoint node --enable-source-maps server.js\n",
+			want:       "FROM node:22\nentrypoint [\"node\",\"--enable-source-maps\",\"server.js\"]\n",
+			wantSafety: rules.FixSafe,
+			wantToken:  "node",
+		},
+		{
+			name:       "literal CMD",
+			content:    "FROM node:22\nCMD node --enable-source-maps 'my app.js'\n",
+			want:       "FROM node:22\nCMD [\"node\",\"--enable-source-maps\",\"my app.js\"]\n",
+			wantSafety: rules.FixSafe,
+			wantToken:  "node",
+		},
+		{
+			name:       "ENTRYPOINT with CMD gets the CMD as arguments",
+			content:    "FROM node:22\nENTRYPOINT node server.js\nCMD [\"--port\", \"8080\"]\n",
+			want:       "FROM node:22\nENTRYPOINT [\"node\",\"server.js\"]\nCMD [\"--port\", \"8080\"]\n",
+			wantSafety: rules.FixSuggestion,
+			wantToken:  "node",
+		},
+		{
+			name:       "expansion keeps the shell and adds exec",
+			content:    "FROM python:3.13\nCMD gunicorn --bind \"0.0.0.0:$PORT\" app:app\n",
+			want:       "FROM python:3.13\nCMD [\"/bin/sh\",\"-c\",\"exec gunicorn --bind \\\"0.0.0.0:$PORT\\\" app:app\"]\n",
+			wantSafety: rules.FixSuggestion,
+		},
+		{
+			name:       "expansion uses the stage SHELL",
+			content:    "FROM debian:bookworm\nSHELL [\"/bin/bash\", \"-c\"]\nENTRYPOINT app --config ~/.app.toml\n",
+			want:       "FROM debian:bookworm\nSHELL [\"/bin/bash\", \"-c\"]\nENTRYPOINT [\"/bin/bash\",\"-c\",\"exec app --config ~/.app.toml\"]\n",
+			wantSafety: rules.FixSuggestion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewShellFormEntrypointRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			v := violations[0]
+			if v.SuggestedFix == nil {
+				t.Fatal("violation has no SuggestedFix")
+			}
+			if v.SuggestedFix.Safety != tt.wantSafety {
+				t.Errorf("fix safety = %v, want %v", v.SuggestedFix.Safety, tt.wantSafety)
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.content), v.SuggestedFix)); got != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+			if v.Metadata == nil || v.Metadata.FixKind != "exec-form" || v.Metadata.Token != tt.wantToken {
+				t.Errorf("metadata = %+v, want fix kind exec-form and token %q", v.Metadata, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestShellFormEntrypointRule_NoFix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "operators", content: "FROM alpine:3.20\nENTRYPOINT ./migrate && ./server\n"},
+		{name: "multi-line", content: "FROM alpine:3.20\nENTRYPOINT ./server \\\n  --verbose\n"},
+		{name: "comment", content: "FROM node:22\nCMD node server.js # start\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewShellFormEntrypointRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			if violations[0].SuggestedFix != nil {
+				t.Errorf("unexpected fix: %s", violations[0].SuggestedFix.Description)
+			}
+		})
+	}
+}
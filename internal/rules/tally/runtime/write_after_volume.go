@@ -0,0 +1,264 @@
+package runtime
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// WriteAfterVolumeRuleCode is the full rule code.
+const WriteAfterVolumeRuleCode = rules.TallyRulePrefix + "runtime/write-after-volume"
+
+// volumeWriteAllArgs lists commands whose path operands are all written.
+// chown and chmod take an owner or mode first; it never matches a volume
+// path, so it needs no special casing.
+var volumeWriteAllArgs = map[string]bool{
+	"chmod": true,
+	"chown": true,
+	"mkdir": true,
+	"rm":    true,
+	"tee":   true,
+	"touch": true,
+}
+
+// volumeWriteLastArg lists commands that write only to their last operand.
+var volumeWriteLastArg = map[string]bool{
+	"cp":      true,
+	"install": true,
+	"ln":      true,
+	"mv":      true,
+	"rsync":   true,
+}
+
+// declaredVolume is a VOLUME path in effect for a stage.
+type declaredVolume struct {
+	path string
+	// line is the VOLUME instruction's line, or the FROM line for a volume
+	// inherited from a parent stage.
+	line      int
+	inherited bool
+}
+
+// WriteAfterVolumeRule flags COPY, ADD, and RUN instructions that write under
+// a path after a VOLUME instruction has declared it. The classic builder
+// discards such changes, and at runtime any volume mounted on the path (a
+// Kubernetes emptyDir or persistent volume, a bind mount) hides what the
+// image put there, so the files silently disappear.
+//
+// RUN writes are detected from literal operands of common file commands
+// (mkdir, touch, cp, mv, chown, ...) and output redirections; dynamic paths
+// are not resolved.
+type WriteAfterVolumeRule struct{}
+
+// NewWriteAfterVolumeRule creates a new rule instance.
+func NewWriteAfterVolumeRule() *WriteAfterVolumeRule {
+	return &WriteAfterVolumeRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *WriteAfterVolumeRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            WriteAfterVolumeRuleCode,
+		Name:            "Write after VOLUME",
+		Description:     "Build step writes under a VOLUME path; the content is discarded or hidden by the mounted volume",
+		DocURL:          rules.TallyDocURL(WriteAfterVolumeRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+	}
+}
+
+// Check runs the rule.
+func (r *WriteAfterVolumeRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Facts == nil {
+		return nil
+	}
+	meta := r.Metadata()
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		if isWindowsStage(input, stageIdx) {
+			continue
+		}
+		sf := input.Facts.Stage(stageIdx)
+		runFacts := make(map[*instructions.RunCommand]*facts.RunFacts, len(sf.Runs))
+		for _, run := range sf.Runs {
+			runFacts[run.Run] = run
+		}
+
+		fromLine := 0
+		if len(stage.Location) > 0 {
+			fromLine = stage.Location[0].Start.Line
+		}
+		volumes, workdir := inheritedVolumes(input, stageIdx, fromLine)
+
+		for _, cmd := range stage.Commands {
+			var written []string
+			switch c := cmd.(type) {
+			case *instructions.VolumeCommand:
+				line := 0
+				if locs := c.Location(); len(locs) > 0 {
+					line = locs[0].Start.Line
+				}
+				for _, vol := range c.Volumes {
+					if p, ok := volumePath(vol); ok {
+						volumes = append(volumes, declaredVolume{path: p, line: line})
+					}
+				}
+				continue
+			case *instructions.WorkdirCommand:
+				workdir = facts.ResolveWorkdir(workdir, c.Path)
+				continue
+			case *instructions.CopyCommand:
+				written = []string{c.DestPath}
+			case *instructions.AddCommand:
+				written = []string{c.DestPath}
+			case *instructions.RunCommand:
+				if run := runFacts[c]; run != nil {
+					written = runWritePaths(run)
+					workdir = run.Workdir
+				}
+			default:
+				continue
+			}
+			if len(volumes) == 0 {
+				continue
+			}
+
+			target, vol, ok := firstWriteUnderVolume(written, workdir, volumes)
+			if !ok {
+				continue
+			}
+			v := rules.NewViolation(
+				rules.NewLocationFromRanges(input.File, cmd.Location()), meta.Code,
+				writeAfterVolumeMessage(cmd.Name(), target, vol),
+				meta.DefaultSeverity,
+			).WithDocURL(meta.DocURL).WithDetail(
+				"The classic builder discards changes made under a volume after VOLUME, and a volume mounted " +
+					"at runtime hides whatever the image stored there. Write the files before the VOLUME " +
+					"instruction, or move VOLUME to the end of the stage.",
+			)
+			v.StageIndex = stageIdx
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// writeAfterVolumeMessage renders the violation message for an instruction
+// writing target under vol.
+func writeAfterVolumeMessage(instruction, target string, vol declaredVolume) string {
+	origin := fmt.Sprintf("declared on line %d", vol.line)
+	if vol.inherited {
+		origin = "inherited from the parent stage"
+	}
+	return fmt.Sprintf(
+		"%s writes to %s after VOLUME %s (%s)",
+		strings.ToUpper(instruction), target, vol.path, origin,
+	)
+}
+
+// inheritedVolumes returns the volumes and final WORKDIR inherited through
+// local stage references (FROM <stage>). Inherited volumes are attributed to
+// the FROM line.
+func inheritedVolumes(input rules.LintInput, stageIdx, fromLine int) ([]declaredVolume, string) {
+	var volumes []declaredVolume
+	workdir := "/"
+	if input.Semantic == nil {
+		return nil, workdir
+	}
+
+	visited := map[int]bool{stageIdx: true}
+	first := true
+	for idx := stageIdx; ; first = false {
+		info := input.Semantic.StageInfo(idx)
+		if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef || info.BaseImage.StageIndex < 0 {
+			break
+		}
+		idx = info.BaseImage.StageIndex
+		if visited[idx] {
+			break
+		}
+		visited[idx] = true
+
+		parent := input.Facts.Stage(idx)
+		if parent == nil {
+			break
+		}
+		if first && parent.FinalWorkdir != "" {
+			workdir = parent.FinalWorkdir
+		}
+		for _, vol := range parent.Volumes {
+			if p, ok := volumePath(vol); ok {
+				volumes = append(volumes, declaredVolume{path: p, line: fromLine, inherited: true})
+			}
+		}
+	}
+	return volumes, workdir
+}
+
+// volumePath normalizes a VOLUME path. Relative, root, and dynamic paths are
+// skipped.
+func volumePath(raw string) (string, bool) {
+	p := strings.Trim(strings.TrimSpace(raw), `"'`)
+	if p == "" || strings.Contains(p, "$") || !path.IsAbs(p) {
+		return "", false
+	}
+	p = path.Clean(p)
+	if p == "/" {
+		return "", false
+	}
+	return p, true
+}
+
+// runWritePaths returns the literal paths a RUN writes to, as written.
+func runWritePaths(run *facts.RunFacts) []string {
+	var paths []string
+	for _, info := range run.CommandInfos {
+		operands := make([]string, 0, len(info.Args))
+		for i, arg := range info.Args {
+			if strings.HasPrefix(arg, "-") || i >= len(info.ArgLiteral) || !info.ArgLiteral[i] {
+				continue
+			}
+			operands = append(operands, arg)
+		}
+		switch {
+		case volumeWriteAllArgs[info.Name]:
+			paths = append(paths, operands...)
+		case volumeWriteLastArg[info.Name] && len(operands) > 1:
+			paths = append(paths, operands[len(operands)-1])
+		}
+	}
+	if run.Shell.Variant.SupportsPOSIXShellAST() {
+		for _, redirect := range shell.FindRedirectTargets(run.CommandScript, run.Shell.Variant) {
+			paths = append(paths, redirect.Path)
+		}
+	}
+	return paths
+}
+
+// firstWriteUnderVolume returns the first written path that falls under a
+// declared volume, resolved against workdir.
+func firstWriteUnderVolume(written []string, workdir string, volumes []declaredVolume) (string, declaredVolume, bool) {
+	for _, raw := range written {
+		if raw == "" || strings.Contains(raw, "$") {
+			continue
+		}
+		target := facts.ResolveWorkdir(workdir, raw)
+		for _, vol := range volumes {
+			if target == vol.path || strings.HasPrefix(target, vol.path+"/") {
+				return target, vol, true
+			}
+		}
+	}
+	return "", declaredVolume{}, false
+}
+
+func init() {
+	rules.Register(NewWriteAfterVolumeRule())
+}
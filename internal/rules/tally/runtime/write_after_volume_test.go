@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestWriteAfterVolumeRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewWriteAfterVolumeRule().Metadata())
+}
+
+func TestWriteAfterVolumeRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewWriteAfterVolumeRule(), []testutil.RuleTestCase{
+		{
+			Name: "RUN after VOLUME",
+			Content: `FROM postgres:17
+VOLUME /var/lib/postgresql/data
+RUN mkdir -p /var/lib/postgresql/data/conf.d
+`,
+			WantViolations: 1,
+			WantCodes:      []string{WriteAfterVolumeRuleCode},
+			WantMessages: []string{
+				"RUN writes to /var/lib/postgresql/data/conf.d after VOLUME /var/lib/postgresql/data (declared on line 2)",
+			},
+		},
+		{
+			Name:           "COPY after VOLUME",
+			Content:        "FROM alpine:3.20\nVOLUME [\"/data\"]\nCOPY seed.db /data/\n",
+			WantViolations: 1,
+			WantMessages:   []string{"COPY writes to /data"},
+		},
+		{
+			Name:           "relative destination under WORKDIR",
+			Content:        "FROM alpine:3.20\nWORKDIR /srv/app\nVOLUME /srv/app/uploads\nCOPY defaults/ uploads/\n",
+			WantViolations: 1,
+			WantMessages:   []string{"COPY writes to /srv/app/uploads"},
+		},
+		{
+			Name:           "redirect",
+			Content:        "FROM alpine:3.20\nVOLUME /etc/app\nRUN echo debug=false > /etc/app/app.conf\n",
+			WantViolations: 1,
+		},
+		{
+			Name:           "cp destination",
+			Content:        "FROM alpine:3.20\nVOLUME /data\nRUN cp /data/template /tmp/template && cp /tmp/x /data/x\n",
+			WantViolations: 1,
+			WantMessages:   []string{"RUN writes to /data/x"},
+		},
+		{
+			Name:           "inherited from parent stage",
+			Content:        "FROM alpine:3.20 AS base\nVOLUME /data\nFROM base\nRUN touch /data/ready\n",
+			WantViolations: 1,
+			WantMessages:   []string{"(inherited from the parent stage)"},
+		},
+		{
+			Name: "writes before VOLUME",
+			Content: `FROM alpine:3.20
+RUN mkdir -p /data && chown 1000 /data
+COPY seed.db /data/
+VOLUME /data
+`,
+			WantViolations: 0,
+		},
+		{
+			Name:           "reads under VOLUME",
+			Content:        "FROM alpine:3.20\nVOLUME /data\nRUN cp /data/template /tmp/template\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "sibling path",
+			Content:        "FROM alpine:3.20\nVOLUME /data\nRUN mkdir /database\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "other stage",
+			Content:        "FROM alpine:3.20 AS build\nVOLUME /data\nFROM alpine:3.20\nRUN mkdir /data\n",
+			WantViolations: 0,
+		},
+	})
+}
@@ -0,0 +1,318 @@
+package tally
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"github.com/zricethezav/gitleaks/v8/detect"
+	"github.com/zricethezav/gitleaks/v8/report"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/configutil"
+)
+
+// SecretInContextRuleCode is the full rule code for the secret-in-context rule.
+const SecretInContextRuleCode = rules.TallyRulePrefix + "secret-in-context"
+
+// binarySniffLen is how much of a file is checked for NUL bytes before it is
+// treated as binary and skipped, matching git's heuristic.
+const binarySniffLen = 8000
+
+// SecretInContextConfig is the configuration for the secret-in-context rule.
+//
+// Pointer types are used for fields that need tri-state semantics (unset vs explicit-zero).
+type SecretInContextConfig struct {
+	// Concurrency is the number of files scanned in parallel (nil = use default).
+	Concurrency *int `json:"concurrency,omitempty" koanf:"concurrency"`
+
+	// MaxFileSize is the size in bytes above which files are skipped (nil = use default).
+	MaxFileSize *int `json:"max-file-size,omitempty" koanf:"max-file-size"`
+
+	// MaxFiles is the number of files scanned per Dockerfile (nil = use default).
+	MaxFiles *int `json:"max-files,omitempty" koanf:"max-files"`
+}
+
+// DefaultSecretInContextConfig returns the default configuration.
+func DefaultSecretInContextConfig() SecretInContextConfig {
+	concurrency := 4
+	maxFileSize := 1 << 20
+	maxFiles := 10000
+	return SecretInContextConfig{
+		Concurrency: &concurrency,
+		MaxFileSize: &maxFileSize,
+		MaxFiles:    &maxFiles,
+	}
+}
+
+// SecretInContextRule scans the build context files that COPY and ADD send to
+// the builder with the gitleaks detectors. Unlike secrets-in-code, which only
+// looks at single files whose content is observable, it walks copied
+// directories too, honoring .dockerignore.
+//
+// The rule is off by default: it reads files from disk, so it only runs when
+// enabled and when a local build context is given (--context, Bake, Compose).
+type SecretInContextRule struct {
+	schema map[string]any
+}
+
+// NewSecretInContextRule creates a new secret-in-context rule instance.
+func NewSecretInContextRule() *SecretInContextRule {
+	schema, err := configutil.RuleSchema(SecretInContextRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &SecretInContextRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *SecretInContextRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            SecretInContextRuleCode,
+		Name:            "Secrets in build context files",
+		Description:     "Build context files sent by COPY/ADD contain hardcoded secrets",
+		DocURL:          rules.TallyDocURL(SecretInContextRuleCode),
+		DefaultSeverity: rules.SeverityOff,
+		Category:        "security",
+		IsExperimental:  false,
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *SecretInContextRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *SecretInContextRule) DefaultConfig() any {
+	return DefaultSecretInContextConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema.
+func (r *SecretInContextRule) ValidateConfig(config any) error {
+	return configutil.ValidateRuleOptions(SecretInContextRuleCode, config)
+}
+
+// resolveConfig extracts the config from input, falling back to defaults.
+func (r *SecretInContextRule) resolveConfig(config any) SecretInContextConfig {
+	cfg := configutil.Coerce(config, DefaultSecretInContextConfig())
+	defaults := DefaultSecretInContextConfig()
+	if cfg.Concurrency == nil || *cfg.Concurrency < 1 {
+		cfg.Concurrency = defaults.Concurrency
+	}
+	if cfg.MaxFileSize == nil || *cfg.MaxFileSize < 1 {
+		cfg.MaxFileSize = defaults.MaxFileSize
+	}
+	if cfg.MaxFiles == nil || *cfg.MaxFiles < 1 {
+		cfg.MaxFiles = defaults.MaxFiles
+	}
+	return cfg
+}
+
+// contextFileWalker lists the files a COPY/ADD source sends. It is
+// implemented by the local build context reader.
+type contextFileWalker interface {
+	Glob(pattern string) ([]string, error)
+	WalkFiles(root string, fn func(path string, size int64) error) error
+}
+
+// contextFileScanner is the part of the build context the rule needs.
+type contextFileScanner interface {
+	contextFileWalker
+	OpenFile(path string) (io.ReadCloser, error)
+}
+
+// contextScanFile is a build context file to scan, with the instruction that
+// first sends it.
+type contextScanFile struct {
+	path        string
+	stageIdx    int
+	instruction string
+	location    []parser.Range
+}
+
+// Check runs the secret-in-context rule.
+func (r *SecretInContextRule) Check(input rules.LintInput) []rules.Violation {
+	return r.CheckContext(context.Background(), input)
+}
+
+// CheckContext runs the secret-in-context rule with caller cancellation.
+func (r *SecretInContextRule) CheckContext(ctx context.Context, input rules.LintInput) []rules.Violation {
+	scanner, ok := input.Facts.ContextFiles().(contextFileScanner)
+	if !ok {
+		return nil
+	}
+	detector := sharedGitleaksDetector()
+	if detector == nil {
+		return nil
+	}
+
+	cfg := r.resolveConfig(input.Config)
+	// secrets-in-code already scans single files whose content is observable.
+	skipObservable := input.IsRuleEnabled(SecretsInCodeRuleCode)
+	files := collectContextScanFiles(input, scanner, int64(*cfg.MaxFileSize), *cfg.MaxFiles, skipObservable)
+	findings := scanContextFiles(ctx, scanner, detector, files, *cfg.Concurrency, int64(*cfg.MaxFileSize))
+
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for i, file := range files {
+		for _, finding := range findings[i] {
+			desc := strings.TrimSuffix(finding.Description, ".")
+			if desc == "" {
+				desc = "Potential secret detected"
+			}
+			// gitleaks counts lines from 0.
+			v := rules.NewViolation(
+				rules.NewLocationFromRanges(input.File, file.location),
+				meta.Code,
+				fmt.Sprintf("build context file %s:%d sent by %s contains a secret (%s)",
+					file.path, finding.StartLine+1, file.instruction, finding.RuleID),
+				rules.SeverityError,
+			).WithDocURL(meta.DocURL).WithDetail(
+				desc + ". Found: " + redact(finding.Secret) + ". " +
+					"Files sent with COPY/ADD end up in the image layers. Remove the secret from the file, " +
+					"exclude the file in .dockerignore, or pass it with --mount=type=secret.",
+			)
+			v.StageIndex = file.stageIdx
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// collectContextScanFiles lists the build context files sent by the COPY and
+// ADD instructions of all stages, in instruction order and without
+// duplicates. Files larger than maxFileSize are skipped, and at most maxFiles
+// are returned.
+func collectContextScanFiles(
+	input rules.LintInput,
+	scanner contextFileScanner,
+	maxFileSize int64,
+	maxFiles int,
+	skipObservable bool,
+) []contextScanFile {
+	var files []contextScanFile
+	seen := make(map[string]bool)
+	for stageIdx := range input.Stages {
+		stageFacts := input.Facts.Stage(stageIdx)
+		if stageFacts == nil {
+			continue
+		}
+		for _, src := range stageFacts.BuildContextSources {
+			if len(files) >= maxFiles {
+				return files
+			}
+			if src == nil || !src.AvailableInContext || (skipObservable && src.ObservableFileSourcePath != "") {
+				continue
+			}
+			for _, root := range contextSourceRoots(scanner, src) {
+				_ = scanner.WalkFiles(root, func(path string, size int64) error {
+					if len(files) >= maxFiles {
+						return filepath.SkipAll
+					}
+					if seen[path] || size > maxFileSize {
+						return nil
+					}
+					seen[path] = true
+					files = append(files, contextScanFile{
+						path:        path,
+						stageIdx:    stageIdx,
+						instruction: fmt.Sprintf("%s at line %d", strings.ToUpper(src.Instruction), src.Line),
+						location:    src.Location,
+					})
+					return nil
+				})
+			}
+		}
+	}
+	return files
+}
+
+// contextSourceRoots returns the context-relative paths a COPY/ADD source
+// sends, expanding wildcards.
+func contextSourceRoots(scanner contextFileWalker, src *facts.BuildContextSource) []string {
+	// Absolute sources are resolved against the context root.
+	root := strings.TrimPrefix(src.NormalizedSourcePath, "/")
+	if root == "" {
+		root = "."
+	}
+	if !strings.ContainsAny(root, "*?[") {
+		return []string{root}
+	}
+	matches, err := scanner.Glob(root)
+	if err != nil {
+		return nil
+	}
+	return matches
+}
+
+// scanContextFiles runs the detector over the files with at most concurrency
+// files in flight, returning the findings for each file by index. Files that
+// cannot be read, are binary, or grew past maxFileSize have no findings.
+// Files not yet started when ctx is canceled are not scanned.
+func scanContextFiles(
+	ctx context.Context,
+	scanner contextFileScanner,
+	detector *detect.Detector,
+	files []contextScanFile,
+	concurrency int,
+	maxFileSize int64,
+) [][]report.Finding {
+	findings := make([][]report.Finding, len(files))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return findings
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			content, ok := readContextTextFile(scanner, files[i].path, maxFileSize)
+			if !ok {
+				return
+			}
+			findings[i] = detector.DetectString(content)
+		}(i)
+	}
+	wg.Wait()
+	return findings
+}
+
+// readContextTextFile reads a build context file, reporting false for files
+// that cannot be read, exceed maxFileSize, or look binary.
+func readContextTextFile(scanner contextFileScanner, path string, maxFileSize int64) (string, bool) {
+	rc, err := scanner.OpenFile(path)
+	if err != nil {
+		return "", false
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil || int64(len(content)) > maxFileSize {
+		return "", false
+	}
+	if bytes.IndexByte(content[:min(len(content), binarySniffLen)], 0) >= 0 {
+		return "", false
+	}
+	return string(content), true
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewSecretInContextRule())
+}
@@ -0,0 +1,214 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/util/suggest"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/invocation"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/runmount"
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// SecretMountMisuseRuleCode is the full rule code for secret-mount-misuse.
+const SecretMountMisuseRuleCode = rules.TallyRulePrefix + "secret-mount-misuse"
+
+// mountOptionKeys are the mount options BuildKit accepts, for suggestions.
+var mountOptionKeys = []string{
+	"type", "from", "source", "target", "readonly", "id", "sharing", "required", "size", "mode",
+	"uid", "gid", "src", "dst", "destination", "ro", "rw", "readwrite", "env",
+}
+
+// SecretMountMisuseRule checks RUN --mount=type=secret usage: secrets
+// written to files that stay in the image layer, misspelled options and
+// invalid required values that BuildKit rejects, and secret ids that the
+// Bake target or Compose service does not provide.
+type SecretMountMisuseRule struct{}
+
+// NewSecretMountMisuseRule creates a new rule instance.
+func NewSecretMountMisuseRule() *SecretMountMisuseRule {
+	return &SecretMountMisuseRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SecretMountMisuseRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            SecretMountMisuseRuleCode,
+		Name:            "Secret mount misuse",
+		Description:     "Secret mounts must not leak into image layers and need valid options and a provided id",
+		DocURL:          rules.TallyDocURL(SecretMountMisuseRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "security",
+		RelatedTo:       []string{RequireSecretMountsRuleCode, MountSecretInsteadOfCopyRuleCode},
+	}
+}
+
+// Check runs the rule.
+func (r *SecretMountMisuseRule) Check(input rules.LintInput) []rules.Violation {
+	if input.Facts == nil {
+		return nil
+	}
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	declared, declaresSecrets := input.InvocationContext.BuildSecrets()
+
+	var violations []rules.Violation
+	for _, runFacts := range input.Facts.Runs() {
+		if runFacts == nil || runFacts.Run == nil {
+			continue
+		}
+		for _, flag := range runmount.MountFlags(runFacts.Run.Location(), sm, runFacts.EscapeToken) {
+			m := flag.Mount()
+			if m.Type != instructions.MountTypeSecret {
+				continue
+			}
+
+			// Findings of one flag share its range, so they are reported as a
+			// single violation.
+			var messages, details []string
+			report := func(message, detail string) {
+				messages = append(messages, message)
+				if !slices.Contains(details, detail) {
+					details = append(details, detail)
+				}
+			}
+
+			checkSecretMountOptions(flag, report)
+			if declaresSecrets {
+				checkSecretDeclared(m, declared, input.InvocationContext.Invocation().Source, report)
+			}
+			checkSecretWrites(runFacts, m, report)
+			if len(messages) == 0 {
+				continue
+			}
+
+			v := rules.NewViolation(
+				rules.NewRangeLocation(input.File, flag.Line, flag.StartCol, flag.Line, flag.EndCol),
+				meta.Code,
+				strings.Join(messages, "; "),
+				meta.DefaultSeverity,
+			).WithDocURL(meta.DocURL).WithDetail(strings.Join(details, " "))
+			v.StageIndex = runFacts.StageIndex
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// checkSecretMountOptions reports unknown options and required values that
+// are not booleans. BuildKit rejects both, failing the build.
+func checkSecretMountOptions(flag runmount.MountFlag, report func(message, detail string)) {
+	opts, err := flag.Options()
+	if err != nil {
+		return
+	}
+	for _, opt := range opts {
+		switch {
+		case !slices.Contains(mountOptionKeys, opt.Key):
+			message := fmt.Sprintf("unknown option %q in secret mount", opt.Key)
+			if match, ok := suggest.Search(opt.Key, mountOptionKeys, false); ok {
+				message += fmt.Sprintf("; did you mean %q?", match)
+			}
+			report(message, "BuildKit rejects mounts with unknown options, so the build fails.")
+		case opt.Key == "required" && opt.HasValue && !strings.Contains(opt.Value, "$"):
+			if _, err := strconv.ParseBool(opt.Value); err != nil {
+				report(
+					fmt.Sprintf("required=%s in secret mount is not a boolean", opt.Value),
+					"BuildKit rejects the mount, so the build fails. Use required=true, required=false "+
+						"or a bare required.",
+				)
+			}
+		}
+	}
+}
+
+// checkSecretDeclared reports a secret id that the Bake target or Compose
+// service does not declare. Ids with variables are skipped.
+func checkSecretDeclared(
+	m *instructions.Mount,
+	declared []invocation.SecretRef,
+	source invocation.InvocationSource,
+	report func(message, detail string),
+) {
+	id := runmount.SecretID(m)
+	if id == "" || strings.Contains(id, "$") {
+		return
+	}
+	if slices.ContainsFunc(declared, func(s invocation.SecretRef) bool {
+		return s.ID == id || s.Target == id
+	}) {
+		return
+	}
+
+	provider := source.Kind
+	switch source.Kind {
+	case invocation.KindBake:
+		provider = "Bake target"
+	case invocation.KindCompose:
+		provider = "Compose service"
+	}
+	if source.Name != "" {
+		provider += fmt.Sprintf(" %q", source.Name)
+	}
+	detail := "The mount stays empty, so commands that read the secret run without it. "
+	if m.Required {
+		detail = "The mount is required, so the build fails. "
+	}
+	report(
+		fmt.Sprintf("secret %q is not provided by %s", id, provider),
+		detail+"Declare the secret in the build's secrets, or fix the id.",
+	)
+}
+
+// checkSecretWrites reports commands that write the secret to a file that
+// stays in the image layer. Files in tmpfs and cache mounts of the same
+// RUN are not part of the layer.
+func checkSecretWrites(runFacts *facts.RunFacts, m *instructions.Mount, report func(message, detail string)) {
+	if !runFacts.UsesShell {
+		return
+	}
+	var paths, envs []string
+	if target := runmount.SecretTarget(m); target != "" {
+		paths = append(paths, target)
+	}
+	if m.Env != nil && *m.Env != "" {
+		envs = append(envs, *m.Env)
+	}
+
+	var scratch []string
+	for _, mount := range runmount.GetMounts(runFacts.Run) {
+		if mount.Type == instructions.MountTypeTmpfs || mount.Type == instructions.MountTypeCache {
+			scratch = append(scratch, facts.ResolveWorkdir(runFacts.Workdir, mount.Target))
+		}
+	}
+
+	id := runmount.SecretID(m)
+	seen := make(map[string]bool)
+	for _, w := range shell.FindSecretWrites(runFacts.CommandScript, runFacts.Shell.Variant, paths, envs) {
+		if seen[w.Dest] {
+			continue
+		}
+		seen[w.Dest] = true
+		if path.IsAbs(w.Dest) && slices.ContainsFunc(scratch, func(dir string) bool {
+			return pathWithin(path.Clean(w.Dest), dir)
+		}) {
+			continue
+		}
+		report(
+			fmt.Sprintf("secret %q is written to %s by %s, which keeps it in the image layer", id, w.Dest, w.Command),
+			"Secret mounts keep secrets out of the image only while the secret stays in the mount. "+
+				"Read the secret where it is needed, or remove the file in the same RUN instruction.",
+		)
+	}
+}
+
+func init() {
+	rules.Register(NewSecretMountMisuseRule())
+}
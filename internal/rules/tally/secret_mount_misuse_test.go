@@ -0,0 +1,171 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/invocation"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestSecretMountMisuseMetadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSecretMountMisuseRule().Metadata())
+}
+
+func TestSecretMountMisuseRule(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewSecretMountMisuseRule(), []testutil.RuleTestCase{
+		{
+			Name: "secret copied into the image",
+			Content: `FROM node:22
+RUN --mount=type=secret,id=npmrc,target=/root/.npmrc cp /root/.npmrc /app/.npmrc && npm ci
+`,
+			WantViolations: 1,
+			WantCodes:      []string{SecretMountMisuseRuleCode},
+			WantMessages:   []string{`secret "npmrc" is written to /app/.npmrc by cp`},
+		},
+		{
+			Name: "default target redirected to a file",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=token cat /run/secrets/token > /etc/app/token
+`,
+			WantViolations: 1,
+			WantMessages:   []string{`secret "token" is written to /etc/app/token by cat`},
+		},
+		{
+			Name: "env secret written to a profile",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=gh,env=GH_TOKEN echo "export GH_TOKEN=$GH_TOKEN" >> /etc/profile.d/gh.sh
+`,
+			WantViolations: 1,
+			WantMessages:   []string{`secret "gh" is written to /etc/profile.d/gh.sh by echo`},
+		},
+		{
+			Name: "file removed in the same RUN",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=token cp /run/secrets/token /tmp/token && ./build.sh && rm /tmp/token
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "write into a tmpfs mount",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=token --mount=type=tmpfs,target=/scratch \
+    cat /run/secrets/token > /scratch/token && ./build.sh /scratch/token
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "secret read in place",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=token,env=TOKEN \
+    --mount=type=secret,id=npmrc,target=/root/.npmrc \
+    curl -H "Authorization: Bearer $TOKEN" -o /app.tar https://example.com/app.tar && npm ci
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "misspelled option",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=token,requried cat /run/secrets/token
+`,
+			WantViolations: 1,
+			WantMessages:   []string{`unknown option "requried" in secret mount; did you mean "required"?`},
+		},
+		{
+			Name: "required value that is not a boolean",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=token,required=flase cat /run/secrets/token
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"required=flase in secret mount is not a boolean"},
+		},
+		{
+			Name: "findings of one flag in one violation",
+			Content: `FROM node:22
+RUN --mount=type=secret,id=npmrc,target=/root/.npmrc,required=yes \
+    cp /root/.npmrc /app/.npmrc && npm ci
+`,
+			WantViolations: 1,
+			WantMessages: []string{
+				`required=yes in secret mount is not a boolean; secret "npmrc" is written to /app/.npmrc by cp`,
+			},
+		},
+		{
+			Name: "valid required values",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=a,required --mount=type=secret,id=b,required=false --mount=type=secret,id=c,required=$REQ true
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "without invocation context ids are not checked",
+			Content: `FROM alpine:3.20
+RUN --mount=type=secret,id=anything,required cat /run/secrets/anything
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "other mount types",
+			Content: `FROM alpine:3.20
+RUN --mount=type=cache,target=/root/.cache,requried cat /root/.cache/x > /app/x
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestSecretMountMisuseRule_Location(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20
+RUN --mount=type=cache,target=/var/cache/apk \
+    --mount=type=secret,id=token,requried cat /run/secrets/token
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	violations := NewSecretMountMisuseRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1: %v", len(violations), violations)
+	}
+	loc := violations[0].Location
+	if loc.Start.Line != 3 || loc.Start.Column != 4 || loc.End.Line != 3 || loc.End.Column != 41 {
+		t.Errorf("location = %+v, want line 3 columns 4-41", loc)
+	}
+}
+
+func TestSecretMountMisuseRule_UndeclaredSecrets(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20
+RUN --mount=type=secret,id=npmrc,required \
+    --mount=type=secret,id=token \
+    --mount=type=secret,id=ssh_key \
+    --mount=type=secret,id=$DYNAMIC \
+    ./build.sh
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	input.InvocationContext = invocation.NewContext(&invocation.BuildInvocation{
+		Source: invocation.InvocationSource{Kind: invocation.KindCompose, Name: "api"},
+		Secrets: []invocation.SecretRef{
+			{Scope: invocation.SecretScopeBuild, ID: "ssh_key"},
+			{Scope: invocation.SecretScopeService, ID: "token"},
+		},
+	})
+
+	violations := NewSecretMountMisuseRule().Check(input)
+	want := []string{
+		`secret "npmrc" is not provided by Compose service "api"`,
+		`secret "token" is not provided by Compose service "api"`,
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("got %d violations, want %d: %v", len(violations), len(want), violations)
+	}
+	for i, v := range violations {
+		if v.Message != want[i] {
+			t.Errorf("violation %d message = %q, want %q", i, v.Message, want[i])
+		}
+	}
+	if violations[0].Detail == violations[1].Detail {
+		t.Errorf("required and optional secrets should have different details, got %q", violations[0].Detail)
+	}
+}
@@ -17,6 +17,19 @@ var (
 	gitleaksDetector *detect.Detector
 )
 
+// sharedGitleaksDetector returns the gitleaks detector with the default
+// config, loading it on first use. It returns nil if the config fails to load.
+func sharedGitleaksDetector() *detect.Detector {
+	gitleaksOnce.Do(func() {
+		d, err := detect.NewDetectorDefaultConfig()
+		if err != nil {
+			return
+		}
+		gitleaksDetector = d
+	})
+	return gitleaksDetector
+}
+
 // SecretsInCodeRuleCode is the full rule code for the secrets-in-code rule.
 const SecretsInCodeRuleCode = rules.TallyRulePrefix + "secrets-in-code"
 
@@ -43,14 +56,7 @@ func (r *SecretsInCodeRule) Metadata() rules.RuleMetadata {
 
 // Check scans the Dockerfile for hardcoded secrets.
 func (r *SecretsInCodeRule) Check(input rules.LintInput) []rules.Violation {
-	gitleaksOnce.Do(func() {
-		d, err := detect.NewDetectorDefaultConfig()
-		if err != nil {
-			return
-		}
-		gitleaksDetector = d
-	})
-	if gitleaksDetector == nil {
+	if sharedGitleaksDetector() == nil {
 		return nil
 	}
 
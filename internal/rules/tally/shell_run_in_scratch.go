@@ -32,6 +32,7 @@ func (r *ShellRunInScratchRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(ShellRunInScratchRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		RelatedTo:       []string{CopyFromEmptyScratchStageRuleCode},
 	}
 }
 
@@ -18,7 +18,8 @@ import (
 // SortPackagesRuleCode is the full rule code for the sort-packages rule.
 const SortPackagesRuleCode = rules.TallyRulePrefix + "sort-packages"
 
-// SortPackagesRule enforces alphabetical sorting of packages in install commands.
+// SortPackagesRule enforces alphabetical sorting of packages in install commands
+// and flags packages listed more than once.
 type SortPackagesRule struct{}
 
 // NewSortPackagesRule creates a new sort-packages rule instance.
@@ -38,12 +39,13 @@ func (r *SortPackagesRule) Metadata() rules.RuleMetadata {
 	return rules.RuleMetadata{
 		Code:            SortPackagesRuleCode,
 		Name:            "Sort Packages",
-		Description:     "Package lists in install commands should be sorted alphabetically",
+		Description:     "Package lists in install commands should be sorted alphabetically and free of duplicates",
 		DocURL:          rules.TallyDocURL(SortPackagesRuleCode),
 		DefaultSeverity: rules.SeverityStyle,
 		Category:        "style",
 		IsExperimental:  false,
 		FixPriority:     9, // Before no-multi-spaces (10) to avoid edit conflicts
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
@@ -209,7 +211,8 @@ func packageSortOrder(a, b shell.PackageArg) int {
 	return strings.Compare(sortKey(a.Normalized), sortKey(b.Normalized))
 }
 
-// checkInstallCommand checks a single install command for unsorted packages.
+// checkInstallCommand checks a single install command for unsorted or
+// duplicated packages.
 func (r *SortPackagesRule) checkInstallCommand(
 	ic shell.InstallCommand,
 	startLine int,
@@ -241,33 +244,51 @@ func (r *SortPackagesRule) checkInstallCommand(
 			break
 		}
 	}
-	if alreadySorted {
+	dups := ic.DuplicatePackages()
+	if alreadySorted && len(dups) == 0 {
 		return nil
 	}
 
-	// Build the full desired order for edits: sorted literals first, then
-	// variables in original relative order.
-	fullSorted := make([]shell.PackageArg, 0, len(ic.Packages))
-	fullSorted = append(fullSorted, sorted...)
-	for _, pkg := range ic.Packages {
-		if pkg.IsVar {
-			fullSorted = append(fullSorted, pkg)
+	// Drop repeated packages from the desired order, keeping the first
+	// occurrence. Only exact repeats are dropped: "curl" and "curl=7.88.1"
+	// request different things, so choosing between them is left to the user.
+	unique := make([]shell.PackageArg, 0, len(sorted))
+	kept := make(map[string]bool, len(sorted))
+	for _, pkg := range sorted {
+		if !kept[pkg.Normalized] {
+			kept[pkg.Normalized] = true
+			unique = append(unique, pkg)
 		}
 	}
 
-	// Build edits using insert+delete: replace the first literal with the
-	// sorted block, delete remaining literals. Variable tokens are never
-	// touched, avoiding conflicts with rules like SC2086.
-	// For multi-line, each sorted literal is placed at the corresponding
-	// literal's original line position to preserve continuation formatting.
-	edits := r.buildEdits(ic.Packages, fullSorted, startLine, cmdStartCol, src)
-
-	msg := fmt.Sprintf("packages in %s %s are not sorted alphabetically", ic.Manager, ic.Subcommand)
+	// Variable tokens are never touched by the edits, avoiding conflicts
+	// with rules like SC2086.
+	edits := r.buildEdits(ic.Packages, unique, startLine, cmdStartCol, src)
+
+	msg, desc := fmt.Sprintf("packages in %s %s are not sorted alphabetically", ic.Manager, ic.Subcommand),
+		"Sort packages alphabetically"
+	if len(dups) > 0 {
+		names := make([]string, 0, len(dups))
+		for _, d := range dups {
+			if !slices.Contains(names, d.Normalized) {
+				names = append(names, d.Normalized)
+			}
+		}
+		if alreadySorted {
+			msg = fmt.Sprintf("packages in %s %s contain duplicates: %s",
+				ic.Manager, ic.Subcommand, strings.Join(names, ", "))
+			desc = "Remove duplicate packages"
+		} else {
+			msg = fmt.Sprintf("packages in %s %s are not sorted alphabetically and contain duplicates: %s",
+				ic.Manager, ic.Subcommand, strings.Join(names, ", "))
+			desc = "Sort packages alphabetically and remove duplicates"
+		}
+	}
 
 	v := rules.NewViolation(loc, meta.Code, msg, meta.DefaultSeverity).
 		WithDocURL(meta.DocURL).
 		WithSuggestedFix(&rules.SuggestedFix{
-			Description: "Sort packages alphabetically",
+			Description: desc,
 			Safety:      rules.FixSafe,
 			Priority:    meta.FixPriority,
 			Edits:       edits,
@@ -277,15 +298,21 @@ func (r *SortPackagesRule) checkInstallCommand(
 	return &v
 }
 
-// buildEdits generates insert+delete edits to sort packages. Replaces the first
-// literal with the sorted block, deletes remaining literals. Variable tokens are
-// never edited — they naturally end up at the tail.
+// buildEdits generates edits that rewrite the literal packages of original
+// into sorted, which holds the desired literals (possibly fewer than the
+// original after de-duplication).
+//
+// When the literals span several lines, each literal slot is rewritten in
+// place with the sorted literal of the same rank, so the one-per-line layout
+// and its continuations are preserved; surplus slots are deleted.
 //
-// When the first package is a variable, a zero-width insert before it is used
-// instead (no overlap with literal deletes at different positions).
+// Otherwise the first literal is replaced with the whole sorted block and the
+// remaining literals are deleted. When the first package is a variable, a
+// zero-width insert before it is used instead (no overlap with literal
+// deletes at different positions). Variables thus end up at the tail.
 //
-// For multi-line commands, also emits cleanup edits to remove continuation lines
-// left empty after literal deletions.
+// Emits cleanup edits to remove continuation lines left empty after literal
+// deletions.
 func (r *SortPackagesRule) buildEdits(
 	original []shell.PackageArg,
 	sorted []shell.PackageArg,
@@ -293,60 +320,69 @@ func (r *SortPackagesRule) buildEdits(
 	cmdStartCol int,
 	src sourceContext,
 ) []rules.TextEdit {
-	nLiterals := 0
-	for _, pkg := range original {
-		if !pkg.IsVar {
-			nLiterals++
+	edits := make([]rules.TextEdit, 0, len(original)+1)
+
+	// docRange converts a package position to a Dockerfile range. Deletions
+	// also remove the separating space before the package.
+	docRange := func(pkg shell.PackageArg, deletion bool) rules.Location {
+		docLine := startLine + pkg.Line
+		docStartCol := pkg.StartCol
+		docEndCol := pkg.EndCol
+		if pkg.Line == 0 {
+			docStartCol += cmdStartCol
+			docEndCol += cmdStartCol
+		}
+		if deletion && docStartCol > 0 {
+			docStartCol--
+		}
+		return rules.NewRangeLocation(src.file, docLine, docStartCol, docLine, docEndCol)
+	}
+
+	// Track which shell-lines have a literal deleted (for cleanup).
+	deletedOnLine := make(map[int]int) // shell line → count of deletions
+	insertLine := -1                   // shell line receiving the sorted block
+
+	if literalsSpanLines(original) {
+		rank := 0
+		for _, pkg := range original {
+			if pkg.IsVar {
+				continue
+			}
+			if rank < len(sorted) {
+				if pkg.Value != sorted[rank].Value {
+					edits = append(edits, rules.TextEdit{Location: docRange(pkg, false), NewText: sorted[rank].Value})
+				}
+			} else {
+				edits = append(edits, rules.TextEdit{Location: docRange(pkg, true), NewText: ""})
+				deletedOnLine[pkg.Line]++
+			}
+			rank++
 		}
+		return append(edits, cleanupAfterDeletions(original, deletedOnLine, insertLine, startLine, src)...)
 	}
-	edits := make([]rules.TextEdit, 0, nLiterals+1)
 
 	// Build sorted literal text.
 	var sb strings.Builder
-	for i := range nLiterals {
+	for i, pkg := range sorted {
 		if i > 0 {
 			sb.WriteByte(' ')
 		}
-		sb.WriteString(sorted[i].Value)
+		sb.WriteString(pkg.Value)
 	}
 	insertText := sb.String()
 
-	// Track which shell-lines have a literal deleted (for cleanup).
-	deletedOnLine := make(map[int]int) // shell line → count of deletions
-	insertLine := -1                   // shell line receiving the sorted block
-
 	if original[0].IsVar {
 		// Zero-width insert before the first package (a variable).
-		first := original[0]
-		docLine := startLine + first.Line
-		insertCol := first.StartCol
-		if first.Line == 0 {
-			insertCol += cmdStartCol
-		}
-		edits = append(edits, rules.TextEdit{
-			Location: rules.NewRangeLocation(src.file, docLine, insertCol, docLine, insertCol),
-			NewText:  insertText + " ",
-		})
-		insertLine = first.Line
+		first := docRange(original[0], false)
+		first.End = first.Start
+		edits = append(edits, rules.TextEdit{Location: first, NewText: insertText + " "})
+		insertLine = original[0].Line
 		// Delete ALL literals including the preceding space.
 		for _, pkg := range original {
 			if pkg.IsVar {
 				continue
 			}
-			docLine := startLine + pkg.Line
-			docStartCol := pkg.StartCol
-			docEndCol := pkg.EndCol
-			if pkg.Line == 0 {
-				docStartCol += cmdStartCol
-				docEndCol += cmdStartCol
-			}
-			if docStartCol > 0 {
-				docStartCol--
-			}
-			edits = append(edits, rules.TextEdit{
-				Location: rules.NewRangeLocation(src.file, docLine, docStartCol, docLine, docEndCol),
-				NewText:  "",
-			})
+			edits = append(edits, rules.TextEdit{Location: docRange(pkg, true), NewText: ""})
 			deletedOnLine[pkg.Line]++
 		}
 	} else {
@@ -356,28 +392,12 @@ func (r *SortPackagesRule) buildEdits(
 			if pkg.IsVar {
 				continue
 			}
-			docLine := startLine + pkg.Line
-			docStartCol := pkg.StartCol
-			docEndCol := pkg.EndCol
-			if pkg.Line == 0 {
-				docStartCol += cmdStartCol
-				docEndCol += cmdStartCol
-			}
 			if !firstLitDone {
-				edits = append(edits, rules.TextEdit{
-					Location: rules.NewRangeLocation(src.file, docLine, docStartCol, docLine, docEndCol),
-					NewText:  insertText,
-				})
+				edits = append(edits, rules.TextEdit{Location: docRange(pkg, false), NewText: insertText})
 				insertLine = pkg.Line
 				firstLitDone = true
 			} else {
-				if docStartCol > 0 {
-					docStartCol--
-				}
-				edits = append(edits, rules.TextEdit{
-					Location: rules.NewRangeLocation(src.file, docLine, docStartCol, docLine, docEndCol),
-					NewText:  "",
-				})
+				edits = append(edits, rules.TextEdit{Location: docRange(pkg, true), NewText: ""})
 				deletedOnLine[pkg.Line]++
 			}
 		}
@@ -389,10 +409,27 @@ func (r *SortPackagesRule) buildEdits(
 	return edits
 }
 
+// literalsSpanLines reports whether the literal packages are spread over more
+// than one line.
+func literalsSpanLines(pkgs []shell.PackageArg) bool {
+	line := -1
+	for _, pkg := range pkgs {
+		if pkg.IsVar {
+			continue
+		}
+		if line >= 0 && pkg.Line != line {
+			return true
+		}
+		line = pkg.Line
+	}
+	return false
+}
+
 // cleanupAfterDeletions emits edits to remove continuation lines left
-// completely empty after literal deletions. For each empty line, removes
-// the trailing backslash + whitespace from the previous line through the
-// end of the empty line.
+// completely empty after literal deletions. An empty line that itself ends
+// with a continuation is removed whole; otherwise the trailing backslash +
+// whitespace from the previous line through the end of the empty line is
+// removed.
 func cleanupAfterDeletions(
 	original []shell.PackageArg,
 	deletedOnLine map[int]int,
@@ -419,6 +456,19 @@ func cleanupAfterDeletions(
 		if prevIdx < 0 || prevIdx >= len(src.instrLines) || shellLine >= len(src.instrLines) {
 			continue
 		}
+		if !onlyPackagesOnLine(src.instrLines[shellLine], shellLine, original, src.escapeToken) {
+			continue // flags or other words remain on the line
+		}
+		// The emptied line continues onto the next one: drop it whole and
+		// keep the previous line's continuation.
+		if ownLine := strings.TrimRight(src.instrLines[shellLine], " \t"); ownLine != "" &&
+			ownLine[len(ownLine)-1] == byte(src.escapeToken) {
+			edits = append(edits, rules.TextEdit{
+				Location: rules.NewRangeLocation(src.file, startLine+shellLine, 0, startLine+shellLine+1, 0),
+				NewText:  "",
+			})
+			continue
+		}
 		prevLine := src.instrLines[prevIdx]
 		trimmed := strings.TrimRight(prevLine, " \t")
 		if trimmed == "" || trimmed[len(trimmed)-1] != byte(src.escapeToken) {
@@ -437,6 +487,22 @@ func cleanupAfterDeletions(
 	return edits
 }
 
+// onlyPackagesOnLine reports whether line holds nothing but the packages
+// located on shellLine, whitespace and a trailing continuation.
+func onlyPackagesOnLine(line string, shellLine int, pkgs []shell.PackageArg, escapeToken rune) bool {
+	rest := []byte(line)
+	for _, pkg := range pkgs {
+		if pkg.Line != shellLine || pkg.EndCol > len(rest) {
+			continue
+		}
+		for i := pkg.StartCol; i < pkg.EndCol; i++ {
+			rest[i] = ' '
+		}
+	}
+	trimmed := strings.TrimSpace(string(rest))
+	return trimmed == "" || trimmed == string(escapeToken)
+}
+
 // sortKey extracts a case-insensitive sort key from a package name by stripping
 // version specifiers. Examples:
 //   - "flask==2.0" → "flask"
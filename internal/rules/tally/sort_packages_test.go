@@ -130,6 +130,28 @@ func TestSortPackagesCheck(t *testing.T) {
 			WantViolations: 1,
 			WantMessages:   []string{"packages in apt-get install are not sorted"},
 		},
+		{
+			Name:           "sorted with duplicate",
+			Content:        "FROM alpine:3.20\nRUN apk add curl curl git\n",
+			WantViolations: 1,
+			WantMessages:   []string{"packages in apk add contain duplicates: curl"},
+		},
+		{
+			Name:           "unsorted with duplicates",
+			Content:        "FROM fedora:39\nRUN dnf install -y wget curl 'wget'\n",
+			WantViolations: 1,
+			WantMessages:   []string{"packages in dnf install are not sorted alphabetically and contain duplicates: wget"},
+		},
+		{
+			Name:           "same package with different pins is not a duplicate",
+			Content:        "FROM python:3.12\nRUN pip install flask flask==2.0\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "repeated variables are not duplicates",
+			Content:        "FROM alpine:3.20\nRUN apk add curl $PKG $PKG\n",
+			WantViolations: 0,
+		},
 		{
 			Name:           "multiple install commands in one RUN",
 			Content:        "FROM alpine:3.20\nRUN apt-get install -y zoo foo && pip install flask django\n",
@@ -159,9 +181,9 @@ func TestSortPackagesFix(t *testing.T) {
 			want:    "FROM alpine:3.20\nRUN apt-get install -y bar foo zoo\n",
 		},
 		{
-			name:    "multi-line",
+			name:    "multi-line keeps one package per line",
 			content: "FROM alpine:3.20\nRUN apt-get install -y \\\n    zoo \\\n    foo\n",
-			want:    "FROM alpine:3.20\nRUN apt-get install -y \\\n    foo zoo\n",
+			want:    "FROM alpine:3.20\nRUN apt-get install -y \\\n    foo \\\n    zoo\n",
 		},
 		{
 			name:    "mixed literals and variables - vars at tail",
@@ -174,16 +196,50 @@ func TestSortPackagesFix(t *testing.T) {
 			want:    "FROM python:3.12\nRUN uv pip install aws-otel otel polars==1.2.3 $CDK_DEPS $RUNTIME_DEPS\n",
 		},
 		{
-			name: "multi-line mixed - sorted literals then vars",
+			name: "multi-line mixed - literal slots sorted in place",
 			content: "FROM python:3.12\nRUN pip install \\\n" +
 				"  foo zoo \\\n" +
 				"  boo abbr $TADA oops \\\n" +
 				"  $END \\\n" +
 				"  almost there\n",
 			want: "FROM python:3.12\nRUN pip install \\\n" +
-				"  abbr almost boo foo oops there zoo \\\n" +
-				"  $TADA \\\n" +
-				"  $END\n",
+				"  abbr almost \\\n" +
+				"  boo foo $TADA oops \\\n" +
+				"  $END \\\n" +
+				"  there zoo\n",
+		},
+		{
+			name:    "duplicates removed",
+			content: "FROM alpine:3.20\nRUN apk add --no-cache curl git curl\n",
+			want:    "FROM alpine:3.20\nRUN apk add --no-cache curl git\n",
+		},
+		{
+			name:    "unsorted with duplicates keeps version pins",
+			content: "FROM alpine:3.20\nRUN apt-get install -y wget=1.21 curl wget=1.21\n",
+			want:    "FROM alpine:3.20\nRUN apt-get install -y curl wget=1.21\n",
+		},
+		{
+			name: "multi-line duplicates drop surplus lines",
+			content: "FROM alpine:3.20\nRUN apt-get install -y \\\n" +
+				"    zlib1g \\\n" +
+				"    curl \\\n" +
+				"    zlib1g \\\n" +
+				"    ca-certificates \\\n" +
+				"    && rm -rf /var/lib/apt/lists/*\n",
+			want: "FROM alpine:3.20\nRUN apt-get install -y \\\n" +
+				"    ca-certificates \\\n" +
+				"    curl \\\n" +
+				"    zlib1g \\\n" +
+				"    && rm -rf /var/lib/apt/lists/*\n",
+		},
+		{
+			name: "multi-line duplicate sharing a line with flags",
+			content: "FROM alpine:3.20\nRUN apt-get install -y \\\n" +
+				"    curl \\\n" +
+				"    curl --no-install-recommends\n",
+			want: "FROM alpine:3.20\nRUN apt-get install -y \\\n" +
+				"    curl \\\n" +
+				"    --no-install-recommends\n",
 		},
 	}
 
@@ -0,0 +1,225 @@
+package tally
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/rules/buildkit/fixes"
+	"github.com/wharflab/tally/internal/rules/configutil"
+	"github.com/wharflab/tally/internal/runmount"
+)
+
+// StageNameConventionsRuleCode is the full rule code for the stage-name-conventions rule.
+const StageNameConventionsRuleCode = rules.TallyRulePrefix + "stage-name-conventions"
+
+// Stage name case conventions.
+const (
+	stageNameCaseKebab = "kebab-case"
+	stageNameCaseSnake = "snake_case"
+	stageNameCaseAny   = "any"
+)
+
+var (
+	kebabStageName = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+	snakeStageName = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+)
+
+// StageNameConventionsConfig is the configuration for the stage-name-conventions rule.
+type StageNameConventionsConfig struct {
+	// Case is the word separator convention: "kebab-case", "snake_case", or "any".
+	Case string `json:"case,omitempty"`
+
+	// Pattern is a regular expression every stage name must also match.
+	// It is matched against the lowercased name, as BuildKit stores it.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// DefaultStageNameConventionsConfig returns the default configuration.
+func DefaultStageNameConventionsConfig() StageNameConventionsConfig {
+	return StageNameConventionsConfig{Case: stageNameCaseKebab}
+}
+
+// StageNameConventionsRule enforces a naming convention for stage names.
+// BuildKit's StageNameCasing only checks for uppercase letters; this rule
+// checks word separators and an optional project-specific pattern such as
+// required "build-" prefixes. Off by default; configuring it enables it.
+type StageNameConventionsRule struct {
+	schema map[string]any
+}
+
+// NewStageNameConventionsRule creates a new stage-name-conventions rule instance.
+func NewStageNameConventionsRule() *StageNameConventionsRule {
+	schema, err := configutil.RuleSchema(StageNameConventionsRuleCode)
+	if err != nil {
+		panic(err)
+	}
+	return &StageNameConventionsRule{schema: schema}
+}
+
+// Metadata returns the rule metadata.
+func (r *StageNameConventionsRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            StageNameConventionsRuleCode,
+		Name:            "Stage Name Conventions",
+		Description:     "Stage names should follow the configured naming convention",
+		DocURL:          rules.TallyDocURL(StageNameConventionsRuleCode),
+		DefaultSeverity: rules.SeverityOff, // Off by default, enabled when configured
+		Category:        "style",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
+	}
+}
+
+// Schema returns the JSON Schema for this rule's configuration.
+func (r *StageNameConventionsRule) Schema() map[string]any {
+	return r.schema
+}
+
+// DefaultConfig returns the default configuration for this rule.
+func (r *StageNameConventionsRule) DefaultConfig() any {
+	return DefaultStageNameConventionsConfig()
+}
+
+// ValidateConfig validates the configuration against the rule's JSON Schema
+// and checks that pattern is a valid regular expression.
+func (r *StageNameConventionsRule) ValidateConfig(config any) error {
+	if err := configutil.ValidateRuleOptions(StageNameConventionsRuleCode, config); err != nil {
+		return err
+	}
+	cfg := configutil.Coerce(config, DefaultStageNameConventionsConfig())
+	if cfg.Pattern == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(cfg.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	return nil
+}
+
+// Check reports named stages that break the convention. A rename fix is
+// offered when converting the separators yields a name that satisfies the
+// whole configuration and does not collide with another stage.
+func (r *StageNameConventionsRule) Check(input rules.LintInput) []rules.Violation {
+	cfg := configutil.Coerce(input.Config, DefaultStageNameConventionsConfig())
+	var pattern *regexp.Regexp
+	if cfg.Pattern != "" {
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil // rejected by ValidateConfig
+		}
+		pattern = re
+	}
+
+	meta := r.Metadata()
+	var violations []rules.Violation
+	for i, stage := range input.Stages {
+		if stage.Name == "" {
+			continue
+		}
+		problem := stageNameProblem(stage.Name, cfg.Case, pattern)
+		if problem == "" {
+			continue
+		}
+
+		v := rules.NewViolation(
+			rules.NewLocationFromRanges(input.File, stage.Location),
+			meta.Code,
+			fmt.Sprintf("Stage name %q %s", stage.Name, problem),
+			rules.SeverityStyle,
+		).WithDocURL(meta.DocURL)
+		v.StageIndex = i
+
+		if newName := convertStageNameCase(stage.Name, cfg.Case); newName != stage.Name &&
+			stageNameProblem(newName, cfg.Case, pattern) == "" && canRenameStage(input, i, newName) {
+			if edits := fixes.StageRenameEdits(
+				input.Semantic, i, stage.Name, newName, input.File, input.Source,
+			); len(edits) > 0 {
+				v = v.WithSuggestedFix(&rules.SuggestedFix{
+					Description: fmt.Sprintf("Rename stage '%s' to '%s'", stage.Name, newName),
+					Safety:      rules.FixSuggestion,
+					Edits:       edits,
+					IsPreferred: true,
+				}).WithDetail("Renaming updates FROM and COPY --from references in this Dockerfile. " +
+					"Build scripts that pass --target or bake files that name the stage need the same change.")
+			}
+		}
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// stageNameProblem describes how name breaks the convention, or returns "" if it does not.
+func stageNameProblem(name, nameCase string, pattern *regexp.Regexp) string {
+	switch nameCase {
+	case stageNameCaseKebab:
+		if !kebabStageName.MatchString(name) {
+			return "is not kebab-case"
+		}
+	case stageNameCaseSnake:
+		if !snakeStageName.MatchString(name) {
+			return "is not snake_case"
+		}
+	}
+	if pattern != nil && !pattern.MatchString(name) {
+		return fmt.Sprintf("does not match pattern %q", pattern.String())
+	}
+	return ""
+}
+
+// convertStageNameCase rewrites the word separators in name to follow nameCase.
+// Runs of separators collapse into one and leading/trailing ones are dropped.
+func convertStageNameCase(name, nameCase string) string {
+	var sep string
+	switch nameCase {
+	case stageNameCaseKebab:
+		sep = "-"
+	case stageNameCaseSnake:
+		sep = "_"
+	default:
+		return name
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	return strings.Join(words, sep)
+}
+
+// canRenameStage reports whether stage idx can be renamed to newName without
+// breaking the build: the name must be free and not reserved, and every
+// reference must be one the rename edits cover.
+func canRenameStage(input rules.LintInput, idx int, newName string) bool {
+	if input.Semantic == nil || newName == "" || newName == "scratch" || newName == "context" {
+		return false
+	}
+	oldName := input.Stages[idx].Name
+	for i, stage := range input.Stages {
+		if i != idx && strings.EqualFold(stage.Name, newName) {
+			return false
+		}
+		for _, cmd := range stage.Commands {
+			if run, ok := cmd.(*instructions.RunCommand); ok {
+				for _, m := range runmount.GetMounts(run) {
+					if strings.EqualFold(m.From, oldName) {
+						return false
+					}
+				}
+			}
+		}
+		if info := input.Semantic.StageInfo(i); info != nil {
+			for _, ref := range info.OnbuildCopyFromRefs {
+				if strings.EqualFold(ref.From, oldName) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// init registers the rule with the default registry.
+func init() {
+	rules.Register(NewStageNameConventionsRule())
+}
@@ -0,0 +1,155 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestStageNameConventionsRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewStageNameConventionsRule().Metadata())
+}
+
+func TestStageNameConventionsRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewStageNameConventionsRule(), []testutil.RuleTestCase{
+		{
+			Name:           "kebab-case by default",
+			Content:        "FROM golang:1.25 AS build-env\nFROM alpine AS runtime\n",
+			WantViolations: 0,
+		},
+		{
+			Name:           "snake_case under kebab-case",
+			Content:        "FROM golang:1.25 AS build_env\nFROM alpine AS runtime\n",
+			WantViolations: 1,
+			WantCodes:      []string{StageNameConventionsRuleCode},
+			WantMessages:   []string{`Stage name "build_env" is not kebab-case`},
+		},
+		{
+			Name:           "kebab-case under snake_case",
+			Content:        "FROM golang:1.25 AS build-env\nFROM alpine AS build_tools\n",
+			Config:         StageNameConventionsConfig{Case: "snake_case"},
+			WantViolations: 1,
+			WantMessages:   []string{`Stage name "build-env" is not snake_case`},
+		},
+		{
+			Name:           "dots break both conventions",
+			Content:        "FROM golang:1.25 AS build.env\n",
+			WantViolations: 1,
+			WantMessages:   []string{"is not kebab-case"},
+		},
+		{
+			Name:           "any case ignores separators",
+			Content:        "FROM golang:1.25 AS build_env\nFROM alpine AS run.time\n",
+			Config:         StageNameConventionsConfig{Case: "any"},
+			WantViolations: 0,
+		},
+		{
+			Name:    "pattern requires prefixes",
+			Content: "FROM golang:1.25 AS build-app\nFROM golang:1.25 AS tests\nFROM alpine AS runtime\n",
+			Config: StageNameConventionsConfig{
+				Case:    "kebab-case",
+				Pattern: "^(build|test)-[a-z0-9-]+$|^runtime$",
+			},
+			WantViolations: 1,
+			WantMessages:   []string{`Stage name "tests" does not match pattern`},
+		},
+		{
+			Name:           "unnamed stages are ignored",
+			Content:        "FROM golang:1.25\nFROM alpine\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestStageNameConventionsRule_Fix(t *testing.T) {
+	t.Parallel()
+	content := `FROM golang:1.25 AS build_env
+RUN go build -o /app .
+
+FROM build_env AS test
+RUN go test ./...
+
+FROM alpine
+COPY --from=build_env /app /app
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	violations := NewStageNameConventionsRule().Check(input)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	if violations[0].SuggestedFix == nil {
+		t.Fatal("expected a suggested fix")
+	}
+
+	got := string(fix.ApplyFix([]byte(content), violations[0].PreferredFix()))
+	want := `FROM golang:1.25 AS build-env
+RUN go build -o /app .
+
+FROM build-env AS test
+RUN go test ./...
+
+FROM alpine
+COPY --from=build-env /app /app
+`
+	if got != want {
+		t.Errorf("fixed content =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestStageNameConventionsRule_NoFix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+		config  any
+	}{
+		{
+			name:    "converted name is taken",
+			content: "FROM alpine AS build-env\nFROM alpine AS build_env\n",
+		},
+		{
+			name:    "referenced by RUN --mount",
+			content: "FROM alpine AS build_env\nFROM alpine\nRUN --mount=type=bind,from=build_env,target=/src ls /src\n",
+		},
+		{
+			name:    "converted name still fails the pattern",
+			content: "FROM alpine AS tests_unit\n",
+			config:  StageNameConventionsConfig{Case: "kebab-case", Pattern: "^build-"},
+		},
+		{
+			name:    "converted name is reserved",
+			content: "FROM alpine AS scratch_\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInputWithConfig(t, "Dockerfile", tt.content, tt.config)
+			for _, v := range NewStageNameConventionsRule().Check(input) {
+				if v.SuggestedFix != nil {
+					t.Errorf("unexpected fix %q", v.SuggestedFix.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestStageNameConventionsRule_ValidateConfig(t *testing.T) {
+	t.Parallel()
+	r := NewStageNameConventionsRule()
+	if err := r.ValidateConfig(map[string]any{"case": "snake_case", "pattern": "^build-"}); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := r.ValidateConfig(map[string]any{"pattern": "(unclosed"}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	if err := r.ValidateConfig(map[string]any{"case": "camelCase"}); err == nil {
+		t.Error("expected error for unknown case")
+	}
+}
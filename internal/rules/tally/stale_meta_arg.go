@@ -0,0 +1,302 @@
+package tally
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	dfshell "github.com/moby/buildkit/frontend/dockerfile/shell"
+
+	"github.com/wharflab/tally/internal/dockerfile"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
+)
+
+// StaleMetaArgRuleCode is the full rule code for the stale-meta-arg rule.
+const StaleMetaArgRuleCode = rules.TallyRulePrefix + "stale-meta-arg"
+
+// StaleMetaArgRule detects global ARGs (meta-args) whose value in FROM is
+// not the one the Dockerfile suggests:
+//
+//   - a global ARG used by FROM that a stage redeclares with a different
+//     default, so the stage sees another value than its base image name;
+//   - a global ARG default that expands a global ARG declared after it;
+//   - a FROM that expands a name declared only as a stage ARG, which FROM
+//     never sees.
+//
+// Each violation shows the order in which the values are resolved.
+type StaleMetaArgRule struct{}
+
+// NewStaleMetaArgRule creates a new stale-meta-arg rule instance.
+func NewStaleMetaArgRule() *StaleMetaArgRule {
+	return &StaleMetaArgRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *StaleMetaArgRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            StaleMetaArgRuleCode,
+		Name:            "Stale meta-arg",
+		Description:     "Global ARGs used in FROM that resolve differently than the Dockerfile suggests",
+		DocURL:          rules.TallyDocURL(StaleMetaArgRuleCode),
+		DefaultSeverity: rules.SeverityWarning,
+		Category:        "correctness",
+	}
+}
+
+// metaArg is a global ARG declaration with its default as written and as
+// resolved with the defaults of the global ARGs before it.
+type metaArg struct {
+	name     string
+	value    *string
+	resolved string
+	line     int
+	location []parser.Range
+}
+
+// fromRef is a FROM instruction that expands a variable.
+type fromRef struct {
+	stageIdx int
+	line     int
+	raw      string
+	location []parser.Range
+}
+
+// Check runs the stale-meta-arg rule.
+func (r *StaleMetaArgRule) Check(input rules.LintInput) []rules.Violation {
+	sem := input.Semantic
+	if sem == nil {
+		return nil
+	}
+	lex := dfshell.NewLex(dockerfile.ASTEscapeToken(input.AST))
+	lex.SkipProcessQuotes = true
+	c := &staleMetaArgChecker{file: input.File, lex: lex, meta: r.Metadata(), sem: sem}
+
+	globals := c.checkMetaArgOrder(sem.MetaArgs())
+	froms := fromArgRefs(sem, lex)
+	c.checkStageArgsInFrom(froms, globals)
+	c.checkRedeclaredGlobals(froms, globals)
+	return c.violations
+}
+
+type staleMetaArgChecker struct {
+	meta       rules.RuleMetadata
+	file       string
+	lex        *dfshell.Lex
+	sem        *semantic.Model
+	violations []rules.Violation
+}
+
+// checkMetaArgOrder resolves the global ARGs in order and reports defaults
+// that expand a global ARG declared later. It returns the last declaration
+// of each global ARG.
+func (c *staleMetaArgChecker) checkMetaArgOrder(cmds []instructions.ArgCommand) map[string]metaArg {
+	var all []metaArg
+	for _, cmd := range cmds {
+		for _, kv := range cmd.Args {
+			all = append(all, metaArg{name: kv.Key, value: kv.Value, line: startLine(cmd.Location()), location: cmd.Location()})
+		}
+	}
+
+	globals := make(map[string]metaArg, len(all))
+	env := make(map[string]string, len(all))
+	for i, arg := range all {
+		if arg.value != nil {
+			arg.resolved = c.expand(*arg.value, env)
+			for _, ref := range c.references(*arg.value) {
+				if _, declared := env[ref]; declared {
+					continue
+				}
+				later := slices.IndexFunc(all[i+1:], func(other metaArg) bool { return other.name == ref })
+				if later < 0 {
+					continue
+				}
+				laterArg := all[i+1+later]
+				c.report(0, arg.location,
+					fmt.Sprintf("ARG %s expands $%s before ARG %s is declared on line %d", arg.name, ref, ref, laterArg.line),
+					fmt.Sprintf("Global ARGs are resolved top to bottom, so $%s is empty here and %s defaults to %q. "+
+						"Declare %s before %s. Resolution order: ARG %s=%q (line %d) → ARG %s=%q (line %d).",
+						ref, arg.name, arg.resolved, ref, arg.name,
+						arg.name, arg.resolved, arg.line, ref, derefString(laterArg.value), laterArg.line))
+			}
+		} else if previous, ok := globals[arg.name]; ok {
+			arg.resolved = previous.resolved
+		}
+		env[arg.name] = arg.resolved
+		globals[arg.name] = arg
+		all[i] = arg
+	}
+	return globals
+}
+
+// checkStageArgsInFrom reports FROM instructions that expand a name that is
+// not a global ARG but is declared as ARG inside a stage.
+func (c *staleMetaArgChecker) checkStageArgsInFrom(froms map[string][]fromRef, globals map[string]metaArg) {
+	stageArgs := make(map[string]metaArg)
+	for i := range c.sem.StageCount() {
+		stage := c.sem.Stage(i)
+		for _, cmd := range stage.Commands {
+			arg, ok := cmd.(*instructions.ArgCommand)
+			if !ok {
+				continue
+			}
+			for _, kv := range arg.Args {
+				if _, seen := stageArgs[kv.Key]; !seen {
+					stageArgs[kv.Key] = metaArg{name: kv.Key, value: kv.Value, line: startLine(arg.Location())}
+				}
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(froms)) {
+		if _, global := globals[name]; global {
+			continue
+		}
+		declared, ok := stageArgs[name]
+		if !ok {
+			continue
+		}
+		for _, from := range froms[name] {
+			resolved := c.expand(from.raw, globalDefaults(globals))
+			where := "after the first FROM"
+			if declared.line > from.line {
+				where = "after this FROM"
+			}
+			c.report(from.stageIdx, from.location,
+				fmt.Sprintf("FROM expands $%s, but ARG %s is declared only inside a stage on line %d", name, name, declared.line),
+				fmt.Sprintf("FROM only sees global ARGs, declared before the first FROM. ARG %s is declared %s, "+
+					"so $%s is empty here and the base image resolves to %q. "+
+					"Move ARG %s above the first FROM and redeclare it without a value (ARG %s) in stages that use it.",
+					name, where, name, resolved, name, name))
+		}
+	}
+}
+
+// checkRedeclaredGlobals reports stage ARGs that redeclare a global ARG used
+// by FROM with a different default.
+func (c *staleMetaArgChecker) checkRedeclaredGlobals(froms map[string][]fromRef, globals map[string]metaArg) {
+	defaults := globalDefaults(globals)
+	for i := range c.sem.StageCount() {
+		stage := c.sem.Stage(i)
+		for _, cmd := range stage.Commands {
+			arg, ok := cmd.(*instructions.ArgCommand)
+			if !ok {
+				continue
+			}
+			for _, kv := range arg.Args {
+				global, ok := globals[kv.Key]
+				refs := froms[kv.Key]
+				if !ok || kv.Value == nil || len(refs) == 0 || *kv.Value == derefString(global.value) {
+					continue
+				}
+				from := refs[0]
+				if own := slices.IndexFunc(refs, func(ref fromRef) bool { return ref.stageIdx == i }); own >= 0 {
+					from = refs[own]
+				}
+				value := c.expand(*kv.Value, defaults)
+				image := c.expand(from.raw, defaults)
+				line := startLine(arg.Location())
+				c.report(i, arg.Location(),
+					fmt.Sprintf("ARG %s redeclares global ARG %s used by FROM on line %d with a different default",
+						kv.Key, kv.Key, from.line),
+					fmt.Sprintf("FROM on line %d resolves $%s with the global default %q, but from line %d on $%s defaults to %q, "+
+						"so the stage builds on %s while its instructions see another %s. "+
+						"Redeclare it without a value (ARG %s) to inherit the global default, or rename one of them. "+
+						"Resolution order: ARG %s=%q (line %d) → FROM %s (line %d) → ARG %s=%q (line %d).",
+						from.line, kv.Key, global.resolved, line, kv.Key, value,
+						image, kv.Key, kv.Key,
+						kv.Key, global.resolved, global.line, image, from.line, kv.Key, value, line))
+			}
+		}
+	}
+}
+
+// fromArgRefs returns the FROM instructions that expand each variable name,
+// in stage order. Both the base name and --platform are considered, and so
+// are the global ARGs that the defaults of expanded global ARGs expand.
+func fromArgRefs(sem *semantic.Model, lex *dfshell.Lex) map[string][]fromRef {
+	words := func(word string) []string {
+		res, err := lex.ProcessWordWithMatches(word, dfshell.EnvsFromSlice(nil))
+		if err != nil {
+			return nil
+		}
+		return slices.Sorted(maps.Keys(res.Unmatched))
+	}
+	globalRefs := make(map[string][]string)
+	for _, cmd := range sem.MetaArgs() {
+		for _, kv := range cmd.Args {
+			if kv.Value != nil {
+				globalRefs[kv.Key] = words(*kv.Value)
+			}
+		}
+	}
+
+	refs := make(map[string][]fromRef)
+	for i := range sem.StageCount() {
+		stage := sem.Stage(i)
+		from := fromRef{stageIdx: i, line: startLine(stage.Location), raw: stage.BaseName, location: stage.Location}
+		pending := append(words(stage.BaseName), words(stage.Platform)...)
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+			if slices.ContainsFunc(refs[name], func(ref fromRef) bool { return ref.stageIdx == i }) {
+				continue
+			}
+			refs[name] = append(refs[name], from)
+			pending = append(pending, globalRefs[name]...)
+		}
+	}
+	return refs
+}
+
+// expand expands word with env, leaving unknown variables empty.
+func (c *staleMetaArgChecker) expand(word string, env map[string]string) string {
+	pairs := make([]string, 0, len(env))
+	for k, v := range env {
+		pairs = append(pairs, k+"="+v)
+	}
+	res, err := c.lex.ProcessWordWithMatches(word, dfshell.EnvsFromSlice(pairs))
+	if err != nil {
+		return word
+	}
+	return res.Result
+}
+
+// references returns the variable names a value expands, in sorted order.
+func (c *staleMetaArgChecker) references(value string) []string {
+	res, err := c.lex.ProcessWordWithMatches(value, dfshell.EnvsFromSlice(nil))
+	if err != nil || len(res.Unmatched) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(res.Unmatched))
+}
+
+func (c *staleMetaArgChecker) report(stageIdx int, location []parser.Range, message, detail string) {
+	v := rules.NewViolation(rules.NewLocationFromRanges(c.file, location), c.meta.Code, message, c.meta.DefaultSeverity).
+		WithDocURL(c.meta.DocURL).
+		WithDetail(detail)
+	v.StageIndex = stageIdx
+	c.violations = append(c.violations, v)
+}
+
+// globalDefaults returns the resolved defaults of the global ARGs.
+func globalDefaults(globals map[string]metaArg) map[string]string {
+	env := make(map[string]string, len(globals))
+	for name, arg := range globals {
+		env[name] = arg.resolved
+	}
+	return env
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func init() {
+	rules.Register(NewStaleMetaArgRule())
+}
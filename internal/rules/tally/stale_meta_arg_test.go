@@ -0,0 +1,158 @@
+package tally
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestStaleMetaArgRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewStaleMetaArgRule().Metadata())
+}
+
+func TestStaleMetaArgRule_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		content    string
+		want       []string // expected message substrings, in order
+		wantLine   []int
+		wantDetail string
+	}{
+		{
+			name: "stage redeclares global used by its FROM",
+			content: `ARG PYTHON_VERSION=3.12
+FROM python:${PYTHON_VERSION}-slim
+ARG PYTHON_VERSION=3.11
+RUN echo $PYTHON_VERSION
+`,
+			want:     []string{"ARG PYTHON_VERSION redeclares global ARG PYTHON_VERSION used by FROM on line 2"},
+			wantLine: []int{3},
+			wantDetail: `Resolution order: ARG PYTHON_VERSION="3.12" (line 1) → FROM python:3.12-slim (line 2) → ` +
+				`ARG PYTHON_VERSION="3.11" (line 3).`,
+		},
+		{
+			name: "redeclared in another stage",
+			content: `ARG GO_VERSION=1.25
+FROM golang:${GO_VERSION} AS build
+
+FROM alpine:3.20
+ARG GO_VERSION=1.24
+`,
+			want:     []string{"used by FROM on line 2"},
+			wantLine: []int{5},
+		},
+		{
+			name: "redeclared without value or with the same default",
+			content: `ARG NODE_VERSION=22
+FROM node:${NODE_VERSION}
+ARG NODE_VERSION
+FROM node:$NODE_VERSION AS other
+ARG NODE_VERSION=22
+`,
+		},
+		{
+			name: "global not used by FROM",
+			content: `ARG MODE=release
+FROM alpine:3.20
+ARG MODE=debug
+`,
+		},
+		{
+			name: "platform expands the global",
+			content: `ARG PLATFORM=linux/amd64
+FROM --platform=$PLATFORM alpine:3.20
+ARG PLATFORM=linux/arm64
+`,
+			want:     []string{"ARG PLATFORM redeclares global ARG PLATFORM used by FROM on line 2"},
+			wantLine: []int{3},
+		},
+		{
+			name: "FROM expands the global through another global",
+			content: `ARG PYTHON_VERSION=3.12
+ARG IMAGE=python:${PYTHON_VERSION}-slim
+FROM ${IMAGE}
+ARG PYTHON_VERSION=3.11
+`,
+			want:     []string{"ARG PYTHON_VERSION redeclares global ARG PYTHON_VERSION used by FROM on line 3"},
+			wantLine: []int{4},
+		},
+		{
+			name: "global default expands a later global",
+			content: `ARG IMAGE=alpine:${TAG}
+ARG TAG=3.20
+FROM ${IMAGE}
+`,
+			want:       []string{"ARG IMAGE expands $TAG before ARG TAG is declared on line 2"},
+			wantLine:   []int{1},
+			wantDetail: `Resolution order: ARG IMAGE="alpine:" (line 1) → ARG TAG="3.20" (line 2).`,
+		},
+		{
+			name: "globals in order",
+			content: `ARG TAG=3.20
+ARG IMAGE=alpine:${TAG}
+FROM ${IMAGE}
+`,
+		},
+		{
+			name: "FROM expands a stage ARG",
+			content: `FROM alpine:3.20 AS base
+ARG DEBIAN_TAG=bookworm
+
+FROM debian:${DEBIAN_TAG}
+`,
+			want:       []string{"FROM expands $DEBIAN_TAG, but ARG DEBIAN_TAG is declared only inside a stage on line 2"},
+			wantLine:   []int{4},
+			wantDetail: `the base image resolves to "debian:"`,
+		},
+		{
+			name: "FROM expands an undeclared name",
+			content: `FROM debian:${DEBIAN_TAG}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewStaleMetaArgRule().Check(input)
+			if len(violations) != len(tt.want) {
+				t.Fatalf("got %d violations, want %d: %v", len(violations), len(tt.want), violations)
+			}
+			for i, v := range violations {
+				if !strings.Contains(v.Message, tt.want[i]) {
+					t.Errorf("violation %d message = %q, want substring %q", i, v.Message, tt.want[i])
+				}
+				if v.Line() != tt.wantLine[i] {
+					t.Errorf("violation %d line = %d, want %d", i, v.Line(), tt.wantLine[i])
+				}
+			}
+			if tt.wantDetail != "" && !strings.Contains(violations[0].Detail, tt.wantDetail) {
+				t.Errorf("detail = %q, want substring %q", violations[0].Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestArgEnvShadowingRule_DefersToStaleMetaArg(t *testing.T) {
+	t.Parallel()
+	content := `ARG VERSION=1.0
+ARG MODE=release
+FROM alpine:${VERSION}
+ARG VERSION=2.0
+ARG MODE=debug
+`
+	input := testutil.MakeLintInput(t, "Dockerfile", content)
+	input.EnabledRules = []string{ArgEnvShadowingRuleCode, StaleMetaArgRuleCode}
+
+	violations := NewArgEnvShadowingRule().Check(input)
+	if len(violations) != 1 || !strings.Contains(violations[0].Message, "ARG MODE redefines global ARG MODE") {
+		t.Errorf("violations = %v, want only the redefined MODE", violations)
+	}
+}
@@ -72,6 +72,7 @@ func (r *StatefulRootRuntimeRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(StatefulRootRuntimeRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
+		RelatedTo:       []string{rules.HadolintRulePrefix + "DL3002"},
 	}
 }
 
@@ -0,0 +1,56 @@
+package tally
+
+import (
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/rules"
+)
+
+// SyntaxErrorRuleCode is the full rule code for the syntax-error rule.
+const SyntaxErrorRuleCode = rules.TallyRulePrefix + "syntax-error"
+
+// SyntaxErrorRule reports instructions BuildKit could not parse. The parser
+// drops those instructions so the rest of the file can still be linted, and
+// the linter emits the violations via NewSyntaxErrorViolation; Check itself
+// returns nil.
+type SyntaxErrorRule struct{}
+
+// NewSyntaxErrorRule creates a new syntax-error rule instance.
+func NewSyntaxErrorRule() *SyntaxErrorRule {
+	return &SyntaxErrorRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *SyntaxErrorRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            SyntaxErrorRuleCode,
+		Name:            "Syntax error",
+		Description:     "Instruction cannot be parsed and would fail the build",
+		DocURL:          rules.TallyDocURL(SyntaxErrorRuleCode),
+		DefaultSeverity: rules.SeverityError,
+		Category:        "correctness",
+	}
+}
+
+// Check returns nil — violations are produced from the parser's syntax errors.
+func (r *SyntaxErrorRule) Check(_ rules.LintInput) []rules.Violation {
+	return nil
+}
+
+// NewSyntaxErrorViolation reports an instruction that BuildKit rejected with
+// message at location.
+func NewSyntaxErrorViolation(file, message string, location []parser.Range) rules.Violation {
+	return rules.NewViolation(
+		rules.NewLocationFromRanges(file, location),
+		SyntaxErrorRuleCode,
+		message,
+		rules.SeverityError,
+	).WithDocURL(rules.TallyDocURL(SyntaxErrorRuleCode)).WithDetail(
+		"BuildKit cannot parse this instruction, so the build fails here. The instruction was skipped " +
+			"while linting the rest of the file; findings that depend on it may be missing.",
+	)
+}
+
+func init() {
+	rules.Register(NewSyntaxErrorRule())
+}
@@ -0,0 +1,47 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestSyntaxErrorRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewSyntaxErrorRule().Metadata())
+}
+
+func TestSyntaxErrorRule_Check(t *testing.T) {
+	t.Parallel()
+	testutil.RunRuleTests(t, NewSyntaxErrorRule(), []testutil.RuleTestCase{
+		{
+			Name:           "check never reports",
+			Content:        "FROM alpine:3.20\nCOPY app\n",
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestNewSyntaxErrorViolation(t *testing.T) {
+	t.Parallel()
+
+	loc := []parser.Range{{Start: parser.Position{Line: 2}, End: parser.Position{Line: 2, Character: 8}}}
+	v := NewSyntaxErrorViolation("Dockerfile", "unknown flag: --foo", loc)
+	if v.RuleCode != SyntaxErrorRuleCode {
+		t.Errorf("RuleCode = %q, want %q", v.RuleCode, SyntaxErrorRuleCode)
+	}
+	if v.Severity != rules.SeverityError {
+		t.Errorf("Severity = %v, want error", v.Severity)
+	}
+	if v.Line() != 2 || v.Message != "unknown flag: --foo" {
+		t.Errorf("violation = line %d %q", v.Line(), v.Message)
+	}
+
+	if v := NewSyntaxErrorViolation("Dockerfile", "bad", nil); !v.Location.IsFileLevel() {
+		t.Errorf("Location = %+v, want file-level", v.Location)
+	}
+}
@@ -56,6 +56,7 @@ func (r *UserCreatedButNeverUsedRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(UserCreatedButNeverUsedRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
+		Fixes:           []rules.FixSafety{rules.FixUnsafe},
 	}
 }
 
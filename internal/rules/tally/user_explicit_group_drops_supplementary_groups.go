@@ -56,6 +56,7 @@ func (r *UserExplicitGroupDropsSupplementaryGroupsRule) Metadata() rules.RuleMet
 		DocURL:          rules.TallyDocURL(UserExplicitGroupDropsSupplementaryGroupsRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
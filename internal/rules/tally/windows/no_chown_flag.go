@@ -33,6 +33,7 @@ func (r *NoChownFlagRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(NoChownFlagRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSafe},
 	}
 }
 
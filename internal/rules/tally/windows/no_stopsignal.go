@@ -30,6 +30,7 @@ func (r *NoStopsignalRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(NoStopsignalRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "correctness",
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
 	}
 }
 
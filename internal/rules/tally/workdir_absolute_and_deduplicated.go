@@ -0,0 +1,344 @@
+package tally
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/facts"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/semantic"
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// WorkdirAbsoluteAndDeduplicatedRuleCode is the full rule code for the
+// workdir-absolute-and-deduplicated rule.
+const WorkdirAbsoluteAndDeduplicatedRuleCode = rules.TallyRulePrefix + "workdir-absolute-and-deduplicated"
+
+// WorkdirAbsoluteAndDeduplicatedRule reports WORKDIR instructions that can be
+// simplified because the stage's working directory is known:
+//
+//   - a relative WORKDIR after an absolute one, in the stage or a parent
+//     stage, can name the absolute path it resolves to;
+//   - a WORKDIR to the directory that is already current is redundant;
+//   - consecutive WORKDIRs that each descend into the previous directory
+//     collapse into the last one, which creates its parents anyway.
+//
+// A relative WORKDIR resolved against the base image's WORKDIR is left to
+// buildkit/WorkdirRelativePath, which can look the image config up. WORKDIRs
+// that expand variables or quote their path, and Windows stages, are skipped.
+type WorkdirAbsoluteAndDeduplicatedRule struct{}
+
+// NewWorkdirAbsoluteAndDeduplicatedRule creates a new rule instance.
+func NewWorkdirAbsoluteAndDeduplicatedRule() *WorkdirAbsoluteAndDeduplicatedRule {
+	return &WorkdirAbsoluteAndDeduplicatedRule{}
+}
+
+// Metadata returns the rule metadata.
+func (r *WorkdirAbsoluteAndDeduplicatedRule) Metadata() rules.RuleMetadata {
+	return rules.RuleMetadata{
+		Code:            WorkdirAbsoluteAndDeduplicatedRuleCode,
+		Name:            "WORKDIR absolute and deduplicated",
+		Description:     "WORKDIR should use absolute paths without redundant or chained changes",
+		DocURL:          rules.TallyDocURL(WorkdirAbsoluteAndDeduplicatedRuleCode),
+		DefaultSeverity: rules.SeverityStyle,
+		Category:        "style",
+		Fixes:           []rules.FixSafety{rules.FixSafe, rules.FixSuggestion},
+		RelatedTo:       []string{rules.BuildKitRulePrefix + "WorkdirRelativePath"},
+	}
+}
+
+// workdirState is the working directory at a point in a stage.
+type workdirState struct {
+	// dir is the working directory: absolute once a WORKDIR set it, relative
+	// to the base image's WORKDIR before that, "" for the base image's own.
+	dir string
+	// ok is false after a WORKDIR whose path tally cannot resolve.
+	ok bool
+	// runSince is true when a RUN, which may remove dir, ran since the
+	// WORKDIR that created it.
+	runSince bool
+}
+
+// known reports whether the working directory is an absolute path.
+func (s workdirState) known() bool {
+	return s.ok && path.IsAbs(s.dir)
+}
+
+// workdirStep is a WORKDIR instruction with the directory it selects.
+type workdirStep struct {
+	cmd *instructions.WorkdirCommand
+	dir string
+}
+
+// Check runs the workdir-absolute-and-deduplicated rule.
+func (r *WorkdirAbsoluteAndDeduplicatedRule) Check(input rules.LintInput) []rules.Violation {
+	meta := r.Metadata()
+	sm := input.SourceMap()
+	finals := make([]workdirState, len(input.Stages))
+
+	var violations []rules.Violation
+	for stageIdx, stage := range input.Stages {
+		if isWindowsStage(input.Semantic, stageIdx) {
+			continue
+		}
+		add := func(v rules.Violation) {
+			v.StageIndex = stageIdx
+			violations = append(violations, v)
+		}
+
+		st := inheritedWorkdirState(input.Semantic, stageIdx, finals)
+		var chain []workdirStep
+		flush := func() {
+			for _, v := range r.chainViolations(input.File, sm, chain, meta) {
+				add(v)
+			}
+			chain = nil
+		}
+
+		for _, cmd := range stage.Commands {
+			w, ok := cmd.(*instructions.WorkdirCommand)
+			if !ok {
+				flush()
+				if _, isRun := cmd.(*instructions.RunCommand); isRun {
+					st.runSince = true
+				}
+				continue
+			}
+			if !plainWorkdirPath(w.Path) || len(w.Location()) == 0 {
+				flush()
+				st = workdirState{}
+				continue
+			}
+			if !st.ok && !path.IsAbs(w.Path) {
+				// Still relative to a directory tally cannot resolve.
+				continue
+			}
+
+			dir := facts.ResolveWorkdir(st.dir, w.Path)
+			if st.known() && dir == st.dir {
+				// Redundant WORKDIRs neither join nor end a chain.
+				add(r.redundantViolation(input.File, sm, w, st, meta))
+				continue
+			}
+			chain = append(chain, workdirStep{cmd: w, dir: dir})
+			st = workdirState{dir: dir, ok: true}
+		}
+		flush()
+		finals[stageIdx] = st
+	}
+	return violations
+}
+
+// inheritedWorkdirState returns the working directory a stage starts in:
+// the final one of its parent stage, or the base image's WORKDIR.
+func inheritedWorkdirState(sem *semantic.Model, stageIdx int, finals []workdirState) workdirState {
+	if sem != nil {
+		if info := sem.StageInfo(stageIdx); info != nil && info.BaseImage != nil && info.BaseImage.IsStageRef {
+			if parent := info.BaseImage.StageIndex; parent >= 0 && parent < stageIdx {
+				return finals[parent]
+			}
+			return workdirState{}
+		}
+	}
+	return workdirState{ok: true}
+}
+
+func isWindowsStage(sem *semantic.Model, stageIdx int) bool {
+	if sem == nil {
+		return false
+	}
+	info := sem.StageInfo(stageIdx)
+	return info != nil && info.IsWindows()
+}
+
+// plainWorkdirPath reports whether a WORKDIR path can be resolved and
+// rewritten as is: no variables, quotes, escapes or whitespace.
+func plainWorkdirPath(p string) bool {
+	return p != "" && !strings.ContainsAny(p, "$\"'\\ \t")
+}
+
+// chainViolations reports a run of consecutive WORKDIRs. When every earlier
+// directory contains the last one, the run collapses into the last WORKDIR;
+// otherwise each relative WORKDIR with a known base is reported on its own.
+func (r *WorkdirAbsoluteAndDeduplicatedRule) chainViolations(
+	file string,
+	sm *sourcemap.SourceMap,
+	chain []workdirStep,
+	meta rules.RuleMetadata,
+) []rules.Violation {
+	if len(chain) > 1 && collapsible(chain) {
+		return []rules.Violation{r.collapseViolation(file, sm, chain, meta)}
+	}
+	var out []rules.Violation
+	for _, step := range chain {
+		if !path.IsAbs(step.cmd.Path) && path.IsAbs(step.dir) {
+			out = append(out, r.relativeViolation(file, sm, step, meta))
+		}
+	}
+	return out
+}
+
+// collapsible reports whether each directory of the chain contains the last,
+// so that creating the last one creates them all.
+func collapsible(chain []workdirStep) bool {
+	last := chain[len(chain)-1].dir
+	for _, step := range chain[:len(chain)-1] {
+		if !pathWithin(last, step.dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// pathWithin reports whether p is dir or below it. Both are clean paths.
+func pathWithin(p, dir string) bool {
+	if dir == "" {
+		return !path.IsAbs(p)
+	}
+	return p == dir || strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
+
+func (r *WorkdirAbsoluteAndDeduplicatedRule) relativeViolation(
+	file string,
+	sm *sourcemap.SourceMap,
+	step workdirStep,
+	meta rules.RuleMetadata,
+) rules.Violation {
+	v := rules.NewViolation(rules.NewLocationFromRanges(file, step.cmd.Location()), meta.Code,
+		fmt.Sprintf("relative WORKDIR %s resolves to %s; use the absolute path", step.cmd.Path, step.dir),
+		meta.DefaultSeverity).
+		WithDocURL(meta.DocURL).
+		WithDetail("An absolute WORKDIR reads the same wherever it is moved and does not depend on the WORKDIRs before it.")
+	if edit, ok := workdirPathEdit(file, sm, step.cmd, step.dir); ok {
+		v = v.WithSuggestedFix(&rules.SuggestedFix{
+			Description: "Use WORKDIR " + step.dir,
+			Safety:      rules.FixSafe,
+			Edits:       []rules.TextEdit{edit},
+			IsPreferred: true,
+		})
+	}
+	return v
+}
+
+func (r *WorkdirAbsoluteAndDeduplicatedRule) redundantViolation(
+	file string,
+	sm *sourcemap.SourceMap,
+	w *instructions.WorkdirCommand,
+	st workdirState,
+	meta rules.RuleMetadata,
+) rules.Violation {
+	detail := "WORKDIR only changes the working directory and creates it when missing; this one does neither."
+	// A RUN in between may have removed the directory, which this WORKDIR
+	// would create again.
+	safety := rules.FixSafe
+	if st.runSince {
+		safety = rules.FixSuggestion
+		detail = "The working directory is unchanged. A RUN since the previous WORKDIR may have removed the directory, " +
+			"which this WORKDIR creates again; delete it if that is not the case."
+	}
+	v := rules.NewViolation(rules.NewLocationFromRanges(file, w.Location()), meta.Code,
+		fmt.Sprintf("WORKDIR %s is redundant; the working directory is already %s", w.Path, st.dir),
+		meta.DefaultSeverity).
+		WithDocURL(meta.DocURL).
+		WithDetail(detail)
+	if edit, ok := deleteWorkdirEdit(file, sm, w); ok {
+		v = v.WithSuggestedFix(&rules.SuggestedFix{
+			Description: "Remove the redundant WORKDIR",
+			Safety:      safety,
+			Edits:       []rules.TextEdit{edit},
+			IsPreferred: true,
+		})
+	}
+	return v
+}
+
+func (r *WorkdirAbsoluteAndDeduplicatedRule) collapseViolation(
+	file string,
+	sm *sourcemap.SourceMap,
+	chain []workdirStep,
+	meta rules.RuleMetadata,
+) rules.Violation {
+	first, last := chain[0], chain[len(chain)-1]
+	loc := rules.NewLocationFromRanges(file, first.cmd.Location())
+	loc.End = rules.NewLocationFromRanges(file, last.cmd.Location()).End
+
+	v := rules.NewViolation(loc, meta.Code,
+		fmt.Sprintf("%d consecutive WORKDIR instructions can be collapsed into WORKDIR %s", len(chain), last.dir),
+		meta.DefaultSeverity).
+		WithDocURL(meta.DocURL).
+		WithDetail("Only the last WORKDIR of the run takes effect, and it creates the directories the earlier ones did.")
+
+	edits := make([]rules.TextEdit, 0, len(chain))
+	for _, step := range chain[:len(chain)-1] {
+		edit, ok := deleteWorkdirEdit(file, sm, step.cmd)
+		if !ok {
+			return v
+		}
+		edits = append(edits, edit)
+	}
+	if last.cmd.Path != last.dir {
+		edit, ok := workdirPathEdit(file, sm, last.cmd, last.dir)
+		if !ok {
+			return v
+		}
+		edits = append(edits, edit)
+	}
+	return v.WithSuggestedFix(&rules.SuggestedFix{
+		Description: "Collapse into WORKDIR " + last.dir,
+		Safety:      rules.FixSafe,
+		Edits:       edits,
+		IsPreferred: true,
+	})
+}
+
+// workdirPathEdit replaces the path of a single-line WORKDIR, keeping the
+// keyword as written.
+func workdirPathEdit(
+	file string,
+	sm *sourcemap.SourceMap,
+	w *instructions.WorkdirCommand,
+	dir string,
+) (rules.TextEdit, bool) {
+	line, ok := singleLineInstruction(sm, w)
+	if !ok {
+		return rules.TextEdit{}, false
+	}
+	text := sm.Line(line - 1)
+	start := addKeywordEnd(text)
+	end := len(strings.TrimRight(text, " \t"))
+	if start >= end {
+		return rules.TextEdit{}, false
+	}
+	return rules.TextEdit{
+		Location: rules.NewRangeLocation(file, line, start, line, end),
+		NewText:  " " + dir,
+	}, true
+}
+
+// deleteWorkdirEdit deletes a single-line WORKDIR with its line.
+func deleteWorkdirEdit(file string, sm *sourcemap.SourceMap, w *instructions.WorkdirCommand) (rules.TextEdit, bool) {
+	line, ok := singleLineInstruction(sm, w)
+	if !ok {
+		return rules.TextEdit{}, false
+	}
+	return rules.TextEdit{
+		Location: rules.DeleteLineLocation(file, line, len(sm.Line(line-1)), sm.LineCount()),
+	}, true
+}
+
+// singleLineInstruction returns the line of an instruction that fits on one
+// line; fixes leave continued instructions alone.
+func singleLineInstruction(sm *sourcemap.SourceMap, cmd instructions.Command) (int, bool) {
+	loc := cmd.Location()
+	if sm == nil || len(loc) != 1 || loc[0].Start.Line != loc[0].End.Line {
+		return 0, false
+	}
+	return loc[0].Start.Line, true
+}
+
+func init() {
+	rules.Register(NewWorkdirAbsoluteAndDeduplicatedRule())
+}
@@ -0,0 +1,201 @@
+package tally
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+
+	fixpkg "github.com/wharflab/tally/internal/fix"
+	"github.com/wharflab/tally/internal/rules"
+	"github.com/wharflab/tally/internal/testutil"
+)
+
+func TestWorkdirAbsoluteAndDeduplicatedRule_Metadata(t *testing.T) {
+	t.Parallel()
+	snaps.MatchStandaloneJSON(t, NewWorkdirAbsoluteAndDeduplicatedRule().Metadata())
+}
+
+func TestWorkdirAbsoluteAndDeduplicatedRule_Check(t *testing.T) {
+	t.Parallel()
+
+	testutil.RunRuleTests(t, NewWorkdirAbsoluteAndDeduplicatedRule(), []testutil.RuleTestCase{
+		{
+			Name: "absolute WORKDIRs",
+			Content: `FROM alpine:3.20
+WORKDIR /app
+RUN make
+WORKDIR /srv
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "relative after absolute",
+			Content: `FROM alpine:3.20
+WORKDIR /app
+RUN make
+WORKDIR src
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"relative WORKDIR src resolves to /app/src; use the absolute path"},
+		},
+		{
+			Name: "relative to the base image is left to buildkit",
+			Content: `FROM alpine:3.20
+WORKDIR app
+RUN make
+WORKDIR src
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "redundant WORKDIR",
+			Content: `FROM alpine:3.20
+WORKDIR /app
+COPY . .
+WORKDIR /app/
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"WORKDIR /app/ is redundant; the working directory is already /app"},
+		},
+		{
+			Name: "consecutive WORKDIRs",
+			Content: `FROM alpine:3.20
+WORKDIR /app
+WORKDIR src
+WORKDIR /app/src/cmd
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"3 consecutive WORKDIR instructions can be collapsed into WORKDIR /app/src/cmd"},
+		},
+		{
+			Name: "consecutive WORKDIRs to unrelated directories",
+			Content: `FROM alpine:3.20
+WORKDIR /data
+WORKDIR /app
+`,
+			WantViolations: 0,
+		},
+		{
+			Name: "relative in a chain that does not collapse",
+			Content: `FROM alpine:3.20
+WORKDIR /app/src
+WORKDIR ../data
+`,
+			WantViolations: 1,
+			WantMessages:   []string{"relative WORKDIR ../data resolves to /app/data"},
+		},
+		{
+			Name: "inherited from parent stage",
+			Content: `FROM alpine:3.20 AS base
+WORKDIR /app
+
+FROM base
+WORKDIR /app
+RUN make
+WORKDIR bin
+`,
+			WantViolations: 2,
+			WantMessages:   []string{"WORKDIR /app is redundant", "relative WORKDIR bin resolves to /app/bin"},
+		},
+		{
+			Name: "variables make the directory unknown",
+			Content: `FROM alpine:3.20
+ARG APP_DIR=/app
+WORKDIR $APP_DIR
+WORKDIR src
+RUN make
+WORKDIR $APP_DIR
+`,
+			WantViolations: 0,
+		},
+	})
+}
+
+func TestWorkdirAbsoluteAndDeduplicatedRule_Fix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		content    string
+		want       string
+		wantSafety rules.FixSafety
+	}{
+		{
+			name: "relative to absolute",
+			content: `FROM alpine:3.20
+workdir /app
+RUN make
+workdir ./src
+`,
+			want: `FROM alpine:3.20
+workdir /app
+RUN make
+workdir /app/src
+`,
+			wantSafety: rules.FixSafe,
+		},
+		{
+			name: "collapse chain",
+			content: `FROM alpine:3.20
+WORKDIR /app
+# sources
+WORKDIR src
+RUN make
+`,
+			want: `FROM alpine:3.20
+# sources
+WORKDIR /app/src
+RUN make
+`,
+			wantSafety: rules.FixSafe,
+		},
+		{
+			name: "redundant",
+			content: `FROM alpine:3.20
+WORKDIR /app
+COPY . .
+WORKDIR /app
+CMD ["app"]
+`,
+			want: `FROM alpine:3.20
+WORKDIR /app
+COPY . .
+CMD ["app"]
+`,
+			wantSafety: rules.FixSafe,
+		},
+		{
+			name: "redundant after RUN",
+			content: `FROM alpine:3.20
+WORKDIR /app
+RUN rm -rf /app
+WORKDIR /app`,
+			want: `FROM alpine:3.20
+WORKDIR /app
+RUN rm -rf /app
+`,
+			wantSafety: rules.FixSuggestion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input := testutil.MakeLintInput(t, "Dockerfile", tt.content)
+			violations := NewWorkdirAbsoluteAndDeduplicatedRule().Check(input)
+			if len(violations) != 1 {
+				t.Fatalf("got %d violations, want 1", len(violations))
+			}
+			fix := violations[0].SuggestedFix
+			if fix == nil {
+				t.Fatal("violation has no SuggestedFix")
+			}
+			if fix.Safety != tt.wantSafety {
+				t.Errorf("fix safety = %v, want %v", fix.Safety, tt.wantSafety)
+			}
+			if got := string(fixpkg.ApplyFix([]byte(tt.content), fix)); got != tt.want {
+				t.Errorf("after fix:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -45,6 +45,7 @@ func (r *WorldWritableStatePathWorkaroundRule) Metadata() rules.RuleMetadata {
 		DocURL:          rules.TallyDocURL(WorldWritableStatePathWorkaroundRuleCode),
 		DefaultSeverity: rules.SeverityWarning,
 		Category:        "security",
+		Fixes:           []rules.FixSafety{rules.FixSuggestion},
 	}
 }
 
@@ -1,11 +1,13 @@
 package rules
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 
 	"github.com/wharflab/tally/internal/invocation"
+	"github.com/wharflab/tally/internal/semantic"
 )
 
 // FixSafety categorizes how reliable a fix is.
@@ -39,6 +41,20 @@ func (s FixSafety) String() string {
 	}
 }
 
+// ParseFixSafety parses a fix safety name as returned by FixSafety.String.
+func ParseFixSafety(s string) (FixSafety, error) {
+	switch strings.ToLower(s) {
+	case "safe":
+		return FixSafe, nil
+	case "suggestion":
+		return FixSuggestion, nil
+	case "unsafe":
+		return FixUnsafe, nil
+	default:
+		return FixUnsafe, fmt.Errorf("unknown fix safety: %q", s)
+	}
+}
+
 // SuggestedFix represents a structured edit hint for auto-fix suggestions.
 // It describes what text to replace and what to replace it with.
 //
@@ -149,12 +165,79 @@ type Violation struct {
 	// Used internally for merging async results; not serialized.
 	StageIndex int `json:"-"`
 
+	// StageRole is the role of the stage the violation is in, set by the
+	// linter for the severity-by-stage-role processor; not serialized.
+	StageRole semantic.StageRole `json:"-"`
+
 	// Invocation carries structured attribution for orchestrator-derived runs.
 	Invocation *invocation.InvocationSource `json:"invocation,omitempty"`
 
 	// InvocationKey is the stable internal identity of the invocation that
 	// produced this violation. Used for dedupe and async merging.
 	InvocationKey string `json:"-"`
+
+	// Suppression records what suppressed this violation. Only set on the
+	// suppressed violations listed with --show-suppressed.
+	Suppression *Suppression `json:"suppression,omitempty"`
+
+	// Metadata is optional structured context for editor integrations.
+	// The linter fills in the instruction and stage; rules may add the
+	// offending token and fix kind.
+	Metadata *ViolationMetadata `json:"metadata,omitempty"`
+}
+
+// ViolationMetadata describes a violation in machine-readable terms, so
+// editor integrations can filter violations and build quick fix menus
+// without parsing the human-readable message.
+type ViolationMetadata struct {
+	// Instruction is the upper-case keyword of the instruction the
+	// violation is on (e.g. "RUN"). Empty for file-level violations and
+	// lines outside any instruction.
+	Instruction string `json:"instruction,omitempty"`
+
+	// Stage identifies the build stage the violation is in.
+	Stage *ViolationStage `json:"stage,omitempty"`
+
+	// Token is the offending text, such as an unpinned image reference
+	// or a relative WORKDIR path.
+	Token string `json:"token,omitempty"`
+
+	// FixKind is a stable kebab-case identifier of what the suggested fix
+	// does (e.g. "pin-image-tag"), shared by rules that fix alike.
+	FixKind string `json:"fixKind,omitempty"`
+}
+
+// ViolationStage identifies a build stage.
+type ViolationStage struct {
+	// Index is the 0-based stage index in the Dockerfile.
+	Index int `json:"index"`
+
+	// Name is the stage name from FROM ... AS <name>, if any.
+	Name string `json:"name,omitempty"`
+}
+
+// SuppressionSource identifies what suppressed a violation.
+type SuppressionSource string
+
+const (
+	// SuppressionInline is an inline ignore directive in the Dockerfile.
+	SuppressionInline SuppressionSource = "inline"
+	// SuppressionConfig is rule configuration: severity "off", an exclude
+	// pattern, or per-rule paths / exclude-paths.
+	SuppressionConfig SuppressionSource = "config"
+	// SuppressionDuplicate is a violation of another rule on the same line
+	// that reports the same problem (RuleMetadata.Supersedes).
+	SuppressionDuplicate SuppressionSource = "duplicate"
+)
+
+// Suppression describes why a violation was suppressed.
+type Suppression struct {
+	// Source is what suppressed the violation.
+	Source SuppressionSource `json:"source"`
+
+	// Justification is the directive text or config setting responsible,
+	// e.g. "# tally ignore=DL3006;reason=pinned upstream".
+	Justification string `json:"justification,omitempty"`
 }
 
 // NewViolation creates a new violation with the minimum required fields.
@@ -264,6 +347,38 @@ func (v Violation) WithDetail(detail string) Violation {
 	return v
 }
 
+// WithSuppression marks the violation as suppressed.
+func (v Violation) WithSuppression(source SuppressionSource, justification string) Violation {
+	v.Suppression = &Suppression{Source: source, Justification: justification}
+	return v
+}
+
+// WithToken records the offending token in the violation metadata.
+func (v Violation) WithToken(token string) Violation {
+	md := v.metadataCopy()
+	md.Token = token
+	v.Metadata = md
+	return v
+}
+
+// WithFixKind records the kind of the suggested fix in the violation metadata.
+func (v Violation) WithFixKind(kind string) Violation {
+	md := v.metadataCopy()
+	md.FixKind = kind
+	v.Metadata = md
+	return v
+}
+
+// metadataCopy returns a copy of the metadata to modify, so violations
+// copied by value never share it.
+func (v Violation) metadataCopy() *ViolationMetadata {
+	if v.Metadata == nil {
+		return &ViolationMetadata{}
+	}
+	md := *v.Metadata
+	return &md
+}
+
 // WithDocURL adds a documentation URL to the violation.
 func (v Violation) WithDocURL(url string) Violation {
 	v.DocURL = url
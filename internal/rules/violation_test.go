@@ -2,6 +2,7 @@ package rules
 
 import (
 	"encoding/json/v2"
+	"strings"
 	"testing"
 
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
@@ -78,6 +79,37 @@ func TestViolation_WithSuggestedFix(t *testing.T) {
 	}
 }
 
+func TestViolation_WithMetadata(t *testing.T) {
+	t.Parallel()
+	base := NewViolation(NewLineLocation("Dockerfile", 1), "rule", "msg", SeverityError).
+		WithToken("ubuntu")
+	v := base.WithFixKind("pin-image-tag")
+
+	if v.Metadata == nil || v.Metadata.Token != "ubuntu" || v.Metadata.FixKind != "pin-image-tag" {
+		t.Errorf("Metadata = %+v, want token and fix kind", v.Metadata)
+	}
+	// Violations are values; setting metadata on a copy must not leak back.
+	if base.Metadata.FixKind != "" {
+		t.Errorf("base FixKind = %q, want empty", base.Metadata.FixKind)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if want := `"metadata":{"token":"ubuntu","fixKind":"pin-image-tag"}`; !strings.Contains(string(data), want) {
+		t.Errorf("JSON = %s, want it to contain %s", data, want)
+	}
+
+	data, err = json.Marshal(NewViolation(NewLineLocation("Dockerfile", 1), "rule", "msg", SeverityError))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if strings.Contains(string(data), "metadata") {
+		t.Errorf("JSON = %s, want no metadata", data)
+	}
+}
+
 func TestViolation_JSON(t *testing.T) {
 	t.Parallel()
 	loc := NewLineLocation("Dockerfile", 10)
@@ -478,6 +510,21 @@ func TestFixSafety_String(t *testing.T) {
 	}
 }
 
+func TestParseFixSafety(t *testing.T) {
+	t.Parallel()
+	for _, safety := range []FixSafety{FixSafe, FixSuggestion, FixUnsafe} {
+		if got, err := ParseFixSafety(safety.String()); err != nil || got != safety {
+			t.Errorf("ParseFixSafety(%q) = %v, %v, want %v", safety.String(), got, err, safety)
+		}
+	}
+	if got, err := ParseFixSafety("SAFE"); err != nil || got != FixSafe {
+		t.Errorf("ParseFixSafety(SAFE) = %v, %v, want safe", got, err)
+	}
+	if _, err := ParseFixSafety("trusted"); err == nil {
+		t.Error("ParseFixSafety(trusted): expected error")
+	}
+}
+
 func TestSuggestedFix_WithSafety(t *testing.T) {
 	t.Parallel()
 	loc := NewLineLocation("Dockerfile", 1)
@@ -0,0 +1,171 @@
+package runmount
+
+import (
+	"encoding/csv"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+// MountFlag is a --mount flag of a RUN instruction as written in the source.
+// Unlike GetMounts, flags are available even when BuildKit would reject
+// their options, which lets rules point at the offending flag.
+type MountFlag struct {
+	// Line is the 1-based line of the flag.
+	Line int
+	// StartCol and EndCol are the 0-based byte columns of the whole flag,
+	// from "--mount" to the end of its value (end exclusive).
+	StartCol, EndCol int
+	// Value is the flag value after "--mount=".
+	Value string
+}
+
+// MountOption is one comma-separated option of a mount flag, such as
+// "target=/root/.npmrc". Bare options such as "required" have no value.
+type MountOption struct {
+	// Key is the lowercased option name.
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+// MountFlags returns the --mount flags of a RUN instruction in source order.
+// It scans the flags between the RUN keyword and the command, following line
+// continuations with the given escape token. Flags are not expanded.
+func MountFlags(runLoc []parser.Range, sm *sourcemap.SourceMap, escape rune) []MountFlag {
+	if len(runLoc) == 0 || sm == nil {
+		return nil
+	}
+	lineNo := runLoc[0].Start.Line
+	endLine := runLoc[len(runLoc)-1].End.Line
+	col := RunKeywordEndColumn(runLoc, sm)
+
+	var flags []MountFlag
+	for lineNo <= endLine && lineNo <= sm.LineCount() {
+		line := sm.Line(lineNo - 1)
+		col = skipHorizontalWhitespace(line, col)
+		rest := line[min(col, len(line)):]
+		if trimmed := strings.TrimRight(rest, " \t\r"); trimmed == "" || trimmed == string(escape) || strings.HasPrefix(rest, "#") {
+			// End of line, line continuation, or a comment line inside the
+			// continuation: the flags go on on the next line.
+			lineNo++
+			col = 0
+			continue
+		}
+		if !strings.HasPrefix(rest, "--") {
+			break
+		}
+		end := col + flagTokenLength(rest)
+		if name, value, ok := strings.Cut(line[col:end], "="); ok && name == "--mount" {
+			flags = append(flags, MountFlag{Line: lineNo, StartCol: col, EndCol: end, Value: value})
+		}
+		col = end
+	}
+	return flags
+}
+
+// flagTokenLength returns the length of the flag at the start of s, which
+// ends at the first whitespace outside quotes.
+func flagTokenLength(s string) int {
+	var quote byte
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case isHorizontalWhitespace(c):
+			return i
+		}
+	}
+	return len(s)
+}
+
+// Options splits the flag value into options the way BuildKit does: as one
+// CSV record of key=value fields with case-insensitive keys.
+func (f MountFlag) Options() ([]MountOption, error) {
+	fields, err := csv.NewReader(strings.NewReader(f.Value)).Read()
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]MountOption, 0, len(fields))
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		opts = append(opts, MountOption{Key: strings.ToLower(key), Value: value, HasValue: ok})
+	}
+	return opts, nil
+}
+
+// Mount converts the flag to a mount, keeping the options it can parse and
+// ignoring invalid ones. The type defaults to bind like in BuildKit.
+func (f MountFlag) Mount() *instructions.Mount {
+	m := &instructions.Mount{Type: instructions.MountTypeBind}
+	opts, err := f.Options()
+	if err != nil {
+		return m
+	}
+	for _, opt := range opts {
+		switch opt.Key {
+		case "type":
+			m.Type = instructions.MountType(strings.ToLower(opt.Value))
+		case "id":
+			m.CacheID = opt.Value
+		case "target", "dst", "destination":
+			m.Target = opt.Value
+		case "source", "src":
+			m.Source = opt.Value
+		case "from":
+			m.From = opt.Value
+		case "env":
+			env := opt.Value
+			m.Env = &env
+		case "sharing":
+			m.CacheSharing = instructions.ShareMode(strings.ToLower(opt.Value))
+		case "required":
+			if !opt.HasValue {
+				m.Required = true
+			} else if required, err := strconv.ParseBool(opt.Value); err == nil {
+				m.Required = required
+			}
+		}
+	}
+	return m
+}
+
+// SecretID returns the id of the secret a secret mount requests: the id
+// option (or source), or the base name of the target when neither is set.
+func SecretID(m *instructions.Mount) string {
+	switch {
+	case m.Source != "":
+		return m.Source
+	case m.CacheID != "":
+		return m.CacheID
+	case m.Target != "":
+		return path.Base(m.Target)
+	}
+	return ""
+}
+
+// SecretTarget returns the file path a secret mount exposes the secret at,
+// defaulting to /run/secrets/<id>. It is empty for secrets exposed only as
+// an environment variable (env= without target=).
+func SecretTarget(m *instructions.Mount) string {
+	if m.Target != "" {
+		return m.Target
+	}
+	if m.Env != nil {
+		return ""
+	}
+	if id := SecretID(m); id != "" {
+		return "/run/secrets/" + path.Base(id)
+	}
+	return ""
+}
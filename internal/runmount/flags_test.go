@@ -0,0 +1,137 @@
+package runmount
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+
+	"github.com/wharflab/tally/internal/sourcemap"
+)
+
+func TestMountFlags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		source string
+		escape rune
+		want   []MountFlag
+	}{
+		{
+			name:   "single line",
+			source: "FROM alpine\nRUN --network=none --mount=type=secret,id=a cat /run/secrets/a\n",
+			escape: '\\',
+			want:   []MountFlag{{Line: 2, StartCol: 19, EndCol: 43, Value: "type=secret,id=a"}},
+		},
+		{
+			name: "continuations and comments",
+			source: "FROM alpine\nRUN --mount=type=cache,target=/a \\  \n" +
+				"    # cache for b\n    --mount=type=secret,id=\"b c\" \\\n    make --mount=x\n",
+			escape: '\\',
+			want: []MountFlag{
+				{Line: 2, StartCol: 4, EndCol: 32, Value: "type=cache,target=/a"},
+				{Line: 4, StartCol: 4, EndCol: 32, Value: `type=secret,id="b c"`},
+			},
+		},
+		{
+			name:   "backtick escape",
+			source: "# escape=`\nFROM alpine\nRUN `\n  --mount=type=tmpfs,target=/t echo hi\n",
+			escape: '`',
+			want:   []MountFlag{{Line: 4, StartCol: 2, EndCol: 30, Value: "type=tmpfs,target=/t"}},
+		},
+		{
+			name:   "no flags",
+			source: "FROM alpine\nRUN echo --mount=type=secret\n",
+			escape: '\\',
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			run := parseRun(t, tt.source)
+			got := MountFlags(run.Location(), sourcemap.New([]byte(tt.source)), tt.escape)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MountFlags() = %+v, want %+v", got, tt.want)
+			}
+			lines := strings.Split(tt.source, "\n")
+			for _, f := range got {
+				if text := lines[f.Line-1][f.StartCol:f.EndCol]; text != "--mount="+f.Value {
+					t.Errorf("flag range covers %q, want the flag", text)
+				}
+			}
+		})
+	}
+}
+
+func TestMountFlag_Options(t *testing.T) {
+	t.Parallel()
+	opts, err := MountFlag{Value: `Type=secret,"id=a,b",required`}.Options()
+	if err != nil {
+		t.Fatalf("Options() error = %v", err)
+	}
+	want := []MountOption{
+		{Key: "type", Value: "secret", HasValue: true},
+		{Key: "id", Value: "a,b", HasValue: true},
+		{Key: "required"},
+	}
+	if !slices.Equal(opts, want) {
+		t.Errorf("Options() = %+v, want %+v", opts, want)
+	}
+
+	if _, err := (MountFlag{Value: `type="secret`}).Options(); err == nil {
+		t.Error("Options() with an unterminated quote should fail")
+	}
+}
+
+func TestMountFlag_Mount(t *testing.T) {
+	t.Parallel()
+	m := MountFlag{Value: "type=secret,id=tok,dst=/root/.tok,required=maybe,requried,env=TOK"}.Mount()
+	if m.Type != instructions.MountTypeSecret || m.CacheID != "tok" || m.Target != "/root/.tok" {
+		t.Errorf("Mount() = %+v, want secret tok at /root/.tok", m)
+	}
+	if m.Required {
+		t.Error("Mount().Required should ignore invalid values")
+	}
+	if m.Env == nil || *m.Env != "TOK" {
+		t.Errorf("Mount().Env = %v, want TOK", m.Env)
+	}
+
+	if m := (MountFlag{Value: "target=/src"}).Mount(); m.Type != instructions.MountTypeBind {
+		t.Errorf("Mount().Type = %q, want bind", m.Type)
+	}
+	if m := (MountFlag{Value: "type=secret,id=a,required"}).Mount(); !m.Required {
+		t.Error("bare required should set Required")
+	}
+}
+
+func TestSecretIDAndTarget(t *testing.T) {
+	t.Parallel()
+	env := "TOKEN"
+	tests := []struct {
+		name       string
+		mount      *instructions.Mount
+		wantID     string
+		wantTarget string
+	}{
+		{"id", &instructions.Mount{CacheID: "npm"}, "npm", "/run/secrets/npm"},
+		{"source wins over id", &instructions.Mount{Source: "src", CacheID: "npm"}, "src", "/run/secrets/src"},
+		{"id from target", &instructions.Mount{Target: "/root/.npmrc"}, ".npmrc", "/root/.npmrc"},
+		{"env only", &instructions.Mount{CacheID: "tok", Env: &env}, "tok", ""},
+		{"env and target", &instructions.Mount{CacheID: "tok", Env: &env, Target: "/tok"}, "tok", "/tok"},
+		{"empty", &instructions.Mount{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := SecretID(tt.mount); got != tt.wantID {
+				t.Errorf("SecretID() = %q, want %q", got, tt.wantID)
+			}
+			if got := SecretTarget(tt.mount); got != tt.wantTarget {
+				t.Errorf("SecretTarget() = %q, want %q", got, tt.wantTarget)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package runmount
 
 import (
+	"path"
 	"slices"
 	"strconv"
 	"strings"
@@ -43,6 +44,39 @@ func GetMounts(run *instructions.RunCommand) []*instructions.Mount {
 	return instructions.GetMounts(run)
 }
 
+// CacheMounts returns the cache mounts of a RUN command.
+func CacheMounts(run *instructions.RunCommand) []*instructions.Mount {
+	var cache []*instructions.Mount
+	for _, m := range GetMounts(run) {
+		if m != nil && m.Type == instructions.MountTypeCache {
+			cache = append(cache, m)
+		}
+	}
+	return cache
+}
+
+// CacheID returns the id BuildKit keys a cache mount by: the id option, or
+// the cleaned target path when no id is set. Mounts with the same id share
+// one cache, whatever their targets.
+func CacheID(m *instructions.Mount) string {
+	if m.CacheID != "" {
+		return m.CacheID
+	}
+	if m.Target == "" {
+		return ""
+	}
+	return path.Clean(m.Target)
+}
+
+// CacheSharing returns the sharing mode of a cache mount. BuildKit defaults
+// to shared when the sharing option is not set.
+func CacheSharing(m *instructions.Mount) instructions.ShareMode {
+	if m.CacheSharing == "" {
+		return instructions.MountSharingShared
+	}
+	return m.CacheSharing
+}
+
 // hasMountFlags checks if the RUN command has any mount flags.
 func hasMountFlags(run *instructions.RunCommand) bool {
 	return slices.ContainsFunc(run.FlagsUsed, func(flag string) bool {
@@ -777,3 +777,47 @@ func TestMountsPopulated(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheMounts(t *testing.T) {
+	t.Parallel()
+	dockerfile := `FROM ubuntu:22.04
+RUN --mount=type=secret,id=token --mount=type=cache,target=/var/cache/apt --mount=type=bind,target=/src make
+`
+	mounts := CacheMounts(parseRun(t, dockerfile))
+	if len(mounts) != 1 || mounts[0].Target != "/var/cache/apt" {
+		t.Fatalf("CacheMounts() = %+v, want the /var/cache/apt mount", mounts)
+	}
+}
+
+func TestCacheID(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		mount instructions.Mount
+		want  string
+	}{
+		{"explicit id", instructions.Mount{Type: instructions.MountTypeCache, Target: "/root/.cache", CacheID: "pip"}, "pip"},
+		{"defaults to target", instructions.Mount{Type: instructions.MountTypeCache, Target: "/root/.cache/"}, "/root/.cache"},
+		{"relative target", instructions.Mount{Type: instructions.MountTypeCache, Target: "./node_modules"}, "node_modules"},
+		{"no target", instructions.Mount{Type: instructions.MountTypeCache}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := CacheID(&tt.mount); got != tt.want {
+				t.Errorf("CacheID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheSharing(t *testing.T) {
+	t.Parallel()
+	if got := CacheSharing(&instructions.Mount{Type: instructions.MountTypeCache}); got != instructions.MountSharingShared {
+		t.Errorf("CacheSharing() = %q, want %q", got, instructions.MountSharingShared)
+	}
+	m := &instructions.Mount{Type: instructions.MountTypeCache, CacheSharing: instructions.MountSharingLocked}
+	if got := CacheSharing(m); got != instructions.MountSharingLocked {
+		t.Errorf("CacheSharing() = %q, want %q", got, instructions.MountSharingLocked)
+	}
+}
@@ -0,0 +1,17 @@
+package schemas
+
+import _ "embed"
+
+// DirectivesSchemaID is the $id of the JSON Schema of the "tally inspect
+// --directives --json" output.
+const DirectivesSchemaID = "https://tally.wharflab.com/directives/tally-directives.schema.json"
+
+//go:embed directives/tally-directives.schema.json
+var directivesSchema []byte
+
+// DirectivesSchema returns the JSON Schema of the inline directives dump.
+func DirectivesSchema() []byte {
+	out := make([]byte, len(directivesSchema))
+	copy(out, directivesSchema)
+	return out
+}
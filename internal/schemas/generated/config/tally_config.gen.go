@@ -4,8 +4,10 @@ package config
 
 import hadolint "github.com/wharflab/tally/internal/schemas/generated/rules/hadolint"
 import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+import shellcheck "github.com/wharflab/tally/internal/schemas/generated/rules/shellcheck"
 import tally "github.com/wharflab/tally/internal/schemas/generated/rules/tally"
 import labels "github.com/wharflab/tally/internal/schemas/generated/rules/tally/labels"
+import runtime "github.com/wharflab/tally/internal/schemas/generated/rules/tally/runtime"
 
 // Schema for rules.buildkit configuration; keys are rule names within the buildkit
 // namespace.
@@ -17,6 +19,9 @@ type IndexSchemaJson_1 struct {
 	// DL3001 corresponds to the JSON schema field "DL3001".
 	DL3001 *hadolint.Dl3001SchemaJson `json:"DL3001,omitempty,omitzero"`
 
+	// DL3020 corresponds to the JSON schema field "DL3020".
+	DL3020 *hadolint.Dl3020SchemaJson `json:"DL3020,omitempty,omitzero"`
+
 	// DL3026 corresponds to the JSON schema field "DL3026".
 	DL3026 *hadolint.Dl3026SchemaJson `json:"DL3026,omitempty,omitzero"`
 
@@ -34,7 +39,7 @@ type IndexSchemaJson_2 map[string]ruleschema.GenericRuleConfig
 // shellcheck namespace.
 type IndexSchemaJson_3 struct {
 	// ShellCheck corresponds to the JSON schema field "ShellCheck".
-	ShellCheck *ruleschema.GenericRuleConfig `json:"ShellCheck,omitempty,omitzero"`
+	ShellCheck *shellcheck.ShellcheckSchemaJson `json:"ShellCheck,omitempty,omitzero"`
 
 	// ShellCheckInternalError corresponds to the JSON schema field
 	// "ShellCheckInternalError".
@@ -44,10 +49,28 @@ type IndexSchemaJson_3 struct {
 // Schema for rules.tally configuration; keys are rule names within the tally
 // namespace.
 type IndexSchemaJson_4 struct {
+	// AllowedBaseImages corresponds to the JSON schema field "allowed-base-images".
+	AllowedBaseImages *tally.AllowedBaseImagesSchemaJson `json:"allowed-base-images,omitempty,omitzero"`
+
+	// BaseImageNotEol corresponds to the JSON schema field "base-image-not-eol".
+	BaseImageNotEol *tally.BaseImageNotEolSchemaJson `json:"base-image-not-eol,omitempty,omitzero"`
+
+	// BaseImageVulnerabilities corresponds to the JSON schema field
+	// "base-image-vulnerabilities".
+	BaseImageVulnerabilities *tally.BaseImageVulnerabilitiesSchemaJson `json:"base-image-vulnerabilities,omitempty,omitzero"`
+
 	// ConsistentIndentation corresponds to the JSON schema field
 	// "consistent-indentation".
 	ConsistentIndentation *tally.ConsistentIndentationSchemaJson `json:"consistent-indentation,omitempty,omitzero"`
 
+	// CopyFromUnresolvedImage corresponds to the JSON schema field
+	// "copy-from-unresolved-image".
+	CopyFromUnresolvedImage *tally.CopyFromUnresolvedImageSchemaJson `json:"copy-from-unresolved-image,omitempty,omitzero"`
+
+	// DeprecatedBaseImage corresponds to the JSON schema field
+	// "deprecated-base-image".
+	DeprecatedBaseImage *tally.DeprecatedBaseImageSchemaJson `json:"deprecated-base-image,omitempty,omitzero"`
+
 	// EolLast corresponds to the JSON schema field "eol-last".
 	EolLast *tally.EolLastSchemaJson `json:"eol-last,omitempty,omitzero"`
 
@@ -63,9 +86,23 @@ type IndexSchemaJson_4 struct {
 	// "labels/prefer-stable-order".
 	LabelsPreferStableOrder *labels.PreferStableOrderSchemaJson `json:"labels/prefer-stable-order,omitempty,omitzero"`
 
+	// MaxCommandsPerRun corresponds to the JSON schema field "max-commands-per-run".
+	MaxCommandsPerRun *tally.MaxCommandsPerRunSchemaJson `json:"max-commands-per-run,omitempty,omitzero"`
+
+	// MaxInstructionsPerStage corresponds to the JSON schema field
+	// "max-instructions-per-stage".
+	MaxInstructionsPerStage *tally.MaxInstructionsPerStageSchemaJson `json:"max-instructions-per-stage,omitempty,omitzero"`
+
 	// MaxLines corresponds to the JSON schema field "max-lines".
 	MaxLines *tally.MaxLinesSchemaJson `json:"max-lines,omitempty,omitzero"`
 
+	// MaxStageCount corresponds to the JSON schema field "max-stage-count".
+	MaxStageCount *tally.MaxStageCountSchemaJson `json:"max-stage-count,omitempty,omitzero"`
+
+	// MountSecretInsteadOfCopy corresponds to the JSON schema field
+	// "mount-secret-instead-of-copy".
+	MountSecretInsteadOfCopy *tally.MountSecretInsteadOfCopySchemaJson `json:"mount-secret-instead-of-copy,omitempty,omitzero"`
+
 	// NewlineBetweenInstructions corresponds to the JSON schema field
 	// "newline-between-instructions".
 	NewlineBetweenInstructions *tally.NewlineBetweenInstructionsSchemaJson `json:"newline-between-instructions,omitempty,omitzero"`
@@ -81,6 +118,9 @@ type IndexSchemaJson_4 struct {
 	// "no-multiple-empty-lines".
 	NoMultipleEmptyLines *tally.NoMultipleEmptyLinesSchemaJson `json:"no-multiple-empty-lines,omitempty,omitzero"`
 
+	// NoPipeToShell corresponds to the JSON schema field "no-pipe-to-shell".
+	NoPipeToShell *tally.NoPipeToShellSchemaJson `json:"no-pipe-to-shell,omitempty,omitzero"`
+
 	// NoTrailingSpaces corresponds to the JSON schema field "no-trailing-spaces".
 	NoTrailingSpaces *tally.NoTrailingSpacesSchemaJson `json:"no-trailing-spaces,omitempty,omitzero"`
 
@@ -111,6 +151,17 @@ type IndexSchemaJson_4 struct {
 	// "require-secret-mounts".
 	RequireSecretMounts *tally.RequireSecretMountsSchemaJson `json:"require-secret-mounts,omitempty,omitzero"`
 
+	// RuntimePrivilegedPortAsNonroot corresponds to the JSON schema field
+	// "runtime/privileged-port-as-nonroot".
+	RuntimePrivilegedPortAsNonroot *runtime.PrivilegedPortAsNonrootSchemaJson `json:"runtime/privileged-port-as-nonroot,omitempty,omitzero"`
+
+	// SecretInContext corresponds to the JSON schema field "secret-in-context".
+	SecretInContext *tally.SecretInContextSchemaJson `json:"secret-in-context,omitempty,omitzero"`
+
+	// StageNameConventions corresponds to the JSON schema field
+	// "stage-name-conventions".
+	StageNameConventions *tally.StageNameConventionsSchemaJson `json:"stage-name-conventions,omitempty,omitzero"`
+
 	AdditionalProperties interface{} `mapstructure:",remain"`
 }
 
@@ -119,18 +170,65 @@ type TallyConfigSchemaJson struct {
 	// Configure opt-in AI AutoFix features (requires an ACP-capable agent).
 	Ai *TallyConfigSchemaJsonAi `json:"ai,omitempty,omitzero"`
 
+	// Build args passed to the build (like docker build --build-arg), keyed by ARG
+	// name. They seed ARG resolution so variable expansion in FROM and other
+	// instructions sees the values CI uses. Args declared by a Bake target or Compose
+	// service take precedence.
+	BuildArgs TallyConfigSchemaJsonBuildArgs `json:"build-args,omitempty,omitzero"`
+
+	// Dockerfile dialect to accept: "docker" follows BuildKit, "podman" also
+	// understands Podman/Buildah extensions such as RUN --mount=type=devpts and
+	// SELinux relabel mount options.
+	Dialect TallyConfigSchemaJsonDialect `json:"dialect,omitempty,omitzero"`
+
+	// Rules whose violations replace those of other rules reporting the same problem
+	// on the same line. Keys are rule codes; values are the rule codes or namespace
+	// wildcards (e.g. "hadolint/*") they replace. An entry replaces the rule's
+	// built-in list, so an empty list keeps every violation it would otherwise
+	// replace.
+	DuplicatePrecedence TallyConfigSchemaJsonDuplicatePrecedence `json:"duplicate-precedence,omitempty,omitzero"`
+
+	// Configs to merge underneath this one, in order: local paths (relative to this
+	// file), https:// URLs, or github.com/<owner>/<repo>[/<path>][@<ref>] references.
+	// Later entries override earlier ones and this file overrides them all.
+	Extends []string `json:"extends,omitempty,omitzero"`
+
 	// Pre-parse file validation checks.
 	FileValidation *TallyConfigSchemaJsonFileValidation `json:"file-validation,omitempty,omitzero"`
 
+	// Rules whose fixes win when two fixes overlap, highest precedence first. Entries
+	// are rule codes or namespace wildcards (e.g. "hadolint/*"). Listed rules win
+	// over later and unlisted rules; the losing fix is retried against the updated
+	// content.
+	FixPrecedence []string `json:"fix-precedence,omitempty,omitzero"`
+
 	// Control inline suppression directives (e.g. # tally-ignore).
 	InlineDirectives *TallyConfigSchemaJsonInlineDirectives `json:"inline-directives,omitempty,omitzero"`
 
 	// Configure output format and destination.
 	Output *TallyConfigSchemaJsonOutput `json:"output,omitempty,omitzero"`
 
+	// Built-in profile layered under this configuration: "recommended" adds checks
+	// that are off by default but need no setup, "strict" enables every such check
+	// and requires explained suppressions, "minimal" keeps only correctness and
+	// security checks, "hadolint-compat" matches hadolint's rule set and exit
+	// behavior.
+	Profile *TallyConfigSchemaJsonProfile `json:"profile,omitempty,omitzero"`
+
+	// Image registry policy shared by rules that check where base images come from,
+	// such as hadolint/DL3026.
+	Registries *TallyConfigSchemaJsonRegistries `json:"registries,omitempty,omitzero"`
+
 	// Rules corresponds to the JSON schema field "rules".
 	Rules *TallyConfigSchemaJsonRules `json:"rules,omitempty,omitzero"`
 
+	// Severity overrides by the role of the stage a violation is in. "final" covers
+	// the exported stage (the last stage, or the --target stage) and the stages it is
+	// built FROM; "builder" covers every other stage. Keys are rule codes, namespace
+	// wildcards (e.g. "hadolint/*"), or "*"; the most specific match wins. Overrides
+	// apply on top of the rule severity and don't enable rules that are off.
+	SeverityByStageRole *TallyConfigSchemaJsonSeverityByStageRole `json:"severity-by-stage-role,omitempty,omitzero"`
+
 	// Configure async checks that require network or other slow I/O (e.g. registry
 	// lookups).
 	SlowChecks *TallyConfigSchemaJsonSlowChecks `json:"slow-checks,omitempty,omitzero"`
@@ -151,13 +249,45 @@ type TallyConfigSchemaJsonAi struct {
 	// Maximum prompt payload size in bytes sent to the agent.
 	MaxInputBytes int `json:"max-input-bytes,omitempty,omitzero"`
 
+	// Custom prompt templates keyed by rule code (Go text/template). Available
+	// fields: {{.Rule}}, {{.File}}, {{.Message}}, {{.Detail}}, {{.Excerpt}}. tally
+	// always appends the Dockerfile and output format instructions.
+	Prompts TallyConfigSchemaJsonAiPrompts `json:"prompts,omitempty,omitzero"`
+
 	// Redact detected secrets in Dockerfile content before sending to the agent.
 	RedactSecrets bool `json:"redact-secrets,omitempty,omitzero"`
 
+	// Rule codes allowed to use AI AutoFix. When omitted or empty, every rule that
+	// offers an AI fix may use it.
+	Rules []string `json:"rules,omitempty,omitzero"`
+
 	// Per-fix execution timeout as a Go duration string (e.g. "90s", "2m").
 	Timeout string `json:"timeout,omitempty,omitzero"`
 }
 
+// Custom prompt templates keyed by rule code (Go text/template). Available fields:
+// {{.Rule}}, {{.File}}, {{.Message}}, {{.Detail}}, {{.Excerpt}}. tally always
+// appends the Dockerfile and output format instructions.
+type TallyConfigSchemaJsonAiPrompts map[string]string
+
+// Build args passed to the build (like docker build --build-arg), keyed by ARG
+// name. They seed ARG resolution so variable expansion in FROM and other
+// instructions sees the values CI uses. Args declared by a Bake target or Compose
+// service take precedence.
+type TallyConfigSchemaJsonBuildArgs map[string]string
+
+type TallyConfigSchemaJsonDialect string
+
+const TallyConfigSchemaJsonDialectDocker TallyConfigSchemaJsonDialect = "docker"
+const TallyConfigSchemaJsonDialectPodman TallyConfigSchemaJsonDialect = "podman"
+
+// Rules whose violations replace those of other rules reporting the same problem
+// on the same line. Keys are rule codes; values are the rule codes or namespace
+// wildcards (e.g. "hadolint/*") they replace. An entry replaces the rule's
+// built-in list, so an empty list keeps every violation it would otherwise
+// replace.
+type TallyConfigSchemaJsonDuplicatePrecedence map[string][]string
+
 // Pre-parse file validation checks.
 type TallyConfigSchemaJsonFileValidation struct {
 	// Maximum file size in bytes (0 = unlimited).
@@ -181,17 +311,36 @@ type TallyConfigSchemaJsonInlineDirectives struct {
 
 // Configure output format and destination.
 type TallyConfigSchemaJsonOutput struct {
+	// Maximum github-actions annotations per level (error, warning, notice); 0
+	// disables the limit.
+	AnnotationLimit int `json:"annotation-limit,omitempty,omitzero"`
+
 	// Minimum severity that causes a non-zero exit code.
 	FailLevel TallyConfigSchemaJsonOutputFailLevel `json:"fail-level,omitempty,omitzero"`
 
 	// Output format for lint results.
 	Format TallyConfigSchemaJsonOutputFormat `json:"format,omitempty,omitzero"`
 
-	// Write output to this path instead of stdout.
+	// Group text output by file, rule or severity, with per-group counts and a
+	// summary. For tap output, selects whether each file, rule or severity is one
+	// test point.
+	GroupBy TallyConfigSchemaJsonOutputGroupBy `json:"group-by,omitempty,omitzero"`
+
+	// Write output to this path instead of stdout. A path with {path}, {name}, {dir}
+	// or {hash} placeholders writes one report per linted file (e.g.
+	// "reports/{dir}.sarif").
 	Path string `json:"path,omitempty,omitzero"`
 
+	// Show each violation's suggested fix as an inline before/after diff (text
+	// format).
+	ShowFixes bool `json:"show-fixes,omitempty,omitzero"`
+
 	// Include source code snippets in output.
 	ShowSource bool `json:"show-source,omitempty,omitzero"`
+
+	// Also list violations suppressed by inline directives or rule configuration,
+	// marked with the suppression source (text, json and sarif formats).
+	ShowSuppressed bool `json:"show-suppressed,omitempty,omitzero"`
 }
 
 type TallyConfigSchemaJsonOutputFailLevel string
@@ -205,11 +354,45 @@ const TallyConfigSchemaJsonOutputFailLevelWarning TallyConfigSchemaJsonOutputFai
 type TallyConfigSchemaJsonOutputFormat string
 
 const TallyConfigSchemaJsonOutputFormatGithubActions TallyConfigSchemaJsonOutputFormat = "github-actions"
+const TallyConfigSchemaJsonOutputFormatHtml TallyConfigSchemaJsonOutputFormat = "html"
 const TallyConfigSchemaJsonOutputFormatJson TallyConfigSchemaJsonOutputFormat = "json"
 const TallyConfigSchemaJsonOutputFormatMarkdown TallyConfigSchemaJsonOutputFormat = "markdown"
 const TallyConfigSchemaJsonOutputFormatSarif TallyConfigSchemaJsonOutputFormat = "sarif"
+const TallyConfigSchemaJsonOutputFormatTap TallyConfigSchemaJsonOutputFormat = "tap"
 const TallyConfigSchemaJsonOutputFormatText TallyConfigSchemaJsonOutputFormat = "text"
 
+type TallyConfigSchemaJsonOutputGroupBy string
+
+const TallyConfigSchemaJsonOutputGroupByFile TallyConfigSchemaJsonOutputGroupBy = "file"
+const TallyConfigSchemaJsonOutputGroupByNone TallyConfigSchemaJsonOutputGroupBy = "none"
+const TallyConfigSchemaJsonOutputGroupByRule TallyConfigSchemaJsonOutputGroupBy = "rule"
+const TallyConfigSchemaJsonOutputGroupBySeverity TallyConfigSchemaJsonOutputGroupBy = "severity"
+
+type TallyConfigSchemaJsonProfile string
+
+const TallyConfigSchemaJsonProfileHadolintCompat TallyConfigSchemaJsonProfile = "hadolint-compat"
+const TallyConfigSchemaJsonProfileMinimal TallyConfigSchemaJsonProfile = "minimal"
+const TallyConfigSchemaJsonProfileRecommended TallyConfigSchemaJsonProfile = "recommended"
+const TallyConfigSchemaJsonProfileStrict TallyConfigSchemaJsonProfile = "strict"
+
+// Image registry policy shared by rules that check where base images come from,
+// such as hadolint/DL3026.
+type TallyConfigSchemaJsonRegistries struct {
+	// Mirror registries keyed by host, each mapped to the registry it mirrors. A
+	// mirror and its upstream are treated as the same registry.
+	Mirrors TallyConfigSchemaJsonRegistriesMirrors `json:"mirrors,omitempty,omitzero"`
+
+	// Trusted registries, used by rules that have no list of their own. Entries are
+	// hosts with an optional port; "*" matches any registry, "*.suffix" any subdomain
+	// and "prefix*" any host with that prefix. An entry without a port matches the
+	// host on any port.
+	Trusted []string `json:"trusted,omitempty,omitzero"`
+}
+
+// Mirror registries keyed by host, each mapped to the registry it mirrors. A
+// mirror and its upstream are treated as the same registry.
+type TallyConfigSchemaJsonRegistriesMirrors map[string]string
+
 type TallyConfigSchemaJsonRules struct {
 	// Buildkit corresponds to the JSON schema field "buildkit".
 	Buildkit IndexSchemaJson `json:"buildkit,omitempty,omitzero"`
@@ -231,17 +414,66 @@ type TallyConfigSchemaJsonRules struct {
 
 	// Tally corresponds to the JSON schema field "tally".
 	Tally *IndexSchemaJson_4 `json:"tally,omitempty,omitzero"`
+
+	// Per-rule execution budget as a Go duration string (e.g. "200ms"). A rule that
+	// exceeds it on a file is skipped and reported by tally/rule-timeout. "0"
+	// disables the budget.
+	Timeout string `json:"timeout,omitempty,omitzero"`
 }
 
+// Severity overrides by the role of the stage a violation is in. "final" covers
+// the exported stage (the last stage, or the --target stage) and the stages it is
+// built FROM; "builder" covers every other stage. Keys are rule codes, namespace
+// wildcards (e.g. "hadolint/*"), or "*"; the most specific match wins. Overrides
+// apply on top of the rule severity and don't enable rules that are off.
+type TallyConfigSchemaJsonSeverityByStageRole struct {
+	// Severities for violations in builder stages, keyed by rule pattern.
+	Builder TallyConfigSchemaJsonSeverityByStageRoleBuilder `json:"builder,omitempty,omitzero"`
+
+	// Severities for violations in the exported stages, keyed by rule pattern.
+	Final TallyConfigSchemaJsonSeverityByStageRoleFinal `json:"final,omitempty,omitzero"`
+}
+
+// Severities for violations in builder stages, keyed by rule pattern.
+type TallyConfigSchemaJsonSeverityByStageRoleBuilder map[string]TallyConfigSchemaJsonSeverityByStageRoleBuilderValue
+
+type TallyConfigSchemaJsonSeverityByStageRoleBuilderValue string
+
+const TallyConfigSchemaJsonSeverityByStageRoleBuilderValueError TallyConfigSchemaJsonSeverityByStageRoleBuilderValue = "error"
+const TallyConfigSchemaJsonSeverityByStageRoleBuilderValueInfo TallyConfigSchemaJsonSeverityByStageRoleBuilderValue = "info"
+const TallyConfigSchemaJsonSeverityByStageRoleBuilderValueOff TallyConfigSchemaJsonSeverityByStageRoleBuilderValue = "off"
+const TallyConfigSchemaJsonSeverityByStageRoleBuilderValueStyle TallyConfigSchemaJsonSeverityByStageRoleBuilderValue = "style"
+const TallyConfigSchemaJsonSeverityByStageRoleBuilderValueWarning TallyConfigSchemaJsonSeverityByStageRoleBuilderValue = "warning"
+
+// Severities for violations in the exported stages, keyed by rule pattern.
+type TallyConfigSchemaJsonSeverityByStageRoleFinal map[string]TallyConfigSchemaJsonSeverityByStageRoleFinalValue
+
+type TallyConfigSchemaJsonSeverityByStageRoleFinalValue string
+
+const TallyConfigSchemaJsonSeverityByStageRoleFinalValueError TallyConfigSchemaJsonSeverityByStageRoleFinalValue = "error"
+const TallyConfigSchemaJsonSeverityByStageRoleFinalValueInfo TallyConfigSchemaJsonSeverityByStageRoleFinalValue = "info"
+const TallyConfigSchemaJsonSeverityByStageRoleFinalValueOff TallyConfigSchemaJsonSeverityByStageRoleFinalValue = "off"
+const TallyConfigSchemaJsonSeverityByStageRoleFinalValueStyle TallyConfigSchemaJsonSeverityByStageRoleFinalValue = "style"
+const TallyConfigSchemaJsonSeverityByStageRoleFinalValueWarning TallyConfigSchemaJsonSeverityByStageRoleFinalValue = "warning"
+
 // Configure async checks that require network or other slow I/O (e.g. registry
 // lookups).
 type TallyConfigSchemaJsonSlowChecks struct {
+	// How long registry lookups are cached on disk as a Go duration string (e.g.
+	// "1h"). "0" disables the cache. Digest-pinned references never expire.
+	CacheTtl string `json:"cache-ttl,omitempty,omitzero"`
+
 	// Stop slow checks on first failure instead of collecting all results.
 	FailFast bool `json:"fail-fast,omitempty,omitzero"`
 
 	// When to run slow checks: "auto" enables them in CI, "on" always, "off" never.
 	Mode TallyConfigSchemaJsonSlowChecksMode `json:"mode,omitempty,omitzero"`
 
+	// Per-registry connection settings keyed by registry host (e.g.
+	// "registry.internal:5000"). Credentials are read from Docker and containers auth
+	// files and credential helpers.
+	Registries TallyConfigSchemaJsonSlowChecksRegistries `json:"registries,omitempty,omitzero"`
+
 	// Overall timeout for all slow checks as a Go duration string (e.g. "20s").
 	Timeout string `json:"timeout,omitempty,omitzero"`
 }
@@ -252,6 +484,18 @@ const TallyConfigSchemaJsonSlowChecksModeAuto TallyConfigSchemaJsonSlowChecksMod
 const TallyConfigSchemaJsonSlowChecksModeOff TallyConfigSchemaJsonSlowChecksMode = "off"
 const TallyConfigSchemaJsonSlowChecksModeOn TallyConfigSchemaJsonSlowChecksMode = "on"
 
+// Per-registry connection settings keyed by registry host (e.g.
+// "registry.internal:5000"). Credentials are read from Docker and containers auth
+// files and credential helpers.
+type TallyConfigSchemaJsonSlowChecksRegistries map[string]struct {
+	// Directory with ca.crt, client.cert, and client.key for this registry (same
+	// layout as /etc/docker/certs.d/<host>).
+	CertsDir *string `json:"certs-dir,omitempty,omitzero"`
+
+	// Skip TLS certificate verification and allow plain HTTP for this registry.
+	Insecure bool `json:"insecure,omitempty,omitzero"`
+}
+
 // Enable application of unsafe fixes. When omitted, unsafe fixes are not applied
 // and callers may display a hint when unsafe fixes are available.
 type TallyConfigSchemaJsonUnsafeFixes *bool
@@ -9,12 +9,21 @@ type Dl3001SchemaJson struct {
 	// Exclude corresponds to the JSON schema field "exclude".
 	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
 
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
 	// Fix corresponds to the JSON schema field "fix".
 	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
 
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
 	// Commands to flag as invalid inside a container.
 	InvalidCommands []string `json:"invalid-commands,omitempty,omitzero"`
 
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
 	// Severity corresponds to the JSON schema field "severity".
 	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
 }
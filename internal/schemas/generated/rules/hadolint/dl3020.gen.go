@@ -0,0 +1,30 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package hadolint
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the hadolint/DL3020 rule.
+type Dl3020SchemaJson struct {
+	// Extra file name suffixes, besides the tar extensions, marking local archives
+	// that ADD is meant to extract. Sources ending with one of them are not reported.
+	ArchiveExtensions []string `json:"archive-extensions,omitempty,omitzero"`
+
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
@@ -9,13 +9,23 @@ type Dl3026SchemaJson struct {
 	// Exclude corresponds to the JSON schema field "exclude".
 	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
 
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
 	// Fix corresponds to the JSON schema field "fix".
 	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
 
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
 	// Severity corresponds to the JSON schema field "severity".
 	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
 
-	// Allowed registries for base images in FROM (empty disables the rule). Supports
-	// "*", "*.suffix" and "prefix*" patterns.
+	// Allowed registries for base images in FROM. Supports "*", "*.suffix" and
+	// "prefix*" patterns, with an optional port. When empty, the global
+	// registries.trusted list is used; if that is empty too, the rule is disabled.
 	TrustedRegistries []string `json:"trusted-registries,omitempty,omitzero"`
 }
@@ -8,11 +8,21 @@ type Exclude struct {
 	Paths []string `json:"paths,omitempty,omitzero"`
 }
 
+// Glob patterns, relative to the config file's directory, of Dockerfiles this rule
+// is disabled for. Takes precedence over paths.
+type ExcludePaths []string
+
 type Fix string
 
 const FixAlways Fix = "always"
 const FixExplicit Fix = "explicit"
 const FixNever Fix = "never"
+
+type FixSafety string
+
+const FixSafetySafe FixSafety = "safe"
+const FixSafetySuggestion FixSafety = "suggestion"
+const FixSafetyUnsafe FixSafety = "unsafe"
 const FixUnsafeOnly Fix = "unsafe-only"
 
 // Generic per-rule configuration used for rules without rule-specific options.
@@ -20,13 +30,26 @@ type GenericRuleConfig struct {
 	// Exclude corresponds to the JSON schema field "exclude".
 	Exclude *Exclude `json:"exclude,omitempty,omitzero"`
 
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
 	// Fix corresponds to the JSON schema field "fix".
 	Fix *Fix `json:"fix,omitempty,omitzero"`
 
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths Paths `json:"paths,omitempty,omitzero"`
+
 	// Severity corresponds to the JSON schema field "severity".
 	Severity *Severity `json:"severity,omitempty,omitzero"`
 }
 
+// Glob patterns, relative to the config file's directory, of the Dockerfiles this
+// rule applies to. When set, the rule is disabled for every other file.
+type Paths []string
+
 type Severity string
 
 const SeverityError Severity = "error"
@@ -0,0 +1,39 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package shellcheck
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the shellcheck/ShellCheck rule.
+type ShellcheckSchemaJson struct {
+	// ShellCheck implementation to run: the embedded WebAssembly build, or an
+	// installed shellcheck executable.
+	Engine ShellcheckSchemaJsonEngine `json:"engine,omitempty,omitzero"`
+
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Executable used by the external engine, as a path or a name looked up in PATH.
+	// TALLY_SHELLCHECK overrides the default.
+	Executable string `json:"executable,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
+
+type ShellcheckSchemaJsonEngine string
+
+const ShellcheckSchemaJsonEngineEmbedded ShellcheckSchemaJsonEngine = "embedded"
+const ShellcheckSchemaJsonEngineExternal ShellcheckSchemaJsonEngine = "external"
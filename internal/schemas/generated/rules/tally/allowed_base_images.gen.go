@@ -0,0 +1,50 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package tally
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the tally/allowed-base-images rule.
+type AllowedBaseImagesSchemaJson struct {
+	// Glob patterns base images of every stage must match. Empty allows every image
+	// that is not denied.
+	Allow BaseImagePatterns `json:"allow,omitempty,omitzero"`
+
+	// Policy for builder stages, whose filesystem is discarded after the build.
+	Builder *BaseImagePolicy `json:"builder,omitempty,omitzero"`
+
+	// Glob patterns no base image may match. Deny wins over allow.
+	Deny BaseImagePatterns `json:"deny,omitempty,omitzero"`
+
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Policy for stages exported in the image: the final (or target) stage and the
+	// stages it is built FROM.
+	Final *BaseImagePolicy `json:"final,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
+
+type BaseImagePatterns []string
+
+type BaseImagePolicy struct {
+	// Glob patterns replacing the top-level allowlist for this stage role.
+	Allow BaseImagePatterns `json:"allow,omitempty,omitzero"`
+
+	// Glob patterns denied in this stage role, in addition to the top-level denylist.
+	Deny BaseImagePatterns `json:"deny,omitempty,omitzero"`
+}
@@ -0,0 +1,29 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package tally
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the tally/base-image-not-eol rule.
+type BaseImageNotEolSchemaJson struct {
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Days after a release reaches end-of-life before the rule reports it.
+	GracePeriodDays int `json:"grace-period-days,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
@@ -0,0 +1,36 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package tally
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the tally/base-image-vulnerabilities rule.
+type BaseImageVulnerabilitiesSchemaJson struct {
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Lowest advisory severity counted in the report.
+	MinSeverity BaseImageVulnerabilitiesSchemaJsonMinSeverity `json:"min-severity,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
+
+type BaseImageVulnerabilitiesSchemaJsonMinSeverity string
+
+const BaseImageVulnerabilitiesSchemaJsonMinSeverityCritical BaseImageVulnerabilitiesSchemaJsonMinSeverity = "critical"
+const BaseImageVulnerabilitiesSchemaJsonMinSeverityHigh BaseImageVulnerabilitiesSchemaJsonMinSeverity = "high"
+const BaseImageVulnerabilitiesSchemaJsonMinSeverityLow BaseImageVulnerabilitiesSchemaJsonMinSeverity = "low"
+const BaseImageVulnerabilitiesSchemaJsonMinSeverityMedium BaseImageVulnerabilitiesSchemaJsonMinSeverity = "medium"
@@ -9,9 +9,18 @@ type ConsistentIndentationSchemaJson struct {
 	// Exclude corresponds to the JSON schema field "exclude".
 	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
 
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
 	// Fix corresponds to the JSON schema field "fix".
 	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
 
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
 	// Severity corresponds to the JSON schema field "severity".
 	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
 }
@@ -0,0 +1,36 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package tally
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the tally/deprecated-base-image rule.
+type DeprecatedBaseImageSchemaJson struct {
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Additional deprecated images mapped to the repository that replaces them, e.g.
+	// internal image renames. The fix keeps the tag. An empty successor reports the
+	// image without a fix. Entries override the built-in mapping.
+	Replacements DeprecatedBaseImageSchemaJsonReplacements `json:"replacements,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
+
+// Additional deprecated images mapped to the repository that replaces them, e.g.
+// internal image renames. The fix keeps the tag. An empty successor reports the
+// image without a fix. Entries override the built-in mapping.
+type DeprecatedBaseImageSchemaJsonReplacements map[string]string
@@ -0,0 +1,31 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package runtime
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the tally/runtime/privileged-port-as-nonroot rule.
+type PrivilegedPortAsNonrootSchemaJson struct {
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+
+	// First port a non-root process may bind, matching the
+	// net.ipv4.ip_unprivileged_port_start sysctl of the target runtime. Exposed ports
+	// below it are reported.
+	UnprivilegedPortStart int `json:"unprivileged-port-start,omitempty,omitzero"`
+}
@@ -0,0 +1,39 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package tally
+
+import ruleschema "github.com/wharflab/tally/internal/schemas/generated/rules/ruleschema"
+
+// Configuration options for the tally/stage-name-conventions rule.
+type StageNameConventionsSchemaJson struct {
+	// Word separator convention stage names must follow.
+	Case StageNameConventionsSchemaJsonCase `json:"case,omitempty,omitzero"`
+
+	// Exclude corresponds to the JSON schema field "exclude".
+	Exclude *ruleschema.Exclude `json:"exclude,omitempty,omitzero"`
+
+	// ExcludePaths corresponds to the JSON schema field "exclude-paths".
+	ExcludePaths ruleschema.ExcludePaths `json:"exclude-paths,omitempty,omitzero"`
+
+	// Fix corresponds to the JSON schema field "fix".
+	Fix *ruleschema.Fix `json:"fix,omitempty,omitzero"`
+
+	// FixSafety corresponds to the JSON schema field "fix-safety".
+	FixSafety *ruleschema.FixSafety `json:"fix-safety,omitempty,omitzero"`
+
+	// Paths corresponds to the JSON schema field "paths".
+	Paths ruleschema.Paths `json:"paths,omitempty,omitzero"`
+
+	// Regular expression every stage name must also match (matched against the
+	// lowercased name).
+	Pattern *string `json:"pattern,omitempty,omitzero"`
+
+	// Severity corresponds to the JSON schema field "severity".
+	Severity *ruleschema.Severity `json:"severity,omitempty,omitzero"`
+}
+
+type StageNameConventionsSchemaJsonCase string
+
+const StageNameConventionsSchemaJsonCaseAny StageNameConventionsSchemaJsonCase = "any"
+const StageNameConventionsSchemaJsonCaseKebabCase StageNameConventionsSchemaJsonCase = "kebab-case"
+const StageNameConventionsSchemaJsonCaseSnakeCase StageNameConventionsSchemaJsonCase = "snake_case"
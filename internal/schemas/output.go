@@ -0,0 +1,17 @@
+package schemas
+
+import _ "embed"
+
+// OutputSchemaID is the $id of the JSON Schema of the "tally lint --format
+// json" report.
+const OutputSchemaID = "https://tally.wharflab.com/output/tally-output.schema.json"
+
+//go:embed output/tally-output.schema.json
+var outputSchema []byte
+
+// OutputSchema returns the JSON Schema of the JSON report.
+func OutputSchema() []byte {
+	out := make([]byte, len(outputSchema))
+	copy(out, outputSchema)
+	return out
+}
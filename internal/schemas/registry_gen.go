@@ -5,58 +5,88 @@ package schemas
 const RootConfigSchemaID = "https://tally.wharflab.com/root/tally-config.schema.json"
 
 var ruleSchemaIDs = map[string]string{
-	"hadolint/DL3001":                    "https://tally.wharflab.com/rules/hadolint/dl3001.schema.json",
-	"hadolint/DL3026":                    "https://tally.wharflab.com/rules/hadolint/dl3026.schema.json",
-	"hadolint/DL4001":                    "https://tally.wharflab.com/rules/hadolint/dl4001.schema.json",
-	"tally/consistent-indentation":       "https://tally.wharflab.com/rules/tally/consistent_indentation.schema.json",
-	"tally/eol-last":                     "https://tally.wharflab.com/rules/tally/eol_last.schema.json",
-	"tally/labels/no-buildx-git-overlap": "https://tally.wharflab.com/rules/tally/labels/no_buildx_git_overlap.schema.json",
-	"tally/labels/prefer-grouped":        "https://tally.wharflab.com/rules/tally/labels/prefer_grouped.schema.json",
-	"tally/labels/prefer-stable-order":   "https://tally.wharflab.com/rules/tally/labels/prefer_stable_order.schema.json",
-	"tally/max-lines":                    "https://tally.wharflab.com/rules/tally/max_lines.schema.json",
-	"tally/newline-between-instructions": "https://tally.wharflab.com/rules/tally/newline_between_instructions.schema.json",
-	"tally/newline-per-chained-call":     "https://tally.wharflab.com/rules/tally/newline_per_chained_call.schema.json",
-	"tally/no-multi-spaces":              "https://tally.wharflab.com/rules/tally/no_multi_spaces.schema.json",
-	"tally/no-multiple-empty-lines":      "https://tally.wharflab.com/rules/tally/no_multiple_empty_lines.schema.json",
-	"tally/no-trailing-spaces":           "https://tally.wharflab.com/rules/tally/no_trailing_spaces.schema.json",
-	"tally/prefer-add-unpack":            "https://tally.wharflab.com/rules/tally/prefer_add_unpack.schema.json",
-	"tally/prefer-copy-heredoc":          "https://tally.wharflab.com/rules/tally/prefer_copy_heredoc.schema.json",
-	"tally/prefer-curl-config":           "https://tally.wharflab.com/rules/tally/prefer_curl_config.schema.json",
-	"tally/prefer-formatted-heredocs":    "https://tally.wharflab.com/rules/tally/prefer_formatted_heredocs.schema.json",
-	"tally/prefer-multi-stage-build":     "https://tally.wharflab.com/rules/tally/prefer_multi_stage_build.schema.json",
-	"tally/prefer-run-heredoc":           "https://tally.wharflab.com/rules/tally/prefer_run_heredoc.schema.json",
-	"tally/prefer-wget-config":           "https://tally.wharflab.com/rules/tally/prefer_wget_config.schema.json",
-	"tally/require-secret-mounts":        "https://tally.wharflab.com/rules/tally/require_secret_mounts.schema.json",
+	"hadolint/DL3001":                          "https://tally.wharflab.com/rules/hadolint/dl3001.schema.json",
+	"hadolint/DL3020":                          "https://tally.wharflab.com/rules/hadolint/dl3020.schema.json",
+	"hadolint/DL3026":                          "https://tally.wharflab.com/rules/hadolint/dl3026.schema.json",
+	"hadolint/DL4001":                          "https://tally.wharflab.com/rules/hadolint/dl4001.schema.json",
+	"shellcheck/ShellCheck":                    "https://tally.wharflab.com/rules/shellcheck/shellcheck.schema.json",
+	"tally/allowed-base-images":                "https://tally.wharflab.com/rules/tally/allowed_base_images.schema.json",
+	"tally/base-image-not-eol":                 "https://tally.wharflab.com/rules/tally/base_image_not_eol.schema.json",
+	"tally/base-image-vulnerabilities":         "https://tally.wharflab.com/rules/tally/base_image_vulnerabilities.schema.json",
+	"tally/consistent-indentation":             "https://tally.wharflab.com/rules/tally/consistent_indentation.schema.json",
+	"tally/copy-from-unresolved-image":         "https://tally.wharflab.com/rules/tally/copy_from_unresolved_image.schema.json",
+	"tally/deprecated-base-image":              "https://tally.wharflab.com/rules/tally/deprecated_base_image.schema.json",
+	"tally/eol-last":                           "https://tally.wharflab.com/rules/tally/eol_last.schema.json",
+	"tally/labels/no-buildx-git-overlap":       "https://tally.wharflab.com/rules/tally/labels/no_buildx_git_overlap.schema.json",
+	"tally/labels/prefer-grouped":              "https://tally.wharflab.com/rules/tally/labels/prefer_grouped.schema.json",
+	"tally/labels/prefer-stable-order":         "https://tally.wharflab.com/rules/tally/labels/prefer_stable_order.schema.json",
+	"tally/max-commands-per-run":               "https://tally.wharflab.com/rules/tally/max_commands_per_run.schema.json",
+	"tally/max-instructions-per-stage":         "https://tally.wharflab.com/rules/tally/max_instructions_per_stage.schema.json",
+	"tally/max-lines":                          "https://tally.wharflab.com/rules/tally/max_lines.schema.json",
+	"tally/max-stage-count":                    "https://tally.wharflab.com/rules/tally/max_stage_count.schema.json",
+	"tally/mount-secret-instead-of-copy":       "https://tally.wharflab.com/rules/tally/mount_secret_instead_of_copy.schema.json",
+	"tally/newline-between-instructions":       "https://tally.wharflab.com/rules/tally/newline_between_instructions.schema.json",
+	"tally/newline-per-chained-call":           "https://tally.wharflab.com/rules/tally/newline_per_chained_call.schema.json",
+	"tally/no-multi-spaces":                    "https://tally.wharflab.com/rules/tally/no_multi_spaces.schema.json",
+	"tally/no-multiple-empty-lines":            "https://tally.wharflab.com/rules/tally/no_multiple_empty_lines.schema.json",
+	"tally/no-pipe-to-shell":                   "https://tally.wharflab.com/rules/tally/no_pipe_to_shell.schema.json",
+	"tally/no-trailing-spaces":                 "https://tally.wharflab.com/rules/tally/no_trailing_spaces.schema.json",
+	"tally/prefer-add-unpack":                  "https://tally.wharflab.com/rules/tally/prefer_add_unpack.schema.json",
+	"tally/prefer-copy-heredoc":                "https://tally.wharflab.com/rules/tally/prefer_copy_heredoc.schema.json",
+	"tally/prefer-curl-config":                 "https://tally.wharflab.com/rules/tally/prefer_curl_config.schema.json",
+	"tally/prefer-formatted-heredocs":          "https://tally.wharflab.com/rules/tally/prefer_formatted_heredocs.schema.json",
+	"tally/prefer-multi-stage-build":           "https://tally.wharflab.com/rules/tally/prefer_multi_stage_build.schema.json",
+	"tally/prefer-run-heredoc":                 "https://tally.wharflab.com/rules/tally/prefer_run_heredoc.schema.json",
+	"tally/prefer-wget-config":                 "https://tally.wharflab.com/rules/tally/prefer_wget_config.schema.json",
+	"tally/require-secret-mounts":              "https://tally.wharflab.com/rules/tally/require_secret_mounts.schema.json",
+	"tally/runtime/privileged-port-as-nonroot": "https://tally.wharflab.com/rules/tally/runtime/privileged_port_as_nonroot.schema.json",
+	"tally/secret-in-context":                  "https://tally.wharflab.com/rules/tally/secret_in_context.schema.json",
+	"tally/stage-name-conventions":             "https://tally.wharflab.com/rules/tally/stage_name_conventions.schema.json",
 }
 
 var schemaBytesByID = map[string][]byte{
-	"https://tally.wharflab.com/root/tally-config.schema.json":                        []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/root/tally-config.schema.json\",\n  \"title\": \"tally configuration\",\n  \"description\": \"Configuration schema for tally Dockerfile linter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"rules\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"include\": {\n          \"description\": \"Glob patterns for rules to enable (e.g. \\\"tally/*\\\", \\\"hadolint/DL3026\\\").\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\" }\n        },\n        \"exclude\": {\n          \"description\": \"Glob patterns for rules to disable (e.g. \\\"buildkit/MaintainerDeprecated\\\").\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\" }\n        },\n        \"tally\": {\n          \"$ref\": \"../../rules/tally/index.schema.json\"\n        },\n        \"hadolint\": {\n          \"$ref\": \"../../rules/hadolint/index.schema.json\"\n        },\n        \"buildkit\": {\n          \"$ref\": \"../../rules/buildkit/index.schema.json\"\n        },\n        \"shellcheck\": {\n          \"$ref\": \"../../rules/shellcheck/index.schema.json\"\n        },\n        \"powershell\": {\n          \"$ref\": \"../../rules/powershell/index.schema.json\"\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"output\": {\n      \"type\": \"object\",\n      \"description\": \"Configure output format and destination.\",\n      \"properties\": {\n        \"format\": {\n          \"description\": \"Output format for lint results.\",\n          \"type\": \"string\",\n          \"enum\": [\"text\", \"json\", \"sarif\", \"github-actions\", \"markdown\"],\n          \"default\": \"text\"\n        },\n        \"path\": {\n          \"description\": \"Write output to this path instead of stdout.\",\n          \"type\": \"string\",\n          \"default\": \"stdout\"\n        },\n        \"show-source\": {\n          \"description\": \"Include source code snippets in output.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"fail-level\": {\n          \"description\": \"Minimum severity that causes a non-zero exit code.\",\n          \"type\": \"string\",\n          \"enum\": [\"error\", \"warning\", \"info\", \"style\", \"none\"],\n          \"default\": \"style\"\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"inline-directives\": {\n      \"type\": \"object\",\n      \"description\": \"Control inline suppression directives (e.g. # tally-ignore).\",\n      \"properties\": {\n        \"enabled\": {\n          \"description\": \"Allow inline directives to suppress violations.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"warn-unused\": {\n          \"description\": \"Warn when an inline directive does not suppress any violation.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"validate-rules\": {\n          \"description\": \"Warn when an inline directive references a rule that does not exist.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"require-reason\": {\n          \"description\": \"Require a reason comment on every inline suppression directive.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"ai\": {\n      \"type\": \"object\",\n      \"description\": \"Configure opt-in AI AutoFix features (requires an ACP-capable agent).\",\n      \"properties\": {\n        \"enabled\": {\n          \"description\": \"Enable AI AutoFix. When false, AI fixes are never resolved.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"command\": {\n          \"description\": \"Argv to launch the ACP agent process over stdio.\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\", \"minLength\": 1 },\n          \"examples\": [\n            [\"gemini\", \"--experimental-acp\", \"--allowed-mcp-server-names=none\", \"--model=gemini-3-flash-preview\"],\n            [\"opencode\", \"--acp\"],\n            [\"kiro\", \"--acp\"]\n          ]\n        },\n        \"timeout\": {\n          \"description\": \"Per-fix execution timeout as a Go duration string (e.g. \\\"90s\\\", \\\"2m\\\").\",\n          \"type\": \"string\",\n          \"default\": \"90s\",\n          \"pattern\": \"^([0-9]+(\\\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$\"\n        },\n        \"max-input-bytes\": {\n          \"description\": \"Maximum prompt payload size in bytes sent to the agent.\",\n          \"type\": \"integer\",\n          \"default\": 262144,\n          \"minimum\": 0\n        },\n        \"redact-secrets\": {\n          \"description\": \"Redact detected secrets in Dockerfile content before sending to the agent.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        {\n          \"enabled\": true,\n          \"command\": [\"gemini\", \"--experimental-acp\", \"--allowed-mcp-server-names=none\", \"--model=gemini-3-flash-preview\"],\n          \"timeout\": \"90s\",\n          \"max-input-bytes\": 262144,\n          \"redact-secrets\": true\n        }\n      ],\n      \"if\": {\n        \"properties\": { \"enabled\": { \"const\": true } },\n        \"required\": [\"enabled\"]\n      },\n      \"then\": {\n        \"required\": [\"command\"],\n        \"properties\": {\n          \"command\": { \"minItems\": 1 }\n        }\n      }\n    },\n    \"unsafe-fixes\": {\n      \"description\": \"Enable application of unsafe fixes. When omitted, unsafe fixes are not applied and callers may display a hint when unsafe fixes are available.\",\n      \"type\": [\"boolean\", \"null\"],\n      \"default\": null\n    },\n    \"file-validation\": {\n      \"type\": \"object\",\n      \"description\": \"Pre-parse file validation checks.\",\n      \"properties\": {\n        \"max-file-size\": {\n          \"description\": \"Maximum file size in bytes (0 = unlimited).\",\n          \"type\": \"integer\",\n          \"default\": 102400,\n          \"minimum\": 0\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"slow-checks\": {\n      \"type\": \"object\",\n      \"description\": \"Configure async checks that require network or other slow I/O (e.g. registry lookups).\",\n      \"properties\": {\n        \"mode\": {\n          \"description\": \"When to run slow checks: \\\"auto\\\" enables them in CI, \\\"on\\\" always, \\\"off\\\" never.\",\n          \"type\": \"string\",\n          \"enum\": [\"auto\", \"on\", \"off\"],\n          \"default\": \"auto\"\n        },\n        \"fail-fast\": {\n          \"description\": \"Stop slow checks on first failure instead of collecting all results.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"timeout\": {\n          \"description\": \"Overall timeout for all slow checks as a Go duration string (e.g. \\\"20s\\\").\",\n          \"type\": \"string\",\n          \"default\": \"20s\",\n          \"pattern\": \"^([0-9]+(\\\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$\"\n        }\n      },\n      \"additionalProperties\": false\n    }\n  },\n  \"additionalProperties\": false\n}\n"),
-	"https://tally.wharflab.com/rules/buildkit/index.schema.json":                     []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/buildkit/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"buildkit/* rule namespace config\",\n  \"description\": \"Schema for rules.buildkit configuration; keys are rule names within the buildkit namespace.\",\n  \"type\": \"object\",\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"StageNameCasing\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/hadolint/dl3001.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl3001.schema.json\",\n  \"title\": \"hadolint/DL3001 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL3001 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"invalid-commands\": {\n      \"type\": \"array\",\n      \"description\": \"Commands to flag as invalid inside a container.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [\"free\", \"kill\", \"mount\", \"ps\", \"service\", \"shutdown\", \"ssh\", \"top\", \"vim\"],\n      \"examples\": [[\"ssh\", \"vim\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"invalid-commands\": [\"ssh\", \"vim\"] },\n    { \"severity\": \"info\", \"invalid-commands\": [\"shutdown\"] }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/hadolint/dl3026.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl3026.schema.json\",\n  \"title\": \"hadolint/DL3026 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL3026 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"trusted-registries\": {\n      \"type\": \"array\",\n      \"description\": \"Allowed registries for base images in FROM (empty disables the rule). Supports \\\"*\\\", \\\"*.suffix\\\" and \\\"prefix*\\\" patterns.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [],\n      \"examples\": [[\"docker.io\", \"gcr.io\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"trusted-registries\": [\"docker.io\"] },\n    { \"severity\": \"warning\", \"trusted-registries\": [\"*.gcr.io\", \"ghcr.io\"] }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/hadolint/dl4001.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl4001.schema.json\",\n  \"title\": \"hadolint/DL4001 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL4001 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"fix-preference\": {\n      \"type\": \"string\",\n      \"description\": \"Which tool auto-fixes should converge on. \\\"auto\\\" (default) infers the target from stage install signals. \\\"curl\\\" and \\\"wget\\\" force the fix direction regardless of which tool is installed.\",\n      \"enum\": [\"auto\", \"curl\", \"wget\"],\n      \"default\": \"auto\",\n      \"examples\": [\"curl\", \"wget\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"fix-preference\": \"curl\" },\n    { \"severity\": \"warning\", \"fix-preference\": \"wget\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/hadolint/index.schema.json":                     []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"hadolint/* rule namespace config\",\n  \"description\": \"Schema for rules.hadolint configuration; keys are rule names within the hadolint namespace.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"DL3001\": {\n      \"$ref\": \"./dl3001.schema.json\"\n    },\n    \"DL3026\": {\n      \"$ref\": \"./dl3026.schema.json\"\n    },\n    \"DL4001\": {\n      \"$ref\": \"./dl4001.schema.json\"\n    }\n  },\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"DL3026\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/powershell/index.schema.json":                   []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/powershell/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"powershell/* rule namespace config\",\n  \"description\": \"Schema for rules.powershell configuration; keys are rule names within the powershell namespace.\",\n  \"type\": \"object\",\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"PSAvoidUsingWriteHost\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/rule-config.schema.json":                        []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/rule-config.schema.json\",\n  \"title\": \"Common rule configuration\",\n  \"description\": \"Shared schema definitions for per-rule configuration across namespaces (tally/*, hadolint/*, buildkit/*).\",\n  \"$defs\": {\n    \"severity\": {\n      \"title\": \"Rule severity\",\n      \"type\": \"string\",\n      \"description\": \"Override the rule's default severity. Use \\\"off\\\" to disable the rule.\",\n      \"enum\": [\"off\", \"error\", \"warning\", \"info\", \"style\"],\n      \"examples\": [\"warning\"]\n    },\n    \"fix\": {\n      \"title\": \"Rule fix mode\",\n      \"type\": \"string\",\n      \"description\": \"Control when auto-fixes are applied for this rule. \\\"never\\\": disable all fixes. \\\"explicit\\\": only on --fix. \\\"always\\\": always apply safe fixes. \\\"unsafe-only\\\": apply only fixes flagged as unsafe.\",\n      \"enum\": [\"never\", \"explicit\", \"always\", \"unsafe-only\"],\n      \"examples\": [\"explicit\"]\n    },\n    \"exclude\": {\n      \"title\": \"Rule exclusions\",\n      \"type\": \"object\",\n      \"description\": \"Exclude this rule for specific file paths.\",\n      \"properties\": {\n        \"paths\": {\n          \"type\": \"array\",\n          \"description\": \"Glob patterns to exclude (e.g. \\\"test/**\\\").\",\n          \"items\": { \"type\": \"string\" },\n          \"examples\": [[\"test/**\"]]\n        }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        {\n          \"paths\": [\"test/**\", \"**/vendor/**\"]\n        }\n      ]\n    },\n    \"genericRuleConfig\": {\n      \"title\": \"Generic rule configuration\",\n      \"type\": \"object\",\n      \"description\": \"Generic per-rule configuration used for rules without rule-specific options.\",\n      \"properties\": {\n        \"severity\": { \"$ref\": \"#/$defs/severity\" },\n        \"fix\": { \"$ref\": \"#/$defs/fix\" },\n        \"exclude\": { \"$ref\": \"#/$defs/exclude\" }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        { \"severity\": \"warning\" },\n        { \"fix\": \"explicit\", \"exclude\": { \"paths\": [\"test/**\"] } }\n      ]\n    }\n  }\n}\n"),
-	"https://tally.wharflab.com/rules/shellcheck/index.schema.json":                   []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/shellcheck/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"shellcheck/* rule namespace config\",\n  \"description\": \"Schema for rules.shellcheck configuration; keys are rule names within the shellcheck namespace.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"ShellCheck\": {\n      \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n    },\n    \"ShellCheckInternalError\": {\n      \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n    }\n  },\n  \"patternProperties\": {\n    \"^SC[0-9]{4}$\": {\n      \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    {\n      \"SC2086\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/consistent_indentation.schema.json":       []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/consistent_indentation.schema.json\",\n  \"title\": \"tally/consistent-indentation rule config\",\n  \"description\": \"Configuration options for the tally/consistent-indentation rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"style\" },\n    { \"severity\": \"off\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/eol_last.schema.json":                     []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/eol_last.schema.json\",\n  \"title\": \"tally/eol-last rule config\",\n  \"description\": \"Configuration options for the tally/eol-last rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"mode\": {\n      \"type\": \"string\",\n      \"enum\": [\"always\", \"never\"],\n      \"default\": \"always\",\n      \"description\": \"Whether files must end with a newline (\\\"always\\\") or must not (\\\"never\\\").\",\n      \"examples\": [\"always\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"mode\": \"always\" },\n    { \"severity\": \"style\", \"mode\": \"never\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/index.schema.json":                        []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"tally/* rule namespace config\",\n  \"description\": \"Schema for rules.tally configuration; keys are rule names within the tally namespace.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"consistent-indentation\": {\n      \"$ref\": \"./consistent_indentation.schema.json\"\n    },\n    \"eol-last\": {\n      \"$ref\": \"./eol_last.schema.json\"\n    },\n    \"labels/no-buildx-git-overlap\": {\n      \"$ref\": \"./labels/no_buildx_git_overlap.schema.json\"\n    },\n    \"labels/prefer-grouped\": {\n      \"$ref\": \"./labels/prefer_grouped.schema.json\"\n    },\n    \"labels/prefer-stable-order\": {\n      \"$ref\": \"./labels/prefer_stable_order.schema.json\"\n    },\n    \"max-lines\": {\n      \"$ref\": \"./max_lines.schema.json\"\n    },\n    \"newline-between-instructions\": {\n      \"$ref\": \"./newline_between_instructions.schema.json\"\n    },\n    \"newline-per-chained-call\": {\n      \"$ref\": \"./newline_per_chained_call.schema.json\"\n    },\n    \"no-multi-spaces\": {\n      \"$ref\": \"./no_multi_spaces.schema.json\"\n    },\n    \"no-multiple-empty-lines\": {\n      \"$ref\": \"./no_multiple_empty_lines.schema.json\"\n    },\n    \"no-trailing-spaces\": {\n      \"$ref\": \"./no_trailing_spaces.schema.json\"\n    },\n    \"prefer-add-unpack\": {\n      \"$ref\": \"./prefer_add_unpack.schema.json\"\n    },\n    \"prefer-copy-heredoc\": {\n      \"$ref\": \"./prefer_copy_heredoc.schema.json\"\n    },\n    \"prefer-curl-config\": {\n      \"$ref\": \"./prefer_curl_config.schema.json\"\n    },\n    \"prefer-formatted-heredocs\": {\n      \"$ref\": \"./prefer_formatted_heredocs.schema.json\"\n    },\n    \"prefer-multi-stage-build\": {\n      \"$ref\": \"./prefer_multi_stage_build.schema.json\"\n    },\n    \"prefer-run-heredoc\": {\n      \"$ref\": \"./prefer_run_heredoc.schema.json\"\n    },\n    \"prefer-wget-config\": {\n      \"$ref\": \"./prefer_wget_config.schema.json\"\n    },\n    \"require-secret-mounts\": {\n      \"$ref\": \"./require_secret_mounts.schema.json\"\n    }\n  },\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"max-lines\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/labels/no_buildx_git_overlap.schema.json": []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/labels/no_buildx_git_overlap.schema.json\",\n  \"title\": \"tally/labels/no-buildx-git-overlap rule config\",\n  \"description\": \"Configuration options for the tally/labels/no-buildx-git-overlap rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"buildx-git-labels\": {\n      \"type\": \"string\",\n      \"enum\": [\"off\", \"none\", \"false\", \"False\", \"FALSE\", \"0\", \"f\", \"F\", \"true\", \"True\", \"TRUE\", \"1\", \"t\", \"T\", \"full\"],\n      \"default\": \"full\",\n      \"description\": \"Controls which Buildx git label mode the rule models. \\\"off\\\", \\\"none\\\", and strconv.ParseBool false values disable the check, strconv.ParseBool true values check revision and Dockerfile-path labels, and \\\"full\\\" also checks source labels.\",\n      \"examples\": [\"full\", \"T\", \"off\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"buildx-git-labels\": \"full\" },\n    { \"severity\": \"warning\", \"buildx-git-labels\": \"full\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/labels/prefer_grouped.schema.json":        []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/labels/prefer_grouped.schema.json\",\n  \"title\": \"tally/labels/prefer-grouped rule config\",\n  \"description\": \"Configuration options for the tally/labels/prefer-grouped rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"min-labels\": {\n      \"type\": \"integer\",\n      \"minimum\": 2,\n      \"default\": 3,\n      \"description\": \"Minimum total label key/value pairs across an adjacent run of LABEL instructions before the rule reports.\",\n      \"examples\": [3, 5]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-labels\": 3 },\n    { \"severity\": \"info\", \"min-labels\": 5 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/labels/prefer_stable_order.schema.json":   []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/labels/prefer_stable_order.schema.json\",\n  \"title\": \"tally/labels/prefer-stable-order rule config\",\n  \"description\": \"Configuration options for the tally/labels/prefer-stable-order rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"order\": {\n      \"type\": \"string\",\n      \"enum\": [\"oci-logical\", \"lexical\"],\n      \"default\": \"oci-logical\",\n      \"description\": \"Comparator to use when checking key order. \\\"oci-logical\\\" groups OCI and ecosystem keys by purpose; \\\"lexical\\\" sorts purely alphabetically.\"\n    },\n    \"sort-unknown\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"When true, custom reverse-DNS keys are clustered by namespace and sorted lexically within each namespace. When false, custom keys keep their relative source order.\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"order\": \"oci-logical\" },\n    { \"order\": \"lexical\", \"severity\": \"info\" },\n    { \"order\": \"oci-logical\", \"sort-unknown\": true }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/max_lines.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/max_lines.schema.json\",\n  \"title\": \"tally/max-lines rule config\",\n  \"description\": \"Configuration options for the tally/max-lines rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 50,\n      \"description\": \"Maximum number of lines allowed (0 = disabled).\",\n      \"examples\": [100]\n    },\n    \"skip-blank-lines\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Exclude blank lines from the count.\",\n      \"examples\": [true]\n    },\n    \"skip-comments\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Exclude comment lines from the count.\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 100 },\n    { \"severity\": \"warning\", \"max\": 200, \"skip-comments\": false },\n    { \"exclude\": { \"paths\": [\"test/**\"] }, \"max\": 120 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/newline_between_instructions.schema.json": []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/newline_between_instructions.schema.json\",\n  \"title\": \"tally/newline-between-instructions rule config\",\n  \"description\": \"Configuration options for the tally/newline-between-instructions rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"mode\": {\n      \"type\": \"string\",\n      \"enum\": [\"grouped\", \"always\", \"never\"],\n      \"default\": \"grouped\",\n      \"description\": \"Controls blank-line behavior between instructions.\",\n      \"examples\": [\"grouped\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"mode\": \"grouped\" },\n    { \"severity\": \"style\", \"mode\": \"always\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/newline_per_chained_call.schema.json":     []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/newline_per_chained_call.schema.json\",\n  \"title\": \"tally/newline-per-chained-call rule config\",\n  \"description\": \"Configuration options for the tally/newline-per-chained-call rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"min-commands\": {\n      \"type\": \"integer\",\n      \"minimum\": 2,\n      \"default\": 2,\n      \"description\": \"Minimum number of chained commands required to trigger splitting.\",\n      \"examples\": [3]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-commands\": 2 },\n    { \"severity\": \"style\", \"min-commands\": 4 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/no_multi_spaces.schema.json":              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_multi_spaces.schema.json\",\n  \"title\": \"tally/no-multi-spaces rule config\",\n  \"description\": \"Configuration options for the tally/no-multi-spaces rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"style\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/no_multiple_empty_lines.schema.json":      []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_multiple_empty_lines.schema.json\",\n  \"title\": \"tally/no-multiple-empty-lines rule config\",\n  \"description\": \"Configuration options for the tally/no-multiple-empty-lines rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 1,\n      \"description\": \"Maximum number of consecutive empty lines allowed anywhere in the file.\",\n      \"examples\": [1, 2]\n    },\n    \"max-bof\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 0,\n      \"description\": \"Maximum number of consecutive empty lines allowed at the beginning of the file.\",\n      \"examples\": [0, 1]\n    },\n    \"max-eof\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 0,\n      \"description\": \"Maximum number of consecutive empty lines allowed at the end of the file.\",\n      \"examples\": [0, 1]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 2 },\n    { \"max\": 1, \"max-bof\": 0, \"max-eof\": 0 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/no_trailing_spaces.schema.json":           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_trailing_spaces.schema.json\",\n  \"title\": \"tally/no-trailing-spaces rule config\",\n  \"description\": \"Configuration options for the tally/no-trailing-spaces rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"skip-blank-lines\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"Skip lines that consist entirely of whitespace.\",\n      \"examples\": [true]\n    },\n    \"ignore-comments\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"Skip any line whose first non-whitespace character is # (Dockerfile comments and # lines in heredocs).\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"ignore-comments\": true },\n    { \"severity\": \"style\", \"skip-blank-lines\": true }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_add_unpack.schema.json":            []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_add_unpack.schema.json\",\n  \"title\": \"tally/prefer-add-unpack rule config\",\n  \"description\": \"Configuration options for the tally/prefer-add-unpack rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"enabled\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Enable or disable this rule (independent of severity).\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"enabled\": false },\n    { \"severity\": \"info\", \"enabled\": true }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_copy_heredoc.schema.json":          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_copy_heredoc.schema.json\",\n  \"title\": \"tally/prefer-copy-heredoc rule config\",\n  \"description\": \"Configuration options for the tally/prefer-copy-heredoc rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"check-single-run\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Detect single RUN instructions that create files and suggest COPY heredoc.\",\n      \"examples\": [true]\n    },\n    \"check-consecutive-runs\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Detect sequences of consecutive RUN instructions that create/append to the same file.\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"check-single-run\": true, \"check-consecutive-runs\": true },\n    { \"severity\": \"style\", \"check-single-run\": false }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_curl_config.schema.json":           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_curl_config.schema.json\",\n  \"title\": \"tally/prefer-curl-config rule config\",\n  \"description\": \"Configuration options for the tally/prefer-curl-config rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"retry\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 5,\n      \"description\": \"Number of retries for failed transfers.\",\n      \"examples\": [3, 5]\n    },\n    \"connect-timeout\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 15,\n      \"description\": \"Maximum time in seconds for the connection phase.\",\n      \"examples\": [10, 15]\n    },\n    \"max-time\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 300,\n      \"description\": \"Maximum time in seconds for the entire transfer.\",\n      \"examples\": [120, 300]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"retry\": 3, \"connect-timeout\": 10 },\n    { \"severity\": \"warning\", \"retry\": 10, \"max-time\": 600 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_formatted_heredocs.schema.json":    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_formatted_heredocs.schema.json\",\n  \"title\": \"tally/prefer-formatted-heredocs rule config\",\n  \"description\": \"Configuration options for the tally/prefer-formatted-heredocs rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"style\" }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_multi_stage_build.schema.json":     []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_multi_stage_build.schema.json\",\n  \"title\": \"tally/prefer-multi-stage-build rule config\",\n  \"description\": \"Configuration options for the tally/prefer-multi-stage-build rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"min-score\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"default\": 4,\n      \"description\": \"Minimum heuristic score required to trigger the suggestion.\",\n      \"examples\": [6]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-score\": 6 },\n    { \"severity\": \"info\", \"min-score\": 6 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_run_heredoc.schema.json":           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_run_heredoc.schema.json\",\n  \"title\": \"tally/prefer-run-heredoc rule config\",\n  \"description\": \"Configuration options for the tally/prefer-run-heredoc rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"min-commands\": {\n      \"type\": \"integer\",\n      \"minimum\": 2,\n      \"default\": 3,\n      \"description\": \"Minimum number of commands required to trigger heredoc conversion.\",\n      \"examples\": [3]\n    },\n    \"check-consecutive-runs\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Enable detection of multiple consecutive RUN instructions.\",\n      \"examples\": [true]\n    },\n    \"check-chained-commands\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Enable detection of chained commands within a single RUN (via &&).\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-commands\": 3 },\n    { \"severity\": \"style\", \"min-commands\": 4, \"check-chained-commands\": false }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/prefer_wget_config.schema.json":           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_wget_config.schema.json\",\n  \"title\": \"tally/prefer-wget-config rule config\",\n  \"description\": \"Configuration options for the tally/prefer-wget-config rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"timeout\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 15,\n      \"description\": \"Maximum time in seconds before retrying a stalled or failed download.\",\n      \"examples\": [10, 15]\n    },\n    \"tries\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 5,\n      \"description\": \"Number of retries for failed downloads.\",\n      \"examples\": [3, 5]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"timeout\": 10, \"tries\": 3 },\n    { \"severity\": \"warning\", \"tries\": 7 }\n  ]\n}\n"),
-	"https://tally.wharflab.com/rules/tally/require_secret_mounts.schema.json":        []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/require_secret_mounts.schema.json\",\n  \"title\": \"tally/require-secret-mounts rule config\",\n  \"description\": \"Configuration options for the tally/require-secret-mounts rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"commands\": {\n      \"type\": \"object\",\n      \"description\": \"Map of command names to required secret mount specifications. Each entry specifies a file target, an environment variable, or both.\",\n      \"additionalProperties\": {\n        \"type\": \"object\",\n        \"properties\": {\n          \"id\": {\n            \"type\": \"string\",\n            \"minLength\": 1,\n            \"description\": \"Required secret ID for the --mount flag.\"\n          },\n          \"target\": {\n            \"type\": \"string\",\n            \"minLength\": 1,\n            \"description\": \"Target path where the secret file is mounted.\"\n          },\n          \"env\": {\n            \"type\": \"string\",\n            \"minLength\": 1,\n            \"description\": \"Environment variable name to expose the secret as.\"\n          },\n          \"required\": {\n            \"type\": \"boolean\",\n            \"default\": false,\n            \"description\": \"Fail the build if the secret is not provided. Maps to the 'required' mount parameter.\"\n          }\n        },\n        \"required\": [\"id\"],\n        \"anyOf\": [\n          { \"required\": [\"target\"] },\n          { \"required\": [\"env\"] }\n        ],\n        \"additionalProperties\": false\n      }\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    {\n      \"severity\": \"warning\",\n      \"commands\": {\n        \"pip\": { \"id\": \"pipconf\", \"target\": \"/root/.config/pip/pip.conf\" },\n        \"aws\": { \"id\": \"aws\", \"target\": \"/root/.aws/credentials\" }\n      }\n    },\n    {\n      \"commands\": {\n        \"gh\": { \"id\": \"gh-token\", \"env\": \"GH_TOKEN\" }\n      }\n    },\n    {\n      \"commands\": {\n        \"aws\": { \"id\": \"aws-creds\", \"target\": \"/root/.aws/credentials\", \"env\": \"AWS_SHARED_CREDENTIALS_FILE\" }\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/root/tally-config.schema.json":                              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/root/tally-config.schema.json\",\n  \"title\": \"tally configuration\",\n  \"description\": \"Configuration schema for tally Dockerfile linter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"rules\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"include\": {\n          \"description\": \"Glob patterns for rules to enable (e.g. \\\"tally/*\\\", \\\"hadolint/DL3026\\\").\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\" }\n        },\n        \"exclude\": {\n          \"description\": \"Glob patterns for rules to disable (e.g. \\\"buildkit/MaintainerDeprecated\\\").\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\" }\n        },\n        \"timeout\": {\n          \"description\": \"Per-rule execution budget as a Go duration string (e.g. \\\"200ms\\\"). A rule that exceeds it on a file is skipped and reported by tally/rule-timeout. \\\"0\\\" disables the budget.\",\n          \"type\": \"string\",\n          \"default\": \"0\",\n          \"pattern\": \"^(0|([0-9]+(\\\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$\",\n          \"examples\": [\"200ms\"]\n        },\n        \"tally\": {\n          \"$ref\": \"../../rules/tally/index.schema.json\"\n        },\n        \"hadolint\": {\n          \"$ref\": \"../../rules/hadolint/index.schema.json\"\n        },\n        \"buildkit\": {\n          \"$ref\": \"../../rules/buildkit/index.schema.json\"\n        },\n        \"shellcheck\": {\n          \"$ref\": \"../../rules/shellcheck/index.schema.json\"\n        },\n        \"powershell\": {\n          \"$ref\": \"../../rules/powershell/index.schema.json\"\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"output\": {\n      \"type\": \"object\",\n      \"description\": \"Configure output format and destination.\",\n      \"properties\": {\n        \"format\": {\n          \"description\": \"Output format for lint results.\",\n          \"type\": \"string\",\n          \"enum\": [\"text\", \"json\", \"sarif\", \"github-actions\", \"markdown\", \"html\", \"tap\"],\n          \"default\": \"text\"\n        },\n        \"path\": {\n          \"description\": \"Write output to this path instead of stdout. A path with {path}, {name}, {dir} or {hash} placeholders writes one report per linted file (e.g. \\\"reports/{dir}.sarif\\\").\",\n          \"type\": \"string\",\n          \"default\": \"stdout\"\n        },\n        \"show-source\": {\n          \"description\": \"Include source code snippets in output.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"fail-level\": {\n          \"description\": \"Minimum severity that causes a non-zero exit code.\",\n          \"type\": \"string\",\n          \"enum\": [\"error\", \"warning\", \"info\", \"style\", \"none\"],\n          \"default\": \"style\"\n        },\n        \"group-by\": {\n          \"description\": \"Group text output by file, rule or severity, with per-group counts and a summary. For tap output, selects whether each file, rule or severity is one test point.\",\n          \"type\": \"string\",\n          \"enum\": [\"none\", \"file\", \"rule\", \"severity\"],\n          \"default\": \"none\"\n        },\n        \"annotation-limit\": {\n          \"description\": \"Maximum github-actions annotations per level (error, warning, notice); 0 disables the limit.\",\n          \"type\": \"integer\",\n          \"minimum\": 0,\n          \"default\": 10\n        },\n        \"show-suppressed\": {\n          \"description\": \"Also list violations suppressed by inline directives or rule configuration, marked with the suppression source (text, json and sarif formats).\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"show-fixes\": {\n          \"description\": \"Show each violation's suggested fix as an inline before/after diff (text format).\",\n          \"type\": \"boolean\",\n          \"default\": false\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"inline-directives\": {\n      \"type\": \"object\",\n      \"description\": \"Control inline suppression directives (e.g. # tally-ignore).\",\n      \"properties\": {\n        \"enabled\": {\n          \"description\": \"Allow inline directives to suppress violations.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"warn-unused\": {\n          \"description\": \"Warn when an inline directive does not suppress any violation.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"validate-rules\": {\n          \"description\": \"Warn when an inline directive references a rule that does not exist.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"require-reason\": {\n          \"description\": \"Require a reason comment on every inline suppression directive.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"ai\": {\n      \"type\": \"object\",\n      \"description\": \"Configure opt-in AI AutoFix features (requires an ACP-capable agent).\",\n      \"properties\": {\n        \"enabled\": {\n          \"description\": \"Enable AI AutoFix. When false, AI fixes are never resolved.\",\n          \"type\": \"boolean\",\n          \"default\": false\n        },\n        \"command\": {\n          \"description\": \"Argv to launch the ACP agent process over stdio.\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\", \"minLength\": 1 },\n          \"examples\": [\n            [\"gemini\", \"--experimental-acp\", \"--allowed-mcp-server-names=none\", \"--model=gemini-3-flash-preview\"],\n            [\"opencode\", \"--acp\"],\n            [\"kiro\", \"--acp\"]\n          ]\n        },\n        \"timeout\": {\n          \"description\": \"Per-fix execution timeout as a Go duration string (e.g. \\\"90s\\\", \\\"2m\\\").\",\n          \"type\": \"string\",\n          \"default\": \"90s\",\n          \"pattern\": \"^([0-9]+(\\\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$\"\n        },\n        \"max-input-bytes\": {\n          \"description\": \"Maximum prompt payload size in bytes sent to the agent.\",\n          \"type\": \"integer\",\n          \"default\": 262144,\n          \"minimum\": 0\n        },\n        \"redact-secrets\": {\n          \"description\": \"Redact detected secrets in Dockerfile content before sending to the agent.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"rules\": {\n          \"description\": \"Rule codes allowed to use AI AutoFix. When omitted or empty, every rule that offers an AI fix may use it.\",\n          \"type\": \"array\",\n          \"items\": { \"type\": \"string\", \"minLength\": 1 },\n          \"uniqueItems\": true,\n          \"examples\": [[\"tally/prefer-multi-stage-build\", \"hadolint/DL4001\"]]\n        },\n        \"prompts\": {\n          \"description\": \"Custom prompt templates keyed by rule code (Go text/template). Available fields: {{.Rule}}, {{.File}}, {{.Message}}, {{.Detail}}, {{.Excerpt}}. tally always appends the Dockerfile and output format instructions.\",\n          \"type\": \"object\",\n          \"additionalProperties\": { \"type\": \"string\", \"minLength\": 1 },\n          \"examples\": [\n            {\n              \"tally/prefer-multi-stage-build\": \"Convert this Dockerfile to a multi-stage build. Use our internal base image registry.example.com/base for the final stage.\\n\\nWhy tally flagged it:\\n{{.Detail}}\"\n            }\n          ]\n        }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        {\n          \"enabled\": true,\n          \"command\": [\"gemini\", \"--experimental-acp\", \"--allowed-mcp-server-names=none\", \"--model=gemini-3-flash-preview\"],\n          \"timeout\": \"90s\",\n          \"max-input-bytes\": 262144,\n          \"redact-secrets\": true\n        }\n      ],\n      \"if\": {\n        \"properties\": { \"enabled\": { \"const\": true } },\n        \"required\": [\"enabled\"]\n      },\n      \"then\": {\n        \"required\": [\"command\"],\n        \"properties\": {\n          \"command\": { \"minItems\": 1 }\n        }\n      }\n    },\n    \"extends\": {\n      \"description\": \"Configs to merge underneath this one, in order: local paths (relative to this file), https:// URLs, or github.com/<owner>/<repo>[/<path>][@<ref>] references. Later entries override earlier ones and this file overrides them all.\",\n      \"type\": \"array\",\n      \"items\": { \"type\": \"string\", \"minLength\": 1 }\n    },\n    \"profile\": {\n      \"description\": \"Built-in profile layered under this configuration: \\\"recommended\\\" adds checks that are off by default but need no setup, \\\"strict\\\" enables every such check and requires explained suppressions, \\\"minimal\\\" keeps only correctness and security checks, \\\"hadolint-compat\\\" matches hadolint's rule set and exit behavior.\",\n      \"type\": \"string\",\n      \"enum\": [\"recommended\", \"strict\", \"minimal\", \"hadolint-compat\"]\n    },\n    \"build-args\": {\n      \"description\": \"Build args passed to the build (like docker build --build-arg), keyed by ARG name. They seed ARG resolution so variable expansion in FROM and other instructions sees the values CI uses. Args declared by a Bake target or Compose service take precedence.\",\n      \"type\": \"object\",\n      \"additionalProperties\": { \"type\": \"string\" },\n      \"examples\": [{ \"VERSION\": \"1.4.2\", \"BASE_IMAGE\": \"alpine:3.20\" }]\n    },\n    \"dialect\": {\n      \"description\": \"Dockerfile dialect to accept: \\\"docker\\\" follows BuildKit, \\\"podman\\\" also understands Podman/Buildah extensions such as RUN --mount=type=devpts and SELinux relabel mount options.\",\n      \"type\": \"string\",\n      \"enum\": [\"docker\", \"podman\"],\n      \"default\": \"docker\"\n    },\n    \"unsafe-fixes\": {\n      \"description\": \"Enable application of unsafe fixes. When omitted, unsafe fixes are not applied and callers may display a hint when unsafe fixes are available.\",\n      \"type\": [\"boolean\", \"null\"],\n      \"default\": null\n    },\n    \"fix-precedence\": {\n      \"description\": \"Rules whose fixes win when two fixes overlap, highest precedence first. Entries are rule codes or namespace wildcards (e.g. \\\"hadolint/*\\\"). Listed rules win over later and unlisted rules; the losing fix is retried against the updated content.\",\n      \"type\": \"array\",\n      \"items\": { \"type\": \"string\", \"minLength\": 1 },\n      \"examples\": [[\"tally/prefer-package-cache-mounts\", \"hadolint/*\"]]\n    },\n    \"duplicate-precedence\": {\n      \"description\": \"Rules whose violations replace those of other rules reporting the same problem on the same line. Keys are rule codes; values are the rule codes or namespace wildcards (e.g. \\\"hadolint/*\\\") they replace. An entry replaces the rule's built-in list, so an empty list keeps every violation it would otherwise replace.\",\n      \"type\": \"object\",\n      \"additionalProperties\": {\n        \"type\": \"array\",\n        \"items\": { \"type\": \"string\", \"minLength\": 1 }\n      },\n      \"examples\": [{ \"tally/mount-secret-instead-of-copy\": [], \"hadolint/DL3020\": [\"tally/mount-secret-instead-of-copy\"] }]\n    },\n    \"severity-by-stage-role\": {\n      \"description\": \"Severity overrides by the role of the stage a violation is in. \\\"final\\\" covers the exported stage (the last stage, or the --target stage) and the stages it is built FROM; \\\"builder\\\" covers every other stage. Keys are rule codes, namespace wildcards (e.g. \\\"hadolint/*\\\"), or \\\"*\\\"; the most specific match wins. Overrides apply on top of the rule severity and don't enable rules that are off.\",\n      \"type\": \"object\",\n      \"properties\": {\n        \"final\": {\n          \"description\": \"Severities for violations in the exported stages, keyed by rule pattern.\",\n          \"type\": \"object\",\n          \"additionalProperties\": { \"type\": \"string\", \"enum\": [\"off\", \"error\", \"warning\", \"info\", \"style\"] }\n        },\n        \"builder\": {\n          \"description\": \"Severities for violations in builder stages, keyed by rule pattern.\",\n          \"type\": \"object\",\n          \"additionalProperties\": { \"type\": \"string\", \"enum\": [\"off\", \"error\", \"warning\", \"info\", \"style\"] }\n        }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        {\n          \"final\": { \"tally/secrets-in-code\": \"error\" },\n          \"builder\": { \"tally/secrets-in-code\": \"warning\", \"hadolint/DL3059\": \"off\" }\n        }\n      ]\n    },\n    \"file-validation\": {\n      \"type\": \"object\",\n      \"description\": \"Pre-parse file validation checks.\",\n      \"properties\": {\n        \"max-file-size\": {\n          \"description\": \"Maximum file size in bytes (0 = unlimited).\",\n          \"type\": \"integer\",\n          \"default\": 102400,\n          \"minimum\": 0\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"slow-checks\": {\n      \"type\": \"object\",\n      \"description\": \"Configure async checks that require network or other slow I/O (e.g. registry lookups).\",\n      \"properties\": {\n        \"mode\": {\n          \"description\": \"When to run slow checks: \\\"auto\\\" enables them in CI, \\\"on\\\" always, \\\"off\\\" never.\",\n          \"type\": \"string\",\n          \"enum\": [\"auto\", \"on\", \"off\"],\n          \"default\": \"auto\"\n        },\n        \"fail-fast\": {\n          \"description\": \"Stop slow checks on first failure instead of collecting all results.\",\n          \"type\": \"boolean\",\n          \"default\": true\n        },\n        \"timeout\": {\n          \"description\": \"Overall timeout for all slow checks as a Go duration string (e.g. \\\"20s\\\").\",\n          \"type\": \"string\",\n          \"default\": \"20s\",\n          \"pattern\": \"^([0-9]+(\\\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$\"\n        },\n        \"cache-ttl\": {\n          \"description\": \"How long registry lookups are cached on disk as a Go duration string (e.g. \\\"1h\\\"). \\\"0\\\" disables the cache. Digest-pinned references never expire.\",\n          \"type\": \"string\",\n          \"default\": \"1h\",\n          \"pattern\": \"^(0|([0-9]+(\\\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$\"\n        },\n        \"registries\": {\n          \"description\": \"Per-registry connection settings keyed by registry host (e.g. \\\"registry.internal:5000\\\"). Credentials are read from Docker and containers auth files and credential helpers.\",\n          \"type\": \"object\",\n          \"additionalProperties\": {\n            \"type\": \"object\",\n            \"properties\": {\n              \"insecure\": {\n                \"description\": \"Skip TLS certificate verification and allow plain HTTP for this registry.\",\n                \"type\": \"boolean\",\n                \"default\": false\n              },\n              \"certs-dir\": {\n                \"description\": \"Directory with ca.crt, client.cert, and client.key for this registry (same layout as /etc/docker/certs.d/<host>).\",\n                \"type\": \"string\",\n                \"minLength\": 1\n              }\n            },\n            \"additionalProperties\": false\n          },\n          \"examples\": [\n            {\n              \"registry.internal:5000\": { \"insecure\": true },\n              \"ghcr.example.com\": { \"certs-dir\": \"/etc/docker/certs.d/ghcr.example.com\" }\n            }\n          ]\n        }\n      },\n      \"additionalProperties\": false\n    },\n    \"registries\": {\n      \"type\": \"object\",\n      \"description\": \"Image registry policy shared by rules that check where base images come from, such as hadolint/DL3026.\",\n      \"properties\": {\n        \"trusted\": {\n          \"description\": \"Trusted registries, used by rules that have no list of their own. Entries are hosts with an optional port; \\\"*\\\" matches any registry, \\\"*.suffix\\\" any subdomain and \\\"prefix*\\\" any host with that prefix. An entry without a port matches the host on any port.\",\n          \"type\": \"array\",\n          \"items\": {\n            \"type\": \"string\",\n            \"minLength\": 1\n          },\n          \"uniqueItems\": true,\n          \"examples\": [[\"docker.io\", \"*.corp.example.com\", \"registry.internal:5000\"]]\n        },\n        \"mirrors\": {\n          \"description\": \"Mirror registries keyed by host, each mapped to the registry it mirrors. A mirror and its upstream are treated as the same registry.\",\n          \"type\": \"object\",\n          \"additionalProperties\": {\n            \"type\": \"string\",\n            \"minLength\": 1\n          },\n          \"examples\": [{ \"mirror.gcr.io\": \"docker.io\", \"harbor.corp.example.com:8443\": \"docker.io\" }]\n        }\n      },\n      \"additionalProperties\": false\n    }\n  },\n  \"additionalProperties\": false\n}\n"),
+	"https://tally.wharflab.com/rules/buildkit/index.schema.json":                           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/buildkit/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"buildkit/* rule namespace config\",\n  \"description\": \"Schema for rules.buildkit configuration; keys are rule names within the buildkit namespace.\",\n  \"type\": \"object\",\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"StageNameCasing\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/hadolint/dl3001.schema.json":                          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl3001.schema.json\",\n  \"title\": \"hadolint/DL3001 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL3001 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"invalid-commands\": {\n      \"type\": \"array\",\n      \"description\": \"Commands to flag as invalid inside a container.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [\"free\", \"kill\", \"mount\", \"ps\", \"service\", \"shutdown\", \"ssh\", \"top\", \"vim\"],\n      \"examples\": [[\"ssh\", \"vim\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"invalid-commands\": [\"ssh\", \"vim\"] },\n    { \"severity\": \"info\", \"invalid-commands\": [\"shutdown\"] }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/hadolint/dl3020.schema.json":                          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl3020.schema.json\",\n  \"title\": \"hadolint/DL3020 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL3020 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"archive-extensions\": {\n      \"type\": \"array\",\n      \"description\": \"Extra file name suffixes, besides the tar extensions, marking local archives that ADD is meant to extract. Sources ending with one of them are not reported.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [],\n      \"examples\": [[\".bundle\", \".layer\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"archive-extensions\": [\".bundle\"] },\n    { \"severity\": \"warning\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/hadolint/dl3026.schema.json":                          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl3026.schema.json\",\n  \"title\": \"hadolint/DL3026 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL3026 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"trusted-registries\": {\n      \"type\": \"array\",\n      \"description\": \"Allowed registries for base images in FROM. Supports \\\"*\\\", \\\"*.suffix\\\" and \\\"prefix*\\\" patterns, with an optional port. When empty, the global registries.trusted list is used; if that is empty too, the rule is disabled.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [],\n      \"examples\": [[\"docker.io\", \"gcr.io\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"trusted-registries\": [\"docker.io\"] },\n    { \"severity\": \"warning\", \"trusted-registries\": [\"*.gcr.io\", \"ghcr.io\"] }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/hadolint/dl4001.schema.json":                          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/dl4001.schema.json\",\n  \"title\": \"hadolint/DL4001 rule config\",\n  \"description\": \"Configuration options for the hadolint/DL4001 rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"fix-preference\": {\n      \"type\": \"string\",\n      \"description\": \"Which tool auto-fixes should converge on. \\\"auto\\\" (default) infers the target from stage install signals. \\\"curl\\\" and \\\"wget\\\" force the fix direction regardless of which tool is installed.\",\n      \"enum\": [\"auto\", \"curl\", \"wget\"],\n      \"default\": \"auto\",\n      \"examples\": [\"curl\", \"wget\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"fix-preference\": \"curl\" },\n    { \"severity\": \"warning\", \"fix-preference\": \"wget\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/hadolint/index.schema.json":                           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/hadolint/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"hadolint/* rule namespace config\",\n  \"description\": \"Schema for rules.hadolint configuration; keys are rule names within the hadolint namespace.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"DL3001\": {\n      \"$ref\": \"./dl3001.schema.json\"\n    },\n    \"DL3020\": {\n      \"$ref\": \"./dl3020.schema.json\"\n    },\n    \"DL3026\": {\n      \"$ref\": \"./dl3026.schema.json\"\n    },\n    \"DL4001\": {\n      \"$ref\": \"./dl4001.schema.json\"\n    }\n  },\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"DL3026\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/powershell/index.schema.json":                         []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/powershell/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"powershell/* rule namespace config\",\n  \"description\": \"Schema for rules.powershell configuration; keys are rule names within the powershell namespace.\",\n  \"type\": \"object\",\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"PSAvoidUsingWriteHost\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/rule-config.schema.json":                              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/rule-config.schema.json\",\n  \"title\": \"Common rule configuration\",\n  \"description\": \"Shared schema definitions for per-rule configuration across namespaces (tally/*, hadolint/*, buildkit/*).\",\n  \"$defs\": {\n    \"severity\": {\n      \"title\": \"Rule severity\",\n      \"type\": \"string\",\n      \"description\": \"Override the rule's default severity. Use \\\"off\\\" to disable the rule.\",\n      \"enum\": [\"off\", \"error\", \"warning\", \"info\", \"style\"],\n      \"examples\": [\"warning\"]\n    },\n    \"fix\": {\n      \"title\": \"Rule fix mode\",\n      \"type\": \"string\",\n      \"description\": \"Control when auto-fixes are applied for this rule. \\\"never\\\": disable all fixes. \\\"explicit\\\": only on --fix. \\\"always\\\": always apply safe fixes. \\\"unsafe-only\\\": apply only fixes flagged as unsafe.\",\n      \"enum\": [\"never\", \"explicit\", \"always\", \"unsafe-only\"],\n      \"examples\": [\"explicit\"]\n    },\n    \"fix-safety\": {\n      \"title\": \"Rule fix safety\",\n      \"type\": \"string\",\n      \"description\": \"Override the safety of this rule's fixes, which decides whether --fix applies them. \\\"safe\\\": apply with --fix. \\\"suggestion\\\" and \\\"unsafe\\\": apply only with --fix-unsafe.\",\n      \"enum\": [\"safe\", \"suggestion\", \"unsafe\"],\n      \"examples\": [\"safe\"]\n    },\n    \"exclude\": {\n      \"title\": \"Rule exclusions\",\n      \"type\": \"object\",\n      \"description\": \"Exclude this rule for specific file paths.\",\n      \"properties\": {\n        \"paths\": {\n          \"type\": \"array\",\n          \"description\": \"Glob patterns to exclude (e.g. \\\"test/**\\\").\",\n          \"items\": { \"type\": \"string\" },\n          \"examples\": [[\"test/**\"]]\n        }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        {\n          \"paths\": [\"test/**\", \"**/vendor/**\"]\n        }\n      ]\n    },\n    \"paths\": {\n      \"title\": \"Rule paths\",\n      \"type\": \"array\",\n      \"description\": \"Glob patterns, relative to the config file's directory, of the Dockerfiles this rule applies to. When set, the rule is disabled for every other file.\",\n      \"items\": { \"type\": \"string\", \"minLength\": 1 },\n      \"examples\": [[\"services/api/**\"]]\n    },\n    \"exclude-paths\": {\n      \"title\": \"Rule excluded paths\",\n      \"type\": \"array\",\n      \"description\": \"Glob patterns, relative to the config file's directory, of Dockerfiles this rule is disabled for. Takes precedence over paths.\",\n      \"items\": { \"type\": \"string\", \"minLength\": 1 },\n      \"examples\": [[\"legacy/**\"]]\n    },\n    \"genericRuleConfig\": {\n      \"title\": \"Generic rule configuration\",\n      \"type\": \"object\",\n      \"description\": \"Generic per-rule configuration used for rules without rule-specific options.\",\n      \"properties\": {\n        \"severity\": { \"$ref\": \"#/$defs/severity\" },\n        \"fix\": { \"$ref\": \"#/$defs/fix\" },\n        \"fix-safety\": { \"$ref\": \"#/$defs/fix-safety\" },\n        \"exclude\": { \"$ref\": \"#/$defs/exclude\" },\n        \"paths\": { \"$ref\": \"#/$defs/paths\" },\n        \"exclude-paths\": { \"$ref\": \"#/$defs/exclude-paths\" }\n      },\n      \"additionalProperties\": false,\n      \"examples\": [\n        { \"severity\": \"warning\" },\n        { \"fix\": \"explicit\", \"exclude\": { \"paths\": [\"test/**\"] } },\n        { \"severity\": \"error\", \"paths\": [\"services/api/**\"], \"exclude-paths\": [\"services/api/legacy/**\"] }\n      ]\n    }\n  }\n}\n"),
+	"https://tally.wharflab.com/rules/shellcheck/index.schema.json":                         []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/shellcheck/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"shellcheck/* rule namespace config\",\n  \"description\": \"Schema for rules.shellcheck configuration; keys are rule names within the shellcheck namespace.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"ShellCheck\": {\n      \"$ref\": \"./shellcheck.schema.json\"\n    },\n    \"ShellCheckInternalError\": {\n      \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n    }\n  },\n  \"patternProperties\": {\n    \"^SC[0-9]{4}$\": {\n      \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    {\n      \"SC2086\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/shellcheck/shellcheck.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/shellcheck/shellcheck.schema.json\",\n  \"title\": \"shellcheck/ShellCheck rule config\",\n  \"description\": \"Configuration options for the shellcheck/ShellCheck rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"engine\": {\n      \"type\": \"string\",\n      \"enum\": [\"embedded\", \"external\"],\n      \"default\": \"embedded\",\n      \"description\": \"ShellCheck implementation to run: the embedded WebAssembly build, or an installed shellcheck executable.\",\n      \"examples\": [\"external\"]\n    },\n    \"executable\": {\n      \"type\": \"string\",\n      \"minLength\": 1,\n      \"default\": \"shellcheck\",\n      \"description\": \"Executable used by the external engine, as a path or a name looked up in PATH. TALLY_SHELLCHECK overrides the default.\",\n      \"examples\": [\"/usr/local/bin/shellcheck\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"engine\": \"external\" },\n    { \"engine\": \"external\", \"executable\": \"/opt/homebrew/bin/shellcheck\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/allowed_base_images.schema.json":                []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/allowed_base_images.schema.json\",\n  \"title\": \"tally/allowed-base-images rule config\",\n  \"description\": \"Configuration options for the tally/allowed-base-images rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"allow\": { \"$ref\": \"#/$defs/baseImagePatterns\", \"description\": \"Glob patterns base images of every stage must match. Empty allows every image that is not denied.\" },\n    \"deny\": { \"$ref\": \"#/$defs/baseImagePatterns\", \"description\": \"Glob patterns no base image may match. Deny wins over allow.\" },\n    \"final\": { \"$ref\": \"#/$defs/baseImagePolicy\", \"description\": \"Policy for stages exported in the image: the final (or target) stage and the stages it is built FROM.\" },\n    \"builder\": { \"$ref\": \"#/$defs/baseImagePolicy\", \"description\": \"Policy for builder stages, whose filesystem is discarded after the build.\" }\n  },\n  \"additionalProperties\": false,\n  \"$defs\": {\n    \"baseImagePatterns\": {\n      \"type\": \"array\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"examples\": [[\"registry.corp/*\", \"cgr.dev/chainguard/*\"]]\n    },\n    \"baseImagePolicy\": {\n      \"type\": \"object\",\n      \"properties\": {\n        \"allow\": { \"$ref\": \"#/$defs/baseImagePatterns\", \"description\": \"Glob patterns replacing the top-level allowlist for this stage role.\" },\n        \"deny\": { \"$ref\": \"#/$defs/baseImagePatterns\", \"description\": \"Glob patterns denied in this stage role, in addition to the top-level denylist.\" }\n      },\n      \"additionalProperties\": false\n    }\n  },\n  \"examples\": [\n    { \"allow\": [\"registry.corp/*\"] },\n    {\n      \"deny\": [\"*:latest\"],\n      \"final\": { \"allow\": [\"cgr.dev/chainguard/*\"] },\n      \"builder\": { \"allow\": [\"cgr.dev/chainguard/*\", \"golang:*\", \"node:*\"] }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/base_image_not_eol.schema.json":                 []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/base_image_not_eol.schema.json\",\n  \"title\": \"tally/base-image-not-eol rule config\",\n  \"description\": \"Configuration options for the tally/base-image-not-eol rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"grace-period-days\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 0,\n      \"description\": \"Days after a release reaches end-of-life before the rule reports it.\",\n      \"examples\": [90]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"warning\" },\n    { \"severity\": \"error\", \"grace-period-days\": 90 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/base_image_vulnerabilities.schema.json":         []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/base_image_vulnerabilities.schema.json\",\n  \"title\": \"tally/base-image-vulnerabilities rule config\",\n  \"description\": \"Configuration options for the tally/base-image-vulnerabilities rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"min-severity\": {\n      \"type\": \"string\",\n      \"enum\": [\"critical\", \"high\", \"medium\", \"low\"],\n      \"default\": \"critical\",\n      \"description\": \"Lowest advisory severity counted in the report.\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"info\" },\n    { \"severity\": \"warning\", \"min-severity\": \"high\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/consistent_indentation.schema.json":             []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/consistent_indentation.schema.json\",\n  \"title\": \"tally/consistent-indentation rule config\",\n  \"description\": \"Configuration options for the tally/consistent-indentation rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"style\" },\n    { \"severity\": \"off\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/copy_from_unresolved_image.schema.json":         []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/copy_from_unresolved_image.schema.json\",\n  \"title\": \"tally/copy-from-unresolved-image rule config\",\n  \"description\": \"Configuration options for the tally/copy-from-unresolved-image rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"check-paths\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"Also look up the COPY sources in the image layers. Downloads every layer of the image.\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"error\" },\n    { \"check-paths\": true }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/deprecated_base_image.schema.json":              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/deprecated_base_image.schema.json\",\n  \"title\": \"tally/deprecated-base-image rule config\",\n  \"description\": \"Configuration options for the tally/deprecated-base-image rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"replacements\": {\n      \"type\": \"object\",\n      \"description\": \"Additional deprecated images mapped to the repository that replaces them, e.g. internal image renames. The fix keeps the tag. An empty successor reports the image without a fix. Entries override the built-in mapping.\",\n      \"additionalProperties\": {\n        \"type\": \"string\"\n      },\n      \"default\": {},\n      \"examples\": [{ \"registry.example.com/base/java\": \"registry.example.com/base/temurin\" }]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"replacements\": { \"registry.example.com/base/java\": \"registry.example.com/base/temurin\" } },\n    { \"severity\": \"error\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/eol_last.schema.json":                           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/eol_last.schema.json\",\n  \"title\": \"tally/eol-last rule config\",\n  \"description\": \"Configuration options for the tally/eol-last rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"mode\": {\n      \"type\": \"string\",\n      \"enum\": [\"always\", \"never\"],\n      \"default\": \"always\",\n      \"description\": \"Whether files must end with a newline (\\\"always\\\") or must not (\\\"never\\\").\",\n      \"examples\": [\"always\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"mode\": \"always\" },\n    { \"severity\": \"style\", \"mode\": \"never\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/index.schema.json":                              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/index.schema.json\",\n  \"$comment\": \"Code generated by _tools/schema-gen. DO NOT EDIT.\",\n  \"title\": \"tally/* rule namespace config\",\n  \"description\": \"Schema for rules.tally configuration; keys are rule names within the tally namespace.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"allowed-base-images\": {\n      \"$ref\": \"./allowed_base_images.schema.json\"\n    },\n    \"base-image-not-eol\": {\n      \"$ref\": \"./base_image_not_eol.schema.json\"\n    },\n    \"base-image-vulnerabilities\": {\n      \"$ref\": \"./base_image_vulnerabilities.schema.json\"\n    },\n    \"consistent-indentation\": {\n      \"$ref\": \"./consistent_indentation.schema.json\"\n    },\n    \"copy-from-unresolved-image\": {\n      \"$ref\": \"./copy_from_unresolved_image.schema.json\"\n    },\n    \"deprecated-base-image\": {\n      \"$ref\": \"./deprecated_base_image.schema.json\"\n    },\n    \"eol-last\": {\n      \"$ref\": \"./eol_last.schema.json\"\n    },\n    \"labels/no-buildx-git-overlap\": {\n      \"$ref\": \"./labels/no_buildx_git_overlap.schema.json\"\n    },\n    \"labels/prefer-grouped\": {\n      \"$ref\": \"./labels/prefer_grouped.schema.json\"\n    },\n    \"labels/prefer-stable-order\": {\n      \"$ref\": \"./labels/prefer_stable_order.schema.json\"\n    },\n    \"max-commands-per-run\": {\n      \"$ref\": \"./max_commands_per_run.schema.json\"\n    },\n    \"max-instructions-per-stage\": {\n      \"$ref\": \"./max_instructions_per_stage.schema.json\"\n    },\n    \"max-lines\": {\n      \"$ref\": \"./max_lines.schema.json\"\n    },\n    \"max-stage-count\": {\n      \"$ref\": \"./max_stage_count.schema.json\"\n    },\n    \"mount-secret-instead-of-copy\": {\n      \"$ref\": \"./mount_secret_instead_of_copy.schema.json\"\n    },\n    \"newline-between-instructions\": {\n      \"$ref\": \"./newline_between_instructions.schema.json\"\n    },\n    \"newline-per-chained-call\": {\n      \"$ref\": \"./newline_per_chained_call.schema.json\"\n    },\n    \"no-multi-spaces\": {\n      \"$ref\": \"./no_multi_spaces.schema.json\"\n    },\n    \"no-multiple-empty-lines\": {\n      \"$ref\": \"./no_multiple_empty_lines.schema.json\"\n    },\n    \"no-pipe-to-shell\": {\n      \"$ref\": \"./no_pipe_to_shell.schema.json\"\n    },\n    \"no-trailing-spaces\": {\n      \"$ref\": \"./no_trailing_spaces.schema.json\"\n    },\n    \"prefer-add-unpack\": {\n      \"$ref\": \"./prefer_add_unpack.schema.json\"\n    },\n    \"prefer-copy-heredoc\": {\n      \"$ref\": \"./prefer_copy_heredoc.schema.json\"\n    },\n    \"prefer-curl-config\": {\n      \"$ref\": \"./prefer_curl_config.schema.json\"\n    },\n    \"prefer-formatted-heredocs\": {\n      \"$ref\": \"./prefer_formatted_heredocs.schema.json\"\n    },\n    \"prefer-multi-stage-build\": {\n      \"$ref\": \"./prefer_multi_stage_build.schema.json\"\n    },\n    \"prefer-run-heredoc\": {\n      \"$ref\": \"./prefer_run_heredoc.schema.json\"\n    },\n    \"prefer-wget-config\": {\n      \"$ref\": \"./prefer_wget_config.schema.json\"\n    },\n    \"require-secret-mounts\": {\n      \"$ref\": \"./require_secret_mounts.schema.json\"\n    },\n    \"runtime/privileged-port-as-nonroot\": {\n      \"$ref\": \"./runtime/privileged_port_as_nonroot.schema.json\"\n    },\n    \"secret-in-context\": {\n      \"$ref\": \"./secret_in_context.schema.json\"\n    },\n    \"stage-name-conventions\": {\n      \"$ref\": \"./stage_name_conventions.schema.json\"\n    }\n  },\n  \"additionalProperties\": {\n    \"$ref\": \"../rule-config.schema.json#/$defs/genericRuleConfig\"\n  },\n  \"examples\": [\n    {\n      \"max-lines\": {\n        \"severity\": \"warning\"\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/labels/no_buildx_git_overlap.schema.json":       []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/labels/no_buildx_git_overlap.schema.json\",\n  \"title\": \"tally/labels/no-buildx-git-overlap rule config\",\n  \"description\": \"Configuration options for the tally/labels/no-buildx-git-overlap rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"buildx-git-labels\": {\n      \"type\": \"string\",\n      \"enum\": [\"off\", \"none\", \"false\", \"False\", \"FALSE\", \"0\", \"f\", \"F\", \"true\", \"True\", \"TRUE\", \"1\", \"t\", \"T\", \"full\"],\n      \"default\": \"full\",\n      \"description\": \"Controls which Buildx git label mode the rule models. \\\"off\\\", \\\"none\\\", and strconv.ParseBool false values disable the check, strconv.ParseBool true values check revision and Dockerfile-path labels, and \\\"full\\\" also checks source labels.\",\n      \"examples\": [\"full\", \"T\", \"off\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"buildx-git-labels\": \"full\" },\n    { \"severity\": \"warning\", \"buildx-git-labels\": \"full\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/labels/prefer_grouped.schema.json":              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/labels/prefer_grouped.schema.json\",\n  \"title\": \"tally/labels/prefer-grouped rule config\",\n  \"description\": \"Configuration options for the tally/labels/prefer-grouped rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"min-labels\": {\n      \"type\": \"integer\",\n      \"minimum\": 2,\n      \"default\": 3,\n      \"description\": \"Minimum total label key/value pairs across an adjacent run of LABEL instructions before the rule reports.\",\n      \"examples\": [3, 5]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-labels\": 3 },\n    { \"severity\": \"info\", \"min-labels\": 5 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/labels/prefer_stable_order.schema.json":         []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/labels/prefer_stable_order.schema.json\",\n  \"title\": \"tally/labels/prefer-stable-order rule config\",\n  \"description\": \"Configuration options for the tally/labels/prefer-stable-order rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"order\": {\n      \"type\": \"string\",\n      \"enum\": [\"oci-logical\", \"lexical\"],\n      \"default\": \"oci-logical\",\n      \"description\": \"Comparator to use when checking key order. \\\"oci-logical\\\" groups OCI and ecosystem keys by purpose; \\\"lexical\\\" sorts purely alphabetically.\"\n    },\n    \"sort-unknown\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"When true, custom reverse-DNS keys are clustered by namespace and sorted lexically within each namespace. When false, custom keys keep their relative source order.\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"order\": \"oci-logical\" },\n    { \"order\": \"lexical\", \"severity\": \"info\" },\n    { \"order\": \"oci-logical\", \"sort-unknown\": true }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/max_commands_per_run.schema.json":               []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/max_commands_per_run.schema.json\",\n  \"title\": \"tally/max-commands-per-run rule config\",\n  \"description\": \"Configuration options for the tally/max-commands-per-run rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 15,\n      \"description\": \"Maximum number of commands allowed in one RUN, counting && chains and heredoc script lines alike (0 = disabled).\",\n      \"examples\": [10]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 10 },\n    { \"severity\": \"error\", \"max\": 20 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/max_instructions_per_stage.schema.json":         []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/max_instructions_per_stage.schema.json\",\n  \"title\": \"tally/max-instructions-per-stage rule config\",\n  \"description\": \"Configuration options for the tally/max-instructions-per-stage rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 40,\n      \"description\": \"Maximum number of instructions allowed in one stage, not counting FROM (0 = disabled).\",\n      \"examples\": [25]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 25 },\n    { \"severity\": \"error\", \"max\": 60 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/max_lines.schema.json":                          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/max_lines.schema.json\",\n  \"title\": \"tally/max-lines rule config\",\n  \"description\": \"Configuration options for the tally/max-lines rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 50,\n      \"description\": \"Maximum number of lines allowed (0 = disabled).\",\n      \"examples\": [100]\n    },\n    \"skip-blank-lines\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Exclude blank lines from the count.\",\n      \"examples\": [true]\n    },\n    \"skip-comments\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Exclude comment lines from the count.\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 100 },\n    { \"severity\": \"warning\", \"max\": 200, \"skip-comments\": false },\n    { \"exclude\": { \"paths\": [\"test/**\"] }, \"max\": 120 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/max_stage_count.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/max_stage_count.schema.json\",\n  \"title\": \"tally/max-stage-count rule config\",\n  \"description\": \"Configuration options for the tally/max-stage-count rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 10,\n      \"description\": \"Maximum number of build stages allowed (0 = disabled).\",\n      \"examples\": [6]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 6 },\n    { \"severity\": \"error\", \"max\": 12 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/mount_secret_instead_of_copy.schema.json":       []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/mount_secret_instead_of_copy.schema.json\",\n  \"title\": \"tally/mount-secret-instead-of-copy rule config\",\n  \"description\": \"Configuration options for the tally/mount-secret-instead-of-copy rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"patterns\": {\n      \"type\": \"array\",\n      \"description\": \"Glob patterns matched case-insensitively against the base name of COPY/ADD sources and destinations.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [\n        \"id_rsa\",\n        \"id_dsa\",\n        \"id_ecdsa\",\n        \"id_ed25519\",\n        \".netrc\",\n        \".npmrc\",\n        \".pypirc\",\n        \".git-credentials\",\n        \".dockercfg\",\n        \"*.pem\",\n        \"*.key\",\n        \"*.p12\",\n        \"*.pfx\",\n        \"serviceaccount.json\",\n        \"service-account*.json\"\n      ],\n      \"examples\": [[\"id_rsa\", \"*.pem\", \"gcp-*.json\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"patterns\": [\"id_rsa\", \"*.pem\", \"gcp-*.json\"] },\n    { \"severity\": \"error\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/newline_between_instructions.schema.json":       []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/newline_between_instructions.schema.json\",\n  \"title\": \"tally/newline-between-instructions rule config\",\n  \"description\": \"Configuration options for the tally/newline-between-instructions rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"mode\": {\n      \"type\": \"string\",\n      \"enum\": [\"grouped\", \"always\", \"never\"],\n      \"default\": \"grouped\",\n      \"description\": \"Controls blank-line behavior between instructions.\",\n      \"examples\": [\"grouped\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"mode\": \"grouped\" },\n    { \"severity\": \"style\", \"mode\": \"always\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/newline_per_chained_call.schema.json":           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/newline_per_chained_call.schema.json\",\n  \"title\": \"tally/newline-per-chained-call rule config\",\n  \"description\": \"Configuration options for the tally/newline-per-chained-call rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"min-commands\": {\n      \"type\": \"integer\",\n      \"minimum\": 2,\n      \"default\": 2,\n      \"description\": \"Minimum number of chained commands required to trigger splitting.\",\n      \"examples\": [3]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-commands\": 2 },\n    { \"severity\": \"style\", \"min-commands\": 4 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/no_multi_spaces.schema.json":                    []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_multi_spaces.schema.json\",\n  \"title\": \"tally/no-multi-spaces rule config\",\n  \"description\": \"Configuration options for the tally/no-multi-spaces rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"style\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/no_multiple_empty_lines.schema.json":            []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_multiple_empty_lines.schema.json\",\n  \"title\": \"tally/no-multiple-empty-lines rule config\",\n  \"description\": \"Configuration options for the tally/no-multiple-empty-lines rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"max\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 1,\n      \"description\": \"Maximum number of consecutive empty lines allowed anywhere in the file.\",\n      \"examples\": [1, 2]\n    },\n    \"max-bof\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 0,\n      \"description\": \"Maximum number of consecutive empty lines allowed at the beginning of the file.\",\n      \"examples\": [0, 1]\n    },\n    \"max-eof\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 0,\n      \"description\": \"Maximum number of consecutive empty lines allowed at the end of the file.\",\n      \"examples\": [0, 1]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"max\": 2 },\n    { \"max\": 1, \"max-bof\": 0, \"max-eof\": 0 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/no_pipe_to_shell.schema.json":                   []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_pipe_to_shell.schema.json\",\n  \"title\": \"tally/no-pipe-to-shell rule config\",\n  \"description\": \"Configuration options for the tally/no-pipe-to-shell rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"allowed-hosts\": {\n      \"type\": \"array\",\n      \"description\": \"Hosts whose downloads may be piped into a shell. Matched case-insensitively; a leading \\\"*.\\\" matches any subdomain.\",\n      \"items\": {\n        \"type\": \"string\",\n        \"minLength\": 1\n      },\n      \"uniqueItems\": true,\n      \"default\": [],\n      \"examples\": [[\"get.example.com\", \"*.internal.example.com\"]]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"allowed-hosts\": [\"sh.rustup.rs\"] },\n    { \"severity\": \"error\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/no_trailing_spaces.schema.json":                 []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/no_trailing_spaces.schema.json\",\n  \"title\": \"tally/no-trailing-spaces rule config\",\n  \"description\": \"Configuration options for the tally/no-trailing-spaces rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"skip-blank-lines\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"Skip lines that consist entirely of whitespace.\",\n      \"examples\": [true]\n    },\n    \"ignore-comments\": {\n      \"type\": \"boolean\",\n      \"default\": false,\n      \"description\": \"Skip any line whose first non-whitespace character is # (Dockerfile comments and # lines in heredocs).\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"ignore-comments\": true },\n    { \"severity\": \"style\", \"skip-blank-lines\": true }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_add_unpack.schema.json":                  []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_add_unpack.schema.json\",\n  \"title\": \"tally/prefer-add-unpack rule config\",\n  \"description\": \"Configuration options for the tally/prefer-add-unpack rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"enabled\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Enable or disable this rule (independent of severity).\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"enabled\": false },\n    { \"severity\": \"info\", \"enabled\": true }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_copy_heredoc.schema.json":                []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_copy_heredoc.schema.json\",\n  \"title\": \"tally/prefer-copy-heredoc rule config\",\n  \"description\": \"Configuration options for the tally/prefer-copy-heredoc rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"check-single-run\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Detect single RUN instructions that create files and suggest COPY heredoc.\",\n      \"examples\": [true]\n    },\n    \"check-consecutive-runs\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Detect sequences of consecutive RUN instructions that create/append to the same file.\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"check-single-run\": true, \"check-consecutive-runs\": true },\n    { \"severity\": \"style\", \"check-single-run\": false }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_curl_config.schema.json":                 []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_curl_config.schema.json\",\n  \"title\": \"tally/prefer-curl-config rule config\",\n  \"description\": \"Configuration options for the tally/prefer-curl-config rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"retry\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 5,\n      \"description\": \"Number of retries for failed transfers.\",\n      \"examples\": [3, 5]\n    },\n    \"connect-timeout\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 15,\n      \"description\": \"Maximum time in seconds for the connection phase.\",\n      \"examples\": [10, 15]\n    },\n    \"max-time\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 300,\n      \"description\": \"Maximum time in seconds for the entire transfer.\",\n      \"examples\": [120, 300]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"retry\": 3, \"connect-timeout\": 10 },\n    { \"severity\": \"warning\", \"retry\": 10, \"max-time\": 600 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_formatted_heredocs.schema.json":          []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_formatted_heredocs.schema.json\",\n  \"title\": \"tally/prefer-formatted-heredocs rule config\",\n  \"description\": \"Configuration options for the tally/prefer-formatted-heredocs rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"style\" }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_multi_stage_build.schema.json":           []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_multi_stage_build.schema.json\",\n  \"title\": \"tally/prefer-multi-stage-build rule config\",\n  \"description\": \"Configuration options for the tally/prefer-multi-stage-build rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"min-score\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"default\": 4,\n      \"description\": \"Minimum heuristic score required to trigger the suggestion.\",\n      \"examples\": [6]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-score\": 6 },\n    { \"severity\": \"info\", \"min-score\": 6 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_run_heredoc.schema.json":                 []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_run_heredoc.schema.json\",\n  \"title\": \"tally/prefer-run-heredoc rule config\",\n  \"description\": \"Configuration options for the tally/prefer-run-heredoc rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"min-commands\": {\n      \"type\": \"integer\",\n      \"minimum\": 2,\n      \"default\": 3,\n      \"description\": \"Minimum number of commands required to trigger heredoc conversion.\",\n      \"examples\": [3]\n    },\n    \"check-consecutive-runs\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Enable detection of multiple consecutive RUN instructions.\",\n      \"examples\": [true]\n    },\n    \"check-chained-commands\": {\n      \"type\": \"boolean\",\n      \"default\": true,\n      \"description\": \"Enable detection of chained commands within a single RUN (via &&).\",\n      \"examples\": [true]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"min-commands\": 3 },\n    { \"severity\": \"style\", \"min-commands\": 4, \"check-chained-commands\": false }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/prefer_wget_config.schema.json":                 []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/prefer_wget_config.schema.json\",\n  \"title\": \"tally/prefer-wget-config rule config\",\n  \"description\": \"Configuration options for the tally/prefer-wget-config rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"timeout\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 15,\n      \"description\": \"Maximum time in seconds before retrying a stalled or failed download.\",\n      \"examples\": [10, 15]\n    },\n    \"tries\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"default\": 5,\n      \"description\": \"Number of retries for failed downloads.\",\n      \"examples\": [3, 5]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"timeout\": 10, \"tries\": 3 },\n    { \"severity\": \"warning\", \"tries\": 7 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/require_secret_mounts.schema.json":              []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/require_secret_mounts.schema.json\",\n  \"title\": \"tally/require-secret-mounts rule config\",\n  \"description\": \"Configuration options for the tally/require-secret-mounts rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"commands\": {\n      \"type\": \"object\",\n      \"description\": \"Map of command names to required secret mount specifications. Each entry specifies a file target, an environment variable, or both.\",\n      \"additionalProperties\": {\n        \"type\": \"object\",\n        \"properties\": {\n          \"id\": {\n            \"type\": \"string\",\n            \"minLength\": 1,\n            \"description\": \"Required secret ID for the --mount flag.\"\n          },\n          \"target\": {\n            \"type\": \"string\",\n            \"minLength\": 1,\n            \"description\": \"Target path where the secret file is mounted.\"\n          },\n          \"env\": {\n            \"type\": \"string\",\n            \"minLength\": 1,\n            \"description\": \"Environment variable name to expose the secret as.\"\n          },\n          \"required\": {\n            \"type\": \"boolean\",\n            \"default\": false,\n            \"description\": \"Fail the build if the secret is not provided. Maps to the 'required' mount parameter.\"\n          }\n        },\n        \"required\": [\"id\"],\n        \"anyOf\": [\n          { \"required\": [\"target\"] },\n          { \"required\": [\"env\"] }\n        ],\n        \"additionalProperties\": false\n      }\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    {\n      \"severity\": \"warning\",\n      \"commands\": {\n        \"pip\": { \"id\": \"pipconf\", \"target\": \"/root/.config/pip/pip.conf\" },\n        \"aws\": { \"id\": \"aws\", \"target\": \"/root/.aws/credentials\" }\n      }\n    },\n    {\n      \"commands\": {\n        \"gh\": { \"id\": \"gh-token\", \"env\": \"GH_TOKEN\" }\n      }\n    },\n    {\n      \"commands\": {\n        \"aws\": { \"id\": \"aws-creds\", \"target\": \"/root/.aws/credentials\", \"env\": \"AWS_SHARED_CREDENTIALS_FILE\" }\n      }\n    }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/runtime/privileged_port_as_nonroot.schema.json": []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/runtime/privileged_port_as_nonroot.schema.json\",\n  \"title\": \"tally/runtime/privileged-port-as-nonroot rule config\",\n  \"description\": \"Configuration options for the tally/runtime/privileged-port-as-nonroot rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"unprivileged-port-start\": {\n      \"type\": \"integer\",\n      \"minimum\": 0,\n      \"maximum\": 65536,\n      \"default\": 1024,\n      \"description\": \"First port a non-root process may bind, matching the net.ipv4.ip_unprivileged_port_start sysctl of the target runtime. Exposed ports below it are reported.\",\n      \"examples\": [1024, 0]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"unprivileged-port-start\": 1024 },\n    { \"severity\": \"error\", \"unprivileged-port-start\": 1024 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/secret_in_context.schema.json":                  []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/secret_in_context.schema.json\",\n  \"title\": \"tally/secret-in-context rule config\",\n  \"description\": \"Configuration options for the tally/secret-in-context rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"concurrency\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"maximum\": 64,\n      \"default\": 4,\n      \"description\": \"Number of build context files scanned in parallel.\"\n    },\n    \"max-file-size\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"default\": 1048576,\n      \"description\": \"Files larger than this many bytes are not scanned.\"\n    },\n    \"max-files\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"default\": 10000,\n      \"description\": \"Maximum number of build context files scanned per Dockerfile.\"\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"severity\": \"error\" },\n    { \"concurrency\": 8, \"max-file-size\": 262144, \"max-files\": 2000 }\n  ]\n}\n"),
+	"https://tally.wharflab.com/rules/tally/stage_name_conventions.schema.json":             []byte("{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"$id\": \"https://tally.wharflab.com/rules/tally/stage_name_conventions.schema.json\",\n  \"title\": \"tally/stage-name-conventions rule config\",\n  \"description\": \"Configuration options for the tally/stage-name-conventions rule.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"severity\": { \"$ref\": \"../rule-config.schema.json#/$defs/severity\" },\n    \"fix\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix\" },\n    \"fix-safety\": { \"$ref\": \"../rule-config.schema.json#/$defs/fix-safety\" },\n    \"exclude\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude\" },\n    \"paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/paths\" },\n    \"exclude-paths\": { \"$ref\": \"../rule-config.schema.json#/$defs/exclude-paths\" },\n    \"case\": {\n      \"type\": \"string\",\n      \"enum\": [\"kebab-case\", \"snake_case\", \"any\"],\n      \"default\": \"kebab-case\",\n      \"description\": \"Word separator convention stage names must follow.\",\n      \"examples\": [\"snake_case\"]\n    },\n    \"pattern\": {\n      \"type\": \"string\",\n      \"minLength\": 1,\n      \"description\": \"Regular expression every stage name must also match (matched against the lowercased name).\",\n      \"examples\": [\"^(build|test)-[a-z0-9-]+$|^runtime$\"]\n    }\n  },\n  \"additionalProperties\": false,\n  \"examples\": [\n    { \"case\": \"kebab-case\" },\n    { \"case\": \"any\", \"pattern\": \"^(build|test)-[a-z0-9-]+$|^runtime$\" }\n  ]\n}\n"),
 }
@@ -23,6 +23,10 @@ type Builder struct {
 	file            string
 	shellDirectives []ShellDirective
 
+	// Previous model and changed lines for incremental rebuilds (see WithPrevious).
+	prev    *Model
+	changed LineRange
+
 	// Accumulated during build
 	globalScope  *VariableScope
 	stagesByName map[string]int
@@ -74,9 +78,14 @@ func (b *Builder) Build() *Model {
 	stageCount := len(stages)
 	stageInfo := make([]*StageInfo, stageCount)
 	graph := newStageGraph(stageCount)
+	reused := b.reusablePrefix(stages, targetStageName)
 
 	for i := range stages {
 		stage := &stages[i]
+		if i < reused {
+			stageInfo[i] = b.reuseStageInfo(stage, i, finalStageIdx, graph)
+			continue
+		}
 		isLast := i == finalStageIdx
 
 		// Create stage info
@@ -116,6 +125,7 @@ func (b *Builder) Build() *Model {
 		b.applyShellDirectives(stage, info)
 
 		applyDefaultShellSemantics(info, stage, effectiveBaseName)
+		info.initialShell = info.ShellSetting
 
 		// Seed the environment used for undefined-var analysis.
 		var stageEnv *fromEnv
@@ -136,6 +146,7 @@ func (b *Builder) Build() *Model {
 		// Process commands in the stage
 		b.processStageCommands(stage, info, graph, stageEnv, fromEval.shlex)
 		info.EffectiveEnv = stageEnv.vars
+		info.context = buildStageContext(i, info.contextSteps, initialStageContext(info, stageInfo), info.IsWindows())
 
 		stageInfo[i] = info
 	}
@@ -441,10 +452,26 @@ func (b *Builder) applyShellDirectives(stage *instructions.Stage, info *StageInf
 	}
 }
 
+// shellDirectiveBetween returns the last shell directive after afterLine and
+// before beforeLine (both 0-based). Directives between two instructions of a
+// stage switch the shell for the instructions that follow, like hadolint does.
+func shellDirectiveBetween(directives []ShellDirective, afterLine, beforeLine int) (ShellDirective, bool) {
+	var (
+		found ShellDirective
+		ok    bool
+	)
+	for _, sd := range directives {
+		if sd.Line > afterLine && sd.Line < beforeLine && (!ok || sd.Line > found.Line) {
+			found, ok = sd, true
+		}
+	}
+	return found, ok
+}
+
 // buildShellLookupsByLine pre-computes the effective shell variant and shell
 // executable name at each instruction's start line within a stage, tracking
-// SHELL instruction transitions.
-func buildShellLookupsByLine(stage *instructions.Stage, info *StageInfo) {
+// SHELL instruction and in-stage shell directive transitions.
+func (b *Builder) buildShellLookupsByLine(stage *instructions.Stage, info *StageInfo) {
 	activeVariant := info.ShellSetting.Variant
 	activeName := DefaultShell[0]
 	if len(info.ShellSetting.Shell) > 0 {
@@ -454,9 +481,15 @@ func buildShellLookupsByLine(stage *instructions.Stage, info *StageInfo) {
 	info.shellVariantByLine = make(map[int]shell.Variant, len(stage.Commands))
 	info.shellNameByLine = make(map[int]string, len(stage.Commands))
 
+	prevLine := stageFromLine(stage)
 	for _, cmd := range stage.Commands {
 		if locs := cmd.Location(); len(locs) > 0 && locs[0].Start.Line > 0 {
 			line := locs[0].Start.Line
+			if sd, ok := shellDirectiveBetween(b.shellDirectives, prevLine, line-1); ok {
+				activeVariant = shell.VariantFromShell(sd.Shell)
+				activeName = sd.Shell
+			}
+			prevLine = line - 1
 			info.shellVariantByLine[line] = activeVariant
 			info.shellNameByLine[line] = activeName
 		}
@@ -504,6 +537,30 @@ func (b *Builder) processBaseImage(stage *instructions.Stage, stageIndex int, gr
 	return ref
 }
 
+// applyInStageShellDirective applies the last shell directive between the
+// previous instruction and the one starting at line (both 0-based) to the
+// stage's shell setting.
+func (b *Builder) applyInStageShellDirective(info *StageInfo, prevLine, line int) {
+	sd, ok := shellDirectiveBetween(b.shellDirectives, prevLine, line)
+	if !ok {
+		return
+	}
+	info.ShellSetting = ShellSetting{
+		Shell:   []string{sd.Shell, "-c"},
+		Variant: shell.VariantFromShell(sd.Shell),
+		Source:  ShellSourceDirective,
+		Line:    sd.Line,
+	}
+}
+
+// stageFromLine returns the 0-based line of the stage's FROM instruction.
+func stageFromLine(stage *instructions.Stage) int {
+	if len(stage.Location) == 0 {
+		return 0
+	}
+	return stage.Location[0].Start.Line - 1
+}
+
 // processShellCommand updates the stage's shell setting from a SHELL instruction
 // and strengthens BaseImageOS when the shell is Windows-specific.
 func (b *Builder) processShellCommand(c *instructions.ShellCommand, info *StageInfo) {
@@ -536,9 +593,15 @@ func (b *Builder) processShellCommand(c *instructions.ShellCommand, info *StageI
 func (b *Builder) processStageCommands(stage *instructions.Stage, info *StageInfo, graph *StageGraph, env *fromEnv, shlex *dfshell.Lex) {
 	declaredArgs := make(map[string]struct{})
 
-	buildShellLookupsByLine(stage, info)
+	b.buildShellLookupsByLine(stage, info)
 
+	prevLine := stageFromLine(stage)
 	for _, cmd := range stage.Commands {
+		if locs := cmd.Location(); len(locs) > 0 && locs[0].Start.Line > 0 {
+			b.applyInStageShellDirective(info, prevLine, locs[0].Start.Line-1)
+			prevLine = locs[0].Start.Line - 1
+		}
+
 		// UndefinedVar analysis must observe the environment at the point of use,
 		// before this command mutates the environment.
 		switch c := cmd.(type) {
@@ -550,6 +613,7 @@ func (b *Builder) processStageCommands(stage *instructions.Stage, info *StageInf
 		default:
 			info.UndefinedVars = append(info.UndefinedVars, undefinedVarsInCommand(cmd, shlex, env, declaredArgs)...)
 		}
+		info.contextSteps = append(info.contextSteps, newContextStep(cmd, shlex, env))
 
 		switch c := cmd.(type) {
 		case *instructions.ArgCommand:
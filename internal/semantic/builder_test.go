@@ -121,6 +121,39 @@ RUN echo "dash shell"
 	}
 }
 
+func TestShellDirectiveInsideStageSwitchesShell(t *testing.T) {
+	t.Parallel()
+	content := `FROM ubuntu:22.04
+RUN echo "default shell"
+# hadolint shell=dash
+RUN echo "dash shell"
+SHELL ["/bin/bash", "-c"]
+RUN echo "bash shell"
+`
+	pr := parseDockerfile(t, content)
+	model := NewBuilder(pr, nil, "Dockerfile").
+		WithShellDirectives([]ShellDirective{{Shell: "dash", Line: 2}}).
+		Build()
+	info := model.StageInfo(0)
+
+	if got := info.ShellNameAtLine(2); got != "/bin/sh" {
+		t.Errorf("line 2: ShellNameAtLine=%q, want %q", got, "/bin/sh")
+	}
+	if got := info.ShellNameAtLine(4); got != "dash" {
+		t.Errorf("line 4: ShellNameAtLine=%q, want %q", got, "dash")
+	}
+	if got := info.ShellVariantAtLine(4); got != shell.VariantPOSIX {
+		t.Errorf("line 4: ShellVariantAtLine=%v, want %v", got, shell.VariantPOSIX)
+	}
+	if got := info.ShellNameAtLine(6); got != "/bin/bash" {
+		t.Errorf("line 6: ShellNameAtLine=%q, want %q", got, "/bin/bash")
+	}
+	// The directive does not change the shell the stage starts with.
+	if info.initialShell.Source != ShellSourceDefault {
+		t.Errorf("initialShell.Source=%v, want %v", info.initialShell.Source, ShellSourceDefault)
+	}
+}
+
 func TestShellVariantAtLineTracksTransitions(t *testing.T) {
 	t.Parallel()
 	content := `FROM alpine:3.18
@@ -1,7 +1,9 @@
 package semantic
 
 // ShellDirective is the subset of directive metadata needed by the semantic
-// builder to seed stage shell state before the first instruction in a stage.
+// builder to seed stage shell state. A directive before a stage's FROM sets
+// the stage's initial shell; one inside a stage switches the shell for the
+// instructions after it.
 type ShellDirective struct {
 	Line  int
 	Shell string
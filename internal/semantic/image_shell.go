@@ -0,0 +1,90 @@
+package semantic
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/wharflab/tally/internal/shell"
+)
+
+// BaseNameLabel is the OCI annotation an image records its own base image
+// under. Build tools that set it let a resolved image be traced back to the
+// distro it was built on (e.g. an application image built FROM alpine).
+const BaseNameLabel = "org.opencontainers.image.base.name"
+
+// ImageShellConfig is the part of a resolved base image configuration that
+// decides which shell RUN instructions use by default.
+type ImageShellConfig struct {
+	// OS is the image's target OS (e.g., "linux", "windows").
+	OS string
+
+	// Shell is the SHELL recorded in the image config. Nil when the image
+	// sets none.
+	Shell []string
+
+	// BaseName is the image the base image itself was built from, as
+	// recorded in BaseNameLabel. Empty when unknown.
+	BaseName string
+}
+
+// RecheckShellSetting returns the shell setting a stage starts with once its
+// base image configuration is resolved, replacing the static guess made from
+// the image name. SHELL instructions of the stage are not applied; the result
+// is the shell in effect before the stage's first command.
+//
+// The boolean is false when the stage does not exist, is not built from an
+// external image, or uses a shell directive ("# hadolint shell=..."), which
+// always wins over the image. The model itself is not modified, so this is
+// safe to call from async result handlers.
+func (m *Model) RecheckShellSetting(stageIdx int, cfg ImageShellConfig) (ShellSetting, bool) {
+	info := m.StageInfo(stageIdx)
+	if info == nil || info.IsScratch() || info.BaseImage == nil || info.BaseImage.IsStageRef {
+		return ShellSetting{}, false
+	}
+	if info.initialShell.Source == ShellSourceDirective {
+		return ShellSetting{}, false
+	}
+	if cfg.OS == "" && info.BaseImageOS == BaseImageOSWindows {
+		cfg.OS = "windows"
+	}
+	return imageShellSetting(info.BaseImage.Effective, cfg), true
+}
+
+// imageShellSetting derives the default shell from the resolved image config.
+// A SHELL baked into the image is used as is. Otherwise the image OS and the
+// names of the image and of the image it was built from select between cmd,
+// PowerShell, a strict POSIX sh, and the bash default.
+func imageShellSetting(baseName string, cfg ImageShellConfig) ShellSetting {
+	setting := ShellSetting{Source: ShellSourceDefault, Line: -1}
+	if len(cfg.Shell) > 0 {
+		setting.Shell = slices.Clone(cfg.Shell)
+		setting.Variant = shell.VariantFromShellCmd(setting.Shell)
+		return setting
+	}
+
+	windows := strings.EqualFold(cfg.OS, "windows")
+	names := []string{baseName}
+	if cfg.BaseName != "" {
+		names = append(names, cfg.BaseName)
+	}
+
+	switch {
+	case slices.ContainsFunc(names, isPowerShellImageName):
+		exe := "pwsh"
+		if windows {
+			exe = windowsPowerShellExe
+		}
+		setting.Shell = []string{exe, "-Command"}
+		setting.Variant = shell.VariantPowerShell
+	case windows:
+		setting.Shell = DefaultWindowsShell()
+		setting.Variant = shell.VariantCmd
+	case slices.ContainsFunc(names, isPOSIXShellDistro):
+		setting.Shell = slices.Clone(DefaultShell)
+		setting.Variant = shell.VariantPOSIX
+	default:
+		setting.Shell = slices.Clone(DefaultShell)
+		setting.Variant = shell.VariantBash
+	}
+	return setting
+}
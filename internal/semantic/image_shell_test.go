@@ -0,0 +1,130 @@
+package semantic
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wharflab/tally/internal/shell"
+)
+
+func TestRecheckShellSetting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		content     string
+		cfg         ImageShellConfig
+		wantOK      bool
+		wantShell   []string
+		wantVariant shell.Variant
+	}{
+		{
+			name:        "linux image without hints keeps bash",
+			content:     "FROM myorg/app:1\n",
+			cfg:         ImageShellConfig{OS: "linux"},
+			wantOK:      true,
+			wantShell:   []string{"/bin/sh", "-c"},
+			wantVariant: shell.VariantBash,
+		},
+		{
+			name:        "base name label reveals alpine",
+			content:     "FROM myorg/app:1\n",
+			cfg:         ImageShellConfig{OS: "linux", BaseName: "docker.io/library/alpine:3.20"},
+			wantOK:      true,
+			wantShell:   []string{"/bin/sh", "-c"},
+			wantVariant: shell.VariantPOSIX,
+		},
+		{
+			name:        "image SHELL is used as is",
+			content:     "FROM myorg/app:1\n",
+			cfg:         ImageShellConfig{OS: "linux", Shell: []string{"/bin/bash", "-o", "pipefail", "-c"}},
+			wantOK:      true,
+			wantShell:   []string{"/bin/bash", "-o", "pipefail", "-c"},
+			wantVariant: shell.VariantBash,
+		},
+		{
+			name:        "windows image defaults to cmd",
+			content:     "FROM myorg/winapp:1\n",
+			cfg:         ImageShellConfig{OS: "windows"},
+			wantOK:      true,
+			wantShell:   []string{"cmd", "/S", "/C"},
+			wantVariant: shell.VariantCmd,
+		},
+		{
+			name:        "windows PowerShell image",
+			content:     "FROM mcr.microsoft.com/powershell:lts-nanoserver-ltsc2022\n",
+			cfg:         ImageShellConfig{OS: "windows"},
+			wantOK:      true,
+			wantShell:   []string{"powershell", "-Command"},
+			wantVariant: shell.VariantPowerShell,
+		},
+		{
+			name:        "linux image resolves a misdetected windows stage",
+			content:     "FROM myorg/app:1\nRUN setx PATH \"%PATH%;C:\\tools\"\n",
+			cfg:         ImageShellConfig{OS: "linux"},
+			wantOK:      true,
+			wantShell:   []string{"/bin/sh", "-c"},
+			wantVariant: shell.VariantBash,
+		},
+		{
+			name:    "scratch",
+			content: "FROM scratch\n",
+			cfg:     ImageShellConfig{OS: "linux"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			model := NewModel(parseDockerfile(t, tt.content), nil, "Dockerfile")
+
+			got, ok := model.RecheckShellSetting(0, tt.cfg)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !slices.Equal(got.Shell, tt.wantShell) {
+				t.Errorf("Shell = %v, want %v", got.Shell, tt.wantShell)
+			}
+			if got.Variant != tt.wantVariant {
+				t.Errorf("Variant = %v, want %v", got.Variant, tt.wantVariant)
+			}
+			if got.Source != ShellSourceDefault || got.Line != -1 {
+				t.Errorf("Source, Line = %v, %d, want default, -1", got.Source, got.Line)
+			}
+		})
+	}
+}
+
+func TestRecheckShellSettingDirectiveWins(t *testing.T) {
+	t.Parallel()
+	content := `# tally shell=bash
+FROM myorg/app:1
+`
+	model := NewBuilder(parseDockerfile(t, content), nil, "Dockerfile").
+		WithShellDirectives([]ShellDirective{{Shell: testShellBash, Line: 0}}).
+		Build()
+
+	if _, ok := model.RecheckShellSetting(0, ImageShellConfig{OS: "windows"}); ok {
+		t.Error("a shell directive must not be overridden by the image config")
+	}
+}
+
+func TestRecheckShellSettingIgnoresStageShellInstruction(t *testing.T) {
+	t.Parallel()
+	content := `FROM myorg/app:1
+RUN echo one | cat
+SHELL ["pwsh", "-Command"]
+`
+	model := NewModel(parseDockerfile(t, content), nil, "Dockerfile")
+
+	got, ok := model.RecheckShellSetting(0, ImageShellConfig{OS: "linux", BaseName: "debian:12"})
+	if !ok || got.Variant != shell.VariantPOSIX {
+		t.Fatalf("RecheckShellSetting = (%v, %v), want the POSIX default", got.Variant, ok)
+	}
+	if model.StageInfo(0).ShellSetting.Variant != shell.VariantPowerShell {
+		t.Error("the model's own shell setting must not change")
+	}
+}
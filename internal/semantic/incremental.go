@@ -0,0 +1,88 @@
+package semantic
+
+import (
+	"maps"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+)
+
+// LineRange is a 1-based, inclusive range of changed lines, in the
+// coordinates of the document the previous model was built from.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// WithPrevious makes Build reuse the analysis of prev for stages that lie
+// entirely before the changed lines, instead of re-analyzing the whole
+// Dockerfile. This is meant for watch and LSP modes, which rebuild the model
+// after every edit.
+//
+// A stage is reused when the FROM of the stage after it starts before
+// changed.Start, since only then can the edit not extend the stage. Stages
+// from the first changed line on are always analyzed again: edits shift the
+// lines that follow them, and every later stage may depend on the edited one.
+// The model is rebuilt from scratch when prev was built with different build
+// args or target stage.
+func (b *Builder) WithPrevious(prev *Model, changed LineRange) *Builder {
+	b.prev = prev
+	b.changed = changed
+	return b
+}
+
+// reusablePrefix returns how many leading stages can be taken from the
+// previous model.
+func (b *Builder) reusablePrefix(stages []instructions.Stage, targetStageName string) int {
+	prev := b.prev
+	if prev == nil || b.changed.Start < 1 || b.changed.End < b.changed.Start {
+		return 0
+	}
+	if prev.targetStageName != targetStageName || !maps.Equal(prev.buildArgs, b.buildArgs) {
+		return 0
+	}
+
+	n := 0
+	for n+1 < len(prev.stages) && n+1 < len(stages) {
+		nextFrom := stageFromLine(&prev.stages[n+1]) + 1
+		if nextFrom >= b.changed.Start || stageFromLine(&stages[n+1])+1 != nextFrom {
+			break
+		}
+		old, cur := &prev.stages[n], &stages[n]
+		if prev.stageInfo[n] == nil || old.Name != cur.Name || old.BaseName != cur.BaseName ||
+			len(old.Commands) != len(cur.Commands) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// reuseStageInfo copies the analysis of an unchanged stage from the previous
+// model. Stage references are resolved again, since they point into the new
+// parse result and forward references may target stages that changed.
+func (b *Builder) reuseStageInfo(stage *instructions.Stage, index, finalStageIdx int, graph *StageGraph) *StageInfo {
+	prevInfo := b.prev.stageInfo[index]
+
+	b.processStageNaming(stage, index)
+
+	info := *prevInfo
+	info.Stage = stage
+	info.IsLastStage = index == finalStageIdx
+	info.BaseImage = b.processBaseImage(stage, index, graph)
+	if prevInfo.BaseImage != nil {
+		info.BaseImage.Effective = prevInfo.BaseImage.Effective
+	}
+
+	info.CopyFromRefs = nil
+	for _, cmd := range stage.Commands {
+		switch c := cmd.(type) {
+		case *instructions.RunCommand:
+			b.processMountDependencies(c, index, graph)
+		case *instructions.CopyCommand:
+			if c.From != "" {
+				info.CopyFromRefs = append(info.CopyFromRefs, b.processCopyFrom(c, index, graph))
+			}
+		}
+	}
+	return &info
+}
@@ -0,0 +1,176 @@
+package semantic
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const incrementalBase = `ARG GO_VERSION=1.25
+FROM golang:${GO_VERSION} AS build
+ENV CGO_ENABLED=0
+WORKDIR /src
+COPY --from=assets /assets ./assets
+RUN apt-get update && apt-get install -y git make
+RUN --mount=from=tools,target=/tools make build
+
+FROM alpine:3.21 AS tools
+RUN apk add --no-cache protobuf
+
+FROM node:22 AS assets
+USER node
+RUN npm ci && npm run build
+
+FROM build AS test
+RUN go test ./...
+
+FROM alpine:3.21
+COPY --from=build /out/app /app
+ENTRYPOINT ["/app"]
+`
+
+func TestBuilderWithPreviousMatchesFullBuild(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		edited  string
+		changed LineRange
+		// reused is the number of stages expected to come from the previous model.
+		reused int
+	}{
+		{
+			name:    "edit in final stage",
+			edited:  strings.Replace(incrementalBase, `ENTRYPOINT ["/app"]`, `USER nobody`+"\n"+`ENTRYPOINT ["/app"]`, 1),
+			changed: LineRange{Start: 21, End: 21},
+			reused:  4,
+		},
+		{
+			name:    "rename forward-referenced stage",
+			edited:  strings.Replace(incrementalBase, "AS assets", "AS web", 1),
+			changed: LineRange{Start: 12, End: 12},
+			reused:  1,
+		},
+		{
+			name:    "append to end of earlier stage",
+			edited:  strings.Replace(incrementalBase, "protobuf\n", "protobuf\nRUN apk add --no-cache curl\n", 1),
+			changed: LineRange{Start: 11, End: 11},
+			reused:  1,
+		},
+		{
+			name:    "edit in first stage",
+			edited:  strings.Replace(incrementalBase, "CGO_ENABLED=0", "CGO_ENABLED=1", 1),
+			changed: LineRange{Start: 3, End: 3},
+			reused:  0,
+		},
+		{
+			name:    "new final stage changes the target",
+			edited:  incrementalBase + "\nFROM scratch AS export\nCOPY --from=build /out /\n",
+			changed: LineRange{Start: 22, End: 24},
+			reused:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			prev := NewBuilder(parseDockerfile(t, incrementalBase), nil, "Dockerfile").Build()
+
+			pr := parseDockerfile(t, tt.edited)
+			full := NewBuilder(pr, nil, "Dockerfile").Build()
+			incr := NewBuilder(pr, nil, "Dockerfile").WithPrevious(prev, tt.changed).Build()
+
+			if !reflect.DeepEqual(full, incr) {
+				for i := range full.stageInfo {
+					if !reflect.DeepEqual(full.stageInfo[i], incr.stageInfo[i]) {
+						t.Errorf("stage %d differs:\nfull: %+v\nincr: %+v", i, full.stageInfo[i], incr.stageInfo[i])
+					}
+				}
+				if !reflect.DeepEqual(full.graph, incr.graph) {
+					t.Errorf("graph differs:\nfull: %+v\nincr: %+v", full.graph, incr.graph)
+				}
+				t.Fatal("incremental model differs from full rebuild")
+			}
+
+			reused := 0
+			for i := range incr.stageInfo {
+				if i < len(prev.stageInfo) && sameStageAnalysis(prev.stageInfo[i], incr.stageInfo[i]) {
+					reused++
+				}
+			}
+			if reused != tt.reused {
+				t.Errorf("reused %d stages, want %d", reused, tt.reused)
+			}
+		})
+	}
+}
+
+func TestBuilderWithPreviousRebuildsOnNewBuildArgs(t *testing.T) {
+	t.Parallel()
+	pr := parseDockerfile(t, incrementalBase)
+	prev := NewBuilder(pr, nil, "Dockerfile").Build()
+
+	buildArgs := map[string]string{"GO_VERSION": "1.24"}
+	incr := NewBuilder(pr, buildArgs, "Dockerfile").WithPrevious(prev, LineRange{Start: 20, End: 20}).Build()
+	if sameStageAnalysis(prev.stageInfo[0], incr.stageInfo[0]) {
+		t.Error("stage 0 was reused although the build args changed")
+	}
+	if got := incr.StageInfo(0).BaseImage.Effective; got != "golang:1.24" {
+		t.Errorf("BaseImage.Effective = %q, want %q", got, "golang:1.24")
+	}
+}
+
+// sameStageAnalysis reports whether two stage infos share their analysis
+// results rather than just having equal ones.
+func sameStageAnalysis(a, b *StageInfo) bool {
+	return reflect.ValueOf(a.shellVariantByLine).Pointer() == reflect.ValueOf(b.shellVariantByLine).Pointer()
+}
+
+// largeMultiStageDockerfile returns a Dockerfile with the given number of
+// stages, each with a chain of package installs and cross-stage copies.
+func largeMultiStageDockerfile(stages int) string {
+	var sb strings.Builder
+	sb.WriteString("ARG BASE=debian:bookworm\n")
+	for i := range stages {
+		fmt.Fprintf(&sb, "FROM ${BASE} AS stage-%d\n", i)
+		sb.WriteString("ARG VERSION=1.0\nENV PATH=/opt/bin:$PATH\nWORKDIR /src\n")
+		for j := range 5 {
+			fmt.Fprintf(&sb,
+				"RUN apt-get update && apt-get install -y --no-install-recommends pkg-%d-%d=${VERSION} curl ca-certificates \\\n"+
+					"    && curl -fsSL https://example.com/tool-%d.tar.gz | tar -xz -C /opt \\\n"+
+					"    && rm -rf /var/lib/apt/lists/*\n", i, j, j)
+		}
+		if i > 0 {
+			fmt.Fprintf(&sb, "COPY --from=stage-%d /opt /opt\n", i-1)
+		}
+	}
+	return sb.String()
+}
+
+func BenchmarkBuilderIncremental(b *testing.B) {
+	content := largeMultiStageDockerfile(50)
+	pr := parseDockerfile(b, content)
+	prev := NewBuilder(pr, nil, "Dockerfile").Build()
+
+	// An edit on the last line only invalidates the final stage.
+	lines := strings.Count(content, "\n")
+	changed := LineRange{Start: lines, End: lines}
+
+	b.Run("Full", func(b *testing.B) {
+		for b.Loop() {
+			NewBuilder(pr, nil, "Dockerfile").Build()
+		}
+	})
+	b.Run("Incremental", func(b *testing.B) {
+		for b.Loop() {
+			NewBuilder(pr, nil, "Dockerfile").WithPrevious(prev, changed).Build()
+		}
+	})
+	b.Run("IncrementalFirstStage", func(b *testing.B) {
+		firstStage := LineRange{Start: 3, End: 3}
+		for b.Loop() {
+			NewBuilder(pr, nil, "Dockerfile").WithPrevious(prev, firstStage).Build()
+		}
+	})
+}
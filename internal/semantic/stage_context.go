@@ -0,0 +1,276 @@
+package semantic
+
+import (
+	"path"
+	"strings"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	dfshell "github.com/moby/buildkit/frontend/dockerfile/shell"
+)
+
+const (
+	// DefaultWorkdir is the working directory of an image that sets no WORKDIR.
+	DefaultWorkdir = "/"
+
+	// DefaultUser is the user of an image that sets no USER.
+	DefaultUser = "root"
+)
+
+// contextStepKind identifies the instructions that change the stage context.
+type contextStepKind uint8
+
+const (
+	contextStepNone contextStepKind = iota
+	contextStepWorkdir
+	contextStepUser
+)
+
+// contextStep is the expanded WORKDIR or USER argument of one command.
+// Steps are recorded once while building the model and replayed from a
+// different starting point when the base image is resolved.
+type contextStep struct {
+	kind contextStepKind
+	// value is the argument after variable expansion.
+	value string
+	// known is false when the argument references variables the model
+	// cannot expand.
+	known bool
+}
+
+// contextValue is a working directory or user together with whether the
+// model could determine it statically.
+type contextValue struct {
+	value string
+	known bool
+}
+
+// contextState is the working directory and user in effect at one point of a stage.
+type contextState struct {
+	workdir contextValue
+	user    contextValue
+}
+
+// StageContext holds the effective working directory and user for every
+// instruction of a stage.
+//
+// Values are tracked through WORKDIR and USER instructions, including those
+// inherited from a parent stage (FROM <stage>). For external base images the
+// image configuration is unknown until it is resolved from the registry, so
+// the lookups return Docker's defaults ("/" and "root") but report them as
+// unknown. See Model.RecheckStageContext.
+type StageContext struct {
+	// StageIdx is the 0-based stage index.
+	StageIdx int
+
+	initial contextState
+	byCmd   []contextState
+}
+
+// Workdir returns the working directory in effect for the command at cmdIdx
+// (an index into the stage's Commands). A WORKDIR command reports the
+// directory it changes to. A negative cmdIdx returns the directory the stage
+// starts in, and an index past the last command returns the directory at the
+// end of the stage.
+//
+// The boolean is false when the directory depends on an unresolved base image,
+// a variable that cannot be expanded, or Windows path semantics. The returned
+// path is then a best guess.
+func (c *StageContext) Workdir(cmdIdx int) (string, bool) {
+	st := c.at(cmdIdx)
+	return st.workdir.value, st.workdir.known
+}
+
+// User returns the user in effect for the command at cmdIdx, as written in
+// the USER instruction (name, uid, and optional group). Indexing and the
+// boolean follow the same rules as Workdir.
+func (c *StageContext) User(cmdIdx int) (string, bool) {
+	st := c.at(cmdIdx)
+	return st.user.value, st.user.known
+}
+
+func (c *StageContext) at(cmdIdx int) contextState {
+	if c == nil {
+		return contextState{
+			workdir: contextValue{value: DefaultWorkdir},
+			user:    contextValue{value: DefaultUser},
+		}
+	}
+	switch {
+	case cmdIdx < 0 || len(c.byCmd) == 0:
+		return c.initial
+	case cmdIdx >= len(c.byCmd):
+		return c.byCmd[len(c.byCmd)-1]
+	default:
+		return c.byCmd[cmdIdx]
+	}
+}
+
+// final returns the context at the end of the stage.
+func (c *StageContext) final() contextState {
+	return c.at(len(c.byCmd))
+}
+
+// newContextStep expands the argument of a WORKDIR or USER command using the
+// environment in effect at that command. Other commands yield a no-op step.
+func newContextStep(cmd instructions.Command, shlex *dfshell.Lex, env *fromEnv) contextStep {
+	var (
+		kind contextStepKind
+		word string
+	)
+	switch c := cmd.(type) {
+	case *instructions.WorkdirCommand:
+		kind, word = contextStepWorkdir, c.Path
+	case *instructions.UserCommand:
+		kind, word = contextStepUser, c.User
+	default:
+		return contextStep{}
+	}
+
+	if !strings.Contains(word, "$") || shlex == nil || env == nil {
+		return contextStep{kind: kind, value: word, known: true}
+	}
+	expanded, unmatched, err := shlex.ProcessWord(word, env)
+	if err != nil {
+		return contextStep{kind: kind, value: word}
+	}
+	return contextStep{kind: kind, value: expanded, known: len(unmatched) == 0}
+}
+
+// buildStageContext replays the recorded steps of a stage from the given
+// starting context. Windows stages use drive-letter paths that are not
+// modeled, so their working directory is always unknown.
+func buildStageContext(stageIdx int, steps []contextStep, initial contextState, windows bool) *StageContext {
+	if windows {
+		initial.workdir.known = false
+	}
+	ctx := &StageContext{
+		StageIdx: stageIdx,
+		initial:  initial,
+		byCmd:    make([]contextState, len(steps)),
+	}
+
+	st := initial
+	for i, step := range steps {
+		switch step.kind {
+		case contextStepWorkdir:
+			st.workdir = resolveWorkdirStep(st.workdir, step, windows)
+		case contextStepUser:
+			st.user = contextValue{value: step.value, known: step.known}
+		case contextStepNone:
+		}
+		ctx.byCmd[i] = st
+	}
+	return ctx
+}
+
+// resolveWorkdirStep applies a WORKDIR step to the current directory.
+// An absolute path is known on its own; a relative one only when the
+// directory it is relative to is known.
+func resolveWorkdirStep(cur contextValue, step contextStep, windows bool) contextValue {
+	if windows {
+		return contextValue{value: step.value}
+	}
+	if step.value == "" {
+		return contextValue{value: cur.value, known: cur.known && step.known}
+	}
+	if path.IsAbs(step.value) {
+		return contextValue{value: path.Clean(step.value), known: step.known}
+	}
+	return contextValue{
+		value: path.Clean(path.Join(cur.value, step.value)),
+		known: step.known && cur.known,
+	}
+}
+
+// initialStageContext returns the context a stage starts with when its base
+// image configuration has not been resolved: scratch starts at Docker's
+// defaults, a stage reference inherits its parent's final context, and an
+// external image starts at the defaults marked unknown.
+func initialStageContext(info *StageInfo, stageInfo []*StageInfo) contextState {
+	defaults := contextState{
+		workdir: contextValue{value: DefaultWorkdir},
+		user:    contextValue{value: DefaultUser},
+	}
+	switch {
+	case info.IsScratch():
+		defaults.workdir.known = true
+		defaults.user.known = true
+		return defaults
+	case info.BaseImage != nil && info.BaseImage.IsStageRef:
+		idx := info.BaseImage.StageIndex
+		if idx >= 0 && idx < len(stageInfo) && stageInfo[idx] != nil && stageInfo[idx].context != nil {
+			return stageInfo[idx].context.final()
+		}
+		return defaults
+	default:
+		return defaults
+	}
+}
+
+// imageContext returns the context a stage starts with when its base image
+// configuration is known. Empty values mean the image uses Docker's defaults.
+func imageContext(workdir, user string) contextState {
+	if workdir == "" {
+		workdir = DefaultWorkdir
+	}
+	if user == "" {
+		user = DefaultUser
+	}
+	return contextState{
+		workdir: contextValue{value: path.Clean(workdir), known: path.IsAbs(workdir)},
+		user:    contextValue{value: user, known: true},
+	}
+}
+
+// EffectiveWorkdir returns the working directory in effect for the command at
+// cmdIdx in the given stage. See StageContext.Workdir for the index rules and
+// the meaning of the boolean. Returns ("", false) for an unknown stage.
+func (m *Model) EffectiveWorkdir(stageIdx, cmdIdx int) (string, bool) {
+	info := m.StageInfo(stageIdx)
+	if info == nil || info.context == nil {
+		return "", false
+	}
+	return info.context.Workdir(cmdIdx)
+}
+
+// EffectiveUser returns the user in effect for the command at cmdIdx in the
+// given stage. See StageContext.User for the index rules and the meaning of
+// the boolean. Returns ("", false) for an unknown stage.
+func (m *Model) EffectiveUser(stageIdx, cmdIdx int) (string, bool) {
+	info := m.StageInfo(stageIdx)
+	if info == nil || info.context == nil {
+		return "", false
+	}
+	return info.context.User(cmdIdx)
+}
+
+// RecheckStageContext recomputes the stage context for the specified stage
+// and all stages that transitively inherit from it (via FROM <stage>),
+// starting from the resolved base image's working directory and user instead
+// of the static defaults. Empty values mean the image sets none.
+//
+// The model itself is not modified, so this is safe to call from async
+// result handlers.
+func (m *Model) RecheckStageContext(stageIdx int, workdir, user string) []*StageContext {
+	if stageIdx < 0 || stageIdx >= len(m.stageInfo) || m.stageInfo[stageIdx] == nil {
+		return nil
+	}
+
+	var results []*StageContext
+	m.recheckContextChain(stageIdx, imageContext(workdir, user), &results)
+	return results
+}
+
+// recheckContextChain rebuilds a single stage context and recursively
+// processes stages that inherit from it.
+func (m *Model) recheckContextChain(stageIdx int, initial contextState, results *[]*StageContext) {
+	info := m.stageInfo[stageIdx]
+	ctx := buildStageContext(stageIdx, info.contextSteps, initial, info.IsWindows())
+	*results = append(*results, ctx)
+
+	for i, child := range m.stageInfo {
+		if child != nil && child.BaseImage != nil && child.BaseImage.IsStageRef && child.BaseImage.StageIndex == stageIdx {
+			m.recheckContextChain(i, ctx.final(), results)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package semantic
+
+import "testing"
+
+type contextWant struct {
+	cmdIdx  int
+	workdir string
+	wdKnown bool
+	user    string
+	uKnown  bool
+}
+
+func assertStageContext(t *testing.T, ctx *StageContext, wants []contextWant) {
+	t.Helper()
+	for _, w := range wants {
+		wd, wdKnown := ctx.Workdir(w.cmdIdx)
+		if wd != w.workdir || wdKnown != w.wdKnown {
+			t.Errorf("Workdir(%d) = (%q, %v), want (%q, %v)", w.cmdIdx, wd, wdKnown, w.workdir, w.wdKnown)
+		}
+		user, uKnown := ctx.User(w.cmdIdx)
+		if user != w.user || uKnown != w.uKnown {
+			t.Errorf("User(%d) = (%q, %v), want (%q, %v)", w.cmdIdx, user, uKnown, w.user, w.uKnown)
+		}
+	}
+}
+
+func TestEffectiveWorkdirAndUser(t *testing.T) {
+	t.Parallel()
+	content := `FROM scratch AS base
+WORKDIR /app
+USER app
+WORKDIR src
+COPY . .
+
+FROM base
+WORKDIR ../data
+USER root:root
+
+FROM alpine:3.20
+ARG DIR=/opt
+WORKDIR $DIR/tool
+WORKDIR $UNSET
+WORKDIR /srv
+`
+	model := NewModel(parseDockerfile(t, content), nil, "Dockerfile")
+
+	tests := []struct {
+		stageIdx int
+		wants    []contextWant
+	}{
+		{
+			stageIdx: 0,
+			wants: []contextWant{
+				{cmdIdx: -1, workdir: "/", wdKnown: true, user: "root", uKnown: true},
+				{cmdIdx: 0, workdir: "/app", wdKnown: true, user: "root", uKnown: true},
+				{cmdIdx: 1, workdir: "/app", wdKnown: true, user: "app", uKnown: true},
+				{cmdIdx: 3, workdir: "/app/src", wdKnown: true, user: "app", uKnown: true},
+				{cmdIdx: 99, workdir: "/app/src", wdKnown: true, user: "app", uKnown: true},
+			},
+		},
+		{
+			stageIdx: 1,
+			wants: []contextWant{
+				{cmdIdx: -1, workdir: "/app/src", wdKnown: true, user: "app", uKnown: true},
+				{cmdIdx: 0, workdir: "/app/data", wdKnown: true, user: "app", uKnown: true},
+				{cmdIdx: 1, workdir: "/app/data", wdKnown: true, user: "root:root", uKnown: true},
+			},
+		},
+		{
+			stageIdx: 2,
+			wants: []contextWant{
+				{cmdIdx: -1, workdir: "/", wdKnown: false, user: "root", uKnown: false},
+				{cmdIdx: 1, workdir: "/opt/tool", wdKnown: true, user: "root", uKnown: false},
+				{cmdIdx: 2, workdir: "/opt/tool", wdKnown: false, user: "root", uKnown: false},
+				{cmdIdx: 3, workdir: "/srv", wdKnown: true, user: "root", uKnown: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		info := model.StageInfo(tt.stageIdx)
+		assertStageContext(t, info.context, tt.wants)
+		for _, w := range tt.wants {
+			wd, known := model.EffectiveWorkdir(tt.stageIdx, w.cmdIdx)
+			if wd != w.workdir || known != w.wdKnown {
+				t.Errorf("EffectiveWorkdir(%d, %d) = (%q, %v), want (%q, %v)",
+					tt.stageIdx, w.cmdIdx, wd, known, w.workdir, w.wdKnown)
+			}
+			user, known := info.UserAt(w.cmdIdx)
+			if user != w.user || known != w.uKnown {
+				t.Errorf("UserAt(%d) in stage %d = (%q, %v), want (%q, %v)",
+					w.cmdIdx, tt.stageIdx, user, known, w.user, w.uKnown)
+			}
+		}
+	}
+
+	if wd, known := model.EffectiveWorkdir(5, 0); wd != "" || known {
+		t.Errorf("EffectiveWorkdir(unknown stage) = (%q, %v), want (\"\", false)", wd, known)
+	}
+}
+
+func TestEffectiveWorkdirRelativeToExternalImage(t *testing.T) {
+	t.Parallel()
+	content := `FROM alpine:3.20
+WORKDIR app
+`
+	model := NewModel(parseDockerfile(t, content), nil, "Dockerfile")
+
+	wd, known := model.EffectiveWorkdir(0, 0)
+	if wd != "/app" || known {
+		t.Errorf("EffectiveWorkdir = (%q, %v), want (\"/app\", false)", wd, known)
+	}
+}
+
+func TestEffectiveWorkdirWindows(t *testing.T) {
+	t.Parallel()
+	content := `FROM mcr.microsoft.com/windows/servercore:ltsc2022
+WORKDIR C:\app
+USER ContainerUser
+`
+	model := NewModel(parseDockerfile(t, content), nil, "Dockerfile")
+
+	if _, known := model.EffectiveWorkdir(0, 0); known {
+		t.Error("Windows WORKDIR should be unknown")
+	}
+	if user, known := model.EffectiveUser(0, 1); user != "ContainerUser" || !known {
+		t.Errorf("EffectiveUser = (%q, %v), want (\"ContainerUser\", true)", user, known)
+	}
+}
+
+func TestRecheckStageContext(t *testing.T) {
+	t.Parallel()
+	content := `FROM node:22 AS base
+WORKDIR app
+
+FROM base
+USER 1000
+WORKDIR dist
+`
+	model := NewModel(parseDockerfile(t, content), nil, "Dockerfile")
+
+	results := model.RecheckStageContext(0, "/home/node", "node")
+	if len(results) != 2 {
+		t.Fatalf("got %d stage contexts, want 2", len(results))
+	}
+	if results[0].StageIdx != 0 || results[1].StageIdx != 1 {
+		t.Fatalf("stage indexes = %d, %d, want 0, 1", results[0].StageIdx, results[1].StageIdx)
+	}
+	assertStageContext(t, results[0], []contextWant{
+		{cmdIdx: -1, workdir: "/home/node", wdKnown: true, user: "node", uKnown: true},
+		{cmdIdx: 0, workdir: "/home/node/app", wdKnown: true, user: "node", uKnown: true},
+	})
+	assertStageContext(t, results[1], []contextWant{
+		{cmdIdx: 0, workdir: "/home/node/app", wdKnown: true, user: "1000", uKnown: true},
+		{cmdIdx: 1, workdir: "/home/node/app/dist", wdKnown: true, user: "1000", uKnown: true},
+	})
+
+	// The model keeps its static view.
+	if wd, known := model.EffectiveWorkdir(1, 1); wd != "/app/dist" || known {
+		t.Errorf("EffectiveWorkdir after recheck = (%q, %v), want (\"/app/dist\", false)", wd, known)
+	}
+
+	// An image without WORKDIR/USER resolves to Docker's defaults.
+	results = model.RecheckStageContext(0, "", "")
+	assertStageContext(t, results[0], []contextWant{
+		{cmdIdx: -1, workdir: "/", wdKnown: true, user: "root", uKnown: true},
+	})
+
+	if got := model.RecheckStageContext(7, "/", ""); got != nil {
+		t.Errorf("RecheckStageContext(unknown stage) = %v, want nil", got)
+	}
+}
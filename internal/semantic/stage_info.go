@@ -223,6 +223,12 @@ type StageInfo struct {
 	// Query via ShellNameAtLine.
 	shellNameByLine map[int]string
 
+	// initialShell is the shell setting in effect before the stage's first
+	// command, i.e. ShellSetting before any SHELL instruction of the stage.
+	// Kept so the default can be refined from the resolved base image (see
+	// Model.RecheckShellSetting).
+	initialShell ShellSetting
+
 	// HeredocShellOverrides contains per-instruction shell overrides detected
 	// from heredoc shebang lines. Rules can use this to determine the effective
 	// shell for a specific RUN instruction instead of the stage-level shell.
@@ -232,13 +238,29 @@ type StageInfo struct {
 	// Tracked from RUN commands that use apt-get, apk, yum, dnf, etc.
 	InstalledPackages []PackageInstall
 
+	// contextSteps holds the expanded WORKDIR/USER argument of each command,
+	// indexed like Stage.Commands. Kept so the context can be replayed from a
+	// resolved base image (see Model.RecheckStageContext).
+	contextSteps []contextStep
+
+	// context tracks the effective working directory and user per command.
+	// Query via WorkdirAt and UserAt, or Model.EffectiveWorkdir/EffectiveUser.
+	context *StageContext
+
 	// IsLastStage is true if this is the final stage in the Dockerfile.
 	IsLastStage bool
 }
 
+// InitialShellSetting returns the shell setting in effect before the stage's
+// first command. ShellSetting reflects SHELL instructions and shell
+// directives inside the stage; this does not.
+func (s *StageInfo) InitialShellSetting() ShellSetting {
+	return s.initialShell
+}
+
 // ShellVariantAtLine returns the effective shell variant at the given
 // 1-based Dockerfile line within this stage. It accounts for mid-stage
-// SHELL instruction transitions. Returns the stage default if the line
+// SHELL instruction and shell directive transitions. Returns the stage default if the line
 // is not found (e.g., non-command lines like comments or blank lines).
 func (s *StageInfo) ShellVariantAtLine(line int) shell.Variant {
 	if v, ok := s.shellVariantByLine[line]; ok {
@@ -261,6 +283,18 @@ func (s *StageInfo) ShellNameAtLine(line int) string {
 	return DefaultShell[0]
 }
 
+// WorkdirAt returns the working directory in effect for the command at
+// cmdIdx in Stage.Commands. See StageContext.Workdir.
+func (s *StageInfo) WorkdirAt(cmdIdx int) (string, bool) {
+	return s.context.Workdir(cmdIdx)
+}
+
+// UserAt returns the user in effect for the command at cmdIdx in
+// Stage.Commands. See StageContext.User.
+func (s *StageInfo) UserAt(cmdIdx int) (string, bool) {
+	return s.context.User(cmdIdx)
+}
+
 // IsWindows returns true if the stage was statically classified as Windows.
 func (s *StageInfo) IsWindows() bool {
 	return s.BaseImageOS == BaseImageOSWindows
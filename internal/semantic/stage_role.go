@@ -0,0 +1,65 @@
+package semantic
+
+// StageRole classifies a stage by whether its filesystem ends up in the
+// image the build exports.
+type StageRole int
+
+const (
+	// StageRoleUnknown is the role of locations outside any stage, such as
+	// global ARGs before the first FROM and file-level findings.
+	StageRoleUnknown StageRole = iota
+
+	// StageRoleFinal marks stages whose layers are exported: the final
+	// stage (or the --target stage) and the stages it is built FROM.
+	StageRoleFinal
+
+	// StageRoleBuilder marks every other stage. Its filesystem is thrown
+	// away after the build; only files copied out with COPY --from reach
+	// the image.
+	StageRoleBuilder
+)
+
+// String returns the name of the role as used in configuration.
+func (r StageRole) String() string {
+	switch r {
+	case StageRoleFinal:
+		return "final"
+	case StageRoleBuilder:
+		return "builder"
+	default:
+		return "unknown"
+	}
+}
+
+// StageRole returns the role of the stage at index.
+func (m *Model) StageRole(index int) StageRole {
+	if m == nil || index < 0 || index >= len(m.stages) {
+		return StageRoleUnknown
+	}
+	for idx := m.finalStageIndex; idx >= 0; {
+		if idx == index {
+			return StageRoleFinal
+		}
+		info := m.StageInfo(idx)
+		if info == nil || info.BaseImage == nil || !info.BaseImage.IsStageRef || info.BaseImage.StageIndex >= idx {
+			break
+		}
+		idx = info.BaseImage.StageIndex
+	}
+	return StageRoleBuilder
+}
+
+// StageIndexAt returns the index of the stage containing the 1-based line,
+// or -1 for lines before the first FROM.
+func (m *Model) StageIndexAt(line int) int {
+	if m == nil {
+		return -1
+	}
+	stageIdx := -1
+	for i := range m.stages {
+		if len(m.stages[i].Location) > 0 && m.stages[i].Location[0].Start.Line <= line {
+			stageIdx = i
+		}
+	}
+	return stageIdx
+}
@@ -0,0 +1,72 @@
+package semantic
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStageRole(t *testing.T) {
+	t.Parallel()
+	content := `ARG GO_VERSION=1.24
+FROM golang:${GO_VERSION} AS build
+RUN go build -o /app .
+
+FROM alpine:3.20 AS base
+RUN apk add --no-cache ca-certificates
+
+FROM base AS runtime
+COPY --from=build /app /app
+
+FROM runtime AS debug
+RUN apk add --no-cache strace
+`
+	tests := []struct {
+		name   string
+		target string
+		want   []StageRole
+	}{
+		{
+			name: "last stage",
+			want: []StageRole{StageRoleBuilder, StageRoleFinal, StageRoleFinal, StageRoleFinal},
+		},
+		{
+			name:   "target stage",
+			target: "runtime",
+			want:   []StageRole{StageRoleBuilder, StageRoleFinal, StageRoleFinal, StageRoleBuilder},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			model := NewBuilder(parseDockerfile(t, content), nil, "Dockerfile").WithTargetStage(tt.target).Build()
+			var got []StageRole
+			for i := range model.StageCount() {
+				got = append(got, model.StageRole(i))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("StageRole = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStageIndexAt(t *testing.T) {
+	t.Parallel()
+	content := `ARG BASE=alpine:3.20
+FROM ${BASE} AS build
+RUN make
+
+FROM scratch
+COPY --from=build /out /
+`
+	model := NewModel(parseDockerfile(t, content), nil, "Dockerfile")
+	tests := map[int]int{1: -1, 2: 0, 3: 0, 4: 0, 5: 1, 6: 1}
+	for line, want := range tests {
+		if got := model.StageIndexAt(line); got != want {
+			t.Errorf("StageIndexAt(%d) = %d, want %d", line, got, want)
+		}
+	}
+	if got := model.StageRole(-1); got != StageRoleUnknown {
+		t.Errorf("StageRole(-1) = %v, want %v", got, StageRoleUnknown)
+	}
+}
@@ -0,0 +1,58 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// largeRunScript builds a RUN script in the shape of real-world install
+// steps: a long && chain with quoted arguments, wrappers, nested sh -c
+// code, pipes and a file written with echo.
+func largeRunScript(steps int) string {
+	parts := []string{"set -eux"}
+	for i := range steps {
+		parts = append(parts,
+			fmt.Sprintf(`apt-get install -y --no-install-recommends "pkg-%d=1.%d.*"`, i, i),
+			fmt.Sprintf(`curl -fsSL "https://example.com/v$VERSION/tool-%d.tar.gz" | tar -xz -C /opt`, i),
+			fmt.Sprintf(`env DEBIAN_FRONTEND=noninteractive sh -c "echo \"step %d\" && ln -s /opt/tool-%d /usr/local/bin/tool-%d"`, i, i, i),
+		)
+	}
+	parts = append(parts, `echo 'export PATH="/opt/bin:$PATH"' > /etc/profile.d/path.sh`, "rm -rf /var/lib/apt/lists/*")
+	return strings.Join(parts, " \\\n    && ")
+}
+
+func BenchmarkLargeRunScript(b *testing.B) {
+	script := largeRunScript(100)
+
+	b.Run("CommandNames", func(b *testing.B) {
+		for b.Loop() {
+			CommandNamesWithVariant(script, VariantBash)
+		}
+	})
+	b.Run("FindCommands", func(b *testing.B) {
+		for b.Loop() {
+			FindCommands(script, VariantBash, "apt-get", "curl", "ln")
+		}
+	})
+	b.Run("CountChainedCommands", func(b *testing.B) {
+		for b.Loop() {
+			CountChainedCommands(script, VariantBash)
+		}
+	})
+	b.Run("ExtractChainedCommands", func(b *testing.B) {
+		for b.Loop() {
+			ExtractChainedCommands(script, VariantBash)
+		}
+	})
+	b.Run("HasExitCommand", func(b *testing.B) {
+		for b.Loop() {
+			HasExitCommand(script, VariantBash)
+		}
+	})
+	b.Run("DetectFileCreation", func(b *testing.B) {
+		for b.Loop() {
+			DetectFileCreation(script, VariantBash, nil)
+		}
+	})
+}
@@ -196,7 +196,7 @@ func isCdCall(call *syntax.CallExpr) bool {
 	if len(call.Args) == 0 {
 		return false
 	}
-	name := call.Args[0].Lit()
+	name := callName(call)
 	return name == "cd"
 }
 
@@ -157,7 +157,7 @@ func matchesCall(call *syntax.CallExpr, match CommandMatcher) bool {
 	if len(call.Args) == 0 {
 		return false
 	}
-	name := call.Args[0].Lit()
+	name := callName(call)
 	if name == "" {
 		return false
 	}
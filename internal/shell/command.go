@@ -274,7 +274,7 @@ func FindCommands(script string, variant Variant, names ...string) []CommandInfo
 		}
 
 		cmdWord := call.Args[0]
-		name := cmdWord.Lit()
+		name := literalWord(cmdWord)
 		if name == "" {
 			return true
 		}
@@ -477,10 +477,12 @@ func (c *CommandInfo) getPowerShellArgValue(flag string) string {
 }
 
 // findNestedShellCommands finds commands within "sh -c 'code'" patterns.
+// Positions are translated to the enclosing script when the code appears
+// verbatim in it; otherwise they stay relative to the nested code.
 func findNestedShellCommands(args []*syntax.Word, variant Variant, nameSet map[string]bool) []CommandInfo {
 	foundDashC := false
 	for _, arg := range args {
-		lit := arg.Lit()
+		lit := literalWord(arg)
 		if lit == "-c" {
 			foundDashC = true
 			continue
@@ -498,9 +500,13 @@ func findNestedShellCommands(args []*syntax.Word, variant Variant, nameSet map[s
 					names = append(names, n)
 				}
 				commands := FindCommands(code, variant, names...)
+				originLine, originCol, mapped := nestedCodeOrigin(arg)
 				for i := range commands {
 					commands[i].SourceKind = CommandSourceKindNestedShell
 					commands[i].HasCommandRange = false
+					if mapped {
+						commands[i].shiftNested(originLine, originCol)
+					}
 				}
 				return commands
 			}
@@ -509,3 +515,22 @@ func findNestedShellCommands(args []*syntax.Word, variant Variant, nameSet map[s
 	}
 	return nil
 }
+
+// shiftNested translates the token positions of a command parsed from nested
+// shell code starting at (originLine, originCol) into the enclosing script.
+func (c *CommandInfo) shiftNested(originLine, originCol int) {
+	line := c.Line
+	c.Line, c.StartCol = shiftNestedPosition(line, c.StartCol, originLine, originCol)
+	_, c.EndCol = shiftNestedPosition(line, c.EndCol, originLine, originCol)
+	if c.Subcommand != "" {
+		line := c.SubcommandLine
+		c.SubcommandLine, c.SubcommandStartCol = shiftNestedPosition(line, c.SubcommandStartCol, originLine, originCol)
+		_, c.SubcommandEndCol = shiftNestedPosition(line, c.SubcommandEndCol, originLine, originCol)
+	}
+	for i := range c.ArgRanges {
+		r := &c.ArgRanges[i]
+		line := r.Line
+		r.Line, r.StartCol = shiftNestedPosition(line, r.StartCol, originLine, originCol)
+		_, r.EndCol = shiftNestedPosition(line, r.EndCol, originLine, originCol)
+	}
+}
@@ -807,6 +807,15 @@ func TestFindCommands_SubcommandPosition(t *testing.T) {
 			wantSubcmdStartCol: 4,
 			wantSubcmdEndCol:   11,
 		},
+		{
+			name:               "nested sh -c",
+			script:             "sh -c 'apt-get install curl'",
+			cmdName:            "apt-get",
+			wantSubcmd:         "install",
+			wantSubcmdLine:     0,
+			wantSubcmdStartCol: 15,
+			wantSubcmdEndCol:   22,
+		},
 	}
 
 	for _, tt := range tests {
@@ -925,3 +934,29 @@ func TestAptGetYesDetection(t *testing.T) {
 		})
 	}
 }
+
+func TestFindCommands_NestedShellPositions(t *testing.T) {
+	t.Parallel()
+	script := "true && sh -c 'set -e\n  wget -q https://example.com/a'"
+	cmds := FindCommands(script, VariantBash, "wget")
+	if len(cmds) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(cmds))
+	}
+	cmd := cmds[0]
+	if cmd.SourceKind != CommandSourceKindNestedShell || cmd.HasCommandRange {
+		t.Errorf("SourceKind = %q, HasCommandRange = %v; want nested shell without a command range",
+			cmd.SourceKind, cmd.HasCommandRange)
+	}
+	if cmd.Line != 1 || cmd.StartCol != 2 || cmd.EndCol != 6 {
+		t.Errorf("name position = %d:%d-%d, want 1:2-6", cmd.Line, cmd.StartCol, cmd.EndCol)
+	}
+	want := []ArgRange{{Line: 1, StartCol: 7, EndCol: 9}, {Line: 1, StartCol: 10, EndCol: 31}}
+	if len(cmd.ArgRanges) != len(want) {
+		t.Fatalf("ArgRanges = %+v, want %+v", cmd.ArgRanges, want)
+	}
+	for i := range want {
+		if cmd.ArgRanges[i] != want[i] {
+			t.Errorf("ArgRanges[%d] = %+v, want %+v", i, cmd.ArgRanges[i], want[i])
+		}
+	}
+}
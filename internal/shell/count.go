@@ -84,7 +84,7 @@ func isExitStatement(stmt *syntax.Stmt) bool {
 		return false
 	}
 
-	return call.Args[0].Lit() == cmdExit
+	return callName(call) == cmdExit
 }
 
 // ExtractChainedCommands extracts individual command strings from && chains.
@@ -320,7 +320,7 @@ func isSimpleStatement(stmt *syntax.Stmt) bool {
 		// NOTE: We intentionally allow `exit` here so ShellCheck fixes like
 		// `cd dir || exit` don't block prefer-run-heredoc.
 		if len(cmd.Args) > 0 {
-			if name := cmd.Args[0].Lit(); name != "" {
+			if name := callName(cmd); name != "" {
 				switch name {
 				case "return", "break", "continue", "exec":
 					return false
@@ -418,7 +418,7 @@ func hasExitCommandFromAST(prog *syntax.File) bool {
 	hasExit := false
 	syntax.Walk(prog, func(node syntax.Node) bool {
 		if call, ok := node.(*syntax.CallExpr); ok && len(call.Args) > 0 {
-			if name := call.Args[0].Lit(); name == cmdExit {
+			if name := callName(call); name == cmdExit {
 				hasExit = true
 				return false // Stop walking
 			}
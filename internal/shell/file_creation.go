@@ -273,7 +273,7 @@ func DetectStandaloneChmod(script string, variant Variant) *ChmodInfo {
 	}
 
 	// Must be chmod command
-	if call.Args[0].Lit() != cmdChmod {
+	if callName(call) != cmdChmod {
 		return nil
 	}
 
@@ -844,7 +844,7 @@ func analyzeCallExpr(
 		return analyzedCmd{cmdType: cmdTypeOther, text: stmtToString(stmt)}
 	}
 
-	cmdName := call.Args[0].Lit()
+	cmdName := callName(call)
 	text := stmtToString(stmt)
 
 	// Check for umask
@@ -1166,7 +1166,7 @@ func analyzePipeToTee(
 		return other, false
 	}
 	teeCall, ok := bin.Y.Cmd.(*syntax.CallExpr)
-	if !ok || len(teeCall.Args) == 0 || teeCall.Args[0].Lit() != cmdTee {
+	if !ok || len(teeCall.Args) == 0 || callName(teeCall) != cmdTee {
 		return other, false
 	}
 
@@ -1265,7 +1265,7 @@ func producerStmtContent(
 			return "", false, false
 		}
 	}
-	name := call.Args[0].Lit()
+	name := callName(call)
 	switch name {
 	case cmdEcho:
 		content, unsafe := extractEchoContent(call, knownVars, options.InterpretPlainEchoEscapes)
@@ -1557,7 +1557,7 @@ func extractFileContent(
 	knownVars func(name string) bool,
 	options FileCreationOptions,
 ) (string, bool) {
-	cmdName := call.Args[0].Lit()
+	cmdName := callName(call)
 
 	switch cmdName {
 	case cmdEcho:
@@ -0,0 +1,48 @@
+package shell
+
+import (
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// NormalizedCommands returns the top-level commands of script in a form
+// suited for comparing the work two RUN instructions do. && chains and
+// ;-separated lists are split into their commands, and each command is
+// printed on one line with canonical spacing, so line continuations,
+// indentation and the way commands are chained or split across RUN
+// instructions don't affect the result.
+//
+// Scripts for shells without a POSIX AST are compared by their
+// whitespace-collapsed text as a single command. It returns nil for empty
+// scripts and scripts that don't parse.
+func NormalizedCommands(script string, variant Variant) []string {
+	if !variant.SupportsPOSIXShellAST() {
+		if normalized := strings.Join(strings.Fields(script), " "); normalized != "" {
+			return []string{normalized}
+		}
+		return nil
+	}
+
+	prog, err := parseScript(script, variant)
+	if err != nil {
+		return nil
+	}
+	var commands []string
+	for _, stmt := range prog.Stmts {
+		commands = appendNormalizedStmt(commands, stmt, variant)
+	}
+	return commands
+}
+
+func appendNormalizedStmt(commands []string, stmt *syntax.Stmt, variant Variant) []string {
+	if bin, ok := stmt.Cmd.(*syntax.BinaryCmd); ok && bin.Op == syntax.AndStmt &&
+		!stmt.Negated && !stmt.Background && len(stmt.Redirs) == 0 {
+		commands = appendNormalizedStmt(commands, bin.X, variant)
+		return appendNormalizedStmt(commands, bin.Y, variant)
+	}
+	if formatted := FormatStatement(stmt, variant); formatted != "" {
+		commands = append(commands, formatted)
+	}
+	return commands
+}
@@ -0,0 +1,75 @@
+package shell
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizedCommands(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		script  string
+		variant Variant
+		want    []string
+	}{
+		{
+			name:    "chain is split",
+			script:  "apt-get update && apt-get install -y curl",
+			variant: VariantBash,
+			want:    []string{"apt-get update", "apt-get install -y curl"},
+		},
+		{
+			name:    "layout is ignored",
+			script:  "apt-get update \\\n    &&   apt-get install \\\n\t-y curl",
+			variant: VariantBash,
+			want:    []string{"apt-get update", "apt-get install -y curl"},
+		},
+		{
+			name:    "semicolons split statements",
+			script:  "set -e; make",
+			variant: VariantPOSIX,
+			want:    []string{"set -e", "make"},
+		},
+		{
+			name:    "pipelines stay whole",
+			script:  "curl -fsSL https://x | sh && make",
+			variant: VariantBash,
+			want:    []string{"curl -fsSL https://x | sh", "make"},
+		},
+		{
+			name:    "trailing || keeps the chain whole",
+			script:  "make && make test || exit 1",
+			variant: VariantBash,
+			want:    []string{"make && make test || exit 1"},
+		},
+		{
+			name:    "non-POSIX shell collapses whitespace",
+			script:  "Install-Module   Pester  -Force",
+			variant: VariantPowerShell,
+			want:    []string{"Install-Module Pester -Force"},
+		},
+		{
+			name:    "unparsable script",
+			script:  "if then",
+			variant: VariantBash,
+			want:    nil,
+		},
+		{
+			name:    "empty script",
+			script:  "  ",
+			variant: VariantPowerShell,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := NormalizedCommands(tt.script, tt.variant)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizedCommands(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
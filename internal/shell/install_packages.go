@@ -29,6 +29,25 @@ type InstallCommand struct {
 	Packages   []PackageArg // non-flag args after subcommand, with positions
 }
 
+// DuplicatePackages returns the literal packages whose normalized text
+// repeats an earlier literal in the same command, in source order. Variable
+// arguments are never reported: their values are unknown until build time.
+func (ic InstallCommand) DuplicatePackages() []PackageArg {
+	seen := make(map[string]bool, len(ic.Packages))
+	var dups []PackageArg
+	for _, pkg := range ic.Packages {
+		if pkg.IsVar {
+			continue
+		}
+		if seen[pkg.Normalized] {
+			dups = append(dups, pkg)
+			continue
+		}
+		seen[pkg.Normalized] = true
+	}
+	return dups
+}
+
 // StripPackageVersion returns the package name with any trailing version
 // specifier removed. Handles the forms used by the package-manager syntaxes
 // tally understands:
@@ -242,7 +261,7 @@ func findInstallPackagesViaPOSIX(script string, variant Variant) []InstallComman
 		}
 
 		cmdWord := call.Args[0]
-		cmdName := cmdWord.Lit()
+		cmdName := literalWord(cmdWord)
 		if cmdName == "" {
 			return true
 		}
@@ -451,6 +451,26 @@ func TestFindInstallPackagesMultiLine(t *testing.T) {
 	}
 }
 
+func TestInstallCommandDuplicatePackages(t *testing.T) {
+	t.Parallel()
+
+	commands := FindInstallPackages(`apt-get install -y curl git "curl" $PKG $PKG git=1:2.39 git`, VariantBash)
+	if len(commands) != 1 {
+		t.Fatalf("got %d commands, want 1", len(commands))
+	}
+
+	dups := commands[0].DuplicatePackages()
+	got := make([]string, 0, len(dups))
+	for _, d := range dups {
+		got = append(got, d.Value)
+	}
+	// Quoted "curl" normalizes to curl; variables and distinct pins are not duplicates.
+	want := []string{`"curl"`, "git"}
+	if !slices.Equal(got, want) {
+		t.Errorf("DuplicatePackages() = %q, want %q", got, want)
+	}
+}
+
 func TestStripPackageVersion(t *testing.T) {
 	t.Parallel()
 
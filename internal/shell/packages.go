@@ -99,7 +99,7 @@ func ExtractPackageInstalls(script string, variant Variant) []PackageInstallInfo
 		}
 
 		// Get the command name
-		cmdName := call.Args[0].Lit()
+		cmdName := callName(call)
 		if cmdName == "" {
 			return true
 		}
@@ -114,7 +114,7 @@ func ExtractPackageInstalls(script string, variant Variant) []PackageInstallInfo
 		// Extract the arguments as strings
 		args := make([]string, 0, len(call.Args)-1)
 		for _, arg := range call.Args[1:] {
-			if lit := arg.Lit(); lit != "" {
+			if lit := literalWord(arg); lit != "" {
 				args = append(args, lit)
 			}
 		}
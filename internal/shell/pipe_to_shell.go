@@ -0,0 +1,251 @@
+package shell
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// pipeDownloaders are commands that write a downloaded file to stdout.
+var pipeDownloaders = map[string]bool{
+	"curl": true,
+	"wget": true,
+}
+
+// PipeToShell is a download piped straight into a shell interpreter, such as
+// "curl -fsSL https://example.com/install.sh | sh -s -- -y". The shell runs
+// whatever the server returns, without a chance to verify it.
+type PipeToShell struct {
+	// Downloader is the base name of the download command (curl or wget).
+	Downloader string
+	// URLs are the URL arguments of the downloader. Expansions are kept as
+	// written ($VAR, ${VAR}); Literal reports whether there were none.
+	URLs []string
+	// Literal is true when every URL is a plain literal.
+	Literal bool
+	// Shell is the base name of the shell interpreter.
+	Shell string
+
+	// StartLine, StartCol, EndLine and EndCol are the 0-based range of the
+	// pipeline in the script (end exclusive).
+	StartLine, StartCol int
+	EndLine, EndCol     int
+
+	// wrapper is the source text before the shell word in the right-hand
+	// command, such as "sudo -E ".
+	wrapper string
+	// shellFlags are the shell options other than the one making it read
+	// the script from stdin.
+	shellFlags []string
+	// scriptArgs are the positional parameters passed to the script.
+	scriptArgs []string
+	// argsLiteral is false when a shell argument needs expansion.
+	argsLiteral bool
+	variant     Variant
+}
+
+// FindPipeToShell returns every pipeline in a script that pipes the output
+// of curl or wget directly into a shell reading the script from stdin:
+// "| sh", "| sh -s -- args", "| bash /dev/stdin" or "| sudo bash".
+// Returns nil for non-POSIX shells and unparseable scripts.
+func FindPipeToShell(script string, variant Variant) []PipeToShell {
+	if !variant.SupportsPOSIXShellAST() {
+		return nil
+	}
+	prog, err := parseScript(script, variant)
+	if err != nil {
+		return nil
+	}
+
+	var found []PipeToShell
+	syntax.Walk(prog, func(node syntax.Node) bool {
+		bin, ok := node.(*syntax.BinaryCmd)
+		if !ok || (bin.Op != syntax.Pipe && bin.Op != syntax.PipeAll) {
+			return true
+		}
+		left := pipelineEdgeCall(bin.X, false)
+		right := pipelineEdgeCall(bin.Y, true)
+		if left == nil || right == nil {
+			return true
+		}
+		if p, ok := pipeToShell(script, left, right, variant); ok {
+			found = append(found, p)
+		}
+		return true
+	})
+	return found
+}
+
+// pipelineEdgeCall returns the call at the left (first) or right (last) end
+// of a pipeline operand, descending into nested pipelines.
+func pipelineEdgeCall(stmt *syntax.Stmt, first bool) *syntax.CallExpr {
+	for stmt != nil {
+		switch cmd := stmt.Cmd.(type) {
+		case *syntax.CallExpr:
+			return cmd
+		case *syntax.BinaryCmd:
+			if cmd.Op != syntax.Pipe && cmd.Op != syntax.PipeAll {
+				return nil
+			}
+			if first {
+				stmt = cmd.X
+			} else {
+				stmt = cmd.Y
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func pipeToShell(script string, left, right *syntax.CallExpr, variant Variant) (PipeToShell, bool) {
+	downloader := path.Base(callName(left))
+	if !pipeDownloaders[downloader] {
+		return PipeToShell{}, false
+	}
+
+	shellIdx := 0
+	shellName := path.Base(callName(right))
+	if commandWrappers[shellName] || shellName == "sudo" {
+		wrapperName := shellName
+		shellName = ""
+		IterateWrapperArgs(right.Args[1:], wrapperName, func(wa WrapperArg) bool {
+			shellName = wa.Name
+			shellIdx = wa.Index + 1
+			return true
+		})
+	}
+	if !shellWrappers[shellName] {
+		return PipeToShell{}, false
+	}
+
+	p := PipeToShell{
+		Downloader: downloader,
+		Literal:    true,
+		Shell:      shellName,
+		variant:    variant,
+	}
+	if !p.parseShellArgs(right.Args[shellIdx+1:]) {
+		return PipeToShell{}, false
+	}
+	downloaderArgs := make([]string, 0, len(left.Args)-1)
+	for _, arg := range left.Args[1:] {
+		value, literal := wordValue(arg)
+		if !literal {
+			value = wordText(arg)
+		}
+		downloaderArgs = append(downloaderArgs, value)
+		if strings.Contains(value, "://") {
+			p.URLs = append(p.URLs, value)
+			p.Literal = p.Literal && literal
+		}
+	}
+	if !downloadsToStdout(downloader, downloaderArgs) {
+		return PipeToShell{}, false
+	}
+
+	start, end := left.Pos(), right.End()
+	p.StartLine, p.StartCol = int(start.Line())-1, int(start.Col())-1
+	p.EndLine, p.EndCol = int(end.Line())-1, int(end.Col())-1
+	if off, shellOff := right.Pos().Offset(), right.Args[shellIdx].Pos().Offset(); shellOff <= uint(len(script)) {
+		p.wrapper = script[off:shellOff]
+	}
+	return p, true
+}
+
+// downloadsToStdout reports whether a curl or wget invocation writes the
+// download to stdout: curl does unless -o/-O name a file, wget only with
+// -O - (also spelled -O- or -qO-).
+func downloadsToStdout(downloader string, args []string) bool {
+	for i, arg := range args {
+		next := ""
+		if i+1 < len(args) {
+			next = args[i+1]
+		}
+		if downloader == "curl" {
+			switch {
+			case arg == "-O" || arg == "--remote-name" || arg == "--remote-name-all":
+				return false
+			case arg == "-o" || arg == "--output":
+				return next == "-"
+			case strings.HasPrefix(arg, "--output="):
+				return arg == "--output=-"
+			}
+			continue
+		}
+		short := strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--")
+		switch {
+		case arg == "--output-document=-" || (short && strings.HasSuffix(arg, "O-")):
+			return true
+		case arg == "--output-document" || (short && strings.HasSuffix(arg, "O")):
+			return next == "-"
+		}
+	}
+	return downloader == "curl"
+}
+
+// parseShellArgs splits the arguments of the shell into options and script
+// arguments. It reports false when the shell does not read its script from
+// stdin: with -c, or with a script file other than /dev/stdin or "-".
+func (p *PipeToShell) parseShellArgs(args []*syntax.Word) bool {
+	p.argsLiteral = true
+	operands, stdinFlag := false, false
+	for _, arg := range args {
+		value, literal := wordValue(arg)
+		p.argsLiteral = p.argsLiteral && literal
+		switch {
+		case stdinFlag && value == "--":
+			// "sh -s -- args": the -- only ends the shell options.
+			stdinFlag = false
+		case operands:
+			stdinFlag = false
+			p.scriptArgs = append(p.scriptArgs, value)
+		case value == "--":
+			operands = true
+		case value == "-" || value == "/dev/stdin":
+			operands = true
+		case strings.HasPrefix(value, "-") && !strings.HasPrefix(value, "--") && len(value) > 1:
+			letters := value[1:]
+			if strings.ContainsRune(letters, 'c') {
+				return false
+			}
+			if strings.ContainsRune(letters, 's') {
+				// -s reads the script from stdin; the rest are its arguments.
+				operands, stdinFlag = true, true
+				letters = strings.ReplaceAll(letters, "s", "")
+			}
+			if letters != "" {
+				p.shellFlags = append(p.shellFlags, "-"+letters)
+			}
+		case strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+"):
+			p.shellFlags = append(p.shellFlags, value)
+		default:
+			// A script file operand: the shell does not run stdin.
+			return false
+		}
+	}
+	return true
+}
+
+// FileCommand returns the command that runs the downloaded script from a
+// file at scriptPath instead of stdin, keeping the wrapper, shell options
+// and script arguments: "sh -s -- -y" becomes "sh /tmp/install.sh -y".
+// It reports false when a shell argument needs expansion.
+func (p PipeToShell) FileCommand(scriptPath string) (string, bool) {
+	if !p.argsLiteral {
+		return "", false
+	}
+	words := slices.Concat([]string{p.Shell}, p.shellFlags, []string{scriptPath}, p.scriptArgs)
+	lang := p.variant.toLangVariant()
+	for i, w := range words {
+		quoted, err := syntax.Quote(w, lang)
+		if err != nil {
+			return "", false
+		}
+		words[i] = quoted
+	}
+	return p.wrapper + strings.Join(words, " "), true
+}
@@ -0,0 +1,109 @@
+package shell
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindPipeToShell(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		script      string
+		wantShell   string
+		wantURLs    []string
+		wantLiteral bool
+		wantFileCmd string // "" when no file command is possible
+	}{
+		{
+			name:        "curl into sh",
+			script:      "curl -fsSL https://get.example.com/install.sh | sh",
+			wantShell:   "sh",
+			wantURLs:    []string{"https://get.example.com/install.sh"},
+			wantLiteral: true,
+			wantFileCmd: "sh /tmp/install.sh",
+		},
+		{
+			name:        "script arguments after -s --",
+			script:      "curl -sSf https://sh.rustup.rs | sh -s -- -y --profile 'minimal'",
+			wantShell:   "sh",
+			wantURLs:    []string{"https://sh.rustup.rs"},
+			wantLiteral: true,
+			wantFileCmd: "sh /tmp/install.sh -y --profile minimal",
+		},
+		{
+			name:        "wget into sudo bash with options",
+			script:      "wget -qO- https://example.com/setup | sudo -E bash -ex",
+			wantShell:   "bash",
+			wantURLs:    []string{"https://example.com/setup"},
+			wantLiteral: true,
+			wantFileCmd: "sudo -E bash -ex /tmp/install.sh",
+		},
+		{
+			name:        "bash reading /dev/stdin",
+			script:      "apk add curl && curl -L \"https://example.com/v${VERSION}/i.sh\" | bash /dev/stdin \"$TARGET\"",
+			wantShell:   "bash",
+			wantURLs:    []string{"https://example.com/v${VERSION}/i.sh"},
+			wantLiteral: false,
+		},
+		{
+			name:        "last of several pipes",
+			script:      "curl -fsSL https://example.com/i.sh | sh -s | tee /tmp/log",
+			wantShell:   "sh",
+			wantURLs:    []string{"https://example.com/i.sh"},
+			wantLiteral: true,
+			wantFileCmd: "sh /tmp/install.sh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := FindPipeToShell(tt.script, VariantBash)
+			if len(got) != 1 {
+				t.Fatalf("got %d pipes, want 1", len(got))
+			}
+			p := got[0]
+			if p.Shell != tt.wantShell || !slices.Equal(p.URLs, tt.wantURLs) || p.Literal != tt.wantLiteral {
+				t.Errorf("got shell %q URLs %q literal %v, want %q %q %v",
+					p.Shell, p.URLs, p.Literal, tt.wantShell, tt.wantURLs, tt.wantLiteral)
+			}
+			fileCmd, ok := p.FileCommand("/tmp/install.sh")
+			if ok != (tt.wantFileCmd != "") || fileCmd != tt.wantFileCmd {
+				t.Errorf("FileCommand() = %q, %v, want %q", fileCmd, ok, tt.wantFileCmd)
+			}
+		})
+	}
+}
+
+func TestFindPipeToShell_Range(t *testing.T) {
+	t.Parallel()
+	got := FindPipeToShell("set -e && curl https://example.com/i.sh \\\n  | sh", VariantPOSIX)
+	if len(got) != 1 {
+		t.Fatalf("got %d pipes, want 1", len(got))
+	}
+	p := got[0]
+	if p.StartLine != 0 || p.StartCol != 10 || p.EndLine != 1 || p.EndCol != 6 {
+		t.Errorf("range = %d:%d-%d:%d, want 0:10-1:6", p.StartLine, p.StartCol, p.EndLine, p.EndCol)
+	}
+}
+
+func TestFindPipeToShell_NotPipeToShell(t *testing.T) {
+	t.Parallel()
+	for _, script := range []string{
+		"curl -fsSL https://example.com/key.gpg | gpg --dearmor -o /usr/share/keyrings/example.gpg",
+		"curl -o /tmp/i.sh https://example.com/i.sh | sh",
+		"wget https://example.com/i.sh | sh",
+		"curl https://example.com/i.sh | sh -c 'cat > /tmp/i.sh'",
+		"curl https://example.com/i.sh | sh ./local.sh",
+		"cat install.sh | sh",
+		"curl https://example.com/i.sh > i.sh && sh i.sh",
+	} {
+		if got := FindPipeToShell(script, VariantBash); len(got) != 0 {
+			t.Errorf("FindPipeToShell(%q) = %+v, want none", script, got)
+		}
+	}
+	if got := FindPipeToShell("curl https://x | sh", VariantPowerShell); got != nil {
+		t.Errorf("PowerShell: got %+v, want nil", got)
+	}
+}
@@ -49,7 +49,7 @@ func FindCommandOccurrences(script string, variant Variant) []CommandOccurrence
 
 		// Get the first word (command name)
 		cmdWord := call.Args[0]
-		name := cmdWord.Lit()
+		name := literalWord(cmdWord)
 		if name == "" {
 			return true
 		}
@@ -71,7 +71,7 @@ func FindCommandOccurrences(script string, variant Variant) []CommandOccurrence
 		// Extract subcommand (first non-flag argument)
 		if len(call.Args) > 1 {
 			for _, arg := range call.Args[1:] {
-				argLit := arg.Lit()
+				argLit := literalWord(arg)
 				if argLit == "" || strings.HasPrefix(argLit, "-") {
 					continue
 				}
@@ -90,7 +90,7 @@ func FindCommandOccurrences(script string, variant Variant) []CommandOccurrence
 
 		// Handle shell wrappers (sh -c, bash -c)
 		if shellWrappers[baseName] {
-			nested := extractNestedShellOccurrences(call.Args[1:], variant, pos)
+			nested := extractNestedShellOccurrences(call.Args[1:], variant)
 			occurrences = append(occurrences, nested...)
 		}
 
@@ -100,6 +100,69 @@ func FindCommandOccurrences(script string, variant Variant) []CommandOccurrence
 	return occurrences
 }
 
+// RedirectTarget is a literal output redirect target (the "/etc/app.conf" in
+// "echo x > /etc/app.conf") with its exact position in the script.
+type RedirectTarget struct {
+	// Path is the literal target as written.
+	Path string
+
+	// Append is true for ">>" redirects.
+	Append bool
+
+	// Line is the 0-based line number within the script where the target appears.
+	Line int
+
+	// StartCol is the 0-based column offset where the target starts.
+	StartCol int
+
+	// EndCol is the 0-based column offset where the target ends (exclusive).
+	EndCol int
+}
+
+// FindRedirectTargets returns the literal ">" and ">>" stdout redirect targets
+// in a shell script, in source order. Targets with quotes or expansions are
+// skipped since their text does not map 1:1 to the source.
+func FindRedirectTargets(script string, variant Variant) []RedirectTarget {
+	parser := syntax.NewParser(
+		syntax.Variant(variant.toLangVariant()),
+		syntax.KeepComments(false),
+	)
+
+	prog, err := parser.Parse(strings.NewReader(script), "")
+	if err != nil {
+		return nil
+	}
+
+	var targets []RedirectTarget
+	syntax.Walk(prog, func(node syntax.Node) bool {
+		redir, ok := node.(*syntax.Redirect)
+		if !ok || (redir.Op != syntax.RdrOut && redir.Op != syntax.AppOut) {
+			return true
+		}
+		if redir.N != nil && redir.N.Value != "1" {
+			return true
+		}
+		target := extractRedirectTarget(redir)
+		if target == "" {
+			return true
+		}
+		pos, end := redir.Word.Pos(), redir.Word.End()
+		if pos.Line() != end.Line() {
+			return true
+		}
+		targets = append(targets, RedirectTarget{
+			Path:     target,
+			Append:   redir.Op == syntax.AppOut,
+			Line:     int(pos.Line()) - 1,
+			StartCol: int(pos.Col()) - 1,
+			EndCol:   int(end.Col()) - 1,
+		})
+		return true
+	})
+
+	return targets
+}
+
 // wrapperOptionsWithValues maps wrapper commands to their flags that consume the next argument.
 // These flags take a value as a separate argument (not with =), so we need to skip that value.
 var wrapperOptionsWithValues = map[string]map[string]bool{
@@ -171,7 +234,7 @@ func extractWrappedOccurrences(args []*syntax.Word, variant Variant, wrapperName
 			occurrences = append(occurrences, extractWrappedOccurrences(wa.RemainingArgs, variant, wa.Name)...)
 		}
 		if shellWrappers[wa.Name] {
-			occurrences = append(occurrences, extractNestedShellOccurrences(wa.RemainingArgs, variant, pos)...)
+			occurrences = append(occurrences, extractNestedShellOccurrences(wa.RemainingArgs, variant)...)
 		}
 		return true // Break after first command found
 	})
@@ -180,12 +243,13 @@ func extractWrappedOccurrences(args []*syntax.Word, variant Variant, wrapperName
 }
 
 // extractNestedShellOccurrences extracts command positions from "sh -c 'code'" patterns.
-// Note: positions within nested shell code are relative to the quoted string, not the original script.
-// This is a limitation that would require additional offset tracking to resolve.
-func extractNestedShellOccurrences(args []*syntax.Word, variant Variant, _ syntax.Pos) []CommandOccurrence {
+// Positions are translated to the enclosing script when the code appears
+// verbatim in it (see nestedCodeOrigin); otherwise they stay relative to the
+// nested code.
+func extractNestedShellOccurrences(args []*syntax.Word, variant Variant) []CommandOccurrence {
 	foundDashC := false
 	for _, arg := range args {
-		lit := arg.Lit()
+		lit := literalWord(arg)
 		if lit == "-c" {
 			foundDashC = true
 			continue
@@ -197,9 +261,16 @@ func extractNestedShellOccurrences(args []*syntax.Word, variant Variant, _ synta
 		if foundDashC {
 			code := extractQuotedContent(arg)
 			if code != "" {
-				// Parse nested shell code
-				// Note: positions are relative to the nested code, not adjusted for parent
-				return FindCommandOccurrences(code, variant)
+				occurrences := FindCommandOccurrences(code, variant)
+				if originLine, originCol, ok := nestedCodeOrigin(arg); ok {
+					for i := range occurrences {
+						occ := &occurrences[i]
+						line := occ.Line
+						occ.Line, occ.StartCol = shiftNestedPosition(line, occ.StartCol, originLine, originCol)
+						_, occ.EndCol = shiftNestedPosition(line, occ.EndCol, originLine, originCol)
+					}
+				}
+				return occurrences
 			}
 			break
 		}
@@ -207,6 +278,49 @@ func extractNestedShellOccurrences(args []*syntax.Word, variant Variant, _ synta
 	return nil
 }
 
+// nestedCodeOrigin reports where the code passed to "sh -c" starts in the
+// enclosing script, as a 0-based line and column. Positions inside the nested
+// code map onto the enclosing script only when the code is a single quoted
+// string whose text appears verbatim in the source: '...', or "..." without
+// escapes or expansions.
+func nestedCodeOrigin(word *syntax.Word) (line, col int, ok bool) {
+	if word == nil || len(word.Parts) != 1 {
+		return 0, 0, false
+	}
+	switch part := word.Parts[0].(type) {
+	case *syntax.SglQuoted:
+		if part.Dollar {
+			return 0, 0, false
+		}
+	case *syntax.DblQuoted:
+		if part.Dollar {
+			return 0, 0, false
+		}
+		for _, p := range part.Parts {
+			lit, isLit := p.(*syntax.Lit)
+			if !isLit || strings.ContainsRune(lit.Value, '\\') {
+				return 0, 0, false
+			}
+		}
+	default:
+		return 0, 0, false
+	}
+	// syntax.Pos is 1-based; the code starts one byte after the opening quote.
+	pos := word.Pos()
+	return int(pos.Line()) - 1, int(pos.Col()), true
+}
+
+// shiftNestedPosition translates a 0-based position inside nested code that
+// starts at (originLine, originCol) into the enclosing script. Only the first
+// line of the nested code is offset by originCol; later lines start at the
+// beginning of a source line.
+func shiftNestedPosition(line, col, originLine, originCol int) (int, int) {
+	if line == 0 {
+		return originLine, originCol + col
+	}
+	return originLine + line, col
+}
+
 // FindCommandOccurrence finds the first occurrence of a specific command.
 // Returns nil if the command is not found.
 func FindCommandOccurrence(script, command string, variant Variant) *CommandOccurrence {
@@ -290,3 +290,54 @@ func TestFindCommandOccurrences_NiceWithAdjustment(t *testing.T) {
 		}
 	}
 }
+
+func TestFindCommandOccurrences_NestedShellPositions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		script   string
+		wantLine int
+		wantCol  int
+	}{
+		{name: "single quoted", script: "sh -c 'apt update'", wantLine: 0, wantCol: 7},
+		{name: "double quoted", script: `bash -c "apt update"`, wantLine: 0, wantCol: 9},
+		{name: "second nested line", script: "sh -c 'true\napt update'", wantLine: 1, wantCol: 0},
+		// Escapes change the offsets, so positions stay relative to the unescaped code.
+		{name: "escaped", script: `sh -c "echo \"x\"; apt update"`, wantLine: 0, wantCol: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			occurrences := FindAllCommandOccurrences(tt.script, "apt", VariantBash)
+			if len(occurrences) != 1 {
+				t.Fatalf("got %d occurrences, want 1", len(occurrences))
+			}
+			occ := occurrences[0]
+			if occ.Line != tt.wantLine || occ.StartCol != tt.wantCol || occ.EndCol != tt.wantCol+3 {
+				t.Errorf("position = %d:%d-%d, want %d:%d-%d",
+					occ.Line, occ.StartCol, occ.EndCol, tt.wantLine, tt.wantCol, tt.wantCol+3)
+			}
+		})
+	}
+}
+
+func TestFindRedirectTargets(t *testing.T) {
+	t.Parallel()
+	script := "mkdir -p /etc/app && echo a > /etc/app/a.conf \\\n  && echo b >> /etc/app/b.conf 2>/dev/null && echo c > \"$HOME/c\""
+	targets := FindRedirectTargets(script, VariantBash)
+
+	want := []RedirectTarget{
+		{Path: "/etc/app/a.conf", Line: 0, StartCol: 30, EndCol: 45},
+		{Path: "/etc/app/b.conf", Append: true, Line: 1, StartCol: 15, EndCol: 30},
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("got %d targets, want %d: %+v", len(targets), len(want), targets)
+	}
+	for i := range want {
+		if targets[i] != want[i] {
+			t.Errorf("target %d = %+v, want %+v", i, targets[i], want[i])
+		}
+	}
+}
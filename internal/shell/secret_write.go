@@ -0,0 +1,232 @@
+package shell
+
+import (
+	"path"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// secretEmitters are commands that print their input or arguments, possibly
+// transformed, so their redirected output carries the secret they read.
+var secretEmitters = map[string]bool{
+	"awk":      true,
+	"base64":   true,
+	cmdCat:     true,
+	"cut":      true,
+	cmdEcho:    true,
+	"envsubst": true,
+	"head":     true,
+	"jq":       true,
+	cmdPrintf:  true,
+	"sed":      true,
+	"tail":     true,
+	"tr":       true,
+}
+
+// SecretWrite is a command that copies secret content into a file, such as
+// "cat /run/secrets/token > /app/token", `echo "$TOKEN" >> ~/.npmrc` or
+// "cp /run/secrets/npmrc .npmrc". Unless the file is removed, the secret is
+// stored in the image layer.
+type SecretWrite struct {
+	// Command is the base name of the command that writes the file.
+	Command string
+	// Dest is the written file as it appears in the script.
+	Dest string
+}
+
+// FindSecretWrites returns the commands in a script that write secret
+// content to a file. A command reads a secret when one of its words
+// mentions a path in secretPaths or expands a variable in secretEnvs, or
+// when its input is redirected from a secret path. Writes are output
+// redirections of commands that print what they read (cat, echo, printf,
+// base64, ...), cp/install, and tee, also at the end of a pipeline. Writes to
+// /dev/* and files removed by rm anywhere in the script are not reported.
+// Returns nil for non-POSIX shells and unparseable scripts.
+func FindSecretWrites(script string, variant Variant, secretPaths, secretEnvs []string) []SecretWrite {
+	if !variant.SupportsPOSIXShellAST() || (len(secretPaths) == 0 && len(secretEnvs) == 0) {
+		return nil
+	}
+	prog, err := parseScript(script, variant)
+	if err != nil {
+		return nil
+	}
+	s := secretScan{script: script, paths: secretPaths, envs: secretEnvs}
+
+	var writes []SecretWrite
+	var removed []string
+	syntax.Walk(prog, func(node syntax.Node) bool {
+		stmt, ok := node.(*syntax.Stmt)
+		if !ok {
+			return true
+		}
+		switch cmd := stmt.Cmd.(type) {
+		case *syntax.CallExpr:
+			name := path.Base(callName(cmd))
+			if name == "rm" {
+				removed = append(removed, operands(cmd.Args[1:])...)
+				return true
+			}
+			if !s.readsSecret(cmd, stmt.Redirs) {
+				return true
+			}
+			if secretEmitters[name] {
+				for _, dest := range outputRedirects(stmt.Redirs) {
+					writes = append(writes, SecretWrite{Command: name, Dest: dest})
+				}
+			}
+			if name == "cp" || name == "install" {
+				if args := operands(cmd.Args[1:]); len(args) > 1 && s.mentionsAny(cmd.Args[1:len(cmd.Args)-1]) {
+					writes = append(writes, SecretWrite{Command: name, Dest: args[len(args)-1]})
+				}
+			}
+		case *syntax.BinaryCmd:
+			if (cmd.Op != syntax.Pipe && cmd.Op != syntax.PipeAll) || !s.stmtReadsSecret(cmd.X) {
+				return true
+			}
+			right := pipelineEdgeCall(cmd.Y, true)
+			if right == nil {
+				return true
+			}
+			name := path.Base(callName(right))
+			if name == cmdTee {
+				for _, dest := range operands(right.Args[1:]) {
+					writes = append(writes, SecretWrite{Command: name, Dest: dest})
+				}
+			}
+			if secretEmitters[name] && cmd.Y.Cmd == right {
+				for _, dest := range outputRedirects(cmd.Y.Redirs) {
+					writes = append(writes, SecretWrite{Command: name, Dest: dest})
+				}
+			}
+		}
+		return true
+	})
+
+	kept := writes[:0]
+	for _, w := range writes {
+		if !strings.HasPrefix(w.Dest, "/dev/") && !removedPath(w.Dest, removed) {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// secretScan checks script words for references to secrets.
+type secretScan struct {
+	script string
+	paths  []string
+	envs   []string
+}
+
+// readsSecret reports whether a call mentions a secret in its words or
+// reads one through an input redirection.
+func (s secretScan) readsSecret(call *syntax.CallExpr, redirs []*syntax.Redirect) bool {
+	if s.mentionsAny(call.Args) {
+		return true
+	}
+	for _, r := range redirs {
+		if r.Op == syntax.RdrIn && s.mentions(r.Word) {
+			return true
+		}
+	}
+	return false
+}
+
+// stmtReadsSecret reports whether any call in a pipeline operand reads a
+// secret.
+func (s secretScan) stmtReadsSecret(stmt *syntax.Stmt) bool {
+	found := false
+	syntax.Walk(stmt, func(node syntax.Node) bool {
+		if found {
+			return false
+		}
+		if st, ok := node.(*syntax.Stmt); ok {
+			if call, ok := st.Cmd.(*syntax.CallExpr); ok && s.readsSecret(call, st.Redirs) {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+func (s secretScan) mentionsAny(words []*syntax.Word) bool {
+	for _, w := range words {
+		if s.mentions(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentions reports whether a word contains a secret path, also inside a
+// command substitution, or expands a secret variable.
+func (s secretScan) mentions(w *syntax.Word) bool {
+	if w == nil {
+		return false
+	}
+	if start, end := w.Pos().Offset(), w.End().Offset(); end <= uint(len(s.script)) {
+		text := s.script[start:end]
+		for _, p := range s.paths {
+			if p != "" && strings.Contains(text, p) {
+				return true
+			}
+		}
+	}
+	found := false
+	syntax.Walk(w, func(node syntax.Node) bool {
+		if pe, ok := node.(*syntax.ParamExp); ok && pe.Param != nil {
+			for _, env := range s.envs {
+				if pe.Param.Value == env {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// outputRedirects returns the literal files that redirections write to.
+func outputRedirects(redirs []*syntax.Redirect) []string {
+	var dests []string
+	for _, r := range redirs {
+		switch r.Op {
+		case syntax.RdrOut, syntax.AppOut, syntax.RdrAll, syntax.AppAll, syntax.RdrClob:
+			if dest, _ := wordValue(r.Word); dest != "" {
+				dests = append(dests, dest)
+			}
+		}
+	}
+	return dests
+}
+
+// operands returns the values of the arguments that are not options.
+func operands(args []*syntax.Word) []string {
+	var out []string
+	for _, arg := range args {
+		value, _ := wordValue(arg)
+		if value == "" || strings.HasPrefix(value, "-") {
+			continue
+		}
+		out = append(out, value)
+	}
+	return out
+}
+
+// removedPath reports whether dest is one of the removed paths or inside
+// one of them.
+func removedPath(dest string, removed []string) bool {
+	dest = path.Clean(dest)
+	for _, r := range removed {
+		r = path.Clean(r)
+		if dest == r || strings.HasPrefix(dest, strings.TrimSuffix(r, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package shell
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindSecretWrites(t *testing.T) {
+	t.Parallel()
+	paths := []string{"/run/secrets/token", "/root/.npmrc"}
+	envs := []string{"GH_TOKEN"}
+	tests := []struct {
+		name   string
+		script string
+		want   []SecretWrite
+	}{
+		{
+			name:   "cat into a file",
+			script: "cat /run/secrets/token > /app/token",
+			want:   []SecretWrite{{Command: "cat", Dest: "/app/token"}},
+		},
+		{
+			name:   "env secret appended to a profile",
+			script: `echo "export GH_TOKEN=${GH_TOKEN}" >> /etc/profile.d/gh.sh && gh auth status`,
+			want:   []SecretWrite{{Command: "echo", Dest: "/etc/profile.d/gh.sh"}},
+		},
+		{
+			name:   "command substitution",
+			script: `printf 'token=%s\n' "$(cat /run/secrets/token)" > .env`,
+			want:   []SecretWrite{{Command: "printf", Dest: ".env"}},
+		},
+		{
+			name:   "input redirection",
+			script: "tr -d '\\n' < /run/secrets/token > /app/token",
+			want:   []SecretWrite{{Command: "tr", Dest: "/app/token"}},
+		},
+		{
+			name:   "copy of the mounted file",
+			script: "cp /root/.npmrc /app/.npmrc && npm ci",
+			want:   []SecretWrite{{Command: "cp", Dest: "/app/.npmrc"}},
+		},
+		{
+			name:   "pipeline into tee and a redirect",
+			script: "cat /run/secrets/token | tee /app/a | base64 > /app/b",
+			want:   []SecretWrite{{Command: "base64", Dest: "/app/b"}, {Command: "tee", Dest: "/app/a"}},
+		},
+		{
+			name:   "file removed in the same RUN",
+			script: "cp /run/secrets/token /tmp/token && make && rm -f /tmp/token",
+		},
+		{
+			name:   "directory removed in the same RUN",
+			script: "mkdir -p /tmp/s && cat /run/secrets/token > /tmp/s/t && make && rm -rf /tmp/s",
+		},
+		{
+			name:   "secret used without writing",
+			script: `GH_TOKEN="$GH_TOKEN" gh release download && curl -H "Authorization: $(cat /run/secrets/token)" https://x > out.tar`,
+		},
+		{
+			name:   "write to /dev",
+			script: "cat /run/secrets/token > /dev/null",
+		},
+		{
+			name:   "unrelated writes",
+			script: "echo $HOME > /app/home && cp /etc/hosts /app/hosts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := FindSecretWrites(tt.script, VariantBash, paths, envs)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FindSecretWrites() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSecretWrites_NoSecrets(t *testing.T) {
+	t.Parallel()
+	if got := FindSecretWrites("cat /run/secrets/token > /app/token", VariantBash, nil, nil); got != nil {
+		t.Errorf("FindSecretWrites() = %+v, want nil", got)
+	}
+}
@@ -256,7 +256,7 @@ func CommandNamesWithVariant(script string, variant Variant) []string {
 	syntax.Walk(prog, func(node syntax.Node) bool {
 		if call, ok := node.(*syntax.CallExpr); ok && len(call.Args) > 0 {
 			// Get the first word (command name)
-			if name := call.Args[0].Lit(); name != "" {
+			if name := callName(call); name != "" {
 				// Strip path prefix (e.g., /usr/bin/wget -> wget)
 				name = path.Base(name)
 				names = append(names, name)
@@ -286,7 +286,7 @@ func extractWrappedCommands(args []*syntax.Word, variant Variant) []string {
 	names := make([]string, 0, 2) // typically 0-2 wrapped commands
 	skipNext := false
 	for i, arg := range args {
-		lit := arg.Lit()
+		lit := literalWord(arg)
 		if lit == "" {
 			continue
 		}
@@ -346,34 +346,7 @@ func isNumeric(s string) bool {
 // extractCommandArg extracts the string content from a shell word and reports
 // whether it is a plain literal with no dynamic shell evaluation.
 func extractCommandArg(word *syntax.Word) (string, bool) {
-	// First try the simple literal case
-	if lit := word.Lit(); lit != "" {
-		return lit, true
-	}
-
-	// Otherwise, extract from quoted parts
-	var sb strings.Builder
-	literal := true
-	for _, part := range word.Parts {
-		switch p := part.(type) {
-		case *syntax.SglQuoted:
-			sb.WriteString(p.Value)
-		case *syntax.DblQuoted:
-			// For double quotes, extract literal parts
-			for _, dpart := range p.Parts {
-				if lit, ok := dpart.(*syntax.Lit); ok {
-					sb.WriteString(lit.Value)
-					continue
-				}
-				literal = false
-			}
-		case *syntax.Lit:
-			sb.WriteString(p.Value)
-		default:
-			literal = false
-		}
-	}
-	return sb.String(), literal
+	return wordValue(word)
 }
 
 // extractQuotedContent extracts the string content from a shell word,
@@ -389,7 +362,7 @@ func extractShellWrapperCommands(args []*syntax.Word, variant Variant) []string
 	var names []string
 	foundDashC := false
 	for _, arg := range args {
-		lit := arg.Lit()
+		lit := literalWord(arg)
 		if lit == "-c" {
 			foundDashC = true
 			continue
@@ -476,3 +449,39 @@ func SetsErrorFlag(cmd string, variant Variant) bool {
 	}
 	return false
 }
+
+// SetsPipefailFirst reports whether the first command a script runs is a
+// "set" builtin that enables pipefail (e.g., "set -o pipefail && ...",
+// "set -euo pipefail; ..."), so that every later pipeline is covered.
+func SetsPipefailFirst(script string, variant Variant) bool {
+	if !variant.SupportsPOSIXShellAST() {
+		return false
+	}
+	prog, err := parseScript(script, variant)
+	if err != nil || len(prog.Stmts) == 0 {
+		return false
+	}
+
+	cmd := prog.Stmts[0].Cmd
+	for {
+		bin, ok := cmd.(*syntax.BinaryCmd)
+		if !ok || (bin.Op != syntax.AndStmt && bin.Op != syntax.OrStmt) {
+			break
+		}
+		cmd = bin.X.Cmd
+	}
+	call, ok := cmd.(*syntax.CallExpr)
+	if !ok || len(call.Args) < 3 || callName(call) != "set" {
+		return false
+	}
+
+	args := call.Args[1:]
+	for i := range len(args) - 1 {
+		flag := args[i].Lit()
+		if strings.HasPrefix(flag, "-") && !strings.HasPrefix(flag, "--") &&
+			strings.HasSuffix(flag, "o") && args[i+1].Lit() == "pipefail" {
+			return true
+		}
+	}
+	return false
+}
@@ -35,6 +35,16 @@ func TestCommandNames(t *testing.T) {
 			script: "apt-get update && apt-get install -y curl",
 			want:   []string{"apt-get", "apt-get"},
 		},
+		{
+			name:   "quoted and alias-bypassing names",
+			script: `"apt-get" update && \curl -fsSL x && '/usr/bin/wget' y`,
+			want:   []string{"apt-get", "curl", "wget"},
+		},
+		{
+			name:   "name needing expansion is skipped",
+			script: "$TOOL run && echo ok",
+			want:   []string{"echo"},
+		},
 		{
 			name:   "command sequence with ;",
 			script: "apt-get update; echo done",
@@ -235,6 +245,11 @@ func TestShellWrappers(t *testing.T) {
 			script: "sh -c 'apt-get update && sudo apt-get install'",
 			want:   []string{"sh", "apt-get", "sudo"},
 		},
+		{
+			name:   "nested sh -c with escaped quotes",
+			script: `sh -c "bash -c \"apt-get update && curl -fsSL x\""`,
+			want:   []string{"sh", "bash", "apt-get", "curl"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -637,3 +652,33 @@ func TestShellFromShebang(t *testing.T) {
 		})
 	}
 }
+
+func TestSetsPipefailFirst(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		script  string
+		variant Variant
+		want    bool
+	}{
+		{"set -o pipefail && wget -O - https://some.site | wc -l", VariantBash, true},
+		{"set -euo pipefail; curl -fsSL https://x | tar -xz", VariantBash, true},
+		{"set -o pipefail\ncurl https://x | sh", VariantBash, true},
+		{"set -e -o pipefail || exit 1; a | b", VariantMksh, true},
+		{"set -o pipefail && a | b", VariantZsh, true},
+		{"wget -O - https://some.site | wc -l && set -o pipefail", VariantBash, false},
+		{"set -e && a | b", VariantBash, false},
+		{"set +o pipefail && a | b", VariantBash, false},
+		{"set -o errexit && a | b", VariantBash, false},
+		{"echo set -o pipefail | cat", VariantBash, false},
+		{"set -o pipefail && a | b", VariantPowerShell, false},
+		{"", VariantBash, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.script), func(t *testing.T) {
+			t.Parallel()
+			if got := SetsPipefailFirst(tt.script, tt.variant); got != tt.want {
+				t.Errorf("SetsPipefailFirst(%q, %v) = %v, want %v", tt.script, tt.variant, got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,35 @@ import (
 // instructions like CMD/ENTRYPOINT when the shell form is trivially
 // tokenizable.
 func SplitSimpleCommand(cmd string, variant Variant) ([]string, bool) {
+	call, ok := parseSingleCall(cmd, variant)
+	if !ok {
+		return nil, false
+	}
+
+	args := make([]string, 0, len(call.Args))
+	for _, w := range call.Args {
+		s, ok := simpleLiteralWord(w)
+		if !ok {
+			return nil, false
+		}
+		args = append(args, s)
+	}
+
+	return args, true
+}
+
+// IsSingleSimpleCommand reports whether cmd is exactly one simple command:
+// a command name and its arguments, without pipelines, boolean operators,
+// redirections, variable assignments, or compound commands. Unlike
+// SplitSimpleCommand, the words may contain expansions.
+func IsSingleSimpleCommand(cmd string, variant Variant) bool {
+	_, ok := parseSingleCall(cmd, variant)
+	return ok
+}
+
+// parseSingleCall parses cmd and returns its call expression when cmd is a
+// single simple command with at least one word and no assignments.
+func parseSingleCall(cmd string, variant Variant) (*syntax.CallExpr, bool) {
 	cmd = strings.TrimSpace(cmd)
 	if cmd == "" {
 		return nil, false
@@ -44,26 +73,10 @@ func SplitSimpleCommand(cmd string, variant Variant) ([]string, bool) {
 	}
 
 	call, ok := stmt.Cmd.(*syntax.CallExpr)
-	if !ok {
-		return nil, false
-	}
-	if len(call.Assigns) > 0 {
-		return nil, false
-	}
-	if len(call.Args) == 0 {
+	if !ok || len(call.Assigns) > 0 || len(call.Args) == 0 {
 		return nil, false
 	}
-
-	args := make([]string, 0, len(call.Args))
-	for _, w := range call.Args {
-		s, ok := simpleLiteralWord(w)
-		if !ok {
-			return nil, false
-		}
-		args = append(args, s)
-	}
-
-	return args, true
+	return call, true
 }
 
 // simpleLiteralWord renders a word that is made entirely of literals and quotes,
@@ -82,31 +95,12 @@ func simpleLiteralWord(w *syntax.Word) (string, bool) {
 		return "", false
 	}
 
-	var b strings.Builder
 	for _, part := range w.Parts {
-		switch p := part.(type) {
-		case *syntax.Lit:
-			// Reject unquoted glob metacharacters; shell form would expand them.
-			if strings.ContainsAny(p.Value, "*?[]") {
-				return "", false
-			}
-			b.WriteString(p.Value)
-		case *syntax.SglQuoted:
-			b.WriteString(p.Value)
-		case *syntax.DblQuoted:
-			for _, dp := range p.Parts {
-				lit, ok := dp.(*syntax.Lit)
-				if !ok {
-					// Disallow $var, `cmd`, $(cmd), etc.
-					return "", false
-				}
-				b.WriteString(lit.Value)
-			}
-		default:
-			// Disallow any other constructs (params, globs, cmd subst, etc).
+		// Reject unquoted glob metacharacters; shell form would expand them.
+		if lit, ok := part.(*syntax.Lit); ok && strings.ContainsAny(lit.Value, "*?[]") {
 			return "", false
 		}
 	}
-
-	return b.String(), true
+	// Disallow $var, `cmd`, $(cmd), $'...' and any other expansion.
+	return wordValue(w)
 }
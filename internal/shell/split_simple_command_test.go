@@ -18,6 +18,9 @@ func TestSplitSimpleCommand(t *testing.T) {
 		{name: "double-quoted-literal", cmd: `echo "hello world"`, wantOK: true, wantArg: []string{"echo", "hello world"}},
 		{name: "mixed-quoted", cmd: `echo foo"bar"`, wantOK: true, wantArg: []string{"echo", "foobar"}},
 		{name: "empty-arg", cmd: "echo ''", wantOK: true, wantArg: []string{"echo", ""}},
+		{name: "escaped-space", cmd: `echo hello\ world`, wantOK: true, wantArg: []string{"echo", "hello world"}},
+		{name: "escaped-quotes", cmd: `echo "say \"hi\"" 'a\b'`, wantOK: true, wantArg: []string{"echo", `say "hi"`, `a\b`}},
+		{name: "dquote-keeps-backslash", cmd: `echo "C:\temp"`, wantOK: true, wantArg: []string{"echo", `C:\temp`}},
 
 		{name: "empty", cmd: "", wantOK: false},
 		{name: "parse-error", cmd: "echo 'unterminated", wantOK: false},
@@ -29,6 +32,7 @@ func TestSplitSimpleCommand(t *testing.T) {
 		{name: "assign", cmd: "FOO=bar echo hi", wantOK: false},
 		{name: "param-expansion", cmd: "echo $HOME", wantOK: false},
 		{name: "param-expansion-double-quoted", cmd: `echo "$HOME"`, wantOK: false},
+		{name: "ansi-c-quoted", cmd: `echo $'a\tb'`, wantOK: false},
 		{name: "unquoted-glob", cmd: "echo *.txt", wantOK: false},
 		{name: "quoted-glob", cmd: `echo "*.txt"`, wantOK: true, wantArg: []string{"echo", "*.txt"}},
 
@@ -52,3 +56,27 @@ func TestSplitSimpleCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSingleSimpleCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{"node server.js", true},
+		{"gunicorn --bind 0.0.0.0:$PORT app:app", true},
+		{`java $JAVA_OPTS -jar "$(ls /app/*.jar)"`, true},
+		{"echo *.txt", true},
+		{"", false},
+		{"a && b", false},
+		{"a | b", false},
+		{"a > log", false},
+		{"FOO=bar app", false},
+		{"if true; then app; fi", false},
+	}
+	for _, tt := range tests {
+		if got := IsSingleSimpleCommand(tt.cmd, VariantPOSIX); got != tt.want {
+			t.Errorf("IsSingleSimpleCommand(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package shell
+
+import (
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// wordValue returns the value the shell would pass for a word after quote
+// removal: backslash escapes are resolved with the rules of their context
+// (unquoted or inside double quotes) and quotes are dropped, so nested code
+// such as sh -c "echo \"a b\"" comes out as echo "a b".
+//
+// Parts that need expansion ($VAR, $(...), `...`, $'...', arithmetic) are
+// left out of the value and reported through literal=false.
+func wordValue(word *syntax.Word) (value string, literal bool) {
+	if word == nil {
+		return "", false
+	}
+	// Fast path for the common plain word, which needs no copy.
+	if len(word.Parts) == 1 {
+		if lit, ok := word.Parts[0].(*syntax.Lit); ok && !strings.Contains(lit.Value, `\`) {
+			return lit.Value, true
+		}
+	}
+	var sb strings.Builder
+	literal = true
+	for _, part := range word.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			writeUnescaped(&sb, p.Value, isUnquotedEscapable)
+		case *syntax.SglQuoted:
+			if p.Dollar {
+				// ANSI-C quoting ($'...') decodes its own escapes.
+				literal = false
+				continue
+			}
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			for _, dpart := range p.Parts {
+				if lit, ok := dpart.(*syntax.Lit); ok {
+					writeUnescaped(&sb, lit.Value, isDoubleQuoteEscapable)
+					continue
+				}
+				literal = false
+			}
+		default:
+			literal = false
+		}
+	}
+	return sb.String(), literal
+}
+
+// literalWord returns the value of a word that has no expansions, or "" if
+// it has any. It is the quote-aware counterpart of [syntax.Word.Lit], which
+// gives up on quoted words and keeps backslashes.
+func literalWord(word *syntax.Word) string {
+	value, literal := wordValue(word)
+	if !literal {
+		return ""
+	}
+	return value
+}
+
+// callName returns the literal command name of a call, or "" when the
+// call has no arguments or its name needs expansion. Quoted names and the
+// alias-bypassing \name form resolve to the plain name.
+func callName(call *syntax.CallExpr) string {
+	if call == nil || len(call.Args) == 0 {
+		return ""
+	}
+	return literalWord(call.Args[0])
+}
+
+// writeUnescaped writes s with backslash escapes removed. A backslash before
+// a character accepted by escapable is dropped; before a newline both are
+// dropped (line continuation); otherwise it is kept.
+func writeUnescaped(sb *strings.Builder, s string, escapable func(byte) bool) {
+	if !strings.Contains(s, `\`) {
+		sb.WriteString(s)
+		return
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		next := s[i+1]
+		switch {
+		case next == '\n':
+			i++
+		case escapable(next):
+			sb.WriteByte(next)
+			i++
+		default:
+			sb.WriteByte('\\')
+		}
+	}
+}
+
+// isUnquotedEscapable reports whether an unquoted backslash escapes c.
+// Outside quotes a backslash escapes every character.
+func isUnquotedEscapable(byte) bool { return true }
+
+// isDoubleQuoteEscapable reports whether a backslash inside double quotes
+// escapes c; for any other character the backslash is kept literally.
+func isDoubleQuoteEscapable(c byte) bool {
+	switch c {
+	case '$', '`', '"', '\\':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package shell
+
+import (
+	"strings"
+	"testing"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+func TestWordValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		word        string
+		want        string
+		wantLiteral bool
+	}{
+		{word: `plain`, want: "plain", wantLiteral: true},
+		{word: `\curl`, want: "curl", wantLiteral: true},
+		{word: `a\ b\\c`, want: `a b\c`, wantLiteral: true},
+		{word: `'a\"b'`, want: `a\"b`, wantLiteral: true},
+		{word: `"a\"b\$c\\d\e"`, want: `a"b$c\d\e`, wantLiteral: true},
+		{word: `"echo \"it's\" done"`, want: `echo "it's" done`, wantLiteral: true},
+		{word: `pre"$HOME"post`, want: "prepost", wantLiteral: false},
+		{word: `$(date)`, want: "", wantLiteral: false},
+		{word: `$'a\tb'`, want: "", wantLiteral: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			t.Parallel()
+			prog, err := syntax.NewParser().Parse(strings.NewReader("cmd "+tt.word), "")
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			call, ok := prog.Stmts[0].Cmd.(*syntax.CallExpr)
+			if !ok {
+				t.Fatalf("not a call: %T", prog.Stmts[0].Cmd)
+			}
+			got, literal := wordValue(call.Args[1])
+			if got != tt.want || literal != tt.wantLiteral {
+				t.Errorf("wordValue(%s) = (%q, %v), want (%q, %v)", tt.word, got, literal, tt.want, tt.wantLiteral)
+			}
+		})
+	}
+}
+
+func TestHasExitCommand_QuotedName(t *testing.T) {
+	t.Parallel()
+	if !HasExitCommand(`cd /app || "exit" 1`, VariantBash) {
+		t.Error(`HasExitCommand did not detect "exit"`)
+	}
+}
@@ -30,7 +30,7 @@ func IterateWrapperArgs(args []*syntax.Word, wrapperName string, callback func(W
 	optionsWithValues := wrapperOptionsWithValues[wrapperName]
 
 	for i, arg := range args {
-		lit := arg.Lit()
+		lit := literalWord(arg)
 		if lit == "" {
 			continue
 		}
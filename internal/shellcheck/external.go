@@ -0,0 +1,124 @@
+package shellcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// ExecutableEnv overrides the executable used by ExternalRunner.
+	ExecutableEnv = "TALLY_SHELLCHECK"
+
+	defaultExecutable = "shellcheck"
+)
+
+// Checker runs ShellCheck on a script. Runner and ExternalRunner implement it.
+type Checker interface {
+	Run(ctx context.Context, script string, opts Options) (JSON1Output, string, error)
+}
+
+var (
+	_ Checker = (*Runner)(nil)
+	_ Checker = (*ExternalRunner)(nil)
+)
+
+// ExternalRunner executes an installed shellcheck binary, passing the script
+// on stdin and reading --format=json1 output.
+type ExternalRunner struct {
+	executable string
+}
+
+// NewExternalRunner returns a runner for executable, a path or a name looked
+// up in PATH. An empty executable means $TALLY_SHELLCHECK, then "shellcheck".
+func NewExternalRunner(executable string) *ExternalRunner {
+	return &ExternalRunner{executable: executable}
+}
+
+// Run checks script and returns the parsed comments and ShellCheck's stderr.
+func (r *ExternalRunner) Run(ctx context.Context, script string, opts Options) (JSON1Output, string, error) {
+	exe, err := r.findExecutable()
+	if err != nil {
+		return JSON1Output{}, "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, exe, externalArgs(opts)...)
+	cmd.Stdin = strings.NewReader(script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// ShellCheck exits with 1 when it reports comments; any other failure
+	// means the check did not run.
+	if err := cmd.Run(); err != nil {
+		exitErr, ok := errors.AsType[*exec.ExitError](err)
+		if !ok || exitErr.ExitCode() != 1 {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return JSON1Output{}, stderr.String(), fmt.Errorf("%s: %w: %s", exe, err, msg)
+			}
+			return JSON1Output{}, stderr.String(), fmt.Errorf("%s: %w", exe, err)
+		}
+	}
+
+	var out JSON1Output
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return JSON1Output{}, stderr.String(), fmt.Errorf("parse shellcheck json1: %w", err)
+	}
+	return out, stderr.String(), nil
+}
+
+func (r *ExternalRunner) findExecutable() (string, error) {
+	candidate := r.executable
+	if candidate == "" {
+		candidate = os.Getenv(ExecutableEnv)
+	}
+	if candidate == "" {
+		candidate = defaultExecutable
+	}
+	exe, err := exec.LookPath(candidate)
+	if err != nil {
+		return "", fmt.Errorf("shellcheck executable %q not found; install shellcheck or set %s", candidate, ExecutableEnv)
+	}
+	return exe, nil
+}
+
+// externalArgs maps Options to shellcheck command-line flags, mirroring the
+// options understood by the embedded reactor.
+func externalArgs(opts Options) []string {
+	args := []string{"--format=json1"}
+	if opts.Dialect != "" {
+		args = append(args, "--shell="+opts.Dialect)
+	}
+	if opts.Severity != "" {
+		args = append(args, "--severity="+opts.Severity)
+	}
+	if opts.Norc {
+		args = append(args, "--norc")
+	}
+	if opts.ExtendedAnalysis != nil {
+		args = append(args, fmt.Sprintf("--extended-analysis=%t", *opts.ExtendedAnalysis))
+	}
+	if len(opts.EnableOptional) > 0 {
+		args = append(args, "--enable="+strings.Join(opts.EnableOptional, ","))
+	}
+	if len(opts.Include) > 0 {
+		args = append(args, "--include="+joinCodes(opts.Include))
+	}
+	if len(opts.Exclude) > 0 {
+		args = append(args, "--exclude="+joinCodes(opts.Exclude))
+	}
+	return append(args, "-")
+}
+
+func joinCodes(codes []string) string {
+	trimmed := make([]string, len(codes))
+	for i, code := range codes {
+		trimmed[i] = strings.TrimPrefix(code, "SC")
+	}
+	return strings.Join(trimmed, ",")
+}
@@ -0,0 +1,100 @@
+package shellcheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExternalArgs(t *testing.T) {
+	t.Parallel()
+
+	extended := false
+	got := externalArgs(Options{
+		Dialect:          "bash",
+		Severity:         "style",
+		Norc:             true,
+		ExtendedAnalysis: &extended,
+		EnableOptional:   []string{"require-variable-braces"},
+		Exclude:          []string{"SC2187", "1090"},
+	})
+	want := []string{
+		"--format=json1",
+		"--shell=bash",
+		"--severity=style",
+		"--norc",
+		"--extended-analysis=false",
+		"--enable=require-variable-braces",
+		"--exclude=2187,1090",
+		"-",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("externalArgs() = %q, want %q", got, want)
+	}
+}
+
+func writeFakeShellcheck(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake shellcheck executable is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "shellcheck")
+	if err := os.WriteFile(path, []byte(script), 0o700); err != nil { //nolint:gosec // test executable
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExternalRunner(t *testing.T) {
+	t.Parallel()
+
+	argsFile := filepath.Join(t.TempDir(), "args")
+	fake := writeFakeShellcheck(t, `#!/bin/sh
+echo "$@" > "`+argsFile+`"
+cat >/dev/null
+echo '{"comments":[{"line":2,"endLine":2,"column":6,"endColumn":8,"level":"info","code":2086,"message":"quote"}]}'
+exit 1
+`)
+
+	out, _, err := NewExternalRunner(fake).Run(context.Background(), "#!/bin/sh\necho $1\n", Options{Dialect: "sh"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(out.Comments) != 1 || out.Comments[0].Code != 2086 {
+		t.Fatalf("Run() comments = %+v, want SC2086", out.Comments)
+	}
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(args)); got != "--format=json1 --shell=sh -" {
+		t.Errorf("shellcheck args = %q", got)
+	}
+}
+
+func TestExternalRunnerFailure(t *testing.T) {
+	t.Parallel()
+
+	fake := writeFakeShellcheck(t, "#!/bin/sh\ncat >/dev/null\necho 'unknown shell' >&2\nexit 3\n")
+
+	_, stderr, err := NewExternalRunner(fake).Run(context.Background(), "echo hi\n", Options{})
+	if err == nil || !strings.Contains(err.Error(), "unknown shell") {
+		t.Fatalf("Run() error = %v, want the stderr message", err)
+	}
+	if !strings.Contains(stderr, "unknown shell") {
+		t.Errorf("stderr = %q", stderr)
+	}
+}
+
+func TestExternalRunnerExecutableFromEnv(t *testing.T) {
+	t.Setenv(ExecutableEnv, "tally-missing-shellcheck")
+
+	_, _, err := NewExternalRunner("").Run(context.Background(), "echo hi\n", Options{})
+	if err == nil || !strings.Contains(err.Error(), `"tally-missing-shellcheck" not found`) {
+		t.Fatalf("Run() error = %v, want the env override to be used", err)
+	}
+}
@@ -6,7 +6,6 @@
 package sourcemap
 
 import (
-	"bytes"
 	"slices"
 	"strings"
 	"unicode/utf8"
@@ -26,37 +25,59 @@ type SourceMap struct {
 	// lineOffsets[i] is the byte offset where line i starts in source.
 	// Used for computing column positions from byte offsets.
 	lineOffsets []int
+
+	// text is source as a string. Lines are substrings of it, so splitting
+	// the source costs one allocation instead of one per line.
+	text string
+
+	// verbatim is true when no line needed \r trimming or UTF-8 repair,
+	// so multi-line snippets can be sliced from text instead of joined.
+	verbatim bool
 }
 
 // New creates a SourceMap from source content.
 // Lines are split on \n (handles both \n and \r\n).
 func New(source []byte) *SourceMap {
-	// Split into lines, preserving empty lines
-	rawLines := bytes.Split(source, []byte{'\n'})
-	lines := make([]string, len(rawLines))
-	lineOffsets := make([]int, len(rawLines))
+	text := string(source)
+	n := strings.Count(text, "\n") + 1
+	lines := make([]string, 0, n)
+	lineOffsets := make([]int, 0, n)
+	verbatim := true
 
-	offset := 0
-	for i, line := range rawLines {
-		lineOffsets[i] = offset
+	// Split into lines, preserving empty lines
+	for offset := 0; ; {
+		raw := text[offset:]
+		next := strings.IndexByte(raw, '\n')
+		if next >= 0 {
+			raw = raw[:next]
+		}
+		lineOffsets = append(lineOffsets, offset)
 		// Trim \r from line endings (for Windows CRLF)
-		s := strings.TrimSuffix(string(line), "\r")
+		line := strings.TrimSuffix(raw, "\r")
 		// Replace invalid UTF-8 sequences with U+FFFD so downstream JSON
 		// marshaling (encoding/json/v2 is strict) never fails on source
 		// snippets. Pre-parse validation catches most cases, but this
 		// provides defense-in-depth for programmatic callers.
-		if !utf8.ValidString(s) {
-			s = strings.ToValidUTF8(s, "\ufffd")
+		if !utf8.ValidString(line) {
+			line = strings.ToValidUTF8(line, "\ufffd")
+			verbatim = false
+		} else if len(line) != len(raw) {
+			verbatim = false
+		}
+		lines = append(lines, line)
+		if next < 0 {
+			break
 		}
-		lines[i] = s
 		// Next line starts after this line + newline character
-		offset += len(line) + 1
+		offset += next + 1
 	}
 
 	return &SourceMap{
 		source:      source,
 		lines:       lines,
 		lineOffsets: lineOffsets,
+		text:        text,
+		verbatim:    verbatim,
 	}
 }
 
@@ -108,6 +129,9 @@ func (sm *SourceMap) Snippet(startLine, endLine int) string {
 		return ""
 	}
 
+	if sm.verbatim {
+		return sm.text[sm.lineOffsets[startLine] : sm.lineOffsets[endLine]+len(sm.lines[endLine])]
+	}
 	return strings.Join(sm.lines[startLine:endLine+1], "\n")
 }
 
@@ -180,6 +204,32 @@ func (sm *SourceMap) ResolveEndLineWithEscape(endLine int, escapeToken rune) int
 	return endLine
 }
 
+// Span is a range of source text. Lines are 0-based like the rest of this
+// package; columns are 0-based byte offsets and EndCol is exclusive.
+type Span struct {
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+}
+
+// TokenSpan returns the span of token when the source has exactly that text
+// at column col of line (both 0-based). Token positions computed from a
+// re-parsed or reconstructed script should be checked this way before they
+// are reported, so that a mapping error degrades to an instruction-wide
+// location instead of pointing at unrelated text. Tokens must not span lines.
+func (sm *SourceMap) TokenSpan(line, col int, token string) (Span, bool) {
+	if token == "" || strings.ContainsRune(token, '\n') || line < 0 || line >= len(sm.lines) || col < 0 {
+		return Span{}, false
+	}
+	text := sm.lines[line]
+	end := col + len(token)
+	if end > len(text) || text[col:end] != token {
+		return Span{}, false
+	}
+	return Span{StartLine: line, StartCol: col, EndLine: line, EndCol: end}, true
+}
+
 // Comment represents a comment extracted from source.
 // Comments in Dockerfiles start with # and extend to end of line.
 type Comment struct {
@@ -84,6 +84,35 @@ func TestLine(t *testing.T) {
 	}
 }
 
+func TestTokenSpan(t *testing.T) {
+	t.Parallel()
+	source := []byte("FROM debian\nRUN apt update && \\\n    apt install curl")
+	sm := New(source)
+
+	tests := []struct {
+		line, col int
+		token     string
+		want      Span
+		wantOK    bool
+	}{
+		{1, 4, "apt", Span{StartLine: 1, StartCol: 4, EndLine: 1, EndCol: 7}, true},
+		{2, 4, "apt", Span{StartLine: 2, StartCol: 4, EndLine: 2, EndCol: 7}, true},
+		{2, 5, "apt", Span{}, false},   // text mismatch
+		{2, 20, "curl", Span{}, false}, // past end of line
+		{3, 0, "apt", Span{}, false},   // out of range
+		{1, -1, "apt", Span{}, false},  // negative column
+		{1, 4, "", Span{}, false},      // empty token
+	}
+
+	for _, tt := range tests {
+		got, ok := sm.TokenSpan(tt.line, tt.col, tt.token)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("TokenSpan(%d, %d, %q) = %+v, %v; want %+v, %v",
+				tt.line, tt.col, tt.token, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
 func TestLineOffset(t *testing.T) {
 	t.Parallel()
 	source := []byte("abc\ndefg\nhi")
@@ -142,6 +171,28 @@ func TestSnippet(t *testing.T) {
 	}
 }
 
+func TestSnippet_RewrittenLines(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"CRLF", "line0\r\nline1\r\nline2", "line0\nline1"},
+		{"invalid UTF-8", "line0\nli\xffne1\nline2", "line0\nli\ufffdne1"},
+		{"trailing newline", "line0\nline1\n", "line0\nline1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := New([]byte(tt.source)).Snippet(0, 1); got != tt.want {
+				t.Errorf("Snippet(0, 1) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSnippetAround(t *testing.T) {
 	t.Parallel()
 	source := []byte("line0\nline1\nline2\nline3\nline4")
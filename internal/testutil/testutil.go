@@ -2,6 +2,7 @@
 package testutil
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -91,7 +92,7 @@ func MakeLintInputWithContext(
 		InvocationContext: invocationCtx,
 		SlowChecksEnabled: true,
 		Config:            nil, // Set by individual tests if needed
-	}
+	}.WithSourceMap(sm)
 }
 
 // MakeLintInputWithConfig creates a LintInput with rule configuration.
@@ -153,6 +154,16 @@ func RunRuleTests(t *testing.T, rule rules.Rule, cases []RuleTestCase) {
 				}
 			}
 
+			// Fixes must be declared in the rule metadata.
+			meta := rule.Metadata()
+			for i, v := range violations {
+				for _, fix := range v.AllFixes() {
+					if !slices.Contains(meta.Fixes, fix.Safety) {
+						t.Errorf("violation[%d] has a %s fix, but %s metadata Fixes = %v", i, fix.Safety, meta.Code, meta.Fixes)
+					}
+				}
+			}
+
 			// Check violation codes
 			if len(tc.WantCodes) > 0 {
 				if len(violations) != len(tc.WantCodes) {
@@ -0,0 +1,111 @@
+// Package textdiff renders line-based unified diffs for human review.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// contextLines is the number of unchanged lines shown around each hunk.
+const contextLines = 3
+
+// Unified returns a unified diff between before and after, labelled with
+// a/<path> and b/<path> like git. It returns an empty string when the
+// contents are equal.
+func Unified(path string, before, after []byte) string {
+	out, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(string(before)),
+		B:        splitLines(string(after)),
+		FromFile: "a/" + path,
+		ToFile:   "b/" + path,
+		Context:  contextLines,
+	})
+	if err != nil {
+		// The writer is a strings.Builder; errors cannot occur.
+		return ""
+	}
+	return out
+}
+
+// splitLines splits s into newline-terminated lines. Unlike
+// difflib.SplitLines it does not add a phantom empty line after a trailing
+// newline; a missing final newline is added so hunks stay well-formed.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+// noNewlineMarker follows a patch line that has no trailing newline.
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+// Patch returns a git-style patch between before and after that `git apply`
+// and `patch -p1` accept. Unlike Unified it keeps a missing final newline
+// apart from a present one, marking such lines as git does. It returns an
+// empty string when the contents are equal.
+func Patch(path string, before, after []byte) string {
+	a := strings.SplitAfter(string(before), "\n")
+	if a[len(a)-1] == "" {
+		a = a[:len(a)-1]
+	}
+	b := strings.SplitAfter(string(after), "\n")
+	if b[len(b)-1] == "" {
+		b = b[:len(b)-1]
+	}
+
+	groups := difflib.NewMatcher(a, b).GetGroupedOpCodes(contextLines)
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+	for _, group := range groups {
+		first, last := group[0], group[len(group)-1]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", patchRange(first.I1, last.I2), patchRange(first.J1, last.J2))
+		for _, op := range group {
+			if op.Tag == 'e' {
+				writePatchLines(&sb, " ", a[op.I1:op.I2])
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				writePatchLines(&sb, "-", a[op.I1:op.I2])
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				writePatchLines(&sb, "+", b[op.J1:op.J2])
+			}
+		}
+	}
+	return sb.String()
+}
+
+// patchRange formats the 0-based half-open line range [start, stop) for a
+// hunk header. An empty range names the line before it.
+func patchRange(start, stop int) string {
+	length := stop - start
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// writePatchLines writes lines with the given prefix, marking a line without
+// a trailing newline.
+func writePatchLines(sb *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n" + noNewlineMarker)
+		}
+	}
+}
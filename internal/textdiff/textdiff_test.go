@@ -0,0 +1,63 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	got := Unified("Dockerfile", []byte("FROM alpine\nRUN a\n"), []byte("FROM alpine\nRUN b\n"))
+	want := "--- a/Dockerfile\n+++ b/Dockerfile\n@@ -1,2 +1,2 @@\n FROM alpine\n-RUN a\n+RUN b\n"
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Unified("Dockerfile", []byte("FROM alpine\n"), []byte("FROM alpine\n")); got != "" {
+		t.Errorf("expected empty diff for equal content, got %q", got)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			name:   "changed line",
+			before: "FROM alpine\nRUN a\n",
+			after:  "FROM alpine\nRUN b\n",
+			want: "diff --git a/Dockerfile b/Dockerfile\n--- a/Dockerfile\n+++ b/Dockerfile\n" +
+				"@@ -1,2 +1,2 @@\n FROM alpine\n-RUN a\n+RUN b\n",
+		},
+		{
+			name:   "added final newline",
+			before: "FROM alpine\nRUN a",
+			after:  "FROM alpine\nRUN a\n",
+			want: "diff --git a/Dockerfile b/Dockerfile\n--- a/Dockerfile\n+++ b/Dockerfile\n" +
+				"@@ -1,2 +1,2 @@\n FROM alpine\n-RUN a\n\\ No newline at end of file\n+RUN a\n",
+		},
+		{
+			name:   "deleted line",
+			before: "FROM alpine\nRUN a\nRUN b\n",
+			after:  "FROM alpine\nRUN b\n",
+			want: "diff --git a/Dockerfile b/Dockerfile\n--- a/Dockerfile\n+++ b/Dockerfile\n" +
+				"@@ -1,3 +1,2 @@\n FROM alpine\n-RUN a\n RUN b\n",
+		},
+		{
+			name:   "equal",
+			before: "FROM alpine\n",
+			after:  "FROM alpine\n",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Patch("Dockerfile", []byte(tt.before), []byte(tt.after)); got != tt.want {
+				t.Errorf("Patch() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}